@@ -0,0 +1,519 @@
+package apfs
+
+// A read only parser for the Apple File System (APFS).
+//
+// An APFS container holds one or more volumes which share a common
+// pool of blocks. All metadata is stored as objects - most objects
+// are virtual and are referred to by an object id which is resolved
+// to a physical block through an object map. The object map keeps
+// older versions of each object keyed by transaction id, which is
+// what allows snapshots to be read: the snapshot's objects are
+// resolved as they were at the snapshot's transaction.
+//
+// Encrypted volumes are detected and reported but can not be read.
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	nxMagic   = "NXSB"
+	apfsMagic = "APSB"
+
+	objTypeMask = 0x0000ffff
+	objPhysical = 0x40000000
+
+	objTypeNXSuperblock = 0x1
+	objTypeBtree        = 0x2
+	objTypeBtreeNode    = 0x3
+	objTypeOmap         = 0xb
+	objTypeFS           = 0xd
+
+	btnodeRoot        = 0x1
+	btnodeLeaf        = 0x2
+	btnodeFixedKVSize = 0x4
+
+	btreeNodeHeaderSize = 56
+	btreeInfoSize       = 40
+
+	maxBtreeDepth      = 16
+	maxFileSystems     = 100
+	maxCheckpointDescs = 4096
+)
+
+var (
+	fsNotFoundError = errors.New("file not found")
+)
+
+type APFSContext struct {
+	reader io.ReaderAt
+
+	block_size int64
+	omap       *objectMap
+
+	BlockCount uint64
+	UUID       string
+
+	// The transaction id of the checkpoint we are reading.
+	XID uint64
+
+	// The container keybag is present when any volume is encrypted.
+	KeybagBlock uint64
+
+	Volumes []*Volume
+}
+
+func NewAPFSContext(reader io.ReaderAt) (*APFSContext, error) {
+	header := make([]byte, 4096)
+	n, err := reader.ReadAt(header, 0)
+	if n < len(header) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	if string(header[32:36]) != nxMagic {
+		return nil, errors.New("apfs: invalid container superblock magic")
+	}
+
+	block_size := int64(binary.LittleEndian.Uint32(header[36:]))
+	if block_size < 4096 || block_size > 65536 ||
+		block_size&(block_size-1) != 0 {
+		return nil, errors.New("apfs: invalid block size")
+	}
+
+	result := &APFSContext{
+		reader:     reader,
+		block_size: block_size,
+	}
+
+	sb, err := result.readObject(0, objTypeNXSuperblock)
+	if err != nil {
+		return nil, err
+	}
+
+	// Block 0 may be stale - the latest valid superblock is found
+	// in the checkpoint descriptor area.
+	sb = result.findLatestSuperblock(sb)
+
+	result.XID = binary.LittleEndian.Uint64(sb[16:])
+	result.BlockCount = binary.LittleEndian.Uint64(sb[40:])
+	result.UUID = formatUUID(sb[72:88])
+	result.KeybagBlock = binary.LittleEndian.Uint64(sb[1296:])
+
+	result.omap, err = result.openObjectMap(binary.LittleEndian.Uint64(sb[160:]))
+	if err != nil {
+		return nil, err
+	}
+
+	max_file_systems := int(binary.LittleEndian.Uint32(sb[180:]))
+	if max_file_systems > maxFileSystems {
+		max_file_systems = maxFileSystems
+	}
+
+	for i := 0; i < max_file_systems; i++ {
+		fs_oid := binary.LittleEndian.Uint64(sb[184+i*8:])
+		if fs_oid == 0 {
+			continue
+		}
+
+		paddr, err := result.omap.Lookup(fs_oid, result.XID)
+		if err != nil {
+			return nil, fmt.Errorf("apfs: volume %d: %w", i, err)
+		}
+
+		volume, err := result.openVolume(paddr, result.XID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("apfs: volume %d: %w", i, err)
+		}
+		result.Volumes = append(result.Volumes, volume)
+	}
+
+	return result, nil
+}
+
+func (self *APFSContext) findLatestSuperblock(sb []byte) []byte {
+	desc_blocks := binary.LittleEndian.Uint32(sb[104:])
+	desc_base := binary.LittleEndian.Uint64(sb[112:])
+
+	// Non contiguous checkpoint areas are stored in a tree which
+	// we do not support - just use the copy in block 0.
+	if desc_blocks&0x80000000 != 0 || desc_blocks > maxCheckpointDescs {
+		return sb
+	}
+
+	best := sb
+	best_xid := binary.LittleEndian.Uint64(sb[16:])
+	for i := uint64(0); i < uint64(desc_blocks); i++ {
+		candidate, err := self.readObject(desc_base+i, objTypeNXSuperblock)
+		if err != nil || string(candidate[32:36]) != nxMagic {
+			continue
+		}
+
+		xid := binary.LittleEndian.Uint64(candidate[16:])
+		if xid > best_xid {
+			best = candidate
+			best_xid = xid
+		}
+	}
+
+	return best
+}
+
+func (self *APFSContext) readBlock(paddr uint64) ([]byte, error) {
+	if self.BlockCount > 0 && paddr >= self.BlockCount {
+		return nil, fmt.Errorf("apfs: block %d out of range", paddr)
+	}
+
+	buf := make([]byte, self.block_size)
+	n, err := self.reader.ReadAt(buf, int64(paddr)*self.block_size)
+	if n < len(buf) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf, nil
+}
+
+// Read an object from a physical block and verify its checksum and
+// type.
+func (self *APFSContext) readObject(paddr uint64, obj_type uint32) ([]byte, error) {
+	buf, err := self.readBlock(paddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if binary.LittleEndian.Uint64(buf) != fletcher64(buf[8:]) {
+		return nil, fmt.Errorf("apfs: invalid checksum for object at block %d", paddr)
+	}
+
+	if binary.LittleEndian.Uint32(buf[24:])&objTypeMask != obj_type {
+		return nil, fmt.Errorf("apfs: unexpected object type at block %d", paddr)
+	}
+
+	return buf, nil
+}
+
+// The Fletcher 64 checksum used by all APFS objects.
+func fletcher64(buf []byte) uint64 {
+	var sum1, sum2 uint64
+	for i := 0; i+4 <= len(buf); i += 4 {
+		sum1 = (sum1 + uint64(binary.LittleEndian.Uint32(buf[i:]))) % 0xffffffff
+		sum2 = (sum2 + sum1) % 0xffffffff
+	}
+
+	c1 := 0xffffffff - (sum1+sum2)%0xffffffff
+	c2 := 0xffffffff - (sum1+c1)%0xffffffff
+	return c2<<32 | c1
+}
+
+// The object map translates virtual object ids to physical blocks.
+type objectMap struct {
+	tree *btree
+}
+
+func (self *APFSContext) openObjectMap(paddr uint64) (*objectMap, error) {
+	buf, err := self.readObject(paddr, objTypeOmap)
+	if err != nil {
+		return nil, err
+	}
+
+	return &objectMap{
+		tree: &btree{
+			ctx:  self,
+			root: binary.LittleEndian.Uint64(buf[48:]),
+		},
+	}, nil
+}
+
+// Find the physical address of the object as it was at the
+// transaction xid.
+func (self *objectMap) Lookup(oid, xid uint64) (uint64, error) {
+	key, value, err := self.tree.lookupLE(func(key []byte) int {
+		if len(key) < 16 {
+			return -1
+		}
+		return compareUint64Pair(
+			binary.LittleEndian.Uint64(key), binary.LittleEndian.Uint64(key[8:]),
+			oid, xid)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if key == nil || binary.LittleEndian.Uint64(key) != oid || len(value) < 16 {
+		return 0, fmt.Errorf("apfs: object %d not found in object map", oid)
+	}
+
+	return binary.LittleEndian.Uint64(value[8:]), nil
+}
+
+type btree struct {
+	ctx  *APFSContext
+	root uint64
+
+	// Virtual trees resolve node ids through the object map.
+	omap *objectMap
+	xid  uint64
+}
+
+type btreeNode struct {
+	buf   []byte
+	flags uint16
+	level uint16
+	nkeys int
+
+	toc_start int
+	key_start int
+	val_end   int
+
+	key_size int
+	val_size int
+}
+
+func (self *btree) getNode(oid uint64, key_size, val_size int) (*btreeNode, error) {
+	paddr := oid
+	if self.omap != nil {
+		var err error
+		paddr, err = self.omap.Lookup(oid, self.xid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buf, err := self.ctx.readBlock(paddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if binary.LittleEndian.Uint64(buf) != fletcher64(buf[8:]) {
+		return nil, fmt.Errorf("apfs: invalid checksum for btree node at block %d", paddr)
+	}
+
+	obj_type := binary.LittleEndian.Uint32(buf[24:]) & objTypeMask
+	if obj_type != objTypeBtree && obj_type != objTypeBtreeNode {
+		return nil, fmt.Errorf("apfs: invalid btree node at block %d", paddr)
+	}
+
+	result := &btreeNode{
+		buf:      buf,
+		flags:    binary.LittleEndian.Uint16(buf[32:]),
+		level:    binary.LittleEndian.Uint16(buf[34:]),
+		nkeys:    int(binary.LittleEndian.Uint32(buf[36:])),
+		val_end:  len(buf),
+		key_size: key_size,
+		val_size: val_size,
+	}
+
+	table_off := int(binary.LittleEndian.Uint16(buf[40:]))
+	table_len := int(binary.LittleEndian.Uint16(buf[42:]))
+	result.toc_start = btreeNodeHeaderSize + table_off
+	result.key_start = result.toc_start + table_len
+
+	// The root node stores the tree info at the end of the block.
+	if result.flags&btnodeRoot != 0 {
+		result.val_end -= btreeInfoSize
+		info := buf[len(buf)-btreeInfoSize:]
+		result.key_size = int(binary.LittleEndian.Uint32(info[8:]))
+		result.val_size = int(binary.LittleEndian.Uint32(info[12:]))
+	}
+
+	toc_size := 8
+	if result.flags&btnodeFixedKVSize != 0 {
+		toc_size = 4
+	}
+
+	if result.key_start > result.val_end ||
+		result.toc_start+result.nkeys*toc_size > result.key_start {
+		return nil, fmt.Errorf("apfs: invalid btree node at block %d", paddr)
+	}
+
+	return result, nil
+}
+
+func (self *btreeNode) isLeaf() bool {
+	return self.flags&btnodeLeaf != 0
+}
+
+func (self *btreeNode) entry(i int) (key []byte, value []byte, err error) {
+	var k_off, k_len, v_off, v_len int
+
+	if self.flags&btnodeFixedKVSize != 0 {
+		toc := self.buf[self.toc_start+i*4:]
+		k_off = int(binary.LittleEndian.Uint16(toc))
+		v_off = int(binary.LittleEndian.Uint16(toc[2:]))
+		k_len = self.key_size
+		v_len = self.val_size
+
+		// Index nodes always store the child's object id.
+		if !self.isLeaf() {
+			v_len = 8
+		}
+	} else {
+		toc := self.buf[self.toc_start+i*8:]
+		k_off = int(binary.LittleEndian.Uint16(toc))
+		k_len = int(binary.LittleEndian.Uint16(toc[2:]))
+		v_off = int(binary.LittleEndian.Uint16(toc[4:]))
+		v_len = int(binary.LittleEndian.Uint16(toc[6:]))
+	}
+
+	key_start := self.key_start + k_off
+	val_start := self.val_end - v_off
+	if key_start+k_len > self.val_end || val_start < self.key_start ||
+		val_start+v_len > self.val_end {
+		return nil, nil, errors.New("apfs: invalid btree entry")
+	}
+
+	return self.buf[key_start : key_start+k_len],
+		self.buf[val_start : val_start+v_len], nil
+}
+
+func (self *btreeNode) child(i int) (uint64, error) {
+	_, value, err := self.entry(i)
+	if err != nil {
+		return 0, err
+	}
+	if len(value) < 8 {
+		return 0, errors.New("apfs: invalid btree index entry")
+	}
+	return binary.LittleEndian.Uint64(value), nil
+}
+
+// Find the last record with a key that compares less than or equal
+// to the target. The compare function returns the ordering of the
+// key relative to the target.
+func (self *btree) lookupLE(compare func(key []byte) int) (key []byte, value []byte, err error) {
+	node, err := self.getNode(self.root, 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for depth := 0; depth < maxBtreeDepth; depth++ {
+		found := -1
+		for i := 0; i < node.nkeys; i++ {
+			key, _, err := node.entry(i)
+			if err != nil {
+				return nil, nil, err
+			}
+			if compare(key) > 0 {
+				break
+			}
+			found = i
+		}
+
+		if found < 0 {
+			return nil, nil, nil
+		}
+
+		if node.isLeaf() {
+			return node.entry(found)
+		}
+
+		child, err := node.child(found)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		node, err = self.getNode(child, node.key_size, node.val_size)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return nil, nil, errors.New("apfs: btree too deep")
+}
+
+// Visit all the leaf records in key order for which the range
+// function returns 0. The range function returns <0 for keys before
+// the range and >0 for keys after it.
+func (self *btree) walk(in_range func(key []byte) int,
+	cb func(key, value []byte) error) error {
+	node, err := self.getNode(self.root, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	_, err = self.walkNode(node, 0, in_range, cb)
+	return err
+}
+
+func (self *btree) walkNode(node *btreeNode, depth int,
+	in_range func(key []byte) int,
+	cb func(key, value []byte) error) (done bool, err error) {
+	if depth > maxBtreeDepth {
+		return true, errors.New("apfs: btree too deep")
+	}
+
+	for i := 0; i < node.nkeys; i++ {
+		key, value, err := node.entry(i)
+		if err != nil {
+			return true, err
+		}
+
+		if in_range(key) > 0 {
+			return true, nil
+		}
+
+		if node.isLeaf() {
+			if in_range(key) == 0 {
+				err = cb(key, value)
+				if err != nil {
+					return true, err
+				}
+			}
+			continue
+		}
+
+		// The child only covers keys up to the next index key so
+		// skip it if the next key is still before the range.
+		if i+1 < node.nkeys {
+			next_key, _, err := node.entry(i + 1)
+			if err != nil {
+				return true, err
+			}
+			if in_range(next_key) < 0 {
+				continue
+			}
+		}
+
+		child_oid, err := node.child(i)
+		if err != nil {
+			return true, err
+		}
+
+		child, err := self.getNode(child_oid, node.key_size, node.val_size)
+		if err != nil {
+			return true, err
+		}
+
+		done, err = self.walkNode(child, depth+1, in_range, cb)
+		if done || err != nil {
+			return done, err
+		}
+	}
+
+	return false, nil
+}
+
+func compareUint64Pair(a1, a2, b1, b2 uint64) int {
+	switch {
+	case a1 < b1:
+		return -1
+	case a1 > b1:
+		return 1
+	case a2 < b2:
+		return -1
+	case a2 > b2:
+		return 1
+	}
+	return 0
+}
+
+func formatUUID(b []byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,542 @@
+package apfs
+
+// This is an accessor which parses an APFS container
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	// A virtual directory at the top of each volume listing the
+	// volume's snapshots.
+	snapshotDirectory = "$snapshots"
+)
+
+type APFSFileInfo struct {
+	inode      *Inode
+	name       string
+	_full_path *accessors.OSPath
+
+	// Volumes and snapshots are presented as directories without
+	// an inode.
+	mtime time.Time
+	data  *ordereddict.Dict
+}
+
+func (self *APFSFileInfo) IsDir() bool {
+	if self.inode == nil {
+		return true
+	}
+	return self.inode.IsDir()
+}
+
+func (self *APFSFileInfo) Size() int64 {
+	if self.inode == nil {
+		return 0
+	}
+	return self.inode.LogicalSize()
+}
+
+func (self *APFSFileInfo) Data() *ordereddict.Dict {
+	if self.inode == nil {
+		return self.data
+	}
+
+	result := ordereddict.NewDict().
+		Set("inode", self.inode.Number).
+		Set("parent", self.inode.Parent).
+		Set("mode", self.inode.FileMode().String()).
+		Set("uid", self.inode.Uid).
+		Set("gid", self.inode.Gid).
+		Set("links", self.inode.Nlink).
+		Set("flags", fmt.Sprintf("%#x", self.inode.BSDFlags)).
+		Set("protection_class", self.inode.ProtectionClass)
+
+	if self.inode.IsLink() {
+		target, err := self.inode.LinkTarget()
+		if err == nil {
+			result.Set("link", target)
+		}
+	}
+
+	if self.inode.IsCompressed() {
+		info, err := self.inode.CompressionInfo()
+		if err == nil {
+			result.Set("compression", info.Type)
+		}
+	}
+
+	return result
+}
+
+func (self *APFSFileInfo) Name() string {
+	return self.name
+}
+
+func (self *APFSFileInfo) UniqueName() string {
+	return self._full_path.String()
+}
+
+func (self *APFSFileInfo) Mode() os.FileMode {
+	if self.inode == nil {
+		return os.ModeDir | 0755
+	}
+	return self.inode.FileMode()
+}
+
+func (self *APFSFileInfo) ModTime() time.Time {
+	return self.Mtime()
+}
+
+func (self *APFSFileInfo) FullPath() string {
+	return self._full_path.String()
+}
+
+func (self *APFSFileInfo) OSPath() *accessors.OSPath {
+	return self._full_path
+}
+
+func (self *APFSFileInfo) Btime() time.Time {
+	if self.inode == nil {
+		return self.mtime
+	}
+	return self.inode.Btime
+}
+
+func (self *APFSFileInfo) Mtime() time.Time {
+	if self.inode == nil {
+		return self.mtime
+	}
+	return self.inode.Mtime
+}
+
+func (self *APFSFileInfo) Ctime() time.Time {
+	if self.inode == nil {
+		return self.mtime
+	}
+	return self.inode.Ctime
+}
+
+func (self *APFSFileInfo) Atime() time.Time {
+	if self.inode == nil {
+		return self.mtime
+	}
+	return self.inode.Atime
+}
+
+// Symlinks are not followed - the target is available in the Data
+// field.
+func (self *APFSFileInfo) IsLink() bool {
+	return false
+}
+
+func (self *APFSFileInfo) GetLink() (*accessors.OSPath, error) {
+	return nil, errors.New("Not implemented")
+}
+
+func volumeInfo(volume *Volume) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("index", volume.Index).
+		Set("uuid", volume.UUID).
+		Set("role", volume.Role).
+		Set("volume_group", volume.VolumeGroup).
+		Set("encrypted", volume.Crypto.Encrypted).
+		Set("snapshots", volume.NumSnapshots)
+}
+
+func snapshotInfo(snapshot *Snapshot) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("xid", snapshot.XID).
+		Set("created", snapshot.Created)
+}
+
+type APFSFileSystemAccessor struct {
+	scope vfilter.Scope
+
+	// The delegate accessor we use to open the underlying volume.
+	accessor string
+	device   *accessors.OSPath
+
+	root *accessors.OSPath
+}
+
+func NewAPFSFileSystemAccessor(
+	scope vfilter.Scope,
+	root_path *accessors.OSPath,
+	device *accessors.OSPath, accessor string) *APFSFileSystemAccessor {
+	return &APFSFileSystemAccessor{
+		scope:    scope,
+		accessor: accessor,
+		device:   device,
+		root:     root_path,
+	}
+}
+
+func (self APFSFileSystemAccessor) New(scope vfilter.Scope) (
+	accessors.FileSystemAccessor, error) {
+	// Create a new cache in the scope.
+	return &APFSFileSystemAccessor{
+		scope:    scope,
+		device:   self.device,
+		accessor: self.accessor,
+		root:     self.root,
+	}, nil
+}
+
+func (self APFSFileSystemAccessor) ParsePath(path string) (
+	*accessors.OSPath, error) {
+	return accessors.NewLinuxOSPath(path)
+}
+
+// Resolve the path to the volume (or snapshot) it is in and the
+// components within that volume.
+func (self *APFSFileSystemAccessor) getVolume(
+	apfs_ctx *APFSContext, components []string) (*Volume, []string, error) {
+	if len(components) == 0 {
+		return nil, nil, fsNotFoundError
+	}
+
+	volume, err := apfs_ctx.GetVolume(components[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(components) < 3 || components[1] != snapshotDirectory {
+		return volume, components[1:], nil
+	}
+
+	snapshot, err := volume.GetSnapshot(components[2])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	volume, err = volume.OpenSnapshot(snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return volume, components[3:], nil
+}
+
+func (self *APFSFileSystemAccessor) ReadDir(path string) (
+	res []accessors.FileInfo, err error) {
+	// Normalize the path
+	fullpath, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.ReadDirWithOSPath(fullpath)
+}
+
+func (self *APFSFileSystemAccessor) ReadDirWithOSPath(
+	fullpath *accessors.OSPath) (res []accessors.FileInfo, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	result := []accessors.FileInfo{}
+
+	apfs_ctx, err := GetAPFSContext(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	// The top level lists the volumes in the container.
+	if len(fullpath.Components) == 0 {
+		for _, volume := range apfs_ctx.Volumes {
+			result = append(result, &APFSFileInfo{
+				name:       volume.Name,
+				mtime:      volume.LastModified,
+				data:       volumeInfo(volume),
+				_full_path: fullpath.Append(volume.Name),
+			})
+		}
+		return result, nil
+	}
+
+	if len(fullpath.Components) == 2 &&
+		fullpath.Components[1] == snapshotDirectory {
+		volume, err := apfs_ctx.GetVolume(fullpath.Components[0])
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots, err := volume.Snapshots()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, snapshot := range snapshots {
+			result = append(result, &APFSFileInfo{
+				name:       snapshot.Name,
+				mtime:      snapshot.Created,
+				data:       snapshotInfo(snapshot),
+				_full_path: fullpath.Append(snapshot.Name),
+			})
+		}
+		return result, nil
+	}
+
+	volume, components, err := self.getVolume(apfs_ctx, fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := volume.OpenComponents(components)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := volume.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// List the directory.
+	for _, entry := range entries {
+		inode, err := volume.GetInode(entry.Inode)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, &APFSFileInfo{
+			inode:      inode,
+			name:       entry.Name,
+			_full_path: fullpath.Append(entry.Name),
+		})
+	}
+	return result, nil
+}
+
+// Adapt the inode reader to a ReadSeekCloser
+type readAdapter struct {
+	sync.Mutex
+
+	pos    int64
+	reader InodeReader
+}
+
+func (self *readAdapter) Read(buf []byte) (res int, err error) {
+	self.Lock()
+	defer self.Unlock()
+
+	res, err = self.reader.ReadAt(buf, self.pos)
+	self.pos += int64(res)
+
+	// Short reads are not errors as long as we read something.
+	if res > 0 && errors.Is(err, io.EOF) {
+		err = nil
+	}
+
+	return res, err
+}
+
+func (self *readAdapter) ReadAt(buf []byte, offset int64) (int, error) {
+	return self.reader.ReadAt(buf, offset)
+}
+
+func (self *readAdapter) Close() error {
+	return nil
+}
+
+func (self *readAdapter) Seek(offset int64, whence int) (int64, error) {
+	self.Lock()
+	defer self.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+		self.pos = offset
+	case io.SeekCurrent:
+		self.pos += offset
+	case io.SeekEnd:
+		self.pos = self.reader.Size() + offset
+	}
+
+	if self.pos < 0 {
+		self.pos = 0
+		return 0, os.ErrInvalid
+	}
+	return self.pos, nil
+}
+
+func (self *APFSFileSystemAccessor) Open(
+	path string) (res accessors.ReadSeekCloser, err error) {
+
+	full_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.OpenWithOSPath(full_path)
+}
+
+func (self *APFSFileSystemAccessor) OpenWithOSPath(
+	fullpath *accessors.OSPath) (res accessors.ReadSeekCloser, err error) {
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	apfs_ctx, err := GetAPFSContext(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	volume, components, err := self.getVolume(apfs_ctx, fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	inode, err := volume.OpenComponents(components)
+	if err != nil {
+		return nil, err
+	}
+
+	if inode.IsDir() {
+		return nil, errors.New("apfs: Can not open a directory")
+	}
+
+	reader, err := inode.Reader()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readAdapter{reader: reader}, nil
+}
+
+func (self *APFSFileSystemAccessor) Lstat(
+	path string) (res accessors.FileInfo, err error) {
+
+	fullpath, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.LstatWithOSPath(fullpath)
+}
+
+func (self *APFSFileSystemAccessor) LstatWithOSPath(
+	fullpath *accessors.OSPath) (res accessors.FileInfo, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	apfs_ctx, err := GetAPFSContext(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	components := fullpath.Components
+	switch len(components) {
+	case 0:
+		return &APFSFileInfo{_full_path: fullpath}, nil
+
+	case 1:
+		volume, err := apfs_ctx.GetVolume(components[0])
+		if err != nil {
+			return nil, err
+		}
+		return &APFSFileInfo{
+			name:       volume.Name,
+			mtime:      volume.LastModified,
+			data:       volumeInfo(volume),
+			_full_path: fullpath,
+		}, nil
+
+	case 2:
+		if components[1] == snapshotDirectory {
+			return &APFSFileInfo{
+				name:       snapshotDirectory,
+				_full_path: fullpath,
+			}, nil
+		}
+	}
+
+	volume, volume_components, err := self.getVolume(apfs_ctx, components)
+	if err != nil {
+		return nil, err
+	}
+
+	inode, err := volume.OpenComponents(volume_components)
+	if err != nil {
+		return nil, err
+	}
+
+	// The top of a snapshot is presented as a directory.
+	if len(volume_components) == 0 && volume.Snapshot != nil {
+		return &APFSFileInfo{
+			name:       volume.Snapshot.Name,
+			mtime:      volume.Snapshot.Created,
+			data:       snapshotInfo(volume.Snapshot),
+			_full_path: fullpath,
+		}, nil
+	}
+
+	return &APFSFileInfo{
+		inode:      inode,
+		name:       fullpath.Basename(),
+		_full_path: fullpath,
+	}, nil
+}
+
+func init() {
+	accessors.Register("apfs", &APFSFileSystemAccessor{},
+		`Access the APFS filesystem inside an image by parsing the raw container.
+
+This accessor is designed to operate on images or raw block devices
+directly and does not require the filesystem to be mounted. It
+requires a delegate accessor to get the raw APFS container (e.g. the
+partition within a disk image).
+
+The top level directory lists the volumes in the container by
+name. Each volume contains a virtual directory $snapshots which lists
+the volume's snapshots, and files within a snapshot can be accessed as
+/<volume>/$snapshots/<snapshot name>/<path>.
+
+Encrypted volumes are listed but their content can not be read.
+
+## Example
+
+The following query will glob all the files under the /Users
+directory of the Data volume
+
+SELECT *
+FROM glob(globs='/*Data/Users/**',
+  accessor="apfs",
+  root=pathspec(
+    DelegateAccessor="offset",
+    DelegatePath=pathspec(
+      DelegateAccessor="file",
+      DelegatePath="disk.dd",
+      Path="209735680")))
+
+`)
+
+	json.RegisterCustomEncoder(&APFSFileInfo{}, accessors.MarshalGlobFileInfo)
+}
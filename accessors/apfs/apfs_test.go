@@ -0,0 +1,524 @@
+package apfs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/file"
+)
+
+const (
+	testBlockSize = 4096
+
+	// Timestamps are in nanoseconds
+	testTime = uint64(1698832800) * 1000000000
+)
+
+type kv struct {
+	key   []byte
+	value []byte
+}
+
+// Build a small container with a plain volume (with a snapshot)
+// and an encrypted volume. The live volume's fs tree has two levels
+// while the snapshot's tree is a single leaf.
+type apfsBuilder struct {
+	image   []byte
+	objects []uint64
+}
+
+func (self *apfsBuilder) block(n uint64) []byte {
+	return self.image[n*testBlockSize : (n+1)*testBlockSize]
+}
+
+func (self *apfsBuilder) object(n, oid, xid uint64, obj_type uint32) []byte {
+	buf := self.block(n)
+	binary.LittleEndian.PutUint64(buf[8:], oid)
+	binary.LittleEndian.PutUint64(buf[16:], xid)
+	binary.LittleEndian.PutUint32(buf[24:], obj_type)
+	self.objects = append(self.objects, n)
+	return buf
+}
+
+func (self *apfsBuilder) node(n, oid, xid uint64, root, leaf bool,
+	fixed_size int, entries []kv) {
+	obj_type := uint32(objTypeBtreeNode)
+	flags := uint16(0)
+	val_end := testBlockSize
+	if root {
+		obj_type = objTypeBtree
+		flags |= btnodeRoot
+		val_end -= btreeInfoSize
+	}
+	if leaf {
+		flags |= btnodeLeaf
+	}
+	if oid == n {
+		obj_type |= objPhysical
+	}
+
+	toc_size := 8
+	if fixed_size > 0 {
+		flags |= btnodeFixedKVSize
+		toc_size = 4
+	}
+
+	buf := self.object(n, oid, xid, obj_type)
+	binary.LittleEndian.PutUint16(buf[32:], flags)
+	if !leaf {
+		binary.LittleEndian.PutUint16(buf[34:], 1)
+	}
+	binary.LittleEndian.PutUint32(buf[36:], uint32(len(entries)))
+	binary.LittleEndian.PutUint16(buf[42:], uint16(len(entries)*toc_size))
+
+	key_start := btreeNodeHeaderSize + len(entries)*toc_size
+	k_off, v_off := 0, 0
+	for i, e := range entries {
+		copy(buf[key_start+k_off:], e.key)
+		v_off += len(e.value)
+		copy(buf[val_end-v_off:], e.value)
+
+		toc := buf[btreeNodeHeaderSize+i*toc_size:]
+		binary.LittleEndian.PutUint16(toc, uint16(k_off))
+		if fixed_size > 0 {
+			binary.LittleEndian.PutUint16(toc[2:], uint16(v_off))
+		} else {
+			binary.LittleEndian.PutUint16(toc[2:], uint16(len(e.key)))
+			binary.LittleEndian.PutUint16(toc[4:], uint16(v_off))
+			binary.LittleEndian.PutUint16(toc[6:], uint16(len(e.value)))
+		}
+		k_off += len(e.key)
+	}
+
+	if root {
+		info := buf[testBlockSize-btreeInfoSize:]
+		binary.LittleEndian.PutUint32(info[4:], testBlockSize)
+		binary.LittleEndian.PutUint32(info[8:], uint32(fixed_size))
+		binary.LittleEndian.PutUint32(info[12:], uint32(fixed_size))
+	}
+}
+
+func (self *apfsBuilder) seal() {
+	for _, n := range self.objects {
+		buf := self.block(n)
+		binary.LittleEndian.PutUint64(buf, fletcher64(buf[8:]))
+	}
+}
+
+func omapEntry(oid, xid, paddr uint64) kv {
+	key := binary.LittleEndian.AppendUint64(nil, oid)
+	key = binary.LittleEndian.AppendUint64(key, xid)
+	value := binary.LittleEndian.AppendUint64(make([]byte, 8), paddr)
+	return kv{key: key, value: value}
+}
+
+func recordKey(oid uint64, record_type uint64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, oid|record_type<<objTypeShift)
+}
+
+func inodeRecord(oid, parent uint64, mode uint16, bsd_flags uint32,
+	size int64, mtime uint64) kv {
+	value := make([]byte, 92)
+	binary.LittleEndian.PutUint64(value, parent)
+	binary.LittleEndian.PutUint64(value[8:], oid)
+	binary.LittleEndian.PutUint64(value[16:], testTime)
+	binary.LittleEndian.PutUint64(value[24:], mtime)
+	binary.LittleEndian.PutUint64(value[32:], mtime)
+	binary.LittleEndian.PutUint64(value[40:], mtime)
+	binary.LittleEndian.PutUint32(value[56:], 1)
+	binary.LittleEndian.PutUint32(value[60:], 3)
+	binary.LittleEndian.PutUint32(value[68:], bsd_flags)
+	binary.LittleEndian.PutUint32(value[72:], 501)
+	binary.LittleEndian.PutUint32(value[76:], 20)
+	binary.LittleEndian.PutUint16(value[80:], mode)
+
+	if size >= 0 {
+		// A single data stream extended field.
+		value = append(value, 1, 0, 40, 0, inoExtTypeDstream, 0, 40, 0)
+		dstream := make([]byte, 40)
+		binary.LittleEndian.PutUint64(dstream, uint64(size))
+		value = append(value, dstream...)
+	}
+
+	return kv{key: recordKey(oid, apfsTypeInode), value: value}
+}
+
+func dirRecord(parent uint64, name string, child uint64, dtype uint16) kv {
+	key := recordKey(parent, apfsTypeDirRecord)
+	key = binary.LittleEndian.AppendUint32(key, uint32(len(name)+1)|0xabc<<10)
+	key = append(key, name...)
+	key = append(key, 0)
+
+	value := binary.LittleEndian.AppendUint64(nil, child)
+	value = binary.LittleEndian.AppendUint64(value, testTime)
+	value = binary.LittleEndian.AppendUint16(value, dtype)
+	return kv{key: key, value: value}
+}
+
+func extentRecord(oid, logical, length, physical uint64) kv {
+	key := binary.LittleEndian.AppendUint64(recordKey(oid, apfsTypeFileExtent), logical)
+	value := binary.LittleEndian.AppendUint64(nil, length)
+	value = binary.LittleEndian.AppendUint64(value, physical)
+	value = binary.LittleEndian.AppendUint64(value, 0)
+	return kv{key: key, value: value}
+}
+
+func xattrRecord(oid uint64, name string, flags uint16, data []byte) kv {
+	key := recordKey(oid, apfsTypeXattr)
+	key = binary.LittleEndian.AppendUint16(key, uint16(len(name)+1))
+	key = append(key, name...)
+	key = append(key, 0)
+
+	value := binary.LittleEndian.AppendUint16(nil, flags)
+	value = binary.LittleEndian.AppendUint16(value, uint16(len(data)))
+	return kv{key: key, value: append(value, data...)}
+}
+
+func decmpfsHeader(compression_type uint32, size uint64) []byte {
+	result := []byte(decmpfsMagic)
+	result = binary.LittleEndian.AppendUint32(result, compression_type)
+	return binary.LittleEndian.AppendUint64(result, size)
+}
+
+func zlibCompress(data []byte) []byte {
+	b := &bytes.Buffer{}
+	w := zlib.NewWriter(b)
+	w.Write(data)
+	w.Close()
+	return b.Bytes()
+}
+
+func testData(size int, seed byte) []byte {
+	result := make([]byte, size)
+	for i := range result {
+		result[i] = byte(i/512) + seed + byte(i%7)
+	}
+	return result
+}
+
+func (self *apfsBuilder) volume(n, oid uint64, name string, role uint16,
+	fs_flags uint64, omap_block, root_oid, snap_meta uint64) {
+	sb := self.object(n, oid, 9, objTypeFS)
+	copy(sb[32:], apfsMagic)
+	binary.LittleEndian.PutUint64(sb[56:], apfsIncompatCaseInsensitive)
+
+	// Encryption metadata: version 5, class D
+	binary.LittleEndian.PutUint16(sb[96:], 5)
+	binary.LittleEndian.PutUint32(sb[104:], 4)
+	binary.LittleEndian.PutUint32(sb[108:], 0x14000)
+
+	binary.LittleEndian.PutUint32(sb[116:], objTypeBtree)
+	binary.LittleEndian.PutUint32(sb[124:], objPhysical|objTypeBtree)
+	binary.LittleEndian.PutUint64(sb[128:], omap_block)
+	binary.LittleEndian.PutUint64(sb[136:], root_oid)
+	binary.LittleEndian.PutUint64(sb[152:], snap_meta)
+	binary.LittleEndian.PutUint64(sb[216:], 1)
+	copy(sb[240:], "0123456789abcdef")
+	binary.LittleEndian.PutUint64(sb[256:], testTime)
+	binary.LittleEndian.PutUint64(sb[264:], fs_flags)
+	copy(sb[272:], "newfs_apfs")
+	copy(sb[704:], name)
+	binary.LittleEndian.PutUint16(sb[964:], role)
+	copy(sb[1008:], "fedcba9876543210")
+}
+
+func (self *apfsBuilder) container(n, xid uint64, omap_block uint64) {
+	sb := self.object(n, 1, xid, objPhysical|objTypeNXSuperblock)
+	copy(sb[32:], nxMagic)
+	binary.LittleEndian.PutUint32(sb[36:], testBlockSize)
+	binary.LittleEndian.PutUint64(sb[40:], uint64(len(self.image)/testBlockSize))
+	copy(sb[72:], "containeruuid...")
+	binary.LittleEndian.PutUint32(sb[104:], 2)
+	binary.LittleEndian.PutUint64(sb[112:], 1)
+	binary.LittleEndian.PutUint64(sb[160:], omap_block)
+	binary.LittleEndian.PutUint32(sb[180:], 100)
+	binary.LittleEndian.PutUint64(sb[184:], 1026)
+	binary.LittleEndian.PutUint64(sb[192:], 1040)
+	binary.LittleEndian.PutUint64(sb[1296:], 50)
+}
+
+func buildAPFS() *apfsBuilder {
+	self := &apfsBuilder{image: make([]byte, 64*testBlockSize)}
+
+	// Block 0 holds a stale superblock pointing to an invalid object
+	// map - the current superblock is in the checkpoint area.
+	self.container(0, 1, 3)
+	self.container(2, 10, 4)
+
+	omap := self.object(4, 4, 10, objPhysical|objTypeOmap)
+	binary.LittleEndian.PutUint64(omap[48:], 5)
+	self.node(5, 5, 10, true, true, 16, []kv{
+		omapEntry(1026, 9, 10),
+		omapEntry(1040, 9, 40),
+	})
+
+	// The Data volume
+	self.volume(10, 1026, "Data", 0x40, apfsFSUnencrypted, 11, 1028, 13)
+	omap = self.object(11, 11, 9, objPhysical|objTypeOmap)
+	binary.LittleEndian.PutUint64(omap[48:], 12)
+	self.node(12, 12, 9, true, true, 16, []kv{
+		omapEntry(1028, 3, 20),
+		omapEntry(1028, 8, 21),
+		omapEntry(1029, 8, 22),
+		omapEntry(1030, 8, 23),
+	})
+
+	// The snapshot metadata tree
+	snap := make([]byte, 50)
+	binary.LittleEndian.PutUint64(snap[8:], 14)
+	binary.LittleEndian.PutUint64(snap[16:], testTime)
+	binary.LittleEndian.PutUint16(snap[48:], 6)
+	snap = append(snap, "snap1\x00"...)
+	self.node(13, 13, 9, true, true, 0, []kv{
+		{key: recordKey(5, apfsTypeSnapMetadata), value: snap},
+	})
+	self.volume(14, 1026, "Data", 0x40, apfsFSUnencrypted, 11, 1028, 0)
+
+	// The fs tree at the time of the snapshot.
+	self.node(20, 1028, 3, true, true, 0, []kv{
+		inodeRecord(2, 1, 0x41ed, 0, -1, testTime),
+		dirRecord(2, "hello.txt", 16, 8),
+		dirRecord(2, "old.txt", 17, 8),
+		inodeRecord(16, 2, 0x81a4, 0, 11, testTime),
+		extentRecord(16, 0, testBlockSize, 30),
+		inodeRecord(17, 2, 0x81a4, 0, 9, testTime),
+		extentRecord(17, 0, testBlockSize, 31),
+	})
+	copy(self.block(30), "hello world")
+	copy(self.block(31), "old file\n")
+
+	// The current fs tree.
+	self.node(21, 1028, 8, true, false, 0, []kv{
+		{key: recordKey(2, apfsTypeInode), value: binary.LittleEndian.AppendUint64(nil, 1029)},
+		{key: recordKey(18, apfsTypeInode), value: binary.LittleEndian.AppendUint64(nil, 1030)},
+	})
+
+	self.node(22, 1029, 8, false, true, 0, []kv{
+		inodeRecord(2, 1, 0x41ed, 0, -1, testTime),
+		dirRecord(2, "hello.txt", 16, 8),
+		dirRecord(2, "link", 20, 10),
+		dirRecord(2, "new.txt", 18, 8),
+		dirRecord(2, "sub", 19, 4),
+		inodeRecord(16, 2, 0x81a4, 0, 5000, testTime+1000),
+		extentRecord(16, 0, testBlockSize, 32),
+		extentRecord(16, testBlockSize, testBlockSize, 33),
+	})
+	copy(self.image[32*testBlockSize:], testData(2*testBlockSize, 1))
+
+	compressed := bytes.Repeat([]byte("Compressed data "), 100)
+	fork_data := testData(0x18000, 2)
+
+	// The resource fork contains a block table pointing at zlib
+	// compressed 64kb blocks.
+	var blocks [][]byte
+	for i := 0; i < len(fork_data); i += compressionBlockSize {
+		end := i + compressionBlockSize
+		if end > len(fork_data) {
+			end = len(fork_data)
+		}
+		blocks = append(blocks, zlibCompress(fork_data[i:end]))
+	}
+
+	resource := binary.LittleEndian.AppendUint32(nil, uint32(len(blocks)))
+	offset := 4 + len(blocks)*8
+	for _, b := range blocks {
+		resource = binary.LittleEndian.AppendUint32(resource, uint32(offset))
+		resource = binary.LittleEndian.AppendUint32(resource, uint32(len(b)))
+		offset += len(b)
+	}
+	for _, b := range blocks {
+		resource = append(resource, b...)
+	}
+
+	fork := make([]byte, 0x100)
+	binary.BigEndian.PutUint32(fork, 0x100)
+	fork = binary.BigEndian.AppendUint32(fork, uint32(len(resource)))
+	fork = append(fork, resource...)
+	copy(self.image[36*testBlockSize:], fork)
+
+	fork_stream := binary.LittleEndian.AppendUint64(nil, 100)
+	fork_stream = binary.LittleEndian.AppendUint64(fork_stream, uint64(len(fork)))
+	fork_stream = append(fork_stream, make([]byte, 32)...)
+
+	self.node(23, 1030, 8, false, true, 0, []kv{
+		inodeRecord(18, 2, 0x81a4, 0, 4, testTime),
+		extentRecord(18, 0, testBlockSize, 34),
+		inodeRecord(19, 2, 0x41ed, 0, -1, testTime),
+		dirRecord(19, "compressed.txt", 21, 8),
+		dirRecord(19, "fork.bin", 22, 8),
+		inodeRecord(20, 2, 0xa1ed, 0, -1, testTime),
+		xattrRecord(20, symlinkXattr, xattrDataEmbedded, []byte("hello.txt\x00")),
+		inodeRecord(21, 19, 0x81a4, bsdFlagCompressed, -1, testTime),
+		xattrRecord(21, decmpfsXattr, xattrDataEmbedded, append(
+			decmpfsHeader(3, uint64(len(compressed))), zlibCompress(compressed)...)),
+		inodeRecord(22, 19, 0x81a4, bsdFlagCompressed, -1, testTime),
+		xattrRecord(22, decmpfsXattr, xattrDataEmbedded,
+			decmpfsHeader(4, uint64(len(fork_data)))),
+		xattrRecord(22, resourceForkXattr, xattrDataStream, fork_stream),
+		extentRecord(100, 0, 8*testBlockSize, 36),
+	})
+	copy(self.block(34), "new\n")
+
+	// The encrypted volume's fs tree can not be read.
+	self.volume(40, 1040, "Secret", 0x2, 0, 41, 1050, 0)
+	omap = self.object(41, 41, 9, objPhysical|objTypeOmap)
+	binary.LittleEndian.PutUint64(omap[48:], 42)
+	self.node(42, 42, 9, true, true, 16, []kv{
+		omapEntry(1050, 9, 43),
+	})
+	copy(self.block(43), testData(testBlockSize, 9))
+
+	self.seal()
+	return self
+}
+
+func getTestAccessor(t *testing.T, builder *apfsBuilder) (
+	*APFSFileSystemAccessor, func()) {
+	dir, err := ioutil.TempDir("", "apfs")
+	assert.NoError(t, err)
+
+	image_path := filepath.Join(dir, "apfs.dd")
+	assert.NoError(t, ioutil.WriteFile(image_path, builder.image, 0644))
+
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+
+	fs_accessor := NewAPFSFileSystemAccessor(scope,
+		accessors.MustNewLinuxOSPath(""),
+		accessors.MustNewGenericOSPath(image_path), "file")
+
+	return fs_accessor, func() {
+		scope.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestAPFS(t *testing.T) {
+	builder := buildAPFS()
+	fs_accessor, closer := getTestAccessor(t, builder)
+	defer closer()
+
+	listDir := func(path string) []string {
+		children, err := fs_accessor.ReadDir(path)
+		assert.NoError(t, err)
+
+		result := []string{}
+		for _, c := range children {
+			result = append(result, c.Name())
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	readFile := func(path string) []byte {
+		fd, err := fs_accessor.Open(path)
+		assert.NoError(t, err)
+		defer fd.Close()
+
+		data, err := ioutil.ReadAll(fd)
+		assert.NoError(t, err)
+		return data
+	}
+
+	assert.Equal(t, []string{"Data", "Secret"}, listDir("/"))
+	assert.Equal(t, []string{"hello.txt", "link", "new.txt", "sub"},
+		listDir("/Data"))
+	assert.Equal(t, []string{"compressed.txt", "fork.bin"},
+		listDir("/Data/sub"))
+
+	stat, err := fs_accessor.Lstat("/Data")
+	assert.NoError(t, err)
+	role, _ := stat.Data().Get("role")
+	assert.Equal(t, "Data", role)
+
+	expected := builder.image[32*testBlockSize : 32*testBlockSize+5000]
+	assert.Equal(t, expected, readFile("/Data/hello.txt"))
+
+	// The volume is case insensitive.
+	assert.Equal(t, expected, readFile("/Data/HELLO.TXT"))
+
+	// Files compressed in the decmpfs xattr and the resource fork.
+	assert.Equal(t, bytes.Repeat([]byte("Compressed data "), 100),
+		readFile("/Data/sub/compressed.txt"))
+	assert.True(t, bytes.Equal(testData(0x18000, 2),
+		readFile("/Data/sub/fork.bin")))
+
+	stat, err = fs_accessor.Lstat("/Data/sub/fork.bin")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0x18000), stat.Size())
+
+	stat, err = fs_accessor.Lstat("/Data/link")
+	assert.NoError(t, err)
+	target, _ := stat.Data().Get("link")
+	assert.Equal(t, "hello.txt", target)
+
+	// The snapshot shows the volume before the changes.
+	assert.Equal(t, []string{"snap1"}, listDir("/Data/$snapshots"))
+	assert.Equal(t, []string{"hello.txt", "old.txt"},
+		listDir("/Data/$snapshots/snap1"))
+	assert.Equal(t, "hello world", string(readFile("/Data/$snapshots/snap1/hello.txt")))
+	assert.Equal(t, "old file\n", string(readFile("/Data/$snapshots/snap1/old.txt")))
+
+	// The encrypted volume is reported but can not be read.
+	stat, err = fs_accessor.Lstat("/Secret")
+	assert.NoError(t, err)
+	encrypted, _ := stat.Data().Get("encrypted")
+	assert.Equal(t, true, encrypted)
+
+	_, err = fs_accessor.ReadDir("/Secret")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "encrypted")
+}
+
+func TestAPFSSnapshotDiff(t *testing.T) {
+	builder := buildAPFS()
+	apfs_ctx, err := NewAPFSContext(bytes.NewReader(builder.image))
+	assert.NoError(t, err)
+
+	volume, err := apfs_ctx.GetVolume("Data")
+	assert.NoError(t, err)
+	assert.Equal(t, "D", volume.Crypto.KeyClass)
+	assert.Equal(t, formatUUID([]byte("fedcba9876543210")), volume.VolumeGroup)
+
+	snapshot, err := volume.GetSnapshot("snap1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), snapshot.XID)
+
+	before, err := volume.OpenSnapshot(snapshot)
+	assert.NoError(t, err)
+
+	output_chan := make(chan *DiffEntry)
+	go func() {
+		defer close(output_chan)
+		assert.NoError(t, DiffVolumes(context.Background(),
+			before, volume, output_chan))
+	}()
+
+	changes := []string{}
+	for item := range output_chan {
+		changes = append(changes, item.Change+" "+item.Path)
+	}
+
+	assert.Equal(t, []string{
+		"modified /hello.txt",
+		"added /link",
+		"added /new.txt",
+		"removed /old.txt",
+		"added /sub",
+		"added /sub/compressed.txt",
+		"added /sub/fork.bin",
+	}, changes)
+}
@@ -0,0 +1,153 @@
+package apfs
+
+import (
+	"context"
+	"path"
+	"sort"
+)
+
+const (
+	maxDiffDepth = 256
+)
+
+type DiffEntry struct {
+	Path   string
+	Change string
+	Old    *Inode
+	New    *Inode
+}
+
+// Compare two versions of a volume (usually a snapshot and the live
+// volume) and report the files which were added, removed or
+// modified between them.
+func DiffVolumes(ctx context.Context, before, after *Volume,
+	output_chan chan *DiffEntry) error {
+	old_root, err := before.GetInode(rootDirInode)
+	if err != nil {
+		return err
+	}
+
+	new_root, err := after.GetInode(rootDirInode)
+	if err != nil {
+		return err
+	}
+
+	return diffDirectory(ctx, "/", before, after, old_root, new_root, 0, output_chan)
+}
+
+func listDirectory(volume *Volume, dir *Inode) (map[string]*DirEntry, error) {
+	result := make(map[string]*DirEntry)
+	if dir == nil || !dir.IsDir() {
+		return result, nil
+	}
+
+	entries, err := volume.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		result[e.Name] = e
+	}
+	return result, nil
+}
+
+func diffDirectory(ctx context.Context, dir_path string,
+	before, after *Volume, old_dir, new_dir *Inode, depth int,
+	output_chan chan *DiffEntry) error {
+	if depth > maxDiffDepth {
+		return nil
+	}
+
+	old_entries, err := listDirectory(before, old_dir)
+	if err != nil {
+		return err
+	}
+
+	new_entries, err := listDirectory(after, new_dir)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(old_entries)+len(new_entries))
+	for name := range old_entries {
+		names = append(names, name)
+	}
+	for name := range new_entries {
+		_, pres := old_entries[name]
+		if !pres {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var old_inode, new_inode *Inode
+
+		old_entry, pres := old_entries[name]
+		if pres {
+			old_inode, err = before.GetInode(old_entry.Inode)
+			if err != nil {
+				continue
+			}
+		}
+
+		new_entry, pres := new_entries[name]
+		if pres {
+			new_inode, err = after.GetInode(new_entry.Inode)
+			if err != nil {
+				continue
+			}
+		}
+
+		change := ""
+		switch {
+		case old_inode == nil:
+			change = "added"
+		case new_inode == nil:
+			change = "removed"
+		case isModified(old_inode, new_inode):
+			change = "modified"
+		}
+
+		full_path := path.Join(dir_path, name)
+		if change != "" {
+			select {
+			case <-ctx.Done():
+				return nil
+			case output_chan <- &DiffEntry{
+				Path:   full_path,
+				Change: change,
+				Old:    old_inode,
+				New:    new_inode,
+			}:
+			}
+		}
+
+		if (old_inode != nil && old_inode.IsDir()) ||
+			(new_inode != nil && new_inode.IsDir()) {
+			err = diffDirectory(ctx, full_path, before, after,
+				old_inode, new_inode, depth+1, output_chan)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Directories are only considered modified if they changed type -
+// changes to their content are reported separately.
+func isModified(before, after *Inode) bool {
+	if before.Number != after.Number || before.Mode != after.Mode {
+		return true
+	}
+
+	if before.IsDir() {
+		return false
+	}
+
+	return before.Size != after.Size || !before.Mtime.Equal(after.Mtime) ||
+		!before.Ctime.Equal(after.Ctime) || before.BSDFlags != after.BSDFlags
+}
@@ -0,0 +1,542 @@
+package apfs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	// Extended field types stored after the inode
+	inoExtTypeName    = 4
+	inoExtTypeDstream = 8
+
+	// Xattr flags
+	xattrDataStream   = 0x1
+	xattrDataEmbedded = 0x2
+
+	bsdFlagCompressed = 0x20
+
+	decmpfsMagic      = "fpmc"
+	decmpfsHeaderSize = 16
+
+	symlinkXattr      = "com.apple.fs.symlink"
+	decmpfsXattr      = "com.apple.decmpfs"
+	resourceForkXattr = "com.apple.ResourceFork"
+
+	fileExtentLenMask = 0x00ffffffffffffff
+
+	maxExtents            = 1024 * 1024
+	maxDecompressedSize   = 256 * 1024 * 1024
+	compressionBlockSize  = 0x10000
+	maxCompressionBlocks  = maxDecompressedSize / compressionBlockSize
+	resourceForkHeaderLen = 16
+)
+
+type Inode struct {
+	volume *Volume
+
+	Number          uint64
+	Parent          uint64
+	PrivateID       uint64
+	Nlink           int32
+	BSDFlags        uint32
+	Uid             uint32
+	Gid             uint32
+	Mode            uint16
+	ProtectionClass string
+	Name            string
+
+	// Size of the data stream. Compressed files store their data
+	// in extended attributes and have no data stream.
+	Size int64
+
+	Btime time.Time
+	Mtime time.Time
+	Ctime time.Time
+	Atime time.Time
+}
+
+func newInode(volume *Volume, number uint64, raw []byte) *Inode {
+	result := &Inode{
+		volume: volume,
+		Number: number,
+	}
+
+	if len(raw) < 92 {
+		return result
+	}
+
+	result.Parent = binary.LittleEndian.Uint64(raw)
+	result.PrivateID = binary.LittleEndian.Uint64(raw[8:])
+	result.Btime = apfsTime(binary.LittleEndian.Uint64(raw[16:]))
+	result.Mtime = apfsTime(binary.LittleEndian.Uint64(raw[24:]))
+	result.Ctime = apfsTime(binary.LittleEndian.Uint64(raw[32:]))
+	result.Atime = apfsTime(binary.LittleEndian.Uint64(raw[40:]))
+	result.Nlink = int32(binary.LittleEndian.Uint32(raw[56:]))
+	result.ProtectionClass = protectionClass(binary.LittleEndian.Uint32(raw[60:]))
+	result.BSDFlags = binary.LittleEndian.Uint32(raw[68:])
+	result.Uid = binary.LittleEndian.Uint32(raw[72:])
+	result.Gid = binary.LittleEndian.Uint32(raw[76:])
+	result.Mode = binary.LittleEndian.Uint16(raw[80:])
+
+	result.parseExtendedFields(raw[92:])
+
+	return result
+}
+
+// Extended fields consist of a table of field headers followed by
+// the field data, each aligned to 8 bytes.
+func (self *Inode) parseExtendedFields(buf []byte) {
+	if len(buf) < 4 {
+		return
+	}
+
+	count := int(binary.LittleEndian.Uint16(buf))
+	data_offset := 4 + count*4
+	for i := 0; i < count; i++ {
+		header := buf[4+i*4:]
+		if len(header) < 4 {
+			return
+		}
+
+		field_type := header[0]
+		size := int(binary.LittleEndian.Uint16(header[2:]))
+		if data_offset+size > len(buf) {
+			return
+		}
+		data := buf[data_offset : data_offset+size]
+
+		switch field_type {
+		case inoExtTypeName:
+			self.Name = cString(data)
+
+		case inoExtTypeDstream:
+			if len(data) >= 8 {
+				self.Size = int64(binary.LittleEndian.Uint64(data))
+			}
+		}
+
+		data_offset += (size + 7) &^ 7
+	}
+}
+
+func (self *Inode) IsDir() bool {
+	return self.Mode&0xf000 == 0x4000
+}
+
+func (self *Inode) IsLink() bool {
+	return self.Mode&0xf000 == 0xa000
+}
+
+func (self *Inode) IsCompressed() bool {
+	return self.BSDFlags&bsdFlagCompressed != 0
+}
+
+func (self *Inode) FileMode() os.FileMode {
+	result := os.FileMode(self.Mode & 0777)
+	switch self.Mode & 0xf000 {
+	case 0x4000:
+		result |= os.ModeDir
+	case 0xa000:
+		result |= os.ModeSymlink
+	case 0x2000:
+		result |= os.ModeDevice | os.ModeCharDevice
+	case 0x6000:
+		result |= os.ModeDevice
+	case 0x1000:
+		result |= os.ModeNamedPipe
+	case 0xc000:
+		result |= os.ModeSocket
+	}
+	return result
+}
+
+func (self *Inode) LinkTarget() (string, error) {
+	data, err := self.GetXattr(symlinkXattr)
+	if err != nil {
+		return "", err
+	}
+	return cString(data), nil
+}
+
+// List the names of the extended attributes.
+func (self *Inode) Xattrs() ([]string, error) {
+	var result []string
+	err := self.volume.records(self.Number, apfsTypeXattr,
+		func(key, value []byte) error {
+			if len(key) >= 10 {
+				result = append(result, cString(key[10:]))
+			}
+			return nil
+		})
+	return result, err
+}
+
+func (self *Inode) GetXattr(name string) ([]byte, error) {
+	var result []byte
+	var found bool
+
+	err := self.volume.records(self.Number, apfsTypeXattr,
+		func(key, value []byte) error {
+			if found || len(key) < 10 || len(value) < 4 ||
+				cString(key[10:]) != name {
+				return nil
+			}
+			found = true
+
+			flags := binary.LittleEndian.Uint16(value)
+			length := int(binary.LittleEndian.Uint16(value[2:]))
+			data := value[4:]
+			if length > len(data) {
+				return errors.New("apfs: invalid xattr")
+			}
+			data = data[:length]
+
+			if flags&xattrDataEmbedded != 0 {
+				result = append([]byte{}, data...)
+				return nil
+			}
+
+			// Large attributes are stored in their own data
+			// stream.
+			if flags&xattrDataStream == 0 || len(data) < 16 {
+				return errors.New("apfs: invalid xattr")
+			}
+
+			size := int64(binary.LittleEndian.Uint64(data[8:]))
+			if size > maxDecompressedSize {
+				return errors.New("apfs: xattr too large")
+			}
+
+			reader, err := self.volume.newExtentReader(
+				binary.LittleEndian.Uint64(data), size)
+			if err != nil {
+				return err
+			}
+
+			result = make([]byte, size)
+			n, err := reader.ReadAt(result, 0)
+			if err != nil && err != io.EOF {
+				return err
+			}
+			result = result[:n]
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, fsNotFoundError
+	}
+	return result, nil
+}
+
+// The decmpfs header of compressed files.
+type CompressionInfo struct {
+	Type             uint32
+	UncompressedSize int64
+	data             []byte
+}
+
+func (self *Inode) CompressionInfo() (*CompressionInfo, error) {
+	data, err := self.GetXattr(decmpfsXattr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < decmpfsHeaderSize || string(data[:4]) != decmpfsMagic {
+		return nil, errors.New("apfs: invalid decmpfs header")
+	}
+
+	return &CompressionInfo{
+		Type:             binary.LittleEndian.Uint32(data[4:]),
+		UncompressedSize: int64(binary.LittleEndian.Uint64(data[8:])),
+		data:             data[decmpfsHeaderSize:],
+	}, nil
+}
+
+// The logical size of the file content.
+func (self *Inode) LogicalSize() int64 {
+	if self.IsCompressed() {
+		info, err := self.CompressionInfo()
+		if err == nil {
+			return info.UncompressedSize
+		}
+	}
+	return self.Size
+}
+
+type InodeReader interface {
+	io.ReaderAt
+	Size() int64
+}
+
+func (self *Inode) Reader() (InodeReader, error) {
+	if self.IsCompressed() {
+		return self.decompress()
+	}
+
+	return self.volume.newExtentReader(self.PrivateID, self.Size)
+}
+
+// Decompress the file into memory. Only zlib compression is
+// currently supported.
+func (self *Inode) decompress() (InodeReader, error) {
+	info, err := self.CompressionInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.UncompressedSize > maxDecompressedSize {
+		return nil, errors.New("apfs: compressed file too large")
+	}
+
+	var data []byte
+	switch info.Type {
+	case 1:
+		data = info.data
+
+	// zlib data stored in the decmpfs attribute.
+	case 3:
+		data, err = decompressZlibBlock(info.data)
+
+	// zlib data stored in chunks in the resource fork.
+	case 4:
+		var fork []byte
+		fork, err = self.GetXattr(resourceForkXattr)
+		if err == nil {
+			data, err = decompressResourceFork(fork)
+		}
+
+	default:
+		return nil, fmt.Errorf("apfs: unsupported compression type %d", info.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > info.UncompressedSize {
+		data = data[:info.UncompressedSize]
+	}
+
+	return &bufferReader{data: data}, nil
+}
+
+// Blocks starting with 0xff (or 0x?f for the attribute) are stored
+// uncompressed.
+func decompressZlibBlock(data []byte) ([]byte, error) {
+	if len(data) > 0 && data[0]&0x0f == 0x0f {
+		return data[1:], nil
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	return ioutil.ReadAll(io.LimitReader(zr, maxDecompressedSize))
+}
+
+func decompressResourceFork(fork []byte) ([]byte, error) {
+	if len(fork) < resourceForkHeaderLen {
+		return nil, errors.New("apfs: invalid resource fork")
+	}
+
+	// The resource fork header is big endian but the compressed
+	// block table is little endian.
+	data_offset := int(binary.BigEndian.Uint32(fork))
+	if data_offset+8 > len(fork) {
+		return nil, errors.New("apfs: invalid resource fork")
+	}
+
+	resource := fork[data_offset+4:]
+	count := int(binary.LittleEndian.Uint32(resource))
+	if count > maxCompressionBlocks || 4+count*8 > len(resource) {
+		return nil, errors.New("apfs: invalid resource fork")
+	}
+
+	var result []byte
+	for i := 0; i < count; i++ {
+		offset := int(binary.LittleEndian.Uint32(resource[4+i*8:]))
+		size := int(binary.LittleEndian.Uint32(resource[8+i*8:]))
+		if offset+size > len(resource) {
+			return nil, errors.New("apfs: invalid resource fork")
+		}
+
+		block, err := decompressZlibBlock(resource[offset : offset+size])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, block...)
+	}
+
+	return result, nil
+}
+
+type bufferReader struct {
+	data []byte
+}
+
+func (self *bufferReader) Size() int64 {
+	return int64(len(self.data))
+}
+
+func (self *bufferReader) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset >= int64(len(self.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(buf, self.data[offset:])
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+type extent struct {
+	logical  int64
+	length   int64
+	physical uint64
+}
+
+// Read a data stream through its file extents.
+type ExtentReader struct {
+	volume  *Volume
+	size    int64
+	extents []extent
+}
+
+func (self *Volume) newExtentReader(id uint64, size int64) (*ExtentReader, error) {
+	result := &ExtentReader{
+		volume: self,
+		size:   size,
+	}
+
+	add_extent := func(logical, len_and_flags, physical uint64) error {
+		if len(result.extents) > maxExtents {
+			return errors.New("apfs: too many extents")
+		}
+		result.extents = append(result.extents, extent{
+			logical:  int64(logical),
+			length:   int64(len_and_flags & fileExtentLenMask),
+			physical: physical,
+		})
+		return nil
+	}
+
+	var err error
+	if self.fext != nil {
+		err = self.fext.walk(func(key []byte) int {
+			if len(key) < 16 {
+				return -1
+			}
+			return compareUint64Pair(binary.LittleEndian.Uint64(key), 0, id, 0)
+		}, func(key, value []byte) error {
+			if len(value) < 16 {
+				return nil
+			}
+			return add_extent(binary.LittleEndian.Uint64(key[8:]),
+				binary.LittleEndian.Uint64(value),
+				binary.LittleEndian.Uint64(value[8:]))
+		})
+
+	} else {
+		err = self.records(id, apfsTypeFileExtent, func(key, value []byte) error {
+			if len(key) < 16 || len(value) < 16 {
+				return nil
+			}
+			return add_extent(binary.LittleEndian.Uint64(key[8:]),
+				binary.LittleEndian.Uint64(value),
+				binary.LittleEndian.Uint64(value[8:]))
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result.extents, func(i, j int) bool {
+		return result.extents[i].logical < result.extents[j].logical
+	})
+
+	return result, nil
+}
+
+func (self *ExtentReader) Size() int64 {
+	return self.size
+}
+
+func (self *ExtentReader) findExtent(offset int64) *extent {
+	idx := sort.Search(len(self.extents), func(i int) bool {
+		return self.extents[i].logical+self.extents[i].length > offset
+	})
+	if idx < len(self.extents) && self.extents[idx].logical <= offset {
+		return &self.extents[idx]
+	}
+	return nil
+}
+
+func (self *ExtentReader) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset >= self.size {
+		return 0, io.EOF
+	}
+
+	to_read := buf
+	if offset+int64(len(to_read)) > self.size {
+		to_read = to_read[:self.size-offset]
+	}
+
+	block_size := self.volume.ctx.block_size
+	n := 0
+	for n < len(to_read) {
+		current := offset + int64(n)
+		e := self.findExtent(current)
+
+		// Read up to the end of the extent or the start of the
+		// next one.
+		length := int64(len(to_read) - n)
+		if e != nil {
+			if available := e.logical + e.length - current; length > available {
+				length = available
+			}
+		} else {
+			idx := sort.Search(len(self.extents), func(i int) bool {
+				return self.extents[i].logical > current
+			})
+			if idx < len(self.extents) &&
+				self.extents[idx].logical-current < length {
+				length = self.extents[idx].logical - current
+			}
+		}
+
+		chunk := to_read[n : n+int(length)]
+
+		// Sparse regions read as zeros.
+		if e == nil || e.physical == 0 {
+			for i := range chunk {
+				chunk[i] = 0
+			}
+		} else {
+			disk_offset := int64(e.physical)*block_size + current - e.logical
+			_, err := self.volume.ctx.reader.ReadAt(chunk, disk_offset)
+			if err != nil && err != io.EOF {
+				return n, err
+			}
+		}
+		n += int(length)
+	}
+
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,64 @@
+package apfs
+
+import (
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/constants"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/readers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+func GetAPFSContext(scope vfilter.Scope,
+	device, fullpath *accessors.OSPath, accessor string) (
+	result *APFSContext, err error) {
+
+	if device == nil {
+		device, err = fullpath.Delegate(scope)
+		if err != nil {
+			return nil, err
+		}
+		accessor = fullpath.DelegateAccessor()
+	}
+
+	return GetAPFSCache(scope, device, accessor)
+}
+
+func GetAPFSCache(scope vfilter.Scope,
+	device *accessors.OSPath, accessor string) (*APFSContext, error) {
+	key := "apfs_cache" + device.String() + accessor
+
+	// Get the cache context from the root scope's cache
+	cache_ctx, ok := vql_subsystem.CacheGet(scope, key).(*APFSContext)
+	if !ok {
+		err := vql_subsystem.CheckFilesystemAccess(scope, accessor)
+		if err != nil {
+			return nil, err
+		}
+
+		lru_size := vql_subsystem.GetIntFromRow(
+			scope, scope, constants.NTFS_CACHE_SIZE)
+
+		paged_reader, err := readers.NewPagedReader(
+			scope, accessor, device, int(lru_size))
+		if err != nil {
+			return nil, err
+		}
+
+		cache_ctx, err = NewAPFSContext(paged_reader)
+		if err != nil {
+			paged_reader.Close()
+			return nil, err
+		}
+		vql_subsystem.CacheSet(scope, key, cache_ctx)
+
+		// Close the device when we are done with this query.
+		err = vql_subsystem.GetRootScope(scope).AddDestructor(func() {
+			paged_reader.Close()
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cache_ctx, nil
+}
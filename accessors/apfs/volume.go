@@ -0,0 +1,454 @@
+package apfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Volume flags
+	apfsFSUnencrypted = 0x1
+	apfsFSOneKey      = 0x8
+
+	// Incompatible volume features
+	apfsIncompatCaseInsensitive = 0x1
+	apfsIncompatSealedVolume    = 0x20
+
+	// Filesystem record types
+	apfsTypeSnapMetadata = 1
+	apfsTypeInode        = 3
+	apfsTypeXattr        = 4
+	apfsTypeFileExtent   = 8
+	apfsTypeDirRecord    = 9
+
+	objIdMask     = 0x0fffffffffffffff
+	objTypeShift  = 60
+	rootDirInode  = 2
+	maxSnapshots  = 10000
+	maxVolumeName = 256
+)
+
+var (
+	volumeRoles = map[uint16]string{
+		0x0001: "System",
+		0x0002: "User",
+		0x0004: "Recovery",
+		0x0008: "VM",
+		0x0010: "Preboot",
+		0x0020: "Installer",
+		0x0040: "Data",
+		0x0080: "Baseband",
+		0x00c0: "Update",
+		0x0100: "xART",
+		0x0140: "Hardware",
+		0x0180: "Backup",
+		0x01c0: "Sidecar",
+		0x0240: "Enterprise",
+		0x02c0: "Prelogin",
+	}
+
+	protectionClasses = map[uint32]string{
+		0:  "None",
+		1:  "A",
+		2:  "B",
+		3:  "C",
+		4:  "D",
+		6:  "F",
+		14: "M",
+	}
+)
+
+// The encryption state of the volume metadata.
+type CryptoState struct {
+	MajorVersion    uint16
+	MinorVersion    uint16
+	Flags           uint32
+	KeyClass        string
+	KeyOSVersion    uint32
+	KeyRevision     uint16
+	Encrypted       bool
+	OneKey          bool
+	KeybagAvailable bool
+}
+
+type Volume struct {
+	ctx  *APFSContext
+	omap *objectMap
+
+	// The fs tree holds all the filesystem records.
+	root *btree
+
+	// Sealed volumes store file extents in a separate tree.
+	fext *btree
+
+	snap_meta *btree
+
+	Index            uint32
+	Name             string
+	UUID             string
+	Role             string
+	VolumeGroup      string
+	FormattedBy      string
+	Features         uint64
+	IncompatFeatures uint64
+	Flags            uint64
+	NumFiles         uint64
+	NumDirectories   uint64
+	NumSymlinks      uint64
+	NumSnapshots     uint64
+	LastModified     time.Time
+	Crypto           CryptoState
+
+	// The transaction id used to resolve objects in this volume.
+	XID uint64
+
+	// Set when this volume is a snapshot.
+	Snapshot *Snapshot
+}
+
+// Open the volume superblock at paddr. Snapshots are resolved
+// through the current object map of the volume which retains the
+// older object versions.
+func (self *APFSContext) openVolume(
+	paddr, xid uint64, omap *objectMap) (*Volume, error) {
+	sb, err := self.readObject(paddr, objTypeFS)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(sb[32:36]) != apfsMagic {
+		return nil, errors.New("apfs: invalid volume superblock magic")
+	}
+
+	result := &Volume{
+		ctx:              self,
+		XID:              xid,
+		Index:            binary.LittleEndian.Uint32(sb[36:]),
+		Features:         binary.LittleEndian.Uint64(sb[40:]),
+		IncompatFeatures: binary.LittleEndian.Uint64(sb[56:]),
+		NumFiles:         binary.LittleEndian.Uint64(sb[184:]),
+		NumDirectories:   binary.LittleEndian.Uint64(sb[192:]),
+		NumSymlinks:      binary.LittleEndian.Uint64(sb[200:]),
+		NumSnapshots:     binary.LittleEndian.Uint64(sb[216:]),
+		UUID:             formatUUID(sb[240:256]),
+		LastModified:     apfsTime(binary.LittleEndian.Uint64(sb[256:])),
+		Flags:            binary.LittleEndian.Uint64(sb[264:]),
+		FormattedBy:      cString(sb[272:304]),
+		Name:             cString(sb[704 : 704+maxVolumeName]),
+		Role:             volumeRole(binary.LittleEndian.Uint16(sb[964:])),
+	}
+
+	if !bytes.Equal(sb[1008:1024], make([]byte, 16)) {
+		result.VolumeGroup = formatUUID(sb[1008:1024])
+	}
+
+	crypto := sb[96:116]
+	result.Crypto = CryptoState{
+		MajorVersion:    binary.LittleEndian.Uint16(crypto),
+		MinorVersion:    binary.LittleEndian.Uint16(crypto[2:]),
+		Flags:           binary.LittleEndian.Uint32(crypto[4:]),
+		KeyClass:        protectionClass(binary.LittleEndian.Uint32(crypto[8:])),
+		KeyOSVersion:    binary.LittleEndian.Uint32(crypto[12:]),
+		KeyRevision:     binary.LittleEndian.Uint16(crypto[16:]),
+		Encrypted:       result.Flags&apfsFSUnencrypted == 0,
+		OneKey:          result.Flags&apfsFSOneKey != 0,
+		KeybagAvailable: self.KeybagBlock != 0,
+	}
+
+	result.omap = omap
+	if result.omap == nil {
+		result.omap, err = self.openObjectMap(binary.LittleEndian.Uint64(sb[128:]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.root = result.newTree(
+		binary.LittleEndian.Uint64(sb[136:]),
+		binary.LittleEndian.Uint32(sb[116:]))
+
+	result.snap_meta = result.newTree(
+		binary.LittleEndian.Uint64(sb[152:]),
+		binary.LittleEndian.Uint32(sb[124:]))
+
+	if result.IncompatFeatures&apfsIncompatSealedVolume != 0 {
+		fext_oid := binary.LittleEndian.Uint64(sb[1032:])
+		if fext_oid != 0 {
+			result.fext = result.newTree(fext_oid,
+				binary.LittleEndian.Uint32(sb[1040:]))
+		}
+	}
+
+	return result, nil
+}
+
+func (self *Volume) newTree(oid uint64, tree_type uint32) *btree {
+	result := &btree{
+		ctx:  self.ctx,
+		root: oid,
+	}
+
+	if tree_type&objPhysical == 0 {
+		result.omap = self.omap
+		result.xid = self.XID
+	}
+	return result
+}
+
+func (self *Volume) IsCaseInsensitive() bool {
+	return self.IncompatFeatures&apfsIncompatCaseInsensitive != 0
+}
+
+// Visit all the filesystem records of a given type for the object.
+func (self *Volume) records(oid uint64, record_type uint8,
+	cb func(key, value []byte) error) error {
+	err := self.root.walk(func(key []byte) int {
+		if len(key) < 8 {
+			return -1
+		}
+		header := binary.LittleEndian.Uint64(key)
+		return compareUint64Pair(header&objIdMask, header>>objTypeShift,
+			oid, uint64(record_type))
+	}, cb)
+
+	if err != nil && self.Crypto.Encrypted {
+		return fmt.Errorf("apfs: volume %v is encrypted: %w", self.Name, err)
+	}
+	return err
+}
+
+type Snapshot struct {
+	XID        uint64
+	Name       string
+	Created    time.Time
+	Changed    time.Time
+	Inode      uint64
+	Flags      uint32
+	sblock_oid uint64
+}
+
+// List the snapshots of the volume.
+func (self *Volume) Snapshots() ([]*Snapshot, error) {
+	if self.snap_meta.root == 0 {
+		return nil, nil
+	}
+
+	var result []*Snapshot
+	err := self.snap_meta.walk(func(key []byte) int {
+		return 0
+	}, func(key, value []byte) error {
+		if len(key) < 8 || binary.LittleEndian.Uint64(key)>>objTypeShift !=
+			apfsTypeSnapMetadata || len(value) < 50 {
+			return nil
+		}
+
+		if len(result) > maxSnapshots {
+			return errors.New("apfs: too many snapshots")
+		}
+
+		name_len := int(binary.LittleEndian.Uint16(value[48:]))
+		if 50+name_len > len(value) {
+			name_len = len(value) - 50
+		}
+
+		result = append(result, &Snapshot{
+			XID:        binary.LittleEndian.Uint64(key) & objIdMask,
+			sblock_oid: binary.LittleEndian.Uint64(value[8:]),
+			Created:    apfsTime(binary.LittleEndian.Uint64(value[16:])),
+			Changed:    apfsTime(binary.LittleEndian.Uint64(value[24:])),
+			Inode:      binary.LittleEndian.Uint64(value[32:]),
+			Flags:      binary.LittleEndian.Uint32(value[44:]),
+			Name:       cString(value[50 : 50+name_len]),
+		})
+		return nil
+	})
+
+	return result, err
+}
+
+// Open the volume as it was at the time the snapshot was taken.
+func (self *Volume) OpenSnapshot(snapshot *Snapshot) (*Volume, error) {
+	result, err := self.ctx.openVolume(
+		snapshot.sblock_oid, snapshot.XID, self.omap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Volume names are used to address the volume so keep the
+	// current name even if the volume was renamed since.
+	result.Name = self.Name
+	result.Snapshot = snapshot
+	return result, nil
+}
+
+func (self *Volume) GetSnapshot(name string) (*Snapshot, error) {
+	snapshots, err := self.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range snapshots {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return nil, fsNotFoundError
+}
+
+func (self *Volume) GetInode(number uint64) (*Inode, error) {
+	var result *Inode
+	err := self.records(number, apfsTypeInode, func(key, value []byte) error {
+		if result == nil {
+			result = newInode(self, number, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, fsNotFoundError
+	}
+	return result, nil
+}
+
+type DirEntry struct {
+	Name      string
+	Inode     uint64
+	Type      uint16
+	DateAdded time.Time
+}
+
+func (self *Volume) ReadDir(inode *Inode) ([]*DirEntry, error) {
+	if !inode.IsDir() {
+		return nil, errors.New("apfs: not a directory")
+	}
+
+	var result []*DirEntry
+	err := self.records(inode.Number, apfsTypeDirRecord, func(key, value []byte) error {
+		if len(value) < 18 {
+			return nil
+		}
+
+		name, ok := parseDirRecordName(key)
+		if !ok {
+			return nil
+		}
+
+		result = append(result, &DirEntry{
+			Name:      name,
+			Inode:     binary.LittleEndian.Uint64(value),
+			DateAdded: apfsTime(binary.LittleEndian.Uint64(value[8:])),
+			Type:      binary.LittleEndian.Uint16(value[16:]) & 0xf,
+		})
+		return nil
+	})
+
+	return result, err
+}
+
+// Directory record keys contain the name either with a hash (on
+// case or normalization insensitive volumes) or just with a length.
+func parseDirRecordName(key []byte) (string, bool) {
+	if len(key) >= 12 {
+		name_len := int(binary.LittleEndian.Uint32(key[8:]) & 0x3ff)
+		if 12+name_len == len(key) {
+			return cString(key[12:]), true
+		}
+	}
+
+	if len(key) >= 10 {
+		name_len := int(binary.LittleEndian.Uint16(key[8:]))
+		if 10+name_len == len(key) {
+			return cString(key[10:]), true
+		}
+	}
+
+	return "", false
+}
+
+func (self *Volume) OpenComponents(components []string) (*Inode, error) {
+	inode, err := self.GetInode(rootDirInode)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range components {
+		if !inode.IsDir() {
+			return nil, fsNotFoundError
+		}
+
+		entries, err := self.ReadDir(inode)
+		if err != nil {
+			return nil, err
+		}
+
+		var next uint64
+		for _, entry := range entries {
+			if entry.Name == component ||
+				(self.IsCaseInsensitive() &&
+					strings.EqualFold(entry.Name, component)) {
+				next = entry.Inode
+				break
+			}
+		}
+
+		if next == 0 {
+			return nil, fsNotFoundError
+		}
+
+		inode, err = self.GetInode(next)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return inode, nil
+}
+
+func volumeRole(role uint16) string {
+	if role == 0 {
+		return ""
+	}
+
+	name, pres := volumeRoles[role]
+	if !pres {
+		return fmt.Sprintf("%#x", role)
+	}
+	return name
+}
+
+func protectionClass(class uint32) string {
+	name, pres := protectionClasses[class]
+	if !pres {
+		return fmt.Sprintf("%d", class)
+	}
+	return name
+}
+
+// APFS timestamps are nanoseconds since the epoch.
+func apfsTime(ns uint64) time.Time {
+	return time.Unix(0, int64(ns)).UTC()
+}
+
+func cString(b []byte) string {
+	idx := bytes.IndexByte(b, 0)
+	if idx >= 0 {
+		b = b[:idx]
+	}
+	return string(b)
+}
+
+func (self *APFSContext) GetVolume(name string) (*Volume, error) {
+	for _, volume := range self.Volumes {
+		if volume.Name == name {
+			return volume, nil
+		}
+	}
+	return nil, fsNotFoundError
+}
@@ -0,0 +1,250 @@
+//go:build linux
+// +build linux
+
+// An accessor to read files inside a container's mount namespace.
+//
+// Paths are of the form /<container>/path/in/container where
+// container is the container id, an id prefix or its name. Files are
+// read through /proc/<pid>/root of the container's init process.
+
+package container
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/json"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/linux/containers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	ErrNotFound = errors.New("container not found")
+)
+
+// Report paths relative to the container rather than the delegate
+// /proc/<pid>/root path.
+type ContainerFileInfo struct {
+	accessors.FileInfo
+
+	prefix        *accessors.OSPath
+	remove_prefix *accessors.OSPath
+}
+
+func (self ContainerFileInfo) FullPath() string {
+	return self.OSPath().String()
+}
+
+func (self ContainerFileInfo) Name() string {
+	return self.OSPath().Basename()
+}
+
+func (self ContainerFileInfo) OSPath() *accessors.OSPath {
+	trimmed := self.FileInfo.OSPath().TrimComponents(
+		self.remove_prefix.Components...)
+	return self.prefix.Append(trimmed.Components...)
+}
+
+type ContainerFileSystemAccessor struct {
+	scope vfilter.Scope
+
+	mu         sync.Mutex
+	containers []*containers.Container
+}
+
+func (self *ContainerFileSystemAccessor) New(scope vfilter.Scope) (
+	accessors.FileSystemAccessor, error) {
+	err := vql_subsystem.CheckAccess(scope, acls.FILESYSTEM_READ)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContainerFileSystemAccessor{scope: scope}, nil
+}
+
+func (self *ContainerFileSystemAccessor) ParsePath(path string) (
+	*accessors.OSPath, error) {
+	return accessors.NewLinuxOSPath(path)
+}
+
+func (self *ContainerFileSystemAccessor) listContainers() []*containers.Container {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.containers == nil {
+		self.containers = containers.ListContainers(
+			context.Background(), containers.DefaultOptions())
+	}
+	return self.containers
+}
+
+// Map the container path to the delegate path in the host's /proc
+func (self *ContainerFileSystemAccessor) getDelegate(
+	path *accessors.OSPath) (
+	accessors.FileSystemAccessor, *accessors.OSPath, *accessors.OSPath, error) {
+
+	c := containers.FindContainer(self.listContainers(), path.Components[0])
+	if c == nil || c.Pid <= 0 {
+		return nil, nil, nil, ErrNotFound
+	}
+
+	delegate, err := accessors.GetAccessor("file", self.scope)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	root, err := delegate.ParsePath("/proc")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	root = root.Append(strconv.Itoa(c.Pid), "root")
+
+	return delegate, root, root.Append(path.Components[1:]...), nil
+}
+
+func (self *ContainerFileSystemAccessor) wrap(
+	path *accessors.OSPath, root *accessors.OSPath,
+	info accessors.FileInfo) accessors.FileInfo {
+	prefix := path.Copy()
+	prefix.Components = []string{path.Components[0]}
+
+	return &ContainerFileInfo{
+		FileInfo:      info,
+		prefix:        prefix,
+		remove_prefix: root,
+	}
+}
+
+func (self *ContainerFileSystemAccessor) ReadDir(path string) (
+	[]accessors.FileInfo, error) {
+	full_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.ReadDirWithOSPath(full_path)
+}
+
+func (self *ContainerFileSystemAccessor) ReadDirWithOSPath(
+	path *accessors.OSPath) ([]accessors.FileInfo, error) {
+
+	// The top level lists the running containers.
+	if len(path.Components) == 0 {
+		var result []accessors.FileInfo
+		for _, c := range self.listContainers() {
+			if c.Pid <= 0 {
+				continue
+			}
+
+			result = append(result, &accessors.VirtualFileInfo{
+				IsDir_: true,
+				Path:   path.Append(c.Id),
+				Mtime_: c.Created,
+				Data_: ordereddict.NewDict().
+					Set("Name", c.Name).
+					Set("Runtime", c.Runtime).
+					Set("Image", c.Image).
+					Set("Pid", c.Pid),
+			})
+		}
+		return result, nil
+	}
+
+	delegate, root, delegate_path, err := self.getDelegate(path)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := delegate.ReadDirWithOSPath(delegate_path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]accessors.FileInfo, 0, len(children))
+	for _, child := range children {
+		result = append(result, self.wrap(path, root, child))
+	}
+	return result, nil
+}
+
+func (self *ContainerFileSystemAccessor) Open(path string) (
+	accessors.ReadSeekCloser, error) {
+	full_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.OpenWithOSPath(full_path)
+}
+
+func (self *ContainerFileSystemAccessor) OpenWithOSPath(
+	path *accessors.OSPath) (accessors.ReadSeekCloser, error) {
+	if len(path.Components) == 0 {
+		return nil, errors.New("container: can not open the root")
+	}
+
+	delegate, _, delegate_path, err := self.getDelegate(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return delegate.OpenWithOSPath(delegate_path)
+}
+
+func (self *ContainerFileSystemAccessor) Lstat(path string) (
+	accessors.FileInfo, error) {
+	full_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.LstatWithOSPath(full_path)
+}
+
+func (self *ContainerFileSystemAccessor) LstatWithOSPath(
+	path *accessors.OSPath) (accessors.FileInfo, error) {
+	if len(path.Components) == 0 {
+		return &accessors.VirtualFileInfo{
+			IsDir_: true,
+			Path:   path,
+		}, nil
+	}
+
+	delegate, root, delegate_path, err := self.getDelegate(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := delegate.LstatWithOSPath(delegate_path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.wrap(path, root, info), nil
+}
+
+func init() {
+	json.RegisterCustomEncoder(&ContainerFileInfo{}, accessors.MarshalGlobFileInfo)
+
+	accessors.Register("container", &ContainerFileSystemAccessor{},
+		`Access files inside a running container.
+
+Paths are of the form /<container>/path where container is the
+container id, an unambiguous id prefix or the container name (see
+the containers() plugin). Files are read through /proc/<pid>/root
+of the container's init process so the container's own mounts are
+visible.
+
+Globs must use the full container id as the first component since
+that is how the top level is listed.
+
+Note that absolute symbolic links inside the container are resolved
+relative to the host's root.
+`)
+}
@@ -0,0 +1,883 @@
+package ext4
+
+// A read only parser for the ext2/3/4 family of filesystems.
+//
+// Files are located using either the extent tree (ext4) or the
+// legacy indirect block map (ext2/3). Small files and directories
+// may be stored inline within the inode itself.
+//
+// Deleted directory entries are recovered from the slack space of
+// directory blocks. The content of deleted inodes is recovered from
+// any extents which remain in the inode - this is best effort since
+// the blocks may have already been reused.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ext4Magic       = 0xEF53
+	ext4ExtentMagic = 0xF30A
+	ext4XattrMagic  = 0xEA020000
+
+	ext4RootInode = 2
+
+	// Incompatible features
+	ext4FeatureFiletype   = 0x0002
+	ext4FeatureMetaBG     = 0x0010
+	ext4Feature64Bit      = 0x0080
+	ext4FeatureInlineData = 0x8000
+
+	// Compatible features
+	ext4FeatureSparseSuper = 0x0001
+
+	// Inode flags
+	ext4IndexFlag  = 0x00001000
+	ext4ExtentFlag = 0x00080000
+	ext4InlineFlag = 0x10000000
+
+	// Maximum depth of the extent tree.
+	ext4MaxExtentDepth = 5
+
+	// Maximum size of directory to parse.
+	ext4MaxDirectorySize = 64 * 1024 * 1024
+
+	inlineDataSize = 60
+)
+
+var (
+	fsNotFoundError = errors.New("file not found")
+)
+
+type Ext4Context struct {
+	reader io.ReaderAt
+
+	block_size       int64
+	inode_size       int64
+	inodes_count     uint32
+	inodes_per_group uint32
+	blocks_count     int64
+	incompat         uint32
+
+	Label string
+	UUID  string
+
+	// The block of the inode table for each group.
+	inode_tables []int64
+}
+
+func NewExt4Context(reader io.ReaderAt) (*Ext4Context, error) {
+	sb := make([]byte, 1024)
+	n, err := reader.ReadAt(sb, 1024)
+	if n < len(sb) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	if binary.LittleEndian.Uint16(sb[0x38:]) != ext4Magic {
+		return nil, errors.New("ext4: invalid superblock magic")
+	}
+
+	log_block_size := binary.LittleEndian.Uint32(sb[0x18:])
+	if log_block_size > 6 {
+		return nil, errors.New("ext4: invalid block size")
+	}
+
+	result := &Ext4Context{
+		reader:           reader,
+		block_size:       1024 << log_block_size,
+		inode_size:       128,
+		inodes_count:     binary.LittleEndian.Uint32(sb[0x00:]),
+		inodes_per_group: binary.LittleEndian.Uint32(sb[0x28:]),
+		blocks_count:     int64(binary.LittleEndian.Uint32(sb[0x04:])),
+		incompat:         binary.LittleEndian.Uint32(sb[0x60:]),
+		Label:            string(bytes.TrimRight(sb[0x78:0x88], "\x00")),
+		UUID:             formatUUID(sb[0x68:0x78]),
+	}
+
+	// Dynamic revision filesystems specify the inode size.
+	if binary.LittleEndian.Uint32(sb[0x4C:]) >= 1 {
+		result.inode_size = int64(binary.LittleEndian.Uint16(sb[0x58:]))
+	}
+
+	desc_size := int64(32)
+	if result.incompat&ext4Feature64Bit != 0 {
+		result.blocks_count |= int64(binary.LittleEndian.Uint32(sb[0x150:])) << 32
+		desc_size = int64(binary.LittleEndian.Uint16(sb[0xFE:]))
+		if desc_size < 32 {
+			desc_size = 32
+		}
+	}
+
+	blocks_per_group := int64(binary.LittleEndian.Uint32(sb[0x20:]))
+	first_data_block := int64(binary.LittleEndian.Uint32(sb[0x14:]))
+
+	if result.inode_size < 128 || result.inode_size > result.block_size ||
+		result.inodes_per_group == 0 || blocks_per_group == 0 {
+		return nil, errors.New("ext4: invalid superblock")
+	}
+
+	group_count := (result.blocks_count - first_data_block +
+		blocks_per_group - 1) / blocks_per_group
+	if group_count <= 0 || group_count > 1<<24 {
+		return nil, errors.New("ext4: invalid group count")
+	}
+
+	// Read the group descriptors.
+	descs_per_block := result.block_size / desc_size
+	first_meta_bg := int64(binary.LittleEndian.Uint32(sb[0x104:]))
+	sparse_super := binary.LittleEndian.Uint32(sb[0x5C:])&
+		ext4FeatureSparseSuper != 0
+
+	block := make([]byte, result.block_size)
+	current_block := int64(-1)
+	for group := int64(0); group < group_count; group++ {
+		meta_bg := group / descs_per_block
+		desc_block := first_data_block + 1 + meta_bg
+
+		// With the meta_bg feature, descriptor blocks are stored at
+		// the start of each meta group.
+		if result.incompat&ext4FeatureMetaBG != 0 && meta_bg >= first_meta_bg {
+			meta_group := meta_bg * descs_per_block
+			desc_block = first_data_block + meta_group*blocks_per_group
+			if groupHasSuper(meta_group, sparse_super) {
+				desc_block++
+			}
+		}
+
+		if desc_block != current_block {
+			err := result.readBlock(block, desc_block)
+			if err != nil {
+				return nil, err
+			}
+			current_block = desc_block
+		}
+
+		desc := block[(group%descs_per_block)*desc_size:]
+		inode_table := int64(binary.LittleEndian.Uint32(desc[0x08:]))
+		if desc_size >= 64 {
+			inode_table |= int64(binary.LittleEndian.Uint32(desc[0x28:])) << 32
+		}
+		result.inode_tables = append(result.inode_tables, inode_table)
+	}
+
+	return result, nil
+}
+
+func groupHasSuper(group int64, sparse_super bool) bool {
+	if !sparse_super || group <= 1 {
+		return true
+	}
+
+	for _, base := range []int64{3, 5, 7} {
+		value := base
+		for value < group {
+			value *= base
+		}
+		if value == group {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *Ext4Context) readBlock(buf []byte, block int64) error {
+	n, err := self.reader.ReadAt(buf, block*self.block_size)
+	if n < len(buf) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return nil
+}
+
+func (self *Ext4Context) hasFiletype() bool {
+	return self.incompat&ext4FeatureFiletype != 0
+}
+
+func (self *Ext4Context) GetInode(number uint32) (*Inode, error) {
+	if number == 0 || number > self.inodes_count {
+		return nil, fmt.Errorf("ext4: invalid inode number %v", number)
+	}
+
+	group := (number - 1) / self.inodes_per_group
+	index := int64((number - 1) % self.inodes_per_group)
+	if int(group) >= len(self.inode_tables) {
+		return nil, fmt.Errorf("ext4: invalid inode number %v", number)
+	}
+
+	buf := make([]byte, self.inode_size)
+	offset := self.inode_tables[group]*self.block_size + index*self.inode_size
+	n, err := self.reader.ReadAt(buf, offset)
+	if n < len(buf) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return newInode(self, number, buf), nil
+}
+
+// Walk the directory tree from the root to find the inode.
+func (self *Ext4Context) OpenComponents(components []string) (*Inode, error) {
+	inode, err := self.GetInode(ext4RootInode)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range components {
+		if !inode.IsDir() {
+			return nil, fsNotFoundError
+		}
+
+		entries, err := self.ReadDir(inode)
+		if err != nil {
+			return nil, err
+		}
+
+		// Prefer allocated entries but fall back to deleted entries
+		// with the same name.
+		var next uint32
+		for _, entry := range entries {
+			if entry.Name == component {
+				if !entry.Deleted {
+					next = entry.Inode
+					break
+				}
+				if next == 0 {
+					next = entry.Inode
+				}
+			}
+		}
+
+		if next == 0 {
+			return nil, fsNotFoundError
+		}
+
+		inode, err = self.GetInode(next)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return inode, nil
+}
+
+// Find all deleted inodes which still have a mode set.
+func (self *Ext4Context) DeletedInodes() ([]*Inode, error) {
+	var result []*Inode
+
+	table_size := int64(self.inodes_per_group) * self.inode_size
+	buf := make([]byte, table_size)
+	for group, table := range self.inode_tables {
+		n, _ := self.reader.ReadAt(buf, table*self.block_size)
+		for i := int64(0); (i+1)*self.inode_size <= int64(n); i++ {
+			raw := buf[i*self.inode_size : (i+1)*self.inode_size]
+			if binary.LittleEndian.Uint16(raw) == 0 ||
+				binary.LittleEndian.Uint32(raw[0x14:]) == 0 {
+				continue
+			}
+
+			number := uint32(group)*self.inodes_per_group + uint32(i) + 1
+			if number > self.inodes_count {
+				break
+			}
+
+			result = append(result, newInode(self, number,
+				append([]byte{}, raw...)))
+		}
+	}
+
+	return result, nil
+}
+
+type DirEntry struct {
+	Inode    uint32
+	Name     string
+	FileType uint8
+	Deleted  bool
+}
+
+func (self *Ext4Context) ReadDir(inode *Inode) ([]*DirEntry, error) {
+	if !inode.IsDir() {
+		return nil, errors.New("ext4: not a directory")
+	}
+
+	var result []*DirEntry
+
+	// Inline directories start with the parent inode number followed
+	// by directory entries. More entries may be stored in the
+	// extended attribute.
+	if inode.Flags&ext4InlineFlag != 0 {
+		result = append(result, &DirEntry{
+			Inode: binary.LittleEndian.Uint32(inode.raw[0x28:]),
+			Name:  "..",
+		})
+		self.parseDirBlock(inode.raw[0x28+4:0x28+inlineDataSize], false, &result)
+
+		extra, _ := inode.getXattr("data")
+		if len(extra) > 0 {
+			self.parseDirBlock(extra, false, &result)
+		}
+		return result, nil
+	}
+
+	size := inode.Size
+	if size > ext4MaxDirectorySize {
+		size = ext4MaxDirectorySize
+	}
+
+	reader, err := inode.Reader()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, self.block_size)
+	for offset := int64(0); offset < size; offset += self.block_size {
+		n, err := reader.ReadAt(buf, offset)
+		if n < len(buf) {
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+
+		// Hash tree directories keep their index in the first block
+		// and in blocks which appear to contain a single empty entry.
+		// Do not scan these for deleted entries.
+		scan_slack := true
+		if inode.Flags&ext4IndexFlag != 0 {
+			if offset == 0 || (binary.LittleEndian.Uint32(buf) == 0 &&
+				int64(binary.LittleEndian.Uint16(buf[4:])) == self.block_size) {
+				scan_slack = false
+			}
+		}
+
+		self.parseDirBlock(buf, scan_slack, &result)
+	}
+
+	return result, nil
+}
+
+func (self *Ext4Context) parseDirEntry(buf []byte) (
+	inode uint32, rec_len, name_len int, file_type uint8) {
+	inode = binary.LittleEndian.Uint32(buf)
+	rec_len = int(binary.LittleEndian.Uint16(buf[4:]))
+	if self.hasFiletype() {
+		name_len = int(buf[6])
+		file_type = buf[7]
+	} else {
+		name_len = int(binary.LittleEndian.Uint16(buf[6:]))
+	}
+	return inode, rec_len, name_len, file_type
+}
+
+func (self *Ext4Context) parseDirBlock(
+	buf []byte, scan_slack bool, result *[]*DirEntry) {
+	for pos := 0; pos+8 <= len(buf); {
+		inode, rec_len, name_len, file_type := self.parseDirEntry(buf[pos:])
+		if rec_len < 8 || rec_len%4 != 0 || pos+rec_len > len(buf) {
+			return
+		}
+
+		used := 0
+		if inode != 0 && name_len > 0 && 8+name_len <= rec_len {
+			*result = append(*result, &DirEntry{
+				Inode:    inode,
+				Name:     string(buf[pos+8 : pos+8+name_len]),
+				FileType: file_type,
+			})
+			used = (8 + name_len + 3) &^ 3
+		}
+
+		if scan_slack && used > 0 {
+			self.scanSlack(buf[pos+used:pos+rec_len], result)
+		}
+
+		pos += rec_len
+	}
+}
+
+// Deleted entries are merged into the previous entry by extending its
+// record length, so they remain in the slack after the previous
+// entry.
+func (self *Ext4Context) scanSlack(buf []byte, result *[]*DirEntry) {
+	for pos := 0; pos+8 <= len(buf); {
+		inode, rec_len, name_len, file_type := self.parseDirEntry(buf[pos:])
+		if inode == 0 || inode > self.inodes_count ||
+			name_len == 0 || pos+8+name_len > len(buf) ||
+			rec_len < 8+name_len || rec_len%4 != 0 ||
+			(self.hasFiletype() && (file_type == 0 || file_type > 7)) {
+			pos += 4
+			continue
+		}
+
+		name := buf[pos+8 : pos+8+name_len]
+		if !utf8.Valid(name) || bytes.IndexAny(name, "/\x00") >= 0 {
+			pos += 4
+			continue
+		}
+
+		*result = append(*result, &DirEntry{
+			Inode:    inode,
+			Name:     string(name),
+			FileType: file_type,
+			Deleted:  true,
+		})
+		pos += (8 + name_len + 3) &^ 3
+	}
+}
+
+type Inode struct {
+	ctx *Ext4Context
+	raw []byte
+
+	Number uint32
+	Mode   uint16
+	Uid    uint32
+	Gid    uint32
+	Size   int64
+	Links  uint16
+	Flags  uint32
+
+	Atime time.Time
+	Mtime time.Time
+	Ctime time.Time
+	Btime time.Time
+	Dtime time.Time
+}
+
+func newInode(ctx *Ext4Context, number uint32, raw []byte) *Inode {
+	result := &Inode{
+		ctx:    ctx,
+		raw:    raw,
+		Number: number,
+		Mode:   binary.LittleEndian.Uint16(raw[0x00:]),
+		Uid: uint32(binary.LittleEndian.Uint16(raw[0x02:])) |
+			uint32(binary.LittleEndian.Uint16(raw[0x78:]))<<16,
+		Gid: uint32(binary.LittleEndian.Uint16(raw[0x18:])) |
+			uint32(binary.LittleEndian.Uint16(raw[0x7A:]))<<16,
+		Size: int64(binary.LittleEndian.Uint32(raw[0x04:])) |
+			int64(binary.LittleEndian.Uint32(raw[0x6C:]))<<32,
+		Links: binary.LittleEndian.Uint16(raw[0x1A:]),
+		Flags: binary.LittleEndian.Uint32(raw[0x20:]),
+	}
+
+	extra_size := 0
+	if len(raw) > 0x82 {
+		extra_size = int(binary.LittleEndian.Uint16(raw[0x80:]))
+	}
+
+	// Timestamps may have an extra field with nanoseconds and epoch
+	// bits.
+	timestamp := func(offset, extra_offset int) time.Time {
+		sec := int64(int32(binary.LittleEndian.Uint32(raw[offset:])))
+		var nsec int64
+		if extra_offset > 0 && extra_offset+4 <= 0x80+extra_size &&
+			extra_offset+4 <= len(raw) {
+			extra := binary.LittleEndian.Uint32(raw[extra_offset:])
+			sec += int64(extra&3) << 32
+			nsec = int64(extra >> 2)
+		}
+		if sec == 0 && nsec == 0 {
+			return time.Time{}
+		}
+		return time.Unix(sec, nsec).UTC()
+	}
+
+	result.Atime = timestamp(0x08, 0x8C)
+	result.Ctime = timestamp(0x0C, 0x84)
+	result.Mtime = timestamp(0x10, 0x88)
+	result.Dtime = timestamp(0x14, 0)
+	if 0x94 <= 0x80+extra_size && 0x94 <= len(raw) {
+		result.Btime = timestamp(0x90, 0x94)
+	}
+
+	return result
+}
+
+func (self *Inode) IsDir() bool {
+	return self.Mode&0xF000 == 0x4000
+}
+
+func (self *Inode) IsLink() bool {
+	return self.Mode&0xF000 == 0xA000
+}
+
+func (self *Inode) IsDeleted() bool {
+	return !self.Dtime.IsZero()
+}
+
+func (self *Inode) FileMode() os.FileMode {
+	result := os.FileMode(self.Mode & 0777)
+	switch self.Mode & 0xF000 {
+	case 0x4000:
+		result |= os.ModeDir
+	case 0xA000:
+		result |= os.ModeSymlink
+	case 0x1000:
+		result |= os.ModeNamedPipe
+	case 0xC000:
+		result |= os.ModeSocket
+	case 0x2000:
+		result |= os.ModeDevice | os.ModeCharDevice
+	case 0x6000:
+		result |= os.ModeDevice
+	}
+	return result
+}
+
+// Read the value of an extended attribute in the system namespace
+// stored within the inode.
+func (self *Inode) getXattr(name string) ([]byte, error) {
+	if len(self.raw) <= 0x82 {
+		return nil, fsNotFoundError
+	}
+
+	start := 0x80 + int(binary.LittleEndian.Uint16(self.raw[0x80:]))
+	if start+4 > len(self.raw) ||
+		binary.LittleEndian.Uint32(self.raw[start:]) != ext4XattrMagic {
+		return nil, fsNotFoundError
+	}
+
+	entries := self.raw[start+4:]
+	for pos := 0; pos+16 <= len(entries); {
+		name_len := int(entries[pos])
+		name_index := entries[pos+1]
+		if name_len == 0 && name_index == 0 {
+			break
+		}
+
+		value_offset := int(binary.LittleEndian.Uint16(entries[pos+2:]))
+		value_size := int(binary.LittleEndian.Uint32(entries[pos+8:]))
+		if pos+16+name_len > len(entries) {
+			break
+		}
+
+		// Index 7 is the system namespace
+		if name_index == 7 && string(entries[pos+16:pos+16+name_len]) == name {
+			if value_offset+value_size > len(entries) {
+				return nil, errors.New("ext4: invalid xattr")
+			}
+			return entries[value_offset : value_offset+value_size], nil
+		}
+
+		pos += (16 + name_len + 3) &^ 3
+	}
+
+	return nil, fsNotFoundError
+}
+
+// The target of a symbolic link.
+func (self *Inode) LinkTarget() (string, error) {
+	if !self.IsLink() {
+		return "", errors.New("ext4: not a symlink")
+	}
+
+	// Fast symlinks are stored in the block map.
+	if self.Flags&(ext4ExtentFlag|ext4InlineFlag) == 0 &&
+		self.Size < inlineDataSize {
+		return string(self.raw[0x28 : 0x28+self.Size]), nil
+	}
+
+	reader, err := self.Reader()
+	if err != nil {
+		return "", err
+	}
+
+	size := self.Size
+	if size > 4096 {
+		size = 4096
+	}
+
+	buf := make([]byte, size)
+	n, err := reader.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return string(buf[:n]), nil
+}
+
+// A run of contiguous blocks.
+type run struct {
+	logical  int64
+	physical int64
+	length   int64
+}
+
+func (self *Inode) Reader() (*InodeReader, error) {
+	result := &InodeReader{
+		ctx:  self.ctx,
+		size: self.Size,
+	}
+
+	if self.Flags&ext4InlineFlag != 0 {
+		data := append([]byte{}, self.raw[0x28:0x28+inlineDataSize]...)
+		extra, _ := self.getXattr("data")
+		result.inline = append(data, extra...)
+		if int64(len(result.inline)) > result.size {
+			result.inline = result.inline[:result.size]
+		}
+		return result, nil
+	}
+
+	// Fast symlinks store the target in the block map.
+	if self.IsLink() && self.Flags&ext4ExtentFlag == 0 &&
+		self.Size < inlineDataSize {
+		result.inline = append([]byte{}, self.raw[0x28:0x28+self.Size]...)
+		return result, nil
+	}
+
+	var err error
+	if self.Flags&ext4ExtentFlag != 0 {
+		err = self.ctx.parseExtents(self.raw[0x28:0x28+inlineDataSize],
+			self.IsDeleted(), 0, &result.runs)
+	} else {
+		err = self.ctx.parseBlockMap(self.raw[0x28:0x28+inlineDataSize],
+			&result.runs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleted inodes often have their size cleared - use the extents
+	// to work out how much data there is.
+	if self.IsDeleted() && result.size == 0 {
+		for _, r := range result.runs {
+			end := (r.logical + r.length) * self.ctx.block_size
+			if end > result.size {
+				result.size = end
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (self *Ext4Context) parseExtents(
+	buf []byte, deleted bool, depth int, runs *[]run) error {
+	if depth > ext4MaxExtentDepth {
+		return errors.New("ext4: extent tree too deep")
+	}
+
+	if len(buf) < 12 || binary.LittleEndian.Uint16(buf) != ext4ExtentMagic {
+		return errors.New("ext4: invalid extent header")
+	}
+
+	entries := int(binary.LittleEndian.Uint16(buf[2:]))
+	tree_depth := binary.LittleEndian.Uint16(buf[6:])
+
+	// When an inode is deleted the number of entries is cleared but
+	// the entries themselves may remain.
+	if deleted {
+		entries = int(binary.LittleEndian.Uint16(buf[4:]))
+	}
+
+	for i := 0; i < entries && 12+(i+1)*12 <= len(buf); i++ {
+		entry := buf[12+i*12:]
+		if tree_depth == 0 {
+			length := int64(binary.LittleEndian.Uint16(entry[4:]))
+
+			// Uninitialized extents read as zeros.
+			uninitialized := length > 32768
+			if uninitialized {
+				length -= 32768
+			}
+
+			if length == 0 {
+				continue
+			}
+
+			physical := int64(binary.LittleEndian.Uint16(entry[6:]))<<32 |
+				int64(binary.LittleEndian.Uint32(entry[8:]))
+			if uninitialized {
+				physical = 0
+			}
+
+			*runs = append(*runs, run{
+				logical:  int64(binary.LittleEndian.Uint32(entry)),
+				physical: physical,
+				length:   length,
+			})
+			continue
+		}
+
+		leaf := int64(binary.LittleEndian.Uint32(entry[4:])) |
+			int64(binary.LittleEndian.Uint16(entry[8:]))<<32
+		if leaf == 0 || leaf >= self.blocks_count {
+			continue
+		}
+
+		block := make([]byte, self.block_size)
+		err := self.readBlock(block, leaf)
+		if err != nil {
+			return err
+		}
+
+		err = self.parseExtents(block, deleted, depth+1, runs)
+		if err != nil && !deleted {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Parse the legacy direct and indirect block map.
+func (self *Ext4Context) parseBlockMap(buf []byte, runs *[]run) error {
+	per_block := self.block_size / 4
+	logical := int64(0)
+
+	add := func(block int64) {
+		if block != 0 {
+			last := len(*runs) - 1
+			if last >= 0 {
+				r := &(*runs)[last]
+				if r.logical+r.length == logical &&
+					r.physical+r.length == block {
+					r.length++
+					logical++
+					return
+				}
+			}
+			*runs = append(*runs, run{
+				logical: logical, physical: block, length: 1})
+		}
+		logical++
+	}
+
+	var walk func(block int64, level int) error
+	walk = func(block int64, level int) error {
+		if block == 0 || block >= self.blocks_count {
+			// Sparse range - skip all the blocks covered.
+			count := int64(1)
+			for i := 0; i < level; i++ {
+				count *= per_block
+			}
+			logical += count
+			return nil
+		}
+
+		if level == 0 {
+			add(block)
+			return nil
+		}
+
+		data := make([]byte, self.block_size)
+		err := self.readBlock(data, block)
+		if err != nil {
+			return err
+		}
+
+		for i := int64(0); i < per_block; i++ {
+			err := walk(int64(binary.LittleEndian.Uint32(data[i*4:])), level-1)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i := 0; i < 15; i++ {
+		level := 0
+		if i >= 12 {
+			level = i - 11
+		}
+		err := walk(int64(binary.LittleEndian.Uint32(buf[i*4:])), level)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Read the content of an inode.
+type InodeReader struct {
+	ctx    *Ext4Context
+	size   int64
+	inline []byte
+	runs   []run
+}
+
+func (self *InodeReader) Size() int64 {
+	return self.size
+}
+
+func (self *InodeReader) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset >= self.size {
+		return 0, io.EOF
+	}
+
+	if self.inline != nil {
+		n := copy(buf, self.inline[offset:])
+		if n < len(buf) {
+			return n, io.EOF
+		}
+		return n, nil
+	}
+
+	to_read := buf
+	if offset+int64(len(to_read)) > self.size {
+		to_read = to_read[:self.size-offset]
+	}
+
+	block_size := self.ctx.block_size
+	n := 0
+	for n < len(to_read) {
+		current := offset + int64(n)
+		block := current / block_size
+		block_offset := current % block_size
+
+		// Find the run containing this block. Holes read as zero.
+		length := block_size - block_offset
+		var physical int64 = -1
+		for _, r := range self.runs {
+			if block >= r.logical && block < r.logical+r.length {
+				if r.physical != 0 {
+					physical = r.physical + block - r.logical
+				}
+				length = (r.logical+r.length-block)*block_size - block_offset
+				break
+			}
+		}
+
+		if length > int64(len(to_read)-n) {
+			length = int64(len(to_read) - n)
+		}
+
+		chunk := to_read[n : n+int(length)]
+		if physical < 0 {
+			for i := range chunk {
+				chunk[i] = 0
+			}
+		} else {
+			_, err := self.ctx.reader.ReadAt(chunk,
+				physical*block_size+block_offset)
+			if err != nil && err != io.EOF {
+				return n, err
+			}
+		}
+		n += int(length)
+	}
+
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func formatUUID(b []byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,417 @@
+package ext4
+
+// This is an accessor which parses an ext2/3/4 filesystem
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	// A virtual directory at the root of the filesystem listing
+	// deleted inodes by number.
+	deletedDirectory = "$deleted"
+)
+
+type Ext4FileInfo struct {
+	inode      *Inode
+	name       string
+	deleted    bool
+	_full_path *accessors.OSPath
+}
+
+func (self *Ext4FileInfo) IsDir() bool {
+	return self.inode.IsDir()
+}
+
+func (self *Ext4FileInfo) Size() int64 {
+	return self.inode.Size
+}
+
+func (self *Ext4FileInfo) Data() *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("inode", self.inode.Number).
+		Set("mode", self.inode.FileMode().String()).
+		Set("uid", self.inode.Uid).
+		Set("gid", self.inode.Gid).
+		Set("links", self.inode.Links).
+		Set("flags", fmt.Sprintf("%#x", self.inode.Flags))
+
+	if self.inode.IsLink() {
+		target, err := self.inode.LinkTarget()
+		if err == nil {
+			result.Set("link", target)
+		}
+	}
+
+	if self.deleted {
+		result.Set("deleted", true)
+	}
+
+	if self.inode.IsDeleted() {
+		result.Set("dtime", self.inode.Dtime)
+	}
+
+	return result
+}
+
+func (self *Ext4FileInfo) Name() string {
+	return self.name
+}
+
+func (self *Ext4FileInfo) UniqueName() string {
+	return self._full_path.String()
+}
+
+func (self *Ext4FileInfo) Mode() os.FileMode {
+	return self.inode.FileMode()
+}
+
+func (self *Ext4FileInfo) ModTime() time.Time {
+	return self.inode.Mtime
+}
+
+func (self *Ext4FileInfo) FullPath() string {
+	return self._full_path.String()
+}
+
+func (self *Ext4FileInfo) OSPath() *accessors.OSPath {
+	return self._full_path
+}
+
+func (self *Ext4FileInfo) Btime() time.Time {
+	return self.inode.Btime
+}
+
+func (self *Ext4FileInfo) Mtime() time.Time {
+	return self.inode.Mtime
+}
+
+func (self *Ext4FileInfo) Ctime() time.Time {
+	return self.inode.Ctime
+}
+
+func (self *Ext4FileInfo) Atime() time.Time {
+	return self.inode.Atime
+}
+
+// Symlinks are not followed - the target is available in the Data
+// field.
+func (self *Ext4FileInfo) IsLink() bool {
+	return false
+}
+
+func (self *Ext4FileInfo) GetLink() (*accessors.OSPath, error) {
+	return nil, errors.New("Not implemented")
+}
+
+type Ext4FileSystemAccessor struct {
+	scope vfilter.Scope
+
+	// The delegate accessor we use to open the underlying volume.
+	accessor string
+	device   *accessors.OSPath
+
+	root *accessors.OSPath
+}
+
+func NewExt4FileSystemAccessor(
+	scope vfilter.Scope,
+	root_path *accessors.OSPath,
+	device *accessors.OSPath, accessor string) *Ext4FileSystemAccessor {
+	return &Ext4FileSystemAccessor{
+		scope:    scope,
+		accessor: accessor,
+		device:   device,
+		root:     root_path,
+	}
+}
+
+func (self Ext4FileSystemAccessor) New(scope vfilter.Scope) (
+	accessors.FileSystemAccessor, error) {
+	// Create a new cache in the scope.
+	return &Ext4FileSystemAccessor{
+		scope:    scope,
+		device:   self.device,
+		accessor: self.accessor,
+		root:     self.root,
+	}, nil
+}
+
+func (self Ext4FileSystemAccessor) ParsePath(path string) (
+	*accessors.OSPath, error) {
+	return accessors.NewLinuxOSPath(path)
+}
+
+// Resolve the path to an inode. Paths under the deleted directory
+// refer to inodes by number.
+func (self *Ext4FileSystemAccessor) getInode(
+	ext4_ctx *Ext4Context, components []string) (*Inode, error) {
+	if len(components) == 2 && components[0] == deletedDirectory {
+		number, err := strconv.ParseUint(components[1], 10, 32)
+		if err != nil {
+			return nil, fsNotFoundError
+		}
+		return ext4_ctx.GetInode(uint32(number))
+	}
+
+	return ext4_ctx.OpenComponents(components)
+}
+
+func (self *Ext4FileSystemAccessor) ReadDir(path string) (
+	res []accessors.FileInfo, err error) {
+	// Normalize the path
+	fullpath, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.ReadDirWithOSPath(fullpath)
+}
+
+func (self *Ext4FileSystemAccessor) ReadDirWithOSPath(
+	fullpath *accessors.OSPath) (res []accessors.FileInfo, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	result := []accessors.FileInfo{}
+
+	ext4_ctx, err := GetExt4Context(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fullpath.Components) == 1 &&
+		fullpath.Components[0] == deletedDirectory {
+		inodes, err := ext4_ctx.DeletedInodes()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, inode := range inodes {
+			name := fmt.Sprintf("%d", inode.Number)
+			result = append(result, &Ext4FileInfo{
+				inode:      inode,
+				name:       name,
+				deleted:    true,
+				_full_path: fullpath.Append(name),
+			})
+		}
+		return result, nil
+	}
+
+	dir, err := ext4_ctx.OpenComponents(fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ext4_ctx.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// List the directory.
+	for _, entry := range entries {
+		// Skip these useless directories.
+		if entry.Name == "." || entry.Name == ".." {
+			continue
+		}
+
+		inode, err := ext4_ctx.GetInode(entry.Inode)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, &Ext4FileInfo{
+			inode:      inode,
+			name:       entry.Name,
+			deleted:    entry.Deleted,
+			_full_path: fullpath.Append(entry.Name),
+		})
+	}
+	return result, nil
+}
+
+// Adapt the inode reader to a ReadSeekCloser
+type readAdapter struct {
+	sync.Mutex
+
+	pos    int64
+	reader *InodeReader
+}
+
+func (self *readAdapter) Read(buf []byte) (res int, err error) {
+	self.Lock()
+	defer self.Unlock()
+
+	res, err = self.reader.ReadAt(buf, self.pos)
+	self.pos += int64(res)
+
+	// Short reads are not errors as long as we read something.
+	if res > 0 && errors.Is(err, io.EOF) {
+		err = nil
+	}
+
+	return res, err
+}
+
+func (self *readAdapter) ReadAt(buf []byte, offset int64) (int, error) {
+	return self.reader.ReadAt(buf, offset)
+}
+
+func (self *readAdapter) Close() error {
+	return nil
+}
+
+func (self *readAdapter) Seek(offset int64, whence int) (int64, error) {
+	self.Lock()
+	defer self.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+		self.pos = offset
+	case io.SeekCurrent:
+		self.pos += offset
+	case io.SeekEnd:
+		self.pos = self.reader.Size() + offset
+	}
+
+	if self.pos < 0 {
+		self.pos = 0
+		return 0, os.ErrInvalid
+	}
+	return self.pos, nil
+}
+
+func (self *Ext4FileSystemAccessor) Open(
+	path string) (res accessors.ReadSeekCloser, err error) {
+
+	full_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.OpenWithOSPath(full_path)
+}
+
+func (self *Ext4FileSystemAccessor) OpenWithOSPath(
+	fullpath *accessors.OSPath) (res accessors.ReadSeekCloser, err error) {
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	ext4_ctx, err := GetExt4Context(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	inode, err := self.getInode(ext4_ctx, fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	if inode.IsDir() {
+		return nil, errors.New("ext4: Can not open a directory")
+	}
+
+	reader, err := inode.Reader()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readAdapter{reader: reader}, nil
+}
+
+func (self *Ext4FileSystemAccessor) Lstat(
+	path string) (res accessors.FileInfo, err error) {
+
+	fullpath, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.LstatWithOSPath(fullpath)
+}
+
+func (self *Ext4FileSystemAccessor) LstatWithOSPath(
+	fullpath *accessors.OSPath) (res accessors.FileInfo, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	ext4_ctx, err := GetExt4Context(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	inode, err := self.getInode(ext4_ctx, fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ext4FileInfo{
+		inode:      inode,
+		name:       fullpath.Basename(),
+		deleted:    inode.IsDeleted(),
+		_full_path: fullpath,
+	}, nil
+}
+
+func init() {
+	accessors.Register("ext4", &Ext4FileSystemAccessor{},
+		`Access the ext2/3/4 filesystem inside an image by parsing the raw filesystem.
+
+This accessor is designed to operate on images or raw block devices
+directly and does not require the filesystem to be mounted. It
+requires a delegate accessor to get the raw image and will open files
+using the full path rooted at the top of the filesystem.
+
+Deleted directory entries are recovered from directory slack and are
+marked as deleted in the Data field. The special directory /$deleted
+lists all deleted inodes by inode number, and any inode may be opened
+as /$deleted/<inode number>. Recovering the content of deleted inodes
+is best effort since their blocks may have been reused.
+
+## Example
+
+The following query will glob all the files under the /etc directory
+inside an ext4 image file
+
+SELECT *
+FROM glob(globs='/etc/**',
+  accessor="ext4",
+  root=pathspec(
+    DelegateAccessor="file",
+    DelegatePath='ext4.dd'))
+
+`)
+
+	json.RegisterCustomEncoder(&Ext4FileInfo{}, accessors.MarshalGlobFileInfo)
+}
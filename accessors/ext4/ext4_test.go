@@ -0,0 +1,140 @@
+package ext4
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/config"
+	"www.velocidex.com/golang/velociraptor/glob"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/file"
+)
+
+// The test image was created with:
+// mke2fs -t ext4 -b 1024 -I 256 -O ^has_journal,inline_data,^resize_inode -d src test.ext4.dd 1M
+// and deleted.txt was removed with debugfs.
+func getTestAccessor(t *testing.T) (*Ext4FileSystemAccessor, func()) {
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+
+	abs_path, _ := filepath.Abs("../../artifacts/testdata/files/test.ext4.dd")
+	root_path := accessors.MustNewLinuxOSPath("")
+
+	fs_accessor := NewExt4FileSystemAccessor(
+		scope, root_path, accessors.MustNewGenericOSPath(abs_path), "file")
+
+	return fs_accessor, func() { scope.Close() }
+}
+
+func readFile(t *testing.T, accessor accessors.FileSystemAccessor,
+	path string) []byte {
+	fd, err := accessor.Open(path)
+	assert.NoError(t, err)
+	defer fd.Close()
+
+	data, err := ioutil.ReadAll(fd)
+	assert.NoError(t, err)
+	return data
+}
+
+func TestExt4FilesystemAccessor(t *testing.T) {
+	fs_accessor, closer := getTestAccessor(t)
+	defer closer()
+
+	config_obj := config.GetDefaultConfig()
+	root_path := accessors.MustNewLinuxOSPath("")
+
+	globber := glob.NewGlobber()
+	globber.Add(accessors.MustNewLinuxOSPath("/*"))
+	globber.Add(accessors.MustNewLinuxOSPath("/dir1/**"))
+
+	hits := []string{}
+	deleted := []string{}
+	for hit := range globber.ExpandWithContext(
+		context.Background(), fs_accessor.scope, config_obj,
+		root_path, fs_accessor) {
+		hits = append(hits, hit.OSPath().String())
+
+		is_deleted, _ := hit.Data().Get("deleted")
+		if is_deleted == true {
+			deleted = append(deleted, hit.Name())
+		}
+	}
+	sort.Strings(hits)
+
+	assert.Equal(t, []string{
+		"/deleted.txt",
+		"/dir1",
+		"/dir1/inline.txt",
+		"/dir1/inline2.txt",
+		"/dir1/sub",
+		"/dir1/sub/big.bin",
+		"/hello.txt",
+		"/link",
+		"/lost+found",
+		"/many",
+	}, hits)
+
+	// The deleted file is recovered from the directory slack.
+	assert.Equal(t, []string{"deleted.txt"}, deleted)
+
+	// A regular file stored in extents.
+	assert.Equal(t, "hello world\n", string(readFile(t, fs_accessor, "/hello.txt")))
+
+	// Small files are stored inline in the inode, and larger inline
+	// files continue in the extended attributes.
+	assert.Equal(t, "small\n", string(readFile(t, fs_accessor, "/dir1/inline.txt")))
+	assert.Equal(t, strings.Repeat("0123456789", 10),
+		string(readFile(t, fs_accessor, "/dir1/inline2.txt")))
+
+	big := readFile(t, fs_accessor, "/dir1/sub/big.bin")
+	assert.Equal(t, 300000, len(big))
+	for i := range big {
+		if big[i] != byte((i*7)%251) {
+			t.Fatalf("Invalid data at offset %v", i)
+		}
+	}
+
+	// Symlinks are reported with their target.
+	stat, err := fs_accessor.Lstat("/link")
+	assert.NoError(t, err)
+	target, _ := stat.Data().Get("link")
+	assert.Equal(t, "hello.txt", target)
+
+	// Directories spanning multiple blocks.
+	children, err := fs_accessor.ReadDir("/many")
+	assert.NoError(t, err)
+	assert.Equal(t, 100, len(children))
+}
+
+func TestExt4DeletedInodes(t *testing.T) {
+	fs_accessor, closer := getTestAccessor(t)
+	defer closer()
+
+	children, err := fs_accessor.ReadDir("/$deleted")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(children))
+	assert.Equal(t, "12", children[0].Name())
+
+	expected := bytes.Repeat([]byte("This file will be deleted\n"), 100)
+
+	// The inode size was cleared so the recovered data is rounded
+	// up to the block size.
+	data := readFile(t, fs_accessor, "/$deleted/12")
+	assert.Equal(t, 3072, len(data))
+	assert.Equal(t, expected, data[:len(expected)])
+
+	// The deleted directory entry refers to the same inode.
+	data = readFile(t, fs_accessor, "/deleted.txt")
+	assert.Equal(t, expected, data[:len(expected)])
+}
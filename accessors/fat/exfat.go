@@ -0,0 +1,529 @@
+package fat
+
+// A read only parser for the exFAT filesystem.
+//
+// exFAT stores files as sets of directory entries: a primary file
+// entry, a stream extension describing the data and one or more file
+// name entries. Data is stored in clusters which are either
+// contiguous (when the NoFatChain flag is set) or linked through the
+// File Allocation Table.
+//
+// Deleted files keep their directory entry sets with the InUse bit
+// cleared, so they can be listed and their data recovered as long as
+// the clusters were not reused.
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	exfatEntrySize = 32
+
+	// Directory entry types. The high bit is the InUse flag.
+	exfatEntryEndOfDirectory = 0x00
+	exfatEntryInUse          = 0x80
+	exfatEntryVolumeLabel    = 0x83
+	exfatEntryFile           = 0x85
+	exfatEntryStream         = 0xc0
+	exfatEntryFileName       = 0xc1
+
+	exfatFlagNoFatChain = 0x2
+
+	exfatAttrReadOnly  = 0x1
+	exfatAttrHidden    = 0x2
+	exfatAttrSystem    = 0x4
+	exfatAttrDirectory = 0x10
+	exfatAttrArchive   = 0x20
+
+	exfatFatEndOfChain = 0xfffffff8
+	exfatNameChars     = 15
+
+	exfatMaxDirectorySize = 256 * 1024 * 1024
+)
+
+var (
+	fsNotFoundError = errors.New("file not found")
+)
+
+type ExFATContext struct {
+	reader io.ReaderAt
+
+	bytes_per_sector    int64
+	bytes_per_cluster   int64
+	fat_offset          int64
+	cluster_heap_offset int64
+	cluster_count       uint32
+	root_cluster        uint32
+
+	SerialNumber uint32
+	Label        string
+}
+
+func NewExFATContext(reader io.ReaderAt) (*ExFATContext, error) {
+	boot := make([]byte, 512)
+	n, err := reader.ReadAt(boot, 0)
+	if n < len(boot) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	if string(boot[3:11]) != "EXFAT   " ||
+		binary.LittleEndian.Uint16(boot[510:]) != 0xaa55 {
+		return nil, errors.New("exfat: invalid boot sector")
+	}
+
+	sector_shift := uint(boot[108])
+	cluster_shift := uint(boot[109])
+	if sector_shift < 9 || sector_shift > 12 ||
+		sector_shift+cluster_shift > 25 {
+		return nil, errors.New("exfat: invalid boot sector")
+	}
+
+	result := &ExFATContext{
+		reader:            reader,
+		bytes_per_sector:  int64(1) << sector_shift,
+		bytes_per_cluster: int64(1) << (sector_shift + cluster_shift),
+		cluster_count:     binary.LittleEndian.Uint32(boot[92:]),
+		root_cluster:      binary.LittleEndian.Uint32(boot[96:]),
+		SerialNumber:      binary.LittleEndian.Uint32(boot[100:]),
+	}
+
+	result.fat_offset = int64(binary.LittleEndian.Uint32(boot[80:])) *
+		result.bytes_per_sector
+	result.cluster_heap_offset = int64(binary.LittleEndian.Uint32(boot[88:])) *
+		result.bytes_per_sector
+
+	// The volume label is stored in the root directory.
+	root, err := result.rootEntries()
+	if err == nil {
+		result.Label = root.label
+	}
+
+	return result, nil
+}
+
+func (self *ExFATContext) isValidCluster(cluster uint32) bool {
+	return cluster >= 2 && cluster-2 < self.cluster_count
+}
+
+func (self *ExFATContext) clusterOffset(cluster uint32) int64 {
+	return self.cluster_heap_offset +
+		int64(cluster-2)*self.bytes_per_cluster
+}
+
+// Follow the cluster chain in the FAT. Returns nil if the chain is
+// broken.
+func (self *ExFATContext) fatChain(first uint32, max_clusters int64) []uint32 {
+	var result []uint32
+	buf := make([]byte, 4)
+
+	cluster := first
+	for self.isValidCluster(cluster) && int64(len(result)) < max_clusters {
+		result = append(result, cluster)
+
+		_, err := self.reader.ReadAt(buf, self.fat_offset+int64(cluster)*4)
+		if err != nil {
+			return nil
+		}
+
+		next := binary.LittleEndian.Uint32(buf)
+		if next >= exfatFatEndOfChain {
+			return result
+		}
+
+		if !self.isValidCluster(next) {
+			return nil
+		}
+		cluster = next
+	}
+
+	return result
+}
+
+type ExFATDirEntry struct {
+	Name            string
+	Attributes      uint16
+	Size            int64
+	ValidDataLength int64
+	FirstCluster    uint32
+	NoFatChain      bool
+	Deleted         bool
+
+	Ctime time.Time
+	Mtime time.Time
+	Atime time.Time
+}
+
+func (self *ExFATDirEntry) IsDir() bool {
+	return self.Attributes&exfatAttrDirectory != 0
+}
+
+func (self *ExFATDirEntry) AttributeString() string {
+	result := ""
+	for _, attr := range []struct {
+		flag uint16
+		name string
+	}{
+		{exfatAttrReadOnly, "R"},
+		{exfatAttrHidden, "H"},
+		{exfatAttrSystem, "S"},
+		{exfatAttrDirectory, "D"},
+		{exfatAttrArchive, "A"},
+	} {
+		if self.Attributes&attr.flag != 0 {
+			result += attr.name
+		}
+	}
+	return result
+}
+
+// The root directory has no directory entry of its own.
+func (self *ExFATContext) rootEntry() *ExFATDirEntry {
+	return &ExFATDirEntry{
+		Attributes:   exfatAttrDirectory,
+		FirstCluster: self.root_cluster,
+	}
+}
+
+// Open a reader over the entry's data.
+func (self *ExFATContext) Reader(entry *ExFATDirEntry) (*ExFATReader, error) {
+	result := &ExFATReader{
+		ctx:        self,
+		size:       entry.Size,
+		valid_size: entry.ValidDataLength,
+	}
+
+	// The root directory does not record its size.
+	if entry.FirstCluster == self.root_cluster && entry.Size == 0 {
+		result.size = exfatMaxDirectorySize
+		result.valid_size = result.size
+	}
+
+	if result.size == 0 {
+		return result, nil
+	}
+
+	if !self.isValidCluster(entry.FirstCluster) {
+		return nil, fmt.Errorf("exfat: invalid first cluster %d", entry.FirstCluster)
+	}
+
+	cluster_count := (result.size + self.bytes_per_cluster - 1) /
+		self.bytes_per_cluster
+
+	if !entry.NoFatChain {
+		result.clusters = self.fatChain(entry.FirstCluster, cluster_count)
+		if result.clusters != nil {
+			if int64(len(result.clusters)) < cluster_count {
+				result.size = int64(len(result.clusters)) * self.bytes_per_cluster
+			}
+			return result, nil
+		}
+
+		// The FAT chain of deleted files may be gone, the best
+		// we can do is to assume the clusters were contiguous.
+		if !entry.Deleted {
+			return nil, errors.New("exfat: broken cluster chain")
+		}
+	}
+
+	for i := int64(0); i < cluster_count; i++ {
+		cluster := entry.FirstCluster + uint32(i)
+		if !self.isValidCluster(cluster) {
+			break
+		}
+		result.clusters = append(result.clusters, cluster)
+	}
+
+	if int64(len(result.clusters)) < cluster_count {
+		result.size = int64(len(result.clusters)) * self.bytes_per_cluster
+	}
+
+	return result, nil
+}
+
+type ExFATReader struct {
+	ctx        *ExFATContext
+	size       int64
+	valid_size int64
+	clusters   []uint32
+}
+
+func (self *ExFATReader) Size() int64 {
+	return self.size
+}
+
+func (self *ExFATReader) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset >= self.size {
+		return 0, io.EOF
+	}
+
+	to_read := buf
+	if offset+int64(len(to_read)) > self.size {
+		to_read = to_read[:self.size-offset]
+	}
+
+	bytes_per_cluster := self.ctx.bytes_per_cluster
+	n := 0
+	for n < len(to_read) {
+		current := offset + int64(n)
+		cluster_idx := current / bytes_per_cluster
+		cluster_offset := current % bytes_per_cluster
+
+		length := bytes_per_cluster - cluster_offset
+		if length > int64(len(to_read)-n) {
+			length = int64(len(to_read) - n)
+		}
+		chunk := to_read[n : n+int(length)]
+
+		// Data past the valid data length reads as zeros.
+		if current >= self.valid_size ||
+			cluster_idx >= int64(len(self.clusters)) {
+			for i := range chunk {
+				chunk[i] = 0
+			}
+
+		} else {
+			if current+length > self.valid_size {
+				valid := int(self.valid_size - current)
+				for i := valid; i < len(chunk); i++ {
+					chunk[i] = 0
+				}
+				chunk = chunk[:valid]
+			}
+
+			_, err := self.ctx.reader.ReadAt(chunk,
+				self.ctx.clusterOffset(self.clusters[cluster_idx])+cluster_offset)
+			if err != nil && err != io.EOF {
+				return n, err
+			}
+		}
+
+		n += int(length)
+	}
+
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+type exfatDirectory struct {
+	entries []*ExFATDirEntry
+	label   string
+}
+
+func (self *ExFATContext) rootEntries() (*exfatDirectory, error) {
+	return self.readDirectory(self.rootEntry())
+}
+
+func (self *ExFATContext) ReadDir(dir *ExFATDirEntry) ([]*ExFATDirEntry, error) {
+	if !dir.IsDir() {
+		return nil, errors.New("exfat: not a directory")
+	}
+
+	result, err := self.readDirectory(dir)
+	if err != nil {
+		return nil, err
+	}
+	return result.entries, nil
+}
+
+func (self *ExFATContext) readDirectory(dir *ExFATDirEntry) (*exfatDirectory, error) {
+	reader, err := self.Reader(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	size := reader.Size()
+	if size > exfatMaxDirectorySize {
+		size = exfatMaxDirectorySize
+	}
+
+	result := &exfatDirectory{}
+	buf := make([]byte, self.bytes_per_cluster)
+	var pending []byte
+
+	for offset := int64(0); offset < size; offset += self.bytes_per_cluster {
+		n, err := reader.ReadAt(buf, offset)
+		if n == 0 {
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+
+		// Entry sets may span clusters so we parse the entire
+		// directory as a stream.
+		pending = append(pending, buf[:n]...)
+		consumed, done := self.parseEntries(pending, result)
+		if done {
+			break
+		}
+		pending = append([]byte{}, pending[consumed:]...)
+	}
+
+	return result, nil
+}
+
+// Parse as many complete entry sets as possible from buf. Returns
+// the number of bytes consumed and if the end of directory was seen.
+func (self *ExFATContext) parseEntries(
+	buf []byte, result *exfatDirectory) (int, bool) {
+	pos := 0
+	for pos+exfatEntrySize <= len(buf) {
+		entry := buf[pos : pos+exfatEntrySize]
+		entry_type := entry[0]
+
+		switch entry_type {
+		case exfatEntryEndOfDirectory:
+			return pos, true
+
+		case exfatEntryVolumeLabel:
+			count := int(entry[1])
+			if count > 11 {
+				count = 11
+			}
+			result.label = decodeUTF16(entry[2 : 2+count*2])
+
+		case exfatEntryFile, exfatEntryFile &^ exfatEntryInUse:
+			secondary_count := int(entry[1])
+			set_size := (secondary_count + 1) * exfatEntrySize
+			if pos+set_size > len(buf) {
+				// Wait for more data
+				return pos, false
+			}
+
+			parsed := parseEntrySet(buf[pos:pos+set_size],
+				entry_type == exfatEntryFile)
+			if parsed != nil {
+				result.entries = append(result.entries, parsed)
+				pos += set_size
+				continue
+			}
+		}
+
+		pos += exfatEntrySize
+	}
+
+	return pos, false
+}
+
+// Parse a file entry set. Deleted sets must consist entirely of
+// deleted entries, otherwise the entries were reused.
+func parseEntrySet(buf []byte, in_use bool) *ExFATDirEntry {
+	expected_flag := byte(exfatEntryInUse)
+	if !in_use {
+		expected_flag = 0
+	}
+
+	stream := buf[exfatEntrySize : 2*exfatEntrySize]
+	if stream[0] != exfatEntryStream&^exfatEntryInUse|expected_flag {
+		return nil
+	}
+
+	name_length := int(stream[3])
+	name := make([]byte, 0, name_length*2)
+	for pos := 2 * exfatEntrySize; pos < len(buf); pos += exfatEntrySize {
+		entry := buf[pos : pos+exfatEntrySize]
+		if entry[0] != exfatEntryFileName&^exfatEntryInUse|expected_flag {
+			break
+		}
+		name = append(name, entry[2:2+exfatNameChars*2]...)
+	}
+
+	if len(name) > name_length*2 {
+		name = name[:name_length*2]
+	}
+
+	if len(name) == 0 {
+		return nil
+	}
+
+	file := buf[:exfatEntrySize]
+	return &ExFATDirEntry{
+		Name:            decodeUTF16(name),
+		Attributes:      binary.LittleEndian.Uint16(file[4:]),
+		Ctime:           exfatTime(binary.LittleEndian.Uint32(file[8:]), file[20], file[22]),
+		Mtime:           exfatTime(binary.LittleEndian.Uint32(file[12:]), file[21], file[23]),
+		Atime:           exfatTime(binary.LittleEndian.Uint32(file[16:]), 0, file[24]),
+		NoFatChain:      stream[1]&exfatFlagNoFatChain != 0,
+		ValidDataLength: int64(binary.LittleEndian.Uint64(stream[8:])),
+		FirstCluster:    binary.LittleEndian.Uint32(stream[20:]),
+		Size:            int64(binary.LittleEndian.Uint64(stream[24:])),
+		Deleted:         !in_use,
+	}
+}
+
+// Resolve a path, preferring allocated entries over deleted ones
+// with the same name.
+func (self *ExFATContext) OpenComponents(components []string) (*ExFATDirEntry, error) {
+	current := self.rootEntry()
+
+	for _, component := range components {
+		entries, err := self.ReadDir(current)
+		if err != nil {
+			return nil, fsNotFoundError
+		}
+
+		var next *ExFATDirEntry
+		for _, entry := range entries {
+			if strings.EqualFold(entry.Name, component) {
+				if next == nil || (next.Deleted && !entry.Deleted) {
+					next = entry
+				}
+			}
+		}
+
+		if next == nil {
+			return nil, fsNotFoundError
+		}
+		current = next
+	}
+
+	return current, nil
+}
+
+// Timestamps are stored in local time with an optional offset from
+// UTC in 15 minute increments. The 10ms increment extends the two
+// second resolution of the timestamp.
+func exfatTime(timestamp uint32, increment_10ms uint8, utc_offset uint8) time.Time {
+	if timestamp == 0 {
+		return time.Time{}
+	}
+
+	location := time.UTC
+	if utc_offset&0x80 != 0 {
+		// A signed 7 bit value
+		offset := int(utc_offset & 0x7f)
+		if offset >= 0x40 {
+			offset -= 0x80
+		}
+		location = time.FixedZone("", offset*15*60)
+	}
+
+	ns := int(increment_10ms%200) * 10 * int(time.Millisecond)
+	return time.Date(
+		1980+int(timestamp>>25),
+		time.Month(timestamp>>21&0xf),
+		int(timestamp>>16&0x1f),
+		int(timestamp>>11&0x1f),
+		int(timestamp>>5&0x3f),
+		int(timestamp&0x1f)*2,
+		ns, location).UTC()
+}
+
+func decodeUTF16(b []byte) string {
+	u16 := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u16 = append(u16, binary.LittleEndian.Uint16(b[i:]))
+	}
+	return strings.TrimRight(string(utf16.Decode(u16)), "\x00")
+}
@@ -0,0 +1,299 @@
+package fat
+
+// This is an accessor which parses an exFAT filesystem
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type ExFATFileInfo struct {
+	info       *ExFATDirEntry
+	_full_path *accessors.OSPath
+}
+
+func (self *ExFATFileInfo) IsDir() bool {
+	return self.info.IsDir()
+}
+
+func (self *ExFATFileInfo) Size() int64 {
+	return self.info.Size
+}
+
+func (self *ExFATFileInfo) Data() *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("first_cluster", self.info.FirstCluster).
+		Set("attr", self.info.AttributeString()).
+		Set("valid_data_length", self.info.ValidDataLength).
+		Set("no_fat_chain", self.info.NoFatChain)
+
+	if self.info.Deleted {
+		result.Set("deleted", true)
+	}
+
+	return result
+}
+
+func (self *ExFATFileInfo) Name() string {
+	return self.info.Name
+}
+
+func (self *ExFATFileInfo) UniqueName() string {
+	return self._full_path.String()
+}
+
+func (self *ExFATFileInfo) Mode() os.FileMode {
+	var result os.FileMode = 0755
+	if self.IsDir() {
+		result |= os.ModeDir
+	}
+	return result
+}
+
+func (self *ExFATFileInfo) ModTime() time.Time {
+	return self.info.Mtime
+}
+
+func (self *ExFATFileInfo) FullPath() string {
+	return self._full_path.String()
+}
+
+func (self *ExFATFileInfo) OSPath() *accessors.OSPath {
+	return self._full_path
+}
+
+func (self *ExFATFileInfo) Btime() time.Time {
+	return self.info.Ctime
+}
+
+func (self *ExFATFileInfo) Mtime() time.Time {
+	return self.info.Mtime
+}
+
+func (self *ExFATFileInfo) Ctime() time.Time {
+	return self.info.Ctime
+}
+
+func (self *ExFATFileInfo) Atime() time.Time {
+	return self.info.Atime
+}
+
+// Not supported
+func (self *ExFATFileInfo) IsLink() bool {
+	return false
+}
+
+func (self *ExFATFileInfo) GetLink() (*accessors.OSPath, error) {
+	return nil, errors.New("Not implemented")
+}
+
+type ExFATFileSystemAccessor struct {
+	scope vfilter.Scope
+
+	// The delegate accessor we use to open the underlying volume.
+	accessor string
+	device   *accessors.OSPath
+
+	root *accessors.OSPath
+}
+
+func NewExFATFileSystemAccessor(
+	scope vfilter.Scope,
+	root_path *accessors.OSPath,
+	device *accessors.OSPath, accessor string) *ExFATFileSystemAccessor {
+	return &ExFATFileSystemAccessor{
+		scope:    scope,
+		accessor: accessor,
+		device:   device,
+		root:     root_path,
+	}
+}
+
+func (self ExFATFileSystemAccessor) New(scope vfilter.Scope) (
+	accessors.FileSystemAccessor, error) {
+	// Create a new cache in the scope.
+	return &ExFATFileSystemAccessor{
+		scope:    scope,
+		device:   self.device,
+		accessor: self.accessor,
+		root:     self.root,
+	}, nil
+}
+
+func (self ExFATFileSystemAccessor) ParsePath(path string) (
+	*accessors.OSPath, error) {
+	return accessors.NewWindowsNTFSPath(path)
+}
+
+func (self *ExFATFileSystemAccessor) ReadDir(path string) (
+	res []accessors.FileInfo, err error) {
+	// Normalize the path
+	fullpath, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.ReadDirWithOSPath(fullpath)
+}
+
+func (self *ExFATFileSystemAccessor) ReadDirWithOSPath(
+	fullpath *accessors.OSPath) (res []accessors.FileInfo, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	result := []accessors.FileInfo{}
+
+	exfat_ctx, err := GetExFATContext(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := exfat_ctx.OpenComponents(fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := exfat_ctx.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range entries {
+		result = append(result, &ExFATFileInfo{
+			info:       info,
+			_full_path: fullpath.Append(info.Name),
+		})
+	}
+	return result, nil
+}
+
+func (self *ExFATFileSystemAccessor) Open(
+	path string) (res accessors.ReadSeekCloser, err error) {
+
+	full_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.OpenWithOSPath(full_path)
+}
+
+func (self *ExFATFileSystemAccessor) OpenWithOSPath(
+	fullpath *accessors.OSPath) (res accessors.ReadSeekCloser, err error) {
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	exfat_ctx, err := GetExFATContext(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := exfat_ctx.OpenComponents(fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return nil, errors.New("exfat: Can not open a directory")
+	}
+
+	reader, err := exfat_ctx.Reader(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &readAdapter{
+		info: &ExFATFileInfo{
+			info:       info,
+			_full_path: fullpath,
+		},
+		reader: reader,
+	}, nil
+}
+
+func (self *ExFATFileSystemAccessor) Lstat(
+	path string) (res accessors.FileInfo, err error) {
+
+	fullpath, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.LstatWithOSPath(fullpath)
+}
+
+func (self *ExFATFileSystemAccessor) LstatWithOSPath(
+	fullpath *accessors.OSPath) (res accessors.FileInfo, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	exfat_ctx, err := GetExFATContext(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := exfat_ctx.OpenComponents(fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExFATFileInfo{
+		info:       info,
+		_full_path: fullpath,
+	}, nil
+}
+
+func init() {
+	accessors.Register("exfat", &ExFATFileSystemAccessor{},
+		`Access the exFAT filesystem inside an image by parsing the raw filesystem.
+
+This accessor is designed to operate on images directly. It requires a
+delegate accessor to get the raw image and will open files using the
+full path rooted at the top of the filesystem.
+
+Deleted files are listed with the deleted flag set in the Data
+field. Their content is recovered on a best effort basis since the
+clusters may have been reused.
+
+## Example
+
+The following query will glob all the files inside an exFAT image
+of a USB stick
+
+SELECT *
+FROM glob(globs='/**',
+  accessor="exfat",
+  root=pathspec(
+    DelegateAccessor="file",
+    DelegatePath='usb.dd'))
+
+`)
+
+	json.RegisterCustomEncoder(&ExFATFileInfo{}, accessors.MarshalGlobFileInfo)
+}
@@ -0,0 +1,282 @@
+package fat
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/file"
+)
+
+const (
+	testSectorSize  = 512
+	testClusterSize = 4096
+	testFatOffset   = 24
+	testHeapOffset  = 32
+	testClusters    = 30
+	testRootCluster = 4
+)
+
+// Generate some data which is different in every sector.
+func testData(size int, seed byte) []byte {
+	result := make([]byte, size)
+	for i := range result {
+		result[i] = byte(i/512) + seed + byte(i%7)
+	}
+	return result
+}
+
+func encodeUTF16(name string) []byte {
+	result := []byte{}
+	for _, c := range utf16.Encode([]rune(name)) {
+		result = binary.LittleEndian.AppendUint16(result, c)
+	}
+	return result
+}
+
+func exfatTimestamp(t time.Time) uint32 {
+	return uint32(t.Year()-1980)<<25 | uint32(t.Month())<<21 |
+		uint32(t.Day())<<16 | uint32(t.Hour())<<11 |
+		uint32(t.Minute())<<5 | uint32(t.Second()/2)
+}
+
+type exfatBuilder struct {
+	image []byte
+}
+
+func (self *exfatBuilder) cluster(n int) []byte {
+	offset := testHeapOffset*testSectorSize + (n-2)*testClusterSize
+	return self.image[offset : offset+testClusterSize]
+}
+
+func (self *exfatBuilder) setFat(cluster int, next uint32) {
+	binary.LittleEndian.PutUint32(
+		self.image[testFatOffset*testSectorSize+cluster*4:], next)
+}
+
+type testEntry struct {
+	name       string
+	attr       uint16
+	flags      byte
+	first      uint32
+	size       uint64
+	valid      uint64
+	deleted    bool
+	mtime      uint32
+	mtime_10ms byte
+	mtime_utc  byte
+}
+
+// Encode a file entry set with the file, stream extension and file
+// name entries.
+func (self testEntry) encode() []byte {
+	name := encodeUTF16(self.name)
+	name_entries := (len(self.name) + exfatNameChars - 1) / exfatNameChars
+
+	in_use := byte(exfatEntryInUse)
+	if self.deleted {
+		in_use = 0
+	}
+
+	result := make([]byte, (2+name_entries)*exfatEntrySize)
+	file := result[:exfatEntrySize]
+	file[0] = exfatEntryFile&^exfatEntryInUse | in_use
+	file[1] = byte(1 + name_entries)
+	binary.LittleEndian.PutUint16(file[4:], self.attr)
+	binary.LittleEndian.PutUint32(file[8:], self.mtime)
+	binary.LittleEndian.PutUint32(file[12:], self.mtime)
+	binary.LittleEndian.PutUint32(file[16:], self.mtime)
+	file[21] = self.mtime_10ms
+	file[23] = self.mtime_utc
+
+	stream := result[exfatEntrySize : 2*exfatEntrySize]
+	stream[0] = exfatEntryStream&^exfatEntryInUse | in_use
+	stream[1] = self.flags
+	stream[3] = byte(len(self.name))
+	binary.LittleEndian.PutUint64(stream[8:], self.valid)
+	binary.LittleEndian.PutUint32(stream[20:], self.first)
+	binary.LittleEndian.PutUint64(stream[24:], self.size)
+
+	for i := 0; i < name_entries; i++ {
+		entry := result[(2+i)*exfatEntrySize : (3+i)*exfatEntrySize]
+		entry[0] = exfatEntryFileName&^exfatEntryInUse | in_use
+		end := (i + 1) * exfatNameChars * 2
+		if end > len(name) {
+			end = len(name)
+		}
+		copy(entry[2:], name[i*exfatNameChars*2:end])
+	}
+
+	return result
+}
+
+var testMtime = time.Date(2023, 10, 1, 12, 30, 10, 0, time.UTC)
+
+// Build a small exFAT volume:
+//   - hello.txt is fragmented across clusters 5 and 8.
+//   - dir is a contiguous directory containing nested.txt which is
+//     only partially written.
+//   - deleted.txt was deleted so its FAT chain is cleared.
+func buildExFAT() *exfatBuilder {
+	self := &exfatBuilder{
+		image: make([]byte, testHeapOffset*testSectorSize+
+			testClusters*testClusterSize),
+	}
+
+	boot := self.image[:testSectorSize]
+	copy(boot[3:], "EXFAT   ")
+	binary.LittleEndian.PutUint32(boot[80:], testFatOffset)
+	binary.LittleEndian.PutUint32(boot[84:], 8)
+	binary.LittleEndian.PutUint32(boot[88:], testHeapOffset)
+	binary.LittleEndian.PutUint32(boot[92:], testClusters)
+	binary.LittleEndian.PutUint32(boot[96:], testRootCluster)
+	binary.LittleEndian.PutUint32(boot[100:], 0x12345678)
+	boot[108] = 9
+	boot[109] = 3
+	binary.LittleEndian.PutUint16(boot[510:], 0xaa55)
+
+	self.setFat(0, 0xfffffff8)
+	self.setFat(1, 0xffffffff)
+	self.setFat(testRootCluster, 0xffffffff)
+	self.setFat(5, 8)
+	self.setFat(8, 0xffffffff)
+
+	root := self.cluster(testRootCluster)
+	label := encodeUTF16("USBSTICK")
+	root[0] = exfatEntryVolumeLabel
+	root[1] = byte(len(label) / 2)
+	copy(root[2:], label)
+
+	offset := exfatEntrySize
+	for _, entry := range []testEntry{{
+		name: "hello.txt", attr: exfatAttrArchive, flags: 1,
+		first: 5, size: 5000, valid: 5000,
+		mtime:      exfatTimestamp(testMtime),
+		mtime_10ms: 150,
+		// UTC+10:00 in 15 minute increments
+		mtime_utc: 0x80 | 40,
+	}, {
+		name: "dir", attr: exfatAttrDirectory, flags: 3,
+		first: 6, size: testClusterSize, valid: testClusterSize,
+	}, {
+		name: "deleted.txt", attr: exfatAttrArchive, flags: 1,
+		first: 9, size: 6000, valid: 6000, deleted: true,
+	}, {
+		name: "A very long file name.txt", attr: exfatAttrArchive, flags: 1,
+	}} {
+		encoded := entry.encode()
+		copy(root[offset:], encoded)
+		offset += len(encoded)
+	}
+
+	copy(self.cluster(6), testEntry{
+		name: "nested.txt", attr: exfatAttrArchive | exfatAttrReadOnly,
+		flags: 3, first: 7, size: 1000, valid: 100,
+	}.encode())
+
+	copy(self.cluster(5), testData(testClusterSize, 1))
+	copy(self.cluster(8), testData(testClusterSize, 2))
+	copy(self.cluster(7), testData(testClusterSize, 3))
+	copy(self.cluster(9), testData(testClusterSize, 4))
+	copy(self.cluster(10), testData(testClusterSize, 5))
+
+	return self
+}
+
+func TestExFAT(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exfat")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	builder := buildExFAT()
+	image_path := filepath.Join(dir, "exfat.dd")
+	assert.NoError(t, ioutil.WriteFile(image_path, builder.image, 0644))
+
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+	defer scope.Close()
+
+	fs_accessor := NewExFATFileSystemAccessor(scope,
+		accessors.MustNewWindowsNTFSPath(""),
+		accessors.MustNewGenericOSPath(image_path), "file")
+
+	listDir := func(path string) []string {
+		children, err := fs_accessor.ReadDir(path)
+		assert.NoError(t, err)
+
+		result := []string{}
+		for _, c := range children {
+			result = append(result, c.Name())
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	readFile := func(path string) []byte {
+		fd, err := fs_accessor.Open(path)
+		assert.NoError(t, err)
+		defer fd.Close()
+
+		data, err := ioutil.ReadAll(fd)
+		assert.NoError(t, err)
+		return data
+	}
+
+	assert.Equal(t, []string{"A very long file name.txt",
+		"deleted.txt", "dir", "hello.txt"}, listDir("/"))
+	assert.Equal(t, []string{"nested.txt"}, listDir("/dir"))
+
+	// Follows the FAT chain.
+	expected := append([]byte{}, builder.cluster(5)...)
+	expected = append(expected, builder.cluster(8)[:5000-testClusterSize]...)
+	assert.Equal(t, expected, readFile("/hello.txt"))
+
+	// Names are case insensitive.
+	assert.Equal(t, expected, readFile("/HELLO.TXT"))
+
+	// Data past the valid data length reads as zeros.
+	expected = append([]byte{}, builder.cluster(7)[:100]...)
+	expected = append(expected, make([]byte, 900)...)
+	assert.Equal(t, expected, readFile("/dir/nested.txt"))
+
+	// Deleted files are recovered from contiguous clusters.
+	expected = append([]byte{}, builder.cluster(9)...)
+	expected = append(expected, builder.cluster(10)[:6000-testClusterSize]...)
+	assert.Equal(t, expected, readFile("/deleted.txt"))
+
+	stat, err := fs_accessor.Lstat("/deleted.txt")
+	assert.NoError(t, err)
+	deleted, _ := stat.Data().Get("deleted")
+	assert.Equal(t, true, deleted)
+
+	assert.Equal(t, []byte{}, readFile("/A very long file name.txt"))
+
+	// 12:30:11.50 at UTC+10:00
+	stat, err = fs_accessor.Lstat("/hello.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 10, 1, 2, 30, 11, 500000000, time.UTC),
+		stat.Mtime())
+	assert.Equal(t, int64(5000), stat.Size())
+
+	stat, err = fs_accessor.Lstat("/dir/nested.txt")
+	assert.NoError(t, err)
+	attr, _ := stat.Data().Get("attr")
+	assert.Equal(t, "RA", attr)
+
+	exfat_ctx, err := GetExFATCache(scope,
+		accessors.MustNewGenericOSPath(image_path), "file")
+	assert.NoError(t, err)
+	assert.Equal(t, "USBSTICK", exfat_ctx.Label)
+	assert.Equal(t, uint32(0x12345678), exfat_ctx.SerialNumber)
+}
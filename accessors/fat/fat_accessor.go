@@ -293,15 +293,37 @@ func (self *FATFileSystemAccessor) OpenWithOSPath(
 		return nil, err
 	}
 
+	var reader io.ReaderAt = stream
+
+	// Deleted files have their FAT chain cleared so we can not follow
+	// it. Assume the file was stored contiguously from its first
+	// cluster, which is usually the case on removable media.
+	if stream.Info != nil && stream.Info.IsDeleted && !stream.Info.IsDir {
+		reader = recoverDeletedFile(fat_ctx, stream)
+	}
+
 	return &readAdapter{
 		info: &FATFileInfo{
 			info:       stream.Info,
 			_full_path: fullpath,
 		},
-		reader: stream,
+		reader: reader,
 	}, nil
 }
 
+func recoverDeletedFile(
+	fat_ctx *fat.FATContext, stream *fat.FATReader) io.ReaderAt {
+	size := int64(stream.Info.Size)
+	runs := stream.Runs()
+
+	// The first cluster was also cleared - nothing to recover.
+	if stream.Info.FirstCluster < 2 || len(runs) == 0 || size == 0 {
+		return io.NewSectionReader(fat_ctx.DiskReader, 0, 0)
+	}
+
+	return io.NewSectionReader(fat_ctx.DiskReader, runs[0], size)
+}
+
 func (self *FATFileSystemAccessor) Lstat(
 	path string) (res accessors.FileInfo, err error) {
 
@@ -348,6 +370,10 @@ This accessor is designed to operate on images directly. It requires a
 delegate accessor to get the raw image and will open files using the
 FAT full path rooted at the top of the filesystem.
 
+Deleted files are listed with the deleted flag set in the Data
+field. Since their cluster chain is cleared on deletion, their content
+is recovered assuming the file was stored contiguously.
+
 ## Example
 
 The following query will glob all the files under the directory 'a'
@@ -0,0 +1,78 @@
+package fat
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+// Layout of fat_very_small.dd (FAT12 with 2048 byte clusters).
+const (
+	fat12FatOffset   = 512
+	fat12FatSize     = 512
+	fat12DataOffset  = 17920
+	fat12ClusterSize = 2048
+
+	// The directory entry of a/b/hello.txt and its LFN entry.
+	fat12HelloEntry = 22112
+)
+
+// Simulate deleting a file which spanned several clusters: the
+// directory entries are marked as deleted and the cluster chain is
+// cleared from both FATs.
+func TestFATDeletedFile(t *testing.T) {
+	image, err := ioutil.ReadFile(filepath.Join(
+		"..", "..", "artifacts", "testdata", "files", "fat_very_small.dd"))
+	assert.NoError(t, err)
+
+	data := testData(5000, 1)
+	copy(image[fat12DataOffset+3*fat12ClusterSize:], data)
+
+	image[fat12HelloEntry-32] = 0xe5
+	image[fat12HelloEntry] = 0xe5
+	image[fat12HelloEntry+28] = byte(len(data) & 0xff)
+	image[fat12HelloEntry+29] = byte(len(data) >> 8)
+
+	// Cluster 5 is the odd entry spanning bytes 7 and 8.
+	for _, fat := range []int{fat12FatOffset, fat12FatOffset + fat12FatSize} {
+		image[fat+7] &= 0x0f
+		image[fat+8] = 0
+	}
+
+	dir, err := ioutil.TempDir("", "fat")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	image_path := filepath.Join(dir, "fat.dd")
+	assert.NoError(t, ioutil.WriteFile(image_path, image, 0644))
+
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+	defer scope.Close()
+
+	fs_accessor := NewFATFileSystemAccessor(scope,
+		accessors.MustNewWindowsNTFSPath(""),
+		accessors.MustNewGenericOSPath(image_path), "file")
+
+	children, err := fs_accessor.ReadDir("/a/b")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(children))
+
+	deleted, _ := children[0].Data().Get("deleted")
+	assert.Equal(t, true, deleted)
+
+	fd, err := fs_accessor.Open("/a/b/" + children[0].Name())
+	assert.NoError(t, err)
+	defer fd.Close()
+
+	recovered, err := ioutil.ReadAll(fd)
+	assert.NoError(t, err)
+	assert.Equal(t, data, recovered)
+}
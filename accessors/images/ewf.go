@@ -0,0 +1,712 @@
+package images
+
+// Support for the Expert Witness Compression Format (EWF).
+//
+// Both the original EnCase format (E01, L01 and S01 files) and the
+// newer EWF2 format (Ex01, Lx01) are supported. The virtual disk is
+// stored in chunks which are optionally compressed. The chunks are
+// located through table sections which may be spread over many
+// segment files.
+//
+// Reference: https://github.com/libyal/libewf/blob/main/documentation/
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+)
+
+var (
+	ewf1Signature = []byte("EVF\x09\x0d\x0a\xff\x00")
+	ewf2Signature = []byte("EVF2\x0d\x0a\x81\x00")
+
+	// Logical evidence files have their own signatures but otherwise
+	// share the same structure.
+	lvf1Signature = []byte("LVF\x09\x0d\x0a\xff\x00")
+	lvf2Signature = []byte("LEF2\x0d\x0a\x81\x00")
+)
+
+const (
+	ewf1HeaderSize      = 13
+	ewf1DescriptorSize  = 76
+	ewf2HeaderSize      = 32
+	ewf2DescriptorSize  = 64
+	ewf1TableHeaderSize = 24
+	ewf2TableHeaderSize = 32
+	ewf2TableEntrySize  = 16
+
+	// EWF2 section types
+	ewf2DeviceInformation = 0x01
+	ewf2CaseData          = 0x02
+	ewf2SectorTable       = 0x04
+	ewf2Next              = 0x0d
+	ewf2Done              = 0x0f
+
+	// EWF2 section data flags
+	ewf2Encrypted = 0x02
+
+	// EWF2 chunk flags
+	ewf2ChunkCompressed  = 0x01
+	ewf2ChunkPatternFill = 0x04
+
+	// EWF2 compression methods
+	ewf2CompressionBzip2 = 2
+
+	// Sanity limits
+	ewfMaxChunkSize  = 64 * 1024 * 1024
+	ewfMaxTableCount = 1 << 24
+)
+
+type ewfChunk struct {
+	// Index into the segments array.
+	segment int
+	offset  int64
+
+	// An upper bound on the stored size of the chunk.
+	size int64
+
+	compressed bool
+
+	// EWF2 pattern fill chunks store an 8 byte pattern instead of
+	// the data.
+	pattern []byte
+
+	// Set when the chunk is present in a table.
+	valid bool
+}
+
+type EWFImage struct {
+	mu sync.Mutex
+
+	version  int
+	segments []io.ReaderAt
+	chunks   []ewfChunk
+
+	chunk_size       int64
+	bytes_per_sector int64
+	sector_count     int64
+	size             int64
+
+	// EWF2 may use bzip2 compression.
+	compression_method int
+
+	// Header values such as case number and examiner.
+	header *ordereddict.Dict
+
+	// The most recently decompressed chunk.
+	cache_idx  int64
+	cache_data []byte
+}
+
+func (self *EWFImage) Size() int64 {
+	return self.size
+}
+
+func (self *EWFImage) Close() error {
+	return nil
+}
+
+func (self *EWFImage) Info() *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Format", fmt.Sprintf("EWF%v", self.version)).
+		Set("Segments", len(self.segments)).
+		Set("ChunkSize", self.chunk_size).
+		Set("BytesPerSector", self.bytes_per_sector).
+		Set("SectorCount", self.sector_count).
+		Set("Header", self.header)
+}
+
+func (self *EWFImage) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("EWF: invalid offset %v", offset)
+	}
+
+	if offset >= self.size {
+		return 0, io.EOF
+	}
+
+	to_read := buf
+	if offset+int64(len(to_read)) > self.size {
+		to_read = to_read[:self.size-offset]
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	n := 0
+	for n < len(to_read) {
+		current := offset + int64(n)
+		chunk_idx := current / self.chunk_size
+		chunk_offset := current % self.chunk_size
+
+		data, err := self.getChunk(chunk_idx)
+		if err != nil {
+			return n, err
+		}
+
+		if chunk_offset >= int64(len(data)) {
+			return n, fmt.Errorf("EWF: chunk %v is truncated", chunk_idx)
+		}
+
+		n += copy(to_read[n:], data[chunk_offset:])
+	}
+
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (self *EWFImage) getChunk(chunk_idx int64) ([]byte, error) {
+	if chunk_idx == self.cache_idx && self.cache_data != nil {
+		return self.cache_data, nil
+	}
+
+	if chunk_idx >= int64(len(self.chunks)) || !self.chunks[chunk_idx].valid {
+		return nil, fmt.Errorf("EWF: chunk %v not found in any table", chunk_idx)
+	}
+
+	chunk := &self.chunks[chunk_idx]
+	data := make([]byte, self.chunk_size)
+
+	switch {
+	case chunk.pattern != nil:
+		for i := 0; i < len(data); i += len(chunk.pattern) {
+			copy(data[i:], chunk.pattern)
+		}
+
+	case chunk.compressed:
+		reader := io.NewSectionReader(
+			self.segments[chunk.segment], chunk.offset, chunk.size)
+		var decompressor io.Reader
+		if self.compression_method == ewf2CompressionBzip2 {
+			decompressor = bzip2.NewReader(reader)
+		} else {
+			zr, err := zlib.NewReader(reader)
+			if err != nil {
+				return nil, fmt.Errorf("EWF: chunk %v: %w", chunk_idx, err)
+			}
+			defer zr.Close()
+			decompressor = zr
+		}
+
+		n, err := io.ReadFull(decompressor, data)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("EWF: chunk %v: %w", chunk_idx, err)
+		}
+
+		// The last chunk of the image may be short.
+		data = data[:n]
+
+	default:
+		n, err := self.segments[chunk.segment].ReadAt(data, chunk.offset)
+		if n == 0 && err != nil {
+			return nil, fmt.Errorf("EWF: chunk %v: %w", chunk_idx, err)
+		}
+		data = data[:n]
+	}
+
+	self.cache_idx = chunk_idx
+	self.cache_data = data
+
+	return data, nil
+}
+
+// Store a chunk in the chunks table at the specified index.
+func (self *EWFImage) setChunk(idx int64, chunk ewfChunk) error {
+	if idx < 0 || idx > ewfMaxTableCount*16 {
+		return fmt.Errorf("EWF: chunk index %v out of range", idx)
+	}
+
+	for int64(len(self.chunks)) <= idx {
+		self.chunks = append(self.chunks, ewfChunk{})
+	}
+	chunk.valid = true
+	self.chunks[idx] = chunk
+	return nil
+}
+
+// Parse the section list of an EWF1 segment. Returns true if more
+// segments follow.
+func (self *EWFImage) parseEWF1Segment(reader io.ReaderAt, file_size int64) (
+	bool, error) {
+	segment := len(self.segments) - 1
+	offset := int64(ewf1HeaderSize)
+
+	for offset+ewf1DescriptorSize <= file_size {
+		desc, err := readBytes(reader, offset, ewf1DescriptorSize)
+		if err != nil {
+			return false, err
+		}
+
+		section_type := string(bytes.TrimRight(desc[:16], "\x00"))
+		next := int64(binary.LittleEndian.Uint64(desc[16:]))
+		section_size := int64(binary.LittleEndian.Uint64(desc[24:]))
+		data_offset := offset + ewf1DescriptorSize
+		data_size := section_size - ewf1DescriptorSize
+
+		switch section_type {
+		case "volume", "disk":
+			err = self.parseEWF1Volume(reader, data_offset, data_size)
+			if err != nil {
+				return false, err
+			}
+
+		case "header", "header2":
+			if self.header == nil {
+				self.header = parseEWFHeader(reader, data_offset, data_size)
+			}
+
+		case "table":
+			err = self.parseEWF1Table(reader, segment,
+				offset, data_offset, section_size, file_size)
+			if err != nil {
+				return false, err
+			}
+
+		case "next":
+			return true, nil
+
+		case "done":
+			return false, nil
+		}
+
+		// Sections must always move forward.
+		if next <= offset {
+			break
+		}
+		offset = next
+	}
+
+	return false, nil
+}
+
+func (self *EWFImage) parseEWF1Volume(
+	reader io.ReaderAt, offset, size int64) error {
+	data, err := readBytes(reader, offset, 24)
+	if err != nil {
+		return err
+	}
+
+	sectors_per_chunk := int64(binary.LittleEndian.Uint32(data[8:]))
+	self.bytes_per_sector = int64(binary.LittleEndian.Uint32(data[12:]))
+
+	// The SMART (S01) volume section only stores a 32 bit sector
+	// count.
+	if size == 94 {
+		self.sector_count = int64(binary.LittleEndian.Uint32(data[16:]))
+	} else {
+		self.sector_count = int64(binary.LittleEndian.Uint64(data[16:]))
+	}
+
+	self.chunk_size = sectors_per_chunk * self.bytes_per_sector
+	return nil
+}
+
+func (self *EWFImage) parseEWF1Table(reader io.ReaderAt, segment int,
+	section_offset, offset, section_size, file_size int64) error {
+	header, err := readBytes(reader, offset, ewf1TableHeaderSize)
+	if err != nil {
+		return err
+	}
+
+	count := int64(binary.LittleEndian.Uint32(header))
+	base_offset := int64(binary.LittleEndian.Uint64(header[8:]))
+	if count > ewfMaxTableCount {
+		return fmt.Errorf("EWF: table too large (%v entries)", count)
+	}
+
+	entries, err := readBytes(reader, offset+ewf1TableHeaderSize, int(count*4))
+	if err != nil {
+		return err
+	}
+
+	// The chunks are stored in the sectors section which precedes
+	// the table, except in very old versions where they follow the
+	// table entries in the same section.
+	first_chunk := int64(len(self.chunks))
+	for i := int64(0); i < count; i++ {
+		entry := binary.LittleEndian.Uint32(entries[i*4:])
+		chunk_offset := base_offset + int64(entry&0x7fffffff)
+
+		var end int64
+		if i+1 < count {
+			next_entry := binary.LittleEndian.Uint32(entries[(i+1)*4:])
+			end = base_offset + int64(next_entry&0x7fffffff)
+		} else if chunk_offset < section_offset {
+			end = section_offset
+		} else {
+			end = section_offset + section_size
+		}
+
+		if end <= chunk_offset || end > file_size {
+			end = file_size
+		}
+
+		err = self.setChunk(first_chunk+i, ewfChunk{
+			segment:    segment,
+			offset:     chunk_offset,
+			size:       end - chunk_offset,
+			compressed: entry&0x80000000 != 0,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Parse the section list of an EWF2 segment. The section descriptors
+// follow the section data so we walk the list backwards from the end
+// of the file. Returns true if more segments follow.
+func (self *EWFImage) parseEWF2Segment(reader io.ReaderAt, file_size int64) (
+	bool, error) {
+	type section struct {
+		section_type uint32
+		flags        uint32
+		data_offset  int64
+		data_size    int64
+	}
+
+	var sections []section
+	offset := file_size - ewf2DescriptorSize
+	for offset >= ewf2HeaderSize {
+		desc, err := readBytes(reader, offset, ewf2DescriptorSize)
+		if err != nil {
+			return false, err
+		}
+
+		previous := int64(binary.LittleEndian.Uint64(desc[8:]))
+		data_size := int64(binary.LittleEndian.Uint64(desc[16:]))
+		if data_size > offset {
+			return false, fmt.Errorf("EWF2: invalid section size at %#x", offset)
+		}
+
+		sections = append(sections, section{
+			section_type: binary.LittleEndian.Uint32(desc),
+			flags:        binary.LittleEndian.Uint32(desc[4:]),
+			data_offset:  offset - data_size,
+			data_size:    data_size,
+		})
+
+		// Sections must always move backwards.
+		if previous >= offset || previous < ewf2HeaderSize {
+			break
+		}
+		offset = previous
+	}
+
+	segment := len(self.segments) - 1
+	more := false
+
+	// Process the sections in file order.
+	for i := len(sections) - 1; i >= 0; i-- {
+		s := sections[i]
+		if s.flags&ewf2Encrypted != 0 &&
+			s.section_type != ewf2Next && s.section_type != ewf2Done {
+			return false, fmt.Errorf("EWF2: encrypted images are not supported")
+		}
+
+		switch s.section_type {
+		case ewf2DeviceInformation, ewf2CaseData:
+			values := parseEWFValues(readEWF2Text(reader, s.data_offset, s.data_size))
+			if self.header == nil {
+				self.header = ordereddict.NewDict()
+			}
+			for _, k := range values.Keys() {
+				v, _ := values.Get(k)
+				self.header.Set(k, v)
+			}
+
+		case ewf2SectorTable:
+			err := self.parseEWF2Table(reader, segment, s.data_offset, file_size)
+			if err != nil {
+				return false, err
+			}
+
+		case ewf2Next:
+			more = true
+
+		case ewf2Done:
+			more = false
+		}
+	}
+
+	return more, nil
+}
+
+func (self *EWFImage) parseEWF2Table(reader io.ReaderAt, segment int,
+	offset, file_size int64) error {
+	header, err := readBytes(reader, offset, ewf2TableHeaderSize)
+	if err != nil {
+		return err
+	}
+
+	first_chunk := int64(binary.LittleEndian.Uint64(header))
+	count := int64(binary.LittleEndian.Uint32(header[8:]))
+	if count > ewfMaxTableCount {
+		return fmt.Errorf("EWF2: table too large (%v entries)", count)
+	}
+
+	entries, err := readBytes(reader, offset+ewf2TableHeaderSize,
+		int(count*ewf2TableEntrySize))
+	if err != nil {
+		return err
+	}
+
+	for i := int64(0); i < count; i++ {
+		entry := entries[i*ewf2TableEntrySize:]
+		chunk_offset := int64(binary.LittleEndian.Uint64(entry))
+		chunk_size := int64(binary.LittleEndian.Uint32(entry[8:]))
+		flags := binary.LittleEndian.Uint32(entry[12:])
+
+		chunk := ewfChunk{
+			segment:    segment,
+			offset:     chunk_offset,
+			size:       chunk_size,
+			compressed: flags&ewf2ChunkCompressed != 0,
+		}
+
+		if flags&ewf2ChunkPatternFill != 0 {
+			chunk.pattern = append([]byte{}, entry[:8]...)
+
+		} else if chunk_offset+chunk_size > file_size {
+			return fmt.Errorf("EWF2: chunk %v out of bounds", first_chunk+i)
+		}
+
+		err = self.setChunk(first_chunk+i, chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Text sections are usually zlib compressed.
+func readEWF2Text(reader io.ReaderAt, offset, size int64) string {
+	zr, err := zlib.NewReader(io.NewSectionReader(reader, offset, size))
+	if err == nil {
+		defer zr.Close()
+		data, err := ioutil.ReadAll(io.LimitReader(zr, ewfMaxChunkSize))
+		if err == nil {
+			return decodeText(data)
+		}
+	}
+
+	data, err := readBytes(reader, offset, int(size))
+	if err != nil {
+		return ""
+	}
+	return decodeText(data)
+}
+
+// The EWF1 header sections are compressed text in the same layout
+// as the EWF2 case data.
+func parseEWFHeader(reader io.ReaderAt, offset, size int64) *ordereddict.Dict {
+	return parseEWFValues(readEWF2Text(reader, offset, size))
+}
+
+// The text is a tab separated table where the third line holds the
+// keys and the fourth line holds the values.
+func parseEWFValues(text string) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+	lines := strings.Split(strings.ReplaceAll(text, "\r", ""), "\n")
+	if len(lines) < 4 {
+		return result
+	}
+
+	keys := strings.Split(lines[2], "\t")
+	values := strings.Split(lines[3], "\t")
+	for i, k := range keys {
+		if k == "" || i >= len(values) {
+			continue
+		}
+		result.Set(k, values[i])
+	}
+	return result
+}
+
+// Calculate the name of the segment with the specified number
+// (starting at 1) using the same naming scheme as the first segment:
+// E01 ... E99, EAA ... EZZ, FAA ... and Ex01 ... Ex99, ExAA ... ExZZ
+func ewfSegmentName(name string, number int) (string, error) {
+	idx := strings.LastIndex(name, ".")
+	if idx < 0 {
+		return "", fmt.Errorf("EWF: segment %v has no extension", name)
+	}
+	base := name[:idx+1]
+	ext := name[idx+1:]
+
+	lower := len(ext) > 0 && ext[0] >= 'a' && ext[0] <= 'z'
+
+	var result string
+	switch len(ext) {
+	case 3:
+		first := int(strings.ToUpper(ext[:1])[0])
+		if number < 100 {
+			result = fmt.Sprintf("%c%02d", first, number)
+		} else {
+			m := number - 100
+			first += m / (26 * 26)
+			if first > 'Z' {
+				return "", fmt.Errorf("EWF: too many segments")
+			}
+			result = fmt.Sprintf("%c%c%c", first, 'A'+(m/26)%26, 'A'+m%26)
+		}
+
+	case 4:
+		if number < 100 {
+			result = fmt.Sprintf("%02d", number)
+		} else {
+			m := number - 100
+			if m >= 26*26 {
+				return "", fmt.Errorf("EWF: too many segments")
+			}
+			result = fmt.Sprintf("%c%c", 'A'+m/26, 'A'+m%26)
+		}
+		result = ext[:2] + result
+
+	default:
+		return "", fmt.Errorf("EWF: unsupported segment extension %v", ext)
+	}
+
+	if lower {
+		result = strings.ToLower(result)
+	}
+
+	return base + result, nil
+}
+
+func OpenEWF(opener *segmentOpener, reader io.ReaderAt, size int64,
+	filename *accessors.OSPath, depth int) (Image, error) {
+	signature, err := readBytes(reader, 0, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EWFImage{
+		cache_idx:        -1,
+		bytes_per_sector: 512,
+	}
+
+	switch {
+	case bytes.Equal(signature, ewf1Signature),
+		bytes.Equal(signature, lvf1Signature):
+		result.version = 1
+
+	case bytes.Equal(signature, ewf2Signature),
+		bytes.Equal(signature, lvf2Signature):
+		result.version = 2
+
+	default:
+		return nil, NotSupportedError
+	}
+
+	name := filename.Basename()
+	for number := 1; ; number++ {
+		if number > 1 {
+			segment_name, err := ewfSegmentName(name, number)
+			if err != nil {
+				return nil, err
+			}
+
+			reader, size, _, err = opener.OpenSibling(filename, segment_name)
+			if err != nil {
+				return nil, fmt.Errorf("EWF: unable to open segment %v: %w",
+					segment_name, err)
+			}
+		}
+
+		more, err := result.parseSegment(reader, size, number)
+		if err != nil {
+			return nil, err
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return result, result.finalize()
+}
+
+func (self *EWFImage) parseSegment(
+	reader io.ReaderAt, size int64, number int) (bool, error) {
+	if self.version == 1 {
+		header, err := readBytes(reader, 0, ewf1HeaderSize)
+		if err != nil {
+			return false, err
+		}
+
+		segment_number := int(binary.LittleEndian.Uint16(header[9:]))
+		if segment_number != number {
+			return false, fmt.Errorf("EWF: expected segment %v but found %v",
+				number, segment_number)
+		}
+
+		self.segments = append(self.segments, reader)
+		return self.parseEWF1Segment(reader, size)
+	}
+
+	header, err := readBytes(reader, 0, ewf2HeaderSize)
+	if err != nil {
+		return false, err
+	}
+
+	segment_number := int(binary.LittleEndian.Uint32(header[12:]))
+	if segment_number != number {
+		return false, fmt.Errorf("EWF2: expected segment %v but found %v",
+			number, segment_number)
+	}
+	self.compression_method = int(binary.LittleEndian.Uint16(header[10:]))
+
+	self.segments = append(self.segments, reader)
+	return self.parseEWF2Segment(reader, size)
+}
+
+// Work out the geometry of the disk once all the segments are
+// parsed.
+func (self *EWFImage) finalize() error {
+	if self.version == 2 && self.header != nil {
+		self.bytes_per_sector = getHeaderInt(self.header, "bp", 512)
+		self.sector_count = getHeaderInt(self.header, "ts", 0)
+		self.chunk_size = getHeaderInt(self.header, "sb", 64) *
+			self.bytes_per_sector
+	}
+
+	if self.chunk_size <= 0 || self.chunk_size > ewfMaxChunkSize {
+		return fmt.Errorf("EWF: invalid chunk size %v", self.chunk_size)
+	}
+
+	self.size = self.sector_count * self.bytes_per_sector
+	if self.size <= 0 {
+		self.size = int64(len(self.chunks)) * self.chunk_size
+	}
+
+	return nil
+}
+
+func getHeaderInt(header *ordereddict.Dict, key string, default_value int64) int64 {
+	value, pres := header.GetString(key)
+	if !pres {
+		return default_value
+	}
+
+	var result int64
+	_, err := fmt.Sscanf(value, "%d", &result)
+	if err != nil || result <= 0 {
+		return default_value
+	}
+	return result
+}
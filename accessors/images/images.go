@@ -0,0 +1,345 @@
+// Accessors that expose the virtual disk stored inside common forensic
+// and virtualization container formats (EWF, VHD, VHDX and VMDK).
+//
+// The accessors are delegate style accessors - the delegate refers to
+// the (first segment of the) image file and the accessor presents
+// the contained disk as a single flat file. Additional segments,
+// extents and parent images are opened with the same delegate
+// accessor from the directory containing the image.
+//
+// For example to read the partition table of an EWF image:
+//
+// SELECT * FROM Artifact.Windows.Forensics.PartitionTable(
+//   ImagePath=pathspec(DelegateAccessor="file",
+//                      DelegatePath="/images/disk.E01"),
+//   Accessor="ewf")
+
+package images
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/accessors/zip"
+	"www.velocidex.com/golang/velociraptor/constants"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/readers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	// Maximum depth of parent (snapshot) chains we follow. This
+	// protects against loops in the parent references.
+	MAX_PARENT_DEPTH = 16
+)
+
+var (
+	NotSupportedError = errors.New("Image format not supported")
+)
+
+// An Image is a parsed container presenting the virtual disk as a
+// flat ReaderAt.
+type Image interface {
+	io.ReaderAt
+	Size() int64
+	Close() error
+
+	// Information about the container for the file info Data()
+	Info() *ordereddict.Dict
+}
+
+// Opens the files making up an image using the delegate accessor.
+type segmentOpener struct {
+	scope    vfilter.Scope
+	accessor string
+	lru_size int
+
+	mu      sync.Mutex
+	readers []*readers.AccessorReader
+}
+
+// Open a file and return a ReaderAt with its size. The reader is
+// owned by the opener and will be closed with it.
+func (self *segmentOpener) Open(filename *accessors.OSPath) (
+	io.ReaderAt, int64, error) {
+	accessor, err := accessors.GetAccessor(self.accessor, self.scope)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stat, err := accessor.LstatWithOSPath(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if stat.IsDir() {
+		return nil, 0, fmt.Errorf("%v: is a directory", filename.String())
+	}
+
+	reader, err := readers.NewPagedReader(
+		self.scope, self.accessor, filename, self.lru_size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	self.mu.Lock()
+	self.readers = append(self.readers, reader)
+	self.mu.Unlock()
+
+	return reader, stat.Size(), nil
+}
+
+// Open a file in the same directory as filename.
+func (self *segmentOpener) OpenSibling(filename *accessors.OSPath, name string) (
+	io.ReaderAt, int64, *accessors.OSPath, error) {
+	sibling := filename.Dirname().Append(name)
+	reader, size, err := self.Open(sibling)
+	return reader, size, sibling, err
+}
+
+// Open a parent image referenced by a child. References are usually
+// recorded as absolute paths on the system that created the
+// image. Since images are rarely analyzed in their original location
+// we look for the parent by name in the same directory as the child.
+func (self *segmentOpener) OpenParent(
+	child *accessors.OSPath, depth int, opener imageOpener,
+	candidates ...string) (Image, error) {
+	if depth >= MAX_PARENT_DEPTH {
+		return nil, fmt.Errorf("Parent chain too deep for %v", child.String())
+	}
+
+	var last_err error = fmt.Errorf("No parent locator in %v", child.String())
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		name := baseName(candidate)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		parent_path := child.Dirname().Append(name)
+		if parent_path.String() == child.String() {
+			continue
+		}
+
+		reader, size, err := self.Open(parent_path)
+		if err != nil {
+			last_err = err
+			continue
+		}
+
+		parent, err := opener(self, reader, size, parent_path, depth+1)
+		if err != nil {
+			last_err = err
+			continue
+		}
+		return parent, nil
+	}
+
+	return nil, fmt.Errorf("Unable to open parent image: %w", last_err)
+}
+
+func (self *segmentOpener) Close() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for _, r := range self.readers {
+		r.Close()
+	}
+	self.readers = nil
+	return nil
+}
+
+// Parses an image from its first file.
+type imageOpener func(opener *segmentOpener,
+	reader io.ReaderAt, size int64,
+	filename *accessors.OSPath, depth int) (Image, error)
+
+// Strip any directory components from a windows or unix path.
+func baseName(path string) string {
+	path = strings.TrimRight(path, "\\/\x00")
+	idx := strings.LastIndexAny(path, "\\/:")
+	if idx >= 0 {
+		path = path[idx+1:]
+	}
+	return strings.TrimSpace(path)
+}
+
+// A ReadSeekCloser over an image. The image itself is shared in the
+// scope cache so closing the reader does not close the image.
+type ImageReader struct {
+	image  Image
+	offset int64
+	info   accessors.FileInfo
+}
+
+func (self *ImageReader) Close() error {
+	return nil
+}
+
+func (self *ImageReader) ReadAt(buff []byte, offset int64) (int, error) {
+	return self.image.ReadAt(buff, offset)
+}
+
+func (self *ImageReader) Read(buff []byte) (int, error) {
+	n, err := self.image.ReadAt(buff, self.offset)
+	self.offset += int64(n)
+	return n, err
+}
+
+func (self *ImageReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		self.offset = offset
+	case io.SeekCurrent:
+		self.offset += offset
+	case io.SeekEnd:
+		self.offset = self.image.Size() + offset
+	default:
+		return 0, fmt.Errorf("Invalid whence %v", whence)
+	}
+
+	if self.offset < 0 {
+		self.offset = 0
+		return 0, os.ErrInvalid
+	}
+
+	return self.offset, nil
+}
+
+func (self *ImageReader) LStat() (accessors.FileInfo, error) {
+	return self.info, nil
+}
+
+func getImage(scope vfilter.Scope, format string,
+	opener imageOpener, full_path *accessors.OSPath) (Image, error) {
+
+	pathspec := full_path.PathSpec()
+
+	// The image accessors must use a delegate but if one is not
+	// provided we use the "auto" accessor, to open the underlying
+	// file.
+	if pathspec.DelegateAccessor == "" && pathspec.GetDelegatePath() == "" {
+		pathspec.DelegatePath = pathspec.Path
+		pathspec.DelegateAccessor = "auto"
+	}
+
+	err := vql_subsystem.CheckFilesystemAccess(scope, pathspec.DelegateAccessor)
+	if err != nil {
+		scope.Log("%v: DelegateAccessor denied", err)
+		return nil, err
+	}
+
+	accessor, err := accessors.GetAccessor(pathspec.DelegateAccessor, scope)
+	if err != nil {
+		scope.Log("%v: did you provide a URL or PathSpec?", err)
+		return nil, err
+	}
+
+	device, err := accessor.ParsePath(pathspec.GetDelegatePath())
+	if err != nil {
+		return nil, err
+	}
+
+	key := "image_cache_" + format + pathspec.DelegateAccessor + device.String()
+
+	// Get the parsed image from the root scope's cache
+	image, ok := vql_subsystem.CacheGet(scope, key).(Image)
+	if ok {
+		return image, nil
+	}
+
+	lru_size := vql_subsystem.GetIntFromRow(
+		scope, scope, constants.NTFS_CACHE_SIZE)
+
+	segments := &segmentOpener{
+		scope:    scope,
+		accessor: pathspec.DelegateAccessor,
+		lru_size: int(lru_size),
+	}
+
+	reader, size, err := segments.Open(device)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err = opener(segments, reader, size, device, 0)
+	if err != nil {
+		segments.Close()
+		return nil, fmt.Errorf("%v: %w", format, err)
+	}
+
+	vql_subsystem.CacheSet(scope, key, image)
+
+	// Close the segments when we are done with this query.
+	err = vql_subsystem.GetRootScope(scope).AddDestructor(func() {
+		image.Close()
+		segments.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func makeGetter(format string, opener imageOpener) zip.FileGetter {
+	return func(full_path *accessors.OSPath, scope vfilter.Scope) (
+		zip.ReaderStat, error) {
+
+		image, err := getImage(scope, format, opener, full_path)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ImageReader{
+			image: image,
+			info: &accessors.VirtualFileInfo{
+				Path:  full_path,
+				Size_: image.Size(),
+				Data_: image.Info(),
+			},
+		}, nil
+	}
+}
+
+func init() {
+	accessors.Register("ewf", zip.NewGzipFileSystemAccessor(
+		accessors.MustNewPathspecOSPath(""), makeGetter("ewf", OpenEWF)),
+		`Read the disk image stored in an Expert Witness (E01/Ex01) file.
+
+The delegate should point at the first segment. Further segments
+(E02, E03 ... or Ex02, Ex03 ...) are opened from the same directory.
+`)
+
+	accessors.Register("vhd", zip.NewGzipFileSystemAccessor(
+		accessors.MustNewPathspecOSPath(""), makeGetter("vhd", OpenVHD)),
+		`Read the disk stored in a fixed, dynamic or differencing VHD file.
+
+Parents of differencing disks are searched by name in the same
+directory as the child.
+`)
+
+	accessors.Register("vhdx", zip.NewGzipFileSystemAccessor(
+		accessors.MustNewPathspecOSPath(""), makeGetter("vhdx", OpenVHDX)),
+		`Read the disk stored in a VHDX file.
+
+Parents of differencing disks are searched by name in the same
+directory as the child. Unreplayed log entries are ignored.
+`)
+
+	accessors.Register("vmdk", zip.NewGzipFileSystemAccessor(
+		accessors.MustNewPathspecOSPath(""), makeGetter("vmdk", OpenVMDK)),
+		`Read the disk stored in a VMDK file.
+
+The delegate may point to either a descriptor file or a sparse
+extent. Extents and snapshot parents are opened from the same
+directory.
+`)
+}
@@ -0,0 +1,621 @@
+package images
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/adler32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/file"
+)
+
+// Generate some data which is different in every sector.
+func testData(size int, seed byte) []byte {
+	result := make([]byte, size)
+	for i := range result {
+		result[i] = byte(i/512) + seed + byte(i%7)
+	}
+	return result
+}
+
+func compress(data []byte) []byte {
+	b := &bytes.Buffer{}
+	w := zlib.NewWriter(b)
+	w.Write(data)
+	w.Close()
+	return b.Bytes()
+}
+
+func utf16LE(s string) []byte {
+	result := []byte{}
+	for _, c := range utf16.Encode([]rune(s)) {
+		result = binary.LittleEndian.AppendUint16(result, c)
+	}
+	return result
+}
+
+func utf16BE(s string) []byte {
+	result := []byte{}
+	for _, c := range utf16.Encode([]rune(s)) {
+		result = binary.BigEndian.AppendUint16(result, c)
+	}
+	return result
+}
+
+func readImage(t *testing.T, accessor_name, filename string) []byte {
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+	defer scope.Close()
+
+	accessor, err := accessors.GetAccessor(accessor_name, scope)
+	assert.NoError(t, err)
+
+	pathspec := &accessors.PathSpec{
+		DelegateAccessor: "file",
+		DelegatePath:     filename,
+	}
+
+	stat, err := accessor.Lstat(pathspec.String())
+	assert.NoError(t, err)
+
+	fd, err := accessor.Open(pathspec.String())
+	assert.NoError(t, err)
+	defer fd.Close()
+
+	data, err := ioutil.ReadAll(fd)
+	assert.NoError(t, err)
+	assert.Equal(t, stat.Size(), int64(len(data)))
+
+	return data
+}
+
+type ewf1Writer struct {
+	bytes.Buffer
+}
+
+func (self *ewf1Writer) section(section_type string, data []byte) int64 {
+	offset := int64(self.Len())
+	size := int64(ewf1DescriptorSize + len(data))
+	next := offset + size
+	if section_type == "next" || section_type == "done" {
+		next = offset
+	}
+
+	desc := make([]byte, ewf1DescriptorSize)
+	copy(desc, section_type)
+	binary.LittleEndian.PutUint64(desc[16:], uint64(next))
+	binary.LittleEndian.PutUint64(desc[24:], uint64(size))
+	binary.LittleEndian.PutUint32(desc[72:], adler32.Checksum(desc[:72]))
+
+	self.Write(desc)
+	self.Write(data)
+	return offset
+}
+
+func buildEWF1Segment(number int, chunks [][]byte, last bool) []byte {
+	w := &ewf1Writer{}
+	w.Write(ewf1Signature)
+	w.Write([]byte{1, byte(number), 0, 0, 0})
+
+	if number == 1 {
+		w.section("header", compress([]byte(
+			"1\nmain\nc\tn\te\nCase1\tEvidence1\tExaminer\n\n")))
+
+		volume := make([]byte, 1052)
+		binary.LittleEndian.PutUint32(volume[4:], 4)
+		binary.LittleEndian.PutUint32(volume[8:], 8)
+		binary.LittleEndian.PutUint32(volume[12:], 512)
+		binary.LittleEndian.PutUint64(volume[16:], 4*8)
+		w.section("volume", volume)
+	}
+
+	// Alternate compressed and uncompressed chunks
+	sectors := &bytes.Buffer{}
+	var entries []uint32
+	sectors_offset := int64(w.Len() + ewf1DescriptorSize)
+	for i, chunk := range chunks {
+		offset := uint32(sectors_offset) + uint32(sectors.Len())
+		if i%2 == 0 {
+			sectors.Write(compress(chunk))
+			offset |= 0x80000000
+		} else {
+			sectors.Write(chunk)
+			sectors.Write(binary.LittleEndian.AppendUint32(
+				nil, adler32.Checksum(chunk)))
+		}
+		entries = append(entries, offset)
+	}
+	w.section("sectors", sectors.Bytes())
+
+	table := make([]byte, ewf1TableHeaderSize)
+	binary.LittleEndian.PutUint32(table, uint32(len(entries)))
+	for _, e := range entries {
+		table = binary.LittleEndian.AppendUint32(table, e)
+	}
+	w.section("table", table)
+
+	if last {
+		w.section("done", nil)
+	} else {
+		w.section("next", nil)
+	}
+	return w.Bytes()
+}
+
+func TestEWF1(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "images_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	chunk_size := 8 * 512
+	expected := testData(4*chunk_size, 1)
+	var chunks [][]byte
+	for i := 0; i < 4; i++ {
+		chunks = append(chunks, expected[i*chunk_size:(i+1)*chunk_size])
+	}
+
+	// Split the image over two segments
+	err = ioutil.WriteFile(filepath.Join(tmpdir, "image.E01"),
+		buildEWF1Segment(1, chunks[:2], false), 0644)
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(tmpdir, "image.E02"),
+		buildEWF1Segment(2, chunks[2:], true), 0644)
+	assert.NoError(t, err)
+
+	data := readImage(t, "ewf", filepath.Join(tmpdir, "image.E01"))
+	assert.Equal(t, expected, data)
+}
+
+func buildEWF2(expected []byte, chunk_size int) []byte {
+	w := &bytes.Buffer{}
+	w.Write(ewf2Signature)
+	header := make([]byte, ewf2HeaderSize-len(ewf2Signature))
+	header[0] = 2
+	header[1] = 1
+	binary.LittleEndian.PutUint16(header[2:], 1)
+	binary.LittleEndian.PutUint32(header[4:], 1)
+	w.Write(header)
+
+	previous := int64(0)
+	section := func(section_type uint32, data []byte) {
+		w.Write(data)
+		desc := make([]byte, ewf2DescriptorSize)
+		binary.LittleEndian.PutUint32(desc, section_type)
+		binary.LittleEndian.PutUint64(desc[8:], uint64(previous))
+		binary.LittleEndian.PutUint64(desc[16:], uint64(len(data)))
+		binary.LittleEndian.PutUint32(desc[24:], ewf2DescriptorSize)
+		previous = int64(w.Len())
+		w.Write(desc)
+	}
+
+	section(ewf2DeviceInformation, compress(append([]byte{0xff, 0xfe},
+		utf16LE("1\nmain\nsn\tbp\tts\nSERIAL\t512\t24\n\n")...)))
+	section(ewf2CaseData, compress(append([]byte{0xff, 0xfe},
+		utf16LE("1\nmain\nnm\tsb\nCase2\t8\n\n")...)))
+
+	// The first chunk is compressed, the second is stored and the
+	// third is a pattern fill.
+	table := make([]byte, ewf2TableHeaderSize)
+	binary.LittleEndian.PutUint32(table[8:], 3)
+
+	sectors := &bytes.Buffer{}
+	add_entry := func(offset int64, data []byte, flags uint32) {
+		entry := make([]byte, ewf2TableEntrySize)
+		binary.LittleEndian.PutUint64(entry, uint64(offset))
+		binary.LittleEndian.PutUint32(entry[8:], uint32(len(data)))
+		binary.LittleEndian.PutUint32(entry[12:], flags)
+		table = append(table, entry...)
+	}
+
+	data_offset := int64(w.Len())
+	compressed := compress(expected[:chunk_size])
+	add_entry(data_offset, compressed, ewf2ChunkCompressed)
+	sectors.Write(compressed)
+
+	add_entry(data_offset+int64(sectors.Len()),
+		expected[chunk_size:2*chunk_size], 0)
+	sectors.Write(expected[chunk_size : 2*chunk_size])
+
+	entry := make([]byte, ewf2TableEntrySize)
+	copy(entry, expected[2*chunk_size:2*chunk_size+8])
+	binary.LittleEndian.PutUint32(entry[8:], 8)
+	binary.LittleEndian.PutUint32(entry[12:],
+		ewf2ChunkCompressed|ewf2ChunkPatternFill)
+	table = append(table, entry...)
+
+	section(0x03, sectors.Bytes())
+	section(ewf2SectorTable, table)
+	section(ewf2Done, nil)
+
+	return w.Bytes()
+}
+
+func TestEWF2(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "images_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	chunk_size := 8 * 512
+	expected := testData(3*chunk_size, 2)
+
+	// The last chunk is a repeating pattern.
+	for i := 2 * chunk_size; i < 3*chunk_size; i++ {
+		expected[i] = byte(i % 8)
+	}
+
+	filename := filepath.Join(tmpdir, "image.Ex01")
+	err = ioutil.WriteFile(filename, buildEWF2(expected, chunk_size), 0644)
+	assert.NoError(t, err)
+
+	data := readImage(t, "ewf", filename)
+	assert.Equal(t, expected, data)
+}
+
+func TestEWFSegmentNames(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		number   int
+		expected string
+	}{
+		{"image.E01", 2, "image.E02"},
+		{"image.E01", 99, "image.E99"},
+		{"image.E01", 100, "image.EAA"},
+		{"image.E01", 101, "image.EAB"},
+		{"image.E01", 100 + 26*26, "image.FAA"},
+		{"image.e01", 100, "image.eaa"},
+		{"image.Ex01", 12, "image.Ex12"},
+		{"image.Ex01", 127, "image.ExBB"},
+	} {
+		name, err := ewfSegmentName(c.name, c.number)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, name)
+	}
+}
+
+// Build a dynamic or differencing VHD with 4096 byte blocks. Blocks
+// are a map of block number to sector bitmap and data.
+func buildVHD(disk_size int64, blocks map[int]byte,
+	data []byte, parent string) []byte {
+	block_size := int64(4096)
+	entries := disk_size / block_size
+
+	footer := make([]byte, vhdFooterSize)
+	copy(footer, vhdFooterCookie)
+	binary.BigEndian.PutUint64(footer[16:], vhdFooterSize)
+	binary.BigEndian.PutUint64(footer[40:], uint64(disk_size))
+	binary.BigEndian.PutUint64(footer[48:], uint64(disk_size))
+	if parent != "" {
+		binary.BigEndian.PutUint32(footer[60:], vhdTypeDifferencing)
+	} else {
+		binary.BigEndian.PutUint32(footer[60:], vhdTypeDynamic)
+	}
+
+	bat_offset := int64(vhdFooterSize + vhdHeaderSize)
+	locator_offset := bat_offset + 512
+	blocks_offset := locator_offset + 512
+
+	header := make([]byte, vhdHeaderSize)
+	copy(header, vhdHeaderCookie)
+	binary.BigEndian.PutUint64(header[16:], uint64(bat_offset))
+	binary.BigEndian.PutUint32(header[28:], uint32(entries))
+	binary.BigEndian.PutUint32(header[32:], uint32(block_size))
+
+	locator := make([]byte, 512)
+	if parent != "" {
+		copy(header[64:], utf16BE("Missing.vhd"))
+
+		relative_path := utf16LE(".\\" + parent)
+		copy(locator, relative_path)
+		binary.BigEndian.PutUint32(header[576:], vhdPlatformW2ru)
+		binary.BigEndian.PutUint32(header[576+4:], 512)
+		binary.BigEndian.PutUint32(header[576+8:], uint32(len(relative_path)))
+		binary.BigEndian.PutUint64(header[576+16:], uint64(locator_offset))
+	}
+
+	bat := bytes.Repeat([]byte{0xff}, 512)
+	block_data := &bytes.Buffer{}
+	for i := 0; i < int(entries); i++ {
+		bitmap, pres := blocks[i]
+		if !pres {
+			continue
+		}
+
+		sector := (blocks_offset + int64(block_data.Len())) / vhdSectorSize
+		binary.BigEndian.PutUint32(bat[i*4:], uint32(sector))
+
+		bitmap_sector := make([]byte, 512)
+		bitmap_sector[0] = bitmap
+		block_data.Write(bitmap_sector)
+		block_data.Write(data[int64(i)*block_size : int64(i+1)*block_size])
+	}
+
+	result := &bytes.Buffer{}
+	result.Write(footer)
+	result.Write(header)
+	result.Write(bat)
+	result.Write(locator)
+	result.Write(block_data.Bytes())
+	result.Write(footer)
+	return result.Bytes()
+}
+
+func TestVHD(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "images_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	disk_size := int64(4 * 4096)
+	parent_data := testData(int(disk_size), 3)
+
+	// Block 3 is not allocated in the parent and reads as zeros.
+	parent := buildVHD(disk_size, map[int]byte{0: 0xff, 1: 0xff, 2: 0xff},
+		parent_data, "")
+	err = ioutil.WriteFile(filepath.Join(tmpdir, "parent.vhd"), parent, 0644)
+	assert.NoError(t, err)
+
+	expected := append([]byte{}, parent_data...)
+	zero(expected[3*4096:])
+
+	data := readImage(t, "vhd", filepath.Join(tmpdir, "parent.vhd"))
+	assert.Equal(t, expected, data)
+
+	// The child only has sectors 0 and 2 of block 1
+	child_data := testData(int(disk_size), 100)
+	child := buildVHD(disk_size, map[int]byte{1: 0xa0}, child_data, "parent.vhd")
+	err = ioutil.WriteFile(filepath.Join(tmpdir, "child.vhd"), child, 0644)
+	assert.NoError(t, err)
+
+	copy(expected[4096:4096+512], child_data[4096:])
+	copy(expected[4096+1024:4096+1536], child_data[4096+1024:])
+
+	data = readImage(t, "vhd", filepath.Join(tmpdir, "child.vhd"))
+	assert.Equal(t, expected, data)
+}
+
+const mb = 1024 * 1024
+
+// Build a VHDX with 1mb blocks. Blocks maps the block number to the
+// BAT state. Partially present blocks use the sector bitmap.
+func buildVHDX(disk_size int64, blocks map[int]uint64,
+	data []byte, bitmap []byte, parent string) []byte {
+	result := make([]byte, 3*mb)
+	copy(result, vhdxSignature)
+
+	// Region table
+	regions := result[vhdxRegionTableOffset:]
+	copy(regions, vhdxRegionSignature)
+	binary.LittleEndian.PutUint32(regions[8:], 2)
+	copy(regions[16:], vhdxMetadataRegion)
+	binary.LittleEndian.PutUint64(regions[16+16:], 512*1024)
+	binary.LittleEndian.PutUint32(regions[16+24:], 64*1024)
+	copy(regions[48:], vhdxBATRegion)
+	binary.LittleEndian.PutUint64(regions[48+16:], 576*1024)
+	binary.LittleEndian.PutUint32(regions[48+24:], 64*1024)
+
+	// Metadata
+	metadata := result[512*1024:]
+	copy(metadata, vhdxMetadataSignature)
+	item_offset := 4096
+	item := func(idx int, guid []byte, data []byte) {
+		entry := metadata[32+idx*32:]
+		copy(entry, guid)
+		binary.LittleEndian.PutUint32(entry[16:], uint32(item_offset))
+		binary.LittleEndian.PutUint32(entry[20:], uint32(len(data)))
+		copy(metadata[item_offset:], data)
+		item_offset += len(data)
+	}
+
+	parameters := binary.LittleEndian.AppendUint32(nil, mb)
+	if parent != "" {
+		parameters = binary.LittleEndian.AppendUint32(parameters, vhdxHasParent)
+	} else {
+		parameters = binary.LittleEndian.AppendUint32(parameters, 0)
+	}
+
+	item(0, vhdxFileParameters, parameters)
+	item(1, vhdxVirtualDiskSize,
+		binary.LittleEndian.AppendUint64(nil, uint64(disk_size)))
+	item(2, vhdxLogicalSectorSize, binary.LittleEndian.AppendUint32(nil, 512))
+	count := 3
+
+	if parent != "" {
+		key := utf16LE("relative_path")
+		value := utf16LE(".\\" + parent)
+		locator := make([]byte, 32)
+		binary.LittleEndian.PutUint16(locator[18:], 1)
+		binary.LittleEndian.PutUint32(locator[20:], 32)
+		binary.LittleEndian.PutUint32(locator[24:], uint32(32+len(key)))
+		binary.LittleEndian.PutUint16(locator[28:], uint16(len(key)))
+		binary.LittleEndian.PutUint16(locator[30:], uint16(len(value)))
+		locator = append(locator, key...)
+		locator = append(locator, value...)
+		item(3, vhdxParentLocator, locator)
+		count++
+	}
+	binary.LittleEndian.PutUint16(metadata[10:], uint16(count))
+
+	// Payload blocks follow the headers at 1mb boundaries.
+	bat_offset := 576 * 1024
+	for i := 0; i < int(disk_size/mb); i++ {
+		state, pres := blocks[i]
+		if !pres {
+			continue
+		}
+
+		offset := int64(len(result))
+		binary.LittleEndian.PutUint64(result[bat_offset+i*8:],
+			uint64(offset)|state)
+		result = append(result, data[i*mb:(i+1)*mb]...)
+	}
+
+	if bitmap != nil {
+		// The chunk ratio for 1mb blocks and 512 byte sectors is 4096
+		offset := int64(len(result))
+		binary.LittleEndian.PutUint64(result[bat_offset+4096*8:],
+			uint64(offset)|vhdxBlockFullyPresent)
+		result = append(result, bitmap...)
+		result = append(result, make([]byte, mb-len(bitmap))...)
+	}
+
+	return result
+}
+
+func TestVHDX(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "images_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	disk_size := int64(2 * mb)
+	parent_data := testData(int(disk_size), 4)
+
+	// Block 1 is not present in the parent
+	parent := buildVHDX(disk_size, map[int]uint64{0: vhdxBlockFullyPresent},
+		parent_data, nil, "")
+	err = ioutil.WriteFile(filepath.Join(tmpdir, "parent.vhdx"), parent, 0644)
+	assert.NoError(t, err)
+
+	expected := append([]byte{}, parent_data...)
+	zero(expected[mb:])
+
+	data := readImage(t, "vhdx", filepath.Join(tmpdir, "parent.vhdx"))
+	assert.Equal(t, expected, data)
+
+	// The child has sectors 1 and 3 of block 0 and all of block 1
+	child_data := testData(int(disk_size), 200)
+	child := buildVHDX(disk_size, map[int]uint64{
+		0: vhdxBlockPartiallyPresent,
+		1: vhdxBlockFullyPresent,
+	}, child_data, []byte{0x0a}, "parent.vhdx")
+	err = ioutil.WriteFile(filepath.Join(tmpdir, "child.vhdx"), child, 0644)
+	assert.NoError(t, err)
+
+	copy(expected[512:1024], child_data[512:])
+	copy(expected[1536:2048], child_data[1536:])
+	copy(expected[mb:], child_data[mb:])
+
+	data = readImage(t, "vhdx", filepath.Join(tmpdir, "child.vhdx"))
+	assert.Equal(t, expected, data)
+}
+
+// Build a hosted sparse extent with 4kb grains. Grains maps the grain
+// number to its data.
+func buildVMDKSparse(capacity int64, grains map[int][]byte,
+	descriptor string, compressed bool) []byte {
+	header := make([]byte, 512)
+	copy(header, vmdkSparseMagic)
+	binary.LittleEndian.PutUint32(header[4:], 1)
+	if compressed {
+		binary.LittleEndian.PutUint32(header[8:], vmdkFlagCompressed)
+	}
+	binary.LittleEndian.PutUint64(header[12:], uint64(capacity))
+	binary.LittleEndian.PutUint64(header[20:], 8)
+	if descriptor != "" {
+		binary.LittleEndian.PutUint64(header[28:], 1)
+		binary.LittleEndian.PutUint64(header[36:], 2)
+	}
+	binary.LittleEndian.PutUint32(header[44:], 512)
+	binary.LittleEndian.PutUint64(header[56:], 3)
+
+	result := make([]byte, 8*512)
+	copy(result, header)
+	copy(result[512:], descriptor)
+
+	// The grain directory at sector 3 points to the grain table at
+	// sector 4.
+	binary.LittleEndian.PutUint32(result[3*512:], 4)
+	for i := 0; i < int(capacity/8); i++ {
+		data, pres := grains[i]
+		if !pres {
+			continue
+		}
+
+		sector := len(result) / 512
+		binary.LittleEndian.PutUint32(result[4*512+i*4:], uint32(sector))
+		if compressed {
+			c := compress(data)
+			marker := binary.LittleEndian.AppendUint64(nil, uint64(i*8))
+			marker = binary.LittleEndian.AppendUint32(marker, uint32(len(c)))
+			grain := append(marker, c...)
+			grain = append(grain, make([]byte, 512-len(grain)%512)...)
+			result = append(result, grain...)
+		} else {
+			result = append(result, data...)
+		}
+	}
+
+	return result
+}
+
+func TestVMDK(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "images_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	// The parent is made of a flat extent, a zero extent and a sparse
+	// extent with an unallocated grain.
+	parent_data := testData(32*512, 5)
+	descriptor := `# Disk DescriptorFile
+version=1
+CID=12345678
+parentCID=ffffffff
+createType="twoGbMaxExtentFlat"
+
+# Extent description
+RW 8 FLAT "parent-f001.vmdk" 0
+RW 8 ZERO
+RW 16 SPARSE "parent-s002.vmdk"
+`
+	err = ioutil.WriteFile(filepath.Join(tmpdir, "parent.vmdk"),
+		[]byte(descriptor), 0644)
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(tmpdir, "parent-f001.vmdk"),
+		parent_data[:8*512], 0644)
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(tmpdir, "parent-s002.vmdk"),
+		buildVMDKSparse(16, map[int][]byte{0: parent_data[16*512 : 24*512]},
+			"", false), 0644)
+	assert.NoError(t, err)
+
+	expected := append([]byte{}, parent_data...)
+	zero(expected[8*512 : 16*512])
+	zero(expected[24*512:])
+
+	data := readImage(t, "vmdk", filepath.Join(tmpdir, "parent.vmdk"))
+	assert.Equal(t, expected, data)
+
+	// A monolithic snapshot with an embedded descriptor and
+	// compressed grains.
+	child_data := testData(32*512, 50)
+	descriptor = `# Disk DescriptorFile
+version=1
+CID=87654321
+parentCID=12345678
+createType="monolithicSparse"
+parentFileNameHint="C:\VMs\parent.vmdk"
+
+RW 32 SPARSE "child.vmdk"
+`
+	err = ioutil.WriteFile(filepath.Join(tmpdir, "child.vmdk"),
+		buildVMDKSparse(32, map[int][]byte{1: child_data[8*512 : 16*512]},
+			descriptor, true), 0644)
+	assert.NoError(t, err)
+
+	copy(expected[8*512:16*512], child_data[8*512:])
+
+	data = readImage(t, "vmdk", filepath.Join(tmpdir, "child.vmdk"))
+	assert.Equal(t, expected, data)
+}
@@ -0,0 +1,84 @@
+package images
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+)
+
+// Read exactly len(buf) bytes at offset.
+func readAt(reader io.ReaderAt, buf []byte, offset int64) error {
+	n, err := reader.ReadAt(buf, offset)
+	if n == len(buf) {
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func readBytes(reader io.ReaderAt, offset int64, length int) ([]byte, error) {
+	buf := make([]byte, length)
+	err := readAt(reader, buf, offset)
+	return buf, err
+}
+
+// Zero fills the buffer.
+func zero(buf []byte) {
+	for i := range buf {
+		buf[i] = 0
+	}
+}
+
+// Decompress a zlib stream, reading at most limit bytes from the
+// reader.
+func inflate(reader io.ReaderAt, offset, limit int64, out []byte) error {
+	zr, err := zlib.NewReader(io.NewSectionReader(reader, offset, limit))
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	_, err = io.ReadFull(zr, out)
+	return err
+}
+
+// Decode a UTF16 string with the specified byte order, stopping at
+// the first NUL character.
+func decodeUTF16(data []byte, order binary.ByteOrder) string {
+	ints := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		c := order.Uint16(data[i:])
+		if c == 0 {
+			break
+		}
+		ints = append(ints, c)
+	}
+	return string(utf16.Decode(ints))
+}
+
+// Some text sections may be either UTF16 or plain ASCII
+func decodeText(data []byte) string {
+	if bytes.HasPrefix(data, []byte{0xff, 0xfe}) {
+		return decodeUTF16(data[2:], binary.LittleEndian)
+	}
+
+	if len(data) > 1 && data[1] == 0 {
+		return decodeUTF16(data, binary.LittleEndian)
+	}
+
+	return string(bytes.TrimRight(data, "\x00"))
+}
+
+// Windows GUIDs are stored in mixed endian format.
+func guidBytes(data1 uint32, data2, data3 uint16, data4 [8]byte) []byte {
+	result := make([]byte, 16)
+	binary.LittleEndian.PutUint32(result, data1)
+	binary.LittleEndian.PutUint16(result[4:], data2)
+	binary.LittleEndian.PutUint16(result[6:], data3)
+	copy(result[8:], data4[:])
+	return result
+}
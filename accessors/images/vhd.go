@@ -0,0 +1,333 @@
+package images
+
+// Support for the Microsoft Virtual Hard Disk (VHD) format.
+//
+// Fixed disks are simply the raw disk followed by a footer. Dynamic
+// and differencing disks store the data in blocks located through a
+// Block Allocation Table (BAT). Each block starts with a sector
+// bitmap which indicates which sectors are present in this file -
+// for differencing disks the remaining sectors are read from the
+// parent.
+//
+// Reference: Virtual Hard Disk Image Format Specification
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+)
+
+var (
+	vhdFooterCookie = []byte("conectix")
+	vhdHeaderCookie = []byte("cxsparse")
+)
+
+const (
+	vhdFooterSize    = 512
+	vhdHeaderSize    = 1024
+	vhdSectorSize    = 512
+	vhdUnallocated   = 0xffffffff
+	vhdMaxBlockSize  = 256 * 1024 * 1024
+	vhdMaxBATEntries = 1 << 28
+
+	vhdTypeFixed        = 2
+	vhdTypeDynamic      = 3
+	vhdTypeDifferencing = 4
+
+	// Parent locator platform codes
+	vhdPlatformW2ru = 0x57327275
+	vhdPlatformW2ku = 0x57326b75
+	vhdPlatformMacX = 0x4d616358
+)
+
+type VHDImage struct {
+	mu sync.Mutex
+
+	reader    io.ReaderAt
+	disk_type uint32
+	size      int64
+
+	// Dynamic and differencing disks
+	block_size  int64
+	bitmap_size int64
+	bat         []uint32
+	parent      Image
+
+	// The most recently used block bitmap.
+	bitmap_block int64
+	bitmap       []byte
+}
+
+func (self *VHDImage) Size() int64 {
+	return self.size
+}
+
+func (self *VHDImage) Close() error {
+	if self.parent != nil {
+		return self.parent.Close()
+	}
+	return nil
+}
+
+func (self *VHDImage) Info() *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("Format", "VHD").
+		Set("DiskType", vhdDiskTypeName(self.disk_type))
+
+	if self.disk_type != vhdTypeFixed {
+		result.Set("BlockSize", self.block_size)
+	}
+
+	if self.parent != nil {
+		result.Set("Parent", self.parent.Info())
+	}
+	return result
+}
+
+func vhdDiskTypeName(disk_type uint32) string {
+	switch disk_type {
+	case vhdTypeFixed:
+		return "Fixed"
+	case vhdTypeDynamic:
+		return "Dynamic"
+	case vhdTypeDifferencing:
+		return "Differencing"
+	}
+	return fmt.Sprintf("Unknown (%v)", disk_type)
+}
+
+func (self *VHDImage) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("VHD: invalid offset %v", offset)
+	}
+
+	if offset >= self.size {
+		return 0, io.EOF
+	}
+
+	to_read := buf
+	if offset+int64(len(to_read)) > self.size {
+		to_read = to_read[:self.size-offset]
+	}
+
+	if self.disk_type == vhdTypeFixed {
+		err := readAt(self.reader, to_read, offset)
+		if err != nil {
+			return 0, err
+		}
+
+	} else {
+		self.mu.Lock()
+		defer self.mu.Unlock()
+
+		n := 0
+		for n < len(to_read) {
+			current := offset + int64(n)
+			block := current / self.block_size
+			block_offset := current % self.block_size
+
+			// Read at most to the end of the block
+			length := self.block_size - block_offset
+			if length > int64(len(to_read)-n) {
+				length = int64(len(to_read) - n)
+			}
+
+			run, err := self.readRun(to_read[n:n+int(length)],
+				current, block, block_offset)
+			if err != nil {
+				return n, err
+			}
+			n += run
+		}
+	}
+
+	if len(to_read) < len(buf) {
+		return len(to_read), io.EOF
+	}
+	return len(to_read), nil
+}
+
+// Read a run of sectors within a block which are all either present
+// in this file or not. Returns the number of bytes read.
+func (self *VHDImage) readRun(buf []byte, offset, block, block_offset int64) (
+	int, error) {
+	length := int64(len(buf))
+
+	if block < int64(len(self.bat)) && self.bat[block] != vhdUnallocated {
+		sector_offset := int64(self.bat[block])
+		bitmap, err := self.getBitmap(block, sector_offset)
+		if err != nil {
+			return 0, err
+		}
+
+		// The bitmap is stored MSB first.
+		is_present := func(sector int64) bool {
+			return bitmap[sector/8]&(0x80>>uint(sector%8)) != 0
+		}
+
+		sector := block_offset / vhdSectorSize
+		present := is_present(sector)
+
+		end := sector + 1
+		for end*vhdSectorSize < block_offset+length && is_present(end) == present {
+			end++
+		}
+
+		if end*vhdSectorSize-block_offset < length {
+			length = end*vhdSectorSize - block_offset
+		}
+
+		if present {
+			return int(length), readAt(self.reader, buf[:length],
+				sector_offset*vhdSectorSize+self.bitmap_size+block_offset)
+		}
+	}
+
+	if self.parent != nil {
+		return int(length), readAt(self.parent, buf[:length], offset)
+	}
+
+	zero(buf[:length])
+	return int(length), nil
+}
+
+func (self *VHDImage) getBitmap(block, sector_offset int64) ([]byte, error) {
+	if self.bitmap != nil && self.bitmap_block == block {
+		return self.bitmap, nil
+	}
+
+	bitmap, err := readBytes(self.reader,
+		sector_offset*vhdSectorSize, int(self.bitmap_size))
+	if err != nil {
+		return nil, err
+	}
+
+	self.bitmap_block = block
+	self.bitmap = bitmap
+	return bitmap, nil
+}
+
+func (self *VHDImage) parseDynamicHeader(
+	opener *segmentOpener, filename *accessors.OSPath,
+	offset int64, depth int) error {
+	header, err := readBytes(self.reader, offset, vhdHeaderSize)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(header[:8], vhdHeaderCookie) {
+		return fmt.Errorf("VHD: invalid dynamic disk header")
+	}
+
+	table_offset := int64(binary.BigEndian.Uint64(header[16:]))
+	entries := int64(binary.BigEndian.Uint32(header[28:]))
+	self.block_size = int64(binary.BigEndian.Uint32(header[32:]))
+
+	if self.block_size < vhdSectorSize || self.block_size > vhdMaxBlockSize ||
+		self.block_size%vhdSectorSize != 0 {
+		return fmt.Errorf("VHD: invalid block size %v", self.block_size)
+	}
+
+	if entries > vhdMaxBATEntries ||
+		entries*self.block_size < self.size {
+		return fmt.Errorf("VHD: invalid BAT size %v", entries)
+	}
+
+	// One bit per sector rounded up to a sector.
+	self.bitmap_size = (self.block_size/vhdSectorSize/8 + vhdSectorSize - 1) /
+		vhdSectorSize * vhdSectorSize
+
+	bat, err := readBytes(self.reader, table_offset, int(entries*4))
+	if err != nil {
+		return err
+	}
+
+	self.bat = make([]uint32, entries)
+	for i := range self.bat {
+		self.bat[i] = binary.BigEndian.Uint32(bat[i*4:])
+	}
+
+	if self.disk_type != vhdTypeDifferencing {
+		return nil
+	}
+
+	// Collect the possible parent names from the header.
+	candidates := []string{decodeUTF16(header[64:576], binary.BigEndian)}
+	for i := 0; i < 8; i++ {
+		entry := header[576+i*24:]
+		code := binary.BigEndian.Uint32(entry)
+		length := int(binary.BigEndian.Uint32(entry[8:]))
+		data_offset := int64(binary.BigEndian.Uint64(entry[16:]))
+
+		if length <= 0 || length > 4096 {
+			continue
+		}
+
+		data, err := readBytes(self.reader, data_offset, length)
+		if err != nil {
+			continue
+		}
+
+		switch code {
+		case vhdPlatformW2ru, vhdPlatformW2ku:
+			candidates = append(candidates,
+				decodeUTF16(data, binary.LittleEndian))
+		case vhdPlatformMacX:
+			candidates = append(candidates, string(data))
+		}
+	}
+
+	self.parent, err = opener.OpenParent(filename, depth, OpenVHD, candidates...)
+	return err
+}
+
+func OpenVHD(opener *segmentOpener, reader io.ReaderAt, size int64,
+	filename *accessors.OSPath, depth int) (Image, error) {
+	if size < vhdFooterSize {
+		return nil, NotSupportedError
+	}
+
+	footer, err := readBytes(reader, size-vhdFooterSize, vhdFooterSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Some old implementations write a 511 byte footer.
+	if !bytes.Equal(footer[:8], vhdFooterCookie) {
+		footer, err = readBytes(reader, size-vhdFooterSize+1, vhdFooterSize-1)
+		if err != nil || !bytes.Equal(footer[:8], vhdFooterCookie) {
+			return nil, NotSupportedError
+		}
+	}
+
+	result := &VHDImage{
+		reader:       reader,
+		disk_type:    binary.BigEndian.Uint32(footer[60:]),
+		size:         int64(binary.BigEndian.Uint64(footer[48:])),
+		bitmap_block: -1,
+	}
+
+	switch result.disk_type {
+	case vhdTypeFixed:
+		if result.size > size {
+			return nil, fmt.Errorf("VHD: disk size %v larger than file",
+				result.size)
+		}
+		return result, nil
+
+	case vhdTypeDynamic, vhdTypeDifferencing:
+		data_offset := int64(binary.BigEndian.Uint64(footer[16:]))
+		err = result.parseDynamicHeader(opener, filename, data_offset, depth)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("VHD: unsupported disk type %v", result.disk_type)
+}
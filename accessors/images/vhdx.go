@@ -0,0 +1,447 @@
+package images
+
+// Support for the VHDX format used by Hyper-V.
+//
+// The file is made of regions located through a region table. The
+// metadata region describes the geometry of the disk and the BAT
+// region maps payload blocks to file offsets. Differencing disks
+// also have sector bitmap blocks which indicate which sectors are
+// present in this file.
+//
+// Reference: [MS-VHDX] Virtual Hard Disk v2 (VHDX) File Format
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+)
+
+var (
+	vhdxSignature         = []byte("vhdxfile")
+	vhdxRegionSignature   = []byte("regi")
+	vhdxMetadataSignature = []byte("metadata")
+
+	vhdxBATRegion = guidBytes(0x2DC27766, 0xF623, 0x4200,
+		[8]byte{0x9D, 0x64, 0x11, 0x5E, 0x9B, 0xFD, 0x4A, 0x08})
+	vhdxMetadataRegion = guidBytes(0x8B7CA206, 0x4790, 0x4B9A,
+		[8]byte{0xB8, 0xFE, 0x57, 0x5F, 0x05, 0x0F, 0x88, 0x6E})
+
+	vhdxFileParameters = guidBytes(0xCAA16737, 0xFA36, 0x4D43,
+		[8]byte{0xB3, 0xB6, 0x33, 0xF0, 0xAA, 0x44, 0xE7, 0x6B})
+	vhdxVirtualDiskSize = guidBytes(0x2FA54224, 0xCD1B, 0x4876,
+		[8]byte{0xB2, 0x11, 0x5D, 0xBE, 0xD8, 0x3B, 0xF4, 0xB8})
+	vhdxLogicalSectorSize = guidBytes(0x8141BF1D, 0xA96F, 0x4709,
+		[8]byte{0xBA, 0x47, 0xF2, 0x33, 0xA8, 0xFA, 0xAB, 0x5F})
+	vhdxParentLocator = guidBytes(0xA8D35F2D, 0xB30B, 0x454D,
+		[8]byte{0xAB, 0xF7, 0xD3, 0xD8, 0x48, 0x34, 0xAB, 0x0C})
+)
+
+const (
+	vhdxRegionTableOffset = 192 * 1024
+	vhdxRegionTableSize   = 64 * 1024
+	vhdxMaxRegionEntries  = 2047
+	vhdxMaxMetadataItems  = 2047
+	vhdxMaxBlockSize      = 256 * 1024 * 1024
+	vhdxMaxBATEntries     = 1 << 28
+
+	// Payload block states
+	vhdxBlockNotPresent       = 0
+	vhdxBlockUndefined        = 1
+	vhdxBlockZero             = 2
+	vhdxBlockUnmapped         = 3
+	vhdxBlockFullyPresent     = 6
+	vhdxBlockPartiallyPresent = 7
+
+	// File parameter flags
+	vhdxHasParent = 0x02
+
+	// Each sector bitmap block covers this many sectors.
+	vhdxSectorsPerBitmap = 1 << 23
+)
+
+type VHDXImage struct {
+	mu sync.Mutex
+
+	reader io.ReaderAt
+	size   int64
+
+	block_size  int64
+	sector_size int64
+	chunk_ratio int64
+	bat         []uint64
+	parent      Image
+
+	// The most recently used sector bitmap.
+	bitmap_chunk int64
+	bitmap       []byte
+}
+
+func (self *VHDXImage) Size() int64 {
+	return self.size
+}
+
+func (self *VHDXImage) Close() error {
+	if self.parent != nil {
+		return self.parent.Close()
+	}
+	return nil
+}
+
+func (self *VHDXImage) Info() *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("Format", "VHDX").
+		Set("BlockSize", self.block_size).
+		Set("LogicalSectorSize", self.sector_size)
+
+	if self.parent != nil {
+		result.Set("Parent", self.parent.Info())
+	}
+	return result
+}
+
+func (self *VHDXImage) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("VHDX: invalid offset %v", offset)
+	}
+
+	if offset >= self.size {
+		return 0, io.EOF
+	}
+
+	to_read := buf
+	if offset+int64(len(to_read)) > self.size {
+		to_read = to_read[:self.size-offset]
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	n := 0
+	for n < len(to_read) {
+		current := offset + int64(n)
+		block := current / self.block_size
+		block_offset := current % self.block_size
+
+		// Read at most to the end of the block
+		length := self.block_size - block_offset
+		if length > int64(len(to_read)-n) {
+			length = int64(len(to_read) - n)
+		}
+
+		run, err := self.readRun(to_read[n:n+int(length)],
+			current, block, block_offset)
+		if err != nil {
+			return n, err
+		}
+		n += run
+	}
+
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Payload BAT entries are interleaved with sector bitmap entries:
+// every chunk_ratio payload entries are followed by one sector bitmap
+// entry.
+func (self *VHDXImage) payloadEntry(block int64) uint64 {
+	idx := block + block/self.chunk_ratio
+	if idx >= int64(len(self.bat)) {
+		return vhdxBlockNotPresent
+	}
+	return self.bat[idx]
+}
+
+func (self *VHDXImage) bitmapEntry(chunk int64) uint64 {
+	idx := chunk*(self.chunk_ratio+1) + self.chunk_ratio
+	if idx >= int64(len(self.bat)) {
+		return vhdxBlockNotPresent
+	}
+	return self.bat[idx]
+}
+
+// Read a run of data within a block with the same state. Returns the
+// number of bytes read.
+func (self *VHDXImage) readRun(buf []byte, offset, block, block_offset int64) (
+	int, error) {
+	length := int64(len(buf))
+	entry := self.payloadEntry(block)
+	file_offset := int64(entry>>20) << 20
+
+	switch entry & 7 {
+	case vhdxBlockFullyPresent:
+		return int(length), readAt(self.reader, buf, file_offset+block_offset)
+
+	case vhdxBlockPartiallyPresent:
+		if self.parent == nil {
+			return 0, fmt.Errorf("VHDX: partially present block without parent")
+		}
+
+		chunk := block / self.chunk_ratio
+		bitmap, err := self.getBitmap(chunk)
+		if err != nil {
+			return 0, err
+		}
+
+		// The bitmap is stored LSB first and covers all the sectors
+		// in the chunk.
+		first_sector := (block % self.chunk_ratio) * self.block_size / self.sector_size
+		is_present := func(sector int64) bool {
+			sector += first_sector
+			return bitmap[sector/8]&(1<<uint(sector%8)) != 0
+		}
+
+		sector := block_offset / self.sector_size
+		present := is_present(sector)
+
+		end := sector + 1
+		for end*self.sector_size < block_offset+length && is_present(end) == present {
+			end++
+		}
+
+		if end*self.sector_size-block_offset < length {
+			length = end*self.sector_size - block_offset
+		}
+
+		if present {
+			return int(length), readAt(self.reader, buf[:length],
+				file_offset+block_offset)
+		}
+		return int(length), readAt(self.parent, buf[:length], offset)
+
+	case vhdxBlockNotPresent, vhdxBlockUndefined:
+		// Differencing disks defer to the parent.
+		if self.parent != nil {
+			return int(length), readAt(self.parent, buf, offset)
+		}
+	}
+
+	zero(buf)
+	return int(length), nil
+}
+
+func (self *VHDXImage) getBitmap(chunk int64) ([]byte, error) {
+	if self.bitmap != nil && self.bitmap_chunk == chunk {
+		return self.bitmap, nil
+	}
+
+	entry := self.bitmapEntry(chunk)
+	if entry&7 != vhdxBlockFullyPresent {
+		return nil, fmt.Errorf("VHDX: sector bitmap for chunk %v missing", chunk)
+	}
+
+	bitmap, err := readBytes(self.reader, int64(entry>>20)<<20,
+		vhdxSectorsPerBitmap/8)
+	if err != nil {
+		return nil, err
+	}
+
+	self.bitmap_chunk = chunk
+	self.bitmap = bitmap
+	return bitmap, nil
+}
+
+type vhdxRegion struct {
+	offset int64
+	length int64
+}
+
+func parseVHDXRegions(reader io.ReaderAt) (map[string]vhdxRegion, error) {
+	// There are two copies of the region table. Use the first one
+	// with a valid signature.
+	var table []byte
+	for _, offset := range []int64{
+		vhdxRegionTableOffset, vhdxRegionTableOffset + vhdxRegionTableSize} {
+		data, err := readBytes(reader, offset, vhdxRegionTableSize)
+		if err == nil && bytes.Equal(data[:4], vhdxRegionSignature) {
+			table = data
+			break
+		}
+	}
+
+	if table == nil {
+		return nil, fmt.Errorf("VHDX: no valid region table")
+	}
+
+	count := int(binary.LittleEndian.Uint32(table[8:]))
+	if count > vhdxMaxRegionEntries {
+		return nil, fmt.Errorf("VHDX: too many regions %v", count)
+	}
+
+	result := make(map[string]vhdxRegion)
+	for i := 0; i < count; i++ {
+		entry := table[16+i*32:]
+		result[string(entry[:16])] = vhdxRegion{
+			offset: int64(binary.LittleEndian.Uint64(entry[16:])),
+			length: int64(binary.LittleEndian.Uint32(entry[24:])),
+		}
+	}
+
+	return result, nil
+}
+
+// Returns a map of metadata items keyed by item GUID.
+func parseVHDXMetadata(reader io.ReaderAt, region vhdxRegion) (
+	map[string][]byte, error) {
+	header, err := readBytes(reader, region.offset, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(header[:8], vhdxMetadataSignature) {
+		return nil, fmt.Errorf("VHDX: invalid metadata region")
+	}
+
+	count := int(binary.LittleEndian.Uint16(header[10:]))
+	if count > vhdxMaxMetadataItems {
+		return nil, fmt.Errorf("VHDX: too many metadata items %v", count)
+	}
+
+	entries, err := readBytes(reader, region.offset+32, count*32)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]byte)
+	for i := 0; i < count; i++ {
+		entry := entries[i*32:]
+		offset := int64(binary.LittleEndian.Uint32(entry[16:]))
+		length := int64(binary.LittleEndian.Uint32(entry[20:]))
+		if offset+length > region.length {
+			continue
+		}
+
+		data, err := readBytes(reader, region.offset+offset, int(length))
+		if err != nil {
+			return nil, err
+		}
+		result[string(entry[:16])] = data
+	}
+
+	return result, nil
+}
+
+// The parent locator is a set of UTF16 key value pairs.
+func parseVHDXParentLocator(data []byte) map[string]string {
+	result := make(map[string]string)
+	if len(data) < 20 {
+		return result
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[18:]))
+	for i := 0; i < count; i++ {
+		if 20+i*12+12 > len(data) {
+			break
+		}
+		entry := data[20+i*12:]
+		key_offset := int(binary.LittleEndian.Uint32(entry))
+		value_offset := int(binary.LittleEndian.Uint32(entry[4:]))
+		key_length := int(binary.LittleEndian.Uint16(entry[8:]))
+		value_length := int(binary.LittleEndian.Uint16(entry[10:]))
+
+		if key_offset+key_length > len(data) ||
+			value_offset+value_length > len(data) {
+			continue
+		}
+
+		key := decodeUTF16(data[key_offset:key_offset+key_length],
+			binary.LittleEndian)
+		value := decodeUTF16(data[value_offset:value_offset+value_length],
+			binary.LittleEndian)
+		result[key] = value
+	}
+
+	return result
+}
+
+func OpenVHDX(opener *segmentOpener, reader io.ReaderAt, size int64,
+	filename *accessors.OSPath, depth int) (Image, error) {
+	signature, err := readBytes(reader, 0, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(signature, vhdxSignature) {
+		return nil, NotSupportedError
+	}
+
+	regions, err := parseVHDXRegions(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata_region, pres := regions[string(vhdxMetadataRegion)]
+	if !pres {
+		return nil, fmt.Errorf("VHDX: no metadata region")
+	}
+
+	bat_region, pres := regions[string(vhdxBATRegion)]
+	if !pres {
+		return nil, fmt.Errorf("VHDX: no BAT region")
+	}
+
+	metadata, err := parseVHDXMetadata(reader, metadata_region)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := metadata[string(vhdxFileParameters)]
+	disk_size := metadata[string(vhdxVirtualDiskSize)]
+	sector_size := metadata[string(vhdxLogicalSectorSize)]
+	if len(parameters) < 8 || len(disk_size) < 8 || len(sector_size) < 4 {
+		return nil, fmt.Errorf("VHDX: required metadata missing")
+	}
+
+	result := &VHDXImage{
+		reader:       reader,
+		size:         int64(binary.LittleEndian.Uint64(disk_size)),
+		block_size:   int64(binary.LittleEndian.Uint32(parameters)),
+		sector_size:  int64(binary.LittleEndian.Uint32(sector_size)),
+		bitmap_chunk: -1,
+	}
+
+	if result.block_size <= 0 || result.block_size > vhdxMaxBlockSize ||
+		(result.sector_size != 512 && result.sector_size != 4096) ||
+		result.block_size%result.sector_size != 0 {
+		return nil, fmt.Errorf("VHDX: invalid geometry")
+	}
+
+	result.chunk_ratio = vhdxSectorsPerBitmap * result.sector_size /
+		result.block_size
+
+	entries := bat_region.length / 8
+	if entries > vhdxMaxBATEntries {
+		return nil, fmt.Errorf("VHDX: BAT too large")
+	}
+
+	bat, err := readBytes(reader, bat_region.offset, int(entries*8))
+	if err != nil {
+		return nil, err
+	}
+
+	result.bat = make([]uint64, entries)
+	for i := range result.bat {
+		result.bat[i] = binary.LittleEndian.Uint64(bat[i*8:])
+	}
+
+	flags := binary.LittleEndian.Uint32(parameters[4:])
+	if flags&vhdxHasParent == 0 {
+		return result, nil
+	}
+
+	locator := parseVHDXParentLocator(metadata[string(vhdxParentLocator)])
+	result.parent, err = opener.OpenParent(filename, depth, OpenVHDX,
+		locator["relative_path"], locator["absolute_win32_path"],
+		locator["volume_path"])
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
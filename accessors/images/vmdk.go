@@ -0,0 +1,507 @@
+package images
+
+// Support for the VMware Virtual Disk (VMDK) format.
+//
+// A VMDK disk is described by a text descriptor which lists the
+// extents making up the disk. The descriptor may be a separate file
+// or embedded in a sparse extent. Extents are either flat (raw data)
+// or sparse (hosted sparse extents with a grain directory). Snapshots
+// are stored as child disks which refer to their parent with the
+// parentFileNameHint descriptor field.
+//
+// Reference: VMware Virtual Disk Format 1.1
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+)
+
+var (
+	vmdkSparseMagic = []byte("KDMV")
+
+	vmdkExtentRegex = regexp.MustCompile(
+		`^(RW|RDONLY|NOACCESS)\s+(\d+)\s+(\w+)(?:\s+"([^"]*)")?(?:\s+(\d+))?`)
+	vmdkKeyRegex = regexp.MustCompile(`^(\w+)\s*=\s*"?([^"]*)"?`)
+)
+
+const (
+	vmdkSectorSize        = 512
+	vmdkHeaderSize        = 79
+	vmdkMaxDescriptorSize = 1024 * 1024
+	vmdkMaxGrainSize      = 128 * 1024 * 1024
+	vmdkMaxGDEntries      = 1 << 24
+	vmdkGDAtEnd           = 0xffffffffffffffff
+
+	// Sparse extent flags
+	vmdkFlagZeroGrain  = 1 << 2
+	vmdkFlagCompressed = 1 << 16
+)
+
+const (
+	vmdkExtentFlat = iota
+	vmdkExtentSparse
+	vmdkExtentZero
+)
+
+type vmdkSparse struct {
+	reader io.ReaderAt
+
+	grain_size  int64
+	gtes_per_gt int64
+	gd          []uint32
+	compressed  bool
+	zero_grain  bool
+
+	// The most recently used grain table.
+	gt_idx int64
+	gt     []uint32
+
+	// The most recently decompressed grain
+	grain_idx  int64
+	grain_data []byte
+}
+
+// Returns the sector offset of the grain or 0 if the grain is not
+// allocated. A zero grain is reported as 1.
+func (self *vmdkSparse) grainOffset(grain int64) (int64, error) {
+	gd_idx := grain / self.gtes_per_gt
+	if gd_idx >= int64(len(self.gd)) || self.gd[gd_idx] == 0 {
+		return 0, nil
+	}
+
+	if self.gt == nil || self.gt_idx != gd_idx {
+		data, err := readBytes(self.reader,
+			int64(self.gd[gd_idx])*vmdkSectorSize, int(self.gtes_per_gt*4))
+		if err != nil {
+			return 0, err
+		}
+
+		gt := make([]uint32, self.gtes_per_gt)
+		for i := range gt {
+			gt[i] = binary.LittleEndian.Uint32(data[i*4:])
+		}
+		self.gt = gt
+		self.gt_idx = gd_idx
+	}
+
+	return int64(self.gt[grain%self.gtes_per_gt]), nil
+}
+
+func (self *vmdkSparse) readCompressedGrain(grain, sector int64) ([]byte, error) {
+	if self.grain_data != nil && self.grain_idx == grain {
+		return self.grain_data, nil
+	}
+
+	// The grain marker is the LBA followed by the compressed size.
+	marker, err := readBytes(self.reader, sector*vmdkSectorSize, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	size := int64(binary.LittleEndian.Uint32(marker[8:]))
+	data := make([]byte, self.grain_size)
+	err = inflate(self.reader, sector*vmdkSectorSize+12, size, data)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("VMDK: grain %v: %w", grain, err)
+	}
+
+	self.grain_idx = grain
+	self.grain_data = data
+	return data, nil
+}
+
+// Read a run within a single grain. Returns false if the grain is
+// not allocated.
+func (self *vmdkSparse) readGrain(buf []byte, offset int64) (bool, error) {
+	grain := offset / self.grain_size
+	grain_offset := offset % self.grain_size
+
+	sector, err := self.grainOffset(grain)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case sector == 0:
+		return false, nil
+
+	case sector == 1 && self.zero_grain:
+		zero(buf)
+		return true, nil
+
+	case self.compressed:
+		data, err := self.readCompressedGrain(grain, sector)
+		if err != nil {
+			return false, err
+		}
+		copy(buf, data[grain_offset:])
+		return true, nil
+	}
+
+	return true, readAt(self.reader, buf, sector*vmdkSectorSize+grain_offset)
+}
+
+func parseVMDKSparseHeader(reader io.ReaderAt, size int64) (
+	*vmdkSparse, []byte, error) {
+	header, err := readBytes(reader, 0, vmdkHeaderSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !bytes.Equal(header[:4], vmdkSparseMagic) {
+		return nil, nil, NotSupportedError
+	}
+
+	// Stream optimized images store the grain directory location in
+	// a footer.
+	if binary.LittleEndian.Uint64(header[56:]) == vmdkGDAtEnd {
+		header, err = readBytes(reader, size-2*vmdkSectorSize, vmdkHeaderSize)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !bytes.Equal(header[:4], vmdkSparseMagic) {
+			return nil, nil, fmt.Errorf("VMDK: invalid footer")
+		}
+	}
+
+	flags := binary.LittleEndian.Uint32(header[8:])
+	capacity := int64(binary.LittleEndian.Uint64(header[12:]))
+	grain_size := int64(binary.LittleEndian.Uint64(header[20:])) * vmdkSectorSize
+	descriptor_offset := int64(binary.LittleEndian.Uint64(header[28:]))
+	descriptor_size := int64(binary.LittleEndian.Uint64(header[36:]))
+	gtes_per_gt := int64(binary.LittleEndian.Uint32(header[44:]))
+	gd_offset := int64(binary.LittleEndian.Uint64(header[56:]))
+
+	if grain_size <= 0 || grain_size > vmdkMaxGrainSize ||
+		gtes_per_gt <= 0 || gtes_per_gt > 1<<20 {
+		return nil, nil, fmt.Errorf("VMDK: invalid sparse header")
+	}
+
+	grain_table_coverage := grain_size * gtes_per_gt
+	gd_entries := (capacity*vmdkSectorSize + grain_table_coverage - 1) /
+		grain_table_coverage
+	if gd_entries > vmdkMaxGDEntries {
+		return nil, nil, fmt.Errorf("VMDK: grain directory too large")
+	}
+
+	gd_data, err := readBytes(reader, gd_offset*vmdkSectorSize, int(gd_entries*4))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &vmdkSparse{
+		reader:      reader,
+		grain_size:  grain_size,
+		gtes_per_gt: gtes_per_gt,
+		gd:          make([]uint32, gd_entries),
+		compressed:  flags&vmdkFlagCompressed != 0,
+		zero_grain:  flags&vmdkFlagZeroGrain != 0,
+		gt_idx:      -1,
+		grain_idx:   -1,
+	}
+
+	for i := range result.gd {
+		result.gd[i] = binary.LittleEndian.Uint32(gd_data[i*4:])
+	}
+
+	var descriptor []byte
+	if descriptor_offset > 0 && descriptor_size > 0 &&
+		descriptor_size*vmdkSectorSize <= vmdkMaxDescriptorSize {
+		descriptor, err = readBytes(reader, descriptor_offset*vmdkSectorSize,
+			int(descriptor_size*vmdkSectorSize))
+		if err != nil {
+			return nil, nil, err
+		}
+		descriptor = bytes.TrimRight(descriptor, "\x00")
+	}
+
+	// Sparse extents without a descriptor are treated as a single
+	// extent disk.
+	if len(descriptor) == 0 {
+		descriptor = []byte(fmt.Sprintf("RW %d SPARSE \"\"\n", capacity))
+	}
+
+	return result, descriptor, nil
+}
+
+type vmdkExtent struct {
+	// Offset and size in the virtual disk.
+	start int64
+	size  int64
+
+	extent_type int
+
+	// Flat extents
+	reader io.ReaderAt
+	offset int64
+
+	// Sparse extents
+	sparse *vmdkSparse
+}
+
+type vmdkDescriptor struct {
+	values  map[string]string
+	extents []vmdkExtentLine
+}
+
+type vmdkExtentLine struct {
+	sectors     int64
+	extent_type string
+	filename    string
+	offset      int64
+}
+
+func parseVMDKDescriptor(text string) *vmdkDescriptor {
+	result := &vmdkDescriptor{values: make(map[string]string)}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		match := vmdkExtentRegex.FindStringSubmatch(line)
+		if match != nil {
+			sectors, _ := strconv.ParseInt(match[2], 0, 64)
+			offset, _ := strconv.ParseInt(match[5], 0, 64)
+			result.extents = append(result.extents, vmdkExtentLine{
+				sectors:     sectors,
+				extent_type: match[3],
+				filename:    match[4],
+				offset:      offset,
+			})
+			continue
+		}
+
+		match = vmdkKeyRegex.FindStringSubmatch(line)
+		if match != nil {
+			result.values[match[1]] = strings.TrimSpace(match[2])
+		}
+	}
+
+	return result
+}
+
+type VMDKImage struct {
+	mu sync.Mutex
+
+	extents     []*vmdkExtent
+	size        int64
+	create_type string
+	parent      Image
+}
+
+func (self *VMDKImage) Size() int64 {
+	return self.size
+}
+
+func (self *VMDKImage) Close() error {
+	if self.parent != nil {
+		return self.parent.Close()
+	}
+	return nil
+}
+
+func (self *VMDKImage) Info() *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("Format", "VMDK").
+		Set("CreateType", self.create_type).
+		Set("Extents", len(self.extents))
+
+	if self.parent != nil {
+		result.Set("Parent", self.parent.Info())
+	}
+	return result
+}
+
+func (self *VMDKImage) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("VMDK: invalid offset %v", offset)
+	}
+
+	if offset >= self.size {
+		return 0, io.EOF
+	}
+
+	to_read := buf
+	if offset+int64(len(to_read)) > self.size {
+		to_read = to_read[:self.size-offset]
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	n := 0
+	for n < len(to_read) {
+		current := offset + int64(n)
+
+		// Find the extent containing the offset.
+		idx := sort.Search(len(self.extents), func(i int) bool {
+			return self.extents[i].start+self.extents[i].size > current
+		})
+		if idx >= len(self.extents) {
+			return n, io.EOF
+		}
+
+		extent := self.extents[idx]
+		extent_offset := current - extent.start
+		length := extent.size - extent_offset
+		if length > int64(len(to_read)-n) {
+			length = int64(len(to_read) - n)
+		}
+
+		if extent.extent_type == vmdkExtentSparse {
+			// Read at most to the end of the grain.
+			grain_size := extent.sparse.grain_size
+			if grain_size-extent_offset%grain_size < length {
+				length = grain_size - extent_offset%grain_size
+			}
+		}
+
+		err := self.readExtent(extent, to_read[n:n+int(length)],
+			current, extent_offset)
+		if err != nil {
+			return n, err
+		}
+		n += int(length)
+	}
+
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (self *VMDKImage) readExtent(extent *vmdkExtent,
+	buf []byte, offset, extent_offset int64) error {
+	switch extent.extent_type {
+	case vmdkExtentFlat:
+		return readAt(extent.reader, buf, extent.offset+extent_offset)
+
+	case vmdkExtentSparse:
+		allocated, err := extent.sparse.readGrain(buf, extent_offset)
+		if err != nil || allocated {
+			return err
+		}
+	}
+
+	// Unallocated areas come from the parent
+	if self.parent != nil {
+		return readAt(self.parent, buf, offset)
+	}
+
+	zero(buf)
+	return nil
+}
+
+func OpenVMDK(opener *segmentOpener, reader io.ReaderAt, size int64,
+	filename *accessors.OSPath, depth int) (Image, error) {
+	magic, err := readBytes(reader, 0, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	var descriptor_text []byte
+	var sparse *vmdkSparse
+
+	if bytes.Equal(magic, vmdkSparseMagic) {
+		sparse, descriptor_text, err = parseVMDKSparseHeader(reader, size)
+		if err != nil {
+			return nil, err
+		}
+
+	} else {
+		if size > vmdkMaxDescriptorSize {
+			return nil, NotSupportedError
+		}
+
+		descriptor_text, err = readBytes(reader, 0, int(size))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	descriptor := parseVMDKDescriptor(string(descriptor_text))
+	if len(descriptor.extents) == 0 {
+		return nil, NotSupportedError
+	}
+
+	result := &VMDKImage{
+		create_type: descriptor.values["createType"],
+	}
+
+	for _, line := range descriptor.extents {
+		extent := &vmdkExtent{
+			start: result.size,
+			size:  line.sectors * vmdkSectorSize,
+		}
+		result.size += extent.size
+
+		switch line.extent_type {
+		case "ZERO":
+			extent.extent_type = vmdkExtentZero
+
+		case "FLAT", "VMFS":
+			extent_reader, _, _, err := opener.OpenSibling(filename, line.filename)
+			if err != nil {
+				return nil, fmt.Errorf("VMDK: unable to open extent %v: %w",
+					line.filename, err)
+			}
+			extent.extent_type = vmdkExtentFlat
+			extent.reader = extent_reader
+			extent.offset = line.offset * vmdkSectorSize
+
+		case "SPARSE":
+			extent.extent_type = vmdkExtentSparse
+
+			// The descriptor is embedded in this extent.
+			if sparse != nil && (line.filename == "" ||
+				line.filename == filename.Basename()) {
+				extent.sparse = sparse
+				break
+			}
+
+			extent_reader, extent_size, _, err := opener.OpenSibling(
+				filename, line.filename)
+			if err != nil {
+				return nil, fmt.Errorf("VMDK: unable to open extent %v: %w",
+					line.filename, err)
+			}
+
+			extent.sparse, _, err = parseVMDKSparseHeader(
+				extent_reader, extent_size)
+			if err != nil {
+				return nil, fmt.Errorf("VMDK: extent %v: %w", line.filename, err)
+			}
+
+		default:
+			return nil, fmt.Errorf("VMDK: unsupported extent type %v",
+				line.extent_type)
+		}
+
+		result.extents = append(result.extents, extent)
+	}
+
+	parent_cid := strings.ToLower(descriptor.values["parentCID"])
+	parent_hint := descriptor.values["parentFileNameHint"]
+	if parent_hint != "" && parent_cid != "ffffffff" {
+		result.parent, err = opener.OpenParent(
+			filename, depth, OpenVMDK, parent_hint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
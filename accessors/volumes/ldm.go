@@ -0,0 +1,667 @@
+package volumes
+
+// Reconstruct Windows Dynamic Disk (Logical Disk Manager) volumes.
+//
+// Each dynamic disk carries a copy of the LDM database which
+// describes all the volumes in the disk group. The database is a
+// list of VBLK records describing disks, partitions (extents on a
+// disk), components (the way partitions are combined) and volumes.
+//
+// The layout follows the Linux kernel's block/partitions/ldm.c.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+const (
+	ldmSectorSize = 512
+
+	// Location of the PRIVHEAD on MBR disks.
+	ldmPrivHeadSector = 6
+
+	// The VMDB is located this many sectors into the config area.
+	ldmVMDBSector = 17
+
+	ldmMaxConfigSize = 16 * 1024 * 1024
+	ldmVblkHeadSize  = 16
+
+	ldmTypeComponent = 0x32
+	ldmTypePartition = 0x33
+	ldmTypeDisk3     = 0x34
+	ldmTypeDisk4     = 0x44
+	ldmTypeVolume    = 0x51
+
+	ldmFlagPartIndex   = 0x08
+	ldmFlagCompStripe  = 0x10
+	ldmFlagVolumeID1   = 0x08
+	ldmFlagVolumeID2   = 0x20
+	ldmFlagVolumeSize  = 0x80
+	ldmFlagVolumeDrive = 0x02
+
+	ldmComponentStriped = 1
+	ldmComponentSpanned = 2
+	ldmComponentRAID    = 3
+)
+
+// GUID of the LDM metadata partition on GPT disks
+// (5808C8AA-7E8F-42E0-85D2-E1E90434CFB3) in on disk order.
+var ldmMetadataPartitionGUID = []byte{
+	0xaa, 0xc8, 0x08, 0x58, 0x8f, 0x7e, 0xe0, 0x42,
+	0x85, 0xd2, 0xe1, 0xe9, 0x04, 0x34, 0xcf, 0xb3}
+
+type ldmPrivHead struct {
+	device       *Device
+	disk_id      string
+	disk_start   int64
+	config_start int64
+	config_size  int64
+}
+
+type ldmPartition struct {
+	obj_id        uint64
+	name          string
+	start         int64
+	volume_offset int64
+	size          int64
+	parent_id     uint64
+	disk_id       uint64
+	index         int
+}
+
+type ldmComponent struct {
+	obj_id     uint64
+	name       string
+	comp_type  byte
+	children   uint64
+	parent_id  uint64
+	chunk_size int64
+	partitions []*ldmPartition
+}
+
+type ldmVolume struct {
+	obj_id      uint64
+	name        string
+	volume_type string
+	size        int64
+	drive_hint  string
+	components  []*ldmComponent
+}
+
+type ldmDatabase struct {
+	seq        uint32
+	volumes    []*ldmVolume
+	components map[uint64]*ldmComponent
+	partitions []*ldmPartition
+
+	// Map disk object ids to the disk GUID
+	disks map[uint64]string
+}
+
+func readLDMPrivHeadAt(device *Device, sector int64) (*ldmPrivHead, error) {
+	buf := make([]byte, ldmSectorSize)
+	n, _ := device.Reader.ReadAt(buf, sector*ldmSectorSize)
+	if n < len(buf) || string(buf[:8]) != "PRIVHEAD" {
+		return nil, fmt.Errorf("LDM: no PRIVHEAD found on %v",
+			device.Path.String())
+	}
+
+	return &ldmPrivHead{
+		device:  device,
+		disk_id: strings.ToLower(string(bytes.TrimRight(buf[0x30:0x30+36], "\x00"))),
+		disk_start: int64(binary.BigEndian.Uint64(buf[0x11B:])) *
+			ldmSectorSize,
+		config_start: int64(binary.BigEndian.Uint64(buf[0x12B:])),
+		config_size:  int64(binary.BigEndian.Uint64(buf[0x133:])),
+	}, nil
+}
+
+// On GPT disks the PRIVHEAD is in the last sector of the LDM metadata
+// partition.
+func findLDMGPTPrivHead(device *Device) (int64, error) {
+	header := make([]byte, ldmSectorSize)
+	n, _ := device.Reader.ReadAt(header, ldmSectorSize)
+	if n < len(header) || string(header[:8]) != "EFI PART" {
+		return 0, fmt.Errorf("LDM: no GPT header found")
+	}
+
+	entries_lba := int64(binary.LittleEndian.Uint64(header[0x48:]))
+	count := int64(binary.LittleEndian.Uint32(header[0x50:]))
+	entry_size := int64(binary.LittleEndian.Uint32(header[0x54:]))
+	if entry_size < 0x30 || count > 1024 {
+		return 0, fmt.Errorf("LDM: invalid GPT header")
+	}
+
+	entry := make([]byte, entry_size)
+	for i := int64(0); i < count; i++ {
+		n, _ := device.Reader.ReadAt(entry,
+			entries_lba*ldmSectorSize+i*entry_size)
+		if n < len(entry) {
+			break
+		}
+
+		if bytes.Equal(entry[:16], ldmMetadataPartitionGUID) {
+			return int64(binary.LittleEndian.Uint64(entry[0x28:])), nil
+		}
+	}
+
+	return 0, fmt.Errorf("LDM: no LDM metadata partition found")
+}
+
+func readLDMPrivHead(device *Device) (*ldmPrivHead, error) {
+	result, err := readLDMPrivHeadAt(device, ldmPrivHeadSector)
+	if err == nil {
+		return result, nil
+	}
+
+	sector, gpt_err := findLDMGPTPrivHead(device)
+	if gpt_err != nil {
+		return nil, err
+	}
+
+	return readLDMPrivHeadAt(device, sector)
+}
+
+// Returns the offset after the variable length field at base+offset
+// (relative to base). The field is prefixed by its length.
+func ldmRelative(buf []byte, base, offset int) (int, error) {
+	base += offset
+	if offset < 0 || base >= len(buf) || base+int(buf[base]) >= len(buf) {
+		return 0, fmt.Errorf("LDM: VBLK record truncated")
+	}
+	return int(buf[base]) + offset + 1, nil
+}
+
+// Variable length big endian number.
+func ldmGetNumber(buf []byte) uint64 {
+	length := int(buf[0])
+	if length > 8 || length+1 > len(buf) {
+		return 0
+	}
+
+	var result uint64
+	for i := 1; i <= length; i++ {
+		result = result<<8 | uint64(buf[i])
+	}
+	return result
+}
+
+func ldmGetString(buf []byte) string {
+	length := int(buf[0])
+	if length+1 > len(buf) {
+		return ""
+	}
+	return string(buf[1 : length+1])
+}
+
+// Calculate a chain of relative offsets - each field follows the
+// previous one.
+func ldmRelatives(buf []byte, bases ...int) ([]int, error) {
+	result := make([]int, 0, len(bases))
+	last := 0
+	for _, base := range bases {
+		next, err := ldmRelative(buf, base, last)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, next)
+		last = next
+	}
+	return result, nil
+}
+
+func (self *ldmDatabase) parseVblk(buf []byte) error {
+	if len(buf) < 0x19 {
+		return fmt.Errorf("LDM: VBLK record too short")
+	}
+
+	flags := buf[0x12]
+	vblk_type := buf[0x13]
+
+	r, err := ldmRelatives(buf, 0x18, 0x18)
+	if err != nil {
+		return err
+	}
+	r_objid, r_name := r[0], r[1]
+	obj_id := ldmGetNumber(buf[0x18:])
+	name := ldmGetString(buf[0x18+r_objid:])
+
+	switch vblk_type {
+	case ldmTypeComponent:
+		r, err := ldmRelatives(buf, 0x18, 0x18, 0x18, 0x1D, 0x2D)
+		if err != nil {
+			return err
+		}
+		r_vstate, r_child, r_parent := r[2], r[3], r[4]
+
+		component := &ldmComponent{
+			obj_id:    obj_id,
+			name:      name,
+			comp_type: buf[0x18+r_vstate],
+			children:  ldmGetNumber(buf[0x1D+r_vstate:]),
+			parent_id: ldmGetNumber(buf[0x2D+r_child:]),
+		}
+
+		if flags&ldmFlagCompStripe != 0 {
+			if 0x2E+r_parent >= len(buf) {
+				return fmt.Errorf("LDM: VBLK record truncated")
+			}
+			component.chunk_size = int64(ldmGetNumber(
+				buf[0x2E+r_parent:])) * ldmSectorSize
+		}
+		self.components[obj_id] = component
+
+	case ldmTypePartition:
+		r, err := ldmRelatives(buf, 0x18, 0x18, 0x34, 0x34, 0x34)
+		if err != nil {
+			return err
+		}
+		r_size, r_parent, r_diskid := r[2], r[3], r[4]
+
+		partition := &ldmPartition{
+			obj_id: obj_id,
+			name:   name,
+			start: int64(binary.BigEndian.Uint64(buf[0x24+r_name:])) *
+				ldmSectorSize,
+			volume_offset: int64(binary.BigEndian.Uint64(buf[0x2C+r_name:])) *
+				ldmSectorSize,
+			size:      int64(ldmGetNumber(buf[0x34+r_name:])) * ldmSectorSize,
+			parent_id: ldmGetNumber(buf[0x34+r_size:]),
+			disk_id:   ldmGetNumber(buf[0x34+r_parent:]),
+		}
+
+		if flags&ldmFlagPartIndex != 0 && 0x35+r_diskid < len(buf) {
+			partition.index = int(buf[0x35+r_diskid])
+		}
+		self.partitions = append(self.partitions, partition)
+
+	case ldmTypeDisk3:
+		// The disk id is stored as a GUID string.
+		self.disks[obj_id] = strings.ToLower(ldmGetString(buf[0x18+r_name:]))
+
+	case ldmTypeDisk4:
+		// The disk id is stored as raw bytes.
+		if 0x18+r_name+16 > len(buf) {
+			return fmt.Errorf("LDM: VBLK record truncated")
+		}
+		id := buf[0x18+r_name:]
+		self.disks[obj_id] = fmt.Sprintf("%x-%x-%x-%x-%x",
+			id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+
+	case ldmTypeVolume:
+		r, err := ldmRelatives(buf, 0x18, 0x18, 0x18, 0x18, 0x2D, 0x3D)
+		if err != nil {
+			return err
+		}
+		r_child, r_size := r[4], r[5]
+
+		volume := &ldmVolume{
+			obj_id:      obj_id,
+			name:        name,
+			volume_type: ldmGetString(buf[0x18+r_name:]),
+			size:        int64(ldmGetNumber(buf[0x3D+r_child:])) * ldmSectorSize,
+		}
+
+		// Skip the optional fields to get to the drive hint.
+		last := r_size
+		for _, flag := range []byte{ldmFlagVolumeID1, ldmFlagVolumeID2,
+			ldmFlagVolumeSize} {
+			if flags&flag != 0 {
+				last, err = ldmRelative(buf, 0x52, last)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if flags&ldmFlagVolumeDrive != 0 && 0x52+last < len(buf) {
+			volume.drive_hint = ldmGetString(buf[0x52+last:])
+		}
+		self.volumes = append(self.volumes, volume)
+	}
+
+	return nil
+}
+
+// Read the VBLK records from the database on a disk.
+func readLDMDatabase(head *ldmPrivHead) (*ldmDatabase, error) {
+	size := head.config_size * ldmSectorSize
+	if size > ldmMaxConfigSize || size < (ldmVMDBSector+1)*ldmSectorSize {
+		return nil, fmt.Errorf("LDM: invalid config size %v", size)
+	}
+
+	config := make([]byte, size)
+	n, err := head.device.Reader.ReadAt(config, head.config_start*ldmSectorSize)
+	if n < len(config) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	vmdb := config[ldmVMDBSector*ldmSectorSize:]
+	if string(vmdb[:4]) != "VMDB" {
+		return nil, fmt.Errorf("LDM: VMDB not found")
+	}
+
+	result := &ldmDatabase{
+		seq:        binary.BigEndian.Uint32(vmdb[0x04:]),
+		components: make(map[uint64]*ldmComponent),
+		disks:      make(map[uint64]string),
+	}
+
+	vblk_size := int(binary.BigEndian.Uint32(vmdb[0x08:]))
+	vblk_offset := int(binary.BigEndian.Uint32(vmdb[0x0C:]))
+	if vblk_size <= ldmVblkHeadSize || vblk_size > ldmSectorSize {
+		return nil, fmt.Errorf("LDM: invalid VBLK size %v", vblk_size)
+	}
+
+	// Records may be split into several fragments which need to be
+	// joined.
+	fragments := make(map[uint32][][]byte)
+	var groups []uint32
+
+	for offset := vblk_offset; offset+vblk_size <= len(vmdb); offset += vblk_size {
+		vblk := vmdb[offset : offset+vblk_size]
+		if string(vblk[:4]) != "VBLK" {
+			break
+		}
+
+		group := binary.BigEndian.Uint32(vblk[0x08:])
+		rec := int(binary.BigEndian.Uint16(vblk[0x0C:]))
+		num := int(binary.BigEndian.Uint16(vblk[0x0E:]))
+
+		// Unused record
+		if num == 0 {
+			continue
+		}
+
+		if num == 1 {
+			_ = result.parseVblk(vblk)
+			continue
+		}
+
+		parts, pres := fragments[group]
+		if !pres {
+			parts = make([][]byte, num)
+			groups = append(groups, group)
+		}
+		if rec < len(parts) {
+			parts[rec] = vblk
+		}
+		fragments[group] = parts
+	}
+
+	for _, group := range groups {
+		parts := fragments[group]
+		buf := []byte{}
+		for i, part := range parts {
+			if part == nil {
+				buf = nil
+				break
+			}
+			if i == 0 {
+				buf = append(buf, part[:ldmVblkHeadSize]...)
+			}
+			buf = append(buf, part[ldmVblkHeadSize:]...)
+		}
+		if buf != nil {
+			_ = result.parseVblk(buf)
+		}
+	}
+
+	return result, nil
+}
+
+type ldmExtent struct {
+	reader io.ReaderAt
+	offset int64
+	size   int64
+}
+
+// Reads a volume made of a single component. Spanned components
+// concatenate the extents, while striped components spread chunks
+// across them.
+type ldmReader struct {
+	extents    []ldmExtent
+	chunk_size int64
+	striped    bool
+	size       int64
+}
+
+func (self *ldmReader) ReadAt(buf []byte, offset int64) (int, error) {
+	n := 0
+	for n < len(buf) {
+		current := offset + int64(n)
+		if current >= self.size {
+			return n, io.EOF
+		}
+
+		var extent ldmExtent
+		var extent_offset, length int64
+
+		if self.striped {
+			count := int64(len(self.extents))
+			chunk := current / self.chunk_size
+			chunk_offset := current % self.chunk_size
+
+			extent = self.extents[chunk%count]
+			extent_offset = (chunk/count)*self.chunk_size + chunk_offset
+			length = self.chunk_size - chunk_offset
+
+		} else {
+			var start int64
+			for _, e := range self.extents {
+				if current < start+e.size {
+					extent = e
+					extent_offset = current - start
+					length = e.size - extent_offset
+					break
+				}
+				start += e.size
+			}
+			if extent.reader == nil {
+				return n, io.EOF
+			}
+		}
+
+		if length > int64(len(buf)-n) {
+			length = int64(len(buf) - n)
+		}
+		if length > self.size-current {
+			length = self.size - current
+		}
+
+		_, err := extent.reader.ReadAt(buf[n:n+int(length)],
+			extent.offset+extent_offset)
+		if err != nil && err != io.EOF {
+			return n, err
+		}
+		n += int(length)
+	}
+
+	return n, nil
+}
+
+func (self *ldmDatabase) buildReader(volume *ldmVolume,
+	disks map[string]*ldmPrivHead) (*ldmReader, error) {
+	if volume.volume_type != "gen" {
+		return nil, fmt.Errorf("LDM: volume type %v not supported",
+			volume.volume_type)
+	}
+
+	if len(volume.components) == 0 {
+		return nil, fmt.Errorf("LDM: volume has no components")
+	}
+
+	// Mirrored volumes have multiple components with the same data -
+	// just read the first available one.
+	var last_err error
+	for _, component := range volume.components {
+		reader, err := self.buildComponentReader(volume, component, disks)
+		if err == nil {
+			return reader, nil
+		}
+		last_err = err
+	}
+
+	return nil, last_err
+}
+
+func (self *ldmDatabase) buildComponentReader(volume *ldmVolume,
+	component *ldmComponent, disks map[string]*ldmPrivHead) (*ldmReader, error) {
+
+	result := &ldmReader{size: volume.size}
+
+	switch component.comp_type {
+	case ldmComponentSpanned:
+		sort.Slice(component.partitions, func(i, j int) bool {
+			return component.partitions[i].volume_offset <
+				component.partitions[j].volume_offset
+		})
+
+	case ldmComponentStriped:
+		if component.chunk_size <= 0 {
+			return nil, fmt.Errorf("LDM: invalid chunk size")
+		}
+		result.striped = true
+		result.chunk_size = component.chunk_size
+		sort.Slice(component.partitions, func(i, j int) bool {
+			return component.partitions[i].index <
+				component.partitions[j].index
+		})
+
+	case ldmComponentRAID:
+		return nil, fmt.Errorf("LDM: RAID5 volumes are not supported")
+
+	default:
+		return nil, fmt.Errorf("LDM: component type %v not supported",
+			component.comp_type)
+	}
+
+	if len(component.partitions) == 0 {
+		return nil, fmt.Errorf("LDM: component %v has no partitions",
+			component.name)
+	}
+
+	for _, partition := range component.partitions {
+		disk_id, pres := self.disks[partition.disk_id]
+		if !pres {
+			return nil, fmt.Errorf("LDM: disk for partition %v not found",
+				partition.name)
+		}
+
+		head, pres := disks[disk_id]
+		if !pres {
+			return nil, fmt.Errorf("LDM: disk %v is missing", disk_id)
+		}
+
+		result.extents = append(result.extents, ldmExtent{
+			reader: head.device.Reader,
+			offset: head.disk_start + partition.start,
+			size:   partition.size,
+		})
+	}
+
+	return result, nil
+}
+
+func ParseLDM(devices []*Device) ([]*LogicalVolume, error) {
+	disks := make(map[string]*ldmPrivHead)
+	var db *ldmDatabase
+	var last_err error
+
+	for _, device := range devices {
+		head, err := readLDMPrivHead(device)
+		if err != nil {
+			last_err = err
+			continue
+		}
+		disks[head.disk_id] = head
+
+		// All disks in the group carry a copy of the database - use
+		// the most recent one.
+		disk_db, err := readLDMDatabase(head)
+		if err != nil {
+			last_err = err
+			continue
+		}
+
+		if db == nil || disk_db.seq > db.seq {
+			db = disk_db
+		}
+	}
+
+	if db == nil {
+		return nil, last_err
+	}
+
+	// Link the objects together.
+	for _, partition := range db.partitions {
+		component, pres := db.components[partition.parent_id]
+		if pres {
+			component.partitions = append(component.partitions, partition)
+		}
+	}
+
+	components := make([]*ldmComponent, 0, len(db.components))
+	for _, component := range db.components {
+		components = append(components, component)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].obj_id < components[j].obj_id
+	})
+
+	var result []*LogicalVolume
+	for _, volume := range db.volumes {
+		for _, component := range components {
+			if component.parent_id == volume.obj_id {
+				volume.components = append(volume.components, component)
+			}
+		}
+
+		layout := "unknown"
+		if len(volume.components) > 1 {
+			layout = "mirror"
+		} else if len(volume.components) == 1 {
+			switch volume.components[0].comp_type {
+			case ldmComponentStriped:
+				layout = "stripe"
+			case ldmComponentRAID:
+				layout = "raid5"
+			case ldmComponentSpanned:
+				layout = "spanned"
+				if len(volume.components[0].partitions) == 1 {
+					layout = "simple"
+				}
+			}
+		}
+
+		logical_volume := &LogicalVolume{
+			Components: []string{volume.name},
+			Size:       volume.size,
+			Data: ordereddict.NewDict().
+				Set("ID", volume.obj_id).
+				Set("Type", volume.volume_type).
+				Set("Layout", layout).
+				Set("DriveHint", volume.drive_hint),
+		}
+
+		reader, err := db.buildReader(volume, disks)
+		if err != nil {
+			logical_volume.Error = err
+		} else {
+			logical_volume.Reader = reader
+		}
+
+		result = append(result, logical_volume)
+	}
+
+	return result, nil
+}
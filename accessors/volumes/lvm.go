@@ -0,0 +1,489 @@
+package volumes
+
+// Reconstruct LVM2 logical volumes.
+//
+// Each physical volume has a label in one of its first four sectors
+// which points to the metadata areas. The metadata area is a
+// circular buffer holding the text description of the volume group.
+// Logical volumes are made of segments which map ranges of logical
+// extents to physical extents on the physical volumes.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+var (
+	lvmLabelID   = []byte("LABELONE")
+	lvmLabelType = []byte("LVM2 001")
+	lvmMDAMagic  = []byte(" LVM2 x[5A%r0N*>")
+)
+
+const (
+	lvmSectorSize      = 512
+	lvmMDAHeaderSize   = 512
+	lvmMaxMetadataSize = 16 * 1024 * 1024
+	lvmMaxDepth        = 8
+)
+
+type lvmPhysicalVolume struct {
+	reader   io.ReaderAt
+	pe_start int64
+}
+
+type lvmArea struct {
+	// Either a physical volume or a sub logical volume.
+	pv     *lvmPhysicalVolume
+	lv     string
+	offset int64
+}
+
+type lvmSegment struct {
+	// Byte range in the logical volume
+	start int64
+	size  int64
+
+	seg_type    string
+	stripe_size int64
+	areas       []lvmArea
+
+	// Mirrors only need to read the first area.
+	mirrored bool
+}
+
+type lvmLogicalVolume struct {
+	name     string
+	segments []*lvmSegment
+	size     int64
+	err      error
+}
+
+type lvmVolumeGroup struct {
+	name string
+	lvs  map[string]*lvmLogicalVolume
+}
+
+// Read from a logical volume by mapping the range to the underlying
+// physical or sub logical volumes.
+func (self *lvmVolumeGroup) readLV(lv *lvmLogicalVolume,
+	buf []byte, offset int64, depth int) (int, error) {
+	if depth > lvmMaxDepth {
+		return 0, fmt.Errorf("LVM: volume %v nested too deeply", lv.name)
+	}
+
+	if lv.err != nil {
+		return 0, lv.err
+	}
+
+	n := 0
+	for n < len(buf) {
+		current := offset + int64(n)
+		var segment *lvmSegment
+		for _, s := range lv.segments {
+			if current >= s.start && current < s.start+s.size {
+				segment = s
+				break
+			}
+		}
+
+		if segment == nil {
+			if n == 0 {
+				return 0, io.EOF
+			}
+			return n, io.EOF
+		}
+
+		seg_offset := current - segment.start
+		length := segment.size - seg_offset
+		if length > int64(len(buf)-n) {
+			length = int64(len(buf) - n)
+		}
+
+		area := segment.areas[0]
+		area_offset := seg_offset
+
+		if !segment.mirrored && len(segment.areas) > 1 {
+			stripe := seg_offset / segment.stripe_size
+			stripe_offset := seg_offset % segment.stripe_size
+			count := int64(len(segment.areas))
+
+			area = segment.areas[stripe%count]
+			area_offset = (stripe/count)*segment.stripe_size + stripe_offset
+			if length > segment.stripe_size-stripe_offset {
+				length = segment.stripe_size - stripe_offset
+			}
+		}
+
+		to_read := buf[n : n+int(length)]
+		if area.pv != nil {
+			_, err := area.pv.reader.ReadAt(to_read,
+				area.pv.pe_start+area.offset+area_offset)
+			if err != nil && err != io.EOF {
+				return n, err
+			}
+
+		} else {
+			sub_lv, pres := self.lvs[area.lv]
+			if !pres {
+				return n, fmt.Errorf("LVM: sub volume %v not found", area.lv)
+			}
+
+			_, err := self.readLV(sub_lv, to_read, area.offset+area_offset, depth+1)
+			if err != nil && err != io.EOF {
+				return n, err
+			}
+		}
+
+		n += int(length)
+	}
+
+	return n, nil
+}
+
+type lvmReader struct {
+	vg *lvmVolumeGroup
+	lv *lvmLogicalVolume
+}
+
+func (self *lvmReader) ReadAt(buf []byte, offset int64) (int, error) {
+	return self.vg.readLV(self.lv, buf, offset, 0)
+}
+
+type lvmPVLabel struct {
+	uuid     string
+	pe_start int64
+
+	// Metadata area offsets and sizes.
+	mdas [][2]int64
+}
+
+// Find the LVM label in the first four sectors.
+func readLVMLabel(reader io.ReaderAt) (*lvmPVLabel, error) {
+	for sector := int64(0); sector < 4; sector++ {
+		buf := make([]byte, lvmSectorSize)
+		_, err := reader.ReadAt(buf, sector*lvmSectorSize)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if !bytes.Equal(buf[:8], lvmLabelID) ||
+			!bytes.Equal(buf[24:32], lvmLabelType) {
+			continue
+		}
+
+		offset := int64(binary.LittleEndian.Uint32(buf[20:]))
+		if offset < 32 || offset+40 > lvmSectorSize {
+			return nil, fmt.Errorf("LVM: invalid label header")
+		}
+
+		header := buf[offset:]
+		result := &lvmPVLabel{
+			uuid: string(header[:32]),
+		}
+
+		// Two lists of disk locations follow: data areas, then
+		// metadata areas, each terminated by an empty entry.
+		list := 0
+		for pos := 40; pos+16 <= len(header); pos += 16 {
+			locn_offset := int64(binary.LittleEndian.Uint64(header[pos:]))
+			locn_size := int64(binary.LittleEndian.Uint64(header[pos+8:]))
+			if locn_offset == 0 {
+				list++
+				if list == 2 {
+					break
+				}
+				continue
+			}
+
+			if list == 0 {
+				if result.pe_start == 0 {
+					result.pe_start = locn_offset
+				}
+			} else {
+				result.mdas = append(result.mdas, [2]int64{locn_offset, locn_size})
+			}
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("LVM: no physical volume label found")
+}
+
+// Read the current metadata text from a metadata area.
+func readLVMMetadata(reader io.ReaderAt, mda_offset, mda_size int64) (
+	string, error) {
+	header := make([]byte, lvmMDAHeaderSize)
+	_, err := reader.ReadAt(header, mda_offset)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	if !bytes.Equal(header[4:20], lvmMDAMagic) {
+		return "", fmt.Errorf("LVM: invalid metadata area header")
+	}
+
+	offset := int64(binary.LittleEndian.Uint64(header[40:]))
+	size := int64(binary.LittleEndian.Uint64(header[48:]))
+	if offset == 0 || size <= 0 || size > lvmMaxMetadataSize ||
+		offset >= mda_size {
+		return "", fmt.Errorf("LVM: no metadata")
+	}
+
+	buf := make([]byte, size)
+
+	// The metadata area is a circular buffer following the header.
+	first := size
+	if offset+size > mda_size {
+		first = mda_size - offset
+	}
+
+	_, err = reader.ReadAt(buf[:first], mda_offset+offset)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	if first < size {
+		_, err = reader.ReadAt(buf[first:], mda_offset+lvmMDAHeaderSize)
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+	}
+
+	return string(bytes.TrimRight(buf, "\x00")), nil
+}
+
+func ParseLVM(devices []*Device) ([]*LogicalVolume, error) {
+	// Map PV uuids to devices.
+	pvs := make(map[string]*Device)
+	labels := make(map[string]*lvmPVLabel)
+
+	// Volume groups by id. We keep the latest version of the
+	// metadata.
+	configs := make(map[string]*ordereddict.Dict)
+	names := make(map[string]string)
+	seqnos := make(map[string]int64)
+
+	var last_err error
+	for _, device := range devices {
+		label, err := readLVMLabel(device.Reader)
+		if err != nil {
+			last_err = fmt.Errorf("%v: %w", device.Path.String(), err)
+			continue
+		}
+
+		pvs[label.uuid] = device
+		labels[label.uuid] = label
+
+		for _, mda := range label.mdas {
+			text, err := readLVMMetadata(device.Reader, mda[0], mda[1])
+			if err != nil {
+				last_err = err
+				continue
+			}
+
+			config, err := parseLVMConfig(text)
+			if err != nil {
+				last_err = err
+				continue
+			}
+
+			// The volume group is the only section at the top level.
+			for _, k := range config.Keys() {
+				vg := lvmGetDict(config, k)
+				id := lvmGetString(vg, "id")
+				if id == "" {
+					continue
+				}
+
+				seqno := lvmGetInt(vg, "seqno")
+				if _, pres := configs[id]; !pres || seqno > seqnos[id] {
+					configs[id] = vg
+					names[id] = k
+					seqnos[id] = seqno
+				}
+			}
+		}
+	}
+
+	if len(configs) == 0 {
+		if last_err == nil {
+			last_err = fmt.Errorf("no volume groups found")
+		}
+		return nil, last_err
+	}
+
+	var result []*LogicalVolume
+	for id, config := range configs {
+		result = append(result,
+			buildLVMVolumeGroup(names[id], config, pvs, labels)...)
+	}
+
+	return result, nil
+}
+
+func lvmNormalizeUUID(uuid string) string {
+	return strings.ReplaceAll(uuid, "-", "")
+}
+
+func buildLVMVolumeGroup(name string, config *ordereddict.Dict,
+	devices map[string]*Device, labels map[string]*lvmPVLabel) []*LogicalVolume {
+	extent_size := lvmGetInt(config, "extent_size") * lvmSectorSize
+
+	vg := &lvmVolumeGroup{
+		name: name,
+		lvs:  make(map[string]*lvmLogicalVolume),
+	}
+
+	// Resolve the physical volumes to devices.
+	pvs := make(map[string]*lvmPhysicalVolume)
+	physical_volumes := lvmGetDict(config, "physical_volumes")
+	for _, pv_name := range physical_volumes.Keys() {
+		pv_config := lvmGetDict(physical_volumes, pv_name)
+		uuid := lvmNormalizeUUID(lvmGetString(pv_config, "id"))
+		device, pres := devices[uuid]
+		if !pres {
+			continue
+		}
+
+		pe_start := lvmGetInt(pv_config, "pe_start") * lvmSectorSize
+		if pe_start == 0 {
+			pe_start = labels[uuid].pe_start
+		}
+
+		pvs[pv_name] = &lvmPhysicalVolume{
+			reader:   device.Reader,
+			pe_start: pe_start,
+		}
+	}
+
+	var result []*LogicalVolume
+	logical_volumes := lvmGetDict(config, "logical_volumes")
+	for _, lv_name := range logical_volumes.Keys() {
+		lv_config := lvmGetDict(logical_volumes, lv_name)
+		lv := &lvmLogicalVolume{name: lv_name}
+		vg.lvs[lv_name] = lv
+
+		var seg_types []string
+		for _, k := range lv_config.Keys() {
+			value, _ := lv_config.Get(k)
+			seg_config, ok := value.(*ordereddict.Dict)
+			if !ok || !strings.HasPrefix(k, "segment") {
+				continue
+			}
+
+			segment, err := buildLVMSegment(seg_config, extent_size, pvs)
+			if err != nil && lv.err == nil {
+				lv.err = fmt.Errorf("LVM: %v: %w", lv_name, err)
+			}
+
+			seg_types = append(seg_types, lvmGetString(seg_config, "type"))
+			if segment != nil {
+				lv.segments = append(lv.segments, segment)
+				if segment.start+segment.size > lv.size {
+					lv.size = segment.start + segment.size
+				}
+			}
+		}
+
+		status := lvmGetList(lv_config, "status")
+		visible := false
+		for _, s := range status {
+			if s == "VISIBLE" {
+				visible = true
+			}
+		}
+
+		volume := &LogicalVolume{
+			Components: []string{name, lv_name},
+			Size:       lv.size,
+			Data: ordereddict.NewDict().
+				Set("VolumeGroup", name).
+				Set("VolumeGroupID", lvmGetString(config, "id")).
+				Set("ID", lvmGetString(lv_config, "id")).
+				Set("Visible", visible).
+				Set("Status", status).
+				Set("SegmentTypes", seg_types).
+				Set("CreationHost", lvmGetString(lv_config, "creation_host")).
+				Set("CreationTime", lvmGetInt(lv_config, "creation_time")),
+			Error: lv.err,
+		}
+
+		if lv.err == nil {
+			volume.Reader = &lvmReader{vg: vg, lv: lv}
+		}
+
+		result = append(result, volume)
+	}
+
+	return result
+}
+
+func buildLVMSegment(config *ordereddict.Dict, extent_size int64,
+	pvs map[string]*lvmPhysicalVolume) (*lvmSegment, error) {
+	result := &lvmSegment{
+		start:       lvmGetInt(config, "start_extent") * extent_size,
+		size:        lvmGetInt(config, "extent_count") * extent_size,
+		seg_type:    lvmGetString(config, "type"),
+		stripe_size: lvmGetInt(config, "stripe_size") * lvmSectorSize,
+	}
+
+	switch result.seg_type {
+	case "striped", "linear":
+		// A list of pv name, start extent pairs
+		stripes := lvmGetList(config, "stripes")
+		for i := 0; i+1 < len(stripes); i += 2 {
+			pv_name, _ := stripes[i].(string)
+			start, _ := stripes[i+1].(int64)
+			pv, pres := pvs[pv_name]
+			if !pres {
+				return nil, fmt.Errorf("physical volume %v is missing", pv_name)
+			}
+			result.areas = append(result.areas, lvmArea{
+				pv:     pv,
+				offset: start * extent_size,
+			})
+		}
+
+	case "mirror":
+		// A list of sub volume, start extent pairs
+		mirrors := lvmGetList(config, "mirrors")
+		for i := 0; i+1 < len(mirrors); i += 2 {
+			lv_name, _ := mirrors[i].(string)
+			start, _ := mirrors[i+1].(int64)
+			result.areas = append(result.areas, lvmArea{
+				lv:     lv_name,
+				offset: start * extent_size,
+			})
+		}
+		result.mirrored = true
+
+	case "raid1", "raid0", "raid0_meta":
+		// A list of sub volumes. Metadata sub volumes are ignored.
+		for _, item := range lvmGetList(config, "raids") {
+			lv_name, _ := item.(string)
+			if strings.Contains(lv_name, "_rmeta_") {
+				continue
+			}
+			result.areas = append(result.areas, lvmArea{lv: lv_name})
+		}
+		result.mirrored = result.seg_type == "raid1"
+
+	default:
+		return nil, fmt.Errorf("segment type %v is not supported", result.seg_type)
+	}
+
+	if len(result.areas) == 0 {
+		return nil, fmt.Errorf("segment has no areas")
+	}
+
+	if !result.mirrored && len(result.areas) > 1 && result.stripe_size <= 0 {
+		return nil, fmt.Errorf("invalid stripe size")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,205 @@
+package volumes
+
+// A parser for the LVM2 text metadata format. The format consists of
+// nested sections and key = value assignments where values are
+// strings, integers or lists:
+//
+// vg0 {
+//   extent_size = 8192
+//   physical_volumes {
+//      pv0 {
+//         id = "abcd-..."
+//      }
+//   }
+// }
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+type lvmTokenizer struct {
+	data string
+	pos  int
+}
+
+func (self *lvmTokenizer) skipSpace() {
+	for self.pos < len(self.data) {
+		c := self.data[self.pos]
+		switch {
+		case c == '#':
+			for self.pos < len(self.data) && self.data[self.pos] != '\n' {
+				self.pos++
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == 0:
+			self.pos++
+		default:
+			return
+		}
+	}
+}
+
+// Returns the next token. Strings are returned with their quotes.
+func (self *lvmTokenizer) next() (string, error) {
+	self.skipSpace()
+	if self.pos >= len(self.data) {
+		return "", nil
+	}
+
+	start := self.pos
+	c := self.data[self.pos]
+	switch {
+	case strings.IndexByte("{}[]=,", c) >= 0:
+		self.pos++
+
+	case c == '"':
+		self.pos++
+		for self.pos < len(self.data) && self.data[self.pos] != '"' {
+			if self.data[self.pos] == '\\' {
+				self.pos++
+			}
+			self.pos++
+		}
+		if self.pos >= len(self.data) {
+			return "", fmt.Errorf("LVM: unterminated string")
+		}
+		self.pos++
+
+	default:
+		for self.pos < len(self.data) &&
+			strings.IndexByte("{}[]=,\"# \t\r\n", self.data[self.pos]) < 0 {
+			self.pos++
+		}
+	}
+
+	return self.data[start:self.pos], nil
+}
+
+func lvmValue(token string) interface{} {
+	if strings.HasPrefix(token, "\"") {
+		return strings.ReplaceAll(token[1:len(token)-1], "\\\"", "\"")
+	}
+
+	value, err := strconv.ParseInt(token, 0, 64)
+	if err == nil {
+		return value
+	}
+	return token
+}
+
+// Parse the content of a section until the closing brace (or the end
+// of the data at the top level).
+func (self *lvmTokenizer) parseSection(depth int) (*ordereddict.Dict, error) {
+	if depth > 10 {
+		return nil, fmt.Errorf("LVM: metadata nested too deeply")
+	}
+
+	result := ordereddict.NewDict()
+	for {
+		token, err := self.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch token {
+		case "":
+			if depth > 0 {
+				return nil, fmt.Errorf("LVM: unexpected end of metadata")
+			}
+			return result, nil
+
+		case "}":
+			return result, nil
+		}
+
+		op, err := self.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case "{":
+			section, err := self.parseSection(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			result.Set(token, section)
+
+		case "=":
+			value, err := self.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			result.Set(token, value)
+
+		default:
+			return nil, fmt.Errorf("LVM: unexpected token %v after %v", op, token)
+		}
+	}
+}
+
+func (self *lvmTokenizer) parseValue() (interface{}, error) {
+	token, err := self.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if token != "[" {
+		return lvmValue(token), nil
+	}
+
+	result := []interface{}{}
+	for {
+		token, err := self.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch token {
+		case "]":
+			return result, nil
+		case ",":
+			continue
+		case "":
+			return nil, fmt.Errorf("LVM: unterminated list")
+		}
+
+		result = append(result, lvmValue(token))
+	}
+}
+
+func parseLVMConfig(data string) (*ordereddict.Dict, error) {
+	tokenizer := &lvmTokenizer{data: data}
+	return tokenizer.parseSection(0)
+}
+
+// Helpers to access the parsed config.
+func lvmGetDict(config *ordereddict.Dict, key string) *ordereddict.Dict {
+	value, _ := config.Get(key)
+	result, ok := value.(*ordereddict.Dict)
+	if !ok {
+		return ordereddict.NewDict()
+	}
+	return result
+}
+
+func lvmGetInt(config *ordereddict.Dict, key string) int64 {
+	value, _ := config.Get(key)
+	result, _ := value.(int64)
+	return result
+}
+
+func lvmGetString(config *ordereddict.Dict, key string) string {
+	value, _ := config.Get(key)
+	result, _ := value.(string)
+	return result
+}
+
+func lvmGetList(config *ordereddict.Dict, key string) []interface{} {
+	value, _ := config.Get(key)
+	result, _ := value.([]interface{})
+	return result
+}
@@ -0,0 +1,524 @@
+package volumes
+
+// Reconstruct Linux software RAID (md) arrays.
+//
+// Each member device carries a superblock describing the array and
+// the role of the device within it. Version 0.90 and 1.0
+// superblocks are stored near the end of the device, version 1.1 at
+// the start and version 1.2 4k from the start.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+const (
+	mdMagic      = 0xa92b4efc
+	mdSectorSize = 512
+
+	mdRoleSpare  = 0xffff
+	mdRoleFaulty = 0xfffe
+
+	mdLevelLinear = 0xffffffff
+
+	// RAID5 layouts
+	mdLeftAsymmetric  = 0
+	mdRightAsymmetric = 1
+	mdLeftSymmetric   = 2
+	mdRightSymmetric  = 3
+
+	mdMaxDisks = 256
+)
+
+type mdMember struct {
+	device *Device
+
+	version    string
+	uuid       string
+	name       string
+	level      uint32
+	layout     uint32
+	chunk_size int64
+	raid_disks int
+	role       int
+	events     uint64
+
+	// Usable size of the member device in bytes (0 if unknown).
+	size        int64
+	data_offset int64
+}
+
+func parseMDSuperblock1(buf []byte, device *Device, version string) *mdMember {
+	if binary.LittleEndian.Uint32(buf) != mdMagic ||
+		binary.LittleEndian.Uint32(buf[4:]) != 1 {
+		return nil
+	}
+
+	dev_number := binary.LittleEndian.Uint32(buf[160:])
+	max_dev := binary.LittleEndian.Uint32(buf[220:])
+	role := mdRoleSpare
+	if dev_number < max_dev && 256+int(dev_number)*2+2 <= len(buf) {
+		role = int(binary.LittleEndian.Uint16(buf[256+dev_number*2:]))
+	}
+
+	result := &mdMember{
+		device:      device,
+		version:     version,
+		uuid:        hex.EncodeToString(buf[16:32]),
+		name:        string(bytes.TrimRight(buf[32:64], "\x00")),
+		level:       binary.LittleEndian.Uint32(buf[72:]),
+		layout:      binary.LittleEndian.Uint32(buf[76:]),
+		chunk_size:  int64(binary.LittleEndian.Uint32(buf[88:])) * mdSectorSize,
+		raid_disks:  int(binary.LittleEndian.Uint32(buf[92:])),
+		data_offset: int64(binary.LittleEndian.Uint64(buf[128:])) * mdSectorSize,
+		size:        int64(binary.LittleEndian.Uint64(buf[136:])) * mdSectorSize,
+		events:      binary.LittleEndian.Uint64(buf[200:]),
+		role:        role,
+	}
+
+	// For most levels the size field is the amount of each device
+	// used by the array.
+	used := int64(binary.LittleEndian.Uint64(buf[80:])) * mdSectorSize
+	if used > 0 && result.level != 0 && result.level != mdLevelLinear {
+		result.size = used
+	}
+
+	return result
+}
+
+func parseMDSuperblock090(buf []byte, device *Device) *mdMember {
+	word := func(i int) uint32 {
+		return binary.LittleEndian.Uint32(buf[i*4:])
+	}
+
+	if word(0) != mdMagic || word(1) != 0 || word(2) != 90 {
+		return nil
+	}
+
+	uuid := make([]byte, 16)
+	binary.LittleEndian.PutUint32(uuid, word(5))
+	binary.LittleEndian.PutUint32(uuid[4:], word(13))
+	binary.LittleEndian.PutUint32(uuid[8:], word(14))
+	binary.LittleEndian.PutUint32(uuid[12:], word(15))
+
+	return &mdMember{
+		device:     device,
+		version:    "0.90",
+		uuid:       hex.EncodeToString(uuid),
+		level:      word(7),
+		layout:     word(64),
+		chunk_size: int64(word(65)),
+		raid_disks: int(word(10)),
+		size:       int64(word(8)) * 1024,
+
+		// The this_disk descriptor follows the disk descriptors.
+		role:   int(word(992 + 3)),
+		events: uint64(word(8+32))<<32 | uint64(word(7+32)),
+	}
+}
+
+// Look for a superblock in all the possible locations.
+func readMDSuperblock(device *Device) (*mdMember, error) {
+	buf := make([]byte, 4096)
+	read := func(offset int64) bool {
+		if offset < 0 {
+			return false
+		}
+		n, _ := device.Reader.ReadAt(buf, offset)
+		return n == len(buf)
+	}
+
+	if read(0) {
+		result := parseMDSuperblock1(buf, device, "1.1")
+		if result != nil {
+			return result, nil
+		}
+	}
+
+	if read(4096) {
+		result := parseMDSuperblock1(buf, device, "1.2")
+		if result != nil {
+			return result, nil
+		}
+	}
+
+	if device.Size > 0 {
+		sectors := device.Size / mdSectorSize
+		if read(((sectors - 16) &^ 7) * mdSectorSize) {
+			result := parseMDSuperblock1(buf, device, "1.0")
+			if result != nil {
+				return result, nil
+			}
+		}
+
+		offset := device.Size&^(64*1024-1) - 64*1024
+		if read(offset) {
+			result := parseMDSuperblock090(buf, device)
+			if result != nil {
+				// The array starts at the beginning of the device
+				// and ends at the superblock.
+				if result.size == 0 || result.size > offset {
+					result.size = offset
+				}
+				return result, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("mdraid: no superblock found on %v",
+		device.Path.String())
+}
+
+type mdArray struct {
+	level      uint32
+	layout     uint32
+	chunk_size int64
+	disks      []*mdMember
+	size       int64
+
+	// Linear arrays concatenate the member devices.
+	offsets []int64
+}
+
+// Read a chunk sized range from a member device.
+func (self *mdArray) readMember(disk int, buf []byte, offset int64) error {
+	member := self.disks[disk]
+	if member == nil {
+		return fmt.Errorf("mdraid: member %v is missing", disk)
+	}
+
+	n, err := member.device.Reader.ReadAt(buf, member.data_offset+offset)
+	if n < len(buf) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return nil
+}
+
+// Reconstruct a missing member from the other members and the XOR
+// parity.
+func (self *mdArray) reconstruct(missing int, buf []byte, offset int64,
+	exclude int) error {
+	for i := range buf {
+		buf[i] = 0
+	}
+
+	tmp := make([]byte, len(buf))
+	for disk := range self.disks {
+		if disk == missing || disk == exclude {
+			continue
+		}
+
+		err := self.readMember(disk, tmp, offset)
+		if err != nil {
+			return err
+		}
+
+		for i := range buf {
+			buf[i] ^= tmp[i]
+		}
+	}
+	return nil
+}
+
+func (self *mdArray) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset >= self.size {
+		return 0, io.EOF
+	}
+
+	to_read := buf
+	if offset+int64(len(to_read)) > self.size {
+		to_read = to_read[:self.size-offset]
+	}
+
+	n := 0
+	for n < len(to_read) {
+		current := offset + int64(n)
+		length, err := self.readRun(to_read[n:], current)
+		if err != nil {
+			return n, err
+		}
+		n += length
+	}
+
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read at most up to the end of the current chunk. Returns the number
+// of bytes read.
+func (self *mdArray) readRun(buf []byte, offset int64) (int, error) {
+	if self.level == mdLevelLinear {
+		for i, member := range self.disks {
+			start := self.offsets[i]
+			if offset >= start && offset < start+member.size {
+				length := start + member.size - offset
+				if length > int64(len(buf)) {
+					length = int64(len(buf))
+				}
+				return int(length), self.readMember(i, buf[:length], offset-start)
+			}
+		}
+		return 0, io.EOF
+	}
+
+	if self.level == 1 {
+		for i, member := range self.disks {
+			if member != nil {
+				return len(buf), self.readMember(i, buf, offset)
+			}
+		}
+		return 0, fmt.Errorf("mdraid: no members available")
+	}
+
+	chunk := offset / self.chunk_size
+	chunk_offset := offset % self.chunk_size
+	length := self.chunk_size - chunk_offset
+	if length > int64(len(buf)) {
+		length = int64(len(buf))
+	}
+	buf = buf[:length]
+
+	n := int64(len(self.disks))
+	var disk int
+	var disk_offset int64
+
+	switch self.level {
+	case 0:
+		disk = int(chunk % n)
+		disk_offset = (chunk/n)*self.chunk_size + chunk_offset
+
+	case 10:
+		// Near layout: each chunk is repeated on consecutive
+		// devices.
+		copies := int64(self.layout & 0xff)
+		position := chunk * copies
+		disk_offset = (position/n)*self.chunk_size + chunk_offset
+		for c := int64(0); c < copies; c++ {
+			disk = int((position + c) % n)
+			if self.disks[disk] != nil {
+				break
+			}
+		}
+
+	case 4, 5, 6:
+		parity_disks := int64(1)
+		if self.level == 6 {
+			parity_disks = 2
+		}
+
+		data_disks := n - parity_disks
+		stripe := chunk / data_disks
+		index := chunk % data_disks
+		disk_offset = stripe*self.chunk_size + chunk_offset
+
+		var parity int64
+		switch {
+		case self.level == 4:
+			parity = n - 1
+			disk = int(index)
+
+		case self.layout == mdLeftAsymmetric || self.layout == mdLeftSymmetric:
+			parity = n - 1 - stripe%n
+		default:
+			parity = stripe % n
+		}
+
+		if self.level != 4 {
+			switch self.layout {
+			case mdLeftAsymmetric, mdRightAsymmetric:
+				disk = int(index)
+				if disk >= int(parity) {
+					disk += int(parity_disks)
+				}
+			default:
+				disk = int((parity + parity_disks + index) % n)
+			}
+		}
+
+		if self.disks[disk] == nil {
+			// For RAID6 the Q syndrome does not participate in the
+			// XOR parity.
+			exclude := -1
+			if self.level == 6 {
+				exclude = int((parity + 1) % n)
+			}
+
+			if self.disks[parity] == nil {
+				return 0, fmt.Errorf("mdraid: unable to reconstruct chunk %v", chunk)
+			}
+
+			return int(length), self.reconstruct(disk, buf, disk_offset, exclude)
+		}
+
+	default:
+		return 0, fmt.Errorf("mdraid: RAID level %v not supported", self.level)
+	}
+
+	return int(length), self.readMember(disk, buf, disk_offset)
+}
+
+func (self *mdArray) computeSize(members []*mdMember) error {
+	n := int64(len(self.disks))
+
+	// All members should have the same size - use the smallest one.
+	var size int64
+	for _, m := range members {
+		if size == 0 || (m.size > 0 && m.size < size) {
+			size = m.size
+		}
+	}
+
+	switch self.level {
+	case mdLevelLinear:
+		var offset int64
+		for _, m := range self.disks {
+			if m == nil {
+				return fmt.Errorf("mdraid: linear array is missing members")
+			}
+			self.offsets = append(self.offsets, offset)
+			offset += m.size
+		}
+		self.size = offset
+		return nil
+
+	case 0:
+		for _, m := range self.disks {
+			if m == nil {
+				return fmt.Errorf("mdraid: RAID0 array is missing members")
+			}
+		}
+		self.size = size * n
+
+	case 1:
+		self.size = size
+
+	case 4, 5:
+		self.size = size * (n - 1)
+
+	case 6:
+		self.size = size * (n - 2)
+
+	case 10:
+		copies := int64(self.layout & 0xff)
+		far := int64(self.layout>>8) & 0xff
+		if copies == 0 || far > 1 || self.layout>>16 != 0 {
+			return fmt.Errorf("mdraid: RAID10 layout %#x not supported", self.layout)
+		}
+		self.size = size * n / copies
+
+	default:
+		return fmt.Errorf("mdraid: RAID level %v not supported", self.level)
+	}
+
+	if self.level != 1 && self.chunk_size <= 0 {
+		return fmt.Errorf("mdraid: invalid chunk size")
+	}
+
+	return nil
+}
+
+func mdLevelName(level uint32) string {
+	if level == mdLevelLinear {
+		return "linear"
+	}
+	return fmt.Sprintf("raid%d", level)
+}
+
+func ParseMDRaid(devices []*Device) ([]*LogicalVolume, error) {
+	arrays := make(map[string][]*mdMember)
+	var last_err error
+
+	for _, device := range devices {
+		member, err := readMDSuperblock(device)
+		if err != nil {
+			last_err = err
+			continue
+		}
+		arrays[member.uuid] = append(arrays[member.uuid], member)
+	}
+
+	if len(arrays) == 0 {
+		return nil, last_err
+	}
+
+	var result []*LogicalVolume
+	for uuid, members := range arrays {
+		// Use the member with the most recent event count to describe
+		// the array. Stale members are excluded.
+		sort.Slice(members, func(i, j int) bool {
+			return members[i].events > members[j].events
+		})
+		first := members[0]
+
+		array := &mdArray{
+			level:      first.level,
+			layout:     first.layout,
+			chunk_size: first.chunk_size,
+		}
+
+		name := first.name
+		if name == "" {
+			name = uuid
+		}
+
+		volume := &LogicalVolume{
+			Components: []string{name},
+		}
+
+		var err error
+		if first.raid_disks <= 0 || first.raid_disks > mdMaxDisks {
+			err = fmt.Errorf("mdraid: invalid number of disks %v", first.raid_disks)
+
+		} else {
+			array.disks = make([]*mdMember, first.raid_disks)
+			var active []*mdMember
+			for _, m := range members {
+				if m.events == first.events && m.role < first.raid_disks &&
+					array.disks[m.role] == nil {
+					array.disks[m.role] = m
+					active = append(active, m)
+				}
+			}
+			err = array.computeSize(active)
+		}
+
+		missing := 0
+		for _, d := range array.disks {
+			if d == nil {
+				missing++
+			}
+		}
+
+		volume.Size = array.size
+		volume.Data = ordereddict.NewDict().
+			Set("UUID", uuid).
+			Set("Name", first.name).
+			Set("Version", first.version).
+			Set("Level", mdLevelName(first.level)).
+			Set("Layout", first.layout).
+			Set("ChunkSize", first.chunk_size).
+			Set("RaidDisks", first.raid_disks).
+			Set("MissingDisks", missing)
+
+		if err != nil {
+			volume.Error = err
+		} else {
+			volume.Reader = array
+		}
+
+		result = append(result, volume)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,478 @@
+// Accessors that reconstruct logical volumes from volume manager
+// metadata (LVM2, Linux software RAID and Windows LDM dynamic disks).
+//
+// The delegate refers to the physical devices making up the volume
+// group or array. When the volume spans more than one device the
+// DelegatePath may be a JSON encoded list of paths, all opened with
+// the DelegateAccessor. Each logical volume is presented as a file
+// which may in turn be used as the delegate of a filesystem accessor
+// such as ntfs.
+//
+// For example to list the files in an LVM logical volume:
+//
+// SELECT * FROM glob(globs="/*", accessor="raw_ntfs",
+//    root=pathspec(DelegateAccessor="lvm",
+//       DelegatePath=pathspec(DelegateAccessor="file",
+//                             DelegatePath="/images/disk.dd",
+//                             Path="/vg0/data")))
+
+package volumes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/json"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/readers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	NotFoundError = errors.New("Volume not found")
+)
+
+// A physical device holding volume manager metadata.
+type Device struct {
+	Path   *accessors.OSPath
+	Reader io.ReaderAt
+	Size   int64
+}
+
+// A reconstructed logical volume.
+type LogicalVolume struct {
+	// The path of the volume in the accessor's namespace.
+	Components []string
+	Size       int64
+	Data       *ordereddict.Dict
+
+	// Reader is nil if the volume layout is not supported. In that
+	// case Error explains why.
+	Reader io.ReaderAt
+	Error  error
+}
+
+// Parses the devices into logical volumes.
+type volumeParser func(devices []*Device) ([]*LogicalVolume, error)
+
+type VolumeContext struct {
+	mu      sync.Mutex
+	volumes []*LogicalVolume
+	readers []*readers.AccessorReader
+}
+
+func (self *VolumeContext) Close() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for _, r := range self.readers {
+		r.Close()
+	}
+	self.readers = nil
+}
+
+func (self *VolumeContext) Get(components []string) (*LogicalVolume, error) {
+	for _, v := range self.volumes {
+		if componentsEqual(v.Components, components) {
+			return v, nil
+		}
+	}
+	return nil, NotFoundError
+}
+
+// Returns true if the path refers to a directory containing
+// volumes (e.g. an LVM volume group).
+func (self *VolumeContext) IsDir(components []string) bool {
+	for _, v := range self.volumes {
+		if len(v.Components) > len(components) &&
+			componentsEqual(v.Components[:len(components)], components) {
+			return true
+		}
+	}
+	return false
+}
+
+func componentsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// The delegate path may be a single path or a JSON list of paths.
+func getDevicePaths(scope vfilter.Scope, full_path *accessors.OSPath) (
+	string, []*accessors.OSPath, error) {
+	pathspec := full_path.PathSpec()
+	accessor_name := pathspec.DelegateAccessor
+	if accessor_name == "" {
+		accessor_name = "auto"
+	}
+
+	err := vql_subsystem.CheckFilesystemAccess(scope, accessor_name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	accessor, err := accessors.GetAccessor(accessor_name, scope)
+	if err != nil {
+		scope.Log("%v: did you provide a URL or PathSpec?", err)
+		return "", nil, err
+	}
+
+	delegate_path := pathspec.GetDelegatePath()
+	paths := []string{delegate_path}
+	if strings.HasPrefix(delegate_path, "[") {
+		paths = nil
+		err := json.Unmarshal([]byte(delegate_path), &paths)
+		if err != nil {
+			return "", nil, fmt.Errorf("Invalid DelegatePath list: %w", err)
+		}
+	}
+
+	var result []*accessors.OSPath
+	for _, p := range paths {
+		os_path, err := accessor.ParsePath(p)
+		if err != nil {
+			return "", nil, err
+		}
+		result = append(result, os_path)
+	}
+
+	return accessor_name, result, nil
+}
+
+func GetVolumeContext(scope vfilter.Scope,
+	full_path *accessors.OSPath, kind string, parser volumeParser) (
+	*VolumeContext, error) {
+
+	accessor_name, device_paths, err := getDevicePaths(scope, full_path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "volume_cache_" + kind + accessor_name
+	for _, p := range device_paths {
+		key += p.String()
+	}
+
+	// Get the cache context from the root scope's cache
+	ctx, ok := vql_subsystem.CacheGet(scope, key).(*VolumeContext)
+	if ok {
+		return ctx, nil
+	}
+
+	accessor, err := accessors.GetAccessor(accessor_name, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	lru_size := vql_subsystem.GetIntFromRow(
+		scope, scope, constants.NTFS_CACHE_SIZE)
+
+	ctx = &VolumeContext{}
+	var devices []*Device
+	for _, device_path := range device_paths {
+		// Raw devices may not be statable so the size is not
+		// always known.
+		var size int64
+		stat, err := accessor.LstatWithOSPath(device_path)
+		if err == nil {
+			size = stat.Size()
+		}
+
+		reader, err := readers.NewPagedReader(
+			scope, accessor_name, device_path, int(lru_size))
+		if err != nil {
+			ctx.Close()
+			return nil, err
+		}
+		ctx.readers = append(ctx.readers, reader)
+
+		devices = append(devices, &Device{
+			Path:   device_path,
+			Reader: reader,
+			Size:   size,
+		})
+	}
+
+	ctx.volumes, err = parser(devices)
+	if err != nil {
+		ctx.Close()
+		return nil, fmt.Errorf("%v: %w", kind, err)
+	}
+
+	sort.Slice(ctx.volumes, func(i, j int) bool {
+		return strings.Join(ctx.volumes[i].Components, "/") <
+			strings.Join(ctx.volumes[j].Components, "/")
+	})
+
+	vql_subsystem.CacheSet(scope, key, ctx)
+
+	// Close the devices when we are done with this query.
+	err = vql_subsystem.GetRootScope(scope).AddDestructor(ctx.Close)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx, nil
+}
+
+type VolumeFileSystemAccessor struct {
+	scope  vfilter.Scope
+	kind   string
+	parser volumeParser
+}
+
+func (self VolumeFileSystemAccessor) New(scope vfilter.Scope) (
+	accessors.FileSystemAccessor, error) {
+	return &VolumeFileSystemAccessor{
+		scope:  scope,
+		kind:   self.kind,
+		parser: self.parser,
+	}, nil
+}
+
+func (self VolumeFileSystemAccessor) ParsePath(path string) (
+	*accessors.OSPath, error) {
+	return accessors.NewLinuxOSPath(path)
+}
+
+func (self *VolumeFileSystemAccessor) ReadDir(path string) (
+	[]accessors.FileInfo, error) {
+	full_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.ReadDirWithOSPath(full_path)
+}
+
+func (self *VolumeFileSystemAccessor) ReadDirWithOSPath(
+	full_path *accessors.OSPath) ([]accessors.FileInfo, error) {
+	ctx, err := GetVolumeContext(self.scope, full_path, self.kind, self.parser)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []accessors.FileInfo
+	seen := make(map[string]bool)
+	depth := len(full_path.Components)
+
+	for _, v := range ctx.volumes {
+		if len(v.Components) <= depth ||
+			!componentsEqual(v.Components[:depth], full_path.Components) {
+			continue
+		}
+
+		name := v.Components[depth]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		child := full_path.Append(name)
+		if len(v.Components) == depth+1 {
+			result = append(result, volumeFileInfo(child, v))
+		} else {
+			result = append(result, &accessors.VirtualFileInfo{
+				Path:   child,
+				IsDir_: true,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func volumeFileInfo(
+	full_path *accessors.OSPath, v *LogicalVolume) accessors.FileInfo {
+	data := v.Data
+	if data == nil {
+		data = ordereddict.NewDict()
+	}
+
+	if v.Error != nil {
+		data = ordereddict.NewDict().
+			Set("Error", v.Error.Error())
+		for _, k := range v.Data.Keys() {
+			value, _ := v.Data.Get(k)
+			data.Set(k, value)
+		}
+	}
+
+	return &accessors.VirtualFileInfo{
+		Path:  full_path,
+		Size_: v.Size,
+		Data_: data,
+	}
+}
+
+func (self *VolumeFileSystemAccessor) Lstat(path string) (
+	accessors.FileInfo, error) {
+	full_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.LstatWithOSPath(full_path)
+}
+
+func (self *VolumeFileSystemAccessor) LstatWithOSPath(
+	full_path *accessors.OSPath) (accessors.FileInfo, error) {
+	ctx, err := GetVolumeContext(self.scope, full_path, self.kind, self.parser)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := ctx.Get(full_path.Components)
+	if err == nil {
+		return volumeFileInfo(full_path, v), nil
+	}
+
+	if len(full_path.Components) == 0 || ctx.IsDir(full_path.Components) {
+		return &accessors.VirtualFileInfo{
+			Path:   full_path,
+			IsDir_: true,
+		}, nil
+	}
+
+	return nil, err
+}
+
+func (self *VolumeFileSystemAccessor) Open(path string) (
+	accessors.ReadSeekCloser, error) {
+	full_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.OpenWithOSPath(full_path)
+}
+
+func (self *VolumeFileSystemAccessor) OpenWithOSPath(
+	full_path *accessors.OSPath) (accessors.ReadSeekCloser, error) {
+	ctx, err := GetVolumeContext(self.scope, full_path, self.kind, self.parser)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := ctx.Get(full_path.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Reader == nil {
+		return nil, v.Error
+	}
+
+	return &volumeReader{
+		reader: v.Reader,
+		size:   v.Size,
+	}, nil
+}
+
+// A ReadSeekCloser over a logical volume. The underlying devices
+// are owned by the VolumeContext.
+type volumeReader struct {
+	mu     sync.Mutex
+	reader io.ReaderAt
+	size   int64
+	offset int64
+}
+
+func (self *volumeReader) Close() error {
+	return nil
+}
+
+func (self *volumeReader) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset >= self.size {
+		return 0, io.EOF
+	}
+
+	to_read := buf
+	if offset+int64(len(to_read)) > self.size {
+		to_read = to_read[:self.size-offset]
+	}
+
+	n, err := self.reader.ReadAt(to_read, offset)
+	if err == nil && n < len(buf) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (self *volumeReader) Read(buf []byte) (int, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	n, err := self.ReadAt(buf, self.offset)
+	self.offset += int64(n)
+	return n, err
+}
+
+func (self *volumeReader) Seek(offset int64, whence int) (int64, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+		self.offset = offset
+	case io.SeekCurrent:
+		self.offset += offset
+	case io.SeekEnd:
+		self.offset = self.size + offset
+	default:
+		return 0, fmt.Errorf("Invalid whence %v", whence)
+	}
+
+	if self.offset < 0 {
+		self.offset = 0
+		return 0, os.ErrInvalid
+	}
+	return self.offset, nil
+}
+
+func init() {
+	accessors.Register("lvm", &VolumeFileSystemAccessor{
+		kind: "lvm", parser: ParseLVM,
+	}, `Access logical volumes in LVM2 volume groups.
+
+The delegate refers to the physical volumes (a single path or a JSON
+list of paths). Volumes are named /<volume group>/<logical volume>.
+Linear and striped segments are supported, as well as reading the
+first image of mirrored (raid1) volumes.
+`)
+
+	accessors.Register("mdraid", &VolumeFileSystemAccessor{
+		kind: "mdraid", parser: ParseMDRaid,
+	}, `Access Linux software RAID (md) arrays.
+
+The delegate refers to the member devices (a single path or a JSON
+list of paths). Arrays are named by their array name or UUID.
+Linear, RAID0, RAID1, RAID4/5/6 and RAID10 (near layout) arrays are
+supported. Degraded RAID4/5 arrays with one missing member are
+reconstructed from parity.
+`)
+
+	accessors.Register("ldm", &VolumeFileSystemAccessor{
+		kind: "ldm", parser: ParseLDM,
+	}, `Access volumes on Windows Dynamic Disks (LDM).
+
+The delegate refers to the dynamic disks (a single path or a JSON list
+of paths). Simple, spanned, striped and mirrored volumes are
+supported. Storage Spaces pools are not supported.
+`)
+}
@@ -0,0 +1,515 @@
+package volumes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/file"
+)
+
+// Generate some data which is different in every sector.
+func testData(size int, seed byte) []byte {
+	result := make([]byte, size)
+	for i := range result {
+		result[i] = byte(i/512) + seed + byte(i%7)
+	}
+	return result
+}
+
+func makeDevices(disks ...[]byte) []*Device {
+	var result []*Device
+	for i, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		result = append(result, &Device{
+			Path:   accessors.MustNewLinuxOSPath("/dev/sd" + string(rune('a'+i))),
+			Reader: bytes.NewReader(disk),
+			Size:   int64(len(disk)),
+		})
+	}
+	return result
+}
+
+func readVolume(t *testing.T, volume *LogicalVolume) []byte {
+	assert.NoError(t, volume.Error)
+
+	result := make([]byte, volume.Size)
+	n, err := volume.Reader.ReadAt(result, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, len(result), n)
+
+	return result
+}
+
+const lvmTestMetadata = `
+# Generated by LVM2
+vg0 {
+	id = "vg0id"
+	seqno = 3
+	status = ["RESIZEABLE", "READ", "WRITE"]
+	extent_size = 8
+
+	physical_volumes {
+		pv0 {
+			id = "aaaaaa-aaaa-aaaa-aaaa-aaaa-aaaa-aaaaaa"
+			pe_start = 2048
+			pe_count = 16
+		}
+		pv1 {
+			id = "bbbbbb-bbbb-bbbb-bbbb-bbbb-bbbb-bbbbbb"
+			pe_start = 2048
+			pe_count = 16
+		}
+	}
+
+	logical_volumes {
+		linear {
+			id = "linearid"
+			status = ["READ", "WRITE", "VISIBLE"]
+			creation_host = "test"
+			segment_count = 2
+
+			segment1 {
+				start_extent = 0
+				extent_count = 2
+				type = "striped"
+				stripe_count = 1
+				stripes = [
+					"pv0", 0
+				]
+			}
+			segment2 {
+				start_extent = 2
+				extent_count = 2
+				type = "striped"
+				stripe_count = 1
+				stripes = [
+					"pv1", 0
+				]
+			}
+		}
+
+		striped {
+			id = "stripedid"
+			status = ["READ", "WRITE", "VISIBLE"]
+			segment_count = 1
+
+			segment1 {
+				start_extent = 0
+				extent_count = 4
+				type = "striped"
+				stripe_count = 2
+				stripe_size = 2
+				stripes = [
+					"pv0", 2,
+					"pv1", 2
+				]
+			}
+		}
+	}
+}
+`
+
+func buildLVMPV(uuid string, seed byte) []byte {
+	pe_start := 1024 * 1024
+	result := make([]byte, pe_start)
+	result = append(result, testData(16*4096, seed)...)
+
+	// The label in sector 1
+	label := result[512:]
+	copy(label, "LABELONE")
+	binary.LittleEndian.PutUint64(label[8:], 1)
+	binary.LittleEndian.PutUint32(label[20:], 32)
+	copy(label[24:], "LVM2 001")
+
+	header := label[32:]
+	copy(header, uuid)
+	binary.LittleEndian.PutUint64(header[32:], uint64(len(result)))
+
+	// Data area then metadata area lists.
+	binary.LittleEndian.PutUint64(header[40:], uint64(pe_start))
+	binary.LittleEndian.PutUint64(header[72:], 4096)
+	binary.LittleEndian.PutUint64(header[80:], 64*1024)
+
+	mda := result[4096:]
+	copy(mda[4:], lvmMDAMagic)
+	binary.LittleEndian.PutUint32(mda[20:], 1)
+	binary.LittleEndian.PutUint64(mda[24:], 4096)
+	binary.LittleEndian.PutUint64(mda[32:], 64*1024)
+	binary.LittleEndian.PutUint64(mda[40:], 512)
+	binary.LittleEndian.PutUint64(mda[48:], uint64(len(lvmTestMetadata)))
+	copy(mda[512:], lvmTestMetadata)
+
+	return result
+}
+
+func TestLVM(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volumes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	pv0 := buildLVMPV("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", 1)
+	pv1 := buildLVMPV("bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", 100)
+
+	pv0_path := filepath.Join(dir, "pv0.img")
+	pv1_path := filepath.Join(dir, "pv1.img")
+	assert.NoError(t, ioutil.WriteFile(pv0_path, pv0, 0644))
+	assert.NoError(t, ioutil.WriteFile(pv1_path, pv1, 0644))
+
+	pe_start := 1024 * 1024
+	extent := 4096
+
+	// Linear volume is made of two extents from each PV.
+	expected_linear := append([]byte{},
+		pv0[pe_start:pe_start+2*extent]...)
+	expected_linear = append(expected_linear,
+		pv1[pe_start:pe_start+2*extent]...)
+
+	// Striped volume alternates 1kb chunks.
+	expected_striped := []byte{}
+	for i := 0; i < 16; i++ {
+		pv := pv0
+		if i%2 == 1 {
+			pv = pv1
+		}
+		start := pe_start + 2*extent + (i/2)*1024
+		expected_striped = append(expected_striped, pv[start:start+1024]...)
+	}
+
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+	defer scope.Close()
+
+	accessor, err := accessors.GetAccessor("lvm", scope)
+	assert.NoError(t, err)
+
+	delegate, _ := json.Marshal([]string{pv0_path, pv1_path})
+	pathspec := &accessors.PathSpec{
+		DelegateAccessor: "file",
+		DelegatePath:     string(delegate),
+	}
+
+	// The top level lists the volume groups.
+	children, err := accessor.ReadDir(pathspec.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(children))
+	assert.Equal(t, "vg0", children[0].Name())
+	assert.True(t, children[0].IsDir())
+
+	pathspec.Path = "/vg0"
+	children, err = accessor.ReadDir(pathspec.String())
+	assert.NoError(t, err)
+
+	names := []string{}
+	for _, c := range children {
+		names = append(names, c.Name())
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"linear", "striped"}, names)
+
+	for _, tc := range []struct {
+		name     string
+		expected []byte
+	}{
+		{"linear", expected_linear},
+		{"striped", expected_striped},
+	} {
+		pathspec.Path = "/vg0/" + tc.name
+		stat, err := accessor.Lstat(pathspec.String())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(tc.expected)), stat.Size())
+
+		fd, err := accessor.Open(pathspec.String())
+		assert.NoError(t, err)
+
+		data, err := ioutil.ReadAll(fd)
+		assert.NoError(t, err)
+		fd.Close()
+
+		assert.True(t, bytes.Equal(tc.expected, data), tc.name)
+	}
+}
+
+func buildMDSuperblock(size int, level, layout uint32,
+	chunk_sectors uint64, raid_disks, role int) []byte {
+	data_offset := 16 * 512
+	result := make([]byte, data_offset+size)
+
+	sb := result[4096:]
+	binary.LittleEndian.PutUint32(sb, mdMagic)
+	binary.LittleEndian.PutUint32(sb[4:], 1)
+	copy(sb[16:], "0123456789abcdef")
+	copy(sb[32:], "host:md0")
+	binary.LittleEndian.PutUint32(sb[72:], level)
+	binary.LittleEndian.PutUint32(sb[76:], layout)
+	binary.LittleEndian.PutUint64(sb[80:], uint64(size/512))
+	binary.LittleEndian.PutUint64(sb[88:], chunk_sectors)
+	binary.LittleEndian.PutUint32(sb[92:], uint32(raid_disks))
+	binary.LittleEndian.PutUint64(sb[128:], uint64(data_offset/512))
+	binary.LittleEndian.PutUint64(sb[136:], uint64(size/512))
+	binary.LittleEndian.PutUint32(sb[160:], uint32(role))
+	binary.LittleEndian.PutUint64(sb[200:], 10)
+	binary.LittleEndian.PutUint32(sb[220:], uint32(raid_disks))
+	for i := 0; i < raid_disks; i++ {
+		binary.LittleEndian.PutUint16(sb[256+i*2:], uint16(i))
+	}
+
+	return result
+}
+
+func TestMDRaid1(t *testing.T) {
+	expected := testData(64*1024, 3)
+
+	var disks [][]byte
+	for i := 0; i < 2; i++ {
+		disk := buildMDSuperblock(len(expected), 1, 0, 0, 2, i)
+		copy(disk[16*512:], expected)
+		disks = append(disks, disk)
+	}
+
+	volumes, err := ParseMDRaid(makeDevices(disks...))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(volumes))
+	assert.Equal(t, []string{"host:md0"}, volumes[0].Components)
+	assert.True(t, bytes.Equal(expected, readVolume(t, volumes[0])))
+
+	// A single member is enough to read a mirror.
+	volumes, err = ParseMDRaid(makeDevices(nil, disks[1]))
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(expected, readVolume(t, volumes[0])))
+}
+
+func TestMDRaid5(t *testing.T) {
+	n := 3
+	chunk := 4096
+	member_size := 64 * 1024
+	expected := testData(member_size*(n-1), 7)
+
+	var disks [][]byte
+	for i := 0; i < n; i++ {
+		disks = append(disks, buildMDSuperblock(
+			member_size, 5, mdLeftSymmetric, uint64(chunk/512), n, i))
+	}
+
+	// Distribute the data in a left symmetric layout.
+	for stripe := 0; stripe < member_size/chunk; stripe++ {
+		parity_disk := n - 1 - stripe%n
+		offset := 16*512 + stripe*chunk
+		parity := disks[parity_disk][offset : offset+chunk]
+
+		for k := 0; k < n-1; k++ {
+			data_disk := (parity_disk + 1 + k) % n
+			start := (stripe*(n-1) + k) * chunk
+			data := expected[start : start+chunk]
+			copy(disks[data_disk][offset:], data)
+			for i := range data {
+				parity[i] ^= data[i]
+			}
+		}
+	}
+
+	volumes, err := ParseMDRaid(makeDevices(disks...))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(volumes))
+	assert.Equal(t, int64(len(expected)), volumes[0].Size)
+	assert.True(t, bytes.Equal(expected, readVolume(t, volumes[0])))
+
+	// Any single member may be missing.
+	for missing := 0; missing < n; missing++ {
+		degraded := append([][]byte{}, disks...)
+		degraded[missing] = nil
+
+		volumes, err := ParseMDRaid(makeDevices(degraded...))
+		assert.NoError(t, err)
+
+		missing_disks, _ := volumes[0].Data.Get("MissingDisks")
+		assert.Equal(t, 1, missing_disks)
+		assert.True(t, bytes.Equal(expected, readVolume(t, volumes[0])))
+	}
+}
+
+// Build VBLK records. Each variable length field follows the
+// previous one.
+type vblkBuilder struct {
+	buf []byte
+	r   int
+}
+
+func newVblk(vblk_type, flags byte) *vblkBuilder {
+	result := &vblkBuilder{buf: make([]byte, 128)}
+	copy(result.buf, "VBLK")
+	binary.BigEndian.PutUint16(result.buf[0x0E:], 1)
+	result.buf[0x12] = flags
+	result.buf[0x13] = vblk_type
+	return result
+}
+
+func (self *vblkBuilder) field(base int, data []byte) *vblkBuilder {
+	copy(self.buf[base+self.r:], data)
+	self.r += len(data)
+	return self
+}
+
+func (self *vblkBuilder) fixed(base int, data []byte) *vblkBuilder {
+	copy(self.buf[base+self.r:], data)
+	return self
+}
+
+func vnum(value uint64) []byte {
+	result := binary.BigEndian.AppendUint64(nil, value)
+	for len(result) > 1 && result[0] == 0 {
+		result = result[1:]
+	}
+	return append([]byte{byte(len(result))}, result...)
+}
+
+func vstr(value string) []byte {
+	return append([]byte{byte(len(value))}, value...)
+}
+
+func be64(value uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, value)
+}
+
+func ldmPartitionVblk(id uint64, name string, start, volume_offset,
+	size, parent uint64, index int) []byte {
+	result := newVblk(ldmTypePartition, ldmFlagPartIndex).
+		field(0x18, vnum(id)).
+		field(0x18, vstr(name)).
+		fixed(0x24, be64(start)).
+		fixed(0x2C, be64(volume_offset)).
+		field(0x34, vnum(size)).
+		field(0x34, vnum(parent)).
+		field(0x34, vnum(1))
+	result.buf[0x35+result.r] = byte(index)
+	return result.buf
+}
+
+func ldmComponentVblk(id uint64, name string, comp_type byte,
+	children, parent, chunk uint64) []byte {
+	var flags byte
+	if chunk > 0 {
+		flags = ldmFlagCompStripe
+	}
+
+	result := newVblk(ldmTypeComponent, flags).
+		field(0x18, vnum(id)).
+		field(0x18, vstr(name)).
+		field(0x18, vstr("ACTIVE")).
+		fixed(0x18, []byte{comp_type}).
+		field(0x1D, vnum(children)).
+		field(0x2D, vnum(parent))
+	if chunk > 0 {
+		result.field(0x2E, vnum(chunk))
+	}
+	return result.buf
+}
+
+func ldmVolumeVblk(id uint64, name string, size uint64) []byte {
+	return newVblk(ldmTypeVolume, 0).
+		field(0x18, vnum(id)).
+		field(0x18, vstr(name)).
+		field(0x18, vstr("gen")).
+		field(0x18, vstr("")).
+		field(0x2D, vnum(1)).
+		field(0x3D, vnum(size)).buf
+}
+
+func TestLDM(t *testing.T) {
+	disk_guid := "01234567-89ab-cdef-0123-456789abcdef"
+	disk := testData(4*1024*1024, 5)
+
+	head := disk[6*512 : 7*512]
+	for i := range head {
+		head[i] = 0
+	}
+	copy(head, "PRIVHEAD")
+	copy(head[0x30:], disk_guid)
+	copy(head[0x11B:], be64(64))
+	copy(head[0x12B:], be64(6144))
+	copy(head[0x133:], be64(2048))
+
+	config := disk[6144*512:]
+	for i := range config {
+		config[i] = 0
+	}
+
+	vmdb := config[17*512:]
+	copy(vmdb, "VMDB")
+	binary.BigEndian.PutUint32(vmdb[0x04:], 5)
+	binary.BigEndian.PutUint32(vmdb[0x08:], 128)
+	binary.BigEndian.PutUint32(vmdb[0x0C:], 512)
+
+	vblks := [][]byte{
+		newVblk(ldmTypeDisk3, 0).
+			field(0x18, vnum(1)).
+			field(0x18, vstr("Disk1")).
+			field(0x18, vstr(disk_guid)).buf,
+
+		// A spanned volume with two partitions stored out of order.
+		ldmVolumeVblk(10, "Volume1", 200),
+		ldmComponentVblk(11, "Volume1-01", ldmComponentSpanned, 2, 10, 0),
+		ldmPartitionVblk(13, "Disk1-02", 400, 100, 100, 11, 0),
+		ldmPartitionVblk(12, "Disk1-01", 100, 0, 100, 11, 0),
+
+		// A striped volume with 16 sector chunks.
+		ldmVolumeVblk(20, "Volume2", 128),
+		ldmComponentVblk(21, "Volume2-01", ldmComponentStriped, 2, 20, 16),
+		ldmPartitionVblk(23, "Disk1-04", 1200, 0, 64, 21, 1),
+		ldmPartitionVblk(22, "Disk1-03", 1000, 0, 64, 21, 0),
+	}
+
+	for i, vblk := range vblks {
+		binary.BigEndian.PutUint32(vblk[0x04:], uint32(i+1))
+		binary.BigEndian.PutUint32(vblk[0x08:], uint32(i+1))
+		copy(vmdb[512+i*128:], vblk)
+	}
+
+	sector := func(s int) int { return (64 + s) * 512 }
+
+	expected_spanned := append([]byte{},
+		disk[sector(100):sector(200)]...)
+	expected_spanned = append(expected_spanned,
+		disk[sector(400):sector(500)]...)
+
+	expected_striped := []byte{}
+	for i := 0; i < 8; i++ {
+		start := 1000 + (i/2)*16
+		if i%2 == 1 {
+			start += 200
+		}
+		expected_striped = append(expected_striped,
+			disk[sector(start):sector(start+16)]...)
+	}
+
+	volumes, err := ParseLDM(makeDevices(disk))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(volumes))
+
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Components[0] < volumes[j].Components[0]
+	})
+
+	assert.Equal(t, []string{"Volume1"}, volumes[0].Components)
+	layout, _ := volumes[0].Data.Get("Layout")
+	assert.Equal(t, "spanned", layout)
+	assert.True(t, bytes.Equal(expected_spanned, readVolume(t, volumes[0])))
+
+	assert.Equal(t, []string{"Volume2"}, volumes[1].Components)
+	layout, _ = volumes[1].Data.Get("Layout")
+	assert.Equal(t, "stripe", layout)
+	assert.True(t, bytes.Equal(expected_striped, readVolume(t, volumes[1])))
+}
@@ -45,26 +45,10 @@ func parseOSPath(ctx context.Context,
 		return accessor.ParsePath(t.String())
 
 	case api.FSPathSpec:
-		// Create an OSPath to represent the abstract filestore path.
-		// Restore the file extension from the filestore abstract
-		// pathspec.
-		components := utils.CopySlice(t.Components())
-		if len(components) > 0 {
-			last_idx := len(components) - 1
-			components[last_idx] += api.GetExtensionForFilestore(t)
-		}
-		return MustNewFileStorePath("fs:").Append(components...), nil
+		return fsPathSpecToOSPath(t), nil
 
 	case api.DSPathSpec:
-		// Create an OSPath to represent the abstract filestore path.
-		// Restore the file extension from the filestore abstract
-		// pathspec.
-		components := utils.CopySlice(t.Components())
-		if len(components) > 0 {
-			last_idx := len(components) - 1
-			components[last_idx] += api.GetExtensionForDatastore(t)
-		}
-		return MustNewFileStorePath("ds:").Append(components...), nil
+		return dsPathSpecToOSPath(t), nil
 
 		// WHERE version(plugin="glob") > 2:
 		// Initializer can be a list of components. In this case we
@@ -126,6 +110,12 @@ func ParseOSPath(ctx context.Context,
 	case PathSpec:
 		return accessor.ParsePath(t.String())
 
+	case api.FSPathSpec:
+		return fsPathSpecToOSPath(t), nil
+
+	case api.DSPathSpec:
+		return dsPathSpecToOSPath(t), nil
+
 	case string:
 		return accessor.ParsePath(t)
 
@@ -137,6 +127,26 @@ func ParseOSPath(ctx context.Context,
 	}
 }
 
+// Create an OSPath to represent the abstract filestore path. Restore
+// the file extension from the filestore abstract pathspec.
+func fsPathSpecToOSPath(path_spec api.FSPathSpec) *OSPath {
+	components := utils.CopySlice(path_spec.Components())
+	if len(components) > 0 {
+		last_idx := len(components) - 1
+		components[last_idx] += api.GetExtensionForFilestore(path_spec)
+	}
+	return MustNewFileStorePath("fs:").Append(components...)
+}
+
+func dsPathSpecToOSPath(path_spec api.DSPathSpec) *OSPath {
+	components := utils.CopySlice(path_spec.Components())
+	if len(components) > 0 {
+		last_idx := len(components) - 1
+		components[last_idx] += api.GetExtensionForDatastore(path_spec)
+	}
+	return MustNewFileStorePath("ds:").Append(components...)
+}
+
 func parseOSPathArray(ctx context.Context,
 	scope types.Scope, args *ordereddict.Dict,
 	value interface{}) (interface{}, error) {
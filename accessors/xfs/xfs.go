@@ -0,0 +1,763 @@
+package xfs
+
+// A read only parser for the XFS filesystem.
+//
+// XFS divides the filesystem into allocation groups. Inode numbers
+// and block numbers encode the allocation group they belong to. File
+// data is described by extents which are stored either directly in
+// the inode or in a B+tree when there are too many to fit.
+//
+// Both V4 and V5 (CRC enabled) filesystems are supported.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// Inode data fork formats
+	xfsFormatDevice  = 0
+	xfsFormatLocal   = 1
+	xfsFormatExtents = 2
+	xfsFormatBtree   = 3
+
+	xfsInodeCoreSizeV2 = 100
+	xfsInodeCoreSizeV3 = 176
+
+	xfsBmapHeaderSizeV4 = 24
+	xfsBmapHeaderSizeV5 = 72
+
+	xfsDirHeaderSizeV4 = 16
+	xfsDirHeaderSizeV5 = 64
+
+	xfsSymlinkHeaderSize = 56
+
+	// Directory data blocks are stored before this byte offset.
+	xfsDirLeafOffset = 32 * 1024 * 1024 * 1024
+
+	xfsSBVersion2Ftype     = 0x200
+	xfsSBFeatIncompatFtype = 0x1
+	xfsDiflag2Bigtime      = 0x8
+
+	xfsMaxBtreeDepth     = 8
+	xfsMaxDirectorySize  = 64 * 1024 * 1024
+	xfsMaxExtents        = 1024 * 1024
+	xfsBigtimeEpochDelta = 1 << 31
+)
+
+var (
+	fsNotFoundError = errors.New("file not found")
+)
+
+type XFSContext struct {
+	reader io.ReaderAt
+
+	version        int
+	block_size     int64
+	ag_blocks      int64
+	ag_count       uint32
+	inode_size     int64
+	inopblog       uint
+	agblklog       uint
+	dir_block_size int64
+	root_ino       uint64
+	has_ftype      bool
+
+	Label string
+	UUID  string
+}
+
+func NewXFSContext(reader io.ReaderAt) (*XFSContext, error) {
+	sb := make([]byte, 512)
+	n, err := reader.ReadAt(sb, 0)
+	if n < len(sb) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	if string(sb[:4]) != "XFSB" {
+		return nil, errors.New("xfs: invalid superblock magic")
+	}
+
+	result := &XFSContext{
+		reader:     reader,
+		version:    int(binary.BigEndian.Uint16(sb[100:]) & 0xf),
+		block_size: int64(binary.BigEndian.Uint32(sb[4:])),
+		root_ino:   binary.BigEndian.Uint64(sb[56:]),
+		ag_blocks:  int64(binary.BigEndian.Uint32(sb[84:])),
+		ag_count:   binary.BigEndian.Uint32(sb[88:]),
+		inode_size: int64(binary.BigEndian.Uint16(sb[104:])),
+		Label:      string(bytes.TrimRight(sb[108:120], "\x00")),
+		UUID:       formatUUID(sb[32:48]),
+		inopblog:   uint(sb[123]),
+		agblklog:   uint(sb[124]),
+	}
+
+	result.dir_block_size = result.block_size << uint(sb[192])
+
+	if result.version == 5 {
+		result.has_ftype = binary.BigEndian.Uint32(sb[216:])&
+			xfsSBFeatIncompatFtype != 0
+	} else {
+		result.has_ftype = binary.BigEndian.Uint32(sb[200:])&
+			xfsSBVersion2Ftype != 0
+	}
+
+	if result.block_size < 512 || result.block_size > 65536 ||
+		result.inode_size < 256 || result.inode_size > result.block_size ||
+		result.ag_blocks == 0 || result.agblklog > 32 || result.inopblog > 8 ||
+		result.dir_block_size > 65536 {
+		return nil, errors.New("xfs: invalid superblock")
+	}
+
+	return result, nil
+}
+
+// Convert a filesystem block number to a byte offset.
+func (self *XFSContext) fsblockOffset(fsblock uint64) int64 {
+	agno := int64(fsblock >> self.agblklog)
+	agbno := int64(fsblock & (1<<self.agblklog - 1))
+	return (agno*self.ag_blocks + agbno) * self.block_size
+}
+
+func (self *XFSContext) readAt(buf []byte, offset int64) error {
+	n, err := self.reader.ReadAt(buf, offset)
+	if n < len(buf) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return nil
+}
+
+func (self *XFSContext) GetInode(number uint64) (*Inode, error) {
+	agno := number >> (self.agblklog + self.inopblog)
+	agbno := (number >> self.inopblog) & (1<<self.agblklog - 1)
+	index := number & (1<<self.inopblog - 1)
+
+	if agno >= uint64(self.ag_count) {
+		return nil, fmt.Errorf("xfs: invalid inode number %v", number)
+	}
+
+	buf := make([]byte, self.inode_size)
+	offset := (int64(agno)*self.ag_blocks+int64(agbno))*self.block_size +
+		int64(index)*self.inode_size
+	err := self.readAt(buf, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(buf[:2]) != "IN" {
+		return nil, fmt.Errorf("xfs: invalid inode %v", number)
+	}
+
+	return newInode(self, number, buf), nil
+}
+
+// Walk the directory tree from the root to find the inode.
+func (self *XFSContext) OpenComponents(components []string) (*Inode, error) {
+	inode, err := self.GetInode(self.root_ino)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range components {
+		if !inode.IsDir() {
+			return nil, fsNotFoundError
+		}
+
+		entries, err := self.ReadDir(inode)
+		if err != nil {
+			return nil, err
+		}
+
+		var next uint64
+		for _, entry := range entries {
+			if entry.Name == component {
+				next = entry.Inode
+				break
+			}
+		}
+
+		if next == 0 {
+			return nil, fsNotFoundError
+		}
+
+		inode, err = self.GetInode(next)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return inode, nil
+}
+
+type DirEntry struct {
+	Inode    uint64
+	Name     string
+	FileType uint8
+}
+
+func (self *XFSContext) ReadDir(inode *Inode) ([]*DirEntry, error) {
+	if !inode.IsDir() {
+		return nil, errors.New("xfs: not a directory")
+	}
+
+	switch inode.Format {
+	case xfsFormatLocal:
+		return self.readShortformDir(inode.dataFork())
+
+	case xfsFormatExtents, xfsFormatBtree:
+		return self.readBlockDir(inode)
+	}
+
+	return nil, fmt.Errorf("xfs: unsupported directory format %v", inode.Format)
+}
+
+// Small directories are stored within the inode.
+func (self *XFSContext) readShortformDir(buf []byte) ([]*DirEntry, error) {
+	if len(buf) < 6 {
+		return nil, errors.New("xfs: invalid short form directory")
+	}
+
+	count := int(buf[0])
+	ino_size := 4
+	if buf[1] > 0 {
+		count = int(buf[1])
+		ino_size = 8
+	}
+
+	getIno := func(b []byte) uint64 {
+		if ino_size == 8 {
+			return binary.BigEndian.Uint64(b)
+		}
+		return uint64(binary.BigEndian.Uint32(b))
+	}
+
+	result := []*DirEntry{{
+		Inode: getIno(buf[2:]),
+		Name:  "..",
+	}}
+
+	pos := 2 + ino_size
+	for i := 0; i < count; i++ {
+		if pos+3 > len(buf) {
+			break
+		}
+
+		name_len := int(buf[pos])
+		name_start := pos + 3
+		end := name_start + name_len
+		entry := &DirEntry{}
+		if self.has_ftype {
+			if end >= len(buf) {
+				break
+			}
+			entry.FileType = buf[end]
+			end++
+		}
+
+		if end+ino_size > len(buf) {
+			break
+		}
+
+		entry.Name = string(buf[name_start : name_start+name_len])
+		entry.Inode = getIno(buf[end:])
+		result = append(result, entry)
+
+		pos = end + ino_size
+	}
+
+	return result, nil
+}
+
+// Larger directories are stored in directory blocks.
+func (self *XFSContext) readBlockDir(inode *Inode) ([]*DirEntry, error) {
+	reader, err := inode.Reader()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the data blocks contain entries. The leaf and free
+	// index blocks are stored at higher offsets.
+	end := inode.Size
+	if end > xfsDirLeafOffset {
+		end = xfsDirLeafOffset
+	}
+	if end > xfsMaxDirectorySize {
+		end = xfsMaxDirectorySize
+	}
+
+	var result []*DirEntry
+	buf := make([]byte, self.dir_block_size)
+	for offset := int64(0); offset < end; offset += self.dir_block_size {
+		// Skip holes in the directory.
+		if !reader.isMapped(offset) {
+			continue
+		}
+
+		n, err := reader.ReadAt(buf, offset)
+		if n < len(buf) {
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+
+		self.parseDirBlock(buf, &result)
+	}
+
+	return result, nil
+}
+
+func (self *XFSContext) parseDirBlock(buf []byte, result *[]*DirEntry) {
+	magic := string(buf[:4])
+	start := xfsDirHeaderSizeV4
+	end := len(buf)
+
+	switch magic {
+	case "XD2B", "XD2D":
+	case "XDB3", "XDD3":
+		start = xfsDirHeaderSizeV5
+	default:
+		return
+	}
+
+	// Single block directories have the leaf entries at the end of
+	// the block.
+	if magic == "XD2B" || magic == "XDB3" {
+		count := int(binary.BigEndian.Uint32(buf[len(buf)-8:]))
+		end = len(buf) - 8 - count*8
+		if end < start {
+			return
+		}
+	}
+
+	for pos := start; pos+16 <= end; {
+		// Unused entries are marked with a free tag.
+		if binary.BigEndian.Uint16(buf[pos:]) == 0xffff {
+			length := int(binary.BigEndian.Uint16(buf[pos+2:]))
+			if length < 8 || length%8 != 0 {
+				return
+			}
+			pos += length
+			continue
+		}
+
+		ino := binary.BigEndian.Uint64(buf[pos:])
+		name_len := int(buf[pos+8])
+		size := 8 + 1 + name_len + 2
+		entry := &DirEntry{
+			Inode: ino,
+		}
+
+		if self.has_ftype {
+			size++
+			if pos+9+name_len < end {
+				entry.FileType = buf[pos+9+name_len]
+			}
+		}
+
+		if pos+9+name_len > end {
+			return
+		}
+		entry.Name = string(buf[pos+9 : pos+9+name_len])
+		*result = append(*result, entry)
+
+		pos += (size + 7) &^ 7
+	}
+}
+
+type Inode struct {
+	ctx *XFSContext
+	raw []byte
+
+	Number   uint64
+	Mode     uint16
+	Format   uint8
+	Version  uint8
+	Uid      uint32
+	Gid      uint32
+	Nlink    uint32
+	Size     int64
+	Nextents uint32
+	Forkoff  uint8
+	Flags2   uint64
+
+	Atime time.Time
+	Mtime time.Time
+	Ctime time.Time
+	Btime time.Time
+}
+
+func newInode(ctx *XFSContext, number uint64, raw []byte) *Inode {
+	result := &Inode{
+		ctx:      ctx,
+		raw:      raw,
+		Number:   number,
+		Mode:     binary.BigEndian.Uint16(raw[2:]),
+		Version:  raw[4],
+		Format:   raw[5],
+		Uid:      binary.BigEndian.Uint32(raw[8:]),
+		Gid:      binary.BigEndian.Uint32(raw[12:]),
+		Nlink:    binary.BigEndian.Uint32(raw[16:]),
+		Size:     int64(binary.BigEndian.Uint64(raw[56:])),
+		Nextents: binary.BigEndian.Uint32(raw[76:]),
+		Forkoff:  raw[82],
+	}
+
+	if result.Version < 2 {
+		result.Nlink = uint32(binary.BigEndian.Uint16(raw[6:]))
+	}
+
+	if result.Version >= 3 {
+		result.Flags2 = binary.BigEndian.Uint64(raw[120:])
+	}
+
+	timestamp := func(offset int) time.Time {
+		if result.Flags2&xfsDiflag2Bigtime != 0 {
+			ns := binary.BigEndian.Uint64(raw[offset:])
+			if ns == 0 {
+				return time.Time{}
+			}
+			return time.Unix(int64(ns/1e9)-xfsBigtimeEpochDelta,
+				int64(ns%1e9)).UTC()
+		}
+
+		sec := int64(int32(binary.BigEndian.Uint32(raw[offset:])))
+		nsec := int64(binary.BigEndian.Uint32(raw[offset+4:]))
+		if sec == 0 && nsec == 0 {
+			return time.Time{}
+		}
+		return time.Unix(sec, nsec).UTC()
+	}
+
+	result.Atime = timestamp(32)
+	result.Mtime = timestamp(40)
+	result.Ctime = timestamp(48)
+	if result.Version >= 3 {
+		result.Btime = timestamp(144)
+	}
+
+	return result
+}
+
+func (self *Inode) coreSize() int {
+	if self.Version >= 3 {
+		return xfsInodeCoreSizeV3
+	}
+	return xfsInodeCoreSizeV2
+}
+
+// The data fork follows the inode core and extends to the attribute
+// fork if there is one.
+func (self *Inode) dataFork() []byte {
+	start := self.coreSize()
+	end := len(self.raw)
+	if self.Forkoff > 0 && start+int(self.Forkoff)*8 < end {
+		end = start + int(self.Forkoff)*8
+	}
+	return self.raw[start:end]
+}
+
+func (self *Inode) IsDir() bool {
+	return self.Mode&0xF000 == 0x4000
+}
+
+func (self *Inode) IsLink() bool {
+	return self.Mode&0xF000 == 0xA000
+}
+
+func (self *Inode) FileMode() os.FileMode {
+	result := os.FileMode(self.Mode & 0777)
+	switch self.Mode & 0xF000 {
+	case 0x4000:
+		result |= os.ModeDir
+	case 0xA000:
+		result |= os.ModeSymlink
+	case 0x1000:
+		result |= os.ModeNamedPipe
+	case 0xC000:
+		result |= os.ModeSocket
+	case 0x2000:
+		result |= os.ModeDevice | os.ModeCharDevice
+	case 0x6000:
+		result |= os.ModeDevice
+	}
+	return result
+}
+
+// The target of a symbolic link.
+func (self *Inode) LinkTarget() (string, error) {
+	if !self.IsLink() {
+		return "", errors.New("xfs: not a symlink")
+	}
+
+	size := self.Size
+	if size > 4096 {
+		size = 4096
+	}
+
+	if self.Format == xfsFormatLocal {
+		fork := self.dataFork()
+		if int(size) > len(fork) {
+			return "", errors.New("xfs: invalid symlink")
+		}
+		return string(fork[:size]), nil
+	}
+
+	reader, err := self.Reader()
+	if err != nil {
+		return "", err
+	}
+
+	// V5 remote symlink blocks start with a header.
+	offset := int64(0)
+	if self.ctx.version == 5 {
+		offset = xfsSymlinkHeaderSize
+	}
+
+	buf := make([]byte, size)
+	n, err := reader.readRaw(buf, offset)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return string(buf[:n]), nil
+}
+
+type extent struct {
+	logical  int64
+	physical uint64
+	length   int64
+
+	// Unwritten extents read as zeros
+	unwritten bool
+}
+
+func parseExtent(buf []byte) extent {
+	l0 := binary.BigEndian.Uint64(buf)
+	l1 := binary.BigEndian.Uint64(buf[8:])
+	return extent{
+		unwritten: l0>>63 != 0,
+		logical:   int64((l0 & (1<<63 - 1)) >> 9),
+		physical:  (l0&(1<<9-1))<<43 | l1>>21,
+		length:    int64(l1 & (1<<21 - 1)),
+	}
+}
+
+func (self *Inode) extents() ([]extent, error) {
+	fork := self.dataFork()
+
+	switch self.Format {
+	case xfsFormatExtents:
+		var result []extent
+		for i := 0; i < int(self.Nextents) && (i+1)*16 <= len(fork); i++ {
+			result = append(result, parseExtent(fork[i*16:]))
+		}
+		return result, nil
+
+	case xfsFormatBtree:
+		if len(fork) < 4 {
+			return nil, errors.New("xfs: invalid btree root")
+		}
+
+		level := binary.BigEndian.Uint16(fork)
+		numrecs := int(binary.BigEndian.Uint16(fork[2:]))
+		maxrecs := (len(fork) - 4) / 16
+		if level == 0 || numrecs > maxrecs {
+			return nil, errors.New("xfs: invalid btree root")
+		}
+
+		var result []extent
+		ptrs := fork[4+maxrecs*8:]
+		for i := 0; i < numrecs; i++ {
+			err := self.ctx.walkBtree(binary.BigEndian.Uint64(ptrs[i*8:]),
+				1, &result)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("xfs: unsupported data fork format %v", self.Format)
+}
+
+func (self *XFSContext) walkBtree(fsblock uint64, depth int,
+	result *[]extent) error {
+	if depth > xfsMaxBtreeDepth {
+		return errors.New("xfs: btree too deep")
+	}
+
+	buf := make([]byte, self.block_size)
+	err := self.readAt(buf, self.fsblockOffset(fsblock))
+	if err != nil {
+		return err
+	}
+
+	header_size := xfsBmapHeaderSizeV4
+	switch string(buf[:4]) {
+	case "BMAP":
+	case "BMA3":
+		header_size = xfsBmapHeaderSizeV5
+	default:
+		return errors.New("xfs: invalid btree block")
+	}
+
+	level := binary.BigEndian.Uint16(buf[4:])
+	numrecs := int(binary.BigEndian.Uint16(buf[6:]))
+	maxrecs := (len(buf) - header_size) / 16
+	if numrecs > maxrecs {
+		return errors.New("xfs: invalid btree block")
+	}
+
+	if level == 0 {
+		for i := 0; i < numrecs; i++ {
+			if len(*result) > xfsMaxExtents {
+				return errors.New("xfs: too many extents")
+			}
+			*result = append(*result, parseExtent(buf[header_size+i*16:]))
+		}
+		return nil
+	}
+
+	ptrs := buf[header_size+maxrecs*8:]
+	for i := 0; i < numrecs; i++ {
+		err := self.walkBtree(binary.BigEndian.Uint64(ptrs[i*8:]),
+			depth+1, result)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (self *Inode) Reader() (*InodeReader, error) {
+	result := &InodeReader{
+		ctx:  self.ctx,
+		size: self.Size,
+	}
+
+	if self.Format == xfsFormatLocal {
+		fork := self.dataFork()
+		if int64(len(fork)) > self.Size {
+			fork = fork[:self.Size]
+		}
+		result.local = append([]byte{}, fork...)
+		return result, nil
+	}
+
+	extents, err := self.extents()
+	if err != nil {
+		return nil, err
+	}
+	result.extents = extents
+
+	return result, nil
+}
+
+// Read the content of an inode.
+type InodeReader struct {
+	ctx     *XFSContext
+	size    int64
+	local   []byte
+	extents []extent
+}
+
+func (self *InodeReader) Size() int64 {
+	return self.size
+}
+
+func (self *InodeReader) findExtent(block int64) *extent {
+	for i := range self.extents {
+		e := &self.extents[i]
+		if block >= e.logical && block < e.logical+e.length {
+			return e
+		}
+	}
+	return nil
+}
+
+func (self *InodeReader) isMapped(offset int64) bool {
+	return self.findExtent(offset/self.ctx.block_size) != nil
+}
+
+func (self *InodeReader) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset >= self.size {
+		return 0, io.EOF
+	}
+
+	to_read := buf
+	if offset+int64(len(to_read)) > self.size {
+		to_read = to_read[:self.size-offset]
+	}
+
+	n, err := self.readRaw(to_read, offset)
+	if err != nil {
+		return n, err
+	}
+
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read ignoring the file size.
+func (self *InodeReader) readRaw(buf []byte, offset int64) (int, error) {
+	if self.local != nil {
+		if offset >= int64(len(self.local)) {
+			return 0, io.EOF
+		}
+		return copy(buf, self.local[offset:]), nil
+	}
+
+	block_size := self.ctx.block_size
+	n := 0
+	for n < len(buf) {
+		current := offset + int64(n)
+		block := current / block_size
+		block_offset := current % block_size
+
+		length := block_size - block_offset
+		e := self.findExtent(block)
+		if e != nil {
+			length = (e.logical+e.length-block)*block_size - block_offset
+		}
+
+		if length > int64(len(buf)-n) {
+			length = int64(len(buf) - n)
+		}
+
+		chunk := buf[n : n+int(length)]
+
+		// Holes and unwritten extents read as zeros.
+		if e == nil || e.unwritten {
+			for i := range chunk {
+				chunk[i] = 0
+			}
+		} else {
+			disk_offset := self.ctx.fsblockOffset(e.physical) +
+				(block-e.logical)*block_size + block_offset
+			_, err := self.ctx.reader.ReadAt(chunk, disk_offset)
+			if err != nil && err != io.EOF {
+				return n, err
+			}
+		}
+		n += int(length)
+	}
+
+	return n, nil
+}
+
+func formatUUID(b []byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
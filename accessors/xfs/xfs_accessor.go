@@ -0,0 +1,358 @@
+package xfs
+
+// This is an accessor which parses an XFS filesystem
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type XFSFileInfo struct {
+	inode      *Inode
+	name       string
+	_full_path *accessors.OSPath
+}
+
+func (self *XFSFileInfo) IsDir() bool {
+	return self.inode.IsDir()
+}
+
+func (self *XFSFileInfo) Size() int64 {
+	return self.inode.Size
+}
+
+func (self *XFSFileInfo) Data() *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("inode", self.inode.Number).
+		Set("mode", self.inode.FileMode().String()).
+		Set("uid", self.inode.Uid).
+		Set("gid", self.inode.Gid).
+		Set("links", self.inode.Nlink)
+
+	if self.inode.IsLink() {
+		target, err := self.inode.LinkTarget()
+		if err == nil {
+			result.Set("link", target)
+		}
+	}
+
+	return result
+}
+
+func (self *XFSFileInfo) Name() string {
+	return self.name
+}
+
+func (self *XFSFileInfo) UniqueName() string {
+	return self._full_path.String()
+}
+
+func (self *XFSFileInfo) Mode() os.FileMode {
+	return self.inode.FileMode()
+}
+
+func (self *XFSFileInfo) ModTime() time.Time {
+	return self.inode.Mtime
+}
+
+func (self *XFSFileInfo) FullPath() string {
+	return self._full_path.String()
+}
+
+func (self *XFSFileInfo) OSPath() *accessors.OSPath {
+	return self._full_path
+}
+
+func (self *XFSFileInfo) Btime() time.Time {
+	return self.inode.Btime
+}
+
+func (self *XFSFileInfo) Mtime() time.Time {
+	return self.inode.Mtime
+}
+
+func (self *XFSFileInfo) Ctime() time.Time {
+	return self.inode.Ctime
+}
+
+func (self *XFSFileInfo) Atime() time.Time {
+	return self.inode.Atime
+}
+
+// Symlinks are not followed - the target is available in the Data
+// field.
+func (self *XFSFileInfo) IsLink() bool {
+	return false
+}
+
+func (self *XFSFileInfo) GetLink() (*accessors.OSPath, error) {
+	return nil, errors.New("Not implemented")
+}
+
+type XFSFileSystemAccessor struct {
+	scope vfilter.Scope
+
+	// The delegate accessor we use to open the underlying volume.
+	accessor string
+	device   *accessors.OSPath
+
+	root *accessors.OSPath
+}
+
+func NewXFSFileSystemAccessor(
+	scope vfilter.Scope,
+	root_path *accessors.OSPath,
+	device *accessors.OSPath, accessor string) *XFSFileSystemAccessor {
+	return &XFSFileSystemAccessor{
+		scope:    scope,
+		accessor: accessor,
+		device:   device,
+		root:     root_path,
+	}
+}
+
+func (self XFSFileSystemAccessor) New(scope vfilter.Scope) (
+	accessors.FileSystemAccessor, error) {
+	// Create a new cache in the scope.
+	return &XFSFileSystemAccessor{
+		scope:    scope,
+		device:   self.device,
+		accessor: self.accessor,
+		root:     self.root,
+	}, nil
+}
+
+func (self XFSFileSystemAccessor) ParsePath(path string) (
+	*accessors.OSPath, error) {
+	return accessors.NewLinuxOSPath(path)
+}
+
+func (self *XFSFileSystemAccessor) ReadDir(path string) (
+	res []accessors.FileInfo, err error) {
+	// Normalize the path
+	fullpath, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.ReadDirWithOSPath(fullpath)
+}
+
+func (self *XFSFileSystemAccessor) ReadDirWithOSPath(
+	fullpath *accessors.OSPath) (res []accessors.FileInfo, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	result := []accessors.FileInfo{}
+
+	xfs_ctx, err := GetXFSContext(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := xfs_ctx.OpenComponents(fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := xfs_ctx.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// List the directory.
+	for _, entry := range entries {
+		// Skip these useless directories.
+		if entry.Name == "." || entry.Name == ".." {
+			continue
+		}
+
+		inode, err := xfs_ctx.GetInode(entry.Inode)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, &XFSFileInfo{
+			inode:      inode,
+			name:       entry.Name,
+			_full_path: fullpath.Append(entry.Name),
+		})
+	}
+	return result, nil
+}
+
+// Adapt the inode reader to a ReadSeekCloser
+type readAdapter struct {
+	sync.Mutex
+
+	pos    int64
+	reader *InodeReader
+}
+
+func (self *readAdapter) Read(buf []byte) (res int, err error) {
+	self.Lock()
+	defer self.Unlock()
+
+	res, err = self.reader.ReadAt(buf, self.pos)
+	self.pos += int64(res)
+
+	// Short reads are not errors as long as we read something.
+	if res > 0 && errors.Is(err, io.EOF) {
+		err = nil
+	}
+
+	return res, err
+}
+
+func (self *readAdapter) ReadAt(buf []byte, offset int64) (int, error) {
+	return self.reader.ReadAt(buf, offset)
+}
+
+func (self *readAdapter) Close() error {
+	return nil
+}
+
+func (self *readAdapter) Seek(offset int64, whence int) (int64, error) {
+	self.Lock()
+	defer self.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+		self.pos = offset
+	case io.SeekCurrent:
+		self.pos += offset
+	case io.SeekEnd:
+		self.pos = self.reader.Size() + offset
+	}
+
+	if self.pos < 0 {
+		self.pos = 0
+		return 0, os.ErrInvalid
+	}
+	return self.pos, nil
+}
+
+func (self *XFSFileSystemAccessor) Open(
+	path string) (res accessors.ReadSeekCloser, err error) {
+
+	full_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.OpenWithOSPath(full_path)
+}
+
+func (self *XFSFileSystemAccessor) OpenWithOSPath(
+	fullpath *accessors.OSPath) (res accessors.ReadSeekCloser, err error) {
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	xfs_ctx, err := GetXFSContext(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	inode, err := xfs_ctx.OpenComponents(fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	if inode.IsDir() {
+		return nil, errors.New("xfs: Can not open a directory")
+	}
+
+	reader, err := inode.Reader()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readAdapter{reader: reader}, nil
+}
+
+func (self *XFSFileSystemAccessor) Lstat(
+	path string) (res accessors.FileInfo, err error) {
+
+	fullpath, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.LstatWithOSPath(fullpath)
+}
+
+func (self *XFSFileSystemAccessor) LstatWithOSPath(
+	fullpath *accessors.OSPath) (res accessors.FileInfo, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			fmt.Printf("PANIC %v\n", r)
+			debug.PrintStack()
+			err, _ = r.(error)
+		}
+	}()
+
+	xfs_ctx, err := GetXFSContext(self.scope, self.device, fullpath, self.accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	inode, err := xfs_ctx.OpenComponents(fullpath.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	return &XFSFileInfo{
+		inode:      inode,
+		name:       fullpath.Basename(),
+		_full_path: fullpath,
+	}, nil
+}
+
+func init() {
+	accessors.Register("xfs", &XFSFileSystemAccessor{},
+		`Access the XFS filesystem inside an image by parsing the raw filesystem.
+
+This accessor is designed to operate on images or raw block devices
+directly and does not require the filesystem to be mounted. It
+requires a delegate accessor to get the raw image and will open files
+using the full path rooted at the top of the filesystem.
+
+## Example
+
+The following query will glob all the files under the /etc directory
+inside an XFS image file
+
+SELECT *
+FROM glob(globs='/etc/**',
+  accessor="xfs",
+  root=pathspec(
+    DelegateAccessor="file",
+    DelegatePath='xfs.dd'))
+
+`)
+
+	json.RegisterCustomEncoder(&XFSFileInfo{}, accessors.MarshalGlobFileInfo)
+}
@@ -0,0 +1,220 @@
+package xfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/file"
+)
+
+const (
+	testBlockSize = 4096
+	testInodeSize = 512
+)
+
+// Generate some data which is different in every sector.
+func testData(size int, seed byte) []byte {
+	result := make([]byte, size)
+	for i := range result {
+		result[i] = byte(i/512) + seed + byte(i%7)
+	}
+	return result
+}
+
+func encodeExtent(logical, physical, length uint64) []byte {
+	result := binary.BigEndian.AppendUint64(nil, logical<<9|physical>>43)
+	return binary.BigEndian.AppendUint64(result, physical<<21|length)
+}
+
+// Build a small V5 filesystem with a single allocation group. Inodes
+// are allocated in block 8 starting with the root inode 64.
+type xfsBuilder struct {
+	image []byte
+}
+
+func (self *xfsBuilder) block(n int) []byte {
+	return self.image[n*testBlockSize : (n+1)*testBlockSize]
+}
+
+func (self *xfsBuilder) inode(number int, mode uint16, format uint8,
+	size int64, nextents uint32) []byte {
+	raw := self.image[8*testBlockSize+(number-64)*testInodeSize:][:testInodeSize]
+	copy(raw, "IN")
+	binary.BigEndian.PutUint16(raw[2:], mode)
+	raw[4] = 3
+	raw[5] = format
+	binary.BigEndian.PutUint32(raw[16:], 1)
+	binary.BigEndian.PutUint32(raw[40:], 1698832800)
+	binary.BigEndian.PutUint64(raw[56:], uint64(size))
+	binary.BigEndian.PutUint32(raw[76:], nextents)
+	binary.BigEndian.PutUint64(raw[152:], uint64(number))
+	return raw[xfsInodeCoreSizeV3:]
+}
+
+func dirEntry(ino uint64, name string, ftype byte) []byte {
+	result := binary.BigEndian.AppendUint64(nil, ino)
+	result = append(result, byte(len(name)))
+	result = append(result, name...)
+	result = append(result, ftype)
+	for (len(result)+2)%8 != 0 {
+		result = append(result, 0)
+	}
+	return append(result, 0, 0)
+}
+
+func buildXFS() *xfsBuilder {
+	self := &xfsBuilder{image: make([]byte, 64*testBlockSize)}
+
+	sb := self.image
+	copy(sb, "XFSB")
+	binary.BigEndian.PutUint32(sb[4:], testBlockSize)
+	binary.BigEndian.PutUint64(sb[8:], 64)
+	binary.BigEndian.PutUint64(sb[56:], 64)
+	binary.BigEndian.PutUint32(sb[84:], 64)
+	binary.BigEndian.PutUint32(sb[88:], 1)
+	binary.BigEndian.PutUint16(sb[100:], 0xB4A5)
+	binary.BigEndian.PutUint16(sb[102:], 512)
+	binary.BigEndian.PutUint16(sb[104:], testInodeSize)
+	binary.BigEndian.PutUint16(sb[106:], testBlockSize/testInodeSize)
+	copy(sb[108:], "testxfs")
+	sb[120] = 12
+	sb[121] = 9
+	sb[122] = 9
+	sb[123] = 3
+	sb[124] = 6
+	binary.BigEndian.PutUint32(sb[216:], xfsSBFeatIncompatFtype)
+
+	// Root directory in short form.
+	sf := []byte{3, 0, 0, 0, 0, 64}
+	for _, e := range []struct {
+		name  string
+		ino   byte
+		ftype byte
+	}{{"hello.txt", 65, 1}, {"dir", 66, 2}, {"link", 67, 7}} {
+		sf = append(sf, byte(len(e.name)), 0, 0)
+		sf = append(sf, e.name...)
+		sf = append(sf, e.ftype, 0, 0, 0, e.ino)
+	}
+	copy(self.inode(64, 0x41ed, xfsFormatLocal, int64(len(sf)), 0), sf)
+
+	// A regular file in two blocks
+	fork := self.inode(65, 0x81a4, xfsFormatExtents, 5000, 1)
+	copy(fork, encodeExtent(0, 20, 2))
+	copy(self.image[20*testBlockSize:], testData(2*testBlockSize, 1))
+
+	// A block directory.
+	fork = self.inode(66, 0x41ed, xfsFormatExtents, testBlockSize, 1)
+	copy(fork, encodeExtent(0, 30, 1))
+
+	dir := self.block(30)
+	copy(dir, "XDB3")
+	entries := append(dirEntry(66, ".", 2), dirEntry(64, "..", 2)...)
+	entries = append(entries, dirEntry(68, "big.bin", 1)...)
+
+	// A freed entry
+	free := make([]byte, 16)
+	binary.BigEndian.PutUint16(free, 0xffff)
+	binary.BigEndian.PutUint16(free[2:], 16)
+	entries = append(entries, free...)
+	entries = append(entries, dirEntry(65, "hardlink.txt", 1)...)
+	copy(dir[xfsDirHeaderSizeV5:], entries)
+
+	// Leaf entries and tail
+	binary.BigEndian.PutUint32(dir[testBlockSize-8:], 4)
+
+	// A symlink stored in the inode.
+	fork = self.inode(67, 0xa1ff, xfsFormatLocal, 9, 0)
+	copy(fork, "hello.txt")
+
+	// A file with a btree extent map and a hole.
+	fork = self.inode(68, 0x81a4, xfsFormatBtree, 4*testBlockSize, 3)
+	binary.BigEndian.PutUint16(fork, 1)
+	binary.BigEndian.PutUint16(fork[2:], 1)
+	maxrecs := (len(fork) - 4) / 16
+	binary.BigEndian.PutUint64(fork[4+maxrecs*8:], 40)
+
+	bmap := self.block(40)
+	copy(bmap, "BMA3")
+	binary.BigEndian.PutUint16(bmap[6:], 2)
+	copy(bmap[xfsBmapHeaderSizeV5:], encodeExtent(0, 50, 2))
+	copy(bmap[xfsBmapHeaderSizeV5+16:], encodeExtent(3, 60, 1))
+	copy(self.image[50*testBlockSize:], testData(2*testBlockSize, 2))
+	copy(self.image[60*testBlockSize:], testData(testBlockSize, 3))
+
+	return self
+}
+
+func TestXFS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xfs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	builder := buildXFS()
+	image_path := filepath.Join(dir, "xfs.dd")
+	assert.NoError(t, ioutil.WriteFile(image_path, builder.image, 0644))
+
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+	defer scope.Close()
+
+	fs_accessor := NewXFSFileSystemAccessor(scope,
+		accessors.MustNewLinuxOSPath(""),
+		accessors.MustNewGenericOSPath(image_path), "file")
+
+	listDir := func(path string) []string {
+		children, err := fs_accessor.ReadDir(path)
+		assert.NoError(t, err)
+
+		result := []string{}
+		for _, c := range children {
+			result = append(result, c.Name())
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	readFile := func(path string) []byte {
+		fd, err := fs_accessor.Open(path)
+		assert.NoError(t, err)
+		defer fd.Close()
+
+		data, err := ioutil.ReadAll(fd)
+		assert.NoError(t, err)
+		return data
+	}
+
+	assert.Equal(t, []string{"dir", "hello.txt", "link"}, listDir("/"))
+	assert.Equal(t, []string{"big.bin", "hardlink.txt"}, listDir("/dir"))
+
+	expected := builder.image[20*testBlockSize : 20*testBlockSize+5000]
+	assert.Equal(t, expected, readFile("/hello.txt"))
+	assert.Equal(t, expected, readFile("/dir/hardlink.txt"))
+
+	// The btree mapped file has a hole in the third block.
+	expected = append([]byte{}, builder.image[50*testBlockSize:52*testBlockSize]...)
+	expected = append(expected, make([]byte, testBlockSize)...)
+	expected = append(expected,
+		builder.image[60*testBlockSize:61*testBlockSize]...)
+	assert.True(t, bytes.Equal(expected, readFile("/dir/big.bin")))
+
+	stat, err := fs_accessor.Lstat("/link")
+	assert.NoError(t, err)
+	target, _ := stat.Data().Get("link")
+	assert.Equal(t, "hello.txt", target)
+
+	stat, err = fs_accessor.Lstat("/hello.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1698832800), stat.Mtime().Unix())
+	assert.Equal(t, int64(5000), stat.Size())
+}
@@ -120,6 +120,18 @@ const (
 	// Allowed raw datastore access
 	DATASTORE_ACCESS
 
+	// Allowed to make changes to the endpoint to remove threats
+	// (e.g. stop services or unload drivers).
+	REMEDIATION
+
+	// Allowed to approve collections and hunts requested by other
+	// users.
+	APPROVE_COLLECTION
+
+	// Allowed to see results which are masked by masking policies
+	// for other users.
+	VIEW_PII
+
 	// When adding new permission - update CheckAccess,
 	// GetRolePermissions and acl.proto
 )
@@ -178,6 +190,12 @@ func (self ACL_PERMISSION) String() string {
 		return "DELETE_RESULTS"
 	case DATASTORE_ACCESS:
 		return "DATASTORE_ACCESS"
+	case REMEDIATION:
+		return "REMEDIATION"
+	case APPROVE_COLLECTION:
+		return "APPROVE_COLLECTION"
+	case VIEW_PII:
+		return "VIEW_PII"
 
 	}
 	return fmt.Sprintf("%d", self)
@@ -230,6 +248,12 @@ func GetPermission(name string) ACL_PERMISSION {
 		return DELETE_RESULTS
 	case "DATASTORE_ACCESS":
 		return DATASTORE_ACCESS
+	case "REMEDIATION":
+		return REMEDIATION
+	case "APPROVE_COLLECTION":
+		return APPROVE_COLLECTION
+	case "VIEW_PII":
+		return VIEW_PII
 
 	}
 	return NO_PERMISSIONS
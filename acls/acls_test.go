@@ -0,0 +1,60 @@
+package acls_test
+
+import (
+	"testing"
+
+	"www.velocidex.com/golang/velociraptor/acls"
+	acl_proto "www.velocidex.com/golang/velociraptor/acls/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestPermissionNames(t *testing.T) {
+	// Every permission name maps to a permission and back.
+	for _, name := range acls.ALL_PERMISSIONS {
+		permission := acls.GetPermission(name)
+		assert.True(t, permission != acls.NO_PERMISSIONS, name)
+		assert.Equal(t, name, permission.String())
+	}
+
+	assert.Equal(t, acls.REMEDIATION, acls.GetPermission("REMEDIATION"))
+	assert.Equal(t, acls.REMEDIATION, acls.GetPermission("remediation"))
+	assert.Equal(t, "REMEDIATION", acls.REMEDIATION.String())
+
+	// Unknown names grant nothing.
+	assert.Equal(t, acls.NO_PERMISSIONS, acls.GetPermission("REMEDIATE"))
+}
+
+func TestTokenPermissions(t *testing.T) {
+	token := &acl_proto.ApiClientACL{}
+	assert.NoError(t, acls.SetTokenPermission(token, "remediation"))
+	assert.True(t, token.Remediation)
+	assert.Equal(t, []string{"REMEDIATION"}, acls.DescribePermissions(token))
+
+	ok, err := services.CheckAccessWithToken(token, acls.REMEDIATION)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// REMEDIATION does not imply the other client permissions.
+	ok, err = services.CheckAccessWithToken(token, acls.EXECVE)
+	assert.NoError(t, err)
+	assert.True(t, !ok)
+
+	assert.Error(t, acls.SetTokenPermission(token, "REMEDIATE"))
+}
+
+func TestRemediationRoles(t *testing.T) {
+	// Only administrators may change endpoints.
+	for _, role := range acls.ALL_ROLES {
+		token := &acl_proto.ApiClientACL{}
+		assert.NoError(t, acls.GetRolePermissions(nil, []string{role}, token))
+		assert.Equal(t, role == "administrator", token.Remediation, role)
+		assert.Equal(t, role == "administrator",
+			utils.InString(acls.DescribePermissions(token), "REMEDIATION"), role)
+
+		ok, err := services.CheckAccessWithToken(token, acls.REMEDIATION)
+		assert.NoError(t, err)
+		assert.Equal(t, role == "administrator", ok, role)
+	}
+}
@@ -0,0 +1,25 @@
+package acls
+
+import "strings"
+
+// Client capabilities artifacts may declare in
+// required_capabilities and the permission a user needs to collect
+// them.
+var capabilityPermissions = map[string]ACL_PERMISSION{
+	"EXECVE":           EXECVE,
+	"FILESYSTEM_WRITE": FILESYSTEM_WRITE,
+
+	// Raw disk access bypasses the filesystem's own access controls.
+	"RAW_DISK": FILESYSTEM_READ,
+
+	// Plugins making network connections (e.g. http_client(),
+	// netcat()) require COLLECT_SERVER.
+	"NETWORK": COLLECT_SERVER,
+}
+
+// Returns the permission needed for the capability and if the
+// capability is known.
+func GetCapabilityPermission(capability string) (ACL_PERMISSION, bool) {
+	permission, pres := capabilityPermissions[strings.ToUpper(capability)]
+	return permission, pres
+}
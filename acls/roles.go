@@ -11,8 +11,8 @@ import (
 
 var (
 	ALL_ROLES = []string{"org_admin", "administrator", "reader",
-		"analyst", "investigator",
-		"artifact_writer", "api"}
+		"analyst", "investigator", "auditor",
+		"artifact_writer", "api", "approver"}
 
 	ALL_PERMISSIONS = []string{
 		"ALL_QUERY",
@@ -35,6 +35,9 @@ var (
 		"PREPARE_RESULTS",
 		"DELETE_RESULTS",
 		"DATASTORE_ACCESS",
+		"REMEDIATION",
+		"APPROVE_COLLECTION",
+		"VIEW_PII",
 	}
 )
 
@@ -110,6 +113,18 @@ func DescribePermissions(token *acl_proto.ApiClientACL) []string {
 		result = append(result, "DATASTORE_ACCESS")
 	}
 
+	if token.Remediation {
+		result = append(result, "REMEDIATION")
+	}
+
+	if token.ApproveCollection {
+		result = append(result, "APPROVE_COLLECTION")
+	}
+
+	if token.ViewPii {
+		result = append(result, "VIEW_PII")
+	}
+
 	return result
 }
 
@@ -157,6 +172,12 @@ func SetTokenPermission(
 			token.DeleteResults = true
 		case "DATASTORE_ACCESS":
 			token.DatastoreAccess = true
+		case "REMEDIATION":
+			token.Remediation = true
+		case "APPROVE_COLLECTION":
+			token.ApproveCollection = true
+		case "VIEW_PII":
+			token.ViewPii = true
 
 		default:
 			return errors.New("Unknown permission")
@@ -196,6 +217,9 @@ func GetRolePermissions(
 			result.MachineState = true
 			result.PrepareResults = true
 			result.DeleteResults = true
+			result.Remediation = true
+			result.ApproveCollection = true
+			result.ViewPii = true
 
 			// An administrator for the root org is allowed to
 			// manipulate orgs.
@@ -204,15 +228,23 @@ func GetRolePermissions(
 			}
 
 			// Readers can view results but not edit or
-			// modify anything.
+			// modify anything. Results are masked by the
+			// masking policies.
 		case "reader":
 			result.ReadResults = true
 
+			// Auditors can view results like readers but
+			// sensitive data (usernames, file contents, IP
+			// addresses) is redacted. See IsRedacted().
+		case "auditor":
+			result.ReadResults = true
+
 			// An API client can read results
 			// (e.g watch_monitoring)
 		case "api":
 			result.AnyQuery = true
 			result.ReadResults = true
+			result.ViewPii = true
 
 			// Analysts can post process results using
 			// notebooks. They can issue new VQL that
@@ -224,6 +256,7 @@ func GetRolePermissions(
 			result.LabelClients = true
 			result.AnyQuery = true
 			result.PrepareResults = true
+			result.ViewPii = true
 
 			// Investigators are like analysts but can
 			// also issue new collections from endpoints.
@@ -235,6 +268,7 @@ func GetRolePermissions(
 			result.LabelClients = true
 			result.AnyQuery = true
 			result.PrepareResults = true
+			result.ViewPii = true
 
 			// Artifact writers are allowed to edit and
 			// create artifacts. NOTE This role is akin to
@@ -242,6 +276,14 @@ func GetRolePermissions(
 		case "artifact_writer":
 			result.ArtifactWriter = true
 
+			// Approvers confirm the collections and hunts
+			// other users request when approvals are
+			// enabled. They need to see the results to
+			// judge the request.
+		case "approver":
+			result.ReadResults = true
+			result.ApproveCollection = true
+
 		default:
 			return errors.New("Unknown role")
 		}
@@ -250,3 +292,20 @@ func GetRolePermissions(
 	result.Roles = nil
 	return nil
 }
+
+// Results are redacted for auditors unless they also hold another
+// role which allows them to see the full data.
+func IsRedacted(roles []string) bool {
+	if !utils.InString(roles, "auditor") {
+		return false
+	}
+
+	for _, role := range roles {
+		switch role {
+		case "administrator", "reader", "analyst", "investigator", "api",
+			"approver":
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package actions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"google.golang.org/protobuf/proto"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+const (
+	CLIENT_INFO_HOST    = "host"
+	CLIENT_INFO_VERSION = "version"
+	CLIENT_INFO_LABELS  = "labels"
+)
+
+// The client info is split into sections which are hashed
+// separately so the client only needs to send the sections which
+// changed.
+type ClientInfoSection struct {
+	Name string
+
+	// Copy the fields in this section from src to dst.
+	Copy func(dst, src *actions_proto.ClientInfo)
+}
+
+var ClientInfoSections = []ClientInfoSection{{
+	Name: CLIENT_INFO_HOST,
+	Copy: func(dst, src *actions_proto.ClientInfo) {
+		dst.Hostname = src.Hostname
+		dst.Fqdn = src.Fqdn
+		dst.System = src.System
+		dst.Release = src.Release
+		dst.Architecture = src.Architecture
+	},
+}, {
+	Name: CLIENT_INFO_VERSION,
+	Copy: func(dst, src *actions_proto.ClientInfo) {
+		dst.ClientName = src.ClientName
+		dst.ClientVersion = src.ClientVersion
+		dst.BuildUrl = src.BuildUrl
+		dst.BuildTime = src.BuildTime
+		dst.InstallTime = src.InstallTime
+	},
+}, {
+	Name: CLIENT_INFO_LABELS,
+	Copy: func(dst, src *actions_proto.ClientInfo) {
+		dst.Labels = src.Labels
+	},
+}}
+
+// A client info with only the fields of this section set.
+func (self ClientInfoSection) Extract(
+	info *actions_proto.ClientInfo) *actions_proto.ClientInfo {
+	result := &actions_proto.ClientInfo{}
+	self.Copy(result, info)
+	return result
+}
+
+func (self ClientInfoSection) Hash(info *actions_proto.ClientInfo) string {
+	serialized, _ := proto.MarshalOptions{Deterministic: true}.Marshal(
+		self.Extract(info))
+	hash := sha256.Sum256(serialized)
+	return hex.EncodeToString(hash[:16])
+}
+
+// Returns true if the update includes this section. Sections which
+// are empty are always included.
+func (self ClientInfoSection) IsIncluded(
+	update *actions_proto.ClientInfo) bool {
+	// Full updates from older clients do not have any hashes.
+	if len(update.SectionHashes) == 0 {
+		return true
+	}
+
+	hash, pres := update.SectionHashes[self.Name]
+	return pres && self.Hash(update) == hash
+}
+
+func ClientInfoHashes(info *actions_proto.ClientInfo) map[string]string {
+	result := make(map[string]string)
+	for _, section := range ClientInfoSections {
+		result[section.Name] = section.Hash(info)
+	}
+	return result
+}
+
+// Build a client info update which omits the sections whose hashes
+// are in previous. The update carries the hashes of all the
+// sections so the server can tell which sections were sent.
+func ClientInfoDelta(info *actions_proto.ClientInfo,
+	previous map[string]string) *actions_proto.ClientInfo {
+	result := &actions_proto.ClientInfo{
+		SectionHashes: ClientInfoHashes(info),
+	}
+
+	for _, section := range ClientInfoSections {
+		if previous[section.Name] != result.SectionHashes[section.Name] {
+			section.Copy(result, info)
+		}
+	}
+
+	return result
+}
+
+// Wrap the client info in the Server.Internal.ClientInfo message
+// the server expects.
+func ClientInfoMessage(
+	info *actions_proto.ClientInfo) (*crypto_proto.VeloMessage, error) {
+	serialized, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &crypto_proto.VeloMessage{
+		SessionId: constants.MONITORING_WELL_KNOWN_FLOW,
+		VQLResponse: &actions_proto.VQLResponse{
+			JSONLResponse: string(serialized) + "\n",
+			Query: &actions_proto.VQLRequest{
+				Name: "Server.Internal.ClientInfo",
+			},
+			TotalRows: 1,
+		},
+	}, nil
+}
@@ -0,0 +1,50 @@
+package actions_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert"
+	"www.velocidex.com/golang/velociraptor/actions"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+)
+
+func TestClientInfoDelta(t *testing.T) {
+	info := &actions_proto.ClientInfo{
+		Hostname:      "host1",
+		ClientVersion: "0.1",
+		Labels:        []string{"Foo"},
+	}
+
+	// Without previous hashes the update is complete.
+	delta := actions.ClientInfoDelta(info, nil)
+	assert.Equal(t, "host1", delta.Hostname)
+	assert.Equal(t, "0.1", delta.ClientVersion)
+	assert.Equal(t, []string{"Foo"}, delta.Labels)
+	assert.Equal(t, 3, len(delta.SectionHashes))
+
+	for _, section := range actions.ClientInfoSections {
+		assert.True(t, section.IsIncluded(delta))
+	}
+
+	// Only changed sections are sent.
+	previous := delta.SectionHashes
+	info.ClientVersion = "0.2"
+
+	delta = actions.ClientInfoDelta(info, previous)
+	assert.Equal(t, "", delta.Hostname)
+	assert.Equal(t, "0.2", delta.ClientVersion)
+	assert.Equal(t, 0, len(delta.Labels))
+
+	included := []string{}
+	for _, section := range actions.ClientInfoSections {
+		if section.IsIncluded(delta) {
+			included = append(included, section.Name)
+		}
+	}
+	assert.Equal(t, []string{actions.CLIENT_INFO_VERSION}, included)
+
+	// Updates without hashes include every section.
+	for _, section := range actions.ClientInfoSections {
+		assert.True(t, section.IsIncluded(&actions_proto.ClientInfo{}))
+	}
+}
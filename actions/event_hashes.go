@@ -0,0 +1,62 @@
+package actions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+)
+
+var (
+	incompleteEventTableError = errors.New(
+		"Event table update refers to queries which are not cached")
+)
+
+// A content hash of an event query. The server adds jitter to
+// MaxWait for each client and event queries never time out so these
+// are not included.
+func EventQueryHash(event *actions_proto.VQLCollectorArgs) string {
+	event = proto.Clone(event).(*actions_proto.VQLCollectorArgs)
+	event.MaxWait = 0
+	event.Timeout = 0
+
+	serialized, _ := proto.MarshalOptions{Deterministic: true}.Marshal(event)
+	hash := sha256.Sum256(serialized)
+	return hex.EncodeToString(hash[:16])
+}
+
+// Expand a delta update into the full table by filling in the
+// queries the server omitted from the cached table. Full updates are
+// returned unchanged.
+func expandEventTable(
+	cached []*actions_proto.VQLCollectorArgs,
+	table *actions_proto.VQLEventTable) (*actions_proto.VQLEventTable, error) {
+	if len(table.EventHashes) == 0 {
+		return table, nil
+	}
+
+	available := make(map[string]*actions_proto.VQLCollectorArgs)
+	for _, event := range cached {
+		available[EventQueryHash(event)] = event
+	}
+
+	// Queries sent in the update take precedence.
+	for _, event := range table.Event {
+		available[EventQueryHash(event)] = event
+	}
+
+	result := &actions_proto.VQLEventTable{
+		Version: table.Version,
+	}
+	for _, hash := range table.EventHashes {
+		event, pres := available[hash]
+		if !pres {
+			return nil, incompleteEventTableError
+		}
+		result.Event = append(result.Event, event)
+	}
+
+	return result, nil
+}
@@ -299,6 +299,26 @@ func (self *EventTable) StartFromWriteback(
 	}
 }
 
+func (self *EventTable) expand(
+	table *actions_proto.VQLEventTable) (*actions_proto.VQLEventTable, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	// Update() will ignore old tables anyway.
+	if table.Version <= self.version {
+		return table, nil
+	}
+
+	result, err := expandEventTable(self.Events, table)
+	if err != nil {
+		// Report a zero version so the server resends the full
+		// table. The current queries keep running until then.
+		self.version = 0
+		return nil, err
+	}
+	return result, nil
+}
+
 func (self *EventTable) UpdateEventTable(
 	ctx context.Context,
 	wg *sync.WaitGroup,
@@ -306,6 +326,16 @@ func (self *EventTable) UpdateEventTable(
 	output_chan chan *crypto_proto.VeloMessage,
 	update_table *actions_proto.VQLEventTable) {
 
+	// Delta updates only carry the queries we do not already have
+	// so fill in the rest from the current table.
+	update_table, err := self.expand(update_table)
+	if err != nil {
+		responder.MakeErrorResponse(
+			output_chan, "F.Monitoring", fmt.Sprintf(
+				"Error updating global event table: %v", err))
+		return
+	}
+
 	// Make a new table if needed.
 	err, changed := self.Update(
 		ctx, wg, config_obj, output_chan, update_table)
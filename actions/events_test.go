@@ -359,6 +359,91 @@ func (self *EventsTestSuite) TestEventEqual() {
 	assert.True(self.T(), ok)
 }
 
+// Clients which cache their event table only receive the queries
+// which changed.
+func (self *EventsTestSuite) TestEventTableDelta() {
+	client_manager, err := services.ClientEventManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	client_manager.(*client_monitoring.ClientEventTable).Clock = self.Clock
+
+	ctx, cancel := context.WithTimeout(self.Ctx, time.Second*60)
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+	output_chan, _ := responder.NewMessageDrain(ctx)
+	table := self.InitializeEventTable(ctx, wg, output_chan)
+	defer table.Close()
+
+	require.NoError(self.T(), client_manager.SetClientMonitoringState(
+		ctx, self.ConfigObj, "", server_state))
+
+	// The client advertises it can apply deltas.
+	checkin := &actions_proto.ForemanCheckin{EventTableDelta: true}
+	assert.False(self.T(), client_manager.ProcessClientCheckin(
+		ctx, self.ConfigObj, self.client_id, checkin))
+
+	// The first update has all the queries.
+	message := client_manager.GetClientUpdateEventTableDelta(
+		ctx, self.ConfigObj, self.client_id)
+	assert.Equal(self.T(), 1, len(message.UpdateEventTable.Event))
+	assert.Equal(self.T(), 1, len(message.UpdateEventTable.EventHashes))
+
+	table.UpdateEventTable(ctx, wg, self.ConfigObj, output_chan,
+		message.UpdateEventTable)
+	assert.Equal(self.T(), message.UpdateEventTable.Version, table.Version())
+
+	// Adding a label only sends the new query.
+	label_manager := services.GetLabeler(self.ConfigObj)
+	require.NoError(self.T(), label_manager.SetClientLabel(
+		ctx, self.ConfigObj, self.client_id, "Label1"))
+
+	message = client_manager.GetClientUpdateEventTableDelta(
+		ctx, self.ConfigObj, self.client_id)
+	assert.Equal(self.T(), 1, len(message.UpdateEventTable.Event))
+	assert.Equal(self.T(), 2, len(message.UpdateEventTable.EventHashes))
+	assert.Equal(self.T(), message.UpdateEventTable.EventHashes[1],
+		actions.EventQueryHash(message.UpdateEventTable.Event[0]))
+
+	table.UpdateEventTable(ctx, wg, self.ConfigObj, output_chan,
+		message.UpdateEventTable)
+	assert.Equal(self.T(), message.UpdateEventTable.Version, table.Version())
+	assert.Equal(self.T(), 2, len(table.Events))
+
+	// The writeback has the full table so it can be restored.
+	data, err := ioutil.ReadFile(self.writeback)
+	assert.NoError(self.T(), err)
+	assert.Contains(self.T(), string(data), "EventArtifact1")
+	assert.Contains(self.T(), string(data), "EventArtifact2")
+
+	// Nothing changed so nothing is sent.
+	delta := client_manager.GetClientUpdateEventTableDelta(
+		ctx, self.ConfigObj, self.client_id)
+	assert.Equal(self.T(), 0, len(delta.UpdateEventTable.Event))
+	assert.Equal(self.T(), 2, len(delta.UpdateEventTable.EventHashes))
+
+	// A client without the cached queries can not apply the delta
+	// and resets its version so it gets the full table.
+	empty_table := self.InitializeEventTable(ctx, wg, output_chan)
+	defer empty_table.Close()
+
+	empty_table.UpdateEventTable(ctx, wg, self.ConfigObj, output_chan,
+		delta.UpdateEventTable)
+	assert.Equal(self.T(), uint64(0), empty_table.Version())
+	assert.Equal(self.T(), 0, len(empty_table.Events))
+
+	checkin.LastEventTableVersion = empty_table.Version()
+	assert.True(self.T(), client_manager.ProcessClientCheckin(
+		ctx, self.ConfigObj, self.client_id, checkin))
+
+	// Only once
+	assert.False(self.T(), client_manager.ProcessClientCheckin(
+		ctx, self.ConfigObj, self.client_id, checkin))
+
+	message = client_manager.GetClientUpdateEventTableDelta(
+		ctx, self.ConfigObj, self.client_id)
+	assert.Equal(self.T(), 2, len(message.UpdateEventTable.Event))
+}
+
 func TestEventsTestSuite(t *testing.T) {
 	suite.Run(t, &EventsTestSuite{})
 }
@@ -58,6 +58,12 @@ func (self *LogWriter) Write(b []byte) (int, error) {
 	return len(b), nil
 }
 
+// Responders which can account for the files a dry run collection
+// would have uploaded.
+type uploadEstimator interface {
+	EstimateUpload(size int64)
+}
+
 type VQLClientAction struct{}
 
 func (self VQLClientAction) StartQuery(
@@ -145,6 +151,18 @@ func (self VQLClientAction) StartQuery(
 		Responder: responder,
 	}
 
+	// Dry run collections only count the files they would upload.
+	var scope_uploader uploads.Uploader = uploader
+	var dry_run_uploader *uploads.DryRunUploader
+	if responder.FlowContext().DryRun() {
+		dry_run_uploader = &uploads.DryRunUploader{}
+		estimator, ok := responder.(uploadEstimator)
+		if ok {
+			dry_run_uploader.Estimate = estimator.EstimateUpload
+		}
+		scope_uploader = dry_run_uploader
+	}
+
 	builder := services.ScopeBuilder{
 		Config: &config_proto.Config{
 			Remappings: config_obj.Remappings,
@@ -158,7 +176,7 @@ func (self VQLClientAction) StartQuery(
 			// Make the session id available in the query.
 			Set("_SessionId", responder.FlowContext().SessionId()).
 			Set(constants.SCOPE_RESPONDER, responder),
-		Uploader:   uploader,
+		Uploader:   scope_uploader,
 		Repository: repository,
 		Logger:     log.New(&LogWriter{config_obj, responder, ctx}, "", 0),
 	}
@@ -182,6 +200,9 @@ func (self VQLClientAction) StartQuery(
 	}
 
 	scope.Log("INFO:Starting query execution for %v.", name)
+	if dry_run_uploader != nil {
+		scope.Log("INFO:Dry run: files will be counted but not uploaded.")
+	}
 
 	throttler := NewThrottler(ctx, scope, float64(rate),
 		float64(cpu_limit), float64(iops_limit))
@@ -239,7 +260,7 @@ func (self VQLClientAction) StartQuery(
 		}
 
 		result_chan := EncodeIntoResponsePackets(
-			vql, sub_ctx, scope,
+			vql, sub_ctx, scope, responder.FlowContext(),
 			int(max_row), int(max_wait), int(max_row_buffer_size))
 	run_query:
 		for {
@@ -318,6 +339,12 @@ func (self VQLClientAction) StartQuery(
 			fmt.Sprintf("%v: Uploaded %v files.", name, uploader.Count))
 	}
 
+	if dry_run_uploader != nil && dry_run_uploader.Count > 0 {
+		responder.Log(ctx, logging.DEFAULT,
+			fmt.Sprintf("%v: Dry run would upload %v files (%v bytes).",
+				name, dry_run_uploader.Count, dry_run_uploader.TotalBytes))
+	}
+
 	responder.Return(ctx)
 }
 
@@ -348,6 +375,8 @@ func EncodeIntoResponsePackets(
 	vql *vfilter.VQL,
 	ctx context.Context,
 	scope types.Scope,
+	// Queries wait between rows while their flow is preempted.
+	flow_context *responder.FlowContext,
 	maxrows int,
 	// Max time to wait before returning some results.
 	max_wait int,
@@ -410,6 +439,8 @@ func EncodeIntoResponsePackets(
 					return
 				}
 
+				flow_context.WaitIfPaused(ctx)
+
 				// Materialize all elements if needed.
 				value := vfilter.RowToDict(ctx, scope, row)
 
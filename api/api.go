@@ -55,6 +55,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/server"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/approvals"
 	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
@@ -195,6 +196,25 @@ func (self *ApiServer) CollectArtifact(
 		return nil, Status(self.verbose, err)
 	}
 
+	// Dangerous collections wait for another user to approve them.
+	approval_service := approvals.GetApprovalService(org_config_obj)
+	if approval_service != nil &&
+		principal != org_config_obj.Client.PinnedServerName {
+		reasons, err := approval_service.CheckCollection(ctx, in)
+		if err != nil {
+			return nil, Status(self.verbose, err)
+		}
+
+		if len(reasons) > 0 {
+			request, err := approval_service.RequestCollection(
+				ctx, principal, in, reasons)
+			if err != nil {
+				return nil, Status(self.verbose, err)
+			}
+			return nil, ApprovalRequired(request.Id, reasons)
+		}
+	}
+
 	launcher, err := services.GetLauncher(org_config_obj)
 	if err != nil {
 		return nil, Status(self.verbose, err)
@@ -260,6 +280,9 @@ func (self *ApiServer) ListClients(
 		notifier.IsClientConnected(
 			ctx, org_config_obj, item.ClientId, 0 /* timeout */)
 	}
+
+	redactApiClients(org_config_obj, principal, result.Items...)
+
 	return result, nil
 }
 
@@ -457,6 +480,8 @@ func (self *ApiServer) GetUserUITraits(
 		result.InterfaceTraits.DisableServerEvents = user_options.DisableServerEvents
 		result.InterfaceTraits.DisableQuarantineButton = user_options.DisableQuarantineButton
 	}
+	result.InterfaceTraits.Redacted = tables.IsRedactedUser(
+		org_config_obj, principal)
 
 	return result, nil
 }
@@ -615,6 +640,12 @@ func (self *ApiServer) VFSGetBuffer(
 			"User is not allowed to view the VFS.")
 	}
 
+	// File contents can not be redacted.
+	if tables.IsRedactedUser(org_config_obj, principal) {
+		return nil, PermissionDenied(nil,
+			"Auditors are not allowed to view file contents.")
+	}
+
 	// If a client id is specified, the path is relative to the
 	// client's storage directory, otherwise it is relative to the
 	// root of the filestore.
@@ -661,6 +692,15 @@ func (self *ApiServer) GetTable(
 		return nil, Status(self.verbose, err)
 	}
 
+	redactor, err := tables.GetRedactor(org_config_obj, principal, in.Artifact)
+	if err != nil {
+		return nil, Status(self.verbose, err)
+	}
+
+	if redactor != nil {
+		redactor.RedactTable(result)
+	}
+
 	return result, nil
 }
 
@@ -1017,17 +1057,27 @@ func (self *ApiServer) CreateDownloadFile(ctx context.Context,
 	env := ordereddict.NewDict()
 	if in.FlowId != "" && in.ClientId != "" {
 		query = `SELECT create_flow_download(password=Password, format=Format,
+      encryption=Encryption, recipients=Recipients,
+      columns=Columns, drop_columns=DropColumns, where=Where, redact=Redact,
       expand_sparse=ExpandSparse, client_id=ClientId, flow_id=FlowId) AS VFSPath
       FROM scope()`
 
 		env.Set("ClientId", in.ClientId).
 			Set("FlowId", in.FlowId).
 			Set("Password", in.Password).
+			Set("Encryption", in.Encryption).
+			Set("Recipients", in.Recipients).
+			Set("Columns", in.Columns).
+			Set("DropColumns", in.DropColumns).
+			Set("Where", in.Where).
+			Set("Redact", in.Redact).
 			Set("Format", format).
 			Set("ExpandSparse", in.ExpandSparse)
 
 	} else if in.HuntId != "" {
 		query = `SELECT create_hunt_download(password=Password,
+      encryption=Encryption, recipients=Recipients,
+      columns=Columns, drop_columns=DropColumns, where=Where, redact=Redact,
       expand_sparse=ExpandSparse,
       hunt_id=HuntId, only_combined=OnlyCombined, format=Format) AS VFSPath
       FROM scope()`
@@ -1035,6 +1085,12 @@ func (self *ApiServer) CreateDownloadFile(ctx context.Context,
 		env.Set("HuntId", in.HuntId).
 			Set("Format", format).
 			Set("Password", in.Password).
+			Set("Encryption", in.Encryption).
+			Set("Recipients", in.Recipients).
+			Set("Columns", in.Columns).
+			Set("DropColumns", in.DropColumns).
+			Set("Where", in.Where).
+			Set("Redact", in.Redact).
 			Set("OnlyCombined", in.OnlyCombinedHunt).
 			Set("ExpandSparse", in.ExpandSparse)
 	}
@@ -1069,6 +1125,79 @@ func (self *ApiServer) CreateDownloadFile(ctx context.Context,
 	return result, Status(self.verbose, err)
 }
 
+// Build the gRPC API server. When creds are nil the server is only
+// served over an existing HTTP/2 server.
+func newAPIServer(
+	config_obj *config_proto.Config,
+	server_obj *server.Server,
+	wg *sync.WaitGroup,
+	creds credentials.TransportCredentials) *grpc.Server {
+
+	// Authenticate API clients using certificates.
+	CA_Pool := x509.NewCertPool()
+	if config_obj.Client != nil {
+		CA_Pool.AppendCertsFromPEM([]byte(config_obj.Client.CaCertificate))
+	}
+
+	options := []grpc.ServerOption{}
+	if creds != nil {
+		options = append(options, grpc.Creds(creds))
+	}
+
+	grpcServer := grpc.NewServer(options...)
+	api_proto.RegisterAPIServer(
+		grpcServer,
+		&ApiServer{
+			server_obj:         server_obj,
+			verbose:            config_obj.Verbose,
+			ca_pool:            CA_Pool,
+			api_client_factory: grpc_client.GRPCAPIClient{},
+			wg:                 wg,
+		},
+	)
+	// Register reflection service.
+	reflection.Register(grpcServer)
+
+	return grpcServer
+}
+
+// Serve the gRPC API from the frontend's port. The frontend routes
+// API connections to the returned server.
+func startSharedAPIServer(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config,
+	server_obj *server.Server) (*grpc.Server, error) {
+
+	if config_obj.API == nil ||
+		config_obj.Client == nil ||
+		config_obj.Frontend == nil {
+		return nil, errors.New("API server not configured")
+	}
+
+	if config_obj.Frontend.UsePlainHttp || config_obj.AutocertCertCache != "" {
+		return nil, errors.New(
+			"API.share_frontend_port requires the frontend to use self signed TLS")
+	}
+
+	grpcServer := newAPIServer(config_obj, server_obj, wg, nil)
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> gRPC API server on the frontend port %v",
+		config_obj.Frontend.BindPort)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		<-ctx.Done()
+		logger.Info("<red>Shutting down</> gRPC API server")
+		grpcServer.Stop()
+	}()
+
+	return grpcServer, nil
+}
+
 func startAPIServer(
 	ctx context.Context,
 	wg *sync.WaitGroup,
@@ -1087,10 +1216,17 @@ func startAPIServer(
 		bind_addr += fmt.Sprintf(":%d", config_obj.API.BindPort)
 	}
 
+	access, err := server.NewAccessControl(ctx, config_obj, "api",
+		config_obj.API.AccessControl)
+	if err != nil {
+		return err
+	}
+
 	lis, err := net.Listen(config_obj.API.BindScheme, bind_addr)
 	if err != nil {
 		return errors.Wrap(err, 0)
 	}
+	lis = access.WrapListener(lis)
 
 	// Use the server certificate to secure the gRPC connection.
 	cert, err := tls.X509KeyPair(
@@ -1100,12 +1236,6 @@ func startAPIServer(
 		return errors.Wrap(err, 0)
 	}
 
-	// Authenticate API clients using certificates.
-	CA_Pool := x509.NewCertPool()
-	if config_obj.Client != nil {
-		CA_Pool.AppendCertsFromPEM([]byte(config_obj.Client.CaCertificate))
-	}
-
 	// Create the TLS credentials
 	tls_config := &tls.Config{}
 	err = getTLSConfig(config_obj, tls_config)
@@ -1116,23 +1246,13 @@ func startAPIServer(
 	// Only accept certs signed by the Velociraptor internal CA
 	tls_config.ClientAuth = tls.RequireAndVerifyClientCert
 	tls_config.Certificates = []tls.Certificate{cert}
-	tls_config.ClientCAs = CA_Pool
+	tls_config.ClientCAs = x509.NewCertPool()
+	tls_config.ClientCAs.AppendCertsFromPEM(
+		[]byte(config_obj.Client.CaCertificate))
 
 	creds := credentials.NewTLS(tls_config)
 
-	grpcServer := grpc.NewServer(grpc.Creds(creds))
-	api_proto.RegisterAPIServer(
-		grpcServer,
-		&ApiServer{
-			server_obj:         server_obj,
-			verbose:            config_obj.Verbose,
-			ca_pool:            CA_Pool,
-			api_client_factory: grpc_client.GRPCAPIClient{},
-			wg:                 wg,
-		},
-	)
-	// Register reflection service.
-	reflection.Register(grpcServer)
+	grpcServer := newAPIServer(config_obj, server_obj, wg, creds)
 
 	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
 	logger.Info("<green>Starting</> gRPC API server on %v ", bind_addr)
@@ -1162,7 +1282,12 @@ func startAPIServer(
 func StartMonitoringService(
 	ctx context.Context,
 	wg *sync.WaitGroup,
-	config_obj *config_proto.Config) error {
+	config_obj *config_proto.Config,
+	server_obj *server.Server) error {
+
+	// Make server specific metrics available to the metrics() plugin
+	// even if the monitoring service is not enabled.
+	registerFilestoreMetrics(config_obj)
 
 	if config_obj.Monitoring == nil {
 		return nil
@@ -1185,6 +1310,12 @@ func StartMonitoringService(
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
+
+	// Drain control is only available on the monitoring port since
+	// it is not authenticated.
+	if server_obj != nil {
+		mux.Handle("/drain", server.DrainHandler(server_obj))
+	}
 	server := &http.Server{
 		Addr:     bind_addr,
 		Handler:  mux,
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/asset_inventory"
+)
+
+type assetInventoryRequest struct {
+	ClientId string `json:"client_id"`
+}
+
+// Get a client's inventory. A GET request returns the versions of the
+// client's inventory, and the rows of a category if one is given. If
+// diff is set the version is compared with an earlier version. A
+// POST request schedules a new inventory collection on the client.
+func assetInventoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		principal := userinfo.Name
+
+		perm, err := services.CheckAccess(
+			org_config_obj, principal, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view the asset inventory.")
+			return
+		}
+
+		service, err := asset_inventory.GetAssetInventoryService(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		if r.Method == "POST" {
+			perm, err = services.CheckAccess(
+				org_config_obj, principal, acls.COLLECT_CLIENT)
+			if !perm || err != nil {
+				returnError(w, http.StatusUnauthorized,
+					"User is not allowed to collect from clients.")
+				return
+			}
+
+			request := &assetInventoryRequest{}
+			err = readJSONRequest(w, r, request)
+			if err != nil || request.ClientId == "" {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			flow_id, err := service.Refresh(
+				r.Context(), principal, request.ClientId)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSONResponse(w, ordereddict.NewDict().
+				Set("flow_id", flow_id))
+			return
+		}
+
+		query := r.URL.Query()
+		client_id := query.Get("client_id")
+		category := query.Get("category")
+		version := query.Get("version")
+
+		record, err := service.Get(client_id)
+		if err != nil {
+			returnError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		result := ordereddict.NewDict().Set("inventory", record)
+		if category != "" {
+			var rows []*ordereddict.Dict
+			if query.Get("diff") != "" {
+				rows, err = service.Diff(r.Context(), client_id, category,
+					query.Get("from"), version)
+			} else {
+				rows, err = service.Rows(r.Context(), client_id,
+					category, version)
+			}
+			if err != nil {
+				returnError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			result.Set("rows", rows)
+		}
+
+		writeJSONResponse(w, result)
+	})
+}
+
+// Search the latest inventory of all clients, e.g. for all hosts
+// with a vulnerable version of a package.
+func assetInventorySearchHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view the asset inventory.")
+			return
+		}
+
+		service, err := asset_inventory.GetAssetInventoryService(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		query := r.URL.Query()
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		result, err := service.Search(r.Context(),
+			&asset_inventory.SearchOptions{
+				Category: query.Get("category"),
+				Name:     query.Get("name"),
+				Op:       query.Get("op"),
+				Version:  query.Get("version"),
+				Limit:    limit,
+			})
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, ordereddict.NewDict().Set("rows", result))
+	})
+}
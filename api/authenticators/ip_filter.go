@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 // Implement a src IP filter if required. This adds an additional
@@ -28,8 +29,35 @@ func IpFilter(config_obj *config_proto.Config,
 		ranges = append(ranges, cidr_net)
 	}
 
+	var trusted_proxies utils.TrustedProxies
+	if config_obj.Frontend != nil {
+		trusted, err := utils.ParseTrustedProxies(config_obj.Frontend.TrustedProxies)
+		if err != nil {
+			panic(err)
+		}
+		trusted_proxies = trusted
+	}
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
+		// When the reverse proxies are known only trust the
+		// forwarded header from them.
+		if len(trusted_proxies) > 0 {
+			remote_address := trusted_proxies.RemoteAddr(
+				r, config_obj.GUI.ForwardedProxyHeader)
+			host, _, err := net.SplitHostPort(remote_address)
+			if err == nil {
+				remote_address = host
+			}
+
+			if matchCidr(ranges, remote_address) {
+				parent.ServeHTTP(w, r)
+				return
+			}
+			http.Error(w, "rejected", http.StatusUnauthorized)
+			return
+		}
+
 		// If the user specified a forwarded header and the header is
 		// there we must check it.
 		if config_obj.GUI.ForwardedProxyHeader != "" {
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/availability"
+)
+
+// Report on fleet availability. The report type is selected by the
+// "report" query parameter (clients, not_seen or flapping).
+func clientAvailabilityHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view client availability.")
+			return
+		}
+
+		service, err := availability.GetAvailabilityService(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		query := r.URL.Query()
+		days, _ := strconv.ParseInt(query.Get("days"), 10, 64)
+		min_gaps, _ := strconv.ParseInt(query.Get("min_gaps"), 10, 64)
+		options := availability.ReportOptions{
+			Days:    days,
+			GroupBy: query.Get("group_by"),
+			MinGaps: min_gaps,
+		}
+
+		var rows interface{}
+		switch query.Get("report") {
+		case "", "clients":
+			rows, err = service.ClientReport(r.Context(), options)
+		case "not_seen":
+			rows, err = service.NotSeenReport(r.Context(), options)
+		case "flapping":
+			rows, err = service.FlappingReport(r.Context(), options)
+		default:
+			returnError(w, http.StatusBadRequest, "Unknown report type")
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, ordereddict.NewDict().Set("rows", rows))
+	})
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/baselines"
+)
+
+type baselineRequest struct {
+	ClientId string   `json:"client_id"`
+	Artifact string   `json:"artifact"`
+	FlowId   string   `json:"flow_id"`
+	Key      []string `json:"key"`
+
+	// Remove the baseline instead of setting it.
+	Delete bool `json:"delete"`
+}
+
+// Manage a client's baselines. A GET request lists the client's
+// baselines while POST requests set or delete a baseline.
+func baselinesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		principal := userinfo.Name
+
+		perm, err := services.CheckAccess(
+			org_config_obj, principal, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view baselines.")
+			return
+		}
+
+		service, err := baselines.GetBaselineService(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		if r.Method == "GET" {
+			items, err := service.List(r.URL.Query().Get("client_id"))
+			if err != nil {
+				returnError(w, http.StatusNotFound, err.Error())
+				return
+			}
+
+			writeJSONResponse(w, ordereddict.NewDict().
+				Set("baselines", items).
+				Set("designated", baselines.DesignatedArtifacts))
+			return
+		}
+
+		perm, err = services.CheckAccess(
+			org_config_obj, principal, acls.COLLECT_CLIENT)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to manage baselines.")
+			return
+		}
+
+		request := &baselineRequest{}
+		err = readJSONRequest(w, r, request)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		if request.Delete {
+			err = service.Delete(request.ClientId, request.Artifact)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSONResponse(w, ordereddict.NewDict())
+			return
+		}
+
+		flow_id := request.FlowId
+		if flow_id == "" {
+			flow_id, err = service.LatestFlow(r.Context(),
+				request.ClientId, request.Artifact)
+			if err != nil {
+				returnError(w, http.StatusNotFound, err.Error())
+				return
+			}
+		}
+
+		baseline, err := service.Set(r.Context(), principal,
+			request.ClientId, request.Artifact, flow_id, request.Key)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSONResponse(w, baseline)
+	})
+}
+
+// Compare a collection to the client's baseline.
+func baselineDriftHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view baselines.")
+			return
+		}
+
+		service, err := baselines.GetBaselineService(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		query := r.URL.Query()
+		flow_id, drift, err := service.Drift(r.Context(),
+			query.Get("client_id"), query.Get("artifact"),
+			query.Get("flow_id"))
+		if err != nil {
+			returnError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, ordereddict.NewDict().
+			Set("flow_id", flow_id).
+			Set("drift", drift))
+	})
+}
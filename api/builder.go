@@ -6,6 +6,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"golang.org/x/crypto/acme/autocert"
+	"google.golang.org/grpc"
 	"www.velocidex.com/golang/velociraptor/api/authenticators"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/logging"
@@ -45,13 +47,16 @@ type Builder struct {
 
 	server_obj *server.Server
 
+	// Set when the gRPC API is served on the frontend port.
+	api_server *grpc.Server
+
 	GUIPort, FrontendPort uint32
 	AutocertCertCache     string
 }
 
 func (self *Builder) StartServer(ctx context.Context, wg *sync.WaitGroup) error {
 	// Always start the prometheus monitoring service
-	err := StartMonitoringService(ctx, wg, self.config_obj)
+	err := StartMonitoringService(ctx, wg, self.config_obj, self.server_obj)
 	if err != nil {
 		return err
 	}
@@ -71,10 +76,12 @@ func (self *Builder) StartServer(ctx context.Context, wg *sync.WaitGroup) error
 
 	// All services are sharing the same port.
 	if self.GUIPort == self.FrontendPort {
-		return startSharedSelfSignedFrontend(ctx, wg, self.config_obj, self.server_obj)
+		return startSharedSelfSignedFrontend(ctx, wg,
+			self.config_obj, self.server_obj, self.api_server)
 	}
 
-	return startSelfSignedFrontend(ctx, wg, self.config_obj, self.server_obj)
+	return startSelfSignedFrontend(ctx, wg,
+		self.config_obj, self.server_obj, self.api_server)
 }
 
 func NewServerBuilder(ctx context.Context,
@@ -105,6 +112,16 @@ func (self *Builder) Close() {
 }
 
 func (self *Builder) WithAPIServer(ctx context.Context, wg *sync.WaitGroup) error {
+	if self.config_obj.API != nil && self.config_obj.API.ShareFrontendPort {
+		api_server, err := startSharedAPIServer(
+			ctx, wg, self.config_obj, self.server_obj)
+		if err != nil {
+			return err
+		}
+		self.api_server = api_server
+		return nil
+	}
+
 	return startAPIServer(ctx, wg, self.config_obj, self.server_obj)
 }
 
@@ -193,7 +210,8 @@ func startSharedSelfSignedFrontend(
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	config_obj *config_proto.Config,
-	server_obj *server.Server) error {
+	server_obj *server.Server,
+	api_server *grpc.Server) error {
 	mux := http.NewServeMux()
 
 	if config_obj.Frontend == nil || config_obj.GUI == nil {
@@ -237,7 +255,7 @@ func startSharedSelfSignedFrontend(
 	if config_obj.Frontend.RequireClientCertificates {
 		server_obj.Info("Frontend and GUI will both require mTLS client side certificates!")
 	}
-	return StartFrontendHttps(ctx, wg, config_obj, server_obj, router)
+	return startFrontendHttps(ctx, wg, config_obj, server_obj, router, api_server)
 }
 
 // Start the Frontend and GUI on different ports using different
@@ -246,7 +264,8 @@ func startSelfSignedFrontend(
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	config_obj *config_proto.Config,
-	server_obj *server.Server) error {
+	server_obj *server.Server,
+	api_server *grpc.Server) error {
 
 	if config_obj.Services == nil {
 		return errors.New("Frontend not configured")
@@ -288,8 +307,8 @@ func startSelfSignedFrontend(
 	}
 
 	// Start comms over https.
-	return StartFrontendHttps(ctx, wg,
-		config_obj, server_obj, mux)
+	return startFrontendHttps(ctx, wg,
+		config_obj, server_obj, mux, api_server)
 }
 
 func getCertificates(config_obj *config_proto.Config) ([]tls.Certificate, error) {
@@ -321,6 +340,18 @@ func StartFrontendHttps(
 	config_obj *config_proto.Config,
 	server_obj *server.Server,
 	router http.Handler) error {
+	return startFrontendHttps(ctx, wg, config_obj, server_obj, router, nil)
+}
+
+// If api_server is set the gRPC API is also served on the frontend
+// port.
+func startFrontendHttps(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config,
+	server_obj *server.Server,
+	router http.Handler,
+	api_server *grpc.Server) error {
 
 	if config_obj.Frontend == nil {
 		return errors.New("Frontend server not configured")
@@ -340,6 +371,15 @@ func StartFrontendHttps(
 		server_obj.Info("Frontend will require mTLS client side certificates!")
 	}
 
+	if api_server != nil {
+		router, err = addAPIRouting(
+			ctx, config_obj, tls_config, api_server, router)
+		if err != nil {
+			return err
+		}
+		server_obj.Info("Frontend will route gRPC API connections")
+	}
+
 	listenAddr := fmt.Sprintf(
 		"%s:%d",
 		config_obj.Frontend.BindAddress,
@@ -440,7 +480,14 @@ func StartFrontendPlainHttp(
 
 		atomic.StoreInt32(&server_obj.Healthy, 1)
 
-		err := server.ListenAndServe()
+		listener, err := net.Listen("tcp", server.Addr)
+		if err != nil {
+			server_obj.Error("Frontend server: Can not listen on %v: %v",
+				server.Addr, err)
+			return
+		}
+
+		err = server.Serve(server_obj.WrapListener(listener))
 		if err != nil && err != http.ErrServerClosed {
 			server_obj.Error("Frontend server error %v", err)
 			return
@@ -612,6 +659,12 @@ func StartHTTPGUI(
 
 	logger := logging.Manager.GetLogger(config_obj, &logging.GUIComponent)
 
+	access, err := server.NewAccessControl(ctx, config_obj, "gui",
+		config_obj.GUI.AccessControl)
+	if err != nil {
+		return err
+	}
+
 	listenAddr := fmt.Sprintf("%s:%d",
 		config_obj.GUI.BindAddress,
 		config_obj.GUI.BindPort)
@@ -635,7 +688,14 @@ func StartHTTPGUI(
 	go func() {
 		defer wg.Done()
 
-		err := server.ListenAndServe()
+		listener, err := net.Listen("tcp", server.Addr)
+		if err != nil {
+			logger.Error("GUI Server: Can not listen on %v: %v",
+				server.Addr, err)
+			return
+		}
+
+		err = server.Serve(access.WrapListener(listener))
 		if err != nil && err != http.ErrServerClosed {
 			logger.Error("GUI Server error: %v", err)
 		}
@@ -690,6 +750,12 @@ func StartSelfSignedGUI(
 		}
 	}
 
+	access, err := server.NewAccessControl(ctx, config_obj, "gui",
+		config_obj.GUI.AccessControl)
+	if err != nil {
+		return err
+	}
+
 	listenAddr := fmt.Sprintf("%s:%d",
 		config_obj.GUI.BindAddress,
 		config_obj.GUI.BindPort)
@@ -714,7 +780,14 @@ func StartSelfSignedGUI(
 	go func() {
 		defer wg.Done()
 
-		err := server.ListenAndServeTLS("", "")
+		listener, err := net.Listen("tcp", server.Addr)
+		if err != nil {
+			logger.Error("GUI Server: Can not listen on %v: %v",
+				server.Addr, err)
+			return
+		}
+
+		err = server.ServeTLS(access.WrapListener(listener), "", "")
 		if err != nil && err != http.ErrServerClosed {
 			logger.Error("GUI Server error: %v", err)
 		}
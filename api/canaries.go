@@ -0,0 +1,167 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/canaries"
+)
+
+type canaryRequest struct {
+	Id string `json:"id"`
+
+	// Create a new canary.
+	Create *canaries.Canary `json:"create"`
+
+	// Deploy the canary to these clients.
+	Deploy []string `json:"deploy"`
+
+	// Delete the canary, optionally removing it from the clients.
+	Delete bool `json:"delete"`
+	Remove bool `json:"remove"`
+}
+
+// Manage canary tokens. A GET request lists all canaries while POST
+// requests create, deploy or delete a canary.
+func canariesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		principal := userinfo.Name
+
+		perm, err := services.CheckAccess(
+			org_config_obj, principal, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view canaries.")
+			return
+		}
+
+		service, err := canaries.GetCanaryService(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		if r.Method == "GET" {
+			writeJSONResponse(w, ordereddict.NewDict().
+				Set("canaries", service.List()))
+			return
+		}
+
+		perm, err = services.CheckAccess(
+			org_config_obj, principal, acls.COLLECT_CLIENT)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to manage canaries.")
+			return
+		}
+
+		request := &canaryRequest{}
+		err = readJSONRequest(w, r, request)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		// Planting and removing canaries writes files and runs
+		// programs on the clients.
+		if len(request.Deploy) > 0 || request.Remove {
+			perm, err = services.CheckAccess(org_config_obj, principal,
+				acls.FILESYSTEM_WRITE, acls.EXECVE)
+			if !perm || err != nil {
+				returnError(w, http.StatusUnauthorized,
+					"User is not allowed to deploy canaries.")
+				return
+			}
+		}
+
+		switch {
+		case request.Create != nil:
+			canary, err := service.Create(principal, request.Create)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSONResponse(w, canary)
+
+		case request.Delete:
+			err = service.Delete(r.Context(), principal,
+				request.Id, request.Remove)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSONResponse(w, ordereddict.NewDict())
+
+		case len(request.Deploy) > 0:
+			flow_ids, err := service.Deploy(r.Context(), principal,
+				request.Id, request.Deploy)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSONResponse(w, ordereddict.NewDict().
+				Set("flow_ids", flow_ids))
+
+		default:
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+		}
+	})
+}
+
+// Get the hit history of a canary.
+func canaryHitsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view canaries.")
+			return
+		}
+
+		service, err := canaries.GetCanaryService(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		hits, err := service.Hits(r.Context(), r.URL.Query().Get("id"))
+		if err != nil {
+			returnError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, ordereddict.NewDict().Set("rows", hits))
+	})
+}
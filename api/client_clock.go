@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/interrogation"
+)
+
+// Get the time zone and clock skew the client reported when it was
+// last interrogated. The GUI uses these to display the client's
+// timestamps in its local time or corrected for skew.
+func clientClockHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view client information.")
+			return
+		}
+
+		client_id := r.URL.Query().Get("client_id")
+		if client_id == "" {
+			returnError(w, http.StatusBadRequest, "client_id must be specified")
+			return
+		}
+
+		// Clients which have not reported their clock yet get an empty
+		// record so the GUI shows their timestamps unchanged.
+		clock, err := interrogation.GetClientClock(org_config_obj, client_id)
+		if err != nil {
+			clock = &interrogation.ClientClock{ClientId: client_id}
+		}
+
+		writeJSONResponse(w, clock)
+	})
+}
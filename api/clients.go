@@ -27,6 +27,8 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 	"www.velocidex.com/golang/velociraptor/acls"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/api/tables"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
@@ -158,5 +160,24 @@ func (self *ApiServer) GetClient(
 		}
 	}
 
+	redactApiClients(org_config_obj, user_name, api_client)
+
 	return api_client, nil
 }
+
+// Hide the clients' IP addresses from auditors.
+func redactApiClients(config_obj *config_proto.Config,
+	principal string, clients ...*api_proto.ApiClient) {
+	if !tables.IsRedactedUser(config_obj, principal) {
+		return
+	}
+
+	redactor, err := tables.NewRedactor(config_obj)
+	for _, client := range clients {
+		if err != nil {
+			client.LastIp = tables.DEFAULT_REDACTION
+			continue
+		}
+		client.LastIp = redactor.RedactString("LastIp", client.LastIp)
+	}
+}
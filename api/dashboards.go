@@ -0,0 +1,584 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/api/tables"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	// The built in dashboard shown when the user has not created
+	// any of their own.
+	DEFAULT_DASHBOARD_ID = "default"
+
+	// Limits on widget queries - widgets are refreshed often so
+	// they must be cheap.
+	MAX_WIDGET_ROWS    = 1000
+	MAX_WIDGET_TIMEOUT = 30 * time.Second
+)
+
+var (
+	dashboardIdRegex = regexp.MustCompile("^D\\.[0-9A-Z]+$")
+
+	dashboardNotFoundError = errors.New("Dashboard not found")
+)
+
+// A widget displays the result of a server side VQL query.
+type DashboardWidget struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
+
+	// How to render the results: table, value, line_chart or
+	// bar_chart.
+	Type  string `json:"type"`
+	Query string `json:"query"`
+
+	// Width in grid columns (out of 12).
+	Width int64 `json:"width,omitempty"`
+
+	// Refresh the widget every this many seconds (0 means never).
+	Refresh int64 `json:"refresh,omitempty"`
+}
+
+type Dashboard struct {
+	Id          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Owner       string             `json:"owner,omitempty"`
+	Shared      bool               `json:"shared,omitempty"`
+	Modified    int64              `json:"modified,omitempty"`
+	ReadOnly    bool               `json:"read_only,omitempty"`
+	Widgets     []*DashboardWidget `json:"widgets"`
+}
+
+func (self *Dashboard) GetWidget(id string) (*DashboardWidget, bool) {
+	for _, w := range self.Widgets {
+		if w.Id == id {
+			return w, true
+		}
+	}
+	return nil, false
+}
+
+// The widget library offered by the GUI - these also make up the
+// default dashboard.
+func defaultDashboard() *Dashboard {
+	return &Dashboard{
+		Id:          DEFAULT_DASHBOARD_ID,
+		Name:        "Overview",
+		Description: "The default dashboard. Save a copy to customize it.",
+		ReadOnly:    true,
+		Widgets: []*DashboardWidget{{
+			Id:      "connectivity",
+			Title:   "Client Connectivity",
+			Type:    "bar_chart",
+			Width:   6,
+			Refresh: 60,
+			Query: `SELECT if(condition=last_seen_at / 1000000 > now() - 600,
+          then="Online", else="Offline") AS State, count() AS Clients
+FROM clients()
+GROUP BY State`,
+		}, {
+			Id:      "hunts",
+			Title:   "Hunt Progress",
+			Type:    "table",
+			Width:   6,
+			Refresh: 60,
+			Query: `SELECT hunt_id AS HuntId, hunt_description AS Description,
+       stats.total_clients_scheduled AS Scheduled,
+       stats.total_clients_with_results AS WithResults
+FROM hunts()
+WHERE state = "RUNNING"`,
+		}, {
+			Id:      "alerts",
+			Title:   "Alerts (Last Day)",
+			Type:    "table",
+			Width:   6,
+			Refresh: 300,
+			Query: `SELECT name AS Alert, count() AS Count
+FROM source(artifact="Server.Internal.Alerts", start_time=now() - 86400)
+GROUP BY Alert`,
+		}, {
+			Id:      "frontend",
+			Title:   "Frontend CPU and Memory",
+			Type:    "line_chart",
+			Width:   6,
+			Refresh: 60,
+			Query: `SELECT _ts AS Timestamp, CPUPercent,
+       MemoryUse / 1048576 AS MemoryUse
+FROM source(artifact="Server.Monitor.Health", source="Prometheus",
+            start_time=now() - 3600)`,
+		}, {
+			Id:      "availability",
+			Title:   "Clients Not Seen (Last Week)",
+			Type:    "table",
+			Width:   6,
+			Refresh: 3600,
+			Query: `SELECT Group, Total, NotSeen
+FROM client_availability(report="not_seen", days=7)
+WHERE NotSeen > 0`,
+		}, {
+			Id:      "flapping",
+			Title:   "Flapping Clients (Last Week)",
+			Type:    "table",
+			Width:   6,
+			Refresh: 3600,
+			Query: `SELECT ClientId, Hostname, Gaps, Availability
+FROM client_availability(report="flapping", days=7)`,
+		}},
+	}
+}
+
+func newDashboardId() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "D." + base32.HexEncoding.EncodeToString(buf)[:13]
+}
+
+func readDashboard(config_obj *config_proto.Config,
+	db datastore.RawDataStore, path_spec api.DSPathSpec) (*Dashboard, error) {
+	data, err := db.GetBuffer(config_obj, path_spec)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Dashboard{}
+	err = json.Unmarshal(data, result)
+	return result, err
+}
+
+func getRawDB(config_obj *config_proto.Config) (
+	datastore.DataStore, datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, nil, errors.New("Datastore does not support raw access")
+	}
+	return db, raw_db, nil
+}
+
+// List the dashboards visible to the user: their own and all shared
+// dashboards in the org.
+func getDashboards(config_obj *config_proto.Config,
+	principal string) ([]*Dashboard, error) {
+	db, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Dashboard{}
+	user_path_manager := paths.NewUserPathManager(principal)
+	for _, dir := range []api.DSPathSpec{
+		user_path_manager.DashboardDir(), paths.DASHBOARDS_ROOT} {
+		children, err := db.ListChildren(config_obj, dir)
+		if err != nil {
+			continue
+		}
+
+		for _, child := range children {
+			if child.IsDir() {
+				continue
+			}
+
+			dashboard, err := readDashboard(config_obj, raw_db, child)
+			if err != nil {
+				continue
+			}
+			result = append(result, dashboard)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	// Without any dashboards of their own the user gets the
+	// default.
+	if len(result) == 0 {
+		result = append(result, defaultDashboard())
+	}
+
+	return result, nil
+}
+
+func getDashboard(config_obj *config_proto.Config,
+	principal, id string) (*Dashboard, error) {
+	if id == DEFAULT_DASHBOARD_ID {
+		return defaultDashboard(), nil
+	}
+
+	if !dashboardIdRegex.MatchString(id) {
+		return nil, dashboardNotFoundError
+	}
+
+	_, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path_spec := range []api.DSPathSpec{
+		paths.NewUserPathManager(principal).Dashboard(id),
+		paths.SharedDashboard(id)} {
+		dashboard, err := readDashboard(config_obj, raw_db, path_spec)
+		if err == nil && dashboard.Id == id {
+			return dashboard, nil
+		}
+	}
+
+	return nil, dashboardNotFoundError
+}
+
+// Only the owner may change a shared dashboard unless the user is
+// an administrator.
+func checkDashboardOwner(config_obj *config_proto.Config,
+	principal string, dashboard *Dashboard) error {
+	if dashboard.Owner == principal {
+		return nil
+	}
+
+	ok, _ := services.CheckAccess(config_obj, principal, acls.SERVER_ADMIN)
+	if !ok {
+		return fmt.Errorf("Dashboard %v is owned by %v",
+			dashboard.Id, dashboard.Owner)
+	}
+	return nil
+}
+
+func setDashboard(config_obj *config_proto.Config,
+	principal string, dashboard *Dashboard) (*Dashboard, error) {
+	db, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Saving the default dashboard makes a copy.
+	if dashboard.Id == "" || dashboard.Id == DEFAULT_DASHBOARD_ID {
+		dashboard.Id = newDashboardId()
+		dashboard.Owner = principal
+
+	} else {
+		existing, err := getDashboard(config_obj, principal, dashboard.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkDashboardOwner(config_obj, principal, existing)
+		if err != nil {
+			return nil, err
+		}
+		dashboard.Owner = existing.Owner
+
+		// Remove the old copy in case the dashboard is no longer
+		// shared (or is newly shared).
+		if existing.Shared != dashboard.Shared {
+			err = deleteDashboardRecord(config_obj, db, existing)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if dashboard.Name == "" {
+		dashboard.Name = dashboard.Id
+	}
+	dashboard.ReadOnly = false
+	dashboard.Modified = utils.GetTime().Now().Unix()
+
+	for idx, widget := range dashboard.Widgets {
+		if widget.Id == "" {
+			widget.Id = fmt.Sprintf("W%d", idx)
+		}
+	}
+
+	serialized, err := json.Marshal(dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	err = raw_db.SetBuffer(config_obj, dashboardPath(dashboard),
+		serialized, utils.BackgroundWriter)
+	return dashboard, err
+}
+
+func dashboardPath(dashboard *Dashboard) api.DSPathSpec {
+	if dashboard.Shared {
+		return paths.SharedDashboard(dashboard.Id)
+	}
+	return paths.NewUserPathManager(dashboard.Owner).Dashboard(dashboard.Id)
+}
+
+func deleteDashboardRecord(config_obj *config_proto.Config,
+	db datastore.DataStore, dashboard *Dashboard) error {
+	return db.DeleteSubject(config_obj, dashboardPath(dashboard))
+}
+
+func deleteDashboard(config_obj *config_proto.Config,
+	principal, id string) error {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	existing, err := getDashboard(config_obj, principal, id)
+	if err != nil {
+		return err
+	}
+
+	if existing.ReadOnly {
+		return errors.New("The default dashboard can not be deleted")
+	}
+
+	err = checkDashboardOwner(config_obj, principal, existing)
+	if err != nil {
+		return err
+	}
+
+	return deleteDashboardRecord(config_obj, db, existing)
+}
+
+// Run the widget's query as the user viewing the dashboard. Shared
+// dashboards therefore never grant more access than the viewer
+// already has.
+func runDashboardWidget(ctx context.Context,
+	config_obj *config_proto.Config,
+	principal string, widget *DashboardWidget) ([]vfilter.Row, error) {
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     config_obj,
+		Env:        ordereddict.NewDict(),
+		ACLManager: acl_managers.NewServerACLManager(config_obj, principal),
+		Logger:     logging.NewPlainLogger(config_obj, &logging.GUIComponent),
+	})
+	defer scope.Close()
+
+	statements, err := vfilter.MultiParse(widget.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	sub_ctx, cancel := context.WithTimeout(ctx, MAX_WIDGET_TIMEOUT)
+	defer cancel()
+
+	result := []vfilter.Row{}
+	for _, vql := range statements {
+		for row := range vql.Eval(sub_ctx, scope) {
+			if len(result) >= MAX_WIDGET_ROWS {
+				return result, nil
+			}
+			result = append(result, vfilter.RowToDict(sub_ctx, scope, row))
+		}
+	}
+
+	return result, nil
+}
+
+type dashboardRequest struct {
+	Id       string     `json:"id"`
+	WidgetId string     `json:"widget_id"`
+	Delete   bool       `json:"delete"`
+	Update   *Dashboard `json:"dashboard"`
+}
+
+// Manage the user's dashboards. A GET request lists all dashboards
+// visible to the user, while POST requests create, update or delete
+// a dashboard.
+func dashboardsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		principal := userinfo.Name
+
+		perm, err := services.CheckAccess(
+			org_config_obj, principal, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view dashboards.")
+			return
+		}
+
+		if r.Method == "GET" {
+			dashboards, err := getDashboards(org_config_obj, principal)
+			if err != nil {
+				returnError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			writeJSONResponse(w, ordereddict.NewDict().
+				Set("dashboards", dashboards))
+			return
+		}
+
+		// Editing dashboards allows running arbitrary VQL so
+		// requires the same permission as notebooks.
+		perm, err = services.CheckAccess(
+			org_config_obj, principal, acls.NOTEBOOK_EDITOR)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to edit dashboards.")
+			return
+		}
+
+		request := &dashboardRequest{}
+		err = readJSONRequest(w, r, request)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		if request.Delete {
+			err = deleteDashboard(org_config_obj, principal, request.Id)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSONResponse(w, ordereddict.NewDict())
+			return
+		}
+
+		if request.Update == nil {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		dashboard, err := setDashboard(org_config_obj, principal, request.Update)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSONResponse(w, dashboard)
+	})
+}
+
+// Run a single widget from a dashboard and return its rows.
+func dashboardWidgetHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		principal := userinfo.Name
+
+		perm, err := services.CheckAccess(
+			org_config_obj, principal, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view dashboards.")
+			return
+		}
+
+		request := &dashboardRequest{}
+		err = readJSONRequest(w, r, request)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		dashboard, err := getDashboard(org_config_obj, principal, request.Id)
+		if err != nil {
+			returnError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		widget, pres := dashboard.GetWidget(request.WidgetId)
+		if !pres {
+			returnError(w, http.StatusNotFound, "Widget not found")
+			return
+		}
+
+		rows, err := runDashboardWidget(
+			r.Context(), org_config_obj, principal, widget)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Widgets are arbitrary queries so only policies for all
+		// results apply.
+		redactor, err := tables.GetRedactor(org_config_obj, principal, "")
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if redactor != nil {
+			for idx, row := range rows {
+				dict, ok := row.(*ordereddict.Dict)
+				if ok {
+					rows[idx] = redactor.RedactRow(dict)
+				}
+			}
+		}
+
+		writeJSONResponse(w, ordereddict.NewDict().Set("rows", rows))
+	})
+}
+
+func readJSONRequest(w http.ResponseWriter, r *http.Request,
+	target interface{}) error {
+	serialized, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(serialized, target)
+}
+
+func writeJSONResponse(w http.ResponseWriter, data interface{}) {
+	serialized, err := json.Marshal(data)
+	if err != nil {
+		returnError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(serialized)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type DashboardsTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *DashboardsTestSuite) TestDashboards() {
+	assert.NoError(self.T(), services.GrantRoles(
+		self.ConfigObj, "alice", []string{"analyst"}))
+	assert.NoError(self.T(), services.GrantRoles(
+		self.ConfigObj, "bob", []string{"analyst"}))
+	assert.NoError(self.T(), services.GrantRoles(
+		self.ConfigObj, "admin", []string{"administrator"}))
+
+	// Users without dashboards get the default.
+	dashboards, err := getDashboards(self.ConfigObj, "alice")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(dashboards))
+	assert.Equal(self.T(), DEFAULT_DASHBOARD_ID, dashboards[0].Id)
+
+	// Saving the default dashboard makes a private copy.
+	private := defaultDashboard()
+	private.Name = "Alice's dashboard"
+	private, err = setDashboard(self.ConfigObj, "alice", private)
+	assert.NoError(self.T(), err)
+	assert.Regexp(self.T(), "^D\\.", private.Id)
+	assert.Equal(self.T(), "alice", private.Owner)
+
+	dashboards, err = getDashboards(self.ConfigObj, "alice")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(dashboards))
+	assert.Equal(self.T(), private.Id, dashboards[0].Id)
+
+	// Bob can not see it.
+	_, err = getDashboard(self.ConfigObj, "bob", private.Id)
+	assert.Error(self.T(), err)
+
+	// Now share it.
+	private.Shared = true
+	shared, err := setDashboard(self.ConfigObj, "alice", private)
+	assert.NoError(self.T(), err)
+
+	dashboards, err = getDashboards(self.ConfigObj, "bob")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(dashboards))
+	assert.Equal(self.T(), shared.Id, dashboards[0].Id)
+
+	// Alice only has the shared copy now.
+	dashboards, err = getDashboards(self.ConfigObj, "alice")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(dashboards))
+
+	// Bob may not change or delete Alice's dashboard but an admin
+	// can.
+	shared.Name = "Bob's dashboard"
+	_, err = setDashboard(self.ConfigObj, "bob", shared)
+	assert.ErrorContains(self.T(), err, "owned by alice")
+
+	err = deleteDashboard(self.ConfigObj, "bob", shared.Id)
+	assert.Error(self.T(), err)
+
+	err = deleteDashboard(self.ConfigObj, "admin", shared.Id)
+	assert.NoError(self.T(), err)
+
+	dashboards, err = getDashboards(self.ConfigObj, "bob")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), DEFAULT_DASHBOARD_ID, dashboards[0].Id)
+
+	// Invalid ids are rejected.
+	_, err = getDashboard(self.ConfigObj, "alice", "../../acl/alice")
+	assert.Error(self.T(), err)
+}
+
+func (self *DashboardsTestSuite) TestRunWidget() {
+	assert.NoError(self.T(), services.GrantRoles(
+		self.ConfigObj, "alice", []string{"analyst"}))
+
+	rows, err := runDashboardWidget(context.Background(), self.ConfigObj,
+		"alice", &DashboardWidget{
+			Query: "LET X = 5 SELECT X + 1 AS Y FROM range(end=3)",
+		})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, len(rows))
+
+	// The built in widgets are valid VQL.
+	for _, widget := range defaultDashboard().Widgets {
+		_, err := vfilter.MultiParse(widget.Query)
+		assert.NoError(self.T(), err, widget.Id)
+	}
+
+	// Queries run with the user's own permissions.
+	rows, err = runDashboardWidget(context.Background(), self.ConfigObj,
+		"alice", &DashboardWidget{
+			Query: "SELECT * FROM execve(argv=['id'])",
+		})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(rows))
+}
+
+func TestDashboards(t *testing.T) {
+	suite.Run(t, &DashboardsTestSuite{})
+}
@@ -57,6 +57,9 @@ import (
 	"www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/reporting/parquet"
+	"www.velocidex.com/golang/velociraptor/reporting/siem"
+	"www.velocidex.com/golang/velociraptor/reporting/stix"
 	"www.velocidex.com/golang/velociraptor/result_sets"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/uploads"
@@ -133,34 +136,19 @@ func vfsFileDownloadHandler() http.Handler {
 			return
 		}
 
-		// Where to read from the file store
-		var path_spec api.FSPathSpec
-
-		// The filename for the attachment header.
-		var filename string
-
-		client_path_manager := paths.NewClientPathManager(request.ClientId)
-
-		// Newer API calls pass the filestore components directly
-		if len(request.FSComponents) > 0 {
-			path_spec = path_specs.NewUnsafeFilestorePath(request.FSComponents...).
-				SetType(api.PATH_TYPE_FILESTORE_ANY)
-
-			filename = utils.Base(request.VfsPath)
-
-			// Uploads table has direct vfs paths
-		} else if request.VfsPath != "" {
-			path_spec, err = client_path_manager.GetUploadsFileFromVFSPath(
-				request.VfsPath)
-			if err != nil {
-				returnError(w, 404, err.Error())
-				return
-			}
-			filename = path_spec.Base()
+		// File contents can not be redacted.
+		principal := GetUserInfo(r.Context(), org_config_obj).Name
+		if tables.IsRedactedUser(org_config_obj, principal) {
+			returnError(w, 403, "Auditors are not allowed to download files.")
+			return
+		}
 
-		} else {
-			// Just reject the request
-			returnError(w, 404, "")
+		// Where to read from the file store and the filename for
+		// the attachment header.
+		path_spec, filename, err := getVFSFilePathSpec(
+			request.ClientId, request.VfsPath, request.FSComponents)
+		if err != nil {
+			returnError(w, 404, err.Error())
 			return
 		}
 
@@ -265,6 +253,32 @@ func vfsFileDownloadHandler() http.Handler {
 	})
 }
 
+// Resolve the file store path of the file requested by the GUI.
+func getVFSFilePathSpec(client_id, vfs_path string, fs_components []string) (
+	path_spec api.FSPathSpec, filename string, err error) {
+
+	// Newer API calls pass the filestore components directly
+	if len(fs_components) > 0 {
+		path_spec = path_specs.NewUnsafeFilestorePath(fs_components...).
+			SetType(api.PATH_TYPE_FILESTORE_ANY)
+
+		return path_spec, utils.Base(vfs_path), nil
+	}
+
+	// Uploads table has direct vfs paths
+	if vfs_path != "" {
+		client_path_manager := paths.NewClientPathManager(client_id)
+		path_spec, err = client_path_manager.GetUploadsFileFromVFSPath(vfs_path)
+		if err != nil {
+			return nil, "", err
+		}
+		return path_spec, path_spec.Base(), nil
+	}
+
+	// Just reject the request
+	return nil, "", errors.New("No file specified")
+}
+
 func detectMime(buffer []byte, detect_mime bool) string {
 	if detect_mime && len(buffer) > 8 {
 		if 0 == bytes.Compare(
@@ -375,22 +389,23 @@ func downloadFileStore(prefix []string) http.Handler {
 			return
 		}
 
+		principal := GetUserInfo(r.Context(), org_config_obj).Name
+		if tables.IsRedactedUser(org_config_obj, principal) {
+			returnError(w, 403, "Auditors are not allowed to download files.")
+			return
+		}
+
 		file_store_factory := file_store.GetFileStore(org_config_obj)
 		fd, err := file_store_factory.ReadFile(path_spec)
 		if err != nil {
 			returnError(w, 404, err.Error())
 			return
 		}
+		defer fd.Close()
 
 		// From here on we already sent the headers and we can
 		// not really report an error to the client.
-		w.Header().Set("Content-Disposition", "attachment; "+
-			sanitizePathspecForAttachment(path_spec))
-
-		w.Header().Set("Content-Type", "binary/octet-stream")
-		w.WriteHeader(200)
-
-		utils.Copy(r.Context(), w, fd)
+		serveFileWithRanges(w, r, fd, path_spec)
 	})
 }
 
@@ -482,6 +497,20 @@ func downloadTable() http.Handler {
 			return
 		}
 
+		redactor, err := tables.GetRedactor(
+			org_config_obj, principal, request.Artifact)
+		if err != nil {
+			returnError(w, 500, err.Error())
+			return
+		}
+
+		if redactor != nil {
+			unredacted := transform
+			transform = func(row *ordereddict.Dict) *ordereddict.Dict {
+				return redactor.RedactRow(unredacted(row))
+			}
+		}
+
 		opts := json.GetJsonOptsForTimezone(request.Timezone)
 		switch request.DownloadFormat {
 		case "csv":
@@ -511,6 +540,103 @@ func downloadTable() http.Handler {
 			}
 			csv_writer.Close()
 
+		case "cef", "leef", "siem_jsonl":
+			format := strings.TrimPrefix(request.DownloadFormat, "siem_")
+			writer, err := siem.NewWriter(org_config_obj, format,
+				request.Artifact, w, opts)
+			if err != nil {
+				returnError(w, 400, err.Error())
+				return
+			}
+
+			download_name = strings.TrimSuffix(download_name, ".json")
+			download_name += "." + format
+
+			// From here on we already sent the headers and we can
+			// not really report an error to the client.
+			w.Header().Set("Content-Disposition", "attachment; "+
+				sanitizeFilenameForAttachment(download_name))
+			w.Header().Set("Content-Type", "binary/octet-stream")
+			w.WriteHeader(200)
+
+			services.LogAudit(r.Context(),
+				org_config_obj, principal, "DownloadTable",
+				ordereddict.NewDict().
+					Set("request", request).
+					Set("remote", r.RemoteAddr))
+
+			for row := range row_chan {
+				err := writer.Write(
+					filterColumns(request.Columns, transform(row)))
+				if err != nil {
+					break
+				}
+			}
+			writer.Close()
+
+		case "parquet":
+			download_name = strings.TrimSuffix(download_name, ".json")
+			download_name += ".parquet"
+
+			// From here on we already sent the headers and we can
+			// not really report an error to the client.
+			w.Header().Set("Content-Disposition", "attachment; "+
+				sanitizeFilenameForAttachment(download_name))
+			w.Header().Set("Content-Type", "binary/octet-stream")
+			w.WriteHeader(200)
+
+			services.LogAudit(r.Context(),
+				org_config_obj, principal, "DownloadTable",
+				ordereddict.NewDict().
+					Set("request", request).
+					Set("remote", r.RemoteAddr))
+
+			writer := parquet.NewWriter(w, opts)
+			for row := range row_chan {
+				err := writer.Write(
+					filterColumns(request.Columns, transform(row)))
+				if err != nil {
+					break
+				}
+			}
+			writer.Close()
+
+		case "stix":
+			builder := stix.NewBuilder(request.Artifact, opts)
+			builder.SetClientId(request.ClientId)
+
+			for row := range row_chan {
+				err := builder.AddRow(
+					filterColumns(request.Columns, transform(row)))
+				if err != nil {
+					returnError(w, 500, err.Error())
+					return
+				}
+			}
+
+			serialized, err := json.MarshalIndentWithOptions(
+				builder.Bundle(), opts)
+			if err != nil {
+				returnError(w, 500, err.Error())
+				return
+			}
+
+			download_name = strings.TrimSuffix(download_name, ".json")
+			download_name += ".stix.json"
+
+			w.Header().Set("Content-Disposition", "attachment; "+
+				sanitizeFilenameForAttachment(download_name))
+			w.Header().Set("Content-Type", "binary/octet-stream")
+			w.WriteHeader(200)
+
+			services.LogAudit(r.Context(),
+				org_config_obj, principal, "DownloadTable",
+				ordereddict.NewDict().
+					Set("request", request).
+					Set("remote", r.RemoteAddr))
+
+			_, _ = w.Write(serialized)
+
 			// Output in jsonl by default.
 		default:
 			if !strings.HasSuffix(download_name, ".json") {
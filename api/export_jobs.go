@@ -0,0 +1,203 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	api_utils "www.velocidex.com/golang/velociraptor/api/utils"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/export_jobs"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type exportJob struct {
+	*api_proto.ContainerStats
+
+	// A signed link to download the container - only set for
+	// finished jobs.
+	DownloadURL string `json:"download_url,omitempty"`
+	Expires     int64  `json:"expires,omitempty"`
+}
+
+// Build an expiring download link for the job.
+func newExportJob(config_obj *config_proto.Config,
+	stats *api_proto.ContainerStats) *exportJob {
+	result := &exportJob{ContainerStats: stats}
+	if stats.State != export_jobs.STATE_FINISHED {
+		return result
+	}
+
+	result.Expires = utils.GetTime().Now().Unix() +
+		export_jobs.LinkExpiry(config_obj)
+
+	query := url.Values{}
+	query.Set("org_id", config_obj.OrgId)
+	query.Set("job_id", stats.JobId)
+	query.Set("expires", strconv.FormatInt(result.Expires, 10))
+	query.Set("signature", export_jobs.SignLink(
+		config_obj, stats.JobId, result.Expires))
+
+	result.DownloadURL = api_utils.Join(api_utils.GetBasePath(config_obj),
+		"/api/v1/DownloadExport") + "?" + query.Encode()
+	return result
+}
+
+// List export jobs with their progress. If the "job_id" query
+// parameter is set only that job is returned.
+func exportJobsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		principal := GetUserInfo(r.Context(), org_config_obj).Name
+		perm, err := services.CheckAccess(
+			org_config_obj, principal, acls.PREPARE_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view export jobs.")
+			return
+		}
+
+		job_id := r.URL.Query().Get("job_id")
+		if job_id != "" {
+			stats, err := export_jobs.Get(org_config_obj, job_id)
+			if err != nil {
+				returnError(w, http.StatusNotFound, err.Error())
+				return
+			}
+			writeJSONResponse(w, newExportJob(org_config_obj, stats))
+			return
+		}
+
+		jobs, err := export_jobs.List(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		result := []*exportJob{}
+		for _, stats := range jobs {
+			result = append(result, newExportJob(org_config_obj, stats))
+		}
+		writeJSONResponse(w, ordereddict.NewDict().Set("jobs", result))
+	})
+}
+
+// Download the container of a finished export job. The request is
+// authorized by the link's signature rather than the user's session
+// so the link can be given to a download manager.
+func downloadExportHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(query.Get("org_id"))
+		if err != nil {
+			returnError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		job_id := query.Get("job_id")
+		expires, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+		err = export_jobs.VerifyLink(org_config_obj,
+			job_id, expires, query.Get("signature"))
+		if errors.Is(err, export_jobs.ErrLinkExpired) {
+			returnError(w, http.StatusGone, err.Error())
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		stats, err := export_jobs.Get(org_config_obj, job_id)
+		if err != nil || stats.State != export_jobs.STATE_FINISHED {
+			returnError(w, http.StatusNotFound, "Export is not available")
+			return
+		}
+
+		path_spec := path_specs.FromGenericComponentList(stats.Components)
+		fd, err := file_store.GetFileStore(org_config_obj).ReadFile(path_spec)
+		if err != nil {
+			returnError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		defer fd.Close()
+
+		// Only log the start of the download and not every resumed
+		// range.
+		if r.Header.Get("Range") == "" {
+			services.LogAudit(r.Context(),
+				org_config_obj, stats.Principal, "DownloadExport",
+				ordereddict.NewDict().
+					Set("job_id", job_id).
+					Set("components", stats.Components).
+					Set("remote", r.RemoteAddr))
+		}
+
+		serveFileWithRanges(w, r, fd, path_spec)
+	})
+}
+
+// Serve the file honoring HTTP range requests so large downloads can
+// be resumed.
+func serveFileWithRanges(w http.ResponseWriter, r *http.Request,
+	fd api.FileReader, path_spec api.FSPathSpec) {
+	w.Header().Set("Content-Disposition", "attachment; "+
+		sanitizePathspecForAttachment(path_spec))
+	w.Header().Set("Content-Type", "binary/octet-stream")
+
+	stat, err := fd.Stat()
+	if err != nil {
+		// Without the size we can not serve ranges.
+		w.WriteHeader(200)
+		utils.Copy(r.Context(), w, fd)
+		return
+	}
+
+	http.ServeContent(w, r, "", stat.ModTime(), &sizedReader{
+		FileReader: fd,
+		size:       stat.Size(),
+	})
+}
+
+// Filestore readers do not always support seeking relative to the
+// end of the file, which http.ServeContent uses to find the size.
+type sizedReader struct {
+	api.FileReader
+	size int64
+}
+
+func (self *sizedReader) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekEnd {
+		return self.FileReader.Seek(self.size+offset, io.SeekStart)
+	}
+	return self.FileReader.Seek(offset, whence)
+}
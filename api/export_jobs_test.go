@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services/export_jobs"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type ExportJobsTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *ExportJobsTestSuite) download(
+	url, byte_range string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", url, nil)
+	if byte_range != "" {
+		req.Header.Set("Range", byte_range)
+	}
+	w := httptest.NewRecorder()
+	downloadExportHandler().ServeHTTP(w, req)
+	return w
+}
+
+func (self *ExportJobsTestSuite) TestDownloadExport() {
+	closer := utils.MockTime(utils.NewMockClock(time.Unix(1000, 0)))
+	defer closer()
+
+	container_path := path_specs.NewUnsafeFilestorePath("downloads", "test").
+		SetType(api.PATH_TYPE_FILESTORE_DOWNLOAD_ZIP)
+
+	fd, err := file_store.GetFileStore(self.ConfigObj).WriteFile(container_path)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("0123456789abcdef"))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	job_id := export_jobs.NewJobId()
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	stats := &api_proto.ContainerStats{
+		JobId:      job_id,
+		Principal:  "admin",
+		State:      export_jobs.STATE_RUNNING,
+		Components: path_specs.AsGenericComponentList(container_path),
+	}
+	assert.NoError(self.T(), db.SetSubject(self.ConfigObj,
+		paths.ExportJobPath(job_id), stats))
+
+	// Running jobs have no link.
+	job := newExportJob(self.ConfigObj, stats)
+	assert.Equal(self.T(), "", job.DownloadURL)
+
+	stats.State = export_jobs.STATE_FINISHED
+	assert.NoError(self.T(), db.SetSubject(self.ConfigObj,
+		paths.ExportJobPath(job_id), stats))
+
+	job = newExportJob(self.ConfigObj, stats)
+	assert.Equal(self.T(), int64(1000+export_jobs.DEFAULT_LINK_EXPIRY),
+		job.Expires)
+
+	w := self.download(job.DownloadURL, "")
+	assert.Equal(self.T(), http.StatusOK, w.Code)
+	assert.Equal(self.T(), "0123456789abcdef", w.Body.String())
+	assert.Equal(self.T(), "bytes", w.Header().Get("Accept-Ranges"))
+
+	// Resume the download.
+	w = self.download(job.DownloadURL, "bytes=10-")
+	assert.Equal(self.T(), http.StatusPartialContent, w.Code)
+	assert.Equal(self.T(), "abcdef", w.Body.String())
+	assert.Equal(self.T(), "bytes 10-15/16", w.Header().Get("Content-Range"))
+
+	// Tampering with the link invalidates it.
+	w = self.download(job.DownloadURL+"0", "")
+	assert.Equal(self.T(), http.StatusForbidden, w.Code)
+
+	// The link expires.
+	closer = utils.MockTime(utils.NewMockClock(
+		time.Unix(1001+export_jobs.DEFAULT_LINK_EXPIRY, 0)))
+	defer closer()
+
+	w = self.download(job.DownloadURL, "")
+	assert.Equal(self.T(), http.StatusGone, w.Code)
+}
+
+func TestExportJobs(t *testing.T) {
+	suite.Run(t, &ExportJobsTestSuite{})
+}
@@ -0,0 +1,296 @@
+package api
+
+import (
+	"net/http"
+
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// Report the hunt's scheduling progress and estimated completion
+// time. The hunt is selected by the "hunt_id" query parameter.
+func huntProgressHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view hunt results.")
+			return
+		}
+
+		hunt_id := r.URL.Query().Get("hunt_id")
+		if hunt_id == "" {
+			returnError(w, http.StatusBadRequest, "hunt_id must be specified")
+			return
+		}
+
+		hunt_dispatcher, err := services.GetHuntDispatcher(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		progress, err := hunt_dispatcher.GetHuntProgress(
+			r.Context(), org_config_obj, hunt_id)
+		if err != nil {
+			returnError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, progress)
+	})
+}
+
+type huntSchedulingRateRequest struct {
+	HuntId           string  `json:"hunt_id"`
+	ClientsPerMinute float64 `json:"clients_per_minute"`
+}
+
+// Get (GET) or change (POST) the rate at which new clients are
+// scheduled on the hunt.
+func huntSchedulingRateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		hunt_dispatcher, err := services.GetHuntDispatcher(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		if r.Method == "GET" {
+			perm, err := services.CheckAccess(
+				org_config_obj, userinfo.Name, acls.READ_RESULTS)
+			if !perm || err != nil {
+				returnError(w, http.StatusUnauthorized,
+					"User is not allowed to view hunts.")
+				return
+			}
+
+			hunt_id := r.URL.Query().Get("hunt_id")
+			clients_per_minute, err := hunt_dispatcher.GetHuntSchedulingRate(
+				org_config_obj, hunt_id)
+			if err != nil {
+				returnError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			writeJSONResponse(w, &huntSchedulingRateRequest{
+				HuntId:           hunt_id,
+				ClientsPerMinute: clients_per_minute,
+			})
+			return
+		}
+
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.START_HUNT)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to modify hunts.")
+			return
+		}
+
+		request := &huntSchedulingRateRequest{}
+		err = readJSONRequest(w, r, request)
+		if err != nil || request.HuntId == "" {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		err = hunt_dispatcher.SetHuntSchedulingRate(r.Context(),
+			org_config_obj, request.HuntId, request.ClientsPerMinute)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, request)
+	})
+}
+
+type huntVQLConditionRequest struct {
+	HuntId string `json:"hunt_id"`
+	Query  string `json:"query"`
+}
+
+// Get (GET) or change (POST) the VQL query which selects the clients
+// the hunt applies to.
+func huntVQLConditionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		hunt_dispatcher, err := services.GetHuntDispatcher(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		if r.Method == "GET" {
+			perm, err := services.CheckAccess(
+				org_config_obj, userinfo.Name, acls.READ_RESULTS)
+			if !perm || err != nil {
+				returnError(w, http.StatusUnauthorized,
+					"User is not allowed to view hunts.")
+				return
+			}
+
+			hunt_id := r.URL.Query().Get("hunt_id")
+			condition, err := hunt_dispatcher.GetHuntVQLCondition(
+				org_config_obj, hunt_id)
+			if err != nil {
+				returnError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			writeJSONResponse(w, &huntVQLConditionRequest{
+				HuntId: hunt_id,
+				Query:  condition.Query,
+			})
+			return
+		}
+
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.START_HUNT)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to modify hunts.")
+			return
+		}
+
+		request := &huntVQLConditionRequest{}
+		err = readJSONRequest(w, r, request)
+		if err != nil || request.HuntId == "" {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		// The condition runs with the permissions of the user who
+		// set it.
+		err = hunt_dispatcher.SetHuntVQLCondition(r.Context(),
+			org_config_obj, request.HuntId, userinfo.Name, request.Query)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, request)
+	})
+}
+
+type huntQuotaRequest struct {
+	HuntId string `json:"hunt_id"`
+	services.HuntQuota
+}
+
+// Get (GET) or change (POST) the total resources the hunt's
+// collections may use before the hunt is stopped.
+func huntQuotaHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		hunt_dispatcher, err := services.GetHuntDispatcher(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		if r.Method == "GET" {
+			perm, err := services.CheckAccess(
+				org_config_obj, userinfo.Name, acls.READ_RESULTS)
+			if !perm || err != nil {
+				returnError(w, http.StatusUnauthorized,
+					"User is not allowed to view hunts.")
+				return
+			}
+
+			hunt_id := r.URL.Query().Get("hunt_id")
+			quota, err := hunt_dispatcher.GetHuntQuota(org_config_obj, hunt_id)
+			if err != nil {
+				returnError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			writeJSONResponse(w, &huntQuotaRequest{
+				HuntId:    hunt_id,
+				HuntQuota: *quota,
+			})
+			return
+		}
+
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.START_HUNT)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to modify hunts.")
+			return
+		}
+
+		request := &huntQuotaRequest{}
+		err = readJSONRequest(w, r, request)
+		if err != nil || request.HuntId == "" {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		err = hunt_dispatcher.SetHuntQuota(r.Context(),
+			org_config_obj, request.HuntId, &request.HuntQuota)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, request)
+	})
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/api/tables"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// Stack the hunt's results: count how many clients returned each
+// distinct value. The hunt is selected by the "hunt_id" query
+// parameter, the source by "artifact" and the stacked columns by a
+// comma separated "columns" parameter.
+func huntStackHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view hunt results.")
+			return
+		}
+
+		query := r.URL.Query()
+		hunt_id := query.Get("hunt_id")
+		if hunt_id == "" {
+			returnError(w, http.StatusBadRequest, "hunt_id must be specified")
+			return
+		}
+
+		hunt_dispatcher, err := services.GetHuntDispatcher(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		columns := []string{}
+		for _, column := range strings.Split(query.Get("columns"), ",") {
+			column = strings.TrimSpace(column)
+			if column != "" {
+				columns = append(columns, column)
+			}
+		}
+
+		stack, err := hunt_dispatcher.StackHuntResults(r.Context(),
+			org_config_obj, hunt_id, query.Get("artifact"), columns)
+		if err != nil {
+			returnError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		redactor, err := tables.GetRedactor(
+			org_config_obj, userinfo.Name, query.Get("artifact"))
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if redactor != nil {
+			for _, row := range stack.Rows {
+				row.Value = redactor.RedactRow(row.Value)
+			}
+		}
+
+		writeJSONResponse(w, stack)
+	})
+}
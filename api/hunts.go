@@ -17,6 +17,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/json"
 	vjson "www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/approvals"
 	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
@@ -139,6 +140,25 @@ func (self *ApiServer) CreateHunt(
 		orgs = append(orgs, org_config_obj.OrgId)
 	}
 
+	// Dangerous or large hunts wait for another user to approve
+	// them.
+	approval_service := approvals.GetApprovalService(org_config_obj)
+	if approval_service != nil {
+		reasons, estimate, err := approval_service.CheckHunt(ctx, in)
+		if err != nil {
+			return nil, Status(self.verbose, err)
+		}
+
+		if len(reasons) > 0 {
+			request, err := approval_service.RequestHunt(
+				ctx, principal, in, reasons, estimate)
+			if err != nil {
+				return nil, Status(self.verbose, err)
+			}
+			return nil, ApprovalRequired(request.Id, reasons)
+		}
+	}
+
 	org_manager, err := services.GetOrgManager()
 	if err != nil {
 		return nil, Status(self.verbose, err)
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/legal_hold"
+)
+
+type legalHoldRequest struct {
+	Hold    *legal_hold.Hold `json:"hold"`
+	Release bool             `json:"release"`
+}
+
+// List, place and release legal holds. A GET request lists all
+// holds, with the volume of held data if the "report" query
+// parameter is set. A POST request places the hold, or releases it
+// if release is set.
+func legalHoldsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		principal := userinfo.Name
+
+		perm, err := services.CheckAccess(
+			org_config_obj, principal, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view legal holds.")
+			return
+		}
+
+		if r.Method == "GET" {
+			var holds []*legal_hold.Hold
+			if r.URL.Query().Get("report") != "" {
+				holds, err = legal_hold.Report(r.Context(), org_config_obj)
+			} else {
+				holds, err = legal_hold.List(org_config_obj)
+			}
+			if err != nil {
+				returnError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			writeJSONResponse(w, ordereddict.NewDict().
+				Set("holds", holds))
+			return
+		}
+
+		// Releasing a hold allows the data to be deleted so it
+		// requires the same permission as deleting clients and
+		// hunts.
+		perm, err = services.CheckAccess(
+			org_config_obj, principal, acls.SERVER_ADMIN)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to manage legal holds.")
+			return
+		}
+
+		request := &legalHoldRequest{}
+		err = readJSONRequest(w, r, request)
+		if err != nil || request.Hold == nil {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		if request.Release {
+			err = legal_hold.Release(r.Context(), org_config_obj,
+				principal, request.Hold)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSONResponse(w, ordereddict.NewDict())
+			return
+		}
+
+		hold, err := legal_hold.Place(r.Context(), org_config_obj,
+			principal, request.Hold)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSONResponse(w, hold)
+	})
+}
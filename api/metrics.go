@@ -0,0 +1,60 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/vql/psutils"
+)
+
+var (
+	filestoreMetricsOnce sync.Once
+)
+
+// Report the disk usage of the volume holding the file store. Running
+// out of space is one of the most common causes of server outages so
+// it is worth alerting on.
+type filestoreUsageCollector struct {
+	location string
+
+	total, used, free *prometheus.Desc
+}
+
+func (self *filestoreUsageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- self.total
+	ch <- self.used
+	ch <- self.free
+}
+
+func (self *filestoreUsageCollector) Collect(ch chan<- prometheus.Metric) {
+	usage, err := psutils.Usage(self.location)
+	if err != nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		self.total, prometheus.GaugeValue, float64(usage.Total))
+	ch <- prometheus.MustNewConstMetric(
+		self.used, prometheus.GaugeValue, float64(usage.Used))
+	ch <- prometheus.MustNewConstMetric(
+		self.free, prometheus.GaugeValue, float64(usage.Free))
+}
+
+func registerFilestoreMetrics(config_obj *config_proto.Config) {
+	if config_obj.Datastore == nil || config_obj.Datastore.Location == "" {
+		return
+	}
+
+	filestoreMetricsOnce.Do(func() {
+		prometheus.MustRegister(&filestoreUsageCollector{
+			location: config_obj.Datastore.Location,
+			total: prometheus.NewDesc("filestore_disk_total_bytes",
+				"Total size of the volume holding the file store.", nil, nil),
+			used: prometheus.NewDesc("filestore_disk_used_bytes",
+				"Used space on the volume holding the file store.", nil, nil),
+			free: prometheus.NewDesc("filestore_disk_free_bytes",
+				"Free space on the volume holding the file store.", nil, nil),
+		})
+	})
+}
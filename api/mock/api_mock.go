@@ -1241,6 +1241,26 @@ func (mr *MockAPIClientMockRecorder) SetUserRoles(arg0, arg1 interface{}, arg2 .
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRoles", reflect.TypeOf((*MockAPIClient)(nil).SetUserRoles), varargs...)
 }
 
+// SubscribeEvents mocks base method.
+func (m *MockAPIClient) SubscribeEvents(arg0 context.Context, arg1 *proto0.EventSubscriptionRequest, arg2 ...grpc.CallOption) (proto0.API_SubscribeEventsClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SubscribeEvents", varargs...)
+	ret0, _ := ret[0].(proto0.API_SubscribeEventsClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeEvents indicates an expected call of SubscribeEvents.
+func (mr *MockAPIClientMockRecorder) SubscribeEvents(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvents", reflect.TypeOf((*MockAPIClient)(nil).SubscribeEvents), varargs...)
+}
+
 // UpdateNotebook mocks base method.
 func (m *MockAPIClient) UpdateNotebook(arg0 context.Context, arg1 *proto0.NotebookMetadata, arg2 ...grpc.CallOption) (*proto0.NotebookMetadata, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,342 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	api_utils "www.velocidex.com/golang/velociraptor/api/utils"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// The version of the stable REST API. Methods listed in
+	// restMethods keep their paths and request/response shapes for
+	// the life of this version.
+	REST_API_VERSION = "v1"
+)
+
+type restMethod struct {
+	Name    string
+	Tag     string
+	Summary string
+}
+
+// The subset of the API covered by the stable REST gateway. Other
+// methods are reachable over the same gateway but are used by the
+// GUI and may change between releases.
+var restMethods = []restMethod{
+	{"ListClients", "Clients", "Search for clients."},
+	{"GetClient", "Clients", "Get information about a client."},
+	{"GetClientMetadata", "Clients", "Get the client's metadata."},
+	{"LabelClients", "Clients", "Add or remove labels on clients."},
+	{"GetArtifacts", "Artifacts", "Search the artifact repository."},
+	{"CollectArtifact", "Flows", "Schedule an artifact collection on a client or the server."},
+	{"CancelFlow", "Flows", "Cancel a running collection."},
+	{"GetClientFlows", "Flows", "List the collections of a client."},
+	{"GetFlowDetails", "Flows", "Get the details of a collection."},
+	{"GetTable", "Results", "Get a page of collection results, logs or uploads."},
+	{"GetHuntResults", "Results", "Get a page of results for an artifact collected by a hunt."},
+	{"CreateHunt", "Hunts", "Create a new hunt."},
+	{"EstimateHunt", "Hunts", "Estimate the number of clients a hunt will target."},
+	{"ListHunts", "Hunts", "List hunts."},
+	{"GetHunt", "Hunts", "Get the details of a hunt."},
+	{"ModifyHunt", "Hunts", "Start, stop, archive or update a hunt."},
+	{"GetHuntFlows", "Hunts", "List the collections scheduled by a hunt."},
+}
+
+var pathParamRegex = regexp.MustCompile(`{([^}]+)}`)
+
+type openAPIBuilder struct {
+	schemas *ordereddict.Dict
+}
+
+func (self *openAPIBuilder) ref(desc protoreflect.MessageDescriptor) *ordereddict.Dict {
+	switch desc.FullName() {
+	case "google.protobuf.Empty", "google.protobuf.Struct", "google.protobuf.Any":
+		return ordereddict.NewDict().Set("type", "object")
+	case "google.protobuf.Timestamp":
+		return ordereddict.NewDict().
+			Set("type", "string").Set("format", "date-time")
+	case "google.protobuf.Value":
+		return ordereddict.NewDict()
+	}
+
+	name := string(desc.FullName())
+	_, pres := self.schemas.Get(name)
+	if !pres {
+		// Reserve the name first so recursive messages terminate.
+		self.schemas.Set(name, nil)
+		self.schemas.Set(name, self.messageSchema(desc))
+	}
+
+	return ordereddict.NewDict().Set("$ref", "#/components/schemas/"+name)
+}
+
+func (self *openAPIBuilder) messageSchema(
+	desc protoreflect.MessageDescriptor) *ordereddict.Dict {
+	properties := ordereddict.NewDict()
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		properties.Set(string(field.Name()), self.fieldSchema(field))
+	}
+
+	return ordereddict.NewDict().
+		Set("type", "object").
+		Set("properties", properties)
+}
+
+func (self *openAPIBuilder) fieldSchema(
+	field protoreflect.FieldDescriptor) *ordereddict.Dict {
+	if field.IsMap() {
+		return ordereddict.NewDict().
+			Set("type", "object").
+			Set("additionalProperties", self.singularSchema(field.MapValue()))
+	}
+
+	if field.IsList() {
+		return ordereddict.NewDict().
+			Set("type", "array").
+			Set("items", self.singularSchema(field))
+	}
+
+	return self.singularSchema(field)
+}
+
+// Types follow the protojson mapping used by the gateway.
+func (self *openAPIBuilder) singularSchema(
+	field protoreflect.FieldDescriptor) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		result.Set("type", "boolean")
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind,
+		protoreflect.Sfixed32Kind:
+		result.Set("type", "integer").Set("format", "int32")
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		result.Set("type", "integer").Set("format", "int64")
+
+	// 64 bit integers are encoded as strings to avoid loss of
+	// precision in JavaScript.
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind,
+		protoreflect.Sfixed64Kind, protoreflect.Uint64Kind,
+		protoreflect.Fixed64Kind:
+		result.Set("type", "string").Set("format", "int64")
+
+	case protoreflect.FloatKind:
+		result.Set("type", "number").Set("format", "float")
+
+	case protoreflect.DoubleKind:
+		result.Set("type", "number").Set("format", "double")
+
+	case protoreflect.StringKind:
+		result.Set("type", "string")
+
+	case protoreflect.BytesKind:
+		result.Set("type", "string").Set("format", "byte")
+
+	case protoreflect.EnumKind:
+		values := []string{}
+		enum_values := field.Enum().Values()
+		for i := 0; i < enum_values.Len(); i++ {
+			values = append(values, string(enum_values.Get(i).Name()))
+		}
+		result.Set("type", "string").Set("enum", values)
+
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return self.ref(field.Message())
+	}
+
+	return result
+}
+
+// Query parameters for GET requests. The gateway only maps scalar
+// fields of the request message.
+func (self *openAPIBuilder) queryParameters(
+	desc protoreflect.MessageDescriptor,
+	path_params []string) []*ordereddict.Dict {
+	result := []*ordereddict.Dict{}
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		name := string(field.Name())
+		if field.IsMap() || utils.InString(path_params, name) ||
+			field.Kind() == protoreflect.MessageKind ||
+			field.Kind() == protoreflect.GroupKind {
+			continue
+		}
+
+		result = append(result, ordereddict.NewDict().
+			Set("name", name).
+			Set("in", "query").
+			Set("schema", self.fieldSchema(field)))
+	}
+	return result
+}
+
+func (self *openAPIBuilder) operation(
+	method restMethod,
+	desc protoreflect.MethodDescriptor,
+	path string, has_body bool) *ordereddict.Dict {
+
+	path_params := []string{}
+	parameters := []*ordereddict.Dict{}
+	for _, match := range pathParamRegex.FindAllStringSubmatch(path, -1) {
+		path_params = append(path_params, match[1])
+		parameters = append(parameters, ordereddict.NewDict().
+			Set("name", match[1]).
+			Set("in", "path").
+			Set("required", true).
+			Set("schema", ordereddict.NewDict().Set("type", "string")))
+	}
+
+	result := ordereddict.NewDict().
+		Set("operationId", method.Name).
+		Set("summary", method.Summary).
+		Set("tags", []string{method.Tag})
+
+	if !has_body {
+		parameters = append(parameters,
+			self.queryParameters(desc.Input(), path_params)...)
+	}
+
+	if len(parameters) > 0 {
+		result.Set("parameters", parameters)
+	}
+
+	if has_body {
+		result.Set("requestBody", ordereddict.NewDict().
+			Set("required", true).
+			Set("content", ordereddict.NewDict().
+				Set("application/json", ordereddict.NewDict().
+					Set("schema", self.ref(desc.Input())))))
+	}
+
+	return result.Set("responses", ordereddict.NewDict().
+		Set("200", ordereddict.NewDict().
+			Set("description", "Success").
+			Set("content", ordereddict.NewDict().
+				Set("application/json", ordereddict.NewDict().
+					Set("schema", self.ref(desc.Output()))))).
+		Set("default", ordereddict.NewDict().
+			Set("description", "Error").
+			Set("content", ordereddict.NewDict().
+				Set("application/json", ordereddict.NewDict().
+					Set("schema", ordereddict.NewDict().
+						Set("$ref", "#/components/schemas/Status"))))))
+}
+
+// Build the OpenAPI 3 document describing the stable REST API. The
+// document is generated from the gRPC service descriptors and their
+// HTTP annotations so it always matches the gateway.
+func GetOpenAPISpec(server_url string) (*ordereddict.Dict, error) {
+	service := api_proto.File_api_proto.Services().ByName("API")
+	if service == nil {
+		return nil, errors.New("API service descriptor not found")
+	}
+
+	builder := &openAPIBuilder{schemas: ordereddict.NewDict()}
+
+	builder.schemas.Set("Status", ordereddict.NewDict().
+		Set("type", "object").
+		Set("properties", ordereddict.NewDict().
+			Set("code", ordereddict.NewDict().
+				Set("type", "integer").Set("format", "int32")).
+			Set("message", ordereddict.NewDict().Set("type", "string"))))
+
+	paths := ordereddict.NewDict()
+	for _, method := range restMethods {
+		desc := service.Methods().ByName(protoreflect.Name(method.Name))
+		if desc == nil {
+			return nil, fmt.Errorf("Method %v not found in API service", method.Name)
+		}
+
+		rule, ok := proto.GetExtension(
+			desc.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+
+		verb, path, has_body := "get", rule.GetGet(), false
+		if rule.GetPost() != "" {
+			verb, path, has_body = "post", rule.GetPost(), rule.GetBody() != ""
+		}
+
+		path_item, pres := paths.Get(path)
+		if !pres {
+			path_item = ordereddict.NewDict()
+			paths.Set(path, path_item)
+		}
+		path_item.(*ordereddict.Dict).Set(verb,
+			builder.operation(method, desc, path, has_body))
+	}
+
+	result := ordereddict.NewDict().
+		Set("openapi", "3.0.3").
+		Set("info", ordereddict.NewDict().
+			Set("title", "Velociraptor REST API").
+			Set("description", "The stable subset of the Velociraptor API. "+
+				"Requests are authenticated by the GUI authenticator and "+
+				"state changing requests must carry the X-CSRF-Token header "+
+				"returned with every authenticated response.").
+			Set("version", REST_API_VERSION).
+			Set("x-velociraptor-version", config.GetVersion().Version))
+
+	if server_url != "" {
+		result.Set("servers", []*ordereddict.Dict{
+			ordereddict.NewDict().Set("url", strings.TrimSuffix(server_url, "/")),
+		})
+	}
+
+	return result.
+		Set("paths", paths).
+		Set("components", ordereddict.NewDict().
+			Set("schemas", builder.schemas).
+			Set("securitySchemes", ordereddict.NewDict().
+				Set("basicAuth", ordereddict.NewDict().
+					Set("type", "http").
+					Set("scheme", "basic")))).
+		Set("security", []*ordereddict.Dict{
+			ordereddict.NewDict().Set("basicAuth", []string{}),
+		}), nil
+}
+
+// Serve the OpenAPI document so users can generate SDKs against
+// their server.
+func openAPIHandler(config_obj *config_proto.Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server_url := config_obj.GUI.PublicUrl
+		if server_url == "" {
+			server_url = api_utils.GetBasePath(config_obj)
+		}
+
+		spec, err := GetOpenAPISpec(server_url)
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		serialized, err := json.MarshalIndent(spec)
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(serialized)
+	})
+}
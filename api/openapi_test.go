@@ -0,0 +1,63 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func getPath(t *testing.T, item *ordereddict.Dict, components ...string) interface{} {
+	var result interface{} = item
+	for _, c := range components {
+		dict, ok := result.(*ordereddict.Dict)
+		if !ok {
+			t.Fatalf("%v is not a dict", c)
+		}
+		result, ok = dict.Get(c)
+		if !ok {
+			t.Fatalf("%v not found", c)
+		}
+	}
+	return result
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	spec, err := GetOpenAPISpec("https://velociraptor.example.com/")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "v1", getPath(t, spec, "info", "version"))
+
+	// Every stable method is described.
+	operations := 0
+	paths := getPath(t, spec, "paths").(*ordereddict.Dict)
+	for _, path := range paths.Keys() {
+		item, _ := paths.Get(path)
+		operations += item.(*ordereddict.Dict).Len()
+	}
+	assert.Equal(t, len(restMethods), operations)
+
+	// Path parameters are required, remaining fields are in the
+	// query string.
+	params := getPath(t, spec, "paths", "/api/v1/GetClient/{client_id}",
+		"get", "parameters").([]*ordereddict.Dict)
+	name, _ := params[0].GetString("in")
+	assert.Equal(t, "path", name)
+	name, _ = params[1].GetString("in")
+	assert.Equal(t, "query", name)
+
+	// Post methods take the request as the body.
+	assert.Equal(t, "#/components/schemas/proto.ArtifactCollectorArgs",
+		getPath(t, spec, "paths", "/api/v1/CollectArtifact", "post",
+			"requestBody", "content", "application/json", "schema", "$ref"))
+
+	// Referenced messages are defined with the gateway's JSON
+	// encoding.
+	assert.Equal(t, "string", getPath(t, spec, "components", "schemas",
+		"proto.ApiClient", "properties", "first_seen_at", "type"))
+	assert.Equal(t, "array", getPath(t, spec, "components", "schemas",
+		"proto.ApiClient", "properties", "labels", "type"))
+	assert.Equal(t, "#/components/schemas/proto.AgentInformation",
+		getPath(t, spec, "components", "schemas",
+			"proto.ApiClient", "properties", "agent_information", "$ref"))
+}
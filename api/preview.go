@@ -0,0 +1,143 @@
+package api
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/gorilla/schema"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/api/preview"
+	"www.velocidex.com/golang/velociraptor/api/tables"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type vfsFilePreviewRequest struct {
+	ClientId     string   `schema:"client_id"`
+	VfsPath      string   `schema:"vfs_path"`
+	FSComponents []string `schema:"fs_components[]"`
+	OrgId        string   `schema:"org_id"`
+
+	// The type of preview: hex, strings, pe, registry or
+	// thumbnail. If not specified we return the detected type of
+	// the file.
+	Type string `schema:"type"`
+
+	// The range of the file for hex and strings previews.
+	Offset int64 `schema:"offset"`
+	Length int64 `schema:"length"`
+
+	// The maximum width or height of thumbnails.
+	MaxSize int `schema:"max_size"`
+}
+
+// URL format: /api/v1/PreviewVFSFile
+
+// Returns previews of files in the filestore so they can be examined
+// without downloading them.
+func vfsFilePreviewHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := vfsFilePreviewRequest{}
+		decoder := schema.NewDecoder()
+		decoder.IgnoreUnknownKeys(true)
+
+		err := decoder.Decode(&request, r.URL.Query())
+		if err != nil {
+			returnError(w, 403, "Error "+err.Error())
+			return
+		}
+
+		org_id := request.OrgId
+		if org_id == "" {
+			org_id = authenticators.GetOrgIdFromRequest(r)
+		}
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, 404, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, 404, err.Error())
+			return
+		}
+
+		principal := GetUserInfo(r.Context(), org_config_obj).Name
+		perm, err := services.CheckAccess(
+			org_config_obj, principal, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view files.")
+			return
+		}
+
+		// Previews show file contents which can not be redacted.
+		if tables.IsRedactedUser(org_config_obj, principal) {
+			returnError(w, 403, "Auditors are not allowed to preview files.")
+			return
+		}
+
+		path_spec, _, err := getVFSFilePathSpec(
+			request.ClientId, request.VfsPath, request.FSComponents)
+		if err != nil {
+			returnError(w, 404, err.Error())
+			return
+		}
+
+		file, err := file_store.GetFileStore(org_config_obj).ReadFile(path_spec)
+		if err != nil {
+			returnError(w, 404, err.Error())
+			return
+		}
+		defer file.Close()
+
+		var reader io.ReaderAt = utils.MakeReaderAtter(file)
+		size := int64(calculateTotalReaderSize(file))
+
+		var result interface{}
+		switch request.Type {
+		case "":
+			result = ordereddict.NewDict().
+				Set("type", preview.Detect(reader)).
+				Set("size", size)
+
+		case "hex":
+			result, err = preview.HexDump(reader, request.Offset, request.Length)
+
+		case "strings":
+			result, err = preview.Strings(reader, request.Offset, request.Length)
+
+		case "pe":
+			result, err = preview.PEInfo(reader, size)
+
+		case "registry":
+			result, err = preview.RegistrySummary(reader)
+
+		case "thumbnail":
+			thumbnail, err := preview.Thumbnail(reader, size, request.MaxSize)
+			if err != nil {
+				returnError(w, 400, err.Error())
+				return
+			}
+			w.Header().Set("Content-Type", "image/png")
+			w.WriteHeader(200)
+			_, _ = w.Write(thumbnail)
+			return
+
+		default:
+			returnError(w, 400, "Unknown preview type "+request.Type)
+			return
+		}
+
+		if err != nil {
+			returnError(w, 400, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, result)
+	})
+}
@@ -0,0 +1,49 @@
+package preview
+
+import (
+	"io"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/go-pe"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Summarize the PE headers. Only cheap to compute properties are
+// included - the full parse_pe() output is available through VQL.
+func PEInfo(reader io.ReaderAt, size int64) (result *ordereddict.Dict, err error) {
+	defer func() {
+		// go-pe may panic on corrupted files.
+		r := recover()
+		if r != nil {
+			result = nil
+			err = notSupportedError
+		}
+	}()
+
+	pe_file, err := pe.NewPEFileWithSize(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := pe_file.Imports()
+	exports := pe_file.Exports()
+
+	return ordereddict.NewDict().
+		Set("FileHeader", pe_file.FileHeader).
+		Set("GUIDAge", pe_file.GUIDAge).
+		Set("PDB", pe_file.PDB).
+		Set("VersionInformation", pe_file.VersionInformation()).
+		Set("Sections", pe_file.Sections).
+		Set("ImportCount", len(imports)).
+		Set("Imports", truncate(imports, 100)).
+		Set("ExportCount", len(exports)).
+		Set("Exports", truncate(exports, 100)).
+		Set("ImpHash", pe_file.ImpHash()), nil
+}
+
+func truncate(in []string, length int) []string {
+	if len(in) > length {
+		return utils.CopySlice(in[:length])
+	}
+	return in
+}
@@ -0,0 +1,208 @@
+/*
+  Produce previews of uploaded files so they can be inspected in the
+  GUI without downloading them.
+*/
+
+package preview
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"unicode/utf16"
+)
+
+const (
+	// The largest hex dump returned in one request.
+	MAX_HEX_LENGTH = 64 * 1024
+
+	// How much of the file we scan for strings in one request.
+	MAX_STRINGS_LENGTH = 4 * 1024 * 1024
+
+	// Stop after this many strings.
+	MAX_STRINGS = 2000
+
+	// Strings shorter than this are not reported.
+	MIN_STRING_LENGTH = 4
+
+	HEX_LINE_LENGTH = 16
+)
+
+var (
+	notSupportedError = errors.New("Preview is not supported for this file")
+)
+
+// The kind of preview that makes sense for the file.
+const (
+	TYPE_BINARY   = "binary"
+	TYPE_PE       = "pe"
+	TYPE_REGISTRY = "registry"
+	TYPE_IMAGE    = "image"
+)
+
+// Guess the type of the file from its first few bytes.
+func Detect(reader io.ReaderAt) string {
+	header := make([]byte, 16)
+	n, _ := reader.ReadAt(header, 0)
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, []byte("MZ")):
+		return TYPE_PE
+
+	case bytes.HasPrefix(header, []byte("regf")):
+		return TYPE_REGISTRY
+
+	case bytes.HasPrefix(header, []byte("\x89PNG\r\n\x1a\n")),
+		bytes.HasPrefix(header, []byte("\xff\xd8\xff")),
+		bytes.HasPrefix(header, []byte("GIF87a")),
+		bytes.HasPrefix(header, []byte("GIF89a")):
+		return TYPE_IMAGE
+	}
+
+	return TYPE_BINARY
+}
+
+type HexLine struct {
+	Offset int64  `json:"offset"`
+	Hex    string `json:"hex"`
+	Text   string `json:"text"`
+}
+
+type HexDumpResult struct {
+	Offset int64      `json:"offset"`
+	Length int64      `json:"length"`
+	Lines  []*HexLine `json:"lines"`
+}
+
+// A hex dump of a range of the file.
+func HexDump(reader io.ReaderAt, offset, length int64) (*HexDumpResult, error) {
+	if offset < 0 {
+		offset = 0
+	}
+
+	if length <= 0 || length > MAX_HEX_LENGTH {
+		length = MAX_HEX_LENGTH
+	}
+
+	buf := make([]byte, length)
+	n, err := reader.ReadAt(buf, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	buf = buf[:n]
+
+	result := &HexDumpResult{
+		Offset: offset,
+		Length: int64(n),
+		Lines:  []*HexLine{},
+	}
+
+	for i := 0; i < len(buf); i += HEX_LINE_LENGTH {
+		end := i + HEX_LINE_LENGTH
+		if end > len(buf) {
+			end = len(buf)
+		}
+		line := buf[i:end]
+
+		text := make([]byte, len(line))
+		for j, c := range line {
+			text[j] = '.'
+			if isPrintable(c) {
+				text[j] = c
+			}
+		}
+
+		result.Lines = append(result.Lines, &HexLine{
+			Offset: offset + int64(i),
+			Hex:    hex.EncodeToString(line),
+			Text:   string(text),
+		})
+	}
+
+	return result, nil
+}
+
+type StringHit struct {
+	Offset   int64  `json:"offset"`
+	Encoding string `json:"encoding"`
+	Value    string `json:"value"`
+}
+
+// Extract ASCII and UTF16 strings from a range of the file similar
+// to the strings utility.
+func Strings(reader io.ReaderAt, offset, length int64) ([]*StringHit, error) {
+	if offset < 0 {
+		offset = 0
+	}
+
+	if length <= 0 || length > MAX_STRINGS_LENGTH {
+		length = MAX_STRINGS_LENGTH
+	}
+
+	buf := make([]byte, length)
+	n, err := reader.ReadAt(buf, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	buf = buf[:n]
+
+	result := []*StringHit{}
+
+	// ASCII strings
+	start := -1
+	for i := 0; i <= len(buf) && len(result) < MAX_STRINGS; i++ {
+		if i < len(buf) && isPrintable(buf[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+
+		if start >= 0 && i-start >= MIN_STRING_LENGTH {
+			result = append(result, &StringHit{
+				Offset:   offset + int64(start),
+				Encoding: "ascii",
+				Value:    string(buf[start:i]),
+			})
+		}
+		start = -1
+	}
+
+	// UTF16 strings are printable characters followed by a 0 byte.
+	for align := 0; align < 2; align++ {
+		start = -1
+		for i := align; i <= len(buf)-1 && len(result) < MAX_STRINGS; i += 2 {
+			if i+1 < len(buf) && isPrintable(buf[i]) && buf[i+1] == 0 {
+				if start < 0 {
+					start = i
+				}
+				continue
+			}
+
+			if start >= 0 && (i-start)/2 >= MIN_STRING_LENGTH {
+				result = append(result, &StringHit{
+					Offset:   offset + int64(start),
+					Encoding: "utf16",
+					Value:    decodeUTF16(buf[start:i]),
+				})
+			}
+			start = -1
+		}
+	}
+
+	return result, nil
+}
+
+func isPrintable(c byte) bool {
+	return c >= 0x20 && c < 0x7f || c == '\t'
+}
+
+func decodeUTF16(buf []byte) string {
+	u16 := make([]uint16, 0, len(buf)/2)
+	for i := 0; i+1 < len(buf); i += 2 {
+		u16 = append(u16, uint16(buf[i])|uint16(buf[i+1])<<8)
+	}
+	return string(utf16.Decode(u16))
+}
@@ -0,0 +1,111 @@
+package preview
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestHexDump(t *testing.T) {
+	data := []byte("Hello world\x00\x01\x02 this is a test")
+	reader := bytes.NewReader(data)
+
+	result, err := HexDump(reader, 6, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), result.Length)
+	assert.Equal(t, 2, len(result.Lines))
+	assert.Equal(t, int64(6), result.Lines[0].Offset)
+	assert.Equal(t, "world... this is", result.Lines[0].Text)
+	assert.Equal(t, "776f726c640001022074686973206973", result.Lines[0].Hex)
+	assert.Equal(t, int64(22), result.Lines[1].Offset)
+
+	// Reading past the end returns a short dump.
+	result, err = HexDump(reader, 20, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)-20), result.Length)
+}
+
+func TestStrings(t *testing.T) {
+	data := []byte("ab\x00Hello\x00\x01W\x00i\x00d\x00e\x00\x00\x00xyz")
+	hits, err := Strings(bytes.NewReader(data), 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(hits))
+
+	assert.Equal(t, int64(3), hits[0].Offset)
+	assert.Equal(t, "ascii", hits[0].Encoding)
+	assert.Equal(t, "Hello", hits[0].Value)
+
+	assert.Equal(t, int64(10), hits[1].Offset)
+	assert.Equal(t, "utf16", hits[1].Encoding)
+	assert.Equal(t, "Wide", hits[1].Value)
+}
+
+func TestPEInfo(t *testing.T) {
+	fd, err := os.Open("../../artifacts/testdata/files/wkscli.dll")
+	assert.NoError(t, err)
+	defer fd.Close()
+
+	stat, err := fd.Stat()
+	assert.NoError(t, err)
+
+	assert.Equal(t, TYPE_PE, Detect(fd))
+
+	info, err := PEInfo(fd, stat.Size())
+	assert.NoError(t, err)
+
+	pdb, _ := info.GetString("PDB")
+	assert.Equal(t, "kbdth0.pdb", pdb)
+
+	imphash, _ := info.GetString("ImpHash")
+	assert.NotEmpty(t, imphash)
+
+	// Not a PE file
+	_, err = PEInfo(bytes.NewReader([]byte("hello")), 5)
+	assert.Error(t, err)
+}
+
+func TestRegistrySummary(t *testing.T) {
+	fd, err := os.Open("../../artifacts/testdata/files/SAM")
+	assert.NoError(t, err)
+	defer fd.Close()
+
+	assert.Equal(t, TYPE_REGISTRY, Detect(fd))
+
+	summary, err := RegistrySummary(fd)
+	assert.NoError(t, err)
+
+	keys, _ := summary.Get("Keys")
+	assert.True(t, len(keys.([]*ordereddict.Dict)) > 0)
+}
+
+func TestThumbnail(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+	for x := 0; x < 1000; x++ {
+		img.Set(x, 250, color.RGBA{R: 255, A: 255})
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, img))
+
+	reader := bytes.NewReader(buf.Bytes())
+	assert.Equal(t, TYPE_IMAGE, Detect(reader))
+
+	thumbnail, err := Thumbnail(reader, int64(buf.Len()), 100)
+	assert.NoError(t, err)
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+	assert.NoError(t, err)
+	assert.Equal(t, "png", format)
+	assert.Equal(t, 100, config.Width)
+	assert.Equal(t, 50, config.Height)
+
+	// Not an image
+	_, err = Thumbnail(bytes.NewReader([]byte("hello")), 5, 100)
+	assert.Error(t, err)
+}
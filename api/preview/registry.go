@@ -0,0 +1,61 @@
+package preview
+
+import (
+	"io"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/regparser"
+)
+
+// Only list this many keys below the root.
+const MAX_REGISTRY_KEYS = 200
+
+// Summarize a registry hive: the header and the keys directly below
+// the root.
+func RegistrySummary(reader io.ReaderAt) (result *ordereddict.Dict, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			result = nil
+			err = notSupportedError
+		}
+	}()
+
+	hive, err := regparser.NewRegistry(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	result = ordereddict.NewDict().
+		Set("FileName", hive.BaseBlock.FileName().Value).
+		Set("LastWritten", hive.BaseBlock.TimeStamp().Time).
+		Set("Version", ordereddict.NewDict().
+			Set("Major", hive.BaseBlock.Major()).
+			Set("Minor", hive.BaseBlock.Minor()))
+
+	root := hive.OpenKey("")
+	if root == nil {
+		return result, nil
+	}
+
+	keys := []*ordereddict.Dict{}
+	subkeys := root.Subkeys()
+	for _, key := range subkeys {
+		if len(keys) >= MAX_REGISTRY_KEYS {
+			break
+		}
+
+		keys = append(keys, ordereddict.NewDict().
+			Set("Name", key.Name()).
+			Set("LastWritten", key.LastWriteTime().Time).
+			Set("Subkeys", len(key.Subkeys())).
+			Set("Values", len(key.Values())))
+	}
+
+	result.Set("RootKey", root.Name()).
+		Set("TotalKeys", len(subkeys)).
+		Set("Keys", keys).
+		Set("RootValues", len(root.Values()))
+
+	return result, nil
+}
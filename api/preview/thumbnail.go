@@ -0,0 +1,132 @@
+package preview
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	// Register the supported image formats
+	_ "image/gif"
+	_ "image/jpeg"
+)
+
+const (
+	// Larger images are not decoded.
+	MAX_IMAGE_SIZE   = 20 * 1024 * 1024
+	MAX_IMAGE_PIXELS = 50 * 1000 * 1000
+
+	DEFAULT_THUMBNAIL_SIZE = 256
+	MAX_THUMBNAIL_SIZE     = 1024
+)
+
+var (
+	imageTooLargeError = errors.New("Image is too large to preview")
+)
+
+// Produce a PNG thumbnail of the image, scaled to fit within
+// max_dimension. The image is decoded and encoded again so any
+// metadata in the original file (e.g. EXIF GPS coordinates) is not
+// included.
+func Thumbnail(reader io.ReaderAt, size int64, max_dimension int) ([]byte, error) {
+	if size > MAX_IMAGE_SIZE {
+		return nil, imageTooLargeError
+	}
+
+	if max_dimension <= 0 {
+		max_dimension = DEFAULT_THUMBNAIL_SIZE
+	}
+
+	if max_dimension > MAX_THUMBNAIL_SIZE {
+		max_dimension = MAX_THUMBNAIL_SIZE
+	}
+
+	// Check the dimensions before decoding to avoid decompression
+	// bombs.
+	config, _, err := image.DecodeConfig(io.NewSectionReader(reader, 0, size))
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Width*config.Height > MAX_IMAGE_PIXELS {
+		return nil, imageTooLargeError
+	}
+
+	img, _, err := image.Decode(io.NewSectionReader(reader, 0, size))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &bytes.Buffer{}
+	err = png.Encode(out, scaleImage(img, max_dimension))
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// Scale the image down to fit in a square of max_dimension by
+// averaging the source pixels covered by each target pixel.
+func scaleImage(img image.Image, max_dimension int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	scale := 1.0
+	if width > max_dimension || height > max_dimension {
+		if width > height {
+			scale = float64(max_dimension) / float64(width)
+		} else {
+			scale = float64(max_dimension) / float64(height)
+		}
+	}
+
+	new_width := int(float64(width) * scale)
+	new_height := int(float64(height) * scale)
+	if new_width < 1 {
+		new_width = 1
+	}
+	if new_height < 1 {
+		new_height = 1
+	}
+
+	result := image.NewRGBA(image.Rect(0, 0, new_width, new_height))
+	for y := 0; y < new_height; y++ {
+		y0 := bounds.Min.Y + y*height/new_height
+		y1 := bounds.Min.Y + (y+1)*height/new_height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < new_width; x++ {
+			x0 := bounds.Min.X + x*width/new_width
+			x1 := bounds.Min.X + (x+1)*width/new_width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					count++
+				}
+			}
+
+			result.Set(x, y, color.RGBA64{
+				R: uint16(r / count),
+				G: uint16(g / count),
+				B: uint16(b / count),
+				A: uint16(a / count),
+			})
+		}
+	}
+
+	return result
+}
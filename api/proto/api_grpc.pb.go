@@ -127,6 +127,9 @@ type APIClient interface {
 	ListChildren(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*ListChildrenResponse, error)
 	// Health check protocol as in https://github.com/grpc/grpc/blob/master/doc/health-checking.md
 	Check(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
+	// Subscribe to an artifact event queue and receive its rows as
+	// they arrive.
+	SubscribeEvents(ctx context.Context, in *EventSubscriptionRequest, opts ...grpc.CallOption) (API_SubscribeEventsClient, error)
 }
 
 type aPIClient struct {
@@ -853,6 +856,38 @@ func (c *aPIClient) Check(ctx context.Context, in *HealthCheckRequest, opts ...g
 	return out, nil
 }
 
+func (c *aPIClient) SubscribeEvents(ctx context.Context, in *EventSubscriptionRequest, opts ...grpc.CallOption) (API_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &API_ServiceDesc.Streams[3], "/proto.API/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPISubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_SubscribeEventsClient interface {
+	Recv() (*EventSubscriptionResponse, error)
+	grpc.ClientStream
+}
+
+type aPISubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPISubscribeEventsClient) Recv() (*EventSubscriptionResponse, error) {
+	m := new(EventSubscriptionResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // APIServer is the server API for API service.
 // All implementations must embed UnimplementedAPIServer
 // for forward compatibility
@@ -962,6 +997,9 @@ type APIServer interface {
 	ListChildren(context.Context, *DataRequest) (*ListChildrenResponse, error)
 	// Health check protocol as in https://github.com/grpc/grpc/blob/master/doc/health-checking.md
 	Check(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
+	// Subscribe to an artifact event queue and receive its rows as
+	// they arrive.
+	SubscribeEvents(*EventSubscriptionRequest, API_SubscribeEventsServer) error
 	mustEmbedUnimplementedAPIServer()
 }
 
@@ -1185,6 +1223,9 @@ func (UnimplementedAPIServer) ListChildren(context.Context, *DataRequest) (*List
 func (UnimplementedAPIServer) Check(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Check not implemented")
 }
+func (UnimplementedAPIServer) SubscribeEvents(*EventSubscriptionRequest, API_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
 func (UnimplementedAPIServer) mustEmbedUnimplementedAPIServer() {}
 
 // UnsafeAPIServer may be embedded to opt out of forward compatibility for this service.
@@ -2508,6 +2549,27 @@ func _API_Check_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventSubscriptionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(APIServer).SubscribeEvents(m, &aPISubscribeEventsServer{stream})
+}
+
+type API_SubscribeEventsServer interface {
+	Send(*EventSubscriptionResponse) error
+	grpc.ServerStream
+}
+
+type aPISubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *aPISubscribeEventsServer) Send(m *EventSubscriptionResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // API_ServiceDesc is the grpc.ServiceDesc for API service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -2809,6 +2871,11 @@ var API_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _API_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api.proto",
 }
@@ -37,7 +37,9 @@ type GetTableRequest struct {
 	ClientId string `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	FlowId   string `protobuf:"bytes,5,opt,name=flow_id,json=flowId,proto3" json:"flow_id,omitempty"`
 	Artifact string `protobuf:"bytes,6,opt,name=artifact,proto3" json:"artifact,omitempty"`
-	// Can be log, uploads for collection additional tables.
+	// Can be log, uploads for collection additional tables. For VFS
+	// listings "diff" compares the listing with the refresh in
+	// flow_id.
 	Type string `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
 	// For collected hunts. With hunts, type can be clients, hunt_status.
 	HuntId string `protobuf:"bytes,8,opt,name=hunt_id,json=huntId,proto3" json:"hunt_id,omitempty"`
@@ -50,7 +52,9 @@ type GetTableRequest struct {
 	// Skip these timeline components.
 	SkipComponents []string `protobuf:"bytes,17,rep,name=skip_components,json=skipComponents,proto3" json:"skip_components,omitempty"`
 	// For download handler when creating an export file - control
-	// output format. Can be "csv", "jsonl"
+	// output format. Can be "csv", "jsonl", "parquet" or for SIEM
+	// ingestion "cef", "leef" and "siem_jsonl" (flattened JSON lines).
+	// "stix" exports a STIX 2.1 bundle of the observables in the rows.
 	DownloadFormat string `protobuf:"bytes,12,opt,name=download_format,json=downloadFormat,proto3" json:"download_format,omitempty"`
 	// Optionally for downloads, the caller may specify the filename.
 	DownloadFilename string `protobuf:"bytes,18,opt,name=download_filename,json=downloadFilename,proto3" json:"download_filename,omitempty"`
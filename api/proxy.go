@@ -153,6 +153,10 @@ func PrepareGUIMux(
 		ipFilter(config_obj, csrfProtect(config_obj,
 			auther.AuthenticateUserHandler(vfsFileDownloadHandler()))))
 
+	mux.Handle(utils.Join(base, "/api/v1/PreviewVFSFile"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(vfsFilePreviewHandler()))))
+
 	mux.Handle(utils.Join(base, "/api/v1/UploadTool"),
 		ipFilter(config_obj, csrfProtect(config_obj,
 			auther.AuthenticateUserHandler(toolUploadHandler()))))
@@ -161,6 +165,98 @@ func PrepareGUIMux(
 		ipFilter(config_obj, csrfProtect(config_obj,
 			auther.AuthenticateUserHandler(formUploadHandler()))))
 
+	mux.Handle(utils.Join(base, "/api/v1/ShellInput"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(shellInputHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/Dashboards"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(dashboardsHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/DashboardWidget"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(dashboardWidgetHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/ClientAvailability"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(clientAvailabilityHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/Canaries"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(canariesHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/CanaryHits"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(canaryHitsHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/AssetInventory"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(assetInventoryHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/AssetInventorySearch"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(assetInventorySearchHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/Vulnerabilities"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(vulnerabilitiesHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/Baselines"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(baselinesHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/BaselineDrift"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(baselineDriftHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/ClientClock"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(clientClockHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/GetHuntProgress"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(huntProgressHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/HuntStack"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(huntStackHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/SubjectData"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(subjectDataHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/LegalHolds"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(legalHoldsHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/HuntSchedulingRate"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(huntSchedulingRateHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/HuntVQLCondition"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(huntVQLConditionHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/HuntQuota"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(huntQuotaHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/ExportJobs"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(exportJobsHandler()))))
+
+	mux.Handle(utils.Join(base, "/api/v1/VQLLanguageServer"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(vqlLanguageHandler()))))
+
+	// Export downloads are authorized by the signed link.
+	mux.Handle(utils.Join(base, "/api/v1/DownloadExport"),
+		ipFilter(config_obj, downloadExportHandler()))
+
+	mux.Handle(utils.Join(base, "/api/v1/openapi.json"),
+		ipFilter(config_obj, csrfProtect(config_obj,
+			auther.AuthenticateUserHandler(openAPIHandler(config_obj)))))
+
 	// Serve prepared zip files.
 	mux.Handle(utils.Join(base, "/downloads/"),
 		ipFilter(config_obj, csrfProtect(config_obj,
@@ -27,6 +27,7 @@ import (
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/artifacts/assets"
 	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/services"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/vfilter"
@@ -128,25 +129,37 @@ func (self *ApiServer) GetKeywordCompletions(
 	}
 	result.Items = append(result.Items, descriptions...)
 
-	manager, err := services.GetRepositoryManager(org_config_obj)
+	artifacts, err := GetArtifactCompletions(ctx, org_config_obj)
 	if err != nil {
 		return nil, Status(self.verbose, err)
 	}
-	repository, err := manager.GetGlobalRepository(org_config_obj)
+	result.Items = append(result.Items, artifacts...)
+
+	return result, nil
+}
+
+func GetArtifactCompletions(ctx context.Context,
+	config_obj *config_proto.Config) ([]*api_proto.Completion, error) {
+	manager, err := services.GetRepositoryManager(config_obj)
 	if err != nil {
-		return nil, Status(self.verbose, err)
+		return nil, err
 	}
-	names, err := repository.List(ctx, org_config_obj)
+	repository, err := manager.GetGlobalRepository(config_obj)
 	if err != nil {
-		return nil, Status(self.verbose, err)
+		return nil, err
+	}
+	names, err := repository.List(ctx, config_obj)
+	if err != nil {
+		return nil, err
 	}
 
+	result := []*api_proto.Completion{}
 	for _, name := range names {
-		artifact, pres := repository.Get(ctx, org_config_obj, name)
+		artifact, pres := repository.Get(ctx, config_obj, name)
 		if !pres {
 			continue
 		}
-		result.Items = append(result.Items, &api_proto.Completion{
+		result = append(result, &api_proto.Completion{
 			Name: "Artifact." + name,
 			Type: "Artifact",
 			Args: getArtifactParamDescriptors(artifact),
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/server"
+)
+
+// When the gRPC API shares the frontend's port we need to tell API
+// connections apart during the TLS handshake so we can require
+// client certificates only from them (browsers would otherwise
+// prompt users for a certificate).
+//
+// gRPC clients only offer the h2 protocol in their ALPN while
+// browsers and Velociraptor clients also offer http/1.1. API clients
+// may also be routed by connecting to API.hostname.
+func isAPIClientHello(
+	config_obj *config_proto.Config, hello *tls.ClientHelloInfo) bool {
+	if config_obj.API.Hostname != "" &&
+		config_obj.API.Hostname != config_obj.Frontend.Hostname &&
+		hello.ServerName == config_obj.API.Hostname {
+		return true
+	}
+
+	return len(hello.SupportedProtos) == 1 &&
+		hello.SupportedProtos[0] == "h2"
+}
+
+func isGRPCRequest(r *http.Request) bool {
+	return r.ProtoMajor == 2 &&
+		strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
+
+// Route API connections on the frontend port to the gRPC server.
+func addAPIRouting(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	tls_config *tls.Config,
+	api_server *grpc.Server,
+	router http.Handler) (http.Handler, error) {
+
+	// Only accept certs signed by the Velociraptor internal CA
+	api_tls_config := tls_config.Clone()
+	api_tls_config.ClientAuth = tls.RequireAndVerifyClientCert
+	api_tls_config.ClientCAs = x509.NewCertPool()
+	if !api_tls_config.ClientCAs.AppendCertsFromPEM(
+		[]byte(config_obj.Client.CaCertificate)) {
+		return nil, errors.New("Unable to parse Client.ca_certificate")
+	}
+	api_tls_config.NextProtos = []string{"h2"}
+
+	// The frontend's access control applies to the connection, API
+	// calls must also pass the API's.
+	access, err := server.NewAccessControl(ctx, config_obj, "api",
+		config_obj.API.AccessControl)
+	if err != nil {
+		return nil, err
+	}
+
+	tls_config.NextProtos = []string{"h2", "http/1.1"}
+	tls_config.GetConfigForClient = func(
+		hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		if isAPIClientHello(config_obj, hello) {
+			return api_tls_config, nil
+		}
+		return nil, nil
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isGRPCRequest(r) {
+			router.ServeHTTP(w, r)
+			return
+		}
+
+		// API calls must have been authenticated during the
+		// handshake.
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			http.Error(w, "Client certificate required", http.StatusForbidden)
+			return
+		}
+
+		if !access.Allow(r.RemoteAddr) {
+			http.Error(w, "rejected", http.StatusForbidden)
+			return
+		}
+
+		api_server.ServeHTTP(w, r)
+	}), nil
+}
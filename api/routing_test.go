@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/crypto"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestAPIRouting(t *testing.T) {
+	ca, err := crypto.GenerateCACert(2048)
+	assert.NoError(t, err)
+
+	config_obj := &config_proto.Config{
+		Client:   &config_proto.ClientConfig{CaCertificate: ca.Cert},
+		API:      &config_proto.APIConfig{Hostname: "api.example.com"},
+		Frontend: &config_proto.FrontendConfig{Hostname: "www.example.com"},
+	}
+
+	tls_config := &tls.Config{}
+	router, err := addAPIRouting(context.Background(), config_obj, tls_config, grpc.NewServer(),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}))
+	assert.NoError(t, err)
+
+	// Browsers and clients offer http/1.1 and are not asked for
+	// client certificates.
+	api_config, err := tls_config.GetConfigForClient(&tls.ClientHelloInfo{
+		ServerName:      "www.example.com",
+		SupportedProtos: []string{"h2", "http/1.1"},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, api_config)
+
+	// gRPC clients only offer h2.
+	api_config, err = tls_config.GetConfigForClient(&tls.ClientHelloInfo{
+		ServerName:      "VelociraptorServer",
+		SupportedProtos: []string{"h2"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, api_config.ClientAuth)
+
+	api_config, err = tls_config.GetConfigForClient(&tls.ClientHelloInfo{
+		ServerName:      "api.example.com",
+		SupportedProtos: []string{"h2", "http/1.1"},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, api_config)
+
+	// Regular requests go to the frontend.
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, http.StatusTeapot, w.Code)
+
+	// gRPC requests without a verified client certificate are
+	// rejected.
+	req := httptest.NewRequest("POST", "/proto.API/GetVersion", nil)
+	req.ProtoMajor = 2
+	req.Header.Set("Content-Type", "application/grpc")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
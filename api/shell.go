@@ -0,0 +1,128 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	INTERACTIVE_SHELL_ARTIFACT = "Generic.Client.InteractiveShell"
+)
+
+type shellInputRequest struct {
+	ClientId string `json:"client_id"`
+	FlowId   string `json:"flow_id"`
+	Data     string `json:"data"`
+	Close    bool   `json:"close"`
+}
+
+// Forward input to an interactive shell running on the client. The
+// client echoes the input into the collection so the entire session
+// is recorded there.
+func shellInputHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		// Sending input is equivalent to running arbitrary commands
+		// on the client.
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		for _, permission := range []acls.ACL_PERMISSION{
+			acls.COLLECT_CLIENT, acls.EXECVE} {
+			perm, err := services.CheckAccess(
+				org_config_obj, userinfo.Name, permission)
+			if !perm || err != nil {
+				returnError(w, http.StatusUnauthorized,
+					"User is not allowed to use interactive shells.")
+				return
+			}
+		}
+
+		serialized, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if err != nil {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		request := &shellInputRequest{}
+		err = json.Unmarshal(serialized, request)
+		if err != nil || request.ClientId == "" || request.FlowId == "" {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		ctx := r.Context()
+		launcher, err := services.GetLauncher(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Only deliver input to running shell collections.
+		flow, err := launcher.Storage().LoadCollectionContext(
+			ctx, org_config_obj, request.ClientId, request.FlowId)
+		if err != nil {
+			returnError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		if flow.State != flows_proto.ArtifactCollectorContext_RUNNING ||
+			flow.Request == nil ||
+			!utils.InString(flow.Request.Artifacts, INTERACTIVE_SHELL_ARTIFACT) {
+			returnError(w, http.StatusBadRequest,
+				"Flow is not a running interactive shell.")
+			return
+		}
+
+		err = services.LogAudit(ctx, org_config_obj, userinfo.Name, "ShellInput",
+			ordereddict.NewDict().
+				Set("client_id", request.ClientId).
+				Set("flow_id", request.FlowId).
+				Set("data", request.Data).
+				Set("close", request.Close))
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		client_manager, err := services.GetClientInfoManager(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		err = client_manager.QueueMessageForClient(ctx, request.ClientId,
+			&crypto_proto.VeloMessage{
+				SessionId: request.FlowId,
+				ShellInput: &crypto_proto.ShellInput{
+					Data:  []byte(request.Data),
+					Close: request.Close,
+				},
+			}, services.NOTIFY_CLIENT, utils.BackgroundWriter)
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		_, _ = w.Write([]byte("{}"))
+	})
+}
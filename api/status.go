@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	errors "github.com/go-errors/errors"
 	"google.golang.org/grpc/codes"
@@ -48,6 +49,13 @@ func InvalidStatus(msg string) error {
 	return status.Error(codes.InvalidArgument, msg)
 }
 
+// The request was held until another user approves it.
+func ApprovalRequired(id string, reasons []string) error {
+	return status.Error(codes.FailedPrecondition, fmt.Sprintf(
+		"Approval required: %v. Request %v is awaiting approval by another user.",
+		strings.Join(reasons, ", "), id))
+}
+
 func PermissionDenied(err error, message string) error {
 	if err != nil {
 		return status.Error(codes.PermissionDenied,
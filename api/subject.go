@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/privacy"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+type subjectRequest struct {
+	Identifier string `json:"identifier"`
+	Purge      bool   `json:"purge"`
+}
+
+// Find, export or purge all data referencing a data subject. A GET
+// request lists the matching items for the "identifier" query
+// parameter, or downloads them as a zip file if "export" is set. A
+// POST request with purge set irreversibly removes the data.
+func subjectDataHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		principal := userinfo.Name
+
+		// Subject data spans all clients, hunts and users.
+		perm, err := services.CheckAccess(
+			org_config_obj, principal, acls.SERVER_ADMIN)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to manage subject data.")
+			return
+		}
+
+		if r.Method == "GET" {
+			query := r.URL.Query()
+			searcher, err := privacy.NewSearcher(
+				org_config_obj, query.Get("identifier"))
+			if err != nil {
+				returnError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			if query.Get("export") != "" {
+				// From here on we already sent the headers and we
+				// can not really report an error to the client.
+				w.Header().Set("Content-Disposition", "attachment; "+
+					sanitizeFilenameForAttachment("subject_data.zip"))
+				w.Header().Set("Content-Type", "binary/octet-stream")
+				w.WriteHeader(200)
+
+				_, _ = searcher.Export(r.Context(), w)
+				return
+			}
+
+			matches := []*privacy.Match{}
+			err = searcher.Search(r.Context(),
+				func(match *privacy.Match) error {
+					matches = append(matches, match)
+					return nil
+				})
+			if err != nil {
+				returnError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			writeJSONResponse(w, ordereddict.NewDict().
+				Set("matches", matches))
+			return
+		}
+
+		request := &subjectRequest{}
+		err = readJSONRequest(w, r, request)
+		if err != nil || !request.Purge {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		searcher, err := privacy.NewSearcher(
+			org_config_obj, request.Identifier)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		matches := []*privacy.Match{}
+		err = searcher.Purge(r.Context(), principal,
+			func(match *privacy.Match) error {
+				matches = append(matches, match)
+				return nil
+			})
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, ordereddict.NewDict().
+			Set("matches", matches))
+	})
+}
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/sirupsen/logrus"
+	context "golang.org/x/net/context"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	artifact_paths "www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	// Rows which are already queued are sent together in batches of
+	// up to this many rows.
+	SUBSCRIPTION_BATCH_SIZE = 100
+)
+
+// Decides which rows are sent to the subscriber.
+type eventFilter struct {
+	client_id string
+	lambda    *vfilter.Lambda
+	scope     vfilter.Scope
+}
+
+func (self *eventFilter) Match(
+	ctx context.Context, row *ordereddict.Dict) bool {
+	if self.client_id != "" {
+		client_id, _ := row.GetString("ClientId")
+		if client_id != self.client_id {
+			return false
+		}
+	}
+
+	if self.lambda == nil {
+		return true
+	}
+
+	// Make the row's columns available directly as well as via the
+	// Row variable.
+	subscope := self.scope.Copy().AppendVars(row)
+	defer subscope.Close()
+
+	return self.scope.Bool(self.lambda.Reduce(ctx, subscope, []vfilter.Any{row}))
+}
+
+func (self *eventFilter) Close() {
+	if self.scope != nil {
+		self.scope.Close()
+	}
+}
+
+func newEventFilter(
+	config_obj *config_proto.Config,
+	principal string,
+	in *api_proto.EventSubscriptionRequest) (*eventFilter, error) {
+	result := &eventFilter{client_id: in.ClientId}
+	if in.Filter == "" {
+		return result, nil
+	}
+
+	lambda, err := vfilter.ParseLambda("Row=>" + in.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid filter %v: %w", in.Filter, err)
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// The filter runs with the subscriber's permissions.
+	result.lambda = lambda
+	result.scope = manager.BuildScope(services.ScopeBuilder{
+		Config:     config_obj,
+		ACLManager: acl_managers.NewServerACLManager(config_obj, principal),
+		Logger: logging.NewPlainLogger(
+			config_obj, &logging.FrontendComponent),
+		Env: ordereddict.NewDict(),
+	})
+
+	return result, nil
+}
+
+func streamSubscription(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	in *api_proto.EventSubscriptionRequest,
+	stream api_proto.API_SubscribeEventsServer,
+	principal string) error {
+
+	logger := logging.GetLogger(config_obj, &logging.APICmponent)
+	logger.WithFields(logrus.Fields{
+		"arg":  in,
+		"org":  in.OrgId,
+		"user": principal,
+	}).Info("SubscribeEvents API call")
+
+	mode, err := artifact_paths.GetArtifactMode(ctx, config_obj, in.Artifact)
+	if err != nil {
+		return InvalidStatus(err.Error())
+	}
+
+	if mode != paths.MODE_CLIENT_EVENT && mode != paths.MODE_SERVER_EVENT {
+		return InvalidStatus(fmt.Sprintf(
+			"Artifact %v is not an event artifact", in.Artifact))
+	}
+
+	filter, err := newEventFilter(config_obj, principal, in)
+	if err != nil {
+		return InvalidStatus(err.Error())
+	}
+	defer filter.Close()
+
+	journal, err := services.GetJournal(config_obj)
+	if err != nil {
+		return err
+	}
+
+	output_chan, cancel := journal.Watch(
+		ctx, in.Artifact, "SubscribeEvents "+principal)
+	defer cancel()
+
+	buf := &bytes.Buffer{}
+	var total_rows int64
+
+	add_row := func(row *ordereddict.Dict) {
+		if !filter.Match(ctx, row) {
+			return
+		}
+		serialized, err := json.Marshal(row)
+		if err != nil {
+			return
+		}
+		buf.Write(serialized)
+		buf.WriteByte('\n')
+		total_rows++
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case row, ok := <-output_chan:
+			if !ok {
+				return nil
+			}
+			add_row(row)
+
+			// Drain any rows which are already waiting so they go
+			// out in the same message.
+		drain:
+			for total_rows < SUBSCRIPTION_BATCH_SIZE {
+				select {
+				case row, ok := <-output_chan:
+					if !ok {
+						break drain
+					}
+					add_row(row)
+				default:
+					break drain
+				}
+			}
+
+			if total_rows == 0 {
+				continue
+			}
+
+			err := stream.Send(&api_proto.EventSubscriptionResponse{
+				Artifact:  in.Artifact,
+				Jsonl:     buf.Bytes(),
+				TotalRows: total_rows,
+			})
+			if err != nil {
+				return err
+			}
+
+			buf = &bytes.Buffer{}
+			total_rows = 0
+		}
+	}
+}
+
+// Subscribe to an event queue. Rows are sent to the caller as they
+// are written to the queue until the caller disconnects.
+func (self *ApiServer) SubscribeEvents(
+	in *api_proto.EventSubscriptionRequest,
+	stream api_proto.API_SubscribeEventsServer) error {
+
+	defer Instrument("SubscribeEvents")()
+
+	ctx := stream.Context()
+	users := services.GetUserManager()
+	user_record, org_config_obj, err := users.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	principal := user_record.Name
+
+	// If the caller wants to switch orgs, change the config to point
+	// to that org. We check permission immediately below.
+	if in.OrgId != "" {
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			return Status(self.verbose, err)
+		}
+
+		org_config_obj, err = org_manager.GetOrgConfig(in.OrgId)
+		if err != nil {
+			return Status(self.verbose, err)
+		}
+	}
+
+	permissions := acls.READ_RESULTS
+	ok, err := services.CheckAccess(org_config_obj, principal, permissions)
+	if err != nil || !ok {
+		return PermissionDenied(err, fmt.Sprintf(
+			"Permission denied: User %v requires permission %v to subscribe to events",
+			principal, permissions))
+	}
+
+	// Wait here for orderly shutdown of event streams.
+	self.wg.Add(1)
+	defer self.wg.Done()
+
+	return streamSubscription(ctx, org_config_obj, in, stream, principal)
+}
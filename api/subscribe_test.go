@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type mockSubscribeStream struct {
+	grpc.ServerStream
+
+	mu        sync.Mutex
+	ctx       context.Context
+	responses []*api_proto.EventSubscriptionResponse
+}
+
+func (self *mockSubscribeStream) Context() context.Context {
+	return self.ctx
+}
+
+func (self *mockSubscribeStream) Send(m *api_proto.EventSubscriptionResponse) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.responses = append(self.responses, m)
+	return nil
+}
+
+func (self *mockSubscribeStream) Rows() []*ordereddict.Dict {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := []*ordereddict.Dict{}
+	for _, response := range self.responses {
+		rows, _ := utils.ParseJsonToDicts(response.Jsonl)
+		result = append(result, rows...)
+	}
+	return result
+}
+
+type SubscribeTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *SubscribeTestSuite) SetupTest() {
+	self.ConfigObj = self.TestSuite.LoadConfig()
+	self.ConfigObj.Services.JournalService = true
+
+	self.TestSuite.SetupTest()
+
+	self.LoadArtifacts(`name: Server.Test.Events
+type: SERVER_EVENT
+`, `name: Client.Test.Events
+type: CLIENT_EVENT
+`, `name: Server.Test.Collection
+type: SERVER
+`)
+}
+
+func (self *SubscribeTestSuite) subscribe(
+	in *api_proto.EventSubscriptionRequest) (*mockSubscribeStream, func()) {
+	ctx, cancel := context.WithCancel(self.Ctx)
+	stream := &mockSubscribeStream{ctx: ctx}
+
+	journal, err := services.GetJournal(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := streamSubscription(ctx, self.ConfigObj, in, stream, "admin")
+		assert.NoError(self.T(), err)
+	}()
+
+	// Wait for the watcher to be registered.
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		return utils.InString(journal.GetWatchers(), in.Artifact)
+	})
+
+	return stream, func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+func (self *SubscribeTestSuite) TestFilter() {
+	stream, closer := self.subscribe(&api_proto.EventSubscriptionRequest{
+		Artifact: "Server.Test.Events",
+		Filter:   "Count > 1 AND Row.Name =~ 'b'",
+	})
+	defer closer()
+
+	journal, err := services.GetJournal(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	err = journal.PushRowsToArtifact(self.Ctx, self.ConfigObj,
+		[]*ordereddict.Dict{
+			ordereddict.NewDict().Set("Name", "a").Set("Count", 1),
+			ordereddict.NewDict().Set("Name", "b").Set("Count", 2),
+			ordereddict.NewDict().Set("Name", "c").Set("Count", 3),
+			ordereddict.NewDict().Set("Name", "bb").Set("Count", 4),
+		}, "Server.Test.Events", "server", "")
+	assert.NoError(self.T(), err)
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		return len(stream.Rows()) == 2
+	})
+
+	rows := stream.Rows()
+	name, _ := rows[0].GetString("Name")
+	assert.Equal(self.T(), "b", name)
+	name, _ = rows[1].GetString("Name")
+	assert.Equal(self.T(), "bb", name)
+}
+
+func (self *SubscribeTestSuite) TestClientFilter() {
+	stream, closer := self.subscribe(&api_proto.EventSubscriptionRequest{
+		Artifact: "Client.Test.Events",
+		ClientId: "C.1234",
+	})
+	defer closer()
+
+	journal, err := services.GetJournal(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	for _, client_id := range []string{"C.1", "C.1234"} {
+		err = journal.PushRowsToArtifact(self.Ctx, self.ConfigObj,
+			[]*ordereddict.Dict{
+				ordereddict.NewDict().Set("ClientId", client_id),
+			}, "Client.Test.Events", client_id, "")
+		assert.NoError(self.T(), err)
+	}
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		return len(stream.Rows()) == 1
+	})
+
+	client_id, _ := stream.Rows()[0].GetString("ClientId")
+	assert.Equal(self.T(), "C.1234", client_id)
+}
+
+func (self *SubscribeTestSuite) TestInvalidRequests() {
+	for _, in := range []*api_proto.EventSubscriptionRequest{
+		// Not an event artifact
+		{Artifact: "Server.Test.Collection"},
+		{Artifact: "Server.Test.Unknown"},
+		{Artifact: "Server.Test.Events", Filter: "Count >"},
+	} {
+		stream := &mockSubscribeStream{ctx: self.Ctx}
+		err := streamSubscription(self.Ctx, self.ConfigObj, in, stream, "admin")
+		assert.Error(self.T(), err)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	suite.Run(t, &SubscribeTestSuite{})
+}
@@ -0,0 +1,187 @@
+package tables
+
+import (
+	"regexp"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/masking"
+)
+
+const (
+	DEFAULT_REDACTION = "[REDACTED]"
+)
+
+var (
+	// Applied when the config file does not specify any rules.
+	DefaultRedactionRules = []*config_proto.RedactionRule{
+		// Usernames
+		{Column: `(?i)(user|owner|account|email|sid$)`},
+
+		// File contents
+		{Column: `(?i)^(data|contents?|hex|hexdump|text|line|strings?)$`},
+
+		// IPv4 and IPv6 addresses in any column.
+		{Pattern: `\b(?:\d{1,3}\.){3}\d{1,3}\b`},
+		{Pattern: `(?i)\b(?:[0-9a-f]{1,4}:){7}[0-9a-f]{1,4}\b`},
+		{Pattern: `(?i)\b(?:[0-9a-f]{1,4}:){1,6}:[0-9a-f]{1,4}(?::[0-9a-f]{1,4})*\b`},
+	}
+)
+
+type redactionRule struct {
+	column      *regexp.Regexp
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Redacts sensitive data from results shown to auditors.
+type Redactor struct {
+	rules []*redactionRule
+}
+
+func NewRedactor(config_obj *config_proto.Config) (*Redactor, error) {
+	return NewRedactorFromRules(auditorRules(config_obj))
+}
+
+func auditorRules(
+	config_obj *config_proto.Config) []*config_proto.RedactionRule {
+	if config_obj.GUI != nil && len(config_obj.GUI.RedactionRules) > 0 {
+		return config_obj.GUI.RedactionRules
+	}
+	return DefaultRedactionRules
+}
+
+// Get the redactor for the results of an artifact shown to the
+// principal. Auditors get the auditor rules and users without the
+// VIEW_PII permission get the masking policies for the
+// artifact. Returns nil if the principal may see the full results.
+func GetRedactor(config_obj *config_proto.Config,
+	principal, artifact string) (*Redactor, error) {
+	rules := []*config_proto.RedactionRule{}
+	if IsRedactedUser(config_obj, principal) {
+		rules = append(rules, auditorRules(config_obj)...)
+	}
+
+	view_pii, err := services.CheckAccess(config_obj, principal, acls.VIEW_PII)
+	if err != nil || !view_pii {
+		masking_rules, err := masking.GetRules(config_obj, artifact)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, masking_rules...)
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return NewRedactorFromRules(rules)
+}
+
+func NewRedactorFromRules(
+	rules []*config_proto.RedactionRule) (*Redactor, error) {
+	result := &Redactor{}
+	for _, rule := range rules {
+		compiled := &redactionRule{
+			replacement: rule.Replacement,
+		}
+		if compiled.replacement == "" {
+			compiled.replacement = DEFAULT_REDACTION
+		}
+
+		var err error
+		if rule.Column != "" {
+			compiled.column, err = regexp.Compile(rule.Column)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if rule.Pattern != "" {
+			compiled.pattern, err = regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// A rule without column or pattern would redact everything.
+		if compiled.column == nil && compiled.pattern == nil {
+			continue
+		}
+
+		result.rules = append(result.rules, compiled)
+	}
+
+	return result, nil
+}
+
+// Redact a single value as serialized for the GUI.
+func (self *Redactor) RedactString(column, value string) string {
+	for _, rule := range self.rules {
+		if rule.column != nil && !rule.column.MatchString(column) {
+			continue
+		}
+
+		if rule.pattern == nil {
+			return rule.replacement
+		}
+		value = rule.pattern.ReplaceAllLiteralString(value, rule.replacement)
+	}
+	return value
+}
+
+// Redact a row before it is exported. Values which are not strings
+// are replaced by their redacted JSON encoding if any rule matches.
+func (self *Redactor) RedactRow(row *ordereddict.Dict) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+	for _, key := range row.Keys() {
+		value, _ := row.Get(key)
+
+		str_value, ok := value.(string)
+		if !ok {
+			if value == nil {
+				result.Set(key, value)
+				continue
+			}
+
+			serialized, err := json.Marshal(value)
+			if err != nil {
+				result.Set(key, DEFAULT_REDACTION)
+				continue
+			}
+			str_value = string(serialized)
+		}
+
+		redacted := self.RedactString(key, str_value)
+		if ok || redacted != str_value {
+			result.Set(key, redacted)
+		} else {
+			result.Set(key, value)
+		}
+	}
+	return result
+}
+
+func (self *Redactor) RedactTable(table *api_proto.GetTableResponse) {
+	for _, row := range table.Rows {
+		for idx, cell := range row.Cell {
+			if idx < len(table.Columns) {
+				row.Cell[idx] = self.RedactString(table.Columns[idx], cell)
+			}
+		}
+	}
+}
+
+// Should results be redacted for this user?
+func IsRedactedUser(
+	config_obj *config_proto.Config, principal string) bool {
+	policy, err := services.GetPolicy(config_obj, principal)
+	if err != nil {
+		return false
+	}
+	return acls.IsRedacted(policy.Roles)
+}
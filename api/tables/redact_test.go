@@ -0,0 +1,79 @@
+package tables
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestRedactDefaultRules(t *testing.T) {
+	redactor, err := NewRedactor(&config_proto.Config{})
+	assert.NoError(t, err)
+
+	row := ordereddict.NewDict().
+		Set("Pid", 432).
+		Set("Username", "DOMAIN\\mike").
+		Set("Data", "secret file contents").
+		Set("CommandLine", "ping 10.1.2.3").
+		Set("Raddr", ordereddict.NewDict().
+			Set("IP", "fe80::1c2d:3e4f").
+			Set("Port", 443)).
+		Set("Time", "2023-11-15T10:00:00Z")
+
+	redacted := redactor.RedactRow(row)
+	assert.Equal(t, `{"Pid":432,"Username":"[REDACTED]","Data":"[REDACTED]","CommandLine":"ping [REDACTED]","Raddr":"{\"IP\":\"[REDACTED]\",\"Port\":443}","Time":"2023-11-15T10:00:00Z"}`,
+		toJson(t, redacted))
+
+	// The GUI table is redacted the same way.
+	table := &api_proto.GetTableResponse{
+		Columns: []string{"Pid", "Owner", "Remote"},
+		Rows: []*api_proto.Row{{
+			Cell: []string{"432", "mike", "192.168.1.1:443"},
+		}},
+	}
+	redactor.RedactTable(table)
+	assert.Equal(t, []string{"432", "[REDACTED]", "[REDACTED]:443"},
+		table.Rows[0].Cell)
+}
+
+func TestRedactConfiguredRules(t *testing.T) {
+	config_obj := &config_proto.Config{
+		GUI: &config_proto.GUIConfig{
+			RedactionRules: []*config_proto.RedactionRule{
+				{Column: "^Hostname$", Replacement: "<host>"},
+				{Column: "^Email$", Pattern: "^[^@]+", Replacement: "***"},
+			},
+		},
+	}
+
+	redactor, err := NewRedactor(config_obj)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "<host>", redactor.RedactString("Hostname", "DESKTOP-1"))
+	assert.Equal(t, "***@example.com",
+		redactor.RedactString("Email", "mike@example.com"))
+
+	// The default rules are replaced.
+	assert.Equal(t, "10.1.2.3", redactor.RedactString("Remote", "10.1.2.3"))
+
+	config_obj.GUI.RedactionRules[0].Column = "(invalid"
+	_, err = NewRedactor(config_obj)
+	assert.Error(t, err)
+}
+
+func TestRedactedRoles(t *testing.T) {
+	assert.True(t, acls.IsRedacted([]string{"auditor"}))
+	assert.True(t, acls.IsRedacted([]string{"auditor", "org_admin"}))
+	assert.True(t, !acls.IsRedacted([]string{"auditor", "investigator"}))
+	assert.True(t, !acls.IsRedacted([]string{"reader"}))
+}
+
+func toJson(t *testing.T, row *ordereddict.Dict) string {
+	serialized, err := row.MarshalJSON()
+	assert.NoError(t, err)
+	return string(serialized)
+}
@@ -158,6 +158,12 @@ func (self *ApiServer) VFSListDirectoryFiles(
 		return nil, Status(self.verbose, err)
 	}
 
+	// Compare the listing with a previous refresh of the directory.
+	if in.Type == "diff" {
+		result, err := vfs_service.DiffDirectory(ctx, org_config_obj, in)
+		return result, Status(self.verbose, err)
+	}
+
 	result, err := vfs_service.ListDirectoryFiles(ctx, org_config_obj, in)
 	if err != nil {
 		return nil, Status(self.verbose, err)
@@ -191,6 +197,26 @@ func (self *ApiServer) VFSDownloadFile(
 		return nil, Status(self.verbose, err)
 	}
 
+	env := []*actions_proto.VQLEnv{{
+		Key:   "Components",
+		Value: json.MustMarshalString(in.Components),
+	}, {
+		Key:   "Accessor",
+		Value: in.Accessor,
+	}}
+
+	if in.Recursively {
+		env = append(env, &actions_proto.VQLEnv{
+			Key: "Recursively", Value: "Y",
+		})
+	}
+
+	if len(in.Names) > 0 {
+		env = append(env, &actions_proto.VQLEnv{
+			Key: "Names", Value: json.MustMarshalString(in.Names),
+		})
+	}
+
 	request := &flows_proto.ArtifactCollectorArgs{
 		ClientId:  in.ClientId,
 		Creator:   principal,
@@ -199,17 +225,16 @@ func (self *ApiServer) VFSDownloadFile(
 		Specs: []*flows_proto.ArtifactSpec{{
 			Artifact: "System.VFS.DownloadFile",
 			Parameters: &flows_proto.ArtifactParameters{
-				Env: []*actions_proto.VQLEnv{{
-					Key:   "Components",
-					Value: json.MustMarshalString(in.Components),
-				}, {
-					Key:   "Accessor",
-					Value: in.Accessor,
-				}},
+				Env: env,
 			},
 		}},
 	}
 
+	// Recursive downloads may fetch a lot of data.
+	if in.Recursively {
+		request.MaxUploadBytes = 1048576000
+	}
+
 	manager, err := services.GetRepositoryManager(org_config_obj)
 	if err != nil {
 		return nil, Status(self.verbose, err)
@@ -233,12 +258,26 @@ func (self *ApiServer) VFSDownloadFile(
 		return nil, Status(self.verbose, err)
 	}
 
-	vfs_service.WriteDownloadInfo(ctx, org_config_obj, in.ClientId,
-		in.Accessor, in.Components, &flows_proto.VFSDownloadInfo{
-			FlowId:   flow_id,
-			Mtime:    uint64(utils.GetTime().Now().UnixNano() / 1000),
-			InFlight: true,
-		})
+	// Mark the files as being downloaded. We do not know in advance
+	// which files a recursive download will fetch.
+	var in_flight [][]string
+	if len(in.Names) > 0 {
+		for _, name := range in.Names {
+			in_flight = append(in_flight, append(
+				utils.CopySlice(in.Components), name))
+		}
+	} else if !in.Recursively {
+		in_flight = append(in_flight, in.Components)
+	}
+
+	for _, components := range in_flight {
+		vfs_service.WriteDownloadInfo(ctx, org_config_obj, in.ClientId,
+			in.Accessor, components, &flows_proto.VFSDownloadInfo{
+				FlowId:   flow_id,
+				Mtime:    uint64(utils.GetTime().Now().UnixNano() / 1000),
+				InFlight: true,
+			})
+	}
 
 	return &api_proto.StartFlowResponse{
 		FlowId: flow_id,
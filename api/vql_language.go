@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vql/langserver"
+)
+
+var (
+	vql_descriptions_once sync.Once
+	vql_descriptions      []*api_proto.Completion
+)
+
+type vqlLanguageRequest struct {
+	// One of complete, hover or diagnose.
+	Method string `json:"method"`
+	Query  string `json:"query"`
+
+	// Zero based position of the cursor.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// The documented plugins and functions followed by any registered
+// ones missing from the documentation. These only change with the
+// binary so they are built once.
+func GetVQLDescriptions() []*api_proto.Completion {
+	vql_descriptions_once.Do(func() {
+		documented, _ := LoadApiDescription()
+		seen := make(map[string]bool)
+		for _, item := range documented {
+			seen[item.Type+":"+item.Name] = true
+			vql_descriptions = append(vql_descriptions, item)
+		}
+
+		for _, item := range IntrospectDescription() {
+			if !seen[item.Type+":"+item.Name] {
+				vql_descriptions = append(vql_descriptions, item)
+			}
+		}
+	})
+
+	return vql_descriptions
+}
+
+// Editor intelligence for VQL: completions, documentation and
+// diagnostics for a query. Used by the GUI editors and by external
+// editors through the API.
+func vqlLanguageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to use the VQL editor.")
+			return
+		}
+
+		request := &vqlLanguageRequest{}
+		err = readJSONRequest(w, r, request)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, "Unsupported params")
+			return
+		}
+
+		artifacts, err := GetArtifactCompletions(r.Context(), org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		server := langserver.NewLanguageServer(
+			append(append([]*api_proto.Completion{},
+				GetVQLDescriptions()...), artifacts...))
+
+		switch request.Method {
+		case "complete":
+			writeJSONResponse(w, ordereddict.NewDict().
+				Set("completions", server.Complete(
+					request.Query, request.Line, request.Column)))
+
+		case "hover":
+			writeJSONResponse(w, ordereddict.NewDict().
+				Set("hover", server.Hover(
+					request.Query, request.Line, request.Column)))
+
+		case "diagnose":
+			writeJSONResponse(w, ordereddict.NewDict().
+				Set("diagnostics", server.Diagnose(request.Query)))
+
+		default:
+			returnError(w, http.StatusBadRequest, "Unsupported method")
+		}
+	})
+}
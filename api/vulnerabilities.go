@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/vulnerabilities"
+)
+
+// Get the vulnerability report of a client, or the fleet report if
+// no client_id is given, together with the database sync status.
+func vulnerabilitiesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		perm, err := services.CheckAccess(
+			org_config_obj, userinfo.Name, acls.READ_RESULTS)
+		if !perm || err != nil {
+			returnError(w, http.StatusUnauthorized,
+				"User is not allowed to view vulnerabilities.")
+			return
+		}
+
+		service, err := vulnerabilities.GetVulnerabilityService(org_config_obj)
+		if err != nil {
+			returnError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		// Clients without vulnerabilities may not have a report yet.
+		rows, _ := service.Report(r.Context(), r.URL.Query().Get("client_id"))
+		if rows == nil {
+			rows = []*ordereddict.Dict{}
+		}
+
+		writeJSONResponse(w, ordereddict.NewDict().
+			Set("rows", rows).
+			Set("sources", service.Status()))
+	})
+}
@@ -4,6 +4,8 @@ package main
 
 var (
 	allowed_plugins = []string{
+		"apfs_snapshot_diff",
+		"apfs_volumes",
 		"artifact_definitions",
 		"batch",
 		"chain",
@@ -24,6 +26,7 @@ var (
 		"flows",
 		"for",
 		"foreach",
+		"fuzzy_search",
 		"glob",
 		"hunt_delete",
 		"hunt_flows",
@@ -80,6 +83,7 @@ var (
 		"basename",
 		"cache",
 		"cancel_flow",
+		"carve",
 		"cidr_contains",
 		"client_create",
 		"client_metadata",
@@ -112,10 +116,12 @@ var (
 		"hunt",
 		"hunt_add",
 		"if",
+		"imphash",
 		"import_collection",
 		"int",
 		"ip",
 		"items",
+		"minimize",
 		"join",
 		"label",
 		"len",
@@ -126,11 +132,14 @@ var (
 		"max",
 		"memoize",
 		"min",
+		"minimize",
 		"now",
 		"parse_binary",
+		"parse_elf",
 		"parse_float",
 		"parse_json",
 		"parse_json_array",
+		"parse_macho",
 		"parse_ntfs",
 		"parse_pe",
 		"parse_pkcs7",
@@ -161,12 +170,16 @@ var (
 		"sleep",
 		"slice",
 		"split",
+		"ssdeep",
+		"ssdeep_compare",
 		"str",
 		"strip",
 		"substr",
 		"sum",
 		"timeline_add",
 		"timestamp",
+		"tlsh",
+		"tlsh_diff",
 		"to_dict",
 		"unhex",
 		"upcase",
@@ -188,6 +201,17 @@ var (
 		"bzip2",
 		"gzip",
 		"zip",
+		"ewf",
+		"vhd",
+		"vhdx",
+		"vmdk",
+		"lvm",
+		"mdraid",
+		"ldm",
+		"ext4",
+		"xfs",
+		"apfs",
+		"exfat",
 		"raw_reg",
 		"mft",
 	}
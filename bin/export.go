@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/Velocidex/yaml/v2"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/reporting/siem"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/startup"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	export_command = app.Command(
+		"export", "Export collection or hunt results for SIEM ingestion.")
+
+	export_command_client_id = export_command.Flag(
+		"client_id", "The client id of the collection").String()
+
+	export_command_flow_id = export_command.Flag(
+		"flow_id", "The flow id of the collection").String()
+
+	export_command_hunt_id = export_command.Flag(
+		"hunt_id", "Export the results of all clients in this hunt").String()
+
+	export_command_artifact = export_command.Flag(
+		"artifact", "The artifact (and optional /source) to export").
+		Required().String()
+
+	export_command_format = export_command.Flag("format", "Output format").
+				Default(siem.FORMAT_JSONL).
+				Enum(siem.FORMAT_JSONL, siem.FORMAT_CEF, siem.FORMAT_LEEF)
+
+	export_command_schema = export_command.Flag(
+		"schema", "A YAML file with the SIEM schema to use instead of the "+
+			"one in the config file (See Defaults.siem_export)").String()
+
+	export_command_output = export_command.Flag(
+		"output", "Write to this file instead of stdout").String()
+)
+
+func doExport() error {
+	logging.DisableLogging()
+
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredUser().
+		LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	if *export_command_hunt_id == "" && *export_command_flow_id == "" {
+		return fmt.Errorf("Either --hunt_id or --client_id and --flow_id must be specified")
+	}
+
+	schema := config_obj.GetDefaults().GetSiemExport()
+	if *export_command_schema != "" {
+		data, err := os.ReadFile(*export_command_schema)
+		if err != nil {
+			return err
+		}
+
+		schema = &config_proto.SiemExportConfig{}
+		err = yaml.UnmarshalStrict(data, schema)
+		if err != nil {
+			return fmt.Errorf("Unable to parse schema %v: %w",
+				*export_command_schema, err)
+		}
+	}
+
+	if schema == nil {
+		schema = &config_proto.SiemExportConfig{}
+	}
+
+	var out io.Writer = os.Stdout
+	if *export_command_output != "" {
+		fd, err := os.OpenFile(*export_command_output,
+			os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+		out = fd
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	config_obj.Services = services.GenericToolServices()
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	logger := &StdoutLogWriter{}
+	builder := services.ScopeBuilder{
+		Config:     sm.Config,
+		ACLManager: acl_managers.NewRoleACLManager(sm.Config, "administrator"),
+		Logger:     log.New(logger, "", 0),
+		Env: ordereddict.NewDict().
+			Set("ClientId", *export_command_client_id).
+			Set("FlowId", *export_command_flow_id).
+			Set("HuntId", *export_command_hunt_id).
+			Set("ArtifactName", *export_command_artifact),
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return err
+	}
+	scope := manager.BuildScope(builder)
+	defer scope.Close()
+
+	query := `
+       SELECT * FROM source(client_id=ClientId, flow_id=FlowId,
+                            hunt_id=HuntId, artifact=ArtifactName)
+`
+	vql, err := vfilter.Parse(query)
+	if err != nil {
+		return err
+	}
+
+	writer := siem.NewWriterWithSchema(schema, *export_command_format,
+		*export_command_artifact, out, json.DefaultEncOpts())
+	defer writer.Close()
+
+	for row := range vql.Eval(sm.Ctx, scope) {
+		err := writer.Write(vfilter.RowToDict(sm.Ctx, scope, row))
+		if err != nil {
+			return err
+		}
+	}
+
+	return logger.Error
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case export_command.FullCommand():
+			FatalIfError(export_command, doExport)
+
+		default:
+			return false
+		}
+
+		return true
+	})
+}
@@ -18,14 +18,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/sirupsen/logrus"
 	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	assets "www.velocidex.com/golang/velociraptor/gui/velociraptor"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/server"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/config_reload"
 	"www.velocidex.com/golang/velociraptor/startup"
 )
 
@@ -63,9 +69,16 @@ func doFrontend() error {
 		return fmt.Errorf("loading config file: %w", err)
 	}
 
-	ctx, cancel := install_sig_handler()
+	ctx, cancel := install_frontend_sig_handler(config_obj)
 	defer cancel()
 
+	// Allow the config to be reloaded from the same file.
+	if *config_path != "" {
+		config_reload.SetConfigPath(*config_path)
+	} else {
+		config_reload.SetConfigPath(os.Getenv("VELOCIRAPTOR_CONFIG"))
+	}
+
 	// Come up with a suitable services plan depending on the frontend
 	// role.
 	if config_obj.Services == nil {
@@ -116,6 +129,48 @@ func doFrontend() error {
 	return nil
 }
 
+// Like install_sig_handler() but SIGHUP reloads the config instead
+// of shutting down the frontend.
+func install_frontend_sig_handler(
+	config_obj *config_proto.Config) (context.Context, context.CancelFunc) {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		defer signal.Stop(reload)
+
+		for {
+			select {
+			case <-quit:
+				// Ordered shutdown now.
+				cancel()
+				return
+
+			case <-reload:
+				// On error the old config remains in effect.
+				_, err := config_reload.Reload(ctx, "SIGHUP")
+				if err != nil {
+					logger := logging.GetLogger(
+						config_obj, &logging.FrontendComponent)
+					logger.Error("%v", err)
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ctx, cancel
+}
+
 func init() {
 	command_handlers = append(command_handlers, func(command string) bool {
 		if command == frontend_cmd.FullCommand() {
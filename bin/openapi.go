@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"www.velocidex.com/golang/velociraptor/api"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+var (
+	openapi_command = app.Command(
+		"openapi", "Write the OpenAPI specification of the REST API (for generating client SDKs).")
+
+	openapi_command_server = openapi_command.Flag(
+		"server_url", "The public URL of the GUI (e.g. https://velociraptor.example.com/)").
+		String()
+
+	openapi_command_output = openapi_command.Flag(
+		"output", "Write to this file instead of stdout").String()
+)
+
+func doOpenAPI() error {
+	spec, err := api.GetOpenAPISpec(*openapi_command_server)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.MarshalIndent(spec)
+	if err != nil {
+		return err
+	}
+
+	if *openapi_command_output != "" {
+		return ioutil.WriteFile(*openapi_command_output, serialized, 0644)
+	}
+
+	fmt.Println(string(serialized))
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case openapi_command.FullCommand():
+			FatalIfError(openapi_command, doOpenAPI)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
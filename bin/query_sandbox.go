@@ -0,0 +1,52 @@
+package main
+
+import (
+	"www.velocidex.com/golang/velociraptor/executor/sandbox"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/startup"
+)
+
+var (
+	// Started by the client to run a query in the sandbox - not for
+	// direct use.
+	query_sandbox_command = app.Command(
+		sandbox.SANDBOX_COMMAND, "Run a query in the sandbox.").Hidden()
+)
+
+func doQuerySandbox() error {
+	logging.DisableLogging()
+
+	child, err := sandbox.NewChild()
+	if err != nil {
+		return err
+	}
+	defer child.Close()
+
+	config_obj := child.Config()
+	config_obj.Services = sandbox.ChildServices()
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	return child.Run(ctx, config_obj)
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case query_sandbox_command.FullCommand():
+			FatalIfError(query_sandbox_command, doQuerySandbox)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/json"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/privacy"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/startup"
+)
+
+var (
+	subject_command = app.Command(
+		"subject", "Find, export and purge data referencing a "+
+			"username, hostname or client id.")
+
+	subject_command_org = subject_command.Flag(
+		"org", "OrgID to search").String()
+
+	subject_search_command = subject_command.Command(
+		"search", "List all stored items referencing the identifier.")
+
+	subject_search_identifier = subject_search_command.Arg(
+		"identifier", "Username, hostname or client id").
+		Required().String()
+
+	subject_export_command = subject_command.Command(
+		"export", "Export all data referencing the identifier to a zip file.")
+
+	subject_export_identifier = subject_export_command.Arg(
+		"identifier", "Username, hostname or client id").
+		Required().String()
+
+	subject_export_output = subject_export_command.Flag(
+		"output", "Path of the zip file to write").
+		Required().String()
+
+	subject_purge_command = subject_command.Command(
+		"purge", "Irreversibly remove all data referencing the identifier.")
+
+	subject_purge_identifier = subject_purge_command.Arg(
+		"identifier", "Username, hostname or client id").
+		Required().String()
+
+	subject_purge_really_do_it = subject_purge_command.Flag(
+		"really_do_it", "Actually purge the data - otherwise only "+
+			"list what would be purged.").Bool()
+)
+
+func doSubject(command string) error {
+	logging.DisableLogging()
+
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredUser().
+		LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	config_obj.Services = services.GenericToolServices()
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	org_manager, err := services.GetOrgManager()
+	if err != nil {
+		return err
+	}
+
+	org_config_obj, err := org_manager.GetOrgConfig(*subject_command_org)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case subject_export_command.FullCommand():
+		return doSubjectExport(ctx, org_config_obj)
+
+	case subject_purge_command.FullCommand():
+		if *subject_purge_really_do_it {
+			searcher, err := privacy.NewSearcher(
+				org_config_obj, *subject_purge_identifier)
+			if err != nil {
+				return err
+			}
+			return searcher.Purge(ctx, constants.PinnedServerName,
+				printSubjectMatch)
+		}
+		fmt.Println("Will purge the following items. " +
+			"Specify --really_do_it to purge them.")
+		return doSubjectSearch(ctx, org_config_obj, *subject_purge_identifier)
+
+	default:
+		return doSubjectSearch(ctx, org_config_obj, *subject_search_identifier)
+	}
+}
+
+func doSubjectSearch(ctx context.Context,
+	config_obj *config_proto.Config, identifier string) error {
+	searcher, err := privacy.NewSearcher(config_obj, identifier)
+	if err != nil {
+		return err
+	}
+	return searcher.Search(ctx, printSubjectMatch)
+}
+
+func doSubjectExport(
+	ctx context.Context, config_obj *config_proto.Config) error {
+	searcher, err := privacy.NewSearcher(config_obj, *subject_export_identifier)
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.OpenFile(*subject_export_output,
+		os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	matches, err := searcher.Export(ctx, fd)
+	if err != nil {
+		return err
+	}
+
+	if len(matches) == 0 {
+		return errors.New("No data references the identifier")
+	}
+
+	fmt.Printf("Exported %v items to %v\n", len(matches),
+		*subject_export_output)
+	return nil
+}
+
+func printSubjectMatch(match *privacy.Match) error {
+	fmt.Println(json.MustMarshalString(match))
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case subject_search_command.FullCommand(),
+			subject_export_command.FullCommand(),
+			subject_purge_command.FullCommand():
+			FatalIfError(subject_command, func() error {
+				return doSubject(command)
+			})
+
+		default:
+			return false
+		}
+
+		return true
+	})
+}
@@ -14,6 +14,7 @@ import (
 	logging "www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/inventory"
 	"www.velocidex.com/golang/velociraptor/startup"
 )
 
@@ -51,6 +52,9 @@ var (
 	third_party_upload_binary_path = third_party_upload.
 					Arg("path", "Path to file or a URL").String()
 
+	third_party_mirror = third_party.Command("mirror",
+		"Download all tools declared by artifacts into the server's public directory")
+
 	url_regexp = regexp.MustCompile("^https?://")
 )
 
@@ -234,6 +238,50 @@ func doThirdPartyUpload() error {
 	return err
 }
 
+func doThirdPartyMirror() error {
+	logging.DisableLogging()
+
+	config_obj, err := makeDefaultConfigLoader().WithRequiredFrontend().
+		LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	config_obj.Services = services.GenericToolServices()
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	repository, err := getRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	results, err := inventory.MirrorTools(ctx, config_obj, repository)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := yaml.Marshal(results)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(serialized))
+
+	for _, result := range results {
+		if result.Error != "" {
+			return fmt.Errorf("Unable to mirror all tools")
+		}
+	}
+	return nil
+}
+
 func init() {
 	command_handlers = append(command_handlers, func(command string) bool {
 		switch command {
@@ -246,6 +294,9 @@ func init() {
 		case third_party_rm.FullCommand():
 			FatalIfError(third_party_rm, doThirdPartyRm)
 
+		case third_party_mirror.FullCommand():
+			FatalIfError(third_party_mirror, doThirdPartyMirror)
+
 		default:
 			return false
 		}
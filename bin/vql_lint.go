@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/api"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/libraries"
+	"www.velocidex.com/golang/velociraptor/startup"
+	"www.velocidex.com/golang/velociraptor/vql/langserver"
+)
+
+var (
+	vql_lint = vql_info.Command("lint", "Statically check artifacts and VQL queries")
+
+	vql_lint_files = vql_lint.Arg("files",
+		"Artifact YAML files or files containing VQL queries").
+		Required().Strings()
+
+	vql_lint_format = vql_lint.Flag("format", "Output format (text or json)").
+			Default("text").Enum("text", "json")
+
+	vql_lint_strict = vql_lint.Flag("strict", "Fail on warnings as well as errors").Bool()
+
+	vql_lint_vars = vql_lint.Flag("var",
+		"A variable defined outside the queries (may be repeated)").Strings()
+)
+
+type vqlLintResult struct {
+	File string `json:"file"`
+	*langserver.ArtifactDiagnostic
+}
+
+func isArtifactFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func doVQLLint() error {
+	logging.DisableLogging()
+
+	config_obj, err := makeDefaultConfigLoader().
+		WithNullLoader().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to create config: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	results := []*vqlLintResult{}
+	report := func(filename string, d *langserver.ArtifactDiagnostic) {
+		results = append(results, &vqlLintResult{
+			File: filename, ArtifactDiagnostic: d})
+	}
+
+	// Load all the artifacts first so they may call each other.
+	contents := make(map[string]string)
+	artifacts := make(map[string]*artifacts_proto.Artifact)
+	for _, filename := range *vql_lint_files {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		contents[filename] = string(data)
+
+		if !isArtifactFile(filename) {
+			continue
+		}
+
+		artifact, err := manager.NewRepository().LoadYaml(
+			string(data), services.ArtifactOptions{})
+		if err == nil {
+			// Linting the built in artifacts replaces them with
+			// the versions being checked.
+			for _, alias := range artifact.Aliases {
+				repository.Del(alias)
+			}
+			artifact, err = repository.LoadYaml(string(data),
+				services.ArtifactOptions{ArtifactIsBuiltIn: true})
+		}
+		if err != nil {
+			report(filename, &langserver.ArtifactDiagnostic{
+				Diagnostic: &langserver.Diagnostic{
+					Severity: langserver.SEVERITY_ERROR,
+					Rule:     langserver.RULE_SYNTAX,
+					Message:  err.Error(),
+				},
+			})
+			continue
+		}
+		artifacts[filename] = artifact
+	}
+
+	artifact_completions, err := api.GetArtifactCompletions(ctx, config_obj)
+	if err != nil {
+		return err
+	}
+
+	server := langserver.NewLanguageServer(
+		append(append([]*api_proto.Completion{},
+			api.GetVQLDescriptions()...), artifact_completions...))
+
+	for _, filename := range *vql_lint_files {
+		if !isArtifactFile(filename) {
+			for _, d := range server.Lint(contents[filename],
+				langserver.LintOptions{Variables: *vql_lint_vars}) {
+				report(filename, &langserver.ArtifactDiagnostic{Diagnostic: d})
+			}
+			continue
+		}
+
+		artifact, pres := artifacts[filename]
+		if !pres {
+			continue
+		}
+
+		imported := []string{}
+		for _, name := range artifact.Imports {
+			export, err := libraries.ResolveImport(
+				ctx, config_obj, repository, name)
+			if err != nil {
+				report(filename, &langserver.ArtifactDiagnostic{
+					Artifact: artifact.Name,
+					Field:    "imports",
+					Diagnostic: &langserver.Diagnostic{
+						Severity: langserver.SEVERITY_ERROR,
+						Rule:     langserver.RULE_SYNTAX,
+						Message:  err.Error(),
+					},
+				})
+				continue
+			}
+			imported = append(imported, export)
+		}
+
+		has_errors := false
+		for _, d := range server.LintArtifact(
+			artifact, strings.Join(imported, "\n")) {
+			if d.Severity == langserver.SEVERITY_ERROR {
+				has_errors = true
+			}
+			report(filename, d)
+		}
+
+		// Also apply the checks made when the artifact is
+		// uploaded to the server.
+		if !has_errors {
+			_, err := manager.NewRepository().LoadYaml(contents[filename],
+				services.ArtifactOptions{ValidateArtifact: true})
+			if err != nil {
+				report(filename, &langserver.ArtifactDiagnostic{
+					Artifact: artifact.Name,
+					Diagnostic: &langserver.Diagnostic{
+						Severity: langserver.SEVERITY_ERROR,
+						Rule:     langserver.RULE_SYNTAX,
+						Message:  err.Error(),
+					},
+				})
+			}
+		}
+	}
+
+	errors := 0
+	warnings := 0
+	for _, result := range results {
+		if result.Severity == langserver.SEVERITY_ERROR {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+
+	switch *vql_lint_format {
+	case "json":
+		serialized, err := json.MarshalIndent(results)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(serialized))
+
+	default:
+		for _, result := range results {
+			location := ""
+			if result.Artifact != "" {
+				location = result.Artifact + " "
+			}
+			if result.Field != "" {
+				location += result.Field + " "
+			}
+
+			fmt.Printf("%s: %s%d:%d: %s: %s (%s)\n", result.File, location,
+				result.Line+1, result.Column+1, result.Severity,
+				result.Message, result.Rule)
+		}
+	}
+
+	if errors > 0 || (*vql_lint_strict && warnings > 0) {
+		return fmt.Errorf("Found %d errors and %d warnings", errors, warnings)
+	}
+
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case vql_lint.FullCommand():
+			FatalIfError(vql_lint, doVQLLint)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
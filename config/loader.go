@@ -605,6 +605,12 @@ func read_config_from_file(filename string) (*config_proto.Config, error) {
 	return result, nil
 }
 
+// Read a config file without applying any mutators or validators
+// (e.g. to reload the config of a running server).
+func ReadConfigFile(filename string) (*config_proto.Config, error) {
+	return read_config_from_file(filename)
+}
+
 func read_api_config_from_file(filename string) (*config_proto.Config, error) {
 	result := &config_proto.Config{ApiConfig: &config_proto.ApiClientConfig{}}
 
@@ -152,6 +152,13 @@ func ValidateFrontendConfig(config_obj *config_proto.Config) error {
 		config_obj.API.PinnedGwName = "GRPC_GW"
 	}
 
+	switch config_obj.Defaults.GetHuntResultStorage() {
+	case "", "jsonl", "columnar":
+	default:
+		return fmt.Errorf("Defaults.hunt_result_storage: unsupported storage %v",
+			config_obj.Defaults.GetHuntResultStorage())
+	}
+
 	return nil
 }
 
@@ -190,5 +197,12 @@ func ValidateDatastoreConfig(config_obj *config_proto.Config) error {
 		}
 	}
 
+	switch config_obj.Datastore.Compression {
+	case "", "zstd":
+	default:
+		return fmt.Errorf("Datastore.compression: unsupported compression %v",
+			config_obj.Datastore.Compression)
+	}
+
 	return nil
 }
@@ -0,0 +1,325 @@
+/*
+  An encryptor for the age file format (https://age-encryption.org/v1).
+
+  Files may be encrypted to one or more X25519 recipients (public keys
+  of the form age1...) or with a passphrase, and can be decrypted
+  with the standard age tools, e.g.
+
+     age -d -i key.txt export.tar.age > export.tar
+
+  Only encryption is implemented since the server never needs to
+  decrypt its own exports.
+*/
+
+package age
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	INTRO         = "age-encryption.org/v1"
+	CHUNK_SIZE    = 64 * 1024
+	FILE_KEY_SIZE = 16
+
+	// The scrypt work factor used for passphrases (log2 N).
+	SCRYPT_WORK_FACTOR = 18
+
+	x25519Label = "age-encryption.org/v1/X25519"
+	scryptLabel = "age-encryption.org/v1/scrypt"
+)
+
+var (
+	ErrNoRecipients = errors.New("age: no recipients or passphrase specified")
+
+	b64 = base64.RawStdEncoding
+)
+
+// A header stanza wrapping the file key for one recipient.
+type stanza struct {
+	Type string
+	Args []string
+	Body []byte
+}
+
+func (self *stanza) marshal(out *bytes.Buffer) {
+	out.WriteString("-> " + self.Type)
+	for _, arg := range self.Args {
+		out.WriteString(" " + arg)
+	}
+	out.WriteString("\n")
+
+	// The body is wrapped at 64 columns and always ends with a
+	// partial (possibly empty) line.
+	body := b64.EncodeToString(self.Body)
+	for len(body) >= 64 {
+		out.WriteString(body[:64] + "\n")
+		body = body[64:]
+	}
+	out.WriteString(body + "\n")
+}
+
+type recipient interface {
+	wrap(file_key []byte) (*stanza, error)
+}
+
+type x25519Recipient struct {
+	public_key []byte
+}
+
+func (self *x25519Recipient) wrap(file_key []byte) (*stanza, error) {
+	ephemeral := make([]byte, curve25519.ScalarSize)
+	_, err := rand.Read(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := curve25519.X25519(ephemeral, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	shared_secret, err := curve25519.X25519(ephemeral, self.public_key)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := append(append([]byte{}, share...), self.public_key...)
+	wrapping_key, err := deriveKey(shared_secret, salt, x25519Label)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := aeadEncrypt(wrapping_key, file_key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stanza{
+		Type: "X25519",
+		Args: []string{b64.EncodeToString(share)},
+		Body: body,
+	}, nil
+}
+
+type scryptRecipient struct {
+	passphrase  string
+	work_factor int
+}
+
+func (self *scryptRecipient) wrap(file_key []byte) (*stanza, error) {
+	salt := make([]byte, 16)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapping_key, err := scrypt.Key([]byte(self.passphrase),
+		append([]byte(scryptLabel), salt...),
+		1<<self.work_factor, 8, 1, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := aeadEncrypt(wrapping_key, file_key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stanza{
+		Type: "scrypt",
+		Args: []string{b64.EncodeToString(salt),
+			fmt.Sprintf("%d", self.work_factor)},
+		Body: body,
+	}, nil
+}
+
+// Parse an age public key (age1...).
+func ParseRecipient(public_key string) ([]byte, error) {
+	hrp, data, err := bech32Decode(strings.TrimSpace(public_key))
+	if err != nil {
+		return nil, fmt.Errorf("age: invalid recipient %q: %w", public_key, err)
+	}
+
+	if hrp != "age" || len(data) != curve25519.PointSize {
+		return nil, fmt.Errorf("age: invalid recipient %q", public_key)
+	}
+	return data, nil
+}
+
+// Returns a writer which encrypts to all recipients. A passphrase
+// may be used instead of recipients (but not both). The file is only
+// complete once the writer is closed.
+func NewWriter(out io.Writer,
+	recipients []string, passphrase string) (io.WriteCloser, error) {
+	return newWriter(out, recipients, passphrase, SCRYPT_WORK_FACTOR)
+}
+
+func newWriter(out io.Writer,
+	recipients []string, passphrase string,
+	work_factor int) (io.WriteCloser, error) {
+	wrappers := []recipient{}
+	for _, r := range recipients {
+		public_key, err := ParseRecipient(r)
+		if err != nil {
+			return nil, err
+		}
+		wrappers = append(wrappers, &x25519Recipient{public_key: public_key})
+	}
+
+	if passphrase != "" {
+		// The scrypt stanza must be the only one in the header.
+		if len(wrappers) > 0 {
+			return nil, errors.New(
+				"age: a passphrase can not be combined with recipients")
+		}
+		wrappers = append(wrappers, &scryptRecipient{
+			passphrase:  passphrase,
+			work_factor: work_factor,
+		})
+	}
+
+	if len(wrappers) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	file_key := make([]byte, FILE_KEY_SIZE)
+	_, err := rand.Read(file_key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &bytes.Buffer{}
+	header.WriteString(INTRO + "\n")
+	for _, w := range wrappers {
+		s, err := w.wrap(file_key)
+		if err != nil {
+			return nil, err
+		}
+		s.marshal(header)
+	}
+	header.WriteString("---")
+
+	hmac_key, err := deriveKey(file_key, nil, "header")
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, hmac_key)
+	mac.Write(header.Bytes())
+	header.WriteString(" " + b64.EncodeToString(mac.Sum(nil)) + "\n")
+
+	// The payload key is derived from a random nonce which follows
+	// the header.
+	nonce := make([]byte, 16)
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	header.Write(nonce)
+
+	payload_key, err := deriveKey(file_key, nonce, "payload")
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(payload_key)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = out.Write(header.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &streamWriter{
+		out:  out,
+		aead: aead,
+		buf:  make([]byte, 0, CHUNK_SIZE),
+	}, nil
+}
+
+// Encrypts the payload in chunks using the STREAM construction.
+type streamWriter struct {
+	out     io.Writer
+	aead    interface{ Seal(dst, nonce, plain, ad []byte) []byte }
+	buf     []byte
+	counter uint64
+	closed  bool
+}
+
+func (self *streamWriter) Write(p []byte) (int, error) {
+	if self.closed {
+		return 0, errors.New("age: write to closed writer")
+	}
+
+	total := len(p)
+	for len(p) > 0 {
+		// Only flush full chunks once more data arrives since
+		// the last chunk is marked specially.
+		if len(self.buf) == CHUNK_SIZE {
+			err := self.flushChunk(false)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		n := copy(self.buf[len(self.buf):CHUNK_SIZE], p)
+		self.buf = self.buf[:len(self.buf)+n]
+		p = p[n:]
+	}
+	return total, nil
+}
+
+func (self *streamWriter) flushChunk(last bool) error {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	for i := 10; i >= 0; i-- {
+		nonce[i] = byte(self.counter >> (8 * (10 - i)))
+	}
+	if last {
+		nonce[11] = 1
+	}
+
+	_, err := self.out.Write(self.aead.Seal(nil, nonce, self.buf, nil))
+	self.buf = self.buf[:0]
+	self.counter++
+	return err
+}
+
+func (self *streamWriter) Close() error {
+	if self.closed {
+		return nil
+	}
+	self.closed = true
+	return self.flushChunk(true)
+}
+
+func deriveKey(secret, salt []byte, info string) ([]byte, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	_, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(info)), key)
+	return key, err
+}
+
+// Wrap the file key with a zero nonce - each wrapping key is only
+// used once.
+func aeadEncrypt(key, plaintext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	return aead.Seal(nil, nonce, plaintext, nil), nil
+}
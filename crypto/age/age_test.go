@@ -0,0 +1,236 @@
+package age
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/scrypt"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+// A minimal age decryptor used to verify the encryptor.
+func decrypt(data []byte, identity []byte, passphrase string) ([]byte, error) {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	header := &bytes.Buffer{}
+
+	readLine := func() (string, error) {
+		line, err := reader.ReadString('\n')
+		header.WriteString(line)
+		return strings.TrimSuffix(line, "\n"), err
+	}
+
+	intro, err := readLine()
+	if err != nil || intro != INTRO {
+		return nil, errors.New("bad intro")
+	}
+
+	var file_key []byte
+	var mac_line string
+	for {
+		line, err := readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(line, "--- ") {
+			mac_line = line
+			break
+		}
+
+		args := strings.Split(strings.TrimPrefix(line, "-> "), " ")
+		body := ""
+		for {
+			part, err := readLine()
+			if err != nil {
+				return nil, err
+			}
+			body += part
+			if len(part) < 64 {
+				break
+			}
+		}
+
+		wrapped, err := b64.DecodeString(body)
+		if err != nil {
+			return nil, err
+		}
+
+		var wrapping_key []byte
+		switch args[0] {
+		case "X25519":
+			if identity == nil {
+				continue
+			}
+			share, _ := b64.DecodeString(args[1])
+			shared_secret, err := curve25519.X25519(identity, share)
+			if err != nil {
+				return nil, err
+			}
+			public_key, _ := curve25519.X25519(identity, curve25519.Basepoint)
+			wrapping_key, err = deriveKey(shared_secret,
+				append(append([]byte{}, share...), public_key...), x25519Label)
+			if err != nil {
+				return nil, err
+			}
+
+		case "scrypt":
+			salt, _ := b64.DecodeString(args[1])
+			work_factor, _ := strconv.Atoi(args[2])
+			wrapping_key, err = scrypt.Key([]byte(passphrase),
+				append([]byte(scryptLabel), salt...),
+				1<<work_factor, 8, 1, chacha20poly1305.KeySize)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		aead, _ := chacha20poly1305.New(wrapping_key)
+		key, err := aead.Open(nil,
+			make([]byte, chacha20poly1305.NonceSize), wrapped, nil)
+		if err == nil {
+			file_key = key
+		}
+	}
+
+	if file_key == nil {
+		return nil, errors.New("no matching stanza")
+	}
+
+	// The MAC covers the header up to and including the "---".
+	hmac_key, _ := deriveKey(file_key, nil, "header")
+	mac := hmac.New(sha256.New, hmac_key)
+	mac.Write(header.Bytes()[:header.Len()-len(mac_line)-1+3])
+	expected, _ := b64.DecodeString(strings.TrimPrefix(mac_line, "--- "))
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return nil, errors.New("bad header mac")
+	}
+
+	nonce := make([]byte, 16)
+	_, err = io.ReadFull(reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	payload_key, _ := deriveKey(file_key, nonce, "payload")
+	aead, _ := chacha20poly1305.New(payload_key)
+
+	payload, _ := io.ReadAll(reader)
+	result := []byte{}
+	chunk_nonce := make([]byte, chacha20poly1305.NonceSize)
+	for counter := 0; ; counter++ {
+		size := CHUNK_SIZE + aead.Overhead()
+		last := len(payload) <= size
+		if last {
+			size = len(payload)
+			chunk_nonce[11] = 1
+		}
+
+		chunk_nonce[10] = byte(counter)
+		plain, err := aead.Open(nil, chunk_nonce, payload[:size], nil)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, plain...)
+		payload = payload[size:]
+
+		if last {
+			return result, nil
+		}
+	}
+}
+
+func TestParseRecipient(t *testing.T) {
+	// The example key from the age README.
+	public_key, err := ParseRecipient(
+		"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p")
+	assert.NoError(t, err)
+	assert.Equal(t, 32, len(public_key))
+
+	encoded, err := bech32Encode("age", public_key)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p",
+		encoded)
+
+	// Bad checksum
+	_, err = ParseRecipient(
+		"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8q")
+	assert.Error(t, err)
+
+	// Wrong type of key
+	_, err = ParseRecipient(
+		"AGE-SECRET-KEY-1QQPQQ")
+	assert.Error(t, err)
+}
+
+func TestRecipients(t *testing.T) {
+	identity := make([]byte, curve25519.ScalarSize)
+	_, err := rand.Read(identity)
+	assert.NoError(t, err)
+
+	public_key, err := curve25519.X25519(identity, curve25519.Basepoint)
+	assert.NoError(t, err)
+
+	recipient, err := bech32Encode("age", public_key)
+	assert.NoError(t, err)
+
+	// Span several chunks with a partial last chunk.
+	plain := bytes.Repeat([]byte("Hello world "), CHUNK_SIZE/4)
+
+	out := &bytes.Buffer{}
+	writer, err := NewWriter(out, []string{recipient}, "")
+	assert.NoError(t, err)
+
+	_, err = writer.Write(plain)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	decrypted, err := decrypt(out.Bytes(), identity, "")
+	assert.NoError(t, err)
+	assert.Equal(t, plain, decrypted)
+
+	// Other identities can not decrypt.
+	other := make([]byte, curve25519.ScalarSize)
+	_, err = rand.Read(other)
+	assert.NoError(t, err)
+
+	_, err = decrypt(out.Bytes(), other, "")
+	assert.Error(t, err)
+}
+
+func TestPassphrase(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	// Use a low work factor to keep the test fast.
+	writer, err := newWriter(out, nil, "hunter2", 10)
+	assert.NoError(t, err)
+
+	// An empty payload still has a final chunk.
+	assert.NoError(t, writer.Close())
+
+	decrypted, err := decrypt(out.Bytes(), nil, "hunter2")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(decrypted))
+
+	_, err = decrypt(out.Bytes(), nil, "wrong")
+	assert.Error(t, err)
+
+	// Passphrases can not be combined with recipients.
+	_, err = NewWriter(out, []string{
+		"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"},
+		"hunter2")
+	assert.Error(t, err)
+
+	_, err = NewWriter(out, nil, "")
+	assert.Equal(t, ErrNoRecipients, err)
+}
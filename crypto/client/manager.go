@@ -435,11 +435,9 @@ func (self *CryptoManager) EncryptMessageList(
 		return nil, errors.Wrap(err, 0)
 	}
 
-	if compression == crypto_proto.PackedMessageList_ZCOMPRESSION {
-		plain_text, err = utils.Compress(plain_text)
-		if err != nil {
-			return nil, errors.Wrap(err, 0)
-		}
+	plain_text, err = vcrypto.Compress(compression, plain_text)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
 	}
 
 	cipher_text, err := self.Encrypt(
@@ -0,0 +1,195 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"www.velocidex.com/golang/velociraptor/constants"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Clients compress message lists before they are encrypted so the
+// usual HTTP content encoding does not apply. Instead the server
+// advertises the encodings it accepts in the Accept-Encoding header
+// of its responses (RFC 7694) and clients switch to their preferred
+// encoding when the server supports it. Servers always accept
+// deflate so this is the encoding used until we hear back from the
+// server.
+const (
+	ACCEPT_ENCODING_HEADER = "Accept-Encoding"
+	DEFAULT_COMPRESSION    = crypto_proto.PackedMessageList_ZCOMPRESSION
+)
+
+var (
+	compression_names = map[crypto_proto.PackedMessageList_CompressionType]string{
+		crypto_proto.PackedMessageList_ZSTD:         "zstd",
+		crypto_proto.PackedMessageList_GZIP:         "gzip",
+		crypto_proto.PackedMessageList_ZCOMPRESSION: "deflate",
+	}
+
+	// The encodings the server accepts in order of preference.
+	accepted_encodings = []crypto_proto.PackedMessageList_CompressionType{
+		crypto_proto.PackedMessageList_ZSTD,
+		crypto_proto.PackedMessageList_GZIP,
+		crypto_proto.PackedMessageList_ZCOMPRESSION,
+	}
+
+	codec_mu     sync.Mutex
+	zstd_encoder *zstd.Encoder
+	zstd_decoder *zstd.Decoder
+)
+
+func getZstdCodecs() (*zstd.Encoder, *zstd.Decoder, error) {
+	codec_mu.Lock()
+	defer codec_mu.Unlock()
+
+	if zstd_encoder == nil {
+		var err error
+		zstd_encoder, err = zstd.NewWriter(nil,
+			zstd.WithEncoderLevel(zstd.SpeedFastest))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Limit the memory a malicious client can make us allocate.
+		zstd_decoder, err = zstd.NewReader(nil,
+			zstd.WithDecoderMaxMemory(4*constants.MAX_MEMORY))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return zstd_encoder, zstd_decoder, nil
+}
+
+func CompressionName(
+	compression crypto_proto.PackedMessageList_CompressionType) string {
+	name, pres := compression_names[compression]
+	if !pres {
+		return "identity"
+	}
+	return name
+}
+
+func ParseCompression(name string) (
+	crypto_proto.PackedMessageList_CompressionType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return crypto_proto.PackedMessageList_ZSTD, nil
+	}
+
+	for k, v := range compression_names {
+		if v == name {
+			return k, nil
+		}
+	}
+	return DEFAULT_COMPRESSION, fmt.Errorf("Unsupported compression %v", name)
+}
+
+// The value of the Accept-Encoding header sent by the server.
+func AcceptedEncodings() string {
+	result := make([]string, 0, len(accepted_encodings))
+	for _, compression := range accepted_encodings {
+		result = append(result, CompressionName(compression))
+	}
+	return strings.Join(result, ", ")
+}
+
+// Pick the compression to use for a server which sent the
+// accept_encoding header. Servers which do not advertise any
+// encodings are assumed to only support deflate.
+func NegotiateCompression(accept_encoding string,
+	preferred crypto_proto.PackedMessageList_CompressionType) crypto_proto.PackedMessageList_CompressionType {
+
+	for _, item := range strings.Split(accept_encoding, ",") {
+		// Ignore any quality values.
+		name := strings.TrimSpace(strings.SplitN(item, ";", 2)[0])
+		if strings.EqualFold(name, CompressionName(preferred)) {
+			return preferred
+		}
+	}
+	return DEFAULT_COMPRESSION
+}
+
+func Compress(compression crypto_proto.PackedMessageList_CompressionType,
+	plain_text []byte) ([]byte, error) {
+
+	switch compression {
+	case crypto_proto.PackedMessageList_UNCOMPRESSED:
+		return plain_text, nil
+
+	case crypto_proto.PackedMessageList_ZCOMPRESSION:
+		return utils.Compress(plain_text)
+
+	case crypto_proto.PackedMessageList_ZSTD:
+		encoder, _, err := getZstdCodecs()
+		if err != nil {
+			return nil, err
+		}
+		return encoder.EncodeAll(plain_text, nil), nil
+
+	case crypto_proto.PackedMessageList_GZIP:
+		var b bytes.Buffer
+		w, err := gzip.NewWriterLevel(&b, gzip.BestSpeed)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = w.Write(plain_text)
+		if err != nil {
+			return nil, err
+		}
+
+		err = w.Close()
+		if err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("Unsupported compression %v", compression)
+	}
+}
+
+func Uncompress(ctx context.Context,
+	compression crypto_proto.PackedMessageList_CompressionType,
+	compressed []byte) ([]byte, error) {
+
+	switch compression {
+	case crypto_proto.PackedMessageList_UNCOMPRESSED:
+		return compressed, nil
+
+	case crypto_proto.PackedMessageList_ZCOMPRESSION:
+		return utils.Uncompress(ctx, compressed)
+
+	case crypto_proto.PackedMessageList_ZSTD:
+		_, decoder, err := getZstdCodecs()
+		if err != nil {
+			return nil, err
+		}
+		return decoder.DecodeAll(compressed, nil)
+
+	case crypto_proto.PackedMessageList_GZIP:
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		result := bytes.NewBuffer(make([]byte, 0, len(compressed)*2))
+		_, err = utils.Copy(ctx, result, reader)
+		if err != nil {
+			return nil, err
+		}
+		return result.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("Unsupported compression %v", compression)
+	}
+}
@@ -0,0 +1,64 @@
+package crypto
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+)
+
+func TestCompressionRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	plain_text := []byte(strings.Repeat("hello world ", 1000))
+
+	for _, compression := range []crypto_proto.PackedMessageList_CompressionType{
+		crypto_proto.PackedMessageList_UNCOMPRESSED,
+		crypto_proto.PackedMessageList_ZCOMPRESSION,
+		crypto_proto.PackedMessageList_ZSTD,
+		crypto_proto.PackedMessageList_GZIP,
+	} {
+		compressed, err := Compress(compression, plain_text)
+		assert.NoError(t, err)
+
+		if compression != crypto_proto.PackedMessageList_UNCOMPRESSED {
+			assert.True(t, len(compressed) < len(plain_text))
+		}
+
+		uncompressed, err := Uncompress(ctx, compression, compressed)
+		assert.NoError(t, err)
+		assert.Equal(t, plain_text, uncompressed)
+	}
+
+	// Data compressed with one algorithm can not be read with another.
+	compressed, err := Compress(crypto_proto.PackedMessageList_ZSTD, plain_text)
+	assert.NoError(t, err)
+
+	_, err = Uncompress(ctx, crypto_proto.PackedMessageList_GZIP, compressed)
+	assert.Error(t, err)
+}
+
+func TestNegotiateCompression(t *testing.T) {
+	zstd := crypto_proto.PackedMessageList_ZSTD
+	gzip := crypto_proto.PackedMessageList_GZIP
+
+	assert.Equal(t, "zstd, gzip, deflate", AcceptedEncodings())
+	assert.Equal(t, zstd, NegotiateCompression(AcceptedEncodings(), zstd))
+	assert.Equal(t, gzip, NegotiateCompression("GZIP;q=0.5, deflate", gzip))
+
+	// Older servers do not send the header and only support deflate.
+	assert.Equal(t, DEFAULT_COMPRESSION, NegotiateCompression("", zstd))
+	assert.Equal(t, DEFAULT_COMPRESSION, NegotiateCompression("gzip", zstd))
+
+	compression, err := ParseCompression("")
+	assert.NoError(t, err)
+	assert.Equal(t, zstd, compression)
+
+	compression, err = ParseCompression(" Gzip")
+	assert.NoError(t, err)
+	assert.Equal(t, gzip, compression)
+
+	_, err = ParseCompression("brotli")
+	assert.Error(t, err)
+}
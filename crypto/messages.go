@@ -7,7 +7,6 @@ import (
 	"google.golang.org/protobuf/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
-	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 // Once a message is decoded the MessageInfo contains metadata about it.
@@ -33,16 +32,14 @@ func (self *MessageInfo) IterateJobs(
 	processor func(ctx context.Context,
 		msg *crypto_proto.VeloMessage) error) (rerr error) {
 	for _, raw := range self.RawCompressed {
-		if self.Compression == crypto_proto.PackedMessageList_ZCOMPRESSION {
-			decompressed, err := utils.Uncompress(ctx, raw)
-			if err != nil {
-				return errors.New("Unable to decompress MessageList")
-			}
-			raw = decompressed
-
+		decompressed, err := Uncompress(ctx, self.Compression, raw)
+		if err != nil {
+			return errors.New("Unable to decompress MessageList")
 		}
+		raw = decompressed
+
 		message_list := &crypto_proto.MessageList{}
-		err := proto.Unmarshal(raw, message_list)
+		err = proto.Unmarshal(raw, message_list)
 		if err != nil {
 			return errors.Wrap(err, 0)
 		}
@@ -0,0 +1,391 @@
+/*
+  Chain of custody manifests for collections.
+
+  The manifest records everything needed to show where the collected
+  data came from and that it was not modified since: the server and
+  client identity, the artifacts and parameters collected, the
+  collection timestamps and the hashes of every file making up the
+  collection (results, logs and uploads).
+
+  The manifest is signed with the server's private key. The
+  signature includes the server certificate so it can be verified
+  against the deployment's CA certificate.
+*/
+
+package custody
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	MANIFEST_VERSION = 1
+)
+
+type ServerIdentity struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	OrgId   string `json:"org_id,omitempty"`
+	OrgName string `json:"org_name,omitempty"`
+
+	// The certificate the manifest is signed with.
+	CertificateSerial string `json:"certificate_serial,omitempty"`
+}
+
+type ClientIdentity struct {
+	ClientId     string   `json:"client_id"`
+	Hostname     string   `json:"hostname,omitempty"`
+	Fqdn         string   `json:"fqdn,omitempty"`
+	System       string   `json:"system,omitempty"`
+	Release      string   `json:"release,omitempty"`
+	MacAddresses []string `json:"mac_addresses,omitempty"`
+	FirstSeenAt  uint64   `json:"first_seen_at,omitempty"`
+
+	// The client's enrolled public key identifies the endpoint
+	// cryptographically.
+	PublicKeySha256 string `json:"public_key_sha256,omitempty"`
+}
+
+type ArtifactInfo struct {
+	Name string `json:"name"`
+
+	// Hash of the artifact definition in the repository.
+	DefinitionSha256 string `json:"definition_sha256,omitempty"`
+	BuiltIn          bool   `json:"built_in,omitempty"`
+
+	Parameters *ordereddict.Dict `json:"parameters,omitempty"`
+}
+
+type CollectionInfo struct {
+	FlowId  string `json:"flow_id"`
+	Creator string `json:"creator,omitempty"`
+	State   string `json:"state"`
+
+	// Timestamps in microseconds since the epoch.
+	CreateTime uint64 `json:"create_time"`
+	StartTime  uint64 `json:"start_time,omitempty"`
+	ActiveTime uint64 `json:"active_time,omitempty"`
+
+	TotalCollectedRows uint64 `json:"total_collected_rows"`
+	TotalUploadedFiles uint64 `json:"total_uploaded_files"`
+	TotalUploadedBytes uint64 `json:"total_uploaded_bytes"`
+
+	// Hash of the exact requests sent to the client. These include
+	// the compiled artifacts so this identifies what actually ran.
+	RequestsSha256 string `json:"requests_sha256,omitempty"`
+}
+
+type FileInfo struct {
+	// One of Result, Log or Upload
+	Type string `json:"type"`
+
+	// Where the file is stored on the server.
+	Path string `json:"path"`
+
+	// For uploads, the file's path on the endpoint and when it was
+	// received.
+	ClientPath string `json:"client_path,omitempty"`
+	Accessor   string `json:"accessor,omitempty"`
+	Received   int64  `json:"received,omitempty"`
+
+	Size   int64  `json:"size"`
+	MD5    string `json:"md5"`
+	SHA1   string `json:"sha1"`
+	SHA256 string `json:"sha256"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Manifest struct {
+	Version int `json:"version"`
+
+	// When the manifest was generated (seconds since the epoch).
+	Generated int64 `json:"generated"`
+
+	Server     ServerIdentity  `json:"server"`
+	Client     ClientIdentity  `json:"client"`
+	Collection CollectionInfo  `json:"collection"`
+	Artifacts  []*ArtifactInfo `json:"artifacts"`
+	Files      []*FileInfo     `json:"files"`
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// Build the manifest for the collection.
+func NewManifest(ctx context.Context, config_obj *config_proto.Config,
+	client_id, flow_id string) (*Manifest, error) {
+	launcher, err := services.GetLauncher(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := launcher.GetFlowDetails(ctx, config_obj, client_id, flow_id)
+	if err != nil {
+		return nil, err
+	}
+
+	collection_context := details.Context
+	if collection_context == nil {
+		return nil, fmt.Errorf("Unknown flow %v", flow_id)
+	}
+
+	request := collection_context.Request
+	if request == nil {
+		request = &flows_proto.ArtifactCollectorArgs{}
+	}
+
+	result := &Manifest{
+		Version:   MANIFEST_VERSION,
+		Generated: utils.GetTime().Now().Unix(),
+		Server:    getServerIdentity(config_obj),
+		Client:    getClientIdentity(ctx, config_obj, client_id),
+		Collection: CollectionInfo{
+			FlowId:             flow_id,
+			Creator:            request.Creator,
+			State:              collection_context.State.String(),
+			CreateTime:         collection_context.CreateTime,
+			StartTime:          collection_context.StartTime,
+			ActiveTime:         collection_context.ActiveTime,
+			TotalCollectedRows: collection_context.TotalCollectedRows,
+			TotalUploadedFiles: collection_context.TotalUploadedFiles,
+			TotalUploadedBytes: collection_context.TotalUploadedBytes,
+		},
+	}
+
+	requests, err := launcher.Storage().GetFlowRequests(
+		ctx, config_obj, client_id, flow_id, 0, 1000)
+	if err == nil {
+		serialized, err := json.Marshal(requests)
+		if err == nil {
+			result.Collection.RequestsSha256 = sha256Hex(serialized)
+		}
+	}
+
+	result.Artifacts = getArtifacts(ctx, config_obj, request)
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	flow_path_manager := paths.NewFlowPathManager(client_id, flow_id)
+
+	for _, name := range collection_context.ArtifactsWithResults {
+		path_manager, err := artifacts.NewArtifactPathManager(ctx,
+			config_obj, client_id, flow_id, name)
+		if err != nil {
+			continue
+		}
+
+		result_path, err := path_manager.GetPathForWriting()
+		if err != nil {
+			continue
+		}
+
+		result.Files = append(result.Files,
+			hashFile(file_store_factory, "Result", result_path))
+	}
+
+	log_path := flow_path_manager.Log()
+	if _, err := file_store_factory.StatFile(log_path); err == nil {
+		result.Files = append(result.Files,
+			hashFile(file_store_factory, "Log", log_path))
+	}
+
+	result.Files = append(result.Files, getUploads(
+		ctx, file_store_factory, flow_path_manager)...)
+
+	return result, nil
+}
+
+func getServerIdentity(config_obj *config_proto.Config) ServerIdentity {
+	version := config.GetVersion()
+	result := ServerIdentity{
+		Version: version.Version,
+		Commit:  version.Commit,
+		OrgId:   utils.NormalizedOrgId(config_obj.OrgId),
+		OrgName: services.GetOrgName(config_obj),
+	}
+
+	if config_obj.Frontend != nil {
+		result.Name = config_obj.Frontend.Hostname
+		cert, err := crypto_utils.ParseX509CertFromPemStr(
+			[]byte(config_obj.Frontend.Certificate))
+		if err == nil {
+			result.Name = crypto_utils.GetSubjectName(cert)
+			result.CertificateSerial = cert.SerialNumber.String()
+		}
+	}
+
+	return result
+}
+
+func getClientIdentity(ctx context.Context,
+	config_obj *config_proto.Config, client_id string) ClientIdentity {
+	result := ClientIdentity{ClientId: client_id}
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err == nil {
+		client_info, err := client_info_manager.Get(ctx, client_id)
+		if err == nil {
+			result.Hostname = client_info.Hostname
+			result.Fqdn = client_info.Fqdn
+			result.System = client_info.System
+			result.Release = client_info.Release
+			result.MacAddresses = client_info.MacAddresses
+			result.FirstSeenAt = client_info.FirstSeenAt
+		}
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err == nil {
+		pem := &crypto_proto.PublicKey{}
+		client_path_manager := paths.NewClientPathManager(client_id)
+		err = db.GetSubject(config_obj, client_path_manager.Key(), pem)
+		if err == nil && len(pem.Pem) > 0 {
+			result.PublicKeySha256 = sha256Hex(pem.Pem)
+		}
+	}
+
+	return result
+}
+
+func getArtifacts(ctx context.Context, config_obj *config_proto.Config,
+	request *flows_proto.ArtifactCollectorArgs) []*ArtifactInfo {
+	result := []*ArtifactInfo{}
+
+	var repository services.Repository
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err == nil {
+		repository, _ = manager.GetGlobalRepository(config_obj)
+	}
+
+	for _, name := range request.Artifacts {
+		info := &ArtifactInfo{Name: name}
+
+		if repository != nil {
+			artifact, pres := repository.Get(ctx, config_obj, name)
+			if pres && artifact.Raw != "" {
+				info.DefinitionSha256 = sha256Hex([]byte(artifact.Raw))
+				info.BuiltIn = artifact.BuiltIn
+			}
+		}
+
+		for _, spec := range request.Specs {
+			if spec.Artifact != name || spec.Parameters == nil {
+				continue
+			}
+
+			parameters := ordereddict.NewDict()
+			for _, env := range spec.Parameters.Env {
+				parameters.Set(env.Key, env.Value)
+			}
+			info.Parameters = parameters
+		}
+
+		result = append(result, info)
+	}
+
+	return result
+}
+
+func getUploads(ctx context.Context, file_store_factory api.FileStore,
+	flow_path_manager *paths.FlowPathManager) []*FileInfo {
+	result := []*FileInfo{}
+
+	reader, err := result_sets.NewResultSetReader(
+		file_store_factory, flow_path_manager.UploadMetadata())
+	if err != nil {
+		return result
+	}
+	defer reader.Close()
+
+	for row := range reader.Rows(ctx) {
+		// Sparse file indexes are not part of the evidence.
+		file_type, _ := row.GetString("Type")
+		if file_type == "idx" {
+			continue
+		}
+
+		client_path, _ := row.GetString("vfs_path")
+
+		var src api.FSPathSpec
+		components, pres := row.GetStrings("_Components")
+		if pres && len(components) > 0 {
+			src = path_specs.NewUnsafeFilestorePath(components...).
+				SetType(api.PATH_TYPE_FILESTORE_ANY)
+		} else {
+			src = path_specs.NewUnsafeFilestorePath(
+				utils.SplitComponents(client_path)...).
+				SetType(api.PATH_TYPE_FILESTORE_ANY)
+		}
+
+		info := hashFile(file_store_factory, "Upload", src)
+		info.ClientPath = client_path
+		info.Accessor, _ = row.GetString("_accessor")
+		received, _ := row.GetInt64("Timestamp")
+		info.Received = received
+
+		result = append(result, info)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result
+}
+
+func hashFile(file_store_factory api.FileStore,
+	file_type string, path api.FSPathSpec) *FileInfo {
+	result := &FileInfo{
+		Type: file_type,
+		Path: path.AsClientPath(),
+	}
+
+	fd, err := file_store_factory.ReadFile(path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer fd.Close()
+
+	md5_sum := md5.New()
+	sha1_sum := sha1.New()
+	sha256_sum := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(md5_sum, sha1_sum, sha256_sum), fd)
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	result.Size = size
+	result.MD5 = hex.EncodeToString(md5_sum.Sum(nil))
+	result.SHA1 = hex.EncodeToString(sha1_sum.Sum(nil))
+	result.SHA256 = hex.EncodeToString(sha256_sum.Sum(nil))
+
+	return result
+}
@@ -0,0 +1,126 @@
+package custody_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/custody"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/data"
+	_ "www.velocidex.com/golang/velociraptor/vql/protocols"
+)
+
+type ManifestTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *ManifestTestSuite) SetupTest() {
+	self.ConfigObj = self.LoadConfig()
+	self.ConfigObj.Services.ServerArtifacts = true
+
+	self.LoadArtifactsIntoConfig([]string{`
+name: TestArtifact
+type: SERVER
+parameters:
+- name: Message
+  default: Hello
+sources:
+- query: |
+    SELECT Message AS Col,
+      upload(accessor="data", file="Some Data", name="test.txt") AS Upload
+    FROM scope()
+`})
+
+	self.TestSuite.SetupTest()
+}
+
+func (self *ManifestTestSuite) collect() string {
+	manager, _ := services.GetRepositoryManager(self.ConfigObj)
+	repository, err := manager.GetGlobalRepository(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	var acl_manager vql_subsystem.ACLManager
+	flow_id, err := launcher.ScheduleArtifactCollection(self.Ctx, self.ConfigObj,
+		acl_manager, repository, &flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{"TestArtifact"},
+			ClientId:  "server",
+			Creator:   "admin",
+		}, utils.SyncCompleter)
+	assert.NoError(self.T(), err)
+
+	vtesting.WaitUntil(time.Second*5, self.T(), func() bool {
+		flow, err := launcher.GetFlowDetails(
+			self.Ctx, self.ConfigObj, "server", flow_id)
+		assert.NoError(self.T(), err)
+
+		return flow.Context.State == flows_proto.ArtifactCollectorContext_FINISHED
+	})
+
+	return flow_id
+}
+
+func (self *ManifestTestSuite) TestManifest() {
+	flow_id := self.collect()
+
+	manifest, err := custody.NewManifest(
+		self.Ctx, self.ConfigObj, "server", flow_id)
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), flow_id, manifest.Collection.FlowId)
+	assert.Equal(self.T(), "admin", manifest.Collection.Creator)
+	assert.Equal(self.T(), "server", manifest.Client.ClientId)
+	assert.Equal(self.T(), 1, len(manifest.Artifacts))
+	assert.Equal(self.T(), "TestArtifact", manifest.Artifacts[0].Name)
+
+	// The upload is hashed.
+	var upload *custody.FileInfo
+	for _, f := range manifest.Files {
+		if f.Type == "Upload" {
+			upload = f
+		}
+	}
+	assert.True(self.T(), upload != nil)
+
+	// sha256 of "Some Data"
+	assert.Equal(self.T(),
+		"2d27ec8437ec76ec2db484c98ed89f7793f0575e271518dd1d62a18fde6e202d",
+		upload.SHA256)
+	assert.Equal(self.T(), int64(9), upload.Size)
+}
+
+func (self *ManifestTestSuite) TestSignAndVerify() {
+	flow_id := self.collect()
+
+	manifest, err := custody.NewManifest(
+		self.Ctx, self.ConfigObj, "server", flow_id)
+	assert.NoError(self.T(), err)
+
+	serialized, signature, err := manifest.Sign(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	verified, err := custody.Verify(self.ConfigObj, serialized, signature)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), flow_id, verified.Collection.FlowId)
+
+	// Any modification to the manifest invalidates the signature.
+	tampered := bytes.Replace(serialized, []byte(flow_id),
+		[]byte("F.Tampered"), -1)
+	_, err = custody.Verify(self.ConfigObj, tampered, signature)
+	assert.Error(self.T(), err)
+}
+
+func TestManifest(t *testing.T) {
+	suite.Run(t, &ManifestTestSuite{})
+}
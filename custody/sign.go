@@ -0,0 +1,132 @@
+package custody
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	SIGNATURE_ALGORITHM = "RSASSA-PKCS1-v1_5-SHA256"
+)
+
+// A detached signature over the serialized manifest.
+type Signature struct {
+	Algorithm string `json:"algorithm"`
+	Signature []byte `json:"signature"`
+
+	// Seconds since the epoch.
+	Signed int64 `json:"signed"`
+
+	// The PEM encoded server certificate.
+	Certificate string `json:"certificate"`
+}
+
+// Serialize the manifest and sign the serialized data. The
+// signature covers the exact bytes so both must be stored as they
+// are.
+func (self *Manifest) Sign(
+	config_obj *config_proto.Config) ([]byte, []byte, error) {
+	serialized, err := json.MarshalIndent(self)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if config_obj.Frontend == nil {
+		return nil, nil, errors.New("No server key to sign with")
+	}
+
+	private_key, err := crypto_utils.ParseRsaPrivateKeyFromPemStr(
+		[]byte(config_obj.Frontend.PrivateKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashed := sha256.Sum256(serialized)
+	signature, err := rsa.SignPKCS1v15(
+		rand.Reader, private_key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serialized_signature, err := json.MarshalIndent(&Signature{
+		Algorithm:   SIGNATURE_ALGORITHM,
+		Signature:   signature,
+		Signed:      utils.GetTime().Now().Unix(),
+		Certificate: config_obj.Frontend.Certificate,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return serialized, serialized_signature, nil
+}
+
+// Verify the serialized manifest against its signature. The
+// signing certificate must be issued by the deployment's CA.
+func Verify(config_obj *config_proto.Config,
+	serialized_manifest, serialized_signature []byte) (*Manifest, error) {
+	signature := &Signature{}
+	err := json.Unmarshal(serialized_signature, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	if signature.Algorithm != SIGNATURE_ALGORITHM {
+		return nil, errors.New("Unsupported signature algorithm")
+	}
+
+	cert, err := crypto_utils.ParseX509CertFromPemStr(
+		[]byte(signature.Certificate))
+	if err != nil {
+		return nil, err
+	}
+
+	if config_obj.Client == nil {
+		return nil, errors.New("No CA certificate to verify with")
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(config_obj.Client.CaCertificate)) {
+		return nil, errors.New("Unable to parse CA certificate")
+	}
+
+	// The certificate must have been valid when the manifest was
+	// signed - it may have expired since.
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: time.Unix(signature.Signed, 0),
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	public_key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("Not RSA algorithm")
+	}
+
+	hashed := sha256.Sum256(serialized_manifest)
+	err = rsa.VerifyPKCS1v15(public_key, crypto.SHA256, hashed[:],
+		signature.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	err = json.Unmarshal(serialized_manifest, manifest)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
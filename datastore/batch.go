@@ -0,0 +1,510 @@
+// Group commit for the MemcacheFileDataStore.
+//
+// Flow completion causes many small writes, often to the same few
+// files (e.g. the flow object and its stats). When batching is
+// enabled, mutations are collected for a short interval and
+// committed together:
+//
+// 1. The batch is appended to a journal in the datastore directory,
+//    followed by a commit marker. The journal is synced to disk once
+//    for the whole batch.
+//
+// 2. The files are written without syncing them individually. Only
+//    the last mutation of each file is written.
+//
+// 3. When the journal grows too large all the files written since
+//    the last checkpoint are synced and the journal is truncated.
+//
+// If the server crashes, the journal is replayed at startup so every
+// committed batch reaches the files. Batches without a commit marker
+// were never acknowledged and are dropped. Replaying is idempotent
+// because the journal is in commit order.
+
+package datastore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+)
+
+const (
+	JOURNAL_NAME = "datastore.journal"
+
+	// Marks the end of a committed batch in the journal.
+	journal_op_commit = -1
+)
+
+var (
+	metricBatchCommits = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "datastore_batch_commits",
+			Help: "Number of batches committed to the datastore",
+		})
+
+	metricBatchSize = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "datastore_batch_size",
+			Help:    "Number of files written in each batch",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+		})
+
+	metricBatchCoalesced = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "datastore_batch_coalesced",
+			Help: "Writes which were replaced by a later write to the same file",
+		})
+
+	metricBatchLatency = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "datastore_batch_commit_latency",
+			Help:    "Time to commit a batch to the datastore",
+			Buckets: prometheus.LinearBuckets(0.01, 0.05, 10),
+		})
+
+	metricJournalBytes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "datastore_journal_bytes",
+			Help: "Size of the datastore journal since the last checkpoint",
+		})
+
+	metricJournalReplayed = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "datastore_journal_replayed",
+			Help: "Writes replayed from the journal at startup",
+		})
+)
+
+type journalRecord struct {
+	Op       int    `json:"op"`
+	Filename string `json:"file,omitempty"`
+	Data     []byte `json:"data,omitempty"`
+}
+
+type batchWriter struct {
+	mu sync.Mutex
+
+	// The last mutation for each file which is not committed yet.
+	pending map[string]*Mutation
+
+	// Mutations being committed right now - readers still need to
+	// see them until they are on disk.
+	committing map[string]*Mutation
+
+	// Mutations replaced by a later write. They still need to be
+	// completed when their batch is committed.
+	replaced []*Mutation
+
+	// Only one commit at a time.
+	commit_mu sync.Mutex
+
+	journal_path string
+	journal      *os.File
+	journal_size int64
+	max_journal  int64
+
+	// Files written since the last checkpoint.
+	dirty map[string]bool
+
+	max_size int
+	interval time.Duration
+	trigger  chan bool
+
+	// Called for each mutation once it is committed.
+	complete func(mutation *Mutation)
+
+	config_obj *config_proto.Config
+}
+
+func newBatchWriter(config_obj *config_proto.Config,
+	complete func(mutation *Mutation)) (*batchWriter, error) {
+	if config_obj.Datastore == nil || config_obj.Datastore.Location == "" {
+		return nil, datastoreNotConfiguredError
+	}
+
+	result := &batchWriter{
+		pending:    make(map[string]*Mutation),
+		committing: make(map[string]*Mutation),
+		dirty:      make(map[string]bool),
+		journal_path: filepath.Join(
+			config_obj.Datastore.Location, JOURNAL_NAME),
+		max_size: int(config_obj.Datastore.WriteBatchMaxSize),
+		max_journal: config_obj.Datastore.WriteJournalMaxSizeMb *
+			1024 * 1024,
+		interval: time.Duration(
+			config_obj.Datastore.WriteBatchIntervalMs) * time.Millisecond,
+		trigger:    make(chan bool, 1),
+		complete:   complete,
+		config_obj: config_obj,
+	}
+
+	if result.max_size <= 0 {
+		result.max_size = 1000
+	}
+
+	if result.max_journal <= 0 {
+		result.max_journal = 64 * 1024 * 1024
+	}
+
+	// Bring the files up to date with the journal from the last
+	// run. Once they are synced the old journal is truncated.
+	err := result.replay()
+	if err != nil {
+		return nil, err
+	}
+
+	result.journal, err = os.OpenFile(result.journal_path,
+		os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_TRUNC, 0660)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Queue the mutation for the next commit.
+func (self *batchWriter) add(mutation *Mutation) {
+	filename := mutation.urn.AsDatastoreFilename(mutation.org_config_obj)
+
+	self.mu.Lock()
+	old, pres := self.pending[filename]
+	if pres {
+		self.replaced = append(self.replaced, old)
+		metricBatchCoalesced.Inc()
+	}
+	self.pending[filename] = mutation
+	full := len(self.pending) >= self.max_size
+	self.mu.Unlock()
+
+	if full {
+		select {
+		case self.trigger <- true:
+		default:
+		}
+	}
+}
+
+// Get the data for the file if it is not committed yet. deleted is
+// true if the file is about to be removed.
+func (self *batchWriter) get(filename string) (
+	data []byte, deleted bool, pres bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	mutation, pres := self.pending[filename]
+	if !pres {
+		mutation, pres = self.committing[filename]
+		if !pres {
+			return nil, false, false
+		}
+	}
+
+	return mutation.data, mutation.op == MUTATION_OP_DEL_SUBJECT, true
+}
+
+// Commit all pending mutations. Errors writing individual files are
+// recorded in their mutations.
+func (self *batchWriter) Commit() error {
+	batch, replaced, err := self.commit()
+
+	// Completions may write to the datastore again so they run after
+	// the commit lock is released.
+	for _, mutation := range replaced {
+		self.complete(mutation)
+	}
+
+	for _, mutation := range batch {
+		self.complete(mutation)
+	}
+
+	return err
+}
+
+func (self *batchWriter) commit() (
+	batch map[string]*Mutation, replaced []*Mutation, err error) {
+	self.commit_mu.Lock()
+	defer self.commit_mu.Unlock()
+
+	self.mu.Lock()
+	batch = self.pending
+	replaced = self.replaced
+	self.pending = make(map[string]*Mutation)
+	self.replaced = nil
+	self.committing = batch
+	self.mu.Unlock()
+
+	if len(batch) == 0 && len(replaced) == 0 {
+		return batch, replaced, nil
+	}
+
+	timer := prometheus.NewTimer(metricBatchLatency)
+	defer timer.ObserveDuration()
+
+	// If the journal fails we still write the files but they are
+	// not protected from a crash.
+	err = self.writeJournal(batch)
+	if err != nil {
+		err = fmt.Errorf("Datastore journal: %w", err)
+		logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+		logger.Error("%v", err)
+	}
+
+	for filename, mutation := range batch {
+		switch mutation.op {
+		case MUTATION_OP_SET_SUBJECT:
+			mutation.err = writeContentToFile(
+				mutation.org_config_obj, mutation.urn, mutation.data)
+			self.dirty[filename] = true
+
+		case MUTATION_OP_DEL_SUBJECT:
+			mutation.err = file_based_imp.DeleteSubject(
+				mutation.org_config_obj, mutation.urn)
+			delete(self.dirty, filename)
+		}
+
+		// Nobody may be waiting for the mutation so the error is
+		// logged too.
+		if mutation.err != nil {
+			logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+			logger.Error("Datastore batch: %v: %v", filename, mutation.err)
+		}
+	}
+
+	// A replaced mutation shares the fate of the write that replaced
+	// it.
+	for _, mutation := range replaced {
+		latest, pres := batch[mutation.urn.AsDatastoreFilename(
+			mutation.org_config_obj)]
+		if pres {
+			mutation.err = latest.err
+		}
+	}
+
+	self.mu.Lock()
+	self.committing = make(map[string]*Mutation)
+	self.mu.Unlock()
+
+	metricBatchCommits.Inc()
+	metricBatchSize.Observe(float64(len(batch)))
+
+	if self.journal_size > self.max_journal {
+		checkpoint_err := self.checkpoint()
+		if err == nil {
+			err = checkpoint_err
+		}
+	}
+
+	return batch, replaced, err
+}
+
+func (self *batchWriter) writeJournal(batch map[string]*Mutation) error {
+	if self.journal == nil {
+		return errors.New("journal is closed")
+	}
+
+	buf := []byte{}
+	for filename, mutation := range batch {
+		record := &journalRecord{
+			Op:       mutation.op,
+			Filename: filename,
+			Data:     mutation.data,
+		}
+		serialized, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, serialized...)
+		buf = append(buf, '\n')
+	}
+
+	serialized, _ := json.Marshal(&journalRecord{Op: journal_op_commit})
+	buf = append(buf, serialized...)
+	buf = append(buf, '\n')
+
+	n, err := self.journal.Write(buf)
+	self.journal_size += int64(n)
+	metricJournalBytes.Set(float64(self.journal_size))
+	if err != nil {
+		return err
+	}
+
+	return self.journal.Sync()
+}
+
+// Sync the written files so the journal is no longer needed.
+func (self *batchWriter) checkpoint() error {
+	dirs := make(map[string]bool)
+	for filename := range self.dirty {
+		err := syncPath(filename)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		dirs[filepath.Dir(filename)] = true
+	}
+
+	// New files also need their directory entries synced. This is
+	// not supported on all platforms so errors are ignored.
+	for dir := range dirs {
+		_ = syncPath(dir)
+	}
+
+	self.dirty = make(map[string]bool)
+
+	if self.journal != nil {
+		err := self.journal.Truncate(0)
+		if err != nil {
+			return err
+		}
+		err = self.journal.Sync()
+		if err != nil {
+			return err
+		}
+	}
+
+	self.journal_size = 0
+	metricJournalBytes.Set(0)
+	return nil
+}
+
+// Commit the remaining mutations and checkpoint before we exit.
+func (self *batchWriter) Close() error {
+	err := self.Commit()
+
+	self.commit_mu.Lock()
+	defer self.commit_mu.Unlock()
+
+	if err == nil {
+		err = self.checkpoint()
+	}
+
+	if self.journal != nil {
+		self.journal.Close()
+		self.journal = nil
+	}
+	return err
+}
+
+// Commit every interval or when the batch is full.
+func (self *batchWriter) Start(ctx context.Context, wg *sync.WaitGroup) {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(self.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				err := self.Close()
+				if err != nil {
+					logger.Error("Datastore batch writer: %v", err)
+				}
+				return
+
+			case <-self.trigger:
+			case <-ticker.C:
+			}
+
+			// Errors are logged by Commit.
+			_ = self.Commit()
+		}
+	}()
+}
+
+// Apply the committed batches in the journal to the files.
+func (self *batchWriter) replay() error {
+	fd, err := os.Open(self.journal_path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer fd.Close()
+
+	records := []*journalRecord{}
+	count := 0
+
+	reader := bufio.NewReader(fd)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// A partial line is the end of a batch which was not
+			// committed.
+			break
+		}
+
+		record := &journalRecord{}
+		err = json.Unmarshal(line, record)
+		if err != nil {
+			break
+		}
+
+		if record.Op != journal_op_commit {
+			records = append(records, record)
+			continue
+		}
+
+		for _, record := range records {
+			err := applyJournalRecord(record)
+			if err != nil {
+				return err
+			}
+			self.dirty[record.Filename] = true
+			count++
+		}
+		records = nil
+	}
+
+	if count > 0 {
+		logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+		logger.Info("Datastore journal: Replayed %v writes from %v",
+			count, self.journal_path)
+		metricJournalReplayed.Add(float64(count))
+	}
+
+	return self.checkpoint()
+}
+
+func applyJournalRecord(record *journalRecord) error {
+	switch record.Op {
+	case MUTATION_OP_SET_SUBJECT:
+		err := os.MkdirAll(filepath.Dir(record.Filename), 0700)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(record.Filename, record.Data, 0660)
+
+	case MUTATION_OP_DEL_SUBJECT:
+		err := os.Remove(record.Filename)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func syncPath(path string) error {
+	fd, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	return fd.Sync()
+}
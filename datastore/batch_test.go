@@ -0,0 +1,215 @@
+package datastore_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+// Run all the memcache file tests with write batching enabled.
+type BatchedMemcacheFileTestSuite struct {
+	MemcacheFileTestSuite
+}
+
+func (self *BatchedMemcacheFileTestSuite) SetupTest() {
+	var err error
+	self.dirname, err = ioutil.TempDir("", "datastore_test")
+	assert.NoError(self.T(), err)
+
+	self.config_obj = config.GetDefaultConfig()
+	self.config_obj.Datastore.Implementation = "MemcacheFileDataStore"
+	self.config_obj.Datastore.MemcacheWriteMutationBuffer = 1000
+	self.config_obj.Datastore.FilestoreDirectory = self.dirname
+	self.config_obj.Datastore.Location = self.dirname
+	self.config_obj.Datastore.WriteBatchIntervalMs = 50
+	self.BaseTestSuite.config_obj = self.config_obj
+
+	self.ctx, self.cancel = context.WithCancel(context.Background())
+
+	db := datastore.NewMemcacheFileDataStore(self.config_obj)
+	self.datastore = db
+
+	db.Clear()
+	db.StartWriter(self.ctx, &self.wg, self.config_obj)
+}
+
+// Repeated writes to the same file are combined into one.
+func (self *BatchedMemcacheFileTestSuite) TestCoalescing() {
+	self.cancel()
+	self.wg.Wait()
+
+	// Commit manually with a single writer so writes stay in order.
+	self.config_obj.Datastore.WriteBatchIntervalMs = 60000
+	self.config_obj.Datastore.MemcacheWriteMutationWriters = 1
+	self.ctx, self.cancel = context.WithCancel(context.Background())
+
+	db := datastore.NewMemcacheFileDataStore(self.config_obj)
+	db.StartWriter(self.ctx, &self.wg, self.config_obj)
+
+	snapshot := vtesting.GetMetrics(self.T(), "datastore_batch_")
+
+	urn := path_specs.NewSafeDatastorePath("clients", "C.1234")
+	for i := 0; i < 10; i++ {
+		err := db.SetSubjectWithCompletion(self.config_obj, urn,
+			&api_proto.ClientMetadata{ClientId: fmt.Sprintf("C.%d", i)},
+			nil)
+		assert.NoError(self.T(), err)
+	}
+
+	// Nothing is written yet but readers still see the latest data
+	// when it is not in the cache.
+	filename := urn.AsDatastoreFilename(self.config_obj)
+	_, err := os.Stat(filename)
+	assert.True(self.T(), os.IsNotExist(err))
+
+	db.Clear()
+	record := &api_proto.ClientMetadata{}
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		err := db.GetSubject(self.config_obj, urn, record)
+		return err == nil && record.ClientId == "C.9"
+	})
+
+	db.Flush()
+
+	record = &api_proto.ClientMetadata{}
+	err = file_based_imp.GetSubject(self.config_obj, urn, record)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "C.9", record.ClientId)
+
+	metrics := vtesting.GetMetricsDifference(
+		self.T(), "datastore_batch_co", snapshot)
+	commits, _ := metrics.GetInt64("datastore_batch_commits")
+	coalesced, _ := metrics.GetInt64("datastore_batch_coalesced")
+	assert.Equal(self.T(), int64(1), commits)
+	assert.Equal(self.T(), int64(9), coalesced)
+
+	// Sync writes commit the batch immediately.
+	err = db.SetSubjectWithCompletion(self.config_obj, urn,
+		&api_proto.ClientMetadata{ClientId: "C.sync"}, utils.SyncCompleter)
+	assert.NoError(self.T(), err)
+
+	err = file_based_imp.GetSubject(self.config_obj, urn, record)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "C.sync", record.ClientId)
+}
+
+// Committed batches in the journal are replayed at startup.
+func (self *BatchedMemcacheFileTestSuite) TestJournalReplay() {
+	self.cancel()
+	self.wg.Wait()
+
+	committed := path_specs.NewSafeDatastorePath("clients", "C.1")
+	deleted := path_specs.NewSafeDatastorePath("clients", "C.2")
+	uncommitted := path_specs.NewSafeDatastorePath("clients", "C.3")
+
+	err := file_based_imp.SetSubject(self.config_obj, deleted,
+		&api_proto.ClientMetadata{ClientId: "C.2"})
+	assert.NoError(self.T(), err)
+
+	serialized := json.MustMarshalString(&api_proto.ClientMetadata{
+		ClientId: "C.1"})
+
+	// The last batch has no commit marker because we crashed while
+	// writing it.
+	journal := fmt.Sprintf(`{"op":0,"file":%q,"data":%q}
+{"op":1,"file":%q}
+{"op":-1}
+{"op":0,"file":%q,"data":%q}
+{"op":0,"file":`,
+		committed.AsDatastoreFilename(self.config_obj),
+		base64.StdEncoding.EncodeToString([]byte(serialized)),
+		deleted.AsDatastoreFilename(self.config_obj),
+		uncommitted.AsDatastoreFilename(self.config_obj),
+		base64.StdEncoding.EncodeToString([]byte(serialized)))
+
+	journal_path := filepath.Join(self.dirname, datastore.JOURNAL_NAME)
+	err = ioutil.WriteFile(journal_path, []byte(journal), 0600)
+	assert.NoError(self.T(), err)
+
+	self.ctx, self.cancel = context.WithCancel(context.Background())
+	db := datastore.NewMemcacheFileDataStore(self.config_obj)
+	db.StartWriter(self.ctx, &self.wg, self.config_obj)
+
+	record := &api_proto.ClientMetadata{}
+	err = file_based_imp.GetSubject(self.config_obj, committed, record)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "C.1", record.ClientId)
+
+	err = file_based_imp.GetSubject(self.config_obj, deleted, record)
+	assert.Error(self.T(), err)
+
+	err = file_based_imp.GetSubject(self.config_obj, uncommitted, record)
+	assert.Error(self.T(), err)
+
+	// The journal is truncated once it was replayed.
+	data, err := ioutil.ReadFile(journal_path)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(data))
+}
+
+// The scenario of TestFullDiskErrors: writes to a full disk must fail
+// and the error must reach the caller even though the batch writer
+// commits on its own goroutine.
+func (self *BatchedMemcacheFileTestSuite) TestFullDiskErrors() {
+	if runtime.GOOS != "linux" {
+		self.T().Skip("Requires /dev/full")
+	}
+
+	sample_obj := &crypto_proto.VeloMessage{Source: "Server"}
+	obj_path := path_specs.NewUnsafeDatastorePath("test")
+
+	err := self.datastore.SetSubject(self.config_obj, obj_path, sample_obj)
+	assert.NoError(self.T(), err)
+
+	// Every write to this file now fails with ENOSPC.
+	filename := obj_path.AsDatastoreFilename(self.config_obj)
+	assert.NoError(self.T(), os.Remove(filename))
+	assert.NoError(self.T(), os.Symlink("/dev/full", filename))
+
+	sample_obj.Source = strings.Repeat("TestString", 1000)
+	err = self.datastore.SetSubject(self.config_obj, obj_path, sample_obj)
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "no space left on device")
+
+	// Synchronous writes through the writer pool also see the error.
+	err = self.datastore.SetSubjectWithCompletion(self.config_obj,
+		obj_path, sample_obj, utils.SyncCompleter)
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "no space left on device")
+
+	// Other files in the same batch are not affected.
+	other_path := path_specs.NewUnsafeDatastorePath("other")
+	err = self.datastore.SetSubjectWithCompletion(self.config_obj,
+		other_path, sample_obj, utils.SyncCompleter)
+	assert.NoError(self.T(), err)
+
+	test_obj := &crypto_proto.VeloMessage{}
+	err = file_based_imp.GetSubject(self.config_obj, other_path, test_obj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), sample_obj.Source, test_obj.Source)
+}
+
+func TestBatchedMemCacheFileDatastore(t *testing.T) {
+	suite.Run(t, &BatchedMemcacheFileTestSuite{
+		MemcacheFileTestSuite: MemcacheFileTestSuite{
+			BaseTestSuite: BaseTestSuite{},
+		}})
+}
@@ -53,12 +53,6 @@ func startFullDiskChecker(ctx context.Context, wg *sync.WaitGroup,
 		return nil
 	}
 
-	// How often to check the disk is full.
-	disk_check_freq := config_obj.Datastore.DiskCheckFrequencySec
-	if disk_check_freq == 0 {
-		disk_check_freq = 10
-	}
-
 	volumePath := ""
 	if config_obj.Datastore != nil {
 		volumePath = config_obj.Datastore.Location
@@ -82,7 +76,7 @@ func startFullDiskChecker(ctx context.Context, wg *sync.WaitGroup,
 			case <-ctx.Done():
 				return
 
-			case <-time.After(time.Duration(disk_check_freq) * time.Second):
+			case <-time.After(diskCheckFrequency(config_obj)):
 				AvailableDiskSpace(db, config_obj)
 			}
 		}
@@ -90,3 +84,13 @@ func startFullDiskChecker(ctx context.Context, wg *sync.WaitGroup,
 
 	return nil
 }
+
+// How often to check the disk is full. This may change when the
+// config is reloaded.
+func diskCheckFrequency(config_obj *config_proto.Config) time.Duration {
+	disk_check_freq := config_obj.Datastore.DiskCheckFrequencySec
+	if disk_check_freq <= 0 {
+		disk_check_freq = 10
+	}
+	return time.Duration(disk_check_freq) * time.Second
+}
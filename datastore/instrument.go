@@ -8,6 +8,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/tracing"
 )
 
 var (
@@ -36,7 +37,8 @@ func Instrument(access_type, datastore string,
 		DatastoreHistorgram.WithLabelValues(tag, access_type, datastore).Observe(v)
 	}))
 
-	return timer.ObserveDuration
+	return traceAccess(access_type, datastore, tag, path_spec,
+		timer.ObserveDuration)
 }
 
 func InstrumentWithDelay(
@@ -56,7 +58,26 @@ func InstrumentWithDelay(
 		time.Sleep(time.Duration(inject_time) * time.Millisecond)
 	}
 
-	return timer.ObserveDuration
+	return traceAccess(access_type, datastore, tag, path_spec,
+		timer.ObserveDuration)
+}
+
+// Record a span for the access when storage tracing is enabled.
+func traceAccess(access_type, datastore, tag string,
+	path_spec api.DSPathSpec,
+	observe func() time.Duration) func() time.Duration {
+	if !tracing.StorageEnabled() {
+		return observe
+	}
+
+	span := tracing.StartStorage("datastore."+access_type,
+		tracing.String("tag", tag),
+		tracing.String("datastore", datastore),
+		tracing.String("path", path_spec.AsClientPath()))
+	return func() time.Duration {
+		span.End()
+		return observe()
+	}
 }
 
 func init() {
@@ -44,6 +44,7 @@ package datastore
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 	"sync"
@@ -112,6 +113,17 @@ type Mutation struct {
 
 	// Will run when committed to disk.
 	completion func()
+
+	// The caller is waiting for this mutation so a pending batch is
+	// committed immediately.
+	sync bool
+
+	// The directory cache was already updated for this mutation.
+	cached bool
+
+	// The result of writing the mutation. Only valid once the
+	// mutation is completed.
+	err error
 }
 
 type MemcacheFileDataStore struct {
@@ -122,6 +134,13 @@ type MemcacheFileDataStore struct {
 	ctx    context.Context
 	cancel func()
 
+	// When write batching is enabled mutations are committed in
+	// groups.
+	batch *batchWriter
+
+	// Decoded objects for hot paths. May be nil.
+	objects *ObjectCache
+
 	started bool
 }
 
@@ -163,6 +182,9 @@ func (self *MemcacheFileDataStore) Flush() {
 			}
 			self.processMutation(mutation)
 		default:
+			if self.batch != nil {
+				_ = self.batch.Commit()
+			}
 			return
 		}
 	}
@@ -190,10 +212,25 @@ func (self *MemcacheFileDataStore) StartWriter(
 	if buffer_size < 0 {
 		buffer_size = 1000
 	}
+
+	var batch *batchWriter
+	if config_obj.Datastore != nil &&
+		config_obj.Datastore.WriteBatchIntervalMs > 0 {
+		var err error
+		batch, err = newBatchWriter(config_obj, self.completeMutation)
+		if err != nil {
+			logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+			logger.Error("Unable to start datastore write batching: %v", err)
+		} else {
+			batch.Start(ctx, wg)
+		}
+	}
+
 	self.mu.Lock()
 	self.writer = make(chan *Mutation, buffer_size)
 	self.ctx = ctx
 	self.started = true
+	self.batch = batch
 	self.mu.Unlock()
 
 	if writers == 0 {
@@ -230,39 +267,121 @@ func (self *MemcacheFileDataStore) StartWriter(
 
 func (self *MemcacheFileDataStore) processMutation(mutation *Mutation) {
 	metricIdleWriters.Dec()
-	switch mutation.op {
-	case MUTATION_OP_SET_SUBJECT:
-		writeContentToFile(mutation.org_config_obj, mutation.urn, mutation.data)
-		self.invalidateDirCache(mutation.org_config_obj, mutation.urn)
+	defer metricIdleWriters.Inc()
 
-		// Call the completion function once we hit
-		// the directory datastore.
-		if mutation.completion != nil {
-			mutation.completion()
+	// The batch writer completes the mutation when it is committed.
+	if self.batch != nil {
+		self.batch.add(mutation)
+		if mutation.sync {
+			_ = self.batch.Commit()
 		}
+		return
+	}
+
+	switch mutation.op {
+	case MUTATION_OP_SET_SUBJECT:
+		mutation.err = writeContentToFile(
+			mutation.org_config_obj, mutation.urn, mutation.data)
 
 	case MUTATION_OP_DEL_SUBJECT:
-		file_based_imp.DeleteSubject(mutation.org_config_obj, mutation.urn)
+		mutation.err = file_based_imp.DeleteSubject(
+			mutation.org_config_obj, mutation.urn)
+	}
+
+	self.completeMutation(mutation)
+}
+
+// Called once the mutation hit the directory datastore.
+func (self *MemcacheFileDataStore) completeMutation(mutation *Mutation) {
+	switch {
+	case mutation.cached:
+	case mutation.op == MUTATION_OP_SET_SUBJECT:
+		self.invalidateDirCache(mutation.org_config_obj, mutation.urn)
+
+	case mutation.op == MUTATION_OP_DEL_SUBJECT:
 		self.invalidateDirCache(mutation.org_config_obj, mutation.urn.Dir())
+	}
 
-		// Call the completion function once we hit
-		// the directory datastore.
-		if mutation.completion != nil {
-			mutation.completion()
-		}
+	if mutation.completion != nil {
+		mutation.completion()
+	}
+
+	// The object may have been cached again from the memcache before
+	// the completion removed it.
+	if mutation.op == MUTATION_OP_DEL_SUBJECT {
+		self.invalidateObject(mutation.org_config_obj, mutation.urn)
 	}
 
-	metricIdleWriters.Inc()
 	if mutation.wg != nil {
 		mutation.wg.Done()
 	}
 }
 
+// Read data which is not committed to disk yet.
+func (self *MemcacheFileDataStore) getPending(
+	config_obj *config_proto.Config, urn api.DSPathSpec) (
+	data []byte, pres bool, err error) {
+	if self.batch == nil {
+		return nil, false, nil
+	}
+
+	data, deleted, pres := self.batch.get(urn.AsDatastoreFilename(config_obj))
+	if pres && deleted {
+		return nil, true, fmt.Errorf("While opening %v: %w",
+			urn.AsClientPath(), os.ErrNotExist)
+	}
+	return data, pres, nil
+}
+
+func (self *MemcacheFileDataStore) ObjectCache() *ObjectCache {
+	return self.objects
+}
+
 func (self *MemcacheFileDataStore) GetSubject(
 	config_obj *config_proto.Config,
 	urn api.DSPathSpec,
 	message proto.Message) error {
 
+	if !self.objects.Cacheable(urn) {
+		return self.getSubject(config_obj, urn, message)
+	}
+
+	key := urn.AsDatastoreFilename(config_obj)
+	if self.objects.Get(key, message) {
+		return nil
+	}
+
+	// Do not cache the object if it was written while we decoded
+	// it.
+	generation := self.objects.Generation()
+	err := self.getSubject(config_obj, urn, message)
+	if err == nil {
+		self.objects.SetIfUnchanged(key, generation, message)
+	}
+	return err
+}
+
+// Keep the written object so it does not need to be decoded again.
+func (self *MemcacheFileDataStore) setObject(
+	config_obj *config_proto.Config,
+	urn api.DSPathSpec, message proto.Message) {
+	if self.objects.Cacheable(urn) {
+		self.objects.Set(urn.AsDatastoreFilename(config_obj), message)
+	}
+}
+
+func (self *MemcacheFileDataStore) invalidateObject(
+	config_obj *config_proto.Config, urn api.DSPathSpec) {
+	if self.objects != nil {
+		self.objects.Invalidate(urn.AsDatastoreFilename(config_obj))
+	}
+}
+
+func (self *MemcacheFileDataStore) getSubject(
+	config_obj *config_proto.Config,
+	urn api.DSPathSpec,
+	message proto.Message) error {
+
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
@@ -270,9 +389,18 @@ func (self *MemcacheFileDataStore) GetSubject(
 
 	err := self.cache.GetSubject(config_obj, urn, message)
 	if errors.Is(err, os.ErrNotExist) {
-		// The file is not in the cache, read it from the file system
-		// instead.
-		serialized_content, err := readContentFromFile(config_obj, urn)
+		// The file is not in the cache, it may be waiting to be
+		// committed.
+		serialized_content, pres, err := self.getPending(config_obj, urn)
+		if pres {
+			if err != nil {
+				return err
+			}
+			return unmarshalData(serialized_content, urn, message)
+		}
+
+		// Read it from the file system instead.
+		serialized_content, err = readContentFromFile(config_obj, urn)
 		if err != nil {
 			return err
 		}
@@ -329,6 +457,7 @@ func (self *MemcacheFileDataStore) SetSubjectWithCompletion(
 	// Add the data to the memory cache (do not call completion yet
 	// until we sync the file based datastore).
 	err = self.cache.SetSubjectWithCompletion(config_obj, urn, message, nil)
+	self.setObject(config_obj, urn, message)
 
 	if self.ctx == nil {
 		return notInitializedError
@@ -348,8 +477,13 @@ func (self *MemcacheFileDataStore) SetSubjectWithCompletion(
 	// flushed to disk.
 	if utils.CompareFuncs(mutation.completion, utils.SyncCompleter) {
 		wg.Add(1)
-		defer wg.Wait()
 		mutation.completion = wg.Done
+		mutation.sync = true
+	}
+
+	// Config file switches off asynchronous writes.
+	if config_obj.Datastore.MemcacheWriteMutationBuffer < 0 {
+		mutation.sync = true
 	}
 
 	wg.Add(1)
@@ -365,10 +499,11 @@ func (self *MemcacheFileDataStore) SetSubjectWithCompletion(
 	case self.writer <- mutation:
 	}
 
-	// Config file switches off asynchronous writes, wait here for
-	// completion.
-	if config_obj.Datastore.MemcacheWriteMutationBuffer < 0 {
+	// Wait here for completion if the caller asked for a
+	// synchronous write and report the outcome.
+	if mutation.sync {
 		wg.Wait()
+		return mutation.err
 	}
 
 	return err
@@ -403,6 +538,28 @@ func (self *MemcacheFileDataStore) SetSubject(
 	if err != nil {
 		return err
 	}
+	self.setObject(config_obj, urn, message)
+
+	// Writing the file directly would race with the journal so
+	// commit it with the pending batch instead.
+	if self.batch != nil {
+		mutation := &Mutation{
+			op:             MUTATION_OP_SET_SUBJECT,
+			urn:            urn,
+			org_config_obj: config_obj,
+			data:           serialized_content,
+			cached:         true,
+		}
+		self.batch.add(mutation)
+
+		// Once Commit() returns our mutation was committed,
+		// either by us or by a commit already in progress.
+		err = self.batch.Commit()
+		if mutation.err != nil {
+			return mutation.err
+		}
+		return err
+	}
 
 	err = writeContentToFile(config_obj, urn, serialized_content)
 	if err != nil {
@@ -421,6 +578,8 @@ func (self *MemcacheFileDataStore) DeleteSubject(
 		return notInitializedError
 	}
 
+	self.invalidateObject(config_obj, urn)
+
 	// Remove immediately from the cache memcache as soon as the file
 	// is removed from disk.
 	completion := func() {
@@ -444,7 +603,8 @@ func (self *MemcacheFileDataStore) DeleteSubject(
 		// avoid racing with GetSubject().
 		completion:     completion,
 		urn:            urn,
-		org_config_obj: config_obj}:
+		org_config_obj: config_obj,
+		sync:           config_obj.Datastore.MemcacheWriteMutationBuffer < 0}:
 	}
 
 	if config_obj.Datastore.MemcacheWriteMutationBuffer < 0 {
@@ -472,6 +632,8 @@ func (self *MemcacheFileDataStore) DeleteSubjectWithCompletion(
 		return notInitializedError
 	}
 
+	self.invalidateObject(config_obj, urn)
+
 	select {
 	case <-self.ctx.Done():
 		if completion != nil {
@@ -505,6 +667,12 @@ func (self *MemcacheFileDataStore) ListChildren(
 
 	children, err := self.cache.ListChildren(config_obj, urn)
 	if err != nil || children == nil {
+		// Files waiting in the batch must be on disk before we list
+		// the directory.
+		if self.batch != nil {
+			_ = self.batch.Commit()
+		}
+
 		children, err = file_based_imp.ListChildren(config_obj, urn)
 		if err != nil {
 			return children, err
@@ -527,6 +695,9 @@ func (self *MemcacheFileDataStore) Close() {
 
 func (self *MemcacheFileDataStore) Clear() {
 	self.cache.Clear()
+	if self.objects != nil {
+		self.objects.Clear()
+	}
 }
 
 func (self *MemcacheFileDataStore) Debug(config_obj *config_proto.Config) {
@@ -548,6 +719,11 @@ func (self *MemcacheFileDataStore) GetBuffer(
 		return bulk_data, err
 	}
 
+	bulk_data, pres, err := self.getPending(config_obj, urn)
+	if pres {
+		return bulk_data, err
+	}
+
 	bulk_data, err = readContentFromFile(config_obj, urn)
 	if err != nil {
 		return nil, err
@@ -569,6 +745,9 @@ func (self *MemcacheFileDataStore) SetBuffer(
 		return err
 	}
 
+	// The raw data may not decode to the cached object.
+	self.invalidateObject(config_obj, urn)
+
 	if self.ctx == nil {
 		return notInitializedError
 	}
@@ -586,6 +765,7 @@ func (self *MemcacheFileDataStore) SetBuffer(
 		wg:             &wg,
 		data:           data,
 		completion:     completion,
+		sync:           config_obj.Datastore.MemcacheWriteMutationBuffer < 0,
 	}:
 	}
 
@@ -663,6 +843,7 @@ func NewMemcacheFileDataStore(config_obj *config_proto.Config) *MemcacheFileData
 				data_max_size, dir_max_item_size),
 			get_dir_metadata: get_file_dir_metadata,
 		},
+		objects: NewObjectCache(config_obj),
 	}
 
 	return result
@@ -0,0 +1,245 @@
+package datastore
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+var (
+	metricObjectCacheHit = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "datastore_object_cache_hit",
+			Help: "Reads served from the decoded object cache",
+		})
+
+	metricObjectCacheMiss = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "datastore_object_cache_miss",
+			Help: "Reads of cached paths which had to be decoded",
+		})
+
+	metricObjectCacheEvictions = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "datastore_object_cache_evictions",
+			Help: "Objects evicted to stay within the memory budget",
+		})
+
+	metricObjectCacheBytes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "datastore_object_cache_bytes",
+			Help: "Approximate size of the decoded object cache",
+		})
+
+	default_object_cache_tags = []string{
+		"ClientInfo", "Hunt", "FlowContext", "User", "UserACLS"}
+)
+
+const (
+	// Approximate memory used by each entry apart from the message.
+	object_cache_entry_overhead = 200
+)
+
+type objectCacheEntry struct {
+	key     string
+	message proto.Message
+	size    int64
+}
+
+// An LRU of decoded objects for hot datastore paths. The memcache
+// keeps the serialized data but each read still needs to unmarshal
+// it, which is expensive for large JSON objects read very often
+// (e.g. client records and hunts).
+type ObjectCache struct {
+	mu sync.Mutex
+
+	lru   *list.List
+	items map[string]*list.Element
+
+	size     int64
+	max_size int64
+
+	// Only paths with these tags are cached.
+	tags map[string]bool
+
+	// Incremented on every change so readers can tell if the object
+	// changed while they were decoding it.
+	generation uint64
+
+	// Called with the key when an object is invalidated.
+	hooks []func(key string)
+}
+
+func (self *ObjectCache) Cacheable(urn api.DSPathSpec) bool {
+	return self != nil && self.tags[urn.Tag()]
+}
+
+// Copy the cached object into message. Returns false if the object
+// is not cached.
+func (self *ObjectCache) Get(key string, message proto.Message) bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	element, pres := self.items[key]
+	if !pres {
+		metricObjectCacheMiss.Inc()
+		return false
+	}
+
+	entry := element.Value.(*objectCacheEntry)
+
+	// The same path may be read into a different message type.
+	if proto.MessageName(entry.message) != proto.MessageName(message) {
+		metricObjectCacheMiss.Inc()
+		return false
+	}
+
+	self.lru.MoveToFront(element)
+	metricObjectCacheHit.Inc()
+
+	proto.Reset(message)
+	proto.Merge(message, entry.message)
+	return true
+}
+
+func (self *ObjectCache) Generation() uint64 {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.generation
+}
+
+// Cache a copy of the message after it was written.
+func (self *ObjectCache) Set(key string, message proto.Message) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.generation++
+	self.set(key, message)
+}
+
+// Cache a copy of a message which was read, unless it was changed
+// since generation.
+func (self *ObjectCache) SetIfUnchanged(
+	key string, generation uint64, message proto.Message) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if generation != self.generation {
+		return
+	}
+	self.set(key, message)
+}
+
+func (self *ObjectCache) set(key string, message proto.Message) {
+	self.remove(key)
+
+	entry := &objectCacheEntry{
+		key:     key,
+		message: proto.Clone(message),
+		size: int64(proto.Size(message)+len(key)) +
+			object_cache_entry_overhead,
+	}
+
+	// Objects larger than the whole budget are not cached.
+	if entry.size > self.max_size {
+		return
+	}
+
+	self.items[key] = self.lru.PushFront(entry)
+	self.size += entry.size
+
+	for self.size > self.max_size {
+		oldest := self.lru.Back()
+		if oldest == nil {
+			break
+		}
+		self.remove(oldest.Value.(*objectCacheEntry).key)
+		metricObjectCacheEvictions.Inc()
+	}
+
+	metricObjectCacheBytes.Set(float64(self.size))
+}
+
+func (self *ObjectCache) remove(key string) bool {
+	element, pres := self.items[key]
+	if !pres {
+		return false
+	}
+
+	self.lru.Remove(element)
+	delete(self.items, key)
+	self.size -= element.Value.(*objectCacheEntry).size
+	metricObjectCacheBytes.Set(float64(self.size))
+	return true
+}
+
+// Drop the object from the cache, e.g. when it is deleted or
+// changed outside the datastore.
+func (self *ObjectCache) Invalidate(key string) {
+	self.mu.Lock()
+	self.generation++
+	removed := self.remove(key)
+	hooks := self.hooks
+	self.mu.Unlock()
+
+	if removed {
+		for _, hook := range hooks {
+			hook(key)
+		}
+	}
+}
+
+// Register a function to be called when an object is invalidated.
+func (self *ObjectCache) AddInvalidationHook(hook func(key string)) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.hooks = append(self.hooks, hook)
+}
+
+func (self *ObjectCache) Clear() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.generation++
+	self.lru.Init()
+	self.items = make(map[string]*list.Element)
+	self.size = 0
+	metricObjectCacheBytes.Set(0)
+}
+
+// Returns nil if the cache is disabled.
+func NewObjectCache(config_obj *config_proto.Config) *ObjectCache {
+	size_mb := config_obj.GetDatastore().GetObjectCacheSizeMb()
+	if size_mb < 0 {
+		return nil
+	}
+
+	if size_mb == 0 {
+		size_mb = 32
+	}
+
+	tags := config_obj.GetDatastore().GetObjectCacheTags()
+	if len(tags) == 0 {
+		tags = default_object_cache_tags
+	}
+
+	result := &ObjectCache{
+		lru:      list.New(),
+		items:    make(map[string]*list.Element),
+		max_size: size_mb * 1024 * 1024,
+		tags:     make(map[string]bool),
+	}
+
+	for _, tag := range tags {
+		result.tags[tag] = true
+	}
+
+	return result
+}
@@ -0,0 +1,112 @@
+package datastore_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+func TestObjectCacheBudget(t *testing.T) {
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.ObjectCacheSizeMb = 1
+
+	cache := datastore.NewObjectCache(config_obj)
+
+	invalidated := []string{}
+	cache.AddInvalidationHook(func(key string) {
+		invalidated = append(invalidated, key)
+	})
+
+	// Each object is about 300kb so only 3 fit in the budget.
+	padding := strings.Repeat("x", 300*1024)
+	for i := 0; i < 5; i++ {
+		cache.Set(fmt.Sprintf("key%d", i), &api_proto.ClientMetadata{
+			ClientId: fmt.Sprintf("C.%d", i),
+			Items:    []*api_proto.ClientMetadataItem{{Value: padding}},
+		})
+	}
+
+	present := []string{}
+	for i := 0; i < 5; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if cache.Get(key, &api_proto.ClientMetadata{}) {
+			present = append(present, key)
+		}
+	}
+	assert.Equal(t, []string{"key2", "key3", "key4"}, present)
+
+	// Cached objects are copied out.
+	record := &api_proto.ClientMetadata{}
+	assert.True(t, cache.Get("key4", record))
+	assert.Equal(t, "C.4", record.ClientId)
+	record.ClientId = "Changed"
+
+	assert.True(t, cache.Get("key4", record))
+	assert.Equal(t, "C.4", record.ClientId)
+
+	// A different message type is a miss.
+	assert.False(t, cache.Get("key4", &actions_proto.ClientInfo{}))
+
+	// Stale reads are not cached.
+	generation := cache.Generation()
+	cache.Invalidate("key4")
+	cache.SetIfUnchanged("key4", generation, record)
+	assert.False(t, cache.Get("key4", record))
+	assert.Equal(t, []string{"key4"}, invalidated)
+}
+
+func TestObjectCacheDatastore(t *testing.T) {
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.Location = t.TempDir()
+
+	db := datastore.NewMemcacheFileDataStore(config_obj)
+	db.Clear()
+
+	urn := paths.NewClientPathManager("C.1234").Path()
+	err := db.SetSubject(config_obj, urn, &actions_proto.ClientInfo{
+		ClientId: "C.1234",
+		Hostname: "host1",
+	})
+	assert.NoError(t, err)
+
+	snapshot := vtesting.GetMetrics(t, "datastore_object_cache_")
+
+	for i := 0; i < 3; i++ {
+		record := &actions_proto.ClientInfo{}
+		err = db.GetSubject(config_obj, urn, record)
+		assert.NoError(t, err)
+		assert.Equal(t, "host1", record.Hostname)
+	}
+
+	metrics := vtesting.GetMetricsDifference(
+		t, "datastore_object_cache_(hit|miss)", snapshot)
+	hits, _ := metrics.GetInt64("datastore_object_cache_hit")
+	misses, _ := metrics.GetInt64("datastore_object_cache_miss")
+	assert.Equal(t, int64(3), hits)
+	assert.Equal(t, int64(0), misses)
+
+	// Writes update the cached object.
+	err = db.SetSubject(config_obj, urn, &actions_proto.ClientInfo{
+		ClientId: "C.1234",
+		Hostname: "host2",
+	})
+	assert.NoError(t, err)
+
+	record := &actions_proto.ClientInfo{}
+	err = db.GetSubject(config_obj, urn, record)
+	assert.NoError(t, err)
+	assert.Equal(t, "host2", record.Hostname)
+
+	// Untagged paths are never cached.
+	flow_urn := paths.NewFlowPathManager("C.1234", "F.1").Stats()
+	assert.False(t, db.ObjectCache().Cacheable(flow_urn))
+	assert.True(t, db.ObjectCache().Cacheable(urn))
+}
@@ -27,7 +27,6 @@ import (
 	"google.golang.org/protobuf/proto"
 	"www.velocidex.com/golang/velociraptor/actions"
 	"www.velocidex.com/golang/velociraptor/json"
-	"www.velocidex.com/golang/velociraptor/utils"
 
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
@@ -70,7 +69,7 @@ type ClientExecutor struct {
 
 	config_obj *config_proto.Config
 
-	concurrency *utils.Concurrency
+	concurrency *PriorityScheduler
 
 	flow_manager  *responder.FlowManager
 	event_manager *actions.EventTable
@@ -148,6 +147,17 @@ func (self *ClientExecutor) processRequestPlugin(
 		return
 	}
 
+	// The server is missing some of the sections we omitted from
+	// our last client info update.
+	if req.ClientInfoRequest != nil {
+		message, err := actions.ClientInfoMessage(
+			actions.ClientInfoDelta(self.GetClientInfo(), nil))
+		if err == nil {
+			self.Outbound <- message
+		}
+		return
+	}
+
 	if req.UpdateEventTable != nil {
 		self.event_manager.UpdateEventTable(
 			self.ctx, self.wg, config_obj,
@@ -182,13 +192,24 @@ func NewClientExecutor(
 		level = 2
 	}
 
+	// How long collections may wait in the queue.
+	timeout := time.Hour
+	if config_obj.Client.ConcurrencyTimeout > 0 {
+		timeout = time.Duration(config_obj.Client.ConcurrencyTimeout) * time.Second
+	}
+
+	aging := 600 * time.Second
+	if config_obj.Client.ConcurrencyAging > 0 {
+		aging = time.Duration(config_obj.Client.ConcurrencyAging) * time.Second
+	}
+
 	wg := &sync.WaitGroup{}
 	self := &ClientExecutor{
 		ctx:          ctx,
 		client_id:    client_id,
 		Inbound:      make(chan *crypto_proto.VeloMessage, 10),
 		Outbound:     make(chan *crypto_proto.VeloMessage, 10),
-		concurrency:  utils.NewConcurrencyControl(level, time.Hour),
+		concurrency:  NewPriorityScheduler(level, timeout, aging),
 		wg:           wg,
 		config_obj:   config_obj,
 		flow_manager: responder.NewFlowManager(ctx, config_obj),
@@ -236,6 +257,18 @@ func NewClientExecutor(
 					continue
 				}
 
+				// Input for interactive shells is delivered in
+				// order without starting a new goroutine.
+				if req.ShellInput != nil {
+					if req.AuthState == crypto_proto.VeloMessage_AUTHENTICATED {
+						err := responder.SendShellInput(req.SessionId, req.ShellInput)
+						if err != nil {
+							logger.Info("Shell input for %v: %v", req.SessionId, err)
+						}
+					}
+					continue
+				}
+
 				// Ignore unauthenticated messages - the
 				// server should never send us those.
 				if req.AuthState == crypto_proto.VeloMessage_AUTHENTICATED {
@@ -8,6 +8,7 @@ import (
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/executor/sandbox"
 	"www.velocidex.com/golang/velociraptor/responder"
 )
 
@@ -21,9 +22,12 @@ func (self *ClientExecutor) ProcessFlowRequest(
 	defer flow_context.Close()
 
 	// Control concurrency for the entire collection at once. If a
-	// collection has many queries, they all run concurrently.
+	// collection has many queries, they all run concurrently. Higher
+	// priority collections run first and may preempt lower priority
+	// ones.
 	if !req.Urgent {
-		cancel, err := self.concurrency.StartConcurrencyControl(ctx)
+		cancel, err := self.concurrency.StartConcurrencyControl(
+			ctx, req.FlowRequest.Priority, flow_context)
 		if err != nil {
 			responder.MakeErrorResponse(
 				self.Outbound, req.SessionId, err.Error())
@@ -73,6 +77,13 @@ func (self *ClientExecutor) ProcessFlowRequest(
 			sub_ctx, responder_obj := flow_context.NewResponder(arg)
 			defer responder_obj.Close()
 
+			// Queries which do not need privileges run in the
+			// sandbox if it is enabled.
+			if sandbox.ShouldSandbox(config_obj, arg) {
+				sandbox.StartQuery(config_obj, sub_ctx, responder_obj, arg)
+				return
+			}
+
 			actions.VQLClientAction{}.StartQuery(
 				config_obj, sub_ctx, responder_obj, arg)
 		}(arg)
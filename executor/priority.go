@@ -0,0 +1,203 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// A collection which can be paused to make room for a higher
+// priority collection.
+type Preemptible interface {
+	Pause()
+	Resume()
+}
+
+// Collections waiting in the queue are told their position so they
+// can report it to the server.
+type queueReporter interface {
+	SetQueuePosition(position uint64)
+}
+
+type prioritySlot struct {
+	priority int64
+
+	// Slots with the same priority are granted in arrival order.
+	seq uint64
+
+	preemptible Preemptible
+
+	// Closed when the slot is first granted.
+	granted chan bool
+
+	// Set when the slot was taken away from a running collection.
+	preempted bool
+
+	// When the slot started waiting.
+	queued time.Time
+}
+
+func (self *prioritySlot) setQueuePosition(position uint64) {
+	reporter, ok := self.preemptible.(queueReporter)
+	if ok {
+		reporter.SetQueuePosition(position)
+	}
+}
+
+// Limits the number of collections running at the same time on the
+// client, like utils.Concurrency. Waiting collections are started in
+// priority order, oldest first. Collections are promoted by one
+// priority level for each aging period they wait so low priority
+// collections eventually run.
+//
+// When a collection is waiting and all the slots are taken by lower
+// priority collections, the lowest priority running collection is
+// preempted: it is paused at the next row boundary and its slot is
+// given to the waiting collection. The preempted collection is
+// resumed when a slot becomes available again.
+type PriorityScheduler struct {
+	mu      sync.Mutex
+	size    int
+	timeout time.Duration
+	aging   time.Duration
+	seq     uint64
+
+	running []*prioritySlot
+	waiting []*prioritySlot
+}
+
+func (self *PriorityScheduler) StartConcurrencyControl(
+	ctx context.Context, priority int64,
+	preemptible Preemptible) (func(), error) {
+
+	self.mu.Lock()
+	self.seq++
+	slot := &prioritySlot{
+		priority:    priority,
+		seq:         self.seq,
+		preemptible: preemptible,
+		granted:     make(chan bool),
+		queued:      utils.GetTime().Now(),
+	}
+	self.waiting = append(self.waiting, slot)
+	self.preempt(priority)
+	self.schedule()
+	self.mu.Unlock()
+
+	release := func() {
+		self.mu.Lock()
+		defer self.mu.Unlock()
+
+		self.running = removeSlot(self.running, slot)
+		self.waiting = removeSlot(self.waiting, slot)
+		self.schedule()
+	}
+
+	select {
+	case <-slot.granted:
+		return release, nil
+
+	case <-ctx.Done():
+		release()
+		return nil, errors.New("Concurrency: Timed out due to cancellation")
+
+	case <-time.After(self.timeout):
+		release()
+		return nil, errors.New("Timed out in concurrency control")
+	}
+}
+
+// Take a slot away from the lowest priority running collection if
+// it is lower than priority. Must be called with the lock held.
+func (self *PriorityScheduler) preempt(priority int64) {
+	if len(self.running) < self.size {
+		return
+	}
+
+	var lowest *prioritySlot
+	for _, slot := range self.running {
+		if slot.preemptible == nil || slot.priority >= priority {
+			continue
+		}
+
+		if lowest == nil || slot.priority < lowest.priority ||
+			(slot.priority == lowest.priority && slot.seq > lowest.seq) {
+			lowest = slot
+		}
+	}
+
+	if lowest == nil {
+		return
+	}
+
+	lowest.preemptible.Pause()
+	lowest.preempted = true
+	lowest.queued = utils.GetTime().Now()
+	self.running = removeSlot(self.running, lowest)
+	self.waiting = append(self.waiting, lowest)
+}
+
+// The priority of a waiting slot including the promotion it gained
+// from waiting.
+func (self *PriorityScheduler) effectivePriority(
+	slot *prioritySlot, now time.Time) int64 {
+	if self.aging <= 0 {
+		return slot.priority
+	}
+	return slot.priority + int64(now.Sub(slot.queued)/self.aging)
+}
+
+// Grant free slots to the highest priority waiting collections and
+// update the queue positions of the rest. Must be called with the
+// lock held.
+func (self *PriorityScheduler) schedule() {
+	now := utils.GetTime().Now()
+	sort.SliceStable(self.waiting, func(i, j int) bool {
+		p_i := self.effectivePriority(self.waiting[i], now)
+		p_j := self.effectivePriority(self.waiting[j], now)
+		if p_i != p_j {
+			return p_i > p_j
+		}
+		return self.waiting[i].seq < self.waiting[j].seq
+	})
+
+	for len(self.running) < self.size && len(self.waiting) > 0 {
+		next := self.waiting[0]
+		self.waiting = self.waiting[1:]
+		self.running = append(self.running, next)
+		next.setQueuePosition(0)
+
+		if next.preempted {
+			next.preempted = false
+			next.preemptible.Resume()
+		} else {
+			close(next.granted)
+		}
+	}
+
+	for idx, slot := range self.waiting {
+		slot.setQueuePosition(uint64(idx + 1))
+	}
+}
+
+func removeSlot(slots []*prioritySlot, slot *prioritySlot) []*prioritySlot {
+	for idx, s := range slots {
+		if s == slot {
+			return append(slots[:idx:idx], slots[idx+1:]...)
+		}
+	}
+	return slots
+}
+
+func NewPriorityScheduler(
+	size int, timeout, aging time.Duration) *PriorityScheduler {
+	return &PriorityScheduler{
+		size:    size,
+		timeout: timeout,
+		aging:   aging,
+	}
+}
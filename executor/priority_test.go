@@ -0,0 +1,184 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+type testPreemptible struct {
+	mu             sync.Mutex
+	paused         bool
+	queue_position uint64
+}
+
+func (self *testPreemptible) SetQueuePosition(position uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.queue_position = position
+}
+
+func (self *testPreemptible) QueuePosition() uint64 {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.queue_position
+}
+
+func (self *testPreemptible) Pause() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.paused = true
+}
+
+func (self *testPreemptible) Resume() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.paused = false
+}
+
+func (self *testPreemptible) IsPaused() bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.paused
+}
+
+// Waiting collections are started in priority order.
+func TestPrioritySchedulerOrder(t *testing.T) {
+	ctx := context.Background()
+	scheduler := NewPriorityScheduler(1, time.Minute, 0)
+
+	// A collection which can not be preempted holds the only slot.
+	release, err := scheduler.StartConcurrencyControl(ctx, 0, nil)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []int64
+
+	wg := &sync.WaitGroup{}
+	for _, priority := range []int64{-1, 0, 5} {
+		wg.Add(1)
+		go func(priority int64) {
+			defer wg.Done()
+
+			cancel, err := scheduler.StartConcurrencyControl(
+				ctx, priority, nil)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			order = append(order, priority)
+			mu.Unlock()
+
+			cancel()
+		}(priority)
+	}
+
+	// Wait for all of them to queue up.
+	vtesting.WaitUntil(time.Second, t, func() bool {
+		scheduler.mu.Lock()
+		defer scheduler.mu.Unlock()
+		return len(scheduler.waiting) == 3
+	})
+
+	release()
+	wg.Wait()
+
+	assert.Equal(t, []int64{5, 0, -1}, order)
+}
+
+// A higher priority collection preempts a running lower priority
+// collection.
+func TestPrioritySchedulerPreemption(t *testing.T) {
+	ctx := context.Background()
+	scheduler := NewPriorityScheduler(1, time.Minute, 0)
+
+	low := &testPreemptible{}
+	release_low, err := scheduler.StartConcurrencyControl(ctx, 0, low)
+	require.NoError(t, err)
+	assert.False(t, low.IsPaused())
+
+	// Collections of the same priority do not preempt.
+	same_ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	_, err = scheduler.StartConcurrencyControl(
+		same_ctx, 0, &testPreemptible{})
+	assert.Error(t, err)
+	assert.False(t, low.IsPaused())
+
+	// A high priority collection is started immediately.
+	release_high, err := scheduler.StartConcurrencyControl(
+		ctx, 10, &testPreemptible{})
+	require.NoError(t, err)
+	assert.True(t, low.IsPaused())
+
+	// The low priority collection resumes when the high priority
+	// collection is done.
+	release_high()
+	assert.False(t, low.IsPaused())
+
+	release_low()
+	assert.Equal(t, 0, len(scheduler.running))
+	assert.Equal(t, 0, len(scheduler.waiting))
+}
+
+// Low priority collections are promoted while they wait.
+func TestPrioritySchedulerAging(t *testing.T) {
+	clock := utils.NewMockClock(time.Unix(1000, 0))
+	closer := utils.MockTime(clock)
+	defer closer()
+
+	ctx := context.Background()
+	scheduler := NewPriorityScheduler(1, time.Minute, time.Minute)
+
+	release, err := scheduler.StartConcurrencyControl(ctx, 0, nil)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []int64
+
+	wg := &sync.WaitGroup{}
+	start := func(priority int64, reporter *testPreemptible) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cancel, err := scheduler.StartConcurrencyControl(
+				ctx, priority, reporter)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			order = append(order, priority)
+			mu.Unlock()
+
+			cancel()
+		}()
+
+		vtesting.WaitUntil(time.Second, t, func() bool {
+			return reporter.QueuePosition() > 0
+		})
+	}
+
+	low := &testPreemptible{}
+	start(-1, low)
+	assert.Equal(t, uint64(1), low.QueuePosition())
+
+	// After waiting for two aging periods the low priority collection
+	// is ahead of a new normal priority one.
+	clock.Set(time.Unix(1000, 0).Add(150 * time.Second))
+
+	normal := &testPreemptible{}
+	start(0, normal)
+	assert.Equal(t, uint64(1), low.QueuePosition())
+	assert.Equal(t, uint64(2), normal.QueuePosition())
+
+	release()
+	wg.Wait()
+
+	assert.Equal(t, []int64{-1, 0}, order)
+}
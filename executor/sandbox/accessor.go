@@ -0,0 +1,261 @@
+package sandbox
+
+import (
+	"errors"
+	"io"
+	"net/rpc"
+	"os"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// Replaces an accessor in the child. All operations are forwarded to
+// the client process which still has the privileges to read the
+// file. Paths are parsed locally by the original accessor so they
+// behave exactly the same.
+type brokeredAccessor struct {
+	name     string
+	client   *rpc.Client
+	delegate accessors.FileSystemAccessor
+}
+
+func (self *brokeredAccessor) New(scope vfilter.Scope) (
+	accessors.FileSystemAccessor, error) {
+	return self, nil
+}
+
+func (self *brokeredAccessor) ParsePath(path string) (*accessors.OSPath, error) {
+	return self.delegate.ParsePath(path)
+}
+
+func (self *brokeredAccessor) ReadDir(path string) ([]accessors.FileInfo, error) {
+	os_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return self.ReadDirWithOSPath(os_path)
+}
+
+func (self *brokeredAccessor) ReadDirWithOSPath(
+	path *accessors.OSPath) ([]accessors.FileInfo, error) {
+	reply := []FileInfo{}
+	err := self.client.Call("Broker.ReadDir", &PathArgs{
+		Accessor: self.name,
+		Path:     path.String(),
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]accessors.FileInfo, 0, len(reply))
+	for _, info := range reply {
+		result = append(result, self.decodeFileInfo(
+			path.Append(info.Name), info))
+	}
+	return result, nil
+}
+
+func (self *brokeredAccessor) Lstat(path string) (accessors.FileInfo, error) {
+	os_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return self.LstatWithOSPath(os_path)
+}
+
+func (self *brokeredAccessor) LstatWithOSPath(
+	path *accessors.OSPath) (accessors.FileInfo, error) {
+	reply := FileInfo{}
+	err := self.client.Call("Broker.Lstat", &PathArgs{
+		Accessor: self.name,
+		Path:     path.String(),
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.decodeFileInfo(path, reply), nil
+}
+
+func (self *brokeredAccessor) Open(path string) (accessors.ReadSeekCloser, error) {
+	os_path, err := self.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return self.OpenWithOSPath(os_path)
+}
+
+func (self *brokeredAccessor) OpenWithOSPath(
+	path *accessors.OSPath) (accessors.ReadSeekCloser, error) {
+	reply := OpenReply{}
+	err := self.client.Call("Broker.Open", &PathArgs{
+		Accessor: self.name,
+		Path:     path.String(),
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return &brokeredFile{
+		client: self.client,
+		handle: reply.Handle,
+		size:   reply.Size,
+	}, nil
+}
+
+func (self *brokeredAccessor) decodeFileInfo(
+	path *accessors.OSPath, info FileInfo) *brokeredFileInfo {
+	result := &brokeredFileInfo{
+		info:     info,
+		path:     path,
+		accessor: self,
+	}
+
+	if len(info.Data) > 0 {
+		data, err := utils.ParseJsonToObject(info.Data)
+		if err == nil {
+			result.data = data
+		}
+	}
+
+	return result
+}
+
+type brokeredFileInfo struct {
+	info     FileInfo
+	path     *accessors.OSPath
+	data     *ordereddict.Dict
+	accessor *brokeredAccessor
+}
+
+func (self *brokeredFileInfo) Name() string {
+	return self.info.Name
+}
+
+func (self *brokeredFileInfo) ModTime() time.Time {
+	return self.info.Mtime
+}
+
+func (self *brokeredFileInfo) FullPath() string {
+	return self.info.FullPath
+}
+
+func (self *brokeredFileInfo) OSPath() *accessors.OSPath {
+	return self.path
+}
+
+func (self *brokeredFileInfo) Btime() time.Time {
+	return self.info.Btime
+}
+
+func (self *brokeredFileInfo) Mtime() time.Time {
+	return self.info.Mtime
+}
+
+func (self *brokeredFileInfo) Ctime() time.Time {
+	return self.info.Ctime
+}
+
+func (self *brokeredFileInfo) Atime() time.Time {
+	return self.info.Atime
+}
+
+func (self *brokeredFileInfo) Data() *ordereddict.Dict {
+	if self.data == nil {
+		return ordereddict.NewDict()
+	}
+	return self.data
+}
+
+func (self *brokeredFileInfo) Size() int64 {
+	return self.info.Size
+}
+
+func (self *brokeredFileInfo) IsDir() bool {
+	return self.info.IsDir
+}
+
+func (self *brokeredFileInfo) IsLink() bool {
+	return self.info.IsLink
+}
+
+func (self *brokeredFileInfo) GetLink() (*accessors.OSPath, error) {
+	if !self.info.IsLink || self.info.Link == "" {
+		return nil, errors.New("Not a link")
+	}
+	return self.accessor.ParsePath(self.info.Link)
+}
+
+func (self *brokeredFileInfo) Mode() os.FileMode {
+	return os.FileMode(self.info.Mode)
+}
+
+// A file opened in the client process. Reads are forwarded in
+// chunks of at most MAX_READ_SIZE.
+type brokeredFile struct {
+	client *rpc.Client
+	handle int64
+	size   int64
+	offset int64
+}
+
+func (self *brokeredFile) Read(buf []byte) (int, error) {
+	length := int64(len(buf))
+	if length == 0 {
+		return 0, nil
+	}
+
+	if length > MAX_READ_SIZE {
+		length = MAX_READ_SIZE
+	}
+
+	reply := []byte{}
+	err := self.client.Call("Broker.ReadAt", &ReadArgs{
+		Handle: self.handle,
+		Offset: self.offset,
+		Length: length,
+	}, &reply)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(reply) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(buf, reply)
+	self.offset += int64(n)
+	return n, nil
+}
+
+func (self *brokeredFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		self.offset = offset
+	case io.SeekCurrent:
+		self.offset += offset
+	case io.SeekEnd:
+		if self.size < 0 {
+			return 0, errors.New("Seek from end not supported")
+		}
+		self.offset = self.size + offset
+	default:
+		return 0, errors.New("Invalid whence")
+	}
+
+	if self.offset < 0 {
+		self.offset = 0
+		return 0, errors.New("Seek before start of file")
+	}
+
+	return self.offset, nil
+}
+
+func (self *brokeredFile) Close() error {
+	return self.client.Call("Broker.CloseFile",
+		&HandleArgs{Handle: self.handle}, &Empty{})
+}
@@ -0,0 +1,315 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"google.golang.org/protobuf/proto"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/responder"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	invalidHandleError = errors.New("Invalid handle")
+)
+
+// The Broker runs in the client process and serves the child's
+// requests. It is exported over net/rpc so all exported methods must
+// follow the net/rpc conventions.
+type Broker struct {
+	ctx       context.Context
+	responder responder.Responder
+	request   *Request
+
+	mu sync.Mutex
+
+	// Only these accessors may be used by the child.
+	allowed_accessors map[string]bool
+	accessors         map[string]accessors.FileSystemAccessor
+	scope             vfilter.Scope
+
+	handles     map[int64]accessors.ReadSeekCloser
+	next_handle int64
+
+	// Set when the child completed the query.
+	returned bool
+}
+
+func (self *Broker) GetRequest(args *Empty, reply *Request) error {
+	*reply = *self.request
+	return nil
+}
+
+// Only result and upload messages may be forwarded - the child can
+// not send arbitrary messages to the server.
+func (self *Broker) AddResponse(args *MessageArgs, reply *Empty) error {
+	message := &crypto_proto.VeloMessage{}
+	err := proto.Unmarshal(args.Message, message)
+	if err != nil {
+		return err
+	}
+
+	if message.VQLResponse == nil && message.FileBuffer == nil {
+		return fmt.Errorf("Message type not allowed from sandbox: %v",
+			json.MustMarshalString(message))
+	}
+
+	self.responder.AddResponse(&crypto_proto.VeloMessage{
+		RequestId:   message.RequestId,
+		VQLResponse: message.VQLResponse,
+		FileBuffer:  message.FileBuffer,
+	})
+	return nil
+}
+
+func (self *Broker) Log(args *LogArgs, reply *Empty) error {
+	self.responder.Log(self.ctx, args.Level, args.Message)
+	return nil
+}
+
+func (self *Broker) RaiseError(args *LogArgs, reply *Empty) error {
+	self.mu.Lock()
+	self.returned = true
+	self.mu.Unlock()
+
+	self.responder.RaiseError(self.ctx, args.Message)
+	return nil
+}
+
+func (self *Broker) Return(args *Empty, reply *Empty) error {
+	self.mu.Lock()
+	self.returned = true
+	self.mu.Unlock()
+
+	self.responder.Return(self.ctx)
+	return nil
+}
+
+func (self *Broker) NextUploadId(args *Empty, reply *int64) error {
+	*reply = self.responder.NextUploadId()
+	return nil
+}
+
+func (self *Broker) getAccessor(name string) (
+	accessors.FileSystemAccessor, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if !self.allowed_accessors[name] {
+		return nil, fmt.Errorf("Accessor %v is not brokered", name)
+	}
+
+	accessor, pres := self.accessors[name]
+	if !pres {
+		var err error
+		accessor, err = accessors.GetAccessor(name, self.scope)
+		if err != nil {
+			return nil, err
+		}
+		self.accessors[name] = accessor
+	}
+
+	return accessor, nil
+}
+
+func (self *Broker) ReadDir(args *PathArgs, reply *[]FileInfo) error {
+	accessor, err := self.getAccessor(args.Accessor)
+	if err != nil {
+		return err
+	}
+
+	path, err := accessor.ParsePath(args.Path)
+	if err != nil {
+		return err
+	}
+
+	children, err := accessor.ReadDirWithOSPath(path)
+	if err != nil {
+		return err
+	}
+
+	result := make([]FileInfo, 0, len(children))
+	for _, child := range children {
+		result = append(result, encodeFileInfo(child))
+	}
+	*reply = result
+	return nil
+}
+
+func (self *Broker) Lstat(args *PathArgs, reply *FileInfo) error {
+	accessor, err := self.getAccessor(args.Accessor)
+	if err != nil {
+		return err
+	}
+
+	path, err := accessor.ParsePath(args.Path)
+	if err != nil {
+		return err
+	}
+
+	stat, err := accessor.LstatWithOSPath(path)
+	if err != nil {
+		return err
+	}
+
+	*reply = encodeFileInfo(stat)
+	return nil
+}
+
+func (self *Broker) Open(args *PathArgs, reply *OpenReply) error {
+	accessor, err := self.getAccessor(args.Accessor)
+	if err != nil {
+		return err
+	}
+
+	path, err := accessor.ParsePath(args.Path)
+	if err != nil {
+		return err
+	}
+
+	fd, err := accessor.OpenWithOSPath(path)
+	if err != nil {
+		return err
+	}
+
+	// Find the size of the file so the child can seek from the end.
+	size, err := fd.Seek(0, io.SeekEnd)
+	if err != nil {
+		size = -1
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.next_handle++
+	self.handles[self.next_handle] = fd
+
+	reply.Handle = self.next_handle
+	reply.Size = size
+	return nil
+}
+
+func (self *Broker) ReadAt(args *ReadArgs, reply *[]byte) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	fd, pres := self.handles[args.Handle]
+	if !pres {
+		return invalidHandleError
+	}
+
+	length := args.Length
+	if length > MAX_READ_SIZE {
+		length = MAX_READ_SIZE
+	}
+
+	_, err := fd.Seek(args.Offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, length)
+	n, err := io.ReadFull(fd, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	*reply = buf[:n]
+	return nil
+}
+
+func (self *Broker) CloseFile(args *HandleArgs, reply *Empty) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	fd, pres := self.handles[args.Handle]
+	if !pres {
+		return invalidHandleError
+	}
+	delete(self.handles, args.Handle)
+
+	return fd.Close()
+}
+
+func (self *Broker) isReturned() bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.returned
+}
+
+// Release any files the child left open.
+func (self *Broker) close() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for _, fd := range self.handles {
+		fd.Close()
+	}
+	self.handles = make(map[int64]accessors.ReadSeekCloser)
+	self.scope.Close()
+}
+
+func encodeFileInfo(info accessors.FileInfo) FileInfo {
+	result := FileInfo{
+		Name:     info.Name(),
+		FullPath: info.FullPath(),
+		Size:     info.Size(),
+		Mode:     uint32(info.Mode()),
+		IsDir:    info.IsDir(),
+		IsLink:   info.IsLink(),
+		Mtime:    info.Mtime(),
+		Atime:    info.Atime(),
+		Ctime:    info.Ctime(),
+		Btime:    info.Btime(),
+	}
+
+	if result.IsLink {
+		link, err := info.GetLink()
+		if err == nil {
+			result.Link = link.String()
+		}
+	}
+
+	data := info.Data()
+	if !utils.IsNil(data) {
+		result.Data, _ = json.Marshal(data)
+	}
+
+	return result
+}
+
+func NewBroker(
+	ctx context.Context,
+	responder responder.Responder,
+	request *Request) *Broker {
+	allowed_accessors := make(map[string]bool)
+	for _, name := range request.Accessors {
+		allowed_accessors[name] = true
+	}
+
+	// Brokered accessors only provide read access so the child does
+	// not need any other permissions.
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+
+	return &Broker{
+		ctx:               ctx,
+		responder:         responder,
+		request:           request,
+		allowed_accessors: allowed_accessors,
+		accessors:         make(map[string]accessors.FileSystemAccessor),
+		scope:             scope,
+		handles:           make(map[int64]accessors.ReadSeekCloser),
+	}
+}
@@ -0,0 +1,174 @@
+package sandbox
+
+import (
+	"context"
+	"io"
+	"net/rpc"
+
+	"github.com/Velocidex/ordereddict"
+	"google.golang.org/protobuf/proto"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/actions"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/responder"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+// The child side of the sandbox. Runs in the de-privileged process
+// started with the query_sandbox command.
+type Child struct {
+	client  *rpc.Client
+	request *Request
+	config  *config_proto.Config
+}
+
+// The config the client sent us.
+func (self *Child) Config() *config_proto.Config {
+	return self.config
+}
+
+// Replace the brokered accessors with ones which forward to the
+// client and run the query.
+func (self *Child) Run(ctx context.Context, config_obj *config_proto.Config) error {
+	// Only used to get the original accessors for parsing paths.
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+	defer scope.Close()
+
+	for _, name := range self.request.Accessors {
+		delegate, err := accessors.GetAccessor(name, scope)
+		if err != nil {
+			continue
+		}
+
+		accessors.Register(name, &brokeredAccessor{
+			name:     name,
+			client:   self.client,
+			delegate: delegate,
+		}, "Brokered by the client process")
+	}
+
+	arg := &actions_proto.VQLCollectorArgs{}
+	err := proto.Unmarshal(self.request.Args, arg)
+	if err != nil {
+		return err
+	}
+
+	responder := &remoteResponder{
+		client: self.client,
+		flow_context: responder.NewDetachedFlowContext(
+			self.request.FlowId, &crypto_proto.FlowRequest{
+				DryRun: self.request.DryRun,
+			}),
+	}
+
+	actions.VQLClientAction{}.StartQuery(config_obj, ctx, responder, arg)
+	return nil
+}
+
+func (self *Child) Close() error {
+	return self.client.Close()
+}
+
+// Connect to the client over the inherited pipes and fetch the
+// request.
+func NewChild() (*Child, error) {
+	// Our stderr goes to the collection log so only report errors.
+	logging.SuppressLogging = true
+
+	err := restrictProcess()
+	if err != nil {
+		return nil, err
+	}
+
+	in, out, err := childPipes()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &pipeConn{
+		Reader:  in,
+		Writer:  out,
+		closers: []io.Closer{in, out},
+	}
+
+	client := rpc.NewClient(conn)
+
+	request := &Request{}
+	err = client.Call("Broker.GetRequest", &Empty{}, request)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	config_obj := &config_proto.Config{}
+	err = proto.Unmarshal(request.Config, config_obj)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &Child{
+		client:  client,
+		request: request,
+		config:  config_obj,
+	}, nil
+}
+
+// Forwards all responses to the client's responder.
+type remoteResponder struct {
+	client       *rpc.Client
+	flow_context *responder.FlowContext
+}
+
+func (self *remoteResponder) AddResponse(message *crypto_proto.VeloMessage) {
+	serialized, err := proto.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	_ = self.client.Call("Broker.AddResponse",
+		&MessageArgs{Message: serialized}, &Empty{})
+}
+
+func (self *remoteResponder) RaiseError(ctx context.Context, message string) {
+	_ = self.client.Call("Broker.RaiseError",
+		&LogArgs{Message: message}, &Empty{})
+}
+
+func (self *remoteResponder) Return(ctx context.Context) {
+	_ = self.client.Call("Broker.Return", &Empty{}, &Empty{})
+}
+
+func (self *remoteResponder) Log(ctx context.Context, level string, msg string) {
+	_ = self.client.Call("Broker.Log",
+		&LogArgs{Level: level, Message: msg}, &Empty{})
+}
+
+func (self *remoteResponder) NextUploadId() int64 {
+	var result int64
+	_ = self.client.Call("Broker.NextUploadId", &Empty{}, &result)
+	return result
+}
+
+func (self *remoteResponder) FlowContext() *responder.FlowContext {
+	return self.flow_context
+}
+
+func (self *remoteResponder) Close() {}
+
+// The services the child needs to run client queries. The child
+// never talks to the server so there is no communicator.
+func ChildServices() *config_proto.ServerServicesConfig {
+	return &config_proto.ServerServicesConfig{
+		JournalService:      true,
+		RepositoryManager:   true,
+		InventoryService:    true,
+		NotificationService: true,
+		Launcher:            true,
+	}
+}
@@ -0,0 +1,197 @@
+package sandbox
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/responder"
+)
+
+// Joins the two pipe ends into a single connection for net/rpc.
+type pipeConn struct {
+	io.Reader
+	io.Writer
+
+	closers []io.Closer
+}
+
+func (self *pipeConn) Close() error {
+	for _, c := range self.closers {
+		c.Close()
+	}
+	return nil
+}
+
+// Run the query in a sandboxed child process. The responder receives
+// the same messages as if the query ran in this process.
+func StartQuery(
+	config_obj *config_proto.Config,
+	ctx context.Context,
+	responder responder.Responder,
+	arg *actions_proto.VQLCollectorArgs) {
+
+	err := startQuery(config_obj, ctx, responder, arg)
+	if err != nil {
+		responder.RaiseError(ctx, fmt.Sprintf("query_sandbox: %v", err))
+	}
+}
+
+func startQuery(
+	config_obj *config_proto.Config,
+	ctx context.Context,
+	responder responder.Responder,
+	arg *actions_proto.VQLCollectorArgs) error {
+
+	request, err := newRequest(config_obj, responder, arg)
+	if err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	// The child reads requests from the first pipe and writes to the
+	// second.
+	child_in, parent_out, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	parent_in, child_out, err := os.Pipe()
+	if err != nil {
+		child_in.Close()
+		parent_out.Close()
+		return err
+	}
+
+	conn := &pipeConn{
+		Reader:  parent_in,
+		Writer:  parent_out,
+		closers: []io.Closer{parent_in, parent_out},
+	}
+	defer conn.Close()
+
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(sub_ctx, executable, SANDBOX_COMMAND)
+	cmd.ExtraFiles = []*os.File{child_in, child_out}
+
+	// The child has no access to the client's environment.
+	cmd.Env = []string{}
+
+	closer, err := setSysProcAttr(ctx, cmd, getConfig(config_obj), responder)
+	if err != nil {
+		child_in.Close()
+		child_out.Close()
+		return err
+	}
+	defer closer()
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		child_in.Close()
+		child_out.Close()
+		return err
+	}
+
+	err = cmd.Start()
+
+	// The child holds its own copies now.
+	child_in.Close()
+	child_out.Close()
+
+	if err != nil {
+		return err
+	}
+
+	broker := NewBroker(sub_ctx, responder, request)
+	defer broker.close()
+
+	server := rpc.NewServer()
+	err = server.Register(broker)
+	if err != nil {
+		return err
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	// Serve until the child closes its end of the pipe.
+	go func() {
+		defer wg.Done()
+		server.ServeConn(conn)
+	}()
+
+	// Anything the child prints (e.g. a panic) goes to the
+	// collection log.
+	go func() {
+		defer wg.Done()
+
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			responder.Log(ctx, logging.DEFAULT,
+				"query_sandbox: "+scanner.Text())
+		}
+	}()
+
+	wg.Wait()
+	err = cmd.Wait()
+
+	if !broker.isReturned() {
+		if err == nil {
+			err = fmt.Errorf("child exited before completing the query")
+		}
+		return fmt.Errorf("child failed: %v", err)
+	}
+
+	return nil
+}
+
+func newRequest(
+	config_obj *config_proto.Config,
+	responder responder.Responder,
+	arg *actions_proto.VQLCollectorArgs) (*Request, error) {
+
+	// The child only needs the client config to run queries. It
+	// never talks to the server itself.
+	child_config := &config_proto.Config{
+		Remappings: config_obj.Remappings,
+	}
+	if config_obj.Client != nil {
+		child_config.Client = proto.Clone(
+			config_obj.Client).(*config_proto.ClientConfig)
+		child_config.Client.Nonce = ""
+	}
+
+	serialized_config, err := proto.Marshal(child_config)
+	if err != nil {
+		return nil, err
+	}
+
+	serialized_args, err := proto.Marshal(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	flow_context := responder.FlowContext()
+	return &Request{
+		Config:    serialized_config,
+		Args:      serialized_args,
+		FlowId:    flow_context.SessionId(),
+		DryRun:    flow_context.DryRun(),
+		Accessors: getBrokeredAccessors(getConfig(config_obj)),
+	}, nil
+}
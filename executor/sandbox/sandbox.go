@@ -0,0 +1,243 @@
+/*
+  The query sandbox runs collections which do not need elevated
+  privileges in a child process.
+
+  The child is the client binary itself started with the hidden
+  query_sandbox command. On Linux it runs as an unprivileged user
+  (nobody by default), may not regain privileges (no_new_privs) and
+  installs a seccomp filter denying syscalls it never needs (e.g.
+  execve, ptrace or mount). If the client is not running as root the
+  user can not be changed and a warning is logged to the
+  collection. On Windows it runs with a restricted token without
+  privileges or Administrators access at Low integrity. The child
+  refuses to run if these restrictions can not be applied.
+
+  This limits the damage a malicious artifact, or a bug in a parser
+  fed with attacker controlled data, can do to the endpoint. Other
+  platforms run all queries in the client process.
+
+  The child talks to the client over a pair of pipes using net/rpc.
+  The client process brokers all access to the outside world:
+
+  1. Responses, logs and uploads are forwarded to the collection's
+     responder.
+
+  2. The brokered accessors (file, auto, ntfs and raw_file by
+     default) are read through the client so the child can still
+     read protected files - but only through the read only accessor
+     API.
+
+  Queries calling plugins or functions that require privileged
+  permissions (e.g. execve()) still run in the client process.
+*/
+
+package sandbox
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter/types"
+)
+
+const (
+	// The hidden command which starts the child.
+	SANDBOX_COMMAND = "query_sandbox"
+
+	// Maximum size of a single brokered read.
+	MAX_READ_SIZE = 1024 * 1024
+)
+
+var (
+	defaultUser = "nobody"
+
+	defaultPrivilegedPermissions = []string{
+		"EXECVE", "FILESYSTEM_WRITE", "MACHINE_STATE", "REMEDIATION",
+	}
+
+	defaultBrokeredAccessors = []string{
+		"file", "auto", "ntfs", "raw_file",
+	}
+
+	// Anything that looks like a call to a plugin or function.
+	callRegex = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*\(`)
+)
+
+// The RPC protocol between the child and the client.
+type Empty struct{}
+
+type Request struct {
+	// A serialized config_proto.Config with only the client config.
+	Config []byte
+
+	// The serialized actions_proto.VQLCollectorArgs.
+	Args []byte
+
+	FlowId string
+	DryRun bool
+
+	Accessors []string
+}
+
+type MessageArgs struct {
+	// A serialized crypto_proto.VeloMessage
+	Message []byte
+}
+
+type LogArgs struct {
+	Level   string
+	Message string
+}
+
+type PathArgs struct {
+	Accessor string
+	Path     string
+}
+
+type FileInfo struct {
+	Name     string
+	FullPath string
+	Size     int64
+	Mode     uint32
+	IsDir    bool
+	IsLink   bool
+	Link     string
+	Mtime    time.Time
+	Atime    time.Time
+	Ctime    time.Time
+	Btime    time.Time
+
+	// JSON encoded data dict
+	Data []byte
+}
+
+type OpenReply struct {
+	Handle int64
+	Size   int64
+}
+
+type ReadArgs struct {
+	Handle int64
+	Offset int64
+	Length int64
+}
+
+type HandleArgs struct {
+	Handle int64
+}
+
+func getConfig(config_obj *config_proto.Config) *config_proto.QuerySandboxConfig {
+	if config_obj == nil || config_obj.Client == nil {
+		return nil
+	}
+	return config_obj.Client.QuerySandbox
+}
+
+// Should this collection run in the sandbox?
+func ShouldSandbox(
+	config_obj *config_proto.Config,
+	arg *actions_proto.VQLCollectorArgs) bool {
+	sandbox_config := getConfig(config_obj)
+	if sandbox_config == nil || !sandbox_config.Enabled || !IsSupported() {
+		return false
+	}
+
+	privileged := sandbox_config.PrivilegedPermissions
+	if len(privileged) == 0 {
+		privileged = defaultPrivilegedPermissions
+	}
+
+	return !NeedsPrivileges(arg, privileged)
+}
+
+// Check if the collection calls any plugin or function requiring
+// one of the privileged permissions. This errs on the side of
+// caution: any identifier followed by a bracket (even in a string)
+// is treated as a call.
+func NeedsPrivileges(
+	arg *actions_proto.VQLCollectorArgs, privileged []string) bool {
+	is_privileged := make(map[string]bool)
+	for _, p := range privileged {
+		is_privileged[strings.ToUpper(p)] = true
+	}
+
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	type_map := types.NewTypeMap()
+	for _, name := range calledNames(arg) {
+		var metadata *ordereddict.Dict
+
+		plugin, pres := vql_subsystem.GetPlugin(name)
+		if pres {
+			metadata = plugin.Info(scope, type_map).Metadata
+		} else {
+			function, pres := vql_subsystem.GetFunction(name)
+			if !pres {
+				continue
+			}
+			metadata = function.Info(scope, type_map).Metadata
+		}
+
+		if metadata == nil {
+			continue
+		}
+
+		permissions, _ := metadata.GetString("permissions")
+		for _, p := range strings.Split(permissions, ",") {
+			if is_privileged[p] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// All the names called anywhere in the collection, including the
+// artifacts sent with it.
+func calledNames(arg *actions_proto.VQLCollectorArgs) []string {
+	vql := []string{arg.Precondition}
+	for _, query := range arg.Query {
+		vql = append(vql, query.VQL)
+	}
+
+	for _, artifact := range arg.Artifacts {
+		vql = append(vql, artifact.Precondition, artifact.Export)
+		for _, source := range artifact.Sources {
+			vql = append(vql, source.Precondition, source.Query)
+		}
+	}
+
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, match := range callRegex.FindAllStringSubmatch(
+		strings.Join(vql, "\n"), -1) {
+		name := match[1]
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func getUser(sandbox_config *config_proto.QuerySandboxConfig) string {
+	if sandbox_config != nil && sandbox_config.User != "" {
+		return sandbox_config.User
+	}
+	return defaultUser
+}
+
+func getBrokeredAccessors(
+	sandbox_config *config_proto.QuerySandboxConfig) []string {
+	if sandbox_config != nil && len(sandbox_config.BrokeredAccessors) > 0 {
+		return sandbox_config.BrokeredAccessors
+	}
+	return defaultBrokeredAccessors
+}
@@ -0,0 +1,182 @@
+package sandbox_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/executor/sandbox"
+	"www.velocidex.com/golang/velociraptor/responder"
+	"www.velocidex.com/golang/velociraptor/startup"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/file"
+	_ "www.velocidex.com/golang/velociraptor/vql/common"
+	_ "www.velocidex.com/golang/velociraptor/vql/filesystem"
+)
+
+// The test binary doubles as the sandbox child.
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == sandbox.SANDBOX_COMMAND {
+		err := runChild()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+func runChild() error {
+	child, err := sandbox.NewChild()
+	if err != nil {
+		return err
+	}
+	defer child.Close()
+
+	config_obj := child.Config()
+	config_obj.Services = sandbox.ChildServices()
+
+	ctx := context.Background()
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+	if err != nil {
+		return err
+	}
+
+	return child.Run(ctx, config_obj)
+}
+
+func makeConfig(t *testing.T) *config_proto.Config {
+	current, err := user.Current()
+	assert.NoError(t, err)
+
+	return &config_proto.Config{
+		Client: &config_proto.ClientConfig{
+			QuerySandbox: &config_proto.QuerySandboxConfig{
+				Enabled: true,
+				User:    current.Username,
+			},
+		},
+	}
+}
+
+func TestNeedsPrivileges(t *testing.T) {
+	config_obj := makeConfig(t)
+
+	for _, query := range []string{
+		"SELECT * FROM execve(argv=['ls'])",
+		"SELECT * FROM info() WHERE execve (argv=['ls'])",
+	} {
+		arg := &actions_proto.VQLCollectorArgs{
+			Query: []*actions_proto.VQLRequest{{VQL: query}},
+		}
+		assert.False(t, sandbox.ShouldSandbox(config_obj, arg), query)
+	}
+
+	arg := &actions_proto.VQLCollectorArgs{
+		Query: []*actions_proto.VQLRequest{{
+			VQL: "SELECT * FROM glob(globs='/*')"}},
+	}
+	assert.Equal(t, sandbox.IsSupported(),
+		sandbox.ShouldSandbox(config_obj, arg))
+
+	// Disabled by default.
+	assert.False(t, sandbox.ShouldSandbox(&config_proto.Config{
+		Client: &config_proto.ClientConfig{}}, arg))
+}
+
+func TestSandboxedQuery(t *testing.T) {
+	if !sandbox.IsSupported() {
+		t.Skip("Query sandbox not supported on this platform")
+	}
+
+	tmpdir, err := ioutil.TempDir("", "sandbox_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	filename := filepath.Join(tmpdir, "hello.txt")
+	err = ioutil.WriteFile(filename, []byte("hello world"), 0600)
+	assert.NoError(t, err)
+
+	config_obj := makeConfig(t)
+	test_responder := responder.TestResponderWithFlowId(
+		config_obj, "F.TestSandboxedQuery")
+	defer test_responder.Close()
+
+	sandbox.StartQuery(config_obj, context.Background(), test_responder,
+		&actions_proto.VQLCollectorArgs{
+			Query: []*actions_proto.VQLRequest{{
+				Name: "Test",
+				VQL: fmt.Sprintf(
+					"SELECT read_file(filename=%q) AS Data FROM scope()",
+					filename),
+			}},
+		})
+
+	messages := []*crypto_proto.VeloMessage{}
+	vtesting.WaitUntil(5*time.Second, t, func() bool {
+		messages = test_responder.Drain.Messages()
+		for _, msg := range messages {
+			if msg.VQLResponse != nil {
+				return true
+			}
+		}
+		return false
+	})
+
+	results := ""
+	for _, msg := range messages {
+		if msg.VQLResponse != nil {
+			results += msg.VQLResponse.JSONLResponse
+		}
+	}
+	assert.True(t, strings.Contains(results, "hello world"), results)
+}
+
+func TestSandboxDeniesExecve(t *testing.T) {
+	if runtime.GOOS != "linux" || !sandbox.IsSupported() {
+		t.Skip("Seccomp filter only applies on Linux")
+	}
+
+	config_obj := makeConfig(t)
+	test_responder := responder.TestResponderWithFlowId(
+		config_obj, "F.TestSandboxDeniesExecve")
+	defer test_responder.Close()
+
+	// Normally the query would run in the client process because
+	// execve() requires the EXECVE permission.
+	sandbox.StartQuery(config_obj, context.Background(), test_responder,
+		&actions_proto.VQLCollectorArgs{
+			Query: []*actions_proto.VQLRequest{{
+				Name: "Test",
+				VQL:  "SELECT * FROM execve(argv=['/bin/true'])",
+			}},
+		})
+
+	test_responder.FlowContext().FlushLogMessages(context.Background())
+
+	logs := ""
+	vtesting.WaitUntil(5*time.Second, t, func() bool {
+		logs = ""
+		for _, msg := range test_responder.Drain.Messages() {
+			if msg.LogMessage != nil {
+				logs += msg.LogMessage.Jsonl
+			}
+		}
+		return strings.Contains(logs, "operation not permitted")
+	})
+}
@@ -0,0 +1,193 @@
+//go:build linux
+// +build linux
+
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/responder"
+)
+
+// Not all of these are exported by golang.org/x/sys/unix.
+const (
+	SECCOMP_SET_MODE_FILTER   = 1
+	SECCOMP_FILTER_FLAG_TSYNC = 1
+
+	SECCOMP_RET_KILL_PROCESS = 0x80000000
+	SECCOMP_RET_ERRNO        = 0x00050000
+	SECCOMP_RET_ALLOW        = 0x7fff0000
+
+	// Offsets into struct seccomp_data
+	seccompDataNr   = 0
+	seccompDataArch = 4
+
+	// Syscall numbers with this bit set use the x32 ABI on amd64.
+	x32SyscallBit = 0x40000000
+)
+
+var (
+	auditArch = map[string]uint32{
+		"amd64": unix.AUDIT_ARCH_X86_64,
+		"386":   unix.AUDIT_ARCH_I386,
+		"arm64": unix.AUDIT_ARCH_AARCH64,
+		"arm":   unix.AUDIT_ARCH_ARM,
+	}
+
+	// The child reads files through the broker so it never needs to
+	// start programs, inspect other processes or change the system.
+	deniedSyscalls = []uint32{
+		unix.SYS_EXECVE,
+		unix.SYS_EXECVEAT,
+		unix.SYS_PTRACE,
+		unix.SYS_PROCESS_VM_READV,
+		unix.SYS_PROCESS_VM_WRITEV,
+		unix.SYS_MOUNT,
+		unix.SYS_UMOUNT2,
+		unix.SYS_PIVOT_ROOT,
+		unix.SYS_CHROOT,
+		unix.SYS_UNSHARE,
+		unix.SYS_SETNS,
+		unix.SYS_INIT_MODULE,
+		unix.SYS_FINIT_MODULE,
+		unix.SYS_DELETE_MODULE,
+		unix.SYS_KEXEC_LOAD,
+		unix.SYS_REBOOT,
+		unix.SYS_BPF,
+		unix.SYS_PERF_EVENT_OPEN,
+	}
+)
+
+func IsSupported() bool {
+	_, pres := auditArch[runtime.GOARCH]
+	return pres
+}
+
+// Run the child as the unprivileged user. We can only switch users
+// when running as root - otherwise the child runs as the current
+// user and is only restricted by the seccomp filter it installs.
+func setSysProcAttr(
+	ctx context.Context, cmd *exec.Cmd,
+	sandbox_config *config_proto.QuerySandboxConfig,
+	responder responder.Responder) (func(), error) {
+	attr := &syscall.SysProcAttr{
+		// Do not leave the child running if the client dies.
+		Pdeathsig: syscall.SIGKILL,
+	}
+
+	if os.Getuid() != 0 {
+		responder.Log(ctx, logging.WARNING, fmt.Sprintf(
+			"query_sandbox: Client is not running as root so the sandbox "+
+				"can not switch to user %v. The query runs as uid %v with "+
+				"only the seccomp filter applied.",
+			getUser(sandbox_config), os.Getuid()))
+
+	} else {
+		u, err := user.Lookup(getUser(sandbox_config))
+		if err != nil {
+			return nil, err
+		}
+
+		uid, err := strconv.ParseUint(u.Uid, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		gid, err := strconv.ParseUint(u.Gid, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		attr.Credential = &syscall.Credential{
+			Uid:    uint32(uid),
+			Gid:    uint32(gid),
+			Groups: []uint32{},
+		}
+	}
+
+	cmd.SysProcAttr = attr
+	return func() {}, nil
+}
+
+// The pipes to the client are passed as fd 3 and 4.
+func childPipes() (*os.File, *os.File, error) {
+	return os.NewFile(3, "sandbox_in"), os.NewFile(4, "sandbox_out"), nil
+}
+
+// Called in the child to make sure it can never regain privileges
+// (e.g. by executing a setuid binary) and can not call any of the
+// denied syscalls. If the filter can not be installed the child
+// exits rather than run unrestricted.
+func restrictProcess() error {
+	err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0)
+	if err != nil {
+		return fmt.Errorf("Setting no_new_privs: %w", err)
+	}
+
+	filter, err := seccompFilter()
+	if err != nil {
+		return err
+	}
+
+	prog := &unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: &filter[0],
+	}
+
+	// The Go runtime already started other threads so the filter
+	// must be synchronized to all of them.
+	_, _, errno := unix.Syscall(unix.SYS_SECCOMP, SECCOMP_SET_MODE_FILTER,
+		SECCOMP_FILTER_FLAG_TSYNC, uintptr(unsafe.Pointer(prog)))
+	if errno != 0 {
+		return fmt.Errorf("Installing seccomp filter: %w", errno)
+	}
+	return nil
+}
+
+func seccompFilter() ([]unix.SockFilter, error) {
+	arch, pres := auditArch[runtime.GOARCH]
+	if !pres {
+		return nil, fmt.Errorf("Seccomp filter not supported on %v",
+			runtime.GOARCH)
+	}
+
+	stmt := func(code uint16, k uint32) unix.SockFilter {
+		return unix.SockFilter{Code: code, K: k}
+	}
+	jump := func(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+		return unix.SockFilter{Code: code, K: k, Jt: jt, Jf: jf}
+	}
+
+	// Kill the process if it uses a different syscall ABI since the
+	// syscall numbers below would not match.
+	filter := []unix.SockFilter{
+		stmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArch),
+		jump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, arch, 1, 0),
+		stmt(unix.BPF_RET|unix.BPF_K, SECCOMP_RET_KILL_PROCESS),
+		stmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataNr),
+		jump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, x32SyscallBit, 0, 1),
+		stmt(unix.BPF_RET|unix.BPF_K, SECCOMP_RET_KILL_PROCESS),
+	}
+
+	// Each denied syscall fails with EPERM so the query can log
+	// a sensible error.
+	for _, nr := range deniedSyscalls {
+		filter = append(filter,
+			jump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, nr, 0, 1),
+			stmt(unix.BPF_RET|unix.BPF_K,
+				SECCOMP_RET_ERRNO|uint32(unix.EPERM)))
+	}
+
+	return append(filter, stmt(unix.BPF_RET|unix.BPF_K, SECCOMP_RET_ALLOW)), nil
+}
@@ -0,0 +1,35 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/responder"
+)
+
+var notSupportedError = errors.New("Query sandbox is not supported on this platform")
+
+func IsSupported() bool {
+	return false
+}
+
+func setSysProcAttr(
+	ctx context.Context, cmd *exec.Cmd,
+	sandbox_config *config_proto.QuerySandboxConfig,
+	responder responder.Responder) (func(), error) {
+	return nil, notSupportedError
+}
+
+func childPipes() (*os.File, *os.File, error) {
+	return nil, nil, notSupportedError
+}
+
+func restrictProcess() error {
+	return notSupportedError
+}
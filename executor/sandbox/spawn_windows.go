@@ -0,0 +1,176 @@
+//go:build windows
+// +build windows
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/responder"
+)
+
+const (
+	// CreateRestrictedToken flags
+	DISABLE_MAX_PRIVILEGE = 0x1
+
+	SECURITY_MANDATORY_LOW_RID = 0x1000
+
+	// Windows can not pass extra files by fd number so the child
+	// finds the inherited pipe handles in this environment variable.
+	SANDBOX_HANDLES_ENV = "VELOCIRAPTOR_SANDBOX_HANDLES"
+)
+
+var (
+	modadvapi32               = windows.NewLazySystemDLL("advapi32.dll")
+	procCreateRestrictedToken = modadvapi32.NewProc("CreateRestrictedToken")
+)
+
+func IsSupported() bool {
+	return true
+}
+
+// Run the child with a restricted copy of the client's token: all
+// privileges are removed, the Administrators group is only used to
+// deny access and the integrity level is lowered to Low so the
+// child can not write to anything the client owns. Using a
+// restricted copy of our own token does not require any privilege.
+func setSysProcAttr(
+	ctx context.Context, cmd *exec.Cmd,
+	sandbox_config *config_proto.QuerySandboxConfig,
+	responder responder.Responder) (func(), error) {
+	token, err := restrictedToken()
+	if err != nil {
+		return nil, fmt.Errorf("Creating restricted token: %w", err)
+	}
+
+	handles := []syscall.Handle{}
+	names := []string{}
+	for _, file := range cmd.ExtraFiles {
+		handle := windows.Handle(file.Fd())
+		err := windows.SetHandleInformation(handle,
+			windows.HANDLE_FLAG_INHERIT, windows.HANDLE_FLAG_INHERIT)
+		if err != nil {
+			token.Close()
+			return nil, err
+		}
+		handles = append(handles, syscall.Handle(handle))
+		names = append(names, strconv.FormatUint(uint64(handle), 10))
+	}
+	cmd.ExtraFiles = nil
+	cmd.Env = append(cmd.Env, SANDBOX_HANDLES_ENV+"="+strings.Join(names, ","))
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:                 true,
+		Token:                      syscall.Token(token),
+		AdditionalInheritedHandles: handles,
+	}
+
+	// The token is only needed until the child is started.
+	return func() { token.Close() }, nil
+}
+
+func restrictedToken() (windows.Token, error) {
+	var token windows.Token
+	err := windows.OpenProcessToken(windows.CurrentProcess(),
+		windows.TOKEN_DUPLICATE|windows.TOKEN_QUERY|
+			windows.TOKEN_ASSIGN_PRIMARY|windows.TOKEN_ADJUST_DEFAULT,
+		&token)
+	if err != nil {
+		return 0, err
+	}
+	defer token.Close()
+
+	admins, err := windows.CreateWellKnownSid(windows.WinBuiltinAdministratorsSid)
+	if err != nil {
+		return 0, err
+	}
+
+	disabled := []windows.SIDAndAttributes{{Sid: admins}}
+
+	var restricted windows.Token
+	res, _, err := procCreateRestrictedToken.Call(
+		uintptr(token), DISABLE_MAX_PRIVILEGE,
+		uintptr(len(disabled)), uintptr(unsafe.Pointer(&disabled[0])),
+		0, 0, 0, 0,
+		uintptr(unsafe.Pointer(&restricted)))
+	if res == 0 {
+		return 0, err
+	}
+
+	low, err := windows.CreateWellKnownSid(windows.WinLowLabelSid)
+	if err != nil {
+		restricted.Close()
+		return 0, err
+	}
+
+	label := &windows.Tokenmandatorylabel{
+		Label: windows.SIDAndAttributes{
+			Sid:        low,
+			Attributes: windows.SE_GROUP_INTEGRITY,
+		},
+	}
+	err = windows.SetTokenInformation(restricted,
+		windows.TokenIntegrityLevel,
+		(*byte)(unsafe.Pointer(label)), label.Size())
+	if err != nil {
+		restricted.Close()
+		return 0, err
+	}
+
+	return restricted, nil
+}
+
+func childPipes() (*os.File, *os.File, error) {
+	handles := strings.Split(os.Getenv(SANDBOX_HANDLES_ENV), ",")
+	if len(handles) != 2 {
+		return nil, nil, errors.New("Sandbox pipe handles not provided")
+	}
+
+	in, err := strconv.ParseUint(handles[0], 10, 64)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out, err := strconv.ParseUint(handles[1], 10, 64)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return os.NewFile(uintptr(in), "sandbox_in"),
+		os.NewFile(uintptr(out), "sandbox_out"), nil
+}
+
+// The restrictions are applied by the token the client started us
+// with. Refuse to run if that did not happen.
+func restrictProcess() error {
+	token := windows.GetCurrentProcessToken()
+
+	var level, returned uint32
+	buffer := make([]byte, 64)
+	err := windows.GetTokenInformation(token, windows.TokenIntegrityLevel,
+		&buffer[0], uint32(len(buffer)), &returned)
+	if err != nil {
+		return err
+	}
+
+	label := (*windows.Tokenmandatorylabel)(unsafe.Pointer(&buffer[0]))
+	count := label.Label.Sid.SubAuthorityCount()
+	if count > 0 {
+		level = label.Label.Sid.SubAuthority(uint32(count - 1))
+	}
+
+	if level > SECURITY_MANDATORY_LOW_RID {
+		return fmt.Errorf("Sandbox is running at integrity level %#x", level)
+	}
+	return nil
+}
@@ -64,6 +64,18 @@ func GetExtensionForFilestore(path_spec FSPathSpec) string {
 
 	case PATH_TYPE_FILESTORE_ANY:
 		return ""
+
+	case PATH_TYPE_FILESTORE_COLUMNAR:
+		return ".col"
+
+	case PATH_TYPE_FILESTORE_COLUMNAR_INDEX:
+		return ".col.index"
+
+	case PATH_TYPE_FILESTORE_DOWNLOAD_7Z:
+		return ".7z"
+
+	case PATH_TYPE_FILESTORE_DOWNLOAD_AGE:
+		return ".tar.age"
 	}
 
 	return ""
@@ -126,5 +138,21 @@ func GetFileStorePathTypeFromExtension(name string) (PathType, string) {
 		return PATH_TYPE_FILESTORE_YAML, name[:len(name)-5]
 	}
 
+	if strings.HasSuffix(name, ".col") {
+		return PATH_TYPE_FILESTORE_COLUMNAR, name[:len(name)-4]
+	}
+
+	if strings.HasSuffix(name, ".col.index") {
+		return PATH_TYPE_FILESTORE_COLUMNAR_INDEX, name[:len(name)-10]
+	}
+
+	if strings.HasSuffix(name, ".7z") {
+		return PATH_TYPE_FILESTORE_DOWNLOAD_7Z, name[:len(name)-3]
+	}
+
+	if strings.HasSuffix(name, ".tar.age") {
+		return PATH_TYPE_FILESTORE_DOWNLOAD_AGE, name[:len(name)-8]
+	}
+
 	return PATH_TYPE_FILESTORE_ANY, name
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"www.velocidex.com/golang/velociraptor/tracing"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
@@ -72,9 +73,12 @@ func Instrument(access_type, datastore string, path_spec FSPathSpec) func() time
 	// Mark the start of the time.
 	start := Clock().Now()
 
+	span := startSpan(access_type, datastore, tag, path_spec)
+
 	// When this func is called we calculate the time difference and
 	// observe it into the histogram.
 	return func() time.Duration {
+		span.End()
 		d := Clock().Now().Sub(start)
 		FilestoreHistorgram.WithLabelValues(
 			tag, access_type, datastore).Observe(d.Seconds())
@@ -101,9 +105,12 @@ func InstrumentWithDelay(
 		Clock().Sleep(time.Duration(inject_time) * time.Millisecond)
 	}
 
+	span := startSpan(access_type, datastore, tag, path_spec)
+
 	// When this func is called we calculate the time difference and
 	// observe it into the histogram.
 	return func() time.Duration {
+		span.End()
 		d := Clock().Now().Sub(start)
 		FilestoreHistorgram.WithLabelValues(
 			tag, access_type, datastore).Observe(d.Seconds())
@@ -111,6 +118,24 @@ func InstrumentWithDelay(
 	}
 }
 
+// Only records a span when storage tracing is enabled.
+func startSpan(access_type, datastore, tag string,
+	path_spec FSPathSpec) *tracing.Span {
+	if !tracing.StorageEnabled() {
+		return nil
+	}
+
+	attributes := []tracing.Attribute{
+		tracing.String("tag", tag),
+		tracing.String("datastore", datastore),
+	}
+	if path_spec != nil {
+		attributes = append(attributes,
+			tracing.String("path", path_spec.AsClientPath()))
+	}
+	return tracing.StartStorage("filestore."+access_type, attributes...)
+}
+
 func init() {
 	// Allow the delay to be specified by the env var.
 	delay_str, pres := os.LookupEnv("VELOCIRAPTOR_SLOW_FILESYSTEM")
@@ -106,6 +106,14 @@ const (
 
 	// Arbitrary extensions.
 	PATH_TYPE_FILESTORE_ANY
+
+	// Used for result sets stored in columnar format.
+	PATH_TYPE_FILESTORE_COLUMNAR
+	PATH_TYPE_FILESTORE_COLUMNAR_INDEX
+
+	// Encrypted export containers in the download folder.
+	PATH_TYPE_FILESTORE_DOWNLOAD_7Z
+	PATH_TYPE_FILESTORE_DOWNLOAD_AGE
 )
 
 type _PathSpec interface {
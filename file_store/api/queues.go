@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"hash/fnv"
 	"time"
 
 	"github.com/Velocidex/ordereddict"
@@ -16,6 +17,38 @@ type QueueOptions struct {
 
 	// Who is listening to this queue
 	OwnerName string
+
+	// When Shards > 1 the listener only receives the rows of clients
+	// which hash to Shard. This allows several readers to process a
+	// busy queue in parallel while keeping the rows of each client in
+	// order.
+	Shard  int
+	Shards int
+}
+
+// Should the listener receive this row?
+func (self QueueOptions) Accepts(row *ordereddict.Dict) bool {
+	if self.Shards <= 1 {
+		return true
+	}
+	return ShardForRow(row, self.Shards) == self.Shard
+}
+
+// Rows are sharded by their ClientId. Rows without a client id all go
+// to the first shard.
+func ShardForRow(row *ordereddict.Dict, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+
+	client_id, _ := row.GetString("ClientId")
+	if client_id == "" {
+		return 0
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(client_id))
+	return int(hash.Sum32() % uint32(shards))
 }
 
 // A QueueManager writes query results into queues. The manager is
@@ -0,0 +1,214 @@
+package compressed
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+/*
+  Compressed files consist of a sequence of independently compressed
+  chunks so they can be appended to and read at random offsets:
+
+  Header (24 bytes):
+     Magic "VZSTDCH1"
+     uint64 logical offset of the chunk's data in the file.
+     uint32 uncompressed length
+     uint32 compressed length
+
+  zstd frame (compressed length bytes)
+
+  Footer (8 bytes):
+     uint32 total length of the chunk (header + frame + footer)
+     Magic "VZCE"
+
+  The footer allows the logical size of the file to be found from
+  the last chunk without reading the entire file.
+*/
+
+const (
+	// The maximum amount of data compressed into a single chunk.
+	CHUNK_SIZE = 1024 * 1024
+
+	HEADER_SIZE = 24
+	FOOTER_SIZE = 8
+)
+
+var (
+	chunk_magic  = []byte("VZSTDCH1")
+	footer_magic = []byte("VZCE")
+
+	ErrCorrupted = errors.New("Compressed file is corrupted")
+
+	codec_mu sync.Mutex
+	encoder  *zstd.Encoder
+	decoder  *zstd.Decoder
+)
+
+type chunkHeader struct {
+	Offset             int64
+	UncompressedLength int64
+	CompressedLength   int64
+
+	// Where the compressed frame starts in the underlying file.
+	FileOffset int64
+}
+
+func (self *chunkHeader) End() int64 {
+	return self.Offset + self.UncompressedLength
+}
+
+func (self *chunkHeader) ChunkLength() int64 {
+	return HEADER_SIZE + self.CompressedLength + FOOTER_SIZE
+}
+
+func getCodecs() (*zstd.Encoder, *zstd.Decoder, error) {
+	codec_mu.Lock()
+	defer codec_mu.Unlock()
+
+	if encoder == nil {
+		var err error
+		encoder, err = zstd.NewWriter(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		decoder, err = zstd.NewReader(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return encoder, decoder, nil
+}
+
+// Compress the data into a complete chunk starting at logical offset.
+func encodeChunk(data []byte, offset int64) ([]byte, error) {
+	encoder, _, err := getCodecs()
+	if err != nil {
+		return nil, err
+	}
+
+	frame := encoder.EncodeAll(data, nil)
+	chunk_length := HEADER_SIZE + len(frame) + FOOTER_SIZE
+
+	result := make([]byte, chunk_length)
+	copy(result, chunk_magic)
+	binary.LittleEndian.PutUint64(result[8:], uint64(offset))
+	binary.LittleEndian.PutUint32(result[16:], uint32(len(data)))
+	binary.LittleEndian.PutUint32(result[20:], uint32(len(frame)))
+	copy(result[HEADER_SIZE:], frame)
+
+	footer := result[HEADER_SIZE+len(frame):]
+	binary.LittleEndian.PutUint32(footer, uint32(chunk_length))
+	copy(footer[4:], footer_magic)
+
+	return result, nil
+}
+
+func decodeFrame(frame []byte, expected_length int64) ([]byte, error) {
+	_, decoder, err := getCodecs()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decoder.DecodeAll(frame, make([]byte, 0, expected_length))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(result)) != expected_length {
+		return nil, ErrCorrupted
+	}
+	return result, nil
+}
+
+func readAt(reader api.FileReader, buf []byte, offset int64) error {
+	_, err := reader.Seek(offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.ReadFull(reader, buf)
+	return err
+}
+
+func readHeader(reader api.FileReader, file_offset int64) (*chunkHeader, error) {
+	buf := make([]byte, HEADER_SIZE)
+	err := readAt(reader, buf, file_offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(buf[:8], chunk_magic) {
+		return nil, ErrCorrupted
+	}
+
+	return &chunkHeader{
+		Offset:             int64(binary.LittleEndian.Uint64(buf[8:16])),
+		UncompressedLength: int64(binary.LittleEndian.Uint32(buf[16:20])),
+		CompressedLength:   int64(binary.LittleEndian.Uint32(buf[20:24])),
+		FileOffset:         file_offset + HEADER_SIZE,
+	}, nil
+}
+
+// Returns true if the file starts with a compressed chunk.
+func isCompressed(reader api.FileReader, physical_size int64) bool {
+	if physical_size < HEADER_SIZE+FOOTER_SIZE {
+		return false
+	}
+
+	buf := make([]byte, len(chunk_magic))
+	err := readAt(reader, buf, 0)
+	return err == nil && bytes.Equal(buf, chunk_magic)
+}
+
+// Find the last chunk in the file from its footer.
+func readLastHeader(
+	reader api.FileReader, physical_size int64) (*chunkHeader, error) {
+	buf := make([]byte, FOOTER_SIZE)
+	err := readAt(reader, buf, physical_size-FOOTER_SIZE)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(buf[4:], footer_magic) {
+		return nil, ErrCorrupted
+	}
+
+	chunk_length := int64(binary.LittleEndian.Uint32(buf[:4]))
+	if chunk_length > physical_size {
+		return nil, ErrCorrupted
+	}
+
+	return readHeader(reader, physical_size-chunk_length)
+}
+
+// Read all the chunk headers in the file.
+func readChunks(
+	reader api.FileReader, physical_size int64) ([]*chunkHeader, error) {
+	var result []*chunkHeader
+	var offset int64
+
+	for file_offset := int64(0); file_offset < physical_size; {
+		header, err := readHeader(reader, file_offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Offset != offset {
+			return nil, ErrCorrupted
+		}
+
+		result = append(result, header)
+		offset = header.End()
+		file_offset += header.ChunkLength()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,202 @@
+/*
+  A file store which transparently compresses result sets and uploads.
+
+  Files are stored as a sequence of independently compressed zstd
+  chunks (see chunks.go). Each write to a file is compressed as it is
+  flushed so files written incrementally end up with many small
+  chunks - the recompression service periodically rewrites cold
+  files into full size chunks to improve the compression ratio.
+
+  Readers and writers always see the uncompressed data so result
+  set indexes and upload offsets remain valid. Files written before
+  compression was enabled are detected and continue to be read and
+  appended to uncompressed.
+*/
+
+package compressed
+
+import (
+	"io"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+type CompressedFileStore struct {
+	delegate api.FileStore
+
+	// Decides which files are compressed.
+	should_compress func(filename api.FSPathSpec) bool
+}
+
+// Wrap the file store if compression is enabled in the config.
+func MaybeWrap(config_obj *config_proto.Config,
+	delegate api.FileStore) api.FileStore {
+	if config_obj.Datastore == nil ||
+		config_obj.Datastore.Compression == "" {
+		return delegate
+	}
+	return NewCompressedFileStore(delegate)
+}
+
+func NewCompressedFileStore(delegate api.FileStore) *CompressedFileStore {
+	return &CompressedFileStore{
+		delegate:        delegate,
+		should_compress: ShouldCompress,
+	}
+}
+
+func (self *CompressedFileStore) ReadFile(
+	filename api.FSPathSpec) (api.FileReader, error) {
+	fd, err := self.delegate.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := fd.Stat()
+	if err != nil || !isCompressed(fd, stat.Size()) {
+		_, err = fd.Seek(0, io.SeekStart)
+		if err != nil {
+			fd.Close()
+			return nil, err
+		}
+		return fd, nil
+	}
+
+	reader, err := newCompressedReader(fd, stat.Size())
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	return reader, nil
+}
+
+func (self *CompressedFileStore) WriteFile(
+	filename api.FSPathSpec) (api.FileWriter, error) {
+	return self.WriteFileWithCompletion(filename, nil)
+}
+
+func (self *CompressedFileStore) WriteFileWithCompletion(
+	filename api.FSPathSpec, completion func()) (api.FileWriter, error) {
+	fd, err := self.delegate.WriteFileWithCompletion(filename, completion)
+	if err != nil {
+		return nil, err
+	}
+
+	if !self.should_compress(filename) {
+		return fd, nil
+	}
+
+	writer := &CompressedWriter{
+		delegate: fd,
+		filename: filename,
+		owner:    self,
+	}
+
+	// Continue appending to existing files in their current format.
+	physical_size, err := fd.Size()
+	if err == nil && physical_size > 0 {
+		size, compressed, err := self.logicalSize(filename, physical_size)
+		if err != nil {
+			fd.Close()
+			return nil, err
+		}
+		writer.plain = !compressed
+		writer.size = size
+	}
+
+	return writer, nil
+}
+
+// Returns the uncompressed size of the file and if it is compressed.
+func (self *CompressedFileStore) logicalSize(
+	filename api.FSPathSpec, physical_size int64) (int64, bool, error) {
+	fd, err := self.delegate.ReadFile(filename)
+	if err != nil {
+		return 0, false, err
+	}
+	defer fd.Close()
+
+	if !isCompressed(fd, physical_size) {
+		return physical_size, false, nil
+	}
+
+	header, err := readLastHeader(fd, physical_size)
+	if err != nil {
+		return 0, false, err
+	}
+	return header.End(), true, nil
+}
+
+func (self *CompressedFileStore) getFileInfo(
+	filename api.FSPathSpec, stat api.FileInfo) api.FileInfo {
+	if filename == nil || stat.IsDir() || !self.should_compress(filename) {
+		return stat
+	}
+
+	size, compressed, err := self.logicalSize(filename, stat.Size())
+	if err != nil || !compressed {
+		return stat
+	}
+
+	return &FileInfo{FileInfo: stat, size: size}
+}
+
+func (self *CompressedFileStore) StatFile(
+	filename api.FSPathSpec) (api.FileInfo, error) {
+	stat, err := self.delegate.StatFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return self.getFileInfo(filename, stat), nil
+}
+
+func (self *CompressedFileStore) ListDirectory(
+	dirname api.FSPathSpec) ([]api.FileInfo, error) {
+	infos, err := self.delegate.ListDirectory(dirname)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]api.FileInfo, 0, len(infos))
+	for _, info := range infos {
+		result = append(result, self.getFileInfo(info.PathSpec(), info))
+	}
+	return result, nil
+}
+
+func (self *CompressedFileStore) Delete(filename api.FSPathSpec) error {
+	return self.delegate.Delete(filename)
+}
+
+func (self *CompressedFileStore) Move(src, dest api.FSPathSpec) error {
+	return self.delegate.Move(src, dest)
+}
+
+func (self *CompressedFileStore) Close() error {
+	return self.delegate.Close()
+}
+
+// Result sets, logs and uploads are compressed. Indexes are small
+// and read at random so they are left alone.
+func ShouldCompress(filename api.FSPathSpec) bool {
+	switch filename.Type() {
+	case api.PATH_TYPE_FILESTORE_JSON, api.PATH_TYPE_FILESTORE_COLUMNAR:
+		return true
+
+	case api.PATH_TYPE_FILESTORE_ANY:
+		components := filename.Components()
+		if len(components) < 4 || components[0] != "clients" {
+			return false
+		}
+
+		switch components[2] {
+		case "vfs_files":
+			return true
+		case "collections":
+			return len(components) > 5 && components[4] == "uploads"
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,193 @@
+package compressed
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/tests"
+)
+
+type CompressedTestSuite struct {
+	*tests.FileStoreTestSuite
+
+	config_obj *config_proto.Config
+	file_store *CompressedFileStore
+	delegate   api.FileStore
+}
+
+func (self *CompressedTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "file_store_test")
+	assert.NoError(self.T(), err)
+
+	self.config_obj.Datastore.FilestoreDirectory = dir
+	self.config_obj.Datastore.Location = dir
+}
+
+func (self *CompressedTestSuite) TearDownTest() {
+	os.RemoveAll(self.config_obj.Datastore.FilestoreDirectory)
+}
+
+// Generate some compressible data with rows at known offsets.
+func getRows(start, end int) []byte {
+	result := &bytes.Buffer{}
+	for i := start; i < end; i++ {
+		fmt.Fprintf(result, "{\"Row\":%08d,\"Data\":\"Some repeating data\"}\n", i)
+	}
+	return result.Bytes()
+}
+
+func (self *CompressedTestSuite) readAll(filename api.FSPathSpec) []byte {
+	reader, err := self.file_store.ReadFile(filename)
+	assert.NoError(self.T(), err)
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	assert.NoError(self.T(), err)
+	return data
+}
+
+func (self *CompressedTestSuite) TestLargeFile() {
+	filename := path_specs.NewUnsafeFilestorePath("clients", "C.1", "large")
+
+	// Write enough data for several chunks over multiple writes.
+	expected := getRows(0, 100000)
+	fd, err := self.file_store.WriteFile(filename)
+	assert.NoError(self.T(), err)
+
+	for i := 0; i < len(expected); i += 100000 {
+		end := i + 100000
+		if end > len(expected) {
+			end = len(expected)
+		}
+		_, err = fd.Write(expected[i:end])
+		assert.NoError(self.T(), err)
+	}
+	fd.Close()
+
+	// The data is stored compressed.
+	physical, err := self.delegate.StatFile(filename)
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), physical.Size() < int64(len(expected))/5)
+
+	// But the file store reports the uncompressed size.
+	stat, err := self.file_store.StatFile(filename)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(len(expected)), stat.Size())
+
+	assert.Equal(self.T(), expected, self.readAll(filename))
+
+	// Seek across chunk boundaries.
+	reader, err := self.file_store.ReadFile(filename)
+	assert.NoError(self.T(), err)
+	defer reader.Close()
+
+	row := getRows(60000, 60001)
+	_, err = reader.Seek(int64(len(row)*60000), io.SeekStart)
+	assert.NoError(self.T(), err)
+
+	buf := make([]byte, len(row))
+	_, err = io.ReadFull(reader, buf)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), string(row), string(buf))
+
+	// Appending continues the logical offsets.
+	fd, err = self.file_store.WriteFile(filename)
+	assert.NoError(self.T(), err)
+
+	size, err := fd.Size()
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(len(expected)), size)
+
+	_, err = fd.Write(getRows(100000, 100010))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	assert.Equal(self.T(), getRows(0, 100010), self.readAll(filename))
+}
+
+func (self *CompressedTestSuite) TestUncompressedFiles() {
+	filename := path_specs.NewUnsafeFilestorePath("clients", "C.1", "legacy")
+
+	// A file written before compression was enabled.
+	fd, err := self.delegate.WriteFile(filename)
+	assert.NoError(self.T(), err)
+	fd.Write(getRows(0, 10))
+	fd.Close()
+
+	// Is still appended to uncompressed.
+	fd, err = self.file_store.WriteFile(filename)
+	assert.NoError(self.T(), err)
+	fd.Write(getRows(10, 20))
+	fd.Close()
+
+	physical, err := self.delegate.StatFile(filename)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(len(getRows(0, 20))), physical.Size())
+	assert.Equal(self.T(), getRows(0, 20), self.readAll(filename))
+
+	// Indexes are never compressed.
+	index := filename.SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX)
+	fd, err = self.file_store.WriteFile(index)
+	assert.NoError(self.T(), err)
+	fd.Write([]byte("12345678"))
+	fd.Close()
+
+	physical, err = self.delegate.StatFile(index)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(8), physical.Size())
+}
+
+func (self *CompressedTestSuite) TestRecompress() {
+	filename := path_specs.NewUnsafeFilestorePath("clients", "C.1", "small")
+
+	// Many small writes produce many small chunks.
+	for i := 0; i < 100; i++ {
+		fd, err := self.file_store.WriteFile(filename)
+		assert.NoError(self.T(), err)
+		fd.Write(getRows(i*10, i*10+10))
+		fd.Close()
+	}
+
+	before, err := self.delegate.StatFile(filename)
+	assert.NoError(self.T(), err)
+
+	saved, err := self.file_store.Recompress(filename)
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), saved > 0)
+
+	after, err := self.delegate.StatFile(filename)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), before.Size()-saved, after.Size())
+
+	// The content is unchanged.
+	assert.Equal(self.T(), getRows(0, 1000), self.readAll(filename))
+
+	// A second pass does nothing.
+	saved, err = self.file_store.Recompress(filename)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(0), saved)
+}
+
+func TestCompressedFileStore(t *testing.T) {
+	config_obj := config.GetDefaultConfig()
+	delegate := directory.NewDirectoryFileStore(config_obj)
+	file_store := NewCompressedFileStore(delegate)
+
+	suite.Run(t, &CompressedTestSuite{
+		FileStoreTestSuite: tests.NewFileStoreTestSuite(config_obj, file_store),
+		file_store:         file_store,
+		delegate:           delegate,
+		config_obj:         config_obj,
+	})
+}
@@ -0,0 +1,173 @@
+package compressed
+
+import (
+	"errors"
+	"io"
+	"sort"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// Presents the uncompressed data of a compressed file.
+type CompressedReader struct {
+	delegate api.FileReader
+	chunks   []*chunkHeader
+	size     int64
+	offset   int64
+
+	// The amount of the underlying file covered by chunks.
+	physical_size int64
+
+	// The last decompressed chunk.
+	cached_chunk *chunkHeader
+	cached_data  []byte
+}
+
+func newCompressedReader(
+	delegate api.FileReader, physical_size int64) (*CompressedReader, error) {
+	chunks, err := readChunks(delegate, physical_size)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CompressedReader{
+		delegate:      delegate,
+		chunks:        chunks,
+		physical_size: physical_size,
+	}
+
+	if len(chunks) > 0 {
+		result.size = chunks[len(chunks)-1].End()
+	}
+
+	return result, nil
+}
+
+func (self *CompressedReader) getChunk(chunk *chunkHeader) ([]byte, error) {
+	if self.cached_chunk == chunk {
+		return self.cached_data, nil
+	}
+
+	frame := make([]byte, chunk.CompressedLength)
+	err := readAt(self.delegate, frame, chunk.FileOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := decodeFrame(frame, chunk.UncompressedLength)
+	if err != nil {
+		return nil, err
+	}
+
+	self.cached_chunk = chunk
+	self.cached_data = data
+	return data, nil
+}
+
+// Pick up chunks appended since the file was opened.
+func (self *CompressedReader) refresh() error {
+	stat, err := self.delegate.Stat()
+	if err != nil {
+		return err
+	}
+
+	for self.physical_size < stat.Size() {
+		header, err := readHeader(self.delegate, self.physical_size)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			// The chunk is still being written.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if self.physical_size+header.ChunkLength() > stat.Size() {
+			return nil
+		}
+
+		if header.Offset != self.size {
+			return ErrCorrupted
+		}
+
+		self.chunks = append(self.chunks, header)
+		self.size = header.End()
+		self.physical_size += header.ChunkLength()
+	}
+	return nil
+}
+
+func (self *CompressedReader) Read(buff []byte) (int, error) {
+	if self.offset >= self.size {
+		err := self.refresh()
+		if err != nil {
+			return 0, err
+		}
+
+		if self.offset >= self.size {
+			return 0, io.EOF
+		}
+	}
+
+	total := 0
+	for total < len(buff) && self.offset < self.size {
+		// Find the chunk containing the current offset.
+		idx := sort.Search(len(self.chunks), func(i int) bool {
+			return self.chunks[i].End() > self.offset
+		})
+		if idx >= len(self.chunks) {
+			break
+		}
+
+		chunk := self.chunks[idx]
+		data, err := self.getChunk(chunk)
+		if err != nil {
+			return total, err
+		}
+
+		n := copy(buff[total:], data[self.offset-chunk.Offset:])
+		total += n
+		self.offset += int64(n)
+	}
+
+	return total, nil
+}
+
+func (self *CompressedReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		self.offset = offset
+	case io.SeekCurrent:
+		self.offset += offset
+	case io.SeekEnd:
+		self.offset = self.size + offset
+	}
+
+	if self.offset < 0 {
+		self.offset = 0
+		return 0, errors.New("Seek to negative offset")
+	}
+	return self.offset, nil
+}
+
+func (self *CompressedReader) Stat() (api.FileInfo, error) {
+	stat, err := self.delegate.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileInfo{FileInfo: stat, size: self.size}, nil
+}
+
+func (self *CompressedReader) Close() error {
+	return self.delegate.Close()
+}
+
+// Reports the uncompressed size of compressed files.
+type FileInfo struct {
+	api.FileInfo
+	size int64
+}
+
+func (self *FileInfo) Size() int64 {
+	return self.size
+}
@@ -0,0 +1,101 @@
+package compressed
+
+import (
+	"io"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// Rewrite the file into full size chunks if this would improve its
+// compression. Uncompressed files are compressed. Returns the number
+// of bytes saved (which may be negative for incompressible files).
+func (self *CompressedFileStore) Recompress(
+	filename api.FSPathSpec) (int64, error) {
+	if !self.should_compress(filename) {
+		return 0, nil
+	}
+
+	stat, err := self.delegate.StatFile(filename)
+	if err != nil {
+		return 0, err
+	}
+
+	if stat.IsDir() || stat.Size() == 0 {
+		return 0, nil
+	}
+	physical_size := stat.Size()
+
+	reader, err := self.ReadFile(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	compressed_reader, ok := reader.(*CompressedReader)
+	if ok && !needsRecompression(compressed_reader.chunks) {
+		return 0, nil
+	}
+
+	tmp_path := filename.SetType(api.PATH_TYPE_FILESTORE_TMP)
+	fd, err := self.delegate.WriteFile(tmp_path)
+	if err != nil {
+		return 0, err
+	}
+
+	err = fd.Truncate()
+	if err != nil {
+		fd.Close()
+		return 0, err
+	}
+
+	writer := &CompressedWriter{
+		delegate: fd,
+		filename: tmp_path,
+		owner:    self,
+	}
+
+	_, err = io.Copy(writer, reader)
+	if err != nil {
+		writer.Close()
+		_ = self.delegate.Delete(tmp_path)
+		return 0, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		_ = self.delegate.Delete(tmp_path)
+		return 0, err
+	}
+
+	new_stat, err := self.delegate.StatFile(tmp_path)
+	if err != nil {
+		return 0, err
+	}
+
+	// Incompressible files (e.g. uploads of compressed files) are
+	// still replaced so they are not considered again - the chunk
+	// overhead is negligible.
+	err = self.delegate.Move(tmp_path, filename)
+	if err != nil {
+		_ = self.delegate.Delete(tmp_path)
+		return 0, err
+	}
+
+	return physical_size - new_stat.Size(), nil
+}
+
+// Files written incrementally end up with many small chunks which
+// compress poorly.
+func needsRecompression(chunks []*chunkHeader) bool {
+	if len(chunks) < 2 {
+		return false
+	}
+
+	// Only the last chunk may be short.
+	for _, chunk := range chunks[:len(chunks)-1] {
+		if chunk.UncompressedLength < CHUNK_SIZE/2 {
+			return true
+		}
+	}
+	return false
+}
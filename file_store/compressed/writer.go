@@ -0,0 +1,155 @@
+package compressed
+
+import (
+	"io"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// Compresses data as it is flushed to the underlying file.
+type CompressedWriter struct {
+	delegate api.FileWriter
+	filename api.FSPathSpec
+	owner    *CompressedFileStore
+
+	// The file was written before compression was enabled - keep
+	// appending to it uncompressed.
+	plain bool
+
+	// The uncompressed size of the data already written to the
+	// delegate.
+	size int64
+
+	// Data not yet compressed.
+	buffer []byte
+}
+
+func (self *CompressedWriter) Size() (int64, error) {
+	if self.plain {
+		return self.delegate.Size()
+	}
+	return self.size + int64(len(self.buffer)), nil
+}
+
+func (self *CompressedWriter) Write(data []byte) (int, error) {
+	if self.plain {
+		return self.delegate.Write(data)
+	}
+
+	self.buffer = append(self.buffer, data...)
+	if len(self.buffer) >= CHUNK_SIZE {
+		err := self.flushBuffer()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+// Write the buffered data to the delegate as complete chunks.
+func (self *CompressedWriter) flushBuffer() error {
+	for len(self.buffer) > 0 {
+		length := len(self.buffer)
+		if length > CHUNK_SIZE {
+			length = CHUNK_SIZE
+		}
+
+		chunk, err := encodeChunk(self.buffer[:length], self.size)
+		if err != nil {
+			return err
+		}
+
+		_, err = self.delegate.Write(chunk)
+		if err != nil {
+			return err
+		}
+
+		self.size += int64(length)
+		self.buffer = self.buffer[length:]
+	}
+
+	self.buffer = nil
+	return nil
+}
+
+func (self *CompressedWriter) Truncate() error {
+	self.plain = false
+	self.size = 0
+	self.buffer = nil
+
+	return self.delegate.Truncate()
+}
+
+// Compressed data can not be updated in place so the file is
+// rewritten. This is expensive but updates are rare.
+func (self *CompressedWriter) Update(data []byte, offset int64) error {
+	if self.plain {
+		return self.delegate.Update(data, offset)
+	}
+
+	// Appending does not require a rewrite.
+	size, _ := self.Size()
+	if offset == size {
+		_, err := self.Write(data)
+		return err
+	}
+
+	err := self.Flush()
+	if err != nil {
+		return err
+	}
+
+	content, err := self.readAll()
+	if err != nil {
+		return err
+	}
+
+	end := offset + int64(len(data))
+	if end > int64(len(content)) {
+		content = append(content, make([]byte, end-int64(len(content)))...)
+	}
+	copy(content[offset:], data)
+
+	err = self.Truncate()
+	if err != nil {
+		return err
+	}
+
+	_, err = self.Write(content)
+	if err != nil {
+		return err
+	}
+
+	return self.flushBuffer()
+}
+
+func (self *CompressedWriter) readAll() ([]byte, error) {
+	if self.size == 0 {
+		return nil, nil
+	}
+
+	reader, err := self.owner.ReadFile(self.filename)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func (self *CompressedWriter) Flush() error {
+	err := self.flushBuffer()
+	if err != nil {
+		return err
+	}
+	return self.delegate.Flush()
+}
+
+func (self *CompressedWriter) Close() error {
+	err := self.flushBuffer()
+	close_err := self.delegate.Close()
+	if err != nil {
+		return err
+	}
+	return close_err
+}
@@ -10,12 +10,24 @@ import (
 	"sync/atomic"
 
 	"github.com/Velocidex/ordereddict"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
+var (
+	queueBacklogGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "journal_queue_backlog_bytes",
+			Help: "Size of events buffered to disk waiting for slow listeners.",
+		},
+		[]string{"queue"},
+	)
+)
+
 // A listener wraps a channel that our client will listen on. The
 // client will remove events from the channel in its own time and will
 // block waiting for new messages. The sender will send a message to
@@ -60,6 +72,9 @@ type Listener struct {
 	// Name of the file_buffer
 	tmpfile string
 
+	// The backlog size last reported to the queue backlog gauge.
+	reported_backlog int64
+
 	// Listener context.
 	ctx    context.Context
 	cancel func()
@@ -69,7 +84,7 @@ type Listener struct {
 func (self *Listener) Send(item *ordereddict.Dict) {
 	defer utils.CheckForPanic("Listener.Send: %v", item)
 
-	if self.closed {
+	if self.closed || !self.options.Accepts(item) {
 		return
 	}
 
@@ -96,6 +111,7 @@ func (self *Listener) Send(item *ordereddict.Dict) {
 	// No direct delivery available - force buffer file enqueue
 	if self.file_buffer_active {
 		self.file_buffer.Enqueue(item)
+		self._reportBacklog()
 		return
 	}
 
@@ -111,6 +127,7 @@ func (self *Listener) Send(item *ordereddict.Dict) {
 		// file until it is drained.
 	default:
 		self.file_buffer.Enqueue(item)
+		self._reportBacklog()
 
 		// Switch to file buffer mode
 		self._switchToFileMode()
@@ -136,6 +153,26 @@ func (self *Listener) _switchToFileMode() {
 	}
 }
 
+// Update the backlog gauge with the change in our pending size since
+// the last report. Many listeners may watch the same queue so the
+// gauge tracks their total.
+func (self *Listener) _reportBacklog() {
+	if self.file_buffer == nil {
+		return
+	}
+
+	pending := self.file_buffer.PendingSize()
+	if self.closed {
+		pending = 0
+	}
+
+	if pending != self.reported_backlog {
+		queueBacklogGauge.WithLabelValues(self.name).Add(
+			float64(pending - self.reported_backlog))
+		self.reported_backlog = pending
+	}
+}
+
 // Switch from file mode to direct mode. If any messages are in the
 // buffer we drain them too.
 func (self *Listener) _switchToDirectMode() {
@@ -188,6 +225,10 @@ func (self *Listener) Close() {
 		}
 		self.file_buffer.Wg.Wait()
 		self.file_buffer.Close()
+
+		self.mu.Lock()
+		self._reportBacklog()
+		self.mu.Unlock()
 	}
 
 	// Close the output to release our readers.
@@ -212,6 +253,7 @@ func (self *Listener) Debug() *ordereddict.Dict {
 	result := ordereddict.NewDict().
 		Set("BackingFile", self.tmpfile).
 		Set("Name", self.name).
+		Set("Shard", fmt.Sprintf("%v/%v", self.options.Shard, self.options.Shards)).
 		Set("file_buffer_active", self.file_buffer_active).
 		Set("closed", self.closed)
 
@@ -286,6 +328,7 @@ func NewListener(
 
 				self.mu.Lock()
 				items := self.file_buffer.Lease(lease_size)
+				self._reportBacklog()
 				if len(items) == 0 {
 					// Buffer file is empty - reset the trigger and
 					// signal to the Send() function that direct
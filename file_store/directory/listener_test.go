@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Velocidex/ordereddict"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 	"www.velocidex.com/golang/velociraptor/file_store/directory"
@@ -105,3 +106,46 @@ func (self *TestSuite) TestListenerPreserveTypes() {
 	assert.True(self.T(), reflect.DeepEqual(
 		events[0].ToDict(), event_source.ToDict()))
 }
+
+func getBacklogMetric(name string) float64 {
+	gathering, _ := prometheus.DefaultGatherer.Gather()
+	for _, family := range gathering {
+		if family.GetName() != "journal_queue_backlog_bytes" {
+			continue
+		}
+		for _, m := range family.Metric {
+			for _, l := range m.Label {
+				if l.GetName() == "queue" && l.GetValue() == name {
+					return m.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// The size of events waiting for a slow listener is reported.
+func (self *TestSuite) TestListenerBacklogMetric() {
+	listener, err := directory.NewListener(
+		self.ConfigObj, self.Sm.Ctx, "TestListenerBacklog",
+		api.QueueOptions{FileBufferLeaseSize: 1})
+	assert.NoError(self.T(), err)
+
+	// Nothing is reading the events yet so they are buffered.
+	for i := 0; i < 5; i++ {
+		listener.Send(ordereddict.NewDict().Set("X", i))
+	}
+
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		return getBacklogMetric("TestListenerBacklog") > 0
+	})
+
+	// Drain the listener - the backlog should go back to 0.
+	go func() {
+		for range listener.Output() {
+		}
+	}()
+	listener.Close()
+
+	assert.Equal(self.T(), float64(0), getBacklogMetric("TestListenerBacklog"))
+}
@@ -24,9 +24,11 @@ import (
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/compressed"
 	"www.velocidex.com/golang/velociraptor/file_store/directory"
 	"www.velocidex.com/golang/velociraptor/file_store/memcache"
 	"www.velocidex.com/golang/velociraptor/file_store/memory"
+	"www.velocidex.com/golang/velociraptor/file_store/worm"
 )
 
 var (
@@ -63,6 +65,20 @@ func GetFileStore(config_obj *config_proto.Config) api.FileStore {
 }
 
 func getImpl(implementation string,
+	config_obj *config_proto.Config) (api.FileStore, error) {
+	res, err := getBaseImpl(implementation, config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Protect collected evidence from modification.
+	if config_obj.Datastore.WormRetentionSec > 0 {
+		return worm.NewWormFileStore(config_obj, res), nil
+	}
+	return res, nil
+}
+
+func getBaseImpl(implementation string,
 	config_obj *config_proto.Config) (api.FileStore, error) {
 	switch implementation {
 	case "Test":
@@ -72,7 +88,8 @@ func getImpl(implementation string,
 		return memcache.NewMemcacheFileStore(config_obj), nil
 
 	case "FileBaseDataStore", "ReadOnlyDataStore":
-		return directory.NewDirectoryFileStore(config_obj), nil
+		return compressed.MaybeWrap(config_obj,
+			directory.NewDirectoryFileStore(config_obj)), nil
 
 	default:
 		return nil, fmt.Errorf("Unsupported filestore %v", implementation)
@@ -16,6 +16,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/compressed"
 	"www.velocidex.com/golang/velociraptor/file_store/directory"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
@@ -33,7 +34,7 @@ var (
 type MemcacheFileWriter struct {
 	mu sync.Mutex
 
-	delegate  *directory.DirectoryFileStore
+	delegate  api.FileStore
 	key       string
 	filename  api.FSPathSpec
 	truncated bool
@@ -211,7 +212,7 @@ type MemcacheFileStore struct {
 	mu sync.Mutex
 
 	config_obj *config_proto.Config
-	delegate   *directory.DirectoryFileStore
+	delegate   api.FileStore
 
 	data_cache *ttlcache.Cache // map[urn]*MemcacheFileWriter
 
@@ -234,7 +235,8 @@ func NewMemcacheFileStore(config_obj *config_proto.Config) *MemcacheFileStore {
 	}
 
 	result := &MemcacheFileStore{
-		delegate:   directory.NewDirectoryFileStore(config_obj),
+		delegate: compressed.MaybeWrap(config_obj,
+			directory.NewDirectoryFileStore(config_obj)),
 		data_cache: ttlcache.NewCache(),
 		max_age:    time.Duration(max_age) * time.Millisecond,
 		min_age:    time.Duration(ttl) * time.Millisecond,
@@ -31,6 +31,7 @@ type Listener struct {
 	id      int64
 	Channel chan *ordereddict.Dict
 	name    string
+	options api.QueueOptions
 }
 
 type QueuePool struct {
@@ -54,15 +55,17 @@ func (self *QueuePool) GetWatchers() []string {
 	return result
 }
 
-func (self *QueuePool) Register(vfs_path string) (<-chan *ordereddict.Dict, func()) {
+func (self *QueuePool) Register(vfs_path string, options api.QueueOptions) (
+	<-chan *ordereddict.Dict, func()) {
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
 	registrations := self.registrations[vfs_path]
 	new_registration := &Listener{
 		Channel: make(chan *ordereddict.Dict, 1000),
-		id:      time.Now().UnixNano(),
+		id:      int64(utils.GetId()),
 		name:    vfs_path,
+		options: options,
 	}
 	registrations = append(registrations, new_registration)
 
@@ -127,6 +130,10 @@ func (self *QueuePool) BroadcastJsonl(vfs_path string, jsonl []byte) {
 		if err == nil {
 			for _, row := range rows {
 				for _, item := range registrations {
+					if !item.options.Accepts(row) {
+						continue
+					}
+
 					select {
 					case item.Channel <- row:
 					case <-time.After(2 * time.Second):
@@ -145,6 +152,10 @@ func (self *QueuePool) Broadcast(vfs_path string, row *ordereddict.Dict) {
 	registrations := self.getRegistrations(vfs_path)
 	// Ensure we do not hold the lock for very long here.
 	for _, item := range registrations {
+		if !item.options.Accepts(row) {
+			continue
+		}
+
 		select {
 		case item.Channel <- row:
 		case <-time.After(2 * time.Second):
@@ -238,7 +249,10 @@ func (self *MemoryQueueManager) GetWatchers() []string {
 func (self *MemoryQueueManager) Watch(
 	ctx context.Context, queue_name string,
 	queue_options *api.QueueOptions) (output <-chan *ordereddict.Dict, cancel func()) {
-	return self.pool.Register(queue_name)
+	if queue_options == nil {
+		queue_options = &api.QueueOptions{}
+	}
+	return self.pool.Register(queue_name, *queue_options)
 }
 
 func NewMemoryQueueManager(config_obj *config_proto.Config,
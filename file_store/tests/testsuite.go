@@ -119,6 +119,14 @@ func (self *FileStoreTestSuite) TestListChildrenWithTypes() {
 		api.PATH_TYPE_FILESTORE_YAML,
 
 		api.PATH_TYPE_FILESTORE_ANY,
+
+		// Used for columnar result sets
+		api.PATH_TYPE_FILESTORE_COLUMNAR,
+		api.PATH_TYPE_FILESTORE_COLUMNAR_INDEX,
+
+		// Encrypted exports
+		api.PATH_TYPE_FILESTORE_DOWNLOAD_7Z,
+		api.PATH_TYPE_FILESTORE_DOWNLOAD_AGE,
 	} {
 		filename := path_specs.NewSafeFilestorePath(
 			"a", fmt.Sprintf("b%v", idx)).SetType(t)
@@ -0,0 +1,210 @@
+/*
+  A WORM (write once read many) file store.
+
+  The WORM file store wraps another file store and refuses to
+  overwrite or delete collected evidence (results, logs and uploads)
+  until the configured retention window has passed since the file
+  was last written. Files may still be appended to so collections
+  in progress are not affected.
+
+  Derived files (e.g. the sorted and filtered result set caches and
+  the result set indexes) and all other parts of the file store are
+  not protected.
+*/
+
+package worm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	ErrProtected = errors.New("WORM: file is protected from modification")
+)
+
+type Flusher interface {
+	Flush()
+}
+
+type WormFileStore struct {
+	delegate  api.FileStore
+	retention time.Duration
+}
+
+func NewWormFileStore(
+	config_obj *config_proto.Config,
+	delegate api.FileStore) *WormFileStore {
+	return &WormFileStore{
+		delegate: delegate,
+		retention: time.Duration(
+			config_obj.Datastore.WormRetentionSec) * time.Second,
+	}
+}
+
+// Returns an error if the file may not be modified.
+func (self *WormFileStore) checkModify(filename api.FSPathSpec) error {
+	if !IsProtected(filename) {
+		return nil
+	}
+
+	stat, err := self.delegate.StatFile(filename)
+	if err != nil || stat.IsDir() || stat.Size() == 0 {
+		return nil
+	}
+
+	// If the file store can not tell us when the file was written
+	// we can not know when it expires so we protect it.
+	mod_time := stat.ModTime()
+	if mod_time.IsZero() ||
+		utils.GetTime().Now().Sub(mod_time) < self.retention {
+		return fmt.Errorf("%w: %v", ErrProtected, filename.AsClientPath())
+	}
+	return nil
+}
+
+func (self *WormFileStore) ReadFile(
+	filename api.FSPathSpec) (api.FileReader, error) {
+	return self.delegate.ReadFile(filename)
+}
+
+func (self *WormFileStore) WriteFile(
+	filename api.FSPathSpec) (api.FileWriter, error) {
+	return self.WriteFileWithCompletion(filename, utils.BackgroundWriter)
+}
+
+func (self *WormFileStore) WriteFileWithCompletion(
+	filename api.FSPathSpec, completion func()) (api.FileWriter, error) {
+	fd, err := self.delegate.WriteFileWithCompletion(filename, completion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WormFileWriter{
+		FileWriter: fd,
+		filename:   filename,
+		owner:      self,
+	}, nil
+}
+
+func (self *WormFileStore) StatFile(
+	filename api.FSPathSpec) (api.FileInfo, error) {
+	return self.delegate.StatFile(filename)
+}
+
+func (self *WormFileStore) ListDirectory(
+	dirname api.FSPathSpec) ([]api.FileInfo, error) {
+	return self.delegate.ListDirectory(dirname)
+}
+
+func (self *WormFileStore) Delete(filename api.FSPathSpec) error {
+	err := self.checkModify(filename)
+	if err != nil {
+		return err
+	}
+	return self.delegate.Delete(filename)
+}
+
+func (self *WormFileStore) Move(src, dest api.FSPathSpec) error {
+	err := self.checkModify(src)
+	if err != nil {
+		return err
+	}
+
+	err = self.checkModify(dest)
+	if err != nil {
+		return err
+	}
+	return self.delegate.Move(src, dest)
+}
+
+func (self *WormFileStore) Close() error {
+	return self.delegate.Close()
+}
+
+// Pass flushes to the delegate if it needs them.
+func (self *WormFileStore) Flush() {
+	flusher, ok := self.delegate.(Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+// Writers may only append to protected files.
+type WormFileWriter struct {
+	api.FileWriter
+
+	filename api.FSPathSpec
+	owner    *WormFileStore
+}
+
+func (self *WormFileWriter) Truncate() error {
+	size, err := self.FileWriter.Size()
+	if err == nil && size > 0 {
+		err := self.owner.checkModify(self.filename)
+		if err != nil {
+			return err
+		}
+	}
+	return self.FileWriter.Truncate()
+}
+
+func (self *WormFileWriter) Update(data []byte, offset int64) error {
+	size, err := self.FileWriter.Size()
+	if err == nil && offset < size {
+		err := self.owner.checkModify(self.filename)
+		if err != nil {
+			return err
+		}
+	}
+	return self.FileWriter.Update(data, offset)
+}
+
+// Protected files are the results, logs and uploads of client and
+// server collections and event monitoring.
+func IsProtected(filename api.FSPathSpec) bool {
+	if filename.Type() == api.PATH_TYPE_FILESTORE_TMP {
+		return false
+	}
+
+	components := filename.Components()
+	if len(components) == 0 {
+		return false
+	}
+
+	// Result sets cache their transformed copies below the
+	// original file. These are rebuilt on demand.
+	for _, c := range components {
+		if c == "filter" || c == "sorted" || c == "indexes" ||
+			strings.HasPrefix(c, "Range ") {
+			return false
+		}
+	}
+
+	switch components[0] {
+	case "server_artifacts", "server_artifact_logs":
+		return true
+
+	case "clients":
+		if len(components) < 3 {
+			return false
+		}
+
+		switch components[2] {
+		case "artifacts", "monitoring", "monitoring_logs", "vfs_files":
+			return true
+
+		case "collections":
+			// Notebooks attached to collections are not evidence.
+			return len(components) > 4 && components[4] != "notebook"
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package worm
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/tests"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type WormTestSuite struct {
+	*tests.FileStoreTestSuite
+
+	config_obj *config_proto.Config
+	file_store *WormFileStore
+}
+
+func (self *WormTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "file_store_test")
+	assert.NoError(self.T(), err)
+
+	self.config_obj.Datastore.FilestoreDirectory = dir
+	self.config_obj.Datastore.Location = dir
+}
+
+func (self *WormTestSuite) TearDownTest() {
+	os.RemoveAll(self.config_obj.Datastore.FilestoreDirectory)
+}
+
+func (self *WormTestSuite) writeFile(
+	filename api.FSPathSpec, data string) api.FileWriter {
+	fd, err := self.file_store.WriteFile(filename)
+	assert.NoError(self.T(), err)
+
+	_, err = fd.Write([]byte(data))
+	assert.NoError(self.T(), err)
+	assert.NoError(self.T(), fd.Flush())
+
+	return fd
+}
+
+func (self *WormTestSuite) TestProtectedFiles() {
+	results := path_specs.NewUnsafeFilestorePath(
+		"clients", "C.123", "artifacts", "Generic.Client.Info", "F.1234").
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+
+	fd := self.writeFile(results, "{\"A\":1}\n")
+	defer fd.Close()
+
+	// Appending is allowed.
+	_, err := fd.Write([]byte("{\"A\":2}\n"))
+	assert.NoError(self.T(), err)
+
+	// Overwriting is not.
+	err = fd.Update([]byte("X"), 0)
+	assert.True(self.T(), errors.Is(err, ErrProtected))
+
+	err = fd.Truncate()
+	assert.True(self.T(), errors.Is(err, ErrProtected))
+
+	// Neither is deleting or moving it.
+	err = self.file_store.Delete(results)
+	assert.True(self.T(), errors.Is(err, ErrProtected))
+
+	err = self.file_store.Move(results,
+		path_specs.NewUnsafeFilestorePath("temp", "F.1234"))
+	assert.True(self.T(), errors.Is(err, ErrProtected))
+
+	_, err = self.file_store.StatFile(results)
+	assert.NoError(self.T(), err)
+
+	// Once the retention window passes the file may be deleted.
+	closer := utils.MockTime(utils.NewMockClock(
+		time.Now().Add(2 * time.Hour)))
+	defer closer()
+
+	err = self.file_store.Delete(results)
+	assert.NoError(self.T(), err)
+}
+
+func (self *WormTestSuite) TestUnprotectedFiles() {
+	// Files outside collections are not protected.
+	download := path_specs.NewUnsafeFilestorePath(
+		"downloads", "C.123", "F.1234", "export").
+		SetType(api.PATH_TYPE_FILESTORE_DOWNLOAD_ZIP)
+
+	fd := self.writeFile(download, "data")
+	assert.NoError(self.T(), fd.Truncate())
+	fd.Close()
+
+	assert.NoError(self.T(), self.file_store.Delete(download))
+
+	// Neither are the cached transforms of result sets.
+	cache := path_specs.NewUnsafeFilestorePath(
+		"clients", "C.123", "artifacts", "Generic.Client.Info", "F.1234",
+		"sorted", "Col", "asc").
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+
+	fd = self.writeFile(cache, "{\"A\":1}\n")
+	assert.NoError(self.T(), fd.Truncate())
+	fd.Close()
+}
+
+func TestIsProtected(t *testing.T) {
+	for _, c := range []struct {
+		components []string
+		protected  bool
+	}{
+		{[]string{"clients", "C.123", "artifacts", "Art", "F.1"}, true},
+		{[]string{"clients", "C.123", "collections", "F.1", "logs"}, true},
+		{[]string{"clients", "C.123", "collections", "F.1", "uploads",
+			"file", "foo"}, true},
+		{[]string{"clients", "C.123", "monitoring", "Art", "2022-01-01"}, true},
+		{[]string{"server_artifacts", "Art", "2022-01-01"}, true},
+		{[]string{"clients", "C.123", "collections", "F.1", "notebook",
+			"N.1"}, false},
+		{[]string{"clients", "C.123", "flow_index"}, false},
+		{[]string{"clients", "C.123", "artifacts", "Art", "F.1",
+			"filter", "Col", "foo"}, false},
+		{[]string{"clients", "C.123", "artifacts", "Art", "F.1",
+			"indexes", "metadata"}, false},
+		{[]string{"downloads", "C.123", "F.1"}, false},
+		{[]string{"notebooks", "N.1"}, false},
+	} {
+		path := path_specs.NewUnsafeFilestorePath(c.components...)
+		assert.Equal(t, c.protected, IsProtected(path), c.components)
+	}
+}
+
+func TestWormFileStore(t *testing.T) {
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.WormRetentionSec = 3600
+
+	file_store := NewWormFileStore(config_obj,
+		directory.NewDirectoryFileStore(config_obj))
+	suite.Run(t, &WormTestSuite{
+		FileStoreTestSuite: tests.NewFileStoreTestSuite(config_obj, file_store),
+		file_store:         file_store,
+		config_obj:         config_obj,
+	})
+}
@@ -22,8 +22,10 @@ import (
 	"www.velocidex.com/golang/velociraptor/paths"
 	artifact_paths "www.velocidex.com/golang/velociraptor/paths/artifacts"
 	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/server/connections"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/launcher"
+	"www.velocidex.com/golang/velociraptor/tracing"
 	utils "www.velocidex.com/golang/velociraptor/utils"
 )
 
@@ -249,6 +251,20 @@ func (self *ClientFlowRunner) MonitoringVQLResponse(
 func (self *ClientFlowRunner) ProcessSingleMessage(
 	ctx context.Context, msg *crypto_proto.VeloMessage) error {
 
+	ctx, span := tracing.Start(ctx, "flows.ProcessSingleMessage",
+		tracing.String("client_id", msg.Source),
+		tracing.String("flow_id", msg.SessionId),
+		tracing.String("message_type", messageType(msg)))
+	defer span.End()
+
+	err := self.processSingleMessage(ctx, msg)
+	span.RecordError(err)
+	return err
+}
+
+func (self *ClientFlowRunner) processSingleMessage(
+	ctx context.Context, msg *crypto_proto.VeloMessage) error {
+
 	flow_id := msg.SessionId
 	client_id := msg.Source
 
@@ -256,6 +272,11 @@ func (self *ClientFlowRunner) ProcessSingleMessage(
 		return self.ProcessMonitoringMessage(ctx, msg)
 	}
 
+	if msg.ForemanCheckin != nil {
+		return ProcessForemanCheckin(
+			ctx, self.config_obj, client_id, msg.ForemanCheckin)
+	}
+
 	// Should never happen because these are filled in from the crypto
 	// envelope.
 	if flow_id == "" || client_id == "" {
@@ -279,6 +300,9 @@ func (self *ClientFlowRunner) ProcessSingleMessage(
 	}
 
 	if msg.FlowStats != nil {
+		connections.Tracker.FlowStats(
+			client_id, flow_id, msg.FlowStats.FlowComplete)
+
 		err := self.FlowStats(ctx, client_id, flow_id, msg.FlowStats)
 		if err != nil {
 			return fmt.Errorf("FlowStats: %w", err)
@@ -297,6 +321,23 @@ func (self *ClientFlowRunner) ProcessSingleMessage(
 	return nil
 }
 
+// Describe the message for tracing.
+func messageType(msg *crypto_proto.VeloMessage) string {
+	switch {
+	case msg.ForemanCheckin != nil:
+		return "ForemanCheckin"
+	case msg.LogMessage != nil:
+		return "LogMessage"
+	case msg.VQLResponse != nil:
+		return "VQLResponse"
+	case msg.FlowStats != nil:
+		return "FlowStats"
+	case msg.FileBuffer != nil:
+		return "FileBuffer"
+	}
+	return "Unknown"
+}
+
 func (self *ClientFlowRunner) FileBuffer(
 	ctx context.Context, client_id, flow_id string,
 	file_buffer *actions_proto.FileBuffer) error {
@@ -444,6 +485,7 @@ func (self *ClientFlowRunner) FlowStats(
 		TotalLogs:                  msg.TotalLogs,
 		ActiveTime:                 msg.Timestamp,
 		QueryStats:                 msg.QueryStatus,
+		QueuePosition:              msg.QueuePosition,
 	}
 
 	// Deobfuscate artifact names
@@ -3,9 +3,15 @@ package flows
 import (
 	"context"
 
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/actions"
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
 	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 // Process ClientInfo messages. These are processed directly on the
@@ -23,37 +29,77 @@ func (self *ClientFlowRunner) maybeProcessClientInfo(
 		return err
 	}
 
+	// Newer clients only send the sections which changed since their
+	// last update.
+	hashes := client_info.SectionHashes
+	if len(hashes) == 0 {
+		hashes = actions.ClientInfoHashes(&client_info.ClientInfo)
+	}
+
 	// Update client info record if needed
 	client_info_manager, err := services.GetClientInfoManager(self.config_obj)
 	if err != nil {
 		return err
 	}
 
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	changes := ordereddict.NewDict()
+
+	// Set when the client omitted a section we do not have.
+	request_full_update := false
+
 	err = client_info_manager.Modify(ctx, client_id,
 		func(old_client_info *services.ClientInfo) (*services.ClientInfo, error) {
+			// The server lost the record (e.g. the client was
+			// deleted) but the client may still only send a delta.
 			if old_client_info == nil {
-				return client_info, nil
+				old_client_info = &services.ClientInfo{}
+			}
+
+			// Only record the hashes of the sections we actually
+			// have.
+			new_hashes := make(map[string]string)
+			for k, v := range old_client_info.SectionHashes {
+				new_hashes[k] = v
 			}
+			new_hashes[actions.CLIENT_INFO_LABELS] = hashes[actions.CLIENT_INFO_LABELS]
 
+			// Now merge the new sections with the old record
 			dirty := false
-			update := func(old, new *string) {
-				if *old != *new {
-					*old = *new
-					dirty = true
+			old_client_info.ClientId = client_id
+			for _, section := range actions.ClientInfoSections {
+				// Labels are managed by the labeler below.
+				if section.Name == actions.CLIENT_INFO_LABELS {
+					continue
 				}
+
+				if !section.IsIncluded(&client_info.ClientInfo) {
+					old_hash, pres := old_client_info.SectionHashes[section.Name]
+					if !pres || old_hash != hashes[section.Name] {
+						logger.Info("ClientInfo: Client %v did not send its "+
+							"%v section which we do not have, requesting "+
+							"a full update", client_id, section.Name)
+						request_full_update = true
+					}
+					continue
+				}
+
+				new_hashes[section.Name] = hashes[section.Name]
+				if section.Hash(&old_client_info.ClientInfo) == hashes[section.Name] {
+					continue
+				}
+
+				changes.Set(section.Name, ordereddict.NewDict().
+					Set("Old", section.Extract(&old_client_info.ClientInfo)).
+					Set("New", section.Extract(&client_info.ClientInfo)))
+				section.Copy(&old_client_info.ClientInfo, &client_info.ClientInfo)
+				dirty = true
 			}
 
-			// Now merge the new record with the old
-			old_client_info.ClientId = client_id
-			update(&old_client_info.Hostname, &client_info.Hostname)
-			update(&old_client_info.System, &client_info.System)
-			update(&old_client_info.Release, &client_info.Release)
-			update(&old_client_info.Architecture, &client_info.Architecture)
-			update(&old_client_info.Fqdn, &client_info.Fqdn)
-			update(&old_client_info.ClientName, &client_info.ClientName)
-			update(&old_client_info.ClientVersion, &client_info.ClientVersion)
-			update(&old_client_info.BuildUrl, &client_info.BuildUrl)
-			update(&old_client_info.BuildTime, &client_info.BuildTime)
+			if !utils.StringMapEq(old_client_info.SectionHashes, new_hashes) {
+				old_client_info.SectionHashes = new_hashes
+				dirty = true
+			}
 
 			// Nothing to do ignore the update.
 			if !dirty {
@@ -65,7 +111,24 @@ func (self *ClientFlowRunner) maybeProcessClientInfo(
 		return err
 	}
 
-	// Now update any labels baked into the client.
+	if request_full_update {
+		err = client_info_manager.QueueMessageForClient(ctx, client_id,
+			&crypto_proto.VeloMessage{
+				SessionId:         constants.MONITORING_WELL_KNOWN_FLOW,
+				ClientInfoRequest: &crypto_proto.Cancel{},
+			}, services.NOTIFY_CLIENT, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if changes.Len() > 0 {
+		logger.Info("ClientInfo: Client %v changed: %v",
+			client_id, json.MustMarshalString(changes))
+	}
+
+	// Now update any labels baked into the client. Delta updates
+	// only carry the labels when they changed.
 	if len(client_info.Labels) > 0 {
 		labeler := services.GetLabeler(self.config_obj)
 
@@ -0,0 +1,124 @@
+package flows
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"www.velocidex.com/golang/velociraptor/actions"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+func (self *TestSuite) sendClientInfo(runner *ClientFlowRunner, client_id string,
+	client_info *actions_proto.ClientInfo) *services.ClientInfo {
+	err := runner.maybeProcessClientInfo(self.Ctx, client_id,
+		&actions_proto.VQLResponse{
+			JSONLResponse: json.MustMarshalString(client_info),
+			Query: &actions_proto.VQLRequest{
+				Name: "Server.Internal.ClientInfo",
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	record, err := client_info_manager.Get(self.Ctx, client_id)
+	assert.NoError(self.T(), err)
+	return record
+}
+
+func (self *TestSuite) TestClientInfoDelta() {
+	runner := NewFlowRunner(self.Ctx, self.ConfigObj)
+	defer runner.Close(self.Ctx)
+
+	send := func(client_info *actions_proto.ClientInfo) *services.ClientInfo {
+		return self.sendClientInfo(runner, self.client_id, client_info)
+	}
+
+	info := &actions_proto.ClientInfo{
+		Hostname:      "host1",
+		System:        "linux",
+		ClientVersion: "0.1",
+	}
+
+	// A full update from an older client without hashes.
+	record := send(info)
+	assert.Equal(self.T(), "host1", record.Hostname)
+	assert.Equal(self.T(), "0.1", record.ClientVersion)
+	assert.Equal(self.T(), actions.ClientInfoHashes(info), record.SectionHashes)
+
+	// Nothing changed so the update carries no sections.
+	previous := actions.ClientInfoHashes(info)
+	delta := actions.ClientInfoDelta(info, previous)
+	assert.Equal(self.T(), "", delta.Hostname)
+
+	record = send(delta)
+	assert.Equal(self.T(), "host1", record.Hostname)
+	assert.Equal(self.T(), "0.1", record.ClientVersion)
+
+	// Only the changed host section is sent and merged.
+	info.Hostname = "host2"
+	delta = actions.ClientInfoDelta(info, previous)
+	assert.Equal(self.T(), "host2", delta.Hostname)
+	assert.Equal(self.T(), "", delta.ClientVersion)
+
+	record = send(delta)
+	assert.Equal(self.T(), "host2", record.Hostname)
+	assert.Equal(self.T(), "linux", record.System)
+	assert.Equal(self.T(), "0.1", record.ClientVersion)
+	assert.Equal(self.T(), delta.SectionHashes, record.SectionHashes)
+}
+
+// The server has no record of the client (e.g. it was deleted) but
+// the client only sends a delta.
+func (self *TestSuite) TestClientInfoDeltaWithoutRecord() {
+	runner := NewFlowRunner(self.Ctx, self.ConfigObj)
+	defer runner.Close(self.Ctx)
+
+	client_id := "C.DeltaWithoutRecord"
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	info := &actions_proto.ClientInfo{
+		Hostname:      "host1",
+		System:        "linux",
+		ClientVersion: "0.1",
+	}
+
+	// Nothing changed since the client's last update.
+	delta := actions.ClientInfoDelta(info, actions.ClientInfoHashes(info))
+	record := self.sendClientInfo(runner, client_id, delta)
+	assert.Equal(self.T(), "", record.Hostname)
+
+	// The omitted sections are not recorded as known.
+	_, pres := record.SectionHashes[actions.CLIENT_INFO_HOST]
+	assert.False(self.T(), pres)
+
+	// The client is asked for a full update.
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		tasks, err := client_info_manager.PeekClientTasks(self.Ctx, client_id)
+		assert.NoError(self.T(), err)
+		return len(tasks) == 1
+	})
+
+	tasks, err := client_info_manager.GetClientTasks(self.Ctx, client_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(tasks))
+	assert.NotNil(self.T(), tasks[0].ClientInfoRequest)
+
+	// Which the client answers with all sections.
+	record = self.sendClientInfo(runner, client_id,
+		actions.ClientInfoDelta(info, nil))
+	assert.Equal(self.T(), "host1", record.Hostname)
+	assert.Equal(self.T(), "linux", record.System)
+	assert.Equal(self.T(), "0.1", record.ClientVersion)
+	assert.Equal(self.T(), actions.ClientInfoHashes(info), record.SectionHashes)
+
+	// No further requests are needed.
+	tasks, err = client_info_manager.GetClientTasks(self.Ctx, client_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(tasks))
+}
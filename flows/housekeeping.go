@@ -25,9 +25,11 @@ import (
 	errors "github.com/go-errors/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/tracing"
 	utils "www.velocidex.com/golang/velociraptor/utils"
 )
 
@@ -38,6 +40,62 @@ var (
 	})
 )
 
+func sendClientEventTable(
+	ctx context.Context,
+	client_manager services.ClientInfoManager,
+	client_event_manager services.ClientEventTable,
+	config_obj *config_proto.Config,
+	client_id string) error {
+
+	update_message := client_event_manager.GetClientUpdateEventTableDelta(
+		ctx, config_obj, client_id)
+
+	if update_message.UpdateEventTable == nil {
+		return errors.New("Invalid event update")
+	}
+
+	// Inform the client manager that this client will now receive
+	// the latest event table.
+	client_manager.UpdateStats(ctx, client_id, &services.Stats{
+		LastEventTableVersion: update_message.UpdateEventTable.Version,
+	})
+
+	clientEventUpdateCounter.Inc()
+	return client_manager.QueueMessageForClient(
+		ctx, client_id, update_message,
+		services.NOTIFY_CLIENT, utils.BackgroundWriter)
+}
+
+// Clients which were unable to apply a delta event table update
+// need to be sent the full table again.
+func ProcessForemanCheckin(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id string, checkin *actions_proto.ForemanCheckin) error {
+
+	ctx, span := tracing.Start(ctx, "flows.ProcessForemanCheckin",
+		tracing.String("client_id", client_id))
+	defer span.End()
+
+	client_event_manager, err := services.ClientEventManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	if !client_event_manager.ProcessClientCheckin(
+		ctx, config_obj, client_id, checkin) {
+		return nil
+	}
+
+	client_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return sendClientEventTable(ctx, client_manager,
+		client_event_manager, config_obj, client_id)
+}
+
 func CheckClientStatus(
 	ctx context.Context,
 	config_obj *config_proto.Config,
@@ -64,24 +122,8 @@ func CheckClientStatus(
 	if client_event_manager != nil &&
 		client_event_manager.CheckClientEventsVersion(
 			ctx, config_obj, client_id, stats.LastEventTableVersion) {
-
-		update_message := client_event_manager.GetClientUpdateEventTableMessage(
-			ctx, config_obj, client_id)
-
-		if update_message.UpdateEventTable == nil {
-			return errors.New("Invalid event update")
-		}
-
-		// Inform the client manager that this client will now receive
-		// the latest event table.
-		client_manager.UpdateStats(ctx, client_id, &services.Stats{
-			LastEventTableVersion: update_message.UpdateEventTable.Version,
-		})
-
-		clientEventUpdateCounter.Inc()
-		err := client_manager.QueueMessageForClient(
-			ctx, client_id, update_message,
-			services.NOTIFY_CLIENT, utils.BackgroundWriter)
+		err := sendClientEventTable(ctx, client_manager,
+			client_event_manager, config_obj, client_id)
 		if err != nil {
 			return err
 		}
@@ -41,9 +41,21 @@ func checkContextResourceLimits(
 		return errors.New("Invalid context.")
 	}
 
+	max_rows := collection_context.Request.MaxRows
+	max_upload_bytes := collection_context.Request.MaxUploadBytes
+	max_execution_time := uint64(0)
+
+	// The server may impose its own limits on all collections.
+	if config_obj.Frontend != nil && config_obj.Frontend.Resources != nil {
+		resources := config_obj.Frontend.Resources
+		max_rows = minLimit(max_rows, resources.MaxFlowRows)
+		max_upload_bytes = minLimit(
+			max_upload_bytes, resources.MaxFlowUploadBytes)
+		max_execution_time = resources.MaxFlowExecutionTime
+	}
+
 	// We exceeded our total number of rows.
-	if collection_context.Request.MaxRows > 0 &&
-		collection_context.TotalCollectedRows > collection_context.Request.MaxRows {
+	if max_rows > 0 && collection_context.TotalCollectedRows > max_rows {
 		collection_context.State = flows_proto.ArtifactCollectorContext_ERROR
 		collection_context.Status = "Row count exceeded limit"
 		err = cancelCollection(
@@ -52,8 +64,8 @@ func checkContextResourceLimits(
 	}
 
 	// Check for total uploaded bytes.
-	if collection_context.Request.MaxUploadBytes > 0 &&
-		collection_context.TotalUploadedBytes > collection_context.Request.MaxUploadBytes {
+	if max_upload_bytes > 0 &&
+		collection_context.TotalUploadedBytes > max_upload_bytes {
 		collection_context.State = flows_proto.ArtifactCollectorContext_ERROR
 		collection_context.Status = "Collection exceeded upload limits"
 		err = cancelCollection(
@@ -61,9 +73,30 @@ func checkContextResourceLimits(
 			collection_context.SessionId)
 	}
 
+	// Check how long the collection has been running for.
+	if max_execution_time > 0 && collection_context.StartTime > 0 &&
+		collection_context.State == flows_proto.ArtifactCollectorContext_RUNNING {
+		now := uint64(utils.GetTime().Now().UnixNano() / 1000)
+		if now > collection_context.StartTime+max_execution_time*1000000 {
+			collection_context.State = flows_proto.ArtifactCollectorContext_ERROR
+			collection_context.Status = "Collection exceeded execution time limit"
+			err = cancelCollection(
+				ctx, config_obj, collection_context.ClientId,
+				collection_context.SessionId)
+		}
+	}
+
 	return err
 }
 
+// A limit of 0 means unlimited so return the smaller non zero limit.
+func minLimit(a, b uint64) uint64 {
+	if a == 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
 func cancelCollection(
 	ctx context.Context,
 	config_obj *config_proto.Config,
@@ -0,0 +1,110 @@
+package flows
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+func (self *TestSuite) scheduleAndSendRows(
+	request *flows_proto.ArtifactCollectorArgs) (string, func(rows uint64)) {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	repository, err := manager.GetGlobalRepository(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	flow_id, err := launcher.ScheduleArtifactCollection(
+		self.Ctx, self.ConfigObj, acl_managers.NullACLManager{},
+		repository, request, nil)
+	assert.NoError(self.T(), err)
+
+	response_id := uint64(0)
+	return flow_id, func(rows uint64) {
+		response_id++
+		runner := NewLegacyFlowRunner(self.ConfigObj)
+		runner.ProcessSingleMessage(self.Ctx, &crypto_proto.VeloMessage{
+			Source:     self.client_id,
+			SessionId:  flow_id,
+			RequestId:  1,
+			ResponseId: response_id,
+			VQLResponse: &actions_proto.VQLResponse{
+				JSONLResponse: "{}",
+				TotalRows:     rows,
+				Query: &actions_proto.VQLRequest{
+					Name: "Generic.Client.Info/BasicInformation",
+				},
+			},
+		})
+		runner.Close(self.Ctx)
+	}
+}
+
+// The server's limits apply even when the request has none.
+func (self *TestSuite) TestServerResourceLimits() {
+	self.ConfigObj.Frontend.Resources.MaxFlowRows = 3
+	defer func() {
+		self.ConfigObj.Frontend.Resources.MaxFlowRows = 0
+	}()
+
+	flow_id, send := self.scheduleAndSendRows(&flows_proto.ArtifactCollectorArgs{
+		ClientId:  self.client_id,
+		Artifacts: []string{"Generic.Client.Info"},
+	})
+
+	send(2)
+	collection_context, err := LoadCollectionContext(self.Ctx, self.ConfigObj,
+		self.client_id, flow_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), flows_proto.ArtifactCollectorContext_RUNNING,
+		collection_context.State)
+
+	send(2)
+	collection_context, err = LoadCollectionContext(self.Ctx, self.ConfigObj,
+		self.client_id, flow_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), flows_proto.ArtifactCollectorContext_ERROR,
+		collection_context.State)
+	assert.Contains(self.T(), collection_context.Status, "Row count exceeded")
+}
+
+func (self *TestSuite) TestExecutionTimeLimit() {
+	self.ConfigObj.Frontend.Resources.MaxFlowExecutionTime = 60
+	defer func() {
+		self.ConfigObj.Frontend.Resources.MaxFlowExecutionTime = 0
+	}()
+
+	flow_id, send := self.scheduleAndSendRows(&flows_proto.ArtifactCollectorArgs{
+		ClientId:  self.client_id,
+		Artifacts: []string{"Generic.Client.Info"},
+	})
+
+	send(1)
+	collection_context, err := LoadCollectionContext(self.Ctx, self.ConfigObj,
+		self.client_id, flow_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), flows_proto.ArtifactCollectorContext_RUNNING,
+		collection_context.State)
+
+	// Two minutes later the collection is still sending rows.
+	closer := utils.MockTime(utils.NewMockClock(
+		time.Now().Add(2 * time.Minute)))
+	defer closer()
+
+	send(1)
+	collection_context, err = LoadCollectionContext(self.Ctx, self.ConfigObj,
+		self.client_id, flow_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), flows_proto.ArtifactCollectorContext_ERROR,
+		collection_context.State)
+	assert.Contains(self.T(), collection_context.Status,
+		"exceeded execution time limit")
+}
@@ -21,6 +21,8 @@ import ArtifactInspector from './components/artifacts/artifacts.jsx';
 import UserInspector from './components/users/user-inspector.jsx';
 import VeloHunts from './components/hunts/hunts.jsx';
 import UserDashboard from './components/sidebar/user-dashboard.jsx';
+import Canaries from './components/canaries/canaries.jsx';
+import AssetInventory from './components/asset-inventory/inventory.jsx';
 import UserLabel from './components/users/user-label.jsx';
 import EventMonitoring from './components/events/events.jsx';
 import SnackbarProvider from 'react-simple-snackbar';
@@ -150,6 +152,8 @@ class App extends Component {
                        <ArtifactInspector client={this.state.client}/>
                      </Route>
                      <Route path="/users/:user?" component={UserInspector}/>
+                     <Route path="/canaries" component={Canaries}/>
+                     <Route path="/inventory" component={AssetInventory}/>
                      <Route path="/hunts/:hunt_id?/:tab?">
                        <VeloHunts/>
                      </Route>
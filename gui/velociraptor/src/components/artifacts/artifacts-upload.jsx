@@ -225,7 +225,7 @@ export default class ArtifactsUpload extends React.Component {
                 <Button variant="secondary"
                         disabled={_.isEmpty(this.state.uploaded)}
                         onClick={()=>this.importFile()}>
-                  {T("Import Artifacts", this.state.uploaded && this.state.uploaded.length)}
+                  {T("Import Artifacts", _.size(this.state.uploaded))}
                 </Button>
               </Modal.Footer>
             </Modal>
@@ -354,40 +354,116 @@ export default class Completer {
     };
 
 
-    initializeAceEditor = (ace, options) => {
-        // create a completer object with a required callback function:
-        var vqlCompleter = {
-            identifierRegexps: [/[a-zA-Z_0-9.?$\-\u00A2-\uFFFF]/],
+    // Convert the language server's completions to ace completions.
+    convertCompletions = (items, prefix) => {
+        let scores = {
+            argument: 1000, column: 900, variable: 800, plugin: 700,
+            function: 500, artifact: 100, keyword: 50,
+        };
+        let completions = this.getLocalCompletions(prefix);
+        _.each(items, item=>{
+            let html = null;
+            if (item.documentation) {
+                html = '<div class="arg-help">' +
+                    escapeHTML(item.documentation) + "</div>";
+            }
+            completions.push({
+                caption: item.label,
+                description: item.detail || null,
+                snippet: item.insert_text || item.label,
+                value: item.label,
+                score: scores[item.kind] || 0,
+                meta: item.detail || item.kind,
+                docHTML: html,
+            });
+        });
+        return completions;
+    };
 
-            getCompletions: (editor, session, pos, prefix, callback) => {
-                var previous_rows = session.doc.getAllLines().slice(0, pos.row+1);
-                var last_idx = previous_rows.length-1;
-                previous_rows[last_idx] = previous_rows[last_idx].slice(
-                    0, pos.column - prefix.length);
+    // Guess completions locally for other editors or when the
+    // language server is not available.
+    guessCompletions = (session, pos, prefix) => {
+        var previous_rows = session.doc.getAllLines().slice(0, pos.row+1);
+        var last_idx = previous_rows.length-1;
+        previous_rows[last_idx] = previous_rows[last_idx].slice(
+            0, pos.column - prefix.length);
+
+        var previous = previous_rows.join("");
+        var context = this.guessContext(previous, prefix);
+
+        // Do not complete inside a string.
+        if (context.context === "string") {
+            return [];
+
+        } else if (context.context === "plugin") {
+            return this.getPluginCompletions(prefix).concat(
+                this.getArtifactCompletions(prefix));
 
-                var previous = previous_rows.join("");
-                var context = this.guessContext(previous, prefix);
+        } else if (context.context === "plugin_args") {
+            return this.getPluginArgsCompletions(context.name, prefix).concat(
+                this.getFunctionCompletions(prefix));
 
-                // Do not complete inside a string.
-                if (context.context === "string") {
-                    callback(null, []);
+        } else if (context.context === "function_args") {
+            return this.getFunctionArgsCompletions(context.name, prefix).concat(
+                this.getFunctionCompletions(prefix));
+        }
+
+        return this.getKeywordCompletions(prefix).concat(
+            this.getFunctionCompletions(prefix));
+    };
 
-                } else if (context.context === "plugin") {
-                    callback(null, this.getPluginCompletions(prefix).concat(
-                        this.getArtifactCompletions(prefix)));
+    isVQL = (session) => {
+        return session.getMode() instanceof VqlMode;
+    };
 
-                } else if (context.context === "plugin_args") {
-                    callback(null, this.getPluginArgsCompletions(context.name, prefix).concat(
-                        this.getFunctionCompletions(prefix)));
+    // Show syntax errors and unknown names reported by the language
+    // server in the editor's gutter.
+    diagnose = _.debounce((session) => {
+        if (!this.isVQL(session)) {
+            session.clearAnnotations();
+            return;
+        }
 
-                } else if (context.context === "function_args") {
-                    callback(null, this.getFunctionArgsCompletions(context.name, prefix).concat(
-                        this.getFunctionCompletions(prefix)));
+        api.post('v1/VQLLanguageServer', {
+            method: "diagnose",
+            query: session.getValue(),
+        }).then(response=>{
+            if (response.cancel) return;
+
+            session.setAnnotations(_.map(
+                response.data.diagnostics, d=>({
+                    row: d.line,
+                    column: d.column,
+                    text: d.message,
+                    type: d.severity === "error" ? "error" : "warning",
+                })));
+        }).catch(()=>{});
+    }, 1000);
 
-                } else {
-                    callback(null, this.getKeywordCompletions(prefix).concat(
-                        this.getFunctionCompletions(prefix)));
+    initializeAceEditor = (ace, options) => {
+        // create a completer object with a required callback function:
+        var vqlCompleter = {
+            identifierRegexps: [/[a-zA-Z_0-9.?$\-\u00A2-\uFFFF]/],
+
+            getCompletions: (editor, session, pos, prefix, callback) => {
+                // A ? lists everything so is handled locally.
+                if (!this.isVQL(session) || prefix.startsWith("?")) {
+                    callback(null, this.guessCompletions(session, pos, prefix));
+                    return;
                 }
+
+                api.post('v1/VQLLanguageServer', {
+                    method: "complete",
+                    query: session.getValue(),
+                    line: pos.row,
+                    column: pos.column,
+                }).then(response=>{
+                    if (response.cancel) return;
+                    callback(null, this.convertCompletions(
+                        response.data.completions, prefix));
+                }).catch(()=>{
+                    callback(null, this.guessCompletions(session, pos, prefix));
+                });
             }
         };
 
@@ -396,6 +472,10 @@ export default class Completer {
             VqlMode.setCompletions(this.state.completions);
         });
 
+        if (options.diagnostics) {
+            ace.session.on("change", ()=>this.diagnose(ace.session));
+        }
+
         // finally, bind to langTools:
         language_tools.setCompleters();
         language_tools.addCompleter(vqlCompleter);
@@ -0,0 +1,293 @@
+import "./inventory.css";
+import PropTypes from 'prop-types';
+
+import React from 'react';
+
+import _ from 'lodash';
+import Navbar from 'react-bootstrap/Navbar';
+import Button from 'react-bootstrap/Button';
+import ButtonGroup from 'react-bootstrap/ButtonGroup';
+import Form from 'react-bootstrap/Form';
+import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
+import VeloTable from '../core/table.jsx';
+import VeloTimestamp from '../utils/time.jsx';
+import ClientLink from '../clients/client-link.jsx';
+import T from '../i8n/i8n.jsx';
+import api from '../core/api-service.jsx';
+import {CancelToken} from 'axios';
+
+import { withRouter }  from "react-router-dom";
+
+const CATEGORIES = ["Software", "Patches", "Users",
+                    "ListeningServices", "Hardware"];
+
+const OPERATORS = ["", "<", "<=", "=", "!=", ">=", ">"];
+
+// Search the inventory of all clients (e.g. for hosts with OpenSSL <
+// 3.0.7) and show the inventory of the selected client.
+class AssetInventory extends React.Component {
+    static propTypes = {
+        // React router props.
+        history: PropTypes.object,
+    };
+
+    state = {
+        category: "Software",
+        name: "",
+        op: "",
+        version: "",
+        results: [],
+        searched: false,
+        error: "",
+
+        client_id: "",
+        client_category: "Software",
+        inventory: null,
+        rows: [],
+        diff: false,
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+        this.client_source = CancelToken.source();
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+        this.client_source.cancel("unmounted");
+    }
+
+    search = e => {
+        if (e) {
+            e.preventDefault();
+        }
+
+        this.source.cancel();
+        this.source = CancelToken.source();
+
+        api.get("v1/AssetInventorySearch", {
+            category: this.state.category,
+            name: this.state.name,
+            op: this.state.op,
+            version: this.state.version,
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+            this.setState({results: response.data.rows || [],
+                           searched: true, error: ""});
+        }).catch(err=>{
+            let message = err.response && err.response.data;
+            this.setState({results: [], error: String(message || err)});
+        });
+    }
+
+    fetchClient = (client_id, category, diff) => {
+        this.client_source.cancel();
+        this.client_source = CancelToken.source();
+
+        let params = {client_id: client_id, category: category};
+        if (diff) {
+            params.diff = "1";
+        }
+
+        api.get("v1/AssetInventory", params,
+                this.client_source.token).then(response=>{
+            if (response.cancel) return;
+            this.setState({inventory: response.data.inventory,
+                           rows: response.data.rows || []});
+        }).catch(()=>{
+            this.setState({inventory: null, rows: []});
+        });
+    }
+
+    selectClient = client_id => {
+        this.setState({client_id: client_id, inventory: null, rows: []});
+        this.fetchClient(client_id, this.state.client_category,
+                         this.state.diff);
+    }
+
+    refreshClient = () => {
+        api.post("v1/AssetInventory", {
+            client_id: this.state.client_id,
+        }, this.client_source.token).then(response=>{
+            if (response.cancel) return;
+            this.fetchClient(this.state.client_id,
+                             this.state.client_category, this.state.diff);
+        });
+    }
+
+    renderResults = () => {
+        if (this.state.error) {
+            return <div className="no-content">{this.state.error}</div>;
+        }
+
+        if (_.isEmpty(this.state.results)) {
+            return this.state.searched &&
+                <div className="no-content">
+                  {T("No matching clients.")}
+                </div>;
+        }
+
+        return <VeloTable
+                 rows={this.state.results}
+                 columns={["client_id", "hostname", "category",
+                           "name", "version"]}
+                 headers={{
+                     client_id: T("Client ID"),
+                     hostname: T("Hostname"),
+                     category: T("Category"),
+                     name: T("Name"),
+                     version: T("Version"),
+                 }}
+                 renderers={{
+                     client_id: (cell, row) => <ClientLink client_id={cell}/>,
+                     hostname: (cell, row) => <Button
+                       variant="link"
+                       onClick={()=>this.selectClient(row.client_id)}>
+                                                {cell || row.client_id}
+                                              </Button>,
+                 }}
+               />;
+    }
+
+    renderVersions = () => {
+        let versions = (this.state.inventory &&
+                        this.state.inventory.versions) || [];
+        let latest = _.last(versions);
+        return (
+            <dl className="row">
+              <dt className="col-2">{T("Versions")}</dt>
+              <dd className="col-10">{versions.length}</dd>
+              <dt className="col-2">{T("Last updated")}</dt>
+              <dd className="col-10">
+                { latest &&
+                  <VeloTimestamp usec={latest.timestamp}/> }
+              </dd>
+              { this.state.inventory && this.state.inventory.pending_flow_id &&
+                <>
+                  <dt className="col-2">{T("Pending collection")}</dt>
+                  <dd className="col-10">
+                    {this.state.inventory.pending_flow_id}
+                  </dd>
+                </>
+              }
+            </dl>
+        );
+    }
+
+    renderClient = () => {
+        if (!this.state.client_id) {
+            return null;
+        }
+
+        return (
+            <div className="inventory-client">
+              <h5>
+                <ClientLink client_id={this.state.client_id}/>
+              </h5>
+              <Navbar className="toolbar">
+                <ButtonGroup>
+                  { _.map(CATEGORIES, x=>{
+                      return <Button key={x} variant="default"
+                                     active={x === this.state.client_category}
+                                     onClick={()=>{
+                                         this.setState({client_category: x});
+                                         this.fetchClient(this.state.client_id,
+                                                          x, this.state.diff);
+                                     }}>
+                               {T(x)}
+                             </Button>;
+                  })}
+                </ButtonGroup>
+                <ButtonGroup>
+                  <Button variant="default"
+                          data-position="left"
+                          className="btn-tooltip"
+                          data-tooltip={T("Show changes since the previous version")}
+                          active={this.state.diff}
+                          onClick={()=>{
+                              let diff = !this.state.diff;
+                              this.setState({diff: diff});
+                              this.fetchClient(this.state.client_id,
+                                               this.state.client_category, diff);
+                          }}>
+                    <FontAwesomeIcon icon="code-compare"/>
+                  </Button>
+                  <Button variant="default"
+                          data-position="left"
+                          className="btn-tooltip"
+                          data-tooltip={T("Collect a new inventory")}
+                          onClick={this.refreshClient}>
+                    <FontAwesomeIcon icon="sync"/>
+                  </Button>
+                </ButtonGroup>
+              </Navbar>
+              { this.renderVersions() }
+              { _.isEmpty(this.state.rows) ?
+                <div className="no-content">
+                  {T("No inventory rows.")}
+                </div> :
+                <VeloTable rows={this.state.rows}
+                           columns={_.keys(this.state.rows[0])}/> }
+            </div>
+        );
+    }
+
+    render() {
+        return (
+            <div className="asset-inventory">
+              <Form onSubmit={this.search}>
+                <Form.Row>
+                  <Form.Group className="col-2">
+                    <Form.Label>{T("Category")}</Form.Label>
+                    <Form.Control as="select"
+                                  value={this.state.category}
+                                  onChange={e=>this.setState({
+                                      category: e.currentTarget.value})}>
+                      <option value="">{T("All")}</option>
+                      { _.map(CATEGORIES, x=>{
+                          return <option key={x} value={x}>{T(x)}</option>;
+                      })}
+                    </Form.Control>
+                  </Form.Group>
+                  <Form.Group className="col-4">
+                    <Form.Label>{T("Name")}</Form.Label>
+                    <Form.Control type="text"
+                                  placeholder={T("Name regex, e.g. ^openssl$")}
+                                  value={this.state.name}
+                                  onChange={e=>this.setState({
+                                      name: e.currentTarget.value})}/>
+                  </Form.Group>
+                  <Form.Group className="col-1">
+                    <Form.Label>{T("Version")}</Form.Label>
+                    <Form.Control as="select"
+                                  value={this.state.op}
+                                  onChange={e=>this.setState({
+                                      op: e.currentTarget.value})}>
+                      { _.map(OPERATORS, x=>{
+                          return <option key={x} value={x}>{x}</option>;
+                      })}
+                    </Form.Control>
+                  </Form.Group>
+                  <Form.Group className="col-3">
+                    <Form.Label>&nbsp;</Form.Label>
+                    <Form.Control type="text"
+                                  placeholder={T("e.g. 3.0.7")}
+                                  value={this.state.version}
+                                  onChange={e=>this.setState({
+                                      version: e.currentTarget.value})}/>
+                  </Form.Group>
+                  <Form.Group className="col-2 inventory-search-button">
+                    <Button variant="primary" type="submit">
+                      <FontAwesomeIcon icon="search"/> {T("Search")}
+                    </Button>
+                  </Form.Group>
+                </Form.Row>
+              </Form>
+              { this.renderResults() }
+              { this.renderClient() }
+            </div>
+        );
+    }
+};
+
+export default withRouter(AssetInventory);
@@ -0,0 +1,299 @@
+import "./canaries.css";
+import PropTypes from 'prop-types';
+
+import React from 'react';
+
+import _ from 'lodash';
+import Navbar from 'react-bootstrap/Navbar';
+import Button from 'react-bootstrap/Button';
+import ButtonGroup from 'react-bootstrap/ButtonGroup';
+import Table from 'react-bootstrap/Table';
+import Modal from 'react-bootstrap/Modal';
+import Form from 'react-bootstrap/Form';
+import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
+import VeloTable from '../core/table.jsx';
+import VeloTimestamp from '../utils/time.jsx';
+import ClientLink from '../clients/client-link.jsx';
+import { NewCanaryDialog, DeployCanaryDialog } from './canary-dialogs.jsx';
+import T from '../i8n/i8n.jsx';
+import api from '../core/api-service.jsx';
+import {CancelToken} from 'axios';
+
+import { withRouter }  from "react-router-dom";
+
+const POLL_TIME = 10000;
+
+// Confirm deleting a canary, optionally removing it from the
+// endpoints.
+class DeleteCanaryDialog extends React.Component {
+    static propTypes = {
+        canary: PropTypes.object.isRequired,
+        onClose: PropTypes.func.isRequired,
+        onDelete: PropTypes.func.isRequired,
+    };
+
+    state = {
+        remove: true,
+    }
+
+    render() {
+        return (
+            <Modal show={true}
+                   onHide={this.props.onClose}>
+              <Modal.Header closeButton>
+                <Modal.Title>{T("Delete Canary")}</Modal.Title>
+              </Modal.Header>
+              <Modal.Body>
+                <p>
+                  {T("Delete canary")} {this.props.canary.name}
+                  {" "}({this.props.canary.id})
+                </p>
+                <Form.Check
+                  type="checkbox"
+                  checked={this.state.remove}
+                  label={T("Also remove the canary from the clients")}
+                  onChange={e=>this.setState({
+                      remove: e.currentTarget.checked})} />
+              </Modal.Body>
+              <Modal.Footer>
+                <Button variant="secondary"
+                        onClick={this.props.onClose}>
+                  {T("Close")}
+                </Button>
+                <Button variant="primary"
+                        onClick={()=>this.props.onDelete(this.state.remove)}>
+                  {T("Delete")}
+                </Button>
+              </Modal.Footer>
+            </Modal>
+        );
+    }
+}
+
+// Manage canary tokens and show the hit history of the selected
+// canary.
+class Canaries extends React.Component {
+    static propTypes = {
+        // React router props.
+        history: PropTypes.object,
+    };
+
+    state = {
+        canaries: [],
+        selected_id: "",
+        hits: [],
+        showNewDialog: false,
+        showDeployDialog: false,
+        showDeleteDialog: false,
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+        this.hits_source = CancelToken.source();
+        this.interval = setInterval(this.fetchCanaries, POLL_TIME);
+        this.fetchCanaries();
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+        this.hits_source.cancel("unmounted");
+        clearInterval(this.interval);
+    }
+
+    fetchCanaries = () => {
+        api.get("v1/Canaries", {}, this.source.token).then(response=>{
+            if (response.cancel) return;
+
+            let canaries = response.data.canaries || [];
+            this.setState({canaries: canaries});
+
+            // Refresh the hits when the selected canary was hit.
+            let selected = this.getSelected();
+            if (selected && selected.hits !== this.state.hits.length) {
+                this.fetchHits(selected.id);
+            }
+        });
+    }
+
+    fetchHits = id => {
+        this.hits_source.cancel();
+        this.hits_source = CancelToken.source();
+
+        api.get("v1/CanaryHits", {id: id},
+                this.hits_source.token).then(response=>{
+            if (response.cancel) return;
+            this.setState({hits: response.data.rows || []});
+        });
+    }
+
+    getSelected = () => {
+        return _.find(this.state.canaries,
+                      x=>x.id === this.state.selected_id);
+    }
+
+    selectCanary = id => {
+        this.setState({selected_id: id, hits: []});
+        this.fetchHits(id);
+    }
+
+    deleteCanary = remove => {
+        let selected = this.getSelected();
+        if (!selected) {
+            return;
+        }
+
+        api.post("v1/Canaries", {
+            id: selected.id,
+            delete: true,
+            remove: remove,
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+            this.setState({showDeleteDialog: false,
+                           selected_id: "", hits: []});
+            this.fetchCanaries();
+        });
+    }
+
+    renderHits = () => {
+        if (_.isEmpty(this.state.hits)) {
+            return <div className="no-content">
+                     {T("This canary has not been accessed.")}
+                   </div>;
+        }
+
+        return <VeloTable
+                 rows={this.state.hits}
+                 columns={["Timestamp", "ClientId", "Path",
+                           "User", "Process", "Details"]}
+                 renderers={{
+                     Timestamp: (cell, row) => <VeloTimestamp usec={cell}/>,
+                     ClientId: (cell, row) => <ClientLink client_id={cell}/>,
+                 }}
+               />;
+    }
+
+    renderDetails = selected => {
+        return (
+            <div className="canary-details">
+              <h5>{selected.name}</h5>
+              { selected.description &&
+                <p>{selected.description}</p> }
+              <dl className="row">
+                <dt className="col-2">{T("Path")}</dt>
+                <dd className="col-10">{selected.path}</dd>
+                <dt className="col-2">{T("Deployed to")}</dt>
+                <dd className="col-10">
+                  { _.map(selected.clients, (x, idx)=>{
+                      return <span key={idx} className="canary-client">
+                               <ClientLink client_id={x}/>
+                             </span>;
+                  })}
+                </dd>
+              </dl>
+              <h6>{T("Hit History")}</h6>
+              { this.renderHits() }
+            </div>
+        );
+    }
+
+    render() {
+        let selected = this.getSelected();
+        return (
+            <>
+              { this.state.showNewDialog &&
+                <NewCanaryDialog
+                  onClose={()=>this.setState({showNewDialog: false})}
+                  onCreate={canary=>{
+                      this.setState({showNewDialog: false});
+                      this.fetchCanaries();
+                      this.selectCanary(canary.id);
+                  }}
+                />
+              }
+              { this.state.showDeployDialog && selected &&
+                <DeployCanaryDialog
+                  canary={selected}
+                  onClose={()=>this.setState({showDeployDialog: false})}
+                  onDeploy={()=>{
+                      this.setState({showDeployDialog: false});
+                      this.fetchCanaries();
+                  }}
+                />
+              }
+              { this.state.showDeleteDialog && selected &&
+                <DeleteCanaryDialog
+                  canary={selected}
+                  onClose={()=>this.setState({showDeleteDialog: false})}
+                  onDelete={this.deleteCanary}
+                />
+              }
+              <Navbar className="toolbar">
+                <ButtonGroup>
+                  <Button variant="default"
+                          data-position="right"
+                          className="btn-tooltip"
+                          data-tooltip={T("New canary")}
+                          onClick={()=>this.setState({showNewDialog: true})} >
+                    <FontAwesomeIcon icon="plus"/>
+                  </Button>
+
+                  <Button variant="default"
+                          data-position="right"
+                          className="btn-tooltip"
+                          data-tooltip={T("Deploy canary to clients")}
+                          disabled={!selected}
+                          onClick={()=>this.setState({showDeployDialog: true})} >
+                    <FontAwesomeIcon icon="paper-plane"/>
+                  </Button>
+
+                  <Button variant="default"
+                          data-position="right"
+                          className="btn-tooltip"
+                          data-tooltip={T("Delete canary")}
+                          disabled={!selected}
+                          onClick={()=>this.setState({showDeleteDialog: true})} >
+                    <FontAwesomeIcon icon="trash"/>
+                  </Button>
+                </ButtonGroup>
+              </Navbar>
+              <div className="canaries">
+                <Table bordered hover size="sm">
+                  <thead>
+                    <tr>
+                      <th>{T("Name")}</th>
+                      <th>{T("Type")}</th>
+                      <th>{T("Path")}</th>
+                      <th>{T("Clients")}</th>
+                      <th>{T("Hits")}</th>
+                      <th>{T("Last Hit")}</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    { _.map(this.state.canaries, (x, idx)=>{
+                        return <tr key={idx}
+                                   className={x.id === this.state.selected_id ?
+                                              "row-selected" : undefined}
+                                   onClick={()=>this.selectCanary(x.id)}>
+                                 <td>{x.name}</td>
+                                 <td>{x.type}</td>
+                                 <td>{x.path}</td>
+                                 <td>{(x.clients || []).length}</td>
+                                 <td className={x.hits > 0 ? "canary-hit" : undefined}>
+                                   {x.hits}
+                                 </td>
+                                 <td>
+                                   { x.last_hit > 0 &&
+                                     <VeloTimestamp usec={x.last_hit}/> }
+                                 </td>
+                               </tr>;
+                    })}
+                  </tbody>
+                </Table>
+                { selected && this.renderDetails(selected) }
+              </div>
+            </>
+        );
+    }
+};
+
+export default withRouter(Canaries);
@@ -0,0 +1,222 @@
+import React from 'react';
+import PropTypes from 'prop-types';
+
+import _ from 'lodash';
+import Modal from 'react-bootstrap/Modal';
+import Button from 'react-bootstrap/Button';
+import Form from 'react-bootstrap/Form';
+import Row from 'react-bootstrap/Row';
+import Col from 'react-bootstrap/Col';
+import T from '../i8n/i8n.jsx';
+import api from '../core/api-service.jsx';
+import {CancelToken} from 'axios';
+
+const canary_types = [
+    {type: "file", desc: "Decoy File",
+     placeholder: "C:\\Users\\Public\\Documents\\passwords.xlsx"},
+    {type: "registry", desc: "Registry Value",
+     placeholder: "HKEY_LOCAL_MACHINE\\SOFTWARE\\Backup\\AdminPassword"},
+    {type: "credential", desc: "Fake Credentials",
+     placeholder: "C:\\Users\\Public\\.aws\\credentials"},
+];
+
+// Create a new canary. The canary is only planted on endpoints once
+// it is deployed.
+export class NewCanaryDialog extends React.Component {
+    static propTypes = {
+        onClose: PropTypes.func.isRequired,
+
+        // Called with the new canary.
+        onCreate: PropTypes.func.isRequired,
+    };
+
+    state = {
+        canary: {type: "file"},
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+    }
+
+    setField = (field, value) => {
+        let canary = Object.assign({}, this.state.canary);
+        canary[field] = value;
+        this.setState({canary: canary});
+    }
+
+    createCanary = () => {
+        api.post("v1/Canaries", {
+            create: this.state.canary,
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+            this.props.onCreate(response.data);
+        });
+    }
+
+    render() {
+        let canary = this.state.canary;
+        let type_desc = _.find(canary_types, x=>x.type === canary.type) || {};
+        return (
+            <Modal show={true}
+                   size="lg"
+                   enforceFocus={false}
+                   onHide={this.props.onClose}>
+              <Modal.Header closeButton>
+                <Modal.Title>{T("New Canary")}</Modal.Title>
+              </Modal.Header>
+              <Modal.Body>
+                <Form>
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Name")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Control
+                        value={canary.name || ""}
+                        onChange={e=>this.setField(
+                            "name", e.currentTarget.value)} />
+                    </Col>
+                  </Form.Group>
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Description")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Control
+                        as="textarea" rows={2}
+                        value={canary.description || ""}
+                        onChange={e=>this.setField(
+                            "description", e.currentTarget.value)} />
+                    </Col>
+                  </Form.Group>
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Type")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Control
+                        as="select"
+                        value={canary.type}
+                        onChange={e=>this.setField(
+                            "type", e.currentTarget.value)}>
+                        { _.map(canary_types, x=>{
+                            return <option key={x.type} value={x.type}>
+                                     {T(x.desc)}
+                                   </option>;
+                        })}
+                      </Form.Control>
+                    </Col>
+                  </Form.Group>
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Path")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Control
+                        placeholder={type_desc.placeholder}
+                        value={canary.path || ""}
+                        onChange={e=>this.setField(
+                            "path", e.currentTarget.value)} />
+                    </Col>
+                  </Form.Group>
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Content")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Control
+                        as="textarea" rows={4}
+                        placeholder={canary.type === "credential" ?
+                                     T("Leave empty to generate fake credentials") : ""}
+                        value={canary.value || ""}
+                        onChange={e=>this.setField(
+                            "value", e.currentTarget.value)} />
+                    </Col>
+                  </Form.Group>
+                </Form>
+              </Modal.Body>
+              <Modal.Footer>
+                <Button variant="secondary"
+                        onClick={this.props.onClose}>
+                  {T("Close")}
+                </Button>
+                <Button variant="primary"
+                        disabled={!canary.path}
+                        onClick={this.createCanary}>
+                  {T("Create")}
+                </Button>
+              </Modal.Footer>
+            </Modal>
+        );
+    }
+}
+
+// Deploy a canary to a list of clients.
+export class DeployCanaryDialog extends React.Component {
+    static propTypes = {
+        canary: PropTypes.object.isRequired,
+        onClose: PropTypes.func.isRequired,
+        onDeploy: PropTypes.func.isRequired,
+    };
+
+    state = {
+        clients: "",
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+    }
+
+    getClients = () => {
+        return _.filter(_.map(this.state.clients.split(/[\s,]+/), _.trim));
+    }
+
+    deployCanary = () => {
+        api.post("v1/Canaries", {
+            id: this.props.canary.id,
+            deploy: this.getClients(),
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+            this.props.onDeploy(response.data);
+        });
+    }
+
+    render() {
+        return (
+            <Modal show={true}
+                   size="lg"
+                   enforceFocus={false}
+                   onHide={this.props.onClose}>
+              <Modal.Header closeButton>
+                <Modal.Title>
+                  {T("Deploy Canary")} {this.props.canary.name}
+                </Modal.Title>
+              </Modal.Header>
+              <Modal.Body>
+                <Form>
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Client IDs")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Control
+                        as="textarea" rows={4}
+                        placeholder="C.1234567890abcdef"
+                        value={this.state.clients}
+                        onChange={e=>this.setState({
+                            clients: e.currentTarget.value})} />
+                    </Col>
+                  </Form.Group>
+                </Form>
+              </Modal.Body>
+              <Modal.Footer>
+                <Button variant="secondary"
+                        onClick={this.props.onClose}>
+                  {T("Close")}
+                </Button>
+                <Button variant="primary"
+                        disabled={_.isEmpty(this.getClients())}
+                        onClick={this.deployCanary}>
+                  {T("Deploy")}
+                </Button>
+              </Modal.Footer>
+            </Modal>
+        );
+    }
+}
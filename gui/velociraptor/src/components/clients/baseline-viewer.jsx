@@ -0,0 +1,234 @@
+import "./baseline-viewer.css";
+
+import React, { Component } from 'react';
+import PropTypes from 'prop-types';
+
+import _ from 'lodash';
+import Button from 'react-bootstrap/Button';
+import ButtonGroup from 'react-bootstrap/ButtonGroup';
+import Table from 'react-bootstrap/Table';
+import Form from 'react-bootstrap/Form';
+import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
+import VeloTable from '../core/table.jsx';
+import VeloTimestamp from '../utils/time.jsx';
+import T from '../i8n/i8n.jsx';
+import api from '../core/api-service.jsx';
+import {CancelToken} from 'axios';
+
+
+// Shows the client's baselines and the drift of the latest
+// collection of the selected artifact since the baseline.
+export default class BaselineViewer extends Component {
+    static propTypes = {
+        client: PropTypes.object,
+    }
+
+    state = {
+        baselines: [],
+        designated: [],
+        selected: "",
+        flow_id: "",
+        drift: [],
+        drift_error: "",
+        new_artifact: "",
+    }
+
+    componentDidMount() {
+        this.source = CancelToken.source();
+        this.drift_source = CancelToken.source();
+        this.fetchBaselines();
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+        this.drift_source.cancel("unmounted");
+    }
+
+    getClientId = () => {
+        return this.props.client && this.props.client.client_id;
+    }
+
+    fetchBaselines = () => {
+        api.get("v1/Baselines", {client_id: this.getClientId()},
+                this.source.token).then(response=>{
+            if (response.cancel) return;
+            this.setState({
+                baselines: response.data.baselines || [],
+                designated: response.data.designated || [],
+            });
+        });
+    }
+
+    fetchDrift = artifact => {
+        this.drift_source.cancel();
+        this.drift_source = CancelToken.source();
+        this.setState({selected: artifact, drift: [],
+                       flow_id: "", drift_error: ""});
+
+        api.get("v1/BaselineDrift", {
+            client_id: this.getClientId(),
+            artifact: artifact,
+        }, this.drift_source.token).then(response=>{
+            if (response.cancel) return;
+            this.setState({
+                flow_id: response.data.flow_id,
+                drift: response.data.drift || [],
+            });
+        }).catch(err=>{
+            let data = err.response && err.response.data;
+            this.setState({drift_error: _.isString(data) ? data :
+                           T("Unable to compare to the baseline.")});
+        });
+    }
+
+    // Make the latest collection of the artifact the new baseline.
+    setBaseline = artifact => {
+        api.post("v1/Baselines", {
+            client_id: this.getClientId(),
+            artifact: artifact,
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+            this.fetchBaselines();
+            this.fetchDrift(artifact);
+        });
+    }
+
+    deleteBaseline = artifact => {
+        api.post("v1/Baselines", {
+            client_id: this.getClientId(),
+            artifact: artifact,
+            delete: true,
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+            this.setState({selected: "", drift: []});
+            this.fetchBaselines();
+        });
+    }
+
+    renderDrift = () => {
+        if (this.state.drift_error) {
+            return <div className="no-content">{this.state.drift_error}</div>;
+        }
+
+        if (_.isEmpty(this.state.drift)) {
+            return <div className="no-content">
+                     {T("No drift since the baseline.")}
+                   </div>;
+        }
+
+        let rows = _.map(this.state.drift, x=>{
+            return {
+                Status: x.status,
+                Key: x.key,
+                Changed: (x.changed || []).join(", "),
+                Current: x.row,
+                Baseline: x.baseline,
+            };
+        });
+
+        return <VeloTable
+                 rows={rows}
+                 columns={["Status", "Key", "Changed", "Current", "Baseline"]}
+                 renderers={{
+                     Status: (cell, row) => <span className={"drift-" + cell}>
+                                              {T(cell)}
+                                            </span>,
+                 }}
+               />;
+    }
+
+    renderNewBaseline = () => {
+        let baselined = _.map(this.state.baselines, x=>x.artifact);
+        let available = _.filter(this.state.designated,
+                                 x=>!_.includes(baselined, x.artifact));
+        if (_.isEmpty(available)) {
+            return <></>;
+        }
+
+        return (
+          <Form.Row className="baseline-new">
+            <Form.Control as="select"
+                          value={this.state.new_artifact}
+                          onChange={e=>this.setState({
+                              new_artifact: e.currentTarget.value})}>
+              <option value="">{T("Select an artifact to baseline")}</option>
+              { _.map(available, (x, idx)=>{
+                  return <option key={idx} value={x.artifact}>
+                           {x.artifact}
+                         </option>;
+              })}
+            </Form.Control>
+            <Button variant="default"
+                    disabled={!this.state.new_artifact}
+                    onClick={()=>this.setBaseline(this.state.new_artifact)}>
+              {T("Baseline latest collection")}
+            </Button>
+          </Form.Row>
+        );
+    }
+
+    render() {
+        return (
+            <div className="baselines">
+              <Table bordered hover size="sm">
+                <thead>
+                  <tr>
+                    <th>{T("Artifact")}</th>
+                    <th>{T("Flow Id")}</th>
+                    <th>{T("Created")}</th>
+                    <th>{T("Creator")}</th>
+                    <th>{T("Rows")}</th>
+                    <th></th>
+                  </tr>
+                </thead>
+                <tbody>
+                  { _.map(this.state.baselines, (x, idx)=>{
+                      return <tr key={idx}
+                                 className={x.artifact === this.state.selected ?
+                                            "row-selected" : undefined}
+                                 onClick={()=>this.fetchDrift(x.artifact)}>
+                               <td>{x.artifact}</td>
+                               <td>{x.flow_id}</td>
+                               <td><VeloTimestamp usec={x.created}/></td>
+                               <td>{x.creator}</td>
+                               <td>{x.total_rows}</td>
+                               <td>
+                                 <ButtonGroup>
+                                   <Button variant="default"
+                                           className="btn-tooltip"
+                                           data-tooltip={T("Baseline latest collection")}
+                                           onClick={e=>{
+                                               e.stopPropagation();
+                                               this.setBaseline(x.artifact);
+                                           }}>
+                                     <FontAwesomeIcon icon="sync"/>
+                                   </Button>
+                                   <Button variant="default"
+                                           className="btn-tooltip"
+                                           data-tooltip={T("Delete baseline")}
+                                           onClick={e=>{
+                                               e.stopPropagation();
+                                               this.deleteBaseline(x.artifact);
+                                           }}>
+                                     <FontAwesomeIcon icon="trash"/>
+                                   </Button>
+                                 </ButtonGroup>
+                               </td>
+                             </tr>;
+                  })}
+                </tbody>
+              </Table>
+              { this.renderNewBaseline() }
+              { this.state.selected &&
+                <div className="baseline-drift">
+                  <h6>
+                    {T("Drift since baseline")} {this.state.selected}
+                    { this.state.flow_id && " (" + this.state.flow_id + ")" }
+                  </h6>
+                  { this.renderDrift() }
+                </div>
+              }
+            </div>
+        );
+    }
+}
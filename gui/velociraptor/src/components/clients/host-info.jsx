@@ -7,6 +7,7 @@ import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
 import { withRouter, Link }  from "react-router-dom";
 import VeloTimestamp from "../utils/time.jsx";
 import ShellViewer from "./shell-viewer.jsx";
+import BaselineViewer from "./baseline-viewer.jsx";
 import VeloReportViewer from "../artifacts/reporting.jsx";
 import { LabelClients } from './clients-list.jsx';
 
@@ -20,6 +21,7 @@ import Spinner from '../utils/spinner.jsx';
 import Form from 'react-bootstrap/Form';
 import UserConfig from '../core/user.jsx';
 import MetadataEditor from "./metadata.jsx";
+import { ClientClockProvider, ClientClockSummary } from '../utils/client-clock.jsx';
 import api from '../core/api-service.jsx';
 import {CancelToken} from 'axios';
 import "./host-info.css";
@@ -376,6 +378,9 @@ class VeloHostInfo extends Component {
                   return <div key={idx}>{address}</div>;
               })}
             </dd>
+                        <ClientClockProvider client_id={client_id}>
+                          <ClientClockSummary />
+                        </ClientClockProvider>
                       </dl>
                       <hr />
                       <Card.Header>{T("Client Metadata")}</Card.Header>
@@ -405,6 +410,14 @@ class VeloHostInfo extends Component {
             );
         }
 
+        if (this.state.mode === 'baseline') {
+            return (
+                <div className="client-details baseline">
+                  <BaselineViewer client={this.props.client} />
+                </div>
+            );
+        }
+
         return <div>Unknown mode</div>;
     }
 
@@ -560,6 +573,11 @@ class VeloHostInfo extends Component {
                       <FontAwesomeIcon icon="terminal"/>
                       <span className="button-label">{T("Shell")}</span>
                     </ToggleButton>
+                    <ToggleButton variant="default"
+                                  value='baseline'>
+                      <FontAwesomeIcon icon="columns"/>
+                      <span className="button-label">{T("Baseline")}</span>
+                    </ToggleButton>
                   </ToggleButtonGroup>
                 </div>
                 <div className="clearfix"></div>
@@ -529,6 +529,236 @@ class _VeloVQLCell extends Component {
 
 const VeloVQLCell = withRouter(_VeloVQLCell);
 
+// Refresh interactive shells more often.
+const INTERACTIVE_POLL_TIME = 2000;
+
+// An interactive shell session. Input is sent to the client as it is
+// typed and the transcript is read back from the collection.
+class _VeloInteractiveShellCell extends Component {
+    static propTypes = {
+        flow: PropTypes.object,
+        client: PropTypes.object,
+        fetchLastShellCollections: PropTypes.func.isRequired,
+
+        // React router props.
+        history: PropTypes.object,
+    }
+
+    state = {
+        collapsed: false,
+        output: [],
+        input: "",
+        showDeleteWizard: false,
+    }
+
+    componentDidMount() {
+        this.source = CancelToken.source();
+        this.interval = setInterval(this.loadData, INTERACTIVE_POLL_TIME);
+        this.loadData();
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+        clearInterval(this.interval);
+    }
+
+    isRunning = () => {
+        return this.props.flow && this.props.flow.state === 'RUNNING';
+    }
+
+    viewFlow = target=>{
+        if (!this.props.flow || !this.props.flow.session_id ||
+            !this.props.flow.client_id) {
+            return;
+        }
+
+        let client_id = this.props.flow.client_id;
+        let session_id = this.props.flow.session_id;
+        this.props.history.push('/collected/' + client_id +
+                                "/" + session_id + "/" + target);
+    }
+
+    loadData = () => {
+        if (!this.props.flow || !this.props.flow.session_id) {
+            return;
+        }
+
+        // Once the session is done we only need to load it once.
+        if (!this.isRunning() && this.state.output.length > 0) {
+            return;
+        }
+
+        api.get("v1/GetTable", {
+            artifact: "Generic.Client.InteractiveShell",
+            client_id: this.props.flow.client_id,
+            flow_id: this.props.flow.session_id,
+            rows: 10000,
+        }, this.source.token).then(response=>{
+            if (!response || !response.data || !response.data.rows) {
+                return;
+            };
+
+            let columns = response.data.columns || [];
+            let stream_idx = columns.findIndex(x=>x==="Stream");
+            let data_idx = columns.findIndex(x=>x==="Data");
+            let output = response.data.rows.map(row=>{
+                return {Stream: row.cell[stream_idx], Data: row.cell[data_idx]};
+            });
+            this.setState({output: output});
+        });
+    };
+
+    sendInput = (data, close) => {
+        api.post('v1/ShellInput', {
+            client_id: this.props.flow.client_id,
+            flow_id: this.props.flow.session_id,
+            data: data,
+            close: close,
+        }, this.source.token).then(this.loadData);
+    };
+
+    sendLine = () => {
+        this.sendInput(this.state.input + "\n", false);
+        this.setState({input: ""});
+    };
+
+    cancelFlow = (e) => {
+        api.post('v1/CancelFlow', {
+            client_id: this.props.flow.client_id,
+            flow_id: this.props.flow.session_id,
+        }, this.source.token).then(function() {
+            this.props.fetchLastShellCollections();
+        }.bind(this));
+    };
+
+    render() {
+        let buttons = [];
+        if (this.state.collapsed) {
+            buttons.push(
+                <button className="btn-tooltip btn btn-default" key={1}
+                        data-tooltip={T("Expand")}
+                        data-position="right"
+                        onClick={() => this.setState({collapsed: false})} >
+                  <i><FontAwesomeIcon icon="expand"/></i>
+                </button>
+            );
+        } else {
+            buttons.push(
+                <button className="btn-tooltip btn btn-default" key={2}
+                        data-tooltip={T("Collapse")}
+                        data-position="right"
+                        onClick={() => this.setState({collapsed: true})} >
+                  <i><FontAwesomeIcon icon="compress"/></i>
+                </button>
+            );
+        }
+
+        if (this.isRunning()) {
+            buttons.push(
+                <button className="btn-tooltip btn btn-default" key={3}
+                        data-tooltip={T("Close Session")}
+                        data-position="right"
+                        onClick={() => this.sendInput("", true)}>
+                  <i><FontAwesomeIcon icon="sign-out-alt"/></i>
+                </button>
+            );
+            buttons.push(
+                <button className="btn-tooltip btn btn-default" key={4}
+                        data-tooltip={T("Stop")}
+                        data-position="right"
+                        onClick={this.cancelFlow}>
+                  <i><FontAwesomeIcon icon="stop"/></i>
+                </button>
+            );
+        }
+
+        let flow_status = [
+            <button className="btn btn-outline-info" key={5}
+              onClick={e=>{this.viewFlow("overview");}}
+            >
+              <i><FontAwesomeIcon icon="external-link-alt"/></i>
+            </button>,
+            <button className="btn btn-outline-info" key={6} disabled>
+              { this.isRunning() &&
+                <i><FontAwesomeIcon icon="spinner" spin /></i> }
+              <VeloTimestamp usec={this.props.flow.create_time/1000} />
+              by {this.props.flow.request.creator}
+            </button>,
+            <button className="btn-tooltip btn btn-default" key={7}
+                    data-tooltip={T("Delete")}
+                    data-position="right"
+                    onClick={()=>this.setState({showDeleteWizard: true})}>
+              <i><FontAwesomeIcon icon="trash"/></i>
+            </button>,
+        ];
+
+        return (
+            <>
+              { this.state.showDeleteWizard &&
+                <DeleteFlowDialog
+                  client={this.props.client}
+                  flow={this.props.flow}
+                  onClose={e=>{
+                      this.setState({showDeleteWizard: false});
+                  }}
+                />
+              }
+              <div className={classNames({
+                       collapsed: this.state.collapsed,
+                       expanded: !this.state.collapsed,
+                       'shell-cell': true,
+                   })}>
+                <div className='notebook-input'>
+                  <div className="cell-toolbar">
+                    <div className="btn-group" role="group">
+                      { buttons }
+                    </div>
+                    <div className="btn-group float-right" role="group">
+                      { flow_status }
+                    </div>
+                  </div>
+                </div>
+                <div className='notebook-output interactive-shell-output'>
+                  <pre>
+                    { this.state.output.map((item, index)=>{
+                        return <span key={index}
+                                     className={"shell-" + item.Stream}>
+                                 { item.Stream === "exit" ?
+                                   "\n[" + T("Exited") + " " + item.Data + "]\n" :
+                                   item.Data }
+                               </span>;
+                    })}
+                  </pre>
+                </div>
+                { this.isRunning() &&
+                  <InputGroup className="interactive-shell-input">
+                    <input className="form-control"
+                           placeholder={T("Send input to shell")}
+                           spellCheck="false"
+                           value={this.state.input}
+                           onChange={e=>this.setState({input: e.target.value})}
+                           onKeyDown={e=>{
+                               if (e.key === "Enter") {
+                                   this.sendLine();
+                               }
+                           }}
+                    />
+                    <InputGroup.Append>
+                      <button className="btn btn-default" type="button"
+                              onClick={this.sendLine}>
+                        {T("Send")}
+                      </button>
+                    </InputGroup.Append>
+                  </InputGroup>
+                }
+              </div>
+            </>
+        );
+    };
+};
+
+const VeloInteractiveShellCell = withRouter(_VeloInteractiveShellCell);
+
 class ShellViewer extends Component {
     static propTypes = {
         client: PropTypes.object,
@@ -598,7 +828,7 @@ class ShellViewer extends Component {
             rows: 100,
             start_row: 0,
             sort_direction: false,
-            filter_regex: "(Windows.System.PowerShell|Windows.System.CmdShell|Linux.Sys.BashShell|Generic.Client.VQL)"
+            filter_regex: "(Windows.System.PowerShell|Windows.System.CmdShell|Linux.Sys.BashShell|Generic.Client.VQL|Generic.Client.InteractiveShell)"
         },
                 this.source.token // CancelToken
                ).then(function(response) {
@@ -635,6 +865,7 @@ class ShellViewer extends Component {
                            if (artifact === "Windows.System.PowerShell" ||
                                artifact === "Windows.System.CmdShell" ||
                                artifact === "Generic.Client.VQL" ||
+                               artifact === "Generic.Client.InteractiveShell" ||
                                artifact === "Linux.Sys.BashShell" ) {
                                new_state.flows.push(flow);
                            }
@@ -659,6 +890,9 @@ class ShellViewer extends Component {
             artifact = "Linux.Sys.BashShell";
         } else if(this.state.shell_type.toLowerCase() === "vql") {
             artifact = "Generic.Client.VQL";
+        } else if(this.state.shell_type.toLowerCase() === "interactive") {
+            this.launchInteractive();
+            return;
         } else {
             return;
         };
@@ -681,6 +915,27 @@ class ShellViewer extends Component {
         }, this.source.token);
     };
 
+    // Start an interactive shell session. Input is sent through the
+    // session's cell.
+    launchInteractive = () => {
+        let artifact = "Generic.Client.InteractiveShell";
+        var params = {
+            client_id: this.props.client.client_id,
+            artifacts: [artifact],
+            specs: [{
+                artifact: artifact,
+                parameters: {
+                    env: [],
+                }
+            }],
+            urgent: true,
+        };
+
+        api.post('v1/CollectArtifact', params).then(response=>{
+            this.fetchLastShellCollections();
+        }, this.source.token);
+    };
+
     renderCells(flows) {
         return flows.map((flow, index) => {
             let artifact = flow && flow.request &&  flow.request.artifacts &&
@@ -691,6 +946,12 @@ class ShellViewer extends Component {
                                     flow={flow} client={this.props.client} />;
             };
 
+            if (artifact === "Generic.Client.InteractiveShell") {
+                return <VeloInteractiveShellCell key={index}
+                                    fetchLastShellCollections={this.fetchLastShellCollections}
+                                    flow={flow} client={this.props.client} />;
+            };
+
             return (
                 <VeloShellCell key={index}
                                fetchLastShellCollections={this.fetchLastShellCollections}
@@ -702,7 +963,7 @@ class ShellViewer extends Component {
     aceConfig = (ace) => {
         // Attach a completer to ACE.
         let completer = new Completer();
-        completer.initializeAceEditor(ace, {});
+        completer.initializeAceEditor(ace, {diagnostics: true});
 
         ace.setOptions({
             autoScrollEditorIntoView: true,
@@ -719,6 +980,9 @@ class ShellViewer extends Component {
             simple_textarea = false;
         }
 
+        // Interactive sessions take their input in the session cell.
+        let interactive = this.state.shell_type === "Interactive";
+
 
         return (
             <>
@@ -735,9 +999,14 @@ class ShellViewer extends Component {
                       { (!this.state.client_os || this.state.client_os !== "windows") &&
                         <Dropdown.Item eventKey="Bash">Bash</Dropdown.Item> }
                       <Dropdown.Item eventKey="VQL">VQL</Dropdown.Item>
+                      <Dropdown.Item eventKey="Interactive">Interactive</Dropdown.Item>
                     </DropdownButton>
                   </InputGroup.Prepend>
-                  { simple_textarea ?
+                  { interactive ?
+                    <div className="form-control">
+                      {T("Start an interactive shell session on the client")}
+                    </div> :
+                    simple_textarea ?
                     <textarea focus-me="controller.focus" rows="1"
                               className="form-control"
                               placeholder={T("Run command on client")}
@@ -763,7 +1032,7 @@ class ShellViewer extends Component {
 
                   <InputGroup.Append className="input-group-append">
                     <button className="btn btn-danger" type="button"
-                            disabled={!this.state.command}
+                            disabled={!interactive && !this.state.command}
                             onClick={this.launchCommand}
                     >Launch</button>
                   </InputGroup.Append>
@@ -89,7 +89,7 @@ import classNames from "classnames";
 import Button from 'react-bootstrap/Button';
 import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
 
-import UserConfig from './user.jsx';
+import UserConfig, { GUI_SETTINGS } from './user.jsx';
 
 import api from '../core/api-service.jsx';
 import {CancelToken} from 'axios';
@@ -168,7 +168,8 @@ export default class VeloAce extends Component {
 
     getUserOptions = () => {
         let user_options = this.normalizeOptions(
-            JSON.parse(this.context.traits.ui_settings || "{}"));
+            _.omit(JSON.parse(this.context.traits.ui_settings || "{}"),
+                   GUI_SETTINGS));
         return Object.assign(user_options, this.props.options || {});
     }
 
@@ -178,8 +179,13 @@ export default class VeloAce extends Component {
         // If options have changed we need to update them to the
         // server.
         if (!_.isEqual(new_options, this.getUserOptions())) {
+            // Preserve the GUI settings stored with the editor options.
+            let gui_settings = _.pick(
+                JSON.parse(this.context.traits.ui_settings || "{}"),
+                GUI_SETTINGS);
             api.post("v1/SetGUIOptions",
-                     {options: JSON.stringify(new_options)},
+                     {options: JSON.stringify(
+                         Object.assign(new_options, gui_settings))},
                      this.source.token).then((response) => {
                          this.context.updateTraits();
                      });
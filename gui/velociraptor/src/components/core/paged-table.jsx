@@ -18,6 +18,7 @@ import Button from 'react-bootstrap/Button';
 import Pagination from 'react-bootstrap/Pagination';
 import Form from 'react-bootstrap/Form';
 import ButtonGroup from 'react-bootstrap/ButtonGroup';
+import Dropdown from 'react-bootstrap/Dropdown';
 import Navbar from 'react-bootstrap/Navbar';
 import VeloValueRenderer from '../utils/value.jsx';
 import Spinner from '../utils/spinner.jsx';
@@ -27,6 +28,7 @@ import ClientLink from '../clients/client-link.jsx';
 import HexView from '../utils/hex.jsx';
 
 import T from '../i8n/i8n.jsx';
+import { formatNumber } from '../i8n/format.jsx';
 import UserConfig from '../core/user.jsx';
 
 import {
@@ -510,7 +512,8 @@ class VeloPagedTable extends Component {
 
     customTotal = (from, to, size) => (
         <span className="react-bootstrap-table-pagination-total">
-          {T("TablePagination", from, to, size)}
+          {T("TablePagination", formatNumber(from),
+             formatNumber(to), formatNumber(size))}
         </span>
     );
 
@@ -745,6 +748,44 @@ class VeloPagedTable extends Component {
                               <FontAwesomeIcon icon="file-csv"/>
                               <span className="sr-only">{T("Download CSV")}</span>
                             </Button>
+                            <Button variant="default"
+                                    target="_blank" rel="noopener noreferrer"
+                                    data-tooltip={T("Download Parquet")}
+                                    data-position="right"
+                                    className="btn-tooltip"
+                                    href={api.href("/api/v1/DownloadTable",
+                                                   Object.assign(downloads, {
+                                                       timezone: timezone,
+                                                       download_format: "parquet",
+                                                   }), {internal: true})}>
+                              <FontAwesomeIcon icon="file-download"/>
+                              <span className="sr-only">{T("Download Parquet")}</span>
+                            </Button>
+                            <Dropdown data-tooltip={T("SIEM Export")}
+                                      data-position="right"
+                                      className="btn-tooltip">
+                              <Dropdown.Toggle variant="default">
+                                <FontAwesomeIcon icon="file-export"/>
+                                <span className="sr-only">{T("SIEM Export")}</span>
+                              </Dropdown.Toggle>
+                              <Dropdown.Menu>
+                                { _.map([["siem_jsonl", "JSON Lines"],
+                                         ["cef", "CEF"],
+                                         ["leef", "LEEF"],
+                                         ["stix", "STIX 2.1"]], x=>{
+                                    return <Dropdown.Item
+                                             key={x[0]}
+                                             target="_blank" rel="noopener noreferrer"
+                                             href={api.href("/api/v1/DownloadTable",
+                                                            Object.assign({}, downloads, {
+                                                                timezone: timezone,
+                                                                download_format: x[0],
+                                                            }), {internal: true})}>
+                                             {x[1]}
+                                           </Dropdown.Item>;
+                                })}
+                              </Dropdown.Menu>
+                            </Dropdown>
                           </ButtonGroup>
                           { transformed.length > 0 &&
                             <ButtonGroup className="float-right">
@@ -28,6 +28,7 @@ import { HexViewPopup } from '../utils/hex.jsx';
 import OverlayTrigger from 'react-bootstrap/OverlayTrigger';
 import Tooltip from 'react-bootstrap/Tooltip';
 import T from '../i8n/i8n.jsx';
+import { formatNumber } from '../i8n/format.jsx';
 import TreeCell from './tree-cell.jsx';
 import ContextMenu from '../utils/context.jsx';
 import PreviewUpload from '../widgets/preview_uploads.jsx';
@@ -255,7 +256,8 @@ class VeloTable extends Component {
 
     customTotal = (from, to, size) => (
         <span className="react-bootstrap-table-pagination-total">
-          {T("TablePagination", from, to, size)}
+          {T("TablePagination", formatNumber(from),
+             formatNumber(to), formatNumber(size))}
         </span>
     );
 
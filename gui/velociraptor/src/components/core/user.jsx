@@ -11,6 +11,10 @@ const UserConfig = React.createContext({
 
 const POLL_TIME = 5000;
 
+// Keys in the user's ui_settings which are GUI settings rather than
+// editor options.
+export const GUI_SETTINGS = ["time_format"];
+
 // A component which maintains the user settings
 export class UserSettings extends React.Component {
     static propTypes = {
@@ -37,6 +41,10 @@ export class UserSettings extends React.Component {
             }
 
             traits.username = response.data.username;
+
+            // GUI settings are stored together with the editor
+            // options.
+            traits.time_format = this.getUserOptions(traits).time_format || "iso";
             traits.orgs = response.data.orgs;
 
             this.setState({traits: traits});
@@ -60,6 +60,7 @@ export default class FlowOverview extends React.Component {
         if (this.state.lock) {
             options.password = this.context.traits &&
                 this.context.traits.default_password;
+            options.encryption = this.state.encryption;
         }
         options.flow_id = this.props.flow.session_id;
         options.client_id = this.props.flow.client_id;
@@ -93,6 +94,7 @@ export default class FlowOverview extends React.Component {
         loading: false,
         available_downloads: [],
         lock: false,
+        encryption: "zip",
         expand_sparse: false,
     };
 
@@ -132,6 +134,12 @@ export default class FlowOverview extends React.Component {
                       { flow.session_id } { flow.request.urgent && "( " + T("Urgent") + " )" }
                     </dd>
 
+                    { flow.request.priority &&
+                      <>
+                        <dt className="col-4">{T("Priority")}</dt>
+                        <dd className="col-8">{flow.request.priority}</dd>
+                      </> }
+
                     <dt className="col-4">{T("Creator")}</dt>
                     <dd className="col-8"> { flow.request.creator } </dd>
 
@@ -159,7 +167,22 @@ export default class FlowOverview extends React.Component {
                     </dd>
 
                     <dt className="col-4">{T("State")}</dt>
-                    <dd className="col-8">{ T(flow.state) }</dd>
+                    <dd className="col-8">
+                      { T(flow.state) }
+                      { flow.state === "RUNNING" && flow.queue_position > 0 &&
+                        " (" + T("Queued at position") + " " +
+                        flow.queue_position + ")" }
+                    </dd>
+
+                    { flow.request && flow.request.dry_run &&
+                      <Fragment>
+                        <dt className="col-4">{T("Dry Run")}</dt>
+                        <dd className="col-8">
+                          {T("Would upload")} {flow.total_uploaded_files || 0} {T("files")} (
+                          {flow.total_expected_uploaded_bytes || 0} {T("bytes")})
+                        </dd>
+                      </Fragment>
+                    }
 
                     { flow.state === "ERROR" &&
                       <Fragment>
@@ -262,6 +285,26 @@ export default class FlowOverview extends React.Component {
                             </span>
                           </OverlayTrigger>
                         }
+                        { this.state.lock &&
+                          <Dropdown>
+                            <Dropdown.Toggle variant="default">
+                              {this.state.encryption === "7z" ?
+                               T("7z Archive") : T("Zip Archive")}
+                            </Dropdown.Toggle>
+                            <Dropdown.Menu>
+                              <Dropdown.Item
+                                active={this.state.encryption !== "7z"}
+                                onClick={()=>this.setState({encryption: "zip"})}>
+                                {T("Zip Archive")}
+                              </Dropdown.Item>
+                              <Dropdown.Item
+                                active={this.state.encryption === "7z"}
+                                onClick={()=>this.setState({encryption: "7z"})}>
+                                {T("7z Archive")}
+                              </Dropdown.Item>
+                            </Dropdown.Menu>
+                          </Dropdown>
+                        }
                         {this.state.expand_sparse ?
                          <OverlayTrigger
                            delay={{show: 250, hide: 400}}
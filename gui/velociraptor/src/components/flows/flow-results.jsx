@@ -5,6 +5,7 @@ import VeloPagedTable from '../core/paged-table.jsx';
 import _ from 'lodash';
 
 import FormControl from 'react-bootstrap/FormControl';
+import { ClientClockProvider, ClientClockToggle } from '../utils/client-clock.jsx';
 
 function getFlowState(flow) {
     return {flow_id: flow.session_id,
@@ -76,15 +77,19 @@ export default class FlowResults extends React.Component {
                    </div>;
         }
 
+        // Results may be displayed adjusted for the client's clock.
         return (
-            <>
-              <FormControl as="select" size="sm"
-                           ref={ (el) => this.element=el }
-                           onChange={() => this.setArtifact(this.element.value)}>
-                {_.map(artifacts_with_results, function(item, idx) {
-                    return <option key={idx}> {item} </option>;
-                })}
-              </FormControl>
+            <ClientClockProvider client_id={this.props.flow.client_id}>
+              <div className="d-flex">
+                <ClientClockToggle />
+                <FormControl as="select" size="sm"
+                             ref={ (el) => this.element=el }
+                             onChange={() => this.setArtifact(this.element.value)}>
+                  {_.map(artifacts_with_results, function(item, idx) {
+                      return <option key={idx}> {item} </option>;
+                  })}
+                </FormControl>
+              </div>
               <VeloPagedTable
                 env={{
                     client_id: this.props.flow.client_id,
@@ -94,7 +99,7 @@ export default class FlowResults extends React.Component {
                 params={this.state.params}
                 version={getFlowState(this.props.flow)}
               />
-            </>
+            </ClientClockProvider>
         );
     }
 };
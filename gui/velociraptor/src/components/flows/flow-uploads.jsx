@@ -7,6 +7,10 @@ import OverlayTrigger from 'react-bootstrap/OverlayTrigger';
 import Tooltip from 'react-bootstrap/Tooltip';
 import PreviewUpload from '../widgets/preview_uploads.jsx';
 import api from '../core/api-service.jsx';
+import T from '../i8n/i8n.jsx';
+import Navbar from 'react-bootstrap/Navbar';
+import ButtonGroup from 'react-bootstrap/ButtonGroup';
+import YaraScanUploadsDialog from './flows-yara-scan.jsx';
 
 // Older collections had the upload includes the full filestore path
 // to the file, but this is un necessary because the file must reside
@@ -29,6 +33,10 @@ export default class FlowUploads extends React.Component {
         flow: PropTypes.object,
     };
 
+    state = {
+        showYaraScanDialog: false,
+    }
+
     render() {
         let renderers = {
             Preview: (cell, row, rowIndex) => {
@@ -105,16 +113,32 @@ export default class FlowUploads extends React.Component {
         };
 
         return (
-            <VeloPagedTable
-              className="col-12"
-              renderers={renderers}
-              extra_columns={["Preview"]}
-              params={{
-                  client_id: this.props.flow.client_id,
-                  flow_id: this.props.flow.session_id,
-                  type: "uploads",
-              }}
-            />
+            <>
+              { this.state.showYaraScanDialog &&
+                <YaraScanUploadsDialog
+                  flow={this.props.flow}
+                  onClose={()=>this.setState({showYaraScanDialog: false})}/>
+              }
+              <Navbar className="toolbar">
+                <ButtonGroup>
+                  <Button title={T("Scan uploads with YARA")}
+                          onClick={()=>this.setState({showYaraScanDialog: true})}
+                          variant="default">
+                    <FontAwesomeIcon icon="search"/>
+                  </Button>
+                </ButtonGroup>
+              </Navbar>
+              <VeloPagedTable
+                className="col-12"
+                renderers={renderers}
+                extra_columns={["Preview"]}
+                params={{
+                    client_id: this.props.flow.client_id,
+                    flow_id: this.props.flow.session_id,
+                    type: "uploads",
+                }}
+              />
+            </>
         );
     }
 };
@@ -9,6 +9,7 @@ import VeloPagedTable from '../core/paged-table.jsx';
 import Navbar from 'react-bootstrap/Navbar';
 import ButtonGroup from 'react-bootstrap/ButtonGroup';
 import Button from 'react-bootstrap/Button';
+import Dropdown from 'react-bootstrap/Dropdown';
 
 import api from '../core/api-service.jsx';
 import { formatColumns } from "../core/table.jsx";
@@ -32,6 +33,14 @@ import {CancelToken} from 'axios';
 
 const POLL_TIME = 5000;
 
+// The settings of the collection which are saved with a favorite and
+// applied again when it is launched.
+const FAVORITE_RESOURCES = [
+    "urgent", "priority", "cpu_limit", "iops_limit", "ops_per_second",
+    "progress_timeout", "timeout", "max_rows", "max_upload_bytes",
+    "trace_freq_sec",
+];
+
 const SLIDE_STATES = [{
     level: "30%",
     icon: "arrow-down",
@@ -129,6 +138,7 @@ export class SaveCollectionDialog extends React.PureComponent {
     startSaveFlow = () => {
         let client_id = this.props.client && this.props.client.client_id;
         let specs = this.props.flow.request.specs;
+        let resources = _.pick(this.props.flow.request, FAVORITE_RESOURCES);
         let type = "CLIENT";
         if (client_id==="server") {
             type = "SERVER";
@@ -142,6 +152,8 @@ export class SaveCollectionDialog extends React.PureComponent {
                         Name: this.state.name,
                         Description: this.state.description,
                         Type: type,
+                        Resources: JSON.stringify(resources),
+                        Shared: this.state.shared,
                     }, ()=>{
                         this.props.onClose();
                         this.setState({loading: false});
@@ -169,7 +181,12 @@ export class SaveCollectionDialog extends React.PureComponent {
                   value={this.state.description}
                   setValue={x=>this.setState({description:x})}
                 />
-
+                <VeloForm
+                  param={{name: T("Shared"), type: "bool",
+                          description: T("Share this favorite with all users")}}
+                  value={this.state.shared}
+                  setValue={x=>this.setState({shared:x})}
+                />
               </Modal.Body>
               <Modal.Footer>
                 <Button variant="secondary" onClick={this.props.onClose}>
@@ -211,6 +228,7 @@ class FlowsList extends React.Component {
         version: {version: 0},
         slider: 0,
         transform: undefined,
+        favorites: [],
     }
 
     incrementVersion = () => {
@@ -289,6 +307,25 @@ class FlowsList extends React.Component {
                  });
     }
 
+    fetchFavorites = () => {
+        let client_id = this.props.client && this.props.client.client_id;
+        api.get('v1/GetUserFavorites', {
+            type: client_id === "server" ? "SERVER" : "CLIENT",
+        }, this.source.token).then(response=>{
+            this.setState({favorites: response.data.items || []});
+        });
+    }
+
+    // Launch the favorite directly with its saved parameters and
+    // resource limits.
+    launchFavorite = (fav) => {
+        let request = Object.assign({}, fav.resources, {
+            artifacts: _.map(fav.spec, x=>x.artifact),
+            specs: fav.spec,
+        });
+        this.setCollectionRequest(request);
+    }
+
     cancelButtonClicked = () => {
         let client_id = this.props.selected_flow && this.props.selected_flow.client_id;
         let flow_id = this.props.selected_flow && this.props.selected_flow.session_id;
@@ -386,6 +423,7 @@ class FlowsList extends React.Component {
               }
               { this.state.showSaveCollectionDialog &&
                 <SaveCollectionDialog
+                  client={this.props.client}
                   flow={this.props.selected_flow}
                   onClose={e=>{
                       this.setState({showSaveCollectionDialog: false});
@@ -457,6 +495,36 @@ class FlowsList extends React.Component {
                     <span className="sr-only">{T("New Collection")}</span>
                   </Button>
 
+                  <Dropdown data-tooltip={T("Launch Favorite")}
+                            data-position="right"
+                            className="btn-tooltip"
+                            onToggle={show=>show && this.fetchFavorites()}>
+                    <Dropdown.Toggle variant="default">
+                      <FontAwesomeIcon icon="heart"/>
+                      <span className="sr-only">{T("Launch Favorite")}</span>
+                    </Dropdown.Toggle>
+                    <Dropdown.Menu>
+                      { _.isEmpty(this.state.favorites) &&
+                        <Dropdown.Item disabled>
+                          {T("No favorites")}
+                        </Dropdown.Item>
+                      }
+                      { _.map(this.state.favorites, (fav, idx)=>{
+                          return <Dropdown.Item
+                                   key={idx}
+                                   title={fav.description}
+                                   onClick={()=>this.launchFavorite(fav)}>
+                                   {fav.name}
+                                   { fav.shared &&
+                                     <span className="favorite-shared">
+                                       {" "}({T("Shared")})
+                                     </span>
+                                   }
+                                 </Dropdown.Item>;
+                      })}
+                    </Dropdown.Menu>
+                  </Dropdown>
+
                   { client_id !== "server" &&
                     <Button data-tooltip={T("Add to hunt")}
                             data-position="right"
@@ -0,0 +1,105 @@
+import _ from 'lodash';
+import React from 'react';
+import PropTypes from 'prop-types';
+import T from '../i8n/i8n.jsx';
+import Modal from 'react-bootstrap/Modal';
+import Button from 'react-bootstrap/Button';
+import Form from 'react-bootstrap/Form';
+import Spinner from '../utils/spinner.jsx';
+import { Link }  from "react-router-dom";
+
+import {CancelToken} from 'axios';
+import api from '../core/api-service.jsx';
+
+const ARTIFACT = "Server.Utils.YaraScanUploads";
+
+// Scans the files already uploaded in a collection with new yara
+// rules. The scan runs as a server artifact so its progress and
+// results are available with the server collections.
+export default class YaraScanUploadsDialog extends React.Component {
+    static propTypes = {
+        flow: PropTypes.object,
+        onClose: PropTypes.func.isRequired,
+    };
+
+    state = {
+        loading: false,
+        rules: "",
+        scan_flow_id: "",
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+    }
+
+    componentWillUnmount() {
+        this.source.cancel();
+    }
+
+    startScan = ()=>{
+        let client_id = this.props.flow && this.props.flow.client_id;
+        let flow_id = this.props.flow && this.props.flow.session_id;
+
+        this.setState({loading: true});
+        api.post("v1/CollectArtifact", {
+            client_id: "server",
+            artifacts: [ARTIFACT],
+            specs: [{artifact: ARTIFACT,
+                     parameters:{"env": [
+                         {"key": "ClientId", "value": client_id},
+                         {"key": "FlowId", "value": flow_id},
+                         {"key": "YaraRule", "value": this.state.rules},
+                     ]},
+                    }],
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+
+            this.setState({
+                loading: false,
+                scan_flow_id: response.data.flow_id,
+            });
+        });
+    }
+
+    render() {
+        return (
+            <Modal show={true} size="lg"
+                   onHide={this.props.onClose}>
+              <Modal.Header closeButton>
+                <Modal.Title>{T("Scan uploads with YARA")}</Modal.Title>
+              </Modal.Header>
+              <Modal.Body><Spinner loading={this.state.loading} />
+                { this.state.scan_flow_id ?
+                  <>
+                    {T("Scan started in server collection")} &nbsp;
+                    <Link to={"/collected/server/" +
+                              this.state.scan_flow_id + "/results"}>
+                      {this.state.scan_flow_id}
+                    </Link>
+                  </>
+                  :
+                  <Form.Control as="textarea" rows={15}
+                                placeholder={T("YARA rules")}
+                                spellCheck="false"
+                                value={this.state.rules}
+                                onChange={e=>this.setState(
+                                    {rules: e.currentTarget.value})}/>
+                }
+              </Modal.Body>
+              <Modal.Footer>
+                <Button variant="secondary" onClick={this.props.onClose}>
+                  {T("Close")}
+                </Button>
+                { !this.state.scan_flow_id &&
+                  <Button variant="primary"
+                          disabled={_.isEmpty(this.state.rules) ||
+                                    this.state.loading}
+                          onClick={this.startScan}>
+                    {T("Scan")}
+                  </Button>
+                }
+              </Modal.Footer>
+            </Modal>
+        );
+    }
+};
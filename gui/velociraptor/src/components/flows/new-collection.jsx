@@ -4,6 +4,7 @@ import React from 'react';
 import PropTypes from 'prop-types';
 import _ from 'lodash';
 import T from '../i8n/i8n.jsx';
+import { formatNumber } from '../i8n/format.jsx';
 import Alert from 'react-bootstrap/Alert';
 import Button from 'react-bootstrap/Button';
 import Modal from 'react-bootstrap/Modal';
@@ -220,13 +221,14 @@ class NewCollectionSelectArtifacts extends React.Component {
         });
     }
 
-    deleteFavorite = (name, type)=>{
+    deleteFavorite = (name, type, shared)=>{
         this.setState({loading: true});
         runArtifact("server",   // This collection happens on the server.
                     "Server.Utils.DeleteFavoriteFlow",
                     {
                         Name: name,
                         Type: type,
+                        Shared: shared ? "Y" : "N",
                     }, ()=>{
                         this.fetchFavorites();
                         this.setState({loading: false});
@@ -259,7 +261,8 @@ class NewCollectionSelectArtifacts extends React.Component {
         _.each(this.state.favorites, fav=>{
             if(selection.value === fav.name) {
                 this.setFavorite(fav.spec);
-                this.setState({current_favorite: {name: fav.name, type: fav.type}});
+                this.setState({current_favorite: {
+                    name: fav.name, type: fav.type, shared: fav.shared}});
             }
         });
         return true;
@@ -306,6 +309,7 @@ class NewCollectionSelectArtifacts extends React.Component {
                                   this.deleteFavorite(
                                       this.state.current_favorite.name,
                                       this.state.current_favorite.type,
+                                      this.state.current_favorite.shared,
                                   );
                                   this.setState({
                                       current_favorite: null,
@@ -376,6 +380,9 @@ class NewCollectionResources extends React.Component {
         resources: PropTypes.object,
         setResources: PropTypes.func,
         paginator: PropTypes.object,
+
+        // Hunts run a dry run on a sample of clients.
+        sampleClients: PropTypes.bool,
     }
 
     state = {}
@@ -383,7 +390,7 @@ class NewCollectionResources extends React.Component {
     isInvalid = () => {
         return this.state.invalid_1 || this.state.invalid_2 ||
             this.state.invalid_3 || this.state.invalid_4 ||
-            this.state.invalid_5;
+            this.state.invalid_5 || this.state.invalid_6;
     }
 
     getTimeout = (artifacts) => {
@@ -460,7 +467,7 @@ class NewCollectionResources extends React.Component {
             return T("Unlimited");
         }
 
-        return T("X per second", ops_per_second);
+        return T("X per second", formatNumber(ops_per_second));
     }
 
     getCpuLimit = (artifacts) => {
@@ -498,7 +505,7 @@ class NewCollectionResources extends React.Component {
             return T("Unlimited");
         }
 
-        return T("X per second", iops_limit);
+        return T("X per second", formatNumber(iops_limit));
     }
 
     render() {
@@ -636,6 +643,48 @@ class NewCollectionResources extends React.Component {
                     </Col>
                   </Form.Group>
 
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Priority")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Control
+                        as="select"
+                        value={resources.priority || 0}
+                        onChange={e=>this.props.setResources({
+                            priority: parseInt(e.currentTarget.value)})}
+                      >
+                        <option value="-1">{T("Low")}</option>
+                        <option value="0">{T("Normal")}</option>
+                        <option value="1">{T("High")}</option>
+                      </Form.Control>
+                    </Col>
+                  </Form.Group>
+
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Dry Run")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Check
+                        type="checkbox"
+                        checked={resources.dry_run || false}
+                        label={T("Only estimate the files and bytes to upload")}
+                        onChange={e=>this.props.setResources({
+                            dry_run: e.currentTarget.checked})}
+                      />
+                    </Col>
+                  </Form.Group>
+
+                  { this.props.sampleClients && resources.dry_run &&
+                    <Form.Group as={Row}>
+                      <Form.Label column sm="3">{T("Sample Clients")}</Form.Label>
+                      <Col sm="8">
+                        <ValidatedInteger
+                          placeholder={T("All clients")}
+                          value={resources.client_limit}
+                          setInvalid={value => this.setState({invalid_6: value})}
+                          setValue={value => this.props.setResources({
+                              client_limit: value})} />
+                      </Col>
+                    </Form.Group> }
+
                 </Form>
               </Modal.Body>
               <Modal.Footer>
@@ -842,6 +891,7 @@ class NewCollectionWizard extends React.Component {
             progress_timeout: request.progress_timeout,
             max_rows: request.max_rows,
             trace_freq_sec: request.trace_freq_sec,
+            priority: parseInt(request.priority) || 0,
             max_mbytes: Math.round(
                 request.max_upload_bytes / 1024 / 1024 * 100) / 100  || undefined,
         };
@@ -928,6 +978,10 @@ class NewCollectionWizard extends React.Component {
 
         result.urgent = this.state.resources.urgent;
 
+        if (this.state.resources.dry_run) {
+            result.dry_run = true;
+        }
+
         if (this.state.resources.ops_per_second) {
             result.ops_per_second = this.state.resources.ops_per_second;
         }
@@ -956,6 +1010,10 @@ class NewCollectionWizard extends React.Component {
             result.trace_freq_sec = this.state.resources.trace_freq_sec;
         }
 
+        if (this.state.resources.priority) {
+            result.priority = this.state.resources.priority;
+        }
+
         if (this.state.resources.max_mbytes) {
             result.max_upload_bytes = parseInt(
                 this.state.resources.max_mbytes * 1024 * 1024);
@@ -8,6 +8,7 @@ import HuntOverview from './hunt-overview.jsx';
 import HuntRequest from './hunt-request.jsx';
 import HuntClients from './hunt-clients.jsx';
 import HuntNotebook from './hunt-notebook.jsx';
+import HuntStacking from './hunt-stacking.jsx';
 import Spinner from '../utils/spinner.jsx';
 import T from '../i8n/i8n.jsx';
 import { withRouter }  from "react-router-dom";
@@ -67,6 +68,10 @@ class HuntInspector extends React.Component {
                   { tab === "notebook" &&
                     <HuntNotebook hunt={this.props.hunt} />}
                 </Tab>
+                <Tab eventKey="stacking" title={T("Stacking")}>
+                  { tab === "stacking" &&
+                    <HuntStacking hunt={this.props.hunt} />}
+                </Tab>
 
               </Tabs>
             </div>
@@ -38,11 +38,39 @@ class ModifyHuntDialog extends React.Component {
 
     state = {
         description: "",
-        expires: ""
+        expires: "",
+        clients_per_minute: "",
+        quota_rows: "",
+        quota_upload_bytes: "",
+        quota_execution_time: "",
     }
 
     componentDidMount = () => {
         this.source = CancelToken.source();
+        this.fetchSchedulingRate();
+        this.fetchQuota();
+    }
+
+    fetchSchedulingRate = ()=>{
+        api.get("v1/HuntSchedulingRate", {hunt_id: this.props.hunt.hunt_id},
+                this.source.token).then(response=>{
+                    if (response.cancel) return;
+                    this.setState({
+                        clients_per_minute: response.data.clients_per_minute || 0,
+                    });
+                });
+    }
+
+    fetchQuota = ()=>{
+        api.get("v1/HuntQuota", {hunt_id: this.props.hunt.hunt_id},
+                this.source.token).then(response=>{
+                    if (response.cancel) return;
+                    this.setState({
+                        quota_rows: response.data.max_rows || 0,
+                        quota_upload_bytes: response.data.max_upload_bytes || 0,
+                        quota_execution_time: response.data.max_execution_time || 0,
+                    });
+                });
     }
 
     componentWillUnmount() {
@@ -70,6 +98,18 @@ class ModifyHuntDialog extends React.Component {
             expires: this.getExpiryEpoch() * 1000000,
             hunt_id: hunt_id,
         }, this.source.token).then((response) => {
+            return api.post("v1/HuntSchedulingRate", {
+                hunt_id: hunt_id,
+                clients_per_minute: parseFloat(this.state.clients_per_minute) || 0,
+            }, this.source.token);
+        }).then((response) => {
+            return api.post("v1/HuntQuota", {
+                hunt_id: hunt_id,
+                max_rows: parseInt(this.state.quota_rows) || 0,
+                max_upload_bytes: parseInt(this.state.quota_upload_bytes) || 0,
+                max_execution_time: parseInt(this.state.quota_execution_time) || 0,
+            }, this.source.token);
+        }).then((response) => {
             this.props.onResolve();
         });
     }
@@ -97,6 +137,30 @@ class ModifyHuntDialog extends React.Component {
                      value={expires}
                      setValue={x=>this.setState({expires:x})}
                    />
+                   <VeloForm
+                     param={{name: T("Scheduling Rate"),
+                             description: T("Maximum number of new clients scheduled per minute (0 for no limit)")}}
+                     value={String(this.state.clients_per_minute)}
+                     setValue={x=>this.setState({clients_per_minute:x})}
+                   />
+                   <VeloForm
+                     param={{name: T("Row Quota"),
+                             description: T("Stop the hunt when its collections returned more rows in total (0 for no limit)")}}
+                     value={String(this.state.quota_rows)}
+                     setValue={x=>this.setState({quota_rows:x})}
+                   />
+                   <VeloForm
+                     param={{name: T("Upload Quota"),
+                             description: T("Stop the hunt when its collections uploaded more bytes in total (0 for no limit)")}}
+                     value={String(this.state.quota_upload_bytes)}
+                     setValue={x=>this.setState({quota_upload_bytes:x})}
+                   />
+                   <VeloForm
+                     param={{name: T("Execution Time Quota"),
+                             description: T("Stop the hunt when its collections ran for more seconds in total (0 for no limit)")}}
+                     value={String(this.state.quota_execution_time)}
+                     setValue={x=>this.setState({quota_execution_time:x})}
+                   />
                  </Modal.Body>
 
                  <Modal.Footer>
@@ -173,7 +237,34 @@ class HuntList extends React.Component {
 
     // Launch the hunt.
     setCollectionRequest = (request) => {
+        // Hunts with a VQL condition are created paused and only
+        // started once the condition is set.
+        let vql_condition = request.vql_condition;
+        let start_hunt = request.state === 2;
+        delete request.vql_condition;
+        if (vql_condition) {
+            delete request.state;
+        }
+
         api.post('v1/CreateHunt', request, this.source.token).then((response) => {
+            let hunt_id = response.data && response.data.flow_id;
+            if (!vql_condition || !hunt_id) {
+                return response;
+            }
+
+            return api.post("v1/HuntVQLCondition", {
+                hunt_id: hunt_id,
+                query: vql_condition,
+            }, this.source.token).then(() => {
+                if (!start_hunt) {
+                    return response;
+                }
+                return api.post("v1/ModifyHunt", {
+                    state: "RUNNING",
+                    hunt_id: hunt_id,
+                }, this.source.token);
+            });
+        }).then((response) => {
             // Keep the wizard up until the server confirms the
             // creation worked.
             this.setState({
@@ -208,7 +299,7 @@ class HuntList extends React.Component {
         if (!hunt_id) { return; };
 
         api.post("v1/ModifyHunt", {
-            state: "PAUSED",
+            state: "STOPPED",
             hunt_id: hunt_id,
         }, this.source.token).then((response) => {
             this.props.updateHunts();
@@ -230,6 +321,22 @@ class HuntList extends React.Component {
         });
     }
 
+    // Paused hunts do not schedule new clients but collections
+    // already in flight continue to run.
+    pauseHunt = () => {
+        let hunt_id = this.props.selected_hunt &&
+            this.props.selected_hunt.hunt_id;
+
+        if (!hunt_id) { return; };
+
+        api.post("v1/ModifyHunt", {
+            state: "PAUSED",
+            hunt_id: hunt_id,
+        }, this.source.token).then((response) => {
+            this.props.updateHunts();
+        });
+    }
+
     archiveHunt = () => {
         let hunt_id = this.props.selected_hunt &&
             this.props.selected_hunt.hunt_id;
@@ -467,10 +574,19 @@ class HuntList extends React.Component {
                             <FontAwesomeIcon icon="play" />
                             <span className="sr-only">{T("Run Hunt")}</span>
                         </Button>
-                        <Button data-tooltip={T("Stop Hunt")}
+                        <Button data-tooltip={T("Pause Hunt")}
                             data-position="right"
                             className="btn-tooltip"
                             disabled={state !== 'RUNNING'}
+                            onClick={this.pauseHunt}
+                            variant="default">
+                            <FontAwesomeIcon icon="pause" />
+                            <span className="sr-only">{T("Pause Hunt")}</span>
+                        </Button>
+                        <Button data-tooltip={T("Stop Hunt")}
+                            data-position="right"
+                            className="btn-tooltip"
+                            disabled={state !== 'RUNNING' && state !== 'PAUSED'}
                             onClick={this.stopHunt}
                             variant="default">
                             <FontAwesomeIcon icon="stop" />
@@ -21,6 +21,7 @@ import api from '../core/api-service.jsx';
 import {CancelToken} from 'axios';
 import { requestToParameters } from "../flows/utils.jsx";
 import AvailableDownloads from "../notebooks/downloads.jsx";
+import HuntProgress from "./hunt-progress.jsx";
 
 
 export default class HuntOverview extends React.Component {
@@ -34,6 +35,8 @@ export default class HuntOverview extends React.Component {
     state = {
         preparing: false,
         lock: false,
+        encryption: "zip",
+        vql_condition: "",
     }
 
     componentDidMount = () => {
@@ -43,12 +46,37 @@ export default class HuntOverview extends React.Component {
         let lock_password = this.context.traits &&
             this.context.traits.default_password;
         this.setState({lock: lock_password});
+        this.fetchVQLCondition();
+    }
+
+    componentDidUpdate = (prevProps, prevState, rootNode) => {
+        let prev_hunt_id = prevProps.hunt && prevProps.hunt.hunt_id;
+        let hunt_id = this.props.hunt && this.props.hunt.hunt_id;
+        if (prev_hunt_id !== hunt_id) {
+            this.fetchVQLCondition();
+        }
     }
 
     componentWillUnmount() {
         this.source.cancel("unmounted");
     }
 
+    fetchVQLCondition = () => {
+        let hunt_id = this.props.hunt && this.props.hunt.hunt_id;
+        this.setState({vql_condition: ""});
+        if (!hunt_id) {
+            return;
+        }
+
+        api.get("v1/HuntVQLCondition", {hunt_id: hunt_id},
+                this.source.token).then(response=>{
+                    if (response.cancel) return;
+                    this.setState({
+                        vql_condition: (response.data && response.data.query) || "",
+                    });
+                });
+    }
+
     huntState = () => {
         let hunt = this.props.hunt;
         let stopped = hunt.stats && hunt.stats.stopped;
@@ -77,6 +105,10 @@ export default class HuntOverview extends React.Component {
             password: lock_password,
         };
 
+        if (lock_password) {
+            params.encryption = this.state.encryption;
+        }
+
         switch(download_type) {
         case "all":
             params.json_format = true;
@@ -185,6 +217,11 @@ export default class HuntOverview extends React.Component {
                         <dt className="col-4">{T("Include OS")}</dt>
                         <dd className="col-8">{hunt.condition.os.os}</dd>
                       </>}
+                    { this.state.vql_condition &&
+                      <>
+                        <dt className="col-4">{T("VQL Condition")}</dt>
+                        <dd className="col-8"><pre>{this.state.vql_condition}</pre></dd>
+                      </>}
                     { hunt.condition && hunt.condition.excluded_labels &&
                       <>
                         <dt className="col-4">{T("Excluded Labels")}</dt>
@@ -231,6 +268,7 @@ export default class HuntOverview extends React.Component {
                     <dt className="col-4">{T("Finished clients")}</dt>
                     <dd className="col-8">{stats.total_clients_with_results || 0}</dd>
 
+                    <HuntProgress hunt={hunt}/>
 
                     <dt className="col-4">{T("Download Results")}</dt>
                     <dd className="col-8">
@@ -262,6 +300,26 @@ export default class HuntOverview extends React.Component {
                             </span>
                           </OverlayTrigger>
                         }
+                        { this.state.lock &&
+                          <Dropdown>
+                            <Dropdown.Toggle variant="default">
+                              {this.state.encryption === "7z" ?
+                               T("7z Archive") : T("Zip Archive")}
+                            </Dropdown.Toggle>
+                            <Dropdown.Menu>
+                              <Dropdown.Item
+                                active={this.state.encryption !== "7z"}
+                                onClick={()=>this.setState({encryption: "zip"})}>
+                                {T("Zip Archive")}
+                              </Dropdown.Item>
+                              <Dropdown.Item
+                                active={this.state.encryption === "7z"}
+                                onClick={()=>this.setState({encryption: "7z"})}>
+                                {T("7z Archive")}
+                              </Dropdown.Item>
+                            </Dropdown.Menu>
+                          </Dropdown>
+                        }
                         <Dropdown>
                           <Dropdown.Toggle
                             disabled={this.state.preparing}
@@ -0,0 +1,162 @@
+import React from 'react';
+import PropTypes from 'prop-types';
+
+import ProgressBar from 'react-bootstrap/ProgressBar';
+import VeloTimestamp from "../utils/time.jsx";
+import T from '../i8n/i8n.jsx';
+import api from '../core/api-service.jsx';
+import {CancelToken} from 'axios';
+
+const POLL_TIME = 10000;
+
+const formatBytes = (bytes) => {
+    bytes = bytes || 0;
+    let units = ["B", "KB", "MB", "GB", "TB"];
+    let i = 0;
+    while (bytes >= 1024 && i < units.length - 1) {
+        bytes /= 1024;
+        i++;
+    }
+    return bytes.toFixed(i > 0 ? 1 : 0) + " " + units[i];
+};
+
+// Shows how far the hunt has progressed through its targeted clients
+// and when it is expected to complete.
+export default class HuntProgress extends React.Component {
+    static propTypes = {
+        hunt: PropTypes.object,
+    };
+
+    state = {
+        progress: {},
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+        this.fetchProgress();
+        this.interval = setInterval(this.fetchProgress, POLL_TIME);
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+        clearInterval(this.interval);
+    }
+
+    componentDidUpdate = (prevProps, prevState, rootNode) => {
+        let prev_hunt_id = prevProps.hunt && prevProps.hunt.hunt_id;
+        let hunt_id = this.props.hunt && this.props.hunt.hunt_id;
+        if (prev_hunt_id !== hunt_id) {
+            this.setState({progress: {}});
+            this.fetchProgress();
+        }
+    }
+
+    fetchProgress = () => {
+        let hunt_id = this.props.hunt && this.props.hunt.hunt_id;
+        if (!hunt_id) {
+            return;
+        }
+
+        this.source.cancel();
+        this.source = CancelToken.source();
+
+        api.get("v1/GetHuntProgress", {hunt_id: hunt_id},
+                this.source.token).then(response=>{
+                    if (response.cancel) return;
+                    this.setState({progress: response.data || {}});
+                });
+    }
+
+    render() {
+        let progress = this.state.progress;
+        let targeted = progress.TotalClientsTargeted || 0;
+        if (!progress.HuntId) {
+            return <></>;
+        }
+
+        let percent = (x) => targeted > 0 ? (x || 0) * 100 / targeted : 0;
+        let completed = progress.TotalClientsCompleted || 0;
+        let errored = progress.TotalClientsErrored || 0;
+        let running = progress.TotalClientsRunning || 0;
+        let eta = progress.ETA && !progress.ETA.startsWith("0001-") &&
+            progress.ETA;
+
+        return (
+            <>
+              <dt className="col-4">{T("Progress")}</dt>
+              <dd className="col-8">
+                <ProgressBar>
+                  <ProgressBar variant="success" now={percent(completed)}
+                               label={completed}
+                               key="completed" />
+                  <ProgressBar variant="danger" now={percent(errored)}
+                               label={errored}
+                               key="errored" />
+                  <ProgressBar variant="info" now={percent(running)}
+                               label={running}
+                               key="running" />
+                </ProgressBar>
+                {(progress.PercentComplete || 0).toFixed(1)}% {T("of")} {targeted} {T("targeted clients")}
+              </dd>
+
+              <dt className="col-4">{T("Completed")}</dt>
+              <dd className="col-8">{completed}</dd>
+
+              <dt className="col-4">{T("Errored")}</dt>
+              <dd className="col-8">{errored}</dd>
+
+              <dt className="col-4">{T("Running")}</dt>
+              <dd className="col-8">{running}</dd>
+
+              <dt className="col-4">{T("Rows collected")}</dt>
+              <dd className="col-8">{progress.TotalCollectedRows || 0}</dd>
+
+              <dt className="col-4">{T("Bytes uploaded")}</dt>
+              <dd className="col-8">{formatBytes(progress.TotalUploadedBytes)}</dd>
+
+              { progress.DryRun &&
+                <>
+                  <dt className="col-4">{T("Dry Run")}</dt>
+                  <dd className="col-8">
+                    {T("Sampled clients would upload")} {progress.TotalUploadedFiles || 0} {T("files")} ({formatBytes(progress.TotalExpectedUploadedBytes)})
+                  </dd>
+                </> }
+
+              { progress.ProjectedUploadBytes > 0 &&
+                <>
+                  <dt className="col-4">{T("Projected upload")}</dt>
+                  <dd className="col-8">
+                    {progress.ProjectedUploadFiles || 0} {T("files")} ({formatBytes(progress.ProjectedUploadBytes)})
+                  </dd>
+                </> }
+
+              <dt className="col-4">{T("Check in rate")}</dt>
+              <dd className="col-8">
+                {(progress.CheckInRate || 0).toFixed(1)} {T("clients/hour")}
+              </dd>
+
+              <dt className="col-4">{T("Scheduling Rate")}</dt>
+              <dd className="col-8">
+                { progress.ClientsPerMinute ?
+                  <>{progress.ClientsPerMinute} {T("clients/minute")}</> :
+                  T("Unlimited") }
+              </dd>
+
+              <dt className="col-4">{T("Estimated completion")}</dt>
+              <dd className="col-8">
+                { eta ? <VeloTimestamp iso={eta}/> : T("Unknown") }
+                { progress.ExpiresBeforeCompletion &&
+                  <div className="text-danger">
+                    {T("The hunt will expire before all targeted clients are scheduled.")}
+                  </div> }
+              </dd>
+
+              { progress.QuotaExceeded &&
+                <>
+                  <dt className="col-4">{T("Quota")}</dt>
+                  <dd className="col-8 text-danger">{progress.QuotaExceeded}</dd>
+                </> }
+            </>
+        );
+    }
+};
@@ -0,0 +1,174 @@
+import React from 'react';
+import PropTypes from 'prop-types';
+
+import _ from 'lodash';
+import Form from 'react-bootstrap/Form';
+import Button from 'react-bootstrap/Button';
+import ToggleButtonGroup from 'react-bootstrap/ToggleButtonGroup';
+import ToggleButton from 'react-bootstrap/ToggleButton';
+import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
+import VeloTable from '../core/table.jsx';
+import ClientLink from '../clients/client-link.jsx';
+import Spinner from '../utils/spinner.jsx';
+import T from '../i8n/i8n.jsx';
+import { formatNumber } from '../i8n/format.jsx';
+import api from '../core/api-service.jsx';
+import {CancelToken} from 'axios';
+
+
+// Least frequency of occurrence analysis: count how many of the
+// hunt's clients returned each distinct value.
+export default class HuntStacking extends React.Component {
+    static propTypes = {
+        hunt: PropTypes.object,
+    };
+
+    state = {
+        artifact: "",
+        columns: "",
+        stack: null,
+        loading: false,
+
+        // Show the rarest values first.
+        order: "rarest",
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+        let sources = this.getSources();
+        if (!_.isEmpty(sources)) {
+            this.setState({artifact: sources[0]});
+        }
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+    }
+
+    getSources = () => {
+        let hunt = this.props.hunt || {};
+        if (!_.isEmpty(hunt.artifact_sources)) {
+            return hunt.artifact_sources;
+        }
+        return hunt.artifacts || [];
+    }
+
+    fetchStack = () => {
+        this.source.cancel();
+        this.source = CancelToken.source();
+        this.setState({loading: true});
+
+        api.get("v1/HuntStack", {
+            hunt_id: this.props.hunt.hunt_id,
+            artifact: this.state.artifact,
+            columns: this.state.columns,
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+            this.setState({stack: response.data, loading: false});
+        }).catch(()=>this.setState({loading: false}));
+    }
+
+    renderStack = () => {
+        let stack = this.state.stack;
+        if (!stack) {
+            return <></>;
+        }
+
+        if (_.isEmpty(stack.Rows)) {
+            return <div className="no-content">{T("No results to stack")}</div>;
+        }
+
+        let value_columns = [];
+        let rows = _.map(stack.Rows, x=>{
+            let row = Object.assign({}, x.Value);
+            _.each(_.keys(x.Value), k=>{
+                if (!_.includes(value_columns, k)) {
+                    value_columns.push(k);
+                }
+            });
+            row.Clients = x.Clients;
+            row.Percent = x.Percent;
+            row.Rows = x.Rows;
+            row.SampleClients = x.SampleClients;
+            return row;
+        });
+
+        if (this.state.order === "common") {
+            rows = _.reverse(rows);
+        }
+
+        return (
+            <>
+              <p>
+                {T("ClientsWithResults", stack.TotalClients)}
+                { stack.Truncated &&
+                  <span className="text-danger">
+                    {" "}{T("Too many distinct values, results are truncated")}
+                  </span> }
+              </p>
+              <VeloTable
+                rows={rows}
+                columns={_.concat(["Clients", "Percent"], value_columns,
+                                  ["Rows", "SampleClients"])}
+                renderers={{
+                    Clients: (cell, row) => formatNumber(cell),
+                    Rows: (cell, row) => formatNumber(cell),
+                    Percent: (cell, row) => formatNumber(cell / 100, {
+                        style: "percent", maximumFractionDigits: 1}),
+                    SampleClients: (cell, row) => _.map(cell, (x, idx)=>{
+                        return <span key={idx} className="stack-client">
+                                 <ClientLink client_id={x}/>
+                               </span>;
+                    }),
+                }}
+              />
+            </>
+        );
+    }
+
+    render() {
+        return (
+            <div className="hunt-stacking">
+              <Form className="hunt-stacking-form"
+                    onSubmit={e=>{
+                        e.preventDefault();
+                        this.fetchStack();
+                    }}>
+                <Form.Row>
+                  <Form.Control as="select"
+                                className="col-4"
+                                value={this.state.artifact}
+                                onChange={e=>this.setState({
+                                    artifact: e.currentTarget.value})}>
+                    { _.map(this.getSources(), (x, idx)=>{
+                        return <option key={idx} value={x}>{x}</option>;
+                    })}
+                  </Form.Control>
+                  <Form.Control className="col-4"
+                                placeholder={T("Columns to stack (comma separated, default all)")}
+                                value={this.state.columns}
+                                onChange={e=>this.setState({
+                                    columns: e.currentTarget.value})} />
+                  <Button variant="default" type="submit"
+                          disabled={this.state.loading}>
+                    <FontAwesomeIcon icon="list"/>
+                    <span className="button-label">{T("Stack")}</span>
+                  </Button>
+                  <ToggleButtonGroup type="radio" name="order"
+                                     value={this.state.order}
+                                     onChange={order=>this.setState({order: order})}>
+                    <ToggleButton variant="default" value="rarest">
+                      {T("Rarest first")}
+                    </ToggleButton>
+                    <ToggleButton variant="default" value="common">
+                      {T("Most common first")}
+                    </ToggleButton>
+                  </ToggleButtonGroup>
+                </Form.Row>
+              </Form>
+              <Spinner loading={this.state.loading}/>
+              { this.renderStack() }
+            </div>
+        );
+    }
+};
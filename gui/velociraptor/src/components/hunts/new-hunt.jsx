@@ -97,6 +97,7 @@ class NewHuntConfigureHunt extends React.Component {
                           <option label={T("Run everywhere")} value="">{T("Run everywhere")}</option>
                           <option label={T("Match by label")} value="labels">{T("Match by label")}</option>
                           <option label={T("Operating System")} value="os">{T("Operating System")}</option>
+                          <option label={T("Match by VQL query")} value="vql">{T("Match by VQL query")}</option>
                         </Form.Control>
                     </Col>
                   </Form.Group>
@@ -131,6 +132,21 @@ class NewHuntConfigureHunt extends React.Component {
                     </Form.Group>
                   }
 
+                  { this.props.parameters.include_condition === "vql" &&
+                    <Form.Group as={Row}>
+                      <Form.Label column sm="3">{T("VQL Condition")}</Form.Label>
+                      <Col sm="8">
+                        <Form.Control as="textarea" rows={3}
+                                      spellCheck="false"
+                                      placeholder="SELECT * FROM clients(client_id=ClientId) WHERE os_info.release =~ 'Server 2012'"
+                                      value={this.props.parameters.vql_condition}
+                                      onChange={e => this.setParam(
+                                          "vql_condition", e.target.value)}
+                        />
+                      </Col>
+                    </Form.Group>
+                  }
+
                   <Form.Group as={Row}>
                     <Form.Label column sm="3">{T("Exclude Condition")}</Form.Label>
                     <Col sm="8">
@@ -299,6 +315,21 @@ export default class NewHuntWizard extends React.Component {
             state.hunt_parameters.expires = expiry;
             state.hunt_parameters.org_ids = hunt.org_ids || [];
 
+            // The VQL condition is stored separately from the hunt.
+            if (hunt.hunt_id) {
+                api.get("v1/HuntVQLCondition", {hunt_id: hunt.hunt_id},
+                        this.source.token).then(response=>{
+                            if (response.cancel) return;
+                            let query = response.data && response.data.query;
+                            if (query) {
+                                let hunt_parameters = this.state.hunt_parameters;
+                                hunt_parameters.vql_condition = query;
+                                hunt_parameters.include_condition = "vql";
+                                this.setState({hunt_parameters: hunt_parameters});
+                            }
+                        });
+            }
+
             // Resolve the artifacts from the request into a list of descriptors.
             api.post("v1/GetArtifacts", {
                 names: request.artifacts,
@@ -391,11 +422,20 @@ export default class NewHuntWizard extends React.Component {
             request.max_upload_bytes = this.state.resources.max_mbytes * 1024 * 1024;
         }
 
+        if (this.state.resources.dry_run) {
+            request.dry_run = true;
+        }
+
         let result = {
             start_request: request,
             condition: {},
         };
 
+        // A dry run is usually run on a small sample of clients.
+        if (this.state.resources.dry_run && this.state.resources.client_limit) {
+            result.client_limit = this.state.resources.client_limit;
+        }
+
         let hunt_parameters = this.state.hunt_parameters;
         if (hunt_parameters.expires) {
             result.expires = hunt_parameters.expires.getTime() * 1000;
@@ -413,6 +453,13 @@ export default class NewHuntWizard extends React.Component {
             result.condition.excluded_labels = {label: hunt_parameters.excluded_labels};
         }
 
+        // This is not part of the hunt object - the caller sets it
+        // after the hunt is created.
+        if (hunt_parameters.include_condition === "vql" &&
+            hunt_parameters.vql_condition) {
+            result.vql_condition = hunt_parameters.vql_condition;
+        }
+
         if (hunt_parameters.description) {
             result.hunt_description = hunt_parameters.description;
         }
@@ -510,6 +557,7 @@ export default class NewHuntWizard extends React.Component {
 
                 <NewCollectionResources
                   resources={this.state.resources}
+                  sampleClients={true}
                   paginator={new HuntPaginator("Specify Resources",
                                                "Create Hunt: Specify resource limits")}
 
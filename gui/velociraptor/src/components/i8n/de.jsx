@@ -3,6 +3,7 @@ import hex2a from "./utils";
 import React from 'react';
 import Alert from 'react-bootstrap/Alert';
 import humanizeDuration from "humanize-duration";
+import { plural } from "./format.jsx";
 
 import automated from "./de.json";
 
@@ -580,6 +581,14 @@ const Deutsch = {
     "uploaded_size":"Hochgeladene Größe",
     "TablePagination": (from, to, size)=>
     <>Zeigt Zeile { from } bis { to } von { size }</>,
+    "Import Artifacts": plural({
+        one: "{count} Artefakt importieren",
+        other: "{count} Artefakte importieren",
+    }),
+    "ClientsWithResults": plural({
+        one: "{count} Client mit Ergebnissen",
+        other: "{count} Clients mit Ergebnissen",
+    }),
 
     "Select a language":"Sprache auswählen",
     "English":"Englisch",
@@ -629,6 +638,7 @@ const Deutsch = {
      "Role_investigator": "Ermittler",
      "Role_artifact_writer": "Artefaktschreiber",
      "Role_api": "Schreibgeschützter API-Client",
+     "Role_auditor": "Prüfer",
 
     "Perm_ALL_QUERY": "Alle Abfragen",
      "Perm_ANY_QUERY": "Jede Abfrage",
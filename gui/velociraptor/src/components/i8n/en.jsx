@@ -1,6 +1,7 @@
 import React from 'react';
 import Alert from 'react-bootstrap/Alert';
 import humanizeDuration from "humanize-duration";
+import { plural } from "./format.jsx";
 
 const English = {
     "SEARCH_CLIENTS": "Search clients",
@@ -60,7 +61,14 @@ const English = {
         return "Edit Artifact " + name;
     },
     "Notebook for Collection": name=>"Notebook for Collection "+name,
-    "Import Artifacts": length=><>Import {length} Artifacts</>,
+    "Import Artifacts": plural({
+        one: "Import {count} Artifact",
+        other: "Import {count} Artifacts",
+    }),
+    "ClientsWithResults": plural({
+        one: "{count} client with results",
+        other: "{count} clients with results",
+    }),
     "ArtifactDeletionDialog": (session_id, artifacts, total_bytes, total_rows)=>
     <>
       You are about to permanently delete the artifact collection
@@ -182,6 +190,8 @@ const English = {
     "Role_investigator" : "Investigator",
     "Role_artifact_writer" : "Artifact Writer",
     "Role_api" : "API Client",
+    "Role_auditor" : "Auditor",
+    "Role_approver" : "Approver",
     "ToolRole_administrator" :
     <>
     Like any system, Velociraptor needs an administrator which is all powerful. This account can run arbitrary VQL on the server, reconfigure the server, etc.  The ability to add/create/edit/remove users is dependent on the organizations to which this account belongs.
@@ -210,6 +220,14 @@ const English = {
     <>
     This role is required to provide the ability for the user to connect over the API port.
     </>,
+    "ToolRole_auditor" :
+    <>
+    This role provides the ability to read previously collected results with sensitive data (e.g. usernames, file contents and IP addresses) redacted. Auditors may not download files. This role is useful in privacy-constrained deployments to review what is being collected. Granting any other role which can read results lifts the redaction.
+    </>,
+    "ToolRole_approver" :
+    <>
+    This role provides the ability to approve collections of dangerous artifacts and large hunts requested by other users, when the server requires approval for them. Approvers may read results in order to judge requests but may not start collections themselves.
+    </>,
 
     "Perm_ALL_QUERY" : "All Query",
     "Perm_ANY_QUERY" : "Any Query",
@@ -232,6 +250,9 @@ const English = {
     "Perm_PREPARE_RESULTS" : "Prepare Results",
     "Perm_DELETE_RESULTS" : "Delete Results",
     "Perm_DATASTORE_ACCESS" : "Datastore Access",
+    "Perm_REMEDIATION" : "Remediation",
+    "Perm_APPROVE_COLLECTION" : "Approve Collection",
+    "Perm_VIEW_PII" : "View PII",
 
 
     "ToolPerm_ALL_QUERY" : "Issue all queries without restriction",
@@ -255,6 +276,9 @@ const English = {
     "ToolPerm_PREPARE_RESULTS" : "Allowed to create zip files",
     "ToolPerm_DELETE_RESULTS" : "Allowed to delete clients, flows and other data",
     "ToolPerm_DATASTORE_ACCESS" : " Allowed raw datastore access",
+    "ToolPerm_REMEDIATION" : "Allowed to make changes to endpoints to remove threats (e.g. stop services or unload drivers)",
+    "ToolPerm_APPROVE_COLLECTION" : "Allowed to approve collections and hunts requested by other users",
+    "ToolPerm_VIEW_PII" : "Allowed to see results unmasked when masking policies apply",
 
     "ToolUsernamePasswordless" :
     <>
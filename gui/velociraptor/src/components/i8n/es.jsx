@@ -3,6 +3,7 @@ import hex2a from "./utils";
 import React from 'react';
 import Alert from 'react-bootstrap/Alert';
 import humanizeDuration from "humanize-duration";
+import { plural } from "./format.jsx";
 
 import automated from "./es.json";
 
@@ -581,6 +582,14 @@ const Spanish = {
     "uploaded_size":"Tamaño de la subida",
     "TablePagination": (from, to, size)=>
     <>Mostrar línea { from } a { to } de { size }</>,
+    "Import Artifacts": plural({
+        one: "Importar {count} artefacto",
+        other: "Importar {count} artefactos",
+    }),
+    "ClientsWithResults": plural({
+        one: "{count} cliente con resultados",
+        other: "{count} clientes con resultados",
+    }),
 
     "Select a language":"Seleccione un idioma",
     "English":"Inglés",
@@ -625,6 +634,7 @@ const Spanish = {
      "Role_investigator": "Investigador",
      "Role_artifact_writer": "Escritor de artefactos",
      "Role_api": "Cliente API de solo lectura",
+     "Role_auditor": "Auditor",
 
     "Perm_ALL_QUERY": "Todas las consultas",
      "Perm_ANY_QUERY" : "Cualquier consulta",
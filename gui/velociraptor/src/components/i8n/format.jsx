@@ -0,0 +1,64 @@
+import _ from 'lodash';
+import moment from 'moment';
+
+// Date formats for the supported languages. Importing a locale
+// switches moment's global locale so restore the default.
+import 'moment/locale/de';
+import 'moment/locale/es';
+import 'moment/locale/fr';
+import 'moment/locale/ja';
+import 'moment/locale/pt';
+import 'moment/locale/vi';
+moment.locale("en");
+
+// Our language codes mapped to BCP 47 locales as used by Intl and
+// moment.
+const LOCALES = {
+    "en": "en",
+    "de": "de",
+    "es": "es",
+    "fr": "fr",
+    "jp": "ja",
+    "por": "pt",
+    "vi": "vi",
+};
+
+export function getLocale() {
+    let lang = (window.globals && window.globals.lang) || "en";
+    return LOCALES[lang] || "en";
+}
+
+// Format a number with the user's digit grouping and decimal
+// separator.
+export function formatNumber(value, options) {
+    if (!_.isNumber(value) || _.isNaN(value)) {
+        return value;
+    }
+    return new Intl.NumberFormat(getLocale(), options).format(value);
+}
+
+// Format a moment in the user's locale (e.g. 15.11.2023 10:00:00).
+export function formatLocalizedTime(when) {
+    return when.clone().locale(getLocale()).format("L LTS");
+}
+
+// Builds a translation which depends on a count. Forms are keyed by
+// the language's plural categories (zero, one, two, few, many,
+// other) and "{count}" is replaced by the formatted count. A form may
+// also be a function of the formatted count and any further args.
+//
+//   "Import Artifacts": plural({
+//       one: "Import {count} Artifact",
+//       other: "Import {count} Artifacts",
+//   }),
+export function plural(forms) {
+    return (count, ...args) => {
+        let category = new Intl.PluralRules(getLocale()).select(count);
+        let form = forms[category] || forms.other;
+        let formatted = formatNumber(count);
+        if (_.isFunction(form)) {
+            return form(formatted, ...args);
+        }
+        return form.replace("{count}", formatted);
+    };
+}
@@ -3,6 +3,7 @@ import hex2a from "./utils";
 import React from 'react';
 import Alert from 'react-bootstrap/Alert';
 import humanizeDuration from "humanize-duration";
+import { plural } from "./format.jsx";
 
 import automated from "./fr.json";
 
@@ -586,6 +587,14 @@ const French = {
     "uploaded_size":"Taille téléversée",
     "TablePagination": (from, to, size)=>
     <>Afficher la ligne { from } à { to } de { size }</>,
+    "Import Artifacts": plural({
+        one: "Importer {count} artefact",
+        other: "Importer {count} artefacts",
+    }),
+    "ClientsWithResults": plural({
+        one: "{count} client avec des résultats",
+        other: "{count} clients avec des résultats",
+    }),
 
     "Select a language":"Sélectionner la langue",
     "English":"Anglais",
@@ -630,6 +639,7 @@ const French = {
     "Role_investigator": "Enquêteur",
     "Role_artifact_writer": "Auteur d'artefacts",
     "Role_api": "Client API en lecture seule",
+    "Role_auditor": "Auditeur",
 
     "Perm_ALL_QUERY": "Toutes les requêtes",
      "Perm_ANY_QUERY": "N'importe quelle requête",
@@ -4,6 +4,7 @@ import hex2a from "./utils";
 import React from 'react';
 import Alert from 'react-bootstrap/Alert';
 import humanizeDuration from "humanize-duration";
+import { plural } from "./format.jsx";
 
 import automated from "./jp.json";
 
@@ -563,6 +564,12 @@ const Japanese = {
     "uploaded_size":"アップロードサイズ",
     "TablePagination": (from, to, size)=>
     <>{ size }単位で{ from }から{ to }まで表示している</>,
+    "Import Artifacts": plural({
+        other: "{count}個のアーティファクトをインポート",
+    }),
+    "ClientsWithResults": plural({
+        other: "結果のあるクライアント: {count}台",
+    }),
 
     "Select a language":"言語を選択する",
     "Japanese": "日本語",
@@ -607,6 +614,7 @@ const Japanese = {
      "Role_investigator" : "捜査官",
      "Role_artifact_writer" : "アーティファクト ライター",
      "Role_api" : "読み取り専用 API クライアント",
+     "Role_auditor" : "監査人",
 
     "Perm_ALL_QUERY": "すべてのクエリ",
      "Perm_ANY_QUERY": "任意のクエリ",
@@ -627,6 +627,7 @@ const Portuguese = {
     "Role_investigator" : "Investigador",
     "Role_artifact_writer": "Escritor de artefato",
      "Role_api": "Cliente de API somente leitura",
+     "Role_auditor": "Auditor",
 
     "Perm_ALL_QUERY": "Todas as Consultas",
      "Perm_ANY_QUERY": "Qualquer consulta",
@@ -131,6 +131,7 @@ const Vietnamese = {
      "Role_investigator": "Điều tra viên",
      "Role_artifact_writer": "Artifact Writer",
      "Role_api": "API Client",
+     "Role_auditor": "Kiểm toán viên",
 
     "Perm_ALL_QUERY": "All Query",
      "Perm_ANY_QUERY": "Any Query",
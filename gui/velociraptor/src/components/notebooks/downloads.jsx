@@ -30,9 +30,17 @@ export default class AvailableDownloads extends Component {
                      {row.name}
                    </a>;
         }
+        if (stats.state === "ERROR") {
+            return <>
+                     <FontAwesomeIcon icon="exclamation"/>
+                     <span className="button-label">{row.name}</span>
+                   </>;
+        }
         return <>
                  <FontAwesomeIcon icon="spinner" spin/>
                  <span className="button-label">{row.name}</span>
+                 { stats.progress &&
+                   <span className="button-label">({stats.progress}%)</span>}
                  </>;
     }
 
@@ -85,6 +93,15 @@ export default class AvailableDownloads extends Component {
                                  {stats.total_duration || 0}
                                </dd>
 
+                               { stats.error &&
+                                 <>
+                                   <dt className="col-4">{T("Error")}</dt>
+                                   <dd className="col-8">
+                                     {stats.error}
+                                   </dd>
+                                 </>
+                               }
+
                                { stats.hash &&
                                  <>
                                    <dt className="col-4">{T("SHA256 Hash")}</dt>
@@ -281,7 +281,7 @@ export default class NotebookCellRenderer extends React.Component {
     aceConfig = (ace) => {
         // Attach a completer to ACE.
         let completer = new Completer();
-        completer.initializeAceEditor(ace, {});
+        completer.initializeAceEditor(ace, {diagnostics: true});
         completer.registerCompletions(this.state.local_completions);
         ace.setOptions({
             autoScrollEditorIntoView: true,
@@ -0,0 +1,227 @@
+import React from 'react';
+import PropTypes from 'prop-types';
+
+import _ from 'lodash';
+import Modal from 'react-bootstrap/Modal';
+import Button from 'react-bootstrap/Button';
+import Form from 'react-bootstrap/Form';
+import Row from 'react-bootstrap/Row';
+import Col from 'react-bootstrap/Col';
+import Card from 'react-bootstrap/Card';
+import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
+import VeloAce from '../core/ace.jsx';
+import T from '../i8n/i8n.jsx';
+import api from '../core/api-service.jsx';
+import {CancelToken} from 'axios';
+
+const widget_types = [
+    {type: "table", desc: "Table"},
+    {type: "value", desc: "Single Value"},
+    {type: "line_chart", desc: "Time Chart"},
+    {type: "bar_chart", desc: "Bar Chart"},
+];
+
+// Edit a dashboard and its widgets. Saving the built in default
+// dashboard creates a new dashboard owned by the user.
+export default class DashboardEditor extends React.Component {
+    static propTypes = {
+        dashboard: PropTypes.object.isRequired,
+        onClose: PropTypes.func.isRequired,
+
+        // Called with the saved dashboard.
+        onSave: PropTypes.func.isRequired,
+    };
+
+    state = {
+        dashboard: {},
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+        this.setState({dashboard: _.cloneDeep(this.props.dashboard)});
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+    }
+
+    setField = (field, value) => {
+        let dashboard = Object.assign({}, this.state.dashboard);
+        dashboard[field] = value;
+        this.setState({dashboard: dashboard});
+    }
+
+    setWidgetField = (idx, field, value) => {
+        let widgets = _.cloneDeep(this.state.dashboard.widgets || []);
+        widgets[idx][field] = value;
+        this.setField("widgets", widgets);
+    }
+
+    addWidget = () => {
+        let widgets = _.cloneDeep(this.state.dashboard.widgets || []);
+        widgets.push({
+            title: T("New Widget"),
+            type: "table",
+            width: 6,
+            refresh: 0,
+            query: "SELECT * FROM info()",
+        });
+        this.setField("widgets", widgets);
+    }
+
+    removeWidget = idx => {
+        let widgets = _.cloneDeep(this.state.dashboard.widgets || []);
+        widgets.splice(idx, 1);
+        this.setField("widgets", widgets);
+    }
+
+    saveDashboard = () => {
+        api.post("v1/Dashboards", {
+            dashboard: this.state.dashboard,
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+            this.props.onSave(response.data);
+        });
+    }
+
+    renderWidget = (widget, idx) => {
+        return (
+            <Card key={idx} className="dashboard-editor-widget">
+              <Card.Header>
+                {widget.title}
+                <Button variant="default"
+                        size="sm"
+                        className="float-right"
+                        onClick={() => this.removeWidget(idx)}>
+                  <FontAwesomeIcon icon="trash"/>
+                </Button>
+              </Card.Header>
+              <Card.Body>
+                <Form.Group as={Row}>
+                  <Form.Label column sm="3">{T("Title")}</Form.Label>
+                  <Col sm="8">
+                    <Form.Control
+                      value={widget.title || ""}
+                      onChange={e=>this.setWidgetField(
+                          idx, "title", e.currentTarget.value)} />
+                  </Col>
+                </Form.Group>
+                <Form.Group as={Row}>
+                  <Form.Label column sm="3">{T("Type")}</Form.Label>
+                  <Col sm="8">
+                    <Form.Control
+                      as="select"
+                      value={widget.type || "table"}
+                      onChange={e=>this.setWidgetField(
+                          idx, "type", e.currentTarget.value)}>
+                      { _.map(widget_types, x=>{
+                          return <option key={x.type} value={x.type}>
+                                   {T(x.desc)}
+                                 </option>;
+                      })}
+                    </Form.Control>
+                  </Col>
+                </Form.Group>
+                <Form.Group as={Row}>
+                  <Form.Label column sm="3">{T("Width")}</Form.Label>
+                  <Col sm="8">
+                    <Form.Control
+                      type="number" min="1" max="12"
+                      value={widget.width || 6}
+                      onChange={e=>this.setWidgetField(
+                          idx, "width", parseInt(e.currentTarget.value) || 6)} />
+                  </Col>
+                </Form.Group>
+                <Form.Group as={Row}>
+                  <Form.Label column sm="3">
+                    {T("Refresh (seconds)")}
+                  </Form.Label>
+                  <Col sm="8">
+                    <Form.Control
+                      type="number" min="0"
+                      value={widget.refresh || 0}
+                      onChange={e=>this.setWidgetField(
+                          idx, "refresh", parseInt(e.currentTarget.value) || 0)} />
+                  </Col>
+                </Form.Group>
+                <Form.Group as={Row}>
+                  <Form.Label column sm="3">{T("VQL Query")}</Form.Label>
+                  <Col sm="8">
+                    <VeloAce text={widget.query || ""}
+                             mode="vql"
+                             onChange={x=>this.setWidgetField(idx, "query", x)}
+                    />
+                  </Col>
+                </Form.Group>
+              </Card.Body>
+            </Card>
+        );
+    }
+
+    render() {
+        let dashboard = this.state.dashboard;
+        return (
+            <Modal show={true}
+                   size="lg"
+                   dialogClassName="modal-90w"
+                   enforceFocus={false}
+                   scrollable={true}
+                   onHide={this.props.onClose}>
+              <Modal.Header closeButton>
+                <Modal.Title>{T("Edit Dashboard")}</Modal.Title>
+              </Modal.Header>
+              <Modal.Body>
+                <Form>
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Name")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Control
+                        value={dashboard.name || ""}
+                        onChange={e=>this.setField(
+                            "name", e.currentTarget.value)} />
+                    </Col>
+                  </Form.Group>
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Description")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Control
+                        as="textarea" rows={2}
+                        value={dashboard.description || ""}
+                        onChange={e=>this.setField(
+                            "description", e.currentTarget.value)} />
+                    </Col>
+                  </Form.Group>
+                  <Form.Group as={Row}>
+                    <Form.Label column sm="3">{T("Shared")}</Form.Label>
+                    <Col sm="8">
+                      <Form.Check
+                        type="checkbox"
+                        checked={dashboard.shared || false}
+                        label={T("Share this dashboard with all users")}
+                        onChange={e=>this.setField(
+                            "shared", e.currentTarget.checked)} />
+                    </Col>
+                  </Form.Group>
+                  { _.map(dashboard.widgets, this.renderWidget) }
+                </Form>
+              </Modal.Body>
+              <Modal.Footer>
+                <Button variant="default"
+                        className="mr-auto"
+                        onClick={this.addWidget}>
+                  <FontAwesomeIcon icon="plus"/>
+                  <span className="button-label">{T("Add Widget")}</span>
+                </Button>
+                <Button variant="secondary"
+                        onClick={this.props.onClose}>
+                  {T("Close")}
+                </Button>
+                <Button variant="primary"
+                        onClick={this.saveDashboard}>
+                  {T("Save")}
+                </Button>
+              </Modal.Footer>
+            </Modal>
+        );
+    }
+}
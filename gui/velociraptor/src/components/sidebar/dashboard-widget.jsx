@@ -0,0 +1,141 @@
+import React from 'react';
+import PropTypes from 'prop-types';
+
+import _ from 'lodash';
+import Card from 'react-bootstrap/Card';
+import Button from 'react-bootstrap/Button';
+import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
+import VeloTable from '../core/table.jsx';
+import { VeloTimeChart, VeloBarChart } from '../artifacts/line-charts.jsx';
+import Spinner from '../utils/spinner.jsx';
+import T from '../i8n/i8n.jsx';
+import api from '../core/api-service.jsx';
+import {CancelToken} from 'axios';
+
+
+// A single dashboard widget. The widget's VQL query is run on the
+// server and the rows are rendered according to the widget type.
+export default class DashboardWidget extends React.Component {
+    static propTypes = {
+        dashboard_id: PropTypes.string.isRequired,
+        widget: PropTypes.object.isRequired,
+
+        // Changing the version forces the widget to reload.
+        version: PropTypes.number,
+    };
+
+    state = {
+        rows: [],
+        loading: false,
+        error: "",
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+        this.fetchRows();
+        this.setTimer();
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+        if (this.interval) {
+            clearInterval(this.interval);
+        }
+    }
+
+    componentDidUpdate = (prevProps, prevState, rootNode) => {
+        if (prevProps.version !== this.props.version ||
+            prevProps.dashboard_id !== this.props.dashboard_id ||
+            !_.isEqual(prevProps.widget, this.props.widget)) {
+            this.fetchRows();
+            this.setTimer();
+        }
+    }
+
+    setTimer = () => {
+        if (this.interval) {
+            clearInterval(this.interval);
+            this.interval = undefined;
+        }
+
+        let refresh = this.props.widget.refresh || 0;
+        if (refresh > 0) {
+            this.interval = setInterval(this.fetchRows, refresh * 1000);
+        }
+    }
+
+    fetchRows = () => {
+        this.source.cancel();
+        this.source = CancelToken.source();
+
+        this.setState({loading: true});
+        api.post("v1/DashboardWidget", {
+            id: this.props.dashboard_id,
+            widget_id: this.props.widget.id,
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+
+            this.setState({
+                rows: response.data.rows || [],
+                loading: false,
+                error: "",
+            });
+        }).catch(err=>{
+            let data = err.response && err.response.data;
+            this.setState({loading: false, error: String(data || err)});
+        });
+    }
+
+    renderContent = () => {
+        let rows = this.state.rows;
+        if (this.state.error) {
+            return <div className="dashboard-widget-error">
+                     {this.state.error}
+                   </div>;
+        }
+
+        if (_.isEmpty(rows)) {
+            return <div className="no-content">{T("No data available")}</div>;
+        }
+
+        let columns = _.keys(rows[0]);
+        switch(this.props.widget.type) {
+        case "value": {
+            let value = rows[0][columns[0]];
+            return <div className="dashboard-widget-value">
+                     {_.isObject(value) ? JSON.stringify(value) : String(value)}
+                   </div>;
+        }
+
+        case "line_chart":
+            return <VeloTimeChart data={rows} columns={columns} params={{}}/>;
+
+        case "bar_chart":
+            return <VeloBarChart data={rows} columns={columns} params={{}}/>;
+
+        default:
+            return <VeloTable rows={rows} columns={columns}/>;
+        }
+    }
+
+    render() {
+        return (
+            <Card className="dashboard-widget">
+              <Card.Header>
+                { this.props.widget.title }
+                <Button variant="default"
+                        size="sm"
+                        className="float-right"
+                        disabled={this.state.loading}
+                        onClick={this.fetchRows}>
+                  <FontAwesomeIcon icon="sync"/>
+                </Button>
+              </Card.Header>
+              <Card.Body>
+                <Spinner loading={this.state.loading}/>
+                { this.renderContent() }
+              </Card.Body>
+            </Card>
+        );
+    }
+}
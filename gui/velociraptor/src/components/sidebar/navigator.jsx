@@ -168,6 +168,28 @@ class VeloNavigator extends Component {
                         </NavLink>
                       </li>
 
+                      <li className="nav-link">
+                        <NavLink to="/canaries">
+                          <span>
+                            <i className="navicon">
+                              <FontAwesomeIcon icon="flag" />
+                            </i>
+                          </span>
+                          {T("Canaries")}
+                        </NavLink>
+                      </li>
+
+                      <li className="nav-link">
+                        <NavLink to="/inventory">
+                          <span>
+                            <i className="navicon">
+                              <FontAwesomeIcon icon="list" />
+                            </i>
+                          </span>
+                          {T("Asset Inventory")}
+                        </NavLink>
+                      </li>
+
                       {user_is_admin && !customization.disable_user_management && (
                         <li className="nav-link">
                           <NavLink to="/users">
@@ -9,62 +9,96 @@ import Button from 'react-bootstrap/Button';
 import ButtonGroup from 'react-bootstrap/ButtonGroup';
 import Dropdown from 'react-bootstrap/Dropdown';
 import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
-import VeloReportViewer from "../artifacts/reporting.jsx";
+import DashboardWidget from './dashboard-widget.jsx';
+import DashboardEditor from './dashboard-editor.jsx';
 import T from '../i8n/i8n.jsx';
+import api from '../core/api-service.jsx';
+import {CancelToken} from 'axios';
 
 import { withRouter }  from "react-router-dom";
 
-const ranges = [
-    {desc: "Last Hour", sec: 60*60, sample: 1, rows: 400},
-    {desc: "Last Day", sec: 60*60*24, sample: 6, rows: 2000},
-    {desc: "Last 2 days", sec: 60*60*24*2, sample: 10, rows: 2000},
-    {desc: "Last Week", sec: 60*60*24*7, sample: 40, rows: 2000},
-  ];
-
 
+// The user's dashboards. Each dashboard is made of widgets driven by
+// server side VQL queries. Dashboards are stored per user and may be
+// shared with other users in the org.
 class UserDashboard extends React.Component {
     static propTypes = {
         // React router props.
         history: PropTypes.object,
     };
 
-    constructor(props) {
-        super(props);
-
-        let now = parseInt((new Date()).getTime() / 1000);
-        this.state = {
-            start_time: now - ranges[0].sec,
-            sample: ranges[0].sample,
-            desc: ranges[0].desc,
-            rows: ranges[0].rows,
-            version: 0,
-        };
+    state = {
+        dashboards: [],
+        selected_id: "",
+        version: 0,
+        showEditor: false,
+        editing: null,
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+        this.fetchDashboards();
     }
 
-    shouldComponentUpdate = (nextProps, nextState) => {
-        // Do not keep updating the dashboard - it is quite expensive
-        // and should be done sparingly unless the version is actually
-        // changed. The version is changed by pressing the "Refresh"
-        // Button or selecting a new range.
-        if (this.state.version !== nextState.version) {
-            return true;
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+    }
+
+    fetchDashboards = (selected_id) => {
+        api.get("v1/Dashboards", {}, this.source.token).then(response=>{
+            if (response.cancel) return;
+
+            let dashboards = response.data.dashboards || [];
+            selected_id = selected_id || this.state.selected_id;
+            if (!_.find(dashboards, x=>x.id === selected_id)) {
+                selected_id = dashboards.length > 0 ? dashboards[0].id : "";
+            }
+            this.setState({dashboards: dashboards,
+                           selected_id: selected_id});
+        });
+    }
+
+    getSelected = () => {
+        return _.find(this.state.dashboards,
+                      x=>x.id === this.state.selected_id);
+    }
+
+    newDashboard = () => {
+        this.setState({showEditor: true, editing: {
+            name: T("New Dashboard"),
+            widgets: [],
+        }});
+    }
+
+    deleteDashboard = () => {
+        let selected = this.getSelected();
+        if (!selected || selected.read_only) {
+            return;
         }
-        return false;
-    };
 
-    setRange = (desc) => {
-        // Current time in seconds.
-        let now = parseInt((new Date()).getTime() / 1000);
-        this.setState({start_time: now - desc.sec,
-                       desc: desc.desc,
-                       rows: desc.rows,
-                       version: this.state.version + 1,
-                       sample: desc.sample});
+        api.post("v1/Dashboards", {
+            id: selected.id,
+            delete: true,
+        }, this.source.token).then(response=>{
+            if (response.cancel) return;
+            this.fetchDashboards("");
+        });
     }
 
     render() {
+        let selected = this.getSelected();
         return (
             <>
+              { this.state.showEditor &&
+                <DashboardEditor
+                  dashboard={this.state.editing}
+                  onClose={()=>this.setState({showEditor: false})}
+                  onSave={dashboard=>{
+                      this.setState({showEditor: false});
+                      this.fetchDashboards(dashboard.id);
+                  }}
+                />
+              }
               <Navbar className="toolbar">
                 <ButtonGroup>
                   <Button variant="default"
@@ -81,23 +115,49 @@ class UserDashboard extends React.Component {
                           data-position="right"
                           className="btn-tooltip"
                           data-tooltip={T("Edit the dashboard")}
-                          onClick={() => {
-                      this.props.history.push("/artifacts/Server.Monitor.Health");
-                  }} >
+                          disabled={!selected}
+                          onClick={() => this.setState({
+                              showEditor: true, editing: selected})} >
                     <FontAwesomeIcon icon="pencil-alt"/>
                   </Button>
+
+                  <Button variant="default"
+                          data-position="right"
+                          className="btn-tooltip"
+                          data-tooltip={T("New dashboard")}
+                          onClick={this.newDashboard} >
+                    <FontAwesomeIcon icon="plus"/>
+                  </Button>
+
+                  <Button variant="default"
+                          data-position="right"
+                          className="btn-tooltip"
+                          data-tooltip={T("Delete dashboard")}
+                          disabled={!selected || selected.read_only}
+                          onClick={this.deleteDashboard} >
+                    <FontAwesomeIcon icon="trash"/>
+                  </Button>
                 </ButtonGroup>
                 <ButtonGroup className="float-right">
                   <Dropdown>
                     <Dropdown.Toggle variant="default">
                       <FontAwesomeIcon icon="book" />
-                      <span className="button-label">{this.state.desc}</span>
+                      <span className="button-label">
+                        { selected && selected.name }
+                      </span>
                     </Dropdown.Toggle>
                     <Dropdown.Menu>
-                      { _.map(ranges, (x, idx) => {
-                          return <Dropdown.Item key={idx}
-                                                onClick={() => this.setRange(x)} >
-                                   { x.desc }
+                      { _.map(this.state.dashboards, (x, idx) => {
+                          return <Dropdown.Item
+                                   key={idx}
+                                   active={x.id === this.state.selected_id}
+                                   onClick={() => this.setState({
+                                       selected_id: x.id})} >
+                                   { x.name }
+                                   { x.shared &&
+                                     <FontAwesomeIcon
+                                       className="dashboard-shared"
+                                       icon="users"/> }
                                  </Dropdown.Item>;
                       })}
                     </Dropdown.Menu>
@@ -105,13 +165,22 @@ class UserDashboard extends React.Component {
                 </ButtonGroup>
               </Navbar>
               <div className="dashboard">
-                <VeloReportViewer
-                  artifact="Custom.Server.Monitor.Health"
-                  type="SERVER_EVENT"
-                  params={{start_time: this.state.start_time,
-                           version: this.state.version,
-                           sample: this.state.sample}}
-                />
+                { selected && selected.description &&
+                  <div className="dashboard-description">
+                    { selected.description }
+                  </div>
+                }
+                <div className="row">
+                  { selected && _.map(selected.widgets, (widget, idx)=>{
+                      return <div key={selected.id + idx}
+                                  className={"col-" + (widget.width || 6)}>
+                               <DashboardWidget
+                                 dashboard_id={selected.id}
+                                 widget={widget}
+                                 version={this.state.version}/>
+                             </div>;
+                  })}
+                </div>
               </div>
             </>
         );
@@ -143,6 +143,7 @@ class UserSettings extends React.PureComponent {
                 theme: this.context.traits.theme || "veloci-light",
                 timezone: this.context.traits.timezone || "UTC",
                 lang: this.context.traits.lang || "en",
+                time_format: this.context.traits.time_format || "iso",
                 org: this.context.traits.org || "root",
                 default_password: this.context.traits.default_password || "",
             });
@@ -152,6 +153,7 @@ class UserSettings extends React.PureComponent {
     state = {
         theme: "",
         timezone: "",
+        time_format: "",
         default_password: "",
         org: "",
         org_changed: false,
@@ -162,6 +164,7 @@ class UserSettings extends React.PureComponent {
             theme: this.state.theme,
             timezone: this.state.timezone,
             lang: this.state.lang,
+            time_format: this.state.time_format,
             org: this.state.org,
             default_password: this.state.default_password,
         });
@@ -176,6 +179,7 @@ class UserSettings extends React.PureComponent {
             theme: this.state.theme,
             timezone: this.state.timezone,
             lang: this.state.lang,
+            time_format: this.state.time_format,
             org: org,
             default_password: this.state.default_password,
         });
@@ -338,6 +342,31 @@ class UserSettings extends React.PureComponent {
                   </Col>
                 </Form.Group>
 
+                <Form.Group as={Row}>
+                  <Form.Label column sm="3">
+                    <OverlayTrigger
+                      delay={{show: 250, hide: 400}}
+                      overlay={(props)=><Tooltip {...props}>
+                                          {T("How timestamps are displayed")}
+                                        </Tooltip>}>
+                      <div>{T("Timestamp format")}</div>
+                    </OverlayTrigger>
+                  </Form.Label>
+                  <Col sm="8">
+                    <Form.Control as="select"
+                                  value={this.state.time_format}
+                                  onChange={(e) => {
+                                      this.setState({time_format: e.currentTarget.value});
+                                      this.props.setSetting({
+                                          time_format: e.currentTarget.value,
+                                      });
+                                  }}>
+                      <option value="iso">{T("ISO 8601")}</option>
+                      <option value="localized">{T("Localized")}</option>
+                    </Form.Control>
+                  </Col>
+                </Form.Group>
+
               </Modal.Body>
               <Modal.Footer>
                 <Button variant="secondary" onClick={()=>{
@@ -403,6 +432,12 @@ export default class UserLabel extends React.Component {
           ace_options.theme = "ace/theme/vibrant_ink";
           ace_options.fontFamily = "Iosevka Term";
         }
+
+        // The timestamp format is stored with the editor options.
+        if (options.time_format) {
+            ace_options.time_format = options.time_format;
+        }
+        delete options.time_format;
         options.options = JSON.stringify(ace_options);
 
         api.post("v1/SetGUIOptions", options, this.source.token).then((response) => {
@@ -458,6 +493,17 @@ export default class UserLabel extends React.Component {
                   }
                   { this.orgName() }
                 </Button>
+                { this.context.traits.redacted &&
+                  <Button variant="default"
+                          className="btn-tooltip"
+                          data-tooltip={T("Sensitive data is redacted")}
+                          data-position="left">
+                    <FontAwesomeIcon icon="eye-slash" />
+                    <span className="sr-only">
+                      {T("Sensitive data is redacted")}
+                    </span>
+                  </Button>
+                }
                 <Button variant="default"
                   onClick={()=>this.setState({showUserSettings: true})}
                 >
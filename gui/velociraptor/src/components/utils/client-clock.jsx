@@ -0,0 +1,166 @@
+import React from 'react';
+import PropTypes from 'prop-types';
+
+import _ from 'lodash';
+import Button from 'react-bootstrap/Button';
+import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
+import api from '../core/api-service.jsx';
+import {CancelToken} from 'axios';
+import T from '../i8n/i8n.jsx';
+
+// How timestamps collected from the client are displayed:
+//  server    - as recorded, in the user's timezone.
+//  local     - in the client's own timezone.
+//  corrected - corrected for the client's clock skew.
+export const CLOCK_MODES = ["server", "local", "corrected"];
+
+// Skews below this are measurement noise (see
+// services/interrogation/clock.go)
+const SKEW_TOLERANCE_SEC = 5;
+
+export const ClientClock = React.createContext({
+    clock: null,
+    mode: "server",
+    setMode: () => {},
+});
+
+// Adjust a timestamp for the client's clock. Returns the adjusted
+// moment and the timezone to display it in (or undefined to use the
+// user's timezone).
+export const adjustForClock = (when, clock, mode) => {
+    if (!clock || mode === "server") {
+        return {when: when};
+    }
+
+    if (mode === "local") {
+        return {when: when, timezone: clock.timezone,
+                offset: clock.utc_offset};
+    }
+
+    let skew = clock.skew || 0;
+    if (Math.abs(skew) < SKEW_TOLERANCE_SEC) {
+        return {when: when};
+    }
+    return {when: when.clone().subtract(skew * 1000, "ms")};
+};
+
+// Provides the client's clock to all timestamps rendered for the
+// client's collections.
+export class ClientClockProvider extends React.Component {
+    static propTypes = {
+        client_id: PropTypes.string,
+        children: PropTypes.node,
+    }
+
+    state = {
+        clock: null,
+        mode: "server",
+        setMode: mode=>this.setState({mode: mode}),
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+        this.fetchClock();
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+    }
+
+    componentDidUpdate(prevProps) {
+        if (prevProps.client_id !== this.props.client_id) {
+            this.fetchClock();
+        }
+    }
+
+    fetchClock = () => {
+        this.setState({clock: null, mode: "server"});
+        if (!this.props.client_id || this.props.client_id === "server") {
+            return;
+        }
+
+        api.get("v1/ClientClock", {client_id: this.props.client_id},
+                this.source.token).then(response=>{
+                    if (response.cancel) return;
+
+                    // The client never reported its clock.
+                    if (!response.data.flow_id) {
+                        return;
+                    }
+                    this.setState({clock: response.data});
+                });
+    }
+
+    render() {
+        return (
+            <ClientClock.Provider value={this.state}>
+              { this.props.children }
+            </ClientClock.Provider>
+        );
+    }
+}
+
+// A toolbar button cycling through the clock display modes.
+export class ClientClockToggle extends React.Component {
+    static contextType = ClientClock;
+
+    nextMode = () => {
+        let idx = _.indexOf(CLOCK_MODES, this.context.mode);
+        this.context.setMode(CLOCK_MODES[(idx + 1) % CLOCK_MODES.length]);
+    }
+
+    render() {
+        let clock = this.context.clock;
+        if (!clock) {
+            return <></>;
+        }
+
+        let labels = {
+            server: T("Showing recorded client times"),
+            local: T("Showing client local time"),
+            corrected: T("Showing times corrected for clock skew"),
+        };
+        let tooltip = labels[this.context.mode] + " (" + clock.timezone +
+            ", " + T("Clock Skew") + " " + clock.skew + "s)";
+        return (
+            <Button data-tooltip={tooltip} size="sm"
+                    data-position="right"
+                    className="btn-tooltip"
+                    variant={this.context.mode === "server" ?
+                             "default" : "primary"}
+                    onClick={this.nextMode}>
+              <FontAwesomeIcon icon="clock"/>
+              <span className="sr-only">{tooltip}</span>
+            </Button>
+        );
+    }
+}
+
+// Summary of the client's clock for the host information page.
+export class ClientClockSummary extends React.Component {
+    static contextType = ClientClock;
+
+    render() {
+        let clock = this.context.clock;
+        if (!clock) {
+            return <></>;
+        }
+
+        let skew = clock.skew || 0;
+        return (
+            <>
+              <dt className="col-sm-3">{T("Timezone")}</dt>
+              <dd className="col-sm-9">
+                { clock.timezone } (UTC{ clock.utc_offset >= 0 ? "+" : "" }
+                { clock.utc_offset / 3600 })
+              </dd>
+
+              <dt className="col-sm-3">{T("Clock Skew")}</dt>
+              <dd className={Math.abs(skew) < SKEW_TOLERANCE_SEC ?
+                             "col-sm-9" : "col-sm-9 text-danger"}>
+                { skew }s
+              </dd>
+            </>
+        );
+    }
+}
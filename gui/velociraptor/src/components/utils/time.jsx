@@ -8,6 +8,8 @@ import Tooltip from 'react-bootstrap/Tooltip';
 import OverlayTrigger from 'react-bootstrap/OverlayTrigger';
 import T from '../i8n/i8n.jsx';
 import UserConfig from '../core/user.jsx';
+import { ClientClock, adjustForClock } from './client-clock.jsx';
+import { formatLocalizedTime } from '../i8n/format.jsx';
 
 const renderToolTip = (props, ts) => {
     let now = new Date().getTime();
@@ -103,12 +105,36 @@ class VeloTimestamp extends Component {
         }
 
         let timezone = this.context.traits.timezone || "UTC";
-        var when = moment(ts);
+        return <ClientClock.Consumer>
+                 { value=>this.renderTime(ts, timezone, value) }
+               </ClientClock.Consumer>;
+    };
+
+    // When showing a client's collections, the time may be adjusted
+    // for the client's clock.
+    renderTime = (ts, timezone, client_clock) => {
+        let adjusted = adjustForClock(
+            moment(ts), client_clock.clock, client_clock.mode);
+        let when;
+        if (adjusted.timezone && moment.tz.zone(adjusted.timezone)) {
+            when = moment.tz(adjusted.when, adjusted.timezone);
+        } else if (!_.isUndefined(adjusted.offset)) {
+            when = adjusted.when.utcOffset(adjusted.offset / 60);
+        } else {
+            when = moment.tz(adjusted.when, timezone);
+        }
+
+        // Timestamps are shown in ISO format unless the user prefers
+        // their locale's format.
+        let formatted = this.context.traits.time_format === "localized" ?
+            formatLocalizedTime(when) : when.format();
+
         return <OverlayTrigger
                  delay={{show: 250, hide: 400}}
                  overlay={(props)=>renderToolTip(props, ts)}>
-                 <div className="timestamp">
-                   {moment.tz(when, timezone).format()}
+                 <div className={client_clock.clock && client_clock.mode !== "server" ?
+                                 "timestamp client-clock" : "timestamp"}>
+                   {formatted}
                  </div>
                </OverlayTrigger>;
     };
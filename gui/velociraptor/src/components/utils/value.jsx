@@ -3,8 +3,14 @@ import PropTypes from 'prop-types';
 import _ from 'lodash';
 import ReactJson from 'react-json-view';
 import UserConfig from '../core/user.jsx';
+import T from '../i8n/i8n.jsx';
 import VeloTimestamp from "./time.jsx";
 import ContextMenu from './context.jsx';
+import './value.css';
+
+// Placeholder for values redacted by the server for auditors (see
+// api/tables/redact.go).
+const REDACTED = "[REDACTED]";
 
 // Try to detect something that looks like a timestamp.
 const timestamp_regex = /(\d{4}-[01]\d-[0-3]\dT[0-2]\d:[0-5]\d:[0-5]\d(?:\.\d+)?(?:[+-][0-2]\d:?[0-5]\d|Z))/;
@@ -91,6 +97,11 @@ export default class VeloValueRenderer extends React.Component {
     render() {
         let v = this.props.value;
 
+        if (v === REDACTED && this.context.traits &&
+            this.context.traits.redacted) {
+            return <span className="redacted">{T("Redacted")}</span>;
+        }
+
         if (_.isString(v)) {
             return <ContextMenu value={v}>{this.maybeFormatTime(v)}</ContextMenu>;
         }
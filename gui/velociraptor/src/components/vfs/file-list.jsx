@@ -10,6 +10,7 @@ import Navbar from 'react-bootstrap/Navbar';
 import Button from 'react-bootstrap/Button';
 import ButtonGroup from 'react-bootstrap/ButtonGroup';
 import Modal from 'react-bootstrap/Modal';
+import Form from 'react-bootstrap/Form';
 import VeloTimestamp from "../utils/time.jsx";
 import VeloPagedTable from '../core/paged-table.jsx';
 
@@ -148,6 +149,95 @@ class ExportDialog extends Component {
     }
 }
 
+// Compare the current listing of the directory with a previous
+// refresh.
+class DiffDialog extends Component {
+    static propTypes = {
+        client: PropTypes.object,
+        node: PropTypes.object,
+        onClose: PropTypes.func.isRequired,
+    }
+
+    state = {
+        previous: [],
+        flow_id: "",
+        loading: true,
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+        api.get("v1/VFSStatDirectory", {
+            client_id: this.props.client.client_id,
+            vfs_components: this.props.node.path,
+        }, this.source.token).then(response=>{
+            if (response.data.cancel) {
+                return;
+            }
+            let previous = response.data.previous || [];
+            this.setState({
+                previous: previous,
+                flow_id: previous.length > 0 ? previous[0].flow_id : "",
+                loading: false,
+            });
+        });
+    }
+
+    componentWillUnmount() {
+        this.source.cancel();
+    }
+
+    render() {
+        let path = "/" + this.props.node.path.join("/");
+        return (
+            <Modal show={true}
+                   dialogClassName="modal-90w"
+                   onHide={this.props.onClose}>
+              <Modal.Header closeButton>
+                <Modal.Title>{T("Compare with a previous refresh")}</Modal.Title>
+              </Modal.Header>
+              <Modal.Body>
+                <div className="vfspath">{path}</div>
+                { _.isEmpty(this.state.previous) ?
+                  !this.state.loading &&
+                  <h5 className="no-content">
+                    {T("This directory was not refreshed before.")}
+                  </h5>
+                  :
+                  <>
+                    <Form.Control as="select"
+                                  value={this.state.flow_id}
+                                  onChange={e=>this.setState({
+                                      flow_id: e.currentTarget.value})}>
+                      {_.map(this.state.previous, x=>{
+                          return <option key={x.flow_id} value={x.flow_id}>
+                                   {x.flow_id + " (" + (x.total_rows || 0) +
+                                    " " + T("files") + ")"}
+                                 </option>;
+                      })}
+                    </Form.Control>
+                    <VeloPagedTable
+                      url="v1/VFSListDirectoryFiles"
+                      params={{
+                          client_id: this.props.client.client_id,
+                          vfs_components: this.props.node.path,
+                          flow_id: this.state.flow_id,
+                          type: "diff",
+                      }}
+                      version={{flow_id: this.state.flow_id}}
+                    />
+                  </>
+                }
+              </Modal.Body>
+              <Modal.Footer>
+                <Button variant="secondary" onClick={this.props.onClose}>
+                  {T("Close")}
+                </Button>
+              </Modal.Footer>
+            </Modal>
+        );
+    }
+}
+
 
 function DownloadContextMenu({children, value}) {
     const { show } = useContextMenu({
@@ -185,11 +275,16 @@ class VeloFileList extends Component {
         // Manage recursive VFS downloads
         showDownloadAllDialog: false,
         showExportDialog: false,
+        showDiffDialog: false,
         lastRecursiveDownloadFlowId: null,
         lastRecursiveDownloadData: {},
         selectedFile: "",
 
         selectedTableIdx: 0,
+
+        // When set we are selecting files to collect in bulk. Maps
+        // file names to true.
+        bulkSelection: null,
     }
 
     componentDidMount = () => {
@@ -207,6 +302,35 @@ class VeloFileList extends Component {
         }
     }
 
+    toggleBulkSelection = (row) => {
+        let selection = Object.assign({}, this.state.bulkSelection);
+        if (selection[row.Name]) {
+            delete selection[row.Name];
+        } else {
+            selection[row.Name] = true;
+        }
+        this.setState({bulkSelection: selection});
+    }
+
+    // Collect all the selected files in the one collection.
+    collectBulkSelection = () => {
+        let names = _.keys(this.state.bulkSelection);
+        let path = this.props.node.path || [];
+        if (_.isEmpty(names) || _.isEmpty(path)) {
+            return;
+        }
+
+        api.post("v1/VFSDownloadFile", {
+            client_id: this.props.client.client_id,
+            accessor: path[0],
+            components: path.slice(1),
+            names: names,
+        }, this.source.token).then(response => {
+            this.setState({bulkSelection: null});
+            this.props.bumpVersion();
+        });
+    }
+
     updateCurrentFile = (row) => {
         // We store the currently selected row in the node. When the
         // user updates the row, we change the node's internal
@@ -309,17 +433,11 @@ class VeloFileList extends Component {
 
         // path is a list of components starting with the accessor
         let accessor = node.path[0];
-        api.post("v1/CollectArtifact", {
-            urgent: true,
+        api.post("v1/VFSDownloadFile", {
             client_id: this.props.client.client_id,
-            artifacts: ["System.VFS.DownloadFile"],
-            specs: [{artifact: "System.VFS.DownloadFile",
-                     parameters: {"env": [
-                         { "key": "Components",
-                           "value": JSON.stringify(node.path.slice(1))},
-                         { "key": "Accessor", "value": accessor},
-                         { "key": "Recursively", "value": "Y"}]}}],
-            max_upload_bytes: 1048576000,
+            accessor: accessor,
+            components: node.path.slice(1),
+            recursively: true,
         }, this.source.token).then((response) => {
             // Hold onto the flow id so we can track progress.
             this.setState({
@@ -442,6 +560,13 @@ class VeloFileList extends Component {
             selected: [this.state.selectedFile],
         };
 
+        // In bulk selection mode clicking a row toggles it.
+        let bulkSelection = this.state.bulkSelection;
+        if (bulkSelection) {
+            selectRow.onSelect = this.toggleBulkSelection;
+            selectRow.selected = [];
+        }
+
         let toolbar = (
             <>
               <ButtonGroup className="float-right vfs-toolbar">
@@ -512,6 +637,45 @@ class VeloFileList extends Component {
                   </Button>
                 }
 
+                { bulkSelection ?
+                  <>
+                    <Button data-tooltip={T("Collect the selected files from the client")}
+                            data-position="left"
+                            className="btn-tooltip"
+                            disabled={_.isEmpty(bulkSelection)}
+                            onClick={this.collectBulkSelection}
+                            variant="default">
+                      <FontAwesomeIcon icon="download"/>
+                      <span className="button-label">
+                        {_.size(bulkSelection) + " " + T("files")}
+                      </span>
+                    </Button>
+                    <Button data-tooltip={T("Cancel")}
+                            data-position="left"
+                            className="btn-tooltip"
+                            onClick={()=>this.setState({bulkSelection: null})}
+                            variant="default">
+                      <FontAwesomeIcon icon="stop"/>
+                    </Button>
+                  </> :
+                  <Button data-tooltip={T("Select files to collect from the client")}
+                          data-position="left"
+                          className="btn-tooltip"
+                          disabled={!this.props.node.flow_id}
+                          onClick={()=>this.setState({bulkSelection: {}})}
+                          variant="default">
+                    <FontAwesomeIcon icon="square-check"/>
+                  </Button>
+                }
+                <Button data-tooltip={T("Compare with a previous refresh")}
+                        data-position="left"
+                        className="btn-tooltip"
+                        disabled={!this.props.node.flow_id}
+                        onClick={()=>this.setState({showDiffDialog: true})}
+                        variant="default">
+                  <FontAwesomeIcon icon="code-compare"/>
+                </Button>
+
                 <Link to={"/collected/" +this.props.client.client_id +
                           "/" + this.props.node.flow_id + "/overview"}
                       data-tooltip={T("View Collection")}
@@ -678,6 +842,12 @@ class VeloFileList extends Component {
                   onCancel={()=>this.setState({showExportDialog: false})}
                   onClose={()=>this.setState({showExportDialog: false})}
                 />}
+              { this.state.showDiffDialog &&
+                <DiffDialog
+                  node={this.props.node}
+                  client={this.props.client}
+                  onClose={()=>this.setState({showDiffDialog: false})}
+                />}
               <div className="fill-parent no-margins selectable">
                 <VeloPagedTable
                   url="v1/VFSListDirectoryFiles"
@@ -691,6 +861,8 @@ class VeloFileList extends Component {
                       vfs_components: this.props.node.path,
                   }}
                   selectRow={ selectRow }
+                  row_classes={row=>bulkSelection && bulkSelection[row.Name] ?
+                               "row-selected" : ""}
                   toolbar={toolbar}
                   initial_page_size={5}
                   row_filter={row_filter}
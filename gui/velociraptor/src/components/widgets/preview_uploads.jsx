@@ -239,6 +239,109 @@ class HexViewTab  extends React.PureComponent {
     }
 }
 
+// Shows a preview of the file rendered on the server.
+class FilePreviewTab extends React.PureComponent {
+    static propTypes = {
+        params:  PropTypes.object,
+        type: PropTypes.string,
+    }
+
+    state = {
+        loading: true,
+        preview: undefined,
+        error: "",
+    }
+
+    componentDidMount = () => {
+        this.source = CancelToken.source();
+        this.fetchPreview_();
+    }
+
+    componentWillUnmount() {
+        this.source.cancel("unmounted");
+    }
+
+    componentDidUpdate = (prevProps, prevState, rootNode) => {
+        if (!_.isEqual(prevProps.params, this.props.params) ||
+            prevProps.type !== this.props.type) {
+            this.fetchPreview_();
+        };
+    }
+
+    fetchPreview_ = () => {
+        let params = Object.assign({}, this.props.params);
+        params.offset = 0;
+        params.length = 0;
+
+        this.source.cancel();
+        this.source = CancelToken.source();
+
+        this.setState({loading: true, error: ""});
+
+        // First detect the type of the file if needed.
+        let type = this.props.type;
+        if (type !== "structure") {
+            params.type = type;
+            this.getPreview_(params);
+            return;
+        }
+
+        api.get("v1/PreviewVFSFile", params, this.source.token).then(response=>{
+            if (response.cancel) return;
+
+            let detected = response.data && response.data.type;
+            if (detected !== "pe" && detected !== "registry") {
+                this.setState({loading: false, preview: undefined,
+                               error: T("No structured preview available")});
+                return;
+            }
+            params.type = detected;
+            this.getPreview_(params);
+        }).catch(err=>{
+            this.setState({loading: false, error: String(err)});
+        });
+    }
+
+    getPreview_ = params=>{
+        api.get("v1/PreviewVFSFile", params, this.source.token).then(response=>{
+            if (response.cancel) return;
+
+            this.setState({loading: false, preview: response.data});
+        }).catch(err=>{
+            let data = err.response && err.response.data;
+            this.setState({loading: false,
+                           error: (data && data.message) || String(err)});
+        });
+    }
+
+    render() {
+        if (this.state.error) {
+            return <div className="preview-json">{this.state.error}</div>;
+        }
+
+        if (this.props.type === "strings" && _.isArray(this.state.preview)) {
+            return (
+                <div className="panel textdump">
+                  <Spinner loading={this.state.loading}/>
+                  {_.map(this.state.preview, (x, idx)=>{
+                      return <div key={idx}>
+                               {"0x" + x.offset.toString(16) + " " +
+                                x.encoding + " " + x.value}
+                             </div>;
+                  })}
+                </div>
+            );
+        }
+
+        return (
+            <div className="preview-json">
+              <Spinner loading={this.state.loading}/>
+              <VeloValueRenderer value={this.state.preview}/>
+            </div>
+        );
+    }
+}
+
 class InspectDialog extends React.PureComponent {
     static propTypes = {
         params:  PropTypes.object,
@@ -273,6 +376,16 @@ class InspectDialog extends React.PureComponent {
                                   size={this.props.size}
                       />}
                   </Tab>
+                  <Tab eventKey="strings" title={T("Strings")}>
+                    { this.state.tab === "strings" &&
+                      <FilePreviewTab params={this.props.params}
+                                      type="strings"/>}
+                  </Tab>
+                  <Tab eventKey="structure" title={T("Structure")}>
+                    { this.state.tab === "structure" &&
+                      <FilePreviewTab params={this.props.params}
+                                      type="structure"/>}
+                  </Tab>
                   <Tab eventKey="details" title={T("Details")}>
                     { this.state.tab === "details" &&
                       <div className="preview-json">
@@ -411,15 +524,15 @@ export default class PreviewUpload extends Component {
 
         // Match the data in case it is an image
         if (checkMime(this.state.view)) {
+            // The server scales the image and strips any metadata
+            // (e.g. EXIF) from it.
             let params = {
                 client_id: this.props.env.client_id,
                 org_id: window.globals.OrgId || "root",
-
-                // Only view first 1mb
-                length: 1000000,
+                type: "thumbnail",
             };
             params["fs_components[]"] = this.state.params.fs_components;
-            let url = api.base_path + "/api/" + this.state.url + "?" +
+            let url = api.base_path + "/api/v1/PreviewVFSFile?" +
                  qs.stringify(params, {indices: false});
             string_data = <img className="preview-thumbnail"
                                src={url} alt="preview upload" />;
@@ -25,7 +25,7 @@ import { faHome, faCrosshairs, faWrench, faEye, faServer, faBook, faLaptop,
          faInfo, faBug, faUser, faList, faIndent, faTextHeight, faBars,
          faUserLargeSlash, faTriangleExclamation, faCircle, faAnglesLeft, faMaximize,
          faMinimize, faNoteSticky, faArrowsUpDown, faBan, faFileExport, faCircleExclamation,
-         faTable,
+         faTable, faUsers, faSquareCheck, faCodeCompare,
        } from '@fortawesome/free-solid-svg-icons';
 
 library.add(faHome, faCrosshairs, faWrench, faEye, faServer, faBook, faLaptop,
@@ -44,7 +44,7 @@ library.add(faHome, faCrosshairs, faWrench, faEye, faServer, faBook, faLaptop,
             faTextHeight, faBars, faUserLargeSlash, faTriangleExclamation,
             faCircle, faAnglesLeft, faMaximize, faMinimize, faNoteSticky,
             faArrowsUpDown, faBan, faFileExport, faCircleExclamation,
-            faTable,
+            faTable, faUsers, faSquareCheck, faCodeCompare,
            );
 
 ReactDOM.render(
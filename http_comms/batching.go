@@ -0,0 +1,133 @@
+package http_comms
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Number of recent POSTs used to estimate the round trip time.
+	BATCH_RTT_SAMPLES = 20
+
+	// Batch for long enough that the round trip is only a small
+	// part of the time it takes to send each batch.
+	BATCH_RTT_MULTIPLIER = 10
+
+	// Weight given to each new bandwidth measurement.
+	BATCH_BANDWIDTH_WEIGHT = 0.2
+
+	// Do not send batches smaller than this.
+	MIN_BATCH_SIZE = 64 * 1024
+)
+
+// Estimates the link to the server from the POSTs the sender makes
+// so non urgent messages can be combined into fewer, larger POSTs on
+// slow or high latency links. Fewer POSTs reduce the request
+// overheads for both the client's link and the frontend.
+type AdaptiveBatcher struct {
+	mu sync.Mutex
+
+	// The duration of the most recent POSTs. The shortest is our
+	// best estimate of the round trip time because longer POSTs
+	// include upload time and server processing.
+	samples []time.Duration
+	next    int
+
+	// Estimated bytes per second.
+	bandwidth float64
+
+	min_wait, max_wait time.Duration
+}
+
+// Record a successful POST of size bytes.
+func (self *AdaptiveBatcher) Record(size int, duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if len(self.samples) < BATCH_RTT_SAMPLES {
+		self.samples = append(self.samples, duration)
+	} else {
+		self.samples[self.next] = duration
+		self.next = (self.next + 1) % BATCH_RTT_SAMPLES
+	}
+
+	// Small POSTs are dominated by the round trip so do not tell us
+	// much about the bandwidth.
+	transfer_time := duration - self._rtt()
+	if size < MIN_BATCH_SIZE || transfer_time <= 0 {
+		return
+	}
+
+	bandwidth := float64(size) / transfer_time.Seconds()
+	if self.bandwidth == 0 {
+		self.bandwidth = bandwidth
+	} else {
+		self.bandwidth = BATCH_BANDWIDTH_WEIGHT*bandwidth +
+			(1-BATCH_BANDWIDTH_WEIGHT)*self.bandwidth
+	}
+}
+
+func (self *AdaptiveBatcher) _rtt() time.Duration {
+	var result time.Duration
+	for _, sample := range self.samples {
+		if result == 0 || sample < result {
+			result = sample
+		}
+	}
+	return result
+}
+
+// How long to wait for more messages before sending the next batch.
+func (self *AdaptiveBatcher) Wait() time.Duration {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	wait := self._rtt() * BATCH_RTT_MULTIPLIER
+	if wait < self.min_wait {
+		return self.min_wait
+	}
+
+	if wait > self.max_wait {
+		return self.max_wait
+	}
+	return wait
+}
+
+// Send the batch early once it is larger than what the link can
+// upload while we wait for the next batch. Otherwise the backlog
+// would grow on slow links.
+func (self *AdaptiveBatcher) BatchSize(max_size uint64) uint64 {
+	wait := self.Wait()
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.bandwidth == 0 {
+		return max_size
+	}
+
+	size := uint64(self.bandwidth * wait.Seconds())
+	if size < MIN_BATCH_SIZE {
+		size = MIN_BATCH_SIZE
+	}
+
+	if size > max_size {
+		return max_size
+	}
+	return size
+}
+
+func NewAdaptiveBatcher(min_wait, max_wait time.Duration) *AdaptiveBatcher {
+	if max_wait < min_wait {
+		max_wait = min_wait
+	}
+
+	return &AdaptiveBatcher{
+		min_wait: min_wait,
+		max_wait: max_wait,
+	}
+}
@@ -0,0 +1,40 @@
+package http_comms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveBatcher(t *testing.T) {
+	batcher := NewAdaptiveBatcher(time.Second, 10*time.Second)
+
+	// Without any measurements we use the configured poll time and
+	// the largest batches.
+	assert.Equal(t, time.Second, batcher.Wait())
+	assert.Equal(t, uint64(5*1024*1024), batcher.BatchSize(5*1024*1024))
+
+	// A fast link keeps the minimum wait.
+	batcher.Record(1024, 20*time.Millisecond)
+	assert.Equal(t, time.Second, batcher.Wait())
+
+	// A slow, high latency link waits longer but never longer than
+	// the maximum.
+	batcher = NewAdaptiveBatcher(time.Second, 10*time.Second)
+	batcher.Record(1024, 500*time.Millisecond)
+	assert.Equal(t, 5*time.Second, batcher.Wait())
+
+	batcher.Record(1024, 2*time.Second)
+	assert.Equal(t, 5*time.Second, batcher.Wait())
+
+	// 100kb uploaded in 2 seconds after the round trip: 50kb/s
+	batcher.Record(100*1024, 2500*time.Millisecond)
+	assert.Equal(t, uint64(250*1024), batcher.BatchSize(5*1024*1024))
+
+	// Batches are never smaller than the minimum.
+	batcher = NewAdaptiveBatcher(time.Second, 10*time.Second)
+	batcher.Record(1024, 10*time.Millisecond)
+	batcher.Record(100*1024, 10*time.Second)
+	assert.Equal(t, uint64(MIN_BATCH_SIZE), batcher.BatchSize(5*1024*1024))
+}
@@ -28,6 +28,7 @@ import (
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -37,6 +38,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/time/rate"
 	"google.golang.org/protobuf/proto"
+	"www.velocidex.com/golang/velociraptor/actions"
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/constants"
@@ -44,8 +46,8 @@ import (
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
 	"www.velocidex.com/golang/velociraptor/crypto/storage"
 	"www.velocidex.com/golang/velociraptor/executor"
-	"www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services/writeback"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql/networking"
 )
@@ -64,6 +66,16 @@ var (
 	proxyHandler = http.ProxyFromEnvironment
 )
 
+// Sent from connector's Post() when the server is overloaded and
+// asks us to retry later (HTTP 503 with a Retry-After header).
+type RetryAfterError struct {
+	Wait time.Duration
+}
+
+func (self *RetryAfterError) Error() string {
+	return fmt.Sprintf("Server asked to retry after %v", self.Wait)
+}
+
 // Responsible for maybe enrolling the client. Enrollments should not
 // be done too frequently and should only be done in response for the
 // 406 HTTP codes.
@@ -121,6 +133,9 @@ type IConnector interface {
 		data []byte, priority bool) (*bytes.Buffer, error)
 	ReKeyNextServer(ctx context.Context)
 	ServerName() string
+
+	// The compression negotiated with the current server.
+	Compression() crypto_proto.PackedMessageList_CompressionType
 }
 
 // Responsible for using HTTP to talk with the end point.
@@ -152,6 +167,11 @@ type HTTPConnector struct {
 	// an error occurs or we are further redirected.
 	redirect_to_server int
 
+	// The compression the client would like to use and the
+	// compression the current server accepts.
+	preferred_compression crypto_proto.PackedMessageList_CompressionType
+	compression           crypto_proto.PackedMessageList_CompressionType
+
 	clock utils.Clock
 }
 
@@ -207,12 +227,22 @@ func NewHTTPConnector(
 		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
 	}
 
+	preferred_compression, err := crypto.ParseCompression(
+		config_obj.Client.PreferredCompression)
+	if err != nil {
+		logger.Info("%v - using %v instead.", err,
+			crypto.CompressionName(preferred_compression))
+	}
+
 	self := &HTTPConnector{
 		config_obj: config_obj,
 		manager:    manager,
 		logger:     logger,
 		clock:      clock,
 
+		preferred_compression: preferred_compression,
+		compression:           crypto.DEFAULT_COMPRESSION,
+
 		// Start with a random URL from the set of
 		// preconfigured URLs. This should distribute clients
 		// randomly to all frontends.
@@ -339,6 +369,7 @@ func (self *HTTPConnector) Post(
 		// frontend. Clearing the server name will force
 		// rekey to that server.
 		self.server_name = ""
+		self.resetCompression()
 
 		self.logger.Info("Redirecting to frontend: %v", dest[0])
 
@@ -363,6 +394,8 @@ func (self *HTTPConnector) Post(
 		return nil, EnrolError
 
 	case 200:
+		self.negotiateCompression(resp.Header.Get(crypto.ACCEPT_ENCODING_HEADER))
+
 		encrypted := &bytes.Buffer{}
 
 		// We need to be able to cancel the read here so we do not use
@@ -383,6 +416,17 @@ func (self *HTTPConnector) Post(
 		return encrypted, nil
 
 	default:
+		// The frontend is up but overloaded - retry the same
+		// frontend once it is ready for us.
+		retry_after, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if err == nil && retry_after > 0 {
+			self.logger.Info("Post to %v returned %v - retrying after %v seconds\n",
+				self.GetCurrentUrl(handler), resp.StatusCode, retry_after)
+			return nil, &RetryAfterError{
+				Wait: time.Duration(retry_after) * time.Second,
+			}
+		}
+
 		self.logger.Info("Post to %v returned %v - advancing\n",
 			self.GetCurrentUrl(handler), resp.StatusCode)
 
@@ -407,6 +451,7 @@ func (self *HTTPConnector) advanceToNextServer(ctx context.Context) {
 	self.current_url_idx = ((self.current_url_idx + 1) % len(self.urls))
 	self.redirect_to_server = 0
 	self.server_name = ""
+	self.compression = crypto.DEFAULT_COMPRESSION
 
 	// We are cycling through all our frontend's PEM keys
 	// in this loop. Once we go all the way around we
@@ -483,6 +528,39 @@ func (self *HTTPConnector) ServerName() string {
 	return self.server_name
 }
 
+func (self *HTTPConnector) Compression() crypto_proto.PackedMessageList_CompressionType {
+	if self.config_obj.Client.DisableCompression {
+		return crypto_proto.PackedMessageList_UNCOMPRESSED
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.compression
+}
+
+func (self *HTTPConnector) negotiateCompression(accept_encoding string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	compression := crypto.NegotiateCompression(
+		accept_encoding, self.preferred_compression)
+	if compression != self.compression {
+		self.logger.Info("Switching to %v compression for %v",
+			crypto.CompressionName(compression),
+			self.urls[self.current_url_idx])
+		self.compression = compression
+	}
+}
+
+// A new server may not support our compression.
+func (self *HTTPConnector) resetCompression() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.compression = crypto.DEFAULT_COMPRESSION
+}
+
 func (self *HTTPConnector) rekeyNextServer(ctx context.Context) error {
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -574,6 +652,10 @@ type NotificationReader struct {
 
 	limiter *rate.Limiter
 
+	// Measures the POSTs to adapt batching. Only used by the
+	// Sender.
+	batcher *AdaptiveBatcher
+
 	// Pause the PumpRingBufferToSendMessage loop - stops transmitting
 	// data to the server temporarily. New data will still be queued
 	// in the ring buffer if there is room.
@@ -671,6 +753,28 @@ func (self *NotificationReader) sendMessageList(
 				continue
 			}
 
+			// The server is overloaded - wait as long as it asked
+			// us to, plus some jitter to avoid all clients
+			// returning at the same time.
+			retry_after := &RetryAfterError{}
+			if errors.As(err, &retry_after) {
+				wait := retry_after.Wait + time.Duration(
+					GetRand()(int(self.maxPollDev)))*time.Second
+				self.logger.Info("Server overloaded, sleeping for %v", wait)
+
+				if executor.Nanny != nil {
+					executor.Nanny.UpdatePumpRbToServer()
+					executor.Nanny.UpdateReadFromServer()
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-self.clock.After(wait):
+				}
+				continue
+			}
+
 			// Failed to fetch the URL - This could happen because
 			// the server is overloaded, or the client is off the
 			// network. We need to back right off and retry the
@@ -714,6 +818,14 @@ func (self *NotificationReader) sendToURL(
 		self.connector.ReKeyNextServer(ctx)
 	}
 
+	// We may have switched to a server which does not support the
+	// compression these messages were prepared with.
+	message_list, compression, err = self.maybeRecompress(
+		ctx, message_list, compression)
+	if err != nil {
+		return err
+	}
+
 	// Clients always compress messages to the server.
 	cipher_text, err := self.manager.Encrypt(
 		message_list,
@@ -734,8 +846,12 @@ func (self *NotificationReader) sendToURL(
 		self.name, self.connector.GetCurrentUrl(self.handler),
 		utils.GetTime().Now().Sub(now))
 
+	now = utils.GetTime().Now()
 	encrypted, err := self.connector.Post(ctx, self.name,
 		self.handler, cipher_text, urgent)
+	if err == nil && self.batcher != nil {
+		self.batcher.Record(len(cipher_text), utils.GetTime().Now().Sub(now))
+	}
 
 	// Enrollment is pretty quick so we need to retry sooner -
 	// return no error so the next poll happens in minPoll.
@@ -775,6 +891,36 @@ func (self *NotificationReader) sendToURL(
 		})
 }
 
+func (self *NotificationReader) maybeRecompress(
+	ctx context.Context, message_list [][]byte,
+	compression crypto_proto.PackedMessageList_CompressionType) (
+	[][]byte, crypto_proto.PackedMessageList_CompressionType, error) {
+
+	// All servers support deflate.
+	current := self.connector.Compression()
+	if compression == current ||
+		compression == crypto_proto.PackedMessageList_UNCOMPRESSED ||
+		compression == crypto.DEFAULT_COMPRESSION {
+		return message_list, compression, nil
+	}
+
+	result := make([][]byte, 0, len(message_list))
+	for _, item := range message_list {
+		plain_text, err := crypto.Uncompress(ctx, compression, item)
+		if err != nil {
+			return nil, compression, err
+		}
+
+		compressed, err := crypto.Compress(current, plain_text)
+		if err != nil {
+			return nil, compression, err
+		}
+		result = append(result, compressed)
+	}
+
+	return result, current, nil
+}
+
 func (self *NotificationReader) maybeCallOnExit() {
 	if self.on_exit != nil {
 		self.on_exit()
@@ -793,11 +939,6 @@ func (self *NotificationReader) maybeCallOnExit() {
 func (self *NotificationReader) Start(
 	ctx context.Context, wg *sync.WaitGroup) {
 
-	compression := crypto_proto.PackedMessageList_ZCOMPRESSION
-	if self.config_obj.Client.DisableCompression {
-		compression = crypto_proto.PackedMessageList_UNCOMPRESSED
-	}
-
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -814,16 +955,12 @@ func (self *NotificationReader) Start(
 			message_list := self.GetMessageList()
 			serialized_message_list, err := proto.Marshal(message_list)
 			if err == nil {
-				if compression == crypto_proto.PackedMessageList_ZCOMPRESSION {
-					compressed, err := utils.Compress(serialized_message_list)
-					if err == nil {
-						self.sendMessageList(
-							ctx, [][]byte{compressed}, !URGENT, compression)
-					}
-
-				} else {
+				compression := self.connector.Compression()
+				compressed, err := crypto.Compress(
+					compression, serialized_message_list)
+				if err == nil {
 					self.sendMessageList(
-						ctx, [][]byte{serialized_message_list}, !URGENT, compression)
+						ctx, [][]byte{compressed}, !URGENT, compression)
 				}
 			}
 
@@ -853,6 +990,7 @@ func (self *NotificationReader) GetMessageList() *crypto_proto.MessageList {
 			SessionId: constants.FOREMAN_WELL_KNOWN_FLOW,
 			ForemanCheckin: &actions_proto.ForemanCheckin{
 				LastEventTableVersion: self.executor.EventManager().Version(),
+				EventTableDelta:       true,
 			},
 		}}}
 
@@ -860,31 +998,62 @@ func (self *NotificationReader) GetMessageList() *crypto_proto.MessageList {
 	// infrequently.
 	now := utils.GetTime().Now()
 	if now.Add(-self.last_update_period).After(self.last_update_time) {
+		first_update := self.last_update_time.IsZero()
 		self.last_update_time = now
 
 		client_info := self.executor.GetClientInfo()
-		client_info_data, err := json.Marshal(client_info)
+		if !self.config_obj.Client.DisableClientInfoDelta {
+			client_info = self.clientInfoDelta(client_info, first_update)
+		}
+
+		message, err := actions.ClientInfoMessage(client_info)
 		if err == nil {
 			logger := logging.GetLogger(self.config_obj, &logging.ClientComponent)
 			logger.Debug("Sending client info update %v", client_info)
 
-			client_info_data = append(client_info_data, '\n')
-			result.Job = append(result.Job, &crypto_proto.VeloMessage{
-				SessionId: "F.Monitoring",
-				VQLResponse: &actions_proto.VQLResponse{
-					JSONLResponse: string(client_info_data),
-					Query: &actions_proto.VQLRequest{
-						Name: "Server.Internal.ClientInfo",
-					},
-					TotalRows: 1,
-				},
-			})
+			result.Job = append(result.Job, message)
 		}
 	}
 
 	return result
 }
 
+// The client info is sent every time the client starts but rarely
+// changes so the first update only carries the sections which
+// changed since the last update before the restart. Later periodic
+// updates carry all the sections in case the server missed an
+// earlier update.
+func (self *NotificationReader) clientInfoDelta(
+	client_info *actions_proto.ClientInfo,
+	first_update bool) *actions_proto.ClientInfo {
+
+	writeback_service := writeback.GetWritebackService()
+
+	var previous map[string]string
+	if first_update {
+		wb, err := writeback_service.GetWriteback(self.config_obj)
+		if err == nil {
+			previous = wb.ClientInfoHashes
+		}
+	}
+
+	result := actions.ClientInfoDelta(client_info, previous)
+	err := writeback_service.MutateWriteback(self.config_obj,
+		func(wb *config_proto.Writeback) error {
+			if utils.StringMapEq(wb.ClientInfoHashes, result.SectionHashes) {
+				return writeback.WritebackNoUpdate
+			}
+			wb.ClientInfoHashes = result.SectionHashes
+			return writeback.WritebackUpdateLevel2
+		})
+	if err != nil {
+		logger := logging.GetLogger(self.config_obj, &logging.ClientComponent)
+		logger.Debug("Unable to store client info hashes: %v", err)
+	}
+
+	return result
+}
+
 type HTTPCommunicator struct {
 	config_obj *config_proto.Config
 
@@ -14,11 +14,11 @@ import (
 	"google.golang.org/protobuf/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/crypto"
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
 	"www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/responder"
-	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 const (
@@ -192,8 +192,9 @@ func LeaseAndCompress(self IRingBuffer, size uint64,
 			break
 		}
 
-		if compression == crypto_proto.PackedMessageList_ZCOMPRESSION {
-			compressed_message_list, err := utils.Compress(next_message_list)
+		if compression != crypto_proto.PackedMessageList_UNCOMPRESSED {
+			compressed_message_list, err := crypto.Compress(
+				compression, next_message_list)
 			if err != nil || len(compressed_message_list) == 0 {
 				// Something terrible happened! The file is
 				// corrupted and it is better to start again.
@@ -120,6 +120,10 @@ func (self *Sender) PumpExecutorToRingBuffer(ctx context.Context) {
 				}
 				self.urgent_buffer.Enqueue(serialized_msg)
 
+				// Urgent messages should not wait for the
+				// batch to fill.
+				self.releaseBatch()
+
 			} else {
 				// NOTE: This is kind of a hack. We hold in
 				// memory a bunch of VeloMessage proto objects
@@ -151,22 +155,23 @@ func (self *Sender) PumpExecutorToRingBuffer(ctx context.Context) {
 			// We have just filled the message queue with
 			// enough data, trigger the sender to send
 			// this data out immediately.
-			if self.ring_buffer.AvailableBytes() > self.
-				config_obj.Client.MaxUploadSize {
-
-				// Signal to
-				// PumpRingBufferToSendMessage() that
-				// it should not wait before sending
-				// the next packet.
-				self.mu.Lock()
-				close(self.release)
-				self.release = make(chan bool)
-				self.mu.Unlock()
+			if self.ring_buffer.AvailableBytes() > self.batcher.BatchSize(
+				self.config_obj.Client.MaxUploadSize) {
+				self.releaseBatch()
 			}
 		}
 	}
 }
 
+// Signal to PumpRingBufferToSendMessage() that it should not wait
+// before sending the next packet.
+func (self *Sender) releaseBatch() {
+	self.mu.Lock()
+	close(self.release)
+	self.release = make(chan bool)
+	self.mu.Unlock()
+}
+
 // Manages the sending of messages to the server. Reads messages from
 // the ring buffer if there are any to send and compose a Message List
 // to send. This also manages timing and retransmissions - blocks if
@@ -177,15 +182,14 @@ func (self *Sender) PumpRingBufferToSendMessage(ctx context.Context) {
 	// We should never exit from this.
 	defer self.maybeCallOnExit()
 
-	compression := crypto_proto.PackedMessageList_ZCOMPRESSION
-	if self.config_obj.Client.DisableCompression {
-		compression = crypto_proto.PackedMessageList_UNCOMPRESSED
-	}
-
 	for {
 		executor.Nanny.UpdatePumpRbToServer()
 
 		if atomic.LoadInt32(&self.IsPaused) == 0 {
+			// The compression may change as we learn what the
+			// server supports.
+			compression := self.connector.Compression()
+
 			// Grab some messages from the urgent ring buffer.
 			compressed_messages := LeaseAndCompress(self.urgent_buffer,
 				self.config_obj.Client.MaxUploadSize, compression)
@@ -223,8 +227,9 @@ func (self *Sender) PumpRingBufferToSendMessage(ctx context.Context) {
 			continue
 
 			// Wait a minimum amount of time to allow for
-			// responses to be queued in the same POST.
-		case <-self.clock.After(self.minPoll):
+			// responses to be queued in the same POST. On slow
+			// links we wait longer to send fewer, larger POSTs.
+		case <-self.clock.After(self.batcher.Wait()):
 			continue
 		}
 	}
@@ -289,5 +294,11 @@ func NewSender(
 		clock:   clock,
 	}
 
+	max_batch_wait := time.Duration(config_obj.Client.MaxBatchWait) * time.Second
+	if max_batch_wait == 0 {
+		max_batch_wait = 10 * time.Second
+	}
+	result.batcher = NewAdaptiveBatcher(result.minPoll, max_batch_wait)
+
 	return result, nil
 }
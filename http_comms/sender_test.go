@@ -55,6 +55,10 @@ func (self *MockHTTPConnector) GetCurrentUrl(handler string) string {
 	return "http://URL/" + handler
 }
 
+func (self *MockHTTPConnector) Compression() crypto_proto.PackedMessageList_CompressionType {
+	return crypto_proto.PackedMessageList_ZCOMPRESSION
+}
+
 func (self *MockHTTPConnector) SetConnected(c bool) {
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	rotatelogs "github.com/Velocidex/file-rotatelogs"
+)
+
+// Compress log files once they are rotated. The compressed files
+// still match the rotator's pattern so they expire with max_age.
+func compressRotatedFile(e rotatelogs.Event) {
+	rotated, ok := e.(*rotatelogs.FileRotatedEvent)
+	if !ok || rotated.PreviousFile() == "" {
+		return
+	}
+
+	err := compressFile(rotated.PreviousFile())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to compress log file %v: %v\n",
+			rotated.PreviousFile(), err)
+	}
+}
+
+func compressFile(filename string) error {
+	in, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	// The rotator may reuse the file name after it was compressed so
+	// do not overwrite an older compressed file.
+	target := filename + ".gz"
+	for i := 1; ; i++ {
+		_, err := os.Stat(target)
+		if err != nil {
+			break
+		}
+		target = fmt.Sprintf("%s.%d.gz", filename, i)
+	}
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		out.Close()
+		os.Remove(target)
+		return err
+	}
+
+	err = out.Close()
+	if err != nil {
+		return err
+	}
+
+	in.Close()
+	return os.Remove(filename)
+}
@@ -5,6 +5,12 @@ import (
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
+const (
+	// Structured logs record the component which logged the
+	// message in this field.
+	COMPONENT_FIELD = "component"
+)
+
 type JSONFormatter struct {
 	*logrus.JSONFormatter
 }
@@ -15,3 +21,19 @@ func (self *JSONFormatter) Format(e *logrus.Entry) ([]byte, error) {
 	result, err := self.JSONFormatter.Format(e)
 	return result, err
 }
+
+// The component is not shown in the console text format.
+func textFields(entry *logrus.Entry) logrus.Fields {
+	_, pres := entry.Data[COMPONENT_FIELD]
+	if !pres {
+		return entry.Data
+	}
+
+	result := make(logrus.Fields)
+	for k, v := range entry.Data {
+		if k != COMPONENT_FIELD {
+			result[k] = v
+		}
+	}
+	return result
+}
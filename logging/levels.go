@@ -1,5 +1,14 @@
 package logging
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+)
+
 // Logging levels for responder.Log()
 const (
 	DEFAULT = "DEFAULT"
@@ -12,3 +21,111 @@ const (
 	// the server into the alert queue.
 	ALERT = "ALERT"
 )
+
+// Components may be referred to by their short names.
+var component_names = map[string]*string{
+	"generic":  &GenericComponent,
+	"frontend": &FrontendComponent,
+	"client":   &ClientComponent,
+	"gui":      &GUIComponent,
+	"tool":     &ToolComponent,
+	"api":      &APICmponent,
+	"audit":    &Audit,
+}
+
+// Find the component by its short or full name. Returns nil if the
+// component is not known.
+func GetComponent(name string) *string {
+	component, pres := component_names[strings.ToLower(name)]
+	if pres {
+		return component
+	}
+
+	for _, component := range component_names {
+		if strings.EqualFold(*component, name) {
+			return component
+		}
+	}
+	return nil
+}
+
+func parseLevel(level string) (logrus.Level, error) {
+	switch strings.ToLower(level) {
+	case "", "debug":
+		return logrus.DebugLevel, nil
+	case "info":
+		return logrus.InfoLevel, nil
+	case "warn", "warning":
+		return logrus.WarnLevel, nil
+	case "error":
+		return logrus.ErrorLevel, nil
+	}
+	return logrus.DebugLevel, fmt.Errorf("Invalid log level %v", level)
+}
+
+// The initial level of the component from the config.
+func getConfigLevel(
+	config_obj *config_proto.Config, component *string) (logrus.Level, error) {
+	for _, item := range config_obj.GetLogging().GetComponentLevels() {
+		if GetComponent(item.Component) == nil {
+			return logrus.DebugLevel, fmt.Errorf(
+				"Unknown log component %v", item.Component)
+		}
+
+		if *GetComponent(item.Component) == *component {
+			return parseLevel(item.Level)
+		}
+	}
+	return parseLevel(config_obj.GetLogging().GetLevel())
+}
+
+// Change the level of a component at runtime. The change lasts
+// until the logging config is reloaded.
+func SetLevel(name, level string) error {
+	component := GetComponent(name)
+	if component == nil {
+		return fmt.Errorf("Unknown log component %v", name)
+	}
+
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if Manager == nil {
+		return errors.New("Logging is not initialized")
+	}
+
+	Manager.mu.Lock()
+	defer Manager.mu.Unlock()
+
+	for c, ctx := range Manager.contexts {
+		if *c == *component {
+			ctx.SetLevel(lvl)
+		}
+	}
+	return nil
+}
+
+// The current level of each component.
+func GetLevels() map[string]string {
+	result := make(map[string]string)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if Manager == nil {
+		return result
+	}
+
+	Manager.mu.Lock()
+	defer Manager.mu.Unlock()
+
+	for c, ctx := range Manager.contexts {
+		result[*c] = ctx.GetLevel().String()
+	}
+	return result
+}
@@ -63,7 +63,7 @@ var (
 	// Lock for memory logs and prelogs.
 	memory_log_mu sync.Mutex
 	prelogs       []string
-	memory_logs   []string
+	memory_logs   = &memoryLogs{}
 
 	tag_regex         = regexp.MustCompile("<([^>/0]+)>")
 	closing_tag_regex = regexp.MustCompile("</>")
@@ -91,6 +91,11 @@ func InitLogging(config_obj *config_proto.Config) error {
 		contexts: make(map[*string]*LogContext),
 	}
 
+	setMemoryLogSize(int(config_obj.GetLogging().GetMemoryLogSize()))
+
+	// The generic component must be first because the other
+	// components share its log files unless
+	// separate_logs_per_component is set.
 	for _, component := range []*string{
 		&GenericComponent, &FrontendComponent, &ClientComponent,
 		&GUIComponent, &ToolComponent, &APICmponent, &Audit} {
@@ -115,19 +120,6 @@ func InitLogging(config_obj *config_proto.Config) error {
 	return nil
 }
 
-func ClearMemoryLogs() {
-	memory_log_mu.Lock()
-	memory_logs = nil
-	memory_log_mu.Unlock()
-}
-
-func GetMemoryLogs() []string {
-	memory_log_mu.Lock()
-	defer memory_log_mu.Unlock()
-
-	return append([]string{}, memory_logs...)
-}
-
 // Early in the startup process, we find that we need to log sometimes
 // but we have no idea where to send the logs and what components to
 // load (because the config is not fully loaded yet). We therefore
@@ -157,39 +149,64 @@ func FlushPrelogs(config_obj *config_proto.Config) {
 	prelogs = make([]string, 0)
 }
 
+// Listeners receive all messages logged to the component's files.
+type logListeners struct {
+	mu        sync.Mutex
+	listeners map[uint64]chan string
+}
+
 type LogContext struct {
 	*logrus.Logger
 
+	// The name of the component added to structured logs.
+	component string
+
 	mu      sync.Mutex
 	enabled map[string]bool
 
-	listeners map[uint64]chan string
+	// Shared by all components when they log to the same files.
+	sink *logListeners
 }
 
 func (self *LogContext) AddListener(c chan string) func() {
 	self.mu.Lock()
-	defer self.mu.Unlock()
+	if self.sink == nil {
+		self.sink = &logListeners{}
+	}
+	sink := self.sink
+	self.mu.Unlock()
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
 
-	if self.listeners == nil {
-		self.listeners = make(map[uint64]chan string)
+	if sink.listeners == nil {
+		sink.listeners = make(map[uint64]chan string)
 	}
 
 	id := utils.GetId()
-	self.listeners[id] = c
+	sink.listeners[id] = c
 
 	return func() {
-		self.mu.Lock()
-		defer self.mu.Unlock()
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
 
-		delete(self.listeners, id)
+		delete(sink.listeners, id)
 	}
 }
 
 func (self *LogContext) forwardMessage(msg string) {
 	self.mu.Lock()
-	defer self.mu.Unlock()
+	sink := self.sink
+	self.mu.Unlock()
+
+	if sink == nil {
+		return
+	}
 
-	for _, c := range self.listeners {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	for _, c := range sink.listeners {
 		select {
 		case c <- msg:
 		default:
@@ -197,36 +214,37 @@ func (self *LogContext) forwardMessage(msg string) {
 	}
 }
 
-func (self *LogContext) Debug(format string, v ...interface{}) {
-	msg := fmt.Sprintf(format, v...)
-	if self.Logger != nil {
-		self.Logger.Debug(msg)
+// Messages below the component's level are dropped.
+func (self *LogContext) log(level logrus.Level, msg string) {
+	if self.Logger == nil {
+		self.forwardMessage(msg)
+		return
+	}
+
+	if !self.Logger.IsLevelEnabled(level) {
+		return
 	}
+
+	self.Logger.WithField(COMPONENT_FIELD, self.component).Log(level, msg)
 	self.forwardMessage(msg)
 }
 
+func (self *LogContext) Debug(format string, v ...interface{}) {
+	self.log(logrus.DebugLevel, fmt.Sprintf(format, v...))
+}
+
 func (self *LogContext) Info(format string, v ...interface{}) {
-	msg := fmt.Sprintf(format, v...)
-	if self.Logger != nil {
-		self.Logger.Info(msg)
-	}
-	self.forwardMessage(msg)
+	self.log(logrus.InfoLevel, fmt.Sprintf(format, v...))
 }
 
 func (self *LogContext) Warn(format string, v ...interface{}) {
-	msg := fmt.Sprintf(format, v...)
-	if self.Logger != nil {
-		self.Logger.Warn(msg)
-	}
-	self.forwardMessage(msg)
+	self.log(logrus.WarnLevel, fmt.Sprintf(format, v...))
 }
 
 func (self *LogContext) Error(format string, v ...interface{}) {
 	msg := fmt.Sprintf(format, v...)
-	if self.Logger != nil {
-		self.Logger.Error(msg)
-	}
-	self.forwardMessage(msg)
+	countServiceError(msg)
+	self.log(logrus.ErrorLevel, msg)
 }
 
 func (self *LogContext) IsEnabled(level string) bool {
@@ -267,11 +285,6 @@ func (self *LogManager) GetLogger(
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
-	if config_obj.Logging != nil &&
-		!config_obj.Logging.SeparateLogsPerComponent {
-		component = &GenericComponent
-	}
-
 	ctx, pres := self.contexts[component]
 	if !pres {
 		return &LogContext{
@@ -281,7 +294,9 @@ func (self *LogManager) GetLogger(
 				Hooks:     make(logrus.LevelHooks),
 				Level:     logrus.DebugLevel,
 			},
-			enabled: make(map[string]bool),
+			component: *component,
+			enabled:   make(map[string]bool),
+			sink:      &logListeners{},
 		}
 	}
 	return ctx
@@ -326,14 +341,26 @@ func getRotator(
 		rotation = 604800 // 7 days
 	}
 
-	result, err := rotatelogs.New(
-		base_path+".%Y%m%d%H%M",
+	max_size := rotator_config.MaxSize
+	if max_size == 0 {
+		max_size = config_obj.Logging.MaxSize
+	}
+
+	options := []rotatelogs.Option{
 		rotatelogs.WithLinkName(base_path),
 		// 365 days.
-		rotatelogs.WithMaxAge(time.Duration(max_age)*time.Second),
+		rotatelogs.WithMaxAge(time.Duration(max_age) * time.Second),
 		// 7 days.
-		rotatelogs.WithRotationTime(time.Duration(rotation)*time.Second),
-	)
+		rotatelogs.WithRotationTime(time.Duration(rotation) * time.Second),
+		rotatelogs.WithRotationSize(int64(max_size)),
+	}
+
+	if rotator_config.Compress || config_obj.Logging.Compress {
+		options = append(options, rotatelogs.WithHandler(
+			rotatelogs.HandlerFunc(compressRotatedFile)))
+	}
+
+	result, err := rotatelogs.New(base_path+".%Y%m%d%H%M", options...)
 	if err != nil {
 		return nil, err, false
 	}
@@ -350,11 +377,33 @@ func (self *LogManager) makeNewComponent(
 	config_obj *config_proto.Config,
 	component *string) (*LogContext, error) {
 
-	enabled := make(map[string]bool)
+	level, err := getConfigLevel(config_obj, component)
+	if err != nil {
+		return nil, err
+	}
 
 	Log := logrus.New()
-	Log.Out = newInMemoryLogWriter()
-	Log.Level = logrus.DebugLevel
+	Log.Out = ioutil.Discard
+	Log.Level = level
+
+	// Unless separate_logs_per_component is set all components write
+	// to the generic component's files.
+	generic, pres := self.contexts[&GenericComponent]
+	if pres && !config_obj.GetLogging().GetSeparateLogsPerComponent() {
+		for level, hooks := range generic.Hooks {
+			Log.Hooks[level] = append([]logrus.Hook{}, hooks...)
+		}
+
+		return &LogContext{
+			Logger:    Log,
+			component: *component,
+			enabled:   generic.enabled,
+			sink:      generic.sink,
+		}, nil
+	}
+
+	enabled := make(map[string]bool)
+	Log.Hooks.Add(memoryHook{})
 
 	if !disable_log_to_files &&
 		config_obj != nil &&
@@ -421,14 +470,24 @@ func (self *LogManager) makeNewComponent(
 		stderr_map[logrus.ErrorLevel] = os.Stderr
 	}
 
-	Log.Hooks.Add(lfshook.NewHook(stderr_map, &Formatter{stderr_map}))
+	if strings.EqualFold(config_obj.GetLogging().GetFormat(), "json") {
+		Log.Hooks.Add(lfshook.NewHook(stderr_map,
+			&JSONFormatter{&logrus.JSONFormatter{
+				DisableHTMLEscape: true,
+			}}))
+	} else {
+		Log.Hooks.Add(lfshook.NewHook(stderr_map, &Formatter{stderr_map}))
+	}
+
 	if !NoColor && !isatty.IsTerminal(os.Stdout.Fd()) {
 		NoColor = true
 	}
 
 	return &LogContext{
-		Logger:  Log,
-		enabled: enabled,
+		Logger:    Log,
+		component: *component,
+		enabled:   enabled,
+		sink:      &logListeners{},
 	}, nil
 }
 
@@ -522,22 +581,3 @@ func clearTag(message string) string {
 	return closing_tag_regex.ReplaceAllString(message, "")
 }
 
-type inMemoryLogWriter struct{}
-
-func (self inMemoryLogWriter) Write(p []byte) (n int, err error) {
-	memory_log_mu.Lock()
-	defer memory_log_mu.Unlock()
-
-	// Truncate too many logs
-	if len(memory_logs) > 1000 {
-		memory_logs = nil
-	}
-
-	memory_logs = append(memory_logs, string(p))
-
-	return len(p), nil
-}
-
-func newInMemoryLogWriter() *inMemoryLogWriter {
-	return &inMemoryLogWriter{}
-}
@@ -13,10 +13,12 @@ import (
 	"github.com/sebdah/goldie"
 	"github.com/stretchr/testify/suite"
 	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
 	"www.velocidex.com/golang/velociraptor/vtesting/assert"
 )
 
@@ -76,6 +78,91 @@ func (self *LoggingTestSuite) TestAuditLog() {
 	goldie.Assert(t, "TestAuditLog", data)
 }
 
+func (self *LoggingTestSuite) TestLevelsAndMemoryLogs() {
+	t := self.T()
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Logging.Level = "info"
+	config_obj.Logging.MemoryLogSize = 5
+	config_obj.Logging.ComponentLevels = []*config_proto.LoggingComponentLevel{{
+		Component: "gui", Level: "debug",
+	}}
+	defer logging.InitLogging(config.GetDefaultConfig())
+
+	err := logging.InitLogging(config_obj)
+	assert.NoError(t, err)
+
+	frontend := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	gui := logging.GetLogger(config_obj, &logging.GUIComponent)
+
+	frontend.Debug("Frontend debug")
+	gui.Debug("GUI debug")
+	frontend.Info("Frontend <green>info</>")
+
+	// Change the level at runtime.
+	assert.NoError(t, logging.SetLevel("VelociraptorFrontend", "error"))
+	frontend.Info("Frontend dropped")
+	frontend.Error("Frontend error")
+
+	messages := []string{}
+	for _, entry := range logging.GetRecentLogs() {
+		messages = append(messages, entry.Component+" "+entry.Level+" "+entry.Message)
+	}
+	assert.Equal(t, []string{
+		"VelociraptorGUI DEBUG GUI debug",
+		"VelociraptorFrontend INFO Frontend info",
+		"VelociraptorFrontend ERROR Frontend error",
+	}, messages[len(messages)-3:])
+
+	assert.Equal(t, "error", logging.GetLevels()["VelociraptorFrontend"])
+	assert.Error(t, logging.SetLevel("frontend", "verbose"))
+	assert.Error(t, logging.SetLevel("nosuchcomponent", "info"))
+
+	// Only the most recent messages are kept.
+	for i := 0; i < 10; i++ {
+		gui.Info("Message %v", i)
+	}
+	recent := logging.GetRecentLogs()
+	assert.Equal(t, 5, len(recent))
+	assert.Equal(t, "Message 9", recent[4].Message)
+
+	filter, err := logging.NewLogFilter("frontend", "warn")
+	assert.NoError(t, err)
+	assert.True(t, !filter.Match(recent[4]))
+
+	// Invalid levels are rejected.
+	config_obj.Logging.Level = "verbose"
+	assert.Error(t, logging.InitLogging(config_obj))
+}
+
+func (self *LoggingTestSuite) TestRotationAndCompression() {
+	t := self.T()
+
+	dir, err := ioutil.TempDir("", "file_store_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Logging.OutputDirectory = dir
+	config_obj.Logging.MaxSize = 100
+	config_obj.Logging.Compress = true
+	defer logging.InitLogging(config.GetDefaultConfig())
+
+	err = logging.InitLogging(config_obj)
+	assert.NoError(t, err)
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	for i := 0; i < 5; i++ {
+		logger.Info("Message %v with some padding to fill the file", i)
+	}
+
+	// Rotated files are compressed in the background.
+	vtesting.WaitUntil(5*time.Second, t, func() bool {
+		matches, _ := filepath.Glob(filepath.Join(dir, "Velociraptor_info.log.*.gz"))
+		return len(matches) > 0
+	})
+}
+
 func TestLogging(t *testing.T) {
 	suite.Run(t, &LoggingTestSuite{})
 }
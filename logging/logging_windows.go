@@ -67,8 +67,9 @@ func (self *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
 	fmt.Fprintf(b, "[%s] %v %s ", levelText, entry.Time.Format(time.RFC3339),
 		strings.TrimRight(entry.Message, "\r\n"))
 
-	if len(entry.Data) > 0 {
-		serialized, _ := json.Marshal(entry.Data)
+	data := textFields(entry)
+	if len(data) > 0 {
+		serialized, _ := json.Marshal(data)
 		fmt.Fprintf(b, "%s", serialized)
 	}
 
@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	DEFAULT_MEMORY_LOG_SIZE = 1000
+)
+
+// A recent log message kept in memory.
+type LogEntry struct {
+	Time      time.Time
+	Level     string
+	Component string
+	Message   string
+}
+
+// A ring buffer of the most recent log messages so they can be
+// inspected without access to the log files.
+type memoryLogs struct {
+	entries []LogEntry
+
+	// The index of the oldest entry once the buffer is full.
+	next int
+	size int
+}
+
+func (self *memoryLogs) add(entry LogEntry) {
+	if self.size <= 0 {
+		self.size = DEFAULT_MEMORY_LOG_SIZE
+	}
+
+	if len(self.entries) < self.size {
+		self.entries = append(self.entries, entry)
+		return
+	}
+
+	self.entries[self.next] = entry
+	self.next = (self.next + 1) % len(self.entries)
+}
+
+// Entries from oldest to newest.
+func (self *memoryLogs) get() []LogEntry {
+	result := make([]LogEntry, 0, len(self.entries))
+	result = append(result, self.entries[self.next:]...)
+	return append(result, self.entries[:self.next]...)
+}
+
+func (self *memoryLogs) resize(size int) {
+	if size <= 0 {
+		size = DEFAULT_MEMORY_LOG_SIZE
+	}
+
+	entries := self.get()
+	if len(entries) > size {
+		entries = entries[len(entries)-size:]
+	}
+
+	self.entries = entries
+	self.next = 0
+	self.size = size
+}
+
+func ClearMemoryLogs() {
+	memory_log_mu.Lock()
+	memory_logs.entries = nil
+	memory_logs.next = 0
+	memory_log_mu.Unlock()
+}
+
+func setMemoryLogSize(size int) {
+	memory_log_mu.Lock()
+	memory_logs.resize(size)
+	memory_log_mu.Unlock()
+}
+
+// Recent log messages from oldest to newest.
+func GetRecentLogs() []LogEntry {
+	memory_log_mu.Lock()
+	defer memory_log_mu.Unlock()
+
+	return memory_logs.get()
+}
+
+func GetMemoryLogs() []string {
+	result := []string{}
+	for _, entry := range GetRecentLogs() {
+		result = append(result, fmt.Sprintf("time=%q level=%v component=%v msg=%q\n",
+			entry.Time.Format(time.RFC3339), strings.ToLower(entry.Level),
+			entry.Component, entry.Message))
+	}
+	return result
+}
+
+// Record all messages into the ring buffer.
+type memoryHook struct{}
+
+func (self memoryHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (self memoryHook) Fire(entry *logrus.Entry) error {
+	component, _ := entry.Data[COMPONENT_FIELD].(string)
+
+	memory_log_mu.Lock()
+	defer memory_log_mu.Unlock()
+
+	memory_logs.add(LogEntry{
+		Time:      utils.GetTime().Now().UTC(),
+		Level:     strings.ToUpper(entry.Level.String()),
+		Component: component,
+		Message:   clearTag(strings.TrimRight(entry.Message, "\r\n")),
+	})
+	return nil
+}
+
+// Select recent log messages by component and minimum level.
+type LogFilter struct {
+	component string
+	level     logrus.Level
+}
+
+func NewLogFilter(component, level string) (*LogFilter, error) {
+	result := &LogFilter{}
+	if component != "" {
+		c := GetComponent(component)
+		if c == nil {
+			return nil, fmt.Errorf("Unknown log component %v", component)
+		}
+		result.component = *c
+	}
+
+	var err error
+	result.level, err = parseLevel(level)
+	return result, err
+}
+
+func (self *LogFilter) Match(entry LogEntry) bool {
+	if self.component != "" && entry.Component != self.component {
+		return false
+	}
+
+	level, err := logrus.ParseLevel(entry.Level)
+	if err != nil {
+		return true
+	}
+
+	// More severe levels are smaller.
+	return level <= self.level
+}
@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"regexp"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	serviceErrorCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "service_errors",
+			Help: "Count of errors logged by each service.",
+		},
+		[]string{"service"},
+	)
+
+	// By convention error messages are prefixed by the name of the
+	// service or function that raised them (e.g. "HuntDispatcher: ...")
+	service_prefix_regex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_.]{0,63}):`)
+)
+
+// Attribute the error message to a service so a rise in errors from
+// one service can be detected.
+func countServiceError(msg string) {
+	service := "Other"
+	match := service_prefix_regex.FindStringSubmatch(clearTag(msg))
+	if len(match) > 1 {
+		service = match[1]
+	}
+	serviceErrorCounter.WithLabelValues(service).Inc()
+}
@@ -0,0 +1,10 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// A request for approval of a collection or hunt.
+func ApprovalPath(id string) api.DSPathSpec {
+	return APPROVALS_ROOT.AddChild(id).SetTag("Approval")
+}
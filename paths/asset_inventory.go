@@ -0,0 +1,20 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+// The client's inventory record listing the available versions.
+func AssetInventoryPath(client_id string) api.DSPathSpec {
+	return ASSET_INVENTORY_ROOT.AddChild(client_id).SetTag("AssetInventory")
+}
+
+// The rows of one category (e.g. Software) in a version of the
+// client's inventory.
+func AssetInventoryResultsPath(
+	client_id, version, category string) api.FSPathSpec {
+	return path_specs.NewUnsafeFilestorePath(
+		"asset_inventory", client_id, version, category).
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+}
@@ -0,0 +1,17 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+// The canary record.
+func CanaryPath(id string) api.DSPathSpec {
+	return CANARIES_ROOT.AddChild(id).SetTag("Canary")
+}
+
+// All accesses to the canary are recorded in this result set.
+func CanaryHitsPath(id string) api.FSPathSpec {
+	return path_specs.NewUnsafeFilestorePath("canaries", id, "hits").
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+}
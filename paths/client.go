@@ -137,3 +137,31 @@ func (self ClientPathManager) GetUploadsFileFromVFSPath(vfs_path string) (
 	return path_specs.NewUnsafeFilestorePath(components...).
 		SetType(api.PATH_TYPE_FILESTORE_ANY), nil
 }
+
+// Baseline snapshots of the client's state, one per artifact.
+func (self ClientPathManager) BaselinesDirectory() api.DSPathSpec {
+	return CLIENTS_ROOT.AddUnsafeChild(self.client_id, "baselines").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+}
+
+func (self ClientPathManager) Baseline(artifact string) api.DSPathSpec {
+	return CLIENTS_ROOT.AddUnsafeChild(self.client_id, "baselines", artifact).
+		SetType(api.PATH_TYPE_DATASTORE_JSON).
+		SetTag("Baseline")
+}
+
+// The rows of the baseline are stored in the filestore.
+func (self ClientPathManager) BaselineRows(artifact string) api.FSPathSpec {
+	return CLIENTS_ROOT.AddUnsafeChild(self.client_id, "baseline_rows", artifact).
+		AsFilestorePath().
+		SetType(api.PATH_TYPE_FILESTORE_JSON).
+		SetTag("BaselineRows")
+}
+
+// The client's time zone and clock skew as measured during
+// interrogation.
+func (self ClientPathManager) Clock() api.DSPathSpec {
+	return CLIENTS_ROOT.AddUnsafeChild(self.client_id, "clock").
+		SetType(api.PATH_TYPE_DATASTORE_JSON).
+		SetTag("ClientClock")
+}
@@ -38,6 +38,11 @@ var (
 		"client_info", "snapshot").
 		SetType(api.PATH_TYPE_FILESTORE_JSON)
 
+	// Stores a snapshot of client check in history
+	CLIENTS_AVAILABILITY_SNAPSHOT = path_specs.NewUnsafeFilestorePath(
+		"client_info", "availability").
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+
 	CONFIG_ROOT = path_specs.NewSafeDatastorePath("config").
 			SetType(api.PATH_TYPE_DATASTORE_JSON)
 
@@ -61,6 +66,81 @@ var (
 	TEMP_ROOT = path_specs.NewUnsafeFilestorePath("temp").
 			SetType(api.PATH_TYPE_FILESTORE_ANY)
 
+	// Managed GeoIP databases kept up to date by the GeoIP service.
+	GEOIP_ROOT = path_specs.NewUnsafeFilestorePath("geoip").
+			SetType(api.PATH_TYPE_FILESTORE_ANY)
+
+	// Cached lookups against external enrichment services.
+	ENRICHMENT_ROOT = path_specs.NewUnsafeDatastorePath("enrichment").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Indicators ingested from threat intel feeds (e.g. TAXII).
+	IOC_ROOT = path_specs.NewUnsafeDatastorePath("ioc").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Polling state of the EDR alert connectors.
+	EDR_ROOT = path_specs.NewUnsafeDatastorePath("edr").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Outbound webhook delivery queues.
+	WEBHOOK_ROOT = path_specs.NewUnsafeDatastorePath("webhooks").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// User defined dashboards shared with the org. Private
+	// dashboards are stored with the user.
+	DASHBOARDS_ROOT = path_specs.NewSafeDatastorePath("dashboards").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Canary tokens deployed to clients.
+	CANARIES_ROOT = path_specs.NewSafeDatastorePath("canaries").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Per client asset inventory records.
+	ASSET_INVENTORY_ROOT = path_specs.NewSafeDatastorePath("asset_inventory").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// The sync state of the vulnerability databases.
+	VULNERABILITY_STATUS = path_specs.NewSafeDatastorePath(
+		"vulnerabilities", "status").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Responses started by the playbook engine.
+	PLAYBOOK_RUNS_ROOT = path_specs.NewSafeDatastorePath("playbooks", "runs").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Collections and hunts awaiting approval by a second user.
+	APPROVALS_ROOT = path_specs.NewSafeDatastorePath("approvals").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Flows, hunts and clients exempt from deletion.
+	LEGAL_HOLDS_ROOT = path_specs.NewSafeDatastorePath("legal_holds").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Export jobs creating download containers.
+	EXPORT_JOBS_ROOT = path_specs.NewSafeDatastorePath("export_jobs").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Shared VQL libraries imported by artifacts.
+	VQL_LIBRARIES_ROOT = path_specs.NewSafeDatastorePath("vql_libraries").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Encrypted credentials used by server artifacts.
+	SECRETS_ROOT = path_specs.NewSafeDatastorePath("secrets").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Policies masking sensitive data in results.
+	MASKING_POLICIES_ROOT = path_specs.NewSafeDatastorePath("masking_policies").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Favorite collections shared with the org. Private favorites
+	// are stored with the user.
+	FAVORITES_ROOT = path_specs.NewUnsafeDatastorePath("favorites").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Markers written by the frontend readiness checks.
+	HEALTH_ROOT = path_specs.NewUnsafeDatastorePath("health").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
 	// Timelines
 	TIMELINE_URN = path_specs.NewSafeDatastorePath("timelines").
 			SetType(api.PATH_TYPE_DATASTORE_JSON)
@@ -36,3 +36,8 @@ func NewDashboardPathManager(dashboard_type, artifact,
 			SetType(api.PATH_TYPE_DATASTORE_JSON),
 	}
 }
+
+// Dashboards shared with all users in the org.
+func SharedDashboard(id string) api.DSPathSpec {
+	return DASHBOARDS_ROOT.AddChild(id).SetTag("Dashboard")
+}
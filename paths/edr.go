@@ -0,0 +1,20 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// Each EDR connector keeps its polling state in a single record.
+type EDRPathManager struct{}
+
+func NewEDRPathManager() *EDRPathManager {
+	return &EDRPathManager{}
+}
+
+func (self EDRPathManager) Path() api.DSPathSpec {
+	return EDR_ROOT.SetDir()
+}
+
+func (self EDRPathManager) Connector(name string) api.DSPathSpec {
+	return EDR_ROOT.AddUnsafeChild(name).SetTag("EDRConnector")
+}
@@ -0,0 +1,28 @@
+package paths
+
+import (
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// Manages the cache of lookups against external enrichment services
+// (e.g. VirusTotal). Each service has its own namespace keyed by the
+// lookup key (usually a hash).
+type EnrichmentPathManager struct {
+	service string
+}
+
+func NewEnrichmentPathManager(service string) *EnrichmentPathManager {
+	return &EnrichmentPathManager{service: service}
+}
+
+func (self EnrichmentPathManager) Path() api.DSPathSpec {
+	return ENRICHMENT_ROOT.AddChild(self.service).SetDir()
+}
+
+// Hashes are case insensitive so we normalize the key.
+func (self EnrichmentPathManager) Cache(key string) api.DSPathSpec {
+	return ENRICHMENT_ROOT.AddUnsafeChild(self.service, strings.ToLower(key)).
+		SetTag("Enrichment")
+}
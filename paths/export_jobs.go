@@ -0,0 +1,10 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// The status of an export job.
+func ExportJobPath(job_id string) api.DSPathSpec {
+	return EXPORT_JOBS_ROOT.AddChild(job_id).SetTag("ExportJob")
+}
@@ -0,0 +1,13 @@
+package paths
+
+import "www.velocidex.com/golang/velociraptor/file_store/api"
+
+// Favorites shared with all users in the org.
+func SharedFavorite(name, type_name string) api.DSPathSpec {
+	return FAVORITES_ROOT.AddChild(type_name, name).SetTag("Favorites")
+}
+
+// The directory that contains all the shared favorites of this type.
+func SharedFavoriteDir(type_name string) api.DSPathSpec {
+	return FAVORITES_ROOT.AddChild(type_name)
+}
@@ -0,0 +1,27 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// Managed GeoIP databases are stored in the filestore by their
+// edition name (e.g. GeoLite2-City.mmdb).
+type GeoIPPathManager struct{}
+
+func NewGeoIPPathManager() *GeoIPPathManager {
+	return &GeoIPPathManager{}
+}
+
+func (self GeoIPPathManager) Path() api.FSPathSpec {
+	return GEOIP_ROOT
+}
+
+func (self GeoIPPathManager) Database(filename string) api.FSPathSpec {
+	return GEOIP_ROOT.AddUnsafeChild(filename)
+}
+
+// New databases are downloaded here first and then moved into place
+// so readers never see a partial file.
+func (self GeoIPPathManager) Temp(filename string) api.FSPathSpec {
+	return GEOIP_ROOT.AddUnsafeChild(filename + ".tmp")
+}
@@ -38,7 +38,7 @@ func (self HuntPathManager) GetHuntDownloadsStats(only_combined bool,
 
 func NewHuntPathManager(hunt_id string) *HuntPathManager {
 	return &HuntPathManager{
-		path:    HUNTS_ROOT.AddChild(hunt_id),
+		path:    HUNTS_ROOT.AddChild(hunt_id).SetTag("Hunt"),
 		hunt_id: hunt_id,
 	}
 }
@@ -47,6 +47,30 @@ func (self HuntPathManager) Stats() api.DSPathSpec {
 	return self.path.AddChild("stats")
 }
 
+// The rate limit for scheduling new clients on the hunt.
+func (self HuntPathManager) SchedulingRate() api.DSPathSpec {
+	return self.path.AddChild("scheduling_rate").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+}
+
+// Limits on the total resources used by the hunt's collections.
+func (self HuntPathManager) Quota() api.DSPathSpec {
+	return self.path.AddChild("quota").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+}
+
+// The resources used by the hunt's completed collections so far.
+func (self HuntPathManager) QuotaUsage() api.DSPathSpec {
+	return self.path.AddChild("quota_usage").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+}
+
+// A VQL query which selects the clients the hunt applies to.
+func (self HuntPathManager) VQLCondition() api.DSPathSpec {
+	return self.path.AddChild("vql_condition").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+}
+
 func (self HuntPathManager) HuntDirectory() api.DSPathSpec {
 	return HUNTS_ROOT
 }
@@ -18,6 +18,18 @@ func (self *PathManagerTestSuite) TestHuntPathManager() {
 	assert.Equal(self.T(), "/ds/hunts/H.1234/stats.db",
 		self.getDatastorePath(manager.Stats()))
 
+	assert.Equal(self.T(), "/ds/hunts/H.1234/scheduling_rate.json.db",
+		self.getDatastorePath(manager.SchedulingRate()))
+
+	assert.Equal(self.T(), "/ds/hunts/H.1234/vql_condition.json.db",
+		self.getDatastorePath(manager.VQLCondition()))
+
+	assert.Equal(self.T(), "/ds/hunts/H.1234/quota.json.db",
+		self.getDatastorePath(manager.Quota()))
+
+	assert.Equal(self.T(), "/ds/hunts/H.1234/quota_usage.json.db",
+		self.getDatastorePath(manager.QuotaUsage()))
+
 	assert.Equal(self.T(), "/fs/hunts/H.1234.json",
 		self.getFilestorePath(manager.Clients()))
 
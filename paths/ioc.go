@@ -0,0 +1,21 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// Each threat intel collection is stored as a single table of
+// indicators together with its polling state.
+type IOCPathManager struct{}
+
+func NewIOCPathManager() *IOCPathManager {
+	return &IOCPathManager{}
+}
+
+func (self IOCPathManager) Path() api.DSPathSpec {
+	return IOC_ROOT.SetDir()
+}
+
+func (self IOCPathManager) Collection(name string) api.DSPathSpec {
+	return IOC_ROOT.AddUnsafeChild(name).SetTag("IOCTable")
+}
@@ -0,0 +1,23 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// A legal hold on all the client's data.
+func ClientLegalHoldPath(client_id string) api.DSPathSpec {
+	return LEGAL_HOLDS_ROOT.AddChild("clients", client_id).
+		SetTag("LegalHold")
+}
+
+// A legal hold on a single collection.
+func FlowLegalHoldPath(client_id, flow_id string) api.DSPathSpec {
+	return LEGAL_HOLDS_ROOT.AddChild("flows", client_id, flow_id).
+		SetTag("LegalHold")
+}
+
+// A legal hold on the hunt and all its collections.
+func HuntLegalHoldPath(hunt_id string) api.DSPathSpec {
+	return LEGAL_HOLDS_ROOT.AddChild("hunts", hunt_id).
+		SetTag("LegalHold")
+}
@@ -0,0 +1,9 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+func MaskingPolicyPath(name string) api.DSPathSpec {
+	return MASKING_POLICIES_ROOT.AddChild(name).SetTag("MaskingPolicy")
+}
@@ -141,6 +141,10 @@ type NotebookCellPathManager struct {
 	client_id            string
 }
 
+func (self *NotebookCellPathManager) NotebookId() string {
+	return self.notebook_id
+}
+
 func (self *NotebookCellPathManager) Directory() api.FSPathSpec {
 	return self.root.AddChild(self.notebook_id, self.cell_id).AsFilestorePath()
 }
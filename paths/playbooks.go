@@ -0,0 +1,10 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// A response started by a playbook.
+func PlaybookRunPath(id string) api.DSPathSpec {
+	return PLAYBOOK_RUNS_ROOT.AddChild(id).SetTag("PlaybookRun")
+}
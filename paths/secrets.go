@@ -0,0 +1,10 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// A named secret. The value is encrypted before it is stored here.
+func SecretPath(name string) api.DSPathSpec {
+	return SECRETS_ROOT.AddChild(name).SetTag("Secret")
+}
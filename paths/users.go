@@ -1,6 +1,9 @@
 package paths
 
-import "www.velocidex.com/golang/velociraptor/file_store/api"
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
 
 type UserPathManager struct {
 	Name string
@@ -53,6 +56,24 @@ func (self UserPathManager) FavoriteDir(type_name string) api.DSPathSpec {
 	return USERS_ROOT.AddChild(self.Name, "Favorites", type_name)
 }
 
+// Where we store the user's private dashboards
+func (self UserPathManager) Dashboard(id string) api.DSPathSpec {
+	return USERS_ROOT.AddChild(self.Name, "dashboards", id).
+		SetTag("Dashboard")
+}
+
+// The directory containing all the user's private dashboards
+func (self UserPathManager) DashboardDir() api.DSPathSpec {
+	return USERS_ROOT.AddChild(self.Name, "dashboards")
+}
+
+// The queries the user ran in notebooks.
+func (self UserPathManager) QueryHistory() api.FSPathSpec {
+	return path_specs.NewUnsafeFilestorePath(
+		"users", self.Name, "query_history").
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+}
+
 // Controls the schema of user related data.
 func NewUserPathManager(username string) *UserPathManager {
 	return &UserPathManager{username}
@@ -22,4 +22,7 @@ func (self *PathManagerTestSuite) TestUserPathManager() {
 
 	assert.Equal(self.T(), "/ds/users/%E4%BD%A0%E5%A5%BD%E4%B8%96%E7%95%8C/Favorites/CLIENT/%E4%BD%A0%E5%A5%BD%E4%B8%96%E7%95%8C.json.db",
 		self.getDatastorePath(manager.Favorites("你好世界", "CLIENT")))
+
+	assert.Equal(self.T(), "/ds/favorites/CLIENT/%E4%BD%A0%E5%A5%BD%E4%B8%96%E7%95%8C.json.db",
+		self.getDatastorePath(paths.SharedFavorite("你好世界", "CLIENT")))
 }
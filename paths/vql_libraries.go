@@ -0,0 +1,10 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// All the versions of a VQL library.
+func VQLLibraryPath(name string) api.DSPathSpec {
+	return VQL_LIBRARIES_ROOT.AddChild(name).SetTag("VQLLibrary")
+}
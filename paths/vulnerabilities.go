@@ -0,0 +1,27 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+// The normalized advisories of a vulnerability database.
+func VulnerabilityDatabasePath(name string) api.FSPathSpec {
+	return path_specs.NewUnsafeFilestorePath(
+		"vulnerabilities", "databases", name).
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+}
+
+// The vulnerabilities found in the client's software inventory.
+func VulnerabilityClientReportPath(client_id string) api.FSPathSpec {
+	return path_specs.NewUnsafeFilestorePath(
+		"vulnerabilities", "clients", client_id).
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+}
+
+// The vulnerabilities found across the fleet with the affected
+// clients.
+func VulnerabilityFleetReportPath() api.FSPathSpec {
+	return path_specs.NewUnsafeFilestorePath("vulnerabilities", "fleet").
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+}
@@ -0,0 +1,34 @@
+package paths
+
+import (
+	"fmt"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// Messages waiting for delivery are persisted individually so they
+// survive a server restart without rewriting the whole queue.
+type WebhookPathManager struct {
+	name string
+}
+
+func NewWebhookPathManager(name string) *WebhookPathManager {
+	return &WebhookPathManager{name: name}
+}
+
+// The delivery status of the destination.
+func (self WebhookPathManager) Queue() api.DSPathSpec {
+	return WEBHOOK_ROOT.AddUnsafeChild(self.name, "status").
+		SetTag("WebhookQueue")
+}
+
+func (self WebhookPathManager) Pending() api.DSPathSpec {
+	return WEBHOOK_ROOT.AddUnsafeChild(self.name, "pending")
+}
+
+// Messages are named by their sequence number so they list in
+// delivery order.
+func (self WebhookPathManager) Message(sequence uint64) api.DSPathSpec {
+	return self.Pending().AddChild(fmt.Sprintf("%016x", sequence)).
+		SetTag("WebhookMessage")
+}
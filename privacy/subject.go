@@ -0,0 +1,576 @@
+/*
+  Locate, export and purge all stored data referencing a data subject.
+
+  A subject is identified by a string such as a username, hostname or
+  client id. Data referencing the subject is found in two ways:
+
+  1. Path matches: The identifier is a component of the item's path
+     (e.g. users/<username>/... or clients/<client_id>/...). The
+     entire item belongs to the subject and is deleted on purge.
+
+  2. Content matches: The identifier appears in the item's data
+     (case insensitive, as a whole word). On purge:
+     - Rows referencing the subject are removed from result sets.
+     - The identifier is replaced in JSON datastore records so
+       records referencing it (e.g. hunts created by a user) remain
+       usable.
+     - Protobuf datastore records can not be edited and need manual
+       review.
+     - Timelines are derived from other results and are deleted.
+     - The audit log is retained as the record of the purge.
+
+  Data on legal hold is reported but never purged.
+
+  Only result sets are searched for content in the filestore -
+  uploaded files are only matched by path.
+*/
+
+package privacy
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/legal_hold"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	ACTION_DELETE      = "delete"
+	ACTION_DELETE_ROWS = "delete_rows"
+	ACTION_REDACT      = "redact"
+	ACTION_RETAIN      = "retain"
+	ACTION_MANUAL      = "manual"
+	ACTION_HOLD        = "hold"
+
+	PURGED = "[PURGED]"
+
+	// Shorter identifiers match too much data to be safely purged.
+	MIN_IDENTIFIER_LENGTH = 3
+)
+
+var (
+	// The audit log records the purge itself so it is never purged.
+	retainedArtifacts = []string{"Server.Audit.Logs"}
+)
+
+// A stored item referencing the subject.
+type Match struct {
+	// Datastore or Filestore
+	Type string `json:"type"`
+	Path string `json:"path"`
+
+	// "path" if the identifier is a component of the path, otherwise
+	// "content".
+	Reason string `json:"reason"`
+
+	// Number of result set rows referencing the subject.
+	Rows int64 `json:"rows,omitempty"`
+
+	// What purging does to this item.
+	Action string `json:"action"`
+
+	Purged bool   `json:"purged,omitempty"`
+	Error  string `json:"error,omitempty"`
+
+	ds_path api.DSPathSpec
+	fs_path api.FSPathSpec
+}
+
+type Searcher struct {
+	config_obj *config_proto.Config
+	identifier string
+
+	// Matches the identifier in raw data.
+	raw_regex *regexp.Regexp
+
+	// Matches the JSON encoded identifier. Groups 1 and 3 are the
+	// surrounding characters.
+	json_regex *regexp.Regexp
+}
+
+func NewSearcher(
+	config_obj *config_proto.Config, identifier string) (*Searcher, error) {
+	identifier = strings.TrimSpace(identifier)
+	if len(identifier) < MIN_IDENTIFIER_LENGTH {
+		return nil, errors.New("Identifier is too short")
+	}
+
+	encoded, err := json.Marshal(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Searcher{
+		config_obj: config_obj,
+		identifier: identifier,
+		raw_regex:  wordRegex(identifier),
+		json_regex: wordRegex(string(encoded[1 : len(encoded)-1])),
+	}, nil
+}
+
+func wordRegex(value string) *regexp.Regexp {
+	return regexp.MustCompile(
+		`(?i)(^|[^\w-])(` + regexp.QuoteMeta(value) + `)($|[^\w-])`)
+}
+
+func (self *Searcher) pathMatches(components []string) bool {
+	for _, c := range components {
+		if strings.EqualFold(c, self.identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRetained(components []string) bool {
+	for _, c := range components {
+		if utils.InString(retainedArtifacts, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Enumerate all items referencing the subject.
+func (self *Searcher) Search(
+	ctx context.Context, cb func(match *Match) error) error {
+	err := self.searchDatastore(ctx, cb)
+	if err != nil {
+		return err
+	}
+	return self.searchFilestore(ctx, cb)
+}
+
+func (self *Searcher) searchDatastore(
+	ctx context.Context, cb func(match *Match) error) error {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return errors.New("Datastore does not support raw access")
+	}
+
+	return datastore.Walk(self.config_obj, db,
+		path_specs.NewUnsafeDatastorePath(),
+		datastore.WalkWithoutDirectories,
+		func(path api.DSPathSpec) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			match := &Match{
+				Type:    "Datastore",
+				Path:    path.AsClientPath(),
+				ds_path: path,
+			}
+
+			if self.pathMatches(path.Components()) {
+				match.Reason = "path"
+				match.Action = ACTION_DELETE
+				return self.emit(match, path.Components(), cb)
+			}
+
+			data, err := raw_db.GetBuffer(self.config_obj, path)
+			if err != nil {
+				return nil
+			}
+
+			switch path.Type() {
+			case api.PATH_TYPE_DATASTORE_JSON:
+				if !self.json_regex.Match(data) {
+					return nil
+				}
+				match.Action = ACTION_REDACT
+
+			default:
+				if !self.raw_regex.Match(data) {
+					return nil
+				}
+				match.Action = ACTION_MANUAL
+			}
+
+			match.Reason = "content"
+			return self.emit(match, path.Components(), cb)
+		})
+}
+
+// Held items are reported but never purged.
+func (self *Searcher) emit(match *Match, components []string,
+	cb func(match *Match) error) error {
+	if legal_hold.IsPathHeld(self.config_obj, components) {
+		match.Action = ACTION_HOLD
+	}
+	return cb(match)
+}
+
+func (self *Searcher) searchFilestore(
+	ctx context.Context, cb func(match *Match) error) error {
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+
+	return api.Walk(file_store_factory, path_specs.NewUnsafeFilestorePath(),
+		func(path api.FSPathSpec, info os.FileInfo) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			// Datastore files are already covered when the datastore
+			// shares the filestore directory.
+			if self.sharedDirectory() {
+				switch path.Type() {
+				case api.PATH_TYPE_FILESTORE_DB, api.PATH_TYPE_FILESTORE_DB_JSON:
+					return nil
+				}
+			}
+
+			match := &Match{
+				Type:    "Filestore",
+				Path:    path.AsClientPath(),
+				fs_path: path,
+			}
+
+			if self.pathMatches(path.Components()) {
+				match.Reason = "path"
+				match.Action = ACTION_DELETE
+				return self.emit(match, path.Components(), cb)
+			}
+
+			// Only result sets are searched - the index is
+			// rewritten with them.
+			if path.Type() != api.PATH_TYPE_FILESTORE_JSON {
+				return nil
+			}
+
+			rows, err := self.countRows(file_store_factory, path)
+			if err != nil || rows == 0 {
+				return nil
+			}
+
+			match.Reason = "content"
+			match.Rows = rows
+			match.Action = ACTION_DELETE_ROWS
+
+			if isRetained(path.Components()) {
+				match.Action = ACTION_RETAIN
+
+			} else if isTimeline(file_store_factory, path) {
+				match.Action = ACTION_DELETE
+			}
+
+			return self.emit(match, path.Components(), cb)
+		})
+}
+
+func (self *Searcher) sharedDirectory() bool {
+	if self.config_obj.Datastore == nil {
+		return false
+	}
+	return self.config_obj.Datastore.FilestoreDirectory ==
+		self.config_obj.Datastore.Location
+}
+
+// Timelines are indexed by time and can not be rewritten.
+func isTimeline(file_store_factory api.FileStore, path api.FSPathSpec) bool {
+	_, err := file_store_factory.StatFile(
+		path.SetType(api.PATH_TYPE_FILESTORE_JSON_TIME_INDEX))
+	return err == nil
+}
+
+// Call cb on every row of the result set.
+func eachRow(file_store_factory api.FileStore, path api.FSPathSpec,
+	cb func(row []byte) error) error {
+	fd, err := file_store_factory.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	reader := bufio.NewReader(fd)
+	for {
+		row, err := reader.ReadBytes('\n')
+		if len(row) > 0 {
+			err := cb(row)
+			if err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (self *Searcher) countRows(
+	file_store_factory api.FileStore, path api.FSPathSpec) (int64, error) {
+	count := int64(0)
+	err := eachRow(file_store_factory, path, func(row []byte) error {
+		if self.json_regex.Match(row) {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Write all data referencing the subject into a zip file. Items
+// matching by path are exported in full, result sets only with the
+// rows referencing the subject.
+func (self *Searcher) Export(
+	ctx context.Context, writer io.Writer) ([]*Match, error) {
+	zip_writer := zip.NewWriter(writer)
+	defer zip_writer.Close()
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, errors.New("Datastore does not support raw access")
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+
+	matches := []*Match{}
+	err = self.Search(ctx, func(match *Match) error {
+		matches = append(matches, match)
+
+		out, err := zip_writer.Create(
+			strings.ToLower(match.Type) + match.Path)
+		if err != nil {
+			return err
+		}
+
+		if match.ds_path != nil {
+			data, err := raw_db.GetBuffer(self.config_obj, match.ds_path)
+			if err != nil {
+				match.Error = err.Error()
+				return nil
+			}
+			_, err = out.Write(data)
+			return err
+		}
+
+		return eachRow(file_store_factory, match.fs_path,
+			func(row []byte) error {
+				if match.Reason == "path" || self.json_regex.Match(row) {
+					_, err := out.Write(row)
+					return err
+				}
+				return nil
+			})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := zip_writer.Create("matches.json")
+	if err != nil {
+		return nil, err
+	}
+
+	serialized, err := json.MarshalIndent(ordereddict.NewDict().
+		Set("identifier", self.identifier).
+		Set("org_id", self.config_obj.OrgId).
+		Set("matches", matches))
+	if err != nil {
+		return nil, err
+	}
+	_, err = out.Write(serialized)
+
+	return matches, err
+}
+
+// Irreversibly remove all data referencing the subject. The purge is
+// recorded in the audit log.
+func (self *Searcher) Purge(ctx context.Context,
+	principal string, cb func(match *Match) error) error {
+
+	// Collect all matches first so we do not modify the stores while
+	// walking them.
+	matches := []*Match{}
+	err := self.Search(ctx, func(match *Match) error {
+		matches = append(matches, match)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+
+	deleted_rows := int64(0)
+	for _, match := range matches {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := self.purgeMatch(db, file_store_factory, match)
+		if err != nil {
+			match.Error = err.Error()
+		} else if match.Action != ACTION_RETAIN &&
+			match.Action != ACTION_MANUAL &&
+			match.Action != ACTION_HOLD {
+			match.Purged = true
+			if match.Action == ACTION_DELETE_ROWS {
+				deleted_rows += match.Rows
+			}
+		}
+
+		err = cb(match)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Drop the cached client record.
+	if constants.ClientIdRegex.MatchString(self.identifier) &&
+		!legal_hold.IsClientHeld(self.config_obj, self.identifier) {
+		client_info_manager, err := services.GetClientInfoManager(
+			self.config_obj)
+		if err == nil {
+			client_info_manager.Remove(ctx, self.identifier)
+		}
+	}
+
+	return services.LogAudit(ctx, self.config_obj, principal,
+		"SubjectPurge", ordereddict.NewDict().
+			Set("identifier", self.identifier).
+			Set("org_id", self.config_obj.OrgId).
+			Set("items", len(matches)).
+			Set("deleted_rows", deleted_rows))
+}
+
+func (self *Searcher) purgeMatch(
+	db datastore.DataStore, file_store_factory api.FileStore,
+	match *Match) error {
+	switch match.Action {
+	case ACTION_DELETE:
+		if match.ds_path != nil {
+			return db.DeleteSubject(self.config_obj, match.ds_path)
+		}
+
+		err := file_store_factory.Delete(match.fs_path)
+		if err != nil || match.Reason == "path" {
+			return err
+		}
+
+		// A timeline is deleted with its indexes.
+		for _, t := range []api.PathType{
+			api.PATH_TYPE_FILESTORE_JSON_INDEX,
+			api.PATH_TYPE_FILESTORE_JSON_TIME_INDEX} {
+			_ = file_store_factory.Delete(match.fs_path.SetType(t))
+		}
+		return nil
+
+	case ACTION_REDACT:
+		raw_db, ok := db.(datastore.RawDataStore)
+		if !ok {
+			return errors.New("Datastore does not support raw access")
+		}
+
+		data, err := raw_db.GetBuffer(self.config_obj, match.ds_path)
+		if err != nil {
+			return err
+		}
+
+		return raw_db.SetBuffer(self.config_obj, match.ds_path,
+			self.redact(data), utils.BackgroundWriter)
+
+	case ACTION_DELETE_ROWS:
+		return self.deleteRows(file_store_factory, match.fs_path)
+	}
+
+	return nil
+}
+
+// Replace all occurrences of the identifier. Adjacent occurrences
+// share the separating character so repeat until none are left.
+func (self *Searcher) redact(data []byte) []byte {
+	for self.json_regex.Match(data) {
+		data = self.json_regex.ReplaceAll(data, []byte("${1}"+PURGED+"${3}"))
+	}
+	return data
+}
+
+// Rewrite the result set without the rows referencing the subject.
+func (self *Searcher) deleteRows(
+	file_store_factory api.FileStore, path api.FSPathSpec) error {
+	tmpfile, err := ioutil.TempFile("", "purge")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpfile.Name())
+	defer tmpfile.Close()
+
+	err = eachRow(file_store_factory, path, func(row []byte) error {
+		if self.json_regex.Match(row) {
+			return nil
+		}
+		_, err := tmpfile.Write(row)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tmpfile.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		path, json.DefaultEncOpts(), utils.BackgroundWriter,
+		result_sets.TruncateMode)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	reader := bufio.NewReader(tmpfile)
+	for {
+		row, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(row)) > 0 {
+			if row[len(row)-1] != '\n' {
+				row = append(row, '\n')
+			}
+			writer.WriteJSONL(row, 1)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
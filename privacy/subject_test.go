@@ -0,0 +1,218 @@
+package privacy_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"sort"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/privacy"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services/legal_hold"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+var (
+	user_path = path_specs.NewSafeDatastorePath("users", "mike")
+	hunt_path = path_specs.NewSafeDatastorePath("hunts", "H.1234").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+	other_hunt_path = path_specs.NewSafeDatastorePath("hunts", "H.5678").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	result_path = path_specs.NewSafeFilestorePath(
+		"clients", "C.123", "artifacts", "Windows.Sys.Users", "F.1")
+	audit_path = path_specs.NewSafeFilestorePath(
+		"server_artifacts", "Server.Audit.Logs", "2023-11-15")
+	upload_path = path_specs.NewSafeFilestorePath(
+		"clients", "C.123", "uploads", "mike", "ntuser.dat").
+		SetType(api.PATH_TYPE_FILESTORE_ANY)
+)
+
+type SubjectTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *SubjectTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	assert.NoError(self.T(), db.SetSubject(self.ConfigObj, user_path,
+		&api_proto.VelociraptorUser{Name: "mike"}))
+	assert.NoError(self.T(), db.SetSubject(self.ConfigObj, hunt_path,
+		&api_proto.Hunt{HuntId: "H.1234", Creator: "Mike"}))
+
+	// Only matches whole words.
+	assert.NoError(self.T(), db.SetSubject(self.ConfigObj, other_hunt_path,
+		&api_proto.Hunt{HuntId: "H.5678", Creator: "mikes"}))
+
+	self.writeRows(result_path,
+		ordereddict.NewDict().Set("Name", "mike").Set("Uid", 1),
+		ordereddict.NewDict().Set("Name", "admin").Set("Uid", 2),
+		ordereddict.NewDict().Set("Name", "DOMAIN\\mike").Set("Uid", 3))
+
+	self.writeRows(audit_path,
+		ordereddict.NewDict().Set("principal", "mike"))
+
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	fd, err := file_store_factory.WriteFile(upload_path)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("hello"))
+	assert.NoError(self.T(), err)
+	fd.Close()
+}
+
+func (self *SubjectTestSuite) writeRows(
+	path api.FSPathSpec, rows ...*ordereddict.Dict) {
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		path, json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	for _, row := range rows {
+		writer.Write(row)
+	}
+	writer.Close()
+}
+
+func (self *SubjectTestSuite) search(identifier string) []string {
+	searcher, err := privacy.NewSearcher(self.ConfigObj, identifier)
+	assert.NoError(self.T(), err)
+
+	result := []string{}
+	err = searcher.Search(self.Ctx, func(match *privacy.Match) error {
+		result = append(result, match.Path+" "+match.Action)
+		return nil
+	})
+	assert.NoError(self.T(), err)
+	sort.Strings(result)
+
+	return result
+}
+
+func (self *SubjectTestSuite) TestSearch() {
+	assert.Equal(self.T(), []string{
+		"/clients/C.123/artifacts/Windows.Sys.Users/F.1.json delete_rows",
+		"/clients/C.123/uploads/mike/ntuser.dat delete",
+		"/hunts/H.1234.json.db redact",
+		"/server_artifacts/Server.Audit.Logs/2023-11-15.json retain",
+		"/users/mike.json.db delete",
+	}, self.search("mike"))
+
+	_, err := privacy.NewSearcher(self.ConfigObj, "mi")
+	assert.Error(self.T(), err)
+}
+
+func (self *SubjectTestSuite) TestExport() {
+	searcher, err := privacy.NewSearcher(self.ConfigObj, "mike")
+	assert.NoError(self.T(), err)
+
+	buf := &bytes.Buffer{}
+	matches, err := searcher.Export(self.Ctx, buf)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 5, len(matches))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()),
+		int64(buf.Len()))
+	assert.NoError(self.T(), err)
+
+	files := make(map[string]string)
+	for _, f := range reader.File {
+		fd, err := f.Open()
+		assert.NoError(self.T(), err)
+		data, err := ioutil.ReadAll(fd)
+		assert.NoError(self.T(), err)
+		files[f.Name] = string(data)
+	}
+
+	// Only the rows referencing the subject are exported.
+	assert.Equal(self.T(),
+		"{\"Name\":\"mike\",\"Uid\":1}\n{\"Name\":\"DOMAIN\\\\mike\",\"Uid\":3}\n",
+		files["filestore/clients/C.123/artifacts/Windows.Sys.Users/F.1.json"])
+	assert.Equal(self.T(), "hello",
+		files["filestore/clients/C.123/uploads/mike/ntuser.dat"])
+	assert.Contains(self.T(), files["matches.json"], `"identifier": "mike"`)
+}
+
+func (self *SubjectTestSuite) TestPurge() {
+	searcher, err := privacy.NewSearcher(self.ConfigObj, "mike")
+	assert.NoError(self.T(), err)
+
+	err = searcher.Purge(self.Ctx, "admin",
+		func(match *privacy.Match) error {
+			assert.Equal(self.T(), "", match.Error)
+			return nil
+		})
+	assert.NoError(self.T(), err)
+
+	// Only the retained audit log still references the subject.
+	assert.Equal(self.T(), []string{
+		"/server_artifacts/Server.Audit.Logs/2023-11-15.json retain",
+	}, self.search("mike"))
+
+	// Other rows are kept.
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	rs_reader, err := result_sets.NewResultSetReader(
+		file_store_factory, result_path)
+	assert.NoError(self.T(), err)
+	defer rs_reader.Close()
+
+	assert.Equal(self.T(), int64(1), rs_reader.TotalRows())
+
+	// The hunt is kept with its creator redacted.
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	hunt := &api_proto.Hunt{}
+	assert.NoError(self.T(), db.GetSubject(self.ConfigObj, hunt_path, hunt))
+	assert.Equal(self.T(), privacy.PURGED, hunt.Creator)
+
+	assert.NoError(self.T(), db.GetSubject(
+		self.ConfigObj, other_hunt_path, hunt))
+	assert.Equal(self.T(), "mikes", hunt.Creator)
+}
+
+func (self *SubjectTestSuite) TestLegalHold() {
+	_, err := legal_hold.Place(self.Ctx, self.ConfigObj, "admin",
+		&legal_hold.Hold{Type: legal_hold.TYPE_CLIENT, ClientId: "C.123"})
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), []string{
+		"/clients/C.123/artifacts/Windows.Sys.Users/F.1.json hold",
+		"/clients/C.123/uploads/mike/ntuser.dat hold",
+		"/hunts/H.1234.json.db redact",
+		"/server_artifacts/Server.Audit.Logs/2023-11-15.json retain",
+		"/users/mike.json.db delete",
+	}, self.search("mike"))
+
+	searcher, err := privacy.NewSearcher(self.ConfigObj, "mike")
+	assert.NoError(self.T(), err)
+
+	err = searcher.Purge(self.Ctx, "admin",
+		func(match *privacy.Match) error { return nil })
+	assert.NoError(self.T(), err)
+
+	// The held client's data is kept.
+	assert.Equal(self.T(), []string{
+		"/clients/C.123/artifacts/Windows.Sys.Users/F.1.json hold",
+		"/clients/C.123/uploads/mike/ntuser.dat hold",
+		"/server_artifacts/Server.Audit.Logs/2023-11-15.json retain",
+	}, self.search("mike"))
+}
+
+func TestSubject(t *testing.T) {
+	suite.Run(t, &SubjectTestSuite{})
+}
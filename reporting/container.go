@@ -112,11 +112,10 @@ type Container struct {
 	// implementation.
 	zip *concurrent_zip.Writer
 
-	// If a password is set, we create a new zip file here, and a
-	// member within it then redirect the zip above to write on
-	// it.
-	delegate_zip *zip.Writer
-	delegate_fd  io.Writer
+	// If the container is encrypted, we create a new outer container
+	// here, and a member within it then redirect the zip above to
+	// write on it.
+	delegate io.Closer
 
 	// manage orderly shutdown of the container.
 	mu sync.Mutex
@@ -589,8 +588,12 @@ func (self *Container) Close() error {
 
 	self.zip.Close()
 
-	if self.delegate_zip != nil {
-		self.delegate_zip.Close()
+	if self.delegate != nil {
+		err := self.delegate.Close()
+		if err != nil {
+			self.fd.Close()
+			return err
+		}
 	}
 
 	// Only report the hash if we actually wrote something (few bytes
@@ -643,8 +646,15 @@ func NewContainer(
 func NewContainerFromWriter(
 	config_obj *config_proto.Config, fd io.WriteCloser,
 	password string, level int64, metadata []vfilter.Row) (*Container, error) {
+	return NewEncryptedContainerFromWriter(config_obj, fd,
+		&EncryptionOptions{Method: ENCRYPTION_ZIP, Password: password},
+		level, metadata)
+}
 
-	var err error
+func NewEncryptedContainerFromWriter(
+	config_obj *config_proto.Config, fd io.WriteCloser,
+	encryption *EncryptionOptions, level int64,
+	metadata []vfilter.Row) (*Container, error) {
 
 	if level < 0 || level > 9 {
 		level = 5
@@ -665,29 +675,15 @@ func NewContainerFromWriter(
 	result.stats.Timestamp = uint64(Clock.Now().Unix())
 
 	// We need to build a protected container.
-	if password != "" {
-
-		result.delegate_zip = zip.NewWriter(result.writer)
-		if metadata != nil && len(metadata) != 0 {
-			fh, err := result.delegate_zip.Create("metadata.json")
-			if err != nil {
-				return nil, err
-			}
-			fh.Write(json.MustMarshalIndent(metadata))
-		}
-		// We are writing a zip file into here - no need to
-		// compress.
-		fh := &zip.FileHeader{
-			Name:   "data.zip",
-			Method: zip.Store,
-		}
-		fh.SetPassword(password)
-		result.delegate_fd, err = result.delegate_zip.CreateHeader(fh)
+	if encryption.IsEncrypted() {
+		delegate, delegate_fd, err := newEncryptedDelegate(
+			result.writer, encryption, metadata)
 		if err != nil {
 			return nil, err
 		}
+		result.delegate = delegate
+		result.zip = concurrent_zip.NewWriter(delegate_fd)
 
-		result.zip = concurrent_zip.NewWriter(result.delegate_fd)
 	} else {
 		result.zip = concurrent_zip.NewWriter(result.writer)
 		result.zip.RegisterCompressor(
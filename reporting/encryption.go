@@ -0,0 +1,261 @@
+package reporting
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/alexmullins/zip"
+	"www.velocidex.com/golang/velociraptor/crypto/age"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/reporting/sevenzip"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// Protected containers always hold the collection as a zip file
+// (data.zip) which is wrapped in an encrypted outer container.
+const (
+	// An AES-256 encrypted zip file.
+	ENCRYPTION_ZIP = "zip"
+
+	// An AES-256 encrypted 7z archive.
+	ENCRYPTION_7Z = "7z"
+
+	// A tar file encrypted with age to a passphrase or to the
+	// recipients' public keys.
+	ENCRYPTION_AGE = "age"
+)
+
+type EncryptionOptions struct {
+	Method     string
+	Password   string
+	Recipients []string
+}
+
+func (self *EncryptionOptions) IsEncrypted() bool {
+	return self != nil && (self.Password != "" || len(self.Recipients) > 0)
+}
+
+// The file store type of the exported container.
+func (self *EncryptionOptions) PathType() api.PathType {
+	if self.IsEncrypted() {
+		switch self.Method {
+		case ENCRYPTION_7Z:
+			return api.PATH_TYPE_FILESTORE_DOWNLOAD_7Z
+		case ENCRYPTION_AGE:
+			return api.PATH_TYPE_FILESTORE_DOWNLOAD_AGE
+		}
+	}
+	return api.PATH_TYPE_FILESTORE_DOWNLOAD_ZIP
+}
+
+// Validate the user's encryption options. When no method is given we
+// use age if recipients are specified, and an encrypted zip
+// otherwise.
+func NewEncryptionOptions(
+	method, password string, recipients []string) (*EncryptionOptions, error) {
+	result := &EncryptionOptions{
+		Method:     method,
+		Password:   password,
+		Recipients: recipients,
+	}
+
+	switch method {
+	case "":
+		result.Method = ENCRYPTION_ZIP
+		if len(recipients) > 0 {
+			result.Method = ENCRYPTION_AGE
+		}
+		return NewEncryptionOptions(result.Method, password, recipients)
+
+	case ENCRYPTION_ZIP, ENCRYPTION_7Z:
+		if len(recipients) > 0 {
+			return nil, fmt.Errorf(
+				"Recipients are only supported with age encryption")
+		}
+
+	case ENCRYPTION_AGE:
+		if password != "" && len(recipients) > 0 {
+			return nil, fmt.Errorf(
+				"age encryption requires either a password or recipients but not both")
+		}
+
+		if password == "" && len(recipients) == 0 {
+			return nil, fmt.Errorf(
+				"age encryption requires a password or recipients")
+		}
+
+		for _, r := range recipients {
+			_, err := age.ParseRecipient(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf(
+			"Unknown encryption %v: should be 'zip', '7z' or 'age'", method)
+	}
+
+	return result, nil
+}
+
+// Create the outer container on out. Returns the writer for the
+// data.zip member and a closer which finalizes the outer container.
+func newEncryptedDelegate(
+	out io.Writer, options *EncryptionOptions,
+	metadata []vfilter.Row) (io.Closer, io.Writer, error) {
+	switch options.Method {
+	case ENCRYPTION_7Z:
+		return newSevenZipDelegate(out, options, metadata)
+
+	case ENCRYPTION_AGE:
+		return newAgeDelegate(out, options, metadata)
+
+	default:
+		return newZipDelegate(out, options, metadata)
+	}
+}
+
+func newZipDelegate(
+	out io.Writer, options *EncryptionOptions,
+	metadata []vfilter.Row) (io.Closer, io.Writer, error) {
+	delegate_zip := zip.NewWriter(out)
+	if len(metadata) != 0 {
+		fh, err := delegate_zip.Create("metadata.json")
+		if err != nil {
+			return nil, nil, err
+		}
+		fh.Write(json.MustMarshalIndent(metadata))
+	}
+
+	// We are writing a zip file into here - no need to
+	// compress.
+	fh := &zip.FileHeader{
+		Name:   "data.zip",
+		Method: zip.Store,
+	}
+	fh.SetPassword(options.Password)
+	delegate_fd, err := delegate_zip.CreateHeader(fh)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return delegate_zip, delegate_fd, nil
+}
+
+func newSevenZipDelegate(
+	out io.Writer, options *EncryptionOptions,
+	metadata []vfilter.Row) (io.Closer, io.Writer, error) {
+	archive, err := sevenzip.NewWriter(out, options.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := Clock.Now()
+	if len(metadata) != 0 {
+		fh, err := archive.Create("metadata.json", now)
+		if err != nil {
+			return nil, nil, err
+		}
+		fh.Write(json.MustMarshalIndent(metadata))
+	}
+
+	delegate_fd, err := archive.Create("data.zip", now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return archive, delegate_fd, nil
+}
+
+// Tar headers need the size of each member so data.zip is spooled to
+// a temporary file and the tar is written when the container closes.
+type ageDelegate struct {
+	out      io.WriteCloser
+	spool    *os.File
+	metadata []byte
+}
+
+func (self *ageDelegate) Close() error {
+	defer os.Remove(self.spool.Name())
+	defer self.spool.Close()
+
+	size, err := self.spool.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	_, err = self.spool.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	now := Clock.Now()
+	tar_writer := tar.NewWriter(self.out)
+	if len(self.metadata) > 0 {
+		err = tar_writer.WriteHeader(&tar.Header{
+			Name:    "metadata.json",
+			Mode:    0600,
+			Size:    int64(len(self.metadata)),
+			ModTime: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tar_writer.Write(self.metadata)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tar_writer.WriteHeader(&tar.Header{
+		Name:    "data.zip",
+		Mode:    0600,
+		Size:    size,
+		ModTime: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tar_writer, self.spool)
+	if err != nil {
+		return err
+	}
+
+	err = tar_writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return self.out.Close()
+}
+
+func newAgeDelegate(
+	out io.Writer, options *EncryptionOptions,
+	metadata []vfilter.Row) (io.Closer, io.Writer, error) {
+	age_writer, err := age.NewWriter(out, options.Recipients, options.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spool, err := ioutil.TempFile("", "export")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &ageDelegate{
+		out:   age_writer,
+		spool: spool,
+	}
+	if len(metadata) != 0 {
+		result.metadata = json.MustMarshalIndent(metadata)
+	}
+
+	return result, spool, nil
+}
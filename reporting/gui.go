@@ -30,6 +30,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/result_sets/columnar"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/timelines"
 	"www.velocidex.com/golang/velociraptor/utils"
@@ -686,10 +687,21 @@ func (self *GuiTemplateEngine) RunQuery(vql *vfilter.VQL,
 	result = append(result, path)
 
 	file_store_factory := file_store.GetFileStore(self.config_obj)
-	rs_writer, err := result_sets.NewResultSetWriter(
-		file_store_factory, path.Path(),
-		opts, utils.SyncCompleter,
-		result_sets.TruncateMode)
+
+	var rs_writer result_sets.ResultSetWriter
+	var err error
+
+	if self.useColumnarStorage() {
+		rs_writer, err = columnar.NewColumnarResultSetWriter(
+			file_store_factory, path.Path(),
+			opts, utils.SyncCompleter,
+			result_sets.TruncateMode)
+	} else {
+		rs_writer, err = result_sets.NewResultSetWriter(
+			file_store_factory, path.Path(),
+			opts, utils.SyncCompleter,
+			result_sets.TruncateMode)
+	}
 	if err != nil {
 		self.Error("Error: %v\n", err)
 		return result, nil
@@ -745,6 +757,13 @@ func (self *GuiTemplateEngine) RunQuery(vql *vfilter.VQL,
 	}
 }
 
+// Hunt results can be very large so hunt notebook tables may be
+// stored in columnar format to speed up sorting and filtering.
+func (self *GuiTemplateEngine) useColumnarStorage() bool {
+	return self.config_obj.Defaults.GetHuntResultStorage() == "columnar" &&
+		strings.HasPrefix(self.path_manager.NotebookId(), "N.H.")
+}
+
 func IsEmptyQuery(query string) bool {
 	return whitespace_regexp.MatchString(query)
 }
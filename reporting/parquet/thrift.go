@@ -0,0 +1,100 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Parquet metadata is serialized using the thrift compact
+// protocol. We only ever write a handful of structs so this
+// implements just enough of the protocol for them.
+// https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md
+
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+type thriftWriter struct {
+	buf bytes.Buffer
+
+	// The last field id written in each nested struct - field ids
+	// are delta encoded.
+	last_field []int16
+}
+
+func (self *thriftWriter) varint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	self.buf.Write(tmp[:n])
+}
+
+func (self *thriftWriter) zigzag(v int64) {
+	self.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (self *thriftWriter) fieldHeader(id int16, field_type byte) {
+	last := self.last_field[len(self.last_field)-1]
+	delta := id - last
+	if delta > 0 && delta <= 15 {
+		self.buf.WriteByte(byte(delta)<<4 | field_type)
+	} else {
+		self.buf.WriteByte(field_type)
+		self.zigzag(int64(id))
+	}
+	self.last_field[len(self.last_field)-1] = id
+}
+
+func (self *thriftWriter) structBegin() {
+	self.last_field = append(self.last_field, 0)
+}
+
+func (self *thriftWriter) structEnd() {
+	self.buf.WriteByte(0)
+	self.last_field = self.last_field[:len(self.last_field)-1]
+}
+
+func (self *thriftWriter) i32Field(id int16, v int32) {
+	self.fieldHeader(id, thriftI32)
+	self.zigzag(int64(v))
+}
+
+func (self *thriftWriter) i64Field(id int16, v int64) {
+	self.fieldHeader(id, thriftI64)
+	self.zigzag(v)
+}
+
+func (self *thriftWriter) stringField(id int16, v string) {
+	self.fieldHeader(id, thriftBinary)
+	self.varint(uint64(len(v)))
+	self.buf.WriteString(v)
+}
+
+func (self *thriftWriter) structField(id int16) {
+	self.fieldHeader(id, thriftStruct)
+	self.structBegin()
+}
+
+// Starts a list field. The caller then writes size elements of
+// elem_type.
+func (self *thriftWriter) listField(id int16, elem_type byte, size int) {
+	self.fieldHeader(id, thriftList)
+	if size < 15 {
+		self.buf.WriteByte(byte(size)<<4 | elem_type)
+	} else {
+		self.buf.WriteByte(0xf0 | elem_type)
+		self.varint(uint64(size))
+	}
+}
+
+func (self *thriftWriter) i32Elem(v int32) {
+	self.zigzag(int64(v))
+}
+
+func (self *thriftWriter) stringElem(v string) {
+	self.varint(uint64(len(v)))
+	self.buf.WriteString(v)
+}
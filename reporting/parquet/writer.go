@@ -0,0 +1,507 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/golang/snappy"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+// Physical types, encodings and codecs from parquet.thrift
+// https://github.com/apache/parquet-format/blob/master/src/main/thrift/parquet.thrift
+const (
+	typeBoolean   = 0
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	repetitionOptional = 1
+
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecSnappy = 1
+
+	pageTypeData = 0
+
+	DEFAULT_ROW_GROUP_SIZE = 10000
+)
+
+var magic = []byte("PAR1")
+
+// The type of a column inferred from the values in it.
+type columnKind int
+
+const (
+	kindNull columnKind = iota
+	kindBool
+	kindInt
+	kindFloat
+	kindTimestamp
+	kindString
+)
+
+// Combine the kind of a new value with the kind of the column so
+// far. Integers are widened to floats and any other mix falls back
+// to strings.
+func mergeKinds(a, b columnKind) columnKind {
+	switch {
+	case a == kindNull:
+		return b
+	case b == kindNull || a == b:
+		return a
+	case (a == kindInt && b == kindFloat) || (a == kindFloat && b == kindInt):
+		return kindFloat
+	default:
+		return kindString
+	}
+}
+
+func kindOf(value interface{}) columnKind {
+	switch t := value.(type) {
+	case nil:
+		return kindNull
+	case bool:
+		return kindBool
+	case int64:
+		return kindInt
+	case uint64:
+		// Parquet has no unsigned 64 bit type so larger values are
+		// stored as strings.
+		if t > math.MaxInt64 {
+			return kindString
+		}
+		return kindInt
+	case float64:
+		return kindFloat
+	case time.Time:
+		return kindTimestamp
+	default:
+		return kindString
+	}
+}
+
+type column struct {
+	name string
+	kind columnKind
+}
+
+type columnChunk struct {
+	column            *column
+	num_values        int64
+	offset            int64
+	uncompressed_size int64
+	compressed_size   int64
+}
+
+type rowGroup struct {
+	columns  []*columnChunk
+	num_rows int64
+}
+
+// Writes rows into a Parquet file. The file has a flat schema with
+// one optional column for each top level field. Nested values are
+// stored as JSON strings.
+//
+// The schema is inferred from the first row group: column types are
+// chosen to fit all the values seen in it. Fields which first appear
+// in later rows are dropped and values which do not fit the column
+// type are stored as nulls (or converted for string columns).
+type Writer struct {
+	out    io.Writer
+	offset int64
+	opts   *json.EncOpts
+
+	row_group_size int
+	buffer         []*ordereddict.Dict
+
+	columns    []*column
+	row_groups []*rowGroup
+	num_rows   int64
+}
+
+func (self *Writer) Write(row *ordereddict.Dict) error {
+	// Normalize the row through JSON so values are typed the same
+	// way as in the JSON exports.
+	serialized, err := json.MarshalWithOptions(row, self.opts)
+	if err != nil {
+		return err
+	}
+
+	normalized := ordereddict.NewDict()
+	err = json.Unmarshal(serialized, normalized)
+	if err != nil {
+		return err
+	}
+
+	self.buffer = append(self.buffer, normalized)
+	if len(self.buffer) >= self.row_group_size {
+		return self.flush()
+	}
+	return nil
+}
+
+func (self *Writer) write(data []byte) error {
+	n, err := self.out.Write(data)
+	self.offset += int64(n)
+	return err
+}
+
+func (self *Writer) inferSchema() {
+	kinds := ordereddict.NewDict()
+	for _, row := range self.buffer {
+		for _, k := range row.Keys() {
+			v, _ := row.Get(k)
+			kind, pres := kinds.Get(k)
+			if !pres {
+				kind = kindNull
+			}
+			kinds.Set(k, mergeKinds(kind.(columnKind), kindOf(v)))
+		}
+	}
+
+	self.columns = []*column{}
+	for _, k := range kinds.Keys() {
+		kind, _ := kinds.Get(k)
+		if kind == kindNull {
+			kind = kindString
+		}
+		self.columns = append(self.columns, &column{
+			name: k, kind: kind.(columnKind),
+		})
+	}
+}
+
+// Write the buffered rows as a row group.
+func (self *Writer) flush() error {
+	if self.columns == nil {
+		self.inferSchema()
+	}
+
+	if self.offset == 0 {
+		err := self.write(magic)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(self.buffer) == 0 {
+		return nil
+	}
+
+	row_group := &rowGroup{num_rows: int64(len(self.buffer))}
+	for _, column := range self.columns {
+		chunk, err := self.writeColumnChunk(column)
+		if err != nil {
+			return err
+		}
+		row_group.columns = append(row_group.columns, chunk)
+	}
+
+	self.row_groups = append(self.row_groups, row_group)
+	self.num_rows += row_group.num_rows
+	self.buffer = nil
+
+	return nil
+}
+
+// Each column chunk consists of a single data page holding the
+// definition levels (1 for present values, 0 for nulls) followed by
+// the plain encoded present values.
+func (self *Writer) writeColumnChunk(column *column) (*columnChunk, error) {
+	levels := make([]byte, 0, len(self.buffer))
+	values := &bytes.Buffer{}
+	booleans := []bool{}
+
+	for _, row := range self.buffer {
+		value, _ := row.Get(column.name)
+		value, ok := convertValue(column.kind, value)
+		if !ok {
+			levels = append(levels, 0)
+			continue
+		}
+		levels = append(levels, 1)
+
+		switch t := value.(type) {
+		case bool:
+			booleans = append(booleans, t)
+		case int64:
+			_ = binary.Write(values, binary.LittleEndian, t)
+		case float64:
+			_ = binary.Write(values, binary.LittleEndian, math.Float64bits(t))
+		case string:
+			_ = binary.Write(values, binary.LittleEndian, uint32(len(t)))
+			values.WriteString(t)
+		}
+	}
+
+	if column.kind == kindBool {
+		values.Write(packBooleans(booleans))
+	}
+
+	encoded_levels := encodeLevels(levels)
+	page := &bytes.Buffer{}
+	_ = binary.Write(page, binary.LittleEndian, uint32(len(encoded_levels)))
+	page.Write(encoded_levels)
+	page.Write(values.Bytes())
+
+	compressed := snappy.Encode(nil, page.Bytes())
+
+	header := &thriftWriter{}
+	header.structBegin()
+	header.i32Field(1, pageTypeData)
+	header.i32Field(2, int32(page.Len()))
+	header.i32Field(3, int32(len(compressed)))
+	header.structField(5)
+	header.i32Field(1, int32(len(levels)))
+	header.i32Field(2, encodingPlain)
+	header.i32Field(3, encodingRLE)
+	header.i32Field(4, encodingRLE)
+	header.structEnd()
+	header.structEnd()
+
+	chunk := &columnChunk{
+		column:            column,
+		num_values:        int64(len(levels)),
+		offset:            self.offset,
+		uncompressed_size: int64(header.buf.Len() + page.Len()),
+		compressed_size:   int64(header.buf.Len() + len(compressed)),
+	}
+
+	err := self.write(header.buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return chunk, self.write(compressed)
+}
+
+// Write the remaining rows and the file footer.
+func (self *Writer) Close() error {
+	err := self.flush()
+	if err != nil {
+		return err
+	}
+
+	footer := &thriftWriter{}
+	footer.structBegin()
+	footer.i32Field(1, 1)
+
+	// The schema is a root element followed by the columns.
+	footer.listField(2, thriftStruct, len(self.columns)+1)
+	footer.structBegin()
+	footer.stringField(4, "schema")
+	footer.i32Field(5, int32(len(self.columns)))
+	footer.structEnd()
+
+	for _, column := range self.columns {
+		physical_type, converted_type := column.types()
+		footer.structBegin()
+		footer.i32Field(1, physical_type)
+		footer.i32Field(3, repetitionOptional)
+		footer.stringField(4, column.name)
+		if converted_type >= 0 {
+			footer.i32Field(6, converted_type)
+		}
+		footer.structEnd()
+	}
+
+	footer.i64Field(3, self.num_rows)
+
+	footer.listField(4, thriftStruct, len(self.row_groups))
+	for _, row_group := range self.row_groups {
+		total_size := int64(0)
+
+		footer.structBegin()
+		footer.listField(1, thriftStruct, len(row_group.columns))
+		for _, chunk := range row_group.columns {
+			total_size += chunk.uncompressed_size
+			physical_type, _ := chunk.column.types()
+
+			footer.structBegin()
+			footer.i64Field(2, chunk.offset)
+			footer.structField(3)
+			footer.i32Field(1, physical_type)
+			footer.listField(2, thriftI32, 2)
+			footer.i32Elem(encodingPlain)
+			footer.i32Elem(encodingRLE)
+			footer.listField(3, thriftBinary, 1)
+			footer.stringElem(chunk.column.name)
+			footer.i32Field(4, codecSnappy)
+			footer.i64Field(5, chunk.num_values)
+			footer.i64Field(6, chunk.uncompressed_size)
+			footer.i64Field(7, chunk.compressed_size)
+			footer.i64Field(9, chunk.offset)
+			footer.structEnd()
+			footer.structEnd()
+		}
+		footer.i64Field(2, total_size)
+		footer.i64Field(3, row_group.num_rows)
+		footer.structEnd()
+	}
+
+	footer.stringField(6, "Velociraptor version "+constants.VERSION)
+	footer.structEnd()
+
+	err = self.write(footer.buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	length := make([]byte, 4)
+	binary.LittleEndian.PutUint32(length, uint32(footer.buf.Len()))
+	err = self.write(length)
+	if err != nil {
+		return err
+	}
+
+	return self.write(magic)
+}
+
+// The physical and converted type (or -1 for none) of the column.
+func (self *column) types() (int32, int32) {
+	switch self.kind {
+	case kindBool:
+		return typeBoolean, -1
+	case kindInt:
+		return typeInt64, -1
+	case kindFloat:
+		return typeDouble, -1
+	case kindTimestamp:
+		return typeInt64, convertedTimestampMicros
+	default:
+		return typeByteArray, convertedUTF8
+	}
+}
+
+// Convert the value to the type stored in the column. Returns false
+// if the value should be stored as a null.
+func convertValue(kind columnKind, value interface{}) (interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+
+	switch kind {
+	case kindBool:
+		v, ok := value.(bool)
+		return v, ok
+
+	case kindInt:
+		switch t := value.(type) {
+		case int64:
+			return t, true
+		case uint64:
+			if t <= math.MaxInt64 {
+				return int64(t), true
+			}
+		}
+
+	case kindFloat:
+		switch t := value.(type) {
+		case int64:
+			return float64(t), true
+		case uint64:
+			return float64(t), true
+		case float64:
+			return t, true
+		}
+
+	case kindTimestamp:
+		t, ok := value.(time.Time)
+		if ok {
+			return t.UnixNano() / 1000, true
+		}
+
+	case kindString:
+		return valueToString(value), true
+	}
+
+	return nil, false
+}
+
+func valueToString(value interface{}) string {
+	switch t := value.(type) {
+	case string:
+		return t
+
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+
+	case bool:
+		return strconv.FormatBool(t)
+
+	case uint64:
+		return strconv.FormatUint(t, 10)
+
+	case int64:
+		return strconv.FormatInt(t, 10)
+
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+
+	default:
+		serialized, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprintf("%v", t)
+		}
+		return string(serialized)
+	}
+}
+
+// Encode definition levels (bit width 1) using the RLE/bit packing
+// hybrid encoding. We only emit RLE runs.
+func encodeLevels(levels []byte) []byte {
+	result := []byte{}
+	var tmp [binary.MaxVarintLen64]byte
+
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+
+		n := binary.PutUvarint(tmp[:], uint64(j-i)<<1)
+		result = append(result, tmp[:n]...)
+		result = append(result, levels[i])
+		i = j
+	}
+	return result
+}
+
+// Plain encoded booleans are bit packed, least significant bit first.
+func packBooleans(values []bool) []byte {
+	result := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			result[i/8] |= 1 << (uint(i) % 8)
+		}
+	}
+	return result
+}
+
+func NewWriter(out io.Writer, opts *json.EncOpts) *Writer {
+	return &Writer{
+		out:            out,
+		opts:           opts,
+		row_group_size: DEFAULT_ROW_GROUP_SIZE,
+	}
+}
+
+// Set the number of rows in each row group (default 10000). All the
+// rows in a row group are kept in memory until it is written.
+func (self *Writer) SetRowGroupSize(size int) {
+	if size > 0 {
+		self.row_group_size = size
+	}
+}
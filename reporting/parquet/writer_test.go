@@ -0,0 +1,244 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A minimal thrift compact protocol reader which decodes structs
+// into maps of field id to value.
+type thriftReader struct {
+	*bytes.Reader
+}
+
+func (self *thriftReader) zigzag() int64 {
+	v, _ := binary.ReadUvarint(self)
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (self *thriftReader) value(field_type byte) interface{} {
+	switch field_type {
+	case thriftI32, thriftI64:
+		return self.zigzag()
+
+	case thriftBinary:
+		length, _ := binary.ReadUvarint(self)
+		buf := make([]byte, length)
+		_, _ = self.Read(buf)
+		return string(buf)
+
+	case thriftList:
+		header, _ := self.ReadByte()
+		size := int(header >> 4)
+		if size == 15 {
+			s, _ := binary.ReadUvarint(self)
+			size = int(s)
+		}
+		result := []interface{}{}
+		for i := 0; i < size; i++ {
+			result = append(result, self.value(header&0x0f))
+		}
+		return result
+
+	case thriftStruct:
+		result := map[int16]interface{}{}
+		last := int16(0)
+		for {
+			header, _ := self.ReadByte()
+			if header == 0 {
+				return result
+			}
+			id := last + int16(header>>4)
+			if header>>4 == 0 {
+				id = int16(self.zigzag())
+			}
+			result[id] = self.value(header & 0x0f)
+			last = id
+		}
+	}
+	panic("Unsupported thrift type")
+}
+
+// Read back all the columns in the file as lists of values.
+func readParquet(t *testing.T, data []byte) (
+	[]map[int16]interface{}, *ordereddict.Dict) {
+	require.Equal(t, "PAR1", string(data[:4]))
+	require.Equal(t, "PAR1", string(data[len(data)-4:]))
+
+	footer_len := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer_data := data[len(data)-8-footer_len : len(data)-8]
+	metadata := (&thriftReader{bytes.NewReader(footer_data)}).
+		value(thriftStruct).(map[int16]interface{})
+
+	schema := []map[int16]interface{}{}
+	for _, item := range metadata[2].([]interface{}) {
+		schema = append(schema, item.(map[int16]interface{}))
+	}
+
+	columns := ordereddict.NewDict()
+	for _, rg := range metadata[4].([]interface{}) {
+		for idx, c := range rg.(map[int16]interface{})[1].([]interface{}) {
+			element := schema[idx+1]
+			name := element[4].(string)
+
+			meta := c.(map[int16]interface{})[3].(map[int16]interface{})
+			reader := &thriftReader{bytes.NewReader(data[meta[9].(int64):])}
+			header := reader.value(thriftStruct).(map[int16]interface{})
+			compressed := make([]byte, header[3].(int64))
+			_, _ = reader.Read(compressed)
+
+			page, err := snappy.Decode(nil, compressed)
+			require.NoError(t, err)
+			require.Equal(t, int(header[2].(int64)), len(page))
+
+			values, _ := columns.Get(name)
+			if values == nil {
+				values = []interface{}{}
+			}
+			columns.Set(name, append(values.([]interface{}),
+				decodePage(element[1].(int64), page)...))
+		}
+	}
+
+	return schema, columns
+}
+
+func decodePage(physical_type int64, page []byte) []interface{} {
+	levels_len := binary.LittleEndian.Uint32(page)
+	levels_reader := bytes.NewReader(page[4 : 4+levels_len])
+	values := bytes.NewReader(page[4+levels_len:])
+
+	levels := []byte{}
+	for levels_reader.Len() > 0 {
+		header, _ := binary.ReadUvarint(levels_reader)
+		level, _ := levels_reader.ReadByte()
+		for i := uint64(0); i < header>>1; i++ {
+			levels = append(levels, level)
+		}
+	}
+
+	result := []interface{}{}
+	bit := 0
+	booleans := []byte{}
+	if physical_type == typeBoolean {
+		booleans = page[4+levels_len:]
+	}
+	for _, level := range levels {
+		if level == 0 {
+			result = append(result, nil)
+			continue
+		}
+
+		switch physical_type {
+		case typeBoolean:
+			result = append(result, booleans[bit/8]&(1<<(bit%8)) != 0)
+			bit++
+
+		case typeInt64:
+			var v int64
+			_ = binary.Read(values, binary.LittleEndian, &v)
+			result = append(result, v)
+
+		case typeDouble:
+			var v uint64
+			_ = binary.Read(values, binary.LittleEndian, &v)
+			result = append(result, math.Float64frombits(v))
+
+		case typeByteArray:
+			var length uint32
+			_ = binary.Read(values, binary.LittleEndian, &length)
+			buf := make([]byte, length)
+			_, _ = values.Read(buf)
+			result = append(result, string(buf))
+		}
+	}
+	return result
+}
+
+func TestParquetWriter(t *testing.T) {
+	ts := time.Unix(1700000000, 123000).UTC()
+
+	out := &bytes.Buffer{}
+	writer := NewWriter(out, nil)
+	writer.SetRowGroupSize(2)
+
+	rows := []*ordereddict.Dict{
+		ordereddict.NewDict().
+			Set("Name", "a").
+			Set("Size", 10).
+			Set("Ratio", 1).
+			Set("Active", true).
+			Set("Time", ts).
+			Set("Info", ordereddict.NewDict().Set("X", 1)).
+			Set("Big", uint64(math.MaxUint64)),
+		ordereddict.NewDict().
+			Set("Name", "b").
+			Set("Size", nil).
+			Set("Ratio", 0.5).
+			Set("Active", false).
+			Set("Time", ts.Add(time.Second)).
+			Set("Info", []string{"x"}).
+			Set("Big", 1),
+
+		// The second row group has a new column which is
+		// dropped and values which do not fit their columns.
+		ordereddict.NewDict().
+			Set("Name", 5).
+			Set("Size", uint64(1)<<63).
+			Set("Ratio", uint64(math.MaxUint64)).
+			Set("Active", "big").
+			Set("Extra", 1),
+	}
+
+	for _, row := range rows {
+		assert.NoError(t, writer.Write(row))
+	}
+	assert.NoError(t, writer.Close())
+
+	schema, columns := readParquet(t, out.Bytes())
+
+	// Root element and the columns with their physical types.
+	assert.Equal(t, int64(7), schema[0][5])
+	types := ordereddict.NewDict()
+	for _, element := range schema[1:] {
+		types.Set(element[4].(string), []interface{}{element[1], element[6]})
+	}
+	assert.Equal(t, ordereddict.NewDict().
+		Set("Name", []interface{}{int64(typeByteArray), int64(convertedUTF8)}).
+		Set("Size", []interface{}{int64(typeInt64), nil}).
+		Set("Ratio", []interface{}{int64(typeDouble), nil}).
+		Set("Active", []interface{}{int64(typeBoolean), nil}).
+		Set("Time", []interface{}{int64(typeInt64), int64(convertedTimestampMicros)}).
+		Set("Info", []interface{}{int64(typeByteArray), int64(convertedUTF8)}).
+		Set("Big", []interface{}{int64(typeByteArray), int64(convertedUTF8)}),
+		types)
+
+	assert.Equal(t, ordereddict.NewDict().
+		Set("Name", []interface{}{"a", "b", "5"}).
+		Set("Size", []interface{}{int64(10), nil, nil}).
+		Set("Ratio", []interface{}{float64(1), 0.5, float64(math.MaxUint64)}).
+		Set("Active", []interface{}{true, false, nil}).
+		Set("Time", []interface{}{
+			ts.UnixNano() / 1000, ts.Add(time.Second).UnixNano() / 1000, nil}).
+		Set("Info", []interface{}{`{"X":1}`, `["x"]`, nil}).
+		Set("Big", []interface{}{"18446744073709551615", "1", nil}),
+		columns)
+}
+
+func TestParquetWriterEmpty(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer := NewWriter(out, nil)
+	assert.NoError(t, writer.Close())
+
+	schema, columns := readParquet(t, out.Bytes())
+	assert.Equal(t, 1, len(schema))
+	assert.Equal(t, 0, columns.Len())
+}
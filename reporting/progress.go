@@ -2,12 +2,17 @@ package reporting
 
 import (
 	"context"
+	"sync"
 	"time"
 
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services/export_jobs"
 )
 
 type progressReporter struct {
@@ -19,21 +24,123 @@ type progressReporter struct {
 	container_path api.FSPathSpec
 
 	Type string
+
+	mu sync.Mutex
+
+	// Set when the export is tracked as an export job.
+	job_id    string
+	principal string
+
+	// Progress is estimated from the number of items exported.
+	total     int64
+	completed int64
+
+	closed bool
+	err    error
 }
 
-func (self *progressReporter) writeStats() {
-	db, err := datastore.GetDB(self.config_obj)
-	if err != nil {
-		return
-	}
+func (self *progressReporter) stats() *api_proto.ContainerStats {
 	stats := self.zip_writer.Stats()
 	stats.Type = self.Type
 	stats.Components = path_specs.AsGenericComponentList(self.container_path)
+	stats.JobId = self.job_id
+	stats.Principal = self.principal
+
+	switch {
+	case self.err != nil:
+		stats.State = export_jobs.STATE_ERROR
+		stats.Error = self.err.Error()
+
+	case self.closed:
+		stats.State = export_jobs.STATE_FINISHED
+		stats.Progress = 100
+
+	default:
+		stats.State = export_jobs.STATE_RUNNING
+		if self.total > 0 {
+			stats.Progress = uint64(self.completed * 100 / self.total)
+
+			// Only report 100% when the container is closed.
+			if stats.Progress > 99 {
+				stats.Progress = 99
+			}
+		}
+	}
+
+	return stats
+}
+
+func (self *progressReporter) writeStats() *api_proto.ContainerStats {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	stats := self.stats()
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return stats
+	}
 	_ = db.SetSubject(self.config_obj, self.path, stats)
+
+	if self.job_id != "" {
+		_ = db.SetSubject(self.config_obj,
+			paths.ExportJobPath(self.job_id), stats)
+	}
+	return stats
 }
 
+// Track the export as an export job on behalf of the principal.
+func (self *progressReporter) SetJob(job_id, principal string) {
+	self.mu.Lock()
+	self.job_id = job_id
+	self.principal = principal
+	self.mu.Unlock()
+
+	self.writeStats()
+}
+
+// Add to the number of items we expect to export.
+func (self *progressReporter) AddTotal(count int64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.total += count
+}
+
+// Mark another item as exported.
+func (self *progressReporter) Increment() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.completed++
+}
+
+// Record that the export failed.
+func (self *progressReporter) SetError(err error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.err = err
+}
+
+// Close must be called after the container is closed so the final
+// stats include the container's hash.
 func (self *progressReporter) Close() {
+	self.mu.Lock()
+	self.closed = true
+	self.mu.Unlock()
+
 	self.cancel()
+	stats := self.writeStats()
+
+	if stats.JobId != "" {
+		err := export_jobs.Notify(
+			context.Background(), self.config_obj, stats)
+		if err != nil {
+			logger := logging.GetLogger(self.config_obj, &logging.GUIComponent)
+			logger.Error("Export job %v: %v", stats.JobId, err)
+		}
+	}
 }
 
 func NewProgressReporter(
@@ -60,7 +167,6 @@ func NewProgressReporter(
 		for {
 			select {
 			case <-self.ctx.Done():
-				self.writeStats()
 				return
 
 			case <-time.After(2 * time.Second):
@@ -0,0 +1,440 @@
+/*
+  A minimal writer for AES-256 encrypted 7z archives.
+
+  All members are stored (without compression) in a single solid
+  folder which is encrypted with 7-Zip's AES-256 + SHA-256 coder, so
+  the archive can be opened with 7-Zip and compatible tools using
+  the password.
+
+  The 7z signature header at the start of the archive points to the
+  archive header at the end, so the encrypted data is spooled to a
+  temporary file and the archive is written out on Close().
+*/
+
+package sevenzip
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	// 7-Zip's default key derivation work factor (log2 of the
+	// number of SHA-256 rounds).
+	NUM_CYCLES_POWER = 19
+)
+
+// Property ids used in the archive header.
+const (
+	kEnd              = 0x00
+	kHeader           = 0x01
+	kMainStreamsInfo  = 0x04
+	kFilesInfo        = 0x05
+	kPackInfo         = 0x06
+	kUnPackInfo       = 0x07
+	kSubStreamsInfo   = 0x08
+	kSize             = 0x09
+	kCRC              = 0x0A
+	kFolder           = 0x0B
+	kCodersUnPackSize = 0x0C
+	kNumUnPackStream  = 0x0D
+	kEmptyStream      = 0x0E
+	kEmptyFile        = 0x0F
+	kName             = 0x11
+	kMTime            = 0x14
+)
+
+var (
+	signature = []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C, 0, 4}
+
+	// The 7zAES coder.
+	aesCoderId = []byte{0x06, 0xF1, 0x07, 0x01}
+
+	ErrClosed = errors.New("7z: archive is closed")
+)
+
+type fileInfo struct {
+	name  string
+	mtime time.Time
+	size  uint64
+	crc   hash.Hash32
+}
+
+type Writer struct {
+	out io.Writer
+
+	spool     *os.File
+	spool_buf *bufio.Writer
+	encrypter *cbcWriter
+
+	iv    []byte
+	files []*fileInfo
+
+	closed bool
+}
+
+// Add a new member to the archive. Members are written sequentially
+// - the returned writer is valid until the next call to Create() or
+// Close().
+func (self *Writer) Create(name string, mtime time.Time) (io.Writer, error) {
+	if self.closed {
+		return nil, ErrClosed
+	}
+
+	info := &fileInfo{
+		name:  name,
+		mtime: mtime,
+		crc:   crc32.NewIEEE(),
+	}
+	self.files = append(self.files, info)
+
+	return &memberWriter{owner: self, info: info}, nil
+}
+
+type memberWriter struct {
+	owner *Writer
+	info  *fileInfo
+}
+
+func (self *memberWriter) Write(p []byte) (int, error) {
+	if self.owner.closed {
+		return 0, ErrClosed
+	}
+
+	n, err := self.owner.encrypter.Write(p)
+	self.info.size += uint64(n)
+	self.info.crc.Write(p[:n])
+	return n, err
+}
+
+func (self *Writer) Close() error {
+	if self.closed {
+		return nil
+	}
+	self.closed = true
+
+	defer func() {
+		self.spool.Close()
+		os.Remove(self.spool.Name())
+	}()
+
+	err := self.encrypter.Close()
+	if err != nil {
+		return err
+	}
+
+	err = self.spool_buf.Flush()
+	if err != nil {
+		return err
+	}
+
+	pack_size, err := self.spool.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	header := self.header(uint64(pack_size))
+
+	start_header := make([]byte, 20)
+	binary.LittleEndian.PutUint64(start_header[0:], uint64(pack_size))
+	binary.LittleEndian.PutUint64(start_header[8:], uint64(len(header)))
+	binary.LittleEndian.PutUint32(start_header[16:], crc32.ChecksumIEEE(header))
+
+	crc := make([]byte, 4)
+	binary.LittleEndian.PutUint32(crc, crc32.ChecksumIEEE(start_header))
+
+	for _, data := range [][]byte{signature, crc, start_header} {
+		_, err = self.out.Write(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = self.spool.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(self.out, self.spool)
+	if err != nil {
+		return err
+	}
+
+	_, err = self.out.Write(header)
+	return err
+}
+
+// Build the archive header describing the packed stream and the
+// members.
+func (self *Writer) header(pack_size uint64) []byte {
+	out := &bytes.Buffer{}
+	out.WriteByte(kHeader)
+
+	// Empty members do not have a stream in the folder.
+	var unpack_size uint64
+	streams := []*fileInfo{}
+	empty := make([]bool, len(self.files))
+	has_empty := false
+	for idx, f := range self.files {
+		if f.size == 0 {
+			empty[idx] = true
+			has_empty = true
+			continue
+		}
+		streams = append(streams, f)
+		unpack_size += f.size
+	}
+
+	if len(streams) > 0 {
+		out.WriteByte(kMainStreamsInfo)
+
+		out.WriteByte(kPackInfo)
+		writeNumber(out, 0) // PackPos
+		writeNumber(out, 1) // NumPackStreams
+		out.WriteByte(kSize)
+		writeNumber(out, pack_size)
+		out.WriteByte(kEnd)
+
+		out.WriteByte(kUnPackInfo)
+		out.WriteByte(kFolder)
+		writeNumber(out, 1) // NumFolders
+		out.WriteByte(0)    // External
+
+		// A single simple coder: AES with properties.
+		props := self.coderProperties()
+		writeNumber(out, 1) // NumCoders
+		out.WriteByte(byte(len(aesCoderId)) | 0x20)
+		out.Write(aesCoderId)
+		writeNumber(out, uint64(len(props)))
+		out.Write(props)
+
+		out.WriteByte(kCodersUnPackSize)
+		writeNumber(out, unpack_size)
+		out.WriteByte(kEnd)
+
+		out.WriteByte(kSubStreamsInfo)
+		if len(streams) != 1 {
+			out.WriteByte(kNumUnPackStream)
+			writeNumber(out, uint64(len(streams)))
+
+			// The size of the last stream is implied.
+			out.WriteByte(kSize)
+			for _, f := range streams[:len(streams)-1] {
+				writeNumber(out, f.size)
+			}
+		}
+
+		out.WriteByte(kCRC)
+		out.WriteByte(1) // AllAreDefined
+		for _, f := range streams {
+			_ = binary.Write(out, binary.LittleEndian, f.crc.Sum32())
+		}
+		out.WriteByte(kEnd)
+
+		out.WriteByte(kEnd)
+	}
+
+	out.WriteByte(kFilesInfo)
+	writeNumber(out, uint64(len(self.files)))
+
+	if has_empty {
+		bits := packBits(empty)
+		out.WriteByte(kEmptyStream)
+		writeNumber(out, uint64(len(bits)))
+		out.Write(bits)
+
+		// All empty streams are files rather than directories.
+		empty_files := []bool{}
+		for _, e := range empty {
+			if e {
+				empty_files = append(empty_files, true)
+			}
+		}
+		bits = packBits(empty_files)
+		out.WriteByte(kEmptyFile)
+		writeNumber(out, uint64(len(bits)))
+		out.Write(bits)
+	}
+
+	names := &bytes.Buffer{}
+	names.WriteByte(0) // External
+	for _, f := range self.files {
+		for _, c := range utf16.Encode([]rune(f.name)) {
+			_ = binary.Write(names, binary.LittleEndian, c)
+		}
+		names.Write([]byte{0, 0})
+	}
+	out.WriteByte(kName)
+	writeNumber(out, uint64(names.Len()))
+	out.Write(names.Bytes())
+
+	times := &bytes.Buffer{}
+	times.WriteByte(1) // AllAreDefined
+	times.WriteByte(0) // External
+	for _, f := range self.files {
+		_ = binary.Write(times, binary.LittleEndian, fileTime(f.mtime))
+	}
+	out.WriteByte(kMTime)
+	writeNumber(out, uint64(times.Len()))
+	out.Write(times.Bytes())
+
+	out.WriteByte(kEnd) // FilesInfo
+	out.WriteByte(kEnd) // Header
+
+	return out.Bytes()
+}
+
+// The 7zAES coder properties: the key derivation work factor and the
+// IV (no salt is used).
+func (self *Writer) coderProperties() []byte {
+	result := []byte{
+		NUM_CYCLES_POWER | 0x40,
+		byte(len(self.iv) - 1),
+	}
+	return append(result, self.iv...)
+}
+
+func NewWriter(out io.Writer, password string) (*Writer, error) {
+	if password == "" {
+		return nil, errors.New("7z: a password is required")
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	_, err := rand.Read(iv)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(deriveKey(password, NUM_CYCLES_POWER))
+	if err != nil {
+		return nil, err
+	}
+
+	spool, err := ioutil.TempFile("", "7z")
+	if err != nil {
+		return nil, err
+	}
+	spool_buf := bufio.NewWriter(spool)
+
+	return &Writer{
+		out:       out,
+		spool:     spool,
+		spool_buf: spool_buf,
+		encrypter: &cbcWriter{
+			out:  spool_buf,
+			mode: cipher.NewCBCEncrypter(block, iv),
+		},
+		iv: iv,
+	}, nil
+}
+
+// 7-Zip derives the key by hashing the password (as UTF-16LE) with a
+// round counter 2^num_cycles_power times.
+func deriveKey(password string, num_cycles_power uint) []byte {
+	encoded := &bytes.Buffer{}
+	for _, c := range utf16.Encode([]rune(password)) {
+		_ = binary.Write(encoded, binary.LittleEndian, c)
+	}
+	password_bytes := encoded.Bytes()
+
+	hasher := sha256.New()
+	counter := make([]byte, 8)
+	for round := uint64(0); round < 1<<num_cycles_power; round++ {
+		binary.LittleEndian.PutUint64(counter, round)
+		hasher.Write(password_bytes)
+		hasher.Write(counter)
+	}
+	return hasher.Sum(nil)
+}
+
+// Encrypts in AES-CBC mode, padding the final block with zeros.
+type cbcWriter struct {
+	out  io.Writer
+	mode cipher.BlockMode
+	buf  []byte
+}
+
+func (self *cbcWriter) Write(p []byte) (int, error) {
+	self.buf = append(self.buf, p...)
+
+	complete := len(self.buf) / aes.BlockSize * aes.BlockSize
+	if complete > 0 {
+		data := make([]byte, complete)
+		self.mode.CryptBlocks(data, self.buf[:complete])
+		self.buf = append(self.buf[:0], self.buf[complete:]...)
+
+		_, err := self.out.Write(data)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (self *cbcWriter) Close() error {
+	if len(self.buf) == 0 {
+		return nil
+	}
+
+	data := make([]byte, aes.BlockSize)
+	copy(data, self.buf)
+	self.buf = nil
+	self.mode.CryptBlocks(data, data)
+	_, err := self.out.Write(data)
+	return err
+}
+
+// Numbers in the header use a variable length encoding where the
+// number of leading 1 bits in the first byte gives the number of
+// extra bytes.
+func writeNumber(out *bytes.Buffer, value uint64) {
+	first := byte(0)
+	mask := byte(0x80)
+	i := 0
+	for ; i < 8; i++ {
+		if value < uint64(1)<<(7*(uint(i)+1)) {
+			first |= byte(value >> (8 * uint(i)))
+			break
+		}
+		first |= mask
+		mask >>= 1
+	}
+
+	out.WriteByte(first)
+	for ; i > 0; i-- {
+		out.WriteByte(byte(value))
+		value >>= 8
+	}
+}
+
+// Bit vectors are stored most significant bit first.
+func packBits(values []bool) []byte {
+	result := make([]byte, (len(values)+7)/8)
+	for idx, v := range values {
+		if v {
+			result[idx/8] |= 0x80 >> uint(idx%8)
+		}
+	}
+	return result
+}
+
+// Windows FILETIME: 100ns intervals since 1601.
+func fileTime(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano()/100) + 116444736000000000
+}
@@ -0,0 +1,150 @@
+package sevenzip
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestWriteNumber(t *testing.T) {
+	for _, tc := range []struct {
+		value    uint64
+		expected []byte
+	}{
+		{0, []byte{0x00}},
+		{0x7f, []byte{0x7f}},
+		{0x80, []byte{0x80, 0x80}},
+		{0x3fff, []byte{0xbf, 0xff}},
+		{0x4000, []byte{0xc0, 0x00, 0x40}},
+		{0x123456, []byte{0xd2, 0x56, 0x34}},
+		{1 << 56, []byte{0xff, 0, 0, 0, 0, 0, 0, 0, 1}},
+	} {
+		out := &bytes.Buffer{}
+		writeNumber(out, tc.value)
+		assert.Equal(t, tc.expected, out.Bytes())
+	}
+}
+
+func TestWriter(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer, err := NewWriter(out, "hunter2")
+	assert.NoError(t, err)
+
+	members := []struct {
+		name string
+		data string
+	}{
+		{"metadata.json", "{}"},
+		{"empty.txt", ""},
+		{"data.zip", "Some data which spans a few AES blocks"},
+	}
+
+	mtime := time.Unix(1602103388, 0)
+	for _, m := range members {
+		fd, err := writer.Create(m.name, mtime)
+		assert.NoError(t, err)
+
+		_, err = fd.Write([]byte(m.data))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	data := out.Bytes()
+	assert.Equal(t, signature, data[:6+2])
+
+	// The start header points at the archive header.
+	start_header := data[12:32]
+	assert.Equal(t, crc32.ChecksumIEEE(start_header),
+		binary.LittleEndian.Uint32(data[8:12]))
+
+	pack_size := binary.LittleEndian.Uint64(start_header[0:])
+	header_size := binary.LittleEndian.Uint64(start_header[8:])
+	header := data[32+pack_size:]
+	assert.Equal(t, header_size, uint64(len(header)))
+	assert.Equal(t, crc32.ChecksumIEEE(header),
+		binary.LittleEndian.Uint32(start_header[16:]))
+
+	// Names are stored in UTF-16.
+	assert.True(t, bytes.Contains(header, []byte("e\x00m\x00p\x00t\x00y\x00")))
+
+	// Find the coder properties to recover the IV.
+	idx := bytes.Index(header, aesCoderId)
+	assert.True(t, idx > 0)
+	props := header[idx+len(aesCoderId)+1:]
+	assert.Equal(t, byte(NUM_CYCLES_POWER|0x40), props[0])
+	iv := props[2 : 2+aes.BlockSize]
+
+	block, err := aes.NewCipher(deriveKey("hunter2", NUM_CYCLES_POWER))
+	assert.NoError(t, err)
+
+	packed := data[32 : 32+pack_size]
+	assert.Equal(t, 0, len(packed)%aes.BlockSize)
+
+	plain := make([]byte, len(packed))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, packed)
+
+	expected := ""
+	for _, m := range members {
+		expected += m.data
+	}
+	assert.Equal(t, expected, string(plain[:len(expected)]))
+
+	// Further writes fail.
+	_, err = writer.Create("late.txt", mtime)
+	assert.Equal(t, ErrClosed, err)
+}
+
+// Check the archive with the real 7-Zip tool when it is installed.
+func TestWriterWith7z(t *testing.T) {
+	var seven_zip string
+	for _, name := range []string{"7z", "7zz", "7za"} {
+		path, err := exec.LookPath(name)
+		if err == nil {
+			seven_zip = path
+			break
+		}
+	}
+	if seven_zip == "" {
+		t.Skip("7z is not installed")
+	}
+
+	out := &bytes.Buffer{}
+	writer, err := NewWriter(out, "hunter2")
+	assert.NoError(t, err)
+
+	fd, err := writer.Create("data.txt", time.Unix(1602103388, 0))
+	assert.NoError(t, err)
+
+	_, err = fd.Write([]byte("Some data which spans a few AES blocks"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	filename := filepath.Join(t.TempDir(), "test.7z")
+	assert.NoError(t, os.WriteFile(filename, out.Bytes(), 0600))
+
+	// Testing the archive decrypts all the members and checks their
+	// CRCs.
+	output, err := exec.Command(seven_zip, "t", "-phunter2", filename).
+		CombinedOutput()
+	assert.NoError(t, err, string(output))
+	assert.Contains(t, string(output), "Everything is Ok")
+
+	output, err = exec.Command(seven_zip, "e", "-so", "-phunter2", filename).
+		Output()
+	assert.NoError(t, err)
+	assert.Equal(t, "Some data which spans a few AES blocks", string(output))
+
+	// The wrong password must fail.
+	output, err = exec.Command(seven_zip, "t", "-pwrong", filename).
+		CombinedOutput()
+	assert.Error(t, err, string(output))
+}
@@ -0,0 +1,93 @@
+package siem
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+// Flatten the row into a single level dict. Nested dicts are
+// expanded with their keys joined by the separator. Lists are kept
+// as a single field since SIEMs can not usually handle a variable
+// number of fields - CEF and LEEF render them as JSON strings.
+//
+// The row is first normalized through JSON so all values are
+// rendered the same way as in the JSON exports.
+func Flatten(
+	row *ordereddict.Dict, separator string,
+	opts *json.EncOpts) (*ordereddict.Dict, error) {
+
+	serialized, err := json.MarshalWithOptions(row, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := ordereddict.NewDict()
+	err = json.Unmarshal(serialized, normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ordereddict.NewDict()
+	flatten(result, "", separator, normalized)
+	return result, nil
+}
+
+func flatten(result *ordereddict.Dict,
+	prefix, separator string, value *ordereddict.Dict) {
+	for _, k := range value.Keys() {
+		v, _ := value.Get(k)
+
+		name := k
+		if prefix != "" {
+			name = prefix + separator + k
+		}
+
+		nested, ok := v.(*ordereddict.Dict)
+		if ok {
+			flatten(result, name, separator, nested)
+			continue
+		}
+
+		result.Set(name, v)
+	}
+}
+
+// Render a flattened value as a string.
+func valueToString(value interface{}) string {
+	switch t := value.(type) {
+	case nil:
+		return ""
+
+	case string:
+		return t
+
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+
+	case bool:
+		return strconv.FormatBool(t)
+
+	case uint64:
+		return strconv.FormatUint(t, 10)
+
+	case int64:
+		return strconv.FormatInt(t, 10)
+
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+
+	case []interface{}:
+		serialized, err := json.Marshal(t)
+		if err != nil {
+			return ""
+		}
+		return string(serialized)
+
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
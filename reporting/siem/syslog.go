@@ -0,0 +1,134 @@
+package siem
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+const (
+	SYSLOG_SEVERITY_NOTICE = 5
+	SYSLOG_SEVERITY_INFO   = 6
+
+	// The SD-ID of the structured data element carrying the row's
+	// fields. 32473 is the private enterprise number reserved for
+	// documentation by RFC 5612.
+	SYSLOG_SD_ID = "velociraptor@32473"
+)
+
+var (
+	syslogFacilities = map[string]int{
+		"kern": 0, "user": 1, "mail": 2, "daemon": 3,
+		"auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+		"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+		"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+		"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+	}
+
+	sdValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+)
+
+func ParseSyslogFacility(name string) (int, error) {
+	if name == "" {
+		return syslogFacilities["local0"], nil
+	}
+
+	facility, pres := syslogFacilities[strings.ToLower(name)]
+	if !pres {
+		return 0, fmt.Errorf("Unknown syslog facility %v", name)
+	}
+	return facility, nil
+}
+
+// An RFC 5424 syslog message.
+type SyslogMessage struct {
+	Facility  int
+	Severity  int
+	Timestamp time.Time
+	Hostname  string
+	AppName   string
+	ProcId    string
+	MsgId     string
+
+	// Rendered as SD-PARAMs of a single structured data element.
+	Params *ordereddict.Dict
+	Msg    string
+}
+
+// Format the message, truncating the MSG part so the result is no
+// longer than max_size bytes.
+func (self *SyslogMessage) Format(max_size int) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "<%d>1 %s %s %s %s %s ",
+		self.Facility*8+self.Severity,
+		self.Timestamp.UTC().Format("2006-01-02T15:04:05.000000Z"),
+		headerField(self.Hostname, 255),
+		headerField(self.AppName, 48),
+		headerField(self.ProcId, 128),
+		headerField(self.MsgId, 32))
+
+	if self.Params == nil || self.Params.Len() == 0 {
+		buf.WriteString("-")
+	} else {
+		buf.WriteString("[" + SYSLOG_SD_ID)
+		for _, k := range self.Params.Keys() {
+			v, _ := self.Params.Get(k)
+			name := sdName(k)
+			if name == "" {
+				continue
+			}
+			buf.WriteString(" " + name + `="` +
+				sdValueEscaper.Replace(valueToString(v)) + `"`)
+		}
+		buf.WriteString("]")
+	}
+
+	if self.Msg != "" {
+		buf.WriteString(" " + self.Msg)
+	}
+
+	result := buf.Bytes()
+	if max_size > 0 && len(result) > max_size {
+		result = result[:max_size]
+	}
+	return result
+}
+
+// Header fields are printable US-ASCII without spaces, or "-" when
+// empty.
+func headerField(value string, max_len int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+
+	if value == "" {
+		return "-"
+	}
+
+	if len(value) > max_len {
+		value = value[:max_len]
+	}
+	return value
+}
+
+// SD-NAMEs are at most 32 printable US-ASCII characters and may not
+// contain '=', ' ', ']' or '"'. Invalid characters are replaced with _
+func sdName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return name
+}
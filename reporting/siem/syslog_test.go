@@ -0,0 +1,50 @@
+package siem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyslogMessage(t *testing.T) {
+	flat, err := Flatten(testRow(), ".", nil)
+	assert.NoError(t, err)
+
+	facility, err := ParseSyslogFacility("LOCAL4")
+	assert.NoError(t, err)
+
+	_, err = ParseSyslogFacility("bogus")
+	assert.Error(t, err)
+
+	flat.Set(`Odd "Key"=`, `a]b"c\`)
+	message := &SyslogMessage{
+		Facility:  facility,
+		Severity:  SYSLOG_SEVERITY_NOTICE,
+		Timestamp: time.Unix(1700000000, 123456000),
+		Hostname:  "server host",
+		AppName:   "velociraptor",
+		ProcId:    "123",
+		MsgId:     "Server.Audit.Logs",
+		Params:    flat,
+		Msg:       `{"EventID":4624}`,
+	}
+
+	assert.Equal(t, `<165>1 2023-11-14T22:13:20.123456Z serverhost velociraptor 123 `+
+		`Server.Audit.Logs [velociraptor@32473 EventID="4624" `+
+		`System.Computer="host|1" System.User.Name="bob=admin" `+
+		`Tags="[\"a\",\"b\"\]" Message="line1`+"\n"+`line2`+"\t"+`end" `+
+		`Level="8" Odd__Key__="a\]b\"c\\"] {"EventID":4624}`,
+		string(message.Format(0)))
+
+	// Empty fields are rendered as nil values. Long messages are
+	// truncated.
+	message = &SyslogMessage{
+		Facility:  facility,
+		Severity:  SYSLOG_SEVERITY_INFO,
+		Timestamp: time.Unix(1700000000, 0),
+	}
+	assert.Equal(t, `<166>1 2023-11-14T22:13:20.000000Z - - - - -`,
+		string(message.Format(0)))
+	assert.Equal(t, 20, len(message.Format(20)))
+}
@@ -0,0 +1,274 @@
+package siem
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+const (
+	// Newline delimited JSON with flattened field names.
+	FORMAT_JSONL = "jsonl"
+
+	// ArcSight Common Event Format.
+	FORMAT_CEF = "cef"
+
+	// QRadar Log Event Extended Format (version 1.0).
+	FORMAT_LEEF = "leef"
+
+	DEFAULT_SEVERITY = 5
+)
+
+var (
+	invalidFormatError = errors.New("Unsupported SIEM export format")
+
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+	cefExtensionEscaper = strings.NewReplacer(
+		`\`, `\\`, `=`, `\=`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+	// LEEF has no escaping for attribute values so the delimiter
+	// and line breaks are replaced.
+	leefValueEscaper = strings.NewReplacer(
+		"\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+)
+
+func IsSupportedFormat(format string) bool {
+	switch format {
+	case FORMAT_JSONL, FORMAT_CEF, FORMAT_LEEF:
+		return true
+	}
+	return false
+}
+
+// Writes result rows in a format suitable for SIEM ingestion. Each
+// row is written as a single line.
+type Writer struct {
+	format string
+	out    *bufio.Writer
+	opts   *json.EncOpts
+
+	// Used as the event id and name when the schema does not
+	// specify fields for them - usually the artifact name.
+	source string
+
+	vendor, product, version string
+
+	separator       string
+	signature_field string
+	name_field      string
+	severity_field  string
+	severity        uint64
+
+	field_map map[string]string
+	exclude   map[string]bool
+}
+
+func (self *Writer) Write(row *ordereddict.Dict) error {
+	flat, err := Flatten(row, self.separator, self.opts)
+	if err != nil {
+		return err
+	}
+
+	switch self.format {
+	case FORMAT_CEF:
+		err = self.writeCEF(flat)
+	case FORMAT_LEEF:
+		err = self.writeLEEF(flat)
+	default:
+		err = self.writeJSONL(flat)
+	}
+	if err != nil {
+		return err
+	}
+
+	return self.out.WriteByte('\n')
+}
+
+func (self *Writer) Close() error {
+	return self.out.Flush()
+}
+
+// Apply the schema's field map and exclusions.
+func (self *Writer) fields(flat *ordereddict.Dict) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+	for _, k := range flat.Keys() {
+		if self.exclude[k] {
+			continue
+		}
+
+		v, _ := flat.Get(k)
+		name, pres := self.field_map[k]
+		if !pres {
+			name = k
+		}
+		result.Set(name, v)
+	}
+	return result
+}
+
+func (self *Writer) getString(flat *ordereddict.Dict, field, fallback string) string {
+	if field != "" {
+		value, pres := flat.Get(field)
+		if pres {
+			return valueToString(value)
+		}
+	}
+	return fallback
+}
+
+func (self *Writer) writeJSONL(flat *ordereddict.Dict) error {
+	serialized, err := json.MarshalWithOptions(self.fields(flat), self.opts)
+	if err != nil {
+		return err
+	}
+	_, err = self.out.Write(serialized)
+	return err
+}
+
+// CEF:Version|Device Vendor|Device Product|Device Version|Signature ID|Name|Severity|Extension
+func (self *Writer) writeCEF(flat *ordereddict.Dict) error {
+	signature := self.getString(flat, self.signature_field, self.source)
+	name := self.getString(flat, self.name_field, self.source)
+
+	severity := strconv.FormatUint(self.severity, 10)
+	severity = self.getString(flat, self.severity_field, severity)
+
+	extensions := []string{}
+	fields := self.fields(flat)
+	for _, k := range fields.Keys() {
+		v, _ := fields.Get(k)
+		extensions = append(extensions, fmt.Sprintf("%s=%s",
+			sanitizeKey(k), cefExtensionEscaper.Replace(valueToString(v))))
+	}
+
+	_, err := fmt.Fprintf(self.out, "CEF:0|%s|%s|%s|%s|%s|%s|%s",
+		cefHeaderEscaper.Replace(self.vendor),
+		cefHeaderEscaper.Replace(self.product),
+		cefHeaderEscaper.Replace(self.version),
+		cefHeaderEscaper.Replace(signature),
+		cefHeaderEscaper.Replace(name),
+		cefHeaderEscaper.Replace(severity),
+		strings.Join(extensions, " "))
+	return err
+}
+
+// LEEF:1.0|Vendor|Product|Version|EventID|<tab separated attributes>
+func (self *Writer) writeLEEF(flat *ordereddict.Dict) error {
+	event_id := self.getString(flat, self.signature_field, self.source)
+
+	attributes := []string{}
+	if self.severity_field != "" {
+		severity, pres := flat.Get(self.severity_field)
+		if pres {
+			attributes = append(attributes, "sev="+
+				leefValueEscaper.Replace(valueToString(severity)))
+		}
+	}
+
+	fields := self.fields(flat)
+	for _, k := range fields.Keys() {
+		v, _ := fields.Get(k)
+		attributes = append(attributes, fmt.Sprintf("%s=%s",
+			sanitizeKey(k), leefValueEscaper.Replace(valueToString(v))))
+	}
+
+	_, err := fmt.Fprintf(self.out, "LEEF:1.0|%s|%s|%s|%s|%s",
+		cefHeaderEscaper.Replace(self.vendor),
+		cefHeaderEscaper.Replace(self.product),
+		cefHeaderEscaper.Replace(self.version),
+		cefHeaderEscaper.Replace(event_id),
+		strings.Join(attributes, "\t"))
+	return err
+}
+
+// CEF and LEEF keys may only contain alphanumeric characters. Other
+// characters (like the flattening separator) are replaced with _
+func sanitizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// Create a new writer using the schema from the config file. The
+// source is used as the event id when the schema does not name a
+// field for it.
+func NewWriter(
+	config_obj *config_proto.Config,
+	format string, source string,
+	out io.Writer, opts *json.EncOpts) (*Writer, error) {
+	if !IsSupportedFormat(format) {
+		return nil, fmt.Errorf("%w: %v", invalidFormatError, format)
+	}
+
+	schema := config_obj.GetDefaults().GetSiemExport()
+	if schema == nil {
+		schema = &config_proto.SiemExportConfig{}
+	}
+
+	return NewWriterWithSchema(schema, format, source, out, opts), nil
+}
+
+func NewWriterWithSchema(
+	schema *config_proto.SiemExportConfig,
+	format string, source string,
+	out io.Writer, opts *json.EncOpts) *Writer {
+
+	result := &Writer{
+		format:          format,
+		out:             bufio.NewWriter(out),
+		opts:            opts,
+		source:          source,
+		vendor:          schema.DeviceVendor,
+		product:         schema.DeviceProduct,
+		version:         schema.DeviceVersion,
+		separator:       schema.Separator,
+		signature_field: schema.SignatureField,
+		name_field:      schema.NameField,
+		severity_field:  schema.SeverityField,
+		severity:        schema.DefaultSeverity,
+		field_map:       make(map[string]string),
+		exclude:         make(map[string]bool),
+	}
+
+	if result.vendor == "" {
+		result.vendor = "Velocidex"
+	}
+
+	if result.product == "" {
+		result.product = "Velociraptor"
+	}
+
+	if result.version == "" {
+		result.version = constants.VERSION
+	}
+
+	if result.separator == "" {
+		result.separator = "."
+	}
+
+	if result.severity == 0 {
+		result.severity = DEFAULT_SEVERITY
+	}
+
+	for _, mapping := range schema.FieldMap {
+		result.field_map[mapping.Field] = mapping.Name
+	}
+
+	for _, field := range schema.ExcludeFields {
+		result.exclude[field] = true
+	}
+
+	return result
+}
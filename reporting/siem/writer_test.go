@@ -0,0 +1,62 @@
+package siem
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+)
+
+func testRow() *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("EventID", 4624).
+		Set("System", ordereddict.NewDict().
+			Set("Computer", "host|1").
+			Set("User", ordereddict.NewDict().Set("Name", "bob=admin"))).
+		Set("Tags", []string{"a", "b"}).
+		Set("Message", "line1\nline2\tend").
+		Set("Level", 8)
+}
+
+func TestSIEMWriter(t *testing.T) {
+	schema := &config_proto.SiemExportConfig{
+		DeviceVersion: "1.0",
+		SeverityField: "Level",
+		FieldMap: []*config_proto.SiemFieldMapping{{
+			Field: "System.User.Name", Name: "suser",
+		}},
+		ExcludeFields: []string{"Level"},
+	}
+
+	for _, tc := range []struct {
+		format   string
+		expected string
+	}{{
+		format: FORMAT_JSONL,
+		expected: `{"EventID":4624,"System.Computer":"host|1","suser":"bob=admin",` +
+			`"Tags":["a","b"],"Message":"line1\nline2\tend"}` + "\n",
+	}, {
+		format: FORMAT_CEF,
+		expected: `CEF:0|Velocidex|Velociraptor|1.0|Test.Artifact|Test.Artifact|8|` +
+			`EventID=4624 System_Computer=host|1 suser=bob\=admin ` +
+			`Tags=["a","b"] Message=line1\nline2` + "\tend\n",
+	}, {
+		format: FORMAT_LEEF,
+		expected: `LEEF:1.0|Velocidex|Velociraptor|1.0|Test.Artifact|` +
+			"sev=8\tEventID=4624\tSystem_Computer=host|1\tsuser=bob=admin\t" +
+			`Tags=["a","b"]` + "\tMessage=line1 line2 end\n",
+	}} {
+		out := &bytes.Buffer{}
+		writer := NewWriterWithSchema(schema, tc.format, "Test.Artifact", out, nil)
+		assert.NoError(t, writer.Write(testRow()))
+		assert.NoError(t, writer.Close())
+		assert.Equal(t, tc.expected, out.String(), tc.format)
+	}
+}
+
+func TestSIEMWriterInvalidFormat(t *testing.T) {
+	_, err := NewWriter(&config_proto.Config{}, "xml", "", &bytes.Buffer{}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,354 @@
+package stix
+
+import (
+	"bytes"
+	std_json "encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/google/uuid"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	SPEC_VERSION = "2.1"
+
+	timestampFormat = "2006-01-02T15:04:05.000Z"
+)
+
+var (
+	// Namespace for deterministic identifiers of STIX Cyber-observable
+	// Objects (Section 2.9 of the STIX 2.1 specification).
+	scoNamespace = uuid.MustParse("00abedb4-aa42-466c-9c01-fed23315a9b7")
+
+	// Namespace for our own deterministic identifiers (identities).
+	velociraptorNamespace = uuid.NewSHA1(uuid.NameSpaceURL,
+		[]byte("https://docs.velociraptor.app/"))
+
+	patternEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+)
+
+// Builds a STIX 2.1 bundle from result rows.
+//
+// Each row with observables (hashes, IPs, domains, URLs and file
+// paths) is represented by an observed-data object referencing the
+// extracted Cyber-observable Objects, an indicator matching them
+// which is related to the observed-data with a based-on
+// relationship, and a sighting of the indicator on the client the
+// row came from.
+//
+// Observables are deduplicated across rows since their identifiers
+// are derived from their values.
+type Builder struct {
+	opts *json.EncOpts
+
+	// Used as the indicator name when the row does not have a
+	// name_field - usually the artifact name.
+	source     string
+	name_field string
+
+	// The default client for rows without a ClientId column.
+	client_id string
+
+	now      string
+	identity string
+
+	objects []*ordereddict.Dict
+	ids     map[string]bool
+
+	// Number of rows which had no observables.
+	skipped int
+}
+
+func NewBuilder(source string, opts *json.EncOpts) *Builder {
+	self := &Builder{
+		opts:   opts,
+		source: source,
+		now:    formatTime(utils.GetTime().Now()),
+		ids:    make(map[string]bool),
+	}
+
+	self.identity = "identity--" + uuid.NewSHA1(
+		velociraptorNamespace, []byte("velociraptor")).String()
+	self.addObject(self.sdo("identity", self.identity).
+		Set("name", "Velociraptor").
+		Set("identity_class", "system"))
+
+	return self
+}
+
+// Name indicators using this field of the row (e.g. the rule name
+// of a detection artifact).
+func (self *Builder) SetNameField(field string) {
+	self.name_field = field
+}
+
+func (self *Builder) SetClientId(client_id string) {
+	self.client_id = client_id
+}
+
+func (self *Builder) Skipped() int {
+	return self.skipped
+}
+
+func (self *Builder) AddRow(row *ordereddict.Dict) error {
+	// Normalize the row through JSON so values are typed the same
+	// way as in the JSON exports.
+	serialized, err := json.MarshalWithOptions(row, self.opts)
+	if err != nil {
+		return err
+	}
+
+	normalized := ordereddict.NewDict()
+	err = json.Unmarshal(serialized, normalized)
+	if err != nil {
+		return err
+	}
+
+	found := newObservables()
+	found.walk("", normalized)
+	if found.empty() {
+		self.skipped++
+		return nil
+	}
+
+	refs, patterns := self.addObservables(found)
+
+	observed_data_id := "observed-data--" + uuid.New().String()
+	self.addObject(self.sdo("observed-data", observed_data_id).
+		Set("first_observed", self.now).
+		Set("last_observed", self.now).
+		Set("number_observed", 1).
+		Set("object_refs", refs))
+
+	name := self.source
+	if self.name_field != "" {
+		value, pres := normalized.Get(self.name_field)
+		if pres {
+			name = fmt.Sprintf("%v", value)
+		}
+	}
+
+	indicator_id := "indicator--" + uuid.New().String()
+	indicator := self.sdo("indicator", indicator_id).
+		Set("name", name).
+		Set("pattern", strings.Join(patterns, " OR ")).
+		Set("pattern_type", "stix").
+		Set("valid_from", self.now)
+	if self.source != "" && name != self.source {
+		indicator.Set("description", "Detected by "+self.source)
+	}
+	self.addObject(indicator)
+
+	self.addObject(self.sdo("relationship",
+		"relationship--"+uuid.New().String()).
+		Set("relationship_type", "based-on").
+		Set("source_ref", indicator_id).
+		Set("target_ref", observed_data_id))
+
+	client_identity := self.clientIdentity(normalized)
+	if client_identity != "" {
+		self.addObject(self.sdo("sighting", "sighting--"+uuid.New().String()).
+			Set("first_seen", self.now).
+			Set("last_seen", self.now).
+			Set("count", 1).
+			Set("sighting_of_ref", indicator_id).
+			Set("observed_data_refs", []string{observed_data_id}).
+			Set("where_sighted_refs", []string{client_identity}))
+	}
+
+	return nil
+}
+
+// Returns the complete bundle.
+func (self *Builder) Bundle() *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("type", "bundle").
+		Set("id", "bundle--"+uuid.New().String()).
+		Set("objects", self.objects)
+}
+
+// Add the Cyber-observable Objects and return their ids and the
+// pattern matching each of them.
+func (self *Builder) addObservables(found *observables) ([]string, []string) {
+	refs := []string{}
+	patterns := []string{}
+
+	add := func(obj *ordereddict.Dict, pattern string) {
+		id, _ := obj.GetString("id")
+		refs = append(refs, id)
+		patterns = append(patterns, "["+pattern+"]")
+		self.addObject(obj)
+	}
+
+	// All the hashes in a row describe the same file - name it after
+	// the first path in the row.
+	paths := found.paths
+	if len(found.hashes) > 0 && len(paths) == 0 {
+		paths = []string{""}
+	}
+
+	for idx, path := range paths {
+		file := ordereddict.NewDict()
+		comparisons := []string{}
+
+		if idx == 0 && len(found.hashes) > 0 {
+			hashes := ordereddict.NewDict()
+			for _, name := range []string{"MD5", "SHA-1", "SHA-256"} {
+				value, pres := found.hashes[name]
+				if pres {
+					hashes.Set(name, value)
+					comparisons = append(comparisons, fmt.Sprintf(
+						"file:hashes.'%s' = '%s'", name, value))
+				}
+			}
+			file.Set("hashes", hashes)
+		}
+
+		if path != "" {
+			dir, name := splitPath(path)
+			file.Set("name", name)
+
+			if dir != "" {
+				directory := self.sco("directory",
+					ordereddict.NewDict().Set("path", dir))
+				self.addObject(directory)
+
+				directory_id, _ := directory.GetString("id")
+				file.Set("parent_directory_ref", directory_id)
+			}
+
+			// Files with hashes are matched on the hashes alone.
+			if len(comparisons) == 0 {
+				comparisons = append(comparisons,
+					fmt.Sprintf("file:name = '%s'", escape(name)))
+				if dir != "" {
+					comparisons[0] += fmt.Sprintf(
+						" AND file:parent_directory_ref.path = '%s'",
+						escape(dir))
+				}
+			}
+		}
+
+		add(self.sco("file", file), strings.Join(comparisons, " OR "))
+	}
+
+	for _, item := range []struct {
+		sco_type string
+		values   []string
+	}{
+		{"ipv4-addr", found.ipv4},
+		{"ipv6-addr", found.ipv6},
+		{"domain-name", found.domains},
+		{"url", found.urls},
+	} {
+		for _, value := range item.values {
+			add(self.sco(item.sco_type, ordereddict.NewDict().Set("value", value)),
+				fmt.Sprintf("%s:value = '%s'", item.sco_type, escape(value)))
+		}
+	}
+
+	return refs, patterns
+}
+
+// Find or create the identity representing the client the row came
+// from.
+func (self *Builder) clientIdentity(row *ordereddict.Dict) string {
+	client_id, _ := row.GetString("ClientId")
+	if client_id == "" {
+		client_id = self.client_id
+	}
+	if client_id == "" {
+		return ""
+	}
+
+	name, _ := row.GetString("Fqdn")
+	if name == "" {
+		name = client_id
+	}
+
+	id := "identity--" + uuid.NewSHA1(
+		velociraptorNamespace, []byte("client:"+client_id)).String()
+	self.addObject(self.sdo("identity", id).
+		Set("name", name).
+		Set("identity_class", "system").
+		Set("x_velociraptor_client_id", client_id))
+
+	return id
+}
+
+func (self *Builder) addObject(obj *ordereddict.Dict) {
+	id, _ := obj.GetString("id")
+	if !self.ids[id] {
+		self.ids[id] = true
+		self.objects = append(self.objects, obj)
+	}
+}
+
+// The common properties of STIX Domain and Relationship Objects.
+func (self *Builder) sdo(object_type, id string) *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("type", object_type).
+		Set("spec_version", SPEC_VERSION).
+		Set("id", id).
+		Set("created", self.now).
+		Set("modified", self.now)
+
+	if self.identity != "" && id != self.identity {
+		result.Set("created_by_ref", self.identity)
+	}
+	return result
+}
+
+// Build a Cyber-observable Object. The id is derived from the
+// properties so the same observable always has the same id.
+func (self *Builder) sco(object_type string, properties *ordereddict.Dict) *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("type", object_type).
+		Set("spec_version", SPEC_VERSION).
+		Set("id", object_type+"--"+scoId(properties).String())
+
+	for _, k := range properties.Keys() {
+		v, _ := properties.Get(k)
+		result.Set(k, v)
+	}
+	return result
+}
+
+// The identifier is a UUIDv5 of the canonical JSON serialization of
+// the ID contributing properties. Only one hash contributes, chosen
+// in the order of preference MD5, SHA-1, SHA-256.
+func scoId(properties *ordereddict.Dict) uuid.UUID {
+	contributing := make(map[string]interface{})
+	for _, k := range properties.Keys() {
+		v, _ := properties.Get(k)
+
+		hashes, ok := v.(*ordereddict.Dict)
+		if ok && k == "hashes" {
+			name := hashes.Keys()[0]
+			value, _ := hashes.Get(name)
+			v = map[string]interface{}{name: value}
+		}
+		contributing[k] = v
+	}
+
+	// encoding/json sorts map keys and without HTML escaping gives
+	// the canonical serialization for these simple values.
+	buf := &bytes.Buffer{}
+	encoder := std_json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(contributing)
+
+	return uuid.NewSHA1(scoNamespace, bytes.TrimSpace(buf.Bytes()))
+}
+
+func escape(value string) string {
+	return patternEscaper.Replace(value)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timestampFormat)
+}
@@ -0,0 +1,162 @@
+package stix
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+func TestSplitPath(t *testing.T) {
+	for _, c := range [][]string{
+		{`C:\Windows\evil.exe`, `C:\Windows`, `evil.exe`},
+		{`C:\evil.exe`, `C:\`, `evil.exe`},
+		{`/usr/bin/ls`, `/usr/bin`, `ls`},
+		{`/ls`, `/`, `ls`},
+		{`ls`, ``, `ls`},
+	} {
+		dir, name := splitPath(c[0])
+		assert.Equal(t, c[1], dir, c[0])
+		assert.Equal(t, c[2], name, c[0])
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	closer := utils.MockTime(utils.NewMockClock(time.Unix(1700000000, 0)))
+	defer closer()
+
+	builder := NewBuilder("Windows.Detection.Test", nil)
+	builder.SetNameField("Rule")
+
+	rows := []*ordereddict.Dict{
+		ordereddict.NewDict().
+			Set("ClientId", "C.123").
+			Set("Fqdn", "host1.example.com").
+			Set("Rule", "Evil").
+			Set("Hash", ordereddict.NewDict().
+				Set("MD5", "D41D8CD98F00B204E9800998ECF8427E").
+				Set("SHA256", strings.Repeat("a", 64))).
+			Set("OSPath", `C:\Windows\evil.exe`).
+			Set("Remote", "8.8.8.8:443").
+			Set("Local", "127.0.0.1:5000"),
+		ordereddict.NewDict().
+			Set("ClientId", "C.123").
+			Set("Fqdn", "host1.example.com").
+			Set("Rule", "Beacon").
+			Set("Url", "https://evil.com/x?a='b'").
+			Set("Connections", []string{"8.8.8.8", "2001:db8::1"}),
+
+		// No observables in this row.
+		ordereddict.NewDict().
+			Set("Name", "evil.com").
+			Set("Size", 10),
+	}
+
+	for _, row := range rows {
+		assert.NoError(t, builder.AddRow(row))
+	}
+	assert.Equal(t, 1, builder.Skipped())
+
+	bundle := builder.Bundle()
+	objects_any, _ := bundle.Get("objects")
+	objects := objects_any.([]*ordereddict.Dict)
+
+	by_id := make(map[string]*ordereddict.Dict)
+	types := []string{}
+	for _, obj := range objects {
+		id, _ := obj.GetString("id")
+		object_type, _ := obj.GetString("type")
+		assert.True(t, strings.HasPrefix(id, object_type+"--"), id)
+		by_id[id] = obj
+		types = append(types, object_type)
+	}
+
+	// Observables and identities are deduplicated.
+	assert.Equal(t, []string{
+		"identity",
+		"directory", "file", "ipv4-addr", "observed-data",
+		"indicator", "relationship", "identity", "sighting",
+		"ipv6-addr", "domain-name", "url", "observed-data",
+		"indicator", "relationship", "sighting",
+	}, types)
+
+	// All references must resolve within the bundle.
+	for _, obj := range objects {
+		for _, k := range obj.Keys() {
+			v, _ := obj.Get(k)
+			refs := []string{}
+			switch t := v.(type) {
+			case string:
+				if strings.HasSuffix(k, "_ref") {
+					refs = append(refs, t)
+				}
+			case []string:
+				refs = t
+			}
+			for _, ref := range refs {
+				_, pres := by_id[ref]
+				assert.True(t, pres, "%v: %v", k, ref)
+			}
+		}
+	}
+
+	indicators := []string{}
+	for _, obj := range objects {
+		object_type, _ := obj.GetString("type")
+		if object_type == "indicator" {
+			name, _ := obj.GetString("name")
+			pattern, _ := obj.GetString("pattern")
+			indicators = append(indicators, name+": "+pattern)
+
+			created, _ := obj.GetString("created")
+			assert.Equal(t, "2023-11-14T22:13:20.000Z", created)
+		}
+	}
+
+	assert.Equal(t, []string{
+		"Evil: [file:hashes.'MD5' = 'd41d8cd98f00b204e9800998ecf8427e' OR " +
+			"file:hashes.'SHA-256' = '" + strings.Repeat("a", 64) + "'] OR " +
+			"[ipv4-addr:value = '8.8.8.8']",
+		"Beacon: [ipv4-addr:value = '8.8.8.8'] OR " +
+			"[ipv6-addr:value = '2001:db8::1'] OR " +
+			"[domain-name:value = 'evil.com'] OR " +
+			`[url:value = 'https://evil.com/x?a=\'b\'']`,
+	}, indicators)
+
+	// The file is named after the path in the same row.
+	directory := objects[1]
+	path, _ := directory.GetString("path")
+	assert.Equal(t, `C:\Windows`, path)
+
+	file := objects[2]
+	name, _ := file.GetString("name")
+	assert.Equal(t, "evil.exe", name)
+
+	directory_id, _ := directory.GetString("id")
+	parent, _ := file.GetString("parent_directory_ref")
+	assert.Equal(t, directory_id, parent)
+
+	client := objects[7]
+	client_name, _ := client.GetString("name")
+	assert.Equal(t, "host1.example.com", client_name)
+}
+
+func TestScoIdIsDeterministic(t *testing.T) {
+	a := scoId(ordereddict.NewDict().Set("value", "8.8.8.8"))
+	b := scoId(ordereddict.NewDict().Set("value", "8.8.8.8"))
+	c := scoId(ordereddict.NewDict().Set("value", "8.8.4.4"))
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+
+	// Only the preferred hash contributes to the id.
+	d := scoId(ordereddict.NewDict().Set("hashes", ordereddict.NewDict().
+		Set("MD5", "d41d8cd98f00b204e9800998ecf8427e")))
+	e := scoId(ordereddict.NewDict().Set("hashes", ordereddict.NewDict().
+		Set("MD5", "d41d8cd98f00b204e9800998ecf8427e").
+		Set("SHA-256", strings.Repeat("a", 64))))
+	assert.Equal(t, d, e)
+}
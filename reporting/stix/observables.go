@@ -0,0 +1,175 @@
+package stix
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+var (
+	hexRegex     = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	domainRegex  = regexp.MustCompile(`^(?i)([a-z0-9_]([a-z0-9_-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}\.?$`)
+	winPathRegex = regexp.MustCompile(`^([a-zA-Z]:\\|\\\\)`)
+
+	// Fields describing the client the row came from rather than
+	// what was found on it.
+	clientFields = map[string]bool{
+		"ClientId": true,
+		"Fqdn":     true,
+		"Hostname": true,
+	}
+)
+
+// The observables extracted from a single row.
+type observables struct {
+	// STIX hash name -> value
+	hashes  map[string]string
+	paths   []string
+	ipv4    []string
+	ipv6    []string
+	domains []string
+	urls    []string
+
+	seen map[string]bool
+}
+
+func newObservables() *observables {
+	return &observables{
+		hashes: make(map[string]string),
+		seen:   make(map[string]bool),
+	}
+}
+
+func (self *observables) empty() bool {
+	return len(self.hashes) == 0 && len(self.paths) == 0 &&
+		len(self.ipv4) == 0 && len(self.ipv6) == 0 &&
+		len(self.domains) == 0 && len(self.urls) == 0
+}
+
+func (self *observables) add(list *[]string, kind, value string) {
+	key := kind + ":" + value
+	if !self.seen[key] {
+		self.seen[key] = true
+		*list = append(*list, value)
+	}
+}
+
+// Walk the normalized row looking for observables. The name of the
+// enclosing field is used as a hint for values which are otherwise
+// ambiguous (e.g. a hex string is only a hash if the field is named
+// like one).
+func (self *observables) walk(field string, value interface{}) {
+	switch t := value.(type) {
+	case *ordereddict.Dict:
+		for _, k := range t.Keys() {
+			if field == "" && clientFields[k] {
+				continue
+			}
+			v, _ := t.Get(k)
+			self.walk(k, v)
+		}
+
+	case []interface{}:
+		for _, item := range t {
+			self.walk(field, item)
+		}
+
+	case string:
+		self.classify(field, strings.TrimSpace(t))
+	}
+}
+
+func (self *observables) classify(field, value string) {
+	if value == "" {
+		return
+	}
+	hint := strings.ToLower(field)
+
+	if hexRegex.MatchString(value) && hasAny(hint, "hash", "md5", "sha") {
+		switch len(value) {
+		case 32:
+			self.hashes["MD5"] = strings.ToLower(value)
+		case 40:
+			self.hashes["SHA-1"] = strings.ToLower(value)
+		case 64:
+			self.hashes["SHA-256"] = strings.ToLower(value)
+		}
+		return
+	}
+
+	lower := strings.ToLower(value)
+	if strings.HasPrefix(lower, "http://") ||
+		strings.HasPrefix(lower, "https://") {
+		parsed, err := url.Parse(value)
+		if err == nil && parsed.Hostname() != "" {
+			self.add(&self.urls, "url", value)
+			self.classifyHost(parsed.Hostname(), true)
+		}
+		return
+	}
+
+	if winPathRegex.MatchString(value) ||
+		(strings.HasPrefix(value, "/") &&
+			hasAny(hint, "path", "file", "exe", "image")) {
+		self.add(&self.paths, "path", value)
+		return
+	}
+
+	self.classifyHost(value,
+		hasAny(hint, "domain", "host", "fqdn", "dns", "query", "server"))
+}
+
+// Classify an IP address (optionally with a port) or a domain
+// name. Domain names are only recognized when is_host is set since
+// many other strings (e.g. file names) look like domains.
+func (self *observables) classifyHost(value string, is_host bool) {
+	host, _, err := net.SplitHostPort(value)
+	if err != nil {
+		host = value
+	}
+
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	if ip != nil {
+		if ip.IsLoopback() || ip.IsUnspecified() {
+			return
+		}
+		if ip.To4() != nil {
+			self.add(&self.ipv4, "ipv4", ip.String())
+		} else {
+			self.add(&self.ipv6, "ipv6", ip.String())
+		}
+		return
+	}
+
+	if is_host && domainRegex.MatchString(host) {
+		self.add(&self.domains, "domain",
+			strings.TrimSuffix(strings.ToLower(host), "."))
+	}
+}
+
+func hasAny(value string, substrings ...string) bool {
+	for _, s := range substrings {
+		if strings.Contains(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Split a Windows or Unix path into its directory and basename.
+func splitPath(path string) (string, string) {
+	idx := strings.LastIndexAny(path, `\/`)
+	if idx < 0 {
+		return "", path
+	}
+
+	dir := path[:idx]
+	// Keep the separator for root directories (C:\ or /)
+	if dir == "" || strings.HasSuffix(dir, ":") {
+		dir = path[:idx+1]
+	}
+	return dir, path[idx+1:]
+}
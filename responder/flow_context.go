@@ -67,6 +67,15 @@ type FlowContext struct {
 	last_stats_timestamp uint64
 	frequency_msec       uint64
 
+	// While the flow is preempted by a higher priority flow this
+	// is set and queries wait for it to be closed at their next row
+	// boundary.
+	resume chan bool
+
+	// The position in the client's queue while the flow waits to
+	// start or resume.
+	queue_position uint64
+
 	// We ensure to only send the final flow complete message
 	// once. This will trigger a System.Flow.Completion event on the
 	// server.
@@ -187,6 +196,20 @@ func makeCheckpoint(
 	return checkpoint.Name()
 }
 
+// A flow context for queries running outside the flow manager
+// (e.g. in the query sandbox). It only carries the flow id and
+// request options - the client tracks the real flow.
+func NewDetachedFlowContext(
+	flow_id string, req *crypto_proto.FlowRequest) *FlowContext {
+	if req == nil {
+		req = &crypto_proto.FlowRequest{}
+	}
+	return &FlowContext{
+		flow_id: flow_id,
+		req:     req,
+	}
+}
+
 // Is the flow complete? A flow is complete when all its queries are
 // either in the OK or GENERIC_ERROR state.
 func (self *FlowContext) IsFlowComplete() bool {
@@ -238,6 +261,73 @@ func (self *FlowContext) ChargeBytes(bytes uint64) error {
 	return nil
 }
 
+// Pause the flow's queries at their next row boundary to free the
+// client for a higher priority flow.
+func (self *FlowContext) Pause() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.resume == nil {
+		self.resume = make(chan bool)
+		self.addLogMessage("INFO",
+			"Collection preempted by a higher priority collection")
+	}
+}
+
+func (self *FlowContext) Resume() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.resume != nil {
+		close(self.resume)
+		self.resume = nil
+		self.addLogMessage("INFO", "Collection resumed")
+	}
+}
+
+func (self *FlowContext) SetQueuePosition(position uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.queue_position == 0 && position > 0 && len(self.responders) == 0 {
+		self.addLogMessage("INFO", fmt.Sprintf(
+			"Collection is queued at position %v", position))
+	}
+	self.queue_position = position
+}
+
+// Dry run collections count the files they would upload instead of
+// uploading them.
+func (self *FlowContext) DryRun() bool {
+	if self == nil || self.req == nil {
+		return false
+	}
+	return self.req.DryRun
+}
+
+// Called by the queries between rows. Blocks while the flow is
+// paused.
+func (self *FlowContext) WaitIfPaused(ctx context.Context) {
+	if self == nil {
+		return
+	}
+
+	self.mu.Lock()
+	resume := self.resume
+	flow_ctx := self.ctx
+	self.mu.Unlock()
+
+	if resume == nil || flow_ctx == nil {
+		return
+	}
+
+	select {
+	case <-resume:
+	case <-ctx.Done():
+	case <-flow_ctx.Done():
+	}
+}
+
 // Cancel all the responders and wait for them to complete. This may
 // be called multiple times, but there will be only one log message.
 func (self *FlowContext) Cancel() {
@@ -463,6 +553,8 @@ func (self *FlowContext) getStats() *crypto_proto.VeloMessage {
 		FlowStats: &crypto_proto.FlowStats{},
 	}
 
+	result.FlowStats.QueuePosition = self.queue_position
+
 	// Fill in all the responder's stats.
 	for _, r := range self.responders {
 		result.FlowStats.QueryStatus = append(result.FlowStats.QueryStatus,
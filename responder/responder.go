@@ -90,6 +90,16 @@ func (self *FlowResponder) FlowContext() *FlowContext {
 	return self.flow_context
 }
 
+// Account for a file which a dry run collection would have
+// uploaded.
+func (self *FlowResponder) EstimateUpload(size int64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.status.UploadedFiles++
+	self.status.ExpectedUploadedBytes += size
+}
+
 func (self *FlowResponder) NextUploadId() int64 {
 	return self.flow_context.NextUploadId()
 }
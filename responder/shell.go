@@ -0,0 +1,68 @@
+package responder
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+)
+
+// Interactive shells running on the client register their session
+// here so the executor can deliver input sent by the server.
+type shellSessions struct {
+	mu       sync.Mutex
+	sessions map[string]chan *crypto_proto.ShellInput
+}
+
+var (
+	shell_sessions = &shellSessions{
+		sessions: make(map[string]chan *crypto_proto.ShellInput),
+	}
+
+	ShellSessionNotFound = errors.New("No interactive shell for session")
+)
+
+// Register an interactive shell for the session. The returned closer
+// must be called when the shell exits.
+func RegisterShellSession(session_id string) (
+	<-chan *crypto_proto.ShellInput, func(), error) {
+	shell_sessions.mu.Lock()
+	defer shell_sessions.mu.Unlock()
+
+	_, pres := shell_sessions.sessions[session_id]
+	if pres {
+		return nil, nil, fmt.Errorf(
+			"Session %v already has an interactive shell", session_id)
+	}
+
+	input := make(chan *crypto_proto.ShellInput, 100)
+	shell_sessions.sessions[session_id] = input
+
+	return input, func() {
+		shell_sessions.mu.Lock()
+		defer shell_sessions.mu.Unlock()
+
+		delete(shell_sessions.sessions, session_id)
+	}, nil
+}
+
+// Deliver input to the shell running in the session. Input is never
+// blocked on - if the shell is not keeping up the input is dropped.
+func SendShellInput(session_id string, input *crypto_proto.ShellInput) error {
+	shell_sessions.mu.Lock()
+	defer shell_sessions.mu.Unlock()
+
+	input_chan, pres := shell_sessions.sessions[session_id]
+	if !pres {
+		return ShellSessionNotFound
+	}
+
+	select {
+	case input_chan <- input:
+		return nil
+	default:
+		return fmt.Errorf("Interactive shell for %v is not reading input",
+			session_id)
+	}
+}
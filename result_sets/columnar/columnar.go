@@ -0,0 +1,182 @@
+/*
+  Columnar result sets.
+
+  Normally result sets are stored as JSONL - one row per line (see
+  the simple package). This is cheap to write and to read in order,
+  but sorting or filtering a large table (for example the results of
+  a hunt over many clients) requires parsing every row.
+
+  Columnar result sets store the same rows column by column:
+
+  - The data file (.col) contains column chunks. Each chunk holds the
+    JSON encoded values of a single column for a group of rows, one
+    value per line.
+
+  - The index file (.col.index) is a JSONL file with one line per row
+    group, describing where each of its column chunks is stored.
+
+  Sorting and filtering only need to read and parse the chunks of a
+  single column. Rows are reassembled from their column values when
+  read so readers see the same rows as for JSONL result sets, except
+  that columns are ordered consistently within each row group.
+
+  Row groups are written once they are full or when the writer is
+  closed. Columnar result sets can not be updated in place so they
+  are only suitable for tables which are written in one go, like
+  notebook cell results.
+*/
+
+package columnar
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/Velocidex/json"
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+const (
+	DEFAULT_ROW_GROUP_SIZE = 10000
+)
+
+var (
+	ErrNotSupported = errors.New("Not supported for columnar result sets")
+	ErrCorrupted    = errors.New("Columnar result set is corrupted")
+)
+
+type columnChunk struct {
+	Name   string `json:"name"`
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+
+	// Rows in the group which do not have this column (sorted).
+	Missing []int64 `json:"missing,omitempty"`
+}
+
+func (self *columnChunk) isMissing(row int64) bool {
+	idx := sort.Search(len(self.Missing), func(i int) bool {
+		return self.Missing[i] >= row
+	})
+	return idx < len(self.Missing) && self.Missing[idx] == row
+}
+
+type rowGroup struct {
+	Rows    int64          `json:"rows"`
+	Columns []*columnChunk `json:"columns"`
+
+	// The row id of the first row in the group. This is not stored
+	// but calculated when the index is loaded.
+	start int64
+}
+
+// Build the JSON for the row from its column values.
+func (self *rowGroup) buildRow(values [][][]byte, row int64) []byte {
+	result := &bytes.Buffer{}
+	result.WriteByte('{')
+	for idx, column := range self.Columns {
+		if column.isMissing(row) {
+			continue
+		}
+
+		if result.Len() > 1 {
+			result.WriteByte(',')
+		}
+
+		name, _ := json.Marshal(column.Name)
+		result.Write(name)
+		result.WriteByte(':')
+		result.Write(values[idx][row])
+	}
+	result.WriteByte('}')
+	return result.Bytes()
+}
+
+func DataPath(log_path api.FSPathSpec) api.FSPathSpec {
+	return log_path.SetType(api.PATH_TYPE_FILESTORE_COLUMNAR)
+}
+
+func IndexPath(log_path api.FSPathSpec) api.FSPathSpec {
+	return log_path.SetType(api.PATH_TYPE_FILESTORE_COLUMNAR_INDEX)
+}
+
+// Returns true if the result set at log_path is stored in columnar
+// format.
+func IsColumnar(
+	file_store_factory api.FileStore, log_path api.FSPathSpec) bool {
+	_, err := file_store_factory.StatFile(IndexPath(log_path))
+	return err == nil
+}
+
+// Remove the columnar result set.
+func DeleteResultSet(
+	file_store_factory api.FileStore, log_path api.FSPathSpec) error {
+	return deleteFiles(file_store_factory,
+		DataPath(log_path), IndexPath(log_path))
+}
+
+func deleteFiles(
+	file_store_factory api.FileStore, filenames ...api.FSPathSpec) error {
+	for _, filename := range filenames {
+		err := file_store_factory.Delete(filename)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load all the complete row groups from the index.
+func readIndex(
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec) ([]*rowGroup, error) {
+	fd, err := file_store_factory.ReadFile(IndexPath(log_path))
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	result := []*rowGroup{}
+	start := int64(0)
+	reader := bufio.NewReader(fd)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// An incomplete line is still being written.
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		group := &rowGroup{}
+		err = json.Unmarshal(line, group)
+		if err != nil {
+			return nil, err
+		}
+
+		group.start = start
+		start += group.Rows
+		result = append(result, group)
+	}
+}
+
+// Decode a single column value the same way JSONL rows are decoded.
+func decodeValue(serialized []byte) (interface{}, bool) {
+	item := ordereddict.NewDict()
+	data := make([]byte, 0, len(serialized)+6)
+	data = append(data, `{"v":`...)
+	data = append(data, serialized...)
+	data = append(data, '}')
+
+	err := item.UnmarshalJSON(data)
+	if err != nil {
+		return nil, false
+	}
+	return item.Get("v")
+}
@@ -0,0 +1,186 @@
+package columnar_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/alecthomas/assert"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/result_sets/columnar"
+	"www.velocidex.com/golang/velociraptor/result_sets/simple"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const TOTAL_ROWS = 25000
+
+type ColumnarTestSuite struct {
+	test_utils.TestSuite
+
+	file_store    api.FileStore
+	jsonl_path    api.FSPathSpec
+	columnar_path api.FSPathSpec
+}
+
+func (self *ColumnarTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	self.file_store = file_store.GetFileStore(self.ConfigObj)
+	self.jsonl_path = path_specs.NewSafeFilestorePath("test", "jsonl")
+	self.columnar_path = path_specs.NewSafeFilestorePath("test", "columnar")
+
+	// Write the same rows in both formats.
+	jsonl_writer, err := result_sets.NewResultSetWriter(
+		self.file_store, self.jsonl_path,
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	columnar_writer, err := columnar.NewColumnarResultSetWriter(
+		self.file_store, self.columnar_path,
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	for i := 0; i < TOTAL_ROWS; i++ {
+		row := ordereddict.NewDict().
+			Set("Row", i).
+			Set("Name", fmt.Sprintf("Name %05d", (i*7919)%TOTAL_ROWS))
+
+		// Some rows have an extra column.
+		if i%3 == 0 {
+			row.Set("Extra", ordereddict.NewDict().Set("Line", "a\nb"))
+		}
+		jsonl_writer.Write(row)
+		columnar_writer.Write(row)
+	}
+
+	jsonl_writer.Close()
+	columnar_writer.Close()
+}
+
+func (self *ColumnarTestSuite) getJSON(
+	reader result_sets.ResultSetReader) []string {
+	result := []string{}
+	json_chan, err := reader.JSON(context.Background())
+	assert.NoError(self.T(), err)
+
+	for row := range json_chan {
+		result = append(result, string(row))
+	}
+	return result
+}
+
+func (self *ColumnarTestSuite) TestReader() {
+	reader, err := result_sets.NewResultSetReader(
+		self.file_store, self.columnar_path)
+	assert.NoError(self.T(), err)
+	defer reader.Close()
+
+	_, ok := reader.(*columnar.ColumnarResultSetReader)
+	assert.True(self.T(), ok)
+	assert.Equal(self.T(), int64(TOTAL_ROWS), reader.TotalRows())
+
+	expected, err := result_sets.NewResultSetReader(
+		self.file_store, self.jsonl_path)
+	assert.NoError(self.T(), err)
+	defer expected.Close()
+
+	// The columnar result set produces the same rows.
+	assert.Equal(self.T(), self.getJSON(expected), self.getJSON(reader))
+
+	// Seek across row groups.
+	reader, err = result_sets.NewResultSetReader(
+		self.file_store, self.columnar_path)
+	assert.NoError(self.T(), err)
+	defer reader.Close()
+
+	err = reader.SeekToRow(20001)
+	assert.NoError(self.T(), err)
+
+	rows := simple.GetAllResults(reader)
+	assert.Equal(self.T(), TOTAL_ROWS-20001, len(rows))
+
+	value, _ := rows[0].Get("Row")
+	assert.Equal(self.T(), uint64(20001), value)
+
+	_, pres := rows[0].Get("Extra")
+	assert.True(self.T(), pres)
+
+	_, pres = rows[1].Get("Extra")
+	assert.False(self.T(), pres)
+}
+
+func (self *ColumnarTestSuite) TestOptions() {
+	for _, options := range []result_sets.ResultSetOptions{
+		{SortColumn: "Name"},
+		{SortColumn: "Name", SortAsc: true},
+		{FilterColumn: "Name", FilterRegex: regexp.MustCompile("Name 0.+5$")},
+		{FilterColumn: "Extra", FilterRegex: regexp.MustCompile("a")},
+		{SortColumn: "Name", StartIdx: 100, EndIdx: 15000,
+			FilterColumn: "Name", FilterRegex: regexp.MustCompile("1")},
+	} {
+		expected, err := result_sets.NewResultSetReaderWithOptions(
+			self.Ctx, self.ConfigObj, self.file_store,
+			self.jsonl_path, options)
+		assert.NoError(self.T(), err)
+
+		reader, err := result_sets.NewResultSetReaderWithOptions(
+			self.Ctx, self.ConfigObj, self.file_store,
+			self.columnar_path, options)
+		assert.NoError(self.T(), err)
+
+		assert.Equal(self.T(), expected.TotalRows(), reader.TotalRows())
+
+		// Check a page from the middle of the table.
+		assert.NoError(self.T(), expected.SeekToRow(expected.TotalRows()/2))
+		assert.NoError(self.T(), reader.SeekToRow(reader.TotalRows()/2))
+
+		assert.Equal(self.T(), self.getJSON(expected), self.getJSON(reader))
+
+		expected.Close()
+		reader.Close()
+	}
+}
+
+func (self *ColumnarTestSuite) TestTruncate() {
+	// Replacing a JSONL result set with a columnar one.
+	writer, err := columnar.NewColumnarResultSetWriter(
+		self.file_store, self.jsonl_path,
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	writer.Write(ordereddict.NewDict().Set("Foo", "Bar"))
+	writer.Close()
+
+	reader, err := result_sets.NewResultSetReader(
+		self.file_store, self.jsonl_path)
+	assert.NoError(self.T(), err)
+	defer reader.Close()
+
+	assert.Equal(self.T(), []string{"{\"Foo\":\"Bar\"}\n"}, self.getJSON(reader))
+
+	// Columnar result sets can not be updated.
+	writer, err = columnar.NewColumnarResultSetWriter(
+		self.file_store, self.jsonl_path,
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.AppendMode)
+	assert.NoError(self.T(), err)
+	defer writer.Close()
+
+	err = writer.Update(0, ordereddict.NewDict())
+	assert.Error(self.T(), err)
+}
+
+func TestColumnarResultSets(t *testing.T) {
+	suite.Run(t, &ColumnarTestSuite{})
+}
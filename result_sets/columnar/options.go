@@ -0,0 +1,241 @@
+package columnar
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+// Filtering and sorting a columnar result set only needs to read the
+// relevant column so, unlike JSONL result sets, the transformed
+// table is not cached - the reader just produces the selected rows
+// in order.
+func NewResultSetReaderWithOptions(
+	ctx context.Context,
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec,
+	options result_sets.ResultSetOptions) (result_sets.ResultSetReader, error) {
+
+	reader, err := NewColumnarResultSetReader(file_store_factory, log_path)
+	if err != nil {
+		return nil, err
+	}
+
+	selection, err := reader.SelectRange(options.StartIdx, options.EndIdx)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	if options.FilterColumn != "" && options.FilterRegex != nil {
+		selection, err = reader.Filter(ctx, selection,
+			options.FilterColumn, options.FilterRegex)
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+	}
+
+	if options.SortColumn != "" {
+		selection, err = reader.Sort(ctx, selection,
+			options.SortColumn, options.SortAsc)
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+	}
+
+	reader.SetSelection(selection)
+	return reader, nil
+}
+
+// Only produce the selected rows in the given order. A nil selection
+// means all rows.
+func (self *ColumnarResultSetReader) SetSelection(selection []int64) {
+	self.selection = selection
+	self.idx = 0
+}
+
+// The names of all the columns in the result set.
+func (self *ColumnarResultSetReader) Columns() []string {
+	result := []string{}
+	seen := make(map[string]bool)
+	for _, group := range self.groups {
+		for _, column := range group.Columns {
+			if !seen[column.Name] {
+				seen[column.Name] = true
+				result = append(result, column.Name)
+			}
+		}
+	}
+	return result
+}
+
+// The range of rows to consider. A nil selection means all rows.
+func (self *ColumnarResultSetReader) SelectRange(
+	start_idx, end_idx uint64) ([]int64, error) {
+	if end_idx < start_idx {
+		return nil, errors.New("Invalid range for reader")
+	}
+
+	if start_idx == 0 && end_idx == 0 {
+		return nil, nil
+	}
+
+	if end_idx > uint64(self.total_rows) {
+		end_idx = uint64(self.total_rows)
+	}
+
+	result := []int64{}
+	for i := int64(start_idx); i < int64(end_idx); i++ {
+		result = append(result, i)
+	}
+	return result, nil
+}
+
+type columnValue struct {
+	value   interface{}
+	present bool
+}
+
+// Decode the values of a single column for the selected rows.
+func (self *ColumnarResultSetReader) readColumnValues(
+	ctx context.Context,
+	selection []int64, name string) ([]columnValue, error) {
+
+	if selection == nil {
+		selection = make([]int64, 0, self.total_rows)
+		for i := int64(0); i < self.total_rows; i++ {
+			selection = append(selection, i)
+		}
+	}
+
+	result := make([]columnValue, 0, len(selection))
+
+	// Cache the column chunk of the current group since rows are
+	// usually selected in order.
+	var current_group *rowGroup
+	var current_column *columnChunk
+	var values [][]byte
+
+	for _, id := range selection {
+		group_idx := self.findGroup(id)
+		if group_idx >= len(self.groups) {
+			return nil, ErrCorrupted
+		}
+
+		group := self.groups[group_idx]
+		if group != current_group {
+			err := ctx.Err()
+			if err != nil {
+				return nil, err
+			}
+
+			current_group = group
+			current_column = nil
+			values = nil
+
+			for _, column := range group.Columns {
+				if column.Name == name {
+					current_column = column
+					break
+				}
+			}
+
+			if current_column != nil {
+				values, err = self.readColumn(current_column, group.Rows)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		row := id - group.start
+		if current_column == nil || current_column.isMissing(row) {
+			result = append(result, columnValue{})
+			continue
+		}
+
+		value, pres := decodeValue(values[row])
+		result = append(result, columnValue{value: value, present: pres})
+	}
+
+	return result, nil
+}
+
+// Keep rows where the column matches the regex.
+func (self *ColumnarResultSetReader) Filter(
+	ctx context.Context, selection []int64,
+	column string, regex *regexp.Regexp) ([]int64, error) {
+
+	values, err := self.readColumnValues(ctx, selection, column)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []int64{}
+	for idx, value := range values {
+		if !value.present {
+			continue
+		}
+
+		if regex.FindStringIndex(utils.ToString(value.value)) != nil {
+			if selection == nil {
+				result = append(result, int64(idx))
+			} else {
+				result = append(result, selection[idx])
+			}
+		}
+	}
+	return result, nil
+}
+
+// Sort the rows by the column. This uses the same comparison as
+// sorting JSONL result sets.
+func (self *ColumnarResultSetReader) Sort(
+	ctx context.Context, selection []int64,
+	column string, desc bool) ([]int64, error) {
+
+	values, err := self.readColumnValues(ctx, selection, column)
+	if err != nil {
+		return nil, err
+	}
+
+	type sortItem struct {
+		id    int64
+		value columnValue
+	}
+
+	items := make([]sortItem, 0, len(values))
+	for idx, value := range values {
+		id := int64(idx)
+		if selection != nil {
+			id = selection[idx]
+		}
+		items = append(items, sortItem{id: id, value: value})
+	}
+
+	scope := vql_subsystem.MakeScope()
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].value.present || !items[j].value.present {
+			return false
+		}
+
+		if desc {
+			return !scope.Lt(items[i].value.value, items[j].value.value)
+		}
+		return scope.Lt(items[i].value.value, items[j].value.value)
+	})
+
+	result := make([]int64, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.id)
+	}
+	return result, nil
+}
@@ -0,0 +1,246 @@
+package columnar
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+const (
+	// Number of rows assembled at once.
+	BATCH_SIZE = 1000
+)
+
+type ColumnarResultSetReader struct {
+	fd         api.FileReader
+	groups     []*rowGroup
+	total_rows int64
+
+	// If set, only these rows are produced in this order (e.g. after
+	// filtering or sorting).
+	selection []int64
+
+	// The next row to produce (an index into the selection if set).
+	idx int64
+
+	// The last loaded row group.
+	cached_group  *rowGroup
+	cached_values [][][]byte
+}
+
+func (self *ColumnarResultSetReader) TotalRows() int64 {
+	if self.selection != nil {
+		return int64(len(self.selection))
+	}
+	return self.total_rows
+}
+
+func (self *ColumnarResultSetReader) SeekToRow(start int64) error {
+	if start < 0 || (start > 0 && start >= self.TotalRows()) {
+		return io.EOF
+	}
+
+	self.idx = start
+	return nil
+}
+
+func (self *ColumnarResultSetReader) rowId(idx int64) int64 {
+	if self.selection != nil {
+		return self.selection[idx]
+	}
+	return idx
+}
+
+// Find the row group containing the row.
+func (self *ColumnarResultSetReader) findGroup(row int64) int {
+	return sort.Search(len(self.groups), func(i int) bool {
+		return self.groups[i].start+self.groups[i].Rows > row
+	})
+}
+
+// Read the column chunk and split it into the serialized values.
+func (self *ColumnarResultSetReader) readColumn(
+	column *columnChunk, rows int64) ([][]byte, error) {
+	data := make([]byte, column.Length)
+	_, err := self.fd.Seek(column.Offset, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.ReadFull(self.fd, data)
+	if err != nil {
+		return nil, err
+	}
+
+	values := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	if int64(len(values)) != rows {
+		return nil, ErrCorrupted
+	}
+	return values, nil
+}
+
+// Read all the columns of the row group.
+func (self *ColumnarResultSetReader) loadGroup(
+	group *rowGroup) ([][][]byte, error) {
+	if self.cached_group == group {
+		return self.cached_values, nil
+	}
+
+	result := make([][][]byte, 0, len(group.Columns))
+	for _, column := range group.Columns {
+		values, err := self.readColumn(column, group.Rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, values)
+	}
+
+	self.cached_group = group
+	self.cached_values = result
+	return result, nil
+}
+
+// Assemble the JSON for the rows in the order given. Each row group
+// is only loaded once, even when the rows are not in order.
+func (self *ColumnarResultSetReader) getRows(ids []int64) ([][]byte, error) {
+	result := make([][]byte, len(ids))
+
+	by_group := make(map[int][]int)
+	groups := []int{}
+	for pos, id := range ids {
+		group_idx := self.findGroup(id)
+		if group_idx >= len(self.groups) {
+			return nil, ErrCorrupted
+		}
+
+		_, pres := by_group[group_idx]
+		if !pres {
+			groups = append(groups, group_idx)
+		}
+		by_group[group_idx] = append(by_group[group_idx], pos)
+	}
+
+	for _, group_idx := range groups {
+		group := self.groups[group_idx]
+		values, err := self.loadGroup(group)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pos := range by_group[group_idx] {
+			result[pos] = group.buildRow(values, ids[pos]-group.start)
+		}
+	}
+
+	return result, nil
+}
+
+// Produce the serialized rows from the current position until emit
+// returns false.
+func (self *ColumnarResultSetReader) generate(emit func(row []byte) bool) {
+	total_rows := self.TotalRows()
+
+	for self.idx < total_rows {
+		end := self.idx + BATCH_SIZE
+		if end > total_rows {
+			end = total_rows
+		}
+
+		ids := make([]int64, 0, end-self.idx)
+		for i := self.idx; i < end; i++ {
+			ids = append(ids, self.rowId(i))
+		}
+
+		rows, err := self.getRows(ids)
+		if err != nil {
+			return
+		}
+
+		for _, row := range rows {
+			if !emit(row) {
+				return
+			}
+			self.idx++
+		}
+	}
+}
+
+func (self *ColumnarResultSetReader) Rows(
+	ctx context.Context) <-chan *ordereddict.Dict {
+	output := make(chan *ordereddict.Dict)
+
+	go func() {
+		defer close(output)
+
+		self.generate(func(row_data []byte) bool {
+			item := ordereddict.NewDict()
+			err := item.UnmarshalJSON(row_data)
+			if err != nil {
+				return true
+			}
+
+			select {
+			case <-ctx.Done():
+				return false
+			case output <- item:
+				return true
+			}
+		})
+	}()
+
+	return output
+}
+
+func (self *ColumnarResultSetReader) JSON(
+	ctx context.Context) (<-chan []byte, error) {
+	output := make(chan []byte)
+
+	go func() {
+		defer close(output)
+
+		self.generate(func(row_data []byte) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case output <- append(row_data, '\n'):
+				return true
+			}
+		})
+	}()
+
+	return output, nil
+}
+
+func (self *ColumnarResultSetReader) Close() {
+	self.fd.Close()
+}
+
+func NewColumnarResultSetReader(
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec) (*ColumnarResultSetReader, error) {
+	groups, err := readIndex(file_store_factory, log_path)
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := file_store_factory.ReadFile(DataPath(log_path))
+	if err != nil {
+		return nil, err
+	}
+
+	total_rows := int64(0)
+	if len(groups) > 0 {
+		last := groups[len(groups)-1]
+		total_rows = last.start + last.Rows
+	}
+
+	return &ColumnarResultSetReader{
+		fd:         fd,
+		groups:     groups,
+		total_rows: total_rows,
+	}, nil
+}
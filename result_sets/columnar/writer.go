@@ -0,0 +1,263 @@
+package columnar
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/Velocidex/json"
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	vjson "www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+)
+
+var (
+	null_value = []byte("null")
+)
+
+// The values of a column in the row group being built.
+type columnBuffer struct {
+	name    string
+	values  bytes.Buffer
+	count   int64
+	missing []int64
+}
+
+func (self *columnBuffer) add(serialized []byte) {
+	// Values are stored one per line.
+	if bytes.IndexByte(serialized, '\n') >= 0 {
+		compacted := &bytes.Buffer{}
+		err := json.Compact(compacted, serialized)
+		if err != nil {
+			serialized = null_value
+		} else {
+			serialized = compacted.Bytes()
+		}
+	}
+
+	self.values.Write(serialized)
+	self.values.WriteByte('\n')
+	self.count++
+}
+
+type ColumnarResultSetWriter struct {
+	mu       sync.Mutex
+	opts     *json.EncOpts
+	fd       api.FileWriter
+	index_fd api.FileWriter
+
+	row_group_size int64
+
+	// The row group currently being built.
+	columns    []*columnBuffer
+	column_idx map[string]*columnBuffer
+	rows       int64
+
+	sync bool
+}
+
+func (self *ColumnarResultSetWriter) SetStartRow(i int64) {}
+
+func (self *ColumnarResultSetWriter) SetSync() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.sync = true
+}
+
+func (self *ColumnarResultSetWriter) getColumn(name string) *columnBuffer {
+	column, pres := self.column_idx[name]
+	if pres {
+		return column
+	}
+
+	// The column is missing from all the previous rows in the group.
+	column = &columnBuffer{name: name}
+	for i := int64(0); i < self.rows; i++ {
+		column.missing = append(column.missing, i)
+		column.add(null_value)
+	}
+
+	self.columns = append(self.columns, column)
+	self.column_idx[name] = column
+	return column
+}
+
+func (self *ColumnarResultSetWriter) Write(row *ordereddict.Dict) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self._Write(row)
+}
+
+func (self *ColumnarResultSetWriter) _Write(row *ordereddict.Dict) {
+	for _, key := range row.Keys() {
+		column := self.getColumn(key)
+		if column.count > self.rows {
+			continue
+		}
+
+		value, _ := row.Get(key)
+		serialized, err := vjson.MarshalWithOptions(value, self.opts)
+		if err != nil {
+			serialized = null_value
+		}
+		column.add(serialized)
+	}
+
+	for _, column := range self.columns {
+		if column.count == self.rows {
+			column.missing = append(column.missing, self.rows)
+			column.add(null_value)
+		}
+	}
+
+	self.rows++
+	if self.rows >= self.row_group_size {
+		self._Flush()
+	}
+}
+
+// The JSONL has to be split into columns so this is not as cheap as
+// for JSONL result sets.
+func (self *ColumnarResultSetWriter) WriteJSONL(
+	serialized []byte, total_rows uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	reader := bufio.NewReader(bytes.NewReader(serialized))
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 1 {
+			item := ordereddict.NewDict()
+			if item.UnmarshalJSON(line) == nil {
+				self._Write(item)
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (self *ColumnarResultSetWriter) Update(
+	index uint64, row *ordereddict.Dict) error {
+	return ErrNotSupported
+}
+
+// Small row groups defeat the purpose of columnar storage so only
+// complete row groups are written. The remaining rows are written
+// when the writer is closed.
+func (self *ColumnarResultSetWriter) Flush() {}
+
+func (self *ColumnarResultSetWriter) _Flush() {
+	if self.rows == 0 {
+		return
+	}
+
+	offset, err := self.fd.Size()
+	if err != nil {
+		return
+	}
+
+	group := &rowGroup{Rows: self.rows}
+	for _, column := range self.columns {
+		data := column.values.Bytes()
+		_, err := self.fd.Write(data)
+		if err != nil {
+			return
+		}
+
+		group.Columns = append(group.Columns, &columnChunk{
+			Name:    column.name,
+			Offset:  offset,
+			Length:  int64(len(data)),
+			Missing: column.missing,
+		})
+		offset += int64(len(data))
+	}
+
+	// The index is written after the data so readers never see
+	// incomplete row groups.
+	serialized, err := vjson.Marshal(group)
+	if err != nil {
+		return
+	}
+	serialized = append(serialized, '\n')
+	_, _ = self.index_fd.Write(serialized)
+
+	self.rows = 0
+	self.columns = nil
+	self.column_idx = make(map[string]*columnBuffer)
+}
+
+func (self *ColumnarResultSetWriter) Close() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self._Flush()
+	self.fd.Close()
+	self.index_fd.Close()
+}
+
+// Creates a writer for a columnar result set at log_path. The
+// completion is called when the index is closed.
+func NewColumnarResultSetWriter(
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec,
+	opts *json.EncOpts,
+	completion func(),
+	truncate result_sets.WriteMode) (*ColumnarResultSetWriter, error) {
+
+	// A JSONL result set at the same path would take precedence.
+	if truncate {
+		err := deleteFiles(file_store_factory, log_path,
+			log_path.SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX))
+		if err != nil {
+			return nil, err
+		}
+
+	} else {
+		_, err := file_store_factory.StatFile(log_path)
+		if err == nil {
+			return nil, errors.New(
+				"Can not append columnar rows to a JSONL result set")
+		}
+	}
+
+	fd, err := file_store_factory.WriteFile(DataPath(log_path))
+	if err != nil {
+		return nil, err
+	}
+
+	index_fd, err := file_store_factory.WriteFileWithCompletion(
+		IndexPath(log_path), completion)
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+
+	if truncate {
+		err = fd.Truncate()
+		if err == nil {
+			err = index_fd.Truncate()
+		}
+
+		if err != nil {
+			fd.Close()
+			index_fd.Close()
+			return nil, err
+		}
+	}
+
+	return &ColumnarResultSetWriter{
+		opts:           opts,
+		fd:             fd,
+		index_fd:       index_fd,
+		row_group_size: DEFAULT_ROW_GROUP_SIZE,
+		column_idx:     make(map[string]*columnBuffer),
+	}, nil
+}
@@ -0,0 +1,295 @@
+/*
+  Result set indexes.
+
+  Sorting or filtering a JSONL result set in the GUI requires reading
+  and parsing every row (the transformed result set is then cached
+  by the simple result set factory). For large result sets, like the
+  results of a big hunt, this can take minutes.
+
+  Large result sets are therefore indexed in the background:
+
+  - A columnar copy of the result set (see the columnar package) so
+    filtering only needs to read a single column and rows can be
+    produced in any order.
+
+  - A sorted view for each column, stored as the row ids in
+    ascending order of the column's values.
+
+  Indexes are stored in the "indexes" directory below the result set
+  and are only used while they match the result set. Indexes are
+  rebuilt when a stale index is needed again.
+*/
+
+package indexes
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/result_sets/columnar"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	ErrNoIndex = errors.New("Result set is not indexed")
+)
+
+// Describes the state of the result set when the index was built.
+type Metadata struct {
+	Rows    int64    `json:"rows"`
+	Size    int64    `json:"size"`
+	Columns []string `json:"columns"`
+}
+
+func IndexDirectory(log_path api.FSPathSpec) api.FSPathSpec {
+	return log_path.AddUnsafeChild("indexes")
+}
+
+func columnsPath(log_path api.FSPathSpec) api.FSPathSpec {
+	return IndexDirectory(log_path).AddChild("columns")
+}
+
+func sortedPath(log_path api.FSPathSpec, column string) api.FSPathSpec {
+	return IndexDirectory(log_path).AddUnsafeChild("sorted", column).
+		SetType(api.PATH_TYPE_FILESTORE_SPARSE_IDX)
+}
+
+func metadataPath(log_path api.FSPathSpec) api.FSPathSpec {
+	return IndexDirectory(log_path).AddChild("metadata").
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+}
+
+// The current size of the result set.
+func getResultSetSize(
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec) (rows int64, size int64, err error) {
+	stat, err := file_store_factory.StatFile(log_path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	idx_stat, err := file_store_factory.StatFile(
+		log_path.SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return idx_stat.Size() / 8, stat.Size(), nil
+}
+
+// Returns the metadata of the index if it is up to date.
+func GetMetadata(
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec) (*Metadata, error) {
+	fd, err := file_store_factory.ReadFile(metadataPath(log_path))
+	if err != nil {
+		return nil, ErrNoIndex
+	}
+	defer fd.Close()
+
+	data, err := io.ReadAll(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Metadata{}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, ErrNoIndex
+	}
+
+	rows, size, err := getResultSetSize(file_store_factory, log_path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The result set changed since the index was built.
+	if rows != result.Rows || size != result.Size {
+		return nil, ErrNoIndex
+	}
+
+	return result, nil
+}
+
+// Build the index for the result set at log_path.
+func BuildIndex(
+	ctx context.Context,
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec) (*Metadata, error) {
+
+	// Invalidate the old index while we rebuild it.
+	err := file_store_factory.Delete(metadataPath(log_path))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	rows, size, err := getResultSetSize(file_store_factory, log_path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := result_sets.NewResultSetReader(file_store_factory, log_path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	writer, err := columnar.NewColumnarResultSetWriter(
+		file_store_factory, columnsPath(log_path), nil,
+		utils.SyncCompleter, result_sets.TruncateMode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only index the rows that were present when we started.
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	count := int64(0)
+	for row := range reader.Rows(sub_ctx) {
+		if count >= rows {
+			break
+		}
+		writer.Write(row)
+		count++
+	}
+	writer.Close()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	columns, err := columnar.NewColumnarResultSetReader(
+		file_store_factory, columnsPath(log_path))
+	if err != nil {
+		return nil, err
+	}
+	defer columns.Close()
+
+	result := &Metadata{
+		Rows:    columns.TotalRows(),
+		Size:    size,
+		Columns: columns.Columns(),
+	}
+
+	// The result set is still being written - try again later.
+	if result.Rows != rows {
+		return nil, ErrNoIndex
+	}
+
+	for _, column := range result.Columns {
+		order, err := columns.Sort(ctx, nil, column, false)
+		if err != nil {
+			return nil, err
+		}
+
+		err = writeSorted(file_store_factory, sortedPath(log_path, column), order)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Writing the metadata makes the index visible.
+	serialized, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := file_store_factory.WriteFile(metadataPath(log_path))
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	err = fd.Truncate()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fd.Write(serialized)
+	return result, err
+}
+
+func writeSorted(
+	file_store_factory api.FileStore,
+	filename api.FSPathSpec, order []int64) error {
+	fd, err := file_store_factory.WriteFile(filename)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	err = fd.Truncate()
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	for _, id := range order {
+		_ = binary.Write(buf, binary.LittleEndian, uint64(id))
+	}
+	_, err = fd.Write(buf.Bytes())
+	return err
+}
+
+func readSorted(
+	file_store_factory api.FileStore, filename api.FSPathSpec) ([]int64, error) {
+	fd, err := file_store_factory.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	data, err := io.ReadAll(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]int64, 0, len(data)/8)
+	for i := 0; i+8 <= len(data); i += 8 {
+		result = append(result, int64(binary.LittleEndian.Uint64(data[i:])))
+	}
+	return result, nil
+}
+
+// Schedules result sets for indexing in the background. This is
+// installed by the result set indexer service.
+type Scheduler func(file_store_factory api.FileStore, log_path api.FSPathSpec)
+
+var (
+	mu         sync.Mutex
+	schedulers = make(map[string]Scheduler)
+)
+
+func RegisterScheduler(org_id string, scheduler Scheduler) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if scheduler == nil {
+		delete(schedulers, org_id)
+		return
+	}
+	schedulers[org_id] = scheduler
+}
+
+// Request the result set to be indexed. Does nothing if the indexer
+// service is not running for the org.
+func Schedule(
+	org_id string,
+	file_store_factory api.FileStore, log_path api.FSPathSpec) {
+	mu.Lock()
+	scheduler, pres := schedulers[org_id]
+	mu.Unlock()
+
+	if pres {
+		scheduler(file_store_factory, log_path)
+	}
+}
@@ -0,0 +1,133 @@
+package indexes_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/alecthomas/assert"
+	"github.com/stretchr/testify/suite"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/result_sets/indexes"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const TOTAL_ROWS = 20000
+
+var test_options = []result_sets.ResultSetOptions{
+	{SortColumn: "Name"},
+	{SortColumn: "Name", SortAsc: true},
+	{SortColumn: "Row", SortAsc: true},
+	{FilterColumn: "Name", FilterRegex: regexp.MustCompile("Name 0.+5$")},
+	{FilterColumn: "Extra", FilterRegex: regexp.MustCompile("a")},
+	{SortColumn: "Name", StartIdx: 100, EndIdx: 15000,
+		FilterColumn: "Name", FilterRegex: regexp.MustCompile("1")},
+}
+
+type IndexTestSuite struct {
+	test_utils.TestSuite
+
+	file_store api.FileStore
+	log_path   api.FSPathSpec
+}
+
+func (self *IndexTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	// Do not index the result set in the background.
+	if self.ConfigObj.Defaults == nil {
+		self.ConfigObj.Defaults = &config_proto.Defaults{}
+	}
+	self.ConfigObj.Defaults.ResultSetIndexMinRows = -1
+
+	self.file_store = file_store.GetFileStore(self.ConfigObj)
+	self.log_path = path_specs.NewSafeFilestorePath("test", "large")
+
+	writer, err := result_sets.NewResultSetWriter(
+		self.file_store, self.log_path,
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	for i := 0; i < TOTAL_ROWS; i++ {
+		row := ordereddict.NewDict().
+			Set("Row", i).
+			Set("Name", fmt.Sprintf("Name %05d", (i*7919)%TOTAL_ROWS))
+
+		if i%3 == 0 {
+			row.Set("Extra", "a")
+		}
+		writer.Write(row)
+	}
+	writer.Close()
+}
+
+func (self *IndexTestSuite) getRows(
+	reader result_sets.ResultSetReader, start int64) []string {
+	assert.NoError(self.T(), reader.SeekToRow(start))
+
+	result := []string{}
+	for row := range reader.Rows(context.Background()) {
+		result = append(result, json.MustMarshalString(row))
+	}
+	return result
+}
+
+func (self *IndexTestSuite) TestIndexedReader() {
+	// Scan the result set without an index.
+	expected := [][]string{}
+	for _, options := range test_options {
+		reader, err := result_sets.NewResultSetReaderWithOptions(
+			self.Ctx, self.ConfigObj, self.file_store,
+			self.log_path, options)
+		assert.NoError(self.T(), err)
+
+		expected = append(expected, self.getRows(reader, reader.TotalRows()/2))
+		reader.Close()
+	}
+
+	_, err := indexes.NewIndexedReader(self.Ctx, self.file_store,
+		self.log_path, test_options[0])
+	assert.Equal(self.T(), indexes.ErrNoIndex, err)
+
+	metadata, err := indexes.BuildIndex(self.Ctx, self.file_store, self.log_path)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(TOTAL_ROWS), metadata.Rows)
+	assert.Equal(self.T(), []string{"Row", "Name", "Extra"}, metadata.Columns)
+
+	// The index produces the same rows.
+	for idx, options := range test_options {
+		reader, err := indexes.NewIndexedReader(self.Ctx, self.file_store,
+			self.log_path, options)
+		assert.NoError(self.T(), err)
+
+		assert.Equal(self.T(), expected[idx],
+			self.getRows(reader, reader.TotalRows()/2))
+		reader.Close()
+	}
+
+	// Appending to the result set invalidates the index.
+	writer, err := result_sets.NewResultSetWriter(
+		self.file_store, self.log_path,
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.AppendMode)
+	assert.NoError(self.T(), err)
+	writer.Write(ordereddict.NewDict().Set("Row", TOTAL_ROWS))
+	writer.Close()
+
+	_, err = indexes.NewIndexedReader(self.Ctx, self.file_store,
+		self.log_path, test_options[0])
+	assert.Equal(self.T(), indexes.ErrNoIndex, err)
+}
+
+func TestIndexes(t *testing.T) {
+	suite.Run(t, &IndexTestSuite{})
+}
@@ -0,0 +1,95 @@
+package indexes
+
+import (
+	"context"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/result_sets/columnar"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Returns a reader applying the options using the index of the result
+// set. Returns ErrNoIndex if the result set does not have an up to
+// date index.
+func NewIndexedReader(
+	ctx context.Context,
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec,
+	options result_sets.ResultSetOptions) (result_sets.ResultSetReader, error) {
+
+	metadata, err := GetMetadata(file_store_factory, log_path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := columnar.NewColumnarResultSetReader(
+		file_store_factory, columnsPath(log_path))
+	if err != nil {
+		return nil, err
+	}
+
+	selection, err := reader.SelectRange(options.StartIdx, options.EndIdx)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	if options.FilterColumn != "" && options.FilterRegex != nil {
+		selection, err = reader.Filter(ctx, selection,
+			options.FilterColumn, options.FilterRegex)
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+	}
+
+	// Columns which are not in the index are not present in any
+	// row so sorting on them does not change the order.
+	if options.SortColumn != "" &&
+		utils.InString(metadata.Columns, options.SortColumn) {
+		order, err := readSorted(file_store_factory,
+			sortedPath(log_path, options.SortColumn))
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+
+		// The order of the JSONL sorter is reversed when SortAsc is
+		// set.
+		if options.SortAsc {
+			reverse(order)
+		}
+
+		selection = applyOrder(order, selection, metadata.Rows)
+	}
+
+	reader.SetSelection(selection)
+	return reader, nil
+}
+
+// Restrict the sorted order to the rows in the selection.
+func applyOrder(order, selection []int64, total_rows int64) []int64 {
+	if selection == nil {
+		return order
+	}
+
+	selected := make([]bool, total_rows)
+	for _, id := range selection {
+		selected[id] = true
+	}
+
+	result := make([]int64, 0, len(selection))
+	for _, id := range order {
+		if id < total_rows && selected[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func reverse(order []int64) {
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 	vjson "www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/result_sets/columnar"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
@@ -466,6 +467,12 @@ func (self ResultSetFactory) NewResultSetReader(
 
 	fd, err := file_store_factory.ReadFile(log_path)
 	if err == io.EOF || errors.Is(err, os.ErrNotExist) {
+		// The result set may be stored in columnar format instead.
+		if columnar.IsColumnar(file_store_factory, log_path) {
+			return columnar.NewColumnarResultSetReader(
+				file_store_factory, log_path)
+		}
+
 		fd = &NullReader{
 			Reader:    bytes.NewReader([]byte{}),
 			pathSpec_: log_path,
@@ -2,6 +2,7 @@ package simple
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,8 @@ import (
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/result_sets/columnar"
+	"www.velocidex.com/golang/velociraptor/result_sets/indexes"
 	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vql/sorter"
@@ -22,6 +25,27 @@ func (self ResultSetFactory) NewResultSetReaderWithOptions(
 	log_path api.FSPathSpec,
 	options result_sets.ResultSetOptions) (result_sets.ResultSetReader, error) {
 
+	// Columnar result sets can be filtered and sorted directly.
+	_, err := file_store_factory.StatFile(log_path)
+	if err != nil && columnar.IsColumnar(file_store_factory, log_path) {
+		return columnar.NewResultSetReaderWithOptions(
+			ctx, file_store_factory, log_path, options)
+	}
+
+	// Large result sets are indexed in the background.
+	if options.SortColumn != "" ||
+		(options.FilterColumn != "" && options.FilterRegex != nil) {
+		reader, err := indexes.NewIndexedReader(
+			ctx, file_store_factory, log_path, options)
+		if err == nil {
+			return reader, nil
+		}
+
+		if errors.Is(err, indexes.ErrNoIndex) {
+			scheduleIndex(config_obj, file_store_factory, log_path)
+		}
+	}
+
 	// First do the filtering and then do the sorting.
 	return self.getFilteredReader(ctx, config_obj, file_store_factory,
 		log_path, options)
@@ -222,6 +246,30 @@ func (self ResultSetFactory) getSortedReader(
 	return self.NewResultSetReader(file_store_factory, transformed_path)
 }
 
+// Only index result sets large enough to be slow to transform.
+func scheduleIndex(
+	config_obj *config_proto.Config,
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec) {
+
+	min_rows := config_obj.Defaults.GetResultSetIndexMinRows()
+	if min_rows < 0 {
+		return
+	}
+
+	if min_rows == 0 {
+		min_rows = 10000
+	}
+
+	stat, err := file_store_factory.StatFile(
+		log_path.SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX))
+	if err != nil || stat.Size()/8 < min_rows {
+		return
+	}
+
+	indexes.Schedule(config_obj.OrgId, file_store_factory, log_path)
+}
+
 func getExpiry(config_obj *config_proto.Config) time.Duration {
 	// Default is 10 min to filter the file.
 	if config_obj.Defaults != nil &&
@@ -0,0 +1,447 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/geoip"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	accessDeniedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "listener_access_denied",
+			Help: "Number of connections rejected by listener access control.",
+		},
+		[]string{"listener", "reason"},
+	)
+
+	accessDeniedError = errors.New("Connection rejected by access control")
+)
+
+const (
+	DEFAULT_ACCESS_GEOIP_EDITION = "GeoLite2-City"
+
+	// Repeated decisions about the same address are only audited
+	// this often so a misbehaving client can not flood the audit
+	// log.
+	accessAuditInterval = time.Minute
+)
+
+// Listener level access control rejects connections as soon as they
+// are accepted, before we spend resources on a TLS handshake.
+type AccessControl struct {
+	ctx        context.Context
+	config_obj *config_proto.Config
+	logger     *logging.LogContext
+
+	// The name of the listener (frontend, gui or api).
+	listener string
+
+	allowed   utils.Networks
+	denied    utils.Networks
+	countries map[string]bool
+
+	geoip_database string
+	geoip_edition  string
+
+	// Connections from trusted proxies are accepted by the listener
+	// and the forwarded address is checked by AllowRequest().
+	trusted      utils.TrustedProxies
+	proxy_header string
+
+	ban_threshold int
+	ban_window    time.Duration
+	ban_duration  time.Duration
+
+	mu          sync.Mutex
+	db          geoip.Database
+	db_error    time.Time
+	failures    map[string][]time.Time
+	bans        map[string]time.Time
+	audited     map[string]time.Time
+	last_expiry time.Time
+}
+
+func NewAccessControl(
+	ctx context.Context, config_obj *config_proto.Config,
+	listener string, spec *config_proto.ListenerAccessControl) (
+	*AccessControl, error) {
+	if spec == nil {
+		spec = &config_proto.ListenerAccessControl{}
+	}
+
+	result := &AccessControl{
+		ctx:            ctx,
+		config_obj:     config_obj,
+		logger:         logging.GetLogger(config_obj, &logging.FrontendComponent),
+		listener:       listener,
+		countries:      make(map[string]bool),
+		geoip_database: spec.GeoipDatabase,
+		geoip_edition:  spec.GeoipEdition,
+		ban_threshold:  int(spec.BanThreshold),
+		ban_window:     time.Duration(spec.BanWindow) * time.Second,
+		ban_duration:   time.Duration(spec.BanDuration) * time.Second,
+		failures:       make(map[string][]time.Time),
+		bans:           make(map[string]time.Time),
+		audited:        make(map[string]time.Time),
+	}
+
+	var err error
+	result.allowed, err = utils.ParseNetworks(spec.AllowedCidr)
+	if err != nil {
+		return nil, err
+	}
+
+	result.denied, err = utils.ParseNetworks(spec.DeniedCidr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, country := range spec.BlockedCountries {
+		result.countries[strings.ToUpper(strings.TrimSpace(country))] = true
+	}
+
+	if result.geoip_edition == "" {
+		result.geoip_edition = DEFAULT_ACCESS_GEOIP_EDITION
+	}
+
+	if result.ban_window == 0 {
+		result.ban_window = time.Minute
+	}
+
+	if result.ban_duration == 0 {
+		result.ban_duration = 10 * time.Minute
+	}
+
+	return result, nil
+}
+
+// Are there any rules to check?
+func (self *AccessControl) IsEmpty() bool {
+	return len(self.allowed) == 0 && len(self.denied) == 0 &&
+		len(self.countries) == 0 && self.ban_threshold == 0
+}
+
+// Returns the reason the address is rejected or "" if it is
+// allowed.
+func (self *AccessControl) check(ip net.IP) string {
+	key := ip.String()
+
+	// Trusted proxies are never blocked - we check the client's
+	// address instead.
+	if self.trusted.IsTrusted(key) {
+		return ""
+	}
+
+	if self.isBanned(key) {
+		return "banned"
+	}
+
+	if self.denied.Contains(key) {
+		return "denied_cidr"
+	}
+
+	if len(self.allowed) > 0 && !self.allowed.Contains(key) {
+		return "allowed_cidr"
+	}
+
+	if len(self.countries) > 0 {
+		country := self.getCountry(ip)
+		if self.countries[country] {
+			return "country " + country
+		}
+	}
+
+	return ""
+}
+
+// Is a connection from this address (with or without a port)
+// allowed? Rejections are audited.
+func (self *AccessControl) Allow(addr string) bool {
+	if self.IsEmpty() {
+		return true
+	}
+
+	// Not an IP connection (e.g. a unix domain socket).
+	ip := utils.ParseAddr(addr)
+	if ip == nil {
+		return true
+	}
+
+	reason := self.check(ip)
+	if reason == "" {
+		return true
+	}
+
+	accessDeniedCounter.WithLabelValues(
+		self.listener, strings.Split(reason, " ")[0]).Inc()
+	self.audit(ip.String(), "ListenerConnectionRejected", reason)
+	return false
+}
+
+// Connections from trusted proxies are accepted by the listener, so
+// check the address they forwarded for the request.
+func (self *AccessControl) AllowRequest(req *http.Request) bool {
+	if len(self.trusted) == 0 || !self.trusted.IsTrusted(req.RemoteAddr) {
+		return true
+	}
+
+	addr := self.trusted.RemoteAddr(req, self.proxy_header)
+	if addr == req.RemoteAddr {
+		return true
+	}
+	return self.Allow(addr)
+}
+
+// Record a failed enrollment or undecryptable message from the
+// address. Too many failures result in a temporary ban.
+func (self *AccessControl) RecordFailure(addr, reason string) {
+	if self.ban_threshold == 0 {
+		return
+	}
+
+	ip := utils.ParseAddr(addr)
+	if ip == nil {
+		return
+	}
+
+	key := ip.String()
+	if self.trusted.IsTrusted(key) {
+		return
+	}
+
+	now := utils.GetTime().Now()
+
+	self.mu.Lock()
+	self.expire(now)
+
+	failures := []time.Time{now}
+	for _, ts := range self.failures[key] {
+		if now.Sub(ts) < self.ban_window {
+			failures = append(failures, ts)
+		}
+	}
+
+	banned := len(failures) >= self.ban_threshold
+	if banned {
+		delete(self.failures, key)
+		self.bans[key] = now.Add(self.ban_duration)
+	} else {
+		self.failures[key] = failures
+	}
+	self.mu.Unlock()
+
+	if banned {
+		self.logger.Info("AccessControl: Banning %v for %v after repeated %v failures",
+			key, self.ban_duration, reason)
+		self.audit(key, "ListenerAddressBanned", reason)
+	}
+}
+
+func (self *AccessControl) isBanned(key string) bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	expires, pres := self.bans[key]
+	if !pres {
+		return false
+	}
+
+	if utils.GetTime().Now().After(expires) {
+		delete(self.bans, key)
+		return false
+	}
+	return true
+}
+
+// Clear stale entries. Called with the lock held.
+func (self *AccessControl) expire(now time.Time) {
+	if now.Sub(self.last_expiry) < self.ban_window {
+		return
+	}
+	self.last_expiry = now
+
+	for key, failures := range self.failures {
+		if len(failures) == 0 || now.Sub(failures[0]) >= self.ban_window {
+			delete(self.failures, key)
+		}
+	}
+
+	for key, expires := range self.bans {
+		if now.After(expires) {
+			delete(self.bans, key)
+		}
+	}
+
+	for key, ts := range self.audited {
+		if now.Sub(ts) >= accessAuditInterval {
+			delete(self.audited, key)
+		}
+	}
+}
+
+func (self *AccessControl) audit(addr, operation, reason string) {
+	now := utils.GetTime().Now()
+	key := operation + addr + reason
+
+	self.mu.Lock()
+	self.expire(now)
+	last, pres := self.audited[key]
+	if pres && now.Sub(last) < accessAuditInterval {
+		self.mu.Unlock()
+		return
+	}
+	self.audited[key] = now
+	self.mu.Unlock()
+
+	err := services.LogAudit(self.ctx, self.config_obj, "AccessControl",
+		operation, ordereddict.NewDict().
+			Set("listener", self.listener).
+			Set("address", addr).
+			Set("reason", reason))
+	if err != nil {
+		self.logger.Error("AccessControl: %v", err)
+	}
+}
+
+func (self *AccessControl) getDatabase() (geoip.Database, error) {
+	if self.geoip_database == "" {
+		return geoip.GetManagedDatabase(self.config_obj, self.geoip_edition)
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.db == nil {
+		db, err := geoip.OpenFile(self.geoip_database)
+		if err != nil {
+			return nil, err
+		}
+		self.db = db
+	}
+	return self.db, nil
+}
+
+// Returns the ISO country code of the address or "" if it is not
+// known. If the database is not available we fail open rather than
+// locking out all clients.
+func (self *AccessControl) getCountry(ip net.IP) string {
+	db, err := self.getDatabase()
+	if err != nil {
+		self.mu.Lock()
+		now := utils.GetTime().Now()
+		should_log := now.Sub(self.db_error) > accessAuditInterval
+		if should_log {
+			self.db_error = now
+		}
+		self.mu.Unlock()
+
+		if should_log {
+			self.logger.Error("AccessControl: Unable to block countries: %v", err)
+		}
+		return ""
+	}
+
+	record, err := db.Lookup(ip)
+	if err != nil {
+		return ""
+	}
+	return countryFromRecord(record)
+}
+
+// Both MaxMind and IP2Location records are supported.
+func countryFromRecord(record interface{}) string {
+	switch t := record.(type) {
+	case *ordereddict.Dict:
+		country, _ := t.GetString("country_short")
+		return country
+
+	case map[string]interface{}:
+		country, ok := t["country"].(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		iso_code, _ := country["iso_code"].(string)
+		return iso_code
+	}
+	return ""
+}
+
+func (self *AccessControl) Close() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.db != nil {
+		self.db.Close()
+		self.db = nil
+	}
+}
+
+// Reject connections as soon as they are accepted.
+func (self *AccessControl) WrapListener(ln net.Listener) net.Listener {
+	if self.IsEmpty() {
+		return ln
+	}
+	return &accessControlListener{Listener: ln, access: self}
+}
+
+type accessControlListener struct {
+	net.Listener
+	access *AccessControl
+}
+
+func (self *accessControlListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := self.Listener.Accept()
+		if err != nil {
+			return conn, err
+		}
+
+		// The client's address is only known once the PROXY protocol
+		// header is read in the connection's own goroutine.
+		_, ok := conn.(*proxyProtocolConn)
+		if ok {
+			return &accessControlConn{Conn: conn, access: self.access}, nil
+		}
+
+		if self.access.Allow(conn.RemoteAddr().String()) {
+			return conn, nil
+		}
+		conn.Close()
+	}
+}
+
+type accessControlConn struct {
+	net.Conn
+	access *AccessControl
+
+	once sync.Once
+	err  error
+}
+
+func (self *accessControlConn) Read(b []byte) (int, error) {
+	self.once.Do(func() {
+		if !self.access.Allow(self.Conn.RemoteAddr().String()) {
+			self.err = accessDeniedError
+			self.Conn.Close()
+		}
+	})
+
+	if self.err != nil {
+		return 0, self.err
+	}
+	return self.Conn.Read(b)
+}
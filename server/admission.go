@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/crypto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	admissionRejectedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "frontend_admission_rejections",
+			Help: "Number of client requests rejected by admission control.",
+		},
+		[]string{"reason"},
+	)
+
+	admissionQueueGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "frontend_admission_queue_length",
+		Help: "Number of client requests waiting for a concurrency slot.",
+	})
+
+	backpressureError = errors.New("Frontend queue is full")
+)
+
+// Admission control protects the frontend from check-in storms
+// (e.g. after a network outage when all clients reconnect at
+// once). Rather than accepting every request and timing out, excess
+// requests are rejected early with a Retry-After header so clients
+// back off.
+type AdmissionControl struct {
+	// Accessed atomically so must be 64 bit aligned.
+	waiting int64
+
+	// Token buckets for data POSTs and enrollments. A nil limiter
+	// is unlimited.
+	posts       *rate.Limiter
+	enrollments *rate.Limiter
+
+	// Client event batches only get a share of the concurrency so
+	// flow results are processed first under load.
+	events chan bool
+
+	max_queue   int64
+	retry_after time.Duration
+}
+
+func newLimiter(per_second, burst uint64) *rate.Limiter {
+	if per_second == 0 {
+		return nil
+	}
+
+	if burst == 0 {
+		burst = 10 * per_second
+	}
+	return rate.NewLimiter(rate.Limit(per_second), int(burst))
+}
+
+func NewAdmissionControl(
+	config_obj *config_proto.Config, concurrency uint64) *AdmissionControl {
+	resources := config_obj.Frontend.Resources
+	if resources == nil {
+		resources = &config_proto.FrontendResourceControl{}
+	}
+
+	result := &AdmissionControl{
+		posts: newLimiter(
+			resources.PostsPerSecond, resources.PostsBurst),
+		max_queue:   int64(resources.MaxQueueLength),
+		retry_after: time.Duration(resources.RetryAfter) * time.Second,
+	}
+
+	// Negative enrollment rates disable enrollment altogether, so
+	// there is nothing to limit.
+	enrollments_per_second := resources.EnrollmentsPerSecond
+	if enrollments_per_second == 0 {
+		enrollments_per_second = 100
+	}
+	if enrollments_per_second > 0 {
+		result.enrollments = newLimiter(
+			uint64(enrollments_per_second), resources.EnrollmentsBurst)
+	}
+
+	if result.max_queue == 0 {
+		result.max_queue = 10 * int64(concurrency)
+	}
+
+	if result.retry_after == 0 {
+		result.retry_after = 30 * time.Second
+	}
+
+	event_concurrency := resources.EventConcurrency
+	if event_concurrency == 0 {
+		event_concurrency = concurrency / 2
+	}
+	if event_concurrency == 0 {
+		event_concurrency = 1
+	}
+	result.events = make(chan bool, event_concurrency)
+
+	return result
+}
+
+func (self *AdmissionControl) AdmitPost() bool {
+	return self.posts == nil || self.posts.Allow()
+}
+
+func (self *AdmissionControl) AdmitEnrollment() bool {
+	return self.enrollments == nil || self.enrollments.Allow()
+}
+
+// Wait for a slot in the concurrency pool unless too many requests
+// are already waiting.
+func (self *AdmissionControl) Queue(
+	ctx context.Context, pool *utils.Concurrency) (func(), error) {
+	defer atomic.AddInt64(&self.waiting, -1)
+	defer admissionQueueGauge.Dec()
+
+	admissionQueueGauge.Inc()
+	if atomic.AddInt64(&self.waiting, 1) > self.max_queue {
+		return nil, backpressureError
+	}
+
+	return pool.StartConcurrencyControl(ctx)
+}
+
+// Take an event batch slot if one is available right now.
+func (self *AdmissionControl) AdmitEvents() (func(), bool) {
+	select {
+	case self.events <- true:
+		return func() { <-self.events }, true
+	default:
+		return nil, false
+	}
+}
+
+// Ask the client to come back later.
+func (self *AdmissionControl) Reject(w http.ResponseWriter, reason string) {
+	admissionRejectedCounter.WithLabelValues(reason).Inc()
+
+	w.Header().Set("Retry-After",
+		fmt.Sprintf("%d", int(self.retry_after.Seconds())))
+	http.Error(w, "Overloaded", http.StatusServiceUnavailable)
+}
+
+// Does the POST only carry client monitoring events?
+func isEventBatch(ctx context.Context, config_obj *config_proto.Config,
+	message_info *crypto.MessageInfo) bool {
+	count := 0
+	only_events := true
+	err := message_info.IterateJobs(ctx, config_obj,
+		func(ctx context.Context, msg *crypto_proto.VeloMessage) error {
+			if msg.SessionId != constants.MONITORING_WELL_KNOWN_FLOW {
+				only_events = false
+			}
+			count++
+			return nil
+		})
+	return err == nil && only_events && count > 0
+}
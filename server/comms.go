@@ -27,14 +27,15 @@ import (
 	"math/rand"
 	"net/http"
 	"net/url"
-	"sync/atomic"
 	"time"
 
 	"www.velocidex.com/golang/velociraptor/crypto"
 	"www.velocidex.com/golang/velociraptor/file_store"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/server/connections"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/tracing"
 	"www.velocidex.com/golang/velociraptor/utils"
 
 	"github.com/Velocidex/ordereddict"
@@ -131,7 +132,8 @@ func PrepareFrontendMux(
 	}
 
 	base := config_obj.Frontend.BasePath
-	router.Handle(base+"/healthz", healthz(server_obj))
+	router.Handle(base+"/healthz", healthz(config_obj, server_obj))
+	router.Handle(base+"/readyz", readyz(config_obj, server_obj))
 	router.Handle(base+"/server.pem", server_pem(config_obj))
 
 	// DEPRECATED: These are the old handler names - not great
@@ -159,16 +161,6 @@ func PrepareFrontendMux(
 	return nil
 }
 
-func healthz(server_obj *Server) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if atomic.LoadInt32(&server_obj.Healthy) == 1 {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-		w.WriteHeader(http.StatusServiceUnavailable)
-	})
-}
-
 func server_pem(config_obj *config_proto.Config) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
@@ -252,12 +244,17 @@ func readWithLimits(
 			"(%v out of max %v)", req.RemoteAddr, err, n, max_upload_size*2)
 
 		receiveDecryptionErrors.Inc()
+		server_obj.Access().RecordFailure(server_obj.RemoteAddr(req), "crypto")
 		return nil, errors.New("Unable to decrypt")
 	}
-	message_info.RemoteAddr = utils.RemoteAddr(req, config_obj.Frontend.GetProxyHeader())
+	message_info.RemoteAddr = server_obj.RemoteAddr(req)
 	server_obj.Debug("Received a post of length %v from %v (%v)",
 		n, message_info.RemoteAddr, message_info.Source)
 
+	if message_info.Authenticated {
+		connections.Tracker.AddBytesReceived(message_info.Source, uint64(n))
+	}
+
 	return message_info, nil
 }
 
@@ -276,9 +273,12 @@ func receive_client_messages(
 			panic("http handler is not a flusher")
 		}
 
+		ctx, span := tracing.StartRequest(req, "frontend.receive")
+		defer span.End()
+
 		// Allow a limited time to read from the client because this
 		// is the hot path.
-		ctx, cancel := context.WithTimeout(req.Context(), 600*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 600*time.Second)
 		defer cancel()
 
 		// If the client connection drops we close the reader.
@@ -297,12 +297,24 @@ func receive_client_messages(
 		}
 
 		receiveCounter.Inc()
+		defer server_obj.trackInFlight(w)()
+
+		if !server_obj.Access().AllowRequest(req) {
+			http.Error(w, "", http.StatusForbidden)
+			return
+		}
 
 		priority := req.Header.Get("X-Priority")
 		// For urgent messages skip concurrency control - This
 		// allows clients with urgent messages to always be
 		// processing even when the frontend are loaded.
+		admission := server_obj.Admission()
 		if priority != "urgent" {
+			if !admission.AdmitPost() {
+				admission.Reject(w, "rate")
+				return
+			}
+
 			// Keep track of the average time the request spends
 			// waiting for a concurrency slot. If this time is too
 			// long it means concurrency may need to be increased.
@@ -310,7 +322,12 @@ func receive_client_messages(
 				concurrencyWaitHistorgram.Observe(v)
 			}))
 
-			cancel, err := server_obj.Concurrency().StartConcurrencyControl(ctx)
+			cancel, err := admission.Queue(ctx, server_obj.Concurrency())
+			if err == backpressureError {
+				admission.Reject(w, "queue")
+				return
+			}
+
 			if err != nil {
 				http.Error(w, "Timeout", http.StatusRequestTimeout)
 				timeoutCounter.Inc()
@@ -335,6 +352,8 @@ func receive_client_messages(
 		// Read the payload from the client.
 		message_info, err := readWithLimits(ctx, config_obj, server_obj, req)
 		if err != nil {
+			span.RecordError(err)
+
 			// Drop the packet on the floor to release it from the
 			// client's queue. If the client sends a very large packet
 			// it will be truncated by the above limit, and will not
@@ -353,9 +372,20 @@ func receive_client_messages(
 			return
 		}
 
+		span.SetAttributes(
+			tracing.String("client_id", message_info.Source),
+			tracing.String("org_id", message_info.OrgId),
+			tracing.Bool("authenticated", message_info.Authenticated),
+			tracing.Bool("urgent", priority == "urgent"))
+
 		// Very few Unauthenticated client messages are valid
 		// - currently only enrolment requests.
 		if !message_info.Authenticated {
+			if !admission.AdmitEnrollment() {
+				admission.Reject(w, "enrollment")
+				return
+			}
+
 			err := server_obj.ProcessUnauthenticatedMessages(
 				req.Context(), config_obj, message_info)
 			if err == nil {
@@ -374,11 +404,28 @@ func receive_client_messages(
 					http.StatusNotAcceptable)
 			} else {
 				server_obj.Debug("Unable to process (%v)", message_info.Source)
+				server_obj.Access().RecordFailure(
+					message_info.RemoteAddr, "enrollment")
 				http.Error(w, "", http.StatusServiceUnavailable)
 			}
 			return
 		}
 
+		// Flow results take priority over client event batches
+		// which may only use part of the concurrency.
+		if priority != "urgent" &&
+			isEventBatch(ctx, config_obj, message_info) {
+			release, ok := admission.AdmitEvents()
+			if !ok {
+				admission.Reject(w, "events")
+				return
+			}
+			defer release()
+		}
+
+		defer connections.Tracker.StartUpload(
+			message_info.Source, priority == "urgent")()
+
 		// From here below we have received the client payload
 		// and it should not resend it to us. We need to keep
 		// the client blocked until we finish processing the
@@ -387,6 +434,9 @@ func receive_client_messages(
 		// while the flow is processed.
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		w.Header().Set("X-Accel-Buffering", "no")
+
+		// Tell the client which compression it may use.
+		w.Header().Set(crypto.ACCEPT_ENCODING_HEADER, crypto.AcceptedEncodings())
 		w.WriteHeader(http.StatusOK)
 		flusher.Flush()
 
@@ -397,8 +447,10 @@ func receive_client_messages(
 			// Process the request with a different context - if the
 			// client disconnects quickly the request context will be
 			// cancelled and aborted, but we do not want this to
-			// interrupt actually processing the message.
-			subctx, cancel := context.WithTimeout(context.Background(),
+			// interrupt actually processing the message. The
+			// processing is still part of the request's trace.
+			subctx, cancel := context.WithTimeout(
+				tracing.ContextWithSpan(context.Background(), span),
 				60*time.Second)
 			defer cancel()
 
@@ -441,6 +493,8 @@ func receive_client_messages(
 			case response, ok := <-sync:
 				if ok {
 					_, _ = w.Write(response)
+					connections.Tracker.AddBytesSent(
+						message_info.Source, uint64(len(response)))
 				}
 				return
 
@@ -470,6 +524,12 @@ func send_client_messages(server_obj *Server) http.Handler {
 		}
 
 		sendCounter.Inc()
+		defer server_obj.trackInFlight(w)()
+
+		if !server_obj.Access().AllowRequest(req) {
+			http.Error(w, "", http.StatusForbidden)
+			return
+		}
 
 		// Keep track of currently connected clients.
 		currentConnections.Inc()
@@ -487,10 +547,11 @@ func send_client_messages(server_obj *Server) http.Handler {
 		if err != nil {
 			// Just plain reject with a 403.
 			server_obj.Debug("Rejecting request from %v: %v", req.RemoteAddr, err)
+			server_obj.Access().RecordFailure(server_obj.RemoteAddr(req), "crypto")
 			http.Error(w, "", http.StatusForbidden)
 			return
 		}
-		message_info.RemoteAddr = req.RemoteAddr
+		message_info.RemoteAddr = server_obj.RemoteAddr(req)
 
 		// Reject unauthenticated messages. This ensures
 		// untrusted clients are not allowed to keep
@@ -584,6 +645,9 @@ func send_client_messages(server_obj *Server) http.Handler {
 		notification, cancel := notifier.ListenForNotification(source)
 		defer cancel()
 
+		defer connections.Tracker.Connect(source, message_info.OrgId,
+			message_info.RemoteAddr, req.UserAgent(), getTransport(req))()
+
 		// Deadlines are designed to ensure that connections
 		// are not blocked for too long (maybe several
 		// minutes). This helps to expire connections when the
@@ -600,6 +664,7 @@ func send_client_messages(server_obj *Server) http.Handler {
 		// We now write the header and block the client until
 		// a notification is sent on the notification pool.
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set(crypto.ACCEPT_ENCODING_HEADER, crypto.AcceptedEncodings())
 		w.WriteHeader(http.StatusOK)
 		flusher.Flush()
 
@@ -620,6 +685,7 @@ func send_client_messages(server_obj *Server) http.Handler {
 			if err != nil || n < len(serialized_pad) {
 				server_obj.Info("reader: Error %v", err)
 			}
+			connections.Tracker.AddBytesSent(source, uint64(n))
 			return
 		}
 
@@ -653,6 +719,7 @@ func send_client_messages(server_obj *Server) http.Handler {
 				if err != nil || n < len(serialized_pad) {
 					server_obj.Debug("reader: Error %v", err)
 				}
+				connections.Tracker.AddBytesSent(source, uint64(n))
 
 				flusher.Flush()
 				return
@@ -669,6 +736,18 @@ func send_client_messages(server_obj *Server) http.Handler {
 				flusher.Flush()
 				return
 
+			case <-server_obj.Draining():
+				// Release the client so it reconnects to another
+				// frontend.
+				_, err := w.Write(serialized_pad)
+				if err != nil {
+					server_obj.Debug("reader: Error %v", err)
+					return
+				}
+
+				flusher.Flush()
+				return
+
 				// Write a pad message every 10 seconds
 				// to keep the conenction alive.
 			case <-time.After(10 * time.Second):
@@ -684,6 +763,15 @@ func send_client_messages(server_obj *Server) http.Handler {
 	})
 }
 
+// The transport the client connected with. TLS may also be
+// terminated by a reverse proxy in front of the frontend.
+func getTransport(req *http.Request) string {
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
 // Record the status of the request so we can log it.
 type statusRecorder struct {
 	http.ResponseWriter
@@ -752,15 +840,22 @@ func downloadPublic(
 			return
 		}
 
-		// From here on we already sent the headers and we can
-		// not really report an error to the client.
+		defer fd.Close()
+
 		w.Header().Set("Content-Disposition", "attachment; filename="+
 			url.PathEscape(path_spec.Base())+api.GetExtensionForFilestore(path_spec))
 
 		w.Header().Set("Content-Type", "binary/octet-stream")
-		w.WriteHeader(200)
 
-		utils.Copy(r.Context(), w, fd)
+		// Support range requests so clients can resume large tool
+		// downloads.
+		var mtime time.Time
+		stat, err := fd.Stat()
+		if err == nil {
+			mtime = stat.ModTime()
+		}
+
+		http.ServeContent(w, r, path_spec.Base(), mtime, fd)
 	})
 }
 
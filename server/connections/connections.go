@@ -0,0 +1,349 @@
+/*
+  Keep track of the state of client connections to this frontend.
+
+  Clients maintain two kinds of HTTP connections with the frontend:
+
+  1. The reader connection is a long poll which remains open while
+     the client is waiting for new tasks. A client is considered
+     connected while it holds a reader connection.
+
+  2. The receiver connections are short lived POSTs which upload
+     results to the server. Each of these takes a concurrency slot
+     (unless the client marked it urgent) while it is being
+     processed.
+
+  This package is a leaf so it can be used by the frontend, the flow
+  runner and VQL plugins without import cycles.
+*/
+
+package connections
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// Forget about clients which disconnected more than this long
+	// ago.
+	expiry = 10 * time.Minute
+
+	// Check ins are counted in one minute buckets over this many
+	// minutes.
+	checkinWindow = 5
+)
+
+// A snapshot of the state of a single client connection.
+type ConnectionState struct {
+	ClientId   string `json:"ClientId"`
+	OrgId      string `json:"OrgId"`
+	RemoteAddr string `json:"RemoteAddr"`
+	UserAgent  string `json:"UserAgent"`
+
+	// The transport the reader connection used (http or https).
+	Transport string `json:"Transport"`
+
+	// Is the client holding a reader connection now?
+	Connected bool `json:"Connected"`
+
+	// When we first saw the client and when the last reader
+	// connection was established and closed.
+	FirstSeen         time.Time `json:"FirstSeen"`
+	ConnectedSince    time.Time `json:"ConnectedSince"`
+	LastDisconnect    time.Time `json:"LastDisconnect"`
+	LastPing          time.Time `json:"LastPing"`
+	ReaderConnections uint64    `json:"ReaderConnections"`
+
+	// Number of reader and receiver connections in the last few
+	// minutes. A high number indicates a client in a check in loop.
+	RecentCheckins uint64 `json:"RecentCheckins"`
+
+	// Number of receiver connections currently being processed and
+	// how many concurrency slots they hold.
+	ActiveUploads int64    `json:"ActiveUploads"`
+	Concurrency   int64    `json:"Concurrency"`
+	Uploads       uint64   `json:"Uploads"`
+	UrgentUploads uint64   `json:"UrgentUploads"`
+	BytesReceived uint64   `json:"BytesReceived"`
+	BytesSent     uint64   `json:"BytesSent"`
+	InFlightFlows []string `json:"InFlightFlows"`
+}
+
+// Overall state of this frontend.
+type FrontendStatus struct {
+	ConcurrencyLimit int    `json:"ConcurrencyLimit"`
+	ConcurrencyInUse int    `json:"ConcurrencyInUse"`
+	ConnectedClients int    `json:"ConnectedClients"`
+	ActiveUploads    int64  `json:"ActiveUploads"`
+	InFlightFlows    int    `json:"InFlightFlows"`
+	BytesReceived    uint64 `json:"BytesReceived"`
+	BytesSent        uint64 `json:"BytesSent"`
+}
+
+// Count events in one minute buckets.
+type checkinCounter struct {
+	buckets [checkinWindow]uint64
+	minute  int64
+}
+
+// Move the window to the current minute, clearing buckets for minutes
+// we did not see.
+func (self *checkinCounter) advance(now time.Time) {
+	minute := now.Unix() / 60
+	for i := int64(0); i < checkinWindow && self.minute < minute; i++ {
+		self.minute++
+		self.buckets[self.minute%checkinWindow] = 0
+	}
+	self.minute = minute
+}
+
+func (self *checkinCounter) Inc(now time.Time) {
+	self.advance(now)
+	self.buckets[self.minute%checkinWindow]++
+}
+
+func (self *checkinCounter) Count(now time.Time) uint64 {
+	self.advance(now)
+
+	result := uint64(0)
+	for _, count := range self.buckets {
+		result += count
+	}
+	return result
+}
+
+type connection struct {
+	state    ConnectionState
+	checkins checkinCounter
+
+	// Incremented for each reader connection so an old connection
+	// closing does not mark a newer one disconnected.
+	generation uint64
+
+	// Flows the client reported running and when we last heard
+	// about them.
+	flows map[string]time.Time
+}
+
+type ConnectionTracker struct {
+	mu          sync.Mutex
+	connections map[string]*connection
+
+	// The frontend's concurrency control for receiver connections.
+	concurrency *utils.Concurrency
+
+	clock utils.Clock
+}
+
+func (self *ConnectionTracker) SetConcurrency(concurrency *utils.Concurrency) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.concurrency = concurrency
+}
+
+func (self *ConnectionTracker) get(client_id string) *connection {
+	conn, pres := self.connections[client_id]
+	if !pres {
+		conn = &connection{
+			state: ConnectionState{
+				ClientId:  client_id,
+				FirstSeen: self.clock.Now(),
+			},
+			flows: make(map[string]time.Time),
+		}
+		self.connections[client_id] = conn
+	}
+	return conn
+}
+
+// Called when a client establishes a reader connection. Returns a
+// function to be called when the connection is closed.
+func (self *ConnectionTracker) Connect(
+	client_id, org_id, remote_addr, user_agent, transport string) func() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	now := self.clock.Now()
+	self.expire(now)
+
+	conn := self.get(client_id)
+	conn.state.OrgId = org_id
+	conn.state.RemoteAddr = remote_addr
+	conn.state.UserAgent = user_agent
+	conn.state.Transport = transport
+	conn.state.Connected = true
+	conn.state.ConnectedSince = now
+	conn.state.LastPing = now
+	conn.state.ReaderConnections++
+	conn.checkins.Inc(now)
+	conn.generation++
+	generation := conn.generation
+
+	return func() {
+		self.mu.Lock()
+		defer self.mu.Unlock()
+
+		if conn.generation != generation {
+			return
+		}
+		conn.state.Connected = false
+		conn.state.LastDisconnect = self.clock.Now()
+	}
+}
+
+// Called when the client starts uploading results. Returns a
+// function to be called when the upload is processed.
+func (self *ConnectionTracker) StartUpload(
+	client_id string, urgent bool) func() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	now := self.clock.Now()
+	conn := self.get(client_id)
+	conn.state.LastPing = now
+	conn.state.Uploads++
+	conn.checkins.Inc(now)
+	conn.state.ActiveUploads++
+	if urgent {
+		conn.state.UrgentUploads++
+	} else {
+		conn.state.Concurrency++
+	}
+
+	return func() {
+		self.mu.Lock()
+		defer self.mu.Unlock()
+
+		conn.state.ActiveUploads--
+		if !urgent {
+			conn.state.Concurrency--
+		}
+	}
+}
+
+func (self *ConnectionTracker) AddBytesReceived(client_id string, n uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.get(client_id).state.BytesReceived += n
+}
+
+func (self *ConnectionTracker) AddBytesSent(client_id string, n uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.get(client_id).state.BytesSent += n
+}
+
+// Record the progress of a flow the client is running. Only clients
+// we already track are updated.
+func (self *ConnectionTracker) FlowStats(
+	client_id, flow_id string, complete bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	conn, pres := self.connections[client_id]
+	if !pres {
+		return
+	}
+
+	if complete {
+		delete(conn.flows, flow_id)
+		return
+	}
+	conn.flows[flow_id] = self.clock.Now()
+}
+
+// Remove clients which disconnected a while ago and flows we have
+// not heard about for a while (e.g. the client crashed).
+func (self *ConnectionTracker) expire(now time.Time) {
+	for client_id, conn := range self.connections {
+		if !conn.state.Connected && conn.state.ActiveUploads == 0 &&
+			now.Sub(conn.state.LastPing) > expiry &&
+			now.Sub(conn.state.LastDisconnect) > expiry {
+			delete(self.connections, client_id)
+			continue
+		}
+
+		for flow_id, last_seen := range conn.flows {
+			if now.Sub(last_seen) > expiry {
+				delete(conn.flows, flow_id)
+			}
+		}
+	}
+}
+
+// Get a snapshot of all connections sorted by client id. If
+// include_disconnected is set also include recently disconnected
+// clients.
+func (self *ConnectionTracker) List(
+	include_disconnected bool) []*ConnectionState {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	now := self.clock.Now()
+	self.expire(now)
+
+	result := make([]*ConnectionState, 0, len(self.connections))
+	for _, conn := range self.connections {
+		if !include_disconnected && !conn.state.Connected {
+			continue
+		}
+
+		state := conn.state
+		state.RecentCheckins = conn.checkins.Count(now)
+		state.InFlightFlows = make([]string, 0, len(conn.flows))
+		for flow_id := range conn.flows {
+			state.InFlightFlows = append(state.InFlightFlows, flow_id)
+		}
+		sort.Strings(state.InFlightFlows)
+		result = append(result, &state)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ClientId < result[j].ClientId
+	})
+
+	return result
+}
+
+// Summarize the tracked connections. Byte counts only cover clients
+// which connected recently.
+func (self *ConnectionTracker) Status() *FrontendStatus {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.expire(self.clock.Now())
+
+	result := &FrontendStatus{}
+	if self.concurrency != nil {
+		result.ConcurrencyLimit = self.concurrency.Size()
+		result.ConcurrencyInUse = self.concurrency.InUse()
+	}
+
+	for _, conn := range self.connections {
+		if conn.state.Connected {
+			result.ConnectedClients++
+		}
+		result.ActiveUploads += conn.state.ActiveUploads
+		result.InFlightFlows += len(conn.flows)
+		result.BytesReceived += conn.state.BytesReceived
+		result.BytesSent += conn.state.BytesSent
+	}
+
+	return result
+}
+
+func NewConnectionTracker(clock utils.Clock) *ConnectionTracker {
+	return &ConnectionTracker{
+		connections: make(map[string]*connection),
+		clock:       clock,
+	}
+}
+
+// The frontend serves all orgs so there is a single tracker.
+var Tracker = NewConnectionTracker(utils.RealClock{})
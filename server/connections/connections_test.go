@@ -0,0 +1,67 @@
+package connections
+
+import (
+	"testing"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestConnectionTracker(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.Set(time.Unix(1600000000, 0))
+
+	tracker := NewConnectionTracker(clock)
+	tracker.SetConcurrency(utils.NewConcurrencyControl(4, time.Second))
+
+	closer := tracker.Connect("C.1", "", "10.0.0.1:1234", "agent", "https")
+	done := tracker.StartUpload("C.1", false)
+	tracker.AddBytesReceived("C.1", 100)
+	tracker.AddBytesSent("C.1", 10)
+	tracker.FlowStats("C.1", "F.1", false)
+	tracker.FlowStats("C.1", "F.2", false)
+
+	// Flows for clients we do not know about are ignored.
+	tracker.FlowStats("C.2", "F.3", false)
+
+	list := tracker.List(false)
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, "https", list[0].Transport)
+	assert.Equal(t, int64(1), list[0].Concurrency)
+	assert.Equal(t, uint64(100), list[0].BytesReceived)
+	assert.Equal(t, uint64(2), list[0].RecentCheckins)
+	assert.Equal(t, []string{"F.1", "F.2"}, list[0].InFlightFlows)
+
+	status := tracker.Status()
+	assert.Equal(t, 4, status.ConcurrencyLimit)
+	assert.Equal(t, 1, status.ConnectedClients)
+	assert.Equal(t, 2, status.InFlightFlows)
+
+	done()
+	tracker.FlowStats("C.1", "F.1", true)
+
+	// A new reader connection replaces the old one.
+	clock.Sleep(time.Minute)
+	closer2 := tracker.Connect("C.1", "", "10.0.0.1:1235", "agent", "https")
+	closer()
+
+	list = tracker.List(false)
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, int64(0), list[0].ActiveUploads)
+	assert.Equal(t, uint64(2), list[0].ReaderConnections)
+	assert.Equal(t, []string{"F.2"}, list[0].InFlightFlows)
+
+	// Disconnected clients are only shown on request.
+	closer2()
+	assert.Equal(t, 0, len(tracker.List(false)))
+	assert.Equal(t, 1, len(tracker.List(true)))
+
+	// Check ins fall out of the window.
+	clock.Sleep(checkinWindow * time.Minute)
+	assert.Equal(t, uint64(0), tracker.List(true)[0].RecentCheckins)
+
+	// Eventually the client is forgotten.
+	clock.Sleep(expiry)
+	assert.Equal(t, 0, len(tracker.List(true)))
+}
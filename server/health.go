@@ -0,0 +1,333 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Health checks for load balancers: /healthz reports if the frontend
+// is alive and /readyz if it should receive client traffic.
+//
+// In drain mode the frontend stops accepting new client connections
+// and asks clients to reconnect elsewhere, while in flight requests
+// are allowed to finish. This allows rolling upgrades behind a load
+// balancer.
+
+var (
+	drainCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "frontend_drain_rejections",
+		Help: "Number of connections rejected because the frontend is draining.",
+	})
+
+	notStartedError = errors.New("Frontend is not started")
+)
+
+const (
+	// Readiness checks touch the datastore and filestore so we
+	// only run them this often.
+	healthCheckInterval = 5 * time.Second
+)
+
+type HealthCheck struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+type HealthStatus struct {
+	Ready    bool          `json:"ready"`
+	Draining bool          `json:"draining"`
+	InFlight int64         `json:"in_flight"`
+	Checks   []HealthCheck `json:"checks,omitempty"`
+}
+
+// Stop accepting new client connections.
+func (self *Server) Drain() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	select {
+	case <-self.draining:
+		return
+	default:
+	}
+
+	self.logger.Info("<red>Draining</> frontend: No new client connections will be accepted")
+	close(self.draining)
+}
+
+// Resume accepting client connections.
+func (self *Server) Undrain() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	select {
+	case <-self.draining:
+		self.logger.Info("Frontend is accepting client connections again")
+		self.draining = make(chan bool)
+	default:
+	}
+}
+
+// The channel is closed when the frontend starts draining.
+func (self *Server) Draining() <-chan bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.draining
+}
+
+func (self *Server) IsDraining() bool {
+	select {
+	case <-self.Draining():
+		return true
+	default:
+		return false
+	}
+}
+
+// The number of client requests currently being served.
+func (self *Server) InFlight() int64 {
+	return atomic.LoadInt64(&self.in_flight)
+}
+
+func (self *Server) trackInFlight(w http.ResponseWriter) func() {
+	atomic.AddInt64(&self.in_flight, 1)
+
+	// Ask the client not to reuse this connection so it reconnects
+	// to another frontend.
+	if self.IsDraining() {
+		w.Header().Set("Connection", "close")
+	}
+
+	return func() {
+		atomic.AddInt64(&self.in_flight, -1)
+	}
+}
+
+func (self *Server) HealthStatus() *HealthStatus {
+	return &HealthStatus{
+		Draining: self.IsDraining(),
+		InFlight: self.InFlight(),
+	}
+}
+
+// Liveness only checks the process itself.
+func (self *Server) checkLive(config_obj *config_proto.Config) []HealthCheck {
+	result := []HealthCheck{
+		newHealthCheck("listener", func() error {
+			if atomic.LoadInt32(&self.Healthy) != 1 {
+				return notStartedError
+			}
+			return nil
+		}),
+		newHealthCheck("services", func() error {
+			_, err := services.GetOrgManager()
+			if err != nil {
+				return err
+			}
+			_, err = services.GetJournal(config_obj)
+			return err
+		}),
+	}
+	return result
+}
+
+// Readiness also checks we can actually store client data.
+func (self *Server) checkReady(config_obj *config_proto.Config) []HealthCheck {
+	self.health_mu.Lock()
+	defer self.health_mu.Unlock()
+
+	now := utils.GetTime().Now()
+	if self.health_checks != nil &&
+		now.Sub(self.health_checked) < healthCheckInterval {
+		return self.health_checks
+	}
+
+	node := services.GetNodeName(config_obj.Frontend)
+	result := append(self.checkLive(config_obj),
+		newHealthCheck("datastore", func() error {
+			return checkDatastore(config_obj, node)
+		}),
+		newHealthCheck("filestore", func() error {
+			return checkFilestore(config_obj, node)
+		}))
+
+	self.health_checks = result
+	self.health_checked = now
+
+	return result
+}
+
+func (self *Server) Liveness(config_obj *config_proto.Config) *HealthStatus {
+	result := self.HealthStatus()
+	result.Checks = self.checkLive(config_obj)
+	result.Ready = allPassed(result.Checks)
+	return result
+}
+
+func (self *Server) Readiness(config_obj *config_proto.Config) *HealthStatus {
+	result := self.HealthStatus()
+	result.Checks = self.checkReady(config_obj)
+	result.Ready = !result.Draining && allPassed(result.Checks)
+	return result
+}
+
+func newHealthCheck(name string, cb func() error) HealthCheck {
+	result := HealthCheck{Name: name}
+	err := cb()
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func allPassed(checks []HealthCheck) bool {
+	for _, check := range checks {
+		if check.Error != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Write and read back a marker for this node.
+func checkDatastore(config_obj *config_proto.Config, node string) error {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	path := paths.HEALTH_ROOT.AddUnsafeChild(node)
+	err = db.SetSubject(config_obj, path, &emptypb.Empty{})
+	if err != nil {
+		return err
+	}
+
+	return db.GetSubject(config_obj, path, &emptypb.Empty{})
+}
+
+func checkFilestore(config_obj *config_proto.Config, node string) error {
+	file_store_factory := file_store.GetFileStore(config_obj)
+	if file_store_factory == nil {
+		return errors.New("Filestore not configured")
+	}
+
+	path := paths.TEMP_ROOT.AddUnsafeChild("healthcheck_" + node)
+	fd, err := file_store_factory.WriteFileWithCompletion(
+		path, utils.SyncCompleter)
+	if err != nil {
+		return err
+	}
+
+	err = fd.Truncate()
+	if err != nil {
+		fd.Close()
+		return err
+	}
+
+	_, err = fd.Write([]byte(utils.GetTime().Now().UTC().String()))
+	if err != nil {
+		fd.Close()
+		return err
+	}
+
+	err = fd.Close()
+	if err != nil {
+		return err
+	}
+
+	_, err = file_store_factory.StatFile(path)
+	return err
+}
+
+func writeHealthStatus(w http.ResponseWriter,
+	status *HealthStatus, ok_status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if status.Ready {
+		w.WriteHeader(ok_status)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if ok_status != http.StatusNoContent || !status.Ready {
+		_ = json.NewEncoder(w).Encode(status)
+	}
+}
+
+// Liveness probe: The frontend is up and its services are running.
+func healthz(config_obj *config_proto.Config, server_obj *Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeHealthStatus(w, server_obj.Liveness(config_obj),
+			http.StatusNoContent)
+	})
+}
+
+// Readiness probe: The frontend can serve clients and is not
+// draining.
+func readyz(config_obj *config_proto.Config, server_obj *Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeHealthStatus(w, server_obj.Readiness(config_obj), http.StatusOK)
+	})
+}
+
+// Control drain mode. This is served on the monitoring port which
+// should only be reachable by operators:
+//
+// GET /drain - report the status
+// POST /drain - start draining
+// DELETE /drain - stop draining
+func DrainHandler(server_obj *Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			server_obj.Drain()
+		case http.MethodDelete:
+			server_obj.Undrain()
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(server_obj.HealthStatus())
+	})
+}
+
+// Reject new connections while draining.
+type drainingListener struct {
+	net.Listener
+	server_obj *Server
+}
+
+func (self *drainingListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := self.Listener.Accept()
+		if err != nil {
+			return conn, err
+		}
+
+		if !self.server_obj.IsDraining() {
+			return conn, nil
+		}
+
+		drainCounter.Inc()
+		conn.Close()
+	}
+}
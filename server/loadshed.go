@@ -45,7 +45,19 @@ func (self *Server) NewLoadSheddingListener(addr string) (*LoadSheddingListener,
 	}
 
 	return &LoadSheddingListener{
-		Listener:  ln,
+		Listener:  self.WrapListener(ln),
 		throttler: self.throttler,
 	}, err, ln.Close
 }
+
+// Accept PROXY protocol headers from trusted proxies if configured,
+// apply access control and reject connections while draining.
+func (self *Server) WrapListener(ln net.Listener) net.Listener {
+	if self.proxy_protocol {
+		ln = NewProxyProtocolListener(ln, self.trusted_proxies)
+	}
+	return &drainingListener{
+		Listener:   self.access.WrapListener(ln),
+		server_obj: self,
+	}
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Support for the PROXY protocol
+// https://www.haproxy.org/download/2.8/doc/proxy-protocol.txt
+//
+// TCP load balancers prepend a header with the original client's
+// address to the connection. We only parse the header on connections
+// from trusted proxies, other connections are passed through as is.
+
+var (
+	proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+	invalidProxyHeaderError = errors.New("Invalid PROXY protocol header")
+)
+
+const (
+	proxyHeaderTimeout = 10 * time.Second
+)
+
+type ProxyProtocolListener struct {
+	net.Listener
+	trusted utils.TrustedProxies
+}
+
+func (self *ProxyProtocolListener) Accept() (net.Conn, error) {
+	conn, err := self.Listener.Accept()
+	if err != nil {
+		return conn, err
+	}
+
+	if !self.trusted.IsTrusted(conn.RemoteAddr().String()) {
+		return conn, nil
+	}
+
+	// The header is parsed lazily in the connection's own goroutine
+	// so a slow proxy can not block the accept loop.
+	return &proxyProtocolConn{
+		Conn:   conn,
+		reader: bufio.NewReader(conn),
+	}, nil
+}
+
+func NewProxyProtocolListener(
+	listener net.Listener, trusted utils.TrustedProxies) net.Listener {
+	return &ProxyProtocolListener{
+		Listener: listener,
+		trusted:  trusted,
+	}
+}
+
+type proxyProtocolConn struct {
+	net.Conn
+
+	once        sync.Once
+	reader      *bufio.Reader
+	remote_addr net.Addr
+	err         error
+}
+
+func (self *proxyProtocolConn) parseHeader() {
+	self.once.Do(func() {
+		_ = self.Conn.SetReadDeadline(
+			utils.GetTime().Now().Add(proxyHeaderTimeout))
+		defer self.Conn.SetReadDeadline(time.Time{})
+
+		self.remote_addr, self.err = readProxyHeader(self.reader)
+		if self.err != nil {
+			self.Conn.Close()
+		}
+	})
+}
+
+func (self *proxyProtocolConn) Read(b []byte) (int, error) {
+	self.parseHeader()
+	if self.err != nil {
+		return 0, self.err
+	}
+	return self.reader.Read(b)
+}
+
+func (self *proxyProtocolConn) RemoteAddr() net.Addr {
+	self.parseHeader()
+	if self.remote_addr != nil {
+		return self.remote_addr
+	}
+	return self.Conn.RemoteAddr()
+}
+
+// Returns the address of the original client, or nil if the header
+// does not carry one (e.g. a health check from the proxy itself).
+func readProxyHeader(reader *bufio.Reader) (net.Addr, error) {
+	signature, err := reader.Peek(len(proxyV2Signature))
+	if err == nil && bytes.Equal(signature, proxyV2Signature) {
+		return readProxyHeaderV2(reader)
+	}
+
+	prefix, err := reader.Peek(6)
+	if err == nil && string(prefix) == "PROXY " {
+		return readProxyHeaderV1(reader)
+	}
+
+	// Trusted proxies must always send a header.
+	return nil, invalidProxyHeaderError
+}
+
+// PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n
+func readProxyHeaderV1(reader *bufio.Reader) (net.Addr, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	// The header is at most 107 bytes.
+	if len(line) > 107 || !strings.HasSuffix(line, "\r\n") {
+		return nil, invalidProxyHeaderError
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+
+	if len(fields) != 6 {
+		return nil, invalidProxyHeaderError
+	}
+
+	ip := net.ParseIP(fields[2])
+	port, err := strconv.Atoi(fields[4])
+	if ip == nil || err != nil || port < 0 || port > 65535 {
+		return nil, invalidProxyHeaderError
+	}
+
+	return &net.TCPAddr{IP: ip, Port: port}, nil
+}
+
+func readProxyHeaderV2(reader *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, 16)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return nil, err
+	}
+
+	version := header[12] >> 4
+	command := header[12] & 0x0F
+	family := header[13] >> 4
+	length := binary.BigEndian.Uint16(header[14:16])
+
+	if version != 2 {
+		return nil, invalidProxyHeaderError
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// LOCAL command - the connection is from the proxy itself.
+	if command == 0 {
+		return nil, nil
+	}
+
+	switch family {
+	case 1: // AF_INET
+		if len(payload) < 12 {
+			return nil, invalidProxyHeaderError
+		}
+		return &net.TCPAddr{
+			IP:   net.IP(payload[0:4]),
+			Port: int(binary.BigEndian.Uint16(payload[8:10])),
+		}, nil
+
+	case 2: // AF_INET6
+		if len(payload) < 36 {
+			return nil, invalidProxyHeaderError
+		}
+		return &net.TCPAddr{
+			IP:   net.IP(payload[0:16]),
+			Port: int(binary.BigEndian.Uint16(payload[32:34])),
+		}, nil
+
+	case 0: // AF_UNSPEC
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unsupported address family %v",
+			invalidProxyHeaderError, family)
+	}
+}
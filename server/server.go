@@ -22,6 +22,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"runtime"
 	"sync"
 	"time"
@@ -36,11 +37,16 @@ import (
 	crypto_server "www.velocidex.com/golang/velociraptor/crypto/server"
 	"www.velocidex.com/golang/velociraptor/flows"
 	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/server/connections"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/tracing"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 type Server struct {
+	// Accessed atomically so must be 64 bit aligned.
+	in_flight int64
+
 	manager *crypto_server.ServerCryptoManager
 	logger  *logging.LogContext
 
@@ -50,10 +56,27 @@ type Server struct {
 	reader_concurrency  *utils.Concurrency
 	concurrency_timeout time.Duration
 	throttler           *utils.Throttler
+	admission           *AdmissionControl
+
+	// Reverse proxies whose forwarded headers we trust.
+	trusted_proxies utils.TrustedProxies
+	proxy_header    string
+	proxy_protocol  bool
+
+	// Connection level allow/deny lists and temporary bans.
+	access *AccessControl
 
 	// The server dynamically adjusts concurrency. This signals exit.
 	done chan bool
 
+	// Closed when the frontend is draining.
+	draining chan bool
+
+	// Cached readiness checks.
+	health_mu      sync.Mutex
+	health_checked time.Time
+	health_checks  []HealthCheck
+
 	Bucket  *ratelimit.Bucket
 	Healthy int32
 }
@@ -72,8 +95,23 @@ func (self *Server) ReaderConcurrency() *utils.Concurrency {
 	return self.reader_concurrency
 }
 
+func (self *Server) Admission() *AdmissionControl {
+	return self.admission
+}
+
+func (self *Server) Access() *AccessControl {
+	return self.access
+}
+
+// The client's address, taking trusted reverse proxies into
+// account.
+func (self *Server) RemoteAddr(req *http.Request) string {
+	return self.trusted_proxies.RemoteAddr(req, self.proxy_header)
+}
+
 func (self *Server) Close() {
 	close(self.done)
+	self.access.Close()
 	if self.throttler != nil {
 		self.throttler.Close()
 	}
@@ -111,13 +149,44 @@ func NewServer(ctx context.Context,
 		logger:              logging.GetLogger(config_obj, &logging.FrontendComponent),
 		concurrency_timeout: time.Duration(concurrency_timeout) * time.Second,
 		done:                make(chan bool),
+		draining:            make(chan bool),
 	}
 
 	result.concurrency = utils.NewConcurrencyControl(
 		int(concurrency), result.concurrency_timeout)
 
+	reader_concurrency := config_obj.Frontend.Resources.ReaderConcurrency
+	if reader_concurrency == 0 {
+		reader_concurrency = 100
+	}
+
 	result.reader_concurrency = utils.NewConcurrencyControl(
-		int(100), result.concurrency_timeout)
+		int(reader_concurrency), result.concurrency_timeout)
+
+	result.admission = NewAdmissionControl(config_obj, concurrency)
+
+	result.trusted_proxies, err = utils.ParseTrustedProxies(
+		config_obj.Frontend.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	result.proxy_header = config_obj.Frontend.ProxyHeader
+	result.proxy_protocol = config_obj.Frontend.ProxyProtocol
+
+	if result.proxy_protocol && len(result.trusted_proxies) == 0 {
+		return nil, errors.New(
+			"Frontend.proxy_protocol requires Frontend.trusted_proxies")
+	}
+
+	result.access, err = NewAccessControl(ctx, config_obj, "frontend",
+		config_obj.Frontend.AccessControl)
+	if err != nil {
+		return nil, fmt.Errorf("Frontend.access_control: %w", err)
+	}
+	result.access.trusted = result.trusted_proxies
+	result.access.proxy_header = result.proxy_header
+
+	connections.Tracker.SetConcurrency(result.concurrency)
 
 	if config_obj.Frontend.Resources.ConnectionsPerSecond > 0 {
 		result.logger.Info("Throttling connections to %v QPS",
@@ -211,6 +280,11 @@ func (self *Server) Process(
 
 	// json.TraceMessage(message_info.Source, message_info)
 
+	ctx, span := tracing.Start(ctx, "frontend.Process",
+		tracing.String("client_id", message_info.Source),
+		tracing.String("org_id", message_info.OrgId))
+	defer span.End()
+
 	org_manager, err := services.GetOrgManager()
 	if err != nil {
 		return nil, 0, err
@@ -238,6 +312,7 @@ func (self *Server) Process(
 	}
 
 	if err != nil {
+		span.RecordError(err)
 		return nil, 0, err
 	}
 
@@ -274,6 +349,7 @@ func (self *Server) Process(
 		if err == nil {
 			message_list.Job = append(message_list.Job, tasks...)
 		}
+		span.SetAttributes(tracing.Int("tasks", int64(len(message_list.Job))))
 	}
 
 	/*
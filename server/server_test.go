@@ -2,11 +2,17 @@ package server_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -782,6 +788,246 @@ func (self *ServerTestSuite) TestUnknownFlow() {
 	assert.NoError(t, err)
 }
 
+func (self *ServerTestSuite) TestAdmissionControl() {
+	config_obj := self.ConfigObj
+	config_obj.Frontend.Resources.PostsPerSecond = 1
+	config_obj.Frontend.Resources.PostsBurst = 2
+	config_obj.Frontend.Resources.EnrollmentsPerSecond = 1
+	config_obj.Frontend.Resources.EnrollmentsBurst = 1
+	config_obj.Frontend.Resources.MaxQueueLength = 1
+	config_obj.Frontend.Resources.RetryAfter = 5
+
+	admission := server.NewAdmissionControl(config_obj, 2)
+
+	// Token buckets allow a burst and then reject.
+	assert.True(self.T(), admission.AdmitPost())
+	assert.True(self.T(), admission.AdmitPost())
+	require.False(self.T(), admission.AdmitPost())
+
+	assert.True(self.T(), admission.AdmitEnrollment())
+	require.False(self.T(), admission.AdmitEnrollment())
+
+	// Event batches get half of the concurrency.
+	release, ok := admission.AdmitEvents()
+	assert.True(self.T(), ok)
+
+	_, ok = admission.AdmitEvents()
+	require.False(self.T(), ok)
+
+	release()
+	_, ok = admission.AdmitEvents()
+	assert.True(self.T(), ok)
+
+	// Requests are rejected when too many are waiting for a slot.
+	pool := utils.NewConcurrencyControl(1, time.Second)
+	end, err := admission.Queue(self.Ctx, pool)
+	assert.NoError(self.T(), err)
+	defer end()
+
+	ctx, cancel := context.WithCancel(self.Ctx)
+	go admission.Queue(ctx, pool)
+	defer cancel()
+
+	// Do not wait for a slot while polling.
+	poll_ctx, poll_cancel := context.WithCancel(self.Ctx)
+	poll_cancel()
+
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		_, err := admission.Queue(poll_ctx, pool)
+		return err != nil && err.Error() == "Frontend queue is full"
+	})
+
+	// Rejected clients are told when to come back.
+	w := httptest.NewRecorder()
+	admission.Reject(w, "queue")
+	assert.Equal(self.T(), http.StatusServiceUnavailable, w.Code)
+	assert.Equal(self.T(), "5", w.Header().Get("Retry-After"))
+}
+
+func (self *ServerTestSuite) TestProxyProtocol() {
+	trusted, err := utils.ParseTrustedProxies([]string{"127.0.0.1"})
+	assert.NoError(self.T(), err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(self.T(), err)
+	defer ln.Close()
+
+	listener := server.NewProxyProtocolListener(ln, trusted)
+
+	for _, header := range []string{
+		"PROXY TCP4 1.2.3.4 5.6.7.8 1000 443\r\n",
+
+		// Version 2 header for the same addresses.
+		"\r\n\r\n\x00\r\nQUIT\n\x21\x11\x00\x0c" +
+			"\x01\x02\x03\x04\x05\x06\x07\x08\x03\xe8\x01\xbb",
+	} {
+		client, err := net.Dial("tcp", ln.Addr().String())
+		assert.NoError(self.T(), err)
+
+		_, err = client.Write([]byte(header + "hello"))
+		assert.NoError(self.T(), err)
+
+		conn, err := listener.Accept()
+		assert.NoError(self.T(), err)
+
+		assert.Equal(self.T(), "1.2.3.4:1000", conn.RemoteAddr().String())
+
+		buf := make([]byte, 5)
+		_, err = io.ReadFull(conn, buf)
+		assert.NoError(self.T(), err)
+		assert.Equal(self.T(), "hello", string(buf))
+
+		conn.Close()
+		client.Close()
+	}
+}
+
+func (self *ServerTestSuite) TestAccessControl() {
+	closer := utils.MockTime(utils.NewMockClock(time.Unix(1700000000, 0)))
+	defer closer()
+
+	access, err := server.NewAccessControl(self.Sm.Ctx, self.ConfigObj,
+		"frontend", &config_proto.ListenerAccessControl{
+			AllowedCidr:  []string{"10.0.0.0/8", "127.0.0.1"},
+			DeniedCidr:   []string{"10.1.0.0/16"},
+			BanThreshold: 3,
+			BanDuration:  60,
+		})
+	assert.NoError(self.T(), err)
+
+	assert.True(self.T(), access.Allow("10.2.3.4:443"))
+	assert.True(self.T(), !access.Allow("10.1.2.3:443"))
+	assert.True(self.T(), !access.Allow("8.8.8.8:443"))
+
+	// Non IP addresses (e.g. unix sockets) are not checked.
+	assert.True(self.T(), access.Allow("@"))
+
+	// Repeated failures result in a temporary ban.
+	access.RecordFailure("10.2.3.4:1000", "crypto")
+	access.RecordFailure("10.2.3.4:1001", "crypto")
+	assert.True(self.T(), access.Allow("10.2.3.4:443"))
+
+	access.RecordFailure("10.2.3.4:1002", "enrollment")
+	assert.True(self.T(), !access.Allow("10.2.3.4:443"))
+	assert.True(self.T(), access.Allow("10.2.3.5:443"))
+
+	utils.GetTime().Sleep(61 * time.Second)
+	assert.True(self.T(), access.Allow("10.2.3.4:443"))
+
+	// Banned connections are closed as soon as they are accepted.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(self.T(), err)
+
+	listener := access.WrapListener(ln)
+	accepted := make(chan net.Conn)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				close(accepted)
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	assert.NoError(self.T(), err)
+	conn := <-accepted
+	conn.Close()
+	client.Close()
+
+	for i := 0; i < 3; i++ {
+		access.RecordFailure("127.0.0.1", "crypto")
+	}
+
+	client, err = net.Dial("tcp", ln.Addr().String())
+	assert.NoError(self.T(), err)
+	_, err = client.Read(make([]byte, 1))
+	assert.Error(self.T(), err)
+	client.Close()
+
+	ln.Close()
+	_, ok := <-accepted
+	assert.True(self.T(), !ok)
+}
+
+func (self *ServerTestSuite) TestHealthChecks() {
+	mux := http.NewServeMux()
+	err := server.PrepareFrontendMux(self.ConfigObj, self.server, mux)
+	assert.NoError(self.T(), err)
+
+	get := func(path string) (int, *server.HealthStatus) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		status := &server.HealthStatus{}
+		if w.Body.Len() > 0 {
+			assert.NoError(self.T(), json.Unmarshal(w.Body.Bytes(), status))
+		}
+		return w.Code, status
+	}
+
+	// The frontend listener is not started yet.
+	code, status := get("/healthz")
+	assert.Equal(self.T(), http.StatusServiceUnavailable, code)
+	assert.Equal(self.T(), "listener", status.Checks[0].Name)
+	assert.Equal(self.T(), "Frontend is not started", status.Checks[0].Error)
+
+	atomic.StoreInt32(&self.server.Healthy, 1)
+	defer atomic.StoreInt32(&self.server.Healthy, 0)
+
+	code, _ = get("/healthz")
+	assert.Equal(self.T(), http.StatusNoContent, code)
+
+	code, status = get("/readyz")
+	assert.Equal(self.T(), http.StatusOK, code)
+	assert.True(self.T(), status.Ready)
+	assert.Equal(self.T(), 4, len(status.Checks))
+
+	// Draining fails readiness but not liveness.
+	drain := server.DrainHandler(self.server)
+	w := httptest.NewRecorder()
+	drain.ServeHTTP(w, httptest.NewRequest("POST", "/drain", nil))
+	assert.Equal(self.T(), http.StatusOK, w.Code)
+	assert.True(self.T(), self.server.IsDraining())
+
+	code, status = get("/readyz")
+	assert.Equal(self.T(), http.StatusServiceUnavailable, code)
+	assert.True(self.T(), status.Draining)
+
+	code, _ = get("/healthz")
+	assert.Equal(self.T(), http.StatusNoContent, code)
+
+	// New connections are rejected while draining.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(self.T(), err)
+	listener := self.server.WrapListener(ln)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	assert.NoError(self.T(), err)
+	_, err = client.Read(make([]byte, 1))
+	assert.Error(self.T(), err)
+	client.Close()
+	ln.Close()
+
+	w = httptest.NewRecorder()
+	drain.ServeHTTP(w, httptest.NewRequest("DELETE", "/drain", nil))
+	assert.Equal(self.T(), http.StatusOK, w.Code)
+	assert.True(self.T(), !self.server.IsDraining())
+
+	code, _ = get("/readyz")
+	assert.Equal(self.T(), http.StatusOK, code)
+}
+
 func TestServerTestSuite(t *testing.T) {
 	suite.Run(t, new(ServerTestSuite))
 }
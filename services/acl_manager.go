@@ -168,6 +168,15 @@ func CheckAccessWithToken(
 
 	case acls.DATASTORE_ACCESS:
 		return token.DatastoreAccess, nil
+
+	case acls.REMEDIATION:
+		return token.Remediation, nil
+
+	case acls.APPROVE_COLLECTION:
+		return token.ApproveCollection, nil
+
+	case acls.VIEW_PII:
+		return token.ViewPii, nil
 	}
 
 	return false, nil
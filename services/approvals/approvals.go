@@ -0,0 +1,547 @@
+/*
+  The approval service implements the four-eyes principle for
+  collections and hunts launched from the GUI.
+
+  When approvals are enabled (Defaults.approvals), collections of
+  dangerous artifacts - those requiring EXECVE or REMEDIATION by
+  default, or listed explicitly - and hunts which may run on more
+  clients than the configured threshold are not launched
+  immediately. Instead a request is recorded and a second user with
+  the APPROVE_COLLECTION permission must approve it. The requester may
+  never approve their own request.
+
+  Approved requests are launched with the requester's permissions so
+  approval never grants the requester more access than they already
+  have.
+
+  Requests are stored in the datastore, announced on the
+  Server.Internal.ApprovalRequests queue (so they can be forwarded to
+  approvers, e.g. by a webhook) and written to the audit log.
+*/
+
+package approvals
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	TYPE_COLLECTION = "collection"
+	TYPE_HUNT       = "hunt"
+
+	STATE_PENDING  = "pending"
+	STATE_APPROVED = "approved"
+	STATE_DENIED   = "denied"
+	STATE_EXPIRED  = "expired"
+	STATE_FAILED   = "failed"
+
+	// Requests and decisions are announced on this queue.
+	REQUESTS_ARTIFACT = "Server.Internal.ApprovalRequests"
+
+	// In seconds
+	DEFAULT_EXPIRY = 86400
+)
+
+var (
+	mu         sync.Mutex
+	g_services = make(map[string]*ApprovalService)
+
+	notFoundError = errors.New("Approval request not found")
+
+	defaultPermissions = []string{"EXECVE", "REMEDIATION"}
+)
+
+// A collection or hunt awaiting approval.
+type Request struct {
+	Id        string `json:"id"`
+	Type      string `json:"type"`
+	Requester string `json:"requester"`
+
+	// Why the request needs approval.
+	Reasons []string `json:"reasons"`
+
+	// A summary of the request for approvers.
+	ClientId         string   `json:"client_id,omitempty"`
+	Artifacts        []string `json:"artifacts"`
+	Description      string   `json:"description,omitempty"`
+	EstimatedClients uint64   `json:"estimated_clients,omitempty"`
+
+	// The serialized flows_proto.ArtifactCollectorArgs or
+	// api_proto.Hunt to launch once approved.
+	Collection json.RawMessage `json:"collection,omitempty"`
+	Hunt       json.RawMessage `json:"hunt,omitempty"`
+
+	State   string `json:"state"`
+	Created int64  `json:"created"`
+
+	Approver string `json:"approver,omitempty"`
+	Decided  int64  `json:"decided,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+
+	// The flow or hunt launched when the request was approved.
+	FlowId string `json:"flow_id,omitempty"`
+	HuntId string `json:"hunt_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ApprovalService struct {
+	mu         sync.Mutex
+	config_obj *config_proto.Config
+
+	requests map[string]*Request
+
+	// Collections of artifacts requiring these permissions need
+	// approval.
+	permissions map[string]bool
+	artifacts   map[string]bool
+}
+
+// Get the approval service for the org. Returns nil if approvals are
+// not enabled.
+func GetApprovalService(config_obj *config_proto.Config) *ApprovalService {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return g_services[utils.NormalizedOrgId(config_obj.OrgId)]
+}
+
+func NewApprovalService(config_obj *config_proto.Config) *ApprovalService {
+	self := &ApprovalService{
+		config_obj:  config_obj,
+		requests:    make(map[string]*Request),
+		permissions: make(map[string]bool),
+		artifacts:   make(map[string]bool),
+	}
+
+	approval_config := config_obj.GetDefaults().GetApprovals()
+	permissions := approval_config.GetPermissions()
+	if len(permissions) == 0 {
+		permissions = defaultPermissions
+	}
+
+	for _, perm := range permissions {
+		self.permissions[strings.ToUpper(perm)] = true
+	}
+
+	for _, artifact := range approval_config.GetArtifacts() {
+		self.artifacts[artifact] = true
+	}
+
+	return self
+}
+
+func newRequestId() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "A." + base32.HexEncoding.EncodeToString(buf)[:13]
+}
+
+func getRawDB(config_obj *config_proto.Config) (
+	datastore.DataStore, datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, nil, errors.New("Datastore does not support raw access")
+	}
+	return db, raw_db, nil
+}
+
+// Load all requests from the datastore.
+func (self *ApprovalService) Load() error {
+	db, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	children, err := db.ListChildren(self.config_obj, paths.APPROVALS_ROOT)
+	if err != nil {
+		return err
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		data, err := raw_db.GetBuffer(self.config_obj, child)
+		if err != nil {
+			continue
+		}
+
+		request := &Request{}
+		err = json.Unmarshal(data, request)
+		if err != nil || request.Id == "" {
+			continue
+		}
+		self.requests[request.Id] = request
+	}
+
+	return nil
+}
+
+func (self *ApprovalService) save(request *Request) error {
+	_, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(self.config_obj, paths.ApprovalPath(request.Id),
+		serialized, utils.SyncCompleter)
+}
+
+func copyRequest(request *Request) *Request {
+	result := *request
+	result.Reasons = append([]string{}, request.Reasons...)
+	result.Artifacts = append([]string{}, request.Artifacts...)
+	return &result
+}
+
+// Pending requests which were not decided in time expire. Called
+// with the lock held.
+func (self *ApprovalService) expire(request *Request, now time.Time) {
+	if request.State != STATE_PENDING {
+		return
+	}
+
+	expiry := int64(DEFAULT_EXPIRY)
+	approval_config := self.config_obj.GetDefaults().GetApprovals()
+	if approval_config.GetExpirySec() > 0 {
+		expiry = int64(approval_config.ExpirySec)
+	}
+
+	if now.Unix()-request.Created >= expiry {
+		request.State = STATE_EXPIRED
+		request.Decided = now.Unix()
+		_ = self.save(request)
+	}
+}
+
+// Get a copy of the request.
+func (self *ApprovalService) Get(id string) (*Request, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	request, pres := self.requests[id]
+	if !pres {
+		return nil, notFoundError
+	}
+	self.expire(request, utils.GetTime().Now())
+
+	return copyRequest(request), nil
+}
+
+// List all requests, most recent first.
+func (self *ApprovalService) List() []*Request {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	now := utils.GetTime().Now()
+	result := make([]*Request, 0, len(self.requests))
+	for _, request := range self.requests {
+		self.expire(request, now)
+		result = append(result, copyRequest(request))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Created == result[j].Created {
+			return result[i].Id < result[j].Id
+		}
+		return result[i].Created > result[j].Created
+	})
+	return result
+}
+
+// Returns the reasons the collection needs approval. The collection
+// may be launched immediately if there are none.
+func (self *ApprovalService) CheckCollection(ctx context.Context,
+	request *flows_proto.ArtifactCollectorArgs) ([]string, error) {
+	manager, err := services.GetRepositoryManager(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var reasons []string
+	for _, name := range request.Artifacts {
+		if self.artifacts[name] {
+			reasons = append(reasons, fmt.Sprintf(
+				"artifact %v requires approval", name))
+			continue
+		}
+
+		artifact, pres := repository.Get(ctx, self.config_obj, name)
+		if !pres {
+			continue
+		}
+
+		// The permissions the artifact declares, directly or
+		// through its capabilities.
+		var permissions []string
+		for _, perm := range artifact.RequiredPermissions {
+			permissions = append(permissions, strings.ToUpper(perm))
+		}
+
+		for _, capability := range artifact.RequiredCapabilities {
+			permission, pres := acls.GetCapabilityPermission(capability)
+			if pres {
+				permissions = append(permissions, permission.String())
+			}
+		}
+
+		for _, perm := range permissions {
+			if self.permissions[perm] {
+				reasons = append(reasons, fmt.Sprintf(
+					"artifact %v requires %v", name, perm))
+				break
+			}
+		}
+	}
+
+	return reasons, nil
+}
+
+// Returns the reasons the hunt needs approval.
+func (self *ApprovalService) CheckHunt(ctx context.Context,
+	hunt *api_proto.Hunt) ([]string, uint64, error) {
+	var reasons []string
+
+	if hunt.StartRequest != nil {
+		collection_reasons, err := self.CheckCollection(ctx, hunt.StartRequest)
+		if err != nil {
+			return nil, 0, err
+		}
+		reasons = append(reasons, collection_reasons...)
+	}
+
+	threshold := self.config_obj.GetDefaults().GetApprovals().GetHuntClientThreshold()
+	if threshold == 0 {
+		return reasons, 0, nil
+	}
+
+	estimate, err := self.estimateHuntSize(ctx, hunt)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if estimate > threshold {
+		reasons = append(reasons, fmt.Sprintf(
+			"hunt may run on %v clients (more than %v)", estimate, threshold))
+	}
+
+	return reasons, estimate, nil
+}
+
+// Estimate how many clients the hunt may run on. This errs on the
+// side of caution: the OS condition and excluded labels are
+// ignored.
+func (self *ApprovalService) estimateHuntSize(ctx context.Context,
+	hunt *api_proto.Hunt) (uint64, error) {
+	indexer, err := services.GetIndexer(self.config_obj)
+	if err != nil {
+		return 0, err
+	}
+
+	terms := []string{"all"}
+	labels := hunt.GetCondition().GetLabels().GetLabel()
+	if len(labels) > 0 {
+		terms = nil
+		for _, label := range labels {
+			terms = append(terms, "label:"+strings.ToLower(label))
+		}
+	}
+
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clients := make(map[string]bool)
+	for _, term := range terms {
+		for hit := range indexer.SearchIndexWithPrefix(
+			sub_ctx, self.config_obj, term) {
+			if hit != nil && hit.Term == term {
+				clients[hit.Entity] = true
+			}
+		}
+	}
+
+	result := uint64(len(clients))
+	if hunt.ClientLimit > 0 && hunt.ClientLimit < result {
+		result = hunt.ClientLimit
+	}
+	return result, nil
+}
+
+// Hold the collection until it is approved.
+func (self *ApprovalService) RequestCollection(ctx context.Context,
+	principal string, collection *flows_proto.ArtifactCollectorArgs,
+	reasons []string) (*Request, error) {
+	serialized, err := json.Marshal(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.newRequest(ctx, &Request{
+		Type:       TYPE_COLLECTION,
+		Requester:  principal,
+		Reasons:    reasons,
+		ClientId:   collection.ClientId,
+		Artifacts:  collection.Artifacts,
+		Collection: serialized,
+	})
+}
+
+// Hold the hunt until it is approved.
+func (self *ApprovalService) RequestHunt(ctx context.Context,
+	principal string, hunt *api_proto.Hunt,
+	reasons []string, estimated_clients uint64) (*Request, error) {
+	serialized, err := json.Marshal(hunt)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.newRequest(ctx, &Request{
+		Type:             TYPE_HUNT,
+		Requester:        principal,
+		Reasons:          reasons,
+		Artifacts:        hunt.GetStartRequest().GetArtifacts(),
+		Description:      hunt.HuntDescription,
+		EstimatedClients: estimated_clients,
+		Hunt:             serialized,
+	})
+}
+
+func (self *ApprovalService) newRequest(
+	ctx context.Context, request *Request) (*Request, error) {
+	request.Id = newRequestId()
+	request.State = STATE_PENDING
+	request.Created = utils.GetTime().Now().Unix()
+
+	self.mu.Lock()
+	self.requests[request.Id] = request
+	err := self.save(request)
+	result := copyRequest(request)
+	self.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = services.LogAudit(ctx, self.config_obj, request.Requester,
+		"ApprovalRequest", ordereddict.NewDict().
+			Set("request_id", result.Id).
+			Set("type", result.Type).
+			Set("client_id", result.ClientId).
+			Set("artifacts", result.Artifacts).
+			Set("reasons", result.Reasons))
+	if err != nil {
+		return nil, err
+	}
+
+	return self.announce(ctx, result)
+}
+
+// Announce the request on the requests queue.
+func (self *ApprovalService) announce(
+	ctx context.Context, request *Request) (*Request, error) {
+	journal_service, err := services.GetJournal(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	err = journal_service.PushRowsToArtifact(ctx, self.config_obj,
+		[]*ordereddict.Dict{RequestToRow(request)},
+		REQUESTS_ARTIFACT, "server", "")
+	return request, err
+}
+
+func RequestToRow(request *Request) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("RequestId", request.Id).
+		Set("Type", request.Type).
+		Set("Requester", request.Requester).
+		Set("Reasons", request.Reasons).
+		Set("ClientId", request.ClientId).
+		Set("Artifacts", request.Artifacts).
+		Set("Description", request.Description).
+		Set("EstimatedClients", request.EstimatedClients).
+		Set("State", request.State).
+		Set("Created", time.Unix(request.Created, 0).UTC()).
+		Set("Approver", request.Approver).
+		Set("Comment", request.Comment).
+		Set("FlowId", request.FlowId).
+		Set("HuntId", request.HuntId).
+		Set("Error", request.Error)
+}
+
+func StartApprovalService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if !config_obj.GetDefaults().GetApprovals().GetEnabled() {
+		return nil
+	}
+
+	self := NewApprovalService(config_obj)
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Approval service for %v.",
+		services.GetOrgName(config_obj))
+
+	err := self.Load()
+	if err != nil {
+		logger.Debug("ApprovalService: No requests loaded: %v", err)
+	}
+
+	org_id := utils.NormalizedOrgId(config_obj.OrgId)
+	mu.Lock()
+	g_services[org_id] = self
+	mu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		mu.Lock()
+		delete(g_services, org_id)
+		mu.Unlock()
+	}()
+
+	return nil
+}
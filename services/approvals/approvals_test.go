@@ -0,0 +1,219 @@
+package approvals_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/approvals"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type ApprovalTestSuite struct {
+	test_utils.TestSuite
+}
+
+var mock_definitions = []string{`
+name: Server.Internal.ApprovalRequests
+type: INTERNAL
+`, `
+name: Generic.Client.Info
+sources:
+- query: SELECT * FROM scope()
+`, `
+name: Windows.System.PowerShell
+required_permissions:
+- EXECVE
+sources:
+- query: SELECT * FROM scope()
+`, `
+name: Windows.Remediation.Quarantine
+sources:
+- query: SELECT * FROM scope()
+`}
+
+func (self *ApprovalTestSuite) SetupTest() {
+	self.ConfigObj = self.TestSuite.LoadConfig()
+	self.ConfigObj.Services.HuntDispatcher = true
+	self.ConfigObj.Defaults.Approvals = &config_proto.ApprovalConfig{
+		Enabled:             true,
+		Artifacts:           []string{"Windows.Remediation.Quarantine"},
+		HuntClientThreshold: 1,
+	}
+	self.LoadArtifactsIntoConfig(mock_definitions)
+
+	self.TestSuite.SetupTest()
+
+	for user, role := range map[string]string{
+		"alice": "investigator",
+		"bob":   "reader",
+		"carol": "approver",
+	} {
+		assert.NoError(self.T(),
+			services.GrantRoles(self.ConfigObj, user, []string{role}))
+	}
+
+	indexer, err := services.GetIndexer(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.NoError(self.T(), indexer.SetIndex("C.1", "all"))
+	assert.NoError(self.T(), indexer.SetIndex("C.2", "all"))
+	assert.NoError(self.T(), indexer.SetIndex("C.2", "label:servers"))
+}
+
+func (self *ApprovalTestSuite) TestCollections() {
+	clock := utils.NewMockClock(time.Unix(1700000000, 0))
+	closer := utils.MockTime(clock)
+	defer closer()
+
+	service := approvals.NewApprovalService(self.ConfigObj)
+
+	// Harmless collections are launched directly.
+	reasons, err := service.CheckCollection(self.Ctx,
+		&flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{"Generic.Client.Info"},
+		})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(reasons))
+
+	for _, artifact := range []string{
+		"Windows.System.PowerShell", "Windows.Remediation.Quarantine"} {
+		reasons, err = service.CheckCollection(self.Ctx,
+			&flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info", artifact},
+			})
+		assert.NoError(self.T(), err)
+		assert.Equal(self.T(), 1, len(reasons), artifact)
+	}
+
+	request, err := service.RequestCollection(self.Ctx, "alice",
+		&flows_proto.ArtifactCollectorArgs{
+			Creator:   "alice",
+			ClientId:  "C.1",
+			Artifacts: []string{"Windows.Remediation.Quarantine"},
+		}, reasons)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), approvals.STATE_PENDING, request.State)
+
+	// The requester may not approve their own request and readers
+	// may not approve at all.
+	for _, user := range []string{"alice", "bob"} {
+		_, err = service.Decide(self.Ctx, user, request.Id, true, "")
+		assert.Error(self.T(), err, user)
+	}
+
+	approved, err := service.Decide(self.Ctx, "carol", request.Id, true, "ok")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), approvals.STATE_APPROVED, approved.State)
+	assert.Equal(self.T(), "carol", approved.Approver)
+	assert.Equal(self.T(), "", approved.Error)
+	assert.True(self.T(), approved.FlowId != "")
+
+	// The flow is launched on behalf of the requester.
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	details, err := launcher.GetFlowDetails(
+		self.Ctx, self.ConfigObj, "C.1", approved.FlowId)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "alice", details.Context.Request.Creator)
+
+	// A request is only decided once.
+	_, err = service.Decide(self.Ctx, "carol", request.Id, false, "")
+	assert.Error(self.T(), err)
+
+	// Pending requests expire.
+	clock.Set(time.Unix(1700000010, 0))
+	request, err = service.RequestCollection(self.Ctx, "alice",
+		&flows_proto.ArtifactCollectorArgs{
+			ClientId:  "C.1",
+			Artifacts: []string{"Windows.System.PowerShell"},
+		}, reasons)
+	assert.NoError(self.T(), err)
+
+	clock.Set(time.Unix(1700000010+86400, 0))
+	_, err = service.Decide(self.Ctx, "carol", request.Id, true, "")
+	assert.Error(self.T(), err)
+
+	// Requests survive a restart.
+	restored := approvals.NewApprovalService(self.ConfigObj)
+	assert.NoError(self.T(), restored.Load())
+
+	requests := restored.List()
+	assert.Equal(self.T(), 2, len(requests))
+	assert.Equal(self.T(), approvals.STATE_EXPIRED, requests[0].State)
+	assert.Equal(self.T(), approved.FlowId, requests[1].FlowId)
+}
+
+func (self *ApprovalTestSuite) TestHunts() {
+	service := approvals.NewApprovalService(self.ConfigObj)
+
+	hunt := &api_proto.Hunt{
+		HuntDescription: "Inventory",
+		StartRequest: &flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{"Generic.Client.Info"},
+		},
+		State: api_proto.Hunt_RUNNING,
+	}
+
+	// Both clients may be scheduled.
+	reasons, estimate, err := service.CheckHunt(self.Ctx, hunt)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(2), estimate)
+	assert.Equal(self.T(), 1, len(reasons))
+
+	// Small hunts do not need approval.
+	for _, small := range []*api_proto.Hunt{{
+		StartRequest: hunt.StartRequest,
+		ClientLimit:  1,
+	}, {
+		StartRequest: hunt.StartRequest,
+		Condition: &api_proto.HuntCondition{
+			UnionField: &api_proto.HuntCondition_Labels{
+				Labels: &api_proto.HuntLabelCondition{
+					Label: []string{"Servers"},
+				},
+			},
+		},
+	}} {
+		reasons, estimate, err := service.CheckHunt(self.Ctx, small)
+		assert.NoError(self.T(), err)
+		assert.Equal(self.T(), uint64(1), estimate)
+		assert.Equal(self.T(), 0, len(reasons))
+	}
+
+	request, err := service.RequestHunt(self.Ctx, "alice", hunt, reasons, estimate)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "Inventory", request.Description)
+
+	approved, err := service.Decide(self.Ctx, "carol", request.Id, true, "")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "", approved.Error)
+	assert.True(self.T(), approved.HuntId != "")
+
+	hunt_dispatcher, err := services.GetHuntDispatcher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	created, pres := hunt_dispatcher.GetHunt(approved.HuntId)
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), "Inventory", created.HuntDescription)
+
+	// Denied requests are not launched.
+	request, err = service.RequestHunt(self.Ctx, "alice", hunt, reasons, estimate)
+	assert.NoError(self.T(), err)
+
+	denied, err := service.Decide(self.Ctx, "carol", request.Id, false, "too big")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), approvals.STATE_DENIED, denied.State)
+	assert.Equal(self.T(), "too big", denied.Comment)
+	assert.Equal(self.T(), "", denied.HuntId)
+}
+
+func TestApprovals(t *testing.T) {
+	suite.Run(t, &ApprovalTestSuite{})
+}
@@ -0,0 +1,242 @@
+package approvals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+// Approve or deny a pending request. Approved requests are launched
+// immediately on behalf of the requester.
+func (self *ApprovalService) Decide(ctx context.Context,
+	principal, id string, approve bool, comment string) (*Request, error) {
+	ok, err := services.CheckAccess(self.config_obj, principal,
+		acls.APPROVE_COLLECTION)
+	if !ok || err != nil {
+		return nil, fmt.Errorf(
+			"User %v is not allowed to decide approval requests", principal)
+	}
+
+	now := utils.GetTime().Now()
+
+	self.mu.Lock()
+	request, pres := self.requests[id]
+	if !pres {
+		self.mu.Unlock()
+		return nil, notFoundError
+	}
+
+	self.expire(request, now)
+	if request.State != STATE_PENDING {
+		self.mu.Unlock()
+		return nil, fmt.Errorf("Request %v is %v", id, request.State)
+	}
+
+	// The whole point is that a second person looks at it.
+	if request.Requester == principal {
+		self.mu.Unlock()
+		return nil, errors.New("Users may not decide their own requests")
+	}
+
+	request.Approver = principal
+	request.Decided = now.Unix()
+	request.Comment = comment
+
+	// Mark the request so it is not launched twice.
+	request.State = STATE_APPROVED
+	operation := "ApprovalApprove"
+	if !approve {
+		request.State = STATE_DENIED
+		operation = "ApprovalDeny"
+	}
+	err = self.save(request)
+	result := copyRequest(request)
+	self.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = services.LogAudit(ctx, self.config_obj, principal,
+		operation, ordereddict.NewDict().
+			Set("request_id", result.Id).
+			Set("type", result.Type).
+			Set("requester", result.Requester).
+			Set("comment", comment))
+	if err != nil {
+		return nil, err
+	}
+
+	if !approve {
+		return self.announce(ctx, result)
+	}
+
+	return self.launch(ctx, id)
+}
+
+// Launch an approved request and record the flow or hunt started.
+func (self *ApprovalService) launch(
+	ctx context.Context, id string) (*Request, error) {
+	self.mu.Lock()
+	request, pres := self.requests[id]
+	if !pres {
+		self.mu.Unlock()
+		return nil, notFoundError
+	}
+	snapshot := copyRequest(request)
+	self.mu.Unlock()
+
+	var flow_id, hunt_id string
+	var err error
+
+	switch snapshot.Type {
+	case TYPE_COLLECTION:
+		flow_id, err = self.launchCollection(ctx, snapshot)
+	case TYPE_HUNT:
+		hunt_id, err = self.launchHunt(ctx, snapshot)
+	default:
+		err = fmt.Errorf("Unknown request type %q", snapshot.Type)
+	}
+
+	self.mu.Lock()
+	request.FlowId = flow_id
+	request.HuntId = hunt_id
+	if err != nil {
+		request.State = STATE_FAILED
+		request.Error = err.Error()
+	}
+	save_err := self.save(request)
+	result := copyRequest(request)
+	self.mu.Unlock()
+
+	if save_err != nil {
+		return nil, save_err
+	}
+
+	// Attributed to the requester just like a collection launched
+	// directly from the GUI.
+	audit_err := services.LogAudit(ctx, self.config_obj, result.Requester,
+		"ApprovalLaunch", ordereddict.NewDict().
+			Set("request_id", result.Id).
+			Set("approver", result.Approver).
+			Set("client", result.ClientId).
+			Set("flow_id", flow_id).
+			Set("hunt_id", hunt_id).
+			Set("error", result.Error))
+	if audit_err != nil {
+		return nil, audit_err
+	}
+
+	return self.announce(ctx, result)
+}
+
+func (self *ApprovalService) launchCollection(
+	ctx context.Context, request *Request) (string, error) {
+	collection := &flows_proto.ArtifactCollectorArgs{}
+	err := json.Unmarshal(request.Collection, collection)
+	if err != nil {
+		return "", err
+	}
+
+	manager, err := services.GetRepositoryManager(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	launcher, err := services.GetLauncher(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	// The requester's permissions are checked again since they may
+	// have changed since the request was made.
+	acl_manager := acl_managers.NewServerACLManager(
+		self.config_obj, request.Requester)
+
+	return launcher.ScheduleArtifactCollection(
+		ctx, self.config_obj, acl_manager, repository, collection,
+		func() {
+			notifier, err := services.GetNotifier(self.config_obj)
+			if err == nil {
+				notifier.NotifyListener(ctx,
+					self.config_obj, collection.ClientId, "CollectArtifact")
+			}
+		})
+}
+
+// Hunts may span several orgs. They are created in each of them
+// like the API does.
+func (self *ApprovalService) launchHunt(
+	ctx context.Context, request *Request) (string, error) {
+	hunt := &api_proto.Hunt{}
+	err := json.Unmarshal(request.Hunt, hunt)
+	if err != nil {
+		return "", err
+	}
+
+	permission := acls.COLLECT_CLIENT
+	if hunt.State == api_proto.Hunt_RUNNING {
+		permission = acls.START_HUNT
+	}
+
+	org_configs := []*config_proto.Config{self.config_obj}
+	if len(hunt.OrgIds) > 0 {
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			return "", err
+		}
+
+		org_configs = nil
+		for _, org_id := range hunt.OrgIds {
+			org_config_obj, err := org_manager.GetOrgConfig(org_id)
+			if err != nil {
+				return "", err
+			}
+			org_configs = append(org_configs, org_config_obj)
+		}
+	}
+
+	for _, org_config_obj := range org_configs {
+		org_id := services.GetOrgName(org_config_obj)
+		ok, err := services.CheckAccess(
+			org_config_obj, request.Requester, permission)
+		if !ok || err != nil {
+			return "", fmt.Errorf(
+				"User %v is not allowed to launch hunts in org %v",
+				request.Requester, org_id)
+		}
+
+		hunt_dispatcher, err := services.GetHuntDispatcher(org_config_obj)
+		if err != nil {
+			return "", err
+		}
+
+		acl_manager := acl_managers.NewServerACLManager(
+			org_config_obj, request.Requester)
+		new_hunt, err := hunt_dispatcher.CreateHunt(
+			ctx, org_config_obj, acl_manager, hunt)
+		if err != nil {
+			return "", err
+		}
+
+		// Use the same hunt id in all orgs.
+		hunt.HuntId = new_hunt.HuntId
+	}
+
+	return hunt.HuntId, nil
+}
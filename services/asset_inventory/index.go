@@ -0,0 +1,212 @@
+package asset_inventory
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+type SearchOptions struct {
+	// Only search this category (e.g. Software). All categories are
+	// searched if empty.
+	Category string `json:"category"`
+
+	// A regex matched against the name (case insensitive).
+	Name string `json:"name"`
+
+	// Compare the version with this version using the operator
+	// (e.g. "<", ">="). All versions match if empty.
+	Op      string `json:"op"`
+	Version string `json:"version"`
+
+	// Stop after this many results (default 10000).
+	Limit int `json:"limit"`
+}
+
+type SearchResult struct {
+	ClientId string `json:"client_id"`
+	Hostname string `json:"hostname"`
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+}
+
+// An item in the client's inventory.
+type indexEntry struct {
+	name    string
+	version string
+}
+
+// An in memory index of the latest inventory of all clients so we can
+// answer fleet wide questions (e.g. which hosts have OpenSSL < 3.0.7)
+// without reading every client's inventory.
+type FleetIndex struct {
+	mu sync.Mutex
+
+	// category -> lower case name -> client id -> entries
+	items map[string]map[string]map[string][]indexEntry
+
+	// client id -> category -> lower case names so we can remove
+	// the old entries when the client's inventory changes.
+	clients map[string]map[string][]string
+}
+
+// The name and version of a row. Rows without a Version column
+// (e.g. Hardware) are indexed by their Value.
+func rowKey(row *ordereddict.Dict) (name, version string) {
+	name, _ = row.GetString("Name")
+	version, pres := row.GetString("Version")
+	if !pres {
+		version, _ = row.GetString("Value")
+	}
+	return name, version
+}
+
+// Replace the client's entries for the category.
+func (self *FleetIndex) Update(
+	client_id, category string, rows []*ordereddict.Dict) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.remove(client_id, category)
+
+	by_name, pres := self.items[category]
+	if !pres {
+		by_name = make(map[string]map[string][]indexEntry)
+		self.items[category] = by_name
+	}
+
+	names := []string{}
+	for _, row := range rows {
+		name, version := rowKey(row)
+		if name == "" {
+			continue
+		}
+
+		key := strings.ToLower(name)
+		by_client, pres := by_name[key]
+		if !pres {
+			by_client = make(map[string][]indexEntry)
+			by_name[key] = by_client
+		}
+
+		if len(by_client[client_id]) == 0 {
+			names = append(names, key)
+		}
+		by_client[client_id] = append(by_client[client_id], indexEntry{
+			name:    name,
+			version: version,
+		})
+	}
+
+	client_categories, pres := self.clients[client_id]
+	if !pres {
+		client_categories = make(map[string][]string)
+		self.clients[client_id] = client_categories
+	}
+	client_categories[category] = names
+}
+
+func (self *FleetIndex) remove(client_id, category string) {
+	client_categories, pres := self.clients[client_id]
+	if !pres {
+		return
+	}
+
+	by_name := self.items[category]
+	for _, key := range client_categories[category] {
+		by_client, pres := by_name[key]
+		if !pres {
+			continue
+		}
+
+		delete(by_client, client_id)
+		if len(by_client) == 0 {
+			delete(by_name, key)
+		}
+	}
+	delete(client_categories, category)
+}
+
+// Remove all the client's entries, e.g. when the client is deleted.
+func (self *FleetIndex) RemoveClient(client_id string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for category := range self.clients[client_id] {
+		self.remove(client_id, category)
+	}
+	delete(self.clients, client_id)
+}
+
+func (self *FleetIndex) Search(
+	options *SearchOptions) ([]*SearchResult, error) {
+	name_regex, err := regexp.Compile("(?i)" + options.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := options.Limit
+	if limit <= 0 {
+		limit = 10000
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := []*SearchResult{}
+	for category, by_name := range self.items {
+		if options.Category != "" &&
+			!strings.EqualFold(options.Category, category) {
+			continue
+		}
+
+		for key, by_client := range by_name {
+			if !name_regex.MatchString(key) {
+				continue
+			}
+
+			for client_id, entries := range by_client {
+				for _, entry := range entries {
+					if !matchVersion(entry.version,
+						options.Op, options.Version) {
+						continue
+					}
+
+					result = append(result, &SearchResult{
+						ClientId: client_id,
+						Category: category,
+						Name:     entry.name,
+						Version:  entry.version,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ClientId != result[j].ClientId {
+			return result[i].ClientId < result[j].ClientId
+		}
+		if result[i].Category != result[j].Category {
+			return result[i].Category < result[j].Category
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+func NewFleetIndex() *FleetIndex {
+	return &FleetIndex{
+		items:   make(map[string]map[string]map[string][]indexEntry),
+		clients: make(map[string]map[string][]string),
+	}
+}
@@ -0,0 +1,792 @@
+/*
+  The asset inventory service keeps a versioned inventory of each
+  client: installed software, patches, local users, listening
+  services and hardware.
+
+  The inventory is collected with the Generic.Client.Inventory
+  artifact. The master node schedules the artifact on newly
+  interrogated clients and on clients whose inventory is older than
+  the refresh interval. The results of each completed collection are
+  copied into a new version of the client's inventory, and the last
+  few versions are kept so we can see what changed on the client.
+
+  The latest version of all clients is indexed in memory to answer
+  fleet wide searches such as "which hosts have OpenSSL < 3.0.7".
+*/
+
+package asset_inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+const (
+	INVENTORY_ARTIFACT = "Generic.Client.Inventory"
+
+	// New inventory versions are announced on this queue.
+	UPDATES_ARTIFACT = "Server.Internal.AssetInventory"
+
+	SCHEDULE_INTERVAL = time.Hour
+
+	DEFAULT_REFRESH_INTERVAL = int64(7 * 24 * 3600)
+	DEFAULT_MAX_VERSIONS     = 10
+	DEFAULT_MAX_COLLECTIONS  = 1000
+)
+
+var (
+	// The sources of the inventory artifact.
+	Categories = []string{
+		"Software", "Patches", "Users", "ListeningServices", "Hardware"}
+
+	mu         sync.Mutex
+	g_services = make(map[string]*AssetInventoryService)
+
+	notRunningError = errors.New("Asset inventory service not running")
+	notFoundError   = errors.New("No inventory for client")
+)
+
+type InventoryVersion struct {
+	// The flow id of the collection.
+	Version   string `json:"version"`
+	Timestamp int64  `json:"timestamp"`
+
+	// Number of rows in each category.
+	Counts map[string]int `json:"counts"`
+}
+
+type ClientInventory struct {
+	ClientId string `json:"client_id"`
+
+	// Oldest first.
+	Versions []*InventoryVersion `json:"versions"`
+
+	// A refresh collection which has not completed yet.
+	PendingFlowId string `json:"pending_flow_id,omitempty"`
+	PendingSince  int64  `json:"pending_since,omitempty"`
+}
+
+func (self *ClientInventory) Latest() *InventoryVersion {
+	if len(self.Versions) == 0 {
+		return nil
+	}
+	return self.Versions[len(self.Versions)-1]
+}
+
+func (self *ClientInventory) getVersion(version string) (*InventoryVersion, error) {
+	if version == "" {
+		latest := self.Latest()
+		if latest == nil {
+			return nil, notFoundError
+		}
+		return latest, nil
+	}
+
+	for _, item := range self.Versions {
+		if item.Version == version {
+			return item, nil
+		}
+	}
+	return nil, fmt.Errorf("Inventory version %v not found", version)
+}
+
+func copyInventory(record *ClientInventory) *ClientInventory {
+	result := *record
+	result.Versions = append([]*InventoryVersion{}, record.Versions...)
+	return &result
+}
+
+type AssetInventoryService struct {
+	mu         sync.Mutex
+	config_obj *config_proto.Config
+
+	records map[string]*ClientInventory
+	index   *FleetIndex
+
+	refresh_interval int64
+	max_versions     int
+	max_collections  int
+}
+
+// Get the asset inventory service for the org.
+func GetAssetInventoryService(
+	config_obj *config_proto.Config) (*AssetInventoryService, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result, pres := g_services[utils.NormalizedOrgId(config_obj.OrgId)]
+	if !pres {
+		return nil, notRunningError
+	}
+	return result, nil
+}
+
+func NewAssetInventoryService(
+	config_obj *config_proto.Config) *AssetInventoryService {
+	result := &AssetInventoryService{
+		config_obj:       config_obj,
+		records:          make(map[string]*ClientInventory),
+		index:            NewFleetIndex(),
+		refresh_interval: DEFAULT_REFRESH_INTERVAL,
+		max_versions:     DEFAULT_MAX_VERSIONS,
+		max_collections:  DEFAULT_MAX_COLLECTIONS,
+	}
+
+	inventory_config := config_obj.Defaults.GetAssetInventory()
+	if inventory_config.GetRefreshIntervalSec() != 0 {
+		result.refresh_interval = inventory_config.RefreshIntervalSec
+	}
+
+	if inventory_config.GetMaxVersions() > 0 {
+		result.max_versions = int(inventory_config.MaxVersions)
+	}
+
+	if inventory_config.GetMaxCollectionsPerHour() > 0 {
+		result.max_collections = int(inventory_config.MaxCollectionsPerHour)
+	}
+
+	return result
+}
+
+func validCategory(category string) bool {
+	return utils.InString(Categories, category)
+}
+
+func getRawDB(config_obj *config_proto.Config) (
+	datastore.DataStore, datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, nil, errors.New("Datastore does not support raw access")
+	}
+	return db, raw_db, nil
+}
+
+// Load all inventory records from the datastore and index the
+// latest version of each client.
+func (self *AssetInventoryService) Load(ctx context.Context) error {
+	db, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	children, err := db.ListChildren(self.config_obj, paths.ASSET_INVENTORY_ROOT)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		data, err := raw_db.GetBuffer(self.config_obj, child)
+		if err != nil {
+			continue
+		}
+
+		record := &ClientInventory{}
+		err = json.Unmarshal(data, record)
+		if err != nil || record.ClientId == "" {
+			continue
+		}
+
+		self.mu.Lock()
+		self.records[record.ClientId] = record
+		self.mu.Unlock()
+
+		latest := record.Latest()
+		if latest == nil {
+			continue
+		}
+
+		for _, category := range Categories {
+			rows, err := self.readRows(ctx, record.ClientId,
+				latest.Version, category)
+			if err == nil {
+				self.index.Update(record.ClientId, category, rows)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (self *AssetInventoryService) save(record *ClientInventory) error {
+	_, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(self.config_obj,
+		paths.AssetInventoryPath(record.ClientId),
+		serialized, utils.BackgroundWriter)
+}
+
+// The clients which have an inventory.
+func (self *AssetInventoryService) Clients() []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := make([]string, 0, len(self.records))
+	for client_id, record := range self.records {
+		if len(record.Versions) > 0 {
+			result = append(result, client_id)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Get a copy of the client's inventory record.
+func (self *AssetInventoryService) Get(client_id string) (*ClientInventory, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	record, pres := self.records[client_id]
+	if !pres {
+		return nil, notFoundError
+	}
+	return copyInventory(record), nil
+}
+
+func (self *AssetInventoryService) readRows(ctx context.Context,
+	client_id, version, category string) ([]*ordereddict.Dict, error) {
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(file_store_factory,
+		paths.AssetInventoryResultsPath(client_id, version, category))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	result := []*ordereddict.Dict{}
+	for row := range reader.Rows(ctx) {
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+// Get the rows of the category in a version of the client's
+// inventory. The latest version is used if version is empty.
+func (self *AssetInventoryService) Rows(ctx context.Context,
+	client_id, category, version string) ([]*ordereddict.Dict, error) {
+	if !validCategory(category) {
+		return nil, fmt.Errorf("Unknown inventory category %v", category)
+	}
+
+	record, err := self.Get(client_id)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := record.getVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Counts[category] == 0 {
+		return nil, nil
+	}
+
+	return self.readRows(ctx, client_id, item.Version, category)
+}
+
+// Compare two versions of the category in the client's
+// inventory. By default the latest version is compared with the
+// previous version.
+func (self *AssetInventoryService) Diff(ctx context.Context,
+	client_id, category, from, to string) ([]*ordereddict.Dict, error) {
+	record, err := self.Get(client_id)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == "" {
+		latest := record.Latest()
+		if latest == nil {
+			return nil, notFoundError
+		}
+		to = latest.Version
+	}
+
+	if from == "" {
+		for i, item := range record.Versions {
+			if item.Version == to && i > 0 {
+				from = record.Versions[i-1].Version
+			}
+		}
+	}
+
+	// Without an earlier version everything was added.
+	var old_rows []*ordereddict.Dict
+	if from != "" {
+		old_rows, err = self.Rows(ctx, client_id, category, from)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	new_rows, err := self.Rows(ctx, client_id, category, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffRows(old_rows, new_rows), nil
+}
+
+func versionsByName(rows []*ordereddict.Dict) (map[string][]string, []string) {
+	result := make(map[string][]string)
+	names := []string{}
+	for _, row := range rows {
+		name, version := rowKey(row)
+		_, pres := result[name]
+		if !pres {
+			names = append(names, name)
+		}
+		result[name] = append(result[name], version)
+	}
+	return result, names
+}
+
+func diffRows(old_rows, new_rows []*ordereddict.Dict) []*ordereddict.Dict {
+	old_versions, old_names := versionsByName(old_rows)
+	new_versions, new_names := versionsByName(new_rows)
+
+	result := []*ordereddict.Dict{}
+	add := func(change, name string, old, new []string) {
+		result = append(result, ordereddict.NewDict().
+			Set("Change", change).
+			Set("Name", name).
+			Set("OldVersion", strings.Join(old, ", ")).
+			Set("NewVersion", strings.Join(new, ", ")))
+	}
+
+	for _, name := range new_names {
+		old, pres := old_versions[name]
+		new := new_versions[name]
+		if !pres {
+			add("added", name, nil, new)
+		} else if !utils.StringSliceEq(old, new) {
+			add("changed", name, old, new)
+		}
+	}
+
+	for _, name := range old_names {
+		_, pres := new_versions[name]
+		if !pres {
+			add("removed", name, old_versions[name], nil)
+		}
+	}
+
+	return result
+}
+
+// Search the latest inventory of all clients.
+func (self *AssetInventoryService) Search(ctx context.Context,
+	options *SearchOptions) ([]*SearchResult, error) {
+	if !validOperator(options.Op) {
+		return nil, fmt.Errorf("Unsupported version operator %v", options.Op)
+	}
+
+	result, err := self.index.Search(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in the hostnames from the interrogation data.
+	client_info_manager, err := services.GetClientInfoManager(self.config_obj)
+	if err != nil {
+		return result, nil
+	}
+
+	snapshot := client_info_manager.Snapshot(ctx)
+	for _, item := range result {
+		client_info, pres := snapshot.Get(item.ClientId)
+		if pres {
+			item.Hostname = client_info.Hostname
+		}
+	}
+
+	return result, nil
+}
+
+// Schedule an inventory collection on the client.
+func (self *AssetInventoryService) Refresh(ctx context.Context,
+	principal, client_id string) (string, error) {
+	manager, err := services.GetRepositoryManager(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	launcher, err := services.GetLauncher(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	// The caller has already been checked for permission to collect
+	// from the client.
+	flow_id, err := launcher.ScheduleArtifactCollection(
+		ctx, self.config_obj, acl_managers.NullACLManager{},
+		repository,
+		&flows_proto.ArtifactCollectorArgs{
+			Creator:   principal,
+			ClientId:  client_id,
+			Artifacts: []string{INVENTORY_ARTIFACT},
+		}, func() {
+			notifier, err := services.GetNotifier(self.config_obj)
+			if err == nil {
+				notifier.NotifyListener(ctx,
+					self.config_obj, client_id, "AssetInventory")
+			}
+		})
+	if err != nil {
+		return "", err
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	record, pres := self.records[client_id]
+	if !pres {
+		record = &ClientInventory{ClientId: client_id}
+		self.records[client_id] = record
+	}
+	record.PendingFlowId = flow_id
+	record.PendingSince = utils.GetTime().Now().Unix()
+
+	return flow_id, self.save(record)
+}
+
+// Does the client need a new inventory collection?
+func (self *AssetInventoryService) needsRefresh(client_id string, now int64) bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	record, pres := self.records[client_id]
+	if !pres {
+		return true
+	}
+
+	// Offline clients may take a while to pick up the collection.
+	if record.PendingFlowId != "" &&
+		record.PendingSince > now-self.refresh_interval {
+		return false
+	}
+
+	latest := record.Latest()
+	return latest == nil || latest.Timestamp < now-self.refresh_interval
+}
+
+// Schedule collections on clients with old inventories.
+func (self *AssetInventoryService) ScheduleRefreshes(ctx context.Context) error {
+	if self.refresh_interval < 0 {
+		return nil
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetTime().Now().Unix()
+	count := 0
+
+	snapshot := client_info_manager.Snapshot(ctx)
+	for _, client_id := range snapshot.Keys() {
+		if count >= self.max_collections {
+			break
+		}
+
+		if !self.needsRefresh(client_id, now) {
+			continue
+		}
+
+		_, err := self.Refresh(ctx, "AssetInventoryService", client_id)
+		if err != nil {
+			return err
+		}
+		count++
+	}
+
+	return nil
+}
+
+// Copy the results of a completed inventory collection into a new
+// version of the client's inventory.
+func (self *AssetInventoryService) ProcessCollection(ctx context.Context,
+	client_id, flow_id string) error {
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	version := &InventoryVersion{
+		Version:   flow_id,
+		Timestamp: utils.GetTime().Now().Unix(),
+		Counts:    make(map[string]int),
+	}
+
+	for _, category := range Categories {
+		path_manager, err := artifacts.NewArtifactPathManager(ctx,
+			self.config_obj, client_id, flow_id,
+			INVENTORY_ARTIFACT+"/"+category)
+		if err != nil {
+			return err
+		}
+
+		rows := []*ordereddict.Dict{}
+		reader, err := result_sets.NewResultSetReader(
+			file_store_factory, path_manager.Path())
+		if err == nil {
+			for row := range reader.Rows(ctx) {
+				// Added by the client.
+				row.Delete("_Source")
+				rows = append(rows, row)
+			}
+			reader.Close()
+		}
+
+		writer, err := result_sets.NewResultSetWriter(file_store_factory,
+			paths.AssetInventoryResultsPath(client_id, flow_id, category),
+			json.DefaultEncOpts(), utils.SyncCompleter,
+			result_sets.TruncateMode)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			writer.Write(row)
+		}
+		writer.Close()
+
+		version.Counts[category] = len(rows)
+		self.index.Update(client_id, category, rows)
+	}
+
+	self.mu.Lock()
+	record, pres := self.records[client_id]
+	if !pres {
+		record = &ClientInventory{ClientId: client_id}
+		self.records[client_id] = record
+	}
+
+	if record.PendingFlowId == flow_id {
+		record.PendingFlowId = ""
+		record.PendingSince = 0
+	}
+
+	record.Versions = append(record.Versions, version)
+	var expired []*InventoryVersion
+	if len(record.Versions) > self.max_versions {
+		expired = record.Versions[:len(record.Versions)-self.max_versions]
+		record.Versions = record.Versions[len(expired):]
+	}
+	err := self.save(record)
+	self.mu.Unlock()
+
+	for _, item := range expired {
+		for _, category := range Categories {
+			path := paths.AssetInventoryResultsPath(
+				client_id, item.Version, category)
+			_ = file_store_factory.Delete(path)
+			_ = file_store_factory.Delete(
+				path.SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX))
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	journal_service, err := services.GetJournal(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	return journal_service.PushRowsToArtifact(ctx, self.config_obj,
+		[]*ordereddict.Dict{ordereddict.NewDict().
+			Set("ClientId", client_id).
+			Set("Version", version.Version).
+			Set("Counts", version.Counts)},
+		UPDATES_ARTIFACT, "server", "")
+}
+
+// Remove the client's inventory, e.g. when the client is deleted.
+func (self *AssetInventoryService) RemoveClient(client_id string) error {
+	self.mu.Lock()
+	record, pres := self.records[client_id]
+	delete(self.records, client_id)
+	self.mu.Unlock()
+
+	self.index.RemoveClient(client_id)
+	if !pres {
+		return nil
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	for _, item := range record.Versions {
+		for _, category := range Categories {
+			path := paths.AssetInventoryResultsPath(
+				client_id, item.Version, category)
+			_ = file_store_factory.Delete(path)
+			_ = file_store_factory.Delete(
+				path.SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX))
+		}
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+	return db.DeleteSubject(self.config_obj, paths.AssetInventoryPath(client_id))
+}
+
+func isInventoryCollection(flow *flows_proto.ArtifactCollectorContext) bool {
+	for _, name := range flow.ArtifactsWithResults {
+		if strings.HasPrefix(name, INVENTORY_ARTIFACT+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Only the master node maintains the inventory.
+func StartAssetInventoryService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if !services.IsMaster(config_obj) {
+		return nil
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Asset Inventory service for %v.",
+		services.GetOrgName(config_obj))
+
+	self := NewAssetInventoryService(config_obj)
+	err := self.Load(ctx)
+	if err != nil {
+		logger.Debug("AssetInventoryService: No inventory loaded: %v", err)
+	}
+
+	org_id := utils.NormalizedOrgId(config_obj.OrgId)
+	mu.Lock()
+	g_services[org_id] = self
+	mu.Unlock()
+
+	err = journal.WatchQueueWithCB(ctx, config_obj, wg,
+		"System.Flow.Completion", "AssetInventoryService",
+		func(ctx context.Context, config_obj *config_proto.Config,
+			row *ordereddict.Dict) error {
+			flow, err := journal.GetFlowFromQueue(ctx, config_obj, row)
+			if err != nil || !isInventoryCollection(flow) {
+				return nil
+			}
+
+			err = self.ProcessCollection(ctx, flow.ClientId, flow.SessionId)
+			if err != nil {
+				logger.Error("AssetInventoryService: %v", err)
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	// Take the first inventory as soon as new clients are
+	// interrogated.
+	err = journal.WatchQueueWithCB(ctx, config_obj, wg,
+		"Server.Internal.Interrogation", "AssetInventoryService",
+		func(ctx context.Context, config_obj *config_proto.Config,
+			row *ordereddict.Dict) error {
+			client_id, _ := row.GetString("ClientId")
+			if client_id == "" || self.refresh_interval < 0 ||
+				!self.needsRefresh(client_id, utils.GetTime().Now().Unix()) {
+				return nil
+			}
+
+			_, err := self.Refresh(ctx, "AssetInventoryService", client_id)
+			if err != nil {
+				logger.Error("AssetInventoryService: %v", err)
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	err = journal.WatchQueueWithCB(ctx, config_obj, wg,
+		"Server.Internal.ClientDelete", "AssetInventoryService",
+		func(ctx context.Context, config_obj *config_proto.Config,
+			row *ordereddict.Dict) error {
+			client_id, _ := row.GetString("ClientId")
+			if client_id != "" {
+				err := self.RemoveClient(client_id)
+				if err != nil {
+					logger.Error("AssetInventoryService: %v", err)
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			mu.Lock()
+			delete(g_services, org_id)
+			mu.Unlock()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(SCHEDULE_INTERVAL):
+				err := self.ScheduleRefreshes(ctx)
+				if err != nil {
+					logger.Error("AssetInventoryService: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package asset_inventory_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/asset_inventory"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type AssetInventoryTestSuite struct {
+	test_utils.TestSuite
+}
+
+var mock_definitions = []string{`
+name: Server.Internal.AssetInventory
+type: INTERNAL
+`, `
+name: Generic.Client.Inventory
+sources:
+- name: Software
+  query: SELECT * FROM scope()
+- name: Patches
+  query: SELECT * FROM scope()
+- name: Users
+  query: SELECT * FROM scope()
+- name: ListeningServices
+  query: SELECT * FROM scope()
+- name: Hardware
+  query: SELECT * FROM scope()
+`}
+
+func (self *AssetInventoryTestSuite) SetupTest() {
+	self.ConfigObj = self.TestSuite.LoadConfig()
+	self.ConfigObj.Defaults.AssetInventory = &config_proto.AssetInventoryConfig{
+		MaxVersions: 2,
+	}
+
+	self.LoadArtifactsIntoConfig(mock_definitions)
+
+	self.TestSuite.SetupTest()
+}
+
+// Write the results of a fake inventory collection.
+func (self *AssetInventoryTestSuite) writeCollection(
+	client_id, flow_id string, software ...string) {
+	path_manager, err := artifacts.NewArtifactPathManager(self.Ctx,
+		self.ConfigObj, client_id, flow_id,
+		asset_inventory.INVENTORY_ARTIFACT+"/Software")
+	assert.NoError(self.T(), err)
+
+	writer, err := result_sets.NewResultSetWriter(
+		file_store.GetFileStore(self.ConfigObj), path_manager.Path(),
+		json.DefaultEncOpts(), utils.SyncCompleter, result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	for i := 0; i+1 < len(software); i += 2 {
+		writer.Write(ordereddict.NewDict().
+			Set("Name", software[i]).
+			Set("Version", software[i+1]).
+			Set("_Source", asset_inventory.INVENTORY_ARTIFACT+"/Software"))
+	}
+	writer.Close()
+}
+
+func (self *AssetInventoryTestSuite) TestInventory() {
+	closer := utils.MockTime(utils.NewMockClock(time.Unix(1700000000, 0)))
+	defer closer()
+
+	service := asset_inventory.NewAssetInventoryService(self.ConfigObj)
+
+	self.writeCollection("C.1", "F.1", "OpenSSL", "1.1.1f", "curl", "7.68.0")
+	self.writeCollection("C.2", "F.2", "OpenSSL", "3.0.10")
+
+	assert.NoError(self.T(), service.ProcessCollection(self.Ctx, "C.1", "F.1"))
+	assert.NoError(self.T(), service.ProcessCollection(self.Ctx, "C.2", "F.2"))
+
+	rows, err := service.Rows(self.Ctx, "C.1", "Software", "")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(rows))
+
+	// Columns added by the client are not kept.
+	_, pres := rows[0].Get("_Source")
+	assert.True(self.T(), !pres)
+
+	_, err = service.Rows(self.Ctx, "C.1", "Unknown", "")
+	assert.Error(self.T(), err)
+
+	// Search the fleet for vulnerable versions.
+	results, err := service.Search(self.Ctx, &asset_inventory.SearchOptions{
+		Category: "Software",
+		Name:     "^openssl$",
+		Op:       "<",
+		Version:  "3.0.7",
+	})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(results))
+	assert.Equal(self.T(), "C.1", results[0].ClientId)
+	assert.Equal(self.T(), "1.1.1f", results[0].Version)
+
+	_, err = service.Search(self.Ctx, &asset_inventory.SearchOptions{Op: "~"})
+	assert.Error(self.T(), err)
+
+	// Upgrading OpenSSL creates a new version.
+	self.writeCollection("C.1", "F.3", "OpenSSL", "3.0.13", "vim", "8.1")
+	assert.NoError(self.T(), service.ProcessCollection(self.Ctx, "C.1", "F.3"))
+
+	results, err = service.Search(self.Ctx, &asset_inventory.SearchOptions{
+		Name: "openssl", Op: "<", Version: "3.0.7",
+	})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(results))
+
+	diff, err := service.Diff(self.Ctx, "C.1", "Software", "", "")
+	assert.NoError(self.T(), err)
+
+	changes := []string{}
+	for _, row := range diff {
+		change, _ := row.GetString("Change")
+		name, _ := row.GetString("Name")
+		changes = append(changes, change+" "+name)
+	}
+	assert.Equal(self.T(), []string{
+		"changed OpenSSL", "added vim", "removed curl"}, changes)
+
+	// Only max_versions versions are kept.
+	self.writeCollection("C.1", "F.4", "OpenSSL", "3.0.13")
+	assert.NoError(self.T(), service.ProcessCollection(self.Ctx, "C.1", "F.4"))
+
+	record, err := service.Get("C.1")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(record.Versions))
+	assert.Equal(self.T(), "F.3", record.Versions[0].Version)
+
+	_, err = service.Rows(self.Ctx, "C.1", "Software", "F.1")
+	assert.Error(self.T(), err)
+
+	// The inventory and index survive a restart.
+	restored := asset_inventory.NewAssetInventoryService(self.ConfigObj)
+	assert.NoError(self.T(), restored.Load(self.Ctx))
+
+	results, err = restored.Search(self.Ctx, &asset_inventory.SearchOptions{
+		Name: "openssl",
+	})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(results))
+
+	// Removing the client removes it from the index.
+	assert.NoError(self.T(), restored.RemoveClient("C.2"))
+	results, err = restored.Search(self.Ctx, &asset_inventory.SearchOptions{
+		Name: "openssl",
+	})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(results))
+}
+
+func (self *AssetInventoryTestSuite) TestScheduleRefreshes() {
+	clock := utils.NewMockClock(time.Unix(1700000000, 0))
+	closer := utils.MockTime(clock)
+	defer closer()
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	for i := 0; i < 3; i++ {
+		err = client_info_manager.Set(self.Ctx, &services.ClientInfo{
+			ClientInfo: actions_proto.ClientInfo{
+				ClientId: fmt.Sprintf("C.%d", i),
+			},
+		})
+		assert.NoError(self.T(), err)
+	}
+
+	service := asset_inventory.NewAssetInventoryService(self.ConfigObj)
+
+	// C.0 has a recent inventory.
+	self.writeCollection("C.0", "F.1", "curl", "7.68.0")
+	assert.NoError(self.T(), service.ProcessCollection(self.Ctx, "C.0", "F.1"))
+
+	assert.NoError(self.T(), service.ScheduleRefreshes(self.Ctx))
+
+	pending := []string{}
+	for i := 0; i < 3; i++ {
+		client_id := fmt.Sprintf("C.%d", i)
+		record, err := service.Get(client_id)
+		if err == nil && record.PendingFlowId != "" {
+			pending = append(pending, client_id)
+		}
+	}
+	assert.Equal(self.T(), []string{"C.1", "C.2"}, pending)
+
+	// Pending collections are not scheduled again.
+	record, _ := service.Get("C.1")
+	flow_id := record.PendingFlowId
+
+	assert.NoError(self.T(), service.ScheduleRefreshes(self.Ctx))
+	record, _ = service.Get("C.1")
+	assert.Equal(self.T(), flow_id, record.PendingFlowId)
+
+	// Old inventories are refreshed.
+	clock.Set(time.Unix(1700000000+8*24*3600, 0))
+	assert.NoError(self.T(), service.ScheduleRefreshes(self.Ctx))
+
+	record, _ = service.Get("C.0")
+	assert.True(self.T(), record.PendingFlowId != "")
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, c := range []struct {
+		a, b   string
+		result int
+	}{
+		{"1.1.1f", "1.1.1k", -1},
+		{"3.0.2", "3.0.10", -1},
+		{"1.1.1", "1.1.1a", -1},
+		{"1.1.1f-1ubuntu2.16", "1.1.1f-1ubuntu2.9", 1},
+		{"10.0.19041.1", "10.0.19041.01", 0},
+		{"2.0", "1.9.9", 1},
+	} {
+		assert.Equal(t, c.result, asset_inventory.CompareVersions(c.a, c.b),
+			"%v vs %v", c.a, c.b)
+	}
+}
+
+func TestAssetInventory(t *testing.T) {
+	suite.Run(t, &AssetInventoryTestSuite{})
+}
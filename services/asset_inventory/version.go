@@ -0,0 +1,122 @@
+package asset_inventory
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Package versions come in many formats (e.g. 1.1.1f-1ubuntu2.16,
+// 3.0.7, 10.0.19041.1) which are not valid semantic versions. We
+// compare them the way most package managers do: the version is
+// split into runs of digits and letters which are compared in order,
+// digits numerically and letters lexically. Separators are ignored
+// and a version with more parts is newer (e.g. 1.1.1f > 1.1.1).
+func CompareVersions(a, b string) int {
+	a_parts := splitVersion(a)
+	b_parts := splitVersion(b)
+
+	for i := 0; i < len(a_parts) && i < len(b_parts); i++ {
+		result := compareVersionPart(a_parts[i], b_parts[i])
+		if result != 0 {
+			return result
+		}
+	}
+
+	switch {
+	case len(a_parts) < len(b_parts):
+		return -1
+	case len(a_parts) > len(b_parts):
+		return 1
+	}
+	return 0
+}
+
+func splitVersion(version string) []string {
+	result := []string{}
+	current := strings.Builder{}
+	current_is_digit := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			result = append(result, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, c := range strings.ToLower(version) {
+		is_digit := unicode.IsDigit(c)
+		if !is_digit && !unicode.IsLetter(c) {
+			flush()
+			continue
+		}
+
+		if is_digit != current_is_digit {
+			flush()
+		}
+		current_is_digit = is_digit
+		current.WriteRune(c)
+	}
+	flush()
+
+	return result
+}
+
+func compareVersionPart(a, b string) int {
+	a_is_digit := unicode.IsDigit(rune(a[0]))
+	b_is_digit := unicode.IsDigit(rune(b[0]))
+
+	// Numbers sort after letters so 1.1.1.2 > 1.1.1f
+	if a_is_digit != b_is_digit {
+		if a_is_digit {
+			return 1
+		}
+		return -1
+	}
+
+	if !a_is_digit {
+		return strings.Compare(a, b)
+	}
+
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// Check the version against the operator and the version to compare
+// with. An empty operator matches all versions.
+func matchVersion(version, op, other string) bool {
+	if op == "" || other == "" {
+		return true
+	}
+
+	result := CompareVersions(version, other)
+	switch op {
+	case "=", "==":
+		return result == 0
+	case "!=":
+		return result != 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	}
+	return false
+}
+
+func validOperator(op string) bool {
+	switch op {
+	case "", "=", "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,280 @@
+/*
+  The availability service tracks client check ins over time so we
+  can report on the health of the fleet.
+
+  The client info manager only remembers the last time each client was
+  seen. This service periodically samples these ping times into a per
+  client ring buffer of hourly buckets, so we can tell how often each
+  client was online over the last month, and which clients keep
+  dropping off and coming back (flapping).
+
+  The ring buffers are small (90 bytes per client) and are kept in
+  memory. The master node periodically writes them to a snapshot in
+  the filestore so history survives restarts.
+*/
+
+package availability
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// Each bucket covers an hour.
+	BUCKET_SIZE = int64(3600)
+
+	// Keep 30 days of history.
+	HISTORY_BUCKETS = int64(24 * 30)
+
+	SAMPLE_INTERVAL   = 5 * time.Minute
+	SNAPSHOT_INTERVAL = time.Hour
+)
+
+var (
+	mu         sync.Mutex
+	g_services = make(map[string]*AvailabilityService)
+
+	notRunningError = errors.New("Availability service not running")
+)
+
+type AvailabilityService struct {
+	mu         sync.Mutex
+	config_obj *config_proto.Config
+
+	history map[string]*History
+	dirty   bool
+}
+
+// Get the availability service for the org.
+func GetAvailabilityService(
+	config_obj *config_proto.Config) (*AvailabilityService, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result, pres := g_services[utils.NormalizedOrgId(config_obj.OrgId)]
+	if !pres {
+		return nil, notRunningError
+	}
+	return result, nil
+}
+
+func NewAvailabilityService(
+	config_obj *config_proto.Config) *AvailabilityService {
+	return &AvailabilityService{
+		config_obj: config_obj,
+		history:    make(map[string]*History),
+	}
+}
+
+func bucketForTime(t time.Time) int64 {
+	return t.Unix() / BUCKET_SIZE
+}
+
+// Record that the client was seen at the time.
+func (self *AvailabilityService) Record(client_id string, seen time.Time) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	history, pres := self.history[client_id]
+	if !pres {
+		history = NewHistory(HISTORY_BUCKETS)
+		self.history[client_id] = history
+	}
+
+	bucket := bucketForTime(seen)
+	if !history.Seen(bucket) {
+		history.Mark(bucket)
+		self.dirty = true
+	}
+}
+
+// Get a copy of the client's history.
+func (self *AvailabilityService) GetHistory(client_id string) (*History, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	history, pres := self.history[client_id]
+	if !pres {
+		return nil, false
+	}
+
+	return &History{
+		Head:   history.Head,
+		Bitmap: append([]byte{}, history.Bitmap...),
+	}, true
+}
+
+// Record the last ping time of all clients. Clients currently
+// connected are seen right now.
+func (self *AvailabilityService) Sample(ctx context.Context) error {
+	client_info_manager, err := services.GetClientInfoManager(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	notifier, _ := services.GetNotifier(self.config_obj)
+	now := utils.GetTime().Now()
+
+	snapshot := client_info_manager.Snapshot(ctx)
+	for _, client_id := range snapshot.Keys() {
+		if notifier != nil && notifier.IsClientDirectlyConnected(client_id) {
+			self.Record(client_id, now)
+			continue
+		}
+
+		client_info, pres := snapshot.Get(client_id)
+		if !pres || client_info.Ping == 0 {
+			continue
+		}
+
+		// Ping times are in microseconds.
+		self.Record(client_id, time.Unix(0, int64(client_info.Ping)*1000))
+	}
+
+	return nil
+}
+
+func (self *AvailabilityService) LoadSnapshot(ctx context.Context) error {
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(
+		file_store_factory, paths.CLIENTS_AVAILABILITY_SNAPSHOT)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for row := range reader.Rows(ctx) {
+		client_id, _ := row.GetString("client_id")
+		head, _ := row.GetInt64("head")
+		hex_bitmap, _ := row.GetString("bitmap")
+
+		bitmap, err := hex.DecodeString(hex_bitmap)
+		if err != nil || client_id == "" ||
+			int64(len(bitmap))*8 != HISTORY_BUCKETS {
+			continue
+		}
+
+		self.history[client_id] = &History{
+			Head:   head,
+			Bitmap: bitmap,
+		}
+	}
+
+	return nil
+}
+
+func (self *AvailabilityService) SaveSnapshot(ctx context.Context) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if !self.dirty {
+		return nil
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	writer, err := result_sets.NewResultSetWriter(
+		file_store_factory, paths.CLIENTS_AVAILABILITY_SNAPSHOT,
+		json.DefaultEncOpts(), utils.BackgroundWriter,
+		result_sets.TruncateMode)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	for client_id, history := range self.history {
+		writer.Write(ordereddict.NewDict().
+			Set("client_id", client_id).
+			Set("head", history.Head).
+			Set("bitmap", hex.EncodeToString(history.Bitmap)))
+	}
+
+	self.dirty = false
+	return nil
+}
+
+// Periodically sample the client pings. Only the master node tracks
+// availability since it sees all client pings through the client
+// info manager.
+func StartAvailabilityService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if !services.IsMaster(config_obj) {
+		return nil
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Client Availability service for %v.",
+		services.GetOrgName(config_obj))
+
+	self := NewAvailabilityService(config_obj)
+	err := self.LoadSnapshot(ctx)
+	if err != nil {
+		logger.Debug("AvailabilityService: No snapshot loaded: %v", err)
+	}
+
+	org_id := utils.NormalizedOrgId(config_obj.OrgId)
+	mu.Lock()
+	g_services[org_id] = self
+	mu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			mu.Lock()
+			delete(g_services, org_id)
+			mu.Unlock()
+		}()
+
+		last_snapshot := utils.GetTime().Now()
+
+		for {
+			err := self.Sample(ctx)
+			if err != nil {
+				logger.Error("AvailabilityService: %v", err)
+			}
+
+			now := utils.GetTime().Now()
+			if now.Sub(last_snapshot) > SNAPSHOT_INTERVAL {
+				last_snapshot = now
+				err := self.SaveSnapshot(ctx)
+				if err != nil {
+					logger.Error("AvailabilityService: %v", err)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				// Write the snapshot on shutdown.
+				err := self.SaveSnapshot(context.Background())
+				if err != nil {
+					logger.Error("AvailabilityService: writing snapshot: %v", err)
+				}
+				return
+
+			case <-time.After(SAMPLE_INTERVAL):
+			}
+		}
+	}()
+
+	return nil
+}
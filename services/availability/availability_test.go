@@ -0,0 +1,166 @@
+package availability_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/availability"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestHistory(t *testing.T) {
+	history := availability.NewHistory(16)
+	assert.Equal(t, int64(16), history.Size())
+
+	history.Mark(100)
+	history.Mark(101)
+	history.Mark(105)
+
+	assert.True(t, history.Seen(100))
+	assert.True(t, history.Seen(101))
+	assert.True(t, !history.Seen(102))
+	assert.True(t, history.Seen(105))
+
+	// Buckets in the future are not seen.
+	assert.True(t, !history.Seen(106))
+
+	seen, gaps := history.Stats(100, 105)
+	assert.Equal(t, int64(3), seen)
+	assert.Equal(t, int64(1), gaps)
+
+	// Moving past the end of the ring buffer forgets the old
+	// buckets.
+	history.Mark(117)
+	assert.True(t, !history.Seen(100))
+	assert.True(t, !history.Seen(101))
+	assert.True(t, history.Seen(105))
+
+	// The slot shared by 100 and 116 was cleared when skipping
+	// over 116.
+	assert.True(t, !history.Seen(116))
+	assert.True(t, history.Seen(117))
+
+	// Too old to record.
+	history.Mark(90)
+	assert.True(t, !history.Seen(90))
+
+	// A large jump clears everything.
+	history.Mark(1000)
+	seen, _ = history.Stats(1000-15, 1000)
+	assert.Equal(t, int64(1), seen)
+}
+
+type AvailabilityTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *AvailabilityTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	self.LoadArtifacts(`name: Server.Internal.MetadataModifications
+type: SERVER_EVENT
+`)
+}
+
+func (self *AvailabilityTestSuite) TestReports() {
+	now := time.Unix(1700000000, 0)
+	closer := utils.MockTime(utils.NewMockClock(now))
+	defer closer()
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	service := availability.NewAvailabilityService(self.ConfigObj)
+
+	hour := time.Hour
+	clients := []struct {
+		client_id, label string
+		last_seen        time.Time
+		seen_hours       []int
+	}{
+		// Always online.
+		{"C.1", "Servers", now, []int{0, 1, 2, 3, 4, 5, 6}},
+
+		// Not seen for 10 days.
+		{"C.2", "Servers", now.Add(-240 * hour), nil},
+
+		// Keeps dropping off.
+		{"C.3", "", now, []int{0, 2, 4, 6, 8}},
+	}
+
+	for _, c := range clients {
+		err := client_info_manager.Set(self.Ctx, &services.ClientInfo{
+			actions_proto.ClientInfo{
+				ClientId: c.client_id,
+				Hostname: "host" + c.client_id,
+				Ping:     uint64(c.last_seen.UnixNano() / 1000),
+			},
+		})
+		assert.NoError(self.T(), err)
+
+		if c.label != "" {
+			err = services.GetLabeler(self.ConfigObj).SetClientLabel(
+				self.Ctx, self.ConfigObj, c.client_id, c.label)
+			assert.NoError(self.T(), err)
+		}
+
+		for _, h := range c.seen_hours {
+			service.Record(c.client_id, now.Add(-time.Duration(h)*hour))
+		}
+	}
+
+	options := availability.ReportOptions{Days: 7}
+
+	not_seen, err := service.NotSeenReport(self.Ctx, options)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(not_seen))
+
+	assert.Equal(self.T(), availability.NO_GROUP, not_seen[0].Group)
+	assert.Equal(self.T(), int64(1), not_seen[0].Total)
+	assert.Equal(self.T(), int64(0), not_seen[0].NotSeen)
+
+	assert.Equal(self.T(), "Servers", not_seen[1].Group)
+	assert.Equal(self.T(), int64(2), not_seen[1].Total)
+	assert.Equal(self.T(), int64(1), not_seen[1].NotSeen)
+	assert.Equal(self.T(), []string{"C.2"}, not_seen[1].Clients)
+
+	flapping, err := service.FlappingReport(self.Ctx, options)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(flapping))
+	assert.Equal(self.T(), "C.3", flapping[0].ClientId)
+	assert.Equal(self.T(), int64(4), flapping[0].Gaps)
+
+	// Group by a metadata field instead.
+	err = client_info_manager.SetMetadata(self.Ctx, "C.1",
+		ordereddict.NewDict().Set("OU", "Finance"), "admin")
+	assert.NoError(self.T(), err)
+
+	options.GroupBy = "OU"
+	not_seen, err = service.NotSeenReport(self.Ctx, options)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(not_seen))
+	assert.Equal(self.T(), "(none)", not_seen[0].Group)
+	assert.Equal(self.T(), int64(2), not_seen[0].Total)
+	assert.Equal(self.T(), "Finance", not_seen[1].Group)
+
+	// History survives a restart.
+	assert.NoError(self.T(), service.SaveSnapshot(self.Ctx))
+
+	restored := availability.NewAvailabilityService(self.ConfigObj)
+	assert.NoError(self.T(), restored.LoadSnapshot(self.Ctx))
+
+	history, pres := restored.GetHistory("C.3")
+	assert.True(self.T(), pres)
+	original, _ := service.GetHistory("C.3")
+	assert.Equal(self.T(), original, history)
+}
+
+func TestAvailability(t *testing.T) {
+	suite.Run(t, &AvailabilityTestSuite{})
+}
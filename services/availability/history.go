@@ -0,0 +1,91 @@
+package availability
+
+// A fixed size ring buffer of check in buckets. Each bucket is a
+// single bit which is set when the client checked in during the
+// bucket's time period. Buckets are addressed by their absolute
+// number (unix time / bucket size) so the ring buffer does not need
+// to be periodically rotated - old buckets are cleared as newer
+// buckets are marked.
+type History struct {
+	// The most recent bucket marked.
+	Head int64
+
+	Bitmap []byte
+}
+
+func NewHistory(size int64) *History {
+	return &History{Bitmap: make([]byte, (size+7)/8)}
+}
+
+func (self *History) Size() int64 {
+	return int64(len(self.Bitmap)) * 8
+}
+
+func (self *History) set(bucket int64, value bool) {
+	idx := bucket % self.Size()
+	if value {
+		self.Bitmap[idx/8] |= 1 << uint(idx%8)
+	} else {
+		self.Bitmap[idx/8] &^= 1 << uint(idx%8)
+	}
+}
+
+// Mark the client as seen during the bucket.
+func (self *History) Mark(bucket int64) {
+	size := self.Size()
+	if size == 0 {
+		return
+	}
+
+	// Too old to be recorded.
+	if bucket <= self.Head-size {
+		return
+	}
+
+	// Moving forward - clear all the buckets between the old head
+	// and the new one since the client was not seen in them.
+	if bucket > self.Head {
+		start := self.Head + 1
+		if bucket-start >= size || self.Head == 0 {
+			start = bucket - size + 1
+		}
+		for i := start; i < bucket; i++ {
+			self.set(i, false)
+		}
+		self.Head = bucket
+	}
+
+	self.set(bucket, true)
+}
+
+// Was the client seen during the bucket?
+func (self *History) Seen(bucket int64) bool {
+	size := self.Size()
+	if size == 0 || bucket > self.Head || bucket <= self.Head-size {
+		return false
+	}
+
+	idx := bucket % size
+	return self.Bitmap[idx/8]&(1<<uint(idx%8)) != 0
+}
+
+// Count the buckets the client was seen in, and the number of times
+// it went offline and came back, over the range of buckets [start,
+// end].
+func (self *History) Stats(start, end int64) (seen, gaps int64) {
+	in_gap := false
+	for i := start; i <= end; i++ {
+		if self.Seen(i) {
+			seen++
+			if in_gap {
+				gaps++
+				in_gap = false
+			}
+
+			// A gap only starts once the client was seen.
+		} else if seen > 0 {
+			in_gap = true
+		}
+	}
+	return seen, gaps
+}
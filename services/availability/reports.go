@@ -0,0 +1,195 @@
+package availability
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	NO_GROUP = "(none)"
+)
+
+type ReportOptions struct {
+	// The report covers this many days.
+	Days int64
+
+	// Group clients by "label" or a client metadata field (e.g. OU).
+	GroupBy string
+
+	// A client is flapping if it went offline and returned at least
+	// this many times.
+	MinGaps int64
+}
+
+type ClientAvailability struct {
+	ClientId string    `json:"ClientId"`
+	Hostname string    `json:"Hostname"`
+	Groups   []string  `json:"Groups"`
+	LastSeen time.Time `json:"LastSeen"`
+
+	// Percentage of hours the client was seen during the report
+	// period.
+	Availability float64 `json:"Availability"`
+
+	// Number of times the client went offline and came back.
+	Gaps int64 `json:"Gaps"`
+}
+
+// Summary of clients in a group.
+type GroupAvailability struct {
+	Group   string   `json:"Group"`
+	Total   int64    `json:"Total"`
+	NotSeen int64    `json:"NotSeen"`
+	Clients []string `json:"Clients"`
+}
+
+func (self *ReportOptions) normalize() {
+	if self.Days <= 0 {
+		self.Days = 7
+	}
+
+	max_days := HISTORY_BUCKETS * BUCKET_SIZE / 86400
+	if self.Days > max_days {
+		self.Days = max_days
+	}
+
+	if self.GroupBy == "" {
+		self.GroupBy = "label"
+	}
+
+	if self.MinGaps <= 0 {
+		self.MinGaps = 3
+	}
+}
+
+// Report the availability of every client over the report period.
+func (self *AvailabilityService) ClientReport(
+	ctx context.Context, options ReportOptions) ([]*ClientAvailability, error) {
+	options.normalize()
+
+	client_info_manager, err := services.GetClientInfoManager(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	end := bucketForTime(utils.GetTime().Now())
+	start := end - options.Days*86400/BUCKET_SIZE + 1
+
+	result := []*ClientAvailability{}
+	snapshot := client_info_manager.Snapshot(ctx)
+	for _, client_id := range snapshot.Keys() {
+		client_info, pres := snapshot.Get(client_id)
+		if !pres {
+			continue
+		}
+
+		record := &ClientAvailability{
+			ClientId: client_id,
+			Hostname: client_info.Hostname,
+			LastSeen: time.Unix(0, int64(client_info.Ping)*1000).UTC(),
+		}
+
+		if options.GroupBy == "label" {
+			record.Groups = client_info.Labels
+		} else {
+			metadata, err := client_info_manager.GetMetadata(ctx, client_id)
+			if err == nil {
+				group, pres := metadata.GetString(options.GroupBy)
+				if pres && group != "" {
+					record.Groups = []string{group}
+				}
+			}
+		}
+
+		if len(record.Groups) == 0 {
+			record.Groups = []string{NO_GROUP}
+		}
+
+		history, pres := self.GetHistory(client_id)
+		if pres {
+			seen, gaps := history.Stats(start, end)
+			record.Availability = float64(seen*100) / float64(end-start+1)
+			record.Gaps = gaps
+		}
+
+		result = append(result, record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ClientId < result[j].ClientId
+	})
+
+	return result, nil
+}
+
+// Summarize, for each group, the clients that were not seen during
+// the report period.
+func (self *AvailabilityService) NotSeenReport(
+	ctx context.Context, options ReportOptions) ([]*GroupAvailability, error) {
+	options.normalize()
+
+	clients, err := self.ClientReport(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := utils.GetTime().Now().Add(
+		-time.Duration(options.Days) * 24 * time.Hour)
+
+	groups := make(map[string]*GroupAvailability)
+	for _, client := range clients {
+		for _, name := range client.Groups {
+			group, pres := groups[name]
+			if !pres {
+				group = &GroupAvailability{Group: name, Clients: []string{}}
+				groups[name] = group
+			}
+
+			group.Total++
+			if client.LastSeen.Before(cutoff) {
+				group.NotSeen++
+				group.Clients = append(group.Clients, client.ClientId)
+			}
+		}
+	}
+
+	result := make([]*GroupAvailability, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Group < result[j].Group
+	})
+
+	return result, nil
+}
+
+// Clients which repeatedly went offline and returned during the
+// report period.
+func (self *AvailabilityService) FlappingReport(
+	ctx context.Context, options ReportOptions) ([]*ClientAvailability, error) {
+	options.normalize()
+
+	clients, err := self.ClientReport(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*ClientAvailability{}
+	for _, client := range clients {
+		if client.Gaps >= options.MinGaps {
+			result = append(result, client)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Gaps > result[j].Gaps
+	})
+
+	return result, nil
+}
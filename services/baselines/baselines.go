@@ -0,0 +1,585 @@
+/*
+  The baseline service stores a snapshot of a client's state (e.g. its
+  services, autoruns, scheduled tasks and drivers) so later
+  collections can be compared against it. Rather than reviewing every
+  row of a hunt, analysts can focus on what changed since the
+  baseline was taken (drift).
+
+  A baseline is the result set of a single artifact source collected
+  in a flow. It is copied out of the flow so it remains available
+  even if the flow is later deleted. Rows are matched by the key
+  columns of the artifact - rows with a new key were added, rows
+  whose key disappeared were removed and rows with the same key but
+  different values were changed.
+
+  Baselines are set explicitly with baseline_set() or the GUI. The
+  first collection of a designated artifact on a client automatically
+  becomes its baseline.
+*/
+
+package baselines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	STATUS_ADDED   = "added"
+	STATUS_REMOVED = "removed"
+	STATUS_CHANGED = "changed"
+
+	// Refuse to baseline very large result sets since drift is
+	// calculated in memory.
+	MAX_ROWS = 100000
+
+	// How many of the client's recent flows to search for the
+	// latest collection of an artifact.
+	MAX_FLOWS = 1000
+)
+
+var (
+	mu         sync.Mutex
+	g_services = make(map[string]*BaselineService)
+
+	notRunningError = errors.New("Baseline service not running")
+	notFoundError   = errors.New("Baseline not found")
+
+	// These columns are added by the server and do not reflect the
+	// state of the client.
+	ignoredColumns = []string{"_Source", "_ts", "ClientId", "FlowId", "Fqdn"}
+)
+
+// An artifact source suitable for baselining and the columns which
+// identify a row.
+type BaselineArtifact struct {
+	Artifact string   `json:"artifact"`
+	Key      []string `json:"key"`
+}
+
+// The first collection of these artifacts becomes the client's
+// baseline automatically.
+var DesignatedArtifacts = []BaselineArtifact{
+	{Artifact: "Windows.System.Services", Key: []string{"Name"}},
+	{Artifact: "Windows.Sysinternals.Autoruns",
+		Key: []string{"Entry Location", "Entry"}},
+	{Artifact: "Windows.System.TaskScheduler/Analysis", Key: []string{"OSPath"}},
+	{Artifact: "Windows.Sys.Drivers/RunningDrivers", Key: []string{"Name"}},
+	{Artifact: "Windows.Sys.StartupItems", Key: []string{"Name", "OSPath"}},
+	{Artifact: "Linux.Sys.Services", Key: []string{"Unit"}},
+	{Artifact: "Linux.Sys.Crontab/CronTabs", Key: []string{"Path", "Command"}},
+}
+
+func GetDesignatedArtifact(artifact string) (*BaselineArtifact, bool) {
+	for _, item := range DesignatedArtifacts {
+		if item.Artifact == artifact {
+			return &item, true
+		}
+	}
+	return nil, false
+}
+
+type Baseline struct {
+	ClientId string `json:"client_id"`
+	Artifact string `json:"artifact"`
+
+	// The flow the baseline was taken from.
+	FlowId string `json:"flow_id"`
+
+	// Columns identifying a row. If empty, the whole row is the key
+	// so rows can only be added or removed.
+	Key []string `json:"key,omitempty"`
+
+	Creator    string `json:"creator,omitempty"`
+	Created    int64  `json:"created"`
+	TotalRows  int64  `json:"total_rows"`
+	Designated bool   `json:"designated,omitempty"`
+}
+
+// A difference between the baseline and a later collection.
+type Drift struct {
+	Status   string            `json:"status"`
+	Key      string            `json:"key"`
+	Row      *ordereddict.Dict `json:"row,omitempty"`
+	Baseline *ordereddict.Dict `json:"baseline,omitempty"`
+
+	// For changed rows, the columns which differ.
+	Changed []string `json:"changed,omitempty"`
+}
+
+type BaselineService struct {
+	config_obj *config_proto.Config
+}
+
+// Get the baseline service for the org.
+func GetBaselineService(
+	config_obj *config_proto.Config) (*BaselineService, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result, pres := g_services[utils.NormalizedOrgId(config_obj.OrgId)]
+	if !pres {
+		return nil, notRunningError
+	}
+	return result, nil
+}
+
+func NewBaselineService(config_obj *config_proto.Config) *BaselineService {
+	return &BaselineService{
+		config_obj: config_obj,
+	}
+}
+
+func getRawDB(config_obj *config_proto.Config) (
+	datastore.DataStore, datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, nil, errors.New("Datastore does not support raw access")
+	}
+	return db, raw_db, nil
+}
+
+// Get the client's baseline for the artifact.
+func (self *BaselineService) Get(
+	client_id, artifact string) (*Baseline, error) {
+	_, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := raw_db.GetBuffer(self.config_obj,
+		paths.NewClientPathManager(client_id).Baseline(artifact))
+	if err != nil {
+		return nil, notFoundError
+	}
+
+	result := &Baseline{}
+	err = json.Unmarshal(data, result)
+	if err != nil || result.Artifact != artifact {
+		return nil, notFoundError
+	}
+	return result, nil
+}
+
+// List all the client's baselines.
+func (self *BaselineService) List(client_id string) ([]*Baseline, error) {
+	db, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(self.config_obj,
+		paths.NewClientPathManager(client_id).BaselinesDirectory())
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Baseline{}
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		data, err := raw_db.GetBuffer(self.config_obj, child)
+		if err != nil {
+			continue
+		}
+
+		baseline := &Baseline{}
+		err = json.Unmarshal(data, baseline)
+		if err != nil || baseline.Artifact == "" {
+			continue
+		}
+		result = append(result, baseline)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Artifact < result[j].Artifact
+	})
+	return result, nil
+}
+
+// Take a new baseline from the flow's results. If no key is given,
+// the key of the designated artifact is used.
+func (self *BaselineService) Set(ctx context.Context,
+	principal, client_id, artifact, flow_id string,
+	key []string) (*Baseline, error) {
+
+	if client_id == "" || artifact == "" || flow_id == "" {
+		return nil, errors.New("client_id, artifact and flow_id must be specified")
+	}
+
+	designated, is_designated := GetDesignatedArtifact(artifact)
+	if len(key) == 0 && is_designated {
+		key = designated.Key
+	}
+
+	rows, err := self.readFlowResults(ctx, client_id, flow_id, artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	client_path_manager := paths.NewClientPathManager(client_id)
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	writer, err := result_sets.NewResultSetWriter(
+		file_store_factory, client_path_manager.BaselineRows(artifact),
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.TruncateMode)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		writer.Write(row)
+	}
+	writer.Close()
+
+	baseline := &Baseline{
+		ClientId:   client_id,
+		Artifact:   artifact,
+		FlowId:     flow_id,
+		Key:        key,
+		Creator:    principal,
+		Created:    utils.GetTime().Now().Unix(),
+		TotalRows:  int64(len(rows)),
+		Designated: is_designated,
+	}
+
+	serialized, err := json.Marshal(baseline)
+	if err != nil {
+		return nil, err
+	}
+
+	_, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	err = raw_db.SetBuffer(self.config_obj,
+		client_path_manager.Baseline(artifact),
+		serialized, utils.SyncCompleter)
+	return baseline, err
+}
+
+// Remove the client's baseline for the artifact.
+func (self *BaselineService) Delete(client_id, artifact string) error {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	client_path_manager := paths.NewClientPathManager(client_id)
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	_ = file_store_factory.Delete(client_path_manager.BaselineRows(artifact))
+
+	return db.DeleteSubject(self.config_obj,
+		client_path_manager.Baseline(artifact))
+}
+
+func (self *BaselineService) readFlowResults(ctx context.Context,
+	client_id, flow_id, artifact string) ([]*ordereddict.Dict, error) {
+	path_manager, err := artifacts.NewArtifactPathManager(ctx,
+		self.config_obj, client_id, flow_id, artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(
+		file_store_factory, path_manager.Path())
+	if err != nil {
+		return nil, fmt.Errorf("No results for %v in flow %v: %w",
+			artifact, flow_id, err)
+	}
+	defer reader.Close()
+
+	return readRows(ctx, reader)
+}
+
+func readRows(ctx context.Context,
+	reader result_sets.ResultSetReader) ([]*ordereddict.Dict, error) {
+	result := []*ordereddict.Dict{}
+	for row := range reader.Rows(ctx) {
+		if len(result) >= MAX_ROWS {
+			return nil, fmt.Errorf("Too many rows to baseline (max %v)",
+				MAX_ROWS)
+		}
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+// Find the most recent flow on the client with results for the
+// artifact.
+func (self *BaselineService) LatestFlow(ctx context.Context,
+	client_id, artifact string) (string, error) {
+	launcher, err := services.GetLauncher(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	flows, err := launcher.GetFlows(ctx, self.config_obj, client_id,
+		result_sets.ResultSetOptions{}, 0, MAX_FLOWS)
+	if err != nil {
+		return "", err
+	}
+
+	var latest *flows_proto.ArtifactCollectorContext
+	for _, flow := range flows.Items {
+		if utils.InString(flow.ArtifactsWithResults, artifact) &&
+			(latest == nil || flow.CreateTime > latest.CreateTime) {
+			latest = flow
+		}
+	}
+
+	if latest == nil {
+		return "", fmt.Errorf("No collections of %v found", artifact)
+	}
+	return latest.SessionId, nil
+}
+
+// Compare the flow's results to the baseline. If flow_id is empty,
+// the latest collection of the artifact is used. Returns the flow id
+// that was compared and the differences.
+func (self *BaselineService) Drift(ctx context.Context,
+	client_id, artifact, flow_id string) (string, []*Drift, error) {
+	baseline, err := self.Get(client_id, artifact)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if flow_id == "" {
+		flow_id, err = self.LatestFlow(ctx, client_id, artifact)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(file_store_factory,
+		paths.NewClientPathManager(client_id).BaselineRows(artifact))
+	if err != nil {
+		return "", nil, err
+	}
+	defer reader.Close()
+
+	baseline_rows, err := readRows(ctx, reader)
+	if err != nil {
+		return "", nil, err
+	}
+
+	current_rows, err := self.readFlowResults(ctx, client_id, flow_id, artifact)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return flow_id, Compare(baseline.Key, baseline_rows, current_rows), nil
+}
+
+// Compare two sets of rows. Rows are matched by the key columns.
+func Compare(key []string,
+	baseline_rows, current_rows []*ordereddict.Dict) []*Drift {
+	baseline_index := indexRows(key, baseline_rows)
+	current_index := indexRows(key, current_rows)
+
+	result := []*Drift{}
+	for _, k := range current_index.keys {
+		row := current_index.rows[k]
+		old_row, pres := baseline_index.rows[k]
+		if !pres {
+			result = append(result, &Drift{
+				Status: STATUS_ADDED,
+				Key:    k,
+				Row:    row,
+			})
+			continue
+		}
+
+		changed := changedColumns(old_row, row)
+		if len(changed) > 0 {
+			result = append(result, &Drift{
+				Status:   STATUS_CHANGED,
+				Key:      k,
+				Row:      row,
+				Baseline: old_row,
+				Changed:  changed,
+			})
+		}
+	}
+
+	for _, k := range baseline_index.keys {
+		_, pres := current_index.rows[k]
+		if !pres {
+			result = append(result, &Drift{
+				Status:   STATUS_REMOVED,
+				Key:      k,
+				Baseline: baseline_index.rows[k],
+			})
+		}
+	}
+
+	return result
+}
+
+type rowIndex struct {
+	// Keys in the order they were seen.
+	keys []string
+	rows map[string]*ordereddict.Dict
+}
+
+func indexRows(key []string, rows []*ordereddict.Dict) *rowIndex {
+	result := &rowIndex{rows: make(map[string]*ordereddict.Dict)}
+	for _, row := range rows {
+		k := rowKey(key, row)
+		if _, pres := result.rows[k]; pres {
+			continue
+		}
+		result.keys = append(result.keys, k)
+		result.rows[k] = row
+	}
+	return result
+}
+
+func rowKey(key []string, row *ordereddict.Dict) string {
+	if len(key) == 0 {
+		return serializeValue(stripRow(row))
+	}
+
+	parts := make([]string, 0, len(key))
+	for _, column := range key {
+		value, _ := row.Get(column)
+		parts = append(parts, utils.ToString(value))
+	}
+	return strings.Join(parts, " | ")
+}
+
+func stripRow(row *ordereddict.Dict) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+	for _, k := range row.Keys() {
+		if utils.InString(ignoredColumns, k) {
+			continue
+		}
+		v, _ := row.Get(k)
+		result.Set(k, v)
+	}
+	return result
+}
+
+func serializeValue(value interface{}) string {
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return utils.ToString(value)
+	}
+	return string(serialized)
+}
+
+func changedColumns(old_row, new_row *ordereddict.Dict) []string {
+	result := []string{}
+	seen := make(map[string]bool)
+
+	for _, row := range []*ordereddict.Dict{new_row, old_row} {
+		for _, k := range row.Keys() {
+			if seen[k] || utils.InString(ignoredColumns, k) {
+				continue
+			}
+			seen[k] = true
+
+			old_value, _ := old_row.Get(k)
+			new_value, _ := new_row.Get(k)
+			if serializeValue(old_value) != serializeValue(new_value) {
+				result = append(result, k)
+			}
+		}
+	}
+	return result
+}
+
+// Baseline designated artifacts the first time they are collected
+// from a client.
+func (self *BaselineService) ProcessFlowCompletion(ctx context.Context,
+	row *ordereddict.Dict) error {
+	flow, err := journal.GetFlowFromQueue(ctx, self.config_obj, row)
+	if err != nil {
+		return err
+	}
+
+	for _, artifact := range flow.ArtifactsWithResults {
+		_, pres := GetDesignatedArtifact(artifact)
+		if !pres {
+			continue
+		}
+
+		_, err := self.Get(flow.ClientId, artifact)
+		if err == nil {
+			continue
+		}
+
+		_, err = self.Set(ctx, "BaselineService",
+			flow.ClientId, artifact, flow.SessionId, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func StartBaselineService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if !services.IsMaster(config_obj) {
+		return nil
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Baseline service for %v.",
+		services.GetOrgName(config_obj))
+
+	self := NewBaselineService(config_obj)
+
+	org_id := utils.NormalizedOrgId(config_obj.OrgId)
+	mu.Lock()
+	g_services[org_id] = self
+	mu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		mu.Lock()
+		delete(g_services, org_id)
+		mu.Unlock()
+	}()
+
+	return journal.WatchQueueWithCB(ctx, config_obj, wg,
+		"System.Flow.Completion", "BaselineService",
+		func(ctx context.Context, config_obj *config_proto.Config,
+			row *ordereddict.Dict) error {
+			return self.ProcessFlowCompletion(ctx, row)
+		})
+}
@@ -0,0 +1,159 @@
+package baselines_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/baselines"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+const ARTIFACT = "Windows.System.Services"
+
+type BaselineTestSuite struct {
+	test_utils.TestSuite
+}
+
+var mock_definitions = []string{`
+name: Windows.System.Services
+sources:
+- query: SELECT * FROM scope()
+`}
+
+func (self *BaselineTestSuite) SetupTest() {
+	self.ConfigObj = self.TestSuite.LoadConfig()
+	self.LoadArtifactsIntoConfig(mock_definitions)
+
+	self.TestSuite.SetupTest()
+}
+
+// Write a completed collection of the services artifact.
+func (self *BaselineTestSuite) writeFlow(
+	flow_id string, create_time int64, rows []*ordereddict.Dict) {
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	assert.NoError(self.T(), launcher.Storage().WriteFlow(
+		self.Ctx, self.ConfigObj, &flows_proto.ArtifactCollectorContext{
+			ClientId:             "C.1",
+			SessionId:            flow_id,
+			CreateTime:           uint64(create_time),
+			State:                flows_proto.ArtifactCollectorContext_FINISHED,
+			ArtifactsWithResults: []string{ARTIFACT},
+			Request: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{ARTIFACT},
+			},
+		}, utils.SyncCompleter))
+
+	path_manager, err := artifacts.NewArtifactPathManager(self.Ctx,
+		self.ConfigObj, "C.1", flow_id, ARTIFACT)
+	assert.NoError(self.T(), err)
+
+	writer, err := result_sets.NewResultSetWriter(
+		file_store.GetFileStore(self.ConfigObj), path_manager.Path(),
+		json.DefaultEncOpts(), utils.SyncCompleter, result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	for _, row := range rows {
+		writer.Write(row)
+	}
+	writer.Close()
+}
+
+func service(name, state, path string) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Name", name).
+		Set("State", state).
+		Set("PathName", path).
+		Set("_Source", ARTIFACT)
+}
+
+func (self *BaselineTestSuite) TestDrift() {
+	closer := utils.MockTime(utils.NewMockClock(time.Unix(1700000000, 0)))
+	defer closer()
+
+	self.writeFlow("F.1", 100, []*ordereddict.Dict{
+		service("Dhcp", "Running", `C:\Windows\system32\svchost.exe`),
+		service("Spooler", "Running", `C:\Windows\System32\spoolsv.exe`),
+		service("WinDefend", "Running", `C:\ProgramData\MsMpEng.exe`),
+	})
+
+	service_obj := baselines.NewBaselineService(self.ConfigObj)
+
+	// The first collection of a designated artifact becomes the
+	// baseline.
+	assert.NoError(self.T(), service_obj.ProcessFlowCompletion(self.Ctx,
+		ordereddict.NewDict().
+			Set("ClientId", "C.1").
+			Set("FlowId", "F.1")))
+
+	baseline, err := service_obj.Get("C.1", ARTIFACT)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "F.1", baseline.FlowId)
+	assert.Equal(self.T(), []string{"Name"}, baseline.Key)
+	assert.Equal(self.T(), int64(3), baseline.TotalRows)
+
+	self.writeFlow("F.2", 200, []*ordereddict.Dict{
+		service("Dhcp", "Running", `C:\Windows\system32\svchost.exe`),
+		service("Spooler", "Running", `C:\Users\Public\spoolsv.exe`),
+		service("Updater", "Running", `C:\Temp\updater.exe`),
+	})
+
+	// Later collections do not replace the baseline.
+	assert.NoError(self.T(), service_obj.ProcessFlowCompletion(self.Ctx,
+		ordereddict.NewDict().
+			Set("ClientId", "C.1").
+			Set("FlowId", "F.2")))
+
+	// Drift is calculated against the latest collection by default.
+	flow_id, drift, err := service_obj.Drift(self.Ctx, "C.1", ARTIFACT, "")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "F.2", flow_id)
+
+	status := make(map[string]string)
+	for _, item := range drift {
+		status[item.Key] = item.Status
+	}
+	assert.Equal(self.T(), map[string]string{
+		"Spooler":   baselines.STATUS_CHANGED,
+		"Updater":   baselines.STATUS_ADDED,
+		"WinDefend": baselines.STATUS_REMOVED,
+	}, status)
+
+	for _, item := range drift {
+		if item.Status == baselines.STATUS_CHANGED {
+			assert.Equal(self.T(), []string{"PathName"}, item.Changed)
+		}
+	}
+
+	// Re-baselining on the latest collection removes the drift.
+	_, err = service_obj.Set(self.Ctx, "admin", "C.1", ARTIFACT, "F.2", nil)
+	assert.NoError(self.T(), err)
+
+	_, drift, err = service_obj.Drift(self.Ctx, "C.1", ARTIFACT, "")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(drift))
+
+	items, err := service_obj.List("C.1")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(items))
+	assert.Equal(self.T(), "admin", items[0].Creator)
+
+	assert.NoError(self.T(), service_obj.Delete("C.1", ARTIFACT))
+	_, err = service_obj.Get("C.1", ARTIFACT)
+	assert.Error(self.T(), err)
+}
+
+func TestBaselines(t *testing.T) {
+	suite.Run(t, &BaselineTestSuite{})
+}
@@ -0,0 +1,648 @@
+/*
+  The canary service manages canary tokens - decoy files, registry
+  keys and fake credentials planted on endpoints. Legitimate users
+  have no reason to touch them so any access is a strong signal of
+  an intruder poking around.
+
+  Canaries are planted by collecting Generic.Deploy.Canary on the
+  selected clients, which also enables OS auditing of the canary
+  path. Deployed clients are labeled so they run the
+  Generic.Detection.CanaryAccess event artifact. This service watches
+  that event queue, records each hit against the canary and raises
+  an alert in Server.Internal.Alerts.
+
+  Canary records are stored in the datastore and their hits in a
+  result set in the filestore.
+*/
+
+package canaries
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+const (
+	TYPE_FILE       = "file"
+	TYPE_REGISTRY   = "registry"
+	TYPE_CREDENTIAL = "credential"
+
+	DEPLOY_ARTIFACT    = "Generic.Deploy.Canary"
+	DETECTION_ARTIFACT = "Generic.Detection.CanaryAccess"
+
+	// Clients with deployed canaries receive this label so they
+	// run the detection artifact.
+	CANARY_LABEL = "Canary"
+
+	ALERT_NAME = "Canary Accessed"
+)
+
+var (
+	mu         sync.Mutex
+	g_services = make(map[string]*CanaryService)
+
+	notRunningError = errors.New("Canary service not running")
+	notFoundError   = errors.New("Canary not found")
+
+	canaryIdRegex = regexp.MustCompile("^CAN\\.[0-9A-Z]+$")
+)
+
+type Canary struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// One of file, registry or credential. Credential canaries are
+	// files containing fake cloud credentials.
+	Type string `json:"type"`
+
+	// The file path or registry value path on the endpoint.
+	Path string `json:"path"`
+
+	// The content of the file or registry value.
+	Value string `json:"value,omitempty"`
+
+	// Clients the canary was deployed to.
+	Clients []string `json:"clients,omitempty"`
+
+	Creator string `json:"creator,omitempty"`
+	Created int64  `json:"created"`
+	Hits    int64  `json:"hits"`
+	LastHit int64  `json:"last_hit,omitempty"`
+}
+
+type CanaryService struct {
+	mu         sync.Mutex
+	config_obj *config_proto.Config
+
+	canaries map[string]*Canary
+}
+
+// Get the canary service for the org.
+func GetCanaryService(
+	config_obj *config_proto.Config) (*CanaryService, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result, pres := g_services[utils.NormalizedOrgId(config_obj.OrgId)]
+	if !pres {
+		return nil, notRunningError
+	}
+	return result, nil
+}
+
+func NewCanaryService(config_obj *config_proto.Config) *CanaryService {
+	return &CanaryService{
+		config_obj: config_obj,
+		canaries:   make(map[string]*Canary),
+	}
+}
+
+func newCanaryId() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "CAN." + base32.HexEncoding.EncodeToString(buf)[:13]
+}
+
+// Generate a fake AWS credentials file. The keys look real enough
+// to be tempting but are not valid.
+func fakeCredentials() string {
+	buf := make([]byte, 40)
+	_, _ = rand.Read(buf)
+
+	key_id := "AKIA" + base32.StdEncoding.EncodeToString(buf[:10])[:16]
+	secret := base64.StdEncoding.EncodeToString(buf[10:])[:40]
+
+	return fmt.Sprintf("[default]\naws_access_key_id = %s\n"+
+		"aws_secret_access_key = %s\n", key_id, secret)
+}
+
+// Load all canaries from the datastore.
+func (self *CanaryService) Load() error {
+	db, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	children, err := db.ListChildren(self.config_obj, paths.CANARIES_ROOT)
+	if err != nil {
+		return err
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		data, err := raw_db.GetBuffer(self.config_obj, child)
+		if err != nil {
+			continue
+		}
+
+		canary := &Canary{}
+		err = json.Unmarshal(data, canary)
+		if err != nil || canary.Id == "" {
+			continue
+		}
+		self.canaries[canary.Id] = canary
+	}
+
+	return nil
+}
+
+func getRawDB(config_obj *config_proto.Config) (
+	datastore.DataStore, datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, nil, errors.New("Datastore does not support raw access")
+	}
+	return db, raw_db, nil
+}
+
+func (self *CanaryService) save(canary *Canary) error {
+	_, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(canary)
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(self.config_obj, paths.CanaryPath(canary.Id),
+		serialized, utils.BackgroundWriter)
+}
+
+// Create a new canary. It is not deployed to any clients yet.
+func (self *CanaryService) Create(
+	principal string, canary *Canary) (*Canary, error) {
+	switch canary.Type {
+	case TYPE_FILE, TYPE_REGISTRY:
+	case TYPE_CREDENTIAL:
+		if canary.Value == "" {
+			canary.Value = fakeCredentials()
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported canary type %v", canary.Type)
+	}
+
+	if canary.Path == "" {
+		return nil, errors.New("A canary path must be specified")
+	}
+
+	result := &Canary{
+		Id:          newCanaryId(),
+		Name:        canary.Name,
+		Description: canary.Description,
+		Type:        canary.Type,
+		Path:        canary.Path,
+		Value:       canary.Value,
+		Creator:     principal,
+		Created:     utils.GetTime().Now().Unix(),
+	}
+
+	if result.Name == "" {
+		result.Name = result.Id
+	}
+
+	err := self.save(result)
+	if err != nil {
+		return nil, err
+	}
+
+	self.mu.Lock()
+	self.canaries[result.Id] = result
+	self.mu.Unlock()
+
+	return copyCanary(result), nil
+}
+
+func copyCanary(canary *Canary) *Canary {
+	result := *canary
+	result.Clients = append([]string{}, canary.Clients...)
+	return &result
+}
+
+// Get a copy of the canary.
+func (self *CanaryService) Get(id string) (*Canary, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	canary, pres := self.canaries[id]
+	if !pres {
+		return nil, notFoundError
+	}
+	return copyCanary(canary), nil
+}
+
+// List all canaries sorted by name.
+func (self *CanaryService) List() []*Canary {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := make([]*Canary, 0, len(self.canaries))
+	for _, canary := range self.canaries {
+		result = append(result, copyCanary(canary))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Deploy the canary to the clients. Returns the flow ids of the
+// deployment collections.
+func (self *CanaryService) Deploy(ctx context.Context,
+	principal, id string, client_ids []string) ([]string, error) {
+	canary, err := self.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = self.ensureMonitoring(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	labeler := services.GetLabeler(self.config_obj)
+	if labeler == nil {
+		return nil, errors.New("Labeler not available")
+	}
+
+	result := []string{}
+	for _, client_id := range client_ids {
+		flow_id, err := self.schedule(ctx, principal, client_id, canary, false)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, flow_id)
+
+		err = labeler.SetClientLabel(ctx, self.config_obj, client_id, CANARY_LABEL)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	stored, pres := self.canaries[id]
+	if !pres {
+		return result, notFoundError
+	}
+
+	for _, client_id := range client_ids {
+		if !utils.InString(stored.Clients, client_id) {
+			stored.Clients = append(stored.Clients, client_id)
+		}
+	}
+
+	return result, self.save(stored)
+}
+
+// Delete the canary. If remove is set, the canary is also removed
+// from all the clients it was deployed to.
+func (self *CanaryService) Delete(ctx context.Context,
+	principal, id string, remove bool) error {
+	canary, err := self.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if remove {
+		for _, client_id := range canary.Clients {
+			_, err := self.schedule(ctx, principal, client_id, canary, true)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	err = db.DeleteSubject(self.config_obj, paths.CanaryPath(id))
+	if err != nil {
+		return err
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	_ = file_store_factory.Delete(paths.CanaryHitsPath(id))
+
+	self.mu.Lock()
+	delete(self.canaries, id)
+	self.mu.Unlock()
+
+	return nil
+}
+
+func (self *CanaryService) schedule(ctx context.Context,
+	principal, client_id string, canary *Canary, remove bool) (string, error) {
+	manager, err := services.GetRepositoryManager(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	launcher, err := services.GetLauncher(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	remove_str := "N"
+	if remove {
+		remove_str = "Y"
+	}
+
+	// The caller has already been checked for permission to
+	// manage canaries.
+	return launcher.ScheduleArtifactCollection(
+		ctx, self.config_obj, acl_managers.NullACLManager{},
+		repository,
+		&flows_proto.ArtifactCollectorArgs{
+			Creator:   principal,
+			ClientId:  client_id,
+			Artifacts: []string{DEPLOY_ARTIFACT},
+			Specs: []*flows_proto.ArtifactSpec{{
+				Artifact: DEPLOY_ARTIFACT,
+				Parameters: &flows_proto.ArtifactParameters{
+					Env: []*actions_proto.VQLEnv{
+						{Key: "CanaryId", Value: canary.Id},
+						{Key: "Type", Value: canary.Type},
+						{Key: "Path", Value: canary.Path},
+						{Key: "Value", Value: canary.Value},
+						{Key: "Remove", Value: remove_str},
+					},
+				},
+			}},
+		}, func() {
+			notifier, err := services.GetNotifier(self.config_obj)
+			if err == nil {
+				notifier.NotifyListener(ctx,
+					self.config_obj, client_id, "CanaryDeploy")
+			}
+		})
+}
+
+// Make sure clients with the canary label run the detection
+// artifact.
+func (self *CanaryService) ensureMonitoring(
+	ctx context.Context, principal string) error {
+	client_event_manager, err := services.ClientEventManager(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	state := client_event_manager.GetClientMonitoringState()
+	for _, label_events := range state.LabelEvents {
+		if label_events.Label == CANARY_LABEL &&
+			label_events.Artifacts != nil &&
+			utils.InString(label_events.Artifacts.Artifacts, DETECTION_ARTIFACT) {
+			return nil
+		}
+	}
+
+	var label_events *flows_proto.LabelEvents
+	for _, item := range state.LabelEvents {
+		if item.Label == CANARY_LABEL {
+			label_events = item
+			break
+		}
+	}
+
+	if label_events == nil {
+		label_events = &flows_proto.LabelEvents{Label: CANARY_LABEL}
+		state.LabelEvents = append(state.LabelEvents, label_events)
+	}
+
+	if label_events.Artifacts == nil {
+		label_events.Artifacts = &flows_proto.ArtifactCollectorArgs{}
+	}
+	label_events.Artifacts.Artifacts = append(
+		label_events.Artifacts.Artifacts, DETECTION_ARTIFACT)
+
+	return client_event_manager.SetClientMonitoringState(
+		ctx, self.config_obj, principal, state)
+}
+
+// Registry paths are reported by the audit log in their native form
+// (e.g. \REGISTRY\MACHINE\...) so normalize both sides before
+// comparing.
+func normalizePath(path string) string {
+	path = strings.ToLower(strings.ReplaceAll(path, "/", "\\"))
+	path = strings.TrimLeft(path, "\\")
+
+	for _, prefix := range []struct{ from, to string }{
+		{"hkey_local_machine\\", "registry\\machine\\"},
+		{"hklm\\", "registry\\machine\\"},
+		{"hkey_users\\", "registry\\user\\"},
+		{"hku\\", "registry\\user\\"},
+	} {
+		if strings.HasPrefix(path, prefix.from) {
+			return prefix.to + strings.TrimPrefix(path, prefix.from)
+		}
+	}
+	return path
+}
+
+// Find the canary deployed on the client at the path.
+func (self *CanaryService) findCanary(client_id, path string) (*Canary, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	normalized := normalizePath(path)
+	for _, canary := range self.canaries {
+		if !utils.InString(canary.Clients, client_id) {
+			continue
+		}
+
+		canary_path := normalizePath(canary.Path)
+
+		// Registry audit events refer to the key while the
+		// canary refers to the value.
+		if normalized == canary_path ||
+			(canary.Type == TYPE_REGISTRY &&
+				strings.HasPrefix(canary_path, normalized+"\\")) {
+			return canary, true
+		}
+	}
+	return nil, false
+}
+
+// Process a row from the detection artifact.
+func (self *CanaryService) ProcessEvent(ctx context.Context,
+	row *ordereddict.Dict) error {
+	client_id, _ := row.GetString("ClientId")
+	path, _ := row.GetString("Path")
+
+	canary, pres := self.findCanary(client_id, path)
+	if !pres {
+		return nil
+	}
+
+	now := utils.GetTime().Now()
+	hit := ordereddict.NewDict().
+		Set("Timestamp", now.Unix()).
+		Set("ClientId", client_id).
+		Set("Path", path)
+
+	for _, field := range []string{"User", "Process", "Details"} {
+		value, _ := row.Get(field)
+		hit.Set(field, value)
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	writer, err := result_sets.NewResultSetWriter(
+		file_store_factory, paths.CanaryHitsPath(canary.Id),
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.AppendMode)
+	if err != nil {
+		return err
+	}
+	writer.Write(hit)
+	writer.Close()
+
+	self.mu.Lock()
+	canary.Hits++
+	canary.LastHit = now.Unix()
+	err = self.save(canary)
+	alert := &services.AlertMessage{
+		ClientId:  client_id,
+		AlertName: ALERT_NAME,
+		Timestamp: now,
+		EventData: ordereddict.NewDict().
+			Set("CanaryId", canary.Id).
+			Set("Name", canary.Name).
+			Set("Type", canary.Type).
+			Set("Hit", hit),
+		Artifact:     DETECTION_ARTIFACT,
+		ArtifactType: "CLIENT_EVENT",
+	}
+	self.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	serialized = append(serialized, '\n')
+
+	journal_service, err := services.GetJournal(self.config_obj)
+	if err != nil {
+		return err
+	}
+	return journal_service.PushJsonlToArtifact(ctx, self.config_obj,
+		serialized, 1, "Server.Internal.Alerts", "server", "")
+}
+
+// Get the hit history of the canary.
+func (self *CanaryService) Hits(ctx context.Context,
+	id string) ([]*ordereddict.Dict, error) {
+	if !canaryIdRegex.MatchString(id) {
+		return nil, notFoundError
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(
+		file_store_factory, paths.CanaryHitsPath(id))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	result := []*ordereddict.Dict{}
+	for row := range reader.Rows(ctx) {
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+// Watch the detection artifact for canary hits. Only the master node
+// processes hits so they are counted once.
+func StartCanaryService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if !services.IsMaster(config_obj) {
+		return nil
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Canary service for %v.",
+		services.GetOrgName(config_obj))
+
+	self := NewCanaryService(config_obj)
+	err := self.Load()
+	if err != nil {
+		logger.Debug("CanaryService: No canaries loaded: %v", err)
+	}
+
+	org_id := utils.NormalizedOrgId(config_obj.OrgId)
+	mu.Lock()
+	g_services[org_id] = self
+	mu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		mu.Lock()
+		delete(g_services, org_id)
+		mu.Unlock()
+	}()
+
+	return journal.WatchQueueWithCB(ctx, config_obj, wg,
+		DETECTION_ARTIFACT, "CanaryService",
+		func(ctx context.Context, config_obj *config_proto.Config,
+			row *ordereddict.Dict) error {
+			err := self.ProcessEvent(ctx, row)
+			if err != nil {
+				logger.Error("CanaryService: %v", err)
+			}
+			return nil
+		})
+}
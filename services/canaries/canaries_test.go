@@ -0,0 +1,157 @@
+package canaries_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/canaries"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type CanaryTestSuite struct {
+	test_utils.TestSuite
+}
+
+var mock_definitions = []string{`
+name: Server.Internal.Alerts
+type: SERVER_EVENT
+`, `
+name: Generic.Deploy.Canary
+parameters:
+- name: CanaryId
+- name: Type
+- name: Path
+- name: Value
+- name: Remove
+  type: bool
+sources:
+- query: SELECT * FROM scope()
+`, `
+name: Generic.Detection.CanaryAccess
+type: CLIENT_EVENT
+sources:
+- query: SELECT * FROM scope()
+`}
+
+func (self *CanaryTestSuite) SetupTest() {
+	self.ConfigObj = self.TestSuite.LoadConfig()
+	self.ConfigObj.Services.ClientMonitoring = true
+
+	self.LoadArtifactsIntoConfig(mock_definitions)
+
+	self.TestSuite.SetupTest()
+}
+
+func (self *CanaryTestSuite) TestCanaries() {
+	closer := utils.MockTime(utils.NewMockClock(time.Unix(1700000000, 0)))
+	defer closer()
+
+	service := canaries.NewCanaryService(self.ConfigObj)
+
+	_, err := service.Create("admin", &canaries.Canary{Type: "unknown", Path: "/x"})
+	assert.Error(self.T(), err)
+
+	// Credential canaries receive generated fake credentials.
+	creds, err := service.Create("admin", &canaries.Canary{
+		Name: "Creds",
+		Type: canaries.TYPE_CREDENTIAL,
+		Path: `C:\Users\Public\.aws\credentials`,
+	})
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), strings.Contains(creds.Value, "aws_access_key_id = AKIA"))
+
+	reg, err := service.Create("admin", &canaries.Canary{
+		Name:  "Registry",
+		Type:  canaries.TYPE_REGISTRY,
+		Path:  `HKEY_LOCAL_MACHINE\SOFTWARE\Backup\AdminPassword`,
+		Value: "hunter2",
+	})
+	assert.NoError(self.T(), err)
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	err = client_info_manager.Set(self.Ctx, &services.ClientInfo{
+		ClientInfo: actions_proto.ClientInfo{ClientId: "C.1"},
+	})
+	assert.NoError(self.T(), err)
+
+	flow_ids, err := service.Deploy(self.Ctx, "admin", reg.Id, []string{"C.1"})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(flow_ids))
+
+	// The client is labeled and the label runs the detection
+	// artifact.
+	assert.True(self.T(), services.GetLabeler(self.ConfigObj).IsLabelSet(
+		self.Ctx, self.ConfigObj, "C.1", canaries.CANARY_LABEL))
+
+	client_event_manager, err := services.ClientEventManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	state := client_event_manager.GetClientMonitoringState()
+	found := false
+	for _, label_events := range state.LabelEvents {
+		if label_events.Label == canaries.CANARY_LABEL {
+			found = utils.InString(label_events.Artifacts.Artifacts,
+				canaries.DETECTION_ARTIFACT)
+		}
+	}
+	assert.True(self.T(), found)
+
+	// Events from other clients or paths are ignored.
+	for _, event := range []*ordereddict.Dict{
+		ordereddict.NewDict().
+			Set("ClientId", "C.2").
+			Set("Path", `\REGISTRY\MACHINE\SOFTWARE\Backup`),
+		ordereddict.NewDict().
+			Set("ClientId", "C.1").
+			Set("Path", `\REGISTRY\MACHINE\SOFTWARE\Other`),
+	} {
+		assert.NoError(self.T(), service.ProcessEvent(self.Ctx, event))
+	}
+
+	// Windows reports the native registry path of the key.
+	assert.NoError(self.T(), service.ProcessEvent(self.Ctx,
+		ordereddict.NewDict().
+			Set("ClientId", "C.1").
+			Set("Path", `\REGISTRY\MACHINE\SOFTWARE\Backup`).
+			Set("User", `CORP\mallory`).
+			Set("Process", `C:\Windows\regedit.exe`)))
+
+	hits, err := service.Hits(self.Ctx, reg.Id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(hits))
+
+	user, _ := hits[0].GetString("User")
+	assert.Equal(self.T(), `CORP\mallory`, user)
+
+	// Canaries survive a restart.
+	restored := canaries.NewCanaryService(self.ConfigObj)
+	assert.NoError(self.T(), restored.Load())
+
+	canary, err := restored.Get(reg.Id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(1), canary.Hits)
+	assert.Equal(self.T(), int64(1700000000), canary.LastHit)
+	assert.Equal(self.T(), []string{"C.1"}, canary.Clients)
+	assert.Equal(self.T(), 2, len(restored.List()))
+
+	// Deleting removes the canary and its hits.
+	assert.NoError(self.T(), restored.Delete(self.Ctx, "admin", reg.Id, true))
+	_, err = restored.Get(reg.Id)
+	assert.Error(self.T(), err)
+
+	hits, _ = restored.Hits(self.Ctx, reg.Id)
+	assert.Equal(self.T(), 0, len(hits))
+}
+
+func TestCanaries(t *testing.T) {
+	suite.Run(t, &CanaryTestSuite{})
+}
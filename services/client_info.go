@@ -61,9 +61,51 @@ func (self ClientInfo) OS() ClientOS {
 	return Unknown
 }
 
+type ClientInfoChangeType int
+
+const (
+	// The client record was set or modified.
+	ClientInfoChanged ClientInfoChangeType = iota
+
+	// Only the client stats (ping, ip address etc) changed.
+	ClientStatsChanged
+
+	ClientRemoved
+	ClientMetadataChanged
+
+	// All records were reloaded from the snapshot. ClientId is empty.
+	ClientInfoReloaded
+)
+
+// Sent to watchers when a client record changes. Watchers should
+// fetch the latest record from the ClientInfoManager if they need it.
+type ClientInfoChange struct {
+	ClientId string
+	Type     ClientInfoChangeType
+}
+
+// A point in time view of all client records. The records are
+// decoded on first access and shared between all users of the
+// snapshot so they must not be modified.
+type ClientInfoSnapshot interface {
+	Get(client_id string) (*ClientInfo, bool)
+	Keys() []string
+	Len() int
+}
+
 type ClientInfoManager interface {
 	ListClients(ctx context.Context) <-chan string
 
+	// Get a consistent view of all the clients. This is much cheaper
+	// than calling Get() for each client returned by ListClients().
+	Snapshot(ctx context.Context) ClientInfoSnapshot
+
+	// Receive notifications about changes to client records. Changes
+	// are dropped if the watcher does not keep up. The channel is
+	// closed when the context is done or cancel is called.
+	Watch(ctx context.Context, name string) (
+		output <-chan *ClientInfoChange, cancel func())
+
 	// Used to set a new client record. To modify an existing record -
 	// or set a new one use Modify()
 	Set(ctx context.Context,
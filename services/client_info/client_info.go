@@ -42,6 +42,7 @@ import (
 	"time"
 
 	"github.com/Velocidex/ordereddict"
+	"github.com/Velocidex/ttlcache/v2"
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/logging"
@@ -62,6 +63,23 @@ type ClientInfoManager struct {
 	mutation_manager *MutationManager
 
 	storage *Store
+
+	// Other services watch these for changes to client records.
+	changes *ChangeBus
+
+	// Cache client metadata to avoid reading it from the datastore
+	// each time.
+	metadata *ttlcache.Cache
+}
+
+func (self *ClientInfoManager) Snapshot(
+	ctx context.Context) services.ClientInfoSnapshot {
+	return self.storage.Snapshot()
+}
+
+func (self *ClientInfoManager) Watch(ctx context.Context, name string) (
+	<-chan *services.ClientInfoChange, func()) {
+	return self.changes.Watch(ctx, name)
 }
 
 func (self *ClientInfoManager) ListClients(ctx context.Context) <-chan string {
@@ -159,7 +177,13 @@ func (self *ClientInfoManager) UpdateStats(
 		record.LastEventTableVersion = stats.LastEventTableVersion
 	}
 
-	return self.storage.SetRecord(record)
+	err = self.storage.SetRecord(record)
+	if err != nil {
+		return err
+	}
+
+	self.changes.Publish(client_id, services.ClientStatsChanged)
+	return nil
 }
 
 func (self *ClientInfoManager) Start(
@@ -238,6 +262,15 @@ func (self *ClientInfoManager) Start(
 		return err
 	}
 
+	// Metadata may be changed on other frontends.
+	err = journal.WatchQueueWithCB(ctx, config_obj, wg,
+		"Server.Internal.MetadataModifications",
+		"ClientInfoManager",
+		self.ProcessMetadataModifications)
+	if err != nil {
+		return err
+	}
+
 	// The master will be informed when new clients appear.
 	err = journal.WatchQueueWithCB(ctx, config_obj, wg,
 		"Server.Internal.ClientPing",
@@ -266,7 +299,13 @@ func (self *ClientInfoManager) ProcessSnapshotWrites(
 
 	// If we receive a snapshot write broadcast then we are a minion
 	// and we must re-read the snapshot to receive the new data.
-	return self.storage.LoadFromSnapshot(ctx, config_obj)
+	err := self.storage.LoadFromSnapshot(ctx, config_obj)
+	if err != nil {
+		return err
+	}
+
+	self.changes.Publish("", services.ClientInfoReloaded)
+	return nil
 }
 
 // Send mutations periodically
@@ -360,6 +399,7 @@ func (self *ClientInfoManager) ProcessPing(
 			if err == nil {
 				record.Ping = uint64(value)
 				self.storage.SetRecord(record)
+				self.changes.Publish(client_id, services.ClientStatsChanged)
 			}
 		}
 	}
@@ -375,6 +415,7 @@ func (self *ClientInfoManager) ProcessPing(
 			if err == nil {
 				record.IpAddress = value
 				self.storage.SetRecord(record)
+				self.changes.Publish(client_id, services.ClientStatsChanged)
 			}
 		}
 	}
@@ -391,6 +432,7 @@ func (self *ClientInfoManager) ProcessPing(
 			if err == nil {
 				record.LastHuntTimestamp = uint64(value)
 				self.storage.SetRecord(record)
+				self.changes.Publish(client_id, services.ClientStatsChanged)
 			}
 		}
 	}
@@ -407,6 +449,7 @@ func (self *ClientInfoManager) ProcessPing(
 			if err == nil {
 				record.LastEventTableVersion = uint64(value)
 				self.storage.SetRecord(record)
+				self.changes.Publish(client_id, services.ClientStatsChanged)
 			}
 		}
 	}
@@ -418,7 +461,21 @@ func (self *ClientInfoManager) Modify(
 	ctx context.Context, client_id string,
 	modifier func(client_info *services.ClientInfo) (
 		*services.ClientInfo, error)) error {
-	return self.storage.Modify(ctx, client_id, modifier)
+	changed := false
+	err := self.storage.Modify(ctx, client_id,
+		func(client_info *services.ClientInfo) (*services.ClientInfo, error) {
+			new_record, err := modifier(client_info)
+			changed = err == nil && new_record != nil
+			return new_record, err
+		})
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		self.changes.Publish(client_id, services.ClientInfoChanged)
+	}
+	return nil
 }
 
 func (self *ClientInfoManager) Get(
@@ -444,6 +501,8 @@ func (self *ClientInfoManager) Get(
 
 func (self *ClientInfoManager) Remove(ctx context.Context, client_id string) {
 	self.storage.Remove(client_id)
+	self.metadata.Remove(client_id)
+	self.changes.Publish(client_id, services.ClientRemoved)
 }
 
 func (self *ClientInfoManager) Set(
@@ -453,7 +512,13 @@ func (self *ClientInfoManager) Set(
 		return invalidClientError
 	}
 
-	return self.storage.SetRecord(&client_info.ClientInfo)
+	err := self.storage.SetRecord(&client_info.ClientInfo)
+	if err != nil {
+		return err
+	}
+
+	self.changes.Publish(client_info.ClientId, services.ClientInfoChanged)
+	return nil
 }
 
 func NewClientInfoManager(
@@ -465,6 +530,8 @@ func NewClientInfoManager(
 		config_obj:       config_obj,
 		uuid:             utils.GetGUID(),
 		mutation_manager: NewMutationManager(),
+		changes:          NewChangeBus(),
+		metadata:         newMetadataCache(config_obj),
 	}
 	service.storage = NewStorage(service.uuid)
 
@@ -5,9 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Velocidex/ordereddict"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/protobuf/proto"
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
@@ -37,6 +39,9 @@ type: INTERNAL
 `, `
 name: Server.Internal.ClientInfoSnapshot
 type: INTERNAL
+`, `
+name: Server.Internal.MetadataModifications
+type: INTERNAL
 `})
 
 	// Create a client in the datastore so we can test initializing
@@ -127,6 +132,100 @@ func (self *ClientInfoTestSuite) TestClientInfo() {
 	assert.Equal(self.T(), info.IpAddress, "127.0.0.1")
 }
 
+func (self *ClientInfoTestSuite) TestSnapshotAndWatch() {
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	changes, cancel := client_info_manager.Watch(self.Ctx, "Test")
+
+	snapshot := client_info_manager.Snapshot(self.Ctx)
+	info, pres := snapshot.Get(self.client_id)
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), "Hostname", info.Hostname)
+
+	// The snapshot is reused while nothing changes.
+	assert.True(self.T(), snapshot == client_info_manager.Snapshot(self.Ctx))
+
+	err = client_info_manager.Modify(self.Ctx, self.client_id,
+		func(client_info *services.ClientInfo) (*services.ClientInfo, error) {
+			client_info.Labels = []string{"Label1"}
+			return client_info, nil
+		})
+	assert.NoError(self.T(), err)
+
+	// Unchanged records are not announced.
+	err = client_info_manager.Modify(self.Ctx, self.client_id,
+		func(client_info *services.ClientInfo) (*services.ClientInfo, error) {
+			return nil, nil
+		})
+	assert.NoError(self.T(), err)
+
+	client_info_manager.UpdateStats(self.Ctx, self.client_id,
+		&services.Stats{Ping: 10})
+	client_info_manager.Remove(self.Ctx, "C.DOESNOTEXIT")
+
+	// The old snapshot does not change but a new one has the changes.
+	info, _ = snapshot.Get(self.client_id)
+	assert.Equal(self.T(), 0, len(info.Labels))
+
+	info, _ = client_info_manager.Snapshot(self.Ctx).Get(self.client_id)
+	assert.Equal(self.T(), []string{"Label1"}, info.Labels)
+	assert.Equal(self.T(), uint64(10), info.Ping)
+
+	cancel()
+
+	received := []services.ClientInfoChange{}
+	for change := range changes {
+		received = append(received, *change)
+	}
+	assert.Equal(self.T(), []services.ClientInfoChange{
+		{ClientId: self.client_id, Type: services.ClientInfoChanged},
+		{ClientId: self.client_id, Type: services.ClientStatsChanged},
+		{ClientId: "C.DOESNOTEXIT", Type: services.ClientRemoved},
+	}, received)
+}
+
+func (self *ClientInfoTestSuite) TestMetadataCache() {
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	err = client_info_manager.SetMetadata(self.Ctx, self.client_id,
+		ordereddict.NewDict().Set("Owner", "Bob"), "admin")
+	assert.NoError(self.T(), err)
+
+	// Change the metadata behind the manager's back.
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	client_path_manager := paths.NewClientPathManager(self.client_id)
+	err = db.SetSubject(self.ConfigObj, client_path_manager.Metadata(),
+		&api_proto.ClientMetadata{
+			ClientId: self.client_id,
+			Items: []*api_proto.ClientMetadataItem{{
+				Key: "Owner", Value: "Alice"}},
+		})
+	assert.NoError(self.T(), err)
+
+	// Reads are served from the cache and can be modified safely.
+	metadata, err := client_info_manager.GetMetadata(self.Ctx, self.client_id)
+	assert.NoError(self.T(), err)
+	owner, _ := metadata.GetString("Owner")
+	assert.Equal(self.T(), "Bob", owner)
+	metadata.Set("Owner", "Changed")
+
+	// Other frontends announce their changes which clears the
+	// cache.
+	err = client_info_manager.(*client_info.ClientInfoManager).
+		ProcessMetadataModifications(self.Ctx, self.ConfigObj,
+			ordereddict.NewDict().Set("client_id", self.client_id))
+	assert.NoError(self.T(), err)
+
+	metadata, err = client_info_manager.GetMetadata(self.Ctx, self.client_id)
+	assert.NoError(self.T(), err)
+	owner, _ = metadata.GetString("Owner")
+	assert.Equal(self.T(), "Alice", owner)
+}
+
 // Check that master and minion update each other.
 func (self *ClientInfoTestSuite) TestMasterMinion() {
 	// Fetch the master client info manager
@@ -7,16 +7,36 @@ import (
 	"os"
 
 	"github.com/Velocidex/ordereddict"
+	"github.com/Velocidex/ttlcache/v2"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
-func (self ClientInfoManager) GetMetadata(ctx context.Context,
+func newMetadataCache(config_obj *config_proto.Config) *ttlcache.Cache {
+	expected_clients := int64(100)
+	if config_obj.Frontend != nil && config_obj.Frontend.Resources != nil &&
+		config_obj.Frontend.Resources.ExpectedClients > 0 {
+		expected_clients = config_obj.Frontend.Resources.ExpectedClients
+	}
+
+	result := ttlcache.NewCache()
+	result.SetCacheSizeLimit(int(expected_clients))
+	return result
+}
+
+// Callers are free to modify the returned dict.
+func (self *ClientInfoManager) GetMetadata(ctx context.Context,
 	client_id string) (*ordereddict.Dict, error) {
 
+	cached_any, err := self.metadata.Get(client_id)
+	if err == nil {
+		return copyDict(cached_any.(*ordereddict.Dict)), nil
+	}
+
 	client_path_manager := paths.NewClientPathManager(client_id)
 	db, err := datastore.GetDB(self.config_obj)
 	if err != nil {
@@ -36,10 +56,12 @@ func (self ClientInfoManager) GetMetadata(ctx context.Context,
 	for _, item := range result.Items {
 		result_dict.Set(item.Key, item.Value)
 	}
-	return result_dict, nil
+
+	self.metadata.Set(client_id, result_dict)
+	return copyDict(result_dict), nil
 }
 
-func (self ClientInfoManager) SetMetadata(ctx context.Context,
+func (self *ClientInfoManager) SetMetadata(ctx context.Context,
 	client_id string, metadata *ordereddict.Dict, principal string) error {
 
 	existing_metadata, err := self.GetMetadata(ctx, client_id)
@@ -105,9 +127,17 @@ func (self ClientInfoManager) SetMetadata(ctx context.Context,
 	err = db.SetSubject(self.config_obj,
 		client_path_manager.Metadata(), result)
 	if err != nil {
+		self.metadata.Remove(client_id)
 		return err
 	}
 
+	cached := ordereddict.NewDict()
+	for _, item := range result.Items {
+		cached.Set(item.Key, item.Value)
+	}
+	self.metadata.Set(client_id, cached)
+	self.changes.Publish(client_id, services.ClientMetadataChanged)
+
 	services.LogAudit(ctx,
 		self.config_obj, principal, "SetMetadata",
 		ordereddict.NewDict().
@@ -128,3 +158,27 @@ func (self ClientInfoManager) SetMetadata(ctx context.Context,
 				Set("updated_keys", updated_keys),
 		}, "Server.Internal.MetadataModifications", "server", "")
 }
+
+// Metadata may be changed by other frontends so we need to drop it
+// from the cache.
+func (self *ClientInfoManager) ProcessMetadataModifications(
+	ctx context.Context, config_obj *config_proto.Config,
+	row *ordereddict.Dict) error {
+
+	client_id, pres := row.GetString("client_id")
+	if !pres || client_id == "" {
+		return invalidError
+	}
+
+	self.metadata.Remove(client_id)
+	return nil
+}
+
+func copyDict(in *ordereddict.Dict) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+	for _, key := range in.Keys() {
+		value, _ := in.Get(key)
+		result.Set(key, value)
+	}
+	return result
+}
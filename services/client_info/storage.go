@@ -59,6 +59,79 @@ type Store struct {
 	uuid int64
 
 	dirty bool
+
+	// The last snapshot handed out. Reset on any change.
+	snapshot *Snapshot
+}
+
+// Records are only ever replaced in the data map so the snapshot can
+// share the serialized records with the store.
+type Snapshot struct {
+	mu      sync.Mutex
+	data    map[string][]byte
+	decoded map[string]*services.ClientInfo
+}
+
+func (self *Snapshot) Get(client_id string) (*services.ClientInfo, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	record, pres := self.decoded[client_id]
+	if pres {
+		return record, true
+	}
+
+	serialized, pres := self.data[client_id]
+	if !pres {
+		return nil, false
+	}
+
+	record = &services.ClientInfo{}
+	err := proto.Unmarshal(serialized, &record.ClientInfo)
+	if err != nil {
+		return nil, false
+	}
+
+	if record.ClientId == "" {
+		record.ClientId = client_id
+	}
+
+	self.decoded[client_id] = record
+	return record, true
+}
+
+func (self *Snapshot) Keys() []string {
+	result := make([]string, 0, len(self.data))
+	for k := range self.data {
+		result = append(result, k)
+	}
+	return result
+}
+
+func (self *Snapshot) Len() int {
+	return len(self.data)
+}
+
+// Get a snapshot of all records. The snapshot is reused until the
+// store changes.
+func (self *Store) Snapshot() *Snapshot {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.snapshot != nil {
+		return self.snapshot
+	}
+
+	data := make(map[string][]byte, len(self.data))
+	for k, v := range self.data {
+		data[k] = v
+	}
+
+	self.snapshot = &Snapshot{
+		data:    data,
+		decoded: make(map[string]*services.ClientInfo),
+	}
+	return self.snapshot
 }
 
 func (self *Store) Keys() []string {
@@ -77,6 +150,7 @@ func (self *Store) Remove(client_id string) {
 	defer self.mu.Unlock()
 
 	delete(self.data, client_id)
+	self.snapshot = nil
 }
 
 func (self *Store) Modify(
@@ -152,6 +226,7 @@ func (self *Store) _SetRecord(record *actions_proto.ClientInfo) error {
 
 	self.data[record.ClientId] = serialized
 	self.dirty = true
+	self.snapshot = nil
 	return nil
 }
 
@@ -177,6 +252,7 @@ func (self *Store) LoadFromSnapshot(
 
 	self.data = make(map[string][]byte)
 	self.dirty = false
+	self.snapshot = nil
 
 	// Highly optimized reader for speed.
 	json_chan, err := reader.JSON(ctx)
@@ -364,6 +440,7 @@ func (self *Store) LoadSnapshotFromLegacyData(
 		self.mu.Lock()
 		self.data[client_id] = serialized
 		self.dirty = true
+		self.snapshot = nil
 		self.mu.Unlock()
 
 	}
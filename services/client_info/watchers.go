@@ -0,0 +1,101 @@
+package client_info
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var (
+	metricClientChangesDropped = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "client_info_changes_dropped",
+			Help: "Client change notifications dropped because a watcher was too slow",
+		})
+)
+
+const (
+	WATCHER_BUFFER_SIZE = 1000
+)
+
+type changeWatcher struct {
+	name   string
+	output chan *services.ClientInfoChange
+}
+
+// Fans out client changes to all interested services. Sending never
+// blocks so a slow watcher can not hold up the client info manager.
+type ChangeBus struct {
+	mu       sync.Mutex
+	id       uint64
+	watchers map[uint64]*changeWatcher
+}
+
+func (self *ChangeBus) Watch(ctx context.Context, name string) (
+	<-chan *services.ClientInfoChange, func()) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.id++
+	id := self.id
+	watcher := &changeWatcher{
+		name:   name,
+		output: make(chan *services.ClientInfoChange, WATCHER_BUFFER_SIZE),
+	}
+	self.watchers[id] = watcher
+
+	done := make(chan bool)
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			self.mu.Lock()
+			defer self.mu.Unlock()
+
+			delete(self.watchers, id)
+			close(watcher.output)
+			close(done)
+		})
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancel()
+		case <-done:
+		}
+	}()
+
+	return watcher.output, cancel
+}
+
+func (self *ChangeBus) Publish(
+	client_id string, change_type services.ClientInfoChangeType) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if len(self.watchers) == 0 {
+		return
+	}
+
+	change := &services.ClientInfoChange{
+		ClientId: client_id,
+		Type:     change_type,
+	}
+
+	for _, watcher := range self.watchers {
+		select {
+		case watcher.output <- change:
+		default:
+			metricClientChangesDropped.Inc()
+		}
+	}
+}
+
+func NewChangeBus() *ChangeBus {
+	return &ChangeBus{
+		watchers: make(map[uint64]*changeWatcher),
+	}
+}
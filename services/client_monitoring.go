@@ -21,6 +21,7 @@ package services
 import (
 	"context"
 
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
@@ -52,6 +53,21 @@ type ClientEventTable interface {
 		config_obj *config_proto.Config,
 		client_id string) *crypto_proto.VeloMessage
 
+	// Like GetClientUpdateEventTableMessage() but clients which
+	// cache their event table only receive the changed queries.
+	GetClientUpdateEventTableDelta(
+		ctx context.Context,
+		config_obj *config_proto.Config,
+		client_id string) *crypto_proto.VeloMessage
+
+	// Process the client's ForemanCheckin. Returns true if the
+	// client was unable to apply a delta update and needs to be
+	// sent the full table.
+	ProcessClientCheckin(
+		ctx context.Context,
+		config_obj *config_proto.Config,
+		client_id string, checkin *actions_proto.ForemanCheckin) bool
+
 	// Get the full client monitoring table.
 	GetClientMonitoringState() *flows_proto.ClientEventTable
 
@@ -70,6 +70,9 @@ type ClientEventTable struct {
 	// protobufs in memory.
 	state *flows_proto.ClientEventTable
 
+	// Tracks the queries cached by clients to send them deltas.
+	delta *deltaTracker
+
 	Clock utils.Clock
 
 	// There is a separate manager for each org.
@@ -194,6 +197,7 @@ func (self *ClientEventTable) compileState(
 		return err
 	}
 	state.Artifacts.CompiledCollectorArgs = compiled
+	self.delta.ResetHashes()
 
 	// Now compile the label specific events
 	for _, table := range state.LabelEvents {
@@ -290,6 +294,40 @@ func (self *ClientEventTable) GetClientUpdateEventTableMessage(
 	ctx context.Context,
 	config_obj *config_proto.Config,
 	client_id string) *crypto_proto.VeloMessage {
+	return self.getClientUpdateEventTableMessage(ctx, config_obj, client_id, nil)
+}
+
+// Clients which cache their event table only receive the queries
+// which changed since the last table they were sent.
+func (self *ClientEventTable) GetClientUpdateEventTableDelta(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id string) *crypto_proto.VeloMessage {
+	cached := self.delta.Get(client_id)
+	if cached == nil {
+		return self.GetClientUpdateEventTableMessage(ctx, config_obj, client_id)
+	}
+
+	result := self.getClientUpdateEventTableMessage(
+		ctx, config_obj, client_id, cached)
+	self.delta.Sent(client_id, result.UpdateEventTable)
+
+	return result
+}
+
+func (self *ClientEventTable) ProcessClientCheckin(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id string, checkin *actions_proto.ForemanCheckin) bool {
+	return self.delta.Checkin(client_id, checkin)
+}
+
+// If cached is specified, only queries not in it are included with
+// the hashes of the full table.
+func (self *ClientEventTable) getClientUpdateEventTableMessage(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id string, cached map[string]bool) *crypto_proto.VeloMessage {
 	self.mu.Lock()
 	state := self.state
 	self.mu.Unlock()
@@ -302,8 +340,20 @@ func (self *ClientEventTable) GetClientUpdateEventTableMessage(
 		state.Artifacts = &flows_proto.ArtifactCollectorArgs{}
 	}
 
+	add_event := func(event *actions_proto.VQLCollectorArgs) {
+		if cached != nil {
+			hash := self.delta.Hash(event)
+			result.EventHashes = append(result.EventHashes, hash)
+			if cached[hash] {
+				return
+			}
+		}
+		result.Event = append(result.Event,
+			proto.Clone(event).(*actions_proto.VQLCollectorArgs))
+	}
+
 	for _, event := range state.Artifacts.CompiledCollectorArgs {
-		result.Event = append(result.Event, proto.Clone(event).(*actions_proto.VQLCollectorArgs))
+		add_event(event)
 	}
 
 	// Now apply any event queries that belong to this client based on labels.
@@ -311,8 +361,7 @@ func (self *ClientEventTable) GetClientUpdateEventTableMessage(
 	for _, table := range state.LabelEvents {
 		if labeler.IsLabelSet(ctx, config_obj, client_id, table.Label) {
 			for _, event := range table.Artifacts.CompiledCollectorArgs {
-				result.Event = append(result.Event,
-					proto.Clone(event).(*actions_proto.VQLCollectorArgs))
+				add_event(event)
 			}
 		}
 	}
@@ -498,6 +547,7 @@ func NewClientMonitoringService(
 		Clock:      &utils.RealClock{},
 		id:         uuid.New().String(),
 		config_obj: config_obj,
+		delta:      newDeltaTracker(),
 	}
 
 	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
@@ -0,0 +1,136 @@
+package client_monitoring
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ttlcache/v2"
+	"www.velocidex.com/golang/velociraptor/actions"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+)
+
+const (
+	// Forget what we sent to clients which have not checked in for
+	// this long. They will receive a full table next time.
+	clientCacheTTL = 24 * time.Hour
+)
+
+// What we know about the event queries cached on a client.
+type clientCacheRecord struct {
+	// Hashes of the queries in the last table sent to the client.
+	hashes map[string]bool
+
+	// The last update omitted queries the client should have
+	// cached.
+	delta bool
+}
+
+// Tracks the event tables cached by clients so updates only need to
+// carry the queries which changed.
+type deltaTracker struct {
+	mu sync.Mutex
+
+	// Clients which support delta updates, keyed by client id.
+	clients *ttlcache.Cache
+
+	// Content hashes of the compiled queries. Compiled queries are
+	// immutable until the state is recompiled so we only hash them
+	// once.
+	hashes map[*actions_proto.VQLCollectorArgs]string
+}
+
+func (self *deltaTracker) Hash(event *actions_proto.VQLCollectorArgs) string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	hash, pres := self.hashes[event]
+	if !pres {
+		hash = actions.EventQueryHash(event)
+		self.hashes[event] = hash
+	}
+	return hash
+}
+
+// Called when the state is recompiled.
+func (self *deltaTracker) ResetHashes() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.hashes = make(map[*actions_proto.VQLCollectorArgs]string)
+}
+
+// Returns the hashes cached by the client or nil if the client does
+// not support delta updates.
+func (self *deltaTracker) Get(client_id string) map[string]bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	record, err := self.clients.Get(client_id)
+	if err != nil {
+		return nil
+	}
+
+	result := record.(*clientCacheRecord).hashes
+	if result == nil {
+		result = make(map[string]bool)
+	}
+	return result
+}
+
+// Record the table we sent the client.
+func (self *deltaTracker) Sent(client_id string, table *actions_proto.VQLEventTable) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	record_any, err := self.clients.Get(client_id)
+	if err != nil {
+		return
+	}
+
+	record := record_any.(*clientCacheRecord)
+	record.hashes = make(map[string]bool)
+	for _, hash := range table.EventHashes {
+		record.hashes[hash] = true
+	}
+	record.delta = len(table.Event) < len(table.EventHashes)
+}
+
+// Process the client's checkin. Returns true if the client was sent
+// a delta it could not apply and needs the full table.
+func (self *deltaTracker) Checkin(
+	client_id string, checkin *actions_proto.ForemanCheckin) bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if !checkin.EventTableDelta {
+		_ = self.clients.Remove(client_id)
+		return false
+	}
+
+	record_any, err := self.clients.Get(client_id)
+	if err != nil {
+		_ = self.clients.Set(client_id, &clientCacheRecord{})
+		return false
+	}
+
+	// Clients reset their version when they are unable to apply the
+	// delta.
+	record := record_any.(*clientCacheRecord)
+	if checkin.LastEventTableVersion == 0 && record.delta {
+		record.hashes = nil
+		record.delta = false
+		return true
+	}
+
+	return false
+}
+
+func newDeltaTracker() *deltaTracker {
+	result := &deltaTracker{
+		clients: ttlcache.NewCache(),
+		hashes:  make(map[*actions_proto.VQLCollectorArgs]string),
+	}
+	_ = result.clients.SetTTL(clientCacheTTL)
+
+	return result
+}
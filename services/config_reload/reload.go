@@ -0,0 +1,269 @@
+// Reload the parts of the config which are safe to change while the
+// frontend is running (e.g. on SIGHUP). Changes to other sections
+// are ignored and require a restart.
+package config_reload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/webhooks"
+)
+
+var (
+	mu          sync.Mutex
+	config_path string
+
+	noConfigFileError = errors.New(
+		"Config reload: The config was not loaded from a file")
+)
+
+// A config section which may be reloaded.
+type section struct {
+	name string
+
+	// Returns the section for comparison.
+	get func(config_obj *config_proto.Config) interface{}
+
+	// Copy the section from src to dst.
+	copy func(dst, src *config_proto.Config)
+
+	// Check the new section before we change anything.
+	validate func(config_obj *config_proto.Config) error
+
+	// Make the change effective. Called with the updated root
+	// config.
+	apply func(ctx context.Context, config_obj *config_proto.Config) error
+}
+
+var sections = []section{{
+	name: "GUI.links",
+	get: func(config_obj *config_proto.Config) interface{} {
+		return config_obj.GetGUI().GetLinks()
+	},
+	copy: func(dst, src *config_proto.Config) {
+		if dst.GUI != nil {
+			dst.GUI.Links = src.GetGUI().GetLinks()
+		}
+	},
+}, {
+	name: "Frontend.dyn_dns",
+	get: func(config_obj *config_proto.Config) interface{} {
+		return config_obj.GetFrontend().GetDynDns()
+	},
+	copy: func(dst, src *config_proto.Config) {
+		if dst.Frontend != nil {
+			dst.Frontend.DynDns = src.GetFrontend().GetDynDns()
+		}
+	},
+}, {
+	// How often the free disk space is monitored.
+	name: "Datastore.disk_check",
+	get: func(config_obj *config_proto.Config) interface{} {
+		return []int64{
+			config_obj.GetDatastore().GetDiskCheckFrequencySec(),
+			config_obj.GetDatastore().GetMinAllowedFileSpaceMb(),
+		}
+	},
+	copy: func(dst, src *config_proto.Config) {
+		if dst.Datastore != nil {
+			dst.Datastore.DiskCheckFrequencySec =
+				src.GetDatastore().GetDiskCheckFrequencySec()
+			dst.Datastore.MinAllowedFileSpaceMb =
+				src.GetDatastore().GetMinAllowedFileSpaceMb()
+		}
+	},
+}, {
+	name: "Defaults.webhooks",
+	get: func(config_obj *config_proto.Config) interface{} {
+		return config_obj.GetDefaults().GetWebhooks()
+	},
+	copy: func(dst, src *config_proto.Config) {
+		if dst.Defaults == nil {
+			dst.Defaults = &config_proto.Defaults{}
+		}
+		dst.Defaults.Webhooks = src.GetDefaults().GetWebhooks()
+	},
+	validate: func(config_obj *config_proto.Config) error {
+		_, _, err := webhooks.ParseDestinations(config_obj)
+		return err
+	},
+	apply: func(ctx context.Context, config_obj *config_proto.Config) error {
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			return err
+		}
+
+		// The service only runs if destinations were configured at
+		// startup.
+		for _, org_record := range org_manager.ListOrgs() {
+			org_config, err := org_manager.GetOrgConfig(org_record.Id)
+			if err != nil {
+				continue
+			}
+
+			service, err := services.GetWebhookService(org_config)
+			if err != nil {
+				continue
+			}
+
+			err = service.SetDestinations(config_obj)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}, {
+	name: "Logging",
+	get: func(config_obj *config_proto.Config) interface{} {
+		return config_obj.Logging
+	},
+	copy: func(dst, src *config_proto.Config) {
+		dst.Logging = src.Logging
+	},
+	apply: func(ctx context.Context, config_obj *config_proto.Config) error {
+		return logging.InitLogging(config_obj)
+	},
+}}
+
+// Remember the file the config was loaded from so it can be
+// reloaded later.
+func SetConfigPath(path string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	config_path = path
+}
+
+// Reload the config from the file it was loaded from.
+func Reload(ctx context.Context, principal string) ([]string, error) {
+	mu.Lock()
+	path := config_path
+	mu.Unlock()
+
+	if path == "" {
+		return nil, noConfigFileError
+	}
+
+	new_config, err := config.ReadConfigFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Config reload: %w", err)
+	}
+
+	return ReloadConfig(ctx, principal, new_config)
+}
+
+// Apply the safe sections of new_config to all orgs. If the new
+// config is invalid nothing is changed, and if any section fails to
+// apply all changes are rolled back. Returns the changed sections.
+func ReloadConfig(ctx context.Context, principal string,
+	new_config *config_proto.Config) ([]string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	err := config.ValidateFrontendConfig(new_config)
+	if err != nil {
+		return nil, fmt.Errorf("Config reload: %w", err)
+	}
+
+	org_manager, err := services.GetOrgManager()
+	if err != nil {
+		return nil, err
+	}
+
+	root_config, err := org_manager.GetOrgConfig(services.ROOT_ORG_ID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []section{}
+	for _, s := range sections {
+		if json.MustMarshalString(s.get(root_config)) ==
+			json.MustMarshalString(s.get(new_config)) {
+			continue
+		}
+
+		if s.validate != nil {
+			err := s.validate(new_config)
+			if err != nil {
+				return nil, fmt.Errorf("Config reload: %v: %w", s.name, err)
+			}
+		}
+		changed = append(changed, s)
+	}
+
+	names := []string{}
+	if len(changed) == 0 {
+		return names, nil
+	}
+
+	// Keep the old sections for rollback.
+	old_config := &config_proto.Config{
+		GUI:       &config_proto.GUIConfig{},
+		Frontend:  &config_proto.FrontendConfig{},
+		Datastore: &config_proto.DatastoreConfig{},
+	}
+	for _, s := range changed {
+		s.copy(old_config, root_config)
+	}
+
+	logger := logging.GetLogger(root_config, &logging.FrontendComponent)
+	for idx, s := range changed {
+		setSection(org_manager, s, new_config)
+		names = append(names, s.name)
+
+		if s.apply == nil {
+			continue
+		}
+
+		err := s.apply(ctx, root_config)
+		if err != nil {
+			logger.Error("Config reload: %v: %v - rolling back", s.name, err)
+			rollback(ctx, org_manager, root_config, changed[:idx+1], old_config)
+			return nil, fmt.Errorf("Config reload: %v: %w", s.name, err)
+		}
+	}
+
+	logger = logging.GetLogger(root_config, &logging.FrontendComponent)
+	logger.Info("<green>Config reload</>: Reloaded %v", names)
+
+	err = services.LogAudit(ctx, root_config, principal, "ReloadConfig",
+		ordereddict.NewDict().Set("sections", names))
+	return names, err
+}
+
+// Update the section in every org's config.
+func setSection(org_manager services.OrgManager,
+	s section, src *config_proto.Config) {
+	for _, org := range org_manager.ListOrgs() {
+		org_config, err := org_manager.GetOrgConfig(org.Id)
+		if err == nil {
+			s.copy(org_config, src)
+		}
+	}
+}
+
+func rollback(ctx context.Context, org_manager services.OrgManager,
+	root_config *config_proto.Config,
+	applied []section, old_config *config_proto.Config) {
+	logger := logging.GetLogger(root_config, &logging.FrontendComponent)
+
+	for _, s := range applied {
+		setSection(org_manager, s, old_config)
+		if s.apply != nil {
+			err := s.apply(ctx, root_config)
+			if err != nil {
+				logger.Error("Config reload: Rolling back %v: %v", s.name, err)
+			}
+		}
+	}
+}
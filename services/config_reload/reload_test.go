@@ -0,0 +1,100 @@
+package config_reload_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services/config_reload"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type ReloadTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *ReloadTestSuite) SetupTest() {
+	// Tests modify the config so start fresh each time.
+	self.ConfigObj = self.LoadConfig()
+	self.TestSuite.SetupTest()
+}
+
+func (self *ReloadTestSuite) newConfig() *config_proto.Config {
+	return proto.Clone(self.ConfigObj).(*config_proto.Config)
+}
+
+func (self *ReloadTestSuite) TestReload() {
+	// Nothing changed.
+	changed, err := config_reload.ReloadConfig(
+		self.Ctx, "admin", self.newConfig())
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(changed))
+
+	new_config := self.newConfig()
+	new_config.GUI.Links = []*config_proto.GUILink{{
+		Text: "Wiki",
+		Url:  "https://wiki.example.com/",
+	}}
+	new_config.Datastore.DiskCheckFrequencySec = 30
+
+	// Sections which can not be reloaded are ignored.
+	new_config.Frontend.BindPort = 1234
+	bind_port := self.ConfigObj.Frontend.BindPort
+
+	changed, err = config_reload.ReloadConfig(self.Ctx, "admin", new_config)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), []string{"GUI.links", "Datastore.disk_check"}, changed)
+
+	assert.Equal(self.T(), "Wiki", self.ConfigObj.GUI.Links[0].Text)
+	assert.Equal(self.T(), int64(30), self.ConfigObj.Datastore.DiskCheckFrequencySec)
+	assert.Equal(self.T(), bind_port, self.ConfigObj.Frontend.BindPort)
+}
+
+func (self *ReloadTestSuite) TestInvalidConfig() {
+	new_config := self.newConfig()
+	new_config.GUI.Links = []*config_proto.GUILink{{Text: "Wiki"}}
+	new_config.Defaults.Webhooks = []*config_proto.WebhookConfig{{
+		Name: "Soar", Url: "https://soar.example.com/1",
+	}, {
+		Name: "Soar", Url: "https://soar.example.com/2",
+	}}
+
+	// Nothing is changed.
+	_, err := config_reload.ReloadConfig(self.Ctx, "admin", new_config)
+	assert.ErrorContains(self.T(), err, "Duplicate destination")
+	assert.Equal(self.T(), 0, len(self.ConfigObj.GUI.Links))
+	assert.Equal(self.T(), 0, len(self.ConfigObj.Defaults.Webhooks))
+}
+
+func (self *ReloadTestSuite) TestRollback() {
+	// Logging can not be initialized into a file.
+	tmpfile := filepath.Join(self.T().TempDir(), "file")
+	assert.NoError(self.T(), ioutil.WriteFile(tmpfile, nil, 0600))
+
+	new_config := self.newConfig()
+	new_config.GUI.Links = []*config_proto.GUILink{{Text: "Wiki"}}
+	new_config.Logging = &config_proto.LoggingConfig{
+		OutputDirectory: tmpfile,
+	}
+
+	_, err := config_reload.ReloadConfig(self.Ctx, "admin", new_config)
+	assert.ErrorContains(self.T(), err, "Logging")
+
+	// The applied sections are rolled back.
+	assert.Equal(self.T(), 0, len(self.ConfigObj.GUI.Links))
+	assert.True(self.T(), self.ConfigObj.Logging.GetOutputDirectory() != tmpfile)
+}
+
+func (self *ReloadTestSuite) TestReloadFile() {
+	config_reload.SetConfigPath("")
+	_, err := config_reload.Reload(self.Ctx, "admin")
+	assert.Error(self.T(), err)
+}
+
+func TestReload(t *testing.T) {
+	suite.Run(t, &ReloadTestSuite{})
+}
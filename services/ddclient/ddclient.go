@@ -34,6 +34,8 @@ func (self *DynDNSService) updateIP(config_obj *config_proto.Config) {
 		return
 	}
 
+	self.loadSettings(config_obj)
+
 	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
 	logger.Info("Checking DNS with %v", self.external_ip_url)
 
@@ -88,11 +90,6 @@ func (self *DynDNSService) Start(
 	logger.Info("<green>Starting</> the DynDNS service: Updating hostname %v with checkip URL %v",
 		config_obj.Frontend.Hostname, self.external_ip_url)
 
-	min_update_wait := config_obj.Frontend.DynDns.Frequency
-	if min_update_wait == 0 {
-		min_update_wait = 60
-	}
-
 	// First time check immediately.
 	self.updateIP(config_obj)
 
@@ -104,12 +101,37 @@ func (self *DynDNSService) Start(
 			// Do not try to update sooner than this or we
 			// get banned. It takes a while for dns
 			// records to propagate.
-		case <-time.After(time.Duration(min_update_wait) * time.Second):
+		case <-time.After(getFrequency(config_obj)):
 			self.updateIP(config_obj)
 		}
 	}
 }
 
+func getFrequency(config_obj *config_proto.Config) time.Duration {
+	min_update_wait := config_obj.GetFrontend().GetDynDns().GetFrequency()
+	if min_update_wait == 0 {
+		min_update_wait = 60
+	}
+	return time.Duration(min_update_wait) * time.Second
+}
+
+// The settings may change when the config is reloaded so we
+// reload them before each update.
+func (self *DynDNSService) loadSettings(config_obj *config_proto.Config) {
+	self.external_ip_url = config_obj.GetFrontend().GetDynDns().GetCheckipUrl()
+	self.dns_server = config_obj.GetFrontend().GetDynDns().GetDnsServer()
+
+	// Set sensible defaults that should work reliably most of the
+	// time.
+	if self.external_ip_url == "" {
+		self.external_ip_url = "https://domains.google.com/checkip"
+	}
+
+	if self.dns_server == "" {
+		self.dns_server = "8.8.8.8:53"
+	}
+}
+
 func StartDynDNSService(
 	ctx context.Context,
 	wg *sync.WaitGroup,
@@ -123,20 +145,9 @@ func StartDynDNSService(
 	}
 
 	result := &DynDNSService{
-		config_obj:      config_obj,
-		external_ip_url: config_obj.Frontend.DynDns.CheckipUrl,
-		dns_server:      config_obj.Frontend.DynDns.DnsServer,
-	}
-
-	// Set sensible defaults that should work reliably most of the
-	// time.
-	if result.external_ip_url == "" {
-		result.external_ip_url = "https://domains.google.com/checkip"
-	}
-
-	if result.dns_server == "" {
-		result.dns_server = "8.8.8.8:53"
+		config_obj: config_obj,
 	}
+	result.loadSettings(config_obj)
 
 	wg.Add(1)
 	go func() {
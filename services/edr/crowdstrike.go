@@ -0,0 +1,175 @@
+package edr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+const (
+	CROWDSTRIKE_URL = "https://api.crowdstrike.com"
+
+	// The maximum number of alerts the API returns per request.
+	CROWDSTRIKE_PAGE_SIZE = 1000
+)
+
+// CrowdStrike Falcon. The API client needs the Alerts: Read scope.
+type crowdstrikeSource struct {
+	base   string
+	client *http.Client
+}
+
+type crowdstrikeIds struct {
+	Resources []string `json:"resources"`
+	Meta      struct {
+		Pagination struct {
+			Total int `json:"total"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+type crowdstrikeAlerts struct {
+	Resources []json.RawMessage `json:"resources"`
+}
+
+func (self *crowdstrikeSource) Fetch(
+	ctx context.Context, since time.Time) ([]*Alert, error) {
+	var result []*Alert
+
+	// Alerts are listed by id and then retrieved in batches.
+	for offset, i := 0, 0; i < MAX_PAGES; i++ {
+		query := url.Values{}
+		query.Set("filter", fmt.Sprintf("created_timestamp:>='%s'",
+			since.UTC().Format(time.RFC3339Nano)))
+		query.Set("sort", "created_timestamp.asc")
+		query.Set("limit", strconv.Itoa(CROWDSTRIKE_PAGE_SIZE))
+		query.Set("offset", strconv.Itoa(offset))
+
+		data, err := doRequest(ctx, self.client, "GET",
+			self.base+"/alerts/queries/alerts/v2?"+query.Encode(), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := &crowdstrikeIds{}
+		err = json.Unmarshal(data, ids)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CrowdStrike response: %w", err)
+		}
+
+		if len(ids.Resources) == 0 {
+			break
+		}
+
+		alerts, err := self.getAlerts(ctx, ids.Resources)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, alerts...)
+
+		offset += len(ids.Resources)
+		if offset >= ids.Meta.Pagination.Total {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (self *crowdstrikeSource) getAlerts(
+	ctx context.Context, ids []string) ([]*Alert, error) {
+	body, err := json.Marshal(map[string][]string{"composite_ids": ids})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := doRequest(ctx, self.client, "POST",
+		self.base+"/alerts/entities/alerts/v2", bytes.NewReader(body),
+		http.Header{"Content-Type": {"application/json"}})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &crowdstrikeAlerts{}
+	err = json.Unmarshal(data, page)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CrowdStrike response: %w", err)
+	}
+
+	result := make([]*Alert, 0, len(page.Resources))
+	for _, item := range page.Resources {
+		record, err := parseRecord(item)
+		if err != nil {
+			continue
+		}
+
+		title := utils.GetPathString(record, "display_name")
+		if title == "" {
+			title = utils.GetPathString(record, "name")
+		}
+
+		result = append(result, &Alert{
+			Id:          utils.GetPathString(record, "composite_id"),
+			Time:        utils.GetPathTime(record, "created_timestamp"),
+			Title:       title,
+			Description: utils.GetPathString(record, "description"),
+			Severity:    utils.GetPathString(record, "severity_name"),
+			Category:    utils.GetPathString(record, "tactic"),
+			Status:      utils.GetPathString(record, "status"),
+			Hostname:    utils.GetPathString(record, "device", "hostname"),
+			DeviceId:    utils.GetPathString(record, "device", "device_id"),
+			User:        utils.GetPathString(record, "user_name"),
+			Link:        utils.GetPathString(record, "falcon_host_link"),
+			Raw:         record,
+		})
+	}
+
+	return result, nil
+}
+
+func newCrowdstrikeSource(ctx context.Context,
+	connector *config_proto.EDRConnectorConfig,
+	client networking.HTTPClient) (AlertSource, error) {
+	if connector.ClientId == "" || connector.ClientSecret == "" {
+		return nil, errors.New(
+			"crowdstrike connectors need client_id and client_secret")
+	}
+
+	// Each cloud region has its own API url.
+	base := strings.TrimSuffix(connector.Url, "/")
+	if base == "" {
+		base = CROWDSTRIKE_URL
+	}
+
+	config := &clientcredentials.Config{
+		ClientID:     connector.ClientId,
+		ClientSecret: connector.ClientSecret,
+		TokenURL:     base + "/oauth2/token",
+		AuthStyle:    oauth2.AuthStyleInParams,
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient,
+		&http.Client{Transport: clientTransport{client: client}})
+
+	return &crowdstrikeSource{
+		base:   base,
+		client: config.Client(ctx),
+	}, nil
+}
+
+func init() {
+	RegisterSource("crowdstrike", newCrowdstrikeSource)
+}
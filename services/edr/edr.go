@@ -0,0 +1,152 @@
+/*
+  The EDR service pulls alerts from EDR products and forwards them to
+  the Server.Internal.EDRAlerts event queue.
+
+  Each connector in the config (Defaults.edr_connectors) is polled
+  periodically by the master frontend. Alerts are normalized into a
+  common set of columns so server event artifacts can react to them
+  (e.g. by launching collections on the affected client) without
+  caring which product raised them.
+
+  Support for a new product is added by registering an AlertSource
+  for its connector type.
+*/
+
+package edr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+const (
+	// The queue alerts are forwarded to.
+	EDR_ALERTS_ARTIFACT = "Server.Internal.EDRAlerts"
+
+	// Each API response is read into memory.
+	MAX_RESPONSE_SIZE = 100 * 1024 * 1024
+
+	// Guard against APIs which keep returning more pages.
+	MAX_PAGES = 1000
+)
+
+// An alert normalized from the EDR product's own format.
+type Alert struct {
+	Id          string
+	Time        time.Time
+	Title       string
+	Description string
+
+	// One of Informational, Low, Medium, High or Critical.
+	Severity string
+	Category string
+	Status   string
+
+	// The host the alert is about. DeviceId is the EDR's own id
+	// for the host.
+	Hostname string
+	DeviceId string
+	User     string
+
+	// A link to the alert in the EDR console.
+	Link string
+
+	// The alert as returned by the API.
+	Raw *ordereddict.Dict
+}
+
+// An AlertSource pulls alerts from an EDR product.
+type AlertSource interface {
+	// Return the alerts created at or after since. Alerts may be
+	// returned in any order.
+	Fetch(ctx context.Context, since time.Time) ([]*Alert, error)
+}
+
+type SourceFactory func(ctx context.Context,
+	connector *config_proto.EDRConnectorConfig,
+	client networking.HTTPClient) (AlertSource, error)
+
+var (
+	mu      sync.Mutex
+	sources = make(map[string]SourceFactory)
+)
+
+// Register a source for a connector type.
+func RegisterSource(connector_type string, factory SourceFactory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	sources[strings.ToLower(connector_type)] = factory
+}
+
+func NewSource(ctx context.Context,
+	connector *config_proto.EDRConnectorConfig,
+	client networking.HTTPClient) (AlertSource, error) {
+	mu.Lock()
+	factory, pres := sources[strings.ToLower(connector.Type)]
+	mu.Unlock()
+
+	if !pres {
+		return nil, fmt.Errorf("Unknown connector type %q", connector.Type)
+	}
+	return factory(ctx, connector, client)
+}
+
+// Make a request and return the body of a successful response.
+func doRequest(ctx context.Context, client networking.HTTPClient,
+	method, url string, body io.Reader, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, MAX_RESPONSE_SIZE))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if len(data) > 200 {
+			data = data[:200]
+		}
+		return nil, fmt.Errorf("Request failed with status %v: %v",
+			resp.Status, string(data))
+	}
+
+	return data, nil
+}
+
+// Adapts a HTTPClient so it can be used by the oauth2 package.
+type clientTransport struct {
+	client networking.HTTPClient
+}
+
+func (self clientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return self.client.Do(req)
+}
+
+func parseRecord(data []byte) (*ordereddict.Dict, error) {
+	record := ordereddict.NewDict()
+	err := record.UnmarshalJSON(data)
+	return record, err
+}
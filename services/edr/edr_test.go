@@ -0,0 +1,308 @@
+package edr_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/edr"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type request struct {
+	method, url, auth, body string
+}
+
+// Responses are keyed by the url without the query string.
+type MockClient struct {
+	responses map[string]func(req *http.Request) string
+	requests  []request
+}
+
+func (self *MockClient) Do(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		data, _ := ioutil.ReadAll(req.Body)
+		body = string(data)
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+
+	self.requests = append(self.requests, request{
+		method: req.Method,
+		url:    req.URL.String(),
+		auth:   req.Header.Get("Authorization"),
+		body:   body,
+	})
+
+	key := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+	handler, pres := self.responses[key]
+	if !pres {
+		return &http.Response{
+			StatusCode: 404,
+			Status:     "404 Not Found",
+			Body:       ioutil.NopCloser(&bytes.Buffer{}),
+		}, nil
+	}
+
+	return &http.Response{
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(handler(req)))),
+	}, nil
+}
+
+func (self *MockClient) urls(method string) []string {
+	result := []string{}
+	for _, r := range self.requests {
+		if r.method == method {
+			result = append(result, r.url)
+		}
+	}
+	return result
+}
+
+func token(req *http.Request) string {
+	return `{"access_token": "token", "token_type": "Bearer", "expires_in": 3600}`
+}
+
+const (
+	mdeAlerts = `{"value": [
+ {"id": "da2", "title": "Suspicious PowerShell", "severity": "High",
+  "category": "Execution", "status": "New",
+  "computerDnsName": "desktop-1.corp.example.com", "machineId": "m1",
+  "alertCreationTime": "2023-11-14T21:30:00Z",
+  "relatedUser": {"userName": "bob", "domainName": "CORP"}},
+ {"id": "da1", "title": "Mimikatz", "severity": "High",
+  "alertCreationTime": "2023-11-14T21:20:00Z",
+  "computerDnsName": "desktop-2"}
+]}`
+)
+
+type EDRTestSuite struct {
+	test_utils.TestSuite
+	clock *utils.MockClock
+	mock  *MockClient
+}
+
+func (self *EDRTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+	self.LoadArtifactFiles("../../artifacts/definitions/Server/Internal/EDRAlerts.yaml")
+
+	self.clock = utils.NewMockClock(time.Unix(1700000000, 0))
+	self.ConfigObj.Defaults.EdrConnectors = []*config_proto.EDRConnectorConfig{{
+		Name:         "Defender",
+		Type:         "mde",
+		TenantId:     "tenant",
+		ClientId:     "client",
+		ClientSecret: "secret",
+	}}
+
+	self.mock = &MockClient{responses: map[string]func(req *http.Request) string{
+		"https://login.microsoftonline.com/tenant/oauth2/v2.0/token": token,
+		"https://api.securitycenter.microsoft.com/api/alerts": func(
+			req *http.Request) string {
+			return mdeAlerts
+		},
+	}}
+}
+
+// Poll the connectors and return the alerts forwarded to the queue.
+func (self *EDRTestSuite) poll(poller *edr.Poller) []*ordereddict.Dict {
+	journal, err := services.GetJournal(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	ctx, cancel := context.WithCancel(self.Ctx)
+	defer cancel()
+
+	events, closer := journal.Watch(ctx, edr.EDR_ALERTS_ARTIFACT, "test")
+	defer closer()
+
+	assert.NoError(self.T(), poller.PollAll(ctx))
+
+	result := []*ordereddict.Dict{}
+	for {
+		select {
+		case row := <-events:
+			result = append(result, row)
+		case <-time.After(500 * time.Millisecond):
+			return result
+		}
+	}
+}
+
+func (self *EDRTestSuite) TestMDE() {
+	closer := utils.MockTime(self.clock)
+	defer closer()
+
+	poller, err := edr.NewPoller(self.Ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+	poller.Client = self.mock
+
+	// Alerts are forwarded in order of creation.
+	rows := self.poll(poller)
+	assert.Equal(self.T(), 2, len(rows))
+
+	id, _ := rows[0].GetString("AlertId")
+	assert.Equal(self.T(), "da1", id)
+
+	hostname, _ := rows[1].GetString("Hostname")
+	assert.Equal(self.T(), "desktop-1.corp.example.com", hostname)
+
+	user, _ := rows[1].GetString("User")
+	assert.Equal(self.T(), "CORP\\bob", user)
+
+	link, _ := rows[1].GetString("Link")
+	assert.Equal(self.T(), "https://security.microsoft.com/alerts/da2", link)
+
+	// The first poll looks back an hour.
+	assert.Equal(self.T(), []string{
+		"https://api.securitycenter.microsoft.com/api/alerts?" +
+			"%24filter=alertCreationTime+ge+2023-11-14T21%3A13%3A20Z"},
+		self.mock.urls("GET"))
+	assert.Equal(self.T(), "Bearer token", self.mock.requests[1].auth)
+
+	state, err := edr.LoadState(self.ConfigObj, "Defender")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), time.Date(2023, 11, 14, 21, 30, 0, 0, time.UTC),
+		state.Cursor)
+	assert.Equal(self.T(), uint64(2), state.Total)
+
+	// Not due for polling yet.
+	self.mock.requests = nil
+	assert.Equal(self.T(), 0, len(self.poll(poller)))
+	assert.Equal(self.T(), 0, len(self.mock.requests))
+
+	// The API returns the last alert again since the filter is
+	// inclusive. Only the new alert is forwarded.
+	self.mock.responses["https://api.securitycenter.microsoft.com/api/alerts"] =
+		func(req *http.Request) string {
+			return `{"value": [
+ {"id": "da2", "alertCreationTime": "2023-11-14T21:30:00Z"},
+ {"id": "da3", "title": "Ransomware", "alertCreationTime": "2023-11-14T21:30:00Z",
+  "computerDnsName": "desktop-1"}]}`
+		}
+
+	self.clock.Set(time.Unix(1700000000+600, 0))
+	rows = self.poll(poller)
+	assert.Equal(self.T(), 1, len(rows))
+
+	id, _ = rows[0].GetString("AlertId")
+	assert.Equal(self.T(), "da3", id)
+
+	assert.Equal(self.T(), []string{
+		"https://api.securitycenter.microsoft.com/api/alerts?" +
+			"%24filter=alertCreationTime+ge+2023-11-14T21%3A30%3A00Z"},
+		self.mock.urls("GET"))
+
+	// Failed polls are recorded and keep the cursor.
+	delete(self.mock.responses, "https://api.securitycenter.microsoft.com/api/alerts")
+	self.clock.Set(time.Unix(1700000000+1200, 0))
+	assert.Equal(self.T(), 0, len(self.poll(poller)))
+
+	state, err = edr.LoadState(self.ConfigObj, "Defender")
+	assert.NoError(self.T(), err)
+	assert.Contains(self.T(), state.LastError, "404")
+	assert.Equal(self.T(), []string{"da2", "da3"}, state.Seen)
+	assert.Equal(self.T(), uint64(3), state.Total)
+}
+
+func (self *EDRTestSuite) TestCrowdStrike() {
+	closer := utils.MockTime(self.clock)
+	defer closer()
+
+	self.ConfigObj.Defaults.EdrConnectors = []*config_proto.EDRConnectorConfig{{
+		Name:         "Falcon",
+		Type:         "crowdstrike",
+		Url:          "https://api.eu-1.crowdstrike.com",
+		ClientId:     "client",
+		ClientSecret: "secret",
+	}}
+
+	self.mock.responses = map[string]func(req *http.Request) string{
+		"https://api.eu-1.crowdstrike.com/oauth2/token": token,
+		"https://api.eu-1.crowdstrike.com/alerts/queries/alerts/v2": func(
+			req *http.Request) string {
+			if req.URL.Query().Get("offset") == "0" {
+				return `{"resources": ["a1"], "meta": {"pagination": {"total": 2}}}`
+			}
+			return `{"resources": ["a2"], "meta": {"pagination": {"total": 2}}}`
+		},
+		"https://api.eu-1.crowdstrike.com/alerts/entities/alerts/v2": func(
+			req *http.Request) string {
+			data, _ := ioutil.ReadAll(req.Body)
+			if string(data) == `{"composite_ids":["a2"]}` {
+				return `{"resources": [
+ {"composite_id": "a2", "name": "Persistence",
+  "severity_name": "Medium",
+  "created_timestamp": "2023-11-14T21:55:00Z",
+  "device": {"hostname": "SERVER-2", "device_id": "d2"}}]}`
+			}
+			return `{"resources": [
+ {"composite_id": "a1", "display_name": "CredentialTheft",
+  "severity_name": "Critical", "tactic": "Credential Access",
+  "created_timestamp": "2023-11-14T21:50:00.123Z",
+  "device": {"hostname": "SERVER-1", "device_id": "d1"}}]}`
+		},
+	}
+
+	poller, err := edr.NewPoller(self.Ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+	poller.Client = self.mock
+
+	rows := self.poll(poller)
+	assert.Equal(self.T(), 2, len(rows))
+
+	severity, _ := rows[0].GetString("Severity")
+	assert.Equal(self.T(), "Critical", severity)
+
+	hostname, _ := rows[0].GetString("Hostname")
+	assert.Equal(self.T(), "SERVER-1", hostname)
+
+	title, _ := rows[1].GetString("Title")
+	assert.Equal(self.T(), "Persistence", title)
+
+	// Ids are listed a page at a time and then retrieved.
+	assert.Equal(self.T(), 2, len(self.mock.urls("GET")))
+	posts := []string{}
+	for _, r := range self.mock.requests {
+		if r.method == "POST" && r.auth != "" {
+			posts = append(posts, r.body)
+		}
+	}
+	assert.Equal(self.T(), []string{
+		`{"composite_ids":["a1"]}`, `{"composite_ids":["a2"]}`}, posts)
+}
+
+func (self *EDRTestSuite) TestUnknownType() {
+	closer := utils.MockTime(self.clock)
+	defer closer()
+
+	self.ConfigObj.Defaults.EdrConnectors[0].Type = "other"
+
+	poller, err := edr.NewPoller(self.Ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+	poller.Client = self.mock
+
+	assert.Equal(self.T(), 0, len(self.poll(poller)))
+
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		state, err := edr.LoadState(self.ConfigObj, "Defender")
+		assert.NoError(self.T(), err)
+		return state.LastError == `Unknown connector type "other"`
+	})
+}
+
+func TestEDR(t *testing.T) {
+	suite.Run(t, &EDRTestSuite{})
+}
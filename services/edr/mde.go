@@ -0,0 +1,127 @@
+package edr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+const (
+	MDE_URL       = "https://api.securitycenter.microsoft.com"
+	MDE_LOGIN_URL = "https://login.microsoftonline.com"
+	MDE_PORTAL    = "https://security.microsoft.com/alerts/"
+)
+
+// Microsoft Defender for Endpoint. The app registration needs the
+// Alert.Read.All application permission on the WindowsDefenderATP
+// API.
+type mdeSource struct {
+	base   string
+	client *http.Client
+}
+
+type odataPage struct {
+	Value    []json.RawMessage `json:"value"`
+	NextLink string            `json:"@odata.nextLink"`
+}
+
+func (self *mdeSource) Fetch(
+	ctx context.Context, since time.Time) ([]*Alert, error) {
+	query := url.Values{}
+	query.Set("$filter", "alertCreationTime ge "+
+		since.UTC().Format(time.RFC3339Nano))
+
+	var result []*Alert
+	next := self.base + "/api/alerts?" + query.Encode()
+	for i := 0; next != "" && i < MAX_PAGES; i++ {
+		data, err := doRequest(ctx, self.client, "GET", next, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		page := &odataPage{}
+		err = json.Unmarshal(data, page)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid MDE response: %w", err)
+		}
+
+		for _, item := range page.Value {
+			record, err := parseRecord(item)
+			if err != nil {
+				continue
+			}
+
+			id := utils.GetPathString(record, "id")
+			user := utils.GetPathString(record, "relatedUser", "userName")
+			domain := utils.GetPathString(record, "relatedUser", "domainName")
+			if user != "" && domain != "" {
+				user = domain + "\\" + user
+			}
+
+			result = append(result, &Alert{
+				Id:          id,
+				Time:        utils.GetPathTime(record, "alertCreationTime"),
+				Title:       utils.GetPathString(record, "title"),
+				Description: utils.GetPathString(record, "description"),
+				Severity:    utils.GetPathString(record, "severity"),
+				Category:    utils.GetPathString(record, "category"),
+				Status:      utils.GetPathString(record, "status"),
+				Hostname:    utils.GetPathString(record, "computerDnsName"),
+				DeviceId:    utils.GetPathString(record, "machineId"),
+				User:        user,
+				Link:        MDE_PORTAL + id,
+				Raw:         record,
+			})
+		}
+
+		next = page.NextLink
+	}
+
+	return result, nil
+}
+
+func newMDESource(ctx context.Context,
+	connector *config_proto.EDRConnectorConfig,
+	client networking.HTTPClient) (AlertSource, error) {
+	if connector.TenantId == "" || connector.ClientId == "" ||
+		connector.ClientSecret == "" {
+		return nil, errors.New(
+			"mde connectors need tenant_id, client_id and client_secret")
+	}
+
+	base := strings.TrimSuffix(connector.Url, "/")
+	if base == "" {
+		base = MDE_URL
+	}
+
+	config := &clientcredentials.Config{
+		ClientID:     connector.ClientId,
+		ClientSecret: connector.ClientSecret,
+		TokenURL: fmt.Sprintf("%s/%s/oauth2/v2.0/token",
+			MDE_LOGIN_URL, connector.TenantId),
+		Scopes: []string{MDE_URL + "/.default"},
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient,
+		&http.Client{Transport: clientTransport{client: client}})
+
+	return &mdeSource{
+		base:   base,
+		client: config.Client(ctx),
+	}, nil
+}
+
+func init() {
+	RegisterSource("mde", newMDESource)
+}
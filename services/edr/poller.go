@@ -0,0 +1,342 @@
+package edr
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+const (
+	// In seconds
+	DEFAULT_POLL_INTERVAL = 300
+
+	// When a connector is polled for the first time only recent
+	// alerts are forwarded.
+	INITIAL_LOOKBACK = time.Hour
+)
+
+var (
+	notAvailableError = errors.New("EDR: Datastore not available")
+)
+
+// The polling state of a connector.
+type State struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// Alerts created before the cursor were already forwarded.
+	Cursor time.Time `json:"cursor"`
+
+	// Ids of the alerts created exactly at the cursor that were
+	// forwarded. The APIs filter on time inclusively so these are
+	// returned again by the next poll.
+	Seen []string `json:"seen,omitempty"`
+
+	LastPoll    int64  `json:"last_poll,omitempty"`
+	LastSuccess int64  `json:"last_success,omitempty"`
+	LastError   string `json:"last_error,omitempty"`
+
+	// Total number of alerts forwarded.
+	Total uint64 `json:"total,omitempty"`
+}
+
+type Poller struct {
+	config_obj *config_proto.Config
+
+	// A HTTPClient used to talk to the EDR APIs.
+	Client networking.HTTPClient
+
+	// How often to check if connectors are due for polling.
+	check_interval time.Duration
+}
+
+// Poll all the configured connectors which are due.
+func (self *Poller) PollAll(ctx context.Context) error {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	for _, connector := range self.config_obj.GetDefaults().GetEdrConnectors() {
+		if connector.Name == "" || connector.Type == "" {
+			logger.Error("EDR: Connectors must have a name and type")
+			continue
+		}
+
+		state, err := LoadState(self.config_obj, connector.Name)
+		if err != nil {
+			return err
+		}
+
+		interval := time.Duration(connector.PollIntervalSec) * time.Second
+		if interval == 0 {
+			interval = DEFAULT_POLL_INTERVAL * time.Second
+		}
+
+		now := utils.GetTime().Now()
+		if now.Sub(time.Unix(state.LastPoll, 0)) < interval {
+			continue
+		}
+
+		err = self.Poll(ctx, connector, state)
+		if err != nil {
+			logger.Error("EDR: Unable to poll connector %v: %v",
+				connector.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Fetch new alerts from the connector and forward them to the alert
+// queue.
+func (self *Poller) Poll(ctx context.Context,
+	connector *config_proto.EDRConnectorConfig, state *State) error {
+	now := utils.GetTime().Now()
+
+	// The connector was reconfigured to another product so start
+	// again.
+	if state.Type != connector.Type {
+		state = NewState(connector.Name, connector.Type)
+	}
+
+	state.LastPoll = now.Unix()
+	rows, cursor, seen, err := self.fetch(ctx, connector, state, now)
+	if err == nil && len(rows) > 0 {
+		err = self.forward(ctx, rows)
+	}
+
+	if err != nil {
+		// Leave the cursor alone so the alerts are fetched again.
+		state.LastError = err.Error()
+		_ = StoreState(self.config_obj, state.Name, state)
+		return err
+	}
+
+	state.Cursor = cursor
+	state.Seen = seen
+	state.LastSuccess = now.Unix()
+	state.LastError = ""
+	state.Total += uint64(len(rows))
+
+	if len(rows) > 0 {
+		logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+		logger.Info("EDR: Forwarded %v alerts from connector %v",
+			len(rows), connector.Name)
+	}
+
+	return StoreState(self.config_obj, state.Name, state)
+}
+
+// Fetch the alerts not seen before. Returns the rows to forward and
+// the new cursor.
+func (self *Poller) fetch(ctx context.Context,
+	connector *config_proto.EDRConnectorConfig,
+	state *State, now time.Time) (
+	rows []*ordereddict.Dict, cursor time.Time, seen []string, err error) {
+	cursor = state.Cursor
+	seen = state.Seen
+
+	source, err := NewSource(ctx, connector, self.Client)
+	if err != nil {
+		return nil, cursor, seen, err
+	}
+
+	since := state.Cursor
+	if since.IsZero() {
+		since = now.Add(-INITIAL_LOOKBACK)
+	}
+
+	alerts, err := source.Fetch(ctx, since)
+	if err != nil {
+		return nil, cursor, seen, err
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].Time.Before(alerts[j].Time)
+	})
+
+	forwarded := make(map[string]bool)
+	for _, id := range state.Seen {
+		forwarded[id] = true
+	}
+
+	for _, alert := range alerts {
+		if alert.Time.Before(since) ||
+			(alert.Time.Equal(state.Cursor) && forwarded[alert.Id]) {
+			continue
+		}
+
+		if alert.Time.After(cursor) {
+			cursor = alert.Time
+			seen = nil
+		}
+		seen = append(seen, alert.Id)
+
+		rows = append(rows, alertToRow(connector, alert))
+	}
+
+	return rows, cursor, seen, nil
+}
+
+func (self *Poller) forward(ctx context.Context, rows []*ordereddict.Dict) error {
+	journal, err := services.GetJournal(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	return journal.PushRowsToArtifact(ctx, self.config_obj,
+		rows, EDR_ALERTS_ARTIFACT, "server", "")
+}
+
+func alertToRow(connector *config_proto.EDRConnectorConfig,
+	alert *Alert) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Time", alert.Time).
+		Set("Connector", connector.Name).
+		Set("Type", connector.Type).
+		Set("AlertId", alert.Id).
+		Set("Title", alert.Title).
+		Set("Severity", alert.Severity).
+		Set("Category", alert.Category).
+		Set("Status", alert.Status).
+		Set("Hostname", alert.Hostname).
+		Set("DeviceId", alert.DeviceId).
+		Set("User", alert.User).
+		Set("Description", alert.Description).
+		Set("Link", alert.Link).
+		Set("Alert", alert.Raw)
+}
+
+func NewState(name, connector_type string) *State {
+	return &State{Name: name, Type: connector_type}
+}
+
+func getRawDB(config_obj *config_proto.Config) (datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, notAvailableError
+	}
+	return raw_db, nil
+}
+
+// Load the connector's state. Connectors that were never polled have
+// an empty state.
+func LoadState(config_obj *config_proto.Config, name string) (*State, error) {
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	path_manager := paths.NewEDRPathManager()
+	data, err := raw_db.GetBuffer(config_obj, path_manager.Connector(name))
+	if err != nil || len(data) == 0 {
+		return NewState(name, ""), nil
+	}
+
+	state := NewState(name, "")
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func StoreState(config_obj *config_proto.Config, name string, state *State) error {
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	path_manager := paths.NewEDRPathManager()
+	return raw_db.SetBuffer(config_obj, path_manager.Connector(name),
+		data, utils.SyncCompleter)
+}
+
+func (self *Poller) Start(ctx context.Context) {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(self.check_interval):
+			err := self.PollAll(ctx)
+			if err != nil {
+				logger.Debug("EDR: %v", err)
+			}
+		}
+	}
+}
+
+func NewPoller(ctx context.Context,
+	config_obj *config_proto.Config) (*Poller, error) {
+	scope := vql_subsystem.MakeScope()
+	client, err := networking.GetDefaultHTTPClient(
+		ctx, config_obj.Client, scope, "", networking.EmptyCookieJar)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Poller{
+		config_obj:     config_obj,
+		Client:         client,
+		check_interval: time.Minute,
+	}, nil
+}
+
+// The EDR service runs on the master frontend and forwards alerts
+// from the configured connectors.
+func StartEDRService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if config_obj.Datastore == nil ||
+		len(config_obj.GetDefaults().GetEdrConnectors()) == 0 {
+		return nil
+	}
+
+	poller, err := NewPoller(ctx, config_obj)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> EDR alert connectors for %v.",
+		services.GetOrgName(config_obj))
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		err := poller.PollAll(ctx)
+		if err != nil {
+			logger.Debug("EDR: %v", err)
+		}
+
+		poller.Start(ctx)
+	}()
+
+	return nil
+}
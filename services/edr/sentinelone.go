@@ -0,0 +1,117 @@
+package edr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+// SentinelOne threats. The API token needs the Viewer role.
+type sentinelOneSource struct {
+	base   string
+	token  string
+	client networking.HTTPClient
+}
+
+type sentinelOnePage struct {
+	Data       []json.RawMessage `json:"data"`
+	Pagination struct {
+		NextCursor string `json:"nextCursor"`
+	} `json:"pagination"`
+}
+
+// SentinelOne does not rate threats so use the confidence level.
+func sentinelOneSeverity(confidence string) string {
+	switch strings.ToLower(confidence) {
+	case "malicious":
+		return "High"
+	case "suspicious":
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+func (self *sentinelOneSource) Fetch(
+	ctx context.Context, since time.Time) ([]*Alert, error) {
+	query := url.Values{}
+	query.Set("createdAt__gte", since.UTC().Format(time.RFC3339Nano))
+	query.Set("sortBy", "createdAt")
+	query.Set("sortOrder", "asc")
+	query.Set("limit", "1000")
+
+	header := http.Header{"Authorization": {"ApiToken " + self.token}}
+
+	var result []*Alert
+	for i := 0; i < MAX_PAGES; i++ {
+		data, err := doRequest(ctx, self.client, "GET",
+			self.base+"/web/api/v2.1/threats?"+query.Encode(), nil, header)
+		if err != nil {
+			return nil, err
+		}
+
+		page := &sentinelOnePage{}
+		err = json.Unmarshal(data, page)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid SentinelOne response: %w", err)
+		}
+
+		for _, item := range page.Data {
+			record, err := parseRecord(item)
+			if err != nil {
+				continue
+			}
+
+			id := utils.GetPathString(record, "id")
+			result = append(result, &Alert{
+				Id:          id,
+				Time:        utils.GetPathTime(record, "threatInfo", "createdAt"),
+				Title:       utils.GetPathString(record, "threatInfo", "threatName"),
+				Description: utils.GetPathString(record, "threatInfo", "filePath"),
+				Severity: sentinelOneSeverity(
+					utils.GetPathString(record, "threatInfo", "confidenceLevel")),
+				Category: utils.GetPathString(record, "threatInfo", "classification"),
+				Status:   utils.GetPathString(record, "threatInfo", "incidentStatus"),
+				Hostname: utils.GetPathString(record, "agentRealtimeInfo", "agentComputerName"),
+				DeviceId: utils.GetPathString(record, "agentRealtimeInfo", "agentId"),
+				User:     utils.GetPathString(record, "threatInfo", "processUser"),
+				Link:     self.base + "/incidents/threats/" + id + "/overview",
+				Raw:      record,
+			})
+		}
+
+		if page.Pagination.NextCursor == "" {
+			break
+		}
+		query.Set("cursor", page.Pagination.NextCursor)
+	}
+
+	return result, nil
+}
+
+func newSentinelOneSource(ctx context.Context,
+	connector *config_proto.EDRConnectorConfig,
+	client networking.HTTPClient) (AlertSource, error) {
+	if connector.Url == "" || connector.ApiToken == "" {
+		return nil, errors.New("sentinelone connectors need url and api_token")
+	}
+
+	return &sentinelOneSource{
+		base:   strings.TrimSuffix(connector.Url, "/"),
+		token:  connector.ApiToken,
+		client: client,
+	}, nil
+}
+
+func init() {
+	RegisterSource("sentinelone", newSentinelOneSource)
+}
@@ -0,0 +1,382 @@
+/*
+  The event compaction service rolls old client monitoring events
+  into summaries.
+
+  Client event artifacts can produce a large volume of very similar
+  events (e.g. process creation or network connection events). These
+  are mostly interesting while they are recent, but keeping the raw
+  events forever uses a lot of space. For each configured rule, this
+  service periodically rewrites the daily event files which are older
+  than the minimum age. Events in the same time bucket which share
+  the same group_by columns are replaced by the first such event,
+  with the number of events it represents and the time of the last
+  event added.
+
+  Compacted files remain normal event files so they can still be
+  viewed and queried with the monitoring() plugin.
+*/
+
+package event_compaction
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/compressed"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	artifact_paths "www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/legal_hold"
+	"www.velocidex.com/golang/velociraptor/timelines"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	DEFAULT_INTERVAL   = 86400
+	DEFAULT_MIN_AGE    = 7 * 86400
+	DEFAULT_BUCKET_SEC = 3600
+
+	// Columns added to the compacted rows.
+	COUNT_COLUMN     = "CompactedCount"
+	LAST_SEEN_COLUMN = "CompactedLastSeen"
+)
+
+type Stats struct {
+	Files     int
+	Events    int
+	Summaries int
+}
+
+// Compact the events of all clients for the artifact in the rule.
+func CompactEvents(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	file_store_factory api.FileStore,
+	rule *config_proto.EventCompactionRule) (*Stats, error) {
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	stats := &Stats{}
+
+	min_age := time.Duration(rule.MinAgeSec) * time.Second
+	if min_age == 0 {
+		min_age = DEFAULT_MIN_AGE * time.Second
+	}
+
+	// Never rewrite files which are still protected by the WORM
+	// retention period.
+	if config_obj.Datastore != nil {
+		worm := time.Duration(config_obj.Datastore.WormRetentionSec) * time.Second
+		if worm > min_age {
+			min_age = worm
+		}
+	}
+
+	now := utils.GetTime().Now()
+
+	clients, err := file_store_factory.ListDirectory(
+		paths.CLIENTS_ROOT.AsFilestorePath())
+	if err != nil {
+		return stats, err
+	}
+
+	for _, client := range clients {
+		if ctx.Err() != nil {
+			return stats, ctx.Err()
+		}
+
+		client_id := client.Name()
+		if !client.IsDir() || !strings.HasPrefix(client_id, "C.") ||
+			legal_hold.IsClientHeld(config_obj, client_id) {
+			continue
+		}
+
+		path_manager := artifact_paths.NewArtifactPathManagerWithMode(
+			config_obj, client_id, "", rule.Artifact, paths.MODE_CLIENT_EVENT)
+
+		for _, file := range path_manager.GetAvailableFiles(ctx) {
+			if now.Sub(file.EndTime) < min_age {
+				continue
+			}
+
+			err := compactFile(ctx, file_store_factory, file.Path, rule, stats)
+			if err != nil {
+				logger.Error("EventCompaction: %v: %v",
+					file.Path.AsClientPath(), err)
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// Files which were already compacted have the count column in their
+// first row.
+func isCompacted(file_store_factory api.FileStore,
+	path api.FSPathSpec) (bool, error) {
+	fd, err := file_store_factory.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	defer fd.Close()
+
+	line, err := bufio.NewReader(fd).ReadBytes('\n')
+	if len(line) == 0 {
+		return false, err
+	}
+
+	row := ordereddict.NewDict()
+	err = row.UnmarshalJSON(line)
+	if err != nil {
+		return false, err
+	}
+
+	_, pres := row.Get(COUNT_COLUMN)
+	return pres, nil
+}
+
+func compactFile(
+	ctx context.Context,
+	file_store_factory api.FileStore,
+	path api.FSPathSpec,
+	rule *config_proto.EventCompactionRule,
+	stats *Stats) error {
+
+	compacted, err := isCompacted(file_store_factory, path)
+	if err != nil || compacted {
+		return err
+	}
+
+	path_manager := paths.NewTimelinePathManager(path.Base(), path)
+	reader, err := timelines.NewTimelineReader(file_store_factory, path_manager)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// Write the compacted file into a temp location and move it over
+	// the original when it is complete.
+	tmp_path := paths.TEMP_ROOT.AddChild("event_compaction",
+		fmt.Sprintf("%v_%v", path.Base(), utils.GetId())).
+		SetType(path.Type())
+	tmp_path_manager := paths.NewTimelinePathManager(tmp_path.Base(), tmp_path)
+	writer, err := timelines.NewTimelineWriter(file_store_factory,
+		tmp_path_manager, utils.SyncCompleter, result_sets.TruncateMode)
+	if err != nil {
+		return err
+	}
+
+	compactor := newCompactor(rule, writer)
+	for item := range reader.Read(ctx) {
+		// Keep draining the reader on error.
+		if err == nil {
+			err = compactor.Add(item)
+		}
+	}
+
+	if err == nil {
+		err = compactor.Flush()
+	}
+	writer.Close()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		_ = file_store_factory.Delete(tmp_path_manager.Path())
+		_ = file_store_factory.Delete(tmp_path_manager.Index())
+		return err
+	}
+
+	err = file_store_factory.Move(tmp_path_manager.Index(), path_manager.Index())
+	if err != nil {
+		return err
+	}
+
+	err = file_store_factory.Move(tmp_path_manager.Path(), path_manager.Path())
+	if err != nil {
+		return err
+	}
+
+	stats.Files++
+	stats.Events += compactor.events
+	stats.Summaries += compactor.written
+
+	return nil
+}
+
+type summary struct {
+	row       *ordereddict.Dict
+	timestamp time.Time
+	last_seen time.Time
+	count     int
+}
+
+// Combines the events in each time bucket. Events are read in time
+// order so a bucket is complete when we see the next one.
+type compactor struct {
+	bucket_sec int64
+	group_by   []string
+	writer     *timelines.TimelineWriter
+
+	bucket    int64
+	summaries map[string]*summary
+	order     []*summary
+
+	events, written int
+}
+
+func (self *compactor) key(row *ordereddict.Dict) string {
+	if len(self.group_by) == 0 {
+		return ""
+	}
+
+	values := make([]interface{}, 0, len(self.group_by))
+	for _, column := range self.group_by {
+		value, _ := row.Get(column)
+		values = append(values, value)
+	}
+	return json.MustMarshalString(values)
+}
+
+func (self *compactor) Add(item timelines.TimelineItem) error {
+	bucket := item.Time.Unix() / self.bucket_sec
+	if bucket != self.bucket {
+		err := self.Flush()
+		if err != nil {
+			return err
+		}
+		self.bucket = bucket
+	}
+
+	self.events++
+
+	key := self.key(item.Row)
+	s, pres := self.summaries[key]
+	if !pres {
+		s = &summary{row: item.Row, timestamp: item.Time}
+		self.summaries[key] = s
+		self.order = append(self.order, s)
+	}
+	s.count++
+	s.last_seen = item.Time
+
+	return nil
+}
+
+func (self *compactor) Flush() error {
+	for _, s := range self.order {
+		s.row.Set(COUNT_COLUMN, s.count).
+			Set(LAST_SEEN_COLUMN, s.last_seen.UTC())
+
+		err := self.writer.Write(s.timestamp, s.row)
+		if err != nil {
+			return err
+		}
+		self.written++
+	}
+
+	self.summaries = make(map[string]*summary)
+	self.order = nil
+	return nil
+}
+
+func newCompactor(rule *config_proto.EventCompactionRule,
+	writer *timelines.TimelineWriter) *compactor {
+	bucket_sec := int64(rule.BucketSec)
+	if bucket_sec == 0 {
+		bucket_sec = DEFAULT_BUCKET_SEC
+	}
+
+	return &compactor{
+		bucket_sec: bucket_sec,
+		group_by:   rule.GroupBy,
+		writer:     writer,
+		summaries:  make(map[string]*summary),
+	}
+}
+
+func StartEventCompactionService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if !services.IsMaster(config_obj) ||
+		config_obj.Defaults == nil ||
+		config_obj.Defaults.EventCompaction == nil ||
+		len(config_obj.Defaults.EventCompaction.Rules) == 0 {
+		return nil
+	}
+
+	interval := config_obj.Defaults.EventCompaction.IntervalSec
+	if interval < 0 {
+		return nil
+	}
+
+	if interval == 0 {
+		interval = DEFAULT_INTERVAL
+	}
+
+	// Only file based stores are compacted.
+	implementation, err := datastore.GetImplementationName(config_obj)
+	if err != nil {
+		return err
+	}
+
+	switch implementation {
+	case "FileBaseDataStore", "MemcacheFileDataStore":
+	default:
+		return nil
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Event compaction service for %v.",
+		services.GetOrgName(config_obj))
+
+	// Write directly to the disk so the compacted files are complete
+	// before they replace the originals.
+	file_store_factory := compressed.MaybeWrap(config_obj,
+		directory.NewDirectoryFileStore(config_obj))
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(time.Duration(interval) * time.Second):
+				for _, rule := range config_obj.Defaults.EventCompaction.Rules {
+					stats, err := CompactEvents(ctx, config_obj,
+						file_store_factory, rule)
+					if err != nil && ctx.Err() == nil {
+						logger.Error("EventCompaction: %v: %v",
+							rule.Artifact, err)
+					}
+
+					if stats.Files > 0 {
+						logger.Info("EventCompaction: %v: Compacted %v events into %v rows in %v files",
+							rule.Artifact, stats.Events, stats.Summaries,
+							stats.Files)
+					}
+				}
+			}
+		}
+	}()
+
+	return nil
+}
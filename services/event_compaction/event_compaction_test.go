@@ -0,0 +1,141 @@
+package event_compaction_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets/timed"
+	"www.velocidex.com/golang/velociraptor/services/event_compaction"
+	"www.velocidex.com/golang/velociraptor/timelines"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type EventCompactionTestSuite struct {
+	test_utils.TestSuite
+	client_id string
+}
+
+func (self *EventCompactionTestSuite) SetupTest() {
+	self.ConfigObj = self.LoadConfig()
+	self.LoadArtifactsIntoConfig([]string{`
+name: Windows.Events.ProcessCreation
+type: CLIENT_EVENT
+`})
+	self.TestSuite.SetupTest()
+
+	self.client_id = "C.123"
+}
+
+// Write an event every 5 minutes starting at 2020-04-25 07:33:20
+func (self *EventCompactionTestSuite) writeEvents(count int) {
+	clock := utils.NewMockClock(time.Unix(1587800000, 0))
+
+	path_manager := artifacts.NewArtifactPathManagerWithMode(
+		self.ConfigObj, self.client_id, "",
+		"Windows.Events.ProcessCreation", paths.MODE_CLIENT_EVENT)
+	path_manager.Clock = clock
+
+	writer, err := timed.NewTimedResultSetWriter(
+		file_store.GetFileStore(self.ConfigObj), path_manager,
+		nil, utils.SyncCompleter)
+	assert.NoError(self.T(), err)
+	writer.(*timed.TimedResultSetWriterImpl).Clock = clock
+
+	names := []string{"a.exe", "b.exe"}
+	for i := 0; i < count; i++ {
+		clock.Set(time.Unix(1587800000+300*int64(i), 0))
+		writer.Write(ordereddict.NewDict().
+			Set("Name", names[i%2]).
+			Set("Idx", i))
+	}
+	writer.Close()
+}
+
+func (self *EventCompactionTestSuite) readEvents() []timelines.TimelineItem {
+	path_manager := artifacts.NewArtifactPathManagerWithMode(
+		self.ConfigObj, self.client_id, "",
+		"Windows.Events.ProcessCreation", paths.MODE_CLIENT_EVENT)
+
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	files := path_manager.GetAvailableFiles(self.Ctx)
+	assert.Equal(self.T(), 1, len(files))
+
+	reader, err := timelines.NewTimelineReader(file_store_factory,
+		paths.NewTimelinePathManager(files[0].Path.Base(), files[0].Path))
+	assert.NoError(self.T(), err)
+	defer reader.Close()
+
+	result := []timelines.TimelineItem{}
+	for item := range reader.Read(self.Ctx) {
+		result = append(result, item)
+	}
+	return result
+}
+
+func (self *EventCompactionTestSuite) TestCompaction() {
+	self.writeEvents(20)
+
+	rule := &config_proto.EventCompactionRule{
+		Artifact: "Windows.Events.ProcessCreation",
+		GroupBy:  []string{"Name"},
+	}
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+
+	// The events are too recent to be compacted.
+	closer := utils.MockTime(utils.NewMockClock(time.Unix(1587900000, 0)))
+	stats, err := event_compaction.CompactEvents(
+		self.Ctx, self.ConfigObj, file_store_factory, rule)
+	closer()
+
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, stats.Files)
+	assert.Equal(self.T(), 20, len(self.readEvents()))
+
+	// A month later the events are compacted.
+	closer = utils.MockTime(utils.NewMockClock(time.Unix(1590500000, 0)))
+	defer closer()
+
+	stats, err = event_compaction.CompactEvents(
+		self.Ctx, self.ConfigObj, file_store_factory, rule)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, stats.Files)
+	assert.Equal(self.T(), 20, stats.Events)
+
+	// The events fall into 3 hourly buckets with 2 names in each.
+	events := self.readEvents()
+	assert.Equal(self.T(), 6, len(events))
+	assert.Equal(self.T(), 6, stats.Summaries)
+
+	total := int64(0)
+	for _, event := range events {
+		count, _ := event.Row.GetInt64(event_compaction.COUNT_COLUMN)
+		total += count
+	}
+	assert.Equal(self.T(), int64(20), total)
+
+	// The first bucket has events 0 to 5
+	idx, _ := events[1].Row.GetInt64("Idx")
+	assert.Equal(self.T(), int64(1), idx)
+
+	count, _ := events[1].Row.GetInt64(event_compaction.COUNT_COLUMN)
+	assert.Equal(self.T(), int64(3), count)
+
+	// Compacting again does nothing.
+	stats, err = event_compaction.CompactEvents(
+		self.Ctx, self.ConfigObj, file_store_factory, rule)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, stats.Files)
+	assert.Equal(self.T(), 6, len(self.readEvents()))
+}
+
+func TestEventCompaction(t *testing.T) {
+	suite.Run(t, &EventCompactionTestSuite{})
+}
@@ -0,0 +1,167 @@
+/*
+  Export jobs track the creation of download containers, i.e. the
+  export zip files of collections and hunts.
+
+  Containers are written asynchronously by the server. While the
+  container is written the progress reporter (see reporting) records
+  the job's state and estimated progress in the datastore so the GUI
+  and API can follow it. When the container is ready a
+  Server.Internal.ExportCompletion event is emitted to notify the
+  user.
+
+  Completed exports can be fetched using expiring download links. The
+  link is signed by the server so it can be used by download
+  managers without a GUI session, and supports HTTP range requests
+  so large downloads can be resumed.
+*/
+
+package export_jobs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	STATE_RUNNING  = "RUNNING"
+	STATE_FINISHED = "FINISHED"
+	STATE_ERROR    = "ERROR"
+
+	// Default lifetime of download links (1 day).
+	DEFAULT_LINK_EXPIRY = 24 * 60 * 60
+)
+
+var (
+	ErrLinkExpired      = errors.New("Download link has expired")
+	ErrInvalidSignature = errors.New("Invalid download link")
+	ErrJobNotFound      = errors.New("Export job not found")
+
+	jobIdRegex = regexp.MustCompile(`^E\.[0-9A-Z]+$`)
+)
+
+func NewJobId() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+
+	binary.BigEndian.PutUint32(buf, uint32(utils.GetTime().Now().Unix()))
+	result := base32.HexEncoding.EncodeToString(buf)[:13]
+
+	return "E." + result
+}
+
+// Get the status of the job.
+func Get(config_obj *config_proto.Config,
+	job_id string) (*api_proto.ContainerStats, error) {
+	if !jobIdRegex.MatchString(job_id) {
+		return nil, ErrJobNotFound
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.ContainerStats{}
+	err = db.GetSubject(config_obj, paths.ExportJobPath(job_id), result)
+	if err != nil || result.JobId != job_id {
+		return nil, ErrJobNotFound
+	}
+	return result, nil
+}
+
+// List all jobs, most recent first.
+func List(config_obj *config_proto.Config) ([]*api_proto.ContainerStats, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*api_proto.ContainerStats{}
+	err = datastore.Walk(config_obj, db, paths.EXPORT_JOBS_ROOT,
+		datastore.WalkWithoutDirectories,
+		func(path api.DSPathSpec) error {
+			stats := &api_proto.ContainerStats{}
+			err := db.GetSubject(config_obj, path, stats)
+			if err == nil && stats.JobId != "" {
+				result = append(result, stats)
+			}
+			return nil
+		})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp > result[j].Timestamp
+	})
+
+	return result, err
+}
+
+// Notify listeners that the job is done.
+func Notify(ctx context.Context, config_obj *config_proto.Config,
+	stats *api_proto.ContainerStats) error {
+	journal, err := services.GetJournal(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return journal.PushRowsToArtifact(ctx, config_obj,
+		[]*ordereddict.Dict{ordereddict.NewDict().
+			Set("JobId", stats.JobId).
+			Set("Principal", stats.Principal).
+			Set("State", stats.State).
+			Set("Error", stats.Error).
+			Set("Components", stats.Components).
+			Set("Size", stats.TotalCompressedBytes).
+			Set("Hash", stats.Hash).
+			Set("OrgId", config_obj.OrgId)},
+		"Server.Internal.ExportCompletion", "server", "")
+}
+
+// How long download links remain valid.
+func LinkExpiry(config_obj *config_proto.Config) int64 {
+	expiry := config_obj.Defaults.GetExportLinkExpirySec()
+	if expiry <= 0 {
+		expiry = DEFAULT_LINK_EXPIRY
+	}
+	return expiry
+}
+
+// Sign a download link for the job. The link is valid in the org
+// until the expires time (seconds since epoch).
+func SignLink(config_obj *config_proto.Config,
+	job_id string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(config_obj.Frontend.GetPrivateKey()))
+	_, _ = mac.Write([]byte(fmt.Sprintf("%s\n%s\n%d",
+		config_obj.OrgId, job_id, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyLink(config_obj *config_proto.Config,
+	job_id string, expires int64, signature string) error {
+	expected := SignLink(config_obj, job_id, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	if utils.GetTime().Now().Unix() > expires {
+		return ErrLinkExpired
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package geoip
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// A GeoIP database which may be backed by a MaxMind or an
+// IP2Location database.
+type Database interface {
+	// Returns the record for the IP or nil if the IP is not in the
+	// database.
+	Lookup(ip net.IP) (interface{}, error)
+	Close()
+}
+
+type maxmindDatabase struct {
+	reader *maxminddb.Reader
+}
+
+func (self *maxmindDatabase) Lookup(ip net.IP) (interface{}, error) {
+	var record interface{}
+	err := self.reader.Lookup(ip, &record)
+	return record, err
+}
+
+func (self *maxmindDatabase) Close() {
+	self.reader.Close()
+}
+
+// IP2Location databases are distributed as .BIN files - everything
+// else is assumed to be a MaxMind database.
+func isIP2Location(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), ".bin")
+}
+
+// Open a database from a file on disk.
+func OpenFile(filename string) (Database, error) {
+	if isIP2Location(filename) {
+		fd, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		db, err := NewIP2LocationDatabase(fd)
+		if err != nil {
+			fd.Close()
+			return nil, err
+		}
+		db.closer = fd
+		return db, nil
+	}
+
+	reader, err := maxminddb.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &maxmindDatabase{reader: reader}, nil
+}
+
+// Open a database from memory. The filename is only used to detect
+// the database type.
+func OpenBytes(filename string, data []byte) (Database, error) {
+	if len(data) == 0 {
+		return nil, errors.New("GeoIP database is empty")
+	}
+
+	if isIP2Location(filename) {
+		db, err := NewIP2LocationDatabase(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &maxmindDatabase{reader: reader}, nil
+}
@@ -0,0 +1,267 @@
+package geoip_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/geoip"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type ip2lRecord struct {
+	from                net.IP
+	country_short       string
+	country_long        string
+	region, city        string
+	latitude, longitude float32
+}
+
+// Build a DB5 (country, region, city, latitude, longitude) database
+// with an IPv4 and an IPv6 table.
+func buildIP2LocationDB(ipv4, ipv6 []ip2lRecord) []byte {
+	const columns = 6
+	ipv4_size := uint32(columns * 4)
+	ipv6_size := uint32(16 + (columns-1)*4)
+
+	// The tables end with a row holding the upper bound of the last
+	// range, which is included in the row count.
+	ipv4_addr := uint32(65)
+	ipv6_addr := ipv4_addr + uint32(len(ipv4)+1)*ipv4_size
+	strings_offset := ipv6_addr - 1 + uint32(len(ipv6)+1)*ipv6_size
+
+	pool := &bytes.Buffer{}
+	addString := func(value string) uint32 {
+		offset := strings_offset + uint32(pool.Len())
+		pool.WriteByte(byte(len(value)))
+		pool.WriteString(value)
+		return offset
+	}
+
+	// The short country code has a fixed size slot followed by the
+	// long name.
+	addCountry := func(short, long string) uint32 {
+		offset := addString(short)
+		pool.Write(make([]byte, 2-len(short)))
+		addString(long)
+		return offset
+	}
+
+	out := &bytes.Buffer{}
+	header := make([]byte, 64)
+	header[0] = 5
+	header[1] = columns
+	header[2] = 22
+	binary.LittleEndian.PutUint32(header[5:], uint32(len(ipv4)+1))
+	binary.LittleEndian.PutUint32(header[9:], ipv4_addr)
+	binary.LittleEndian.PutUint32(header[13:], uint32(len(ipv6)+1))
+	binary.LittleEndian.PutUint32(header[17:], ipv6_addr)
+	out.Write(header)
+
+	writeFields := func(record ip2lRecord) {
+		for _, value := range []uint32{
+			addCountry(record.country_short, record.country_long),
+			addString(record.region),
+			addString(record.city),
+			math.Float32bits(record.latitude),
+			math.Float32bits(record.longitude),
+		} {
+			_ = binary.Write(out, binary.LittleEndian, value)
+		}
+	}
+
+	for _, record := range ipv4 {
+		_ = binary.Write(out, binary.LittleEndian,
+			binary.BigEndian.Uint32(record.from.To4()))
+		writeFields(record)
+	}
+	_ = binary.Write(out, binary.LittleEndian, uint32(math.MaxUint32))
+	out.Write(make([]byte, ipv4_size-4))
+
+	for _, record := range ipv6 {
+		ip := record.from.To16()
+		_ = binary.Write(out, binary.LittleEndian, binary.BigEndian.Uint64(ip[8:]))
+		_ = binary.Write(out, binary.LittleEndian, binary.BigEndian.Uint64(ip[:8]))
+		writeFields(record)
+	}
+	_ = binary.Write(out, binary.LittleEndian, uint64(math.MaxUint64))
+	_ = binary.Write(out, binary.LittleEndian, uint64(math.MaxUint64))
+	out.Write(make([]byte, ipv6_size-16))
+
+	out.Write(pool.Bytes())
+	return out.Bytes()
+}
+
+func sampleDB() []byte {
+	unknown := ip2lRecord{country_short: "-", country_long: "-",
+		region: "-", city: "-"}
+	brisbane := ip2lRecord{country_short: "AU", country_long: "Australia",
+		region: "Queensland", city: "Brisbane",
+		latitude: -27.5, longitude: 153.25}
+
+	with_ip := func(record ip2lRecord, ip string) ip2lRecord {
+		record.from = net.ParseIP(ip)
+		return record
+	}
+
+	return buildIP2LocationDB([]ip2lRecord{
+		with_ip(unknown, "0.0.0.0"),
+		with_ip(brisbane, "1.1.1.0"),
+		with_ip(unknown, "1.1.2.0"),
+	}, []ip2lRecord{
+		with_ip(unknown, "::"),
+		with_ip(brisbane, "2001:db8::"),
+		with_ip(unknown, "2001:db9::"),
+	})
+}
+
+func TestIP2Location(t *testing.T) {
+	db, err := geoip.OpenBytes("DB5.BIN", sampleDB())
+	assert.NoError(t, err)
+
+	lookup := func(ip string) *ordereddict.Dict {
+		record, err := db.Lookup(net.ParseIP(ip))
+		assert.NoError(t, err, ip)
+		return record.(*ordereddict.Dict)
+	}
+
+	for _, ip := range []string{"1.1.1.0", "1.1.1.1", "1.1.1.255", "2001:db8::1"} {
+		record := lookup(ip)
+		city, _ := record.GetString("city")
+		assert.Equal(t, "Brisbane", city, ip)
+
+		country, _ := record.GetString("country_long")
+		assert.Equal(t, "Australia", country, ip)
+
+		latitude, _ := record.Get("latitude")
+		assert.Equal(t, -27.5, latitude, ip)
+	}
+
+	for _, ip := range []string{"0.0.0.1", "1.1.2.0", "8.8.8.8",
+		"255.255.255.255", "::1", "2001:db9::1"} {
+		country, _ := lookup(ip).GetString("country_short")
+		assert.Equal(t, "-", country, ip)
+	}
+
+	_, err = geoip.OpenBytes("Invalid.BIN", make([]byte, 100))
+	assert.Error(t, err)
+}
+
+type MockClient struct {
+	responses map[string][]byte
+	requests  []string
+}
+
+func (self *MockClient) Do(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	self.requests = append(self.requests, url)
+
+	response, pres := self.responses[url]
+	if !pres {
+		return &http.Response{
+			StatusCode: 404,
+			Status:     "404 Not Found",
+			Body:       ioutil.NopCloser(&bytes.Buffer{}),
+		}, nil
+	}
+
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewReader(response)),
+	}, nil
+}
+
+type GeoIPTestSuite struct {
+	test_utils.TestSuite
+	dir string
+}
+
+func (self *GeoIPTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "geoip_test")
+	assert.NoError(self.T(), err)
+	self.dir = dir
+
+	// Use a real filestore so database modification times are
+	// tracked.
+	self.ConfigObj = self.LoadConfig()
+	self.ConfigObj.Datastore.Implementation = "FileBaseDataStore"
+	self.ConfigObj.Datastore.FilestoreDirectory = dir
+	self.ConfigObj.Datastore.Location = dir
+
+	self.LoadArtifactsIntoConfig([]string{`
+name: Server.Internal.MetadataModifications
+type: SERVER_EVENT
+`})
+
+	self.TestSuite.SetupTest()
+}
+
+func (self *GeoIPTestSuite) TearDownTest() {
+	self.TestSuite.TearDownTest()
+	os.RemoveAll(self.dir)
+}
+
+func (self *GeoIPTestSuite) TestUpdater() {
+	ctx := context.Background()
+
+	archive := &bytes.Buffer{}
+	zip_writer := zip.NewWriter(archive)
+	fd, err := zip_writer.Create("IP2LOCATION-LITE-DB5.BIN")
+	assert.NoError(self.T(), err)
+	_, err = fd.Write(sampleDB())
+	assert.NoError(self.T(), err)
+	zip_writer.Close()
+
+	mock := &MockClient{responses: map[string][]byte{
+		"https://www.ip2location.com/download/?file=DB5LITEBIN&token=secret": archive.Bytes(),
+	}}
+
+	// Nothing is configured yet.
+	updater, err := geoip.NewGeoIPUpdater(ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+	updater.Client = mock
+
+	assert.NoError(self.T(), updater.UpdateAll(ctx))
+	assert.Equal(self.T(), 0, len(mock.requests))
+
+	_, err = geoip.GetManagedDatabase(self.ConfigObj, "DB5LITEBIN")
+	assert.Error(self.T(), err)
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	err = client_info_manager.SetMetadata(ctx, "server", ordereddict.NewDict().
+		Set(geoip.IP2LOCATION_KEY_METADATA, "secret").
+		Set(geoip.IP2LOCATION_EDITIONS_METADATA, "DB5LITEBIN"), "")
+	assert.NoError(self.T(), err)
+
+	assert.NoError(self.T(), updater.UpdateAll(ctx))
+	assert.Equal(self.T(), 1, len(mock.requests))
+
+	db, err := geoip.GetManagedDatabase(self.ConfigObj, "DB5LITEBIN")
+	assert.NoError(self.T(), err)
+
+	record, err := db.Lookup(net.ParseIP("1.1.1.1"))
+	assert.NoError(self.T(), err)
+	city, _ := record.(*ordereddict.Dict).GetString("city")
+	assert.Equal(self.T(), "Brisbane", city)
+
+	// The database is fresh so it is not downloaded again.
+	assert.NoError(self.T(), updater.UpdateAll(ctx))
+	assert.Equal(self.T(), 1, len(mock.requests))
+}
+
+func TestGeoIPUpdater(t *testing.T) {
+	suite.Run(t, &GeoIPTestSuite{})
+}
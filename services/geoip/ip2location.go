@@ -0,0 +1,335 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+// A reader for the IP2Location BIN database format.
+
+// The column of each field for each database type (DB1 - DB26). A
+// value of 0 means the field is not present in that database type.
+var (
+	ip2l_country_position = []uint32{0, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2}
+	ip2l_region_position = []uint32{0, 0, 0, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+		3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3}
+	ip2l_city_position = []uint32{0, 0, 0, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
+		4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4}
+	ip2l_isp_position = []uint32{0, 0, 3, 0, 5, 0, 7, 5, 7, 0, 8, 0, 9, 0,
+		9, 0, 9, 0, 9, 7, 9, 0, 9, 7, 9, 9, 9}
+	ip2l_latitude_position = []uint32{0, 0, 0, 0, 0, 5, 5, 0, 5, 5, 5, 5, 5, 5,
+		5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}
+	ip2l_longitude_position = []uint32{0, 0, 0, 0, 0, 6, 6, 0, 6, 6, 6, 6, 6, 6,
+		6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6}
+	ip2l_domain_position = []uint32{0, 0, 0, 0, 0, 0, 0, 6, 8, 0, 9, 0, 10, 0,
+		10, 0, 10, 0, 10, 8, 10, 0, 10, 8, 10, 10, 10}
+	ip2l_zipcode_position = []uint32{0, 0, 0, 0, 0, 0, 0, 0, 0, 7, 7, 7, 7, 0,
+		7, 7, 7, 0, 7, 0, 7, 7, 7, 0, 7, 7, 7}
+	ip2l_timezone_position = []uint32{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 8, 8, 7,
+		8, 8, 8, 7, 8, 0, 8, 8, 8, 0, 8, 8, 8}
+)
+
+type ip2locationHeader struct {
+	DBType    uint8
+	DBColumn  uint8
+	Year      uint8
+	Month     uint8
+	Day       uint8
+	IPv4Count uint32
+	IPv4Addr  uint32
+	IPv6Count uint32
+	IPv6Addr  uint32
+	IPv4Index uint32
+	IPv6Index uint32
+	Product   uint8
+	License   uint8
+	DBSize    uint32
+	_         [29]byte
+}
+
+type IP2LocationDatabase struct {
+	reader io.ReaderAt
+	closer io.Closer
+
+	header ip2locationHeader
+}
+
+func NewIP2LocationDatabase(reader io.ReaderAt) (*IP2LocationDatabase, error) {
+	result := &IP2LocationDatabase{reader: reader}
+
+	err := binary.Read(io.NewSectionReader(reader, 0, 64),
+		binary.LittleEndian, &result.header)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.header.DBType == 0 ||
+		int(result.header.DBType) >= len(ip2l_country_position) ||
+		result.header.DBColumn == 0 {
+		return nil, errors.New("Not a valid IP2Location database")
+	}
+
+	return result, nil
+}
+
+func (self *IP2LocationDatabase) Close() {
+	if self.closer != nil {
+		self.closer.Close()
+	}
+}
+
+// Offsets in the header and the index are 1 based.
+func (self *IP2LocationDatabase) readUint32(offset uint32) (uint32, error) {
+	buf := make([]byte, 4)
+	_, err := self.reader.ReadAt(buf, int64(offset)-1)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf), nil
+}
+
+// Returns the 128 bit IPv6 number as high and low parts.
+func (self *IP2LocationDatabase) readUint128(offset uint32) (uint64, uint64, error) {
+	buf := make([]byte, 16)
+	_, err := self.reader.ReadAt(buf, int64(offset)-1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[8:]),
+		binary.LittleEndian.Uint64(buf[:8]), nil
+}
+
+// Strings are stored with a leading length byte. String pointers are
+// 0 based.
+func (self *IP2LocationDatabase) readString(offset uint32) (string, error) {
+	length := make([]byte, 1)
+	_, err := self.reader.ReadAt(length, int64(offset))
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, int(length[0]))
+	_, err = self.reader.ReadAt(buf, int64(offset)+1)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func (self *IP2LocationDatabase) Lookup(ip net.IP) (interface{}, error) {
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return self.lookupIPv4(ipv4)
+	}
+
+	if ipv6 := ip.To16(); ipv6 != nil {
+		return self.lookupIPv6(ipv6)
+	}
+
+	return nil, fmt.Errorf("Invalid IP %v", ip)
+}
+
+func (self *IP2LocationDatabase) lookupIPv4(ip net.IP) (interface{}, error) {
+	header := &self.header
+	if header.IPv4Count == 0 {
+		return nil, nil
+	}
+
+	ip_number := binary.BigEndian.Uint32(ip)
+
+	// The last address is not covered by the exclusive upper bound.
+	if ip_number == math.MaxUint32 {
+		ip_number--
+	}
+
+	column_size := uint32(header.DBColumn) * 4
+	low, high := uint32(0), header.IPv4Count
+
+	// The index narrows the search to the rows for the top 16 bits.
+	if header.IPv4Index > 0 {
+		index_offset := header.IPv4Index + (ip_number>>16)<<3
+		var err error
+		low, err = self.readUint32(index_offset)
+		if err != nil {
+			return nil, err
+		}
+		high, err = self.readUint32(index_offset + 4)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for low <= high {
+		mid := (low + high) >> 1
+		row_offset := header.IPv4Addr + mid*column_size
+
+		ip_from, err := self.readUint32(row_offset)
+		if err != nil {
+			return nil, err
+		}
+
+		ip_to, err := self.readUint32(row_offset + column_size)
+		if err != nil {
+			return nil, err
+		}
+
+		if ip_number >= ip_from && ip_number < ip_to {
+			return self.readRecord(row_offset+4, column_size-4)
+		}
+
+		if ip_number < ip_from {
+			if mid == 0 {
+				break
+			}
+			high = mid - 1
+		} else {
+			low = mid + 1
+		}
+	}
+
+	return nil, nil
+}
+
+func lessThan128(a_high, a_low, b_high, b_low uint64) bool {
+	return a_high < b_high || (a_high == b_high && a_low < b_low)
+}
+
+func (self *IP2LocationDatabase) lookupIPv6(ip net.IP) (interface{}, error) {
+	header := &self.header
+	if header.IPv6Count == 0 {
+		return nil, nil
+	}
+
+	ip_high := binary.BigEndian.Uint64(ip[:8])
+	ip_low := binary.BigEndian.Uint64(ip[8:])
+
+	if ip_high == math.MaxUint64 && ip_low == math.MaxUint64 {
+		ip_low--
+	}
+
+	column_size := 16 + uint32(header.DBColumn-1)*4
+	low, high := uint32(0), header.IPv6Count
+
+	if header.IPv6Index > 0 {
+		index_offset := header.IPv6Index + uint32(ip_high>>48)<<3
+		var err error
+		low, err = self.readUint32(index_offset)
+		if err != nil {
+			return nil, err
+		}
+		high, err = self.readUint32(index_offset + 4)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for low <= high {
+		mid := (low + high) >> 1
+		row_offset := header.IPv6Addr + mid*column_size
+
+		from_high, from_low, err := self.readUint128(row_offset)
+		if err != nil {
+			return nil, err
+		}
+
+		to_high, to_low, err := self.readUint128(row_offset + column_size)
+		if err != nil {
+			return nil, err
+		}
+
+		if !lessThan128(ip_high, ip_low, from_high, from_low) &&
+			lessThan128(ip_high, ip_low, to_high, to_low) {
+			return self.readRecord(row_offset+16, column_size-16)
+		}
+
+		if lessThan128(ip_high, ip_low, from_high, from_low) {
+			if mid == 0 {
+				break
+			}
+			high = mid - 1
+		} else {
+			low = mid + 1
+		}
+	}
+
+	return nil, nil
+}
+
+// Read the fields of the record. The row starts with the second
+// column.
+func (self *IP2LocationDatabase) readRecord(
+	offset, length uint32) (*ordereddict.Dict, error) {
+	row := make([]byte, length)
+	_, err := self.reader.ReadAt(row, int64(offset)-1)
+	if err != nil {
+		return nil, err
+	}
+
+	db_type := self.header.DBType
+	column := func(positions []uint32) (uint32, bool) {
+		position := positions[db_type]
+		if position < 2 || (position-1)*4 > length {
+			return 0, false
+		}
+		return binary.LittleEndian.Uint32(row[(position-2)*4:]), true
+	}
+
+	result := ordereddict.NewDict()
+	if pointer, ok := column(ip2l_country_position); ok {
+		short, err := self.readString(pointer)
+		if err != nil {
+			return nil, err
+		}
+
+		long, err := self.readString(pointer + 3)
+		if err != nil {
+			return nil, err
+		}
+		result.Set("country_short", short).Set("country_long", long)
+	}
+
+	for _, field := range []struct {
+		name      string
+		positions []uint32
+	}{
+		{"region", ip2l_region_position},
+		{"city", ip2l_city_position},
+		{"isp", ip2l_isp_position},
+		{"domain", ip2l_domain_position},
+		{"zipcode", ip2l_zipcode_position},
+		{"timezone", ip2l_timezone_position},
+	} {
+		pointer, ok := column(field.positions)
+		if !ok {
+			continue
+		}
+
+		value, err := self.readString(pointer)
+		if err != nil {
+			return nil, err
+		}
+		result.Set(field.name, value)
+	}
+
+	// Coordinates are stored inline as single precision floats.
+	if value, ok := column(ip2l_latitude_position); ok {
+		result.Set("latitude", toCoordinate(value))
+	}
+
+	if value, ok := column(ip2l_longitude_position); ok {
+		result.Set("longitude", toCoordinate(value))
+	}
+
+	return result, nil
+}
+
+func toCoordinate(value uint32) float64 {
+	coordinate := float64(math.Float32frombits(value))
+	return math.Round(coordinate*1e6) / 1e6
+}
@@ -0,0 +1,113 @@
+package geoip
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// How often to check the filestore for a newer database.
+	RECHECK_INTERVAL = time.Minute
+)
+
+var (
+	managed_mu sync.Mutex
+	managed    = make(map[string]*managedDatabase)
+
+	// Managed databases are stored in one of these formats.
+	managed_extensions = []string{".mmdb", ".bin"}
+)
+
+type managedDatabase struct {
+	db       Database
+	mod_time time.Time
+	checked  time.Time
+}
+
+// Managed databases are shared by all orgs and stored in the root
+// org's filestore.
+func getRootConfig(config_obj *config_proto.Config) *config_proto.Config {
+	org_manager, err := services.GetOrgManager()
+	if err != nil {
+		return config_obj
+	}
+
+	root_config, err := org_manager.GetOrgConfig(services.ROOT_ORG_ID)
+	if err != nil {
+		return config_obj
+	}
+	return root_config
+}
+
+// Get a managed database by its edition name
+// (e.g. GeoLite2-City). Databases are reloaded when the update
+// service replaces them.
+func GetManagedDatabase(
+	config_obj *config_proto.Config, edition string) (Database, error) {
+	managed_mu.Lock()
+	defer managed_mu.Unlock()
+
+	now := utils.GetTime().Now()
+	cached, pres := managed[edition]
+	if pres && now.Sub(cached.checked) < RECHECK_INTERVAL {
+		return cached.db, nil
+	}
+
+	root_config := getRootConfig(config_obj)
+	file_store_factory := file_store.GetFileStore(root_config)
+	if file_store_factory == nil {
+		return nil, fmt.Errorf("GeoIP database %v is not available", edition)
+	}
+
+	path_manager := paths.NewGeoIPPathManager()
+	for _, extension := range managed_extensions {
+		filename := edition + extension
+		path := path_manager.Database(filename)
+		stat, err := file_store_factory.StatFile(path)
+		if err != nil {
+			continue
+		}
+
+		// The database has not changed.
+		if pres && stat.ModTime().Equal(cached.mod_time) {
+			cached.checked = now
+			return cached.db, nil
+		}
+
+		fd, err := file_store_factory.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(fd)
+		fd.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		// The database is loaded in memory so there is nothing to
+		// close when it is replaced.
+		db, err := OpenBytes(filename, data)
+		if err != nil {
+			return nil, fmt.Errorf("GeoIP database %v: %w", edition, err)
+		}
+
+		managed[edition] = &managedDatabase{
+			db:       db,
+			mod_time: stat.ModTime(),
+			checked:  now,
+		}
+		return db, nil
+	}
+
+	return nil, fmt.Errorf("GeoIP database %v is not available - set the "+
+		"GeoIPLicenseKey or IP2LocationToken server metadata to download it",
+		edition)
+}
@@ -0,0 +1,366 @@
+package geoip
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+// The update service is configured through the server metadata so
+// it can be changed from the GUI without restarting the server.
+const (
+	MAXMIND_KEY_METADATA          = "GeoIPLicenseKey"
+	MAXMIND_EDITIONS_METADATA     = "GeoIPEditions"
+	IP2LOCATION_KEY_METADATA      = "IP2LocationToken"
+	IP2LOCATION_EDITIONS_METADATA = "IP2LocationEditions"
+	UPDATE_FREQUENCY_METADATA     = "GeoIPUpdateFrequency"
+
+	DEFAULT_MAXMIND_EDITIONS     = "GeoLite2-City,GeoLite2-ASN"
+	DEFAULT_IP2LOCATION_EDITIONS = "DB11LITEBIN"
+
+	// In hours
+	DEFAULT_UPDATE_FREQUENCY = 24
+
+	// Databases are downloaded into memory.
+	MAX_DOWNLOAD_SIZE = 1024 * 1024 * 1024
+)
+
+var (
+	maxmind_url     = "https://download.maxmind.com/app/geoip_download"
+	ip2location_url = "https://www.ip2location.com/download/"
+)
+
+type provider struct {
+	name              string
+	key_metadata      string
+	editions_metadata string
+	default_editions  string
+	extension         string
+
+	download func(ctx context.Context, client networking.HTTPClient,
+		edition, key string) ([]byte, error)
+}
+
+var providers = []provider{{
+	name:              "MaxMind",
+	key_metadata:      MAXMIND_KEY_METADATA,
+	editions_metadata: MAXMIND_EDITIONS_METADATA,
+	default_editions:  DEFAULT_MAXMIND_EDITIONS,
+	extension:         ".mmdb",
+	download:          downloadMaxMind,
+}, {
+	name:              "IP2Location",
+	key_metadata:      IP2LOCATION_KEY_METADATA,
+	editions_metadata: IP2LOCATION_EDITIONS_METADATA,
+	default_editions:  DEFAULT_IP2LOCATION_EDITIONS,
+	extension:         ".bin",
+	download:          downloadIP2Location,
+}}
+
+type GeoIPUpdater struct {
+	config_obj *config_proto.Config
+
+	// A HTTPClient used to download the databases.
+	Client networking.HTTPClient
+
+	// How often to check if databases need updating.
+	check_interval time.Duration
+}
+
+// Check all the configured databases and download the ones that are
+// missing or older than the update frequency.
+func (self *GeoIPUpdater) UpdateAll(ctx context.Context) error {
+	client_info_manager, err := services.GetClientInfoManager(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := client_info_manager.GetMetadata(ctx, "server")
+	if err != nil {
+		return err
+	}
+
+	frequency := time.Duration(DEFAULT_UPDATE_FREQUENCY) * time.Hour
+	hours, err := strconv.ParseInt(getString(metadata, UPDATE_FREQUENCY_METADATA), 0, 64)
+	if err == nil && hours > 0 {
+		frequency = time.Duration(hours) * time.Hour
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	for _, p := range providers {
+		key := getString(metadata, p.key_metadata)
+		if key == "" {
+			continue
+		}
+
+		editions := getString(metadata, p.editions_metadata)
+		if editions == "" {
+			editions = p.default_editions
+		}
+
+		for _, edition := range strings.Split(editions, ",") {
+			edition = strings.TrimSpace(edition)
+			if edition == "" {
+				continue
+			}
+
+			err := self.update(ctx, p, edition, key, frequency)
+			if err != nil {
+				logger.Error("GeoIPUpdater: Unable to update %v database %v: %v",
+					p.name, edition, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (self *GeoIPUpdater) update(ctx context.Context,
+	p provider, edition, key string, frequency time.Duration) error {
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	path_manager := paths.NewGeoIPPathManager()
+	filename := edition + p.extension
+	path := path_manager.Database(filename)
+
+	// The database is still fresh.
+	stat, err := file_store_factory.StatFile(path)
+	if err == nil &&
+		utils.GetTime().Now().Sub(stat.ModTime()) < frequency {
+		return nil
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	logger.Info("GeoIPUpdater: Downloading %v database %v", p.name, edition)
+
+	data, err := p.download(ctx, self.Client, edition, key)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the database is usable before replacing the old
+	// one.
+	db, err := OpenBytes(filename, data)
+	if err != nil {
+		return err
+	}
+	db.Close()
+
+	tmp_path := path_manager.Temp(filename)
+	fd, err := file_store_factory.WriteFile(tmp_path)
+	if err != nil {
+		return err
+	}
+
+	err = fd.Truncate()
+	if err != nil {
+		fd.Close()
+		return err
+	}
+
+	_, err = fd.Write(data)
+	if err != nil {
+		fd.Close()
+		return err
+	}
+	fd.Close()
+
+	err = file_store_factory.Move(tmp_path, path)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("GeoIPUpdater: Updated %v database %v (%v bytes)",
+		p.name, edition, len(data))
+	return nil
+}
+
+func (self *GeoIPUpdater) Start(ctx context.Context) {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(self.check_interval):
+			err := self.UpdateAll(ctx)
+			if err != nil {
+				logger.Debug("GeoIPUpdater: %v", err)
+			}
+		}
+	}
+}
+
+func getString(metadata *ordereddict.Dict, key string) string {
+	value, _ := metadata.GetString(key)
+	return strings.TrimSpace(value)
+}
+
+func fetch(ctx context.Context, client networking.HTTPClient,
+	download_url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", download_url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Download failed with status %v", resp.Status)
+	}
+
+	return ioutil.ReadAll(io.LimitReader(resp.Body, MAX_DOWNLOAD_SIZE))
+}
+
+// MaxMind databases are distributed as a tar.gz containing the .mmdb
+// file.
+func downloadMaxMind(ctx context.Context, client networking.HTTPClient,
+	edition, key string) ([]byte, error) {
+	params := url.Values{}
+	params.Set("edition_id", edition)
+	params.Set("license_key", key)
+	params.Set("suffix", "tar.gz")
+
+	data, err := fetch(ctx, client, maxmind_url+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	tar_reader := tar.NewReader(gz)
+	for {
+		header, err := tar_reader.Next()
+		if err != nil {
+			return nil, fmt.Errorf("No database found in archive: %w", err)
+		}
+
+		if strings.HasSuffix(header.Name, ".mmdb") {
+			return ioutil.ReadAll(io.LimitReader(tar_reader, MAX_DOWNLOAD_SIZE))
+		}
+	}
+}
+
+// IP2Location databases are distributed as a zip containing the
+// .BIN file.
+func downloadIP2Location(ctx context.Context, client networking.HTTPClient,
+	edition, key string) ([]byte, error) {
+	params := url.Values{}
+	params.Set("token", key)
+	params.Set("file", edition)
+
+	data, err := fetch(ctx, client, ip2location_url+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	// Errors are reported as a short text message.
+	zip_reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		if len(data) < 100 {
+			return nil, errors.New(strings.TrimSpace(string(data)))
+		}
+		return nil, err
+	}
+
+	for _, member := range zip_reader.File {
+		if !isIP2Location(member.Name) {
+			continue
+		}
+
+		fd, err := member.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer fd.Close()
+
+		return ioutil.ReadAll(io.LimitReader(fd, MAX_DOWNLOAD_SIZE))
+	}
+
+	return nil, errors.New("No database found in archive")
+}
+
+func NewGeoIPUpdater(ctx context.Context,
+	config_obj *config_proto.Config) (*GeoIPUpdater, error) {
+	scope := vql_subsystem.MakeScope()
+	client, err := networking.GetDefaultHTTPClient(
+		ctx, config_obj.Client, scope, "", networking.EmptyCookieJar)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeoIPUpdater{
+		config_obj:     config_obj,
+		Client:         client,
+		check_interval: time.Hour,
+	}, nil
+}
+
+// The update service runs on the master frontend and keeps the
+// managed GeoIP databases up to date.
+func StartGeoIPUpdateService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if config_obj.Datastore == nil {
+		return nil
+	}
+
+	updater, err := NewGeoIPUpdater(ctx, config_obj)
+	if err != nil {
+		return err
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// Give the other services a chance to start before the
+		// first check.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Minute):
+		}
+
+		err := updater.UpdateAll(ctx)
+		if err != nil {
+			logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+			logger.Debug("GeoIPUpdater: %v", err)
+		}
+
+		updater.Start(ctx)
+	}()
+
+	return nil
+}
@@ -24,7 +24,9 @@ package services
 
 import (
 	"context"
+	"time"
 
+	"github.com/Velocidex/ordereddict"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
@@ -55,6 +57,115 @@ const (
 	HuntFlushToDatastoreAsync
 )
 
+// A VQL query selecting the clients a hunt applies to. The query
+// runs with the permissions of the principal who set it.
+type HuntVQLCondition struct {
+	Query     string `json:"query"`
+	Principal string `json:"principal"`
+}
+
+// Limits on the total resources used by all the hunt's
+// collections. When a limit is exceeded the hunt is stopped and its
+// outstanding collections are cancelled. A value of 0 means no limit.
+type HuntQuota struct {
+	MaxRows        uint64 `json:"max_rows"`
+	MaxUploadBytes uint64 `json:"max_upload_bytes"`
+
+	// The total execution time of all collections in seconds.
+	MaxExecutionTime uint64 `json:"max_execution_time"`
+}
+
+// A point in time estimate of how far a hunt has progressed.
+type HuntProgress struct {
+	HuntId string `json:"HuntId"`
+	State  string `json:"State"`
+
+	// Number of clients we expect the hunt to run on. These are all
+	// the recently active clients matching the hunt condition, up to
+	// the hunt's client limit.
+	TotalClientsTargeted uint64 `json:"TotalClientsTargeted"`
+
+	TotalClientsScheduled uint64 `json:"TotalClientsScheduled"`
+	TotalClientsCompleted uint64 `json:"TotalClientsCompleted"`
+	TotalClientsErrored   uint64 `json:"TotalClientsErrored"`
+	TotalClientsRunning   uint64 `json:"TotalClientsRunning"`
+
+	TotalCollectedRows uint64 `json:"TotalCollectedRows"`
+	TotalUploadedBytes uint64 `json:"TotalUploadedBytes"`
+
+	// The hunt's scheduling rate limit in clients per minute (0 for
+	// no limit).
+	ClientsPerMinute float64 `json:"ClientsPerMinute"`
+
+	// Set to the reason the hunt was stopped if it exceeded its
+	// quota.
+	QuotaExceeded string `json:"QuotaExceeded"`
+
+	// Clients scheduled per hour recently - this is the rate at which
+	// targeted clients check in and join the hunt.
+	CheckInRate float64 `json:"CheckInRate"`
+
+	// Percentage of targeted clients which have completed the hunt
+	// (successfully or with an error).
+	PercentComplete float64 `json:"PercentComplete"`
+
+	// Estimated time the hunt will complete. Zero if the hunt is not
+	// running or there is not enough information to estimate.
+	ETA time.Time `json:"ETA"`
+
+	// Set when the hunt is likely to expire before all the targeted
+	// clients are scheduled.
+	ExpiresBeforeCompletion bool `json:"ExpiresBeforeCompletion"`
+
+	// Set for dry run hunts which only estimate the upload volume.
+	DryRun bool `json:"DryRun"`
+
+	// Files and bytes the hunt's collections uploaded, or would
+	// have uploaded for a dry run.
+	TotalUploadedFiles         uint64 `json:"TotalUploadedFiles"`
+	TotalExpectedUploadedBytes uint64 `json:"TotalExpectedUploadedBytes"`
+
+	// The upload volume projected to all the matching clients based
+	// on the average of the completed collections. For dry run hunts
+	// the projection ignores the client limit, since the limit only
+	// selects the sample.
+	ProjectedUploadFiles uint64 `json:"ProjectedUploadFiles"`
+	ProjectedUploadBytes uint64 `json:"ProjectedUploadBytes"`
+}
+
+// The number of clients in a hunt which produced each distinct value
+// of some columns. Rare values are often the interesting ones.
+type HuntStackRow struct {
+	// The values of the stacked columns.
+	Value *ordereddict.Dict `json:"Value"`
+
+	Clients uint64 `json:"Clients"`
+	Rows    uint64 `json:"Rows"`
+
+	// Percent of the hunt's clients with results which had this
+	// value.
+	Percent float64 `json:"Percent"`
+
+	// Some of the clients which had this value.
+	SampleClients []string `json:"SampleClients"`
+}
+
+type HuntStack struct {
+	HuntId   string   `json:"HuntId"`
+	Artifact string   `json:"Artifact"`
+	Columns  []string `json:"Columns"`
+
+	// Number of clients which returned results for the artifact.
+	TotalClients uint64 `json:"TotalClients"`
+
+	// Sorted by the number of clients - the least frequent values
+	// first.
+	Rows []*HuntStackRow `json:"Rows"`
+
+	// Set if there were too many distinct values to stack them all.
+	Truncated bool `json:"Truncated"`
+}
+
 type IHuntDispatcher interface {
 	// Applies the function on all the hunts. Functions may not
 	// modify the hunt but will have read only access to the hunt
@@ -87,6 +198,45 @@ type IHuntDispatcher interface {
 		scope vfilter.Scope,
 		hunt_id string, start int) chan *api_proto.FlowDetails
 
+	// Estimate the hunt's progress and when it will complete.
+	GetHuntProgress(ctx context.Context, config_obj *config_proto.Config,
+		hunt_id string) (*HuntProgress, error)
+
+	// Count how many of the hunt's clients returned each distinct
+	// value of the columns in the artifact's results. If no columns
+	// are given, whole rows are stacked.
+	StackHuntResults(ctx context.Context, config_obj *config_proto.Config,
+		hunt_id, artifact string, columns []string) (*HuntStack, error)
+
+	// Limit the rate at which new clients are scheduled on the hunt
+	// (clients per minute). A rate of 0 removes the limit. The hunt
+	// manager applies the new rate immediately.
+	SetHuntSchedulingRate(ctx context.Context,
+		config_obj *config_proto.Config,
+		hunt_id string, clients_per_minute float64) error
+
+	GetHuntSchedulingRate(config_obj *config_proto.Config,
+		hunt_id string) (float64, error)
+
+	// Restrict the hunt to clients selected by a VQL query. The
+	// query is evaluated on the server for each client with the
+	// ClientId variable set, on behalf of the principal. An empty
+	// query removes the condition.
+	SetHuntVQLCondition(ctx context.Context,
+		config_obj *config_proto.Config,
+		hunt_id, principal, query string) error
+
+	GetHuntVQLCondition(config_obj *config_proto.Config,
+		hunt_id string) (*HuntVQLCondition, error)
+
+	// Limit the total resources the hunt may use.
+	SetHuntQuota(ctx context.Context,
+		config_obj *config_proto.Config,
+		hunt_id string, quota *HuntQuota) error
+
+	GetHuntQuota(config_obj *config_proto.Config,
+		hunt_id string) (*HuntQuota, error)
+
 	CreateHunt(ctx context.Context,
 		config_obj *config_proto.Config,
 		acl_manager vql_subsystem.ACLManager,
@@ -0,0 +1,89 @@
+package hunt_dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/vfilter"
+)
+
+func (self *HuntDispatcher) SetHuntVQLCondition(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id, principal, query string) error {
+
+	hunt_obj, pres := self.GetHunt(hunt_id)
+	if !pres {
+		return errors.New("Hunt not found")
+	}
+
+	query = strings.TrimSpace(query)
+	if query != "" {
+		_, err := vfilter.MultiParse(query)
+		if err != nil {
+			return fmt.Errorf("Invalid VQL condition: %w", err)
+		}
+	}
+
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(&services.HuntVQLCondition{
+		Query:     query,
+		Principal: principal,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = raw_db.SetBuffer(config_obj,
+		paths.NewHuntPathManager(hunt_id).VQLCondition(),
+		serialized, utils.BackgroundWriter)
+	if err != nil {
+		return err
+	}
+
+	// Clients which did not match the old condition may match the
+	// new one.
+	if hunt_obj.State == api_proto.Hunt_RUNNING {
+		return self.participateAllConnectedClients(ctx, config_obj, hunt_id)
+	}
+
+	return nil
+}
+
+// Hunts without a VQL condition return an empty query.
+func (self *HuntDispatcher) GetHuntVQLCondition(
+	config_obj *config_proto.Config,
+	hunt_id string) (*services.HuntVQLCondition, error) {
+
+	result := &services.HuntVQLCondition{}
+
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := raw_db.GetBuffer(config_obj,
+		paths.NewHuntPathManager(hunt_id).VQLCondition())
+	if err != nil || len(data) == 0 {
+		return result, nil
+	}
+
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -40,6 +40,10 @@ func (self *HuntDispatcherTestSuite) SetupTest() {
 	self.LoadArtifactsIntoConfig([]string{`
 name: Server.Internal.HuntUpdate
 type: INTERNAL
+`, `
+name: Windows.Sysinternals.Autoruns
+sources:
+- query: SELECT * FROM scope()
 `})
 
 	self.time_closer = utils.MockTime(&utils.IncClock{})
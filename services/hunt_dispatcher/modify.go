@@ -15,7 +15,9 @@ import (
 // 1. A hunt in the paused state can go to the running state. This
 //    will update the StartTime.
 // 2. A hunt in the running state can go to the Stop state
-// 3. A hunt's description can be modified.
+// 3. A hunt in the running state can be paused. Paused hunts do not
+//    schedule new clients until they are resumed (set to running).
+// 4. A hunt's description can be modified.
 func (self *HuntDispatcher) ModifyHunt(
 	ctx context.Context,
 	config_obj *config_proto.Config,
@@ -70,9 +72,12 @@ func (self *HuntDispatcher) ModifyHunt(
 		mutation.State = api_proto.Hunt_RUNNING
 		mutation.StartTime = uint64(utils.GetTime().Now().UnixNano() / 1000)
 
-		// We are trying to pause or stop the hunt.
-	} else if hunt_modification.State == api_proto.Hunt_STOPPED ||
-		hunt_modification.State == api_proto.Hunt_PAUSED {
+		// We are trying to pause the hunt.
+	} else if hunt_modification.State == api_proto.Hunt_PAUSED {
+		mutation.State = api_proto.Hunt_PAUSED
+
+		// We are trying to stop the hunt.
+	} else if hunt_modification.State == api_proto.Hunt_STOPPED {
 		mutation.State = api_proto.Hunt_STOPPED
 	}
 
@@ -0,0 +1,238 @@
+package hunt_dispatcher
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+const (
+	// Only clients seen recently are expected to join the hunt.
+	ACTIVE_CLIENT_WINDOW = 7 * 24 * time.Hour
+
+	// The check in rate is measured over this window.
+	CHECK_IN_RATE_WINDOW = time.Hour
+)
+
+// Estimate the hunt's progress. The ETA is modeled on the rate at
+// which targeted clients have recently checked in and joined the
+// hunt: the remaining clients are expected to join at the same rate
+// and then take as long as the average completed collection.
+func (self *HuntDispatcher) GetHuntProgress(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string) (*services.HuntProgress, error) {
+
+	hunt_obj, pres := self.GetHunt(hunt_id)
+	if !pres {
+		return nil, errors.New("Hunt not found")
+	}
+
+	result := &services.HuntProgress{
+		HuntId: hunt_id,
+		State:  hunt_obj.State.String(),
+		DryRun: hunt_obj.StartRequest != nil && hunt_obj.StartRequest.DryRun,
+	}
+
+	stopped := hunt_obj.Stats != nil && hunt_obj.Stats.Stopped
+	if stopped {
+		result.State = api_proto.Hunt_STOPPED.String()
+	}
+
+	if hunt_obj.Stats != nil {
+		result.TotalClientsScheduled = hunt_obj.Stats.TotalClientsScheduled
+	}
+
+	clients_per_minute, err := self.GetHuntSchedulingRate(config_obj, hunt_id)
+	if err != nil {
+		return nil, err
+	}
+	result.ClientsPerMinute = clients_per_minute
+
+	usage, err := hunt_manager.GetHuntQuotaUsage(config_obj, hunt_id)
+	if err != nil {
+		return nil, err
+	}
+	result.QuotaExceeded = usage.Exceeded
+
+	now := utils.GetTime().Now()
+
+	// Measure the check in rate over the last window, or since the
+	// hunt started if it is more recent.
+	window := CHECK_IN_RATE_WINDOW
+	if hunt_obj.StartTime > 0 {
+		since_start := now.Sub(time.Unix(0, int64(hunt_obj.StartTime)*1000))
+		if since_start > 0 && since_start < window {
+			window = since_start
+		}
+	}
+	window_start := uint64(now.Add(-window).UnixNano() / 1000)
+
+	recently_scheduled := 0
+	total_duration := time.Duration(0)
+
+	// Upload volume of the completed collections.
+	completed_files := uint64(0)
+	completed_bytes := uint64(0)
+
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	for flow := range self.GetFlows(ctx, config_obj, scope, hunt_id, 0) {
+		if flow.Context == nil {
+			continue
+		}
+
+		result.TotalCollectedRows += flow.Context.TotalCollectedRows
+		result.TotalUploadedBytes += flow.Context.TotalUploadedBytes
+		result.TotalUploadedFiles += flow.Context.TotalUploadedFiles
+		result.TotalExpectedUploadedBytes += flow.Context.TotalExpectedUploadedBytes
+
+		if flow.Context.CreateTime > window_start {
+			recently_scheduled++
+		}
+
+		switch flow.Context.State {
+		case flows_proto.ArtifactCollectorContext_FINISHED:
+			result.TotalClientsCompleted++
+			total_duration += time.Duration(flow.Context.ExecutionDuration)
+			completed_files += flow.Context.TotalUploadedFiles
+			completed_bytes += flow.Context.TotalExpectedUploadedBytes
+
+		case flows_proto.ArtifactCollectorContext_ERROR:
+			result.TotalClientsErrored++
+
+		default:
+			result.TotalClientsRunning++
+		}
+	}
+
+	targeted, err := self.countTargetedClients(ctx, config_obj, hunt_obj, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients may have been scheduled manually or have gone quiet
+	// since they were scheduled.
+	if targeted < result.TotalClientsScheduled {
+		targeted = result.TotalClientsScheduled
+	}
+
+	// A dry run samples a few clients to project the volume of the
+	// full hunt so project to all the matching clients.
+	projected := targeted
+
+	if hunt_obj.ClientLimit > 0 && targeted > hunt_obj.ClientLimit {
+		targeted = hunt_obj.ClientLimit
+	}
+	result.TotalClientsTargeted = targeted
+
+	if !result.DryRun {
+		projected = targeted
+	}
+
+	if result.TotalClientsCompleted > 0 {
+		result.ProjectedUploadFiles = completed_files *
+			projected / result.TotalClientsCompleted
+		result.ProjectedUploadBytes = completed_bytes *
+			projected / result.TotalClientsCompleted
+	}
+
+	done := result.TotalClientsCompleted + result.TotalClientsErrored
+	if targeted > 0 {
+		result.PercentComplete = float64(done*100) / float64(targeted)
+	}
+
+	if window > 0 {
+		result.CheckInRate = float64(recently_scheduled) / window.Hours()
+	}
+
+	if stopped || hunt_obj.State != api_proto.Hunt_RUNNING {
+		return result, nil
+	}
+
+	remaining := targeted - result.TotalClientsScheduled
+
+	// Nothing left to do.
+	if remaining == 0 && result.TotalClientsRunning == 0 {
+		return result, nil
+	}
+
+	// No clients are checking in so we can not estimate when the
+	// rest will join.
+	if remaining > 0 && result.CheckInRate == 0 {
+		return result, nil
+	}
+
+	eta := now
+	if remaining > 0 {
+		eta = eta.Add(time.Duration(
+			float64(remaining) / result.CheckInRate * float64(time.Hour)))
+	}
+
+	if result.TotalClientsCompleted > 0 {
+		eta = eta.Add(total_duration /
+			time.Duration(result.TotalClientsCompleted))
+	}
+
+	result.ETA = eta.UTC()
+
+	expires := time.Unix(0, int64(hunt_obj.Expires)*1000)
+	if hunt_obj.Expires > 0 && remaining > 0 && eta.After(expires) {
+		result.ExpiresBeforeCompletion = true
+	}
+
+	return result, nil
+}
+
+// Count the recently active clients that match the hunt's conditions.
+func (self *HuntDispatcher) countTargetedClients(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_obj *api_proto.Hunt, now time.Time) (uint64, error) {
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return 0, err
+	}
+
+	condition, err := self.GetHuntVQLCondition(config_obj, hunt_obj.HuntId)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := uint64(now.Add(-ACTIVE_CLIENT_WINDOW).UnixNano() / 1000)
+
+	result := uint64(0)
+	snapshot := client_info_manager.Snapshot(ctx)
+	for _, client_id := range snapshot.Keys() {
+		client_info, pres := snapshot.Get(client_id)
+		if !pres || client_info.Ping < cutoff {
+			continue
+		}
+
+		if !hunt_manager.HuntMatchesClient(ctx, config_obj, hunt_obj, client_info) {
+			continue
+		}
+
+		matches, err := hunt_manager.HuntMatchesVQLCondition(
+			ctx, config_obj, condition, client_id)
+		if err != nil {
+			return 0, err
+		}
+
+		if matches {
+			result++
+		}
+	}
+
+	return result, nil
+}
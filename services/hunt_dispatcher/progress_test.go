@@ -0,0 +1,219 @@
+package hunt_dispatcher_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func (self *HuntDispatcherTestSuite) TestHuntProgress() {
+	now := time.Unix(1700000000, 0)
+	closer := utils.MockTime(utils.NewMockClock(now))
+	defer closer()
+
+	usec := func(t time.Time) uint64 {
+		return uint64(t.UnixNano() / 1000)
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// 10 active clients and 2 which have not been seen for a month.
+	for i := 0; i < 12; i++ {
+		ping := now
+		if i >= 10 {
+			ping = now.Add(-30 * 24 * time.Hour)
+		}
+
+		err := client_info_manager.Set(self.Ctx, &services.ClientInfo{
+			actions_proto.ClientInfo{
+				ClientId: fmt.Sprintf("C.%d", i),
+				Ping:     usec(ping),
+			},
+		})
+		assert.NoError(self.T(), err)
+	}
+
+	self.master_dispatcher.ModifyHuntObject(self.Ctx, "H.1",
+		func(hunt *api_proto.Hunt) services.HuntModificationAction {
+			hunt.StartTime = usec(now.Add(-2 * time.Hour))
+			hunt.Expires = usec(now.Add(24 * time.Hour))
+			hunt.Stats = &api_proto.HuntStats{TotalClientsScheduled: 4}
+			return services.HuntFlushToDatastore
+		})
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	flows := []*flows_proto.ArtifactCollectorContext{{
+		ClientId:           "C.0",
+		CreateTime:         usec(now.Add(-90 * time.Minute)),
+		ExecutionDuration:  int64(10 * time.Minute),
+		State:              flows_proto.ArtifactCollectorContext_FINISHED,
+		TotalCollectedRows: 10,
+		TotalUploadedBytes: 100,
+	}, {
+		ClientId:           "C.1",
+		CreateTime:         usec(now.Add(-30 * time.Minute)),
+		ExecutionDuration:  int64(20 * time.Minute),
+		State:              flows_proto.ArtifactCollectorContext_FINISHED,
+		TotalCollectedRows: 5,
+		TotalUploadedBytes: 50,
+	}, {
+		ClientId:   "C.2",
+		CreateTime: usec(now.Add(-20 * time.Minute)),
+		State:      flows_proto.ArtifactCollectorContext_ERROR,
+	}, {
+		ClientId:   "C.3",
+		CreateTime: usec(now.Add(-10 * time.Minute)),
+		State:      flows_proto.ArtifactCollectorContext_RUNNING,
+	}}
+
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.NewHuntPathManager("H.1").Clients(), json.DefaultEncOpts(),
+		utils.SyncCompleter, result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	for _, flow := range flows {
+		flow.SessionId = "F.1234"
+		assert.NoError(self.T(), launcher.Storage().WriteFlow(
+			self.Ctx, self.ConfigObj, flow, utils.SyncCompleter))
+
+		writer.Write(ordereddict.NewDict().
+			Set("HuntId", "H.1").
+			Set("ClientId", flow.ClientId).
+			Set("FlowId", flow.SessionId))
+	}
+	writer.Close()
+
+	progress, err := self.master_dispatcher.GetHuntProgress(
+		self.Ctx, self.ConfigObj, "H.1")
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), uint64(10), progress.TotalClientsTargeted)
+	assert.Equal(self.T(), uint64(4), progress.TotalClientsScheduled)
+	assert.Equal(self.T(), uint64(2), progress.TotalClientsCompleted)
+	assert.Equal(self.T(), uint64(1), progress.TotalClientsErrored)
+	assert.Equal(self.T(), uint64(1), progress.TotalClientsRunning)
+	assert.Equal(self.T(), uint64(15), progress.TotalCollectedRows)
+	assert.Equal(self.T(), uint64(150), progress.TotalUploadedBytes)
+	assert.Equal(self.T(), float64(30), progress.PercentComplete)
+
+	// 3 clients joined in the last hour so the remaining 6 will
+	// take 2 hours to join, then 15 minutes on average to complete.
+	assert.Equal(self.T(), float64(3), progress.CheckInRate)
+	assert.Equal(self.T(), now.Add(135*time.Minute).UTC(), progress.ETA)
+	assert.True(self.T(), !progress.ExpiresBeforeCompletion)
+
+	// The hunt expires before the remaining clients can join.
+	self.master_dispatcher.ModifyHuntObject(self.Ctx, "H.1",
+		func(hunt *api_proto.Hunt) services.HuntModificationAction {
+			hunt.Expires = usec(now.Add(time.Hour))
+			return services.HuntFlushToDatastore
+		})
+
+	progress, err = self.master_dispatcher.GetHuntProgress(
+		self.Ctx, self.ConfigObj, "H.1")
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), progress.ExpiresBeforeCompletion)
+
+	// Stopped hunts have no ETA.
+	self.master_dispatcher.ModifyHuntObject(self.Ctx, "H.1",
+		func(hunt *api_proto.Hunt) services.HuntModificationAction {
+			hunt.State = api_proto.Hunt_STOPPED
+			return services.HuntFlushToDatastore
+		})
+
+	progress, err = self.master_dispatcher.GetHuntProgress(
+		self.Ctx, self.ConfigObj, "H.1")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "STOPPED", progress.State)
+	assert.True(self.T(), progress.ETA.IsZero())
+}
+
+// Dry run hunts project the upload volume of the sampled clients to
+// all the matching clients.
+func (self *HuntDispatcherTestSuite) TestHuntProgressDryRun() {
+	now := time.Unix(1700000000, 0)
+	closer := utils.MockTime(utils.NewMockClock(now))
+	defer closer()
+
+	usec := func(t time.Time) uint64 {
+		return uint64(t.UnixNano() / 1000)
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	for i := 0; i < 10; i++ {
+		err := client_info_manager.Set(self.Ctx, &services.ClientInfo{
+			actions_proto.ClientInfo{
+				ClientId: fmt.Sprintf("C.%d", i),
+				Ping:     usec(now),
+			},
+		})
+		assert.NoError(self.T(), err)
+	}
+
+	self.master_dispatcher.ModifyHuntObject(self.Ctx, "H.1",
+		func(hunt *api_proto.Hunt) services.HuntModificationAction {
+			hunt.StartTime = usec(now.Add(-time.Hour))
+			hunt.ClientLimit = 2
+			hunt.StartRequest = &flows_proto.ArtifactCollectorArgs{DryRun: true}
+			hunt.Stats = &api_proto.HuntStats{TotalClientsScheduled: 2}
+			return services.HuntFlushToDatastore
+		})
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.NewHuntPathManager("H.1").Clients(), json.DefaultEncOpts(),
+		utils.SyncCompleter, result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	for i, files := range []uint64{10, 30} {
+		flow := &flows_proto.ArtifactCollectorContext{
+			ClientId:                   fmt.Sprintf("C.%d", i),
+			SessionId:                  "F.1234",
+			CreateTime:                 usec(now.Add(-30 * time.Minute)),
+			State:                      flows_proto.ArtifactCollectorContext_FINISHED,
+			TotalUploadedFiles:         files,
+			TotalExpectedUploadedBytes: files * 1000,
+		}
+		assert.NoError(self.T(), launcher.Storage().WriteFlow(
+			self.Ctx, self.ConfigObj, flow, utils.SyncCompleter))
+
+		writer.Write(ordereddict.NewDict().
+			Set("HuntId", "H.1").
+			Set("ClientId", flow.ClientId).
+			Set("FlowId", flow.SessionId))
+	}
+	writer.Close()
+
+	progress, err := self.master_dispatcher.GetHuntProgress(
+		self.Ctx, self.ConfigObj, "H.1")
+	assert.NoError(self.T(), err)
+
+	assert.True(self.T(), progress.DryRun)
+	assert.Equal(self.T(), uint64(2), progress.TotalClientsTargeted)
+	assert.Equal(self.T(), uint64(40), progress.TotalUploadedFiles)
+	assert.Equal(self.T(), uint64(40000), progress.TotalExpectedUploadedBytes)
+
+	// The sample averages 20 files and 20kb per client.
+	assert.Equal(self.T(), uint64(200), progress.ProjectedUploadFiles)
+	assert.Equal(self.T(), uint64(200000), progress.ProjectedUploadBytes)
+}
@@ -0,0 +1,65 @@
+package hunt_dispatcher
+
+import (
+	"context"
+	"errors"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// The quota is checked by the hunt manager as the hunt's collections
+// complete.
+func (self *HuntDispatcher) SetHuntQuota(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string, quota *services.HuntQuota) error {
+
+	_, pres := self.GetHunt(hunt_id)
+	if !pres {
+		return errors.New("Hunt not found")
+	}
+
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(config_obj,
+		paths.NewHuntPathManager(hunt_id).Quota(),
+		serialized, utils.BackgroundWriter)
+}
+
+// Hunts without a quota return an empty quota.
+func (self *HuntDispatcher) GetHuntQuota(
+	config_obj *config_proto.Config,
+	hunt_id string) (*services.HuntQuota, error) {
+
+	result := &services.HuntQuota{}
+
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := raw_db.GetBuffer(config_obj,
+		paths.NewHuntPathManager(hunt_id).Quota())
+	if err != nil || len(data) == 0 {
+		return result, nil
+	}
+
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
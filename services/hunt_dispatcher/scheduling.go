@@ -0,0 +1,105 @@
+package hunt_dispatcher
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type schedulingRate struct {
+	ClientsPerMinute float64 `json:"clients_per_minute"`
+}
+
+func getRawDB(config_obj *config_proto.Config) (datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, errors.New("Datastore does not support raw access")
+	}
+	return raw_db, nil
+}
+
+func (self *HuntDispatcher) SetHuntSchedulingRate(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string, clients_per_minute float64) error {
+
+	if clients_per_minute < 0 {
+		return errors.New("Scheduling rate can not be negative")
+	}
+
+	_, pres := self.GetHunt(hunt_id)
+	if !pres {
+		return errors.New("Hunt not found")
+	}
+
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(&schedulingRate{
+		ClientsPerMinute: clients_per_minute,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = raw_db.SetBuffer(config_obj,
+		paths.NewHuntPathManager(hunt_id).SchedulingRate(),
+		serialized, utils.BackgroundWriter)
+	if err != nil {
+		return err
+	}
+
+	// Let the hunt manager know about the new rate.
+	journal, err := services.GetJournal(config_obj)
+	if err != nil {
+		return err
+	}
+
+	journal.PushRowsToArtifactAsync(ctx, config_obj,
+		ordereddict.NewDict().
+			Set("hunt_id", hunt_id).
+			Set("mutation", &api_proto.HuntMutation{HuntId: hunt_id}).
+			Set("clients_per_minute", clients_per_minute),
+		"Server.Internal.HuntModification")
+
+	return nil
+}
+
+// Hunts without a scheduling rate limit have a rate of 0.
+func (self *HuntDispatcher) GetHuntSchedulingRate(
+	config_obj *config_proto.Config, hunt_id string) (float64, error) {
+
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := raw_db.GetBuffer(config_obj,
+		paths.NewHuntPathManager(hunt_id).SchedulingRate())
+	if err != nil || len(data) == 0 {
+		return 0, nil
+	}
+
+	result := &schedulingRate{}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ClientsPerMinute, nil
+}
@@ -0,0 +1,190 @@
+package hunt_dispatcher
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+const (
+	// Stacking is done in memory so limit the number of distinct
+	// values.
+	MAX_STACK_VALUES = 100000
+
+	MAX_SAMPLE_CLIENTS = 10
+)
+
+var (
+	// These columns are added by the server and are different for
+	// each client so are never stacked.
+	stackIgnoredColumns = []string{
+		"_Source", "_ts", "ClientId", "FlowId", "Fqdn", "_OrgId"}
+)
+
+type stackEntry struct {
+	row     *services.HuntStackRow
+	clients map[string]bool
+}
+
+// Least frequency of occurrence analysis: count how many clients
+// returned each distinct value across the whole hunt.
+func (self *HuntDispatcher) StackHuntResults(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id, artifact string, columns []string) (*services.HuntStack, error) {
+
+	hunt_obj, pres := self.GetHunt(hunt_id)
+	if !pres {
+		return nil, errors.New("Hunt not found")
+	}
+
+	if artifact == "" {
+		artifact = defaultHuntSource(hunt_obj)
+		if artifact == "" {
+			return nil, errors.New("No artifacts in hunt")
+		}
+	}
+
+	result := &services.HuntStack{
+		HuntId:   hunt_id,
+		Artifact: artifact,
+		Columns:  columns,
+		Rows:     []*services.HuntStackRow{},
+	}
+
+	index := make(map[string]*stackEntry)
+
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+
+	for flow := range self.GetFlows(ctx, config_obj, scope, hunt_id, 0) {
+		if flow.Context == nil ||
+			!utils.InString(flow.Context.ArtifactsWithResults, artifact) {
+			continue
+		}
+
+		client_id := flow.Context.ClientId
+		path_manager, err := artifacts.NewArtifactPathManager(ctx,
+			config_obj, client_id, flow.Context.SessionId, artifact)
+		if err != nil {
+			return nil, err
+		}
+
+		reader, err := result_sets.NewResultSetReader(
+			file_store_factory, path_manager.Path())
+		if err != nil {
+			continue
+		}
+
+		result.TotalClients++
+
+		for row := range reader.Rows(ctx) {
+			value := stackValue(columns, row)
+			key := serializeStackValue(value)
+
+			entry, pres := index[key]
+			if !pres {
+				if len(index) >= MAX_STACK_VALUES {
+					result.Truncated = true
+					continue
+				}
+
+				entry = &stackEntry{
+					row: &services.HuntStackRow{
+						Value:         value,
+						SampleClients: []string{},
+					},
+					clients: make(map[string]bool),
+				}
+				index[key] = entry
+				result.Rows = append(result.Rows, entry.row)
+			}
+
+			entry.row.Rows++
+			if !entry.clients[client_id] {
+				entry.clients[client_id] = true
+				entry.row.Clients++
+				if len(entry.row.SampleClients) < MAX_SAMPLE_CLIENTS {
+					entry.row.SampleClients = append(
+						entry.row.SampleClients, client_id)
+				}
+			}
+		}
+		reader.Close()
+	}
+
+	for _, row := range result.Rows {
+		if result.TotalClients > 0 {
+			row.Percent = float64(row.Clients*100) /
+				float64(result.TotalClients)
+		}
+	}
+
+	// Rarest values first.
+	sort.SliceStable(result.Rows, func(i, j int) bool {
+		if result.Rows[i].Clients != result.Rows[j].Clients {
+			return result.Rows[i].Clients < result.Rows[j].Clients
+		}
+		return result.Rows[i].Rows < result.Rows[j].Rows
+	})
+
+	return result, nil
+}
+
+// By default stack the first source collected by the hunt.
+func defaultHuntSource(hunt_obj *api_proto.Hunt) string {
+	if len(hunt_obj.ArtifactSources) > 0 {
+		return hunt_obj.ArtifactSources[0]
+	}
+
+	if len(hunt_obj.Artifacts) > 0 {
+		return hunt_obj.Artifacts[0]
+	}
+
+	if hunt_obj.StartRequest != nil &&
+		len(hunt_obj.StartRequest.Artifacts) > 0 {
+		return hunt_obj.StartRequest.Artifacts[0]
+	}
+	return ""
+}
+
+func stackValue(columns []string, row *ordereddict.Dict) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+	if len(columns) == 0 {
+		for _, k := range row.Keys() {
+			if utils.InString(stackIgnoredColumns, k) {
+				continue
+			}
+			v, _ := row.Get(k)
+			result.Set(k, v)
+		}
+		return result
+	}
+
+	for _, column := range columns {
+		v, _ := row.Get(column)
+		result.Set(column, v)
+	}
+	return result
+}
+
+func serializeStackValue(value *ordereddict.Dict) string {
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return value.String()
+	}
+	return string(serialized)
+}
@@ -0,0 +1,95 @@
+package hunt_dispatcher_test
+
+import (
+	"fmt"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func (self *HuntDispatcherTestSuite) TestStackHuntResults() {
+	artifact := "Windows.Sysinternals.Autoruns"
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	clients_writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.NewHuntPathManager("H.1").Clients(), json.DefaultEncOpts(),
+		utils.SyncCompleter, result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	// All 4 clients have the same common autoruns but only one has
+	// the suspicious entry.
+	for i := 0; i < 4; i++ {
+		flow := &flows_proto.ArtifactCollectorContext{
+			ClientId:             fmt.Sprintf("C.%d", i),
+			SessionId:            "F.1234",
+			State:                flows_proto.ArtifactCollectorContext_FINISHED,
+			ArtifactsWithResults: []string{artifact},
+		}
+		assert.NoError(self.T(), launcher.Storage().WriteFlow(
+			self.Ctx, self.ConfigObj, flow, utils.SyncCompleter))
+
+		clients_writer.Write(ordereddict.NewDict().
+			Set("HuntId", "H.1").
+			Set("ClientId", flow.ClientId).
+			Set("FlowId", flow.SessionId))
+
+		path_manager, err := artifacts.NewArtifactPathManager(self.Ctx,
+			self.ConfigObj, flow.ClientId, flow.SessionId, artifact)
+		assert.NoError(self.T(), err)
+
+		writer, err := result_sets.NewResultSetWriter(file_store_factory,
+			path_manager.Path(), json.DefaultEncOpts(),
+			utils.SyncCompleter, result_sets.TruncateMode)
+		assert.NoError(self.T(), err)
+
+		entries := []string{"OneDrive", "SecurityHealth"}
+		if i == 2 {
+			entries = append(entries, "Updater")
+		}
+
+		for _, entry := range entries {
+			writer.Write(ordereddict.NewDict().
+				Set("Entry", entry).
+				Set("Enabled", "enabled").
+				Set("ClientId", flow.ClientId))
+		}
+		writer.Close()
+	}
+	clients_writer.Close()
+
+	stack, err := self.master_dispatcher.StackHuntResults(
+		self.Ctx, self.ConfigObj, "H.1", artifact, []string{"Entry"})
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), uint64(4), stack.TotalClients)
+	assert.Equal(self.T(), 3, len(stack.Rows))
+
+	// The rarest entry comes first.
+	entry, _ := stack.Rows[0].Value.GetString("Entry")
+	assert.Equal(self.T(), "Updater", entry)
+	assert.Equal(self.T(), uint64(1), stack.Rows[0].Clients)
+	assert.Equal(self.T(), float64(25), stack.Rows[0].Percent)
+	assert.Equal(self.T(), []string{"C.2"}, stack.Rows[0].SampleClients)
+
+	assert.Equal(self.T(), uint64(4), stack.Rows[2].Clients)
+
+	// Without columns whole rows are stacked, ignoring the
+	// ClientId column.
+	stack, err = self.master_dispatcher.StackHuntResults(
+		self.Ctx, self.ConfigObj, "H.1", artifact, nil)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, len(stack.Rows))
+	assert.Equal(self.T(), []string{"Entry", "Enabled"},
+		stack.Rows[0].Value.Keys())
+}
@@ -0,0 +1,105 @@
+package hunt_manager
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// Hunts may select the clients they apply to with a VQL query over
+// the client's interrogation data, for example:
+//
+//	SELECT * FROM clients(client_id=ClientId)
+//	WHERE os_info.release =~ 'Server 2012'
+//
+// The query is evaluated for each client with the ClientId variable
+// set. The client matches if the query returns a row for it - rows
+// without a client_id column match the current client.
+func HuntMatchesVQLCondition(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	condition *services.HuntVQLCondition, client_id string) (bool, error) {
+
+	if condition == nil || condition.Query == "" {
+		return true, nil
+	}
+
+	multi_vql, err := vfilter.MultiParse(condition.Query)
+	if err != nil {
+		return false, err
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return false, err
+	}
+
+	// Run the query on behalf of the user who set it so they are
+	// subject to ACL checks.
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config: config_obj,
+		ACLManager: acl_managers.NewServerACLManager(
+			config_obj, condition.Principal),
+		Logger: logging.NewPlainLogger(config_obj, &logging.FrontendComponent),
+		Env:    ordereddict.NewDict().Set("ClientId", client_id),
+	})
+	defer scope.Close()
+
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for _, vql := range multi_vql {
+		for row := range vql.Eval(sub_ctx, scope) {
+			row_client_id, pres := vfilter.RowToDict(
+				sub_ctx, scope, row).GetString("client_id")
+			if !pres || row_client_id == client_id {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// Check the hunt's VQL condition (if any) against the client.
+func huntMatchesVQLCondition(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id, client_id string) bool {
+
+	dispatcher, err := services.GetHuntDispatcher(config_obj)
+	if err != nil {
+		return false
+	}
+
+	condition, err := dispatcher.GetHuntVQLCondition(config_obj, hunt_id)
+	if err != nil {
+		return false
+	}
+
+	matches, err := HuntMatchesVQLCondition(ctx, config_obj, condition, client_id)
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("HuntManager: evaluating VQL condition for %v: %v",
+			hunt_id, err)
+		return false
+	}
+
+	return matches
+}
+
+func huntHasVQLCondition(
+	config_obj *config_proto.Config, hunt_id string) bool {
+	dispatcher, err := services.GetHuntDispatcher(config_obj)
+	if err != nil {
+		return false
+	}
+
+	condition, err := dispatcher.GetHuntVQLCondition(config_obj, hunt_id)
+	return err == nil && condition.Query != ""
+}
@@ -0,0 +1,90 @@
+package hunt_manager_test
+
+import (
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
+)
+
+func (self *HuntTestSuite) TestHuntVQLCondition() {
+	hunt_obj := self.createRunningHunt()
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	other_client_id := self.client_id + "2"
+	err = client_info_manager.Set(self.Ctx, &services.ClientInfo{
+		actions_proto.ClientInfo{
+			ClientId: other_client_id,
+		}})
+	assert.NoError(self.T(), err)
+
+	dispatcher, err := services.GetHuntDispatcher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// Invalid VQL is rejected.
+	err = dispatcher.SetHuntVQLCondition(self.Ctx, self.ConfigObj,
+		hunt_obj.HuntId, "admin", "SELECT * FROM")
+	assert.Error(self.T(), err)
+
+	// Only select the other client.
+	err = dispatcher.SetHuntVQLCondition(self.Ctx, self.ConfigObj,
+		hunt_obj.HuntId, "admin",
+		"SELECT ClientId AS client_id FROM scope() WHERE ClientId =~ '2$'")
+	assert.NoError(self.T(), err)
+
+	condition, err := dispatcher.GetHuntVQLCondition(
+		self.ConfigObj, hunt_obj.HuntId)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "admin", condition.Principal)
+
+	err = hunt_manager.HuntManagerForTests.ProcessParticipationWithError(
+		self.Ctx, self.ConfigObj,
+		ordereddict.NewDict().
+			Set("HuntId", hunt_obj.HuntId).
+			Set("ClientId", self.client_id))
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "does not match VQL condition")
+	assert.False(self.T(), self.huntRanOnClient(self.client_id))
+
+	err = hunt_manager.HuntManagerForTests.ProcessParticipationWithError(
+		self.Ctx, self.ConfigObj,
+		ordereddict.NewDict().
+			Set("HuntId", hunt_obj.HuntId).
+			Set("ClientId", other_client_id))
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), self.huntRanOnClient(other_client_id))
+
+	// Rows for other clients do not select this client.
+	matches, err := hunt_manager.HuntMatchesVQLCondition(
+		self.Ctx, self.ConfigObj, &services.HuntVQLCondition{
+			Query:     "SELECT 'C.1' AS client_id FROM scope()",
+			Principal: "admin",
+		}, self.client_id)
+	assert.NoError(self.T(), err)
+	assert.False(self.T(), matches)
+
+	// Rows without a client id select the client being checked.
+	matches, err = hunt_manager.HuntMatchesVQLCondition(
+		self.Ctx, self.ConfigObj, &services.HuntVQLCondition{
+			Query:     "SELECT * FROM scope()",
+			Principal: "admin",
+		}, self.client_id)
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), matches)
+
+	// Removing the condition selects all clients again.
+	err = dispatcher.SetHuntVQLCondition(self.Ctx, self.ConfigObj,
+		hunt_obj.HuntId, "admin", "")
+	assert.NoError(self.T(), err)
+
+	err = hunt_manager.HuntManagerForTests.ProcessParticipationWithError(
+		self.Ctx, self.ConfigObj,
+		ordereddict.NewDict().
+			Set("HuntId", hunt_obj.HuntId).
+			Set("ClientId", self.client_id))
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), self.huntRanOnClient(self.client_id))
+}
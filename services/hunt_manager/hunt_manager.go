@@ -50,6 +50,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -64,6 +65,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/tracing"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
 	"www.velocidex.com/golang/vfilter"
@@ -94,6 +96,17 @@ type HuntManager struct {
 	// Limits how quickly we schedule hunts. Should be fast enough
 	// to be reasoable without overloading frontends
 	limiter *rate.Limiter
+
+	// Per hunt scheduling rate limits.
+	mu            sync.Mutex
+	hunt_limiters map[string]*huntLimiter
+
+	// Usage of hunts with a quota.
+	hunt_quotas map[string]*huntQuotaState
+
+	// Clients waiting for their maintenance window.
+	maintenance_queue    []*maintenanceDeferral
+	maintenance_draining bool
 }
 
 func (self *HuntManager) Start(
@@ -113,7 +126,9 @@ func (self *HuntManager) Start(
 		return err
 	}
 
-	err = journal.WatchQueueWithCB(ctx, config_obj, wg,
+	// Participation and flow completion events arrive from every
+	// client so they are processed in parallel shards.
+	err = journal.WatchQueueWithShardedCB(ctx, config_obj, wg,
 		"System.Hunt.Participation",
 		"HuntManager",
 		self.ProcessParticipation)
@@ -135,7 +150,7 @@ func (self *HuntManager) Start(
 		return err
 	}
 
-	err = journal.WatchQueueWithCB(ctx, config_obj, wg,
+	err = journal.WatchQueueWithShardedCB(ctx, config_obj, wg,
 		"System.Flow.Completion", "HuntManager",
 		self.ProcessFlowCompletion)
 	return err
@@ -163,6 +178,16 @@ func (self *HuntManager) ProcessMutation(
 		return err
 	}
 
+	// The hunt's scheduling rate was changed.
+	value, pres := row.Get("clients_per_minute")
+	if pres {
+		clients_per_minute, ok := toFloat64(value)
+		if ok {
+			self.setHuntSchedulingRate(
+				config_obj, mutation.HuntId, clients_per_minute)
+		}
+	}
+
 	return self.processMutation(ctx, config_obj, mutation)
 }
 
@@ -215,14 +240,20 @@ func (self *HuntManager) processMutation(
 			// need to propagate to all minions
 			// immediately. Eventually they will also hit the
 			// filesystem too.
-			if mutation.State == api_proto.Hunt_STOPPED ||
-				mutation.State == api_proto.Hunt_PAUSED {
+			if mutation.State == api_proto.Hunt_STOPPED {
 				hunt_obj.Stats.Stopped = true
 				hunt_obj.State = api_proto.Hunt_STOPPED
 
 				// Let all dispatchers know this hunt is stopped.
 				modification = services.HuntPropagateChanges
 
+				// A paused hunt does not schedule any new clients
+				// but may be resumed later.
+			} else if mutation.State == api_proto.Hunt_PAUSED {
+				hunt_obj.State = api_proto.Hunt_PAUSED
+
+				modification = services.HuntPropagateChanges
+
 			} else if mutation.State == api_proto.Hunt_RUNNING {
 				hunt_obj.Stats.Stopped = false
 				hunt_obj.State = api_proto.Hunt_RUNNING
@@ -329,10 +360,11 @@ func (self *HuntManager) ProcessInterrogation(
 
 	return self.participateInAllHunts(ctx, config_obj, client_id,
 		// When a new client is interrogated, it can only really
-		// affect hunts with OS conditions.
+		// affect hunts with OS or VQL conditions.
 		func(hunt *api_proto.Hunt) bool {
-			return hunt.Condition != nil &&
-				hunt.Condition.GetOs() != nil
+			return (hunt.Condition != nil &&
+				hunt.Condition.GetOs() != nil) ||
+				huntHasVQLCondition(config_obj, hunt.HuntId)
 		})
 }
 
@@ -377,6 +409,12 @@ func (self *HuntManager) ProcessFlowCompletion(
 		return nil
 	}
 
+	ctx, span := tracing.Start(ctx, "hunt_manager.ProcessFlowCompletion",
+		tracing.String("hunt_id", hunt_id),
+		tracing.String("client_id", flow.ClientId),
+		tracing.String("flow_id", flow_id))
+	defer span.End()
+
 	// Flow is complete so add it to the hunt stats. We send a
 	// mutation to the hunt dispatcher to mediate internal hunt state
 	// manipulation.
@@ -402,6 +440,11 @@ func (self *HuntManager) ProcessFlowCompletion(
 		return err
 	}
 
+	err = self.checkHuntQuota(ctx, config_obj, hunt_id, flow)
+	if err != nil {
+		return err
+	}
+
 	journal, err := services.GetJournal(config_obj)
 	if err != nil {
 		return err
@@ -493,6 +536,13 @@ func (self *HuntManager) ProcessParticipationWithError(
 	ctx context.Context,
 	config_obj *config_proto.Config,
 	row *ordereddict.Dict) error {
+	return self.processParticipation(ctx, config_obj, row, true)
+}
+
+func (self *HuntManager) processParticipation(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	row *ordereddict.Dict, check_hunt_rate bool) error {
 
 	participation_row := &ParticipationRecord{}
 	err := vfilter.ExtractArgs(self.scope, row, participation_row)
@@ -502,6 +552,13 @@ func (self *HuntManager) ProcessParticipationWithError(
 		return err
 	}
 
+	// Errors below are mostly reasons to skip the client so they do
+	// not mark the span as failed.
+	ctx, span := tracing.Start(ctx, "hunt_manager.ProcessParticipation",
+		tracing.String("hunt_id", participation_row.HuntId),
+		tracing.String("client_id", participation_row.ClientId))
+	defer span.End()
+
 	// Get some info about the client
 	client_info_manager, err := services.GetClientInfoManager(config_obj)
 	if err != nil {
@@ -543,11 +600,12 @@ func (self *HuntManager) ProcessParticipationWithError(
 			hunt_obj, participation_row.ClientId)
 	}
 
-	// Ignore stopped hunts.
+	// Ignore stopped or paused hunts.
 	if hunt_obj.Stats.Stopped ||
 		hunt_obj.State != api_proto.Hunt_RUNNING {
 		// Hunt is stopped.
-		return fmt.Errorf("Hunt %v is stopped", participation_row.HuntId)
+		return fmt.Errorf("Hunt %v is %v", participation_row.HuntId,
+			strings.ToLower(hunt_obj.State.String()))
 
 	} else if !huntMatchesOS(hunt_obj, client_info) {
 		// Hunt does not match OS condition
@@ -561,6 +619,11 @@ func (self *HuntManager) ProcessParticipationWithError(
 		participation_row.ClientId) {
 		return fmt.Errorf("Hunt %v: hunt label does not match with %v",
 			participation_row.HuntId, participation_row.ClientId)
+
+	} else if !huntMatchesVQLCondition(ctx, config_obj,
+		participation_row.HuntId, participation_row.ClientId) {
+		return fmt.Errorf("Hunt %v: %v does not match VQL condition",
+			participation_row.HuntId, participation_row.ClientId)
 	}
 
 	// Hunt limit exceeded or it expired - we stop it.
@@ -576,9 +639,22 @@ func (self *HuntManager) ProcessParticipationWithError(
 				Stats:  &api_proto.HuntStats{Stopped: true}})
 	}
 
+	// Clients outside their maintenance windows wait for the next
+	// window to open.
+	if self.deferForMaintenance(ctx, config_obj,
+		participation_row.ClientId, row) {
+		return nil
+	}
+
 	// Control rate of hunt recruitment to balance server load.
 	self.limiter.Wait(ctx)
 
+	// The hunt may limit its own rate so clients may need to wait.
+	if check_hunt_rate &&
+		self.deferParticipation(ctx, config_obj, hunt_obj.HuntId, row) {
+		return nil
+	}
+
 	// Use hunt information to launch the flow against this
 	// client.
 	return scheduleHuntOnClient(ctx,
@@ -598,6 +674,8 @@ func NewHuntManager(
 	result := &HuntManager{
 		limiter: rate.NewLimiter(rate.Limit(
 			config_obj.Frontend.Resources.NotificationsPerSecond), 1),
+		hunt_limiters: make(map[string]*huntLimiter),
+		hunt_quotas:   make(map[string]*huntQuotaState),
 		scope: manager.BuildScope(
 			services.ScopeBuilder{
 				Config: config_obj,
@@ -610,6 +688,16 @@ func NewHuntManager(
 	return result.Start(ctx, config_obj, wg)
 }
 
+// Check if the hunt's conditions allow it to be scheduled on the
+// client.
+func HuntMatchesClient(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_obj *api_proto.Hunt, client_info *services.ClientInfo) bool {
+	return huntMatchesOS(hunt_obj, client_info) &&
+		huntHasLabel(ctx, config_obj, hunt_obj, client_info.ClientId)
+}
+
 // Check if the client should be scheduled based on required labels.
 func huntHasLabel(
 	ctx context.Context,
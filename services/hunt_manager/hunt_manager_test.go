@@ -41,6 +41,11 @@ func (self *HuntTestSuite) SetupTest() {
 	self.ConfigObj.Services.HuntDispatcher = true
 	self.ConfigObj.Services.HuntManager = true
 
+	self.LoadArtifactsIntoConfig([]string{`
+name: Server.Internal.MetadataModifications
+type: SERVER_EVENT
+`})
+
 	self.TestSuite.SetupTest()
 
 	self.hunt_id += "A"
@@ -0,0 +1,431 @@
+package hunt_manager
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Clients may declare maintenance windows - the only times hunts
+// are allowed to run on them (e.g. servers only between 01:00 and
+// 05:00 local time). Windows are declared in the client's metadata:
+//
+//	maintenance_window: Mon-Fri 01:00-05:00; Sat,Sun 00:00-24:00
+//	maintenance_timezone: Europe/Berlin
+//
+// or for all clients carrying a label, in the server metadata:
+//
+//	maintenance_window:POS: 22:00-06:00 America/New_York
+//
+// A window may end with a time zone which overrides the client's
+// maintenance_timezone (default UTC). The client's own windows take
+// precedence over those of its labels.
+//
+// When a client joins a hunt outside its maintenance windows, the
+// collection is delayed until the next window opens. Start times are
+// jittered across the beginning of the window so clients sharing a
+// window do not all start at once. Like the hunt scheduling rate
+// queue, delayed clients are held in memory.
+const (
+	MAINTENANCE_WINDOW_KEY       = "maintenance_window"
+	MAINTENANCE_TIMEZONE_KEY     = "maintenance_timezone"
+	MAINTENANCE_LABEL_KEY_PREFIX = "maintenance_window:"
+
+	// Spread delayed clients over at most this long after the window
+	// opens.
+	MAX_MAINTENANCE_JITTER = time.Hour
+
+	maintenance_poll_time = 10 * time.Second
+)
+
+var (
+	time_range_regex = regexp.MustCompile(
+		`^(\d{1,2}):(\d{2})-(\d{1,2}):(\d{2})$`)
+
+	weekdays = map[string]time.Weekday{
+		"sun": time.Sunday,
+		"mon": time.Monday,
+		"tue": time.Tuesday,
+		"wed": time.Wednesday,
+		"thu": time.Thursday,
+		"fri": time.Friday,
+		"sat": time.Saturday,
+	}
+)
+
+type MaintenanceWindow struct {
+	// Days of the week the window starts on.
+	Days [7]bool
+
+	// Offsets from local midnight. If End is before Start the window
+	// wraps around midnight.
+	Start time.Duration
+	End   time.Duration
+
+	Location *time.Location
+}
+
+func (self *MaintenanceWindow) String() string {
+	days := []string{}
+	for day, ok := range self.Days {
+		if ok {
+			days = append(days, time.Weekday(day).String()[:3])
+		}
+	}
+
+	format := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+
+	return fmt.Sprintf("%v %v-%v %v", strings.Join(days, ","),
+		format(self.Start), format(self.End), self.Location)
+}
+
+func (self *MaintenanceWindow) duration() time.Duration {
+	if self.End > self.Start {
+		return self.End - self.Start
+	}
+	return 24*time.Hour - self.Start + self.End
+}
+
+// The start of the window on the same local day as t.
+func (self *MaintenanceWindow) startOnDay(t time.Time, offset int) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+offset, 0, 0, 0, 0, self.Location).Add(self.Start)
+}
+
+func (self *MaintenanceWindow) Contains(t time.Time) bool {
+	local := t.In(self.Location)
+
+	// Check the window starting today and the one starting yesterday
+	// since it may wrap around midnight.
+	for _, offset := range []int{0, -1} {
+		start := self.startOnDay(local, offset)
+		if !self.Days[start.Weekday()] {
+			continue
+		}
+
+		if !local.Before(start) && local.Before(start.Add(self.duration())) {
+			return true
+		}
+	}
+	return false
+}
+
+// The next time the window opens after t.
+func (self *MaintenanceWindow) NextStart(t time.Time) time.Time {
+	local := t.In(self.Location)
+	for offset := 0; offset <= 7; offset++ {
+		start := self.startOnDay(local, offset)
+		if self.Days[start.Weekday()] && start.After(local) {
+			return start
+		}
+	}
+
+	// Not reached since windows always have at least one day.
+	return t
+}
+
+// Parse a maintenance window specification: windows are separated by
+// ";" and consist of optional days, a time range and an optional
+// time zone.
+func ParseMaintenanceWindows(
+	spec string, location *time.Location) ([]*MaintenanceWindow, error) {
+	result := []*MaintenanceWindow{}
+	for _, item := range strings.Split(spec, ";") {
+		fields := strings.Fields(item)
+		if len(fields) == 0 {
+			continue
+		}
+
+		window := &MaintenanceWindow{Location: location}
+		idx := 0
+
+		// Optional days of the week
+		if !time_range_regex.MatchString(fields[0]) {
+			err := parseDays(fields[0], window)
+			if err != nil {
+				return nil, err
+			}
+			idx++
+		} else {
+			for day := range window.Days {
+				window.Days[day] = true
+			}
+		}
+
+		if idx >= len(fields) {
+			return nil, fmt.Errorf(
+				"Maintenance window %q: missing time range", item)
+		}
+
+		matches := time_range_regex.FindStringSubmatch(fields[idx])
+		if matches == nil {
+			return nil, fmt.Errorf(
+				"Maintenance window %q: invalid time range %q",
+				item, fields[idx])
+		}
+		idx++
+
+		var err error
+		window.Start, err = parseTimeOfDay(matches[1], matches[2])
+		if err != nil {
+			return nil, err
+		}
+
+		window.End, err = parseTimeOfDay(matches[3], matches[4])
+		if err != nil {
+			return nil, err
+		}
+
+		if window.Start == window.End {
+			return nil, fmt.Errorf(
+				"Maintenance window %q: window is empty", item)
+		}
+
+		if idx < len(fields) {
+			window.Location, err = time.LoadLocation(fields[idx])
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Maintenance window %q: %w", item, err)
+			}
+			idx++
+		}
+
+		if idx < len(fields) {
+			return nil, fmt.Errorf(
+				"Maintenance window %q: unexpected %q", item, fields[idx])
+		}
+
+		result = append(result, window)
+	}
+
+	return result, nil
+}
+
+// Days are comma separated weekdays or ranges of weekdays, e.g.
+// Mon-Fri,Sun
+func parseDays(spec string, window *MaintenanceWindow) error {
+	for _, item := range strings.Split(spec, ",") {
+		parts := strings.SplitN(item, "-", 2)
+
+		first, pres := weekdays[strings.ToLower(parts[0])]
+		if !pres {
+			return fmt.Errorf("Maintenance window: invalid day %q", parts[0])
+		}
+
+		last := first
+		if len(parts) == 2 {
+			last, pres = weekdays[strings.ToLower(parts[1])]
+			if !pres {
+				return fmt.Errorf("Maintenance window: invalid day %q",
+					parts[1])
+			}
+		}
+
+		for day := first; ; day = (day + 1) % 7 {
+			window.Days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func parseTimeOfDay(hours, minutes string) (time.Duration, error) {
+	h, _ := strconv.Atoi(hours)
+	m, _ := strconv.Atoi(minutes)
+	if m >= 60 || h > 24 || (h == 24 && m > 0) {
+		return 0, fmt.Errorf("Maintenance window: invalid time %v:%v",
+			hours, minutes)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// Get the maintenance windows that apply to the client. An empty
+// list means hunts may run at any time.
+func GetClientMaintenanceWindows(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id string) ([]*MaintenanceWindow, error) {
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := client_info_manager.GetMetadata(ctx, client_id)
+	if err != nil {
+		return nil, err
+	}
+
+	location := time.UTC
+	timezone, _ := metadata.GetString(MAINTENANCE_TIMEZONE_KEY)
+	if timezone != "" {
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("Maintenance timezone for %v: %w",
+				client_id, err)
+		}
+	}
+
+	spec, _ := metadata.GetString(MAINTENANCE_WINDOW_KEY)
+	if spec != "" {
+		return ParseMaintenanceWindows(spec, location)
+	}
+
+	server_metadata, err := client_info_manager.GetMetadata(ctx, "server")
+	if err != nil {
+		return nil, err
+	}
+
+	labeler := services.GetLabeler(config_obj)
+	result := []*MaintenanceWindow{}
+	for _, key := range server_metadata.Keys() {
+		if !strings.HasPrefix(key, MAINTENANCE_LABEL_KEY_PREFIX) {
+			continue
+		}
+
+		label := strings.TrimPrefix(key, MAINTENANCE_LABEL_KEY_PREFIX)
+		if !labeler.IsLabelSet(ctx, config_obj, client_id, label) {
+			continue
+		}
+
+		spec, _ := server_metadata.GetString(key)
+		windows, err := ParseMaintenanceWindows(spec, location)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, windows...)
+	}
+
+	return result, nil
+}
+
+func InMaintenanceWindow(windows []*MaintenanceWindow, t time.Time) bool {
+	for _, window := range windows {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find the next window to open after t. Returns the window's start
+// time and duration.
+func NextMaintenanceWindow(windows []*MaintenanceWindow, t time.Time) (
+	time.Time, time.Duration) {
+	var start time.Time
+	var duration time.Duration
+	for _, window := range windows {
+		next := window.NextStart(t)
+		if start.IsZero() || next.Before(start) {
+			start = next
+			duration = window.duration()
+		}
+	}
+	return start, duration
+}
+
+type maintenanceDeferral struct {
+	row *ordereddict.Dict
+	due time.Time
+}
+
+// Returns true if the client is outside its maintenance windows. In
+// that case the participation is queued until the next window opens.
+func (self *HuntManager) deferForMaintenance(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id string, row *ordereddict.Dict) bool {
+
+	windows, err := GetClientMaintenanceWindows(ctx, config_obj, client_id)
+	if err != nil {
+		// A broken window specification should not prevent the
+		// client from ever running hunts.
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("HuntManager: %v", err)
+		return false
+	}
+
+	now := utils.GetTime().Now()
+	if len(windows) == 0 || InMaintenanceWindow(windows, now) {
+		return false
+	}
+
+	start, duration := NextMaintenanceWindow(windows, now)
+
+	// Jitter over the first half of the window so the collection
+	// still has time to complete.
+	jitter := duration / 2
+	if jitter > MAX_MAINTENANCE_JITTER {
+		jitter = MAX_MAINTENANCE_JITTER
+	}
+	due := start
+	if jitter > 0 {
+		due = due.Add(time.Duration(rand.Int63n(int64(jitter))))
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.maintenance_queue = append(self.maintenance_queue,
+		&maintenanceDeferral{row: row, due: due})
+	if !self.maintenance_draining {
+		self.maintenance_draining = true
+		go self.drainMaintenanceQueue(ctx, config_obj)
+	}
+
+	return true
+}
+
+func (self *HuntManager) drainMaintenanceQueue(
+	ctx context.Context,
+	config_obj *config_proto.Config) {
+
+	for {
+		now := utils.GetTime().Now()
+
+		self.mu.Lock()
+		if len(self.maintenance_queue) == 0 {
+			self.maintenance_draining = false
+			self.mu.Unlock()
+			return
+		}
+
+		due := []*ordereddict.Dict{}
+		waiting := []*maintenanceDeferral{}
+		for _, item := range self.maintenance_queue {
+			if item.due.After(now) {
+				waiting = append(waiting, item)
+			} else {
+				due = append(due, item.row)
+			}
+		}
+		self.maintenance_queue = waiting
+		self.mu.Unlock()
+
+		// The client is checked again in case the hunt was stopped
+		// or the window changed in the mean time.
+		for _, row := range due {
+			_ = self.processParticipation(ctx, config_obj, row, true)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-utils.GetTime().After(maintenance_poll_time):
+		}
+	}
+}
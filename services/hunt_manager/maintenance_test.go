@@ -0,0 +1,138 @@
+package hunt_manager_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+func TestParseMaintenanceWindows(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	windows, err := hunt_manager.ParseMaintenanceWindows(
+		"Mon-Fri 01:00-05:00; Sat,Sun 22:00-06:00 Europe/Berlin", time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(windows))
+
+	// Wednesday
+	wed := func(hour, minute int) time.Time {
+		return time.Date(2023, 11, 15, hour, minute, 0, 0, time.UTC)
+	}
+
+	assert.True(t, hunt_manager.InMaintenanceWindow(windows, wed(1, 0)))
+	assert.True(t, hunt_manager.InMaintenanceWindow(windows, wed(4, 59)))
+	assert.False(t, hunt_manager.InMaintenanceWindow(windows, wed(5, 0)))
+	assert.False(t, hunt_manager.InMaintenanceWindow(windows, wed(12, 0)))
+
+	// The weekend window wraps around midnight in Berlin time so
+	// Monday 05:00 in Berlin is still inside it.
+	monday := time.Date(2023, 11, 20, 5, 0, 0, 0, berlin)
+	assert.True(t, windows[1].Contains(monday))
+	assert.False(t, windows[1].Contains(monday.Add(time.Hour)))
+
+	// The next window opens Thursday at 01:00.
+	start, duration := hunt_manager.NextMaintenanceWindow(windows, wed(12, 0))
+	assert.Equal(t, time.Date(2023, 11, 16, 1, 0, 0, 0, time.UTC), start.UTC())
+	assert.Equal(t, 4*time.Hour, duration)
+
+	// Friday afternoon waits for the weekend window.
+	start, _ = hunt_manager.NextMaintenanceWindow(windows,
+		time.Date(2023, 11, 17, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2023, 11, 18, 22, 0, 0, 0, berlin), start)
+
+	for _, spec := range []string{
+		"Funday 01:00-02:00", "01:00", "25:00-02:00", "01:00-01:00",
+		"01:00-02:00 Nowhere/Special", "01:00-02:00 UTC extra",
+	} {
+		_, err := hunt_manager.ParseMaintenanceWindows(spec, time.UTC)
+		assert.Error(t, err, spec)
+	}
+}
+
+func (self *HuntTestSuite) TestHuntMaintenanceWindow() {
+	hunt_obj := self.createRunningHunt()
+
+	// Start at noon - outside the window.
+	now := time.Now().UTC()
+	noon := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+	clock := utils.NewMockClock(noon)
+	closer := utils.MockTime(clock)
+	defer closer()
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	err = client_info_manager.SetMetadata(self.Ctx, self.client_id,
+		ordereddict.NewDict().
+			Set(hunt_manager.MAINTENANCE_WINDOW_KEY, "01:00-05:00"), "admin")
+	assert.NoError(self.T(), err)
+
+	err = hunt_manager.HuntManagerForTests.ProcessParticipationWithError(
+		self.Ctx, self.ConfigObj,
+		ordereddict.NewDict().
+			Set("HuntId", hunt_obj.HuntId).
+			Set("ClientId", self.client_id))
+	assert.NoError(self.T(), err)
+
+	// The collection is only scheduled once the window opens.
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		return self.huntRanOnClient(self.client_id)
+	})
+
+	window_start := noon.Add(13 * time.Hour)
+	assert.False(self.T(), clock.Now().Before(window_start))
+	assert.True(self.T(), clock.Now().Before(window_start.Add(2*time.Hour)))
+}
+
+func (self *HuntTestSuite) TestMaintenanceWindowFromLabel() {
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// Windows for labeled clients are declared in the server
+	// metadata.
+	err = client_info_manager.SetMetadata(self.Ctx, "server",
+		ordereddict.NewDict().
+			Set(hunt_manager.MAINTENANCE_LABEL_KEY_PREFIX+"POS",
+				"22:00-06:00"), "admin")
+	assert.NoError(self.T(), err)
+
+	windows, err := hunt_manager.GetClientMaintenanceWindows(
+		self.Ctx, self.ConfigObj, self.client_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(windows))
+
+	err = services.GetLabeler(self.ConfigObj).SetClientLabel(
+		self.Ctx, self.ConfigObj, self.client_id, "POS")
+	assert.NoError(self.T(), err)
+
+	// The client's timezone applies to the label's window.
+	err = client_info_manager.SetMetadata(self.Ctx, self.client_id,
+		ordereddict.NewDict().
+			Set(hunt_manager.MAINTENANCE_TIMEZONE_KEY, "Asia/Tokyo"), "admin")
+	assert.NoError(self.T(), err)
+
+	windows, err = hunt_manager.GetClientMaintenanceWindows(
+		self.Ctx, self.ConfigObj, self.client_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(windows))
+	assert.Equal(self.T(), "Asia/Tokyo", windows[0].Location.String())
+
+	// The client's own window takes precedence.
+	err = client_info_manager.SetMetadata(self.Ctx, self.client_id,
+		ordereddict.NewDict().
+			Set(hunt_manager.MAINTENANCE_WINDOW_KEY, "Sun 01:00-02:00"), "admin")
+	assert.NoError(self.T(), err)
+
+	windows, err = hunt_manager.GetClientMaintenanceWindows(
+		self.Ctx, self.ConfigObj, self.client_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(windows))
+	assert.Equal(self.T(), time.Hour, windows[0].Start)
+}
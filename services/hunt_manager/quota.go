@@ -0,0 +1,248 @@
+package hunt_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// The resources used by the hunt's completed collections. This is
+// maintained by the hunt manager as collections complete and
+// compared with the hunt's quota.
+type HuntQuotaUsage struct {
+	TotalCollectedRows uint64 `json:"total_collected_rows"`
+	TotalUploadedBytes uint64 `json:"total_uploaded_bytes"`
+
+	// Total execution time of all collections in seconds.
+	TotalExecutionTime float64 `json:"total_execution_time"`
+
+	// The reason the hunt was stopped if it exceeded its quota.
+	Exceeded string `json:"exceeded,omitempty"`
+}
+
+func getRawDB(config_obj *config_proto.Config) (datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, errors.New("Datastore does not support raw access")
+	}
+	return raw_db, nil
+}
+
+func GetHuntQuotaUsage(
+	config_obj *config_proto.Config, hunt_id string) (*HuntQuotaUsage, error) {
+	result := &HuntQuotaUsage{}
+
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := raw_db.GetBuffer(config_obj,
+		paths.NewHuntPathManager(hunt_id).QuotaUsage())
+	if err != nil || len(data) == 0 {
+		return result, nil
+	}
+
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func setHuntQuotaUsage(config_obj *config_proto.Config,
+	hunt_id string, usage *HuntQuotaUsage) error {
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(config_obj,
+		paths.NewHuntPathManager(hunt_id).QuotaUsage(),
+		serialized, utils.BackgroundWriter)
+}
+
+// Returns a description of the exceeded limit or an empty string.
+func quotaExceeded(quota *services.HuntQuota, usage *HuntQuotaUsage) string {
+	if quota.MaxRows > 0 && usage.TotalCollectedRows > quota.MaxRows {
+		return fmt.Sprintf("Hunt exceeded row quota (%v > %v)",
+			usage.TotalCollectedRows, quota.MaxRows)
+	}
+
+	if quota.MaxUploadBytes > 0 &&
+		usage.TotalUploadedBytes > quota.MaxUploadBytes {
+		return fmt.Sprintf("Hunt exceeded upload quota (%v > %v bytes)",
+			usage.TotalUploadedBytes, quota.MaxUploadBytes)
+	}
+
+	if quota.MaxExecutionTime > 0 &&
+		usage.TotalExecutionTime > float64(quota.MaxExecutionTime) {
+		return fmt.Sprintf("Hunt exceeded execution time quota (%.0f > %v seconds)",
+			usage.TotalExecutionTime, quota.MaxExecutionTime)
+	}
+
+	return ""
+}
+
+func quotaIsEmpty(quota *services.HuntQuota) bool {
+	return quota == nil ||
+		(quota.MaxRows == 0 && quota.MaxUploadBytes == 0 &&
+			quota.MaxExecutionTime == 0)
+}
+
+// Collections of a hunt complete concurrently so each hunt with a
+// quota keeps its usage in memory under its own lock. The usage is
+// loaded from the datastore on first use and written back after each
+// update.
+type huntQuotaState struct {
+	mu    sync.Mutex
+	usage *HuntQuotaUsage
+}
+
+func (self *HuntManager) getHuntQuotaState(hunt_id string) *huntQuotaState {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result, pres := self.hunt_quotas[hunt_id]
+	if !pres {
+		result = &huntQuotaState{}
+		self.hunt_quotas[hunt_id] = result
+	}
+	return result
+}
+
+// Account for the completed collection in the hunt's usage and stop
+// the hunt if it exceeded its quota. Usage is only tracked while the
+// hunt has a quota.
+func (self *HuntManager) checkHuntQuota(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string, flow *flows_proto.ArtifactCollectorContext) error {
+
+	dispatcher, err := services.GetHuntDispatcher(config_obj)
+	if err != nil {
+		return err
+	}
+
+	quota, err := dispatcher.GetHuntQuota(config_obj, hunt_id)
+	if err != nil {
+		return err
+	}
+
+	if quotaIsEmpty(quota) {
+		return nil
+	}
+
+	reason, err := self.updateHuntQuotaUsage(config_obj, hunt_id, quota, flow)
+	if err != nil || reason == "" {
+		return err
+	}
+
+	hunt_obj, pres := dispatcher.GetHunt(hunt_id)
+	if !pres || hunt_obj.State != api_proto.Hunt_RUNNING {
+		return nil
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("HuntManager: Stopping hunt %v: %v", hunt_id, reason)
+
+	err = self.processMutation(ctx, config_obj, &api_proto.HuntMutation{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_STOPPED,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Cancel the collections still in flight in the background.
+	go self.cancelHuntFlows(ctx, config_obj, hunt_id)
+
+	return nil
+}
+
+// Add the collection to the hunt's usage. Returns the reason if the
+// hunt is now over its quota.
+func (self *HuntManager) updateHuntQuotaUsage(
+	config_obj *config_proto.Config,
+	hunt_id string, quota *services.HuntQuota,
+	flow *flows_proto.ArtifactCollectorContext) (string, error) {
+
+	state := self.getHuntQuotaState(hunt_id)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.usage == nil {
+		usage, err := GetHuntQuotaUsage(config_obj, hunt_id)
+		if err != nil {
+			return "", err
+		}
+		state.usage = usage
+	}
+
+	usage := state.usage
+	usage.TotalCollectedRows += flow.TotalCollectedRows
+	usage.TotalUploadedBytes += flow.TotalUploadedBytes
+	usage.TotalExecutionTime += time.Duration(
+		flow.ExecutionDuration).Seconds()
+
+	// Clears the reason if the quota was raised since the hunt was
+	// stopped.
+	reason := quotaExceeded(quota, usage)
+	usage.Exceeded = reason
+
+	return reason, setHuntQuotaUsage(config_obj, hunt_id, usage)
+}
+
+func (self *HuntManager) cancelHuntFlows(
+	ctx context.Context,
+	config_obj *config_proto.Config, hunt_id string) {
+
+	dispatcher, err := services.GetHuntDispatcher(config_obj)
+	if err != nil {
+		return
+	}
+
+	launcher, err := services.GetLauncher(config_obj)
+	if err != nil {
+		return
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	for flow := range dispatcher.GetFlows(ctx, config_obj, self.scope, hunt_id, 0) {
+		if flow.Context == nil ||
+			flow.Context.State != flows_proto.ArtifactCollectorContext_RUNNING {
+			continue
+		}
+
+		_, err := launcher.CancelFlow(ctx, config_obj,
+			flow.Context.ClientId, flow.Context.SessionId, "hunt quota")
+		if err != nil {
+			logger.Error("HuntManager: cancelling %v on %v: %v",
+				flow.Context.SessionId, flow.Context.ClientId, err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package hunt_manager_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+func (self *HuntTestSuite) TestHuntQuota() {
+	hunt_obj := self.createRunningHunt()
+
+	dispatcher, err := services.GetHuntDispatcher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	err = dispatcher.SetHuntQuota(self.Ctx, self.ConfigObj, hunt_obj.HuntId,
+		&services.HuntQuota{MaxRows: 15})
+	assert.NoError(self.T(), err)
+
+	flow_id := utils.CreateFlowIdFromHuntId(hunt_obj.HuntId)
+	complete := func(rows uint64) {
+		err := hunt_manager.HuntManagerForTests.ProcessFlowCompletion(
+			self.Ctx, self.ConfigObj, ordereddict.NewDict().
+				Set("FlowId", flow_id).
+				Set("Flow", &flows_proto.ArtifactCollectorContext{
+					ClientId:           self.client_id,
+					SessionId:          flow_id,
+					State:              flows_proto.ArtifactCollectorContext_FINISHED,
+					TotalCollectedRows: rows,
+				}))
+		assert.NoError(self.T(), err)
+	}
+
+	// The first collection is within the quota.
+	complete(10)
+
+	usage, err := hunt_manager.GetHuntQuotaUsage(self.ConfigObj, hunt_obj.HuntId)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(10), usage.TotalCollectedRows)
+	assert.Equal(self.T(), "", usage.Exceeded)
+
+	h, _ := dispatcher.GetHunt(hunt_obj.HuntId)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, h.State)
+
+	// The second collection takes the hunt over its quota.
+	complete(10)
+
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		h, _ := dispatcher.GetHunt(hunt_obj.HuntId)
+		return h.State == api_proto.Hunt_STOPPED
+	})
+
+	usage, err = hunt_manager.GetHuntQuotaUsage(self.ConfigObj, hunt_obj.HuntId)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(20), usage.TotalCollectedRows)
+	assert.Contains(self.T(), usage.Exceeded, "row quota")
+}
+
+func (self *HuntTestSuite) TestHuntQuotaConcurrentCompletions() {
+	hunt_obj := self.createRunningHunt()
+
+	dispatcher, err := services.GetHuntDispatcher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	flow_id := utils.CreateFlowIdFromHuntId(hunt_obj.HuntId)
+	complete := func(rows uint64) {
+		err := hunt_manager.HuntManagerForTests.ProcessFlowCompletion(
+			self.Ctx, self.ConfigObj, ordereddict.NewDict().
+				Set("FlowId", flow_id).
+				Set("Flow", &flows_proto.ArtifactCollectorContext{
+					ClientId:           self.client_id,
+					SessionId:          flow_id,
+					State:              flows_proto.ArtifactCollectorContext_FINISHED,
+					TotalCollectedRows: rows,
+				}))
+		assert.NoError(self.T(), err)
+	}
+
+	// Without a quota no usage is tracked.
+	complete(10)
+
+	usage, err := hunt_manager.GetHuntQuotaUsage(self.ConfigObj, hunt_obj.HuntId)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(0), usage.TotalCollectedRows)
+
+	err = dispatcher.SetHuntQuota(self.Ctx, self.ConfigObj, hunt_obj.HuntId,
+		&services.HuntQuota{MaxRows: 1000})
+	assert.NoError(self.T(), err)
+
+	// No completion is lost when they race.
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			complete(10)
+		}()
+	}
+	wg.Wait()
+
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		usage, err := hunt_manager.GetHuntQuotaUsage(
+			self.ConfigObj, hunt_obj.HuntId)
+		return err == nil && usage.TotalCollectedRows == 500
+	})
+
+	h, _ := dispatcher.GetHunt(hunt_obj.HuntId)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, h.State)
+}
@@ -0,0 +1,154 @@
+package hunt_manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"golang.org/x/time/rate"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Hunts may limit the rate at which new clients are scheduled (in
+// clients per minute). This avoids saturating the server when a hunt
+// collects a lot of data.
+//
+// Clients which join the hunt while the limit is reached are queued
+// and scheduled as the limit allows. The queue is held in memory so
+// if the master restarts, queued clients will only be scheduled when
+// the hunt is restarted.
+type huntLimiter struct {
+	limiter *rate.Limiter
+
+	// Participation rows waiting for the limiter.
+	pending  []*ordereddict.Dict
+	draining bool
+}
+
+func newLimit(clients_per_minute float64) rate.Limit {
+	if clients_per_minute <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(clients_per_minute / 60)
+}
+
+func (self *HuntManager) getHuntLimiter(
+	config_obj *config_proto.Config, hunt_id string) *huntLimiter {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result, pres := self.hunt_limiters[hunt_id]
+	if pres {
+		return result
+	}
+
+	clients_per_minute := float64(0)
+	dispatcher, err := services.GetHuntDispatcher(config_obj)
+	if err == nil {
+		clients_per_minute, err = dispatcher.GetHuntSchedulingRate(
+			config_obj, hunt_id)
+		if err != nil {
+			logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+			logger.Error("HuntManager: reading scheduling rate for %v: %v",
+				hunt_id, err)
+		}
+	}
+
+	result = &huntLimiter{
+		limiter: rate.NewLimiter(newLimit(clients_per_minute), 1),
+	}
+	self.hunt_limiters[hunt_id] = result
+
+	return result
+}
+
+// Apply a new scheduling rate to the hunt. Queued clients are
+// scheduled at the new rate.
+func (self *HuntManager) setHuntSchedulingRate(
+	config_obj *config_proto.Config,
+	hunt_id string, clients_per_minute float64) {
+	hunt_limiter := self.getHuntLimiter(config_obj, hunt_id)
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	hunt_limiter.limiter.SetLimit(newLimit(clients_per_minute))
+}
+
+// Returns true if the participation must wait for the hunt's rate
+// limit. In that case the row is queued and will be processed later.
+func (self *HuntManager) deferParticipation(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string, row *ordereddict.Dict) bool {
+	hunt_limiter := self.getHuntLimiter(config_obj, hunt_id)
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	// Do not jump ahead of already queued clients.
+	if len(hunt_limiter.pending) == 0 && hunt_limiter.limiter.Allow() {
+		return false
+	}
+
+	hunt_limiter.pending = append(hunt_limiter.pending, row)
+	if !hunt_limiter.draining {
+		hunt_limiter.draining = true
+		go self.drainPending(ctx, config_obj, hunt_limiter)
+	}
+
+	return true
+}
+
+func (self *HuntManager) drainPending(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_limiter *huntLimiter) {
+
+	for {
+		self.mu.Lock()
+		if len(hunt_limiter.pending) == 0 {
+			hunt_limiter.draining = false
+			self.mu.Unlock()
+			return
+		}
+
+		allowed := hunt_limiter.limiter.Allow()
+		var row *ordereddict.Dict
+		if allowed {
+			row = hunt_limiter.pending[0]
+			hunt_limiter.pending = hunt_limiter.pending[1:]
+		}
+		self.mu.Unlock()
+
+		// Poll the limiter rather than wait on it so a change in
+		// rate takes effect quickly.
+		if !allowed {
+			select {
+			case <-ctx.Done():
+				return
+			case <-utils.GetTime().After(time.Second):
+			}
+			continue
+		}
+
+		// The hunt may have been paused or stopped while the client
+		// was queued - this is checked again here.
+		_ = self.processParticipation(ctx, config_obj, row, false)
+	}
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch t := value.(type) {
+	case float64:
+		return t, true
+	case float32:
+		return float64(t), true
+	}
+
+	result, ok := utils.ToInt64(value)
+	return float64(result), ok
+}
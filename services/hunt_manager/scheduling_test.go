@@ -0,0 +1,150 @@
+package hunt_manager_test
+
+import (
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+func (self *HuntTestSuite) createRunningHunt() *api_proto.Hunt {
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_RUNNING,
+		Stats:        &api_proto.HuntStats{},
+		Expires:      uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
+	}
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.ConfigObj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(self.T(), err)
+
+	dispatcher, err := services.GetHuntDispatcher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	dispatcher.Refresh(self.Ctx, self.ConfigObj)
+
+	return hunt_obj
+}
+
+func (self *HuntTestSuite) huntRanOnClient(client_id string) bool {
+	indexer, err := services.GetIndexer(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	return indexer.CheckSimpleIndex(self.ConfigObj, paths.HUNT_INDEX,
+		client_id, []string{self.hunt_id}) == nil
+}
+
+func (self *HuntTestSuite) TestHuntPauseAndResume() {
+	hunt_obj := self.createRunningHunt()
+
+	dispatcher, err := services.GetHuntDispatcher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// Pause the hunt.
+	err = dispatcher.ModifyHunt(self.Ctx, self.ConfigObj, &api_proto.Hunt{
+		HuntId: hunt_obj.HuntId,
+		State:  api_proto.Hunt_PAUSED,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		h, _ := dispatcher.GetHunt(hunt_obj.HuntId)
+		return h.State == api_proto.Hunt_PAUSED
+	})
+
+	// A paused hunt is not stopped.
+	h, _ := dispatcher.GetHunt(hunt_obj.HuntId)
+	assert.False(self.T(), h.Stats.Stopped)
+
+	// Paused hunts do not schedule new clients.
+	err = hunt_manager.HuntManagerForTests.ProcessParticipationWithError(
+		self.Ctx, self.ConfigObj,
+		ordereddict.NewDict().
+			Set("HuntId", hunt_obj.HuntId).
+			Set("ClientId", self.client_id))
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "is paused")
+	assert.False(self.T(), self.huntRanOnClient(self.client_id))
+
+	// Resume the hunt.
+	err = dispatcher.ModifyHunt(self.Ctx, self.ConfigObj, &api_proto.Hunt{
+		HuntId: hunt_obj.HuntId,
+		State:  api_proto.Hunt_RUNNING,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		h, _ := dispatcher.GetHunt(hunt_obj.HuntId)
+		return h.State == api_proto.Hunt_RUNNING
+	})
+
+	err = hunt_manager.HuntManagerForTests.ProcessParticipationWithError(
+		self.Ctx, self.ConfigObj,
+		ordereddict.NewDict().
+			Set("HuntId", hunt_obj.HuntId).
+			Set("ClientId", self.client_id))
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), self.huntRanOnClient(self.client_id))
+}
+
+func (self *HuntTestSuite) TestHuntSchedulingRate() {
+	hunt_obj := self.createRunningHunt()
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	other_client_id := self.client_id + "2"
+	err = client_info_manager.Set(self.Ctx, &services.ClientInfo{
+		actions_proto.ClientInfo{
+			ClientId: other_client_id,
+		}})
+	assert.NoError(self.T(), err)
+
+	dispatcher, err := services.GetHuntDispatcher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// Only allow one client per minute.
+	err = dispatcher.SetHuntSchedulingRate(
+		self.Ctx, self.ConfigObj, hunt_obj.HuntId, 1)
+	assert.NoError(self.T(), err)
+
+	rate, err := dispatcher.GetHuntSchedulingRate(self.ConfigObj, hunt_obj.HuntId)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), float64(1), rate)
+
+	// The first client is scheduled right away.
+	for _, client_id := range []string{self.client_id, other_client_id} {
+		err = hunt_manager.HuntManagerForTests.ProcessParticipationWithError(
+			self.Ctx, self.ConfigObj,
+			ordereddict.NewDict().
+				Set("HuntId", hunt_obj.HuntId).
+				Set("ClientId", client_id))
+		assert.NoError(self.T(), err)
+	}
+
+	assert.True(self.T(), self.huntRanOnClient(self.client_id))
+
+	// The second client has to wait.
+	time.Sleep(time.Second)
+	assert.False(self.T(), self.huntRanOnClient(other_client_id))
+
+	// Removing the limit schedules the waiting client.
+	err = dispatcher.SetHuntSchedulingRate(
+		self.Ctx, self.ConfigObj, hunt_obj.HuntId, 0)
+	assert.NoError(self.T(), err)
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		return self.huntRanOnClient(other_client_id)
+	})
+}
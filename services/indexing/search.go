@@ -350,22 +350,18 @@ func (self *Indexer) searchUnlabeledClients(
 		return nil, err
 	}
 
-	labeler := services.GetLabeler(config_obj)
-	for client_id := range client_info_manager.ListClients(ctx) {
-		if len(labeler.GetClientLabels(ctx, config_obj, client_id)) > 0 {
+	snapshot := client_info_manager.Snapshot(ctx)
+	for _, client_id := range snapshot.Keys() {
+		client_info, pres := snapshot.Get(client_id)
+		if !pres || len(client_info.Labels) > 0 {
 			continue
 		}
 
 		// Skip clients that are offline
 		if in.Filter == api_proto.SearchClientsRequest_ONLINE {
-			stats, err := client_info_manager.GetStats(ctx, client_id)
-			if err != nil {
-				continue
-			}
-
 			// SKip clients that are too old
-			if now > stats.Ping &&
-				now-stats.Ping > 1000000*60*15 {
+			if now > client_info.Ping &&
+				now-client_info.Ping > 1000000*60*15 {
 				continue
 			}
 		}
@@ -0,0 +1,180 @@
+package interrogation
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// The skew is measured against the time the server processes the
+	// interrogation so it includes network and processing delays.
+	// Smaller skews are not worth correcting.
+	CLOCK_SKEW_TOLERANCE = 5 * time.Second
+)
+
+var (
+	clockNotFoundError = errors.New("Client clock not known")
+)
+
+// Clients report their time zone and current time when they are
+// interrogated. Comparing the reported time with the server's clock
+// gives the client's clock skew so timestamps collected from
+// endpoints with broken clocks can be corrected.
+type ClientClock struct {
+	ClientId string `json:"client_id"`
+
+	// The time zone name as reported by the client. This is an IANA
+	// name (e.g. Europe/Berlin) where the client can determine it,
+	// otherwise an abbreviation (e.g. CET).
+	Timezone string `json:"timezone"`
+
+	// Seconds east of UTC at the time of interrogation.
+	UTCOffset int64 `json:"utc_offset"`
+
+	ClientTime time.Time `json:"client_time"`
+	ServerTime time.Time `json:"server_time"`
+
+	// Seconds the client's clock is ahead of the server's.
+	Skew float64 `json:"skew"`
+
+	// The interrogation flow the measurement was taken from.
+	FlowId string `json:"flow_id"`
+}
+
+func (self *ClientClock) SkewDuration() time.Duration {
+	return time.Duration(self.Skew * float64(time.Second))
+}
+
+// The client's location. Falls back to a fixed offset zone when the
+// reported name is not a known IANA zone.
+func (self *ClientClock) Location() *time.Location {
+	if self.Timezone != "" {
+		loc, err := time.LoadLocation(self.Timezone)
+		if err == nil {
+			return loc
+		}
+	}
+	return time.FixedZone(self.Timezone, int(self.UTCOffset))
+}
+
+// Correct a timestamp taken from the client's clock for the client's
+// skew.
+func (self *ClientClock) Normalize(t time.Time) time.Time {
+	skew := self.SkewDuration()
+	if skew > -CLOCK_SKEW_TOLERANCE && skew < CLOCK_SKEW_TOLERANCE {
+		return t.UTC()
+	}
+	return t.Add(-skew).UTC()
+}
+
+// Interpret a time of day written by the client without zone
+// information (e.g. in a text log) in the client's time zone. The
+// fields of t are taken as is and its location is ignored.
+func (self *ClientClock) FromLocal(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), self.Location())
+}
+
+func getRawDB(
+	config_obj *config_proto.Config) (datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, errors.New("Datastore does not support raw access")
+	}
+	return raw_db, nil
+}
+
+func GetClientClock(
+	config_obj *config_proto.Config, client_id string) (*ClientClock, error) {
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := raw_db.GetBuffer(config_obj,
+		paths.NewClientPathManager(client_id).Clock())
+	if err != nil {
+		return nil, clockNotFoundError
+	}
+
+	result := &ClientClock{}
+	err = json.Unmarshal(data, result)
+	if err != nil || result.ClientId != client_id {
+		return nil, clockNotFoundError
+	}
+	return result, nil
+}
+
+// Record the client's clock from the interrogation row. Older
+// clients do not report their clock so there is nothing to record.
+func updateClientClock(
+	config_obj *config_proto.Config,
+	client_id, flow_id string, row *ordereddict.Dict) error {
+
+	client_time, ok := getTime(row, "ClientTime")
+	if !ok {
+		return nil
+	}
+
+	server_time := utils.GetTime().Now().UTC()
+	skew := client_time.Sub(server_time).Seconds()
+
+	timezone, _ := row.GetString("Timezone")
+	offset, _ := row.Get("TimezoneOffset")
+	utc_offset, _ := utils.ToInt64(offset)
+
+	clock := &ClientClock{
+		ClientId:   client_id,
+		Timezone:   timezone,
+		UTCOffset:  utc_offset,
+		ClientTime: client_time.UTC(),
+		ServerTime: server_time,
+		Skew:       math.Round(skew*1000) / 1000,
+		FlowId:     flow_id,
+	}
+
+	serialized, err := json.Marshal(clock)
+	if err != nil {
+		return err
+	}
+
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(config_obj,
+		paths.NewClientPathManager(client_id).Clock(),
+		serialized, utils.BackgroundWriter)
+}
+
+// Timestamps in result sets are serialized as strings.
+func getTime(row *ordereddict.Dict, field string) (time.Time, bool) {
+	value, pres := row.Get(field)
+	if !pres {
+		return time.Time{}, false
+	}
+
+	switch t := value.(type) {
+	case time.Time:
+		return t, !t.IsZero()
+
+	case string:
+		result, err := time.Parse(time.RFC3339Nano, t)
+		return result, err == nil
+	}
+	return time.Time{}, false
+}
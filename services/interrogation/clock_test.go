@@ -0,0 +1,54 @@
+package interrogation_test
+
+import (
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"www.velocidex.com/golang/velociraptor/services/interrogation"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+func (self *ServicesTestSuite) TestClientClock() {
+	now := time.Unix(1700000000, 0).UTC()
+	closer := utils.MockTime(utils.NewMockClock(now))
+	defer closer()
+
+	// The client's clock is 10 minutes fast.
+	client_time := now.Add(10 * time.Minute)
+	self.EmulateCollection(
+		"Generic.Client.Info/BasicInformation", []*ordereddict.Dict{
+			ordereddict.NewDict().
+				Set("Name", "velociraptor").
+				Set("OS", "windows").
+				Set("Hostname", "ClockHost").
+				Set("Timezone", "Australia/Brisbane").
+				Set("TimezoneOffset", 10*3600).
+				Set("ClientTime", client_time),
+		})
+
+	var clock *interrogation.ClientClock
+	vtesting.WaitUntil(2*time.Second, self.T(), func() bool {
+		var err error
+		clock, err = interrogation.GetClientClock(self.ConfigObj, self.client_id)
+		return err == nil
+	})
+
+	assert.Equal(self.T(), "Australia/Brisbane", clock.Timezone)
+	assert.Equal(self.T(), int64(36000), clock.UTCOffset)
+	assert.Equal(self.T(), float64(600), clock.Skew)
+
+	// Timestamps from the client are corrected for the skew.
+	assert.Equal(self.T(), now, clock.Normalize(client_time))
+
+	// Local timestamps are interpreted in the client's time zone.
+	local := time.Date(2023, 11, 15, 10, 0, 0, 0, time.UTC)
+	assert.Equal(self.T(),
+		time.Date(2023, 11, 14, 23, 50, 0, 0, time.UTC),
+		clock.Normalize(clock.FromLocal(local)))
+
+	// Small skews are measurement noise.
+	clock.Skew = 2
+	assert.Equal(self.T(), now, clock.Normalize(now))
+}
@@ -27,6 +27,7 @@ package interrogation
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync"
 	"time"
 
@@ -358,6 +359,13 @@ func (self *EnrollmentService) ProcessInterrogateResults(
 			return err
 		}
 
+		err = updateClientClock(config_obj, client_id, flow_id, row)
+		if err != nil {
+			logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+			logger.Error("Unable to record client clock for %v: %v",
+				client_id, err)
+		}
+
 		// Needs to be outside mutation because it calls the labeler.
 		label_array, ok := row.GetStrings("Labels")
 		if ok {
@@ -372,6 +380,15 @@ func (self *EnrollmentService) ProcessInterrogateResults(
 		break
 	}
 
+	// Cloud hosts are also labeled with their provider and
+	// region. Clients that are not in a cloud have no CloudInfo
+	// rows.
+	err = applyCloudLabels(ctx, config_obj, client_id, flow_id,
+		strings.TrimSuffix(artifact, "/BasicInformation")+"/CloudInfo")
+	if err != nil {
+		return err
+	}
+
 	journal, err := services.GetJournal(config_obj)
 	if err != nil {
 		return err
@@ -385,6 +402,41 @@ func (self *EnrollmentService) ProcessInterrogateResults(
 	return nil
 }
 
+func applyCloudLabels(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id, flow_id, artifact string) error {
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	path_manager, err := artifacts.NewArtifactPathManager(ctx, config_obj,
+		client_id, flow_id, artifact)
+	if err != nil {
+		return err
+	}
+
+	rs_reader, err := result_sets.NewResultSetReader(
+		file_store_factory, path_manager.Path())
+	if err != nil {
+		// The source may not exist in customized artifacts.
+		return nil
+	}
+	defer rs_reader.Close()
+
+	labeler := services.GetLabeler(config_obj)
+	for row := range rs_reader.Rows(ctx) {
+		label_array, _ := row.GetStrings("Labels")
+		for _, label := range label_array {
+			err := labeler.SetClientLabel(ctx, config_obj, client_id, label)
+			if err != nil {
+				return err
+			}
+		}
+		break
+	}
+
+	return nil
+}
+
 func NewInterrogationService(
 	ctx context.Context,
 	wg *sync.WaitGroup,
@@ -115,6 +115,38 @@ func (self *ServicesTestSuite) TestInterrogationService() {
 	assert.NoError(self.T(), err)
 }
 
+func (self *ServicesTestSuite) TestInterrogationCloudLabels() {
+	journal, err := services.GetJournal(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// The CloudInfo source is written in the same flow.
+	err = journal.PushRowsToArtifact(self.Ctx, self.ConfigObj,
+		[]*ordereddict.Dict{
+			ordereddict.NewDict().
+				Set("Provider", "aws").
+				Set("Region", "us-east-1").
+				Set("Labels", []string{"aws", "aws-us-east-1"}),
+		}, "Generic.Client.Info/CloudInfo", self.client_id, self.flow_id)
+	assert.NoError(self.T(), err)
+
+	self.EmulateCollection(
+		"Generic.Client.Info/BasicInformation", []*ordereddict.Dict{
+			ordereddict.NewDict().
+				Set("Name", "velociraptor").
+				Set("OS", "linux").
+				Set("Hostname", "CloudHost"),
+		})
+
+	labeler := services.GetLabeler(self.ConfigObj)
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		return labeler.IsLabelSet(
+			context.Background(), self.ConfigObj, self.client_id, "aws") &&
+			labeler.IsLabelSet(
+				context.Background(), self.ConfigObj, self.client_id,
+				"aws-us-east-1")
+	})
+}
+
 func (self *ServicesTestSuite) TestEnrollService() {
 	enroll_message := ordereddict.NewDict().Set("ClientId", self.client_id)
 
@@ -93,4 +93,22 @@ tools:
 
 The above artifact will receive tool v2 when it is compiled.
 
+### Mirroring tools for offline deployments
+
+Normally tools are downloaded from their upstream URL the first time
+they are needed. For air-gapped deployments, the `velociraptor tools
+mirror` command (MirrorTools()) downloads every tool declared by the
+artifacts in the repository ahead of time into the server's public
+directory and switches them to be served locally. Clients then only
+ever fetch tools from the frontend (which supports range requests).
+
+Mirrored tools are pinned: their expected_hash is set to the hash of
+the mirrored file, so any later re-download must produce the same
+file. If a hash was already known before mirroring, the mirrored file
+must match it. Running the command again verifies the files already
+in the public directory.
+
+Once mirrored, set `defaults.disable_inventory_service_external_access`
+to prevent the server from ever contacting external URLs.
+
 */
@@ -776,6 +776,94 @@ tools:
 		"http://www.example.com/SampleTool2.exe")
 }
 
+// Mirroring downloads all tools into the public directory so
+// clients never need to reach the upstream URLs.
+func (self *ServicesTestSuite) TestMirrorTools() {
+	ctx := context.Background()
+
+	test_artifact := `
+name: TestArtifact
+tools:
+- name: ToolA
+  url: http://www.example.com/ToolA.exe
+- name: ToolB
+  url: http://www.example.com/ToolB.exe
+`
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	repository := manager.NewRepository()
+	_, err = repository.LoadYaml(test_artifact,
+		services.ArtifactOptions{
+			ValidateArtifact:  true,
+			ArtifactIsBuiltIn: true})
+	assert.NoError(self.T(), err)
+
+	self.mock = &MockClient{
+		responses: map[string]string{
+			"http://www.example.com/ToolA.exe": "Content A",
+			"http://www.example.com/ToolB.exe": "Content B",
+		},
+	}
+
+	inventory_service, err := services.GetInventory(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	inventory_service.(*inventory.InventoryService).Client = self.mock
+
+	// ToolB was previously used so its hash is known.
+	err = inventory_service.AddTool(ctx, self.ConfigObj,
+		&artifacts_proto.Tool{
+			Name: "ToolB",
+			Url:  "http://www.example.com/ToolB.exe",
+		}, services.ToolOptions{ArtifactDefinition: true})
+	assert.NoError(self.T(), err)
+
+	tool, err := inventory_service.GetToolInfo(ctx, self.ConfigObj, "ToolB", "")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), getHash("Content B"), tool.Hash)
+
+	// The upstream file changes - mirroring must detect it.
+	self.mock.responses["http://www.example.com/ToolB.exe"] = "Tampered"
+
+	results, err := inventory.MirrorTools(ctx, self.ConfigObj, repository)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(results))
+
+	statuses := make(map[string]*inventory.MirrorResult)
+	for _, result := range results {
+		statuses[result.Name] = result
+	}
+
+	assert.Equal(self.T(), "Downloaded", statuses["ToolA"].Status)
+	assert.Equal(self.T(), getHash("Content A"), statuses["ToolA"].Hash)
+	assert.Contains(self.T(), statuses["ToolA"].ServeUrl,
+		"https://localhost:8000/public/")
+
+	assert.Equal(self.T(), "Error", statuses["ToolB"].Status)
+	assert.Contains(self.T(), statuses["ToolB"].Error, "does not match")
+
+	// Mirroring again does not download ToolA again.
+	count := self.mock.count
+	results, err = inventory.MirrorTools(ctx, self.ConfigObj, repository)
+	assert.NoError(self.T(), err)
+
+	for _, result := range results {
+		if result.Name == "ToolA" {
+			assert.Equal(self.T(), "Mirrored", result.Status)
+		}
+	}
+
+	// Only ToolB was retried.
+	assert.Equal(self.T(), count+1, self.mock.count)
+
+	// The mirrored copy is served from the public directory.
+	tool, err = inventory_service.GetToolInfo(ctx, self.ConfigObj, "ToolA", "")
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), tool.ServeLocally)
+	assert.Equal(self.T(), getHash("Content A"), tool.ExpectedHash)
+	assert.NoError(self.T(), inventory.VerifyTool(self.ConfigObj, tool))
+}
+
 func getHash(data string) string {
 	sha_sum := sha256.New()
 	sha_sum.Write([]byte(data))
@@ -0,0 +1,190 @@
+package inventory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// The outcome of mirroring a single tool.
+type MirrorResult struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+	ServeUrl string `json:"serve_url,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Mirror all tools declared by artifacts in the repository into the
+// server's public directory. After mirroring, clients only ever fetch
+// tools from the server so the deployment can operate without
+// external network access (see
+// Defaults.disable_inventory_service_external_access).
+//
+// Each mirrored tool is pinned to its hash: If the hash was already
+// known (e.g. from a previous download) the freshly mirrored file must
+// match it. Mirrored tools are marked as admin overrides so updated
+// artifact definitions do not replace them.
+func MirrorTools(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	repository services.Repository) ([]*MirrorResult, error) {
+
+	inventory, err := services.GetInventory(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure all tools declared in artifacts are known to the
+	// inventory, even if the artifact was never compiled.
+	names, err := repository.List(ctx, config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		artifact, pres := repository.Get(ctx, config_obj, name)
+		if !pres {
+			continue
+		}
+
+		for _, tool := range artifact.Tools {
+			tool_request := proto.Clone(tool).(*artifacts_proto.Tool)
+			tool_request.Artifact = artifact.Name
+			err := inventory.AddTool(ctx, config_obj, tool_request,
+				services.ToolOptions{
+					Upgrade:            true,
+					ArtifactDefinition: true,
+				})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := []*MirrorResult{}
+	for _, tool := range inventory.Get().Tools {
+		result = append(result, mirrorTool(ctx, config_obj, inventory, tool))
+	}
+
+	return result, nil
+}
+
+func mirrorTool(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	inventory services.Inventory,
+	tool *artifacts_proto.Tool) *MirrorResult {
+	result := &MirrorResult{
+		Name:    tool.Name,
+		Version: tool.Version,
+	}
+
+	// Tools served from the server's local filesystem do not need
+	// mirroring.
+	if tool.ServePath != "" {
+		result.Status = "Local"
+		result.Hash = tool.Hash
+		return result
+	}
+
+	// Already in the public directory - just make sure it is still
+	// intact.
+	if tool.ServeLocally && tool.Hash != "" {
+		result.Hash = tool.Hash
+		result.ServeUrl = tool.ServeUrl
+		result.Status = "Mirrored"
+
+		err := VerifyTool(config_obj, tool)
+		if err != nil {
+			result.Status = "Error"
+			result.Error = err.Error()
+		}
+		return result
+	}
+
+	tool_request := proto.Clone(tool).(*artifacts_proto.Tool)
+	tool_request.ServeLocally = true
+
+	// Pin the hash we already know about so a changed upstream file
+	// is detected.
+	if tool_request.ExpectedHash == "" {
+		tool_request.ExpectedHash = tool_request.Hash
+	}
+	tool_request.Hash = ""
+
+	err := inventory.AddTool(ctx, config_obj, tool_request,
+		services.ToolOptions{AdminOverride: true})
+	if err != nil {
+		result.Status = "Error"
+		result.Error = err.Error()
+		return result
+	}
+
+	// Force the download into the public directory.
+	materialized, err := inventory.GetToolInfo(
+		ctx, config_obj, tool.Name, tool.Version)
+	if err != nil {
+		result.Status = "Error"
+		result.Error = err.Error()
+		return result
+	}
+
+	// Pin newly downloaded tools to the hash we just calculated.
+	if materialized.ExpectedHash == "" {
+		pinned := proto.Clone(materialized).(*artifacts_proto.Tool)
+		pinned.ExpectedHash = pinned.Hash
+		err = inventory.AddTool(ctx, config_obj, pinned,
+			services.ToolOptions{AdminOverride: true})
+		if err != nil {
+			result.Status = "Error"
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	result.Hash = materialized.Hash
+	result.ServeUrl = materialized.ServeUrl
+	result.Status = "Downloaded"
+
+	return result
+}
+
+// Check that the copy of the tool in the public directory still
+// matches its hash.
+func VerifyTool(config_obj *config_proto.Config, tool *artifacts_proto.Tool) error {
+	path_manager := paths.NewInventoryPathManager(config_obj, tool)
+	pathspec, file_store_factory, err := path_manager.Path()
+	if err != nil {
+		return err
+	}
+
+	fd, err := file_store_factory.ReadFile(pathspec)
+	if err != nil {
+		return fmt.Errorf("Tool %v is not in the public directory: %w",
+			tool.Name, err)
+	}
+	defer fd.Close()
+
+	sha_sum := sha256.New()
+	_, err = utils.Copy(context.Background(), sha_sum, fd)
+	if err != nil {
+		return err
+	}
+
+	hash := hex.EncodeToString(sha_sum.Sum(nil))
+	if hash != tool.Hash {
+		return fmt.Errorf("Tool %v has hash %v but expected %v",
+			tool.Name, hash, tool.Hash)
+	}
+	return nil
+}
@@ -44,6 +44,15 @@ type JournalService interface {
 		queue_name string,
 		watcher_name string) (output <-chan *ordereddict.Dict, cancel func())
 
+	// Watch a busy queue with several readers. Rows are split
+	// between the outputs by their ClientId so rows from the same
+	// client are always delivered in order on the same output.
+	WatchSharded(
+		ctx context.Context,
+		queue_name string,
+		watcher_name string,
+		shards int) (outputs []<-chan *ordereddict.Dict, cancel func())
+
 	GetWatchers() []string
 
 	// Push the rows into the result set in the filestore. NOTE: This
@@ -12,6 +12,7 @@ package journal
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/Velocidex/ordereddict"
@@ -87,6 +88,55 @@ func (self *JournalService) Watch(
 	}
 }
 
+func (self *JournalService) WatchSharded(
+	ctx context.Context, queue_name string,
+	watcher_name string, shards int) ([]<-chan *ordereddict.Dict, func()) {
+
+	if shards <= 1 {
+		output, cancel := self.Watch(ctx, queue_name, watcher_name)
+		return []<-chan *ordereddict.Dict{output}, cancel
+	}
+
+	if self == nil || self.qm == nil {
+		// Readers block on nil channels.
+		return make([]<-chan *ordereddict.Dict, shards), func() {}
+	}
+
+	disable_file_buffering := false
+	if self.config_obj.Frontend != nil &&
+		self.config_obj.Frontend.Resources != nil {
+		disable_file_buffering = self.config_obj.Frontend.Resources.DisableFileBuffering
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	logger.Info("%s: Watching for events from %v with %v shards",
+		watcher_name, queue_name, shards)
+
+	// Each shard has its own listener so a slow shard only buffers
+	// its own rows.
+	outputs := make([]<-chan *ordereddict.Dict, 0, shards)
+	cancellers := make([]func(), 0, shards)
+	for i := 0; i < shards; i++ {
+		res, cancel := self.qm.Watch(ctx, queue_name, &api.QueueOptions{
+			OwnerName:            fmt.Sprintf("%v_%v", watcher_name, i),
+			DisableFileBuffering: disable_file_buffering,
+			Shard:                i,
+			Shards:               shards,
+		})
+		outputs = append(outputs, res)
+		cancellers = append(cancellers, cancel)
+	}
+
+	self.publishWatchers(ctx)
+
+	return outputs, func() {
+		for _, cancel := range cancellers {
+			cancel()
+		}
+		self.publishWatchers(ctx)
+	}
+}
+
 // Write rows to a simple result set. This function manages concurrent
 // access to the result set within the same frontend. Currently there
 // is no need to manage write concurrency across frontends because
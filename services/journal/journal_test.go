@@ -1,9 +1,11 @@
 package journal_test
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/journal"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vtesting"
 
@@ -161,6 +164,97 @@ func (self *JournalTestSuite) TestJournalJsonlWriting() {
 	assert.True(self.T(), 0.07*10 > total_time)
 }
 
+func (self *JournalTestSuite) TestShardedWatch() {
+	j, err := services.GetJournal(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	outputs, cancel := j.WatchSharded(self.Ctx,
+		"System.Hunt.Participation", "Test", 3)
+	assert.Equal(self.T(), 3, len(outputs))
+
+	var mu sync.Mutex
+	seen := make(map[string][]int64)
+	shard_for_client := make(map[string]int)
+	total := 0
+
+	wg := &sync.WaitGroup{}
+	for idx, output := range outputs {
+		wg.Add(1)
+		go func(idx int, output <-chan *ordereddict.Dict) {
+			defer wg.Done()
+
+			for row := range output {
+				client_id, _ := row.GetString("ClientId")
+				i, _ := row.GetInt64("i")
+
+				mu.Lock()
+				seen[client_id] = append(seen[client_id], i)
+				shard_for_client[client_id] = idx
+				total++
+				mu.Unlock()
+			}
+		}(idx, output)
+	}
+
+	for i := 0; i < 5; i++ {
+		for c := 0; c < 6; c++ {
+			err = j.PushRowsToArtifact(self.Ctx, self.ConfigObj,
+				[]*ordereddict.Dict{ordereddict.NewDict().
+					Set("ClientId", fmt.Sprintf("C.%d", c)).
+					Set("i", i)},
+				"System.Hunt.Participation", "server", "")
+			assert.NoError(self.T(), err)
+		}
+	}
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return total == 30
+	})
+
+	cancel()
+	wg.Wait()
+
+	// Each client is delivered in order on its own shard.
+	for c := 0; c < 6; c++ {
+		client_id := fmt.Sprintf("C.%d", c)
+		assert.Equal(self.T(), []int64{0, 1, 2, 3, 4}, seen[client_id])
+		assert.Equal(self.T(),
+			api.ShardForRow(ordereddict.NewDict().
+				Set("ClientId", client_id), 3),
+			shard_for_client[client_id])
+	}
+}
+
+func TestShardEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	input := make(chan *ordereddict.Dict)
+	outputs := journal.ShardEvents(ctx, input, 2)
+	assert.Equal(t, 2, len(outputs))
+
+	go func() {
+		defer close(input)
+		for i := 0; i < 10; i++ {
+			input <- ordereddict.NewDict().
+				Set("ClientId", fmt.Sprintf("C.%d", i%4)).
+				Set("i", i)
+		}
+	}()
+
+	total := 0
+	for idx, output := range outputs {
+		// Outputs are closed when the input is done.
+		for row := range output {
+			assert.Equal(t, idx, api.ShardForRow(row, 2))
+			total++
+		}
+	}
+	assert.Equal(t, 10, total)
+}
+
 func TestJournalTestSuite(t *testing.T) {
 	suite.Run(t, &JournalTestSuite{})
 }
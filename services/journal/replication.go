@@ -645,6 +645,21 @@ func (self *ReplicationService) Watch(
 	return output_chan, cancel
 }
 
+// Minions receive all the events over a single stream from the
+// master and split them between the shards locally.
+func (self *ReplicationService) WatchSharded(
+	ctx context.Context, queue, watcher_name string, shards int) (
+	[]<-chan *ordereddict.Dict, func()) {
+
+	subctx, cancel := context.WithCancel(ctx)
+	output, watch_cancel := self.Watch(subctx, queue, watcher_name)
+
+	return ShardEvents(subctx, output, shards), func() {
+		watch_cancel()
+		cancel()
+	}
+}
+
 // Try to connect to the API handler once and return in case of
 // failure.
 func (self *ReplicationService) watchOnce(
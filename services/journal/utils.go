@@ -8,12 +8,21 @@ import (
 	"github.com/Velocidex/ordereddict"
 	"github.com/sirupsen/logrus"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
+const (
+	// Sharding busy client event queues is opt in since processors
+	// must then handle concurrent events from different clients.
+	DEFAULT_EVENT_QUEUE_SHARDS = 1
+
+	SHARD_BUFFER_SIZE = 100
+)
+
 // Watch "System.Flow.Completion" and trigger when a specific artifact
 // is collected.
 func WatchForCollectionWithCB(ctx context.Context,
@@ -74,32 +83,140 @@ func WatchQueueWithCB(ctx context.Context,
 		defer wg.Done()
 		defer cancel()
 
+		processQueue(ctx, config_obj, qm_chan,
+			artifact, watcher_name, processor)
+	}()
+
+	return nil
+}
+
+// Watch a busy client event queue and apply the processor on several
+// goroutines. Rows from the same client are processed in order by the
+// same goroutine so the processor only needs to be safe for
+// concurrent calls with different clients.
+func WatchQueueWithShardedCB(ctx context.Context,
+	config_obj *config_proto.Config,
+	wg *sync.WaitGroup,
+	artifact, watcher_name string,
+
+	processor func(ctx context.Context,
+		config_obj *config_proto.Config,
+		row *ordereddict.Dict) error) error {
+
+	journal, err := services.GetJournal(config_obj)
+	if err != nil {
+		return err
+	}
+
+	qm_chans, cancel := journal.WatchSharded(
+		ctx, artifact, watcher_name, GetEventQueueShards(config_obj))
+
+	shard_wg := &sync.WaitGroup{}
+	for _, qm_chan := range qm_chans {
+		shard_wg.Add(1)
+		go func(qm_chan <-chan *ordereddict.Dict) {
+			defer shard_wg.Done()
+
+			processQueue(ctx, config_obj, qm_chan,
+				artifact, watcher_name, processor)
+		}(qm_chan)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+
+		shard_wg.Wait()
+	}()
+
+	return nil
+}
+
+func GetEventQueueShards(config_obj *config_proto.Config) int {
+	shards := config_obj.GetFrontend().GetResources().GetEventQueueShards()
+	if shards == 0 {
+		return DEFAULT_EVENT_QUEUE_SHARDS
+	}
+	return int(shards)
+}
+
+// Split the rows from a single queue between shards by client id.
+func ShardEvents(ctx context.Context,
+	input <-chan *ordereddict.Dict, shards int) []<-chan *ordereddict.Dict {
+
+	if shards <= 1 {
+		return []<-chan *ordereddict.Dict{input}
+	}
+
+	outputs := make([]chan *ordereddict.Dict, 0, shards)
+	result := make([]<-chan *ordereddict.Dict, 0, shards)
+	for i := 0; i < shards; i++ {
+		output := make(chan *ordereddict.Dict, SHARD_BUFFER_SIZE)
+		outputs = append(outputs, output)
+		result = append(result, output)
+	}
+
+	go func() {
+		defer func() {
+			for _, output := range outputs {
+				close(output)
+			}
+		}()
+
 		for {
 			select {
-			case row, ok := <-qm_chan:
+			case <-ctx.Done():
+				return
+
+			case row, ok := <-input:
 				if !ok {
 					return
 				}
-				err := processor(ctx, config_obj, row)
-				if err != nil {
-					// Processor errors are not generally serious so
-					// we just log them into the debug log.
-					logger := logging.GetLogger(config_obj,
-						&logging.FrontendComponent)
-					logger.WithFields(logrus.Fields{
-						"Owner":    watcher_name,
-						"Artifact": artifact,
-						"Error":    err.Error(),
-					}).Debug("Event Processor Error")
-				}
 
-			case <-ctx.Done():
-				return
+				select {
+				case <-ctx.Done():
+					return
+				case outputs[api.ShardForRow(row, shards)] <- row:
+				}
 			}
 		}
 	}()
 
-	return nil
+	return result
+}
+
+func processQueue(ctx context.Context,
+	config_obj *config_proto.Config,
+	qm_chan <-chan *ordereddict.Dict,
+	artifact, watcher_name string,
+	processor func(ctx context.Context,
+		config_obj *config_proto.Config,
+		row *ordereddict.Dict) error) {
+
+	for {
+		select {
+		case row, ok := <-qm_chan:
+			if !ok {
+				return
+			}
+			err := processor(ctx, config_obj, row)
+			if err != nil {
+				// Processor errors are not generally serious so
+				// we just log them into the debug log.
+				logger := logging.GetLogger(config_obj,
+					&logging.FrontendComponent)
+				logger.WithFields(logrus.Fields{
+					"Owner":    watcher_name,
+					"Artifact": artifact,
+					"Error":    err.Error(),
+				}).Debug("Event Processor Error")
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
 // A convenience function to recover the full flow object from
@@ -1,11 +1,16 @@
 package launcher
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"www.velocidex.com/golang/velociraptor/acls"
 	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 )
 
@@ -36,3 +41,80 @@ func CheckAccess(
 
 	return nil
 }
+
+// Principal must hold the permissions matching ALL the capabilities
+// the artifact requires.
+func CheckCapabilities(
+	config_obj *config_proto.Config,
+	artifact *artifacts_proto.Artifact,
+	acl_manager vql_subsystem.ACLManager) error {
+
+	for _, capability := range artifact.RequiredCapabilities {
+		permission, pres := acls.GetCapabilityPermission(capability)
+		if !pres {
+			return fmt.Errorf(
+				"While collecting artifact (%s) unknown capability %v",
+				artifact.Name, capability)
+		}
+
+		ok, err := acl_manager.CheckAccess(permission)
+		if !ok {
+			if err != nil {
+				return fmt.Errorf(
+					"While collecting artifact (%s) capability %v requires permission %v: %v",
+					artifact.Name, capability, permission, err)
+			}
+			return fmt.Errorf(
+				"While collecting artifact (%s) capability %v requires permission %v",
+				artifact.Name, capability, permission)
+		}
+	}
+
+	return nil
+}
+
+// The capabilities of all the artifacts in the collection. These
+// were checked (or allowed by policy) when the collection was
+// compiled.
+func getGrantedCapabilities(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	collector_request *flows_proto.ArtifactCollectorArgs) []string {
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range collector_request.Artifacts {
+		var artifact *artifacts_proto.Artifact
+		if collector_request.AllowCustomOverrides {
+			artifact, _ = repository.Get(ctx, config_obj, "Custom."+name)
+		}
+
+		if artifact == nil {
+			artifact, _ = repository.Get(ctx, config_obj, name)
+		}
+
+		if artifact == nil {
+			continue
+		}
+
+		for _, capability := range artifact.RequiredCapabilities {
+			seen[strings.ToUpper(capability)] = true
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for capability := range seen {
+		result = append(result, capability)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -13,6 +13,7 @@ import (
 	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/libraries"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/vfilter"
 )
@@ -59,12 +60,20 @@ func (self *Launcher) CompileSingleArtifact(
 		escaped_name := maybeEscape(name)
 
 		switch parameter.Type {
-		case "", "string", "regex", "yara":
+		case "", "string", "regex", "yara", "tool":
 			// Nothing to do with these types.
 
 		case "redacted":
 			env.Comment = "redacted"
 
+		case "secret":
+			// The parameter holds the name of the secret so the
+			// value is never stored in the flow.
+			result.Query = append(result.Query, &actions_proto.VQLRequest{
+				VQL: fmt.Sprintf("LET %v <= if(condition=%v, then=secret(name=%v))",
+					escaped_name, escaped_name, escaped_name),
+			})
+
 		case "upload":
 			result.Query = append(result.Query, &actions_proto.VQLRequest{
 				VQL: fmt.Sprintf(`LET %v <= if(condition=%v, then={
@@ -237,17 +246,19 @@ func resolveImports(
 		return err
 	}
 
-	// These are a list of names to be imported.
+	// These are a list of names to be imported. Each is either an
+	// artifact whose export section is imported or a VQL library.
 	for _, imported := range artifact.Imports {
 		scope := vql_subsystem.MakeScope()
 
-		dependent_artifact, pres := global_repo.Get(ctx, config_obj, imported)
-		if !pres {
-			return fmt.Errorf("Artifact %v imports %v which is not known.",
-				artifact.Name, imported)
+		export, err := libraries.ResolveImport(
+			ctx, config_obj, global_repo, imported)
+		if err != nil {
+			return fmt.Errorf("Artifact %v imports %v which is not known: %w",
+				artifact.Name, imported, err)
 		}
-		if dependent_artifact.Export != "" {
-			queries, err := vfilter.MultiParse(dependent_artifact.Export)
+		if export != "" {
+			queries, err := vfilter.MultiParse(export)
 			if err != nil {
 				return fmt.Errorf("While parsing export in %s: %w",
 					artifact.Name, err)
@@ -395,6 +406,21 @@ func GetQueryDependencies(
 				continue
 			}
 
+			// Libraries are not artifacts but may call artifacts.
+			if libraries.IsLibraryImport(ctx, config_obj, repository, imp) {
+				library_vql, err := libraries.GetImport(config_obj, imp)
+				if err != nil {
+					return err
+				}
+
+				err = GetQueryDependencies(ctx, config_obj, repository,
+					library_vql, 0, dependency)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
 			dependency[imp] = depth
 			imported_artifact, pres := repository.Get(ctx, config_obj, imp)
 			if !pres {
@@ -17,6 +17,7 @@ import (
 	artifact_paths "www.velocidex.com/golang/velociraptor/paths/artifacts"
 	"www.velocidex.com/golang/velociraptor/result_sets"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/legal_hold"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
@@ -26,6 +27,13 @@ func (self *FlowStorageManager) DeleteFlow(
 	client_id string, flow_id string, principal string,
 	really_do_it bool) ([]*services.DeleteFlowResponse, error) {
 
+	if really_do_it {
+		err := legal_hold.CheckFlow(config_obj, client_id, flow_id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	launcher, err := services.GetLauncher(config_obj)
 	if err != nil {
 		return nil, err
@@ -229,6 +237,11 @@ func (self *Launcher) DeleteEvents(
 	start_time, end_time time.Time,
 	really_do_it bool) ([]*services.DeleteFlowResponse, error) {
 
+	if really_do_it && legal_hold.IsClientHeld(config_obj, client_id) {
+		return nil, fmt.Errorf("%w: client %v",
+			legal_hold.ErrOnHold, client_id)
+	}
+
 	path_manager, err := artifacts.NewArtifactPathManager(ctx,
 		config_obj, client_id, "", artifact)
 	if err != nil {
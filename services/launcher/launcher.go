@@ -232,6 +232,17 @@ func (self *Launcher) CompileCollectorArgs(
 			return nil, err
 		}
 
+		err = CheckCapabilities(config_obj, artifact, acl_manager)
+		if err != nil {
+			if config_obj.Defaults == nil ||
+				!config_obj.Defaults.AllowMissingCapabilities {
+				return nil, err
+			}
+
+			logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+			logger.Warn("CompileCollectorArgs: %v", err)
+		}
+
 		// Adjust collection wide resources to be the maximum
 		// number of all default
 		if artifact.Resources != nil {
@@ -435,6 +446,11 @@ func (self *Launcher) GetVQLCollectorArgs(
 		return nil, err
 	}
 
+	err = addMinimizationFilters(vql_collector_args, spec)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, tool := range artifact.Tools {
 		err = AddToolDependency(ctx, config_obj, tool.Name,
 			tool.Version, vql_collector_args)
@@ -443,6 +459,11 @@ func (self *Launcher) GetVQLCollectorArgs(
 		}
 	}
 
+	err = addToolParameters(ctx, config_obj, artifact, vql_collector_args)
+	if err != nil {
+		return nil, err
+	}
+
 	if options.ObfuscateNames {
 		err = artifacts.Obfuscate(config_obj, vql_collector_args)
 		if err != nil {
@@ -542,6 +563,36 @@ func AddToolDependency(
 	return nil
 }
 
+// Parameters of type "tool" name a tool in the inventory which is
+// selected at collection time. Resolve the tool the same way as tools
+// declared by the artifact so the client can fetch it from the
+// server.
+func addToolParameters(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	artifact *artifacts_proto.Artifact,
+	vql_collector_args *actions_proto.VQLCollectorArgs) error {
+	for _, parameter := range artifact.Parameters {
+		if parameter.Type != "tool" {
+			continue
+		}
+
+		for _, env := range vql_collector_args.Env {
+			if env.Key != parameter.Name || env.Value == "" {
+				continue
+			}
+
+			err := AddToolDependency(ctx, config_obj, env.Value, "",
+				vql_collector_args)
+			if err != nil {
+				return fmt.Errorf("Parameter %v: %w", parameter.Name, err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
 // Scheduling artifact collections only happens on the master node at
 // the moment.
 func (self *Launcher) ScheduleArtifactCollection(
@@ -632,6 +683,8 @@ func (self *Launcher) WriteArtifactCollectionRecord(
 			LogBatchTime:   batch_delay,
 			MaxRows:        collector_request.MaxRows,
 			MaxUploadBytes: collector_request.MaxUploadBytes,
+			Priority:       collector_request.Priority,
+			DryRun:         collector_request.DryRun,
 		},
 	}
 
@@ -667,6 +720,8 @@ func (self *Launcher) WriteArtifactCollectionRecord(
 		ClientId:            client_id,
 		TotalRequests:       int64(len(vql_collector_args)),
 		OutstandingRequests: int64(len(vql_collector_args)),
+		GrantedCapabilities: getGrantedCapabilities(
+			ctx, config_obj, collector_request),
 	}
 
 	// Record the tasks for provenance of what we actually did.
@@ -33,6 +33,7 @@ import (
 	_ "www.velocidex.com/golang/velociraptor/accessors/data"
 	_ "www.velocidex.com/golang/velociraptor/result_sets/timed"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	_ "www.velocidex.com/golang/velociraptor/vql/common"
 	_ "www.velocidex.com/golang/velociraptor/vql/functions"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/csv"
 	_ "www.velocidex.com/golang/velociraptor/vql/protocols"
@@ -166,6 +167,74 @@ func (self *LauncherTestSuite) TestCompilingWithTools() {
 		"https://localhost:8000/public/"+filename)
 }
 
+var testArtifactWithToolParameter = `
+name: Test.Artifact.ToolParameter
+parameters:
+ - name: ToolName
+   type: tool
+   default: Tool2
+
+sources:
+- query:  |
+    SELECT * FROM info()
+`
+
+// Parameters of type tool select the tool at collection time.
+func (self *LauncherTestSuite) TestCompilingWithToolParameter() {
+	ctx := context.Background()
+	repository := self.LoadArtifacts(testArtifactWithToolParameter)
+
+	inventory_service, err := services.GetInventory(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	for _, name := range []string{"Tool2", "Tool3"} {
+		err = inventory_service.AddTool(ctx,
+			self.ConfigObj, &artifacts_proto.Tool{
+				Name:     name,
+				Filename: name + ".exe",
+				Url:      "https://www.example.com/" + name + ".exe",
+				Hash:     "4d1d7a9b" + name,
+			}, services.ToolOptions{AdminOverride: true})
+		assert.NoError(self.T(), err)
+	}
+
+	request := &flows_proto.ArtifactCollectorArgs{
+		Creator:   "UserX",
+		ClientId:  "C.1234",
+		Artifacts: []string{"Test.Artifact.ToolParameter"},
+	}
+	acl_manager := acl_managers.NullACLManager{}
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// The default tool is used.
+	compiled, err := launcher.CompileCollectorArgs(ctx, self.ConfigObj,
+		acl_manager, repository, services.CompilerOptions{}, request)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), getEnvValue(compiled[0].Env, "Tool_Tool2_FILENAME"), "Tool2.exe")
+	assert.Equal(self.T(), getEnvValue(compiled[0].Env, "Tool_Tool2_HASH"), "4d1d7a9bTool2")
+
+	// The user can select a different tool.
+	request.Specs = []*flows_proto.ArtifactSpec{{
+		Artifact: "Test.Artifact.ToolParameter",
+		Parameters: &flows_proto.ArtifactParameters{
+			Env: []*actions_proto.VQLEnv{{Key: "ToolName", Value: "Tool3"}},
+		},
+	}}
+	compiled, err = launcher.CompileCollectorArgs(ctx, self.ConfigObj,
+		acl_manager, repository, services.CompilerOptions{}, request)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), getEnvValue(compiled[0].Env, "Tool_Tool3_FILENAME"), "Tool3.exe")
+	assert.Equal(self.T(), getEnvValue(compiled[0].Env, "Tool_Tool2_FILENAME"), "")
+
+	// Unknown tools are an error.
+	request.Specs[0].Parameters.Env[0].Value = "NoSuchTool"
+	_, err = launcher.CompileCollectorArgs(ctx, self.ConfigObj,
+		acl_manager, repository, services.CompilerOptions{}, request)
+	assert.Error(self.T(), err)
+}
+
 var DependentArtifacts = []string{
 	`
 name: Test.Artifact
@@ -600,6 +669,63 @@ sources:
 	assert.Equal(self.T(), len(compiled[0].Query), 2)
 }
 
+func (self *LauncherTestSuite) TestCompilingCapabilities() {
+	repository := self.LoadArtifacts(`
+name: Test.Artifact.Capabilities
+required_capabilities:
+- FILESYSTEM_WRITE
+- network
+
+sources:
+- query:  |
+    SELECT * FROM info()
+`)
+
+	request := &flows_proto.ArtifactCollectorArgs{
+		Creator:   "UserY",
+		ClientId:  "C.1234",
+		Artifacts: []string{"Test.Artifact.Capabilities"},
+	}
+	ctx := context.Background()
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// The principal does not hold the permissions for the
+	// capabilities.
+	acl_manager := acl_managers.NewServerACLManager(self.ConfigObj, "UserY")
+	_, err = launcher.CompileCollectorArgs(
+		ctx, self.ConfigObj, acl_manager, repository,
+		services.CompilerOptions{}, request)
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "FILESYSTEM_WRITE")
+
+	// The policy may allow the collection with a warning.
+	self.ConfigObj.Defaults.AllowMissingCapabilities = true
+	_, err = launcher.CompileCollectorArgs(
+		ctx, self.ConfigObj, acl_manager, repository,
+		services.CompilerOptions{}, request)
+	assert.NoError(self.T(), err)
+	self.ConfigObj.Defaults.AllowMissingCapabilities = false
+
+	// Grant the matching permissions.
+	err = services.SetPolicy(self.ConfigObj, "UserY",
+		&acl_proto.ApiClientACL{FilesystemWrite: true, CollectServer: true})
+	assert.NoError(self.T(), err)
+
+	acl_manager = acl_managers.NewServerACLManager(self.ConfigObj, "UserY")
+	flow_id, err := launcher.ScheduleArtifactCollection(
+		ctx, self.ConfigObj, acl_manager, repository, request, nil)
+	assert.NoError(self.T(), err)
+
+	// The granted capabilities are recorded in the flow.
+	details, err := launcher.GetFlowDetails(
+		ctx, self.ConfigObj, "C.1234", flow_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), []string{"FILESYSTEM_WRITE", "NETWORK"},
+		details.Context.GrantedCapabilities)
+}
+
 func (self *LauncherTestSuite) TestParameterTypes() {
 	repository := self.LoadArtifacts(testArtifactWithTypes...)
 
@@ -1241,6 +1367,76 @@ func getReqName(in *actions_proto.VQLCollectorArgs) string {
 	return ""
 }
 
+func (self *LauncherTestSuite) TestDataMinimizationFilters() {
+	repository := self.LoadArtifacts(`
+name: Test.Artifact.Minimize
+sources:
+- query: |
+    LET rows <= (dict(Pid=1, CommandLine="backup.exe --password hunter2"),
+                 dict(Pid=2, CommandLine="notepad.exe private.txt"),
+                 dict(Pid=3, CommandLine="cmd.exe"))
+    SELECT * FROM foreach(row=rows)
+`)
+
+	request := &flows_proto.ArtifactCollectorArgs{
+		Artifacts: []string{"Test.Artifact.Minimize"},
+		Specs: []*flows_proto.ArtifactSpec{{
+			Artifact: "Test.Artifact.Minimize",
+			Filters: []*flows_proto.DataMinimizationFilter{{
+				Column:  "^CommandLine$",
+				Pattern: "private",
+				Action:  "drop",
+			}, {
+				Column:      "^CommandLine$",
+				Pattern:     "--password [^ ]+",
+				Replacement: "--password XXX",
+			}},
+		}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	acl_manager := acl_managers.NullACLManager{}
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	compiled, err := launcher.CompileCollectorArgs(
+		ctx, self.ConfigObj, acl_manager, repository,
+		services.CompilerOptions{}, request)
+	assert.NoError(self.T(), err)
+
+	// The filters run on the endpoint.
+	test_responder := responder.TestResponderWithFlowId(
+		self.ConfigObj, "F.TestDataMinimizationFilters")
+	for _, vql_request := range compiled {
+		actions.VQLClientAction{}.StartQuery(
+			self.ConfigObj, ctx, test_responder, vql_request)
+	}
+
+	var messages []*ordereddict.Dict
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		messages = getResponses(test_responder.Drain.Messages())
+		return len(messages) > 0
+	})
+
+	assert.Equal(self.T(), 2, len(messages))
+	value, _ := messages[0].Get("CommandLine")
+	assert.Equal(self.T(), "backup.exe --password XXX", value)
+
+	value, _ = messages[1].Get("CommandLine")
+	assert.Equal(self.T(), "cmd.exe", value)
+
+	// Invalid filters are rejected by the server.
+	request.Specs[0].Filters = []*flows_proto.DataMinimizationFilter{{
+		Action: "drop",
+	}}
+	_, err = launcher.CompileCollectorArgs(
+		ctx, self.ConfigObj, acl_manager, repository,
+		services.CompilerOptions{}, request)
+	assert.Error(self.T(), err)
+}
+
 func TestLauncher(t *testing.T) {
 	suite.Run(t, &LauncherTestSuite{})
 }
@@ -0,0 +1,73 @@
+package launcher
+
+import (
+	"fmt"
+	"regexp"
+
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+)
+
+func validateMinimizationFilter(filter *flows_proto.DataMinimizationFilter) error {
+	switch filter.Action {
+	case "drop":
+		if filter.Pattern == "" {
+			return fmt.Errorf("Dropping rows requires a pattern")
+		}
+	case "", "mask":
+	default:
+		return fmt.Errorf("Unknown data minimization action %v", filter.Action)
+	}
+
+	for _, re := range []string{filter.Column, filter.Pattern} {
+		_, err := regexp.Compile(re)
+		if err != nil {
+			return fmt.Errorf("Data minimization filter: %w", err)
+		}
+	}
+	return nil
+}
+
+// Wrap the result queries with the minimize() plugin so rows are
+// dropped or masked on the endpoint before they are sent to the
+// server. The filter arguments are passed in the environment to
+// avoid having to escape them in the VQL.
+//
+// Older clients do not have the minimize() plugin and will fail the
+// query rather than send unfiltered rows.
+func addMinimizationFilters(
+	vql_collector_args *actions_proto.VQLCollectorArgs,
+	spec *flows_proto.ArtifactSpec) error {
+	if spec == nil {
+		return nil
+	}
+
+	for idx, filter := range spec.Filters {
+		err := validateMinimizationFilter(filter)
+		if err != nil {
+			return err
+		}
+
+		prefix := fmt.Sprintf("MinimizeFilter%d", idx)
+		for _, arg := range []struct{ name, value string }{
+			{"Column", filter.Column},
+			{"Pattern", filter.Pattern},
+			{"Action", filter.Action},
+			{"Replacement", filter.Replacement},
+		} {
+			vql_collector_args.Env = append(vql_collector_args.Env,
+				&actions_proto.VQLEnv{Key: prefix + arg.name, Value: arg.value})
+		}
+
+		for _, query := range vql_collector_args.Query {
+			if query.Name == "" {
+				continue
+			}
+
+			query.VQL = fmt.Sprintf(
+				"SELECT * FROM minimize(query={ %s }, column=%sColumn, pattern=%sPattern, action=%sAction, replacement=%sReplacement)",
+				query.VQL, prefix, prefix, prefix, prefix)
+		}
+	}
+	return nil
+}
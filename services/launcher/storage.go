@@ -233,6 +233,7 @@ func (self *FlowStorageManager) LoadCollectionContext(
 	if len(stats_context.QueryStats) > 0 {
 		collection_context.QueryStats = stats_context.QueryStats
 	}
+	collection_context.QueuePosition = stats_context.QueuePosition
 
 	UpdateFlowStats(collection_context)
 	return collection_context, nil
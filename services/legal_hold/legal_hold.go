@@ -0,0 +1,466 @@
+/*
+  Legal holds preserve data which must not be deleted, for example
+  while it is subject to litigation.
+
+  A hold may be placed on a single collection, a hunt (covering all
+  its collections) or an entire client. Held data is skipped by
+  anything which deletes collected data - deleting flows, hunts and
+  clients, expiring monitoring data and purging subject data.
+
+  Holds are stored in the datastore so they are honored by every
+  frontend and by offline tools without a running service.
+*/
+
+package legal_hold
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	artifact_paths "www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+const (
+	TYPE_CLIENT = "client"
+	TYPE_FLOW   = "flow"
+	TYPE_HUNT   = "hunt"
+)
+
+var (
+	// Returned when deleting held data.
+	ErrOnHold = errors.New("Data is on legal hold")
+
+	flowIdRegex = regexp.MustCompile(`^F\.[^/ ]+$`)
+)
+
+type Hold struct {
+	// One of client, flow or hunt.
+	Type     string `json:"type"`
+	ClientId string `json:"client_id,omitempty"`
+	FlowId   string `json:"flow_id,omitempty"`
+	HuntId   string `json:"hunt_id,omitempty"`
+
+	Reason    string `json:"reason,omitempty"`
+	Principal string `json:"principal,omitempty"`
+	Created   int64  `json:"created"`
+
+	// The volume of held data - only filled in by Report()
+	Files int64 `json:"files,omitempty"`
+	Bytes int64 `json:"bytes,omitempty"`
+}
+
+func (self *Hold) path() (api.DSPathSpec, error) {
+	switch self.Type {
+	case TYPE_CLIENT:
+		if !constants.ClientIdRegex.MatchString(self.ClientId) {
+			return nil, errors.New("Client Id should be of the form C.XXXX")
+		}
+		return paths.ClientLegalHoldPath(self.ClientId), nil
+
+	case TYPE_FLOW:
+		if !constants.ClientIdRegex.MatchString(self.ClientId) {
+			return nil, errors.New("Client Id should be of the form C.XXXX")
+		}
+		if !flowIdRegex.MatchString(self.FlowId) {
+			return nil, errors.New("Flow Id should be of the form F.XXXX")
+		}
+		return paths.FlowLegalHoldPath(self.ClientId, self.FlowId), nil
+
+	case TYPE_HUNT:
+		if !constants.HuntIdRegex.MatchString(self.HuntId) {
+			return nil, errors.New("Hunt Id should be of the form H.XXXX")
+		}
+		return paths.HuntLegalHoldPath(self.HuntId), nil
+	}
+
+	return nil, fmt.Errorf("Unsupported legal hold type %v", self.Type)
+}
+
+func (self *Hold) String() string {
+	switch self.Type {
+	case TYPE_CLIENT:
+		return "client " + self.ClientId
+	case TYPE_FLOW:
+		return "flow " + self.FlowId + " on client " + self.ClientId
+	default:
+		return "hunt " + self.HuntId
+	}
+}
+
+func getRawDB(config_obj *config_proto.Config) (
+	datastore.DataStore, datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, nil, errors.New("Datastore does not support raw access")
+	}
+	return db, raw_db, nil
+}
+
+// Place a new hold or update the reason of an existing one.
+func Place(ctx context.Context, config_obj *config_proto.Config,
+	principal string, hold *Hold) (*Hold, error) {
+	path, err := hold.path()
+	if err != nil {
+		return nil, err
+	}
+
+	_, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Hold{
+		Type:      hold.Type,
+		ClientId:  hold.ClientId,
+		FlowId:    hold.FlowId,
+		HuntId:    hold.HuntId,
+		Reason:    hold.Reason,
+		Principal: principal,
+		Created:   utils.GetTime().Now().Unix(),
+	}
+
+	serialized, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = raw_db.SetBuffer(config_obj, path, serialized, utils.SyncCompleter)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, services.LogAudit(ctx, config_obj, principal,
+		"LegalHoldPlace", ordereddict.NewDict().
+			Set("hold", result))
+}
+
+// Release the hold so the data may be deleted again.
+func Release(ctx context.Context, config_obj *config_proto.Config,
+	principal string, hold *Hold) error {
+	path, err := hold.path()
+	if err != nil {
+		return err
+	}
+
+	existing, err := getHold(config_obj, path)
+	if err != nil {
+		return fmt.Errorf("No legal hold on %v", hold)
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	err = db.DeleteSubject(config_obj, path)
+	if err != nil {
+		return err
+	}
+
+	return services.LogAudit(ctx, config_obj, principal,
+		"LegalHoldRelease", ordereddict.NewDict().
+			Set("hold", existing))
+}
+
+func getHold(config_obj *config_proto.Config,
+	path api.DSPathSpec) (*Hold, error) {
+	_, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := raw_db.GetBuffer(config_obj, path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Hold{}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func isHeld(config_obj *config_proto.Config, path api.DSPathSpec) bool {
+	_, err := getHold(config_obj, path)
+	return err == nil
+}
+
+// List all holds sorted by creation time.
+func List(config_obj *config_proto.Config) ([]*Hold, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Hold{}
+	err = datastore.Walk(config_obj, db, paths.LEGAL_HOLDS_ROOT,
+		datastore.WalkWithoutDirectories,
+		func(path api.DSPathSpec) error {
+			hold, err := getHold(config_obj, path)
+			if err == nil {
+				result = append(result, hold)
+			}
+			return nil
+		})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Created < result[j].Created
+	})
+
+	return result, err
+}
+
+func IsClientHeld(config_obj *config_proto.Config, client_id string) bool {
+	return isHeld(config_obj, paths.ClientLegalHoldPath(client_id))
+}
+
+func IsHuntHeld(config_obj *config_proto.Config, hunt_id string) bool {
+	return isHeld(config_obj, paths.HuntLegalHoldPath(hunt_id))
+}
+
+// A flow is held by its own hold, its client's or its hunt's.
+func IsFlowHeld(config_obj *config_proto.Config,
+	client_id, flow_id string) bool {
+	if IsClientHeld(config_obj, client_id) ||
+		isHeld(config_obj, paths.FlowLegalHoldPath(client_id, flow_id)) {
+		return true
+	}
+
+	hunt_id, ok := utils.ExtractHuntId(flow_id)
+	return ok && IsHuntHeld(config_obj, hunt_id)
+}
+
+// Is any of the client's data held? This includes holds on the
+// client's flows and on hunts the client took part in.
+func IsClientDataHeld(config_obj *config_proto.Config,
+	client_id string) (bool, error) {
+	if IsClientHeld(config_obj, client_id) {
+		return true, nil
+	}
+
+	holds, err := List(config_obj)
+	if err != nil {
+		return false, err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return false, err
+	}
+
+	for _, hold := range holds {
+		switch hold.Type {
+		case TYPE_FLOW:
+			if hold.ClientId == client_id {
+				return true, nil
+			}
+
+		case TYPE_HUNT:
+			flow_id := utils.CreateFlowIdFromHuntId(hold.HuntId)
+			flow_path_manager := paths.NewFlowPathManager(client_id, flow_id)
+			message := &flows_proto.ArtifactCollectorContext{}
+			if db.GetSubject(config_obj,
+				flow_path_manager.Path(), message) == nil {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// Is the datastore or filestore item at this path held? Client
+// items are held by a client hold and items belonging to a flow
+// (e.g. clients/C.123/artifacts/Generic.Client.Info/F.1234) also by
+// the flow or its hunt's hold.
+func IsPathHeld(config_obj *config_proto.Config, components []string) bool {
+	if len(components) < 2 {
+		return false
+	}
+
+	switch components[0] {
+	// The hold records themselves.
+	case paths.LEGAL_HOLDS_ROOT.Base():
+		return true
+
+	case "hunts":
+		return IsHuntHeld(config_obj, components[1])
+
+	case "clients":
+		client_id := components[1]
+		if IsClientHeld(config_obj, client_id) {
+			return true
+		}
+
+		for _, c := range components[2:] {
+			if flowIdRegex.MatchString(c) &&
+				IsFlowHeld(config_obj, client_id, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Return an error wrapping ErrOnHold if the flow is held.
+func CheckFlow(config_obj *config_proto.Config,
+	client_id, flow_id string) error {
+	if IsFlowHeld(config_obj, client_id, flow_id) {
+		return fmt.Errorf("%w: flow %v on client %v",
+			ErrOnHold, flow_id, client_id)
+	}
+	return nil
+}
+
+// Return an error wrapping ErrOnHold if any of the client's data is
+// held.
+func CheckClient(config_obj *config_proto.Config, client_id string) error {
+	held, err := IsClientDataHeld(config_obj, client_id)
+	if err != nil {
+		return err
+	}
+	if held {
+		return fmt.Errorf("%w: client %v", ErrOnHold, client_id)
+	}
+	return nil
+}
+
+// List all holds with the volume of data they hold.
+func Report(ctx context.Context,
+	config_obj *config_proto.Config) ([]*Hold, error) {
+	holds, err := List(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hold := range holds {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		v := &volume{}
+		switch hold.Type {
+		case TYPE_CLIENT:
+			client_path_manager := paths.NewClientPathManager(hold.ClientId)
+			v.addDirectory(config_obj,
+				client_path_manager.Path().AsFilestorePath())
+
+		case TYPE_FLOW:
+			v.addFlow(ctx, config_obj, hold.ClientId, hold.FlowId)
+
+		case TYPE_HUNT:
+			err := v.addHunt(ctx, config_obj, hold.HuntId)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		hold.Files = v.files
+		hold.Bytes = v.bytes
+	}
+
+	return holds, nil
+}
+
+type volume struct {
+	files, bytes int64
+}
+
+func (self *volume) addFile(
+	file_store_factory api.FileStore, path api.FSPathSpec) {
+	info, err := file_store_factory.StatFile(path)
+	if err == nil && !info.IsDir() {
+		self.files++
+		self.bytes += info.Size()
+	}
+}
+
+func (self *volume) addDirectory(
+	config_obj *config_proto.Config, root api.FSPathSpec) {
+	file_store_factory := file_store.GetFileStore(config_obj)
+	_ = api.Walk(file_store_factory, root,
+		func(path api.FSPathSpec, info os.FileInfo) error {
+			self.files++
+			self.bytes += info.Size()
+			return nil
+		})
+}
+
+// A flow's logs and uploads are stored in its directory but its
+// results are stored with the artifact.
+func (self *volume) addFlow(ctx context.Context,
+	config_obj *config_proto.Config, client_id, flow_id string) {
+	flow_path_manager := paths.NewFlowPathManager(client_id, flow_id)
+	self.addDirectory(config_obj, flow_path_manager.Path().AsFilestorePath())
+
+	launcher, err := services.GetLauncher(config_obj)
+	if err != nil {
+		return
+	}
+
+	details, err := launcher.GetFlowDetails(ctx, config_obj, client_id, flow_id)
+	if err != nil || details.Context == nil {
+		return
+	}
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	for _, artifact_name := range details.Context.ArtifactsWithResults {
+		path_manager, err := artifact_paths.NewArtifactPathManager(ctx,
+			config_obj, client_id, flow_id, artifact_name)
+		if err != nil {
+			continue
+		}
+
+		result_path, err := path_manager.GetPathForWriting()
+		if err != nil {
+			continue
+		}
+
+		self.addFile(file_store_factory, result_path)
+		self.addFile(file_store_factory,
+			result_path.SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX))
+	}
+}
+
+func (self *volume) addHunt(ctx context.Context,
+	config_obj *config_proto.Config, hunt_id string) error {
+	hunt_dispatcher, err := services.GetHuntDispatcher(config_obj)
+	if err != nil {
+		return err
+	}
+
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	for flow_details := range hunt_dispatcher.GetFlows(
+		ctx, config_obj, scope, hunt_id, 0) {
+		if flow_details.Context != nil {
+			self.addFlow(ctx, config_obj, flow_details.Context.ClientId,
+				flow_details.Context.SessionId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package legal_hold_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/legal_hold"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type LegalHoldTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *LegalHoldTestSuite) TestClientHold() {
+	hold, err := legal_hold.Place(self.Ctx, self.ConfigObj, "admin",
+		&legal_hold.Hold{
+			Type:     legal_hold.TYPE_CLIENT,
+			ClientId: "C.123",
+			Reason:   "Case 42",
+		})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "admin", hold.Principal)
+
+	// All the client's flows are held.
+	assert.True(self.T(), legal_hold.IsFlowHeld(
+		self.ConfigObj, "C.123", "F.1234"))
+	assert.True(self.T(), !legal_hold.IsFlowHeld(
+		self.ConfigObj, "C.456", "F.1234"))
+
+	err = legal_hold.CheckClient(self.ConfigObj, "C.123")
+	assert.True(self.T(), errors.Is(err, legal_hold.ErrOnHold))
+
+	// Deleting the flow is refused but a dry run is allowed.
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	_, err = launcher.Storage().DeleteFlow(self.Ctx, self.ConfigObj,
+		"C.123", "F.1234", "admin", true)
+	assert.True(self.T(), errors.Is(err, legal_hold.ErrOnHold))
+
+	_, err = launcher.Storage().DeleteFlow(self.Ctx, self.ConfigObj,
+		"C.123", "F.1234", "admin", services.DryRunOnly)
+	assert.True(self.T(), !errors.Is(err, legal_hold.ErrOnHold))
+
+	assert.True(self.T(), legal_hold.IsPathHeld(self.ConfigObj,
+		[]string{"clients", "C.123", "artifacts", "Generic.Client.Info", "F.1"}))
+	assert.True(self.T(), legal_hold.IsPathHeld(self.ConfigObj,
+		[]string{"legal_holds", "clients", "C.123"}))
+
+	// Once released the data may be deleted.
+	err = legal_hold.Release(self.Ctx, self.ConfigObj, "admin",
+		&legal_hold.Hold{Type: legal_hold.TYPE_CLIENT, ClientId: "C.123"})
+	assert.NoError(self.T(), err)
+
+	assert.NoError(self.T(), legal_hold.CheckClient(self.ConfigObj, "C.123"))
+
+	// Nothing left to release.
+	err = legal_hold.Release(self.Ctx, self.ConfigObj, "admin",
+		&legal_hold.Hold{Type: legal_hold.TYPE_CLIENT, ClientId: "C.123"})
+	assert.Error(self.T(), err)
+}
+
+func (self *LegalHoldTestSuite) TestFlowAndHuntHolds() {
+	_, err := legal_hold.Place(self.Ctx, self.ConfigObj, "admin",
+		&legal_hold.Hold{
+			Type:     legal_hold.TYPE_FLOW,
+			ClientId: "C.123",
+			FlowId:   "F.1234",
+		})
+	assert.NoError(self.T(), err)
+
+	_, err = legal_hold.Place(self.Ctx, self.ConfigObj, "admin",
+		&legal_hold.Hold{Type: legal_hold.TYPE_HUNT, HuntId: "H.5678"})
+	assert.NoError(self.T(), err)
+
+	assert.True(self.T(), legal_hold.IsFlowHeld(
+		self.ConfigObj, "C.123", "F.1234"))
+	assert.True(self.T(), !legal_hold.IsFlowHeld(
+		self.ConfigObj, "C.123", "F.9999"))
+
+	// The hunt's flows are held on all clients.
+	assert.True(self.T(), legal_hold.IsFlowHeld(
+		self.ConfigObj, "C.456", "F.5678.H"))
+
+	// Client C.456 took part in the hunt, C.789 did not.
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	assert.NoError(self.T(), db.SetSubject(self.ConfigObj,
+		paths.NewFlowPathManager("C.456", "F.5678.H").Path(),
+		&flows_proto.ArtifactCollectorContext{SessionId: "F.5678.H"}))
+
+	for client_id, expected := range map[string]bool{
+		"C.123": true, "C.456": true, "C.789": false} {
+		held, err := legal_hold.IsClientDataHeld(self.ConfigObj, client_id)
+		assert.NoError(self.T(), err)
+		assert.Equal(self.T(), expected, held, client_id)
+	}
+
+	holds, err := legal_hold.List(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(holds))
+
+	// Invalid ids are rejected.
+	_, err = legal_hold.Place(self.Ctx, self.ConfigObj, "admin",
+		&legal_hold.Hold{Type: legal_hold.TYPE_FLOW, ClientId: "C.123",
+			FlowId: "../F.1"})
+	assert.Error(self.T(), err)
+}
+
+func (self *LegalHoldTestSuite) TestReport() {
+	_, err := legal_hold.Place(self.Ctx, self.ConfigObj, "admin",
+		&legal_hold.Hold{Type: legal_hold.TYPE_CLIENT, ClientId: "C.123"})
+	assert.NoError(self.T(), err)
+
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	flow_path_manager := paths.NewFlowPathManager("C.123", "F.1234")
+	fd, err := file_store_factory.WriteFile(flow_path_manager.Log())
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("0123456789"))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	holds, err := legal_hold.Report(self.Ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(holds))
+	assert.Equal(self.T(), int64(1), holds[0].Files)
+	assert.Equal(self.T(), int64(10), holds[0].Bytes)
+}
+
+func TestLegalHold(t *testing.T) {
+	suite.Run(t, &LegalHoldTestSuite{})
+}
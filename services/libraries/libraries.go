@@ -0,0 +1,324 @@
+/*
+  VQL libraries are named collections of LET definitions shared by
+  many artifacts, so common helpers (e.g. parsing functions) need not
+  be copied into each artifact.
+
+  Artifacts use a library by listing it in their imports:
+
+  imports:
+    - Custom.Parsers       # The latest version
+    - Custom.Parsers@3     # Pinned to version 3
+
+  Each change to a library creates a new immutable version so pinned
+  artifacts keep working when the library changes. Unversioned
+  imports of a known artifact still import that artifact's export
+  section.
+
+  Libraries are stored in the datastore so they are available to
+  every frontend without a running service.
+*/
+
+package libraries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	notFoundError = errors.New("VQL library not found")
+
+	// Library names follow the same rules as artifact names.
+	nameRegex      = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*(\\.[a-zA-Z_][a-zA-Z0-9_]*)*$")
+	versionedRegex = regexp.MustCompile("^([^@]+)@([0-9]+)$")
+)
+
+type Version struct {
+	Version   int64  `json:"version"`
+	VQL       string `json:"vql"`
+	Principal string `json:"principal,omitempty"`
+	Created   int64  `json:"created"`
+}
+
+type Library struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Versions    []*Version `json:"versions"`
+}
+
+func (self *Library) Latest() *Version {
+	if len(self.Versions) == 0 {
+		return nil
+	}
+	return self.Versions[len(self.Versions)-1]
+}
+
+func (self *Library) GetVersion(version int64) (*Version, error) {
+	for _, v := range self.Versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("VQL library %v has no version %v",
+		self.Name, version)
+}
+
+// Split an import into the library name and the pinned version. The
+// version is 0 when not pinned.
+func ParseImport(imported string) (string, int64, error) {
+	matches := versionedRegex.FindStringSubmatch(imported)
+	if matches == nil {
+		return imported, 0, nil
+	}
+
+	version, err := strconv.ParseInt(matches[2], 10, 64)
+	if err != nil || version == 0 {
+		return "", 0, fmt.Errorf("Invalid library version in %v", imported)
+	}
+	return matches[1], version, nil
+}
+
+// Libraries may only define stored queries and functions. Anything
+// else would run whenever the library is imported.
+func validateVQL(vql string) error {
+	queries, err := vfilter.MultiParse(vql)
+	if err != nil {
+		return fmt.Errorf("While parsing VQL library: %w", err)
+	}
+
+	if len(queries) == 0 {
+		return errors.New("VQL library defines nothing")
+	}
+
+	for _, q := range queries {
+		if q.Let == "" {
+			return errors.New("VQL libraries may only contain LET statements")
+		}
+	}
+	return nil
+}
+
+func getRawDB(config_obj *config_proto.Config) (
+	datastore.DataStore, datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, nil, errors.New("Datastore does not support raw access")
+	}
+	return db, raw_db, nil
+}
+
+func getLibrary(config_obj *config_proto.Config,
+	path api.DSPathSpec) (*Library, error) {
+	_, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := raw_db.GetBuffer(config_obj, path)
+	if err != nil || len(data) == 0 {
+		return nil, notFoundError
+	}
+
+	result := &Library{}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func Get(config_obj *config_proto.Config, name string) (*Library, error) {
+	if !nameRegex.MatchString(name) {
+		return nil, fmt.Errorf("Invalid VQL library name %v", name)
+	}
+
+	result, err := getLibrary(config_obj, paths.VQLLibraryPath(name))
+	if errors.Is(err, notFoundError) {
+		return nil, fmt.Errorf("%w: %v", notFoundError, name)
+	}
+	return result, err
+}
+
+// Store a new version of the library. Setting the same VQL as the
+// latest version only updates the description.
+func Set(ctx context.Context, config_obj *config_proto.Config,
+	principal, name, description, vql string) (*Library, error) {
+	if !nameRegex.MatchString(name) {
+		return nil, fmt.Errorf("Invalid VQL library name %v", name)
+	}
+
+	err := validateVQL(vql)
+	if err != nil {
+		return nil, err
+	}
+
+	library, err := Get(config_obj, name)
+	if errors.Is(err, notFoundError) {
+		library = &Library{Name: name}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if description != "" {
+		library.Description = description
+	}
+
+	latest := library.Latest()
+	if latest == nil || latest.VQL != vql {
+		version := &Version{
+			Version:   int64(len(library.Versions) + 1),
+			VQL:       vql,
+			Principal: principal,
+			Created:   utils.GetTime().Now().Unix(),
+		}
+		library.Versions = append(library.Versions, version)
+	}
+
+	serialized, err := json.Marshal(library)
+	if err != nil {
+		return nil, err
+	}
+
+	_, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	err = raw_db.SetBuffer(config_obj, paths.VQLLibraryPath(name),
+		serialized, utils.SyncCompleter)
+	if err != nil {
+		return nil, err
+	}
+
+	return library, services.LogAudit(ctx, config_obj, principal,
+		"VQLLibrarySet", ordereddict.NewDict().
+			Set("name", name).
+			Set("version", library.Latest().Version))
+}
+
+// Delete the library with all its versions. Artifacts importing it
+// will fail to compile.
+func Delete(ctx context.Context, config_obj *config_proto.Config,
+	principal, name string) error {
+	_, err := Get(config_obj, name)
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	err = db.DeleteSubject(config_obj, paths.VQLLibraryPath(name))
+	if err != nil {
+		return err
+	}
+
+	return services.LogAudit(ctx, config_obj, principal,
+		"VQLLibraryDelete", ordereddict.NewDict().
+			Set("name", name))
+}
+
+// List all libraries sorted by name.
+func List(config_obj *config_proto.Config) ([]*Library, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Library{}
+	err = datastore.Walk(config_obj, db, paths.VQL_LIBRARIES_ROOT,
+		datastore.WalkWithoutDirectories,
+		func(path api.DSPathSpec) error {
+			library, err := getLibrary(config_obj, path)
+			if err == nil {
+				result = append(result, library)
+			}
+			return nil
+		})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, err
+}
+
+// Get the VQL for an import of a library, either pinned to a
+// version or the latest version.
+func GetImport(config_obj *config_proto.Config,
+	imported string) (string, error) {
+	name, version, err := ParseImport(imported)
+	if err != nil {
+		return "", err
+	}
+
+	library, err := Get(config_obj, name)
+	if err != nil {
+		return "", err
+	}
+
+	if version == 0 {
+		latest := library.Latest()
+		if latest == nil {
+			return "", fmt.Errorf("%w: %v", notFoundError, name)
+		}
+		return latest.VQL, nil
+	}
+
+	v, err := library.GetVersion(version)
+	if err != nil {
+		return "", err
+	}
+	return v.VQL, nil
+}
+
+// Resolve an artifact's import to the VQL it imports. Unversioned
+// imports of known artifacts import the artifact's export section,
+// anything else names a library.
+func ResolveImport(ctx context.Context, config_obj *config_proto.Config,
+	repository services.Repository, imported string) (string, error) {
+	if !strings.Contains(imported, "@") {
+		artifact, pres := repository.Get(ctx, config_obj, imported)
+		if pres {
+			return artifact.Export, nil
+		}
+	}
+
+	return GetImport(config_obj, imported)
+}
+
+// Is the import a library rather than an artifact?
+func IsLibraryImport(ctx context.Context, config_obj *config_proto.Config,
+	repository services.Repository, imported string) bool {
+	if !strings.Contains(imported, "@") {
+		_, pres := repository.Get(ctx, config_obj, imported)
+		if pres {
+			return false
+		}
+	}
+
+	_, err := GetImport(config_obj, imported)
+	return err == nil
+}
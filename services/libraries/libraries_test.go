@@ -0,0 +1,148 @@
+package libraries_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/libraries"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+var importingArtifacts = []string{`
+name: Custom.PinnedImport
+imports:
+  - Custom.Parsers@1
+sources:
+  - query: SELECT Parse(X=1) FROM scope()
+`, `
+name: Custom.LatestImport
+imports:
+  - Custom.Parsers
+sources:
+  - query: SELECT Parse(X=1) FROM scope()
+`}
+
+type LibrariesTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *LibrariesTestSuite) TestVersions() {
+	library, err := libraries.Set(self.Ctx, self.ConfigObj, "admin",
+		"Custom.Parsers", "Parsing helpers", "LET Parse(X) = X + 1")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(1), library.Latest().Version)
+
+	// Setting the same VQL does not create a new version.
+	library, err = libraries.Set(self.Ctx, self.ConfigObj, "admin",
+		"Custom.Parsers", "", "LET Parse(X) = X + 1")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(library.Versions))
+	assert.Equal(self.T(), "Parsing helpers", library.Description)
+
+	library, err = libraries.Set(self.Ctx, self.ConfigObj, "admin",
+		"Custom.Parsers", "", "LET Parse(X) = X + 2")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(2), library.Latest().Version)
+
+	// Imports may be pinned to a version.
+	vql, err := libraries.GetImport(self.ConfigObj, "Custom.Parsers@1")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "LET Parse(X) = X + 1", vql)
+
+	vql, err = libraries.GetImport(self.ConfigObj, "Custom.Parsers")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "LET Parse(X) = X + 2", vql)
+
+	_, err = libraries.GetImport(self.ConfigObj, "Custom.Parsers@3")
+	assert.Error(self.T(), err)
+
+	all, err := libraries.List(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(all))
+
+	err = libraries.Delete(self.Ctx, self.ConfigObj, "admin", "Custom.Parsers")
+	assert.NoError(self.T(), err)
+
+	_, err = libraries.GetImport(self.ConfigObj, "Custom.Parsers")
+	assert.Error(self.T(), err)
+}
+
+func (self *LibrariesTestSuite) TestValidation() {
+	// Libraries may only define things.
+	_, err := libraries.Set(self.Ctx, self.ConfigObj, "admin",
+		"Custom.Bad", "", "SELECT * FROM info()")
+	assert.Error(self.T(), err)
+
+	_, err = libraries.Set(self.Ctx, self.ConfigObj, "admin",
+		"Custom.Bad", "", "LET X = SELECT * FROM")
+	assert.Error(self.T(), err)
+
+	_, err = libraries.Set(self.Ctx, self.ConfigObj, "admin",
+		"../Bad", "", "LET X = 1")
+	assert.Error(self.T(), err)
+
+	_, _, err = libraries.ParseImport("Custom.Parsers@0")
+	assert.Error(self.T(), err)
+}
+
+func (self *LibrariesTestSuite) TestCompileImports() {
+	repository := self.LoadArtifacts(importingArtifacts...)
+
+	_, err := libraries.Set(self.Ctx, self.ConfigObj, "admin",
+		"Custom.Parsers", "", "LET Parse(X) = X + 1")
+	assert.NoError(self.T(), err)
+
+	_, err = libraries.Set(self.Ctx, self.ConfigObj, "admin",
+		"Custom.Parsers", "", "LET Parse(X) = X + 2")
+	assert.NoError(self.T(), err)
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	for _, tc := range []struct {
+		artifact string
+		expected string
+	}{
+		{"Custom.PinnedImport", "LET Parse(X) = X + 1"},
+		{"Custom.LatestImport", "LET Parse(X) = X + 2"},
+	} {
+		compiled, err := launcher.CompileCollectorArgs(
+			self.Ctx, self.ConfigObj, acl_managers.NullACLManager{},
+			repository, services.CompilerOptions{},
+			&flows_proto.ArtifactCollectorArgs{
+				ClientId:  "C.1234",
+				Artifacts: []string{tc.artifact},
+			})
+		assert.NoError(self.T(), err)
+
+		queries := []string{}
+		for _, q := range compiled[0].Query {
+			queries = append(queries, q.VQL)
+		}
+		assert.Contains(self.T(), queries, tc.expected)
+	}
+
+	// Unknown libraries fail to compile.
+	_, err = launcher.CompileCollectorArgs(
+		self.Ctx, self.ConfigObj, acl_managers.NullACLManager{},
+		self.LoadArtifacts(`
+name: Custom.MissingImport
+imports:
+  - Custom.Missing@1
+sources:
+  - query: SELECT * FROM scope()
+`), services.CompilerOptions{},
+		&flows_proto.ArtifactCollectorArgs{
+			ClientId:  "C.1234",
+			Artifacts: []string{"Custom.MissingImport"},
+		})
+	assert.Error(self.T(), err)
+}
+
+func TestLibraries(t *testing.T) {
+	suite.Run(t, &LibrariesTestSuite{})
+}
@@ -0,0 +1,239 @@
+/*
+  Masking policies hide sensitive data (e.g. credit card numbers or
+  user names in paths) in results viewed or exported by users without
+  the VIEW_PII permission.
+
+  Each policy applies to the artifacts matching its artifact regex (or
+  all results if not set). Like the auditor redaction rules a policy
+  may mask entire columns, or only parts of values matching a
+  pattern.
+
+  Policies are stored in the datastore so they apply on every
+  frontend without a running service.
+*/
+
+package masking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	notFoundError = errors.New("Masking policy not found")
+
+	nameRegex = regexp.MustCompile("^[a-zA-Z0-9_][a-zA-Z0-9_.-]*$")
+)
+
+type Policy struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// A regex selecting the artifacts the policy applies to. If not
+	// set the policy applies to all results.
+	Artifact string `json:"artifact,omitempty"`
+
+	// Mask columns matching this regex. If not set all columns are
+	// masked.
+	Column string `json:"column,omitempty"`
+
+	// Only mask the parts of values matching this regex. If not set
+	// the entire value is masked.
+	Pattern string `json:"pattern,omitempty"`
+
+	// Replace the masked data with this (default "[REDACTED]").
+	Replacement string `json:"replacement,omitempty"`
+
+	Principal string `json:"principal,omitempty"`
+	Modified  int64  `json:"modified"`
+}
+
+func (self *Policy) Validate() error {
+	if !nameRegex.MatchString(self.Name) {
+		return fmt.Errorf("Invalid masking policy name %v", self.Name)
+	}
+
+	if self.Column == "" && self.Pattern == "" {
+		return errors.New("Masking policies need a column or a pattern")
+	}
+
+	for _, re := range []string{self.Artifact, self.Column, self.Pattern} {
+		_, err := regexp.Compile(re)
+		if err != nil {
+			return fmt.Errorf("Masking policy %v: %w", self.Name, err)
+		}
+	}
+	return nil
+}
+
+// Does the policy apply to the results of this artifact?
+func (self *Policy) AppliesTo(artifact string) bool {
+	if self.Artifact == "" {
+		return true
+	}
+
+	if artifact == "" {
+		return false
+	}
+
+	re, err := regexp.Compile(self.Artifact)
+	return err == nil && re.MatchString(artifact)
+}
+
+func getRawDB(config_obj *config_proto.Config) (
+	datastore.DataStore, datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, nil, errors.New("Datastore does not support raw access")
+	}
+	return db, raw_db, nil
+}
+
+func getPolicy(config_obj *config_proto.Config,
+	path api.DSPathSpec) (*Policy, error) {
+	_, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := raw_db.GetBuffer(config_obj, path)
+	if err != nil || len(data) == 0 {
+		return nil, notFoundError
+	}
+
+	result := &Policy{}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func Get(config_obj *config_proto.Config, name string) (*Policy, error) {
+	if !nameRegex.MatchString(name) {
+		return nil, fmt.Errorf("Invalid masking policy name %v", name)
+	}
+
+	result, err := getPolicy(config_obj, paths.MaskingPolicyPath(name))
+	if errors.Is(err, notFoundError) {
+		return nil, fmt.Errorf("%w: %v", notFoundError, name)
+	}
+	return result, err
+}
+
+// Create or replace a policy.
+func Set(ctx context.Context, config_obj *config_proto.Config,
+	principal string, policy *Policy) error {
+	err := policy.Validate()
+	if err != nil {
+		return err
+	}
+
+	policy.Principal = principal
+	policy.Modified = utils.GetTime().Now().Unix()
+
+	serialized, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	_, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	err = raw_db.SetBuffer(config_obj, paths.MaskingPolicyPath(policy.Name),
+		serialized, utils.SyncCompleter)
+	if err != nil {
+		return err
+	}
+
+	return services.LogAudit(ctx, config_obj, principal,
+		"MaskingPolicySet", ordereddict.NewDict().
+			Set("policy", policy))
+}
+
+func Delete(ctx context.Context, config_obj *config_proto.Config,
+	principal, name string) error {
+	_, err := Get(config_obj, name)
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	err = db.DeleteSubject(config_obj, paths.MaskingPolicyPath(name))
+	if err != nil {
+		return err
+	}
+
+	return services.LogAudit(ctx, config_obj, principal,
+		"MaskingPolicyDelete", ordereddict.NewDict().
+			Set("name", name))
+}
+
+// List all policies sorted by name.
+func List(config_obj *config_proto.Config) ([]*Policy, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Policy{}
+	err = datastore.Walk(config_obj, db, paths.MASKING_POLICIES_ROOT,
+		datastore.WalkWithoutDirectories,
+		func(path api.DSPathSpec) error {
+			policy, err := getPolicy(config_obj, path)
+			if err == nil {
+				result = append(result, policy)
+			}
+			return nil
+		})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, err
+}
+
+// Get the redaction rules applying to the results of the artifact.
+func GetRules(config_obj *config_proto.Config,
+	artifact string) ([]*config_proto.RedactionRule, error) {
+	policies, err := List(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*config_proto.RedactionRule{}
+	for _, policy := range policies {
+		if policy.AppliesTo(artifact) {
+			result = append(result, &config_proto.RedactionRule{
+				Column:      policy.Column,
+				Pattern:     policy.Pattern,
+				Replacement: policy.Replacement,
+			})
+		}
+	}
+	return result, nil
+}
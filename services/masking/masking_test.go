@@ -0,0 +1,109 @@
+package masking_test
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	acl_proto "www.velocidex.com/golang/velociraptor/acls/proto"
+	"www.velocidex.com/golang/velociraptor/api/tables"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/masking"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type MaskingTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *MaskingTestSuite) TestPolicies() {
+	for _, policy := range []*masking.Policy{{
+		Name:    "CreditCards",
+		Pattern: `\b(?:\d[ -]?){13,16}\b`,
+	}, {
+		Name:        "HomeDirectories",
+		Artifact:    "FileFinder",
+		Column:      "^OSPath$",
+		Pattern:     `/home/[^/]+`,
+		Replacement: "/home/<user>",
+	}} {
+		err := masking.Set(self.Ctx, self.ConfigObj, "admin", policy)
+		assert.NoError(self.T(), err)
+	}
+
+	policies, err := masking.List(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(policies))
+
+	// Only policies for all results apply to other artifacts.
+	rules, err := masking.GetRules(self.ConfigObj, "Linux.Sys.Users")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(rules))
+
+	rules, err = masking.GetRules(self.ConfigObj, "Linux.Search.FileFinder")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(rules))
+
+	// Policies must mask something.
+	err = masking.Set(self.Ctx, self.ConfigObj, "admin",
+		&masking.Policy{Name: "Empty"})
+	assert.Error(self.T(), err)
+
+	err = masking.Set(self.Ctx, self.ConfigObj, "admin",
+		&masking.Policy{Name: "Invalid", Column: "(invalid"})
+	assert.Error(self.T(), err)
+
+	err = masking.Delete(self.Ctx, self.ConfigObj, "admin", "CreditCards")
+	assert.NoError(self.T(), err)
+
+	rules, err = masking.GetRules(self.ConfigObj, "Linux.Sys.Users")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(rules))
+}
+
+func (self *MaskingTestSuite) TestRedactor() {
+	err := masking.Set(self.Ctx, self.ConfigObj, "admin", &masking.Policy{
+		Name:        "HomeDirectories",
+		Artifact:    "FileFinder",
+		Column:      "^OSPath$",
+		Pattern:     `/home/[^/]+`,
+		Replacement: "/home/<user>",
+	})
+	assert.NoError(self.T(), err)
+
+	for _, user := range []string{"reader", "investigator"} {
+		err = services.SetPolicy(self.ConfigObj, user,
+			&acl_proto.ApiClientACL{Roles: []string{user}})
+		assert.NoError(self.T(), err)
+	}
+
+	row := ordereddict.NewDict().
+		Set("OSPath", "/home/mike/.bash_history").
+		Set("Size", 10)
+
+	// Readers may not view PII.
+	redactor, err := tables.GetRedactor(
+		self.ConfigObj, "reader", "Linux.Search.FileFinder")
+	assert.NoError(self.T(), err)
+	assert.NotNil(self.T(), redactor)
+
+	value, _ := redactor.RedactRow(row).Get("OSPath")
+	assert.Equal(self.T(), "/home/<user>/.bash_history", value)
+
+	// The policy does not apply to other artifacts.
+	redactor, err = tables.GetRedactor(
+		self.ConfigObj, "reader", "Linux.Sys.Users")
+	assert.NoError(self.T(), err)
+	assert.Nil(self.T(), redactor)
+
+	// Investigators see the full results.
+	redactor, err = tables.GetRedactor(
+		self.ConfigObj, "investigator", "Linux.Search.FileFinder")
+	assert.NoError(self.T(), err)
+	assert.Nil(self.T(), redactor)
+}
+
+func TestMasking(t *testing.T) {
+	suite.Run(t, &MaskingTestSuite{})
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 
+	"github.com/Velocidex/ordereddict"
+
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 )
@@ -58,4 +60,22 @@ type NotebookManager interface {
 
 	RemoveNotebookAttachment(ctx context.Context,
 		notebook_id string, components []string) error
+
+	// The VQL the user ran in notebook cells, most recent first.
+	QueryHistory(ctx context.Context, username string,
+		options QueryHistoryOptions) ([]*ordereddict.Dict, error)
+}
+
+type QueryHistoryOptions struct {
+	// Only return queries matching this regex.
+	Search string
+
+	// Only return queries run in this time range (Unix seconds).
+	Start, End int64
+
+	// Only return the entry with this id.
+	Id string
+
+	// Return at most this many entries (all if 0).
+	Limit int
 }
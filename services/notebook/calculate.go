@@ -7,6 +7,7 @@ import (
 
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/services"
 )
 
@@ -76,6 +77,14 @@ func (self *NotebookManager) UpdateNotebookCell(
 			return nil, err
 		}
 
+		// Keep a record of the query in the user's history.
+		err = self.recordQuery(ctx, notebook_metadata, user_name,
+			notebook_resp.NotebookCell)
+		if err != nil {
+			logger := logging.GetLogger(self.config_obj, &logging.GUIComponent)
+			logger.Error("NotebookManager: Unable to record query history: %v", err)
+		}
+
 		return notebook_resp.NotebookCell, job_resp.Err
 	}
 }
@@ -0,0 +1,151 @@
+package notebook
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// A snapshot of the results is kept with each query so the
+	// history is useful even after the cell is recalculated.
+	HISTORY_SNAPSHOT_TABLES = 5
+	HISTORY_SNAPSHOT_ROWS   = 10
+)
+
+// Record a VQL cell calculation in the user's query history.
+func (self *NotebookManager) recordQuery(ctx context.Context,
+	notebook_metadata *api_proto.NotebookMetadata,
+	user_name string, cell *api_proto.NotebookCell) error {
+	if cell == nil || strings.ToLower(cell.Type) != "vql" {
+		return nil
+	}
+
+	cell_path_manager := paths.NewNotebookPathManager(
+		notebook_metadata.NotebookId).Cell(cell.CellId)
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+
+	// The cell's results are stored in one table per query.
+	snapshot := []interface{}{}
+	for i := int64(1); i <= HISTORY_SNAPSHOT_TABLES; i++ {
+		path := cell_path_manager.QueryStorage(i).Path()
+		_, err := file_store_factory.StatFile(path)
+		if err != nil {
+			break
+		}
+
+		reader, err := result_sets.NewResultSetReader(file_store_factory, path)
+		if err != nil {
+			break
+		}
+
+		rows := []*ordereddict.Dict{}
+		for row := range reader.Rows(ctx) {
+			rows = append(rows, row)
+			if len(rows) >= HISTORY_SNAPSHOT_ROWS {
+				break
+			}
+		}
+
+		snapshot = append(snapshot, ordereddict.NewDict().
+			Set("TotalRows", reader.TotalRows()).
+			Set("Rows", rows))
+		reader.Close()
+	}
+
+	entry := ordereddict.NewDict().
+		Set("Id", NewQueryHistoryId()).
+		Set("Timestamp", utils.GetTime().Now().Unix()).
+		Set("User", user_name).
+		Set("NotebookId", notebook_metadata.NotebookId).
+		Set("NotebookName", notebook_metadata.Name).
+		Set("CellId", cell.CellId).
+		Set("Query", cell.Input).
+		Set("Env", cell.Env).
+		Set("Duration", cell.Duration).
+		Set("Error", cell.Error).
+		Set("Results", snapshot)
+
+	writer, err := result_sets.NewResultSetWriter(
+		file_store_factory, paths.NewUserPathManager(user_name).QueryHistory(),
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.AppendMode)
+	if err != nil {
+		return err
+	}
+	writer.Write(entry)
+	writer.Close()
+
+	return nil
+}
+
+func (self *NotebookManager) QueryHistory(ctx context.Context,
+	username string,
+	options services.QueryHistoryOptions) ([]*ordereddict.Dict, error) {
+	var search *regexp.Regexp
+	if options.Search != "" {
+		var err error
+		search, err = regexp.Compile("(?i)" + options.Search)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(file_store_factory,
+		paths.NewUserPathManager(username).QueryHistory())
+	if err != nil {
+		// No queries run yet.
+		return nil, nil
+	}
+	defer reader.Close()
+
+	result := []*ordereddict.Dict{}
+	for row := range reader.Rows(ctx) {
+		if options.Id != "" {
+			id, _ := row.GetString("Id")
+			if id != options.Id {
+				continue
+			}
+		}
+
+		timestamp, _ := row.GetInt64("Timestamp")
+		if options.Start > 0 && timestamp < options.Start {
+			continue
+		}
+
+		if options.End > 0 && timestamp >= options.End {
+			continue
+		}
+
+		if search != nil {
+			query, _ := row.GetString("Query")
+			if !search.MatchString(query) {
+				continue
+			}
+		}
+
+		result = append(result, row)
+	}
+
+	// Entries are appended so the most recent are last.
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	if options.Limit > 0 && len(result) > options.Limit {
+		result = result[:options.Limit]
+	}
+
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package notebook_test
+
+import (
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/alecthomas/assert"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+func (self *NotebookManagerTestSuite) TestQueryHistory() {
+	clock := utils.NewMockClock(time.Unix(100, 0))
+	closer := utils.MockTime(clock)
+	defer closer()
+
+	notebook_manager, err := services.GetNotebookManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	var notebook *api_proto.NotebookMetadata
+	vtesting.WaitUntil(2*time.Second, self.T(), func() bool {
+		notebook, err = notebook_manager.NewNotebook(self.Ctx, "admin",
+			&api_proto.NotebookMetadata{Name: "History"})
+		return err == nil
+	})
+
+	for i, query := range []string{
+		"SELECT _value AS X FROM range(end=20)",
+		"SELECT * FROM info()",
+		"SELECT _value AS Y FROM range(end=3)",
+	} {
+		clock.Set(time.Unix(int64(200+i*100), 0))
+		_, err = notebook_manager.UpdateNotebookCell(self.Ctx, notebook,
+			"admin", &api_proto.NotebookCellRequest{
+				NotebookId: notebook.NotebookId,
+				CellId:     notebook.CellMetadata[0].CellId,
+				Input:      query,
+				Type:       "VQL",
+			})
+		assert.NoError(self.T(), err)
+	}
+
+	// Markdown cells are not recorded.
+	_, err = notebook_manager.UpdateNotebookCell(self.Ctx, notebook,
+		"admin", &api_proto.NotebookCellRequest{
+			NotebookId: notebook.NotebookId,
+			CellId:     notebook.CellMetadata[0].CellId,
+			Input:      "# Heading",
+			Type:       "Markdown",
+		})
+	assert.NoError(self.T(), err)
+
+	history, err := notebook_manager.QueryHistory(self.Ctx, "admin",
+		services.QueryHistoryOptions{})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, len(history))
+
+	// Most recent first.
+	query, _ := history[0].GetString("Query")
+	assert.Equal(self.T(), "SELECT _value AS Y FROM range(end=3)", query)
+
+	// A snapshot of the results is kept.
+	first := history[2]
+	results, _ := first.Get("Results")
+	tables, ok := results.([]interface{})
+	assert.True(self.T(), ok)
+	assert.Equal(self.T(), 1, len(tables))
+
+	table := tables[0].(*ordereddict.Dict)
+	total, _ := table.GetInt64("TotalRows")
+	assert.Equal(self.T(), int64(20), total)
+
+	rows, _ := table.Get("Rows")
+	assert.Equal(self.T(), 10, len(rows.([]interface{})))
+
+	// Search by regex and time.
+	history, err = notebook_manager.QueryHistory(self.Ctx, "admin",
+		services.QueryHistoryOptions{Search: "RANGE"})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(history))
+
+	history, err = notebook_manager.QueryHistory(self.Ctx, "admin",
+		services.QueryHistoryOptions{Search: "range", Start: 250, Limit: 5})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(history))
+
+	// Look up by id.
+	id, _ := first.GetString("Id")
+	history, err = notebook_manager.QueryHistory(self.Ctx, "admin",
+		services.QueryHistoryOptions{Id: id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(history))
+
+	// Other users have their own history.
+	history, err = notebook_manager.QueryHistory(self.Ctx, "bob",
+		services.QueryHistoryOptions{})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(history))
+}
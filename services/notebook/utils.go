@@ -58,3 +58,15 @@ func NewNotebookAttachmentId() string {
 
 	return "NA." + result
 }
+
+// History ids are always random since history entries are looked up
+// by id.
+func NewQueryHistoryId() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+
+	binary.BigEndian.PutUint32(buf, uint32(utils.GetTime().Now().Unix()))
+	result := base32.HexEncoding.EncodeToString(buf)[:13]
+
+	return "NQ." + result
+}
@@ -13,11 +13,19 @@ import (
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/acl_manager"
 	"www.velocidex.com/golang/velociraptor/services/audit_manager"
+	"www.velocidex.com/golang/velociraptor/services/availability"
+	"www.velocidex.com/golang/velociraptor/services/approvals"
+	"www.velocidex.com/golang/velociraptor/services/asset_inventory"
+	"www.velocidex.com/golang/velociraptor/services/baselines"
 	"www.velocidex.com/golang/velociraptor/services/broadcast"
+	"www.velocidex.com/golang/velociraptor/services/canaries"
 	"www.velocidex.com/golang/velociraptor/services/client_info"
 	"www.velocidex.com/golang/velociraptor/services/client_monitoring"
 	"www.velocidex.com/golang/velociraptor/services/ddclient"
+	"www.velocidex.com/golang/velociraptor/services/edr"
+	"www.velocidex.com/golang/velociraptor/services/event_compaction"
 	"www.velocidex.com/golang/velociraptor/services/frontend"
+	"www.velocidex.com/golang/velociraptor/services/geoip"
 	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
 	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
 	"www.velocidex.com/golang/velociraptor/services/indexing"
@@ -28,13 +36,20 @@ import (
 	"www.velocidex.com/golang/velociraptor/services/launcher"
 	"www.velocidex.com/golang/velociraptor/services/notebook"
 	"www.velocidex.com/golang/velociraptor/services/notifications"
+	"www.velocidex.com/golang/velociraptor/services/playbooks"
+	"www.velocidex.com/golang/velociraptor/services/recompression"
 	"www.velocidex.com/golang/velociraptor/services/repository"
+	"www.velocidex.com/golang/velociraptor/services/result_set_indexer"
 	"www.velocidex.com/golang/velociraptor/services/sanity"
 	"www.velocidex.com/golang/velociraptor/services/scheduler"
 	"www.velocidex.com/golang/velociraptor/services/server_artifacts"
 	"www.velocidex.com/golang/velociraptor/services/server_monitoring"
+	"www.velocidex.com/golang/velociraptor/services/syslog_forwarder"
+	"www.velocidex.com/golang/velociraptor/services/taxii"
 	"www.velocidex.com/golang/velociraptor/services/users"
 	"www.velocidex.com/golang/velociraptor/services/vfs_service"
+	"www.velocidex.com/golang/velociraptor/services/vulnerabilities"
+	"www.velocidex.com/golang/velociraptor/services/webhooks"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
@@ -59,6 +74,7 @@ type ServiceContainer struct {
 	server_artifact_manager services.ServerArtifactRunner
 	notifier                services.Notifier
 	acl_manager             services.ACLManager
+	webhooks                services.WebhookService
 }
 
 func (self *ServiceContainer) MockFrontendManager(svc services.FrontendManager) {
@@ -264,6 +280,16 @@ func (self *ServiceContainer) ACLManager() (services.ACLManager, error) {
 	return self.acl_manager, nil
 }
 
+func (self *ServiceContainer) WebhookService() (services.WebhookService, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.webhooks == nil {
+		return nil, errors.New("Webhook service is not running on this node")
+	}
+	return self.webhooks, nil
+}
+
 // Start all the services for the org and install it in the
 // manager. This function is used both in the client and the server to
 // start all the needed services.
@@ -338,6 +364,25 @@ func (self *OrgManager) startRootOrgServices(
 		return err
 	}
 
+	// Only update the GeoIP databases, poll threat intel feeds and
+	// EDR alerts on the master node.
+	if spec.ServerArtifacts {
+		err = geoip.StartGeoIPUpdateService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+
+		err = taxii.StartTaxiiService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+
+		err = edr.StartEDRService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = datastore.StartDatastore(
 		ctx, wg, org_config)
 	if err != nil {
@@ -564,6 +609,14 @@ func (self *OrgManager) startOrgFromContext(org_ctx *OrgContext) (err error) {
 		service_container.mu.Unlock()
 	}
 
+	// Track client check ins for availability reporting.
+	if spec.ClientInfo {
+		err = availability.StartAvailabilityService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
 	if spec.IndexServer {
 		inv, err := indexing.NewIndexingService(ctx, wg, org_config)
 		if err != nil {
@@ -610,6 +663,15 @@ func (self *OrgManager) startOrgFromContext(org_ctx *OrgContext) (err error) {
 		service_container.mu.Unlock()
 	}
 
+	// Index large result sets for the GUI.
+	if spec.NotebookService {
+		err = result_set_indexer.StartResultSetIndexerService(
+			ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
 	if spec.ServerArtifacts {
 		sm, err := server_artifacts.NewServerArtifactService(ctx, wg, org_config)
 		if err != nil {
@@ -620,6 +682,20 @@ func (self *OrgManager) startOrgFromContext(org_ctx *OrgContext) (err error) {
 		service_container.mu.Unlock()
 	}
 
+	// Webhooks are delivered from the master node.
+	if spec.ServerArtifacts {
+		w, err := webhooks.StartWebhookService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+
+		if w != nil {
+			service_container.mu.Lock()
+			service_container.webhooks = w
+			service_container.mu.Unlock()
+		}
+	}
+
 	if spec.ClientMonitoring {
 		client_event_manager, err := client_monitoring.NewClientMonitoringService(ctx, wg, org_config)
 		if err != nil {
@@ -631,6 +707,52 @@ func (self *OrgManager) startOrgFromContext(org_ctx *OrgContext) (err error) {
 		service_container.mu.Unlock()
 	}
 
+	// Baseline designated artifacts on first collection.
+	if spec.Launcher {
+		err = baselines.StartBaselineService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Hold dangerous collections and large hunts for approval.
+	if spec.Launcher {
+		err = approvals.StartApprovalService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Record accesses to deployed canaries.
+	if spec.ClientMonitoring {
+		err = canaries.StartCanaryService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Keep the client inventories up to date and match them against
+	// the vulnerability databases.
+	if spec.Interrogation {
+		err = asset_inventory.StartAssetInventoryService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+
+		err = vulnerabilities.StartVulnerabilityService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Respond to detection events with the configured playbooks.
+	if spec.ClientMonitoring {
+		err = playbooks.StartPlaybookService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
 	if spec.MonitoringService {
 		server_event_manager, err := server_monitoring.NewServerMonitoringService(ctx, wg, org_config)
 		if err != nil {
@@ -653,6 +775,31 @@ func (self *OrgManager) startOrgFromContext(org_ctx *OrgContext) (err error) {
 		service_container.mu.Unlock()
 	}
 
+	// Forward events from the master node only so they are not
+	// duplicated by the minions.
+	if spec.ServerArtifacts {
+		err = syslog_forwarder.StartSyslogForwarderService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Compress cold files in the file store.
+	if spec.ServerArtifacts {
+		err = recompression.StartRecompressionService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Roll old client monitoring events into summaries.
+	if spec.ServerArtifacts {
+		err = event_compaction.StartEventCompactionService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Must be run after all the other services are up
 	if spec.SanityChecker {
 		err = sanity.NewSanityCheckService(ctx, wg, org_config)
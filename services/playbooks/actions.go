@@ -0,0 +1,252 @@
+package playbooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+// The artifacts the GUI uses to quarantine a client.
+var quarantineArtifacts = map[services.ClientOS]string{
+	services.Windows: "Windows.Remediation.Quarantine",
+	services.Linux:   "Linux.Remediation.Quarantine",
+}
+
+// Approve or deny a pending run. Approved runs are executed
+// immediately.
+func (self *PlaybookService) Decide(ctx context.Context,
+	principal, id string, approve bool) (*Run, error) {
+	now := utils.GetTime().Now()
+
+	self.mu.Lock()
+	run, pres := self.runs[id]
+	if !pres {
+		self.mu.Unlock()
+		return nil, notFoundError
+	}
+
+	self.expire(run, now)
+	if run.State != STATE_PENDING {
+		self.mu.Unlock()
+		return nil, fmt.Errorf("Run %v is %v", id, run.State)
+	}
+
+	playbook, pres := self.playbooks[run.Playbook]
+	if !pres {
+		self.mu.Unlock()
+		return nil, fmt.Errorf("Unknown playbook %v", run.Playbook)
+	}
+
+	run.Approver = principal
+	run.Decided = now.Unix()
+
+	// Mark the run so it is not approved twice.
+	run.State = STATE_RUNNING
+	operation := "PlaybookRunApprove"
+	if !approve {
+		operation = "PlaybookRunDeny"
+		run.State = STATE_DENIED
+	}
+	err := self.save(run)
+	self.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = services.LogAudit(ctx, self.config_obj, principal,
+		operation, ordereddict.NewDict().
+			Set("run_id", run.Id).
+			Set("playbook", run.Playbook).
+			Set("client_id", run.ClientId))
+	if err != nil {
+		return nil, err
+	}
+
+	if !approve {
+		return self.announce(ctx, copyRun(run))
+	}
+
+	return self.execute(ctx, playbook, id, principal)
+}
+
+// Take the playbook's actions on the run's client. Actions are taken
+// in order and stop at the first failure.
+func (self *PlaybookService) execute(ctx context.Context,
+	playbook *config_proto.PlaybookConfig,
+	id, principal string) (*Run, error) {
+	self.mu.Lock()
+	run, pres := self.runs[id]
+	if !pres {
+		self.mu.Unlock()
+		return nil, notFoundError
+	}
+	client_id := run.ClientId
+	self.mu.Unlock()
+
+	var flow_ids, labels []string
+	var err error
+
+	for _, action := range playbook.Actions {
+		switch action.Type {
+		case ACTION_COLLECT:
+			var flow_id string
+			flow_id, err = self.collect(ctx, principal, client_id,
+				action.Artifacts, action.Parameters)
+			if err == nil {
+				flow_ids = append(flow_ids, flow_id)
+			}
+
+		case ACTION_LABEL:
+			err = self.label(ctx, client_id, action.Label)
+			if err == nil {
+				labels = append(labels, action.Label)
+			}
+
+		case ACTION_QUARANTINE:
+			var flow_id string
+			flow_id, err = self.quarantine(ctx, principal, client_id)
+			if err == nil {
+				flow_ids = append(flow_ids, flow_id)
+				labels = append(labels, QUARANTINE_LABEL)
+			}
+		}
+
+		if err != nil {
+			err = fmt.Errorf("%v action: %w", action.Type, err)
+			break
+		}
+	}
+
+	self.mu.Lock()
+	run.FlowIds = flow_ids
+	run.Labels = labels
+	run.State = STATE_COMPLETED
+	if err != nil {
+		run.State = STATE_FAILED
+		run.Error = err.Error()
+	}
+	save_err := self.save(run)
+	result := copyRun(run)
+	self.mu.Unlock()
+
+	if save_err != nil {
+		return nil, save_err
+	}
+
+	audit_err := services.LogAudit(ctx, self.config_obj, principal,
+		"PlaybookRun", ordereddict.NewDict().
+			Set("run_id", result.Id).
+			Set("playbook", result.Playbook).
+			Set("client_id", client_id).
+			Set("state", result.State).
+			Set("flow_ids", flow_ids).
+			Set("labels", labels).
+			Set("error", result.Error))
+	if audit_err != nil {
+		return nil, audit_err
+	}
+
+	return self.announce(ctx, result)
+}
+
+func (self *PlaybookService) collect(ctx context.Context,
+	principal, client_id string,
+	artifacts, parameters []string) (string, error) {
+	manager, err := services.GetRepositoryManager(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	launcher, err := services.GetLauncher(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	env := []*actions_proto.VQLEnv{}
+	for _, parameter := range parameters {
+		parts := strings.SplitN(parameter, "=", 2)
+		if len(parts) != 2 {
+			return "", fmt.Errorf("Invalid parameter %q", parameter)
+		}
+		env = append(env, &actions_proto.VQLEnv{Key: parts[0], Value: parts[1]})
+	}
+
+	request := &flows_proto.ArtifactCollectorArgs{
+		Creator:   principal,
+		ClientId:  client_id,
+		Artifacts: artifacts,
+		Urgent:    true,
+	}
+
+	for _, artifact := range artifacts {
+		request.Specs = append(request.Specs, &flows_proto.ArtifactSpec{
+			Artifact:   artifact,
+			Parameters: &flows_proto.ArtifactParameters{Env: env},
+		})
+	}
+
+	// Playbooks are set by the administrator in the config file
+	// so are not subject to ACL checks.
+	return launcher.ScheduleArtifactCollection(
+		ctx, self.config_obj, acl_managers.NullACLManager{},
+		repository, request, func() {
+			notifier, err := services.GetNotifier(self.config_obj)
+			if err == nil {
+				notifier.NotifyListener(ctx,
+					self.config_obj, client_id, "Playbook")
+			}
+		})
+}
+
+func (self *PlaybookService) label(ctx context.Context,
+	client_id, label string) error {
+	labeler := services.GetLabeler(self.config_obj)
+	if labeler == nil {
+		return errors.New("Labeler not available")
+	}
+
+	return labeler.SetClientLabel(ctx, self.config_obj, client_id, label)
+}
+
+// Quarantine the client the same way the GUI does: label it and
+// collect the quarantine artifact for its OS.
+func (self *PlaybookService) quarantine(ctx context.Context,
+	principal, client_id string) (string, error) {
+	client_info_manager, err := services.GetClientInfoManager(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := client_info_manager.Get(ctx, client_id)
+	if err != nil {
+		return "", err
+	}
+
+	artifact, pres := quarantineArtifacts[info.OS()]
+	if !pres {
+		return "", fmt.Errorf("Unable to quarantine client %v with OS %q",
+			client_id, info.System)
+	}
+
+	err = self.label(ctx, client_id, QUARANTINE_LABEL)
+	if err != nil {
+		return "", err
+	}
+
+	return self.collect(ctx, principal, client_id, []string{artifact}, nil)
+}
@@ -0,0 +1,599 @@
+/*
+  The playbook service responds automatically to detection events.
+
+  Each playbook in the config (Defaults.playbooks) watches an event
+  artifact - a client monitoring artifact, or a server queue such as
+  Server.Internal.EDRAlerts. When an event matches the playbook's
+  condition the affected client is found (from the event's client id
+  column, or by hostname for events raised outside Velociraptor) and
+  the playbook's actions are taken on it: collecting artifacts,
+  labeling the client or quarantining it.
+
+  Responses are rate limited per client and per playbook so a burst of
+  events does not launch a flood of collections. Playbooks may require
+  a user to approve each response before it is taken.
+
+  Every response is recorded as a run in the datastore, announced on
+  the Server.Internal.PlaybookRuns queue and written to the audit log.
+*/
+
+package playbooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	ACTION_COLLECT    = "collect"
+	ACTION_LABEL      = "label"
+	ACTION_QUARANTINE = "quarantine"
+
+	STATE_PENDING   = "pending"
+	STATE_RUNNING   = "running"
+	STATE_COMPLETED = "completed"
+	STATE_FAILED    = "failed"
+	STATE_DENIED    = "denied"
+	STATE_EXPIRED   = "expired"
+
+	// Runs are announced on this queue.
+	RUNS_ARTIFACT = "Server.Internal.PlaybookRuns"
+
+	// The label the GUI uses for quarantined clients.
+	QUARANTINE_LABEL = "Quarantine"
+
+	// In seconds
+	DEFAULT_CLIENT_COOLDOWN = 3600
+	DEFAULT_APPROVAL_EXPIRY = 86400
+
+	DEFAULT_MAX_RUNS_PER_HOUR = 10
+)
+
+var (
+	mu         sync.Mutex
+	g_services = make(map[string]*PlaybookService)
+
+	notRunningError = errors.New("Playbook service not running")
+	notFoundError   = errors.New("Playbook run not found")
+)
+
+// A response to an event.
+type Run struct {
+	Id       string `json:"id"`
+	Playbook string `json:"playbook"`
+	ClientId string `json:"client_id"`
+
+	// The event that triggered the run.
+	Event *ordereddict.Dict `json:"event,omitempty"`
+
+	State   string `json:"state"`
+	Created int64  `json:"created"`
+
+	// The user who approved or denied the run.
+	Approver string `json:"approver,omitempty"`
+	Decided  int64  `json:"decided,omitempty"`
+
+	FlowIds []string `json:"flow_ids,omitempty"`
+	Labels  []string `json:"labels,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+type PlaybookService struct {
+	mu         sync.Mutex
+	config_obj *config_proto.Config
+
+	playbooks map[string]*config_proto.PlaybookConfig
+	runs      map[string]*Run
+
+	// Parsed conditions by playbook name.
+	conditions map[string]*vfilter.Lambda
+}
+
+// Get the playbook service for the org.
+func GetPlaybookService(
+	config_obj *config_proto.Config) (*PlaybookService, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result, pres := g_services[utils.NormalizedOrgId(config_obj.OrgId)]
+	if !pres {
+		return nil, notRunningError
+	}
+	return result, nil
+}
+
+func NewPlaybookService(config_obj *config_proto.Config) (*PlaybookService, error) {
+	self := &PlaybookService{
+		config_obj: config_obj,
+		playbooks:  make(map[string]*config_proto.PlaybookConfig),
+		runs:       make(map[string]*Run),
+		conditions: make(map[string]*vfilter.Lambda),
+	}
+
+	for _, playbook := range config_obj.GetDefaults().GetPlaybooks() {
+		if playbook.Name == "" || playbook.Artifact == "" {
+			return nil, errors.New("Playbooks must have a name and artifact")
+		}
+
+		_, pres := self.playbooks[playbook.Name]
+		if pres {
+			return nil, fmt.Errorf("Duplicate playbook %v", playbook.Name)
+		}
+
+		for _, action := range playbook.Actions {
+			switch action.Type {
+			case ACTION_COLLECT:
+				if len(action.Artifacts) == 0 {
+					return nil, fmt.Errorf(
+						"Playbook %v: collect action has no artifacts",
+						playbook.Name)
+				}
+			case ACTION_LABEL:
+				if action.Label == "" {
+					return nil, fmt.Errorf(
+						"Playbook %v: label action has no label",
+						playbook.Name)
+				}
+			case ACTION_QUARANTINE:
+			default:
+				return nil, fmt.Errorf("Playbook %v: Unknown action %q",
+					playbook.Name, action.Type)
+			}
+		}
+
+		if playbook.Condition != "" {
+			lambda, err := vfilter.ParseLambda(playbook.Condition)
+			if err != nil {
+				return nil, fmt.Errorf("Playbook %v: condition: %w",
+					playbook.Name, err)
+			}
+			self.conditions[playbook.Name] = lambda
+		}
+
+		self.playbooks[playbook.Name] = playbook
+	}
+
+	return self, nil
+}
+
+func newRunId() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "PB." + base32.HexEncoding.EncodeToString(buf)[:13]
+}
+
+func getRawDB(config_obj *config_proto.Config) (
+	datastore.DataStore, datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, nil, errors.New("Datastore does not support raw access")
+	}
+	return db, raw_db, nil
+}
+
+// Load all runs from the datastore. The rate limits take previous
+// runs into account so they hold across restarts.
+func (self *PlaybookService) Load() error {
+	db, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	children, err := db.ListChildren(self.config_obj, paths.PLAYBOOK_RUNS_ROOT)
+	if err != nil {
+		return err
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		data, err := raw_db.GetBuffer(self.config_obj, child)
+		if err != nil {
+			continue
+		}
+
+		run := &Run{}
+		err = json.Unmarshal(data, run)
+		if err != nil || run.Id == "" {
+			continue
+		}
+		self.runs[run.Id] = run
+	}
+
+	return nil
+}
+
+func (self *PlaybookService) save(run *Run) error {
+	_, raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(self.config_obj, paths.PlaybookRunPath(run.Id),
+		serialized, utils.SyncCompleter)
+}
+
+func copyRun(run *Run) *Run {
+	result := *run
+	result.FlowIds = append([]string{}, run.FlowIds...)
+	result.Labels = append([]string{}, run.Labels...)
+	return &result
+}
+
+// Pending runs which were not approved in time expire. Called with
+// the lock held.
+func (self *PlaybookService) expire(run *Run, now time.Time) {
+	if run.State != STATE_PENDING {
+		return
+	}
+
+	expiry := int64(DEFAULT_APPROVAL_EXPIRY)
+	playbook, pres := self.playbooks[run.Playbook]
+	if pres && playbook.ApprovalExpirySec > 0 {
+		expiry = int64(playbook.ApprovalExpirySec)
+	}
+
+	if now.Unix()-run.Created >= expiry {
+		run.State = STATE_EXPIRED
+		run.Decided = now.Unix()
+		_ = self.save(run)
+	}
+}
+
+// Get a copy of the run.
+func (self *PlaybookService) Get(id string) (*Run, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	run, pres := self.runs[id]
+	if !pres {
+		return nil, notFoundError
+	}
+	self.expire(run, utils.GetTime().Now())
+
+	return copyRun(run), nil
+}
+
+// List all runs, most recent first.
+func (self *PlaybookService) List() []*Run {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	now := utils.GetTime().Now()
+	result := make([]*Run, 0, len(self.runs))
+	for _, run := range self.runs {
+		self.expire(run, now)
+		result = append(result, copyRun(run))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Created == result[j].Created {
+			return result[i].Id < result[j].Id
+		}
+		return result[i].Created > result[j].Created
+	})
+	return result
+}
+
+// Check the rate limits of the playbook. Called with the lock held.
+func (self *PlaybookService) rateLimited(
+	playbook *config_proto.PlaybookConfig,
+	client_id string, now time.Time) error {
+	cooldown := int64(DEFAULT_CLIENT_COOLDOWN)
+	if playbook.ClientCooldownSec > 0 {
+		cooldown = int64(playbook.ClientCooldownSec)
+	}
+
+	max_runs := DEFAULT_MAX_RUNS_PER_HOUR
+	if playbook.MaxRunsPerHour > 0 {
+		max_runs = int(playbook.MaxRunsPerHour)
+	}
+
+	runs_in_hour := 0
+	for _, run := range self.runs {
+		if run.Playbook != playbook.Name {
+			continue
+		}
+
+		age := now.Unix() - run.Created
+		if run.ClientId == client_id && age < cooldown {
+			return fmt.Errorf("client %v was responded to %v seconds ago",
+				client_id, age)
+		}
+
+		if age < 3600 {
+			runs_in_hour++
+		}
+	}
+
+	if runs_in_hour >= max_runs {
+		return fmt.Errorf("%v runs in the last hour", runs_in_hour)
+	}
+	return nil
+}
+
+// Process an event from the playbook's artifact. Returns the run
+// started for it, or nil if the event did not call for a response.
+func (self *PlaybookService) ProcessEvent(ctx context.Context,
+	name string, row *ordereddict.Dict) (*Run, error) {
+	playbook, pres := self.playbooks[name]
+	if !pres {
+		return nil, fmt.Errorf("Unknown playbook %v", name)
+	}
+
+	matches, err := self.matchesCondition(ctx, playbook, row)
+	if err != nil || !matches {
+		return nil, err
+	}
+
+	client_id, err := self.findClient(ctx, playbook, row)
+	if err != nil {
+		return nil, err
+	}
+
+	// Events about hosts we do not manage can not be responded to.
+	if client_id == "" {
+		return nil, nil
+	}
+
+	now := utils.GetTime().Now()
+	self.mu.Lock()
+	err = self.rateLimited(playbook, client_id, now)
+	if err != nil {
+		self.mu.Unlock()
+
+		logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+		logger.Info("Playbook %v: Not responding to event: %v",
+			playbook.Name, err)
+		return nil, nil
+	}
+
+	run := &Run{
+		Id:       newRunId(),
+		Playbook: playbook.Name,
+		ClientId: client_id,
+		Event:    row,
+		State:    STATE_RUNNING,
+		Created:  now.Unix(),
+	}
+	if playbook.RequireApproval {
+		run.State = STATE_PENDING
+	}
+	self.runs[run.Id] = run
+	err = self.save(run)
+	self.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if playbook.RequireApproval {
+		err = services.LogAudit(ctx, self.config_obj, principal(playbook),
+			"PlaybookRunPending", ordereddict.NewDict().
+				Set("run_id", run.Id).
+				Set("playbook", playbook.Name).
+				Set("client_id", client_id))
+		if err != nil {
+			return nil, err
+		}
+		return self.announce(ctx, run)
+	}
+
+	return self.execute(ctx, playbook, run.Id, principal(playbook))
+}
+
+// The principal automatic responses are attributed to.
+func principal(playbook *config_proto.PlaybookConfig) string {
+	return "playbook:" + playbook.Name
+}
+
+func (self *PlaybookService) matchesCondition(ctx context.Context,
+	playbook *config_proto.PlaybookConfig, row *ordereddict.Dict) (bool, error) {
+	lambda, pres := self.conditions[playbook.Name]
+	if !pres {
+		return true, nil
+	}
+
+	manager, err := services.GetRepositoryManager(self.config_obj)
+	if err != nil {
+		return false, err
+	}
+
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     self.config_obj,
+		ACLManager: acl_managers.NullACLManager{},
+		Logger: logging.NewPlainLogger(
+			self.config_obj, &logging.FrontendComponent),
+	})
+	defer scope.Close()
+
+	return scope.Bool(lambda.Reduce(ctx, scope, []vfilter.Any{row})), nil
+}
+
+// Find the client the event is about.
+func (self *PlaybookService) findClient(ctx context.Context,
+	playbook *config_proto.PlaybookConfig, row *ordereddict.Dict) (string, error) {
+	column := playbook.ClientIdColumn
+	if column == "" {
+		column = "ClientId"
+	}
+
+	client_id, _ := row.GetString(column)
+	if client_id != "" && client_id != "server" {
+		return client_id, nil
+	}
+
+	if playbook.HostnameColumn == "" {
+		return "", nil
+	}
+
+	hostname, _ := row.GetString(playbook.HostnameColumn)
+	if hostname == "" {
+		return "", nil
+	}
+
+	return findClientByHostname(ctx, self.config_obj, hostname)
+}
+
+// Match the hostname against the client hostnames and FQDNs. EDR
+// products may report either.
+func findClientByHostname(ctx context.Context,
+	config_obj *config_proto.Config, hostname string) (string, error) {
+	indexer, err := services.GetIndexer(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	hostname = strings.ToLower(hostname)
+	short_name := strings.Split(hostname, ".")[0]
+
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for hit := range indexer.SearchIndexWithPrefix(
+		sub_ctx, config_obj, "host:"+short_name) {
+		if hit == nil {
+			continue
+		}
+
+		info, err := client_info_manager.Get(sub_ctx, hit.Entity)
+		if err != nil {
+			continue
+		}
+
+		if strings.ToLower(info.Hostname) == short_name ||
+			strings.ToLower(info.Hostname) == hostname ||
+			strings.ToLower(info.Fqdn) == hostname {
+			return hit.Entity, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Announce the run on the runs queue.
+func (self *PlaybookService) announce(
+	ctx context.Context, run *Run) (*Run, error) {
+	journal_service, err := services.GetJournal(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	err = journal_service.PushRowsToArtifact(ctx, self.config_obj,
+		[]*ordereddict.Dict{runToRow(run)}, RUNS_ARTIFACT, "server", "")
+	return run, err
+}
+
+func runToRow(run *Run) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("RunId", run.Id).
+		Set("Playbook", run.Playbook).
+		Set("ClientId", run.ClientId).
+		Set("State", run.State).
+		Set("Created", time.Unix(run.Created, 0).UTC()).
+		Set("Approver", run.Approver).
+		Set("FlowIds", run.FlowIds).
+		Set("Labels", run.Labels).
+		Set("Error", run.Error).
+		Set("Event", run.Event)
+}
+
+// Watch the artifacts of the configured playbooks. Only the master
+// node responds so each event is handled once.
+func StartPlaybookService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if !services.IsMaster(config_obj) ||
+		len(config_obj.GetDefaults().GetPlaybooks()) == 0 {
+		return nil
+	}
+
+	self, err := NewPlaybookService(config_obj)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Playbook service for %v.",
+		services.GetOrgName(config_obj))
+
+	err = self.Load()
+	if err != nil {
+		logger.Debug("PlaybookService: No runs loaded: %v", err)
+	}
+
+	org_id := utils.NormalizedOrgId(config_obj.OrgId)
+	mu.Lock()
+	g_services[org_id] = self
+	mu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		mu.Lock()
+		delete(g_services, org_id)
+		mu.Unlock()
+	}()
+
+	for _, playbook := range config_obj.GetDefaults().GetPlaybooks() {
+		name := playbook.Name
+		err := journal.WatchQueueWithCB(ctx, config_obj, wg,
+			playbook.Artifact, "Playbook "+name,
+			func(ctx context.Context, config_obj *config_proto.Config,
+				row *ordereddict.Dict) error {
+				_, err := self.ProcessEvent(ctx, name, row)
+				if err != nil {
+					logger.Error("Playbook %v: %v", name, err)
+				}
+				return nil
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package playbooks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/playbooks"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type PlaybookTestSuite struct {
+	test_utils.TestSuite
+}
+
+var mock_definitions = []string{`
+name: Server.Internal.PlaybookRuns
+type: INTERNAL
+`, `
+name: Server.Internal.EDRAlerts
+type: INTERNAL
+`, `
+name: Generic.Client.Info
+sources:
+- query: SELECT * FROM scope()
+`, `
+name: Windows.Remediation.Quarantine
+parameters:
+- name: MessageBox
+sources:
+- query: SELECT * FROM scope()
+`}
+
+func (self *PlaybookTestSuite) SetupTest() {
+	self.ConfigObj = self.TestSuite.LoadConfig()
+	self.LoadArtifactsIntoConfig(mock_definitions)
+
+	self.TestSuite.SetupTest()
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	for _, info := range []*services.ClientInfo{
+		{ClientInfo: actions_proto.ClientInfo{ClientId: "C.1",
+			Hostname: "WKS01", Fqdn: "wks01.corp.local", System: "windows"}},
+		{ClientInfo: actions_proto.ClientInfo{ClientId: "C.2",
+			Hostname: "WKS02", Fqdn: "wks02.corp.local", System: "darwin"}},
+	} {
+		assert.NoError(self.T(), client_info_manager.Set(self.Ctx, info))
+	}
+
+	indexer, err := services.GetIndexer(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.NoError(self.T(), indexer.SetIndex("C.1", "host:wks01"))
+	assert.NoError(self.T(), indexer.SetIndex("C.2", "host:wks02"))
+}
+
+func (self *PlaybookTestSuite) TestInvalidPlaybooks() {
+	for _, playbook := range []*config_proto.PlaybookConfig{
+		{Name: "NoArtifact"},
+		{Name: "BadAction", Artifact: "X",
+			Actions: []*config_proto.PlaybookAction{{Type: "reboot"}}},
+		{Name: "NoLabel", Artifact: "X",
+			Actions: []*config_proto.PlaybookAction{{Type: "label"}}},
+		{Name: "BadCondition", Artifact: "X", Condition: "x=>"},
+	} {
+		self.ConfigObj.Defaults.Playbooks = []*config_proto.PlaybookConfig{playbook}
+		_, err := playbooks.NewPlaybookService(self.ConfigObj)
+		assert.Error(self.T(), err, playbook.Name)
+	}
+}
+
+func (self *PlaybookTestSuite) TestPlaybooks() {
+	clock := utils.NewMockClock(time.Unix(1700000000, 0))
+	closer := utils.MockTime(clock)
+	defer closer()
+
+	self.ConfigObj.Defaults.Playbooks = []*config_proto.PlaybookConfig{{
+		Name:           "Triage",
+		Artifact:       "Server.Internal.EDRAlerts",
+		Condition:      "x=>x.Severity =~ 'High'",
+		HostnameColumn: "Hostname",
+		MaxRunsPerHour: 2,
+		Actions: []*config_proto.PlaybookAction{{
+			Type:      "collect",
+			Artifacts: []string{"Generic.Client.Info"},
+		}, {
+			Type:  "label",
+			Label: "Triaged",
+		}},
+	}, {
+		Name:            "Isolate",
+		Artifact:        "Server.Internal.EDRAlerts",
+		RequireApproval: true,
+		Actions: []*config_proto.PlaybookAction{{
+			Type: "quarantine",
+		}},
+	}}
+
+	service, err := playbooks.NewPlaybookService(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// Low severity events and unknown hosts are ignored.
+	for _, event := range []*ordereddict.Dict{
+		ordereddict.NewDict().
+			Set("Severity", "Low").
+			Set("Hostname", "wks01.corp.local"),
+		ordereddict.NewDict().
+			Set("Severity", "High").
+			Set("Hostname", "unknown.corp.local"),
+	} {
+		run, err := service.ProcessEvent(self.Ctx, "Triage", event)
+		assert.NoError(self.T(), err)
+		assert.Nil(self.T(), run)
+	}
+
+	// The client is found by its FQDN.
+	run, err := service.ProcessEvent(self.Ctx, "Triage",
+		ordereddict.NewDict().
+			Set("Severity", "High").
+			Set("Hostname", "WKS01.corp.local"))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "C.1", run.ClientId)
+	assert.Equal(self.T(), playbooks.STATE_COMPLETED, run.State)
+	assert.Equal(self.T(), 1, len(run.FlowIds))
+	assert.Equal(self.T(), []string{"Triaged"}, run.Labels)
+
+	assert.True(self.T(), services.GetLabeler(self.ConfigObj).IsLabelSet(
+		self.Ctx, self.ConfigObj, "C.1", "Triaged"))
+
+	// The client is not responded to again during the cooldown.
+	run, err = service.ProcessEvent(self.Ctx, "Triage",
+		ordereddict.NewDict().
+			Set("Severity", "High").
+			Set("Hostname", "wks01"))
+	assert.NoError(self.T(), err)
+	assert.Nil(self.T(), run)
+
+	// Another client is responded to until the hourly limit.
+	run, err = service.ProcessEvent(self.Ctx, "Triage",
+		ordereddict.NewDict().
+			Set("Severity", "High").
+			Set("Hostname", "wks02"))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "C.2", run.ClientId)
+
+	clock.Set(time.Unix(1700000000+3700, 0))
+	run, err = service.ProcessEvent(self.Ctx, "Triage",
+		ordereddict.NewDict().
+			Set("Severity", "High").
+			Set("Hostname", "wks01"))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "C.1", run.ClientId)
+
+	// Runs requiring approval wait for a decision.
+	pending, err := service.ProcessEvent(self.Ctx, "Isolate",
+		ordereddict.NewDict().Set("ClientId", "C.1"))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), playbooks.STATE_PENDING, pending.State)
+	assert.True(self.T(), !services.GetLabeler(self.ConfigObj).IsLabelSet(
+		self.Ctx, self.ConfigObj, "C.1", playbooks.QUARANTINE_LABEL))
+
+	approved, err := service.Decide(self.Ctx, "admin", pending.Id, true)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), playbooks.STATE_COMPLETED, approved.State)
+	assert.Equal(self.T(), "admin", approved.Approver)
+	assert.True(self.T(), services.GetLabeler(self.ConfigObj).IsLabelSet(
+		self.Ctx, self.ConfigObj, "C.1", playbooks.QUARANTINE_LABEL))
+
+	// A run is only decided once.
+	_, err = service.Decide(self.Ctx, "admin", pending.Id, false)
+	assert.Error(self.T(), err)
+
+	// There is no quarantine artifact for macOS.
+	pending, err = service.ProcessEvent(self.Ctx, "Isolate",
+		ordereddict.NewDict().Set("ClientId", "C.2"))
+	assert.NoError(self.T(), err)
+
+	failed, err := service.Decide(self.Ctx, "admin", pending.Id, true)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), playbooks.STATE_FAILED, failed.State)
+
+	// Pending runs expire if not decided in time.
+	clock.Set(time.Unix(1700000000+2*3700, 0))
+	pending, err = service.ProcessEvent(self.Ctx, "Isolate",
+		ordereddict.NewDict().Set("ClientId", "C.1"))
+	assert.NoError(self.T(), err)
+
+	clock.Set(time.Unix(1700000000+2*3700+86400, 0))
+	_, err = service.Decide(self.Ctx, "admin", pending.Id, true)
+	assert.Error(self.T(), err)
+
+	// Runs survive a restart so the rate limits still apply.
+	restored, err := playbooks.NewPlaybookService(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.NoError(self.T(), restored.Load())
+
+	runs := restored.List()
+	assert.Equal(self.T(), 6, len(runs))
+	assert.Equal(self.T(), playbooks.STATE_EXPIRED, runs[0].State)
+
+	severity, _ := runs[len(runs)-1].Event.GetString("Severity")
+	assert.Equal(self.T(), "High", severity)
+}
+
+func TestPlaybooks(t *testing.T) {
+	suite.Run(t, &PlaybookTestSuite{})
+}
@@ -0,0 +1,164 @@
+/*
+  The recompression service migrates the file store to compressed
+  storage in the background.
+
+  When compression is enabled new data is compressed as it is
+  written, but incrementally written files end up with many small
+  chunks and files written before compression was enabled are not
+  compressed at all. This service periodically walks the collection
+  results and uploads and rewrites files which have not been written
+  for a while into full size compressed chunks.
+
+  Recompression does not change the content of the files.
+*/
+
+package recompression
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/compressed"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	DEFAULT_INTERVAL = 3600
+	DEFAULT_MIN_AGE  = 86400
+)
+
+type Stats struct {
+	Files int
+	Saved int64
+}
+
+// Recompress all cold files in the file store.
+func RecompressFileStore(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	file_store_factory api.FileStore,
+	min_age time.Duration) (*Stats, error) {
+
+	store := compressed.NewCompressedFileStore(file_store_factory)
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	stats := &Stats{}
+	now := utils.GetTime().Now()
+
+	for _, root := range []api.FSPathSpec{
+		paths.CLIENTS_ROOT.AsFilestorePath(),
+		paths.SERVER_MONITORING_ROOT,
+		paths.SERVER_MONITORING_LOGS_ROOT,
+	} {
+		// Walk the underlying store directly as we do not need
+		// the uncompressed sizes.
+		err := api.Walk(file_store_factory, root,
+			func(filename api.FSPathSpec, info os.FileInfo) error {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				if !compressed.ShouldCompress(filename) ||
+					now.Sub(info.ModTime()) < min_age {
+					return nil
+				}
+
+				saved, err := store.Recompress(filename)
+				if err != nil {
+					logger.Error("Recompression: %v: %v",
+						filename.AsClientPath(), err)
+					return nil
+				}
+
+				if saved != 0 {
+					stats.Files++
+					stats.Saved += saved
+				}
+				return nil
+			})
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+func StartRecompressionService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if !services.IsMaster(config_obj) ||
+		config_obj.Datastore == nil ||
+		config_obj.Datastore.Compression == "" {
+		return nil
+	}
+
+	interval := config_obj.Datastore.RecompressionIntervalSec
+	if interval < 0 {
+		return nil
+	}
+
+	if interval == 0 {
+		interval = DEFAULT_INTERVAL
+	}
+
+	min_age := config_obj.Datastore.RecompressionMinAgeSec
+	if min_age == 0 {
+		min_age = DEFAULT_MIN_AGE
+	}
+
+	// Only file based stores are recompressed.
+	implementation, err := datastore.GetImplementationName(config_obj)
+	if err != nil {
+		return err
+	}
+
+	switch implementation {
+	case "FileBaseDataStore", "MemcacheFileDataStore":
+	default:
+		return nil
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Recompression service for %v.",
+		services.GetOrgName(config_obj))
+
+	file_store_factory := directory.NewDirectoryFileStore(config_obj)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(time.Duration(interval) * time.Second):
+				stats, err := RecompressFileStore(ctx, config_obj,
+					file_store_factory,
+					time.Duration(min_age)*time.Second)
+				if err != nil && ctx.Err() == nil {
+					logger.Error("Recompression: %v", err)
+				}
+
+				if stats.Files > 0 {
+					logger.Info("Recompression: Recompressed %v files saving %v bytes",
+						stats.Files, stats.Saved)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
@@ -207,6 +207,14 @@ func (self *Repository) LoadProto(
 			}
 		}
 
+		for _, capability := range artifact.RequiredCapabilities {
+			_, pres := acls.GetCapabilityPermission(capability)
+			if !pres {
+				return nil, fmt.Errorf(
+					"Invalid artifact capability %v", capability)
+			}
+		}
+
 		// Ensure precodition has correct syntax - it should be a VQL
 		// query.
 		if artifact.Precondition != "" {
@@ -0,0 +1,140 @@
+/*
+  The result set indexer builds indexes for large result sets in the
+  background (see result_sets/indexes).
+
+  Result sets are queued for indexing when the GUI sorts or filters
+  a large result set which does not have an up to date index. That
+  request is still served by scanning the result set but once the
+  index is built, sorting and filtering the table is fast.
+*/
+
+package result_set_indexer
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/result_sets/indexes"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	QUEUE_SIZE = 100
+)
+
+type request struct {
+	file_store_factory api.FileStore
+	log_path           api.FSPathSpec
+}
+
+type ResultSetIndexer struct {
+	mu         sync.Mutex
+	config_obj *config_proto.Config
+	queue      chan *request
+
+	// Result sets already queued.
+	pending map[string]bool
+}
+
+func (self *ResultSetIndexer) Schedule(
+	file_store_factory api.FileStore, log_path api.FSPathSpec) {
+	key := log_path.AsClientPath()
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.pending[key] {
+		return
+	}
+
+	select {
+	case self.queue <- &request{
+		file_store_factory: file_store_factory,
+		log_path:           log_path,
+	}:
+		self.pending[key] = true
+
+	default:
+		// The queue is full - the result set will be scheduled
+		// again next time it is sorted or filtered.
+	}
+}
+
+func (self *ResultSetIndexer) buildIndex(ctx context.Context, req *request) {
+	defer func() {
+		self.mu.Lock()
+		delete(self.pending, req.log_path.AsClientPath())
+		self.mu.Unlock()
+	}()
+
+	// The index may have been built while we were queued.
+	_, err := indexes.GetMetadata(req.file_store_factory, req.log_path)
+	if err == nil {
+		return
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	start := utils.GetTime().Now()
+
+	metadata, err := indexes.BuildIndex(ctx, req.file_store_factory, req.log_path)
+	if errors.Is(err, indexes.ErrNoIndex) || ctx.Err() != nil {
+		// The result set changed while we indexed it.
+		return
+	}
+
+	if err != nil {
+		logger.Error("ResultSetIndexer: %v: %v",
+			req.log_path.AsClientPath(), err)
+		return
+	}
+
+	logger.Info("ResultSetIndexer: Indexed %v rows of %v in %v",
+		metadata.Rows, req.log_path.AsClientPath(),
+		utils.GetTime().Now().Sub(start))
+}
+
+func StartResultSetIndexerService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if !services.IsMaster(config_obj) ||
+		config_obj.Defaults.GetResultSetIndexMinRows() < 0 {
+		return nil
+	}
+
+	indexer := &ResultSetIndexer{
+		config_obj: config_obj,
+		queue:      make(chan *request, QUEUE_SIZE),
+		pending:    make(map[string]bool),
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Result Set Indexer service for %v.",
+		services.GetOrgName(config_obj))
+
+	indexes.RegisterScheduler(config_obj.OrgId, indexer.Schedule)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer indexes.RegisterScheduler(config_obj.OrgId, nil)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case req := <-indexer.queue:
+				indexer.buildIndex(ctx, req)
+			}
+		}
+	}()
+
+	return nil
+}
@@ -3,9 +3,11 @@ package sanity
 import (
 	"fmt"
 	"net"
+	"strings"
 
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 func (self *SanityChecks) CheckFrontendSettings(
@@ -23,5 +25,63 @@ func (self *SanityChecks) CheckFrontendSettings(
 			logger.Info("GUI Will only accept conections from <green>%v</>", cidr_net)
 		}
 	}
+
+	if config_obj.Frontend != nil {
+		_, err := utils.ParseTrustedProxies(config_obj.Frontend.TrustedProxies)
+		if err != nil {
+			return fmt.Errorf("Frontend.trusted_proxies: %w", err)
+		}
+
+		for _, cidr := range config_obj.Frontend.TrustedProxies {
+			logger.Info("Trusting forwarded headers from proxy <green>%v</>", cidr)
+		}
+
+		if config_obj.Frontend.ProxyProtocol &&
+			len(config_obj.Frontend.TrustedProxies) == 0 {
+			return fmt.Errorf("Frontend.proxy_protocol requires Frontend.trusted_proxies")
+		}
+
+		err = checkAccessControl(config_obj.Frontend.AccessControl)
+		if err != nil {
+			return fmt.Errorf("Frontend.access_control: %w", err)
+		}
+	}
+
+	if config_obj.GUI != nil {
+		err := checkAccessControl(config_obj.GUI.AccessControl)
+		if err != nil {
+			return fmt.Errorf("GUI.access_control: %w", err)
+		}
+	}
+
+	if config_obj.API != nil {
+		err := checkAccessControl(config_obj.API.AccessControl)
+		if err != nil {
+			return fmt.Errorf("API.access_control: %w", err)
+		}
+	}
+	return nil
+}
+
+func checkAccessControl(spec *config_proto.ListenerAccessControl) error {
+	if spec == nil {
+		return nil
+	}
+
+	_, err := utils.ParseNetworks(spec.AllowedCidr)
+	if err != nil {
+		return err
+	}
+
+	_, err = utils.ParseNetworks(spec.DeniedCidr)
+	if err != nil {
+		return err
+	}
+
+	for _, country := range spec.BlockedCountries {
+		if len(strings.TrimSpace(country)) != 2 {
+			return fmt.Errorf("Invalid country code %v", country)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,110 @@
+package secrets
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	REDACTED = "[REDACTED]"
+)
+
+// Removes the secrets used by a collection from its results and
+// logs. The redactor is stored in the query scope so the secret()
+// function can register the values it decrypts.
+type Redactor struct {
+	mu     sync.Mutex
+	values []string
+}
+
+func NewRedactor() *Redactor {
+	return &Redactor{}
+}
+
+func (self *Redactor) Add(value string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if value == "" || utils.InString(self.values, value) {
+		return
+	}
+
+	// Replace longer secrets first in case one contains another.
+	values := append([]string{value}, self.values...)
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	self.values = values
+}
+
+func (self *Redactor) getValues() []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.values
+}
+
+func (self *Redactor) RedactString(value string) string {
+	for _, secret := range self.getValues() {
+		value = strings.ReplaceAll(value, secret, REDACTED)
+	}
+	return value
+}
+
+// Redact a row before it is written. Values which are not strings
+// are replaced by their redacted JSON encoding if they contain a
+// secret.
+func (self *Redactor) RedactRow(row *ordereddict.Dict) *ordereddict.Dict {
+	if len(self.getValues()) == 0 {
+		return row
+	}
+
+	result := ordereddict.NewDict()
+	for _, key := range row.Keys() {
+		value, _ := row.Get(key)
+
+		switch t := value.(type) {
+		case nil:
+			result.Set(key, value)
+
+		case string:
+			result.Set(key, self.RedactString(t))
+
+		default:
+			serialized, err := json.Marshal(value)
+			if err != nil {
+				result.Set(key, REDACTED)
+				continue
+			}
+
+			redacted := self.RedactString(string(serialized))
+			if redacted != string(serialized) {
+				result.Set(key, redacted)
+			} else {
+				result.Set(key, value)
+			}
+		}
+	}
+	return result
+}
+
+type redactingWriter struct {
+	redactor *Redactor
+	writer   io.Writer
+}
+
+func (self *redactingWriter) Write(b []byte) (int, error) {
+	_, err := self.writer.Write([]byte(self.redactor.RedactString(string(b))))
+	return len(b), err
+}
+
+// Wrap a log writer so secrets are removed from log messages.
+func (self *Redactor) Writer(writer io.Writer) io.Writer {
+	return &redactingWriter{redactor: self, writer: writer}
+}
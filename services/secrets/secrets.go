@@ -0,0 +1,283 @@
+/*
+  Secrets are named credentials (e.g. API keys) used by server
+  artifacts. They avoid passing credentials as plain text artifact
+  parameters, which are stored in the flow objects.
+
+  Secrets are encrypted with a key derived from the frontend's private
+  key before they are written to the datastore. Artifacts refer to
+  secrets by name using a parameter of type "secret" and the value is
+  only decrypted by the secret() VQL function when the query runs.
+
+  Every value decrypted during a collection is registered with the
+  collection's Redactor so it is removed from the results and logs.
+*/
+
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	notFoundError = errors.New("Secret not found")
+
+	nameRegex = regexp.MustCompile("^[a-zA-Z0-9_][a-zA-Z0-9_.-]*$")
+)
+
+type Secret struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// If set, only these users may use the secret.
+	Users []string `json:"users,omitempty"`
+
+	Principal string `json:"principal,omitempty"`
+	Created   int64  `json:"created"`
+	Modified  int64  `json:"modified"`
+
+	// The nonce followed by the AES-GCM encrypted value.
+	Encrypted []byte `json:"encrypted"`
+}
+
+// Can the principal use this secret?
+func (self *Secret) IsAllowed(principal string) bool {
+	return len(self.Users) == 0 || utils.InString(self.Users, principal)
+}
+
+func getCipher(config_obj *config_proto.Config) (cipher.AEAD, error) {
+	if config_obj.Frontend == nil || config_obj.Frontend.PrivateKey == "" {
+		return nil, errors.New("Secrets are only available on the server")
+	}
+
+	key := sha256.Sum256([]byte("secrets:" + config_obj.Frontend.PrivateKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encrypt(config_obj *config_proto.Config,
+	name, value string) ([]byte, error) {
+	aead, err := getCipher(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bind the ciphertext to the name so it can not be copied to
+	// another secret.
+	return aead.Seal(nonce, nonce, []byte(value), []byte(name)), nil
+}
+
+func decrypt(config_obj *config_proto.Config,
+	name string, encrypted []byte) (string, error) {
+	aead, err := getCipher(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	if len(encrypted) < aead.NonceSize() {
+		return "", fmt.Errorf("Secret %v is corrupted", name)
+	}
+
+	nonce := encrypted[:aead.NonceSize()]
+	plain_text, err := aead.Open(nil, nonce,
+		encrypted[aead.NonceSize():], []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("Unable to decrypt secret %v: %w", name, err)
+	}
+	return string(plain_text), nil
+}
+
+func getRawDB(config_obj *config_proto.Config) (
+	datastore.DataStore, datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, nil, errors.New("Datastore does not support raw access")
+	}
+	return db, raw_db, nil
+}
+
+func getSecret(config_obj *config_proto.Config,
+	path api.DSPathSpec) (*Secret, error) {
+	_, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := raw_db.GetBuffer(config_obj, path)
+	if err != nil || len(data) == 0 {
+		return nil, notFoundError
+	}
+
+	result := &Secret{}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Get the secret's metadata. The value remains encrypted.
+func Get(config_obj *config_proto.Config, name string) (*Secret, error) {
+	if !nameRegex.MatchString(name) {
+		return nil, fmt.Errorf("Invalid secret name %v", name)
+	}
+
+	result, err := getSecret(config_obj, paths.SecretPath(name))
+	if errors.Is(err, notFoundError) {
+		return nil, fmt.Errorf("%w: %v", notFoundError, name)
+	}
+	return result, err
+}
+
+// Create or replace a secret. An empty value keeps the existing value
+// so the description and users may be updated on their own.
+func Set(ctx context.Context, config_obj *config_proto.Config,
+	principal, name, description, value string, users []string) (*Secret, error) {
+	if !nameRegex.MatchString(name) {
+		return nil, fmt.Errorf("Invalid secret name %v", name)
+	}
+
+	now := utils.GetTime().Now().Unix()
+	secret, err := Get(config_obj, name)
+	if errors.Is(err, notFoundError) {
+		if value == "" {
+			return nil, fmt.Errorf("Secret %v needs a value", name)
+		}
+		secret = &Secret{Name: name, Created: now}
+
+	} else if err != nil {
+		return nil, err
+	}
+
+	if value != "" {
+		secret.Encrypted, err = encrypt(config_obj, name, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if description != "" {
+		secret.Description = description
+	}
+
+	if users != nil {
+		secret.Users = users
+	}
+	secret.Principal = principal
+	secret.Modified = now
+
+	serialized, err := json.Marshal(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	_, raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	err = raw_db.SetBuffer(config_obj, paths.SecretPath(name),
+		serialized, utils.SyncCompleter)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, services.LogAudit(ctx, config_obj, principal,
+		"SecretSet", ordereddict.NewDict().
+			Set("name", name).
+			Set("value_changed", value != "").
+			Set("users", secret.Users))
+}
+
+func Delete(ctx context.Context, config_obj *config_proto.Config,
+	principal, name string) error {
+	_, err := Get(config_obj, name)
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	err = db.DeleteSubject(config_obj, paths.SecretPath(name))
+	if err != nil {
+		return err
+	}
+
+	return services.LogAudit(ctx, config_obj, principal,
+		"SecretDelete", ordereddict.NewDict().
+			Set("name", name))
+}
+
+// List all secrets sorted by name.
+func List(config_obj *config_proto.Config) ([]*Secret, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Secret{}
+	err = datastore.Walk(config_obj, db, paths.SECRETS_ROOT,
+		datastore.WalkWithoutDirectories,
+		func(path api.DSPathSpec) error {
+			secret, err := getSecret(config_obj, path)
+			if err == nil {
+				result = append(result, secret)
+			}
+			return nil
+		})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, err
+}
+
+// Decrypt the secret's value on behalf of the principal.
+func GetValue(config_obj *config_proto.Config,
+	principal, name string) (string, error) {
+	secret, err := Get(config_obj, name)
+	if err != nil {
+		return "", err
+	}
+
+	if !secret.IsAllowed(principal) {
+		return "", fmt.Errorf("User %v may not use secret %v", principal, name)
+	}
+
+	return decrypt(config_obj, name, secret.Encrypted)
+}
@@ -0,0 +1,143 @@
+package secrets_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/secrets"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type SecretsTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *SecretsTestSuite) TestSecrets() {
+	_, err := secrets.Set(self.Ctx, self.ConfigObj, "admin",
+		"VirusTotal", "VT API key", "supersecretkey", []string{"admin"})
+	assert.NoError(self.T(), err)
+
+	// The value is not stored in plain text.
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	data, err := db.(datastore.RawDataStore).GetBuffer(
+		self.ConfigObj, paths.SecretPath("VirusTotal"))
+	assert.NoError(self.T(), err)
+	assert.NotContains(self.T(), string(data), "supersecretkey")
+
+	value, err := secrets.GetValue(self.ConfigObj, "admin", "VirusTotal")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "supersecretkey", value)
+
+	// Only allowed users may use the secret.
+	_, err = secrets.GetValue(self.ConfigObj, "mike", "VirusTotal")
+	assert.Error(self.T(), err)
+
+	// Updating the metadata keeps the value.
+	secret, err := secrets.Set(self.Ctx, self.ConfigObj, "admin",
+		"VirusTotal", "", "", []string{})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "VT API key", secret.Description)
+
+	value, err = secrets.GetValue(self.ConfigObj, "mike", "VirusTotal")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "supersecretkey", value)
+
+	// New secrets need a value.
+	_, err = secrets.Set(self.Ctx, self.ConfigObj, "admin",
+		"Splunk", "", "", nil)
+	assert.Error(self.T(), err)
+
+	all, err := secrets.List(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(all))
+
+	err = secrets.Delete(self.Ctx, self.ConfigObj, "admin", "VirusTotal")
+	assert.NoError(self.T(), err)
+
+	_, err = secrets.GetValue(self.ConfigObj, "admin", "VirusTotal")
+	assert.Error(self.T(), err)
+}
+
+func (self *SecretsTestSuite) TestRedactor() {
+	redactor := secrets.NewRedactor()
+
+	row := ordereddict.NewDict().
+		Set("Key", "supersecretkey").
+		Set("Headers", ordereddict.NewDict().
+			Set("Authorization", "Bearer supersecretkey")).
+		Set("Count", 1)
+
+	// Nothing to redact yet.
+	assert.Equal(self.T(), row, redactor.RedactRow(row))
+
+	redactor.Add("supersecretkey")
+	redacted := redactor.RedactRow(row)
+
+	value, _ := redacted.Get("Key")
+	assert.Equal(self.T(), secrets.REDACTED, value)
+
+	value, _ = redacted.Get("Headers")
+	assert.Equal(self.T(), `{"Authorization":"Bearer [REDACTED]"}`, value)
+
+	value, _ = redacted.Get("Count")
+	assert.Equal(self.T(), 1, value)
+
+	buf := &bytes.Buffer{}
+	_, err := redactor.Writer(buf).Write([]byte("Using key supersecretkey"))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "Using key [REDACTED]", buf.String())
+}
+
+func (self *SecretsTestSuite) TestCompileSecretParameter() {
+	repository := self.LoadArtifacts(`
+name: Server.Custom.Secret
+type: SERVER
+parameters:
+  - name: APIKey
+    type: secret
+sources:
+  - query: SELECT APIKey FROM scope()
+`)
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	compiled, err := launcher.CompileCollectorArgs(
+		self.Ctx, self.ConfigObj, acl_managers.NullACLManager{},
+		repository, services.CompilerOptions{},
+		&flows_proto.ArtifactCollectorArgs{
+			ClientId:  "server",
+			Artifacts: []string{"Server.Custom.Secret"},
+			Specs: []*flows_proto.ArtifactSpec{{
+				Artifact: "Server.Custom.Secret",
+				Parameters: &flows_proto.ArtifactParameters{
+					Env: []*actions_proto.VQLEnv{{
+						Key: "APIKey", Value: "VirusTotal"}},
+				},
+			}},
+		})
+	assert.NoError(self.T(), err)
+
+	// Only the name of the secret is stored in the request.
+	queries := []string{}
+	for _, q := range compiled[0].Query {
+		queries = append(queries, q.VQL)
+	}
+	assert.Contains(self.T(), queries,
+		"LET APIKey <= if(condition=APIKey, then=secret(name=APIKey))")
+}
+
+func TestSecrets(t *testing.T) {
+	suite.Run(t, &SecretsTestSuite{})
+}
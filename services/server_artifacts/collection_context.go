@@ -15,6 +15,7 @@ import (
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	"www.velocidex.com/golang/velociraptor/artifacts"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
 	"www.velocidex.com/golang/velociraptor/file_store"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
@@ -23,6 +24,8 @@ import (
 	"www.velocidex.com/golang/velociraptor/result_sets"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/launcher"
+	"www.velocidex.com/golang/velociraptor/services/secrets"
+	"www.velocidex.com/golang/velociraptor/uploads"
 	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
@@ -56,6 +59,9 @@ type contextManager struct {
 	row_limit  int64
 	byte_limit int64
 
+	// Dry run collections count uploads instead of storing them.
+	dry_run bool
+
 	dirty bool
 }
 
@@ -95,6 +101,7 @@ func NewCollectionContextManager(
 		log_writer: &counterWriter{ResultSetWriter: log_writer},
 		row_limit:  row_limit,
 		byte_limit: byte_limit,
+		dry_run:    req.DryRun,
 		dirty:      true,
 	}
 
@@ -359,6 +366,23 @@ func (self *contextManager) RunQuery(
 	}
 
 	flow_path_manager := paths.NewFlowPathManager("server", self.session_id)
+	uploader := NewServerUploader(self.config_obj, self.session_id,
+		flow_path_manager, self, query_context)
+	if self.dry_run {
+		uploader = &uploads.DryRunUploader{
+			Estimate: func(size int64) {
+				query_context.UpdateStatus(func(s *crypto_proto.VeloStatus) {
+					s.UploadedFiles++
+					s.ExpectedUploadedBytes += size
+				})
+			},
+		}
+	}
+
+	// Secrets used by the query are removed from its results and
+	// logs.
+	redactor := secrets.NewRedactor()
+
 	scope := manager.BuildScope(services.ScopeBuilder{
 		Config: self.config_obj,
 
@@ -366,13 +390,13 @@ func (self *contextManager) RunQuery(
 		// store. NOTE: This allows arbitrary filestore write. Using
 		// this we can manage the files in the filestore using VQL
 		// artifacts.
-		Uploader: NewServerUploader(self.config_obj, self.session_id,
-			flow_path_manager, self, query_context),
+		Uploader: uploader,
 
 		// Run this query on behalf of the caller so they are
 		// subject to ACL checks
 		ACLManager: acl_managers.NewServerACLManager(self.config_obj, principal),
-		Logger:     log.New(query_context.Logger(), "", 0),
+		Logger:     log.New(redactor.Writer(query_context.Logger()), "", 0),
+		Env:        ordereddict.NewDict().Set(constants.SCOPE_SECRETS, redactor),
 	})
 	defer scope.Close()
 
@@ -489,7 +513,8 @@ func (self *contextManager) RunQuery(
 
 				// rs_writer has its own internal buffering so it is
 				// ok to write a row at a time.
-				rs_writer.Write(vfilter.RowToDict(sub_ctx, scope, row))
+				rs_writer.Write(redactor.RedactRow(
+					vfilter.RowToDict(sub_ctx, scope, row)))
 				query_context.UpdateStatus(func(s *crypto_proto.VeloStatus) {
 					s.ResultRows++
 					_, pres := names_with_response[name]
@@ -15,6 +15,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/actions"
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/file_store"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
@@ -23,6 +24,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/paths/artifacts"
 	"www.velocidex.com/golang/velociraptor/result_sets"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/secrets"
 	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
@@ -355,6 +357,10 @@ func (self *EventTable) RunQuery(
 		artifact:     artifact_name,
 	}
 
+	// Secrets used by the query are removed from its results and
+	// logs.
+	redactor := secrets.NewRedactor()
+
 	builder := services.ScopeBuilder{
 		Config: config_obj,
 		// Run the monitoring queries as the server account. If the
@@ -363,9 +369,10 @@ func (self *EventTable) RunQuery(
 		ACLManager: acl_managers.NewServerACLManager(
 			self.config_obj,
 			self.config_obj.Client.PinnedServerName),
-		Env:        ordereddict.NewDict(),
+		Env: ordereddict.NewDict().
+			Set(constants.SCOPE_SECRETS, redactor),
 		Repository: repository,
-		Logger:     log.New(self.logger, "", 0),
+		Logger:     log.New(redactor.Writer(self.logger), "", 0),
 	}
 
 	for _, env_spec := range vql_request.Env {
@@ -436,7 +443,8 @@ func (self *EventTable) RunQuery(
 						break one_query
 					}
 
-					rs_writer.Write(vfilter.RowToDict(ctx, scope, row).
+					rs_writer.Write(redactor.RedactRow(
+						vfilter.RowToDict(ctx, scope, row)).
 						Set("_ts", self.Clock().Now().Unix()))
 					rs_writer.Flush()
 				}
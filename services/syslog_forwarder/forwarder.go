@@ -0,0 +1,309 @@
+package syslog_forwarder
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/reporting/siem"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	DEFAULT_APP_NAME         = "velociraptor"
+	DEFAULT_MAX_MESSAGE_SIZE = 8192
+	AUDIT_ARTIFACT           = "Server.Audit.Logs"
+
+	dialTimeout = 10 * time.Second
+)
+
+var (
+	syslogForwardedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "syslog_forwarded_total",
+		Help: "Number of server events forwarded to syslog.",
+	})
+
+	syslogErrorCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "syslog_forward_errors_total",
+		Help: "Number of server events which could not be forwarded to syslog.",
+	})
+)
+
+// Forwards rows from server event queues to a syslog server as RFC
+// 5424 messages.
+type Forwarder struct {
+	mu sync.Mutex
+
+	config_obj *config_proto.Config
+	settings   *config_proto.SyslogForwardingConfig
+
+	protocol, server string
+	facility         int
+	hostname         string
+	app_name         string
+	proc_id          string
+	max_size         int
+	tls_config       *tls.Config
+
+	conn net.Conn
+}
+
+func (self *Forwarder) Artifacts() []string {
+	if len(self.settings.Artifacts) == 0 {
+		return []string{AUDIT_ARTIFACT}
+	}
+	return self.settings.Artifacts
+}
+
+// Format the row as a syslog message.
+func (self *Forwarder) Format(artifact string, row *ordereddict.Dict) ([]byte, error) {
+	flat, err := siem.Flatten(row, ".", json.DefaultEncOpts())
+	if err != nil {
+		return nil, err
+	}
+
+	serialized, err := json.MarshalWithOptions(row, json.DefaultEncOpts())
+	if err != nil {
+		return nil, err
+	}
+
+	params := ordereddict.NewDict().
+		Set("artifact", artifact).
+		Set("org_id", utils.NormalizedOrgId(self.config_obj.OrgId))
+	for _, k := range flat.Keys() {
+		v, _ := flat.Get(k)
+		params.Set(k, v)
+	}
+
+	severity := siem.SYSLOG_SEVERITY_INFO
+	if artifact == AUDIT_ARTIFACT {
+		severity = siem.SYSLOG_SEVERITY_NOTICE
+	}
+
+	message := &siem.SyslogMessage{
+		Facility:  self.facility,
+		Severity:  severity,
+		Timestamp: utils.GetTime().Now(),
+		Hostname:  self.hostname,
+		AppName:   self.app_name,
+		ProcId:    self.proc_id,
+		MsgId:     artifact,
+		Params:    params,
+		Msg:       string(serialized),
+	}
+
+	return message.Format(self.max_size), nil
+}
+
+// Send the row to the syslog server. If the connection was lost we
+// reconnect and try once more.
+func (self *Forwarder) Send(artifact string, row *ordereddict.Dict) error {
+	message, err := self.Format(artifact, row)
+	if err != nil {
+		return err
+	}
+
+	// Stream transports use octet counting framing (RFC 6587).
+	if self.protocol != "udp" {
+		message = append([]byte(strconv.Itoa(len(message))+" "), message...)
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for i := 0; i < 2; i++ {
+		if self.conn == nil {
+			self.conn, err = self.dial()
+			if err != nil {
+				syslogErrorCounter.Inc()
+				return err
+			}
+		}
+
+		_, err = self.conn.Write(message)
+		if err == nil {
+			syslogForwardedCounter.Inc()
+			return nil
+		}
+
+		self.conn.Close()
+		self.conn = nil
+	}
+
+	syslogErrorCounter.Inc()
+	return err
+}
+
+func (self *Forwarder) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if self.protocol == "tls" {
+		return tls.DialWithDialer(dialer, "tcp", self.server, self.tls_config)
+	}
+	return dialer.Dial(self.protocol, self.server)
+}
+
+func (self *Forwarder) Close() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.conn != nil {
+		self.conn.Close()
+		self.conn = nil
+	}
+}
+
+// Watch the configured queues and forward their rows until the
+// context is done.
+func (self *Forwarder) Start(ctx context.Context, wg *sync.WaitGroup) error {
+	journal, err := services.GetJournal(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Syslog forwarder</> will forward %v to %v over %v",
+		strings.Join(self.Artifacts(), ", "), self.server, self.protocol)
+
+	for _, artifact := range self.Artifacts() {
+		events, cancel := journal.Watch(ctx, artifact, "SyslogForwarder")
+
+		wg.Add(1)
+		go func(artifact string) {
+			defer wg.Done()
+			defer cancel()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+
+				case row, ok := <-events:
+					if !ok {
+						return
+					}
+
+					err := self.Send(artifact, row)
+					if err != nil {
+						logger.Error("Syslog forwarder: %v", err)
+					}
+				}
+			}
+		}(artifact)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		self.Close()
+	}()
+
+	return nil
+}
+
+func NewForwarder(config_obj *config_proto.Config) (*Forwarder, error) {
+	settings := config_obj.GetDefaults().GetSyslogForwarding()
+	if settings == nil || settings.Server == "" {
+		return nil, errors.New("Syslog forwarder: No server configured")
+	}
+
+	self := &Forwarder{
+		config_obj: config_obj,
+		settings:   settings,
+		protocol:   strings.ToLower(settings.Protocol),
+		server:     settings.Server,
+		hostname:   settings.Hostname,
+		app_name:   settings.AppName,
+		proc_id:    strconv.Itoa(os.Getpid()),
+		max_size:   int(settings.MaxMessageSize),
+	}
+
+	default_port := "514"
+	switch self.protocol {
+	case "":
+		self.protocol = "udp"
+	case "udp", "tcp":
+	case "tls":
+		default_port = "6514"
+	default:
+		return nil, fmt.Errorf("Syslog forwarder: Unsupported protocol %v",
+			settings.Protocol)
+	}
+
+	_, _, err := net.SplitHostPort(self.server)
+	if err != nil {
+		self.server = net.JoinHostPort(self.server, default_port)
+	}
+
+	self.facility, err = siem.ParseSyslogFacility(settings.Facility)
+	if err != nil {
+		return nil, err
+	}
+
+	if self.app_name == "" {
+		self.app_name = DEFAULT_APP_NAME
+	}
+
+	if self.max_size == 0 {
+		self.max_size = DEFAULT_MAX_MESSAGE_SIZE
+	}
+
+	if self.hostname == "" && config_obj.Frontend != nil {
+		self.hostname = config_obj.Frontend.Hostname
+	}
+	if self.hostname == "" {
+		self.hostname, _ = os.Hostname()
+	}
+
+	if self.protocol == "tls" {
+		self.tls_config = &tls.Config{
+			InsecureSkipVerify: settings.SkipVerify,
+		}
+
+		if settings.RootCerts != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(settings.RootCerts)) {
+				return nil, errors.New(
+					"Syslog forwarder: Unable to parse root_certs")
+			}
+			self.tls_config.RootCAs = pool
+		}
+	}
+
+	return self, nil
+}
+
+// Forward the audit logs and selected server event queues of the
+// org to the syslog server configured in the Defaults section.
+func StartSyslogForwarderService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	settings := config_obj.GetDefaults().GetSyslogForwarding()
+	if settings == nil || settings.Server == "" {
+		return nil
+	}
+
+	forwarder, err := NewForwarder(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return forwarder.Start(ctx, wg)
+}
@@ -0,0 +1,162 @@
+package syslog_forwarder_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/syslog_forwarder"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type SyslogForwarderTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *SyslogForwarderTestSuite) SetupTest() {
+	self.ConfigObj = self.TestSuite.LoadConfig()
+	self.ConfigObj.Services.JournalService = true
+
+	self.TestSuite.SetupTest()
+
+	self.LoadArtifacts(`name: Server.Audit.Logs
+type: SERVER_EVENT
+`)
+}
+
+// Read an octet counted frame.
+func readFrame(reader *bufio.Reader) (string, error) {
+	length, err := reader.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+
+	size, err := strconv.Atoi(strings.TrimSpace(length))
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, size)
+	_, err = io.ReadFull(reader, buf)
+	return string(buf), err
+}
+
+func (self *SyslogForwarderTestSuite) TestTCP() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(self.T(), err)
+	defer listener.Close()
+
+	messages := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		for {
+			frame, err := readFrame(reader)
+			if err != nil {
+				return
+			}
+			messages <- frame
+		}
+	}()
+
+	self.ConfigObj.Defaults.SyslogForwarding = &config_proto.SyslogForwardingConfig{
+		Server:   listener.Addr().String(),
+		Protocol: "tcp",
+		Hostname: "velo.example.com",
+	}
+
+	forwarder, err := syslog_forwarder.NewForwarder(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	ctx, cancel := context.WithCancel(self.Ctx)
+	wg := &sync.WaitGroup{}
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	err = forwarder.Start(ctx, wg)
+	assert.NoError(self.T(), err)
+
+	// Audit events are forwarded.
+	err = services.LogAudit(ctx, self.ConfigObj, "admin", "DeleteClient",
+		ordereddict.NewDict().Set("client_id", "C.123"))
+	assert.NoError(self.T(), err)
+
+	select {
+	case message := <-messages:
+		assert.Regexp(self.T(), `^<133>1 \S+ velo.example.com velociraptor \d+ `+
+			`Server.Audit.Logs \[velociraptor@32473 artifact="Server.Audit.Logs" `+
+			`org_id="root" operation="DeleteClient" principal="admin" `+
+			`details.client_id="C.123"`, message)
+		assert.Contains(self.T(), message,
+			`] {"operation":"DeleteClient","principal":"admin",`+
+				`"details":{"client_id":"C.123"}`)
+
+	case <-time.After(10 * time.Second):
+		self.T().Fatalf("Timed out waiting for syslog message")
+	}
+}
+
+func (self *SyslogForwarderTestSuite) TestUDP() {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(self.T(), err)
+	defer conn.Close()
+
+	self.ConfigObj.Defaults.SyslogForwarding = &config_proto.SyslogForwardingConfig{
+		Server:         conn.LocalAddr().String(),
+		Facility:       "auth",
+		AppName:        "vr",
+		MaxMessageSize: 100,
+	}
+
+	forwarder, err := syslog_forwarder.NewForwarder(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	defer forwarder.Close()
+
+	err = forwarder.Send("Server.Internal.Alerts", ordereddict.NewDict().
+		Set("Message", strings.Repeat("x", 200)))
+	assert.NoError(self.T(), err)
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.NoError(self.T(), err)
+
+	// Datagrams are not framed and are truncated to the maximum size.
+	assert.Equal(self.T(), 100, n)
+	assert.True(self.T(), strings.HasPrefix(string(buf[:n]), "<38>1 "))
+	assert.Contains(self.T(), string(buf[:n]), " vr ")
+}
+
+func (self *SyslogForwarderTestSuite) TestInvalidConfig() {
+	for _, settings := range []*config_proto.SyslogForwardingConfig{
+		{Server: "localhost", Protocol: "http"},
+		{Server: "localhost", Facility: "bogus"},
+		{Server: "localhost", Protocol: "tls", RootCerts: "invalid"},
+	} {
+		self.ConfigObj.Defaults.SyslogForwarding = settings
+		_, err := syslog_forwarder.NewForwarder(self.ConfigObj)
+		assert.Error(self.T(), err)
+	}
+}
+
+func TestSyslogForwarder(t *testing.T) {
+	suite.Run(t, &SyslogForwarderTestSuite{})
+}
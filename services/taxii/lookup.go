@@ -0,0 +1,187 @@
+package taxii
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// How often to reload the tables from the datastore (they may
+	// be updated by another frontend).
+	RECHECK_INTERVAL = time.Minute
+)
+
+var (
+	index_mu sync.Mutex
+	index    *iocIndex
+)
+
+type Match struct {
+	Collection string
+	IOC        *IOC
+}
+
+type cidrEntry struct {
+	network *net.IPNet
+	match   *Match
+}
+
+// An in memory index of all the tables.
+type iocIndex struct {
+	loaded time.Time
+	tables []*Table
+	iocs   map[string][]*Match
+	cidrs  []*cidrEntry
+}
+
+func invalidateIndex() {
+	index_mu.Lock()
+	defer index_mu.Unlock()
+
+	index = nil
+}
+
+// IOC tables are shared by all orgs and stored in the root org's
+// datastore.
+func getRootConfig(config_obj *config_proto.Config) *config_proto.Config {
+	org_manager, err := services.GetOrgManager()
+	if err != nil {
+		return config_obj
+	}
+
+	root_config, err := org_manager.GetOrgConfig(services.ROOT_ORG_ID)
+	if err != nil {
+		return config_obj
+	}
+	return root_config
+}
+
+func getIndex(config_obj *config_proto.Config) (*iocIndex, error) {
+	index_mu.Lock()
+	defer index_mu.Unlock()
+
+	now := utils.GetTime().Now()
+	if index != nil && now.Sub(index.loaded) < RECHECK_INTERVAL {
+		return index, nil
+	}
+
+	root_config := getRootConfig(config_obj)
+	db, err := datastore.GetDB(root_config)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(root_config,
+		paths.NewIOCPathManager().Path())
+	if err != nil {
+		return nil, err
+	}
+
+	new_index := &iocIndex{
+		loaded: now,
+		iocs:   make(map[string][]*Match),
+	}
+
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		table, err := LoadTable(root_config, child.Base())
+		if err != nil {
+			continue
+		}
+		new_index.tables = append(new_index.tables, table)
+
+		for _, ioc := range table.IOCs {
+			match := &Match{Collection: table.Name, IOC: ioc}
+			if strings.Contains(ioc.Value, "/") &&
+				(ioc.Type == IOC_IPV4 || ioc.Type == IOC_IPV6) {
+				_, network, err := net.ParseCIDR(ioc.Value)
+				if err == nil {
+					new_index.cidrs = append(new_index.cidrs,
+						&cidrEntry{network: network, match: match})
+				}
+				continue
+			}
+
+			new_index.iocs[ioc.key()] = append(new_index.iocs[ioc.key()], match)
+		}
+	}
+
+	sort.Slice(new_index.tables, func(i, j int) bool {
+		return new_index.tables[i].Name < new_index.tables[j].Name
+	})
+
+	index = new_index
+	return index, nil
+}
+
+// All the collection tables.
+func GetTables(config_obj *config_proto.Config) ([]*Table, error) {
+	index, err := getIndex(config_obj)
+	if err != nil {
+		return nil, err
+	}
+	return index.tables, nil
+}
+
+// Look up a value in the IOC tables. If ioc_type is empty, all
+// types are checked. Expired IOCs are only returned when
+// include_expired is set.
+func Lookup(config_obj *config_proto.Config,
+	ioc_type, value string, include_expired bool) ([]*Match, error) {
+	index, err := getIndex(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	types := []string{ioc_type}
+	if ioc_type == "" {
+		types = []string{IOC_MD5, IOC_SHA1, IOC_SHA256, IOC_SHA512,
+			IOC_FILENAME, IOC_IPV4, IOC_IPV6, IOC_DOMAIN, IOC_URL, IOC_EMAIL}
+	}
+
+	now := utils.GetTime().Now()
+	result := []*Match{}
+	add := func(match *Match) {
+		if include_expired || !match.IOC.Expired(now) {
+			result = append(result, match)
+		}
+	}
+
+	for _, t := range types {
+		normalized, ok := Normalize(t, value)
+		if !ok {
+			continue
+		}
+
+		for _, match := range index.iocs[t+":"+normalized] {
+			add(match)
+		}
+
+		if t == IOC_IPV4 || t == IOC_IPV6 {
+			ip := net.ParseIP(normalized)
+			if ip == nil {
+				continue
+			}
+
+			for _, entry := range index.cidrs {
+				if entry.match.IOC.Type == t && entry.network.Contains(ip) {
+					add(entry.match)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
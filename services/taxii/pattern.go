@@ -0,0 +1,154 @@
+package taxii
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// The types of IOCs in the lookup tables.
+const (
+	IOC_MD5      = "md5"
+	IOC_SHA1     = "sha1"
+	IOC_SHA256   = "sha256"
+	IOC_SHA512   = "sha512"
+	IOC_FILENAME = "filename"
+	IOC_IPV4     = "ipv4"
+	IOC_IPV6     = "ipv6"
+	IOC_DOMAIN   = "domain"
+	IOC_URL      = "url"
+	IOC_EMAIL    = "email"
+)
+
+var (
+	// Matches equality comparisons in a STIX pattern, e.g.
+	// file:hashes.'SHA-256' = 'abc...'
+	comparisonRegex = regexp.MustCompile(
+		`([a-z0-9-]+):([A-Za-z0-9_.'-]+)\s*=\s*'((?:\\.|[^'\\])*)'`)
+
+	unescaper = strings.NewReplacer(`\'`, `'`, `\\`, `\`)
+
+	hash_types = map[string]string{
+		"MD5":    IOC_MD5,
+		"SHA1":   IOC_SHA1,
+		"SHA256": IOC_SHA256,
+		"SHA512": IOC_SHA512,
+	}
+)
+
+type Observable struct {
+	Type  string
+	Value string
+}
+
+// Extract the observables an indicator's STIX pattern matches on.
+//
+// We only handle equality comparisons of the common observable
+// types - other comparisons (e.g. MATCHES or LIKE) can not be turned
+// into lookup table entries and are ignored.
+func ParsePattern(pattern string) []Observable {
+	result := []Observable{}
+	seen := make(map[string]bool)
+
+	for _, match := range comparisonRegex.FindAllStringSubmatch(pattern, -1) {
+		ioc_type := observableType(match[1], match[2])
+		if ioc_type == "" {
+			continue
+		}
+
+		value, ok := Normalize(ioc_type, unescaper.Replace(match[3]))
+		if !ok {
+			continue
+		}
+
+		key := ioc_type + ":" + value
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, Observable{Type: ioc_type, Value: value})
+		}
+	}
+
+	return result
+}
+
+func observableType(object_type, object_path string) string {
+	switch object_type {
+	case "file":
+		if object_path == "name" {
+			return IOC_FILENAME
+		}
+
+		if strings.HasPrefix(object_path, "hashes.") {
+			name := strings.TrimPrefix(object_path, "hashes.")
+			name = strings.ToUpper(strings.Trim(name, "'"))
+			return hash_types[strings.Replace(name, "-", "", -1)]
+		}
+
+	case "ipv4-addr":
+		if object_path == "value" {
+			return IOC_IPV4
+		}
+
+	case "ipv6-addr":
+		if object_path == "value" {
+			return IOC_IPV6
+		}
+
+	case "domain-name":
+		if object_path == "value" {
+			return IOC_DOMAIN
+		}
+
+	case "url":
+		if object_path == "value" {
+			return IOC_URL
+		}
+
+	case "email-addr":
+		if object_path == "value" {
+			return IOC_EMAIL
+		}
+	}
+
+	return ""
+}
+
+// Normalize a value so lookups are not affected by case or
+// formatting. IP addresses may be CIDR ranges.
+func Normalize(ioc_type, value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", false
+	}
+
+	switch ioc_type {
+	case IOC_MD5, IOC_SHA1, IOC_SHA256, IOC_SHA512, IOC_EMAIL:
+		return strings.ToLower(value), true
+
+	case IOC_DOMAIN:
+		return strings.TrimSuffix(strings.ToLower(value), "."), true
+
+	case IOC_IPV4, IOC_IPV6:
+		if strings.Contains(value, "/") {
+			ip, network, err := net.ParseCIDR(value)
+			if err != nil {
+				return "", false
+			}
+
+			// A single address range is the same as the address.
+			ones, bits := network.Mask.Size()
+			if ones == bits {
+				return ip.String(), true
+			}
+			return network.String(), true
+		}
+
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", false
+		}
+		return ip.String(), true
+	}
+
+	return value, true
+}
@@ -0,0 +1,348 @@
+package taxii
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+const (
+	TAXII_MEDIA_TYPE = "application/taxii+json;version=2.1"
+
+	// In seconds
+	DEFAULT_POLL_INTERVAL = 60 * 60
+	DEFAULT_INDICATOR_TTL = 30 * 24 * 60 * 60
+
+	// Each page of objects is read into memory.
+	MAX_PAGE_SIZE = 100 * 1024 * 1024
+
+	// Guard against servers which keep returning more pages.
+	MAX_PAGES = 10000
+)
+
+var (
+	notAvailableError = errors.New("TAXII: Datastore not available")
+)
+
+// The envelope returned by the Get Objects endpoint.
+type envelope struct {
+	More    bool              `json:"more"`
+	Next    string            `json:"next"`
+	Objects []json.RawMessage `json:"objects"`
+}
+
+type stixIndicator struct {
+	Type           string   `json:"type"`
+	Id             string   `json:"id"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	IndicatorTypes []string `json:"indicator_types"`
+	Pattern        string   `json:"pattern"`
+	PatternType    string   `json:"pattern_type"`
+	ValidFrom      string   `json:"valid_from"`
+	ValidUntil     string   `json:"valid_until"`
+	Confidence     int      `json:"confidence"`
+	Revoked        bool     `json:"revoked"`
+}
+
+type Poller struct {
+	config_obj *config_proto.Config
+
+	// A HTTPClient used to talk to the TAXII servers.
+	Client networking.HTTPClient
+
+	// How often to check if collections are due for polling.
+	check_interval time.Duration
+}
+
+// Poll all the configured collections which are due.
+func (self *Poller) PollAll(ctx context.Context) error {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	for _, collection := range self.config_obj.GetDefaults().GetTaxiiCollections() {
+		if collection.Name == "" || collection.Url == "" {
+			logger.Error("TAXII: Collections must have a name and url")
+			continue
+		}
+
+		table, err := LoadTable(self.config_obj, collection.Name)
+		if err != nil {
+			return err
+		}
+
+		interval := time.Duration(collection.PollIntervalSec) * time.Second
+		if interval == 0 {
+			interval = DEFAULT_POLL_INTERVAL * time.Second
+		}
+
+		now := utils.GetTime().Now()
+		if now.Sub(time.Unix(table.LastPoll, 0)) < interval {
+			continue
+		}
+
+		err = self.Poll(ctx, collection, table)
+		if err != nil {
+			logger.Error("TAXII: Unable to poll collection %v: %v",
+				collection.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Fetch new indicators from the collection and update its table.
+func (self *Poller) Poll(ctx context.Context,
+	collection *config_proto.TaxiiCollectionConfig, table *Table) error {
+	now := utils.GetTime().Now()
+
+	// The collection was reconfigured to point somewhere else so
+	// start again.
+	if table.Url != collection.Url {
+		table = NewTable(collection.Name, collection.Url)
+	}
+
+	ttl := time.Duration(collection.IndicatorTtlSec) * time.Second
+	if ttl == 0 {
+		ttl = DEFAULT_INDICATOR_TTL * time.Second
+	}
+
+	table.LastPoll = now.Unix()
+	added, added_after, err := self.fetch(ctx, collection, table, now, ttl)
+	if err != nil {
+		table.LastError = err.Error()
+		_ = StoreTable(self.config_obj, table)
+		return err
+	}
+
+	if added_after != "" {
+		table.AddedAfter = added_after
+	}
+	table.LastSuccess = now.Unix()
+	table.LastError = ""
+	expired := table.Prune(now)
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	logger.Info("TAXII: Polled collection %v: %v IOCs added or updated, "+
+		"%v expired, %v total", collection.Name, added, expired, len(table.IOCs))
+
+	return StoreTable(self.config_obj, table)
+}
+
+// Page through the objects added since the last poll. Returns the
+// number of IOCs added and the added_after value for the next poll.
+func (self *Poller) fetch(ctx context.Context,
+	collection *config_proto.TaxiiCollectionConfig, table *Table,
+	now time.Time, ttl time.Duration) (int, string, error) {
+
+	base_url := strings.TrimSuffix(collection.Url, "/") + "/objects/"
+	params := url.Values{}
+	params.Set("match[type]", "indicator")
+	if table.AddedAfter != "" {
+		params.Set("added_after", table.AddedAfter)
+	}
+
+	added := 0
+	added_after := ""
+	for i := 0; i < MAX_PAGES; i++ {
+		req, err := http.NewRequestWithContext(
+			ctx, "GET", base_url+"?"+params.Encode(), nil)
+		if err != nil {
+			return 0, "", err
+		}
+
+		req.Header.Set("Accept", TAXII_MEDIA_TYPE)
+		if collection.ApiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+collection.ApiKey)
+		} else if collection.Username != "" {
+			req.SetBasicAuth(collection.Username, collection.Password)
+		}
+
+		resp, err := self.Client.Do(req)
+		if err != nil {
+			return 0, "", err
+		}
+
+		data, err := ioutil.ReadAll(io.LimitReader(resp.Body, MAX_PAGE_SIZE))
+		resp.Body.Close()
+		if err != nil {
+			return 0, "", err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return 0, "", fmt.Errorf("Request failed with status %v", resp.Status)
+		}
+
+		// The time the last object in this page was added to the
+		// collection.
+		last := resp.Header.Get("X-TAXII-Date-Added-Last")
+		if last != "" {
+			added_after = last
+		}
+
+		page := &envelope{}
+		if len(data) > 0 {
+			err = json.Unmarshal(data, page)
+			if err != nil {
+				return 0, "", fmt.Errorf("Invalid TAXII response: %w", err)
+			}
+		}
+
+		for _, obj := range page.Objects {
+			indicator := &stixIndicator{}
+			err := json.Unmarshal(obj, indicator)
+			if err != nil || indicator.Type != "indicator" {
+				continue
+			}
+			added += table.AddIndicator(indicator, now, ttl)
+		}
+
+		if !page.More || page.Next == "" {
+			return added, added_after, nil
+		}
+		params.Set("next", page.Next)
+	}
+
+	return added, added_after, nil
+}
+
+func getRawDB(config_obj *config_proto.Config) (datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, notAvailableError
+	}
+	return raw_db, nil
+}
+
+// Load the collection's table. Missing tables are empty.
+func LoadTable(config_obj *config_proto.Config, name string) (*Table, error) {
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	path_manager := paths.NewIOCPathManager()
+	data, err := raw_db.GetBuffer(config_obj, path_manager.Collection(name))
+	if err != nil || len(data) == 0 {
+		return NewTable(name, ""), nil
+	}
+
+	table := NewTable(name, "")
+	err = json.Unmarshal(data, table)
+	if err != nil {
+		return nil, err
+	}
+
+	if table.IOCs == nil {
+		table.IOCs = make(map[string]*IOC)
+	}
+	return table, nil
+}
+
+func StoreTable(config_obj *config_proto.Config, table *Table) error {
+	raw_db, err := getRawDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(table)
+	if err != nil {
+		return err
+	}
+
+	path_manager := paths.NewIOCPathManager()
+	err = raw_db.SetBuffer(config_obj, path_manager.Collection(table.Name),
+		data, utils.SyncCompleter)
+	if err != nil {
+		return err
+	}
+
+	// Make sure lookups see the new table.
+	invalidateIndex()
+	return nil
+}
+
+func (self *Poller) Start(ctx context.Context) {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(self.check_interval):
+			err := self.PollAll(ctx)
+			if err != nil {
+				logger.Debug("TAXII: %v", err)
+			}
+		}
+	}
+}
+
+func NewPoller(ctx context.Context,
+	config_obj *config_proto.Config) (*Poller, error) {
+	scope := vql_subsystem.MakeScope()
+	client, err := networking.GetDefaultHTTPClient(
+		ctx, config_obj.Client, scope, "", networking.EmptyCookieJar)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Poller{
+		config_obj:     config_obj,
+		Client:         client,
+		check_interval: time.Minute,
+	}, nil
+}
+
+// The TAXII service runs on the master frontend and keeps the IOC
+// tables up to date with the configured collections.
+func StartTaxiiService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if config_obj.Datastore == nil ||
+		len(config_obj.GetDefaults().GetTaxiiCollections()) == 0 {
+		return nil
+	}
+
+	poller, err := NewPoller(ctx, config_obj)
+	if err != nil {
+		return err
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		err := poller.PollAll(ctx)
+		if err != nil {
+			logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+			logger.Debug("TAXII: %v", err)
+		}
+
+		poller.Start(ctx)
+	}()
+
+	return nil
+}
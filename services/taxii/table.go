@@ -0,0 +1,150 @@
+package taxii
+
+import (
+	"sort"
+	"time"
+)
+
+// An entry in the IOC lookup tables.
+type IOC struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+
+	// The STIX indicator this IOC came from.
+	IndicatorId    string   `json:"indicator_id"`
+	Name           string   `json:"name,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	IndicatorTypes []string `json:"indicator_types,omitempty"`
+	Confidence     int      `json:"confidence,omitempty"`
+
+	// Freshness of the IOC (Unix seconds). The IOC expires at
+	// ValidUntil.
+	ValidFrom  int64 `json:"valid_from,omitempty"`
+	ValidUntil int64 `json:"valid_until"`
+	FirstSeen  int64 `json:"first_seen"`
+	LastSeen   int64 `json:"last_seen"`
+}
+
+func (self *IOC) Expired(now time.Time) bool {
+	return self.ValidUntil > 0 && self.ValidUntil <= now.Unix()
+}
+
+func (self *IOC) key() string {
+	return self.Type + ":" + self.Value
+}
+
+// The indicators from one TAXII collection and its polling state.
+type Table struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+
+	// Only fetch objects added after this time on the next poll.
+	AddedAfter string `json:"added_after,omitempty"`
+
+	LastPoll    int64  `json:"last_poll"`
+	LastSuccess int64  `json:"last_success"`
+	LastError   string `json:"last_error,omitempty"`
+
+	// Keyed by type:value
+	IOCs map[string]*IOC `json:"iocs"`
+}
+
+func NewTable(name, url string) *Table {
+	return &Table{
+		Name: name,
+		Url:  url,
+		IOCs: make(map[string]*IOC),
+	}
+}
+
+// Merge a STIX indicator into the table. Indicators without a
+// valid_until time expire ttl after they were last seen. Revoked
+// indicators are removed.
+func (self *Table) AddIndicator(
+	indicator *stixIndicator, now time.Time, ttl time.Duration) int {
+	if indicator.Revoked {
+		self.RemoveIndicator(indicator.Id)
+		return 0
+	}
+
+	if indicator.PatternType != "" && indicator.PatternType != "stix" {
+		return 0
+	}
+
+	valid_from := parseTime(indicator.ValidFrom)
+	valid_until := parseTime(indicator.ValidUntil)
+	if valid_until == 0 {
+		valid_until = now.Add(ttl).Unix()
+	}
+
+	count := 0
+	for _, observable := range ParsePattern(indicator.Pattern) {
+		ioc := &IOC{
+			Type:           observable.Type,
+			Value:          observable.Value,
+			IndicatorId:    indicator.Id,
+			Name:           indicator.Name,
+			Description:    indicator.Description,
+			IndicatorTypes: indicator.IndicatorTypes,
+			Confidence:     indicator.Confidence,
+			ValidFrom:      valid_from,
+			ValidUntil:     valid_until,
+			FirstSeen:      now.Unix(),
+			LastSeen:       now.Unix(),
+		}
+
+		existing, pres := self.IOCs[ioc.key()]
+		if pres {
+			ioc.FirstSeen = existing.FirstSeen
+		}
+		self.IOCs[ioc.key()] = ioc
+		count++
+	}
+
+	return count
+}
+
+func (self *Table) RemoveIndicator(id string) {
+	for k, ioc := range self.IOCs {
+		if ioc.IndicatorId == id {
+			delete(self.IOCs, k)
+		}
+	}
+}
+
+// Remove expired IOCs and return how many were removed.
+func (self *Table) Prune(now time.Time) int {
+	count := 0
+	for k, ioc := range self.IOCs {
+		if ioc.Expired(now) {
+			delete(self.IOCs, k)
+			count++
+		}
+	}
+	return count
+}
+
+// All IOCs sorted by type and value.
+func (self *Table) Sorted() []*IOC {
+	result := make([]*IOC, 0, len(self.IOCs))
+	for _, ioc := range self.IOCs {
+		result = append(result, ioc)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].key() < result[j].key()
+	})
+	return result
+}
+
+func parseTime(value string) int64 {
+	if value == "" {
+		return 0
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,244 @@
+package taxii_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services/taxii"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestParsePattern(t *testing.T) {
+	observables := taxii.ParsePattern(
+		`[file:hashes.'SHA-256' = 'AAAA' OR file:hashes.MD5 = 'bbbb'] ` +
+			`OR [ipv4-addr:value = '10.0.0.1/32'] ` +
+			`OR [ipv4-addr:value = '192.168.0.0/16'] ` +
+			`OR [domain-name:value = 'Evil.COM.'] ` +
+			`OR [url:value = 'http://evil.com/it\'s'] ` +
+			`OR [file:name MATCHES '^evil'] ` +
+			`OR [ipv6-addr:value = 'invalid']`)
+
+	assert.Equal(t, []taxii.Observable{
+		{Type: taxii.IOC_SHA256, Value: "aaaa"},
+		{Type: taxii.IOC_MD5, Value: "bbbb"},
+		{Type: taxii.IOC_IPV4, Value: "10.0.0.1"},
+		{Type: taxii.IOC_IPV4, Value: "192.168.0.0/16"},
+		{Type: taxii.IOC_DOMAIN, Value: "evil.com"},
+		{Type: taxii.IOC_URL, Value: "http://evil.com/it's"},
+	}, observables)
+}
+
+type mockResponse struct {
+	body       string
+	added_last string
+}
+
+type MockClient struct {
+	responses map[string]mockResponse
+	requests  []string
+	auth      []string
+}
+
+func (self *MockClient) Do(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	self.requests = append(self.requests, url)
+	self.auth = append(self.auth, req.Header.Get("Authorization"))
+
+	response, pres := self.responses[url]
+	if !pres {
+		return &http.Response{
+			StatusCode: 404,
+			Status:     "404 Not Found",
+			Body:       ioutil.NopCloser(&bytes.Buffer{}),
+		}, nil
+	}
+
+	header := http.Header{}
+	if response.added_last != "" {
+		header.Set("X-TAXII-Date-Added-Last", response.added_last)
+	}
+
+	return &http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(response.body))),
+	}, nil
+}
+
+const (
+	collectionURL = "https://taxii.example.com/api1/collections/abc/"
+	objectsURL    = collectionURL + "objects/?"
+)
+
+type TaxiiTestSuite struct {
+	test_utils.TestSuite
+	clock *utils.MockClock
+}
+
+func (self *TaxiiTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	self.clock = utils.NewMockClock(time.Unix(1700000000, 0))
+	self.ConfigObj.Defaults.TaxiiCollections = []*config_proto.TaxiiCollectionConfig{{
+		Name:            "Feed",
+		Url:             collectionURL,
+		ApiKey:          "secret",
+		IndicatorTtlSec: 3600,
+	}}
+}
+
+func (self *TaxiiTestSuite) lookup(ioc_type, value string) []string {
+	matches, err := taxii.Lookup(self.ConfigObj, ioc_type, value, false)
+	assert.NoError(self.T(), err)
+
+	result := []string{}
+	for _, m := range matches {
+		result = append(result, m.Collection+":"+m.IOC.Name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (self *TaxiiTestSuite) TestPoller() {
+	closer := utils.MockTime(self.clock)
+	defer closer()
+
+	ctx := context.Background()
+	mock := &MockClient{responses: map[string]mockResponse{
+		objectsURL + "match%5Btype%5D=indicator": {
+			body: `{"more": true, "next": "page2", "objects": [
+ {"type": "indicator", "id": "indicator--1", "name": "Evil Hash",
+  "pattern_type": "stix",
+  "pattern": "[file:hashes.'SHA-256' = 'AAAA']",
+  "valid_from": "2023-01-01T00:00:00Z"},
+ {"type": "malware", "id": "malware--1", "name": "Ignored"}
+]}`,
+			added_last: "2023-11-14T00:00:00.000Z",
+		},
+		objectsURL + "match%5Btype%5D=indicator&next=page2": {
+			body: `{"more": false, "objects": [
+ {"type": "indicator", "id": "indicator--2", "name": "Evil Net",
+  "pattern": "[ipv4-addr:value = '203.0.113.0/24'] OR [domain-name:value = 'evil.com']",
+  "valid_from": "2023-01-01T00:00:00Z",
+  "valid_until": "2030-01-01T00:00:00Z"},
+ {"type": "indicator", "id": "indicator--3", "name": "Sigma rule",
+  "pattern_type": "sigma", "pattern": "title: x"}
+]}`,
+			added_last: "2023-11-14T01:00:00.000Z",
+		},
+	}}
+
+	poller, err := taxii.NewPoller(ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+	poller.Client = mock
+
+	assert.NoError(self.T(), poller.PollAll(ctx))
+	assert.Equal(self.T(), 2, len(mock.requests))
+	assert.Equal(self.T(), "Bearer secret", mock.auth[0])
+
+	assert.Equal(self.T(), []string{"Feed:Evil Hash"},
+		self.lookup(taxii.IOC_SHA256, "aaaa"))
+	assert.Equal(self.T(), []string{"Feed:Evil Net"},
+		self.lookup("", "203.0.113.5"))
+	assert.Equal(self.T(), []string{"Feed:Evil Net"},
+		self.lookup(taxii.IOC_DOMAIN, "EVIL.com"))
+	assert.Equal(self.T(), []string{}, self.lookup("", "198.51.100.1"))
+
+	tables, err := taxii.GetTables(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(tables))
+	assert.Equal(self.T(), "2023-11-14T01:00:00.000Z", tables[0].AddedAfter)
+	assert.Equal(self.T(), int64(1700000000), tables[0].LastSuccess)
+	assert.Equal(self.T(), 3, len(tables[0].IOCs))
+
+	// Not due for polling yet.
+	assert.NoError(self.T(), poller.PollAll(ctx))
+	assert.Equal(self.T(), 2, len(mock.requests))
+
+	// The next poll only asks for new objects. The hash indicator
+	// is revoked.
+	mock.responses[objectsURL+
+		"added_after=2023-11-14T01%3A00%3A00.000Z&match%5Btype%5D=indicator"] =
+		mockResponse{body: `{"objects": [
+ {"type": "indicator", "id": "indicator--1", "revoked": true,
+  "pattern": "[file:hashes.'SHA-256' = 'AAAA']"}
+]}`}
+
+	self.clock.Set(time.Unix(1700000000+1800, 0))
+	err = poller.Poll(ctx, self.ConfigObj.Defaults.TaxiiCollections[0], tables[0])
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, len(mock.requests))
+	assert.Equal(self.T(), []string{}, self.lookup(taxii.IOC_SHA256, "aaaa"))
+	assert.Equal(self.T(), []string{"Feed:Evil Net"},
+		self.lookup(taxii.IOC_DOMAIN, "evil.com"))
+
+	// Failed polls are recorded but keep the existing IOCs.
+	delete(mock.responses, objectsURL+
+		"added_after=2023-11-14T01%3A00%3A00.000Z&match%5Btype%5D=indicator")
+	tables, err = taxii.GetTables(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	err = poller.Poll(ctx, self.ConfigObj.Defaults.TaxiiCollections[0], tables[0])
+	assert.Error(self.T(), err)
+
+	tables, err = taxii.GetTables(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.Contains(self.T(), tables[0].LastError, "404")
+	assert.Equal(self.T(), 2, len(tables[0].IOCs))
+}
+
+func (self *TaxiiTestSuite) TestExpiry() {
+	closer := utils.MockTime(self.clock)
+	defer closer()
+
+	table := taxii.NewTable("Feed", collectionURL)
+	now := utils.GetTime().Now()
+
+	ctx := context.Background()
+	mock := &MockClient{responses: map[string]mockResponse{
+		objectsURL + "match%5Btype%5D=indicator": {
+			body: `{"objects": [
+ {"type": "indicator", "id": "indicator--1", "name": "Short lived",
+  "pattern": "[domain-name:value = 'a.com']"},
+ {"type": "indicator", "id": "indicator--2", "name": "Long lived",
+  "pattern": "[domain-name:value = 'b.com']",
+  "valid_until": "2030-01-01T00:00:00Z"}
+]}`,
+		},
+	}}
+
+	poller, err := taxii.NewPoller(ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+	poller.Client = mock
+
+	err = poller.Poll(ctx, self.ConfigObj.Defaults.TaxiiCollections[0], table)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), []string{"Feed:Short lived"},
+		self.lookup(taxii.IOC_DOMAIN, "a.com"))
+
+	// Indicators without valid_until expire after the TTL.
+	self.clock.Set(now.Add(2 * time.Hour))
+	assert.Equal(self.T(), []string{}, self.lookup(taxii.IOC_DOMAIN, "a.com"))
+	assert.Equal(self.T(), []string{"Feed:Long lived"},
+		self.lookup(taxii.IOC_DOMAIN, "b.com"))
+
+	matches, err := taxii.Lookup(self.ConfigObj, taxii.IOC_DOMAIN, "a.com", true)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(matches))
+
+	// Expired IOCs are removed on the next poll.
+	assert.Equal(self.T(), 1, table.Prune(utils.GetTime().Now()))
+}
+
+func TestTaxii(t *testing.T) {
+	suite.Run(t, &TaxiiTestSuite{})
+}
@@ -13,6 +13,7 @@ import (
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/utils"
@@ -406,22 +407,37 @@ func (self *UserStorageManager) GetFavorites(
 		return nil, err
 	}
 
-	components := path_manager.FavoriteDir(fav_type)
-	children, err := db.ListChildren(config_obj, components)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, child := range children {
-		if child.IsDir() {
-			continue
+	// Private favorites are listed before the ones shared with the
+	// org.
+	for _, item := range []struct {
+		dir  api.DSPathSpec
+		path func(name string) api.DSPathSpec
+	}{{
+		dir: path_manager.FavoriteDir(fav_type),
+		path: func(name string) api.DSPathSpec {
+			return path_manager.Favorites(name, fav_type)
+		},
+	}, {
+		dir: paths.SharedFavoriteDir(fav_type),
+		path: func(name string) api.DSPathSpec {
+			return paths.SharedFavorite(name, fav_type)
+		},
+	}} {
+		children, err := db.ListChildren(config_obj, item.dir)
+		if err != nil {
+			return nil, err
 		}
 
-		fav := &api_proto.Favorite{}
-		err = db.GetSubject(config_obj,
-			path_manager.Favorites(child.Base(), fav_type), fav)
-		if err == nil {
-			result.Items = append(result.Items, fav)
+		for _, child := range children {
+			if child.IsDir() {
+				continue
+			}
+
+			fav := &api_proto.Favorite{}
+			err = db.GetSubject(config_obj, item.path(child.Base()), fav)
+			if err == nil {
+				result.Items = append(result.Items, fav)
+			}
 		}
 	}
 
@@ -66,6 +66,15 @@ type VFSService interface {
 		config_obj *config_proto.Config,
 		in *api_proto.GetTableRequest) (*api_proto.GetTableResponse, error)
 
+	// Compares the current listing of the directory with a previous
+	// refresh (specified by in.FlowId, or the most recent one if not
+	// specified). Returns a table of added, removed and modified
+	// files.
+	DiffDirectory(
+		ctx context.Context,
+		config_obj *config_proto.Config,
+		in *api_proto.GetTableRequest) (*api_proto.GetTableResponse, error)
+
 	StatDirectory(
 		config_obj *config_proto.Config,
 		client_id string,
@@ -0,0 +1,234 @@
+package vfs_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+)
+
+// How many previous refreshes of each directory we remember.
+const MAX_PREVIOUS_LISTINGS = 10
+
+// The columns compared between the two listings and the name of the
+// column holding the previous value.
+var diffColumns = []struct {
+	column, previous string
+}{
+	{"Mode", "PreviousMode"},
+	{"Size", "PreviousSize"},
+	{"mtime", "PreviousMtime"},
+}
+
+// Each refresh of a directory replaces its VFS record. Carry the
+// older records forward so previous refreshes can still be found.
+func addPreviousListings(
+	config_obj *config_proto.Config, db datastore.DataStore,
+	vfs_path api.DSPathSpec, record *api_proto.VFSListResponse) {
+
+	existing := &api_proto.VFSListResponse{}
+	err := db.GetSubject(config_obj, vfs_path, existing)
+	if err != nil || existing.FlowId == "" {
+		return
+	}
+
+	// The same flow is updating the directory again.
+	if existing.FlowId == record.FlowId {
+		record.Previous = existing.Previous
+		return
+	}
+
+	previous := existing.Previous
+	existing.Previous = nil
+	existing.Response = ""
+
+	record.Previous = append([]*api_proto.VFSListResponse{existing}, previous...)
+	if len(record.Previous) > MAX_PREVIOUS_LISTINGS {
+		record.Previous = record.Previous[:MAX_PREVIOUS_LISTINGS]
+	}
+}
+
+func (self *VFSService) DiffDirectory(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	in *api_proto.GetTableRequest) (*api_proto.GetTableResponse, error) {
+
+	stat, err := self.StatDirectory(config_obj, in.ClientId, in.VfsComponents)
+	if err != nil {
+		return nil, err
+	}
+
+	var base *api_proto.VFSListResponse
+	for _, previous := range stat.Previous {
+		if in.FlowId == "" || previous.FlowId == in.FlowId {
+			base = previous
+			break
+		}
+	}
+
+	if base == nil {
+		return nil, fmt.Errorf("No previous listing of this directory found for %v",
+			in.FlowId)
+	}
+
+	old_rows, err := readListing(ctx, config_obj, base)
+	if err != nil {
+		return nil, err
+	}
+
+	new_rows, err := readListing(ctx, config_obj, stat)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffListings(old_rows, new_rows)
+
+	opts := json.GetJsonOptsForTimezone(in.Timezone)
+	result := &api_proto.GetTableResponse{
+		Columns: []string{"Change", "Name", "Mode", "Size", "mtime",
+			"PreviousMode", "PreviousSize", "PreviousMtime"},
+		TotalRows: int64(len(changes)),
+	}
+
+	for idx := in.StartRow; idx < uint64(len(changes)) &&
+		idx < in.StartRow+in.Rows; idx++ {
+		row := changes[idx]
+		row_proto := &api_proto.Row{}
+		for _, column := range result.Columns {
+			value, _ := row.Get(column)
+			row_proto.Cell = append(row_proto.Cell, json.AnyToString(value, opts))
+		}
+		result.Rows = append(result.Rows, row_proto)
+	}
+
+	return result, nil
+}
+
+// Compare two listings of the same directory by file name. Files
+// which did not change are not reported.
+func diffListings(old_rows, new_rows []*ordereddict.Dict) []*ordereddict.Dict {
+	result := []*ordereddict.Dict{}
+
+	old_lookup := make(map[string]*ordereddict.Dict)
+	for _, row := range old_rows {
+		name, _ := row.GetString("Name")
+		old_lookup[name] = row
+	}
+
+	seen := make(map[string]bool)
+	for _, row := range new_rows {
+		name, _ := row.GetString("Name")
+		seen[name] = true
+
+		old_row, pres := old_lookup[name]
+		if !pres {
+			result = append(result, makeDiffRow("added", name, row, nil))
+			continue
+		}
+
+		for _, c := range diffColumns {
+			if getColumn(row, c.column) != getColumn(old_row, c.column) {
+				result = append(result, makeDiffRow("modified", name, row, old_row))
+				break
+			}
+		}
+	}
+
+	for _, row := range old_rows {
+		name, _ := row.GetString("Name")
+		if !seen[name] {
+			result = append(result, makeDiffRow("removed", name, nil, row))
+		}
+	}
+
+	return result
+}
+
+func getColumn(row *ordereddict.Dict, column string) string {
+	value, _ := row.Get(column)
+	return json.AnyToString(value, json.DefaultEncOpts())
+}
+
+func makeDiffRow(change, name string,
+	row, old_row *ordereddict.Dict) *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("Change", change).
+		Set("Name", name)
+
+	for _, c := range diffColumns {
+		var value interface{}
+		if row != nil {
+			value, _ = row.Get(c.column)
+		}
+		result.Set(c.column, value)
+	}
+
+	for _, c := range diffColumns {
+		var value interface{}
+		if old_row != nil {
+			value, _ = old_row.Get(c.column)
+		}
+		result.Set(c.previous, value)
+	}
+
+	return result
+}
+
+// Read the rows of a directory listing from the flow that collected
+// it.
+func readListing(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	listing *api_proto.VFSListResponse) ([]*ordereddict.Dict, error) {
+	result := []*ordereddict.Dict{}
+	if listing.TotalRows == 0 {
+		return result, nil
+	}
+
+	artifact_name := listing.Artifact
+	if artifact_name == "" {
+		artifact_name = "System.VFS.ListDirectory"
+	}
+
+	path_manager := artifacts.NewArtifactPathManagerWithMode(
+		config_obj, listing.ClientId, listing.FlowId,
+		artifact_name, paths.MODE_CLIENT)
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	reader, err := result_sets.NewResultSetReader(
+		file_store_factory, path_manager.Path())
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	err = reader.SeekToRow(int64(listing.StartIdx))
+	if err != nil {
+		return nil, err
+	}
+
+	count := listing.StartIdx
+	for row := range reader.Rows(ctx) {
+		count++
+		if count > listing.EndIdx {
+			break
+		}
+
+		name, _ := row.GetString("Name")
+		if name == "." || name == ".." || name == "" {
+			continue
+		}
+		result = append(result, row)
+	}
+
+	return result, nil
+}
@@ -324,9 +324,11 @@ func (self *VFSService) flush_state(
 		return err
 	}
 
+	vfs_path := client_path_manager.VFSPath(vfs_components)
+	addPreviousListings(config_obj, db, vfs_path, record)
+
 	return db.SetSubjectWithCompletion(config_obj,
-		client_path_manager.VFSPath(vfs_components),
-		record, utils.SyncCompleter)
+		vfs_path, record, utils.SyncCompleter)
 }
 
 // Modern clients do the above work on the client removing load from
@@ -395,8 +397,11 @@ func (self *VFSService) ProcessListDirectory(
 
 		// Write the record in the background
 		client_path_manager := paths.NewClientPathManager(flow.ClientId)
+		vfs_path := client_path_manager.VFSPath(components)
+		addPreviousListings(config_obj, db, vfs_path, stats)
+
 		_ = db.SetSubjectWithCompletion(config_obj,
-			client_path_manager.VFSPath(components), stats, utils.BackgroundWriter)
+			vfs_path, stats, utils.BackgroundWriter)
 	}
 }
 
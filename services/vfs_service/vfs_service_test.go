@@ -424,6 +424,80 @@ func (self *VFSServiceTestSuite) TestRecursiveVFSListDirectoryApiAccess() {
 		json.MustMarshalIndent(golden))
 }
 
+func (self *VFSServiceTestSuite) TestVFSDiffDirectory() {
+	vfs_service, err := services.GetVFSService(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// Refresh the same directory twice.
+	self.flow_id = "F.1"
+	self.EmulateCollection(
+		"System.VFS.ListDirectory", []*ordereddict.Dict{
+			makeStat("/a/b", "A"),
+			makeStat("/a/b", "B").Set("Size", 10),
+			makeStat("/a/b", "C"),
+		})
+
+	vtesting.WaitUntil(2*time.Second, self.T(), func() bool {
+		stat, err := vfs_service.StatDirectory(self.ConfigObj,
+			self.client_id, []string{"file", "a", "b"})
+		return err == nil && stat.FlowId == "F.1"
+	})
+
+	self.flow_id = "F.2"
+	self.EmulateCollection(
+		"System.VFS.ListDirectory", []*ordereddict.Dict{
+			makeStat("/a/b", "B").Set("Size", 20),
+			makeStat("/a/b", "C"),
+			makeStat("/a/b", "D"),
+		})
+
+	stat := &api_proto.VFSListResponse{}
+	vtesting.WaitUntil(2*time.Second, self.T(), func() bool {
+		stat, err = vfs_service.StatDirectory(self.ConfigObj,
+			self.client_id, []string{"file", "a", "b"})
+		return err == nil && stat.FlowId == "F.2"
+	})
+
+	// The previous refresh is remembered.
+	assert.Equal(self.T(), 1, len(stat.Previous))
+	assert.Equal(self.T(), "F.1", stat.Previous[0].FlowId)
+	assert.Equal(self.T(), uint64(3), stat.Previous[0].TotalRows)
+
+	api_service := &api.ApiServer{}
+	table, err := api_service.VFSListDirectoryFiles(self.Ctx,
+		&api_proto.GetTableRequest{
+			ClientId:      self.client_id,
+			FlowId:        "F.1",
+			Type:          "diff",
+			VfsComponents: []string{"file", "a", "b"},
+			Rows:          100,
+		})
+	assert.NoError(self.T(), err)
+
+	changes := []string{}
+	for _, row := range table.Rows {
+		changes = append(changes, row.Cell[0]+" "+row.Cell[1])
+	}
+	assert.Equal(self.T(), []string{
+		"modified B", "added D", "removed A"}, changes)
+	assert.Equal(self.T(), int64(3), table.TotalRows)
+
+	// Size and PreviousSize of the modified file.
+	assert.Equal(self.T(), "20", table.Rows[0].Cell[3])
+	assert.Equal(self.T(), "10", table.Rows[0].Cell[6])
+
+	// An unknown refresh is an error.
+	_, err = api_service.VFSListDirectoryFiles(self.Ctx,
+		&api_proto.GetTableRequest{
+			ClientId:      self.client_id,
+			FlowId:        "F.3",
+			Type:          "diff",
+			VfsComponents: []string{"file", "a", "b"},
+			Rows:          100,
+		})
+	assert.Error(self.T(), err)
+}
+
 func (self *VFSServiceTestSuite) TestVFSDownload() {
 	flow_path_manager := paths.NewFlowPathManager(self.client_id, self.flow_id)
 
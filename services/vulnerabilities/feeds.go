@@ -0,0 +1,440 @@
+package vulnerabilities
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+const (
+	TYPE_OSV = "osv"
+	TYPE_NVD = "nvd"
+
+	// Databases are downloaded into memory.
+	MAX_DOWNLOAD_SIZE = 2 * 1024 * 1024 * 1024
+)
+
+// Read the database from the offline bundle or download it.
+func readSource(ctx context.Context, client networking.HTTPClient,
+	source *config_proto.VulnerabilitySource) ([]byte, error) {
+	if source.Path != "" {
+		fd, err := os.Open(source.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer fd.Close()
+
+		return ioutil.ReadAll(io.LimitReader(fd, MAX_DOWNLOAD_SIZE))
+	}
+
+	if source.Url == "" {
+		return nil, fmt.Errorf("Vulnerability source %v has no url or path",
+			source.Name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", source.Url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Download failed with status %v", resp.Status)
+	}
+
+	return ioutil.ReadAll(io.LimitReader(resp.Body, MAX_DOWNLOAD_SIZE))
+}
+
+// Databases are JSON documents, optionally gzip compressed or
+// bundled in a zip file (e.g. the OSV all.zip exports).
+func parseSource(data []byte, source *config_proto.VulnerabilitySource,
+	cb func(advisory *Advisory)) error {
+	parse := parseOSV
+	switch strings.ToLower(source.Type) {
+	case TYPE_OSV, "":
+	case TYPE_NVD:
+		parse = parseNVD
+	default:
+		return fmt.Errorf("Unsupported vulnerability source type %v",
+			source.Type)
+	}
+
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		zip_reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return err
+		}
+
+		for _, member := range zip_reader.File {
+			if !strings.HasSuffix(strings.ToLower(member.Name), ".json") {
+				continue
+			}
+
+			fd, err := member.Open()
+			if err != nil {
+				return err
+			}
+			member_data, err := ioutil.ReadAll(io.LimitReader(fd, MAX_DOWNLOAD_SIZE))
+			fd.Close()
+			if err != nil {
+				return err
+			}
+
+			err = parse(member_data, source, cb)
+			if err != nil {
+				return fmt.Errorf("%v: %w", member.Name, err)
+			}
+		}
+		return nil
+	}
+
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		data, err = ioutil.ReadAll(io.LimitReader(gz, MAX_DOWNLOAD_SIZE))
+		if err != nil {
+			return err
+		}
+	}
+
+	return parse(data, source, cb)
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges []struct {
+		Type   string     `json:"type"`
+		Events []osvEvent `json:"events"`
+	} `json:"ranges"`
+	Versions         []string               `json:"versions"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific"`
+}
+
+type osvRecord struct {
+	Id       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Summary  string   `json:"summary"`
+	Details  string   `json:"details"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected         []osvAffected          `json:"affected"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific"`
+}
+
+// A file may hold a single OSV record or an array of records.
+func parseOSV(data []byte, source *config_proto.VulnerabilitySource,
+	cb func(advisory *Advisory)) error {
+	data = bytes.TrimSpace(data)
+
+	var records []*osvRecord
+	if bytes.HasPrefix(data, []byte("[")) {
+		err := json.Unmarshal(data, &records)
+		if err != nil {
+			return err
+		}
+	} else {
+		record := &osvRecord{}
+		err := json.Unmarshal(data, record)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+
+	for _, record := range records {
+		advisory := osvAdvisory(record, source)
+		if advisory != nil {
+			cb(advisory)
+		}
+	}
+	return nil
+}
+
+func osvAdvisory(record *osvRecord,
+	source *config_proto.VulnerabilitySource) *Advisory {
+	advisory := &Advisory{
+		Id:       record.Id,
+		Aliases:  record.Aliases,
+		Summary:  record.Summary,
+		Severity: stringField(record.DatabaseSpecific, "severity"),
+		Source:   source.Name,
+	}
+
+	if advisory.Summary == "" {
+		advisory.Summary = firstLine(record.Details)
+	}
+
+	if advisory.Severity == "" && len(record.Severity) > 0 {
+		advisory.Severity = record.Severity[0].Score
+	}
+
+	for _, affected := range record.Affected {
+		if !matchEcosystem(affected.Package.Ecosystem, source.Ecosystems) {
+			continue
+		}
+
+		item := &AffectedPackage{
+			Name:      affected.Package.Name,
+			Ecosystem: affected.Package.Ecosystem,
+			Versions:  affected.Versions,
+		}
+
+		for _, r := range affected.Ranges {
+			// GIT ranges are commit hashes.
+			if r.Type == "GIT" {
+				continue
+			}
+			item.Ranges = append(item.Ranges, osvRanges(r.Events)...)
+		}
+
+		if advisory.Severity == "" {
+			advisory.Severity = stringField(affected.DatabaseSpecific, "severity")
+		}
+
+		if len(item.Ranges) > 0 || len(item.Versions) > 0 {
+			advisory.Affected = append(advisory.Affected, item)
+		}
+	}
+
+	if advisory.Id == "" || len(advisory.Affected) == 0 {
+		return nil
+	}
+	return advisory
+}
+
+// OSV describes ranges as a list of events. Each introduced event
+// starts a range which ends at the next fixed or last_affected event.
+func osvRanges(events []osvEvent) []*VersionRange {
+	var result []*VersionRange
+	var current *VersionRange
+
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			if current != nil {
+				result = append(result, current)
+			}
+			current = &VersionRange{}
+			if event.Introduced != "0" {
+				current.Introduced = event.Introduced
+			}
+
+		case event.Fixed != "" || event.LastAffected != "":
+			if current == nil {
+				current = &VersionRange{}
+			}
+			current.Fixed = event.Fixed
+			current.LastAffected = event.LastAffected
+			result = append(result, current)
+			current = nil
+		}
+	}
+
+	if current != nil {
+		result = append(result, current)
+	}
+	return result
+}
+
+// Ecosystems may carry a release (e.g. Debian:11).
+func matchEcosystem(ecosystem string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	name := strings.SplitN(ecosystem, ":", 2)[0]
+	for _, item := range allowed {
+		if strings.EqualFold(item, ecosystem) || strings.EqualFold(item, name) {
+			return true
+		}
+	}
+	return false
+}
+
+type nvdCPEMatch struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	Criteria              string `json:"criteria"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
+
+type nvdMetric struct {
+	BaseSeverity string `json:"baseSeverity"`
+	CvssData     struct {
+		BaseSeverity string `json:"baseSeverity"`
+	} `json:"cvssData"`
+}
+
+type nvdCVE struct {
+	Id           string `json:"id"`
+	Descriptions []struct {
+		Lang  string `json:"lang"`
+		Value string `json:"value"`
+	} `json:"descriptions"`
+	Metrics map[string][]nvdMetric `json:"metrics"`
+
+	Configurations []struct {
+		Nodes []struct {
+			CpeMatch []nvdCPEMatch `json:"cpeMatch"`
+		} `json:"nodes"`
+	} `json:"configurations"`
+}
+
+type nvdFeed struct {
+	Vulnerabilities []struct {
+		Cve nvdCVE `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+// NVD databases are CVE API 2.0 responses (or the equivalent JSON
+// feed files).
+func parseNVD(data []byte, source *config_proto.VulnerabilitySource,
+	cb func(advisory *Advisory)) error {
+	feed := &nvdFeed{}
+	err := json.Unmarshal(data, feed)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range feed.Vulnerabilities {
+		advisory := nvdAdvisory(&item.Cve, source)
+		if advisory != nil {
+			cb(advisory)
+		}
+	}
+	return nil
+}
+
+func nvdAdvisory(cve *nvdCVE,
+	source *config_proto.VulnerabilitySource) *Advisory {
+	advisory := &Advisory{
+		Id:     cve.Id,
+		Source: source.Name,
+	}
+
+	for _, description := range cve.Descriptions {
+		if description.Lang == "en" {
+			advisory.Summary = firstLine(description.Value)
+			break
+		}
+	}
+
+	// Prefer the most recent CVSS version.
+	for _, name := range []string{
+		"cvssMetricV40", "cvssMetricV31", "cvssMetricV30", "cvssMetricV2"} {
+		for _, metric := range cve.Metrics[name] {
+			advisory.Severity = metric.CvssData.BaseSeverity
+			if advisory.Severity == "" {
+				advisory.Severity = metric.BaseSeverity
+			}
+			if advisory.Severity != "" {
+				break
+			}
+		}
+		if advisory.Severity != "" {
+			break
+		}
+	}
+
+	packages := make(map[string]*AffectedPackage)
+	names := []string{}
+
+	for _, configuration := range cve.Configurations {
+		for _, node := range configuration.Nodes {
+			for _, match := range node.CpeMatch {
+				if !match.Vulnerable {
+					continue
+				}
+
+				// cpe:2.3:part:vendor:product:version:...
+				parts := strings.Split(match.Criteria, ":")
+				if len(parts) < 6 {
+					continue
+				}
+				product := parts[4]
+				version := parts[5]
+
+				item, pres := packages[product]
+				if !pres {
+					item = &AffectedPackage{Name: product, Ecosystem: "NVD"}
+					packages[product] = item
+					names = append(names, product)
+				}
+
+				if version != "*" && version != "-" {
+					item.Versions = append(item.Versions, version)
+					continue
+				}
+
+				r := &VersionRange{
+					Introduced:   match.VersionStartIncluding,
+					Fixed:        match.VersionEndExcluding,
+					LastAffected: match.VersionEndIncluding,
+				}
+				if match.VersionStartExcluding != "" {
+					r.Introduced = match.VersionStartExcluding
+					r.IntroducedExclusive = true
+				}
+
+				// Without bounds every version of the product would
+				// match.
+				if r.Introduced == "" && r.Fixed == "" && r.LastAffected == "" {
+					continue
+				}
+				item.Ranges = append(item.Ranges, r)
+			}
+		}
+	}
+
+	for _, name := range names {
+		item := packages[name]
+		if len(item.Ranges) > 0 || len(item.Versions) > 0 {
+			advisory.Affected = append(advisory.Affected, item)
+		}
+	}
+
+	if advisory.Id == "" || len(advisory.Affected) == 0 {
+		return nil
+	}
+	return advisory
+}
+
+func stringField(item map[string]interface{}, field string) string {
+	value, _ := item[field].(string)
+	return value
+}
+
+func firstLine(text string) string {
+	return strings.TrimSpace(strings.SplitN(strings.TrimSpace(text), "\n", 2)[0])
+}
@@ -0,0 +1,169 @@
+package vulnerabilities
+
+import (
+	"strings"
+	"sync"
+
+	"www.velocidex.com/golang/velociraptor/services/asset_inventory"
+)
+
+// A vulnerability normalized from the OSV or NVD formats.
+type Advisory struct {
+	Id       string             `json:"id"`
+	Aliases  []string           `json:"aliases,omitempty"`
+	Summary  string             `json:"summary,omitempty"`
+	Severity string             `json:"severity,omitempty"`
+	Affected []*AffectedPackage `json:"affected"`
+
+	// The name of the database the advisory came from.
+	Source string `json:"source,omitempty"`
+}
+
+type AffectedPackage struct {
+	Name      string          `json:"name"`
+	Ecosystem string          `json:"ecosystem,omitempty"`
+	Ranges    []*VersionRange `json:"ranges,omitempty"`
+
+	// Individual affected versions.
+	Versions []string `json:"versions,omitempty"`
+}
+
+// A range of affected versions. An empty Introduced means all
+// versions before Fixed (or LastAffected) are affected, and an empty
+// Fixed and LastAffected means all versions after Introduced are
+// affected.
+type VersionRange struct {
+	Introduced          string `json:"introduced,omitempty"`
+	IntroducedExclusive bool   `json:"introduced_exclusive,omitempty"`
+	Fixed               string `json:"fixed,omitempty"`
+	LastAffected        string `json:"last_affected,omitempty"`
+}
+
+func (self *VersionRange) Contains(version string) bool {
+	if self.Introduced != "" {
+		result := asset_inventory.CompareVersions(version, self.Introduced)
+		if result < 0 || (result == 0 && self.IntroducedExclusive) {
+			return false
+		}
+	}
+
+	if self.Fixed != "" &&
+		asset_inventory.CompareVersions(version, self.Fixed) >= 0 {
+		return false
+	}
+
+	if self.LastAffected != "" &&
+		asset_inventory.CompareVersions(version, self.LastAffected) > 0 {
+		return false
+	}
+
+	return true
+}
+
+// Returns the matching range if the version is affected. Versions
+// listed individually return an empty range.
+func (self *AffectedPackage) Match(version string) (*VersionRange, bool) {
+	for _, v := range self.Versions {
+		if asset_inventory.CompareVersions(version, v) == 0 {
+			return &VersionRange{}, true
+		}
+	}
+
+	for _, r := range self.Ranges {
+		if r.Contains(version) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Package names differ in case and separators between inventories
+// and databases (e.g. "HTTP Server" and http_server).
+func normalizeName(name string) string {
+	return strings.Map(func(c rune) rune {
+		switch c {
+		case ' ', '-', '.':
+			return '_'
+		}
+		return c
+	}, strings.ToLower(strings.TrimSpace(name)))
+}
+
+type Match struct {
+	Advisory *Advisory
+	Package  string
+	Version  string
+
+	// The first fixed version of the matching range, if known.
+	Fixed string
+}
+
+// The loaded advisories indexed by package name.
+type Database struct {
+	mu       sync.Mutex
+	packages map[string][]*Advisory
+	count    int
+}
+
+func NewDatabase() *Database {
+	return &Database{
+		packages: make(map[string][]*Advisory),
+	}
+}
+
+func (self *Database) Add(advisory *Advisory) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, affected := range advisory.Affected {
+		key := normalizeName(affected.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		self.packages[key] = append(self.packages[key], advisory)
+	}
+	self.count++
+}
+
+func (self *Database) Len() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.count
+}
+
+// Find the advisories affecting this version of the package.
+func (self *Database) Find(name, version string) []*Match {
+	if name == "" || version == "" {
+		return nil
+	}
+
+	key := normalizeName(name)
+
+	self.mu.Lock()
+	advisories := self.packages[key]
+	self.mu.Unlock()
+
+	var result []*Match
+	for _, advisory := range advisories {
+		for _, affected := range advisory.Affected {
+			if normalizeName(affected.Name) != key {
+				continue
+			}
+
+			r, ok := affected.Match(version)
+			if ok {
+				result = append(result, &Match{
+					Advisory: advisory,
+					Package:  name,
+					Version:  version,
+					Fixed:    r.Fixed,
+				})
+				break
+			}
+		}
+	}
+	return result
+}
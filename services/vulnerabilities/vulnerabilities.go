@@ -0,0 +1,671 @@
+/*
+  The vulnerability service matches the software inventory of each
+  client against vulnerability databases in the OSV or NVD formats.
+
+  The databases are configured in Defaults.vulnerabilities. They are
+  downloaded (or loaded from an offline bundle on the server) on a
+  schedule, normalized and stored in the file store so they survive
+  restarts.
+
+  Each time the asset inventory service records a new inventory
+  version the client is matched again and its report is rewritten. A
+  fleet level report lists each vulnerability with the affected
+  clients. Both reports are result sets which can be viewed with the
+  vulnerabilities() VQL plugin or the API.
+*/
+
+package vulnerabilities
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/asset_inventory"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+const (
+	DEFAULT_SYNC_INTERVAL = int64(24 * 3600)
+
+	// How often to write the fleet report when clients change.
+	FLUSH_INTERVAL = time.Minute
+)
+
+var (
+	mu         sync.Mutex
+	g_services = make(map[string]*VulnerabilityService)
+
+	notRunningError = errors.New("Vulnerability service not running")
+)
+
+type SourceStatus struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	LastSync   int64  `json:"last_sync"`
+	Advisories int    `json:"advisories"`
+	Error      string `json:"error,omitempty"`
+}
+
+type VulnerabilityService struct {
+	mu         sync.Mutex
+	config_obj *config_proto.Config
+
+	sources       []*config_proto.VulnerabilitySource
+	sync_interval int64
+
+	db     *Database
+	status map[string]*SourceStatus
+
+	// Matches in the latest inventory of each client.
+	clients map[string][]*Match
+
+	// The fleet report needs to be rewritten.
+	dirty bool
+
+	// A HTTPClient used to download the databases.
+	Client networking.HTTPClient
+}
+
+func GetVulnerabilityService(
+	config_obj *config_proto.Config) (*VulnerabilityService, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result, pres := g_services[utils.NormalizedOrgId(config_obj.OrgId)]
+	if !pres {
+		return nil, notRunningError
+	}
+	return result, nil
+}
+
+func NewVulnerabilityService(ctx context.Context,
+	config_obj *config_proto.Config) (*VulnerabilityService, error) {
+	scope := vql_subsystem.MakeScope()
+	client, err := networking.GetDefaultHTTPClient(
+		ctx, config_obj.Client, scope, "", networking.EmptyCookieJar)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VulnerabilityService{
+		config_obj:    config_obj,
+		sync_interval: DEFAULT_SYNC_INTERVAL,
+		db:            NewDatabase(),
+		status:        make(map[string]*SourceStatus),
+		clients:       make(map[string][]*Match),
+		Client:        client,
+	}
+
+	vulnerability_config := config_obj.Defaults.GetVulnerabilities()
+	if vulnerability_config.GetSyncIntervalSec() != 0 {
+		result.sync_interval = vulnerability_config.SyncIntervalSec
+	}
+
+	for _, source := range vulnerability_config.GetSources() {
+		if source.Name == "" {
+			return nil, errors.New("Vulnerability sources must have a name")
+		}
+		result.sources = append(result.sources, source)
+	}
+
+	return result, nil
+}
+
+func getRawDB(config_obj *config_proto.Config) (datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, errors.New("Datastore does not support raw access")
+	}
+	return raw_db, nil
+}
+
+// Load the sync status and the stored databases.
+func (self *VulnerabilityService) Load(ctx context.Context) error {
+	raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	data, err := raw_db.GetBuffer(self.config_obj, paths.VULNERABILITY_STATUS)
+	if err == nil {
+		status := []*SourceStatus{}
+		if json.Unmarshal(data, &status) == nil {
+			self.mu.Lock()
+			for _, item := range status {
+				self.status[item.Name] = item
+			}
+			self.mu.Unlock()
+		}
+	}
+
+	return self.reloadDatabase(ctx)
+}
+
+func (self *VulnerabilityService) saveStatus() error {
+	raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.Marshal(self.Status())
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(self.config_obj, paths.VULNERABILITY_STATUS,
+		serialized, utils.BackgroundWriter)
+}
+
+// The sync status of the configured databases.
+func (self *VulnerabilityService) Status() []*SourceStatus {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := []*SourceStatus{}
+	for _, source := range self.sources {
+		status, pres := self.status[source.Name]
+		if !pres {
+			status = &SourceStatus{Name: source.Name, Type: source.Type}
+		}
+		item := *status
+		result = append(result, &item)
+	}
+	return result
+}
+
+// Build the in memory database from the stored databases.
+func (self *VulnerabilityService) reloadDatabase(ctx context.Context) error {
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	db := NewDatabase()
+
+	for _, source := range self.sources {
+		reader, err := result_sets.NewResultSetReader(file_store_factory,
+			paths.VulnerabilityDatabasePath(source.Name))
+		if err != nil {
+			continue
+		}
+
+		json_chan, err := reader.JSON(ctx)
+		if err != nil {
+			reader.Close()
+			continue
+		}
+
+		for serialized := range json_chan {
+			advisory := &Advisory{}
+			if json.Unmarshal(serialized, advisory) == nil {
+				db.Add(advisory)
+			}
+		}
+		reader.Close()
+	}
+
+	self.mu.Lock()
+	self.db = db
+	self.mu.Unlock()
+
+	return nil
+}
+
+// Download (or load) and store a single database.
+func (self *VulnerabilityService) syncSource(ctx context.Context,
+	source *config_proto.VulnerabilitySource) (int, error) {
+	data, err := readSource(ctx, self.Client, source)
+	if err != nil {
+		return 0, err
+	}
+
+	// Parse the whole database before replacing the stored one.
+	var advisories []*Advisory
+	err = parseSource(data, source, func(advisory *Advisory) {
+		advisories = append(advisories, advisory)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.VulnerabilityDatabasePath(source.Name),
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.TruncateMode)
+	if err != nil {
+		return 0, err
+	}
+	defer writer.Close()
+
+	for _, advisory := range advisories {
+		serialized, err := json.Marshal(advisory)
+		if err != nil {
+			continue
+		}
+		writer.WriteJSONL(append(serialized, '\n'), 1)
+	}
+
+	return len(advisories), nil
+}
+
+// Sync all the databases and match all clients against them.
+func (self *VulnerabilityService) Sync(ctx context.Context) error {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	var errs []string
+
+	for _, source := range self.sources {
+		logger.Info("VulnerabilityService: Syncing vulnerability database %v",
+			source.Name)
+
+		count, err := self.syncSource(ctx, source)
+
+		self.mu.Lock()
+		status, pres := self.status[source.Name]
+		if !pres {
+			status = &SourceStatus{Name: source.Name}
+			self.status[source.Name] = status
+		}
+		status.Type = source.Type
+		status.LastSync = utils.GetTime().Now().Unix()
+		status.Error = ""
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.Advisories = count
+		}
+		self.mu.Unlock()
+
+		if err != nil {
+			logger.Error("VulnerabilityService: Unable to sync %v: %v",
+				source.Name, err)
+			errs = append(errs, source.Name+": "+err.Error())
+			continue
+		}
+
+		logger.Info("VulnerabilityService: Synced %v advisories from %v",
+			count, source.Name)
+	}
+
+	err := self.saveStatus()
+	if err != nil {
+		return err
+	}
+
+	err = self.reloadDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = self.ScanAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Are any of the databases older than the sync interval?
+func (self *VulnerabilityService) syncDue(now int64) bool {
+	if self.sync_interval < 0 {
+		return false
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for _, source := range self.sources {
+		status, pres := self.status[source.Name]
+		if !pres || status.LastSync < now-self.sync_interval {
+			return true
+		}
+	}
+	return false
+}
+
+// Match the latest software inventory of the client and rewrite its
+// report.
+func (self *VulnerabilityService) ScanClient(
+	ctx context.Context, client_id string) error {
+	inventory, err := asset_inventory.GetAssetInventoryService(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	rows, err := inventory.Rows(ctx, client_id, "Software", "")
+	if err != nil {
+		return err
+	}
+
+	self.mu.Lock()
+	db := self.db
+	self.mu.Unlock()
+
+	var matches []*Match
+	for _, row := range rows {
+		name, _ := row.GetString("Name")
+		version, _ := row.GetString("Version")
+		matches = append(matches, db.Find(name, version)...)
+	}
+
+	hostname := ""
+	client_info_manager, err := services.GetClientInfoManager(self.config_obj)
+	if err == nil {
+		client_info, err := client_info_manager.Get(ctx, client_id)
+		if err == nil {
+			hostname = client_info.Hostname
+		}
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.VulnerabilityClientReportPath(client_id),
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.TruncateMode)
+	if err != nil {
+		return err
+	}
+
+	for _, match := range matches {
+		writer.Write(ordereddict.NewDict().
+			Set("ClientId", client_id).
+			Set("Hostname", hostname).
+			Set("Package", match.Package).
+			Set("Version", match.Version).
+			Set("Id", match.Advisory.Id).
+			Set("Aliases", match.Advisory.Aliases).
+			Set("Severity", match.Advisory.Severity).
+			Set("Summary", match.Advisory.Summary).
+			Set("Fixed", match.Fixed).
+			Set("Source", match.Advisory.Source))
+	}
+	writer.Close()
+
+	self.mu.Lock()
+	self.clients[client_id] = matches
+	self.dirty = true
+	self.mu.Unlock()
+
+	return nil
+}
+
+// Match all clients with an inventory and rewrite the fleet report.
+func (self *VulnerabilityService) ScanAll(ctx context.Context) error {
+	inventory, err := asset_inventory.GetAssetInventoryService(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	for _, client_id := range inventory.Clients() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := self.ScanClient(ctx, client_id)
+		if err != nil {
+			logger.Debug("VulnerabilityService: %v: %v", client_id, err)
+		}
+	}
+
+	return self.WriteFleetReport(ctx)
+}
+
+func (self *VulnerabilityService) RemoveClient(client_id string) error {
+	self.mu.Lock()
+	delete(self.clients, client_id)
+	self.dirty = true
+	self.mu.Unlock()
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	path := paths.VulnerabilityClientReportPath(client_id)
+	_ = file_store_factory.Delete(
+		path.SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX))
+	return file_store_factory.Delete(path)
+}
+
+type fleetEntry struct {
+	advisory *Advisory
+	pkg      string
+	clients  map[string]bool
+	versions map[string]bool
+}
+
+// Rewrite the fleet report listing each vulnerable package with the
+// affected clients.
+func (self *VulnerabilityService) WriteFleetReport(ctx context.Context) error {
+	self.mu.Lock()
+	entries := make(map[string]*fleetEntry)
+	keys := []string{}
+	for client_id, matches := range self.clients {
+		for _, match := range matches {
+			key := match.Advisory.Id + "\x00" + match.Package
+			entry, pres := entries[key]
+			if !pres {
+				entry = &fleetEntry{
+					advisory: match.Advisory,
+					pkg:      match.Package,
+					clients:  make(map[string]bool),
+					versions: make(map[string]bool),
+				}
+				entries[key] = entry
+				keys = append(keys, key)
+			}
+			entry.clients[client_id] = true
+			entry.versions[match.Version] = true
+		}
+	}
+	self.dirty = false
+	self.mu.Unlock()
+
+	// Most widespread vulnerabilities first.
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := entries[keys[i]], entries[keys[j]]
+		if len(a.clients) != len(b.clients) {
+			return len(a.clients) > len(b.clients)
+		}
+		return keys[i] < keys[j]
+	})
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.VulnerabilityFleetReportPath(),
+		json.DefaultEncOpts(), utils.SyncCompleter,
+		result_sets.TruncateMode)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	for _, key := range keys {
+		entry := entries[key]
+		writer.Write(ordereddict.NewDict().
+			Set("Id", entry.advisory.Id).
+			Set("Aliases", entry.advisory.Aliases).
+			Set("Severity", entry.advisory.Severity).
+			Set("Summary", entry.advisory.Summary).
+			Set("Package", entry.pkg).
+			Set("Versions", sortedKeys(entry.versions)).
+			Set("Clients", len(entry.clients)).
+			Set("ClientIds", sortedKeys(entry.clients)).
+			Set("Source", entry.advisory.Source))
+	}
+
+	return nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	result := make([]string, 0, len(set))
+	for k := range set {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Read the client's report, or the fleet report if client_id is
+// empty.
+func (self *VulnerabilityService) Report(ctx context.Context,
+	client_id string) ([]*ordereddict.Dict, error) {
+	path := paths.VulnerabilityFleetReportPath()
+	if client_id != "" {
+		path = paths.VulnerabilityClientReportPath(client_id)
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(file_store_factory, path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	result := []*ordereddict.Dict{}
+	for row := range reader.Rows(ctx) {
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+func (self *VulnerabilityService) flush(ctx context.Context) error {
+	self.mu.Lock()
+	dirty := self.dirty
+	self.mu.Unlock()
+
+	if !dirty {
+		return nil
+	}
+	return self.WriteFleetReport(ctx)
+}
+
+// Only the master node matches vulnerabilities. The service needs
+// the asset inventory service.
+func StartVulnerabilityService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if !services.IsMaster(config_obj) ||
+		len(config_obj.Defaults.GetVulnerabilities().GetSources()) == 0 {
+		return nil
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Vulnerability service for %v.",
+		services.GetOrgName(config_obj))
+
+	self, err := NewVulnerabilityService(ctx, config_obj)
+	if err != nil {
+		return err
+	}
+
+	err = self.Load(ctx)
+	if err != nil {
+		logger.Debug("VulnerabilityService: No databases loaded: %v", err)
+	}
+
+	org_id := utils.NormalizedOrgId(config_obj.OrgId)
+	mu.Lock()
+	g_services[org_id] = self
+	mu.Unlock()
+
+	err = journal.WatchQueueWithCB(ctx, config_obj, wg,
+		asset_inventory.UPDATES_ARTIFACT, "VulnerabilityService",
+		func(ctx context.Context, config_obj *config_proto.Config,
+			row *ordereddict.Dict) error {
+			client_id, _ := row.GetString("ClientId")
+			if client_id != "" {
+				err := self.ScanClient(ctx, client_id)
+				if err != nil {
+					logger.Error("VulnerabilityService: %v", err)
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	err = journal.WatchQueueWithCB(ctx, config_obj, wg,
+		"Server.Internal.ClientDelete", "VulnerabilityService",
+		func(ctx context.Context, config_obj *config_proto.Config,
+			row *ordereddict.Dict) error {
+			client_id, _ := row.GetString("ClientId")
+			if client_id != "" {
+				err := self.RemoveClient(client_id)
+				if err != nil {
+					logger.Debug("VulnerabilityService: %v", err)
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			mu.Lock()
+			delete(g_services, org_id)
+			mu.Unlock()
+		}()
+
+		// Give the other services a chance to start before the
+		// first sync.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Minute):
+		}
+
+		if self.syncDue(utils.GetTime().Now().Unix()) {
+			err = self.Sync(ctx)
+		} else {
+			err = self.ScanAll(ctx)
+		}
+		if err != nil {
+			logger.Error("VulnerabilityService: %v", err)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(FLUSH_INTERVAL):
+				if self.syncDue(utils.GetTime().Now().Unix()) {
+					err = self.Sync(ctx)
+				} else {
+					err = self.flush(ctx)
+				}
+				if err != nil {
+					logger.Error("VulnerabilityService: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
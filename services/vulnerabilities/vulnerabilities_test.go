@@ -0,0 +1,231 @@
+package vulnerabilities_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services/asset_inventory"
+	"www.velocidex.com/golang/velociraptor/services/vulnerabilities"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+var mock_definitions = []string{`
+name: Server.Internal.AssetInventory
+type: INTERNAL
+`, `
+name: Server.Internal.ClientDelete
+type: INTERNAL
+`, `
+name: Generic.Client.Inventory
+sources:
+- name: Software
+  query: SELECT * FROM scope()
+`}
+
+// An OSV export with one Debian and one PyPI advisory.
+var osv_records = []string{`{
+  "id": "DSA-5103-1",
+  "aliases": ["CVE-2022-0778"],
+  "summary": "openssl security update",
+  "affected": [{
+    "package": {"ecosystem": "Debian:11", "name": "openssl"},
+    "ranges": [{"type": "ECOSYSTEM",
+                "events": [{"introduced": "0"}, {"fixed": "1.1.1n"}]}]
+  }]
+}`, `{
+  "id": "PYSEC-1",
+  "affected": [{
+    "package": {"ecosystem": "PyPI", "name": "curl"},
+    "versions": ["7.68.0"]
+  }]
+}`}
+
+var nvd_feed = `{
+  "vulnerabilities": [{
+    "cve": {
+      "id": "CVE-2023-0001",
+      "descriptions": [{"lang": "en", "value": "A curl bug."}],
+      "metrics": {"cvssMetricV31": [{"cvssData": {"baseSeverity": "HIGH"}}]},
+      "configurations": [{"nodes": [{"cpeMatch": [{
+        "vulnerable": true,
+        "criteria": "cpe:2.3:a:haxx:curl:*:*:*:*:*:*:*:*",
+        "versionStartIncluding": "7.0",
+        "versionEndExcluding": "7.88.0"
+      }]}]}]
+    }
+  }, {
+    "cve": {
+      "id": "CVE-2023-0002",
+      "configurations": [{"nodes": [{"cpeMatch": [{
+        "vulnerable": true,
+        "criteria": "cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*",
+        "versionStartIncluding": "3.0.0",
+        "versionEndIncluding": "3.0.7"
+      }]}]}]
+    }
+  }]
+}`
+
+type VulnerabilityTestSuite struct {
+	test_utils.TestSuite
+
+	tmpdir string
+}
+
+func (self *VulnerabilityTestSuite) SetupTest() {
+	var err error
+	self.tmpdir, err = ioutil.TempDir("", "vulnerabilities")
+	assert.NoError(self.T(), err)
+
+	self.ConfigObj = self.TestSuite.LoadConfig()
+	self.ConfigObj.Services.Interrogation = true
+	self.ConfigObj.Defaults.Vulnerabilities = &config_proto.VulnerabilityConfig{
+		Sources: []*config_proto.VulnerabilitySource{{
+			Name:       "OSV",
+			Type:       "osv",
+			Path:       filepath.Join(self.tmpdir, "osv.zip"),
+			Ecosystems: []string{"Debian"},
+		}, {
+			Name: "NVD",
+			Type: "nvd",
+			Path: filepath.Join(self.tmpdir, "nvd.json.gz"),
+		}},
+	}
+
+	self.LoadArtifactsIntoConfig(mock_definitions)
+
+	self.TestSuite.SetupTest()
+}
+
+func (self *VulnerabilityTestSuite) TearDownTest() {
+	self.TestSuite.TearDownTest()
+	os.RemoveAll(self.tmpdir)
+}
+
+func (self *VulnerabilityTestSuite) writeBundles() {
+	buf := &bytes.Buffer{}
+	zip_writer := zip.NewWriter(buf)
+	for i, record := range osv_records {
+		fd, err := zip_writer.Create(string(rune('a'+i)) + ".json")
+		assert.NoError(self.T(), err)
+		_, err = fd.Write([]byte(record))
+		assert.NoError(self.T(), err)
+	}
+	assert.NoError(self.T(), zip_writer.Close())
+	assert.NoError(self.T(), ioutil.WriteFile(
+		filepath.Join(self.tmpdir, "osv.zip"), buf.Bytes(), 0600))
+
+	buf = &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	_, err := gz.Write([]byte(nvd_feed))
+	assert.NoError(self.T(), err)
+	assert.NoError(self.T(), gz.Close())
+	assert.NoError(self.T(), ioutil.WriteFile(
+		filepath.Join(self.tmpdir, "nvd.json.gz"), buf.Bytes(), 0600))
+}
+
+// Record an inventory for the client with this software.
+func (self *VulnerabilityTestSuite) addInventory(
+	client_id, flow_id string, software ...string) {
+	path_manager, err := artifacts.NewArtifactPathManager(self.Ctx,
+		self.ConfigObj, client_id, flow_id,
+		asset_inventory.INVENTORY_ARTIFACT+"/Software")
+	assert.NoError(self.T(), err)
+
+	writer, err := result_sets.NewResultSetWriter(
+		file_store.GetFileStore(self.ConfigObj), path_manager.Path(),
+		json.DefaultEncOpts(), utils.SyncCompleter, result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	for i := 0; i+1 < len(software); i += 2 {
+		writer.Write(ordereddict.NewDict().
+			Set("Name", software[i]).
+			Set("Version", software[i+1]))
+	}
+	writer.Close()
+
+	inventory, err := asset_inventory.GetAssetInventoryService(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.NoError(self.T(), inventory.ProcessCollection(
+		self.Ctx, client_id, flow_id))
+}
+
+func (self *VulnerabilityTestSuite) TestVulnerabilities() {
+	self.writeBundles()
+
+	self.addInventory("C.1", "F.1", "openssl", "1.1.1f", "curl", "7.68.0")
+	self.addInventory("C.2", "F.2", "OpenSSL", "3.0.13", "curl", "8.1.0")
+
+	service, err := vulnerabilities.GetVulnerabilityService(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.NoError(self.T(), service.Sync(self.Ctx))
+
+	status := service.Status()
+	assert.Equal(self.T(), 2, len(status))
+
+	// The PyPI advisory is not in the configured ecosystems.
+	assert.Equal(self.T(), 1, status[0].Advisories)
+	assert.Equal(self.T(), 2, status[1].Advisories)
+
+	rows, err := service.Report(self.Ctx, "C.1")
+	assert.NoError(self.T(), err)
+
+	ids := []string{}
+	for _, row := range rows {
+		id, _ := row.GetString("Id")
+		ids = append(ids, id)
+	}
+	assert.Equal(self.T(), []string{"DSA-5103-1", "CVE-2023-0001"}, ids)
+
+	fixed, _ := rows[0].GetString("Fixed")
+	assert.Equal(self.T(), "1.1.1n", fixed)
+
+	rows, err = service.Report(self.Ctx, "C.2")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(rows))
+
+	// Upgrading curl on C.1 removes it from the report.
+	self.addInventory("C.1", "F.3", "openssl", "1.1.1f", "curl", "8.1.0")
+	assert.NoError(self.T(), service.ScanClient(self.Ctx, "C.1"))
+	assert.NoError(self.T(), service.WriteFleetReport(self.Ctx))
+
+	rows, err = service.Report(self.Ctx, "")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(rows))
+
+	id, _ := rows[0].GetString("Id")
+	assert.Equal(self.T(), "DSA-5103-1", id)
+
+	client_ids, _ := rows[0].Get("ClientIds")
+	assert.Equal(self.T(), []interface{}{"C.1"}, client_ids)
+
+	// The databases survive a restart.
+	restored, err := vulnerabilities.NewVulnerabilityService(
+		self.Ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.NoError(self.T(), restored.Load(self.Ctx))
+	assert.Equal(self.T(), 1, restored.Status()[0].Advisories)
+
+	assert.NoError(self.T(), restored.ScanClient(self.Ctx, "C.1"))
+	rows, err = restored.Report(self.Ctx, "C.1")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(rows))
+}
+
+func TestVulnerabilities(t *testing.T) {
+	suite.Run(t, &VulnerabilityTestSuite{})
+}
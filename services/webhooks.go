@@ -0,0 +1,65 @@
+package services
+
+// The webhook service delivers messages queued by send_webhook() to
+// the destinations configured in the Defaults section. It runs on the
+// master frontend only.
+
+import (
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+)
+
+func GetWebhookService(config_obj *config_proto.Config) (WebhookService, error) {
+	org_manager, err := GetOrgManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return org_manager.Services(config_obj.OrgId).WebhookService()
+}
+
+// A rendered payload waiting for delivery.
+type WebhookMessage struct {
+	Id      string `json:"id"`
+	Payload []byte `json:"payload"`
+	Created int64  `json:"created"`
+
+	// Messages are delivered in sequence order.
+	Sequence uint64 `json:"sequence"`
+
+	// Number of failed delivery attempts so far.
+	Attempts    int    `json:"attempts"`
+	NextAttempt int64  `json:"next_attempt"`
+	LastError   string `json:"last_error,omitempty"`
+}
+
+// The pending messages of a destination and its delivery status.
+type WebhookQueue struct {
+	Name string `json:"name"`
+
+	// Pending messages are stored individually.
+	Pending []*WebhookMessage `json:"-"`
+
+	// Delivered successfully.
+	Delivered int64 `json:"delivered"`
+
+	// Failed after all retries were exhausted.
+	Failed int64 `json:"failed"`
+
+	// Dropped because the queue was full.
+	Dropped int64 `json:"dropped"`
+
+	LastDelivery int64  `json:"last_delivery,omitempty"`
+	LastError    string `json:"last_error,omitempty"`
+}
+
+type WebhookService interface {
+	// Queue the data for delivery to the named destination,
+	// optionally rendered with the template. Returns the message id.
+	Send(name, template string, data interface{}) (string, error)
+
+	// The delivery status of all destinations.
+	Status() ([]*WebhookQueue, error)
+
+	// Replace the destinations when the config is reloaded.
+	SetDestinations(config_obj *config_proto.Config) error
+}
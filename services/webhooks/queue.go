@@ -0,0 +1,114 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"text/template"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+const (
+	DEFAULT_METHOD           = "POST"
+	DEFAULT_CONTENT_TYPE     = "application/json"
+	DEFAULT_SIGNATURE_HEADER = "X-Velociraptor-Signature"
+	DEFAULT_MAX_RETRIES      = 5
+	DEFAULT_RETRY_DELAY      = 30
+	DEFAULT_MAX_QUEUE_SIZE   = 10000
+
+	// Never back off for longer than this (seconds).
+	MAX_RETRY_DELAY = 60 * 60
+)
+
+// Render the payload for the destination. Without a template the
+// data is sent as JSON.
+func Render(
+	destination *config_proto.WebhookConfig,
+	template_str string, data interface{}) ([]byte, error) {
+	if template_str == "" {
+		template_str = destination.Template
+	}
+
+	serialized, err := json.MarshalWithOptions(data, json.DefaultEncOpts())
+	if err != nil {
+		return nil, err
+	}
+
+	if template_str == "" {
+		return serialized, nil
+	}
+
+	// Normalize the data so templates see plain maps and lists.
+	var normalized interface{}
+	err = json.Unmarshal(serialized, &normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(destination.Name).Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			serialized, err := json.Marshal(v)
+			return string(serialized), err
+		},
+	}).Parse(template_str)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, normalized)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func newMessageId() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Calculate the value of the signature header.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// The delay before the next attempt doubles with every failure.
+func retryDelay(
+	destination *config_proto.WebhookConfig, attempts int) time.Duration {
+	delay := destination.RetryDelaySec
+	if delay == 0 {
+		delay = DEFAULT_RETRY_DELAY
+	}
+
+	for i := 1; i < attempts && delay < MAX_RETRY_DELAY; i++ {
+		delay *= 2
+	}
+
+	if delay > MAX_RETRY_DELAY {
+		delay = MAX_RETRY_DELAY
+	}
+	return time.Duration(delay) * time.Second
+}
+
+func maxRetries(destination *config_proto.WebhookConfig) int {
+	if destination.MaxRetries == 0 {
+		return DEFAULT_MAX_RETRIES
+	}
+	return int(destination.MaxRetries)
+}
+
+func maxQueueSize(destination *config_proto.WebhookConfig) int {
+	if destination.MaxQueueSize == 0 {
+		return DEFAULT_MAX_QUEUE_SIZE
+	}
+	return int(destination.MaxQueueSize)
+}
@@ -0,0 +1,535 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+var (
+	webhookDeliveryCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_delivery_total",
+			Help: "Outcome of webhook delivery attempts (delivered, retry, failed or dropped).",
+		},
+		[]string{"destination", "status"},
+	)
+
+	webhookQueueGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "webhook_queue_length",
+			Help: "Number of webhook messages waiting for delivery.",
+		},
+		[]string{"destination"},
+	)
+
+	notAvailableError = errors.New("Webhook: Datastore not available")
+)
+
+// Delivers the messages queued by send_webhook() to the configured
+// destinations. Each pending message is persisted in the datastore so
+// it survives a restart.
+type WebhookService struct {
+	mu sync.Mutex
+
+	config_obj   *config_proto.Config
+	destinations map[string]*config_proto.WebhookConfig
+	names        []string
+	queues       map[string]*services.WebhookQueue
+
+	// The sequence number of the next message in each queue.
+	sequences map[string]uint64
+
+	// A HTTPClient used to deliver the webhooks.
+	Client networking.HTTPClient
+
+	// Wake the delivery loop when new messages are queued.
+	wake chan bool
+}
+
+// Queue the data for delivery to the named destination. Returns the
+// message id.
+func (self *WebhookService) Send(
+	name, template_str string, data interface{}) (string, error) {
+	destination, pres := self.getDestination(name)
+	if !pres {
+		return "", fmt.Errorf("Unknown webhook destination %v", name)
+	}
+
+	payload, err := Render(destination, template_str, data)
+	if err != nil {
+		return "", err
+	}
+
+	self.mu.Lock()
+	queue, err := self.getQueue(name)
+	if err != nil {
+		self.mu.Unlock()
+		return "", err
+	}
+
+	if len(queue.Pending) >= maxQueueSize(destination) {
+		queue.Dropped++
+		err = self.storeQueue(queue)
+		self.mu.Unlock()
+		if err != nil {
+			return "", err
+		}
+
+		webhookDeliveryCounter.WithLabelValues(name, "dropped").Inc()
+		return "", fmt.Errorf("Webhook queue for %v is full", name)
+	}
+
+	now := utils.GetTime().Now().Unix()
+	message := &services.WebhookMessage{
+		Id:          newMessageId(),
+		Payload:     payload,
+		Created:     now,
+		Sequence:    self.sequences[name],
+		NextAttempt: now,
+	}
+	self.sequences[name]++
+
+	// Only the new message is written.
+	err = self.storeMessage(name, message)
+	if err == nil {
+		queue.Pending = append(queue.Pending, message)
+	}
+	self.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	select {
+	case self.wake <- true:
+	default:
+	}
+
+	return message.Id, nil
+}
+
+// Attempt delivery of all messages which are due. Messages are
+// delivered in order so a failing message holds up the rest of its
+// queue until it succeeds or runs out of retries.
+func (self *WebhookService) DeliverPending(ctx context.Context) error {
+	self.mu.Lock()
+	names := self.names
+	self.mu.Unlock()
+
+	for _, name := range names {
+		err := self.deliverQueue(ctx, name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (self *WebhookService) deliverQueue(ctx context.Context, name string) error {
+	destination, pres := self.getDestination(name)
+	if !pres {
+		return nil
+	}
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+
+	for {
+		self.mu.Lock()
+		queue, err := self.getQueue(name)
+		if err != nil {
+			self.mu.Unlock()
+			return err
+		}
+
+		webhookQueueGauge.WithLabelValues(name).Set(float64(len(queue.Pending)))
+
+		now := utils.GetTime().Now()
+		if len(queue.Pending) == 0 ||
+			queue.Pending[0].NextAttempt > now.Unix() {
+			self.mu.Unlock()
+			return nil
+		}
+		message := queue.Pending[0]
+		self.mu.Unlock()
+
+		// Deliver without holding the lock so Send() is not blocked
+		// by slow destinations.
+		err = self.deliver(ctx, destination, message)
+
+		self.mu.Lock()
+		if err == nil {
+			queue.Pending = queue.Pending[1:]
+			queue.Delivered++
+			queue.LastDelivery = now.Unix()
+			webhookDeliveryCounter.WithLabelValues(name, "delivered").Inc()
+			err = self.deleteMessage(name, message)
+
+		} else {
+			message.Attempts++
+			message.LastError = err.Error()
+			queue.LastError = err.Error()
+
+			if message.Attempts > maxRetries(destination) {
+				queue.Pending = queue.Pending[1:]
+				queue.Failed++
+				webhookDeliveryCounter.WithLabelValues(name, "failed").Inc()
+				logger.Error("Webhook: Giving up on message %v to %v after %v attempts: %v",
+					message.Id, name, message.Attempts, err)
+				err = self.deleteMessage(name, message)
+
+			} else {
+				message.NextAttempt = now.Add(
+					retryDelay(destination, message.Attempts)).Unix()
+				webhookDeliveryCounter.WithLabelValues(name, "retry").Inc()
+				err = self.storeMessage(name, message)
+			}
+		}
+
+		if err == nil {
+			err = self.storeQueue(queue)
+		}
+		self.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (self *WebhookService) getDestination(
+	name string) (*config_proto.WebhookConfig, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	destination, pres := self.destinations[name]
+	return destination, pres
+}
+
+// Replace the destinations when the config is reloaded. Messages
+// queued for removed destinations stay in their queues.
+func (self *WebhookService) SetDestinations(
+	config_obj *config_proto.Config) error {
+	destinations, names, err := ParseDestinations(config_obj)
+	if err != nil {
+		return err
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.destinations = destinations
+	self.names = names
+	return nil
+}
+
+func (self *WebhookService) deliver(ctx context.Context,
+	destination *config_proto.WebhookConfig,
+	message *services.WebhookMessage) error {
+	method := destination.Method
+	if method == "" {
+		method = DEFAULT_METHOD
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, destination.Url,
+		bytes.NewReader(message.Payload))
+	if err != nil {
+		return err
+	}
+
+	content_type := destination.ContentType
+	if content_type == "" {
+		content_type = DEFAULT_CONTENT_TYPE
+	}
+	req.Header.Set("Content-Type", content_type)
+	req.Header.Set("X-Velociraptor-Delivery", message.Id)
+
+	for _, header := range destination.Headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("Invalid header %q", header)
+		}
+		req.Header.Set(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+
+	if destination.HmacSecret != "" {
+		header := destination.SignatureHeader
+		if header == "" {
+			header = DEFAULT_SIGNATURE_HEADER
+		}
+		req.Header.Set(header, Sign(destination.HmacSecret, message.Payload))
+	}
+
+	resp, err := self.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1024*1024))
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Request failed with status %v", resp.Status)
+	}
+	return nil
+}
+
+// The delivery status of all destinations.
+func (self *WebhookService) Status() ([]*services.WebhookQueue, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := []*services.WebhookQueue{}
+	for _, name := range self.names {
+		queue, err := self.getQueue(name)
+		if err != nil {
+			return nil, err
+		}
+
+		// Return a copy since the queue keeps changing.
+		pending := make([]*services.WebhookMessage, 0, len(queue.Pending))
+		for _, message := range queue.Pending {
+			message_copy := *message
+			pending = append(pending, &message_copy)
+		}
+
+		result = append(result, &services.WebhookQueue{
+			Name:         queue.Name,
+			Pending:      pending,
+			Delivered:    queue.Delivered,
+			Failed:       queue.Failed,
+			Dropped:      queue.Dropped,
+			LastDelivery: queue.LastDelivery,
+			LastError:    queue.LastError,
+		})
+	}
+	return result, nil
+}
+
+// Get the queue from the cache or load it from the
+// datastore. Must be called with the lock held.
+func (self *WebhookService) getQueue(name string) (
+	*services.WebhookQueue, error) {
+	queue, pres := self.queues[name]
+	if pres {
+		return queue, nil
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	path_manager := paths.NewWebhookPathManager(name)
+	queue = &services.WebhookQueue{Name: name}
+	data, err := raw_db.GetBuffer(self.config_obj, path_manager.Queue())
+	if err == nil && len(data) > 0 {
+		err = json.Unmarshal(data, queue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	children, err := db.ListChildren(self.config_obj, path_manager.Pending())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, child := range children {
+		data, err := raw_db.GetBuffer(self.config_obj, child)
+		if err != nil {
+			continue
+		}
+
+		message := &services.WebhookMessage{}
+		err = json.Unmarshal(data, message)
+		if err != nil {
+			continue
+		}
+		queue.Pending = append(queue.Pending, message)
+	}
+
+	sort.Slice(queue.Pending, func(i, j int) bool {
+		return queue.Pending[i].Sequence < queue.Pending[j].Sequence
+	})
+
+	if len(queue.Pending) > 0 {
+		self.sequences[name] = queue.Pending[len(queue.Pending)-1].Sequence + 1
+	}
+
+	self.queues[name] = queue
+	return queue, nil
+}
+
+// Store the delivery status. The pending messages are stored
+// separately. Must be called with the lock held.
+func (self *WebhookService) storeQueue(queue *services.WebhookQueue) error {
+	raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(queue)
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(self.config_obj,
+		paths.NewWebhookPathManager(queue.Name).Queue(),
+		data, utils.SyncCompleter)
+}
+
+func (self *WebhookService) storeMessage(
+	name string, message *services.WebhookMessage) error {
+	raw_db, err := getRawDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(self.config_obj,
+		paths.NewWebhookPathManager(name).Message(message.Sequence),
+		data, utils.SyncCompleter)
+}
+
+func (self *WebhookService) deleteMessage(
+	name string, message *services.WebhookMessage) error {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.DeleteSubjectWithCompletion(self.config_obj,
+		paths.NewWebhookPathManager(name).Message(message.Sequence),
+		utils.SyncCompleter)
+}
+
+func getRawDB(config_obj *config_proto.Config) (datastore.RawDataStore, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, notAvailableError
+	}
+	return raw_db, nil
+}
+
+func (self *WebhookService) Start(ctx context.Context, wg *sync.WaitGroup) {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			err := self.DeliverPending(ctx)
+			if err != nil {
+				logger.Error("Webhook: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-self.wake:
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+}
+
+// Validate the destinations in the Defaults section.
+func ParseDestinations(config_obj *config_proto.Config) (
+	map[string]*config_proto.WebhookConfig, []string, error) {
+	destinations := make(map[string]*config_proto.WebhookConfig)
+	names := []string{}
+	for _, destination := range config_obj.GetDefaults().GetWebhooks() {
+		if destination.Name == "" || destination.Url == "" {
+			return nil, nil, errors.New(
+				"Webhook: Destinations must have a name and url")
+		}
+
+		_, pres := destinations[destination.Name]
+		if pres {
+			return nil, nil, fmt.Errorf(
+				"Webhook: Duplicate destination %v", destination.Name)
+		}
+		destinations[destination.Name] = destination
+		names = append(names, destination.Name)
+	}
+	return destinations, names, nil
+}
+
+func NewWebhookService(ctx context.Context,
+	config_obj *config_proto.Config) (*WebhookService, error) {
+	scope := vql_subsystem.MakeScope()
+	client, err := networking.GetDefaultHTTPClient(
+		ctx, config_obj.Client, scope, "", networking.EmptyCookieJar)
+	if err != nil {
+		return nil, err
+	}
+
+	destinations, names, err := ParseDestinations(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookService{
+		config_obj:   config_obj,
+		destinations: destinations,
+		names:        names,
+		queues:       make(map[string]*services.WebhookQueue),
+		sequences:    make(map[string]uint64),
+		Client:       client,
+		wake:         make(chan bool, 1),
+	}, nil
+}
+
+// The webhook service runs on the master frontend and delivers
+// webhooks to the destinations configured in the Defaults
+// section. Returns nil if no destinations are configured.
+func StartWebhookService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) (*WebhookService, error) {
+
+	if config_obj.Datastore == nil ||
+		len(config_obj.GetDefaults().GetWebhooks()) == 0 {
+		return nil, nil
+	}
+
+	service, err := NewWebhookService(ctx, config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	service.Start(ctx, wg)
+	return service, nil
+}
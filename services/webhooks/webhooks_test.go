@@ -0,0 +1,271 @@
+package webhooks_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services/webhooks"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type request struct {
+	url       string
+	body      string
+	signature string
+	token     string
+}
+
+type MockClient struct {
+	requests []request
+
+	// Status codes to return in turn. Once exhausted all requests
+	// succeed.
+	statuses []int
+}
+
+func (self *MockClient) Do(req *http.Request) (*http.Response, error) {
+	body, _ := ioutil.ReadAll(req.Body)
+	self.requests = append(self.requests, request{
+		url:       req.URL.String(),
+		body:      string(body),
+		signature: req.Header.Get("X-Signature"),
+		token:     req.Header.Get("Authorization"),
+	})
+
+	status := 200
+	if len(self.statuses) > 0 {
+		status = self.statuses[0]
+		self.statuses = self.statuses[1:]
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Body:       ioutil.NopCloser(&bytes.Buffer{}),
+	}, nil
+}
+
+type WebhookTestSuite struct {
+	test_utils.TestSuite
+	clock *utils.MockClock
+}
+
+func (self *WebhookTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	self.clock = utils.NewMockClock(time.Unix(1700000000, 0))
+	self.ConfigObj.Defaults.Webhooks = []*config_proto.WebhookConfig{{
+		Name:            "Soar",
+		Url:             "https://soar.example.com/hook",
+		Headers:         []string{"Authorization: Bearer token"},
+		Template:        `{"host": {{ json .Fqdn }}, "count": {{ .Count }}}`,
+		HmacSecret:      "secret",
+		SignatureHeader: "X-Signature",
+		MaxRetries:      2,
+		RetryDelaySec:   10,
+		MaxQueueSize:    2,
+	}, {
+		Name: "Raw",
+		Url:  "https://raw.example.com/hook",
+	}}
+}
+
+func (self *WebhookTestSuite) TestRender() {
+	destination := self.ConfigObj.Defaults.Webhooks[0]
+	data := ordereddict.NewDict().Set("Fqdn", `evil "host"`).Set("Count", 3)
+
+	payload, err := webhooks.Render(destination, "", data)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), `{"host": "evil \"host\"", "count": 3}`, string(payload))
+
+	// Without a template the data is sent as JSON.
+	payload, err = webhooks.Render(self.ConfigObj.Defaults.Webhooks[1], "", data)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), `{"Fqdn":"evil \"host\"","Count":3}`, string(payload))
+
+	_, err = webhooks.Render(destination, "{{ .Fqdn ", data)
+	assert.Error(self.T(), err)
+
+	assert.Equal(self.T(),
+		"sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		webhooks.Sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
+
+func (self *WebhookTestSuite) TestDelivery() {
+	closer := utils.MockTime(self.clock)
+	defer closer()
+
+	ctx := context.Background()
+	service, err := webhooks.NewWebhookService(ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	mock := &MockClient{statuses: []int{500, 503}}
+	service.Client = mock
+
+	_, err = service.Send("Unknown", "", ordereddict.NewDict())
+	assert.Error(self.T(), err)
+
+	data := ordereddict.NewDict().Set("Fqdn", "host").Set("Count", 1)
+	id, err := service.Send("Soar", "", data)
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), id != "")
+
+	// The first attempt fails and is retried after the delay.
+	assert.NoError(self.T(), service.DeliverPending(ctx))
+	assert.Equal(self.T(), 1, len(mock.requests))
+	assert.Equal(self.T(), `{"host": "host", "count": 1}`, mock.requests[0].body)
+	assert.Equal(self.T(), "Bearer token", mock.requests[0].token)
+	assert.Equal(self.T(),
+		webhooks.Sign("secret", []byte(mock.requests[0].body)),
+		mock.requests[0].signature)
+
+	assert.NoError(self.T(), service.DeliverPending(ctx))
+	assert.Equal(self.T(), 1, len(mock.requests))
+
+	// Pending messages survive a restart.
+	service, err = webhooks.NewWebhookService(ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+	service.Client = mock
+
+	status, err := service.Status()
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(status[0].Pending))
+	assert.Equal(self.T(), 1, status[0].Pending[0].Attempts)
+	assert.Equal(self.T(), int64(1700000010), status[0].Pending[0].NextAttempt)
+	assert.Contains(self.T(), status[0].LastError, "500")
+
+	// The backoff doubles.
+	self.clock.Set(time.Unix(1700000010, 0))
+	assert.NoError(self.T(), service.DeliverPending(ctx))
+	assert.Equal(self.T(), 2, len(mock.requests))
+
+	status, err = service.Status()
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(1700000030), status[0].Pending[0].NextAttempt)
+
+	self.clock.Set(time.Unix(1700000030, 0))
+	assert.NoError(self.T(), service.DeliverPending(ctx))
+	assert.Equal(self.T(), 3, len(mock.requests))
+
+	status, err = service.Status()
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(status[0].Pending))
+	assert.Equal(self.T(), int64(1), status[0].Delivered)
+	assert.Equal(self.T(), int64(1700000030), status[0].LastDelivery)
+}
+
+func (self *WebhookTestSuite) TestRetriesAndQueueLimit() {
+	closer := utils.MockTime(self.clock)
+	defer closer()
+
+	ctx := context.Background()
+	service, err := webhooks.NewWebhookService(ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	mock := &MockClient{statuses: []int{500, 500, 500}}
+	service.Client = mock
+
+	data := ordereddict.NewDict().Set("Fqdn", "host").Set("Count", 1)
+	for i := 0; i < 2; i++ {
+		_, err = service.Send("Soar", "", data)
+		assert.NoError(self.T(), err)
+	}
+
+	// The queue is full.
+	_, err = service.Send("Soar", "", data)
+	assert.Error(self.T(), err)
+
+	// The first message is given up after max_retries.
+	for i := 0; i < 3; i++ {
+		assert.NoError(self.T(), service.DeliverPending(ctx))
+		self.clock.Set(self.clock.Now().Add(time.Hour))
+	}
+
+	status, err := service.Status()
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(status[0].Pending))
+	assert.Equal(self.T(), int64(1), status[0].Failed)
+	assert.Equal(self.T(), int64(1), status[0].Delivered)
+	assert.Equal(self.T(), int64(1), status[0].Dropped)
+	assert.Equal(self.T(), 4, len(mock.requests))
+
+	// The template can be given for each message.
+	_, err = service.Send("Raw", "{{ .Count }}", data)
+	assert.NoError(self.T(), err)
+	assert.NoError(self.T(), service.DeliverPending(ctx))
+	assert.Equal(self.T(), "1", mock.requests[4].body)
+	assert.Equal(self.T(), "https://raw.example.com/hook", mock.requests[4].url)
+}
+
+func (self *WebhookTestSuite) TestPersistence() {
+	closer := utils.MockTime(self.clock)
+	defer closer()
+
+	ctx := context.Background()
+	service, err := webhooks.NewWebhookService(ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	mock := &MockClient{statuses: []int{500}}
+	service.Client = mock
+
+	for i := 0; i < 3; i++ {
+		_, err = service.Send("Raw", "", ordereddict.NewDict().Set("Count", i))
+		assert.NoError(self.T(), err)
+	}
+
+	// Each message is stored on its own.
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	path_manager := paths.NewWebhookPathManager("Raw")
+	children, err := db.ListChildren(self.ConfigObj, path_manager.Pending())
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, len(children))
+
+	// The first message fails and holds up the queue.
+	assert.NoError(self.T(), service.DeliverPending(ctx))
+
+	// Messages are reloaded in order after a restart.
+	service, err = webhooks.NewWebhookService(ctx, self.ConfigObj)
+	assert.NoError(self.T(), err)
+	service.Client = mock
+
+	self.clock.Set(self.clock.Now().Add(time.Hour))
+	assert.NoError(self.T(), service.DeliverPending(ctx))
+
+	assert.Equal(self.T(), 4, len(mock.requests))
+	for i, body := range []string{
+		`{"Count":0}`, `{"Count":0}`, `{"Count":1}`, `{"Count":2}`} {
+		assert.Equal(self.T(), body, mock.requests[i].body)
+	}
+
+	// Delivered messages are removed.
+	children, err = db.ListChildren(self.ConfigObj, path_manager.Pending())
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(children))
+
+	// New messages continue the sequence.
+	_, err = service.Send("Raw", "", ordereddict.NewDict().Set("Count", 3))
+	assert.NoError(self.T(), err)
+
+	status, err := service.Status()
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(3), status[1].Delivered)
+	assert.Equal(self.T(), uint64(3), status[1].Pending[0].Sequence)
+}
+
+func TestWebhooks(t *testing.T) {
+	suite.Run(t, &WebhookTestSuite{})
+}
@@ -10,6 +10,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/orgs"
+	"www.velocidex.com/golang/velociraptor/tracing"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 )
 
@@ -22,7 +23,14 @@ func StartFrontendServices(
 	executor.SetTempfile(config_obj)
 
 	sm := services.NewServiceManager(ctx, config_obj)
-	_, err := orgs.NewOrgManager(sm.Ctx, sm.Wg, config_obj)
+
+	// Start tracing before the services so their work is traced.
+	err := tracing.StartTracing(sm.Ctx, sm.Wg, config_obj)
+	if err != nil {
+		return sm, err
+	}
+
+	_, err = orgs.NewOrgManager(sm.Ctx, sm.Wg, config_obj)
 	if err != nil {
 		return sm, err
 	}
@@ -0,0 +1,333 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+)
+
+var (
+	spansExported = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tracing_spans_exported",
+		Help: "Number of trace spans sent to the collector.",
+	})
+
+	spansDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tracing_spans_dropped",
+		Help: "Number of trace spans dropped because the queue was full or the collector failed.",
+	})
+)
+
+const (
+	DEFAULT_SERVICE_NAME = "velociraptor"
+
+	// Spans are sent in batches of this size or every
+	// flushInterval.
+	batchSize     = 512
+	maxQueueSize  = 8192
+	flushInterval = 5 * time.Second
+)
+
+type Tracer struct {
+	url          string
+	headers      map[string]string
+	service_name string
+	sample_ratio float64
+
+	// Also trace each datastore and filestore operation.
+	trace_storage bool
+
+	client *http.Client
+
+	mu    sync.Mutex
+	queue []*Span
+	flush chan bool
+}
+
+func (self *Tracer) export(span *Span) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if len(self.queue) >= maxQueueSize {
+		spansDropped.Inc()
+		return
+	}
+
+	self.queue = append(self.queue, span)
+	if len(self.queue) >= batchSize {
+		select {
+		case self.flush <- true:
+		default:
+		}
+	}
+}
+
+func (self *Tracer) Flush(ctx context.Context) error {
+	self.mu.Lock()
+	queue := self.queue
+	self.queue = nil
+	self.mu.Unlock()
+
+	for len(queue) > 0 {
+		batch := queue
+		if len(batch) > batchSize {
+			batch = batch[:batchSize]
+		}
+		queue = queue[len(batch):]
+
+		err := self.send(ctx, batch)
+		if err != nil {
+			spansDropped.Add(float64(len(batch) + len(queue)))
+			return err
+		}
+		spansExported.Add(float64(len(batch)))
+	}
+	return nil
+}
+
+func (self *Tracer) send(ctx context.Context, spans []*Span) error {
+	serialized, err := json.Marshal(self.encode(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", self.url,
+		bytes.NewReader(serialized))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range self.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := self.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Collector returned %v", resp.Status)
+	}
+	return nil
+}
+
+// Encode the spans in the OTLP JSON encoding. Ids are hex encoded
+// and 64 bit integers are strings.
+func (self *Tracer) encode(spans []*Span) map[string]interface{} {
+	encoded := []interface{}{}
+	for _, span := range spans {
+		span.mu.Lock()
+		item := map[string]interface{}{
+			"traceId":           hex.EncodeToString(span.trace_id[:]),
+			"spanId":            hex.EncodeToString(span.span_id[:]),
+			"name":              span.name,
+			"kind":              int(span.kind),
+			"startTimeUnixNano": strconv.FormatInt(span.start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(span.end.UnixNano(), 10),
+			"attributes":        encodeAttributes(span.attributes),
+		}
+
+		if span.parent_id != [8]byte{} {
+			item["parentSpanId"] = hex.EncodeToString(span.parent_id[:])
+		}
+
+		if span.status_code != statusUnset {
+			item["status"] = map[string]interface{}{
+				"code":    span.status_code,
+				"message": span.status_message,
+			}
+		}
+		span.mu.Unlock()
+
+		encoded = append(encoded, item)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": encodeAttributes([]Attribute{
+						String("service.name", self.service_name),
+					}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{
+							"name": DEFAULT_SERVICE_NAME,
+						},
+						"spans": encoded,
+					},
+				},
+			},
+		},
+	}
+}
+
+func encodeAttributes(attributes []Attribute) []interface{} {
+	result := []interface{}{}
+	for _, attr := range attributes {
+		var value map[string]interface{}
+		switch t := attr.Value.(type) {
+		case string:
+			value = map[string]interface{}{"stringValue": t}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(t, 10)}
+		case bool:
+			value = map[string]interface{}{"boolValue": t}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprintf("%v", t)}
+		}
+
+		result = append(result, map[string]interface{}{
+			"key":   attr.Key,
+			"value": value,
+		})
+	}
+	return result
+}
+
+func (self *Tracer) run(ctx context.Context, config_obj *config_proto.Config) {
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	last_error := ""
+
+	flush := func(ctx context.Context) {
+		err := self.Flush(ctx)
+		if err != nil {
+			// Only log when the error changes to avoid flooding the
+			// logs while the collector is down.
+			if err.Error() != last_error {
+				logger.Error("Tracing: Unable to export spans to %v: %v",
+					self.url, err)
+			}
+			last_error = err.Error()
+			return
+		}
+		last_error = ""
+	}
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Send the remaining spans before we exit.
+			subctx, cancel := context.WithTimeout(
+				context.Background(), 10*time.Second)
+			flush(subctx)
+			cancel()
+			return
+
+		case <-self.flush:
+			flush(ctx)
+
+		case <-ticker.C:
+			flush(ctx)
+		}
+	}
+}
+
+func newTracer(config_obj *config_proto.Config) (*Tracer, error) {
+	spec := config_obj.GetMonitoring().GetTracing()
+	if spec.GetEndpoint() == "" {
+		return nil, errors.New("Tracing: No endpoint configured")
+	}
+
+	url := strings.TrimSuffix(spec.Endpoint, "/")
+	if !strings.HasPrefix(url, "http://") &&
+		!strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf(
+			"Tracing: Endpoint must be an http or https URL: %v", spec.Endpoint)
+	}
+
+	// The endpoint is the collector's base URL unless the traces
+	// path is given.
+	if !strings.HasSuffix(url, "/v1/traces") {
+		url += "/v1/traces"
+	}
+
+	result := &Tracer{
+		url:           url,
+		headers:       make(map[string]string),
+		service_name:  spec.ServiceName,
+		sample_ratio:  spec.SampleRatio,
+		trace_storage: spec.TraceStorage,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		flush:         make(chan bool, 1),
+	}
+
+	if result.service_name == "" {
+		result.service_name = DEFAULT_SERVICE_NAME
+	}
+
+	if result.sample_ratio <= 0 || result.sample_ratio > 1 {
+		result.sample_ratio = 1
+	}
+
+	for _, header := range spec.Headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf(
+				"Tracing: Headers must be of the form 'Name: Value': %v", header)
+		}
+		result.headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return result, nil
+}
+
+// Start exporting spans if tracing is configured.
+func StartTracing(ctx context.Context, wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+	if config_obj.GetMonitoring().GetTracing().GetEndpoint() == "" {
+		return nil
+	}
+
+	t, err := newTracer(config_obj)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	tracer = t
+	mu.Unlock()
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> tracing: Exporting spans to %v (sample ratio %v)",
+		t.url, t.sample_ratio)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		t.run(ctx, config_obj)
+
+		mu.Lock()
+		if tracer == t {
+			tracer = nil
+		}
+		mu.Unlock()
+	}()
+
+	return nil
+}
@@ -0,0 +1,282 @@
+// Record spans for client request handling, flow processing, hunt
+// scheduling and storage operations so we can see where the time
+// goes between a client sending results and them appearing in the
+// GUI.
+//
+// Spans are exported to an OpenTelemetry collector using OTLP over
+// HTTP with the JSON encoding. When tracing is not configured all
+// calls are cheap no-ops.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mrand "math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type SpanKind int
+
+// Span kinds as defined by OTLP.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+const (
+	statusUnset = 0
+	statusError = 2
+)
+
+type contextKey int
+
+const spanKey contextKey = 0
+
+var (
+	mu     sync.Mutex
+	tracer *Tracer
+)
+
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// A Span is safe to use when nil so callers do not need to check if
+// tracing is enabled.
+type Span struct {
+	mu sync.Mutex
+
+	tracer    *Tracer
+	recording bool
+
+	trace_id  [16]byte
+	span_id   [8]byte
+	parent_id [8]byte
+
+	name       string
+	kind       SpanKind
+	start      time.Time
+	end        time.Time
+	attributes []Attribute
+
+	status_code    int
+	status_message string
+}
+
+func (self *Span) IsRecording() bool {
+	return self != nil && self.recording
+}
+
+func (self *Span) SetAttributes(attributes ...Attribute) {
+	if !self.IsRecording() {
+		return
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.attributes = append(self.attributes, attributes...)
+}
+
+// Mark the span as failed.
+func (self *Span) RecordError(err error) {
+	if err == nil || !self.IsRecording() {
+		return
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.status_code = statusError
+	self.status_message = err.Error()
+}
+
+// Finish the span and queue it for export. Calling End more than
+// once has no effect.
+func (self *Span) End() {
+	if !self.IsRecording() {
+		return
+	}
+
+	self.mu.Lock()
+	if !self.end.IsZero() {
+		self.mu.Unlock()
+		return
+	}
+	self.end = utils.GetTime().Now()
+	self.mu.Unlock()
+
+	self.tracer.export(self)
+}
+
+// The W3C traceparent header value for propagating the trace.
+func (self *Span) TraceParent() string {
+	if !self.IsRecording() {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01",
+		hex.EncodeToString(self.trace_id[:]),
+		hex.EncodeToString(self.span_id[:]))
+}
+
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey).(*Span)
+	return span
+}
+
+// Attach the span to a new context. This allows work done in the
+// background with a different context to be part of the trace.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey, span)
+}
+
+func getTracer() *Tracer {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return tracer
+}
+
+func IsEnabled() bool {
+	return getTracer() != nil
+}
+
+// Start a new span as a child of the span in the context (if any).
+func Start(ctx context.Context, name string,
+	attributes ...Attribute) (context.Context, *Span) {
+	return StartWithKind(ctx, name, SpanKindInternal, attributes...)
+}
+
+func StartWithKind(ctx context.Context, name string, kind SpanKind,
+	attributes ...Attribute) (context.Context, *Span) {
+	t := getTracer()
+	if t == nil {
+		return ctx, nil
+	}
+
+	parent := SpanFromContext(ctx)
+	span := &Span{
+		tracer:     t,
+		name:       name,
+		kind:       kind,
+		start:      utils.GetTime().Now(),
+		attributes: attributes,
+	}
+
+	if parent != nil {
+		// Unsampled traces stay unsampled.
+		if !parent.recording {
+			return ctx, parent
+		}
+		span.trace_id = parent.trace_id
+		span.parent_id = parent.span_id
+		span.recording = true
+
+	} else {
+		span.recording = t.sample()
+		if !span.recording {
+			return ContextWithSpan(ctx, span), span
+		}
+		_, _ = rand.Read(span.trace_id[:])
+	}
+
+	_, _ = rand.Read(span.span_id[:])
+	return ContextWithSpan(ctx, span), span
+}
+
+// Start a span for an incoming HTTP request. If the request carries
+// a W3C traceparent header (e.g. from a load balancer) the span joins
+// that trace.
+func StartRequest(req *http.Request, name string,
+	attributes ...Attribute) (context.Context, *Span) {
+	ctx := req.Context()
+	if !IsEnabled() {
+		return ctx, nil
+	}
+
+	parent := parseTraceParent(req.Header.Get("traceparent"))
+	if parent != nil {
+		ctx = ContextWithSpan(ctx, parent)
+	}
+
+	return StartWithKind(ctx, name, SpanKindServer, attributes...)
+}
+
+// Parse a W3C traceparent header into a remote parent span.
+func parseTraceParent(header string) *Span {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) != 4 || parts[0] != "00" ||
+		len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return nil
+	}
+
+	result := &Span{}
+	_, err := hex.Decode(result.trace_id[:], []byte(parts[1]))
+	if err != nil {
+		return nil
+	}
+
+	_, err = hex.Decode(result.span_id[:], []byte(parts[2]))
+	if err != nil {
+		return nil
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return nil
+	}
+
+	// The caller decided if the trace is sampled.
+	result.recording = flags[0]&1 == 1
+	return result
+}
+
+func (self *Tracer) sample() bool {
+	if self.sample_ratio >= 1 {
+		return true
+	}
+	return mrand.Float64() < self.sample_ratio
+}
+
+// Datastore and filestore calls do not take a context so their spans
+// are not part of the request's trace. There are many such calls so
+// they are only recorded when trace_storage is enabled.
+func StartStorage(name string, attributes ...Attribute) *Span {
+	if !StorageEnabled() {
+		return nil
+	}
+
+	_, span := Start(context.Background(), name, attributes...)
+	return span
+}
+
+// Callers check this before building the span's attributes since
+// storage calls are on the hot path.
+func StorageEnabled() bool {
+	t := getTracer()
+	return t != nil && t.trace_storage
+}
@@ -0,0 +1,225 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+// A collector which remembers the spans it received.
+type collector struct {
+	mu      sync.Mutex
+	spans   []*ordereddict.Dict
+	headers http.Header
+}
+
+func (self *collector) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+
+	request := struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []*ordereddict.Dict `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}{}
+	err := json.Unmarshal(body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.headers = req.Header
+	for _, r := range request.ResourceSpans {
+		for _, s := range r.ScopeSpans {
+			self.spans = append(self.spans, s.Spans...)
+		}
+	}
+}
+
+func (self *collector) get(name string) *ordereddict.Dict {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for _, span := range self.spans {
+		span_name, _ := span.GetString("name")
+		if span_name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func installTracer(t *testing.T, url string,
+	spec *config_proto.TracingConfig) *Tracer {
+	spec.Endpoint = url
+	config_obj := &config_proto.Config{
+		Monitoring: &config_proto.MonitoringConfig{Tracing: spec},
+	}
+
+	result, err := newTracer(config_obj)
+	assert.NoError(t, err)
+
+	mu.Lock()
+	tracer = result
+	mu.Unlock()
+
+	t.Cleanup(func() {
+		mu.Lock()
+		tracer = nil
+		mu.Unlock()
+	})
+
+	return result
+}
+
+func getString(span *ordereddict.Dict, field string) string {
+	value, _ := span.GetString(field)
+	return value
+}
+
+func getAny(span *ordereddict.Dict, field string) interface{} {
+	value, _ := span.Get(field)
+	return value
+}
+
+func TestSpans(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	// Nothing is recorded when tracing is disabled.
+	_, span := Start(context.Background(), "disabled")
+	assert.True(t, !span.IsRecording())
+	span.End()
+
+	tracer := installTracer(t, server.URL, &config_proto.TracingConfig{
+		Headers: []string{"X-Token: secret"},
+	})
+	assert.Equal(t, server.URL+"/v1/traces", tracer.url)
+
+	ctx, parent := Start(context.Background(), "parent",
+		String("client_id", "C.1234"))
+	_, child := Start(ctx, "child", Int("count", 5))
+	child.RecordError(errors.New("Failed"))
+	child.End()
+	parent.End()
+
+	// Storage spans are off by default.
+	assert.True(t, !StorageEnabled())
+	assert.True(t, StartStorage("datastore.read") == nil)
+
+	assert.NoError(t, tracer.Flush(context.Background()))
+	assert.Equal(t, "secret", c.headers.Get("X-Token"))
+
+	parent_span := c.get("parent")
+	child_span := c.get("child")
+	assert.NotNil(t, parent_span)
+	assert.NotNil(t, child_span)
+
+	assert.Equal(t, getString(parent_span, "traceId"),
+		getString(child_span, "traceId"))
+	assert.Equal(t, getString(parent_span, "spanId"),
+		getString(child_span, "parentSpanId"))
+	assert.Equal(t, "", getString(parent_span, "parentSpanId"))
+
+	assert.Equal(t, `[{"key":"client_id","value":{"stringValue":"C.1234"}}]`,
+		json.MustMarshalString(getAny(parent_span, "attributes")))
+	assert.Equal(t, `[{"key":"count","value":{"intValue":"5"}}]`,
+		json.MustMarshalString(getAny(child_span, "attributes")))
+	assert.Equal(t, `{"code":2,"message":"Failed"}`,
+		json.MustMarshalString(getAny(child_span, "status")))
+}
+
+func TestTraceParent(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	tracer := installTracer(t, server.URL, &config_proto.TracingConfig{})
+
+	// A sampled request from a load balancer joins its trace.
+	req := httptest.NewRequest("POST", "/control", nil)
+	req.Header.Set("traceparent",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	_, span := StartRequest(req, "frontend.receive")
+	assert.True(t, span.IsRecording())
+	span.End()
+
+	// An unsampled request is not recorded and neither are its
+	// children.
+	req = httptest.NewRequest("POST", "/control", nil)
+	req.Header.Set("traceparent",
+		"00-0af7651916cd43dd8448eb211c80319d-b7ad6b7169203331-00")
+	ctx, unsampled := StartRequest(req, "unsampled")
+	assert.True(t, !unsampled.IsRecording())
+
+	_, child := Start(ctx, "unsampled_child")
+	assert.True(t, !child.IsRecording())
+
+	assert.NoError(t, tracer.Flush(context.Background()))
+
+	received := c.get("frontend.receive")
+	assert.NotNil(t, received)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c",
+		getString(received, "traceId"))
+	assert.Equal(t, "b7ad6b7169203331", getString(received, "parentSpanId"))
+	assert.True(t, c.get("unsampled") == nil)
+	assert.True(t, c.get("unsampled_child") == nil)
+
+	// The span id is propagated in the traceparent format.
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-"+
+		getString(received, "spanId")+"-01", span.TraceParent())
+}
+
+func TestExportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			http.Error(w, "", http.StatusServiceUnavailable)
+		}))
+	defer server.Close()
+
+	tracer := installTracer(t, server.URL, &config_proto.TracingConfig{
+		TraceStorage: true,
+	})
+
+	span := StartStorage("datastore.read", String("path", "/clients"))
+	assert.True(t, span.IsRecording())
+	span.End()
+
+	// Ending twice does not export the span again.
+	span.End()
+	assert.Equal(t, 1, len(tracer.queue))
+
+	// The spans are dropped if the collector fails.
+	assert.Error(t, tracer.Flush(context.Background()))
+	assert.Equal(t, 0, len(tracer.queue))
+
+	// Bad configs are rejected.
+	_, err := newTracer(&config_proto.Config{
+		Monitoring: &config_proto.MonitoringConfig{
+			Tracing: &config_proto.TracingConfig{
+				Endpoint: "localhost:4318",
+			}}})
+	assert.Error(t, err)
+
+	_, err = newTracer(&config_proto.Config{
+		Monitoring: &config_proto.MonitoringConfig{
+			Tracing: &config_proto.TracingConfig{
+				Endpoint: "http://localhost:4318",
+				Headers:  []string{"NoValue"},
+			}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package uploads
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// An uploader for dry run collections. Files are not read or sent
+// anywhere - they are only counted so the size of the real
+// collection can be estimated.
+type DryRunUploader struct {
+	// Called with the size of each file which would have been
+	// uploaded.
+	Estimate func(size int64)
+
+	mu         sync.Mutex
+	Count      int
+	TotalBytes int64
+}
+
+func (self *DryRunUploader) Upload(
+	ctx context.Context,
+	scope vfilter.Scope,
+	filename *accessors.OSPath,
+	accessor string,
+	store_as_name *accessors.OSPath,
+	expected_size int64,
+	mtime time.Time,
+	atime time.Time,
+	ctime time.Time,
+	btime time.Time,
+	reader io.Reader) (*UploadResponse, error) {
+
+	if accessor == "" {
+		accessor = "auto"
+	}
+
+	if store_as_name == nil {
+		store_as_name = filename
+	}
+
+	cached, pres, closer := DeduplicateUploads(scope, store_as_name)
+	defer closer()
+	if pres {
+		return cached, nil
+	}
+
+	// Sparse files only transfer their data ranges.
+	range_reader, ok := reader.(RangeReader)
+	if ok {
+		expected_size = 0
+		for _, rng := range range_reader.Ranges() {
+			if !rng.IsSparse {
+				expected_size += rng.Length
+			}
+		}
+	}
+
+	if expected_size < 0 {
+		expected_size = 0
+	}
+
+	self.mu.Lock()
+	self.Count++
+	self.TotalBytes += expected_size
+	self.mu.Unlock()
+
+	if self.Estimate != nil {
+		self.Estimate(expected_size)
+	}
+
+	result := &UploadResponse{
+		StoredName: store_as_name.String(),
+		Accessor:   accessor,
+		Components: store_as_name.Components[:],
+		Size:       uint64(expected_size),
+	}
+	if accessor != "data" {
+		result.Path = filename.String()
+	}
+
+	CacheUploadResult(scope, store_as_name, result)
+	return result, nil
+}
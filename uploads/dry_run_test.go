@@ -0,0 +1,55 @@
+package uploads
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+func TestDryRunUploader(t *testing.T) {
+	estimated := []int64{}
+	uploader := &DryRunUploader{
+		Estimate: func(size int64) {
+			estimated = append(estimated, size)
+		},
+	}
+
+	ctx := context.Background()
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	// The expected size is counted without reading the file.
+	response, err := uploader.Upload(ctx, scope, filename, "file", nil,
+		1000, nilTime, nilTime, nilTime, nilTime, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1000), response.Size)
+	assert.Equal(t, "/foo", response.Path)
+
+	// Uploading the same file again is deduplicated.
+	_, err = uploader.Upload(ctx, scope, filename, "file", nil,
+		1000, nilTime, nilTime, nilTime, nilTime, nil)
+	assert.NoError(t, err)
+
+	// Only the data ranges of sparse files are counted.
+	reader := &TestRangeReader{
+		Reader: bytes.NewReader([]byte("Hello world hello world")),
+		ranges: []Range{
+			{Offset: 0, Length: 6, IsSparse: false},
+			{Offset: 6, Length: 6, IsSparse: true},
+			{Offset: 12, Length: 6, IsSparse: false},
+		},
+	}
+	response, err = uploader.Upload(ctx, scope,
+		accessors.MustNewLinuxOSPath("sparse"), "ntfs", nil,
+		18, nilTime, nilTime, nilTime, nilTime, reader)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), response.Size)
+
+	assert.Equal(t, 2, uploader.Count)
+	assert.Equal(t, int64(1012), uploader.TotalBytes)
+	assert.Equal(t, []int64{1000, 12}, estimated)
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// A list of networks.
+type Networks []*net.IPNet
+
+// Parse a list of CIDR ranges. Single addresses are accepted as
+// well.
+func ParseNetworks(cidrs []string) (Networks, error) {
+	result := Networks{}
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("Invalid network %v", cidr)
+			}
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			result = append(result, &net.IPNet{
+				IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, cidr_net, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid network %v: %w", cidr, err)
+		}
+		result = append(result, cidr_net)
+	}
+	return result, nil
+}
+
+// Is the address (with or without a port) in one of the networks?
+func (self Networks) Contains(addr string) bool {
+	ip := ParseAddr(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range self {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse the IP from an address with or without a port.
+func ParseAddr(addr string) net.IP {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return net.ParseIP(strings.TrimSpace(host))
+}
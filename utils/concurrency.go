@@ -42,6 +42,16 @@ func (self *Concurrency) EndConcurrencyControl() {
 	concurrencyControl.Dec()
 }
 
+// The maximum number of concurrent operations.
+func (self *Concurrency) Size() int {
+	return cap(self.concurrency)
+}
+
+// The number of operations currently holding a slot.
+func (self *Concurrency) InUse() int {
+	return len(self.concurrency)
+}
+
 func NewConcurrencyControl(size int, timeout time.Duration) *Concurrency {
 	return &Concurrency{
 		timeout:     timeout,
@@ -1,9 +1,11 @@
 package utils
 
 import (
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Velocidex/ordereddict"
 	"www.velocidex.com/golang/vfilter"
@@ -70,3 +72,53 @@ func GetAny(dict *ordereddict.Dict, key string) vfilter.Any {
 	res, _ := subdict.Get(last)
 	return res
 }
+
+// Get a value from a nested path in a record. Unlike the dot notation
+// above the path components may contain dots.
+func GetPath(dict *ordereddict.Dict, path ...string) (vfilter.Any, bool) {
+	var value vfilter.Any = dict
+	for _, p := range path {
+		nested, ok := value.(*ordereddict.Dict)
+		if !ok {
+			return nil, false
+		}
+		value, ok = nested.Get(p)
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Get a string from a nested path in a record. Other values are
+// formatted as strings.
+func GetPathString(dict *ordereddict.Dict, path ...string) string {
+	value, _ := GetPath(dict, path...)
+	switch t := value.(type) {
+	case string:
+		return t
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// Get a time from a nested path in a record. Timestamps that look
+// like RFC3339 are already parsed when the record is decoded, those
+// without a time zone are taken to be UTC.
+func GetPathTime(dict *ordereddict.Dict, path ...string) time.Time {
+	value, _ := GetPath(dict, path...)
+	switch t := value.(type) {
+	case time.Time:
+		return t.UTC()
+	case string:
+		for _, format := range []string{time.RFC3339Nano, "2006-01-02T15:04:05"} {
+			ts, err := time.Parse(format, t)
+			if err == nil {
+				return ts.UTC()
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -1,7 +1,10 @@
 package utils;
 
 import (
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
 )
 
 // Retrieve the Remote Address from a request in a reverse-proxy compatible way.
@@ -13,3 +16,53 @@ func RemoteAddr(req *http.Request, header string) string {
 	}
 	return req.RemoteAddr
 }
+
+// A list of networks of reverse proxies and load balancers whose
+// forwarded headers we trust.
+type TrustedProxies []*net.IPNet
+
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	networks, err := ParseNetworks(cidrs)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid trusted proxy: %w", err)
+	}
+	return TrustedProxies(networks), nil
+}
+
+// Is the address (with or without a port) a trusted proxy?
+func (self TrustedProxies) IsTrusted(addr string) bool {
+	return Networks(self).Contains(addr)
+}
+
+// Retrieve the Remote Address from a request only trusting the
+// forwarded header when the request came from a trusted proxy. The
+// header may contain a list of addresses (e.g. X-Forwarded-For) each
+// appended by a proxy, so we take the right most address which is
+// not a trusted proxy - addresses to its left may be forged by the
+// client.
+//
+// If no trusted proxies are configured the header is trusted as
+// before.
+func (self TrustedProxies) RemoteAddr(req *http.Request, header string) string {
+	if len(self) == 0 {
+		return RemoteAddr(req, header)
+	}
+
+	if header == "" || !self.IsTrusted(req.RemoteAddr) {
+		return req.RemoteAddr
+	}
+
+	addrs := strings.Split(req.Header.Get(header), ",")
+	for i := len(addrs) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(addrs[i])
+		if addr == "" {
+			continue
+		}
+
+		if i == 0 || !self.IsTrusted(addr) {
+			return addr
+		}
+	}
+
+	return req.RemoteAddr
+}
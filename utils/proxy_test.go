@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestTrustedProxies(t *testing.T) {
+	_, err := ParseTrustedProxies([]string{"not an address"})
+	assert.Error(t, err)
+
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	assert.NoError(t, err)
+
+	assert.True(t, trusted.IsTrusted("10.1.2.3:443"))
+	assert.True(t, trusted.IsTrusted("192.168.1.1"))
+	assert.True(t, !trusted.IsTrusted("192.168.1.2"))
+
+	req := &http.Request{
+		RemoteAddr: "10.0.0.1:1234",
+		Header:     http.Header{},
+	}
+
+	// The client may forge the left most addresses - take the right
+	// most address not added by a trusted proxy.
+	req.Header.Set("X-Forwarded-For", "1.1.1.1, 2.2.2.2, 192.168.1.1")
+	assert.Equal(t, "2.2.2.2", trusted.RemoteAddr(req, "X-Forwarded-For"))
+
+	// Only trusted proxies may forward addresses.
+	req.RemoteAddr = "3.3.3.3:1234"
+	assert.Equal(t, "3.3.3.3:1234", trusted.RemoteAddr(req, "X-Forwarded-For"))
+
+	// Without trusted proxies the header is used as before.
+	assert.Equal(t, "1.1.1.1, 2.2.2.2, 192.168.1.1",
+		TrustedProxies{}.RemoteAddr(req, "X-Forwarded-For"))
+}
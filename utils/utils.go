@@ -57,6 +57,21 @@ func StringSliceEq(a []string, b []string) bool {
 	return true
 }
 
+func StringMapEq(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		other, pres := b[k]
+		if !pres || other != v {
+			return false
+		}
+	}
+
+	return true
+}
+
 func hard_wrap(text string, colBreak int) string {
 	text = strings.TrimSpace(text)
 	text = strings.Replace(text, "\r\n", "\n", -1)
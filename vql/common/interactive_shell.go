@@ -0,0 +1,237 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	"www.velocidex.com/golang/velociraptor/responder"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type InteractiveShellPluginArgs struct {
+	Argv        []string `vfilter:"required,field=argv,doc=Argv to run the shell with."`
+	Cwd         string   `vfilter:"optional,field=cwd,doc=If specified we change to this working directory first."`
+	IdleTimeout int64    `vfilter:"optional,field=idle_timeout,doc=Terminate the shell when no input was received for this many seconds (default 600)."`
+}
+
+// Runs a shell on the client and feeds it input sent from the server
+// for this session. All input and output is emitted as rows so the
+// session is recorded in the collection.
+type InteractiveShellPlugin struct{}
+
+func (self InteractiveShellPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.EXECVE)
+		if err != nil {
+			scope.Log("interactive_shell: %v", err)
+			return
+		}
+
+		config_obj, ok := artifacts.GetConfig(scope)
+		if ok && config_obj.PreventExecve {
+			scope.Log("interactive_shell: Not allowed to execve by configuration.")
+			return
+		}
+
+		arg := &InteractiveShellPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("interactive_shell: %v", err)
+			return
+		}
+
+		if len(arg.Argv) == 0 {
+			scope.Log("interactive_shell: no command to run")
+			return
+		}
+
+		if arg.IdleTimeout == 0 {
+			arg.IdleTimeout = 600
+		}
+
+		session_id_any, _ := scope.Resolve("_SessionId")
+		session_id, _ := session_id_any.(string)
+		if session_id == "" {
+			scope.Log("interactive_shell: Can only run within a client collection")
+			return
+		}
+
+		input_chan, closer, err := responder.RegisterShellSession(session_id)
+		if err != nil {
+			scope.Log("interactive_shell: %v", err)
+			return
+		}
+		defer closer()
+
+		// Kill subprocess when the scope is destroyed.
+		sub_ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		err = scope.AddDestructor(cancel)
+		if err != nil {
+			return
+		}
+
+		scope.Log("interactive_shell: Running interactive shell %v", arg.Argv)
+
+		command := exec.CommandContext(sub_ctx, arg.Argv[0], arg.Argv[1:]...)
+		command.Dir = arg.Cwd
+
+		stdin, err := command.StdinPipe()
+		if err != nil {
+			scope.Log("interactive_shell: %v", err)
+			return
+		}
+
+		stdout, err := command.StdoutPipe()
+		if err != nil {
+			scope.Log("interactive_shell: %v", err)
+			return
+		}
+
+		stderr, err := command.StderrPipe()
+		if err != nil {
+			scope.Log("interactive_shell: %v", err)
+			return
+		}
+
+		send := func(stream, data string) {
+			select {
+			case <-ctx.Done():
+			case output_chan <- ordereddict.NewDict().
+				Set("Time", utils.GetTime().Now().UTC()).
+				Set("Stream", stream).
+				Set("Data", data):
+			}
+		}
+
+		err = command.Start()
+		if err != nil {
+			scope.Log("interactive_shell: %v", err)
+			send("exit", err.Error())
+			return
+		}
+
+		wg := &sync.WaitGroup{}
+		for stream, pipe := range map[string]io.Reader{
+			"stdout": stdout,
+			"stderr": stderr,
+		} {
+			wg.Add(1)
+			go func(stream string, pipe io.Reader) {
+				defer wg.Done()
+
+				buf := make([]byte, 4096)
+				for {
+					n, err := pipe.Read(buf)
+					if n > 0 {
+						send(stream, string(buf[:n]))
+					}
+					if err != nil {
+						return
+					}
+				}
+			}(stream, pipe)
+		}
+
+		// Closed when both output pipes are closed.
+		done := make(chan bool)
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		idle_timeout := time.Duration(arg.IdleTimeout) * time.Second
+		idle := time.NewTimer(idle_timeout)
+		defer idle.Stop()
+
+	process_input:
+		for {
+			select {
+			case <-ctx.Done():
+				break process_input
+
+			case <-done:
+				break process_input
+
+			case <-idle.C:
+				scope.Log("interactive_shell: No input for %v - terminating shell",
+					idle_timeout)
+				cancel()
+				break process_input
+
+			case input := <-input_chan:
+				if !idle.Stop() {
+					select {
+					case <-idle.C:
+					default:
+					}
+				}
+				idle.Reset(idle_timeout)
+
+				if len(input.Data) > 0 {
+					send("stdin", string(input.Data))
+					_, err := stdin.Write(input.Data)
+					if err != nil {
+						scope.Log("interactive_shell: %v", err)
+					}
+				}
+
+				if input.Close {
+					scope.Log("interactive_shell: Session closed by server")
+					stdin.Close()
+				}
+			}
+		}
+
+		<-done
+		return_code := 0
+		err = command.Wait()
+		if err != nil {
+			return_code = -1
+			exiterr, ok := err.(*exec.ExitError)
+			if ok {
+				status, ok := exiterr.Sys().(syscall.WaitStatus)
+				if ok {
+					return_code = status.ExitStatus()
+				}
+			}
+		}
+
+		send("exit", fmt.Sprintf("%v", return_code))
+	}()
+
+	return output_chan
+}
+
+func (self InteractiveShellPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "interactive_shell",
+		Doc:      "Run a shell which receives its input from the server.",
+		ArgType:  type_map.AddType(scope, &InteractiveShellPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.EXECVE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&InteractiveShellPlugin{})
+}
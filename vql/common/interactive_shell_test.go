@@ -0,0 +1,73 @@
+package common
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/responder"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+	"www.velocidex.com/golang/vfilter"
+)
+
+func TestInteractiveShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires /bin/sh")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	scope := vql_subsystem.MakeScope().AppendVars(
+		ordereddict.NewDict().
+			Set("_SessionId", "F.TestInteractiveShell").
+			Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+	defer scope.Close()
+
+	var rows []*ordereddict.Dict
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		for row := range (InteractiveShellPlugin{}).Call(ctx, scope,
+			ordereddict.NewDict().Set("argv", []string{"/bin/sh"})) {
+			rows = append(rows, vfilter.RowToDict(ctx, scope, row))
+		}
+	}()
+
+	// Wait for the shell to register.
+	send := func(input *crypto_proto.ShellInput) {
+		vtesting.WaitUntil(5*time.Second, t, func() bool {
+			return responder.SendShellInput("F.TestInteractiveShell", input) == nil
+		})
+	}
+
+	send(&crypto_proto.ShellInput{Data: []byte("echo hello\n")})
+	send(&crypto_proto.ShellInput{Close: true})
+	<-done
+
+	streams := []string{}
+	output := ""
+	for _, row := range rows {
+		stream, _ := row.GetString("Stream")
+		data, _ := row.GetString("Data")
+		streams = append(streams, stream)
+		if stream == "stdout" {
+			output += data
+		}
+	}
+
+	assert.Equal(t, "stdin", streams[0])
+	assert.Equal(t, "exit", streams[len(streams)-1])
+	assert.Equal(t, "hello", strings.TrimSpace(output))
+
+	// The session is removed when the shell exits.
+	assert.Error(t, responder.SendShellInput("F.TestInteractiveShell",
+		&crypto_proto.ShellInput{}))
+}
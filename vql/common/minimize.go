@@ -0,0 +1,181 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/json"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	MINIMIZE_DROP = "drop"
+	MINIMIZE_MASK = "mask"
+
+	MINIMIZE_REDACTED = "[REDACTED]"
+)
+
+type MinimizeArgs struct {
+	Query       vfilter.StoredQuery `vfilter:"required,field=query,doc=The query to filter."`
+	Column      string              `vfilter:"optional,field=column,doc=Apply to columns matching this regex (default all columns)."`
+	Pattern     string              `vfilter:"optional,field=pattern,doc=Match column values against this regex. When masking an empty pattern masks the entire value."`
+	Action      string              `vfilter:"optional,field=action,doc=Either drop (drop matching rows) or mask (replace matching data, the default)."`
+	Replacement string              `vfilter:"optional,field=replacement,doc=Replace masked data with this (default [REDACTED])."`
+}
+
+// A compiled data minimization filter.
+type minimizer struct {
+	column      *regexp.Regexp
+	pattern     *regexp.Regexp
+	drop        bool
+	replacement string
+}
+
+func newMinimizer(arg *MinimizeArgs) (*minimizer, error) {
+	result := &minimizer{replacement: arg.Replacement}
+	if result.replacement == "" {
+		result.replacement = MINIMIZE_REDACTED
+	}
+
+	switch arg.Action {
+	case MINIMIZE_DROP:
+		if arg.Pattern == "" {
+			return nil, errors.New("Dropping rows requires a pattern")
+		}
+		result.drop = true
+
+	case "", MINIMIZE_MASK:
+	default:
+		return nil, fmt.Errorf("Unknown action %v", arg.Action)
+	}
+
+	var err error
+	result.column, err = regexp.Compile(arg.Column)
+	if err != nil {
+		return nil, err
+	}
+
+	if arg.Pattern != "" {
+		result.pattern, err = regexp.Compile(arg.Pattern)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Returns the string form of the value to match against. Values
+// which are not strings are matched against their JSON encoding.
+func (self *minimizer) stringify(value vfilter.Any) (string, bool) {
+	switch t := value.(type) {
+	case nil, vfilter.Null, *vfilter.Null:
+		return "", false
+	case string:
+		return t, true
+	default:
+		serialized, err := json.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+		return string(serialized), true
+	}
+}
+
+// Filter the row returning nil if it should be dropped.
+func (self *minimizer) Apply(
+	scope vfilter.Scope, row vfilter.Row) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+	for _, column := range scope.GetMembers(row) {
+		value, _ := scope.Associative(row, column)
+		if !self.column.MatchString(column) {
+			result.Set(column, value)
+			continue
+		}
+
+		str_value, ok := self.stringify(value)
+		if !ok {
+			result.Set(column, value)
+			continue
+		}
+
+		switch {
+		case self.drop:
+			if self.pattern.MatchString(str_value) {
+				return nil
+			}
+			result.Set(column, value)
+
+		case self.pattern == nil:
+			result.Set(column, self.replacement)
+
+		case self.pattern.MatchString(str_value):
+			result.Set(column, self.pattern.ReplaceAllLiteralString(
+				str_value, self.replacement))
+
+		default:
+			result.Set(column, value)
+		}
+	}
+	return result
+}
+
+type MinimizePlugin struct{}
+
+func (self MinimizePlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		arg := &MinimizeArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("minimize: %v", err)
+			return
+		}
+
+		// An invalid filter must not let the data through.
+		filter, err := newMinimizer(arg)
+		if err != nil {
+			scope.Log("minimize: %v", err)
+			return
+		}
+
+		for item := range arg.Query.Eval(ctx, scope) {
+			row := filter.Apply(scope, item)
+			if row == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self MinimizePlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "minimize",
+		Doc:     "Drop or mask data in the rows of another query before they leave the endpoint.",
+		ArgType: type_map.AddType(scope, &MinimizeArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&MinimizePlugin{})
+}
@@ -257,6 +257,9 @@ func (self _ParseFloat) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vf
 	}
 }
 
+// No args
+type _NowArgs struct{}
+
 type _Now struct{}
 
 func (self _Now) Call(
@@ -270,7 +273,7 @@ func (self _Now) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.F
 	return &vfilter.FunctionInfo{
 		Name:    "now",
 		Doc:     "Returns current time in seconds since epoch.",
-		ArgType: type_map.AddType(scope, &_ToIntArgs{}),
+		ArgType: type_map.AddType(scope, &_NowArgs{}),
 	}
 }
 
@@ -0,0 +1,524 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+// A pure Go implementation of the ssdeep context triggered piecewise
+// hash (CTPH). This follows the streaming engine of the reference
+// implementation so files can be hashed in a single pass.
+const (
+	ssdeepRollingWindow  = 7
+	ssdeepMinBlocksize   = 3
+	ssdeepHashPrime      = 0x01000193
+	ssdeepHashInit       = 0x28021967
+	ssdeepSpamSumLength  = 64
+	ssdeepNumBlockHashes = 31
+
+	ssdeepB64 = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+)
+
+func ssdeepBlockSize(i int) uint64 {
+	return ssdeepMinBlocksize << uint(i)
+}
+
+type ssdeepRollState struct {
+	window     [ssdeepRollingWindow]byte
+	h1, h2, h3 uint32
+	n          uint32
+}
+
+func (self *ssdeepRollState) hash(c byte) {
+	self.h2 -= self.h1
+	self.h2 += ssdeepRollingWindow * uint32(c)
+
+	self.h1 += uint32(c)
+	self.h1 -= uint32(self.window[self.n%ssdeepRollingWindow])
+
+	self.window[self.n%ssdeepRollingWindow] = c
+	self.n++
+
+	self.h3 <<= 5
+	self.h3 ^= uint32(c)
+}
+
+func (self *ssdeepRollState) sum() uint32 {
+	return self.h1 + self.h2 + self.h3
+}
+
+func ssdeepSumHash(c byte, h uint32) uint32 {
+	return (h * ssdeepHashPrime) ^ uint32(c)
+}
+
+type ssdeepBlockHash struct {
+	h, halfh   uint32
+	digest     [ssdeepSpamSumLength]byte
+	halfdigest byte
+	dlen       int
+}
+
+// SSDeep calculates the ssdeep hash of the data written to it.
+type SSDeep struct {
+	bhstart, bhend int
+	bh             [ssdeepNumBlockHashes]ssdeepBlockHash
+	total_size     uint64
+	roll           ssdeepRollState
+}
+
+func NewSSDeep() *SSDeep {
+	result := &SSDeep{bhend: 1}
+	result.bh[0].h = ssdeepHashInit
+	result.bh[0].halfh = ssdeepHashInit
+	return result
+}
+
+func (self *SSDeep) Write(data []byte) (int, error) {
+	self.total_size += uint64(len(data))
+	for _, c := range data {
+		self.step(c)
+	}
+	return len(data), nil
+}
+
+func (self *SSDeep) tryForkBlockhash() {
+	if self.bhend >= ssdeepNumBlockHashes {
+		return
+	}
+
+	obh := &self.bh[self.bhend-1]
+	nbh := &self.bh[self.bhend]
+	nbh.h = obh.h
+	nbh.halfh = obh.halfh
+	nbh.digest[0] = 0
+	nbh.halfdigest = 0
+	nbh.dlen = 0
+	self.bhend++
+}
+
+func (self *SSDeep) tryReduceBlockhash() {
+	// We need at least two working hashes.
+	if self.bhend-self.bhstart < 2 {
+		return
+	}
+
+	// The initial blocksize estimate would select this or a
+	// smaller blocksize.
+	if ssdeepBlockSize(self.bhstart)*ssdeepSpamSumLength >= self.total_size {
+		return
+	}
+
+	// The estimate adjustment would select this blocksize.
+	if self.bh[self.bhstart+1].dlen < ssdeepSpamSumLength/2 {
+		return
+	}
+
+	// We are no longer interested in the smallest blocksize.
+	self.bhstart++
+}
+
+func (self *SSDeep) step(c byte) {
+	self.roll.hash(c)
+	h := uint64(self.roll.sum())
+
+	for i := self.bhstart; i < self.bhend; i++ {
+		self.bh[i].h = ssdeepSumHash(c, self.bh[i].h)
+		self.bh[i].halfh = ssdeepSumHash(c, self.bh[i].halfh)
+	}
+
+	for i := self.bhstart; i < self.bhend; i++ {
+		// Once the trigger fails for one blocksize it also fails
+		// for all larger blocksizes.
+		bs := ssdeepBlockSize(i)
+		if h%bs != bs-1 {
+			break
+		}
+
+		bh := &self.bh[i]
+		if bh.dlen == 0 {
+			self.tryForkBlockhash()
+		}
+
+		bh.digest[bh.dlen] = ssdeepB64[bh.h%64]
+		bh.halfdigest = ssdeepB64[bh.halfh%64]
+
+		if bh.dlen < ssdeepSpamSumLength-1 {
+			// Only reset the hash if there is room for more
+			// characters. Otherwise the tail of the data is
+			// combined into the last character.
+			bh.dlen++
+			bh.digest[bh.dlen] = 0
+			bh.h = ssdeepHashInit
+
+			if bh.dlen < ssdeepSpamSumLength/2 {
+				bh.halfh = ssdeepHashInit
+				bh.halfdigest = 0
+			}
+		} else {
+			self.tryReduceBlockhash()
+		}
+	}
+}
+
+// Digest returns the hash in the usual blocksize:hash1:hash2 format.
+func (self *SSDeep) Digest() (string, error) {
+	bi := self.bhstart
+	h := self.roll.sum()
+
+	// The initial blocksize guess.
+	for ssdeepBlockSize(bi)*ssdeepSpamSumLength < self.total_size {
+		bi++
+		if bi >= ssdeepNumBlockHashes {
+			return "", fmt.Errorf("ssdeep: input too large")
+		}
+	}
+
+	// Adapt the blocksize guess to the actual digest length.
+	for bi >= self.bhend {
+		bi--
+	}
+	for bi > self.bhstart && self.bh[bi].dlen < ssdeepSpamSumLength/2 {
+		bi--
+	}
+
+	result := &strings.Builder{}
+	fmt.Fprintf(result, "%d:", ssdeepBlockSize(bi))
+
+	bh := &self.bh[bi]
+	result.Write(bh.digest[:bh.dlen])
+	if h != 0 {
+		result.WriteByte(ssdeepB64[bh.h%64])
+	} else if bh.digest[bh.dlen] != 0 {
+		result.WriteByte(bh.digest[bh.dlen])
+	}
+	result.WriteByte(':')
+
+	if bi < self.bhend-1 {
+		bh = &self.bh[bi+1]
+
+		// The second part is truncated to half the length.
+		dlen := bh.dlen
+		if dlen > ssdeepSpamSumLength/2-1 {
+			dlen = ssdeepSpamSumLength/2 - 1
+		}
+		result.Write(bh.digest[:dlen])
+
+		if h != 0 {
+			result.WriteByte(ssdeepB64[bh.halfh%64])
+		} else if bh.halfdigest != 0 {
+			result.WriteByte(bh.halfdigest)
+		}
+
+	} else if h != 0 {
+		if bi == 0 {
+			result.WriteByte(ssdeepB64[bh.h%64])
+		} else {
+			result.WriteByte(ssdeepB64[bh.halfh%64])
+		}
+	}
+
+	return result.String(), nil
+}
+
+type ssdeepHash struct {
+	block_size uint64
+	hash1      string
+	hash2      string
+}
+
+// Runs of more than 3 identical characters carry little
+// information and are collapsed before comparing.
+func ssdeepEliminateSequences(hash string) string {
+	result := []byte{}
+	for i := 0; i < len(hash); i++ {
+		if i >= 3 && hash[i] == hash[i-1] &&
+			hash[i] == hash[i-2] && hash[i] == hash[i-3] {
+			continue
+		}
+		result = append(result, hash[i])
+	}
+	return string(result)
+}
+
+func parseSSDeep(hash string) (*ssdeepHash, error) {
+	parts := strings.SplitN(hash, ":", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("Invalid ssdeep hash %q", hash)
+	}
+
+	block_size, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || block_size < ssdeepMinBlocksize {
+		return nil, fmt.Errorf("Invalid ssdeep hash %q", hash)
+	}
+
+	// The hash may be followed by a file name.
+	hash2 := parts[2]
+	idx := strings.IndexByte(hash2, ',')
+	if idx >= 0 {
+		hash2 = hash2[:idx]
+	}
+
+	if len(parts[1]) > ssdeepSpamSumLength || len(hash2) > ssdeepSpamSumLength {
+		return nil, fmt.Errorf("Invalid ssdeep hash %q", hash)
+	}
+
+	return &ssdeepHash{
+		block_size: block_size,
+		hash1:      ssdeepEliminateSequences(parts[1]),
+		hash2:      ssdeepEliminateSequences(hash2),
+	}, nil
+}
+
+func ssdeepHasCommonSubstring(s1, s2 string) bool {
+	if len(s1) < ssdeepRollingWindow || len(s2) < ssdeepRollingWindow {
+		return false
+	}
+
+	substrings := make(map[string]bool)
+	for i := 0; i+ssdeepRollingWindow <= len(s1); i++ {
+		substrings[s1[i:i+ssdeepRollingWindow]] = true
+	}
+
+	for i := 0; i+ssdeepRollingWindow <= len(s2); i++ {
+		if substrings[s2[i:i+ssdeepRollingWindow]] {
+			return true
+		}
+	}
+	return false
+}
+
+// The edit distance where a replacement costs the same as an insert
+// and a delete.
+func ssdeepEditDistance(s1, s2 string) int {
+	previous := make([]int, len(s2)+1)
+	current := make([]int, len(s2)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s1); i++ {
+		current[0] = i
+		for j := 1; j <= len(s2); j++ {
+			cost := previous[j-1]
+			if s1[i-1] != s2[j-1] {
+				cost += 2
+			}
+			if previous[j]+1 < cost {
+				cost = previous[j] + 1
+			}
+			if current[j-1]+1 < cost {
+				cost = current[j-1] + 1
+			}
+			current[j] = cost
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(s2)]
+}
+
+func ssdeepScoreStrings(s1, s2 string, block_size uint64) int {
+	// Unless the strings share a substring of the rolling window
+	// size the match is probably a coincidence.
+	if !ssdeepHasCommonSubstring(s1, s2) {
+		return 0
+	}
+
+	score := ssdeepEditDistance(s1, s2)
+
+	// Scale the score to the string lengths and into the range
+	// 0-100.
+	score = (score * ssdeepSpamSumLength) / (len(s1) + len(s2))
+	score = (100 * score) / ssdeepSpamSumLength
+	if score >= 100 {
+		return 0
+	}
+	score = 100 - score
+
+	// Small blocksizes can not produce high scores for short
+	// strings.
+	if block_size >= (99+ssdeepRollingWindow)/ssdeepRollingWindow*ssdeepMinBlocksize {
+		return score
+	}
+
+	min_len := len(s1)
+	if len(s2) < min_len {
+		min_len = len(s2)
+	}
+
+	max_score := int(block_size/ssdeepMinBlocksize) * min_len
+	if score > max_score {
+		return max_score
+	}
+	return score
+}
+
+// SSDeepCompare returns a similarity score between 0 (no
+// similarity) and 100 (identical) of two ssdeep hashes.
+func SSDeepCompare(hash1, hash2 string) (int, error) {
+	h1, err := parseSSDeep(hash1)
+	if err != nil {
+		return 0, err
+	}
+
+	h2, err := parseSSDeep(hash2)
+	if err != nil {
+		return 0, err
+	}
+
+	// Hashes can only be compared if their blocksizes are the same
+	// or differ by a factor of two.
+	switch {
+	case h1.block_size == h2.block_size:
+		if h1.hash1 == h2.hash1 && h1.hash2 == h2.hash2 {
+			return 100, nil
+		}
+
+		score1 := ssdeepScoreStrings(h1.hash1, h2.hash1, h1.block_size)
+		score2 := ssdeepScoreStrings(h1.hash2, h2.hash2, h1.block_size*2)
+		if score2 > score1 {
+			return score2, nil
+		}
+		return score1, nil
+
+	case h1.block_size*2 == h2.block_size:
+		return ssdeepScoreStrings(h2.hash1, h1.hash2, h2.block_size), nil
+
+	case h2.block_size*2 == h1.block_size:
+		return ssdeepScoreStrings(h1.hash1, h2.hash2, h1.block_size), nil
+	}
+
+	return 0, nil
+}
+
+type SSDeepFunctionArgs struct {
+	Path     *accessors.OSPath `vfilter:"required,field=path,doc=Path to open and hash."`
+	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use (use 'data' to hash a string)"`
+}
+
+type SSDeepFunction struct{}
+
+func (self *SSDeepFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+	arg := &SSDeepFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("ssdeep: %v", err)
+		return vfilter.Null{}
+	}
+
+	cached_buffer := pool.Get().(*[]byte)
+	defer pool.Put(cached_buffer)
+
+	buf := *cached_buffer
+
+	err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+	if err != nil {
+		scope.Log("ssdeep: %s", err)
+		return vfilter.Null{}
+	}
+
+	fs, err := accessors.GetAccessor(arg.Accessor, scope)
+	if err != nil {
+		scope.Log("ssdeep: %v", err)
+		return vfilter.Null{}
+	}
+
+	file, err := fs.OpenWithOSPath(arg.Path)
+	if err != nil {
+		return vfilter.Null{}
+	}
+	defer file.Close()
+
+	hasher := NewSSDeep()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return vfilter.Null{}
+
+		default:
+			n, err := file.Read(buf)
+			if n > 0 {
+				_, _ = hasher.Write(buf[:n])
+			}
+
+			if n == 0 || err == io.EOF {
+				digest, err := hasher.Digest()
+				if err != nil {
+					scope.Log("ssdeep: %v", err)
+					return vfilter.Null{}
+				}
+				return digest
+
+			} else if err != nil {
+				scope.Log("ssdeep: %v", err)
+				return vfilter.Null{}
+			}
+
+			// Charge an op for each buffer we read
+			scope.ChargeOp()
+		}
+	}
+}
+
+func (self SSDeepFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "ssdeep",
+		Doc:      "Calculate the ssdeep fuzzy hash of a file.",
+		ArgType:  type_map.AddType(scope, &SSDeepFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+type SSDeepCompareFunctionArgs struct {
+	Hash1 string `vfilter:"required,field=hash1,doc=The first ssdeep hash."`
+	Hash2 string `vfilter:"required,field=hash2,doc=The second ssdeep hash."`
+}
+
+type SSDeepCompareFunction struct{}
+
+func (self *SSDeepCompareFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+	arg := &SSDeepCompareFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("ssdeep_compare: %v", err)
+		return vfilter.Null{}
+	}
+
+	score, err := SSDeepCompare(arg.Hash1, arg.Hash2)
+	if err != nil {
+		scope.Log("ssdeep_compare: %v", err)
+		return vfilter.Null{}
+	}
+
+	return score
+}
+
+func (self SSDeepCompareFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "ssdeep_compare",
+		Doc:     "Compare two ssdeep hashes and return a similarity score between 0 and 100.",
+		ArgType: type_map.AddType(scope, &SSDeepCompareFunctionArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&SSDeepFunction{})
+	vql_subsystem.RegisterFunction(&SSDeepCompareFunction{})
+}
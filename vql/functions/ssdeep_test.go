@@ -0,0 +1,91 @@
+package functions
+
+import (
+	"math/rand"
+	"testing"
+
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func ssdeepBytes(t *testing.T, data []byte, chunk_size int) string {
+	hasher := NewSSDeep()
+	for len(data) > 0 {
+		n := chunk_size
+		if n > len(data) {
+			n = len(data)
+		}
+		_, _ = hasher.Write(data[:n])
+		data = data[n:]
+	}
+
+	digest, err := hasher.Digest()
+	assert.NoError(t, err)
+	return digest
+}
+
+func TestSSDeep(t *testing.T) {
+	assert.Equal(t, "3::", ssdeepBytes(t, nil, 1))
+
+	// Pseudo random text so the hash is stable.
+	words := []string{"alpha ", "bravo ", "charlie ", "delta ",
+		"echo ", "foxtrot ", "golf ", "hotel "}
+	rng := rand.New(rand.NewSource(1))
+	data := []byte{}
+	for len(data) < 100000 {
+		data = append(data, words[rng.Intn(len(words))]...)
+	}
+
+	// The hash does not depend on how the data is written.
+	digest := ssdeepBytes(t, data, len(data))
+	assert.Equal(t, digest, ssdeepBytes(t, data, 4096))
+	assert.Equal(t, digest, ssdeepBytes(t, data, 1))
+
+	score, err := SSDeepCompare(digest, digest)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, score)
+
+	// A small modification still scores highly.
+	modified := append([]byte{}, data...)
+	copy(modified[50000:], "This is a small modification")
+	score, err = SSDeepCompare(digest, ssdeepBytes(t, modified, 4096))
+	assert.NoError(t, err)
+	assert.True(t, score > 80 && score < 100)
+
+	// Unrelated data does not match.
+	rng.Shuffle(len(data), func(i, j int) {
+		data[i], data[j] = data[j], data[i]
+	})
+	score, err = SSDeepCompare(digest, ssdeepBytes(t, data, 4096))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, score)
+
+	// Hashes of two sentences differing only in case.
+	score, err = SSDeepCompare(
+		"3:AXGBicFlgVNhBGcL6wCrFQEv:AXGHsNhxLsr2C",
+		"3:AXGBicFlIHBGcL6wCrFQEv:AXGH6xLsr2C")
+	assert.NoError(t, err)
+	assert.Equal(t, 22, score)
+
+	_, err = SSDeepCompare("3:abc", "3:abc:abc")
+	assert.Error(t, err)
+}
+
+func TestTLSHDiff(t *testing.T) {
+	hash := "910129b04509c911c72fa938c21dc15db6eac1cbaa18980b7f0121f1b98913275bbf19"
+
+	diff, err := TLSHDiff(hash, hash)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, diff)
+
+	// The version prefix is ignored.
+	diff, err = TLSHDiff(hash, "T1"+hash)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, diff)
+
+	parsed, err := ParseTLSH(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, parsed.String())
+
+	_, err = TLSHDiff(hash, "1234")
+	assert.Error(t, err)
+}
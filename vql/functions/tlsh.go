@@ -3,6 +3,9 @@ package functions
 import (
 	"bufio"
 	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
 
 	"github.com/Velocidex/ordereddict"
 	"github.com/glaslos/tlsh"
@@ -16,7 +19,7 @@ import (
 
 type TLSHashFunctionArgs struct {
 	Path     *accessors.OSPath `vfilter:"required,field=path,doc=Path to open and hash."`
-	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use"`
+	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use (use 'data' to hash a string)"`
 }
 
 type TLSHashFunction struct{}
@@ -24,34 +27,32 @@ type TLSHashFunction struct{}
 func (self *TLSHashFunction) Call(ctx context.Context,
 	scope vfilter.Scope,
 	args *ordereddict.Dict) vfilter.Any {
-	arg := &HashFunctionArgs{}
+	arg := &TLSHashFunctionArgs{}
 	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
 	if err != nil {
-		scope.Log("tlsh_hash: %v", err)
+		scope.Log("tlsh: %v", err)
 		return vfilter.Null{}
 	}
 
-	cached_buffer := pool.Get().(*[]byte)
-	defer pool.Put(cached_buffer)
-
 	err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
 	if err != nil {
-		scope.Log("tlsh_hash: %s", err)
+		scope.Log("tlsh: %s", err)
 		return vfilter.Null{}
 	}
 
 	fs, err := accessors.GetAccessor(arg.Accessor, scope)
 	if err != nil {
-		scope.Log("tlsh_hash: %v", err)
+		scope.Log("tlsh: %v", err)
 		return vfilter.Null{}
 	}
 
-	file, err := fs.Open(arg.Path.String())
+	file, err := fs.OpenWithOSPath(arg.Path)
 	if err != nil {
 		return vfilter.Null{}
 	}
 	defer file.Close()
 
+	// TLSH needs at least 50 bytes of input.
 	tlsh_hash, err := tlsh.HashReader(bufio.NewReader(file))
 	if err != nil {
 		return vfilter.Null{}
@@ -61,14 +62,105 @@ func (self *TLSHashFunction) Call(ctx context.Context,
 }
 
 func (self TLSHashFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "tlsh",
+		Doc:      "Calculate the tlsh fuzzy hash of a file.",
+		ArgType:  type_map.AddType(scope, &TLSHashFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+// Deprecated: Kept for backwards compatibility, use tlsh()
+type _TLSHashAliasFunction struct {
+	TLSHashFunction
+}
+
+func (self _TLSHashAliasFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
 	return &vfilter.FunctionInfo{
 		Name:     "tlsh_hash",
-		Doc:      "Calculate the tlsh hash of a file.",
+		Doc:      "Calculate the tlsh hash of a file (Deprecated: use tlsh()).",
 		ArgType:  type_map.AddType(scope, &TLSHashFunctionArgs{}),
 		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
 	}
 }
 
+func swapNibbles(in byte) byte {
+	return in<<4 | in>>4
+}
+
+// ParseTLSH parses a hex encoded TLSH hash. Newer versions of the
+// reference implementation prefix the hash with a version ("T1").
+func ParseTLSH(hash string) (*tlsh.Tlsh, error) {
+	if len(hash) == 72 && strings.HasPrefix(strings.ToUpper(hash), "T1") {
+		hash = hash[2:]
+	}
+
+	data, err := hex.DecodeString(hash)
+	if err != nil || len(data) != 35 {
+		return nil, fmt.Errorf("Invalid tlsh hash %q", hash)
+	}
+
+	q_ratio := data[2]
+	code := [32]byte{}
+	copy(code[:], data[3:])
+
+	return tlsh.New(swapNibbles(data[0]), swapNibbles(data[1]),
+		q_ratio>>4, q_ratio&0xf, q_ratio, code), nil
+}
+
+// TLSHDiff returns the distance between two TLSH hashes. A distance
+// of 0 means the hashes are identical, lower distances indicate more
+// similar files.
+func TLSHDiff(hash1, hash2 string) (int, error) {
+	h1, err := ParseTLSH(hash1)
+	if err != nil {
+		return 0, err
+	}
+
+	h2, err := ParseTLSH(hash2)
+	if err != nil {
+		return 0, err
+	}
+
+	return h1.Diff(h2), nil
+}
+
+type TLSHDiffFunctionArgs struct {
+	Hash1 string `vfilter:"required,field=hash1,doc=The first tlsh hash."`
+	Hash2 string `vfilter:"required,field=hash2,doc=The second tlsh hash."`
+}
+
+type TLSHDiffFunction struct{}
+
+func (self *TLSHDiffFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+	arg := &TLSHDiffFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("tlsh_diff: %v", err)
+		return vfilter.Null{}
+	}
+
+	diff, err := TLSHDiff(arg.Hash1, arg.Hash2)
+	if err != nil {
+		scope.Log("tlsh_diff: %v", err)
+		return vfilter.Null{}
+	}
+
+	return diff
+}
+
+func (self TLSHDiffFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "tlsh_diff",
+		Doc:     "Calculate the distance between two tlsh hashes (0 means identical).",
+		ArgType: type_map.AddType(scope, &TLSHDiffFunctionArgs{}),
+	}
+}
+
 func init() {
 	vql_subsystem.RegisterFunction(&TLSHashFunction{})
+	vql_subsystem.RegisterFunction(&_TLSHashAliasFunction{})
+	vql_subsystem.RegisterFunction(&TLSHDiffFunction{})
 }
@@ -0,0 +1,135 @@
+package golang
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type MetricsPluginArgs struct {
+	Name string `vfilter:"optional,field=name,doc=A regex to select metrics by name (default all metrics)."`
+}
+
+// Report the process's prometheus metrics as rows. Unlike the metrics
+// produced by profile(), each labeled series is a separate row and
+// histograms are summarized by their mean so they can easily be
+// compared against thresholds.
+type MetricsPlugin struct{}
+
+func (self MetricsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("metrics: %v", err)
+			return
+		}
+
+		arg := &MetricsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("metrics: %v", err)
+			return
+		}
+
+		var name_regex *regexp.Regexp
+		if arg.Name != "" {
+			name_regex, err = regexp.Compile(arg.Name)
+			if err != nil {
+				scope.Log("metrics: %v", err)
+				return
+			}
+		}
+
+		gathering, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			scope.Log("metrics: %v", err)
+			return
+		}
+
+		for _, family := range gathering {
+			if name_regex != nil && !name_regex.MatchString(family.GetName()) {
+				continue
+			}
+
+			for _, m := range family.Metric {
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- metricToRow(family, m):
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func metricToRow(family *dto.MetricFamily, m *dto.Metric) *ordereddict.Dict {
+	labels := ordereddict.NewDict()
+	for _, l := range m.Label {
+		labels.Set(l.GetName(), l.GetValue())
+	}
+
+	var value float64
+	var count uint64
+
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		value = m.GetCounter().GetValue()
+
+	case dto.MetricType_GAUGE:
+		value = m.GetGauge().GetValue()
+
+	case dto.MetricType_UNTYPED:
+		value = m.GetUntyped().GetValue()
+
+	case dto.MetricType_HISTOGRAM:
+		count = m.GetHistogram().GetSampleCount()
+		if count > 0 {
+			value = m.GetHistogram().GetSampleSum() / float64(count)
+		}
+
+	case dto.MetricType_SUMMARY:
+		count = m.GetSummary().GetSampleCount()
+		if count > 0 {
+			value = m.GetSummary().GetSampleSum() / float64(count)
+		}
+	}
+
+	return ordereddict.NewDict().
+		Set("Name", family.GetName()).
+		Set("Type", family.GetType().String()).
+		Set("Help", family.GetHelp()).
+		Set("Labels", labels).
+		Set("Value", value).
+		Set("Count", count)
+}
+
+func (self MetricsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "metrics",
+		Doc:      "Report the current prometheus metrics of the running process.",
+		ArgType:  type_map.AddType(scope, &MetricsPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&MetricsPlugin{})
+}
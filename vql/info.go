@@ -21,6 +21,7 @@ import (
 	"context"
 	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	fqdn "github.com/Showmax/go-fqdn"
@@ -59,6 +60,32 @@ func GetInfo(host *psutils.InfoStat) *ordereddict.Dict {
 		Set("ClientStart", start_time)
 }
 
+// The name of the local time zone. Prefer an IANA name since the
+// server can use it to interpret local timestamps.
+func localTimezone(now time.Time) string {
+	tz := strings.TrimPrefix(os.Getenv("TZ"), ":")
+	if tz != "" {
+		return tz
+	}
+
+	if time.Local.String() != "Local" {
+		return time.Local.String()
+	}
+
+	// On Linux and macOS /etc/localtime links into the zoneinfo
+	// database.
+	target, err := os.Readlink("/etc/localtime")
+	if err == nil {
+		idx := strings.Index(target, "zoneinfo/")
+		if idx >= 0 {
+			return target[idx+len("zoneinfo/"):]
+		}
+	}
+
+	name, _ := now.Zone()
+	return name
+}
+
 func init() {
 	RegisterPlugin(
 		vfilter.GenericListPlugin{
@@ -96,9 +123,14 @@ func init() {
 					CacheSet(scope, "__info", info)
 				}
 
+				now := time.Now()
+				_, offset := now.Zone()
 				item := GetInfo(info).
 					Set("Fqdn", fqdn.Get()).
-					Set("Architecture", runtime.GOARCH)
+					Set("Architecture", runtime.GOARCH).
+					Set("Timezone", localTimezone(now)).
+					Set("TimezoneOffset", offset).
+					Set("ClientTime", now)
 				result = append(result, item)
 
 				return result
@@ -0,0 +1,458 @@
+package langserver
+
+import (
+	"strings"
+
+	"github.com/alecthomas/participle/lexer"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// Stored queries may refer to each other so column resolution
+	// gives up after this many levels.
+	MAX_RESOLVE_DEPTH = 5
+)
+
+// A stored query or function defined with LET.
+type letDefinition struct {
+	Name    string
+	Params  []string
+	Columns []string
+
+	// Set when all the columns are known.
+	Complete bool
+
+	// Defined with <= so the query is only evaluated once.
+	Materialized bool
+
+	Body []*token
+}
+
+type span struct {
+	start, end int
+}
+
+// An open bracket enclosing a position in the query. Calls record
+// the name of the plugin or function called.
+type frame struct {
+	open   string
+	index  int
+	name   string
+	plugin bool
+}
+
+// The static analysis of a query. Only the tokens are considered so
+// this works on incomplete queries while the user is typing.
+type analysis struct {
+	query string
+
+	// All tokens including comments.
+	raw []*token
+
+	// Tokens without comments.
+	tokens []*token
+
+	// Offset of the first invalid token or -1.
+	error_offset int
+
+	statements []span
+	lets       map[string]*letDefinition
+	let_names  []string
+
+	// Names defined outside the query, e.g. artifact parameters.
+	variables []string
+
+	// Built in plugins. A call to a plugin takes precedence over a
+	// stored query of the same name.
+	plugins map[string]*api_proto.Completion
+}
+
+func analyze(query string,
+	plugins map[string]*api_proto.Completion) *analysis {
+	self := &analysis{
+		query:        query,
+		error_offset: -1,
+		lets:         make(map[string]*letDefinition),
+		plugins:      plugins,
+	}
+
+	raw, err := tokenize(query)
+	if err != nil {
+		self.error_offset = len(query)
+		lex_err, ok := err.(*lexer.Error)
+		if ok {
+			self.error_offset = lex_err.Tok.Pos.Offset
+		}
+	}
+	self.raw = raw
+	self.tokens = significant(raw)
+	self.statements = splitStatements(self.tokens)
+
+	for _, s := range self.statements {
+		self.parseLet(self.tokens[s.start:s.end])
+	}
+
+	return self
+}
+
+// Make the stored queries defined elsewhere available to this
+// query. The query's own definitions take precedence.
+func (self *analysis) define(other *analysis) {
+	for _, name := range other.let_names {
+		_, pres := self.lets[name]
+		if !pres {
+			self.lets[name] = other.lets[name]
+			self.let_names = append(self.let_names, name)
+		}
+	}
+}
+
+// Statements start with LET, EXPLAIN or a SELECT which is not part
+// of a LET or EXPLAIN.
+func splitStatements(tokens []*token) []span {
+	result := []span{}
+	depth := 0
+	start := 0
+
+	for i, t := range tokens {
+		switch {
+		case t.is("(") || t.is("{") || t.is("["):
+			depth++
+			continue
+
+		case t.is(")") || t.is("}") || t.is("]"):
+			if depth > 0 {
+				depth--
+			}
+			continue
+		}
+
+		if depth > 0 || i == start {
+			continue
+		}
+
+		starts := false
+		switch t.Type {
+		case "LET", "EXPLAIN":
+			starts = true
+		case "SELECT":
+			prev := tokens[i-1]
+			starts = !prev.is("=") && !prev.is("<=") && prev.Type != "EXPLAIN"
+		}
+
+		if starts {
+			result = append(result, span{start, i})
+			start = i
+		}
+	}
+
+	if start < len(tokens) {
+		result = append(result, span{start, len(tokens)})
+	}
+	return result
+}
+
+// Record LET name(params) = SELECT ...
+func (self *analysis) parseLet(tokens []*token) {
+	if len(tokens) < 2 || tokens[0].Type != "LET" ||
+		tokens[1].Type != "Ident" {
+		return
+	}
+
+	definition := &letDefinition{Name: tokens[1].name()}
+	i := 2
+	if i < len(tokens) && tokens[i].is("(") {
+		for i++; i < len(tokens) && !tokens[i].is(")"); i++ {
+			if tokens[i].Type == "Ident" {
+				definition.Params = append(definition.Params, tokens[i].name())
+			}
+		}
+		i++
+	}
+
+	if i < len(tokens) && (tokens[i].is("=") || tokens[i].is("<=")) {
+		definition.Materialized = tokens[i].is("<=")
+		definition.Body = tokens[i+1:]
+		definition.Columns, definition.Complete = self.selectColumns(
+			tokens[i+1:], 0)
+	}
+
+	_, pres := self.lets[definition.Name]
+	if !pres {
+		self.let_names = append(self.let_names, definition.Name)
+	}
+	self.lets[definition.Name] = definition
+}
+
+// Find the index of the top level token of the given type, or -1.
+func findTopLevel(tokens []*token, token_type string) int {
+	depth := 0
+	for i, t := range tokens {
+		switch {
+		case t.is("(") || t.is("{") || t.is("["):
+			depth++
+		case t.is(")") || t.is("}") || t.is("]"):
+			depth--
+		case depth == 0 && t.Type == token_type:
+			return i
+		}
+	}
+	return -1
+}
+
+// Find the index of the bracket closing the one at index open, or
+// the end of the tokens if it is not closed yet.
+func findClose(tokens []*token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.is("(") || t.is("{") || t.is("["):
+			depth++
+		case t.is(")") || t.is("}") || t.is("]"):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens)
+}
+
+// The columns produced by a SELECT statement. Columns are named by
+// their alias or when they are a plain identifier. A * expands to
+// the columns of the query selected from when they are known. The
+// columns are complete unless a * could not be expanded.
+func (self *analysis) selectColumns(
+	tokens []*token, level int) ([]string, bool) {
+	if len(tokens) == 0 || tokens[0].Type != "SELECT" ||
+		level > MAX_RESOLVE_DEPTH {
+		return nil, false
+	}
+
+	end := findTopLevel(tokens, "FROM")
+	if end < 0 {
+		end = len(tokens)
+	}
+
+	result := []string{}
+	complete := true
+	add := func(names ...string) {
+		for _, name := range names {
+			if !utils.InString(result, name) {
+				result = append(result, name)
+			}
+		}
+	}
+
+	for _, item := range splitTopLevel(tokens[1:end], ",") {
+		n := len(item)
+		switch {
+		case n >= 3 && item[n-2].Type == "AS" && item[n-1].Type == "Ident":
+			add(item[n-1].name())
+
+		case n == 1 && item[0].Type == "Ident":
+			add(item[0].name())
+
+		case n == 1 && item[0].is("*"):
+			if end >= len(tokens) {
+				complete = false
+				continue
+			}
+			columns, known := self.sourceColumns(tokens[end+1:], level+1)
+			add(columns...)
+			complete = complete && known
+		}
+	}
+
+	return result, complete
+}
+
+// The columns of the source of a FROM clause and if they are known.
+func (self *analysis) sourceColumns(
+	tokens []*token, level int) ([]string, bool) {
+	if len(tokens) == 0 || level > MAX_RESOLVE_DEPTH {
+		return nil, false
+	}
+
+	if tokens[0].is("{") {
+		return self.selectColumns(
+			tokens[1:findClose(tokens, 0)], level)
+	}
+
+	name, end := chainAfter(tokens, 0)
+	if self.isPluginCall(tokens, name, end) {
+		return nil, false
+	}
+
+	definition, pres := self.lets[name]
+	if pres {
+		return definition.Columns, definition.Complete
+	}
+	return nil, false
+}
+
+// Is the name ending at index end called as a built in plugin?
+func (self *analysis) isPluginCall(
+	tokens []*token, name string, end int) bool {
+	_, pres := self.plugins[name]
+	return pres && end < len(tokens) && tokens[end].is("(")
+}
+
+func splitTopLevel(tokens []*token, separator string) [][]*token {
+	result := [][]*token{}
+	depth := 0
+	start := 0
+	for i, t := range tokens {
+		switch {
+		case t.is("(") || t.is("{") || t.is("["):
+			depth++
+		case t.is(")") || t.is("}") || t.is("]"):
+			depth--
+		case depth == 0 && t.is(separator):
+			result = append(result, tokens[start:i])
+			start = i + 1
+		}
+	}
+	return append(result, tokens[start:])
+}
+
+// The dotted name ending just before index i, e.g. Artifact.Generic.Client.Info(
+func chainBefore(tokens []*token, i int) (string, int) {
+	start := i
+	for j := i - 1; j >= 0; j-- {
+		t := tokens[j]
+		if t.Type == "Ident" && (j == i-1 || tokens[j+1].is(".")) {
+			start = j
+			continue
+		}
+		if t.is(".") && j < i-1 && tokens[j+1].Type == "Ident" {
+			continue
+		}
+		break
+	}
+
+	return joinChain(tokens[start:i]), start
+}
+
+// The dotted name starting at index i and the index following it.
+func chainAfter(tokens []*token, i int) (string, int) {
+	end := i
+	for j := i; j < len(tokens); j++ {
+		t := tokens[j]
+		if t.Type == "Ident" && (j == i || tokens[j-1].is(".")) {
+			end = j + 1
+			continue
+		}
+		if t.is(".") && j > i && tokens[j-1].Type == "Ident" {
+			continue
+		}
+		break
+	}
+
+	return joinChain(tokens[i:end]), end
+}
+
+func joinChain(tokens []*token) string {
+	names := []string{}
+	for _, t := range tokens {
+		if t.Type == "Ident" {
+			names = append(names, t.name())
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+// The brackets open at the end of the tokens.
+func openFrames(tokens []*token) []*frame {
+	stack := []*frame{}
+	for i, t := range tokens {
+		switch {
+		case t.is("("):
+			name, start := chainBefore(tokens, i)
+			stack = append(stack, &frame{
+				open:   "(",
+				index:  i,
+				name:   name,
+				plugin: start > 0 && tokens[start-1].Type == "FROM",
+			})
+
+		case t.is("{") || t.is("["):
+			stack = append(stack, &frame{open: t.Value, index: i})
+
+		case t.is(")") || t.is("}") || t.is("]"):
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return stack
+}
+
+// The statement containing the token at index i. Positions past the
+// end belong to the last statement.
+func (self *analysis) statementAt(i int) []*token {
+	var result []*token
+	for _, s := range self.statements {
+		if s.start <= i {
+			result = self.tokens[s.start:s.end]
+		}
+	}
+	return result
+}
+
+// The tokens of the query enclosing index i: either the innermost
+// subquery or the statement.
+func (self *analysis) enclosingQuery(stack []*frame, i int) []*token {
+	for j := len(stack) - 1; j >= 0; j-- {
+		if stack[j].open == "{" {
+			return self.tokens[stack[j].index+1 : findClose(self.tokens, stack[j].index)]
+		}
+	}
+	return self.statementAt(i)
+}
+
+// The columns available at the given position: those of the query
+// selected from and the aliases defined by the query itself.
+func (self *analysis) columnsAt(stack []*frame, i, offset int) []string {
+	result := []string{}
+	add := func(names ...string) {
+		for _, name := range names {
+			if !utils.InString(result, name) {
+				result = append(result, name)
+			}
+		}
+	}
+
+	// Parameters of a LET function are in scope in its body.
+	statement := self.statementAt(i)
+	if len(statement) > 1 && statement[0].Type == "LET" {
+		definition, pres := self.lets[statement[1].name()]
+		if pres {
+			add(definition.Params...)
+		}
+	}
+
+	query := self.enclosingQuery(stack, i)
+	start := findTopLevel(query, "SELECT")
+	if start < 0 {
+		return result
+	}
+	query = query[start:]
+
+	from := findTopLevel(query, "FROM")
+	if from < 0 {
+		return result
+	}
+	columns, _ := self.sourceColumns(query[from+1:], 0)
+	add(columns...)
+
+	// Aliases may be used in the WHERE and ORDER BY clauses.
+	if query[from].Offset < offset {
+		columns, _ := self.selectColumns(query, 0)
+		add(columns...)
+	}
+
+	return result
+}
@@ -0,0 +1,106 @@
+package langserver
+
+import (
+	"fmt"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/acls"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// A diagnostic for one of the VQL fields of an artifact. Positions
+// are relative to the start of the field.
+type ArtifactDiagnostic struct {
+	Artifact string `json:"artifact"`
+
+	// The field holding the VQL, e.g. sources[0].query
+	Field string `json:"field"`
+
+	*Diagnostic
+}
+
+// Statically check all the VQL in an artifact. The artifact's
+// parameters, exports and the VQL it imports are available to all
+// its queries.
+func (self *LanguageServer) LintArtifact(
+	artifact *artifacts_proto.Artifact, imported string) []*ArtifactDiagnostic {
+	result := []*ArtifactDiagnostic{}
+
+	options := LintOptions{}
+	for _, parameter := range artifact.Parameters {
+		options.Variables = append(options.Variables, parameter.Name)
+	}
+
+	lint := func(field, query string, options LintOptions) {
+		for _, d := range self.Lint(query, options) {
+			result = append(result, &ArtifactDiagnostic{
+				Artifact:   artifact.Name,
+				Field:      field,
+				Diagnostic: d,
+			})
+		}
+	}
+
+	// Preconditions only decide if the artifact runs so their
+	// commands need not be logged.
+	lint_precondition := func(field, query string) {
+		for _, d := range self.Lint(query, LintOptions{}) {
+			if d.Rule != RULE_EXECVE_LOGGING {
+				result = append(result, &ArtifactDiagnostic{
+					Artifact:   artifact.Name,
+					Field:      field,
+					Diagnostic: d,
+				})
+			}
+		}
+	}
+
+	options.Definitions = imported
+	lint("export", artifact.Export, options)
+	lint_precondition("precondition", artifact.Precondition)
+
+	// Sources of collections run one after the other in the same
+	// scope unless they have their own preconditions, so stored
+	// queries from earlier sources are available to later ones.
+	serial := !strings.HasSuffix(strings.ToLower(artifact.Type), "_event")
+	for _, source := range artifact.Sources {
+		if source.Precondition != "" {
+			serial = false
+		}
+	}
+
+	definitions := []string{imported, artifact.Export}
+	uses_execve := callsName(analyze(artifact.Export, nil).tokens, "execve")
+	for i, source := range artifact.Sources {
+		options.Definitions = strings.Join(definitions, "\n")
+
+		lint_precondition(fmt.Sprintf("sources[%d].precondition", i),
+			source.Precondition)
+		lint(fmt.Sprintf("sources[%d].query", i), source.Query, options)
+
+		if serial {
+			definitions = append(definitions, source.Query)
+		}
+
+		if callsName(analyze(source.Query, nil).tokens, "execve") {
+			uses_execve = true
+		}
+	}
+
+	if uses_execve && !utils.InString(
+		artifact.RequiredPermissions, acls.EXECVE.String()) {
+		result = append(result, &ArtifactDiagnostic{
+			Artifact: artifact.Name,
+			Field:    "required_permissions",
+			Diagnostic: &Diagnostic{
+				Severity: SEVERITY_WARNING,
+				Rule:     RULE_EXECVE_PERMISSION,
+				Message: "The artifact calls execve() but does not " +
+					"require the EXECVE permission",
+			},
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,318 @@
+package langserver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/participle/lexer"
+	errors "github.com/pkg/errors"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	SEVERITY_ERROR   = "error"
+	SEVERITY_WARNING = "warning"
+
+	RULE_SYNTAX           = "syntax"
+	RULE_UNKNOWN_FUNCTION = "unknown-function"
+	RULE_UNKNOWN_PLUGIN   = "unknown-plugin"
+	RULE_UNKNOWN_ARGUMENT = "unknown-argument"
+	RULE_MISSING_ARGUMENT = "missing-argument"
+	RULE_ARGUMENT_TYPE    = "argument-type"
+)
+
+type Diagnostic struct {
+	Severity  string `json:"severity"`
+	Rule      string `json:"rule"`
+	Message   string `json:"message"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+}
+
+// Errors from the vfilter parser carry the offending token.
+type positionedError interface {
+	Message() string
+	Token() lexer.Token
+}
+
+// Check the query for syntax errors and common mistakes.
+func (self *LanguageServer) Diagnose(query string) []*Diagnostic {
+	return self.Lint(query, LintOptions{})
+}
+
+func parseDiagnostic(query string, err error) *Diagnostic {
+	result := &Diagnostic{
+		Severity: SEVERITY_ERROR,
+		Rule:     RULE_SYNTAX,
+		Message:  err.Error(),
+	}
+
+	perr, ok := errors.Cause(err).(positionedError)
+	if !ok {
+		return result
+	}
+
+	result.Message = perr.Message()
+
+	tok := perr.Token()
+	start := tok.Pos.Offset
+	end := start
+	if !tok.EOF() {
+		end += len(tok.Value)
+	}
+
+	result.Line, result.Column = toPosition(query, start)
+	result.EndLine, result.EndColumn = toPosition(query, end)
+	return result
+}
+
+// Collects diagnostics for a query.
+type reporter struct {
+	query  string
+	result []*Diagnostic
+}
+
+func (self *reporter) warn(t *token, end int, rule string,
+	format string, args ...interface{}) {
+	d := &Diagnostic{
+		Severity: SEVERITY_WARNING,
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	d.Line, d.Column = toPosition(self.query, t.Offset)
+	d.EndLine, d.EndColumn = toPosition(self.query, end)
+	self.result = append(self.result, d)
+}
+
+type callFrame struct {
+	*frame
+
+	// Set when the called name is known. Stored queries are known
+	// but have no descriptions.
+	desc  *callable
+	args  []string
+	start *token
+
+	// Set when the arguments can not be followed.
+	opaque bool
+}
+
+type callable struct {
+	name     string
+	args     []string
+	required []string
+	types    map[string]*api_proto.ArgDescriptor
+}
+
+// Check calls to unknown plugins, functions and arguments, and
+// literal arguments of the wrong type.
+func (self *LanguageServer) checkCalls(a *analysis, r *reporter) {
+	tokens := a.tokens
+	stack := []*callFrame{}
+
+	for i, t := range tokens {
+		switch {
+		case t.is("("):
+			name, start := chainBefore(tokens, i)
+			f := &callFrame{frame: &frame{
+				open:   "(",
+				index:  i,
+				name:   name,
+				plugin: start > 0 && tokens[start-1].Type == "FROM",
+			}}
+			if name != "" {
+				f.start = tokens[start]
+				known := false
+				f.desc, known = self.resolve(a, name, f.plugin, true)
+				if !known {
+					r.warn(f.start, tokens[i-1].End, unknownRule(f.plugin),
+						"Unknown %v %v", callKind(name, f.plugin), name)
+				}
+			}
+			stack = append(stack, f)
+
+		case t.is("{") || t.is("["):
+			stack = append(stack, &callFrame{frame: &frame{open: t.Value}})
+
+		case t.is(")") || t.is("}") || t.is("]"):
+			if len(stack) == 0 {
+				continue
+			}
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if f.desc == nil || f.opaque {
+				continue
+			}
+
+			for _, required := range f.desc.required {
+				if !utils.InString(f.args, required) {
+					r.warn(f.start, t.End, RULE_MISSING_ARGUMENT,
+						"Missing required argument %v for %v",
+						required, f.desc.name)
+				}
+			}
+
+		// Plugins and stored queries may be used without
+		// brackets. Nested queries may also select from the
+		// columns of the enclosing query's rows.
+		case t.Type == "FROM" && i+1 < len(tokens) && tokens[i+1].Type == "Ident":
+			name, end := chainAfter(tokens, i+1)
+			if (end < len(tokens) && tokens[end].is("(")) ||
+				len(stack) > 0 || utils.InString(a.variables, name) {
+				continue
+			}
+
+			_, known := self.resolve(a, name, true, false)
+			if !known {
+				r.warn(tokens[i+1], tokens[end-1].End, unknownRule(true),
+					"Unknown %v %v", callKind(name, true), name)
+			}
+		}
+
+		// Arguments follow the call's bracket or a comma.
+		if len(stack) == 0 || i+1 >= len(tokens) {
+			continue
+		}
+		f := stack[len(stack)-1]
+		if f.open != "(" || f.name == "" || !(t.is("(") || t.is(",")) {
+			continue
+		}
+
+		arg := tokens[i+1]
+		if arg.is(")") {
+			continue
+		}
+
+		if arg.Type != "Ident" || i+2 >= len(tokens) || !tokens[i+2].is("=") {
+			f.opaque = true
+			continue
+		}
+
+		f.args = append(f.args, arg.name())
+		if f.desc == nil || len(f.desc.args) == 0 ||
+			utils.InString(freeFormArgs, f.desc.name) {
+			continue
+		}
+
+		if !utils.InString(f.desc.args, arg.name()) {
+			r.warn(arg, arg.End, RULE_UNKNOWN_ARGUMENT,
+				"Unknown argument %v for %v", arg.name(), f.desc.name)
+			continue
+		}
+
+		desc, pres := f.desc.types[arg.name()]
+		if pres && i+3 < len(tokens) {
+			checkArgType(tokens, i+3, desc, f.desc.name, r)
+		}
+	}
+}
+
+// Check a literal argument value against the type the argument
+// expects. Expressions are not checked since their type is only
+// known at runtime.
+func checkArgType(tokens []*token, i int,
+	desc *api_proto.ArgDescriptor, name string, r *reporter) {
+	value := tokens[i]
+	end := i + 1
+	if value.is("{") {
+		end = findClose(tokens, i) + 1
+	}
+
+	// The value must be the whole expression.
+	if end < len(tokens) && !tokens[end].is(",") && !tokens[end].is(")") {
+		return
+	}
+
+	kind := ""
+	switch value.Type {
+	case "String", "MultilineString":
+		kind = "a string"
+	case "Number":
+		kind = "a number"
+	case "BOOL":
+		kind = "a bool"
+	case "Operators":
+		if value.is("{") {
+			kind = "a query"
+		}
+	}
+	if kind == "" {
+		return
+	}
+
+	expected := ""
+	switch strings.TrimSpace(desc.Type) {
+	case "int", "int64", "uint64", "float64":
+		if kind == "a string" || kind == "a query" {
+			expected = "a number"
+		}
+
+	case "string":
+		if kind == "a query" && !desc.Repeated {
+			expected = "a string"
+		}
+	}
+
+	if expected != "" {
+		r.warn(value, tokens[end-1].End, RULE_ARGUMENT_TYPE,
+			"Argument %v of %v should be %v not %v",
+			desc.Name, name, expected, kind)
+	}
+}
+
+// Resolve a called name. Returns whether the name is known and its
+// description if it has one. Stored queries mask built in functions
+// but plugins called with brackets take precedence over stored
+// queries.
+func (self *LanguageServer) resolve(
+	a *analysis, name string, plugin, called bool) (*callable, bool) {
+	definition, pres := a.lets[name]
+	_, is_plugin := self.plugins[name]
+	if pres && !(plugin && called && is_plugin) {
+		return &callable{name: name, args: definition.Params}, true
+	}
+
+	// Other dotted names are member accesses we can not follow.
+	if strings.Contains(name, ".") && !strings.HasPrefix(name, "Artifact.") {
+		return nil, true
+	}
+
+	desc := self.describe(name, plugin)
+	if desc == nil {
+		return nil, false
+	}
+
+	result := &callable{
+		name:  name,
+		types: make(map[string]*api_proto.ArgDescriptor),
+	}
+	for _, arg := range desc.Args {
+		result.args = append(result.args, arg.Name)
+		result.types[arg.Name] = arg
+		if arg.Required {
+			result.required = append(result.required, arg.Name)
+		}
+	}
+	return result, true
+}
+
+func callKind(name string, plugin bool) string {
+	if strings.HasPrefix(name, "Artifact.") {
+		return KIND_ARTIFACT
+	}
+	if plugin {
+		return KIND_PLUGIN
+	}
+	return KIND_FUNCTION
+}
+
+func unknownRule(plugin bool) string {
+	if plugin {
+		return RULE_UNKNOWN_PLUGIN
+	}
+	return RULE_UNKNOWN_FUNCTION
+}
@@ -0,0 +1,461 @@
+/*
+  An editor intelligence service for VQL.
+
+  The language server offers completions, documentation and
+  diagnostics for a VQL query at a position in the editor. It is
+  used by the GUI's query editors and exposed over the API so
+  external editors can offer the same features.
+
+  Positions are given as zero based lines and columns like the
+  Language Server Protocol.
+*/
+
+package langserver
+
+import (
+	"sort"
+	"strings"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+)
+
+const (
+	KIND_KEYWORD  = "keyword"
+	KIND_PLUGIN   = "plugin"
+	KIND_FUNCTION = "function"
+	KIND_ARTIFACT = "artifact"
+	KIND_ARGUMENT = "argument"
+	KIND_COLUMN   = "column"
+	KIND_VARIABLE = "variable"
+)
+
+var (
+	keywords = []string{
+		"SELECT", "FROM", "WHERE", "LET", "EXPLAIN", "LIMIT",
+		"GROUP BY", "ORDER BY", "AS", "AND", "OR", "NOT", "IN", "DESC",
+	}
+
+	// The artifact plugin accepts these in addition to the
+	// artifact's parameters.
+	artifactArgs = []*api_proto.ArgDescriptor{{
+		Name:        "source",
+		Type:        "string",
+		Description: "The source to read when the artifact has several sources.",
+	}, {
+		Name:        "preconditions",
+		Type:        "bool",
+		Description: "If set, evaluate the artifact's preconditions.",
+	}}
+
+	// These accept arbitrary arguments in addition to the
+	// described ones.
+	freeFormArgs = []string{"chain", "alert"}
+)
+
+type Completion struct {
+	Label         string `json:"label"`
+	Kind          string `json:"kind"`
+	Detail        string `json:"detail,omitempty"`
+	Documentation string `json:"documentation,omitempty"`
+	InsertText    string `json:"insert_text,omitempty"`
+}
+
+type Hover struct {
+	Name          string                     `json:"name"`
+	Kind          string                     `json:"kind"`
+	Documentation string                     `json:"documentation,omitempty"`
+	Args          []*api_proto.ArgDescriptor `json:"args,omitempty"`
+	Columns       []string                   `json:"columns,omitempty"`
+}
+
+type LanguageServer struct {
+	plugins   map[string]*api_proto.Completion
+	functions map[string]*api_proto.Completion
+	artifacts map[string]*api_proto.Completion
+
+	// Sorted names for stable completion lists.
+	plugin_names   []string
+	function_names []string
+	artifact_names []string
+}
+
+// Build a language server from the plugin, function and artifact
+// descriptions as returned by GetKeywordCompletions. Artifacts are
+// named Artifact.<name>.
+func NewLanguageServer(items []*api_proto.Completion) *LanguageServer {
+	self := &LanguageServer{
+		plugins:   make(map[string]*api_proto.Completion),
+		functions: make(map[string]*api_proto.Completion),
+		artifacts: make(map[string]*api_proto.Completion),
+	}
+
+	for _, item := range items {
+		var lookup map[string]*api_proto.Completion
+		switch item.Type {
+		case "Plugin":
+			lookup = self.plugins
+		case "Function":
+			lookup = self.functions
+		case "Artifact":
+			lookup = self.artifacts
+			item = &api_proto.Completion{
+				Name:        item.Name,
+				Description: item.Description,
+				Type:        item.Type,
+				Args:        append(append([]*api_proto.ArgDescriptor{}, item.Args...), artifactArgs...),
+			}
+		default:
+			continue
+		}
+
+		// The first description wins.
+		_, pres := lookup[item.Name]
+		if !pres {
+			lookup[item.Name] = item
+		}
+	}
+
+	self.plugin_names = sortedKeys(self.plugins)
+	self.function_names = sortedKeys(self.functions)
+	self.artifact_names = sortedKeys(self.artifacts)
+
+	return self
+}
+
+// Completions for the partial word before the position.
+func (self *LanguageServer) Complete(
+	query string, line, column int) []*Completion {
+	offset := toOffset(query, line, column)
+	a := analyze(query, self.plugins)
+
+	// Nothing to complete inside strings or comments.
+	if a.error_offset >= 0 && a.error_offset < offset {
+		return nil
+	}
+	for _, t := range a.raw {
+		if t.Offset < offset && offset < t.End &&
+			(t.isComment() || t.Type == "String" || t.Type == "MultilineString") {
+			return nil
+		}
+		if t.isComment() && t.End == offset && t.Type != "MLineComment" {
+			return nil
+		}
+	}
+
+	// Tokens entirely before the position.
+	n := 0
+	for n < len(a.tokens) && a.tokens[n].End <= offset {
+		n++
+	}
+
+	// The word being typed, possibly a dotted artifact name.
+	start := n
+	for i := n - 1; i >= 0; i-- {
+		t := a.tokens[i]
+		if i == n-1 && t.End != offset {
+			break
+		}
+		if i < n-1 && t.End != a.tokens[i+1].Offset {
+			break
+		}
+		if t.Type == "Ident" || t.is(".") || (t.isKeyword() && i == n-1) {
+			start = i
+			continue
+		}
+		break
+	}
+
+	prefix := ""
+	if start < n {
+		prefix = query[a.tokens[start].Offset:offset]
+	}
+
+	before := a.tokens[:start]
+	stack := openFrames(before)
+
+	var prev *token
+	if len(before) > 0 {
+		prev = before[len(before)-1]
+	}
+
+	var top *frame
+	if len(stack) > 0 {
+		top = stack[len(stack)-1]
+	}
+
+	result := []*Completion{}
+	switch {
+	case prev != nil && prev.Type == "FROM":
+		result = append(result, self.pluginCompletions()...)
+		result = append(result, a.variableCompletions()...)
+		result = append(result, self.artifactCompletions()...)
+
+	case top != nil && top.open == "(" && top.name != "" &&
+		(prev.is("(") || prev.is(",")):
+		result = append(result, self.argCompletions(a, top)...)
+
+	// Naming a new variable or column.
+	case prev != nil && (prev.Type == "LET" || prev.Type == "AS"):
+
+	default:
+		for _, name := range a.columnsAt(stack, start, offset) {
+			result = append(result, &Completion{
+				Label: name,
+				Kind:  KIND_COLUMN,
+			})
+		}
+		result = append(result, a.variableCompletions()...)
+		result = append(result, self.functionCompletions()...)
+
+		if top == nil || top.open == "{" {
+			for _, keyword := range keywords {
+				result = append(result, &Completion{
+					Label:      keyword,
+					Kind:       KIND_KEYWORD,
+					InsertText: keyword + " ",
+				})
+			}
+		}
+	}
+
+	return filterCompletions(result, prefix)
+}
+
+// Documentation for the name under the position.
+func (self *LanguageServer) Hover(query string, line, column int) *Hover {
+	offset := toOffset(query, line, column)
+	a := analyze(query, self.plugins)
+
+	idx := -1
+	for i, t := range a.tokens {
+		if t.Type == "Ident" && t.Offset <= offset && offset <= t.End {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil
+	}
+
+	// Extend to the whole dotted name.
+	for idx >= 2 && a.tokens[idx-1].is(".") && a.tokens[idx-2].Type == "Ident" {
+		idx -= 2
+	}
+	name, end := chainAfter(a.tokens, idx)
+
+	var prev, next *token
+	if idx > 0 {
+		prev = a.tokens[idx-1]
+	}
+	if end < len(a.tokens) {
+		next = a.tokens[end]
+	}
+
+	// An argument name in a call.
+	if next.is("=") && (prev.is("(") || prev.is(",")) {
+		stack := openFrames(a.tokens[:idx])
+		if len(stack) > 0 && stack[len(stack)-1].open == "(" {
+			top := stack[len(stack)-1]
+			desc := self.describe(top.name, top.plugin)
+			if desc != nil {
+				for _, arg := range desc.Args {
+					if arg.Name == name {
+						return &Hover{
+							Name:          name,
+							Kind:          KIND_ARGUMENT,
+							Documentation: arg.Description,
+							Args:          []*api_proto.ArgDescriptor{arg},
+						}
+					}
+				}
+			}
+			return nil
+		}
+	}
+
+	definition, pres := a.lets[name]
+	if pres {
+		return &Hover{
+			Name:    name,
+			Kind:    KIND_VARIABLE,
+			Columns: definition.Columns,
+			Args:    paramDescriptors(definition.Params),
+		}
+	}
+
+	if next.is("(") || (prev != nil && prev.Type == "FROM") {
+		plugin := prev != nil && prev.Type == "FROM"
+		desc := self.describe(name, plugin)
+		if desc == nil {
+			return nil
+		}
+
+		return &Hover{
+			Name:          name,
+			Kind:          kindOf(desc),
+			Documentation: desc.Description,
+			Args:          desc.Args,
+		}
+	}
+
+	return nil
+}
+
+// Find the description of a called plugin, function or artifact.
+func (self *LanguageServer) describe(
+	name string, plugin bool) *api_proto.Completion {
+	if strings.HasPrefix(name, "Artifact.") {
+		return self.artifacts[name]
+	}
+
+	if plugin {
+		return self.plugins[name]
+	}
+	return self.functions[name]
+}
+
+func (self *LanguageServer) argCompletions(
+	a *analysis, top *frame) []*Completion {
+	result := []*Completion{}
+
+	definition, pres := a.lets[top.name]
+	if pres {
+		for _, param := range definition.Params {
+			result = append(result, &Completion{
+				Label:      param,
+				Kind:       KIND_ARGUMENT,
+				InsertText: param + "=",
+			})
+		}
+		return result
+	}
+
+	desc := self.describe(top.name, top.plugin)
+	if desc == nil {
+		return nil
+	}
+
+	for _, arg := range desc.Args {
+		result = append(result, &Completion{
+			Label:         arg.Name,
+			Kind:          KIND_ARGUMENT,
+			Detail:        argDetail(arg),
+			Documentation: arg.Description,
+			InsertText:    arg.Name + "=",
+		})
+	}
+	return result
+}
+
+func (self *LanguageServer) pluginCompletions() []*Completion {
+	result := make([]*Completion, 0, len(self.plugin_names))
+	for _, name := range self.plugin_names {
+		result = append(result, callableCompletion(self.plugins[name], KIND_PLUGIN))
+	}
+	return result
+}
+
+func (self *LanguageServer) functionCompletions() []*Completion {
+	result := make([]*Completion, 0, len(self.function_names))
+	for _, name := range self.function_names {
+		result = append(result, callableCompletion(self.functions[name], KIND_FUNCTION))
+	}
+	return result
+}
+
+func (self *LanguageServer) artifactCompletions() []*Completion {
+	result := make([]*Completion, 0, len(self.artifact_names))
+	for _, name := range self.artifact_names {
+		result = append(result, callableCompletion(self.artifacts[name], KIND_ARTIFACT))
+	}
+	return result
+}
+
+func (self *analysis) variableCompletions() []*Completion {
+	result := make([]*Completion, 0, len(self.let_names))
+	for _, name := range self.let_names {
+		result = append(result, &Completion{
+			Label:  name,
+			Kind:   KIND_VARIABLE,
+			Detail: "LET " + name,
+		})
+	}
+	return result
+}
+
+func callableCompletion(desc *api_proto.Completion, kind string) *Completion {
+	names := []string{}
+	for _, arg := range desc.Args {
+		names = append(names, arg.Name)
+	}
+
+	return &Completion{
+		Label:         desc.Name,
+		Kind:          kind,
+		Detail:        desc.Name + "(" + strings.Join(names, ", ") + ")",
+		Documentation: desc.Description,
+		InsertText:    desc.Name + "(",
+	}
+}
+
+func argDetail(arg *api_proto.ArgDescriptor) string {
+	result := strings.TrimSpace(arg.Type)
+	if arg.Repeated {
+		result = "list of " + result
+	}
+	if arg.Required {
+		result += " (required)"
+	}
+	return result
+}
+
+func paramDescriptors(params []string) []*api_proto.ArgDescriptor {
+	result := []*api_proto.ArgDescriptor{}
+	for _, param := range params {
+		result = append(result, &api_proto.ArgDescriptor{Name: param})
+	}
+	return result
+}
+
+func kindOf(desc *api_proto.Completion) string {
+	switch desc.Type {
+	case "Plugin":
+		return KIND_PLUGIN
+	case "Artifact":
+		return KIND_ARTIFACT
+	}
+	return KIND_FUNCTION
+}
+
+// Keep completions starting with the prefix. Keywords are matched
+// case insensitively since VQL keywords are.
+func filterCompletions(items []*Completion, prefix string) []*Completion {
+	if prefix == "" {
+		return items
+	}
+
+	lower_prefix := strings.ToLower(prefix)
+	result := []*Completion{}
+	for _, item := range items {
+		label := item.Label
+		if item.Kind == KIND_KEYWORD {
+			label = strings.ToLower(label)
+			if !strings.HasPrefix(label, lower_prefix) {
+				continue
+			}
+		} else if !strings.HasPrefix(label, prefix) {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func sortedKeys(in map[string]*api_proto.Completion) []string {
+	result := make([]string, 0, len(in))
+	for k := range in {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,284 @@
+package langserver
+
+import (
+	"testing"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+var descriptions = []*api_proto.Completion{{
+	Name:        "glob",
+	Type:        "Plugin",
+	Description: "Retrieve files based on a list of glob expressions",
+	Args: []*api_proto.ArgDescriptor{
+		{Name: "globs", Type: "string", Repeated: true, Required: true},
+		{Name: "root", Type: "OSPath"},
+	},
+}, {
+	Name: "info",
+	Type: "Plugin",
+}, {
+	Name:        "format",
+	Type:        "Function",
+	Description: "Format one or more items according to a format string.",
+	Args: []*api_proto.ArgDescriptor{
+		{Name: "format", Type: "string", Required: true},
+		{Name: "args", Type: "Any"},
+	},
+}, {
+	Name: "Artifact.Windows.System.Pslist",
+	Type: "Artifact",
+	Args: []*api_proto.ArgDescriptor{
+		{Name: "ProcessRegex", Type: "regex"},
+	},
+}}
+
+// Split the query at the | marking the cursor.
+func cursor(query string) (string, int, int) {
+	for i, c := range query {
+		if c == '|' {
+			line, column := toPosition(query, i)
+			return query[:i] + query[i+1:], line, column
+		}
+	}
+	return query, 0, len(query)
+}
+
+func labels(items []*Completion) []string {
+	result := []string{}
+	for _, item := range items {
+		result = append(result, item.Label)
+	}
+	return result
+}
+
+func TestCompletions(t *testing.T) {
+	server := NewLanguageServer(descriptions)
+
+	for _, tc := range []struct {
+		query    string
+		expected []string
+	}{
+		// Plugins, stored queries and artifacts after FROM.
+		{"LET X = SELECT * FROM info()\nSELECT * FROM |",
+			[]string{"glob", "info", "X", "Artifact.Windows.System.Pslist"}},
+		{"SELECT * FROM gl|", []string{"glob"}},
+		{"SELECT * FROM Artifact.Win|", []string{"Artifact.Windows.System.Pslist"}},
+
+		// Arguments of the called plugin, function or artifact.
+		{"SELECT * FROM glob(|", []string{"globs", "root"}},
+		{"SELECT * FROM glob(globs='*', r|)", []string{"root"}},
+		{"SELECT format(|) FROM scope()", []string{"format", "args"}},
+		{"SELECT * FROM Artifact.Windows.System.Pslist(|",
+			[]string{"ProcessRegex", "source", "preconditions"}},
+
+		// Columns of the upstream query.
+		{"LET X = SELECT Name, Pid AS ProcessId, format(format='%v', args=Name) AS Desc FROM info()\n" +
+			"SELECT P| FROM X", []string{"ProcessId"}},
+		{"SELECT * FROM foreach(row={ SELECT OSPath AS Path FROM glob(globs='*') }, " +
+			"query={ SELECT * FROM scope() })\nWHERE P|", nil},
+		{"SELECT Pa| FROM { SELECT OSPath AS Path FROM glob(globs='*') }",
+			[]string{"Path"}},
+
+		// Keywords are case insensitive.
+		{"sel|", []string{"SELECT"}},
+
+		// Nothing inside strings or comments.
+		{"SELECT 'gl|' FROM info()", nil},
+		{"SELECT * FROM info() -- gl|", nil},
+		{"LET |", nil},
+	} {
+		query, line, column := cursor(tc.query)
+		assert.Equal(t, tc.expected, nilIfEmpty(
+			labels(server.Complete(query, line, column))), tc.query)
+	}
+}
+
+func nilIfEmpty(in []string) []string {
+	if len(in) == 0 {
+		return nil
+	}
+	return in
+}
+
+func TestHover(t *testing.T) {
+	server := NewLanguageServer(descriptions)
+
+	query, line, column := cursor("SELECT * FROM gl|ob(globs='*')")
+	hover := server.Hover(query, line, column)
+	assert.Equal(t, "glob", hover.Name)
+	assert.Equal(t, KIND_PLUGIN, hover.Kind)
+	assert.Equal(t, 2, len(hover.Args))
+
+	query, line, column = cursor("SELECT * FROM glob(ro|ot='/', globs='*')")
+	hover = server.Hover(query, line, column)
+	assert.Equal(t, "root", hover.Name)
+	assert.Equal(t, KIND_ARGUMENT, hover.Kind)
+
+	query, line, column = cursor(
+		"LET X = SELECT Pid FROM info()\nSELECT * FROM |X")
+	hover = server.Hover(query, line, column)
+	assert.Equal(t, KIND_VARIABLE, hover.Kind)
+	assert.Equal(t, []string{"Pid"}, hover.Columns)
+}
+
+func TestDiagnostics(t *testing.T) {
+	server := NewLanguageServer(descriptions)
+
+	// Syntax errors are reported at the offending token.
+	diagnostics := server.Diagnose("SELECT * FROM info()\nSELECT * FROM WHERE")
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, SEVERITY_ERROR, diagnostics[0].Severity)
+	assert.Equal(t, 1, diagnostics[0].Line)
+	assert.Equal(t, 14, diagnostics[0].Column)
+
+	// Unknown names and arguments are warnings.
+	diagnostics = server.Diagnose(`
+LET F(X) = format(format="%v", args=X)
+SELECT F(X=1), formt(format="x"), format(args=1)
+FROM glob(globs="*", rot="/")
+WHERE Name =~ "x"
+
+SELECT * FROM nosuchplugin
+`)
+	messages := []string{}
+	for _, d := range diagnostics {
+		assert.Equal(t, SEVERITY_WARNING, d.Severity)
+		messages = append(messages, d.Message)
+	}
+	assert.Equal(t, []string{
+		"Unknown function formt",
+		"Missing required argument format for format",
+		"Unknown argument rot for glob",
+		"Unknown plugin nosuchplugin",
+	}, messages)
+
+	// Empty queries are fine.
+	assert.Equal(t, 0, len(server.Diagnose("-- Nothing yet\n")))
+}
+
+func lintRules(diagnostics []*Diagnostic) []string {
+	result := []string{}
+	for _, d := range diagnostics {
+		result = append(result, d.Rule)
+	}
+	return result
+}
+
+func TestLint(t *testing.T) {
+	server := NewLanguageServer(append([]*api_proto.Completion{{
+		Name: "if",
+		Type: "Plugin",
+	}, {
+		Name: "foreach",
+		Type: "Plugin",
+		Args: []*api_proto.ArgDescriptor{
+			{Name: "row", Type: "StoredQuery"},
+			{Name: "query", Type: "StoredQuery"},
+		},
+	}, {
+		Name: "execve",
+		Type: "Plugin",
+		Args: []*api_proto.ArgDescriptor{
+			{Name: "argv", Type: "string", Repeated: true, Required: true},
+			{Name: "length", Type: "int64"},
+		},
+	}}, descriptions...))
+
+	for _, tc := range []struct {
+		query    string
+		options  LintOptions
+		expected []string
+	}{
+		// Columns must come from the query selected from.
+		{`LET X <= SELECT Name, Pid FROM info()
+SELECT Name, Ppid, Pid AS P, format(format="%v", args=Name) AS F
+FROM X WHERE Pid > 1 AND Param`,
+			LintOptions{Variables: []string{"Param"}},
+			[]string{RULE_UNKNOWN_COLUMN}},
+
+		// Unknown columns are not reported when the source is
+		// a plugin.
+		{`SELECT Anything FROM info()`, LintOptions{}, []string{}},
+
+		// Stored queries evaluated once per row.
+		{`LET X = SELECT * FROM info()
+SELECT * FROM foreach(row=X, query={ SELECT * FROM X })`,
+			LintOptions{}, []string{RULE_MATERIALIZE}},
+		{`LET X(A) <= SELECT * FROM info()`,
+			LintOptions{}, []string{RULE_MATERIALIZE}},
+
+		// Recursion must be bounded.
+		{`LET Walk(Path) = SELECT * FROM Walk(Path=Path)`,
+			LintOptions{}, []string{RULE_RECURSION}},
+		{`LET Walk(Path, Depth) = SELECT * FROM if(condition=Depth < 5,
+   then={ SELECT * FROM Walk(Path=Path, Depth=Depth + 1) })`,
+			LintOptions{}, []string{}},
+
+		// A plugin call is not a recursive call to a stored
+		// query of the same name.
+		{`LET info = SELECT * FROM info()`, LintOptions{}, []string{}},
+
+		// Commands should be logged.
+		{`SELECT * FROM execve(argv=["ls"])`,
+			LintOptions{}, []string{RULE_EXECVE_LOGGING}},
+		{`SELECT * FROM execve(argv=["ls"])`,
+			LintOptions{Definitions: `LET L = log(message="Running")`},
+			[]string{}},
+
+		// Literals of the wrong type.
+		{`SELECT * FROM execve(argv="ls", length="x")`,
+			LintOptions{Definitions: `LET L = log(message="Running")`},
+			[]string{RULE_ARGUMENT_TYPE}},
+
+		// Stored queries from the definitions are known.
+		{`SELECT * FROM Helper(X=1)`,
+			LintOptions{Definitions: `LET Helper(X) = SELECT * FROM info()`},
+			[]string{}},
+	} {
+		assert.Equal(t, tc.expected,
+			lintRules(server.Lint(tc.query, tc.options)), tc.query)
+	}
+}
+
+func TestLintArtifact(t *testing.T) {
+	server := NewLanguageServer(append([]*api_proto.Completion{{
+		Name: "execve",
+		Type: "Plugin",
+	}}, descriptions...))
+
+	diagnostics := server.LintArtifact(&artifacts_proto.Artifact{
+		Name: "Custom.Test",
+		Parameters: []*artifacts_proto.ArtifactParameter{
+			{Name: "Glob"},
+		},
+		Export: `LET Files = SELECT OSPath FROM glob(globs=Glob)`,
+		Sources: []*artifacts_proto.ArtifactSource{{
+			Query: `LET Paths <= SELECT OSPath FROM Files`,
+		}, {
+			// Paths is defined by the previous source.
+			Query: `SELECT OSPath, Size FROM Paths`,
+		}, {
+			Query: `SELECT * FROM execve(argv=["ls"])`,
+		}, {
+			Query: `SELECT * FROM`,
+		}, {
+			// Imported functions are known.
+			Query: `SELECT Imported(X=1) FROM info()`,
+		}},
+	}, `LET Imported(X) = X + 1`)
+
+	fields := []string{}
+	for _, d := range diagnostics {
+		assert.Equal(t, "Custom.Test", d.Artifact)
+		fields = append(fields, d.Field+":"+d.Rule)
+	}
+	assert.Equal(t, []string{
+		"sources[1].query:" + RULE_UNKNOWN_COLUMN,
+		"sources[2].query:" + RULE_EXECVE_LOGGING,
+		"sources[3].query:" + RULE_SYNTAX,
+		"required_permissions:" + RULE_EXECVE_PERMISSION,
+	}, fields)
+}
@@ -0,0 +1,165 @@
+package langserver
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+var (
+	// The same tokens the vfilter parser uses so positions reported
+	// to the editor line up with parser errors.
+	vqlLexer = lexer.Must(lexer.Regexp(
+		`(?ms)` +
+			`(\s+)` +
+			`|(?P<MLineComment>/[*].*?[*]/)` +
+			`|(?P<VQLComment>^--.*?$)` +
+			`|(?P<Comment>^//.*?$)` +
+			`|(?ims)(?P<EXPLAIN>\bEXPLAIN\b)` +
+			`|(?ims)(?P<SELECT>\bSELECT\b)` +
+			`|(?ims)(?P<WHERE>\bWHERE\b)` +
+			`|(?ims)(?P<AND>\bAND\b)` +
+			`|(?ims)(?P<OR>\bOR\b)` +
+			`|(?ims)(?P<AlternativeOR>\|+)` +
+			`|(?ims)(?P<FROM>\bFROM\b)` +
+			`|(?ims)(?P<NOT>\bNOT\b)` +
+			`|(?ims)(?P<AS>\bAS\b)` +
+			`|(?ims)(?P<IN>\bIN\b)` +
+			`|(?ims)(?P<LIMIT>\bLIMIT\b)` +
+			`|(?ims)(?P<NULL>\bNULL\b)` +
+			`|(?ims)(?P<DESC>\bDESC\b)` +
+			`|(?ims)(?P<GROUPBY>\bGROUP\s+BY\b)` +
+			`|(?ims)(?P<ORDERBY>\bORDER\s+BY\b)` +
+			`|(?ims)(?P<BOOL>\bTRUE\b|\bFALSE\b)` +
+			`|(?ims)(?P<LET>\bLET\b)` +
+			"|(?P<Ident>[a-zA-Z_][a-zA-Z0-9_]*|`[^`]+`)" +
+			`|''(?P<MultilineString>'.*?')''` +
+			`|(?P<String>'([^'\\]*(\\.[^'\\]*)*)'|"([^"\\]*(\\.[^"\\]*)*)")` +
+			`|(?P<Number>[-+]?(0x[0-9a-f]+|\d*\.?\d+([eE][-+]?\d+)?))` +
+			`|(?P<Operators><>|!=|<=|>=|=>|=~|[-:+*/%,.()=<>{}\[\]])`,
+	))
+
+	symbolNames = func() map[rune]string {
+		result := make(map[rune]string)
+		for k, v := range vqlLexer.Symbols() {
+			result[v] = k
+		}
+		return result
+	}()
+)
+
+type token struct {
+	Type  string
+	Value string
+
+	// Byte offsets into the query.
+	Offset int
+	End    int
+}
+
+func (self *token) isComment() bool {
+	switch self.Type {
+	case "MLineComment", "VQLComment", "Comment":
+		return true
+	}
+	return false
+}
+
+func (self *token) isKeyword() bool {
+	switch self.Type {
+	case "Ident", "String", "MultilineString", "Number", "Operators":
+		return false
+	}
+	return !self.isComment()
+}
+
+func (self *token) is(value string) bool {
+	return self != nil && self.Type == "Operators" && self.Value == value
+}
+
+// The identifier name without any quoting.
+func (self *token) name() string {
+	return strings.Trim(self.Value, "`")
+}
+
+// Split the query into tokens. Tokenizing stops at the first invalid
+// token (usually an unterminated string) and returns everything
+// before it.
+func tokenize(query string) ([]*token, error) {
+	lex, err := vqlLexer.Lex(strings.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*token{}
+	for {
+		t, err := lex.Next()
+		if err != nil {
+			return result, err
+		}
+
+		if t.EOF() {
+			return result, nil
+		}
+
+		result = append(result, &token{
+			Type:   symbolNames[t.Type],
+			Value:  t.Value,
+			Offset: t.Pos.Offset,
+			End:    t.Pos.Offset + len(t.Value),
+		})
+	}
+}
+
+// Drop comments which are not significant for the analysis.
+func significant(tokens []*token) []*token {
+	result := make([]*token, 0, len(tokens))
+	for _, t := range tokens {
+		if !t.isComment() {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// Convert a zero based line and column (in characters) to a byte
+// offset into the query.
+func toOffset(query string, line, column int) int {
+	offset := 0
+	for line > 0 {
+		idx := strings.IndexByte(query[offset:], '\n')
+		if idx < 0 {
+			return len(query)
+		}
+		offset += idx + 1
+		line--
+	}
+
+	for column > 0 && offset < len(query) {
+		if query[offset] == '\n' {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(query[offset:])
+		offset += size
+		column--
+	}
+	return offset
+}
+
+// Convert a byte offset to a zero based line and column.
+func toPosition(query string, offset int) (line, column int) {
+	if offset > len(query) {
+		offset = len(query)
+	}
+
+	for _, c := range query[:offset] {
+		if c == '\n' {
+			line++
+			column = 0
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
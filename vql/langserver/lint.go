@@ -0,0 +1,319 @@
+package langserver
+
+import (
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	RULE_UNKNOWN_COLUMN    = "unknown-column"
+	RULE_MATERIALIZE       = "materialize"
+	RULE_RECURSION         = "recursion"
+	RULE_EXECVE_LOGGING    = "execve-logging"
+	RULE_EXECVE_PERMISSION = "execve-permission"
+)
+
+type LintOptions struct {
+	// Names defined outside the query, e.g. artifact parameters.
+	Variables []string
+
+	// VQL defining stored queries and functions the query may
+	// use, e.g. the artifact's export section.
+	Definitions string
+}
+
+// Statically check a query. Queries with syntax errors only report
+// the syntax error since the rest of the analysis would be
+// misleading.
+func (self *LanguageServer) Lint(
+	query string, options LintOptions) []*Diagnostic {
+	a := analyze(query, self.plugins)
+
+	// Empty queries are fine.
+	if len(a.tokens) == 0 && a.error_offset < 0 {
+		return nil
+	}
+
+	_, err := vfilter.MultiParse(query)
+	if err != nil {
+		return []*Diagnostic{parseDiagnostic(query, err)}
+	}
+
+	// Only the query's own stored queries are checked but the
+	// definitions may be used.
+	local := a.localLets()
+	definitions := analyze(options.Definitions, self.plugins)
+	a.define(definitions)
+	a.variables = options.Variables
+
+	r := &reporter{query: query}
+	self.checkCalls(a, r)
+	checkColumns(a, r)
+	checkMaterialized(a, local, r)
+	checkRecursion(a, local, r)
+	checkExecve(a, definitions, r)
+
+	return r.result
+}
+
+// The index of the name token of each LET statement in the query.
+func (self *analysis) localLets() []int {
+	result := []int{}
+	for _, s := range self.statements {
+		if s.end-s.start > 1 && self.tokens[s.start].Type == "LET" &&
+			self.tokens[s.start+1].Type == "Ident" {
+			result = append(result, s.start+1)
+		}
+	}
+	return result
+}
+
+// Report references to columns which the query selected from does
+// not produce. Only top level queries are checked since subqueries
+// may also refer to the columns of enclosing queries.
+func checkColumns(a *analysis, r *reporter) {
+	tokens := a.tokens
+	for _, s := range a.statements {
+		statement := tokens[s.start:s.end]
+		select_idx := findTopLevel(statement, "SELECT")
+		if select_idx < 0 {
+			continue
+		}
+
+		start := s.start + select_idx
+		query := tokens[start:s.end]
+		from := findTopLevel(query, "FROM")
+		if from < 0 || from+1 >= len(query) {
+			continue
+		}
+
+		columns, complete := a.sourceColumns(query[from+1:], 0)
+		if !complete {
+			continue
+		}
+
+		source := "the subquery"
+		source_end := from + 1
+		if query[from+1].is("{") {
+			source_end = findClose(query, from+1) + 1
+		} else {
+			source, source_end = chainAfter(query, from+1)
+			if source_end < len(query) && query[source_end].is("(") {
+				source_end = findClose(query, source_end) + 1
+			}
+		}
+
+		known := append([]string{}, columns...)
+		known = append(known, a.let_names...)
+		known = append(known, a.variables...)
+		if statement[0].Type == "LET" && len(statement) > 1 {
+			definition, pres := a.lets[statement[1].name()]
+			if pres {
+				known = append(known, definition.Params...)
+			}
+		}
+
+		// Aliases and lambda parameters.
+		for i := 1; i < len(query); i++ {
+			if query[i].is("=>") && query[i-1].Type == "Ident" {
+				known = append(known, query[i-1].name())
+			}
+			if query[i-1].Type == "AS" && query[i].Type == "Ident" {
+				known = append(known, query[i].name())
+			}
+		}
+
+		depth := 0
+		for i := 1; i < len(query); i++ {
+			if i > from && i < source_end {
+				continue
+			}
+
+			t := query[i]
+			switch {
+			case t.is("{"):
+				depth++
+			case t.is("}"):
+				depth--
+			}
+
+			if depth > 0 || t.Type != "Ident" || utils.InString(known, t.name()) {
+				continue
+			}
+
+			var next *token
+			if i+1 < len(query) {
+				next = query[i+1]
+			}
+
+			if next.is("(") || next.is("=>") || query[i-1].Type == "AS" ||
+				isMemberOrArgName(query, i) {
+				continue
+			}
+
+			r.warn(t, t.End, RULE_UNKNOWN_COLUMN,
+				"Column %v is not produced by %v", t.name(), source)
+		}
+	}
+}
+
+// Stored queries defined with = are evaluated every time they are
+// used. Report those used by several statements or once for every
+// row of a foreach(). Stored queries with parameters are evaluated
+// for each call so they can not be materialized.
+func checkMaterialized(a *analysis, local []int, r *reporter) {
+	for _, idx := range local {
+		name_token := a.tokens[idx]
+		definition, pres := a.lets[name_token.name()]
+		if !pres {
+			continue
+		}
+
+		if definition.Materialized && len(definition.Params) > 0 {
+			r.warn(name_token, name_token.End, RULE_MATERIALIZE,
+				"%v takes parameters but is materialized; use = instead",
+				definition.Name)
+			continue
+		}
+
+		if definition.Materialized || len(definition.Params) > 0 ||
+			len(definition.Body) == 0 || definition.Body[0].Type != "SELECT" {
+			continue
+		}
+
+		statements := make(map[int]bool)
+		per_row := false
+		for i, t := range a.tokens {
+			if i == idx || t.Type != "Ident" || t.name() != definition.Name ||
+				isMemberOrArgName(a.tokens, i) {
+				continue
+			}
+
+			for k, s := range a.statements {
+				if s.start <= i && i < s.end {
+					statements[k] = true
+				}
+			}
+
+			if insideForeachQuery(a.tokens, i) {
+				per_row = true
+			}
+		}
+
+		if per_row || len(statements) > 1 {
+			r.warn(name_token, name_token.End, RULE_MATERIALIZE,
+				"Stored query %v is evaluated each time it is used; "+
+					"consider LET %v <= to evaluate it once",
+				definition.Name, definition.Name)
+		}
+	}
+}
+
+// Identifiers after a . or naming an argument do not refer to
+// variables.
+func isMemberOrArgName(tokens []*token, i int) bool {
+	if i == 0 {
+		return false
+	}
+
+	prev := tokens[i-1]
+	if prev.is(".") {
+		return true
+	}
+
+	return i+1 < len(tokens) && tokens[i+1].is("=") &&
+		(prev.is("(") || prev.is(","))
+}
+
+// Is the token at index i inside the query of a foreach() which runs
+// once for every row?
+func insideForeachQuery(tokens []*token, i int) bool {
+	stack := openFrames(tokens[:i])
+	for k := 1; k < len(stack); k++ {
+		f := stack[k]
+		if f.open == "{" && f.index >= 2 && tokens[f.index-1].is("=") &&
+			tokens[f.index-2].name() == "query" &&
+			stack[k-1].open == "(" && stack[k-1].name == "foreach" {
+			return true
+		}
+	}
+	return false
+}
+
+// Report stored queries which call themselves without an obvious
+// limit on the depth of the recursion.
+func checkRecursion(a *analysis, local []int, r *reporter) {
+	for _, idx := range local {
+		name_token := a.tokens[idx]
+		definition, pres := a.lets[name_token.name()]
+		if !pres {
+			continue
+		}
+
+		body := definition.Body
+		recursive := false
+		bounded := false
+		for i, t := range body {
+			if t.Type == "LIMIT" {
+				bounded = true
+			}
+
+			if t.Type != "Ident" {
+				continue
+			}
+
+			if t.name() == definition.Name &&
+				!a.isPluginCall(body, t.name(), i+1) &&
+				((i+1 < len(body) && body[i+1].is("(")) ||
+					(i > 0 && body[i-1].Type == "FROM")) {
+				recursive = true
+			}
+
+			// A parameter compared to something, e.g. Depth < 5
+			if utils.InString(definition.Params, t.name()) {
+				for _, j := range []int{i - 1, i + 1} {
+					if j >= 0 && j < len(body) && isComparison(body[j]) {
+						bounded = true
+					}
+				}
+			}
+		}
+
+		if recursive && !bounded {
+			r.warn(name_token, name_token.End, RULE_RECURSION,
+				"%v calls itself without limiting the depth of the recursion",
+				definition.Name)
+		}
+	}
+}
+
+func isComparison(t *token) bool {
+	return t.is("<") || t.is(">") || t.is("<=") || t.is(">=")
+}
+
+// Commands run by execve() should be logged so the collection shows
+// what was run on the endpoint.
+func checkExecve(a *analysis, definitions *analysis, r *reporter) {
+	if callsName(a.tokens, "log") || callsName(definitions.tokens, "log") {
+		return
+	}
+
+	for i, t := range a.tokens {
+		if t.Type == "Ident" && t.name() == "execve" &&
+			i+1 < len(a.tokens) && a.tokens[i+1].is("(") {
+			r.warn(t, t.End, RULE_EXECVE_LOGGING,
+				"execve() is called without logging the command; "+
+					"use log() to record the command line")
+		}
+	}
+}
+
+func callsName(tokens []*token, name string) bool {
+	for i, t := range tokens {
+		if t.Type == "Ident" && t.name() == name &&
+			i+1 < len(tokens) && tokens[i+1].is("(") {
+			return true
+		}
+	}
+	return false
+}
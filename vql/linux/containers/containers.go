@@ -0,0 +1,264 @@
+//go:build linux
+// +build linux
+
+// Discover containers running on the host.
+//
+// Docker is queried over its API socket. Containers managed by
+// containerd and CRI-O are found from the OCI bundles the runtimes
+// keep on disk so we do not need to speak their gRPC APIs.
+
+package containers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+var (
+	namespaceTypes = []string{
+		"cgroup", "ipc", "mnt", "net", "pid", "time", "user", "uts"}
+)
+
+type Mount struct {
+	Source      string
+	Destination string
+	Type        string
+	ReadOnly    bool
+}
+
+type Pod struct {
+	Name      string
+	Namespace string
+	Uid       string
+	Container string
+}
+
+// A normalized description of a container regardless of runtime.
+type Container struct {
+	Runtime     string
+	Id          string
+	Name        string
+	Image       string
+	ImageId     string
+	ImageDigest string
+	State       string
+	Created     time.Time
+
+	// The host pid of the container's init process.
+	Pid    int
+	Mounts []*Mount
+	Labels map[string]string
+	Pod    *Pod
+}
+
+type Options struct {
+	Proc         string
+	DockerSocket string
+
+	// Directories holding OCI bundles, keyed by runtime name.
+	BundleRoots map[string][]string
+}
+
+func DefaultOptions() *Options {
+	return &Options{
+		Proc:         "/proc",
+		DockerSocket: "/var/run/docker.sock",
+		BundleRoots: map[string][]string{
+			"containerd": {
+				"/run/containerd/io.containerd.runtime.v2.task",
+				"/run/containerd/io.containerd.runtime.v1.linux",
+			},
+			"cri-o": {
+				"/run/containers/storage/overlay-containers",
+				"/var/lib/containers/storage/overlay-containers",
+			},
+		},
+	}
+}
+
+// Kubernetes records the pod a container belongs to in the
+// container's labels (Docker) or OCI annotations (containerd and
+// CRI-O).
+func podFromLabels(labels map[string]string) *Pod {
+	get := func(keys ...string) string {
+		for _, k := range keys {
+			v, pres := labels[k]
+			if pres {
+				return v
+			}
+		}
+		return ""
+	}
+
+	pod := &Pod{
+		Name: get("io.kubernetes.pod.name",
+			"io.kubernetes.cri.sandbox-name"),
+		Namespace: get("io.kubernetes.pod.namespace",
+			"io.kubernetes.cri.sandbox-namespace"),
+		Uid: get("io.kubernetes.pod.uid",
+			"io.kubernetes.cri.sandbox-uid"),
+		Container: get("io.kubernetes.container.name",
+			"io.kubernetes.cri.container-name"),
+	}
+
+	if pod.Name == "" && pod.Uid == "" {
+		return nil
+	}
+	return pod
+}
+
+// Enumerate the running containers from all the runtimes we know
+// about. Containers are deduplicated by their id since Docker's
+// containers are also visible as containerd bundles.
+func ListContainers(ctx context.Context, opts *Options) []*Container {
+	seen := make(map[string]bool)
+	var result []*Container
+
+	add := func(containers []*Container) {
+		for _, c := range containers {
+			if seen[c.Id] {
+				continue
+			}
+			seen[c.Id] = true
+			result = append(result, c)
+		}
+	}
+
+	docker, err := listDockerContainers(ctx, opts.DockerSocket)
+	if err == nil {
+		add(docker)
+	}
+
+	runtimes := make([]string, 0, len(opts.BundleRoots))
+	for k := range opts.BundleRoots {
+		runtimes = append(runtimes, k)
+	}
+	sort.Strings(runtimes)
+
+	for _, runtime := range runtimes {
+		add(listBundleContainers(opts, runtime, opts.BundleRoots[runtime]))
+	}
+
+	return result
+}
+
+// Find a container by its id, an unambiguous id prefix or its name.
+func FindContainer(containers []*Container, name string) *Container {
+	var match *Container
+	for _, c := range containers {
+		if c.Id == name || c.Name == name {
+			return c
+		}
+
+		if len(name) >= 4 && strings.HasPrefix(c.Id, name) {
+			if match != nil {
+				return nil
+			}
+			match = c
+		}
+	}
+	return match
+}
+
+func getNamespaces(proc string, pid int) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+	if pid <= 0 {
+		return result
+	}
+
+	ns_dir := filepath.Join(proc, itoa(pid), "ns")
+	for _, ns := range namespaceTypes {
+		link, err := os.Readlink(filepath.Join(ns_dir, ns))
+		if err == nil {
+			result.Set(ns, link)
+		}
+	}
+	return result
+}
+
+type ContainersArgs struct {
+	DockerSocket string `vfilter:"optional,field=docker_socket,doc=The path to the Docker API socket (default /var/run/docker.sock)"`
+}
+
+type ContainersPlugin struct{}
+
+func (self ContainersPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "containers")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("containers: %s", err)
+			return
+		}
+
+		arg := &ContainersArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("containers: %s", err.Error())
+			return
+		}
+
+		opts := DefaultOptions()
+		if arg.DockerSocket != "" {
+			opts.DockerSocket = arg.DockerSocket
+		}
+
+		for _, c := range ListContainers(ctx, opts) {
+			mounts := c.Mounts
+			if mounts == nil {
+				mounts = []*Mount{}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Runtime", c.Runtime).
+				Set("Id", c.Id).
+				Set("Name", c.Name).
+				Set("Image", c.Image).
+				Set("ImageId", c.ImageId).
+				Set("ImageDigest", c.ImageDigest).
+				Set("State", c.State).
+				Set("Created", c.Created).
+				Set("Pid", c.Pid).
+				Set("Namespaces", getNamespaces(opts.Proc, c.Pid)).
+				Set("Mounts", mounts).
+				Set("Labels", c.Labels).
+				Set("Pod", c.Pod):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self ContainersPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "containers",
+		Doc:      "List the containers running on the host (Docker, containerd and CRI-O).",
+		ArgType:  type_map.AddType(scope, &ContainersArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&ContainersPlugin{})
+}
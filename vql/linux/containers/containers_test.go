@@ -0,0 +1,200 @@
+//go:build linux
+// +build linux
+
+package containers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+const testId = "4a3f1b2c4a3f1b2c4a3f1b2c4a3f1b2c4a3f1b2c4a3f1b2c4a3f1b2c4a3f1b2c"
+
+func TestParseCgroup(t *testing.T) {
+	assert.Equal(t, testId, parseCgroupContainerId(strings.NewReader(
+		"0::/system.slice/docker-"+testId+".scope\n")))
+
+	assert.Equal(t, testId, parseCgroupContainerId(strings.NewReader(
+		"12:memory:/kubepods/burstable/pod1234/"+testId+"\n")))
+
+	assert.Equal(t, "", parseCgroupContainerId(strings.NewReader(
+		"0::/user.slice/user-1000.slice/session-2.scope\n")))
+
+	assert.Equal(t, 12, parseNSpid(strings.NewReader(
+		"Name:\tnginx\nNSpid:\t4567\t12\n")))
+}
+
+func writeFile(t *testing.T, path, data string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0600))
+}
+
+func TestBundles(t *testing.T) {
+	dir, err := os.MkdirTemp("", "containers")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Use our own pid as a live init process.
+	pid := itoa(os.Getpid())
+
+	containerd := filepath.Join(dir, "containerd")
+	writeFile(t, filepath.Join(containerd, "k8s.io", testId, "init.pid"), pid)
+	writeFile(t, filepath.Join(containerd, "k8s.io", testId, "config.json"), `{
+ "hostname": "web-1",
+ "annotations": {
+   "io.kubernetes.cri.container-name": "nginx",
+   "io.kubernetes.cri.sandbox-name": "web-1",
+   "io.kubernetes.cri.sandbox-namespace": "default",
+   "io.kubernetes.cri.image-name": "docker.io/library/nginx:1.25"
+ },
+ "mounts": [{"destination": "/etc/hosts", "type": "bind",
+             "source": "/var/lib/hosts", "options": ["rbind", "ro"]}]
+}`)
+
+	// An exited container
+	writeFile(t, filepath.Join(containerd, "default", "dead", "init.pid"), "0")
+	writeFile(t, filepath.Join(containerd, "default", "dead", "config.json"), "{}")
+
+	// CRI-O splits the bundle between two directories.
+	crio_run := filepath.Join(dir, "crio_run")
+	crio_lib := filepath.Join(dir, "crio_lib")
+	writeFile(t, filepath.Join(crio_run, "c1", "userdata", "pidfile"), pid)
+	writeFile(t, filepath.Join(crio_lib, "c1", "userdata", "config.json"), `{
+ "annotations": {
+   "io.kubernetes.cri-o.ContainerID": "c1",
+   "io.kubernetes.cri-o.ImageName": "quay.io/app:1",
+   "io.kubernetes.pod.name": "app",
+   "io.kubernetes.container.name": "app"
+ }
+}`)
+
+	opts := &Options{
+		Proc:         "/proc",
+		DockerSocket: filepath.Join(dir, "missing.sock"),
+		BundleRoots: map[string][]string{
+			"containerd": {containerd},
+			"cri-o":      {crio_run, crio_lib},
+		},
+	}
+
+	containers := ListContainers(context.Background(), opts)
+	assert.Equal(t, 2, len(containers))
+
+	c := FindContainer(containers, "nginx")
+	assert.Equal(t, testId, c.Id)
+	assert.Equal(t, "containerd", c.Runtime)
+	assert.Equal(t, "docker.io/library/nginx:1.25", c.Image)
+	assert.Equal(t, "default", c.Pod.Namespace)
+	assert.Equal(t, "k8s.io", c.Labels["io.containerd.namespace"])
+	assert.True(t, c.Mounts[0].ReadOnly)
+
+	assert.Equal(t, c, FindContainer(containers, testId[:12]))
+
+	c = FindContainer(containers, "c1")
+	assert.Equal(t, "cri-o", c.Runtime)
+	assert.Equal(t, "quay.io/app:1", c.Image)
+	assert.Equal(t, "app", c.Pod.Name)
+}
+
+func TestDocker(t *testing.T) {
+	dir, err := os.MkdirTemp("", "docker")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+
+	responses := map[string]string{
+		"/containers/json": `[{"Id": "` + testId + `"}]`,
+		"/containers/" + testId + "/json": `{
+  "Id": "` + testId + `", "Name": "/web", "Image": "sha256:1111",
+  "Created": "2023-01-02T03:04:05.123Z",
+  "Config": {"Image": "nginx:latest", "Labels": {"app": "web"}},
+  "State": {"Status": "running", "Pid": 1234},
+  "Mounts": [{"Type": "volume", "Source": "/var/lib/docker/volumes/x",
+              "Destination": "/data", "RW": true}]}`,
+		"/images/sha256:1111/json": `{"RepoDigests": ["nginx@sha256:2222"]}`,
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			data, pres := responses[r.URL.Path]
+			if !pres {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(data))
+		}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	containers, err := listDockerContainers(context.Background(), socket)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(containers))
+
+	c := containers[0]
+	assert.Equal(t, "web", c.Name)
+	assert.Equal(t, "nginx:latest", c.Image)
+	assert.Equal(t, "sha256:1111", c.ImageId)
+	assert.Equal(t, "sha256:2222", c.ImageDigest)
+	assert.Equal(t, 1234, c.Pid)
+	assert.Equal(t, "/data", c.Mounts[0].Destination)
+	assert.True(t, c.Pod == nil)
+}
+
+func TestKubelet(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"items": [{
+ "metadata": {"name": "web-1", "namespace": "default", "uid": "u1"},
+ "spec": {"nodeName": "node1", "containers": [
+    {"name": "nginx", "securityContext": {"privileged": true}}]},
+ "status": {"phase": "Running", "containerStatuses": [
+    {"name": "nginx", "containerID": "containerd://` + testId + `"}]}
+}]}`))
+		}))
+	defer server.Close()
+
+	dir, err := os.MkdirTemp("", "kubelet")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	token_file := filepath.Join(dir, "token")
+	writeFile(t, token_file, "secret\n")
+
+	pods, err := getKubeletPods(context.Background(), &KubeletPodsArgs{
+		Url:        server.URL + "/pods",
+		TokenFile:  token_file,
+		SkipVerify: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(pods.Items))
+	assert.Equal(t, "node1", pods.Items[0].Spec.NodeName)
+	assert.True(t, pods.Items[0].Spec.Containers[0].SecurityContext.Privileged)
+
+	runtime, id := splitContainerId(
+		pods.Items[0].Status.ContainerStatuses[0].ContainerID)
+	assert.Equal(t, "containerd", runtime)
+	assert.Equal(t, testId, id)
+
+	// Without credentials we do not try to connect.
+	_, err = getKubeletPods(context.Background(), &KubeletPodsArgs{
+		Url:      server.URL + "/pods",
+		CertFile: filepath.Join(dir, "missing.pem"),
+	})
+	assert.Equal(t, errNoCredentials, err)
+}
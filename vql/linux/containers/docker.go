@@ -0,0 +1,159 @@
+//go:build linux
+// +build linux
+
+package containers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+type dockerListEntry struct {
+	Id string
+}
+
+type dockerInspect struct {
+	Id      string
+	Name    string
+	Image   string
+	Created time.Time
+	Config  struct {
+		Image  string
+		Labels map[string]string
+	}
+	State struct {
+		Status string
+		Pid    int
+	}
+	Mounts []struct {
+		Type        string
+		Source      string
+		Destination string
+		RW          bool
+	}
+}
+
+type dockerImage struct {
+	RepoDigests []string
+}
+
+type dockerClient struct {
+	client *http.Client
+}
+
+func newDockerClient(socket string) *dockerClient {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	return &dockerClient{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
+
+func (self *dockerClient) get(ctx context.Context, path string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", "http://docker"+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := self.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("docker: %v: %v", path, resp.Status)
+	}
+
+	return json.Unmarshal(data, target)
+}
+
+// The repo digest (e.g. docker.io/library/nginx@sha256:...) identifies
+// the image in the registry while the image id is the digest of the
+// local image config.
+func getRepoDigest(image *dockerImage) string {
+	for _, digest := range image.RepoDigests {
+		idx := strings.Index(digest, "@")
+		if idx > 0 {
+			return digest[idx+1:]
+		}
+	}
+	return ""
+}
+
+func listDockerContainers(ctx context.Context, socket string) ([]*Container, error) {
+	client := newDockerClient(socket)
+
+	var list []*dockerListEntry
+	err := client.get(ctx, "/containers/json", &list)
+	if err != nil {
+		return nil, err
+	}
+
+	digests := make(map[string]string)
+
+	var result []*Container
+	for _, entry := range list {
+		inspect := &dockerInspect{}
+		err := client.get(ctx, "/containers/"+url.PathEscape(entry.Id)+"/json", inspect)
+		if err != nil {
+			continue
+		}
+
+		digest, pres := digests[inspect.Image]
+		if !pres {
+			image := &dockerImage{}
+			err := client.get(ctx, "/images/"+url.PathEscape(inspect.Image)+"/json", image)
+			if err == nil {
+				digest = getRepoDigest(image)
+			}
+			digests[inspect.Image] = digest
+		}
+
+		container := &Container{
+			Runtime:     "docker",
+			Id:          inspect.Id,
+			Name:        strings.TrimPrefix(inspect.Name, "/"),
+			Image:       inspect.Config.Image,
+			ImageId:     inspect.Image,
+			ImageDigest: digest,
+			State:       inspect.State.Status,
+			Created:     inspect.Created,
+			Pid:         inspect.State.Pid,
+			Labels:      inspect.Config.Labels,
+			Pod:         podFromLabels(inspect.Config.Labels),
+		}
+
+		for _, m := range inspect.Mounts {
+			container.Mounts = append(container.Mounts, &Mount{
+				Source:      m.Source,
+				Destination: m.Destination,
+				Type:        m.Type,
+				ReadOnly:    !m.RW,
+			})
+		}
+
+		result = append(result, container)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,277 @@
+//go:build linux
+// +build linux
+
+package containers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/json"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	DEFAULT_KUBELET_URL = "https://127.0.0.1:10250/pods"
+	DEFAULT_TOKEN_FILE  = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	DEFAULT_CERT_FILE   = "/var/lib/kubelet/pki/kubelet-client-current.pem"
+)
+
+var (
+	// The kubelet's serving certificate is either self signed or
+	// signed by the cluster CA.
+	defaultKubeletCAs = []string{
+		"/var/lib/kubelet/pki/kubelet.crt",
+		"/etc/kubernetes/pki/ca.crt",
+	}
+
+	errNoCredentials = errors.New("no kubelet credentials available")
+)
+
+type kubeletPodList struct {
+	Items []struct {
+		Metadata struct {
+			Name              string            `json:"name"`
+			Namespace         string            `json:"namespace"`
+			Uid               string            `json:"uid"`
+			CreationTimestamp time.Time         `json:"creationTimestamp"`
+			Labels            map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			NodeName           string `json:"nodeName"`
+			ServiceAccountName string `json:"serviceAccountName"`
+			HostNetwork        bool   `json:"hostNetwork"`
+			HostPID            bool   `json:"hostPID"`
+			Containers         []struct {
+				Name            string `json:"name"`
+				SecurityContext struct {
+					Privileged bool `json:"privileged"`
+				} `json:"securityContext"`
+			} `json:"containers"`
+		} `json:"spec"`
+		Status struct {
+			Phase             string `json:"phase"`
+			PodIP             string `json:"podIP"`
+			ContainerStatuses []struct {
+				Name         string `json:"name"`
+				Image        string `json:"image"`
+				ImageID      string `json:"imageID"`
+				ContainerID  string `json:"containerID"`
+				Ready        bool   `json:"ready"`
+				RestartCount int    `json:"restartCount"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+type KubeletPodsArgs struct {
+	Url        string `vfilter:"optional,field=url,doc=The kubelet pods endpoint (default https://127.0.0.1:10250/pods)"`
+	TokenFile  string `vfilter:"optional,field=token_file,doc=A file containing a bearer token (default the service account token)"`
+	CertFile   string `vfilter:"optional,field=cert_file,doc=A PEM file with a client certificate (default the kubelet client certificate)"`
+	KeyFile    string `vfilter:"optional,field=key_file,doc=A PEM file with the client key (default cert_file)"`
+	CAFile     string `vfilter:"optional,field=ca_file,doc=A PEM file with the CA to verify the kubelet"`
+	SkipVerify bool   `vfilter:"optional,field=skip_verify,doc=Do not verify the kubelet's certificate"`
+}
+
+func newKubeletClient(arg *KubeletPodsArgs) (*http.Client, string, error) {
+	tls_config := &tls.Config{
+		InsecureSkipVerify: arg.SkipVerify,
+	}
+
+	ca_files := defaultKubeletCAs
+	if arg.CAFile != "" {
+		ca_files = []string{arg.CAFile}
+	}
+
+	pool := x509.NewCertPool()
+	for _, ca_file := range ca_files {
+		data, err := os.ReadFile(ca_file)
+		if err == nil && pool.AppendCertsFromPEM(data) {
+			tls_config.RootCAs = pool
+		}
+	}
+
+	token := ""
+	if arg.TokenFile != "" || arg.CertFile == "" {
+		token_file := arg.TokenFile
+		if token_file == "" {
+			token_file = DEFAULT_TOKEN_FILE
+		}
+
+		data, err := os.ReadFile(token_file)
+		if err == nil {
+			token = strings.TrimSpace(string(data))
+		}
+	}
+
+	if token == "" {
+		cert_file := arg.CertFile
+		if cert_file == "" {
+			cert_file = DEFAULT_CERT_FILE
+		}
+
+		key_file := arg.KeyFile
+		if key_file == "" {
+			key_file = cert_file
+		}
+
+		cert, err := tls.LoadX509KeyPair(cert_file, key_file)
+		if err != nil {
+			return nil, "", errNoCredentials
+		}
+		tls_config.Certificates = []tls.Certificate{cert}
+	}
+
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: tls_config,
+		},
+	}, token, nil
+}
+
+func getKubeletPods(ctx context.Context, arg *KubeletPodsArgs) (*kubeletPodList, error) {
+	client, token, err := newKubeletClient(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", arg.Url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kubelet: %v", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 100*1024*1024))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &kubeletPodList{}
+	return result, json.Unmarshal(data, result)
+}
+
+// Container ids are given as <runtime>://<id>
+func splitContainerId(id string) (string, string) {
+	parts := strings.SplitN(id, "://", 2)
+	if len(parts) != 2 {
+		return "", id
+	}
+	return parts[0], parts[1]
+}
+
+type KubeletPodsPlugin struct{}
+
+func (self KubeletPodsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "kubelet_pods")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("kubelet_pods: %s", err)
+			return
+		}
+
+		arg := &KubeletPodsArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("kubelet_pods: %s", err.Error())
+			return
+		}
+
+		if arg.Url == "" {
+			arg.Url = DEFAULT_KUBELET_URL
+		}
+
+		pods, err := getKubeletPods(ctx, arg)
+		if err != nil {
+			scope.Log("kubelet_pods: %v", err)
+			return
+		}
+
+		for _, pod := range pods.Items {
+			privileged := make(map[string]bool)
+			for _, c := range pod.Spec.Containers {
+				privileged[c.Name] = c.SecurityContext.Privileged
+			}
+
+			containers := []*ordereddict.Dict{}
+			for _, c := range pod.Status.ContainerStatuses {
+				runtime, id := splitContainerId(c.ContainerID)
+				containers = append(containers, ordereddict.NewDict().
+					Set("Name", c.Name).
+					Set("Image", c.Image).
+					Set("ImageId", c.ImageID).
+					Set("ContainerId", id).
+					Set("Runtime", runtime).
+					Set("Ready", c.Ready).
+					Set("RestartCount", c.RestartCount).
+					Set("Privileged", privileged[c.Name]))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Namespace", pod.Metadata.Namespace).
+				Set("Name", pod.Metadata.Name).
+				Set("Uid", pod.Metadata.Uid).
+				Set("Node", pod.Spec.NodeName).
+				Set("ServiceAccount", pod.Spec.ServiceAccountName).
+				Set("Phase", pod.Status.Phase).
+				Set("PodIP", pod.Status.PodIP).
+				Set("HostNetwork", pod.Spec.HostNetwork).
+				Set("HostPID", pod.Spec.HostPID).
+				Set("Created", pod.Metadata.CreationTimestamp).
+				Set("Labels", pod.Metadata.Labels).
+				Set("Containers", containers):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self KubeletPodsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "kubelet_pods",
+		Doc:      "List the pods running on this node from the kubelet API.",
+		ArgType:  type_map.AddType(scope, &KubeletPodsArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&KubeletPodsPlugin{})
+}
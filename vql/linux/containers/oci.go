@@ -0,0 +1,203 @@
+//go:build linux
+// +build linux
+
+package containers
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+// The parts of the OCI runtime spec (config.json) we use.
+type ociSpec struct {
+	Hostname    string            `json:"hostname"`
+	Annotations map[string]string `json:"annotations"`
+	Mounts      []struct {
+		Destination string   `json:"destination"`
+		Type        string   `json:"type"`
+		Source      string   `json:"source"`
+		Options     []string `json:"options"`
+	} `json:"mounts"`
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
+
+func readPidFile(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
+
+func firstExisting(paths ...string) string {
+	for _, p := range paths {
+		_, err := os.Stat(p)
+		if err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// Build a container from an OCI bundle. Bundles of exited containers
+// may linger so only those with a live init process are returned.
+func parseBundle(opts *Options, runtime, id, config_path, pid_path string) *Container {
+	pid := readPidFile(pid_path)
+	if pid <= 0 {
+		return nil
+	}
+
+	_, err := os.Stat(filepath.Join(opts.Proc, itoa(pid)))
+	if err != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(config_path)
+	if err != nil {
+		return nil
+	}
+
+	spec := &ociSpec{}
+	err = json.Unmarshal(data, spec)
+	if err != nil {
+		return nil
+	}
+
+	annotations := spec.Annotations
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	container := &Container{
+		Runtime: runtime,
+		Id:      id,
+		Name:    spec.Hostname,
+		State:   "running",
+		Pid:     pid,
+		Labels:  annotations,
+		Pod:     podFromLabels(annotations),
+	}
+
+	// The config is written when the container is created.
+	stat, err := os.Stat(config_path)
+	if err == nil {
+		container.Created = stat.ModTime().UTC()
+	}
+
+	if container.Pod != nil && container.Pod.Container != "" {
+		container.Name = container.Pod.Container
+	}
+
+	switch runtime {
+	case "containerd":
+		container.Image = annotations["io.kubernetes.cri.image-name"]
+
+	case "cri-o":
+		container.Image = annotations["io.kubernetes.cri-o.ImageName"]
+		container.ImageId = annotations["io.kubernetes.cri-o.ImageRef"]
+
+		// Podman uses the same storage layout but does not
+		// annotate with CRI-O fields.
+		_, pres := annotations["io.kubernetes.cri-o.ContainerID"]
+		if !pres {
+			container.Runtime = "podman"
+		}
+	}
+
+	for _, m := range spec.Mounts {
+		read_only := false
+		for _, o := range m.Options {
+			if o == "ro" {
+				read_only = true
+			}
+		}
+
+		container.Mounts = append(container.Mounts, &Mount{
+			Source:      m.Source,
+			Destination: m.Destination,
+			Type:        m.Type,
+			ReadOnly:    read_only,
+		})
+	}
+
+	return container
+}
+
+func readDirNames(path string) []string {
+	var result []string
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			result = append(result, e.Name())
+		}
+	}
+	return result
+}
+
+func listBundleContainers(opts *Options, runtime string, roots []string) []*Container {
+	var result []*Container
+
+	switch runtime {
+
+	// containerd keeps bundles in <root>/<namespace>/<id>/
+	case "containerd":
+		for _, root := range roots {
+			for _, ns := range readDirNames(root) {
+				for _, id := range readDirNames(filepath.Join(root, ns)) {
+					dir := filepath.Join(root, ns, id)
+					c := parseBundle(opts, runtime, id,
+						filepath.Join(dir, "config.json"),
+						filepath.Join(dir, "init.pid"))
+					if c != nil {
+						c.Labels["io.containerd.namespace"] = ns
+						result = append(result, c)
+					}
+				}
+			}
+		}
+
+	// CRI-O (and podman) split the bundle between the run
+	// directory and the persistent storage directory:
+	// <root>/<id>/userdata/
+	default:
+		seen := make(map[string]bool)
+		for _, root := range roots {
+			for _, id := range readDirNames(root) {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+
+				var configs, pids []string
+				for _, r := range roots {
+					configs = append(configs,
+						filepath.Join(r, id, "userdata", "config.json"))
+					pids = append(pids,
+						filepath.Join(r, id, "userdata", "pidfile"))
+				}
+
+				c := parseBundle(opts, runtime, id,
+					firstExisting(configs...), firstExisting(pids...))
+				if c != nil {
+					result = append(result, c)
+				}
+			}
+		}
+	}
+
+	return result
+}
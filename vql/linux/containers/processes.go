@@ -0,0 +1,204 @@
+//go:build linux
+// +build linux
+
+package containers
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/psutils"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+var (
+	// All runtimes name the container's cgroup after the container
+	// id, e.g.:
+	// 0::/system.slice/docker-<id>.scope
+	// 0::/kubepods/burstable/pod<uid>/<id>
+	// 0::/kubepods.slice/.../cri-containerd-<id>.scope
+	containerIdRegex = regexp.MustCompile("[0-9a-f]{64}")
+)
+
+// Find the container id from /proc/<pid>/cgroup. Returns "" for
+// processes not in a container.
+func parseCgroupContainerId(reader io.Reader) string {
+	result := ""
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		matches := containerIdRegex.FindAllString(scanner.Text(), -1)
+		if len(matches) > 0 {
+			result = matches[len(matches)-1]
+		}
+	}
+	return result
+}
+
+// The pid of the process inside its own pid namespace is the last
+// entry of the NSpid line in /proc/<pid>/status
+func parseNSpid(reader io.Reader) int {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "NSpid:") {
+			continue
+		}
+
+		fields := strings.Fields(line[len("NSpid:"):])
+		if len(fields) == 0 {
+			return 0
+		}
+
+		pid, _ := strconv.Atoi(fields[len(fields)-1])
+		return pid
+	}
+	return 0
+}
+
+func getContainerId(proc string, pid int) string {
+	fd, err := os.Open(filepath.Join(proc, itoa(pid), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	defer fd.Close()
+
+	return parseCgroupContainerId(fd)
+}
+
+func getNSpid(proc string, pid int) int {
+	fd, err := os.Open(filepath.Join(proc, itoa(pid), "status"))
+	if err != nil {
+		return 0
+	}
+	defer fd.Close()
+
+	return parseNSpid(fd)
+}
+
+type ContainerProcessesArgs struct {
+	Id           string `vfilter:"optional,field=id,doc=Only show processes in this container (id, id prefix or name)."`
+	DockerSocket string `vfilter:"optional,field=docker_socket,doc=The path to the Docker API socket (default /var/run/docker.sock)"`
+}
+
+type ContainerProcessesPlugin struct{}
+
+func (self ContainerProcessesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "container_processes")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("container_processes: %s", err)
+			return
+		}
+
+		arg := &ContainerProcessesArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("container_processes: %s", err.Error())
+			return
+		}
+
+		opts := DefaultOptions()
+		if arg.DockerSocket != "" {
+			opts.DockerSocket = arg.DockerSocket
+		}
+
+		containers := ListContainers(ctx, opts)
+		by_id := make(map[string]*Container)
+		for _, c := range containers {
+			by_id[c.Id] = c
+		}
+
+		// Resolve the requested container to its id.
+		wanted := ""
+		if arg.Id != "" {
+			c := FindContainer(containers, arg.Id)
+			if c == nil {
+				scope.Log("container_processes: container %v not found", arg.Id)
+				return
+			}
+			wanted = c.Id
+		}
+
+		entries, err := os.ReadDir(opts.Proc)
+		if err != nil {
+			scope.Log("container_processes: %v", err)
+			return
+		}
+
+		for _, entry := range entries {
+			pid, err := strconv.Atoi(entry.Name())
+			if err != nil {
+				continue
+			}
+
+			id := getContainerId(opts.Proc, pid)
+			if id == "" || (wanted != "" && id != wanted) {
+				continue
+			}
+
+			row := ordereddict.NewDict().
+				Set("Pid", pid).
+				Set("ContainerPid", getNSpid(opts.Proc, pid))
+
+			info, err := psutils.GetProcess(ctx, int32(pid))
+			if err == nil {
+				for _, field := range []string{
+					"Ppid", "Name", "Exe", "CommandLine", "Username"} {
+					value, _ := info.Get(field)
+					row.Set(field, value)
+				}
+			}
+
+			row.Set("ContainerId", id)
+			c, pres := by_id[id]
+			if pres {
+				row.Set("ContainerName", c.Name).
+					Set("Runtime", c.Runtime).
+					Set("Image", c.Image)
+			} else {
+				row.Set("ContainerName", "").
+					Set("Runtime", "").
+					Set("Image", "")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self ContainerProcessesPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "container_processes",
+		Doc:      "Map host processes to the containers they run in.",
+		ArgType:  type_map.AddType(scope, &ContainerProcessesArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&ContainerProcessesPlugin{})
+}
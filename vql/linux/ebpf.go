@@ -0,0 +1,523 @@
+//go:build linux
+// +build linux
+
+package linux
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/Velocidex/ordereddict"
+	"golang.org/x/sys/unix"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/psutils"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+// Commands from linux/bpf.h
+const (
+	BPF_OBJ_GET            = 7
+	BPF_PROG_GET_NEXT_ID   = 11
+	BPF_MAP_GET_NEXT_ID    = 12
+	BPF_PROG_GET_FD_BY_ID  = 13
+	BPF_MAP_GET_FD_BY_ID   = 14
+	BPF_OBJ_GET_INFO_BY_FD = 15
+
+	BPF_F_RDONLY = 1 << 3
+)
+
+var bpfProgTypes = []string{
+	"unspec", "socket_filter", "kprobe", "sched_cls", "sched_act",
+	"tracepoint", "xdp", "perf_event", "cgroup_skb", "cgroup_sock",
+	"lwt_in", "lwt_out", "lwt_xmit", "sock_ops", "sk_skb",
+	"cgroup_device", "sk_msg", "raw_tracepoint", "cgroup_sock_addr",
+	"lwt_seg6local", "lirc_mode2", "sk_reuseport", "flow_dissector",
+	"cgroup_sysctl", "raw_tracepoint_writable", "cgroup_sockopt",
+	"tracing", "struct_ops", "ext", "lsm", "sk_lookup", "syscall",
+	"netfilter",
+}
+
+var bpfMapTypes = []string{
+	"unspec", "hash", "array", "prog_array", "perf_event_array",
+	"percpu_hash", "percpu_array", "stack_trace", "cgroup_array",
+	"lru_hash", "lru_percpu_hash", "lpm_trie", "array_of_maps",
+	"hash_of_maps", "devmap", "sockmap", "cpumap", "xskmap", "sockhash",
+	"cgroup_storage", "reuseport_sockarray", "percpu_cgroup_storage",
+	"queue", "stack", "sk_storage", "devmap_hash", "struct_ops",
+	"ringbuf", "inode_storage", "task_storage", "bloom_filter",
+	"user_ringbuf", "cgrp_storage",
+}
+
+func bpfTypeName(names []string, t uint32) string {
+	if int(t) < len(names) {
+		return names[t]
+	}
+	return strconv.Itoa(int(t))
+}
+
+// struct bpf_prog_info (the prefix we need)
+type bpfProgInfo struct {
+	Type            uint32
+	Id              uint32
+	Tag             [8]byte
+	JitedProgLen    uint32
+	XlatedProgLen   uint32
+	JitedProgInsns  uint64
+	XlatedProgInsns uint64
+	LoadTime        uint64
+	CreatedByUid    uint32
+	NrMapIds        uint32
+	MapIds          uint64
+	Name            [16]byte
+	Ifindex         uint32
+	GplCompatible   uint32
+	NetnsDev        uint64
+	NetnsIno        uint64
+}
+
+// struct bpf_map_info (the prefix we need)
+type bpfMapInfo struct {
+	Type       uint32
+	Id         uint32
+	KeySize    uint32
+	ValueSize  uint32
+	MaxEntries uint32
+	MapFlags   uint32
+	Name       [16]byte
+	Ifindex    uint32
+}
+
+// The union bpf_attr for the commands we use.
+type bpfIdAttr struct {
+	Id        uint32
+	NextId    uint32
+	OpenFlags uint32
+}
+
+type bpfInfoAttr struct {
+	Fd      uint32
+	InfoLen uint32
+	Info    uint64
+}
+
+type bpfObjGetAttr struct {
+	Pathname  uint64
+	Fd        uint32
+	FileFlags uint32
+}
+
+func bpf(cmd int, attr unsafe.Pointer, size uintptr) (uintptr, error) {
+	r1, _, errno := unix.Syscall(unix.SYS_BPF, uintptr(cmd),
+		uintptr(attr), size)
+	if errno != 0 {
+		return 0, errno
+	}
+	return r1, nil
+}
+
+// Walk the ids of all programs or maps
+func bpfGetIds(cmd int) []uint32 {
+	var result []uint32
+
+	attr := bpfIdAttr{}
+	for {
+		_, err := bpf(cmd, unsafe.Pointer(&attr), unsafe.Sizeof(attr))
+		if err != nil {
+			return result
+		}
+		result = append(result, attr.NextId)
+		attr.Id = attr.NextId
+	}
+}
+
+func bpfGetFdById(cmd int, id uint32) (int, error) {
+	attr := bpfIdAttr{Id: id}
+	if cmd == BPF_MAP_GET_FD_BY_ID {
+		attr.OpenFlags = BPF_F_RDONLY
+	}
+	fd, err := bpf(cmd, unsafe.Pointer(&attr), unsafe.Sizeof(attr))
+	return int(fd), err
+}
+
+func bpfGetInfo(fd int, info unsafe.Pointer, size uintptr) error {
+	attr := bpfInfoAttr{
+		Fd:      uint32(fd),
+		InfoLen: uint32(size),
+		Info:    uint64(uintptr(info)),
+	}
+	_, err := bpf(BPF_OBJ_GET_INFO_BY_FD, unsafe.Pointer(&attr),
+		unsafe.Sizeof(attr))
+	return err
+}
+
+func getBpfProgInfo(id uint32) (*bpfProgInfo, []uint32, error) {
+	fd, err := bpfGetFdById(BPF_PROG_GET_FD_BY_ID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unix.Close(fd)
+
+	info := &bpfProgInfo{}
+	err = bpfGetInfo(fd, unsafe.Pointer(info), unsafe.Sizeof(*info))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Fetch the map ids with a second call now we know how many.
+	map_ids := make([]uint32, info.NrMapIds)
+	if len(map_ids) > 0 {
+		second := &bpfProgInfo{
+			NrMapIds: info.NrMapIds,
+			MapIds:   uint64(uintptr(unsafe.Pointer(&map_ids[0]))),
+		}
+		err = bpfGetInfo(fd, unsafe.Pointer(second), unsafe.Sizeof(*second))
+		if err != nil {
+			map_ids = nil
+		}
+	}
+
+	return info, map_ids, nil
+}
+
+func getBpfMapInfo(id uint32) (*bpfMapInfo, error) {
+	fd, err := bpfGetFdById(BPF_MAP_GET_FD_BY_ID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer unix.Close(fd)
+
+	info := &bpfMapInfo{}
+	err = bpfGetInfo(fd, unsafe.Pointer(info), unsafe.Sizeof(*info))
+	return info, err
+}
+
+// Parse the prog_id or map_id from a bpf fdinfo file.
+func parseBpfFdInfo(reader io.Reader) (kind string, id uint32) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(parts[0])
+		if key != "prog_id" && key != "map_id" {
+			continue
+		}
+
+		value, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 32)
+		if err == nil {
+			return key, uint32(value)
+		}
+	}
+	return "", 0
+}
+
+func bpfObjectKey(kind string, id uint32) string {
+	return kind + ":" + strconv.Itoa(int(id))
+}
+
+// Find the processes holding bpf objects open by scanning
+// /proc/*/fd for bpf anonymous inodes.
+func getBpfOwners(proc string) map[string][]int32 {
+	result := make(map[string][]int32)
+
+	procs, err := os.ReadDir(proc)
+	if err != nil {
+		return result
+	}
+
+	for _, p := range procs {
+		pid, err := strconv.ParseInt(p.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		fd_dir := filepath.Join(proc, p.Name(), "fd")
+		fds, err := os.ReadDir(fd_dir)
+		if err != nil {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fd_dir, fd.Name()))
+			if err != nil || !strings.HasPrefix(target, "anon_inode:bpf-") {
+				continue
+			}
+
+			info, err := os.Open(filepath.Join(proc, p.Name(), "fdinfo", fd.Name()))
+			if err != nil {
+				continue
+			}
+			kind, id := parseBpfFdInfo(info)
+			info.Close()
+
+			key := bpfObjectKey(kind, id)
+			if kind == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			result[key] = append(result[key], int32(pid))
+		}
+	}
+
+	return result
+}
+
+// Objects pinned in the bpf filesystem survive the process that
+// loaded them.
+func getBpfPinnedPaths(root string) map[string][]string {
+	result := make(map[string][]string)
+
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+
+		path_bytes, err := unix.BytePtrFromString(path)
+		if err != nil {
+			return nil
+		}
+
+		attr := bpfObjGetAttr{
+			Pathname:  uint64(uintptr(unsafe.Pointer(path_bytes))),
+			FileFlags: BPF_F_RDONLY,
+		}
+		fd, err := bpf(BPF_OBJ_GET, unsafe.Pointer(&attr), unsafe.Sizeof(attr))
+		runtime.KeepAlive(path_bytes)
+		if err != nil {
+			return nil
+		}
+		defer unix.Close(int(fd))
+
+		info, err := os.Open(filepath.Join("/proc/self/fdinfo",
+			strconv.Itoa(int(fd))))
+		if err != nil {
+			return nil
+		}
+		defer info.Close()
+
+		kind, id := parseBpfFdInfo(info)
+		if kind != "" {
+			key := bpfObjectKey(kind, id)
+			result[key] = append(result[key], path)
+		}
+		return nil
+	})
+
+	return result
+}
+
+func getProcesses(ctx context.Context, pids []int32,
+	cache map[int32]*ordereddict.Dict) []*ordereddict.Dict {
+	result := []*ordereddict.Dict{}
+	for _, pid := range pids {
+		info, pres := cache[pid]
+		if !pres {
+			info = ordereddict.NewDict().Set("Pid", pid)
+			process, err := psutils.GetProcess(ctx, pid)
+			if err == nil {
+				for _, field := range []string{"Name", "Exe", "CommandLine", "Username"} {
+					value, _ := process.Get(field)
+					info.Set(field, value)
+				}
+			}
+			cache[pid] = info
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
+// The load time is given in nanoseconds since boot.
+func getBootTime() time.Time {
+	ts := &unix.Timespec{}
+	err := unix.ClockGettime(unix.CLOCK_BOOTTIME, ts)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Now().Add(-time.Duration(ts.Nano()))
+}
+
+type EBPFArgs struct {
+	BpfFS string `vfilter:"optional,field=bpffs,doc=Where the bpf filesystem is mounted (default /sys/fs/bpf)"`
+}
+
+type EBPFProgramsPlugin struct{}
+
+func (self EBPFProgramsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "ebpf_programs")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("ebpf_programs: %s", err)
+			return
+		}
+
+		arg := &EBPFArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("ebpf_programs: %s", err.Error())
+			return
+		}
+
+		if arg.BpfFS == "" {
+			arg.BpfFS = "/sys/fs/bpf"
+		}
+
+		ids := bpfGetIds(BPF_PROG_GET_NEXT_ID)
+		if len(ids) == 0 {
+			return
+		}
+
+		boot_time := getBootTime()
+		owners := getBpfOwners("/proc")
+		pinned := getBpfPinnedPaths(arg.BpfFS)
+		cache := make(map[int32]*ordereddict.Dict)
+
+		for _, id := range ids {
+			info, map_ids, err := getBpfProgInfo(id)
+			if err != nil {
+				continue
+			}
+
+			key := bpfObjectKey("prog_id", id)
+			pinned_paths := pinned[key]
+			if pinned_paths == nil {
+				pinned_paths = []string{}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Id", info.Id).
+				Set("Name", unix.ByteSliceToString(info.Name[:])).
+				Set("Type", bpfTypeName(bpfProgTypes, info.Type)).
+				Set("Tag", hex.EncodeToString(info.Tag[:])).
+				Set("LoadTime", boot_time.Add(time.Duration(info.LoadTime)).UTC()).
+				Set("CreatedByUid", info.CreatedByUid).
+				Set("GplCompatible", info.GplCompatible&1 != 0).
+				Set("Size", info.XlatedProgLen).
+				Set("MapIds", map_ids).
+				Set("PinnedPaths", pinned_paths).
+				Set("Processes", getProcesses(ctx, owners[key], cache)):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self EBPFProgramsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "ebpf_programs",
+		Doc:      "List the eBPF programs loaded into the kernel.",
+		ArgType:  type_map.AddType(scope, &EBPFArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+type EBPFMapsPlugin struct{}
+
+func (self EBPFMapsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "ebpf_maps")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("ebpf_maps: %s", err)
+			return
+		}
+
+		arg := &EBPFArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("ebpf_maps: %s", err.Error())
+			return
+		}
+
+		if arg.BpfFS == "" {
+			arg.BpfFS = "/sys/fs/bpf"
+		}
+
+		ids := bpfGetIds(BPF_MAP_GET_NEXT_ID)
+		if len(ids) == 0 {
+			return
+		}
+
+		owners := getBpfOwners("/proc")
+		pinned := getBpfPinnedPaths(arg.BpfFS)
+		cache := make(map[int32]*ordereddict.Dict)
+
+		for _, id := range ids {
+			info, err := getBpfMapInfo(id)
+			if err != nil {
+				continue
+			}
+
+			key := bpfObjectKey("map_id", id)
+			pinned_paths := pinned[key]
+			if pinned_paths == nil {
+				pinned_paths = []string{}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Id", info.Id).
+				Set("Name", unix.ByteSliceToString(info.Name[:])).
+				Set("Type", bpfTypeName(bpfMapTypes, info.Type)).
+				Set("KeySize", info.KeySize).
+				Set("ValueSize", info.ValueSize).
+				Set("MaxEntries", info.MaxEntries).
+				Set("Flags", info.MapFlags).
+				Set("PinnedPaths", pinned_paths).
+				Set("Processes", getProcesses(ctx, owners[key], cache)):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self EBPFMapsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "ebpf_maps",
+		Doc:      "List the eBPF maps in the kernel.",
+		ArgType:  type_map.AddType(scope, &EBPFArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&EBPFProgramsPlugin{})
+	vql_subsystem.RegisterPlugin(&EBPFMapsPlugin{})
+}
@@ -0,0 +1,68 @@
+//go:build linux
+// +build linux
+
+package linux
+
+import (
+	"strings"
+	"testing"
+
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestParseProcModules(t *testing.T) {
+	modules := parseProcModules(strings.NewReader(
+		`nf_nat 49152 2 nft_chain_nat,xt_MASQUERADE, Live 0xffffffffc0a1e000
+rootkit 16384 0 - Loading 0x0000000000000000 (OE)
+`))
+	assert.Equal(t, 2, len(modules))
+	assert.Equal(t, "nf_nat", modules[0].Name)
+	assert.Equal(t, uint64(49152), modules[0].Size)
+	assert.Equal(t, []string{"nft_chain_nat", "xt_MASQUERADE"}, modules[0].UsedBy)
+	assert.Equal(t, "0xffffffffc0a1e000", modules[0].Address)
+
+	assert.Equal(t, []string{}, modules[1].UsedBy)
+	assert.Equal(t, "Loading", modules[1].State)
+
+	assert.Equal(t, []string{"OOT_MODULE", "UNSIGNED_MODULE"},
+		parseTaintFlags("OE\n"))
+
+	paths := parseModulesDep(strings.NewReader(
+		`kernel/fs/ext4/ext4.ko.zst: kernel/fs/jbd2/jbd2.ko.zst
+kernel/drivers/hid/hid-generic.ko:
+`), "/lib/modules/6.1.0")
+	assert.Equal(t, "/lib/modules/6.1.0/kernel/fs/ext4/ext4.ko.zst", paths["ext4"])
+	assert.Equal(t, "/lib/modules/6.1.0/kernel/drivers/hid/hid-generic.ko",
+		paths[normalizeModuleName("hid_generic")])
+}
+
+func TestParseBpfFdInfo(t *testing.T) {
+	kind, id := parseBpfFdInfo(strings.NewReader(`pos:	0
+flags:	02000000
+mnt_id:	15
+prog_type:	8
+prog_jited:	1
+prog_tag:	3b185187f1855c4c
+memlock:	4096
+prog_id:	42
+`))
+	assert.Equal(t, "prog_id", kind)
+	assert.Equal(t, uint32(42), id)
+}
+
+func TestParsePreload(t *testing.T) {
+	libraries := parsePreloadFile(strings.NewReader(
+		"# comment\n/lib/libevil.so /usr/lib/libtwo.so\n/lib/libthree.so # trailing\n"))
+	assert.Equal(t, []string{
+		"/lib/libevil.so", "/usr/lib/libtwo.so", "/lib/libthree.so"}, libraries)
+
+	env := parsePreloadEnvironment([]byte(
+		"HOME=/root\x00LD_PRELOAD=/tmp/a.so:/tmp/b.so\x00"))
+	assert.Equal(t, "/tmp/a.so:/tmp/b.so", env["LD_PRELOAD"])
+	assert.Equal(t, []string{"/tmp/a.so", "/tmp/b.so"},
+		splitPreloadLibraries(env["LD_PRELOAD"]))
+
+	env = parsePreloadEnvironment([]byte(
+		"PATH=\"/usr/bin\"\nexport LD_AUDIT=\"/opt/audit.so\"\n"))
+	assert.Equal(t, "/opt/audit.so", env["LD_AUDIT"])
+}
@@ -0,0 +1,272 @@
+//go:build linux
+// +build linux
+
+package linux
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"golang.org/x/sys/unix"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+// Module taint flags as shown in /sys/module/*/taint. See
+// Documentation/admin-guide/tainted-kernels.rst
+var moduleTaintFlags = map[rune]string{
+	'P': "PROPRIETARY_MODULE",
+	'F': "FORCED_MODULE",
+	'C': "CRAP",
+	'O': "OOT_MODULE",
+	'E': "UNSIGNED_MODULE",
+	'K': "LIVEPATCH",
+	'X': "AUX",
+	'T': "RANDSTRUCT",
+}
+
+type kernelModule struct {
+	Name     string
+	Size     uint64
+	UseCount int64
+	UsedBy   []string
+	State    string
+	Address  string
+}
+
+/*
+nf_nat 49152 2 nft_chain_nat,xt_MASQUERADE, Live 0xffffffffc0a1e000
+*/
+func parseProcModules(reader io.Reader) []*kernelModule {
+	var result []*kernelModule
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		size, _ := strconv.ParseUint(fields[1], 10, 64)
+		use_count, _ := strconv.ParseInt(fields[2], 10, 64)
+
+		module := &kernelModule{
+			Name:     fields[0],
+			Size:     size,
+			UseCount: use_count,
+			UsedBy:   []string{},
+			State:    fields[4],
+		}
+
+		for _, user := range strings.Split(fields[3], ",") {
+			if user != "" && user != "-" {
+				module.UsedBy = append(module.UsedBy, user)
+			}
+		}
+
+		if len(fields) > 5 {
+			module.Address = fields[5]
+		}
+
+		result = append(result, module)
+	}
+
+	return result
+}
+
+func parseTaintFlags(flags string) []string {
+	result := []string{}
+	for _, c := range strings.TrimSpace(flags) {
+		name, pres := moduleTaintFlags[c]
+		if !pres {
+			name = string(c)
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+// Module names use _ and - interchangeably.
+func normalizeModuleName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// Map module names to their path from modules.dep. Modules not
+// listed there were loaded from outside the module tree
+// (e.g. insmod of an arbitrary file).
+func parseModulesDep(reader io.Reader, base string) map[string]string {
+	result := make(map[string]string)
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		idx := strings.Index(line, ":")
+		if idx < 0 {
+			continue
+		}
+
+		path := line[:idx]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(base, path)
+		}
+
+		// Strip extensions like .ko.xz or .ko.zst
+		name := filepath.Base(path)
+		idx = strings.Index(name, ".ko")
+		if idx > 0 {
+			name = name[:idx]
+		}
+		result[normalizeModuleName(name)] = path
+	}
+
+	return result
+}
+
+type KernelModulesArgs struct {
+	ProcModules string `vfilter:"optional,field=proc_modules,doc=The path to /proc/modules"`
+	SysModule   string `vfilter:"optional,field=sys_module,doc=The path to /sys/module"`
+	ModulesDir  string `vfilter:"optional,field=modules_dir,doc=The directory holding modules.dep (default /lib/modules/<release>)"`
+}
+
+type KernelModulesPlugin struct{}
+
+func (self KernelModulesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "kernel_modules")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("kernel_modules: %s", err)
+			return
+		}
+
+		arg := &KernelModulesArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("kernel_modules: %s", err.Error())
+			return
+		}
+
+		if arg.ProcModules == "" {
+			arg.ProcModules = "/proc/modules"
+		}
+
+		if arg.SysModule == "" {
+			arg.SysModule = "/sys/module"
+		}
+
+		if arg.ModulesDir == "" {
+			uname := &unix.Utsname{}
+			err = unix.Uname(uname)
+			if err == nil {
+				arg.ModulesDir = filepath.Join("/lib/modules",
+					unix.ByteSliceToString(uname.Release[:]))
+			}
+		}
+
+		fd, err := os.Open(arg.ProcModules)
+		if err != nil {
+			scope.Log("kernel_modules: %v", err)
+			return
+		}
+		modules := parseProcModules(fd)
+		fd.Close()
+
+		paths := make(map[string]string)
+		dep_fd, err := os.Open(filepath.Join(arg.ModulesDir, "modules.dep"))
+		if err == nil {
+			paths = parseModulesDep(dep_fd, arg.ModulesDir)
+			dep_fd.Close()
+		}
+
+		seen := make(map[string]bool)
+		for _, module := range modules {
+			seen[module.Name] = true
+			taint, _ := os.ReadFile(
+				filepath.Join(arg.SysModule, module.Name, "taint"))
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Name", module.Name).
+				Set("Size", module.Size).
+				Set("UseCount", module.UseCount).
+				Set("UsedBy", module.UsedBy).
+				Set("State", module.State).
+				Set("Address", module.Address).
+				Set("Path", paths[normalizeModuleName(module.Name)]).
+				Set("Taint", parseTaintFlags(string(taint))).
+				Set("Hidden", false):
+			}
+		}
+
+		// Loadable modules always have an initstate file in
+		// /sys/module. A module which appears there but not in
+		// /proc/modules was removed from the module list (a common
+		// rootkit technique).
+		entries, err := os.ReadDir(arg.SysModule)
+		if err != nil {
+			return
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if seen[name] {
+				continue
+			}
+
+			state, err := os.ReadFile(
+				filepath.Join(arg.SysModule, name, "initstate"))
+			if err != nil {
+				continue
+			}
+
+			taint, _ := os.ReadFile(filepath.Join(arg.SysModule, name, "taint"))
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Name", name).
+				Set("Size", uint64(0)).
+				Set("UseCount", int64(0)).
+				Set("UsedBy", []string{}).
+				Set("State", strings.TrimSpace(string(state))).
+				Set("Address", "").
+				Set("Path", paths[normalizeModuleName(name)]).
+				Set("Taint", parseTaintFlags(string(taint))).
+				Set("Hidden", true):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self KernelModulesPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "kernel_modules",
+		Doc:      "Enumerate the modules loaded into the kernel.",
+		ArgType:  type_map.AddType(scope, &KernelModulesArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&KernelModulesPlugin{})
+}
@@ -0,0 +1,214 @@
+//go:build linux
+// +build linux
+
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+// Environment variables which cause the dynamic loader to load
+// additional libraries into a process.
+var preloadVariables = []string{"LD_PRELOAD", "LD_AUDIT"}
+
+// Libraries are separated by white space or colons. Comments are
+// allowed in ld.so.preload.
+func splitPreloadLibraries(value string) []string {
+	result := []string{}
+	for _, item := range strings.FieldsFunc(value, func(c rune) bool {
+		return c == ':' || c == ' ' || c == '\t' || c == '\n'
+	}) {
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func parsePreloadFile(reader io.Reader) []string {
+	result := []string{}
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		idx := strings.Index(line, "#")
+		if idx >= 0 {
+			line = line[:idx]
+		}
+		result = append(result, splitPreloadLibraries(line)...)
+	}
+
+	return result
+}
+
+// Parse /proc/<pid>/environ (NUL separated) or /etc/environment
+// (newline separated, optionally quoted) for preload variables.
+func parsePreloadEnvironment(data []byte) map[string]string {
+	result := make(map[string]string)
+
+	sep := []byte{0}
+	if !bytes.Contains(data, sep) {
+		sep = []byte{'\n'}
+	}
+
+	for _, item := range bytes.Split(data, sep) {
+		line := strings.TrimSpace(string(item))
+		line = strings.TrimPrefix(line, "export ")
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		for _, name := range preloadVariables {
+			if parts[0] == name {
+				result[name] = strings.Trim(parts[1], `"'`)
+			}
+		}
+	}
+
+	return result
+}
+
+type PreloadHooksArgs struct {
+	PreloadFile     string `vfilter:"optional,field=preload_file,doc=The path to ld.so.preload (default /etc/ld.so.preload)"`
+	EnvironmentFile string `vfilter:"optional,field=environment_file,doc=The path to the system environment file (default /etc/environment)"`
+	Proc            string `vfilter:"optional,field=proc,doc=The path to /proc (default /proc)"`
+}
+
+type PreloadHooksPlugin struct{}
+
+func (self PreloadHooksPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "preload_hooks")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("preload_hooks: %s", err)
+			return
+		}
+
+		arg := &PreloadHooksArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("preload_hooks: %s", err.Error())
+			return
+		}
+
+		if arg.PreloadFile == "" {
+			arg.PreloadFile = "/etc/ld.so.preload"
+		}
+
+		if arg.EnvironmentFile == "" {
+			arg.EnvironmentFile = "/etc/environment"
+		}
+
+		if arg.Proc == "" {
+			arg.Proc = "/proc"
+		}
+
+		emit := func(source, variable, library string,
+			process *ordereddict.Dict) bool {
+			_, err := os.Stat(library)
+
+			select {
+			case <-ctx.Done():
+				return false
+			case output_chan <- ordereddict.NewDict().
+				Set("Source", source).
+				Set("Variable", variable).
+				Set("Library", library).
+				Set("Exists", err == nil).
+				Set("Process", process):
+			}
+			return true
+		}
+
+		fd, err := os.Open(arg.PreloadFile)
+		if err == nil {
+			libraries := parsePreloadFile(fd)
+			fd.Close()
+
+			for _, library := range libraries {
+				if !emit(arg.PreloadFile, "", library, nil) {
+					return
+				}
+			}
+		}
+
+		data, err := os.ReadFile(arg.EnvironmentFile)
+		if err == nil {
+			env := parsePreloadEnvironment(data)
+			for _, name := range preloadVariables {
+				for _, library := range splitPreloadLibraries(env[name]) {
+					if !emit(arg.EnvironmentFile, name, library, nil) {
+						return
+					}
+				}
+			}
+		}
+
+		procs, err := os.ReadDir(arg.Proc)
+		if err != nil {
+			return
+		}
+
+		cache := make(map[int32]*ordereddict.Dict)
+		for _, p := range procs {
+			pid, err := strconv.ParseInt(p.Name(), 10, 32)
+			if err != nil {
+				continue
+			}
+
+			data, err := os.ReadFile(filepath.Join(arg.Proc, p.Name(), "environ"))
+			if err != nil {
+				continue
+			}
+
+			env := parsePreloadEnvironment(data)
+			for _, name := range preloadVariables {
+				for _, library := range splitPreloadLibraries(env[name]) {
+					process := getProcesses(
+						ctx, []int32{int32(pid)}, cache)[0]
+					if !emit("Environment", name, library, process) {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self PreloadHooksPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "preload_hooks",
+		Doc:      "Find libraries preloaded into processes by the dynamic loader.",
+		ArgType:  type_map.AddType(scope, &PreloadHooksArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&PreloadHooksPlugin{})
+}
@@ -0,0 +1,363 @@
+// Inspect the Kerberos ticket caches and the security packages loaded
+// into the LSA.
+
+// The ticket caches of all logon sessions are queried from the
+// Kerberos authentication package, which is equivalent to running
+// klist for every session but does not spawn any external tools. Each
+// ticket is checked for signs of forgery or downgrade such as
+// unusually long lived TGTs and RC4 encryption.
+
+package lsa
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	SYSTEM_LOGON_ID          = 0x3e7
+	NETWORK_SERVICE_LOGON_ID = 0x3e4
+)
+
+var (
+	encryptionTypes = map[int32]string{
+		1:  "des-cbc-crc",
+		3:  "des-cbc-md5",
+		17: "aes128-cts-hmac-sha1-96",
+		18: "aes256-cts-hmac-sha1-96",
+		23: "rc4-hmac",
+		24: "rc4-hmac-exp",
+	}
+
+	ticketFlags = []struct {
+		mask uint32
+		name string
+	}{
+		{0x40000000, "forwardable"},
+		{0x20000000, "forwarded"},
+		{0x10000000, "proxiable"},
+		{0x08000000, "proxy"},
+		{0x04000000, "may_postdate"},
+		{0x02000000, "postdated"},
+		{0x01000000, "invalid"},
+		{0x00800000, "renewable"},
+		{0x00400000, "initial"},
+		{0x00200000, "pre_authent"},
+		{0x00100000, "hw_authent"},
+		{0x00040000, "ok_as_delegate"},
+		{0x00010000, "name_canonicalize"},
+	}
+
+	logonTypes = map[uint32]string{
+		0:  "System",
+		2:  "Interactive",
+		3:  "Network",
+		4:  "Batch",
+		5:  "Service",
+		7:  "Unlock",
+		8:  "NetworkCleartext",
+		9:  "NewCredentials",
+		10: "RemoteInteractive",
+		11: "CachedInteractive",
+	}
+
+	// Security packages shipped with Windows. Any other package
+	// loaded into the LSA may be a malicious SSP (e.g. mimilib).
+	builtinPackages = map[string]bool{
+		"negotiate":       true,
+		"negoextender":    true,
+		"kerberos":        true,
+		"ntlm":            true,
+		"tsssp":           true,
+		"pku2u":           true,
+		"cloudap":         true,
+		"wdigest":         true,
+		"schannel":        true,
+		"default tls ssp": true,
+		"credssp":         true,
+		"pwdssp":          true,
+		"livessp":         true,
+		"microsoft unified security protocol provider": true,
+	}
+)
+
+func EncryptionTypeName(etype int32) string {
+	name, pres := encryptionTypes[etype]
+	if !pres {
+		return fmt.Sprintf("%d", etype)
+	}
+	return name
+}
+
+func TicketFlagNames(flags uint32) []string {
+	result := []string{}
+	for _, flag := range ticketFlags {
+		if flags&flag.mask != 0 {
+			result = append(result, flag.name)
+		}
+	}
+	return result
+}
+
+func LogonTypeName(logon_type uint32) string {
+	name, pres := logonTypes[logon_type]
+	if !pres {
+		return fmt.Sprintf("%d", logon_type)
+	}
+	return name
+}
+
+func IsBuiltinPackage(name string) bool {
+	return builtinPackages[strings.ToLower(name)]
+}
+
+// Kerberos times are FILETIMEs where the largest value means never.
+func FiletimeToTime(filetime int64) time.Time {
+	if filetime <= 116444736000000000 || filetime == 0x7fffffffffffffff {
+		return time.Time{}
+	}
+	return time.Unix(0, (filetime-116444736000000000)*100).UTC()
+}
+
+type LogonSession struct {
+	LogonId               uint64
+	UserName              string
+	Domain                string
+	DnsDomain             string
+	Upn                   string
+	AuthenticationPackage string
+	LogonType             uint32
+	Session               uint32
+	Sid                   string
+	LogonTime             time.Time
+}
+
+type KerberosTicket struct {
+	LogonId        string
+	UserName       string
+	LogonType      string
+	ClientName     string
+	ClientRealm    string
+	ServerName     string
+	ServerRealm    string
+	StartTime      time.Time
+	EndTime        time.Time
+	RenewTime      time.Time
+	EncryptionType string
+	SessionKeyType string
+	TicketFlags    []string
+	Anomalies      []string
+
+	session         *LogonSession
+	encryption_type int32
+}
+
+func NewKerberosTicket(session *LogonSession,
+	client_name, client_realm, server_name, server_realm string,
+	start_time, end_time, renew_time time.Time,
+	encryption_type, session_key_type int32, flags uint32) *KerberosTicket {
+	user_name := session.UserName
+	if session.Domain != "" {
+		user_name = session.Domain + "\\" + user_name
+	}
+
+	return &KerberosTicket{
+		LogonId:         fmt.Sprintf("0x%x", session.LogonId),
+		UserName:        user_name,
+		LogonType:       LogonTypeName(session.LogonType),
+		ClientName:      client_name,
+		ClientRealm:     client_realm,
+		ServerName:      server_name,
+		ServerRealm:     server_realm,
+		StartTime:       start_time,
+		EndTime:         end_time,
+		RenewTime:       renew_time,
+		EncryptionType:  EncryptionTypeName(encryption_type),
+		SessionKeyType:  EncryptionTypeName(session_key_type),
+		TicketFlags:     TicketFlagNames(flags),
+		Anomalies:       []string{},
+		session:         session,
+		encryption_type: encryption_type,
+	}
+}
+
+func (self *KerberosTicket) IsTGT() bool {
+	return strings.HasPrefix(strings.ToLower(self.ServerName), "krbtgt/")
+}
+
+type AnalysisOptions struct {
+	MaxTGTLifetime   time.Duration
+	MaxRenewLifetime time.Duration
+}
+
+// Flag properties of the ticket which are unusual for tickets issued
+// by a Windows KDC with the default policy. Forged (golden and silver)
+// tickets are typically minted with a lifetime of years, with RC4
+// keys and sometimes for a client which does not match the logon
+// session they are injected into.
+func (self *KerberosTicket) Analyze(options AnalysisOptions, now time.Time) {
+	session := self.session
+	switch self.encryption_type {
+	case 23, 24:
+		self.Anomalies = append(self.Anomalies, "RC4 encrypted ticket")
+	case 1, 3:
+		self.Anomalies = append(self.Anomalies, "DES encrypted ticket")
+	}
+
+	if self.IsTGT() && !self.StartTime.IsZero() {
+		if options.MaxTGTLifetime > 0 && !self.EndTime.IsZero() &&
+			self.EndTime.Sub(self.StartTime) > options.MaxTGTLifetime {
+			self.Anomalies = append(self.Anomalies, fmt.Sprintf(
+				"TGT lifetime exceeds %v", options.MaxTGTLifetime))
+		}
+
+		if options.MaxRenewLifetime > 0 && !self.RenewTime.IsZero() &&
+			self.RenewTime.Sub(self.StartTime) > options.MaxRenewLifetime {
+			self.Anomalies = append(self.Anomalies, fmt.Sprintf(
+				"TGT renewal exceeds %v", options.MaxRenewLifetime))
+		}
+
+		// A Windows KDC always issues realms as uppercase DNS names.
+		if self.ClientRealm != strings.ToUpper(self.ClientRealm) {
+			self.Anomalies = append(self.Anomalies, "Realm is not uppercase")
+		}
+
+		if session.DnsDomain != "" && self.ClientRealm != "" &&
+			!strings.EqualFold(session.DnsDomain, self.ClientRealm) {
+			self.Anomalies = append(self.Anomalies,
+				"Realm does not match the logon session domain")
+		}
+	}
+
+	if self.StartTime.After(now.Add(5 * time.Minute)) {
+		self.Anomalies = append(self.Anomalies, "Ticket starts in the future")
+	}
+
+	// Tickets for another client in a session indicate pass the
+	// ticket. Sessions created with NewCredentials (runas /netonly)
+	// legitimately use other credentials for the network and the
+	// SYSTEM and NETWORK SERVICE sessions use the machine account.
+	if session.UserName != "" && session.LogonType != 9 &&
+		!isMachineSession(session, self.ClientName) &&
+		!strings.EqualFold(self.ClientName, session.UserName) &&
+		!strings.EqualFold(self.ClientName, session.Upn) {
+		self.Anomalies = append(self.Anomalies,
+			"Client does not match the logon session user")
+	}
+}
+
+func isMachineSession(session *LogonSession, client_name string) bool {
+	return strings.HasSuffix(client_name, "$") &&
+		(session.LogonId == SYSTEM_LOGON_ID ||
+			session.LogonId == NETWORK_SERVICE_LOGON_ID)
+}
+
+type KerberosTicketsArgs struct {
+	MaxTGTLifetime   int64 `vfilter:"optional,field=max_tgt_lifetime,doc=Flag TGTs valid for longer than this many hours (default 10)."`
+	MaxRenewLifetime int64 `vfilter:"optional,field=max_renew_lifetime,doc=Flag TGTs renewable for longer than this many days (default 7)."`
+}
+
+func runKerberosTickets(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	var result []vfilter.Row
+
+	err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+	if err != nil {
+		scope.Log("kerberos_tickets: %v", err)
+		return result
+	}
+
+	arg := &KerberosTicketsArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("kerberos_tickets: %v", err)
+		return result
+	}
+
+	if arg.MaxTGTLifetime == 0 {
+		arg.MaxTGTLifetime = 10
+	}
+
+	if arg.MaxRenewLifetime == 0 {
+		arg.MaxRenewLifetime = 7
+	}
+
+	options := AnalysisOptions{
+		MaxTGTLifetime:   time.Duration(arg.MaxTGTLifetime) * time.Hour,
+		MaxRenewLifetime: time.Duration(arg.MaxRenewLifetime) * 24 * time.Hour,
+	}
+
+	tickets, err := GetKerberosTickets()
+	if err != nil {
+		scope.Log("kerberos_tickets: %v", err)
+		return result
+	}
+
+	now := utils.GetTime().Now()
+	for _, ticket := range tickets {
+		ticket.Analyze(options, now)
+		result = append(result, ticket)
+	}
+
+	return result
+}
+
+type SecurityPackage struct {
+	Name         string
+	Comment      string
+	Version      uint16
+	RPCID        uint16
+	MaxToken     uint32
+	Capabilities uint32
+	Builtin      bool
+}
+
+func runSecurityPackages(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	var result []vfilter.Row
+
+	err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+	if err != nil {
+		scope.Log("security_packages: %v", err)
+		return result
+	}
+
+	packages, err := GetSecurityPackages()
+	if err != nil {
+		scope.Log("security_packages: %v", err)
+		return result
+	}
+
+	for _, pkg := range packages {
+		result = append(result, pkg)
+	}
+
+	return result
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "kerberos_tickets",
+		Doc:        "List the cached Kerberos tickets of all logon sessions and flag anomalies.",
+		Function:   runKerberosTickets,
+		ArgType:    &KerberosTicketsArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	})
+
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "security_packages",
+		Doc:        "List the security packages loaded into the LSA.",
+		Function:   runSecurityPackages,
+		Metadata:   vql.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	})
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package lsa
+
+import "errors"
+
+func GetKerberosTickets() ([]*KerberosTicket, error) {
+	return nil, errors.New("Not implemented")
+}
+
+func GetSecurityPackages() ([]*SecurityPackage, error) {
+	return nil, errors.New("Not implemented")
+}
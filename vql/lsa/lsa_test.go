@@ -0,0 +1,90 @@
+package lsa
+
+import (
+	"testing"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+var (
+	testOptions = AnalysisOptions{
+		MaxTGTLifetime:   10 * time.Hour,
+		MaxRenewLifetime: 7 * 24 * time.Hour,
+	}
+
+	testSession = &LogonSession{
+		LogonId:   0x1a2b3,
+		UserName:  "alice",
+		Domain:    "CORP",
+		DnsDomain: "CORP.EXAMPLE.COM",
+		LogonType: 2,
+	}
+)
+
+func TestAnalyzeTickets(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	start := now.Add(-time.Hour)
+
+	// A normal AES TGT issued by the domain.
+	ticket := NewKerberosTicket(testSession,
+		"alice", "CORP.EXAMPLE.COM", "krbtgt/CORP.EXAMPLE.COM", "CORP.EXAMPLE.COM",
+		start, start.Add(10*time.Hour), start.Add(7*24*time.Hour),
+		18, 18, 0x40e10000)
+	ticket.Analyze(testOptions, now)
+	assert.Equal(t, []string{}, ticket.Anomalies)
+	assert.Equal(t, "0x1a2b3", ticket.LogonId)
+	assert.Equal(t, "CORP\\alice", ticket.UserName)
+	assert.Equal(t, "Interactive", ticket.LogonType)
+	assert.Equal(t, "aes256-cts-hmac-sha1-96", ticket.EncryptionType)
+
+	// A golden ticket valid for ten years with an RC4 key for another
+	// user.
+	ticket = NewKerberosTicket(testSession,
+		"administrator", "corp.example.com", "krbtgt/corp.example.com", "corp.example.com",
+		start, start.Add(10*365*24*time.Hour), start.Add(10*365*24*time.Hour),
+		23, 23, 0x40e00000)
+	ticket.Analyze(testOptions, now)
+	assert.Equal(t, []string{
+		"RC4 encrypted ticket",
+		"TGT lifetime exceeds 10h0m0s",
+		"TGT renewal exceeds 168h0m0s",
+		"Realm is not uppercase",
+		"Client does not match the logon session user",
+	}, ticket.Anomalies)
+
+	// Service tickets are not subject to the TGT lifetime policy.
+	ticket = NewKerberosTicket(testSession,
+		"alice", "CORP.EXAMPLE.COM", "cifs/fs01.corp.example.com", "CORP.EXAMPLE.COM",
+		start.Add(time.Hour), start.Add(30*24*time.Hour), time.Time{},
+		18, 18, 0x40a10000)
+	ticket.Analyze(testOptions, now)
+	assert.Equal(t, []string{}, ticket.Anomalies)
+
+	// The SYSTEM session holds tickets for the machine account.
+	ticket = NewKerberosTicket(&LogonSession{
+		LogonId:   SYSTEM_LOGON_ID,
+		UserName:  "WKS01$",
+		DnsDomain: "CORP.EXAMPLE.COM",
+	}, "wks01$", "CORP.EXAMPLE.COM", "krbtgt/CORP.EXAMPLE.COM", "CORP.EXAMPLE.COM",
+		start, start.Add(10*time.Hour), start.Add(7*24*time.Hour),
+		18, 18, 0x40e10000)
+	ticket.Analyze(testOptions, now)
+	assert.Equal(t, []string{}, ticket.Anomalies)
+}
+
+func TestHelpers(t *testing.T) {
+	assert.Equal(t, []string{
+		"forwardable", "renewable", "initial", "pre_authent",
+		"name_canonicalize"}, TicketFlagNames(0x40e10000))
+
+	assert.Equal(t, "rc4-hmac", EncryptionTypeName(23))
+	assert.Equal(t, "-128", EncryptionTypeName(-128))
+
+	assert.True(t, FiletimeToTime(0x7fffffffffffffff).IsZero())
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		FiletimeToTime(133537680000000000))
+
+	assert.True(t, IsBuiltinPackage("Kerberos"))
+	assert.True(t, !IsBuiltinPackage("mimilib"))
+}
@@ -0,0 +1,289 @@
+//go:build windows
+// +build windows
+
+package lsa
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	KerbQueryTicketCacheEx2Message = 20
+)
+
+var (
+	modsecur32                         = windows.NewLazySystemDLL("secur32.dll")
+	procLsaConnectUntrusted            = modsecur32.NewProc("LsaConnectUntrusted")
+	procLsaRegisterLogonProcess        = modsecur32.NewProc("LsaRegisterLogonProcess")
+	procLsaDeregisterLogonProcess      = modsecur32.NewProc("LsaDeregisterLogonProcess")
+	procLsaLookupAuthenticationPackage = modsecur32.NewProc("LsaLookupAuthenticationPackage")
+	procLsaCallAuthenticationPackage   = modsecur32.NewProc("LsaCallAuthenticationPackage")
+	procLsaFreeReturnBuffer            = modsecur32.NewProc("LsaFreeReturnBuffer")
+	procLsaEnumerateLogonSessions      = modsecur32.NewProc("LsaEnumerateLogonSessions")
+	procLsaGetLogonSessionData         = modsecur32.NewProc("LsaGetLogonSessionData")
+	procEnumerateSecurityPackagesW     = modsecur32.NewProc("EnumerateSecurityPackagesW")
+	procFreeContextBuffer              = modsecur32.NewProc("FreeContextBuffer")
+)
+
+// LSA_STRING
+type lsaString struct {
+	Length        uint16
+	MaximumLength uint16
+	Buffer        *byte
+}
+
+func newLsaString(value string) *lsaString {
+	buf := append([]byte(value), 0)
+	return &lsaString{
+		Length:        uint16(len(value)),
+		MaximumLength: uint16(len(buf)),
+		Buffer:        &buf[0],
+	}
+}
+
+// SECURITY_LOGON_SESSION_DATA
+type securityLogonSessionData struct {
+	Size                  uint32
+	LogonId               windows.LUID
+	UserName              windows.NTUnicodeString
+	LogonDomain           windows.NTUnicodeString
+	AuthenticationPackage windows.NTUnicodeString
+	LogonType             uint32
+	Session               uint32
+	Sid                   *windows.SID
+	LogonTime             int64
+	LogonServer           windows.NTUnicodeString
+	DnsDomainName         windows.NTUnicodeString
+	Upn                   windows.NTUnicodeString
+}
+
+// KERB_QUERY_TKT_CACHE_REQUEST
+type kerbQueryTktCacheRequest struct {
+	MessageType uint32
+	LogonId     windows.LUID
+}
+
+// KERB_TICKET_CACHE_INFO_EX2
+type kerbTicketCacheInfoEx2 struct {
+	ClientName     windows.NTUnicodeString
+	ClientRealm    windows.NTUnicodeString
+	ServerName     windows.NTUnicodeString
+	ServerRealm    windows.NTUnicodeString
+	StartTime      int64
+	EndTime        int64
+	RenewTime      int64
+	EncryptionType int32
+	TicketFlags    uint32
+	SessionKeyType uint32
+	BranchId       uint32
+}
+
+// SecPkgInfoW
+type secPkgInfo struct {
+	Capabilities uint32
+	Version      uint16
+	RPCID        uint16
+	MaxToken     uint32
+	Name         *uint16
+	Comment      *uint16
+}
+
+func ntStatus(r1 uintptr) error {
+	if r1 != 0 {
+		return windows.NTStatus(r1)
+	}
+	return nil
+}
+
+// Registering as a logon process requires SeTcbPrivilege (i.e. running
+// as SYSTEM) and allows querying the tickets of all logon
+// sessions. Otherwise an untrusted connection only sees the caller's
+// own session.
+func connectLsa() (windows.Handle, error) {
+	var handle windows.Handle
+	var mode uint32
+
+	r1, _, _ := procLsaRegisterLogonProcess.Call(
+		uintptr(unsafe.Pointer(newLsaString("Velociraptor"))),
+		uintptr(unsafe.Pointer(&handle)),
+		uintptr(unsafe.Pointer(&mode)))
+	if r1 == 0 {
+		return handle, nil
+	}
+
+	r1, _, _ = procLsaConnectUntrusted.Call(uintptr(unsafe.Pointer(&handle)))
+	return handle, ntStatus(r1)
+}
+
+func lsaFreeReturnBuffer(buffer unsafe.Pointer) {
+	procLsaFreeReturnBuffer.Call(uintptr(buffer))
+}
+
+func getLogonSessions() ([]*LogonSession, error) {
+	var count uint32
+	var luids *windows.LUID
+
+	r1, _, _ := procLsaEnumerateLogonSessions.Call(
+		uintptr(unsafe.Pointer(&count)),
+		uintptr(unsafe.Pointer(&luids)))
+	if err := ntStatus(r1); err != nil {
+		return nil, err
+	}
+	defer lsaFreeReturnBuffer(unsafe.Pointer(luids))
+
+	result := []*LogonSession{}
+	for _, luid := range unsafe.Slice(luids, count) {
+		var data *securityLogonSessionData
+
+		r1, _, _ := procLsaGetLogonSessionData.Call(
+			uintptr(unsafe.Pointer(&luid)),
+			uintptr(unsafe.Pointer(&data)))
+		if r1 != 0 || data == nil {
+			continue
+		}
+
+		session := &LogonSession{
+			LogonId:               uint64(luid.HighPart)<<32 | uint64(luid.LowPart),
+			UserName:              data.UserName.String(),
+			Domain:                data.LogonDomain.String(),
+			DnsDomain:             data.DnsDomainName.String(),
+			Upn:                   data.Upn.String(),
+			AuthenticationPackage: data.AuthenticationPackage.String(),
+			LogonType:             data.LogonType,
+			Session:               data.Session,
+			LogonTime:             FiletimeToTime(data.LogonTime),
+		}
+		if data.Sid != nil {
+			session.Sid = data.Sid.String()
+		}
+		lsaFreeReturnBuffer(unsafe.Pointer(data))
+
+		result = append(result, session)
+	}
+
+	return result, nil
+}
+
+func queryTicketCache(handle windows.Handle, package_id uint32,
+	session *LogonSession) ([]*KerberosTicket, error) {
+	request := &kerbQueryTktCacheRequest{
+		MessageType: KerbQueryTicketCacheEx2Message,
+		LogonId: windows.LUID{
+			LowPart:  uint32(session.LogonId),
+			HighPart: int32(session.LogonId >> 32),
+		},
+	}
+
+	var ret unsafe.Pointer
+	var ret_len, protocol_status uint32
+
+	r1, _, _ := procLsaCallAuthenticationPackage.Call(
+		uintptr(handle), uintptr(package_id),
+		uintptr(unsafe.Pointer(request)), unsafe.Sizeof(*request),
+		uintptr(unsafe.Pointer(&ret)),
+		uintptr(unsafe.Pointer(&ret_len)),
+		uintptr(unsafe.Pointer(&protocol_status)))
+	if err := ntStatus(r1); err != nil {
+		return nil, err
+	}
+	if ret == nil {
+		return nil, nil
+	}
+	defer lsaFreeReturnBuffer(ret)
+
+	if err := ntStatus(uintptr(protocol_status)); err != nil {
+		return nil, err
+	}
+
+	// KERB_QUERY_TKT_CACHE_EX2_RESPONSE: MessageType, CountOfTickets
+	// followed by the (pointer aligned) array of tickets.
+	count := *(*uint32)(unsafe.Add(ret, 4))
+	size := unsafe.Sizeof(kerbTicketCacheInfoEx2{})
+	if uintptr(ret_len) < 8+uintptr(count)*size {
+		return nil, errors.New("Ticket cache response too short")
+	}
+
+	result := []*KerberosTicket{}
+	infos := unsafe.Slice((*kerbTicketCacheInfoEx2)(unsafe.Add(ret, 8)), count)
+	for _, info := range infos {
+		result = append(result, NewKerberosTicket(session,
+			info.ClientName.String(), info.ClientRealm.String(),
+			info.ServerName.String(), info.ServerRealm.String(),
+			FiletimeToTime(info.StartTime),
+			FiletimeToTime(info.EndTime),
+			FiletimeToTime(info.RenewTime),
+			info.EncryptionType, int32(info.SessionKeyType),
+			info.TicketFlags))
+	}
+
+	return result, nil
+}
+
+func GetKerberosTickets() ([]*KerberosTicket, error) {
+	handle, err := connectLsa()
+	if err != nil {
+		return nil, fmt.Errorf("LsaConnectUntrusted: %w", err)
+	}
+	defer procLsaDeregisterLogonProcess.Call(uintptr(handle))
+
+	var package_id uint32
+	r1, _, _ := procLsaLookupAuthenticationPackage.Call(uintptr(handle),
+		uintptr(unsafe.Pointer(newLsaString("Kerberos"))),
+		uintptr(unsafe.Pointer(&package_id)))
+	if err := ntStatus(r1); err != nil {
+		return nil, fmt.Errorf("LsaLookupAuthenticationPackage: %w", err)
+	}
+
+	sessions, err := getLogonSessions()
+	if err != nil {
+		return nil, fmt.Errorf("LsaEnumerateLogonSessions: %w", err)
+	}
+
+	result := []*KerberosTicket{}
+	for _, session := range sessions {
+		// Sessions we are not allowed to query are skipped.
+		tickets, err := queryTicketCache(handle, package_id, session)
+		if err != nil {
+			continue
+		}
+		result = append(result, tickets...)
+	}
+
+	return result, nil
+}
+
+func GetSecurityPackages() ([]*SecurityPackage, error) {
+	var count uint32
+	var packages *secPkgInfo
+
+	r1, _, _ := procEnumerateSecurityPackagesW.Call(
+		uintptr(unsafe.Pointer(&count)),
+		uintptr(unsafe.Pointer(&packages)))
+	if r1 != 0 {
+		return nil, fmt.Errorf("EnumerateSecurityPackages: %w", windows.Errno(r1))
+	}
+	if packages == nil {
+		return nil, nil
+	}
+	defer procFreeContextBuffer.Call(uintptr(unsafe.Pointer(packages)))
+
+	result := []*SecurityPackage{}
+	for _, pkg := range unsafe.Slice(packages, count) {
+		name := windows.UTF16PtrToString(pkg.Name)
+		result = append(result, &SecurityPackage{
+			Name:         name,
+			Comment:      windows.UTF16PtrToString(pkg.Comment),
+			Version:      pkg.Version,
+			RPCID:        pkg.RPCID,
+			MaxToken:     pkg.MaxToken,
+			Capabilities: pkg.Capabilities,
+			Builtin:      IsBuiltinPackage(name),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,261 @@
+package networking
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type DeliverFileArgs struct {
+	Url        string `vfilter:"required,field=url,doc=The URL to fetch the file from (usually the server's public directory)."`
+	Sha256     string `vfilter:"required,field=sha256,doc=The expected SHA256 of the file. The file is rejected if it does not match."`
+	Filename   string `vfilter:"optional,field=filename,doc=The filename to store the file as (default the last component of the URL)."`
+	Dest       string `vfilter:"optional,field=dest,doc=The directory to store the file in (default the temp directory)."`
+	Executable bool   `vfilter:"optional,field=executable,doc=If set the file is made executable (on windows it will have an .exe extension)."`
+	Keep       bool   `vfilter:"optional,field=keep,doc=If set the file is not removed when the collection completes."`
+}
+
+// Delivers a file from the server to the endpoint. The file is
+// verified against its expected hash before being made available and
+// is removed when the collection completes. Each delivery is recorded
+// in the collection's uploads so there is a record of what files were
+// pushed to the endpoint.
+type DeliverFilePlugin struct{}
+
+func (self DeliverFilePlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.FILESYSTEM_WRITE)
+		if err != nil {
+			scope.Log("deliver_file: %v", err)
+			return
+		}
+
+		arg := &DeliverFileArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("deliver_file: %v", err)
+			return
+		}
+
+		dest_path, err := getDeliveryPath(arg)
+		if err != nil {
+			scope.Log("deliver_file: %v", err)
+			return
+		}
+
+		config_obj, _ := artifacts.GetConfig(scope)
+		client, err := GetDefaultHTTPClient(ctx, config_obj, scope, "", nil)
+		if err != nil {
+			scope.Log("deliver_file: %v", err)
+			return
+		}
+
+		size, err := deliverFile(ctx, client, arg.Url, arg.Sha256,
+			dest_path, arg.Executable)
+		if err != nil {
+			scope.Log("deliver_file: %v", err)
+			return
+		}
+
+		scope.Log("deliver_file: Delivered %v (%v bytes) to %v",
+			arg.Url, size, dest_path)
+
+		if !arg.Keep {
+			removal := func() {
+				scope.Log("deliver_file: removing %v", dest_path)
+
+				// On windows the file may still be locked by a
+				// process so keep trying for a while.
+				for i := 0; i < 10; i++ {
+					err := os.Remove(dest_path)
+					if err == nil || os.IsNotExist(err) {
+						return
+					}
+					time.Sleep(time.Second)
+				}
+				scope.Log("deliver_file: Unable to remove %v", dest_path)
+			}
+
+			// Remove the file when the entire collection is done.
+			err := vql_subsystem.GetRootScope(scope).AddDestructor(removal)
+			if err != nil {
+				removal()
+				scope.Log("deliver_file: %v", err)
+				return
+			}
+		}
+
+		result := ordereddict.NewDict().
+			Set("OSPath", dest_path).
+			Set("Size", size).
+			Set("SHA256", strings.ToLower(arg.Sha256)).
+			Set("Url", arg.Url).
+			Set("Kept", arg.Keep)
+
+		recordDelivery(ctx, scope, result)
+
+		select {
+		case <-ctx.Done():
+		case output_chan <- result:
+		}
+	}()
+
+	return output_chan
+}
+
+func (self DeliverFilePlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name: "deliver_file",
+		Doc: "Deliver a file to the endpoint, verifying its hash. " +
+			"The file is removed when the collection completes.",
+		ArgType:  type_map.AddType(scope, &DeliverFileArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_WRITE).Build(),
+	}
+}
+
+func getDeliveryPath(arg *DeliverFileArgs) (string, error) {
+	filename := arg.Filename
+	if filename == "" {
+		parsed, err := url.Parse(arg.Url)
+		if err != nil {
+			return "", err
+		}
+		filename = path.Base(parsed.Path)
+	}
+
+	// The filename must not escape the destination directory.
+	if filename == "" || filename == "." || filename == "/" ||
+		filename == ".." || strings.ContainsAny(filename, `/\`) {
+		return "", fmt.Errorf("Invalid filename %v", filename)
+	}
+
+	if arg.Executable && runtime.GOOS == "windows" &&
+		!strings.HasSuffix(strings.ToLower(filename), ".exe") {
+		filename += ".exe"
+	}
+
+	dest := arg.Dest
+	if dest == "" {
+		dest = os.TempDir()
+	}
+
+	return filepath.Join(dest, filename), nil
+}
+
+// Download the url into dest_path. The data is written to a temporary
+// file next to the destination and only renamed into place once the
+// hash is verified.
+func deliverFile(ctx context.Context, client HTTPClient,
+	url, expected_hash, dest_path string, executable bool) (int64, error) {
+	expected_hash = strings.ToLower(expected_hash)
+	if len(expected_hash) != 64 {
+		return 0, errors.New("Invalid sha256 hash")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("Fetching %v: %v", url, resp.Status)
+	}
+
+	tmpfile, err := ioutil.TempFile(filepath.Dir(dest_path), "tmp*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmpfile.Name())
+
+	sha_sum := sha256.New()
+	size, err := utils.Copy(ctx, io.MultiWriter(tmpfile, sha_sum), resp.Body)
+	tmpfile.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	hash := hex.EncodeToString(sha_sum.Sum(nil))
+	if hash != expected_hash {
+		return 0, fmt.Errorf("Hash mismatch for %v: got %v expected %v",
+			url, hash, expected_hash)
+	}
+
+	permissions := os.FileMode(0600)
+	if executable {
+		permissions = 0700
+	}
+
+	err = os.Chmod(tmpfile.Name(), permissions)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(size), os.Rename(tmpfile.Name(), dest_path)
+}
+
+// Record the delivery in the collection's uploads.
+func recordDelivery(ctx context.Context,
+	scope vfilter.Scope, result *ordereddict.Dict) {
+	uploader, ok := artifacts.GetUploader(scope)
+	if !ok {
+		return
+	}
+
+	serialized, err := json.MarshalIndent(result)
+	if err != nil {
+		return
+	}
+
+	dest_path, _ := result.GetString("OSPath")
+	store_as_name := accessors.MustNewGenericOSPath("delivered").
+		Append(filepath.Base(dest_path) + ".json")
+	now := utils.GetTime().Now()
+
+	_, err = uploader.Upload(ctx, scope, store_as_name, "data",
+		store_as_name, int64(len(serialized)), now, now, now, now,
+		bytes.NewReader(serialized))
+	if err != nil {
+		scope.Log("deliver_file: %v", err)
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&DeliverFilePlugin{})
+}
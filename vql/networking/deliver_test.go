@@ -0,0 +1,72 @@
+package networking
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestDeliverFile(t *testing.T) {
+	message := []byte("Hello world")
+	sha_sum := sha256.Sum256(message)
+	sha_value := hex.EncodeToString(sha_sum[:])
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/public/mytool.exe" {
+				w.WriteHeader(404)
+				return
+			}
+			w.Write(message)
+		}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "deliver_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	client := &http.Client{}
+
+	// Filenames are taken from the url but may not escape the
+	// destination directory.
+	dest_path, err := getDeliveryPath(&DeliverFileArgs{
+		Url: ts.URL + "/public/mytool.exe", Dest: dir})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "mytool.exe"), dest_path)
+
+	_, err = getDeliveryPath(&DeliverFileArgs{
+		Url: ts.URL, Filename: "../../etc/passwd", Dest: dir})
+	assert.Error(t, err)
+
+	// A hash mismatch leaves nothing behind.
+	_, err = deliverFile(ctx, client, ts.URL+"/public/mytool.exe",
+		sha_value[:63]+"0", dest_path, false)
+	assert.ErrorContains(t, err, "Hash mismatch")
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(files))
+
+	// Server errors are reported.
+	_, err = deliverFile(ctx, client, ts.URL+"/public/other.exe",
+		sha_value, dest_path, false)
+	assert.Error(t, err)
+
+	size, err := deliverFile(ctx, client, ts.URL+"/public/mytool.exe",
+		sha_value, dest_path, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(message)), size)
+
+	data, err := ioutil.ReadFile(dest_path)
+	assert.NoError(t, err)
+	assert.Equal(t, message, data)
+}
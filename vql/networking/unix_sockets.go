@@ -0,0 +1,188 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package networking
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/psutils"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type UnixSocket struct {
+	// Uniquely identifies the socket between listings.
+	Key string
+
+	Path      string
+	Type      string
+	Listening bool
+	Connected bool
+
+	// The process owning the socket (-1 if unknown).
+	Pid int32
+
+	// The process at the other end of a connection (-1 if unknown).
+	PeerPid int32
+}
+
+func getProcessInfo(ctx context.Context, pid int32,
+	cache map[int32]*ordereddict.Dict) *ordereddict.Dict {
+	if pid <= 0 {
+		return nil
+	}
+
+	result, pres := cache[pid]
+	if pres {
+		return result
+	}
+
+	result = ordereddict.NewDict().Set("Pid", pid)
+	info, err := psutils.GetProcess(ctx, pid)
+	if err == nil {
+		for _, field := range []string{"Name", "Exe", "CommandLine", "Username"} {
+			value, _ := info.Get(field)
+			result.Set(field, value)
+		}
+	}
+	cache[pid] = result
+	return result
+}
+
+type WatchUnixSocketsArgs struct {
+	Period int64 `vfilter:"optional,field=period,doc=How often to check for changes (default 10 seconds)."`
+}
+
+type WatchUnixSocketsPlugin struct{}
+
+func (self WatchUnixSocketsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "watch_unix_sockets")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("watch_unix_sockets: %s", err)
+			return
+		}
+
+		arg := &WatchUnixSocketsArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("watch_unix_sockets: %s", err.Error())
+			return
+		}
+
+		if arg.Period == 0 {
+			arg.Period = 10
+		}
+
+		// The first listing is the baseline - we only report
+		// changes from it.
+		var last map[string]*UnixSocket
+
+		for {
+			sockets, err := listUnixSockets(ctx)
+			if err != nil {
+				scope.Log("watch_unix_sockets: %v", err)
+			} else {
+				current := make(map[string]*UnixSocket)
+				for _, s := range sockets {
+					current[s.Key] = s
+				}
+
+				if last != nil {
+					for _, row := range diffUnixSockets(ctx, last, current) {
+						select {
+						case <-ctx.Done():
+							return
+						case output_chan <- row:
+						}
+					}
+				}
+				last = current
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Duration(arg.Period) * time.Second):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+// Only named sockets are interesting: New listening sockets are
+// reported as Created, new connections to a named socket as Connected
+// and listening sockets which are gone as Removed.
+func diffUnixSockets(ctx context.Context,
+	last, current map[string]*UnixSocket) []vfilter.Row {
+	var result []vfilter.Row
+	cache := make(map[int32]*ordereddict.Dict)
+
+	row := func(action string, s *UnixSocket) *ordereddict.Dict {
+		return ordereddict.NewDict().
+			Set("Action", action).
+			Set("Path", s.Path).
+			Set("Type", s.Type).
+			Set("Process", getProcessInfo(ctx, s.Pid, cache)).
+			Set("Peer", getProcessInfo(ctx, s.PeerPid, cache))
+	}
+
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := current[key]
+		_, pres := last[key]
+		if pres || s.Path == "" {
+			continue
+		}
+
+		if s.Listening {
+			result = append(result, row("Created", s))
+
+		} else if s.Connected {
+			result = append(result, row("Connected", s))
+		}
+	}
+
+	for key, s := range last {
+		_, pres := current[key]
+		if !pres && s.Listening && s.Path != "" {
+			result = append(result, row("Removed", s))
+		}
+	}
+
+	return result
+}
+
+func (self WatchUnixSocketsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "watch_unix_sockets",
+		Doc:      "Watch for UNIX domain sockets being created, connected to and removed.",
+		ArgType:  type_map.AddType(scope, &WatchUnixSocketsArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&WatchUnixSocketsPlugin{})
+}
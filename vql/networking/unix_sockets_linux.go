@@ -0,0 +1,237 @@
+//go:build linux
+// +build linux
+
+package networking
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Constants from linux/sock_diag.h and linux/unix_diag.h
+const (
+	SOCK_DIAG_BY_FAMILY = 20
+	UDIAG_SHOW_PEER     = 0x4
+	UNIX_DIAG_PEER      = 2
+
+	// __SO_ACCEPTCON in /proc/net/unix flags
+	UNIX_FLAG_LISTENING  = 0x10000
+	UNIX_STATE_CONNECTED = 3
+)
+
+var unixSocketTypes = map[uint64]string{
+	1: "STREAM",
+	2: "DGRAM",
+	5: "SEQPACKET",
+}
+
+type unixDiagReq struct {
+	Family   uint8
+	Protocol uint8
+	Pad      uint16
+	States   uint32
+	Ino      uint32
+	Show     uint32
+	Cookie   [2]uint32
+}
+
+type unixSocketEntry struct {
+	UnixSocket
+	Inode uint64
+}
+
+/*
+Num       RefCount Protocol Flags    Type St Inode Path
+0000000000000000: 00000002 00000000 00010000 0001 01 23456 /run/dbus/system_bus_socket
+*/
+func parseProcNetUnix(reader io.Reader) []*unixSocketEntry {
+	var result []*unixSocketEntry
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Scan() // skip header
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil {
+			continue
+		}
+
+		socket_type, err := strconv.ParseUint(fields[4], 16, 16)
+		if err != nil {
+			continue
+		}
+
+		state, err := strconv.ParseUint(fields[5], 16, 8)
+		if err != nil {
+			continue
+		}
+
+		inode, err := strconv.ParseUint(fields[6], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		type_name, pres := unixSocketTypes[socket_type]
+		if !pres {
+			type_name = fmt.Sprintf("%d", socket_type)
+		}
+
+		entry := &unixSocketEntry{
+			UnixSocket: UnixSocket{
+				Key:       fmt.Sprintf("%d", inode),
+				Type:      type_name,
+				Listening: flags&UNIX_FLAG_LISTENING != 0,
+				Connected: state == UNIX_STATE_CONNECTED,
+				Pid:       -1,
+				PeerPid:   -1,
+			},
+			Inode: inode,
+		}
+
+		// The path may contain spaces.
+		if len(fields) > 7 {
+			entry.Path = strings.Join(fields[7:], " ")
+		}
+
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+// Use the sock_diag netlink interface to find the peer of each
+// connected socket. The peer is not shown in /proc/net/unix.
+func getUnixPeers() (map[uint64]uint64, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK,
+		syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, unix.NETLINK_SOCK_DIAG)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd)
+
+	req := unixDiagReq{
+		Family: syscall.AF_UNIX,
+		States: 0xffffffff,
+		Show:   UDIAG_SHOW_PEER,
+	}
+	req_size := int(unsafe.Sizeof(req))
+
+	buf := make([]byte, syscall.NLMSG_HDRLEN+req_size)
+	*(*syscall.NlMsghdr)(unsafe.Pointer(&buf[0])) = syscall.NlMsghdr{
+		Len:   uint32(len(buf)),
+		Type:  SOCK_DIAG_BY_FAMILY,
+		Flags: syscall.NLM_F_REQUEST | syscall.NLM_F_DUMP,
+		Seq:   1,
+	}
+	*(*unixDiagReq)(unsafe.Pointer(&buf[syscall.NLMSG_HDRLEN])) = req
+
+	err = syscall.Sendto(fd, buf, 0, &syscall.SockaddrNetlink{
+		Family: syscall.AF_NETLINK,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint64]uint64)
+	buf = make([]byte, 64*1024)
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, msg := range msgs {
+			switch msg.Header.Type {
+			case syscall.NLMSG_DONE:
+				return result, nil
+
+			case syscall.NLMSG_ERROR:
+				return nil, fmt.Errorf("sock_diag: netlink error")
+			}
+
+			// struct unix_diag_msg is 16 bytes followed by
+			// the attributes.
+			if len(msg.Data) < 16 {
+				continue
+			}
+			inode := *(*uint32)(unsafe.Pointer(&msg.Data[4]))
+
+			attrs := msg.Data[16:]
+			for len(attrs) >= syscall.SizeofRtAttr {
+				attr := (*syscall.RtAttr)(unsafe.Pointer(&attrs[0]))
+				length := int(attr.Len)
+				if length < syscall.SizeofRtAttr || length > len(attrs) {
+					break
+				}
+
+				if attr.Type == UNIX_DIAG_PEER &&
+					length >= syscall.SizeofRtAttr+4 {
+					peer := *(*uint32)(unsafe.Pointer(
+						&attrs[syscall.SizeofRtAttr]))
+					result[uint64(inode)] = uint64(peer)
+				}
+
+				// Attributes are aligned to 4 bytes
+				length = (length + 3) &^ 3
+				if length > len(attrs) {
+					break
+				}
+				attrs = attrs[length:]
+			}
+		}
+	}
+}
+
+func listUnixSockets(ctx context.Context) ([]*UnixSocket, error) {
+	fd, err := os.Open("/proc/net/unix")
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	entries := parseProcNetUnix(fd)
+
+	// These may fail when not running as root in which case we do
+	// not know the processes.
+	si, _ := gatherSocketInfo()
+	peers, _ := getUnixPeers()
+
+	result := make([]*UnixSocket, 0, len(entries))
+	for _, entry := range entries {
+		pid, pres := si[entry.Inode]
+		if pres {
+			entry.Pid = pid
+		}
+
+		peer, pres := peers[entry.Inode]
+		if pres {
+			peer_pid, pres := si[peer]
+			if pres {
+				entry.PeerPid = peer_pid
+			}
+		}
+
+		result = append(result, &entry.UnixSocket)
+	}
+
+	return result, nil
+}
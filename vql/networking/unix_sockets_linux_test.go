@@ -0,0 +1,85 @@
+//go:build linux
+// +build linux
+
+package networking
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+const procNetUnix = `Num       RefCount Protocol Flags    Type St Inode Path
+0000000000000000: 00000002 00000000 00010000 0001 01 23456 /run/dbus/system_bus_socket
+0000000000000000: 00000003 00000000 00000000 0001 03 23460 /run/dbus/system_bus_socket
+0000000000000000: 00000003 00000000 00000000 0001 03 23461
+0000000000000000: 00000002 00000000 00010000 0005 01 23470 @abstract name
+`
+
+func TestParseProcNetUnix(t *testing.T) {
+	entries := parseProcNetUnix(strings.NewReader(procNetUnix))
+	assert.Equal(t, 4, len(entries))
+
+	assert.Equal(t, uint64(23456), entries[0].Inode)
+	assert.Equal(t, "/run/dbus/system_bus_socket", entries[0].Path)
+	assert.Equal(t, "STREAM", entries[0].Type)
+	assert.True(t, entries[0].Listening)
+
+	// The server end of an accepted connection has the path.
+	assert.True(t, entries[1].Connected)
+	assert.True(t, !entries[1].Listening)
+	assert.Equal(t, "", entries[2].Path)
+
+	assert.Equal(t, "SEQPACKET", entries[3].Type)
+	assert.Equal(t, "@abstract name", entries[3].Path)
+}
+
+func TestListUnixSockets(t *testing.T) {
+	dir, err := os.MkdirTemp("", "unix_sockets")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.sock")
+	listener, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	conn, err := net.Dial("unix", path)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	server, err := listener.Accept()
+	assert.NoError(t, err)
+	defer server.Close()
+
+	sockets, err := listUnixSockets(context.Background())
+	assert.NoError(t, err)
+
+	listening := false
+	connected := false
+	for _, s := range sockets {
+		if s.Path != path {
+			continue
+		}
+
+		assert.Equal(t, int32(os.Getpid()), s.Pid)
+		if s.Listening {
+			listening = true
+		} else if s.Connected {
+			connected = true
+
+			// The peer is ourselves if sock_diag is available.
+			if s.PeerPid != -1 {
+				assert.Equal(t, int32(os.Getpid()), s.PeerPid)
+			}
+		}
+	}
+
+	assert.True(t, listening)
+	assert.True(t, connected)
+}
@@ -0,0 +1,43 @@
+//go:build darwin || freebsd
+// +build darwin freebsd
+
+package networking
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"www.velocidex.com/golang/velociraptor/vql/psutils"
+)
+
+// Peers are not available on these platforms.
+func listUnixSockets(ctx context.Context) ([]*UnixSocket, error) {
+	cons, err := psutils.ConnectionsWithContext(ctx, "unix")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*UnixSocket, 0, len(cons))
+	for _, c := range cons {
+		socket_type := fmt.Sprintf("%d", c.Type)
+		switch c.Type {
+		case syscall.SOCK_STREAM:
+			socket_type = "STREAM"
+		case syscall.SOCK_DGRAM:
+			socket_type = "DGRAM"
+		}
+
+		result = append(result, &UnixSocket{
+			Key:       fmt.Sprintf("%v:%v:%v", c.Pid, c.Fd, c.Laddr.IP),
+			Path:      c.Laddr.IP,
+			Type:      socket_type,
+			Listening: c.Status == "LISTEN",
+			Connected: c.Status != "LISTEN" && c.Raddr.IP != "",
+			Pid:       c.Pid,
+			PeerPid:   -1,
+		})
+	}
+
+	return result, nil
+}
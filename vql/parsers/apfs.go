@@ -0,0 +1,205 @@
+package parsers
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/accessors/apfs"
+	"www.velocidex.com/golang/velociraptor/acls"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type APFSVolumesPluginArgs struct {
+	Filename *accessors.OSPath `vfilter:"required,field=filename,doc=The APFS container to open (e.g. a partition within a raw image)."`
+	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use."`
+}
+
+type APFSVolumesPlugin struct{}
+
+func (self APFSVolumesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer utils.RecoverVQL(scope)
+
+		arg := &APFSVolumesPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("apfs_volumes: %v", err)
+			return
+		}
+
+		apfs_ctx, err := apfs.GetAPFSCache(scope, arg.Filename, arg.Accessor)
+		if err != nil {
+			scope.Log("apfs_volumes: %v", err)
+			return
+		}
+
+		for _, volume := range apfs_ctx.Volumes {
+			snapshots, err := volume.Snapshots()
+			if err != nil {
+				scope.Log("apfs_volumes: %v: %v", volume.Name, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+
+			case output_chan <- ordereddict.NewDict().
+				Set("Container", apfs_ctx.UUID).
+				Set("Index", volume.Index).
+				Set("Name", volume.Name).
+				Set("UUID", volume.UUID).
+				Set("Role", volume.Role).
+				Set("VolumeGroup", volume.VolumeGroup).
+				Set("FormattedBy", volume.FormattedBy).
+				Set("LastModified", volume.LastModified).
+				Set("NumFiles", volume.NumFiles).
+				Set("NumDirectories", volume.NumDirectories).
+				Set("NumSymlinks", volume.NumSymlinks).
+				Set("CaseInsensitive", volume.IsCaseInsensitive()).
+				Set("Encryption", volume.Crypto).
+				Set("Snapshots", snapshots):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self APFSVolumesPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "apfs_volumes",
+		Doc:      "List the volumes and snapshots in an APFS container.",
+		ArgType:  type_map.AddType(scope, &APFSVolumesPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+type APFSSnapshotDiffPluginArgs struct {
+	Filename *accessors.OSPath `vfilter:"required,field=filename,doc=The APFS container to open (e.g. a partition within a raw image)."`
+	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use."`
+	Volume   string            `vfilter:"required,field=volume,doc=The name of the volume."`
+	Snapshot string            `vfilter:"required,field=snapshot,doc=The name of the snapshot to compare from."`
+	Compare  string            `vfilter:"optional,field=compare,doc=The name of the snapshot to compare to (default the live volume)."`
+}
+
+type APFSSnapshotDiffPlugin struct{}
+
+func (self APFSSnapshotDiffPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer utils.RecoverVQL(scope)
+
+		arg := &APFSSnapshotDiffPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("apfs_snapshot_diff: %v", err)
+			return
+		}
+
+		apfs_ctx, err := apfs.GetAPFSCache(scope, arg.Filename, arg.Accessor)
+		if err != nil {
+			scope.Log("apfs_snapshot_diff: %v", err)
+			return
+		}
+
+		volume, err := apfs_ctx.GetVolume(arg.Volume)
+		if err != nil {
+			scope.Log("apfs_snapshot_diff: volume %v: %v", arg.Volume, err)
+			return
+		}
+
+		before, err := openAPFSSnapshot(volume, arg.Snapshot)
+		if err != nil {
+			scope.Log("apfs_snapshot_diff: snapshot %v: %v", arg.Snapshot, err)
+			return
+		}
+
+		after := volume
+		if arg.Compare != "" {
+			after, err = openAPFSSnapshot(volume, arg.Compare)
+			if err != nil {
+				scope.Log("apfs_snapshot_diff: snapshot %v: %v", arg.Compare, err)
+				return
+			}
+		}
+
+		diff_chan := make(chan *apfs.DiffEntry)
+		go func() {
+			defer close(diff_chan)
+
+			err := apfs.DiffVolumes(ctx, before, after, diff_chan)
+			if err != nil {
+				scope.Log("apfs_snapshot_diff: %v", err)
+			}
+		}()
+
+		for item := range diff_chan {
+			row := ordereddict.NewDict().
+				Set("OSPath", item.Path).
+				Set("Change", item.Change)
+
+			for _, i := range []struct {
+				prefix string
+				inode  *apfs.Inode
+			}{{"Old", item.Old}, {"New", item.New}} {
+				if i.inode == nil {
+					row.Set(i.prefix+"Inode", vfilter.Null{}).
+						Set(i.prefix+"Size", vfilter.Null{}).
+						Set(i.prefix+"Mtime", vfilter.Null{})
+					continue
+				}
+
+				row.Set(i.prefix+"Inode", i.inode.Number).
+					Set(i.prefix+"Size", i.inode.LogicalSize()).
+					Set(i.prefix+"Mtime", i.inode.Mtime)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func openAPFSSnapshot(volume *apfs.Volume, name string) (*apfs.Volume, error) {
+	snapshot, err := volume.GetSnapshot(name)
+	if err != nil {
+		return nil, err
+	}
+	return volume.OpenSnapshot(snapshot)
+}
+
+func (self APFSSnapshotDiffPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "apfs_snapshot_diff",
+		Doc:      "Report files added, removed or modified between an APFS snapshot and the live volume (or another snapshot).",
+		ArgType:  type_map.AddType(scope, &APFSSnapshotDiffPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&APFSVolumesPlugin{})
+	vql_subsystem.RegisterPlugin(&APFSSnapshotDiffPlugin{})
+}
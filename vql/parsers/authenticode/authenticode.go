@@ -16,11 +16,12 @@
    along with this program.  If not, see <https://www.gnu.org/licenses/>.
 */
 
-// Get Authenticode information from signed binaries. Currently only
-// using the windows API. It is now possible to read authenticode
-// certificates and cat files using the parse_pkcs7() and the
-// parse_pe() vql functions. Those will return more information, but
-// do not perform the verification against the root store.
+// Get Authenticode information from signed binaries. On Windows we
+// use the windows API, elsewhere the certificate chain is verified
+// against the platform trust store (see chain.go). It is also
+// possible to read authenticode certificates and cat files using the
+// parse_pkcs7() and the parse_pe() vql functions. Those will return
+// more information.
 package authenticode
 
 import (
@@ -105,7 +106,14 @@ func (self *AuthenticodeFunction) Call(ctx context.Context,
 			Update("MoreInfoLink", utils.GetString(signer, "Signer.AuthenticatedAttributes.MoreInfo")).
 			Update("Timestamp", utils.GetAny(signer, "Signer.AuthenticatedAttributes.SigningTime")).
 			Update("Trusted", func() vfilter.Any {
-				return VerifyFileSignature(scope, normalized_path)
+				trusted := VerifyFileSignature(scope, normalized_path)
+				if trusted == NO_API_ACCESS {
+					// Without the Windows API we verify the
+					// chain against the platform trust store.
+					return utils.GetString(
+						VerifyAuthenticode(pe_file), "Trusted")
+				}
+				return trusted
 			})
 
 		if arg.Verbose {
@@ -163,6 +163,28 @@ func VerifyCatalogSignature(
 	return cat_file, nil
 }
 
+// Verify a file which is not signed itself but whose hash is listed
+// in a signed catalog file. Returns NULL if no catalog contains the
+// file.
+func VerifyCatalog(scope vfilter.Scope, normalized_path string) vfilter.Any {
+	fd, err := os.Open(normalized_path)
+	if err != nil {
+		return vfilter.Null{}
+	}
+	defer fd.Close()
+
+	config_obj, _ := vql_subsystem.GetServerConfig(scope)
+
+	output := ordereddict.NewDict().Set("Trusted", "untrusted")
+	cat_file, err := VerifyCatalogSignature(
+		config_obj, scope, fd, normalized_path, output)
+	if err != nil || cat_file == "" {
+		return vfilter.Null{}
+	}
+
+	return output.Set("Catalog", cat_file)
+}
+
 func ParseCatFile(cat_file string, output *ordereddict.Dict, verbose bool) error {
 
 	// Set the catalog file even if we can not read it - will be
@@ -0,0 +1,328 @@
+package authenticode
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/Velocidex/pkcs7"
+	"www.velocidex.com/golang/go-pe"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// Returned by VerifyFileSignature when there is no OS API to verify
+// the signature with.
+const NO_API_ACCESS = "Unknown (No API access)"
+
+var (
+	oidCounterSignature     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 6}
+	oidRFC3161Timestamp     = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 3, 3, 1}
+	oidAttributeSigningTime = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidAttributeDigest      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+
+	oidDigestSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidDigestSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidDigestSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidDigestSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+)
+
+type attribute struct {
+	Type  asn1.ObjectIdentifier
+	Value asn1.RawValue `asn1:"set"`
+}
+
+type issuerAndSerial struct {
+	IssuerName   asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// A counter signature is a SignerInfo over the encrypted digest of
+// the signature it counter signs. We keep the authenticated
+// attributes raw because the signature is calculated over their DER
+// encoding.
+type counterSignature struct {
+	Version                   int `asn1:"default:1"`
+	IssuerAndSerialNumber     issuerAndSerial
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+}
+
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint asn1.RawValue
+	SerialNumber   *big.Int
+	GenTime        time.Time `asn1:"generalized"`
+}
+
+// Verify the signature in a PKCS7 object and build a certificate
+// chain from the signer to a trusted root. If roots is nil we use
+// the platform trust store (On Windows and MacOS this uses the OS
+// APIs to validate the chain).
+//
+// If the signature carries a valid timestamp, the chain is validated
+// at the time of signing so signatures made with now expired
+// certificates remain trusted.
+func VerifyPKCS7(p7 *pkcs7.PKCS7, roots *x509.CertPool) *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("Trusted", "untrusted").
+		Set("SignatureValid", false).
+		Set("Timestamp", vfilter.Null{}).
+		Set("Chain", []string{})
+
+	if len(p7.Signers) == 0 {
+		result.Update("Trusted", "untrusted (No signers)")
+		return result
+	}
+
+	err := p7.Verify()
+	if err != nil {
+		result.Update("Trusted", fmt.Sprintf("untrusted (%v)", err))
+		return result
+	}
+	result.Update("SignatureValid", true)
+
+	signer := &p7.Signers[0]
+	signer_cert := findCertificate(p7.Certificates,
+		signer.IssuerAndSerialNumber.IssuerName.FullBytes,
+		signer.IssuerAndSerialNumber.SerialNumber)
+	if signer_cert == nil {
+		result.Update("Trusted", "untrusted (No certificate for signer)")
+		return result
+	}
+
+	verify_time := time.Now()
+	timestamp, err := getTimestamp(p7, signer)
+	if err == nil {
+		result.Update("Timestamp", timestamp)
+		verify_time = timestamp
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range p7.Certificates {
+		intermediates.AddCert(cert)
+	}
+
+	chains, err := signer_cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		CurrentTime:   verify_time,
+	})
+	if err != nil {
+		result.Update("Trusted", fmt.Sprintf("untrusted (%v)", err))
+		return result
+	}
+
+	chain := []string{}
+	if len(chains) > 0 {
+		for _, cert := range chains[0] {
+			chain = append(chain, cert.Subject.String())
+		}
+	}
+
+	return result.Update("Trusted", "trusted").Update("Chain", chain)
+}
+
+// Verify the embedded Authenticode signature of a PE file. In
+// addition to the checks made by VerifyPKCS7 the file's hash must
+// match the signed hash. Returns nil if the file is not signed.
+func VerifyAuthenticode(pe_file *pe.PEFile) *ordereddict.Dict {
+	p7, err := pe.ParseAuthenticode(pe_file)
+	if err != nil {
+		return nil
+	}
+
+	hash_matches, _ := pe_file.CalcHashToDict().GetBool("HashMatches")
+
+	result := VerifyPKCS7(p7, nil)
+	result.Set("HashMatches", hash_matches)
+	if !hash_matches {
+		result.Update("Trusted", "untrusted (Hash mismatch)")
+	}
+
+	return result
+}
+
+// Signatures may be timestamped with an RFC3161 token or an older
+// style counter signature. Only timestamps with a valid signature
+// are considered.
+func getTimestamp(p7 *pkcs7.PKCS7, signer *pkcs7.SignerInfo) (time.Time, error) {
+	for _, attr := range signer.UnauthenticatedAttributes {
+		switch {
+		case attr.Type.Equal(oidRFC3161Timestamp):
+			token, err := pkcs7.Parse(attr.Value.Bytes)
+			if err != nil {
+				continue
+			}
+
+			err = token.Verify()
+			if err != nil {
+				continue
+			}
+
+			info := &tstInfo{}
+			_, err = asn1.Unmarshal(token.Content, info)
+			if err != nil {
+				continue
+			}
+			return info.GenTime, nil
+
+		case attr.Type.Equal(oidCounterSignature):
+			timestamp, err := verifyCounterSignature(
+				p7, attr.Value.Bytes, signer.EncryptedDigest)
+			if err != nil {
+				continue
+			}
+			return timestamp, nil
+		}
+	}
+
+	return time.Time{}, errors.New("No timestamp")
+}
+
+func verifyCounterSignature(
+	p7 *pkcs7.PKCS7, data []byte, signed_digest []byte) (time.Time, error) {
+	counter := &counterSignature{}
+	_, err := asn1.Unmarshal(data, counter)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// The authenticated attributes are signed as a SET OF but
+	// stored with an implicit tag.
+	signed_data := append([]byte{}, counter.AuthenticatedAttributes.FullBytes...)
+	if len(signed_data) == 0 {
+		return time.Time{}, errors.New("No authenticated attributes")
+	}
+	signed_data[0] = 0x31
+
+	var attributes []attribute
+	_, err = asn1.UnmarshalWithParams(signed_data, &attributes, "set")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	hash, err := getHash(counter.DigestAlgorithm.Algorithm)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var digest []byte
+	var signing_time time.Time
+	for _, attr := range attributes {
+		switch {
+		case attr.Type.Equal(oidAttributeDigest):
+			_, err = asn1.Unmarshal(attr.Value.Bytes, &digest)
+		case attr.Type.Equal(oidAttributeSigningTime):
+			_, err = asn1.Unmarshal(attr.Value.Bytes, &signing_time)
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	// The counter signature must be over our signature.
+	h := hash.New()
+	h.Write(signed_digest)
+	if !bytes.Equal(h.Sum(nil), digest) {
+		return time.Time{}, errors.New("Counter signature digest mismatch")
+	}
+
+	cert := findCertificate(p7.Certificates,
+		counter.IssuerAndSerialNumber.IssuerName.FullBytes,
+		counter.IssuerAndSerialNumber.SerialNumber)
+	if cert == nil {
+		return time.Time{}, errors.New("No certificate for counter signer")
+	}
+
+	algorithm, err := getSignatureAlgorithm(cert, hash)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = cert.CheckSignature(algorithm, signed_data, counter.EncryptedDigest)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if signing_time.IsZero() {
+		return time.Time{}, errors.New("No signing time")
+	}
+
+	return signing_time, nil
+}
+
+func findCertificate(certs []*x509.Certificate,
+	issuer []byte, serial *big.Int) *x509.Certificate {
+	if serial == nil {
+		return nil
+	}
+
+	for _, cert := range certs {
+		if cert.SerialNumber.Cmp(serial) == 0 &&
+			bytes.Equal(cert.RawIssuer, issuer) {
+			return cert
+		}
+	}
+	return nil
+}
+
+func getHash(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case oid.Equal(oidDigestSHA1):
+		return crypto.SHA1, nil
+	case oid.Equal(oidDigestSHA256):
+		return crypto.SHA256, nil
+	case oid.Equal(oidDigestSHA384):
+		return crypto.SHA384, nil
+	case oid.Equal(oidDigestSHA512):
+		return crypto.SHA512, nil
+	}
+	return 0, fmt.Errorf("Unsupported digest algorithm %v", oid)
+}
+
+func getSignatureAlgorithm(
+	cert *x509.Certificate, hash crypto.Hash) (x509.SignatureAlgorithm, error) {
+	switch cert.PublicKeyAlgorithm {
+	case x509.RSA:
+		switch hash {
+		case crypto.SHA1:
+			return x509.SHA1WithRSA, nil
+		case crypto.SHA256:
+			return x509.SHA256WithRSA, nil
+		case crypto.SHA384:
+			return x509.SHA384WithRSA, nil
+		case crypto.SHA512:
+			return x509.SHA512WithRSA, nil
+		}
+
+	case x509.ECDSA:
+		switch hash {
+		case crypto.SHA1:
+			return x509.ECDSAWithSHA1, nil
+		case crypto.SHA256:
+			return x509.ECDSAWithSHA256, nil
+		case crypto.SHA384:
+			return x509.ECDSAWithSHA384, nil
+		case crypto.SHA512:
+			return x509.ECDSAWithSHA512, nil
+		}
+	}
+
+	return x509.UnknownSignatureAlgorithm, fmt.Errorf(
+		"Unsupported signature algorithm %v with %v",
+		cert.PublicKeyAlgorithm, hash)
+}
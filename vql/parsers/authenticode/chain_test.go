@@ -0,0 +1,208 @@
+package authenticode
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/pkcs7"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func makeCert(t *testing.T, name string, serial int64,
+	not_before, not_after time.Time,
+	usage []x509.ExtKeyUsage, issuer *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    not_before,
+		NotAfter:     not_after,
+		ExtKeyUsage:  usage,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	parent, parent_key := template, key
+	if issuer == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parent, parent_key = issuer.cert, issuer.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent,
+		&key.PublicKey, parent_key)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return &testCert{cert: cert, key: key}
+}
+
+type testCounterSignature struct {
+	Version                   int
+	IssuerAndSerialNumber     issuerAndSerial
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+}
+
+func makeAttribute(t *testing.T, oid asn1.ObjectIdentifier, value interface{}) attribute {
+	encoded, err := asn1.Marshal(value)
+	assert.NoError(t, err)
+
+	return attribute{Type: oid, Value: asn1.RawValue{
+		Tag: asn1.TagSet, IsCompound: true, Bytes: encoded}}
+}
+
+// Counter sign the signature with the timestamping certificate.
+func counterSign(t *testing.T, tsa *testCert,
+	signature []byte, signing_time time.Time) testCounterSignature {
+	digest := crypto.SHA256.New()
+	digest.Write(signature)
+
+	attributes, err := asn1.MarshalWithParams([]attribute{
+		makeAttribute(t, oidAttributeDigest, digest.Sum(nil)),
+		makeAttribute(t, oidAttributeSigningTime, signing_time),
+	}, "set")
+	assert.NoError(t, err)
+
+	hash := crypto.SHA256.New()
+	hash.Write(attributes)
+	encrypted_digest, err := tsa.key.Sign(rand.Reader, hash.Sum(nil), crypto.SHA256)
+	assert.NoError(t, err)
+
+	return testCounterSignature{
+		Version: 1,
+		IssuerAndSerialNumber: issuerAndSerial{
+			IssuerName:   asn1.RawValue{FullBytes: tsa.cert.RawIssuer},
+			SerialNumber: tsa.cert.SerialNumber,
+		},
+		DigestAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidDigestSHA256},
+		AuthenticatedAttributes: asn1.RawValue{
+			FullBytes: append([]byte{0xa0}, attributes[1:]...)},
+		DigestEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+			Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+		EncryptedDigest: encrypted_digest,
+	}
+}
+
+func TestVerifyPKCS7(t *testing.T) {
+	now := time.Now()
+	ca := makeCert(t, "Test Root", 1, now.AddDate(-10, 0, 0),
+		now.AddDate(10, 0, 0), nil, nil)
+	signer := makeCert(t, "Test Signer", 2, now.AddDate(-1, 0, 0),
+		now.AddDate(1, 0, 0),
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}, ca)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	sign := func(signer *testCert, content []byte,
+		counter_sign func(signature []byte) []pkcs7.Attribute) *pkcs7.PKCS7 {
+		signed_data, err := pkcs7.NewSignedData(content)
+		assert.NoError(t, err)
+		signed_data.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+
+		err = signed_data.SignWithoutAttr(signer.cert, signer.key,
+			pkcs7.SignerInfoConfig{})
+		assert.NoError(t, err)
+		signed_data.AddCertificate(ca.cert)
+
+		if counter_sign != nil {
+			signer_info := &signed_data.GetSignedData().SignerInfos[0]
+			err = signer_info.SetUnauthenticatedAttributes(
+				counter_sign(signer_info.EncryptedDigest))
+			assert.NoError(t, err)
+		}
+
+		der, err := signed_data.Finish()
+		assert.NoError(t, err)
+
+		p7, err := pkcs7.Parse(der)
+		assert.NoError(t, err)
+		return p7
+	}
+
+	result := VerifyPKCS7(sign(signer, []byte("hello"), nil), roots)
+	trusted, _ := result.GetString("Trusted")
+	assert.Equal(t, "trusted", trusted)
+
+	chain, _ := result.Get("Chain")
+	assert.Equal(t, []string{"CN=Test Signer", "CN=Test Root"}, chain)
+
+	// Not signed by a trusted root.
+	result = VerifyPKCS7(sign(signer, []byte("hello"), nil), x509.NewCertPool())
+	trusted, _ = result.GetString("Trusted")
+	assert.True(t, strings.HasPrefix(trusted, "untrusted"))
+
+	valid, _ := result.GetBool("SignatureValid")
+	assert.True(t, valid)
+
+	// Content was modified after signing.
+	p7 := sign(signer, []byte("hello"), nil)
+	p7.Content = []byte("goodbye")
+	result = VerifyPKCS7(p7, roots)
+	valid, _ = result.GetBool("SignatureValid")
+	assert.True(t, !valid)
+
+	// An expired signer is trusted when the signature was
+	// timestamped while the certificate was valid.
+	expired := makeCert(t, "Expired Signer", 3, now.AddDate(-3, 0, 0),
+		now.AddDate(-2, 0, 0),
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}, ca)
+	tsa := makeCert(t, "Test TSA", 4, now.AddDate(-5, 0, 0),
+		now.AddDate(5, 0, 0),
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping}, ca)
+	signing_time := now.AddDate(-2, -6, 0).UTC().Truncate(time.Second)
+
+	result = VerifyPKCS7(sign(expired, []byte("hello"), nil), roots)
+	trusted, _ = result.GetString("Trusted")
+	assert.True(t, strings.Contains(trusted, "expired"))
+
+	p7 = sign(expired, []byte("hello"), func(signature []byte) []pkcs7.Attribute {
+		return []pkcs7.Attribute{{
+			Type:  oidCounterSignature,
+			Value: counterSign(t, tsa, signature, signing_time),
+		}}
+	})
+	p7.Certificates = append(p7.Certificates, tsa.cert)
+
+	result = VerifyPKCS7(p7, roots)
+	trusted, _ = result.GetString("Trusted")
+	assert.Equal(t, "trusted", trusted)
+
+	timestamp, _ := result.Get("Timestamp")
+	assert.Equal(t, signing_time, timestamp)
+
+	// A counter signature over a different signature is ignored.
+	p7 = sign(expired, []byte("hello"), func(signature []byte) []pkcs7.Attribute {
+		return []pkcs7.Attribute{{
+			Type:  oidCounterSignature,
+			Value: counterSign(t, tsa, []byte("other"), signing_time),
+		}}
+	})
+	p7.Certificates = append(p7.Certificates, tsa.cert)
+
+	result = VerifyPKCS7(p7, roots)
+	trusted, _ = result.GetString("Trusted")
+	assert.True(t, strings.Contains(trusted, "expired"))
+}
@@ -16,7 +16,7 @@ import (
 func VerifyFileSignature(
 	scope vfilter.Scope,
 	normalized_path string) string {
-	return "Unknown (No API access)"
+	return NO_API_ACCESS
 }
 
 func VerifyCatalogSignature(
@@ -24,7 +24,11 @@ func VerifyCatalogSignature(
 	scope vfilter.Scope,
 	fd *os.File, normalized_path string,
 	output *ordereddict.Dict) (string, error) {
-	return "Unknown (No API access)", nil
+	return NO_API_ACCESS, nil
+}
+
+func VerifyCatalog(scope vfilter.Scope, normalized_path string) vfilter.Any {
+	return vfilter.Null{}
 }
 
 func ParseCatFile(cat_file string, output *ordereddict.Dict, verbose bool) error {
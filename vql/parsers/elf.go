@@ -0,0 +1,303 @@
+package parsers
+
+import (
+	"bytes"
+	"context"
+	"debug/elf"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/constants"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/readers"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type _ELFFunctionArgs struct {
+	Filename *accessors.OSPath `vfilter:"required,field=file,doc=The ELF file to open."`
+	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use."`
+}
+
+type _ELFFunction struct{}
+
+func (self _ELFFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "parse_elf",
+		Doc:      "Parse an ELF file.",
+		ArgType:  type_map.AddType(scope, &_ELFFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+func (self _ELFFunction) Call(
+	ctx context.Context, scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	defer utils.RecoverVQL(scope)
+
+	arg := &_ELFFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("parse_elf: %v", err)
+		return &vfilter.Null{}
+	}
+
+	err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+	if err != nil {
+		scope.Log("parse_elf: %s", err)
+		return &vfilter.Null{}
+	}
+
+	lru_size := vql_subsystem.GetIntFromRow(scope, scope, constants.BINARY_CACHE_SIZE)
+	paged_reader, err := readers.NewPagedReader(
+		scope, arg.Accessor, arg.Filename, int(lru_size))
+	if err != nil {
+		return &vfilter.Null{}
+	}
+	defer paged_reader.Close()
+
+	elf_file, err := elf.NewFile(paged_reader)
+	if err != nil {
+		// Suppress logging for invalid ELF files.
+		return &vfilter.Null{}
+	}
+
+	// Return a lazy object.
+	return ordereddict.NewDict().
+		Set("Class", elf_file.Class.String()).
+		Set("Data", elf_file.Data.String()).
+		Set("OSABI", elf_file.OSABI.String()).
+		Set("Type", elf_file.Type.String()).
+		Set("Machine", elf_file.Machine.String()).
+		Set("Entry", elf_file.Entry).
+		Set("Interpreter", func() vfilter.Any {
+			return getELFInterpreter(elf_file)
+		}).
+		Set("SOName", func() vfilter.Any {
+			return getELFDynString(elf_file, elf.DT_SONAME)
+		}).
+		Set("RunPath", func() vfilter.Any {
+			return append(getELFDynString(elf_file, elf.DT_RPATH),
+				getELFDynString(elf_file, elf.DT_RUNPATH)...)
+		}).
+		Set("BuildID", func() vfilter.Any {
+			return getELFBuildID(elf_file)
+		}).
+		Set("Sections", func() vfilter.Any {
+			return getELFSections(elf_file)
+		}).
+		Set("Segments", func() vfilter.Any {
+			return getELFSegments(elf_file)
+		}).
+		Set("Libraries", func() vfilter.Any {
+			libraries, _ := elf_file.ImportedLibraries()
+			return libraries
+		}).
+		Set("Imports", func() vfilter.Any {
+			return getELFImports(elf_file)
+		}).
+		Set("Exports", func() vfilter.Any {
+			return getELFExports(elf_file)
+		}).
+		Set("Overlay", func() vfilter.Any {
+			return getELFOverlay(elf_file, paged_reader, paged_reader.MaxSize())
+		})
+}
+
+func getELFInterpreter(elf_file *elf.File) vfilter.Any {
+	for _, prog := range elf_file.Progs {
+		if prog.Type == elf.PT_INTERP && prog.Filesz < 4096 {
+			data := make([]byte, prog.Filesz)
+			n, _ := prog.ReadAt(data, 0)
+			return string(bytes.TrimRight(data[:n], "\x00"))
+		}
+	}
+	return vfilter.Null{}
+}
+
+func getELFDynString(elf_file *elf.File, tag elf.DynTag) []string {
+	result, _ := elf_file.DynString(tag)
+	if result == nil {
+		return []string{}
+	}
+	return result
+}
+
+// The build id is stored in a GNU note. The linker generates a unique
+// build id for each build which is also used to find debug symbols.
+func getELFBuildID(elf_file *elf.File) vfilter.Any {
+	for _, section := range elf_file.Sections {
+		if section.Type != elf.SHT_NOTE {
+			continue
+		}
+
+		data, err := section.Data()
+		if err != nil {
+			continue
+		}
+
+		for len(data) >= 12 {
+			name_size := int(elf_file.ByteOrder.Uint32(data))
+			desc_size := int(elf_file.ByteOrder.Uint32(data[4:]))
+			note_type := elf_file.ByteOrder.Uint32(data[8:])
+
+			name_end := 12 + alignUp(name_size, 4)
+			desc_end := name_end + alignUp(desc_size, 4)
+			if name_size < 0 || desc_size < 0 || desc_end > len(data) {
+				break
+			}
+
+			// NT_GNU_BUILD_ID
+			if note_type == 3 && string(data[12:12+name_size]) == "GNU\x00" {
+				return hex.EncodeToString(data[name_end : name_end+desc_size])
+			}
+			data = data[desc_end:]
+		}
+	}
+	return vfilter.Null{}
+}
+
+func alignUp(value, alignment int) int {
+	return (value + alignment - 1) &^ (alignment - 1)
+}
+
+func getELFSections(elf_file *elf.File) []*ordereddict.Dict {
+	result := []*ordereddict.Dict{}
+	for _, section := range elf_file.Sections {
+		result = append(result, ordereddict.NewDict().
+			Set("Name", section.Name).
+			Set("Type", section.Type.String()).
+			Set("Flags", section.Flags.String()).
+			Set("Addr", section.Addr).
+			Set("Offset", section.Offset).
+			Set("Size", section.Size))
+	}
+	return result
+}
+
+func getELFSegments(elf_file *elf.File) []*ordereddict.Dict {
+	result := []*ordereddict.Dict{}
+	for _, prog := range elf_file.Progs {
+		result = append(result, ordereddict.NewDict().
+			Set("Type", prog.Type.String()).
+			Set("Flags", prog.Flags.String()).
+			Set("Offset", prog.Off).
+			Set("VAddr", prog.Vaddr).
+			Set("FileSize", prog.Filesz).
+			Set("MemSize", prog.Memsz))
+	}
+	return result
+}
+
+// Imports are formatted like parse_pe() as library!symbol when the
+// symbol version tells us which library it comes from.
+func getELFImports(elf_file *elf.File) []string {
+	result := []string{}
+	symbols, _ := elf_file.ImportedSymbols()
+	for _, symbol := range symbols {
+		if symbol.Library != "" {
+			result = append(result, symbol.Library+"!"+symbol.Name)
+		} else {
+			result = append(result, symbol.Name)
+		}
+	}
+	return result
+}
+
+func getELFExports(elf_file *elf.File) []string {
+	result := []string{}
+	symbols, _ := elf_file.DynamicSymbols()
+	for _, symbol := range symbols {
+		if symbol.Section == elf.SHN_UNDEF || symbol.Name == "" {
+			continue
+		}
+
+		switch elf.ST_BIND(symbol.Info) {
+		case elf.STB_GLOBAL, elf.STB_WEAK:
+		default:
+			continue
+		}
+
+		switch elf.ST_TYPE(symbol.Info) {
+		case elf.STT_FUNC, elf.STT_OBJECT:
+			result = append(result, symbol.Name)
+		}
+	}
+	return result
+}
+
+// Data after all the sections, segments and the section header
+// table is not used by the loader.
+func getELFOverlay(elf_file *elf.File,
+	reader io.ReaderAt, size int64) vfilter.Any {
+	var start uint64
+
+	extend := func(end uint64) {
+		if end > start {
+			start = end
+		}
+	}
+
+	for _, section := range elf_file.Sections {
+		if section.Type != elf.SHT_NOBITS {
+			extend(section.Offset + section.Size)
+		}
+	}
+
+	for _, prog := range elf_file.Progs {
+		extend(prog.Off + prog.Filesz)
+	}
+
+	// The section header table is usually at the end of the file.
+	header, err := getELFSectionHeaderTable(elf_file, reader)
+	if err == nil {
+		extend(header)
+	}
+
+	if start == 0 || int64(start) >= size {
+		return vfilter.Null{}
+	}
+
+	return ordereddict.NewDict().
+		Set("Offset", start).
+		Set("Size", uint64(size)-start)
+}
+
+// Returns the end of the section header table. The elf package does
+// not expose its location so we read it from the ELF header.
+func getELFSectionHeaderTable(
+	elf_file *elf.File, reader io.ReaderAt) (uint64, error) {
+	header_reader := io.NewSectionReader(reader, 0, 64)
+
+	switch elf_file.Class {
+	case elf.ELFCLASS64:
+		header := &elf.Header64{}
+		err := binary.Read(header_reader, elf_file.ByteOrder, header)
+		if err != nil {
+			return 0, err
+		}
+		return header.Shoff + uint64(header.Shnum)*uint64(header.Shentsize), nil
+
+	case elf.ELFCLASS32:
+		header := &elf.Header32{}
+		err := binary.Read(header_reader, elf_file.ByteOrder, header)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(header.Shoff) + uint64(header.Shnum)*uint64(header.Shentsize), nil
+	}
+
+	return 0, fmt.Errorf("Unknown ELF class")
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&_ELFFunction{})
+}
@@ -0,0 +1,81 @@
+package parsers
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	pe "www.velocidex.com/golang/go-pe"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/constants"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/readers"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type _ImpHashFunctionArgs struct {
+	Filename *accessors.OSPath `vfilter:"required,field=file,doc=The PE file to open."`
+	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use (use 'data' to hash a string)."`
+}
+
+// The import hash is the md5 of the normalized import table. Binaries
+// built from the same source usually share the same import hash even
+// when their content hash differs.
+type _ImpHashFunction struct{}
+
+func (self _ImpHashFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "imphash",
+		Doc:      "Calculate the import hash of a PE file.",
+		ArgType:  type_map.AddType(scope, &_ImpHashFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+func (self _ImpHashFunction) Call(
+	ctx context.Context, scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	defer utils.RecoverVQL(scope)
+
+	arg := &_ImpHashFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("imphash: %v", err)
+		return &vfilter.Null{}
+	}
+
+	err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+	if err != nil {
+		scope.Log("imphash: %s", err)
+		return &vfilter.Null{}
+	}
+
+	lru_size := vql_subsystem.GetIntFromRow(scope, scope, constants.BINARY_CACHE_SIZE)
+	paged_reader, err := readers.NewPagedReader(
+		scope, arg.Accessor, arg.Filename, int(lru_size))
+	if err != nil {
+		return &vfilter.Null{}
+	}
+	defer paged_reader.Close()
+
+	pe_file, err := pe.NewPEFileWithSize(paged_reader, paged_reader.MaxSize())
+	if err != nil {
+		// Suppress logging for invalid PE files.
+		return &vfilter.Null{}
+	}
+
+	// Binaries without imports do not have an import hash.
+	if len(pe_file.Imports()) == 0 {
+		return &vfilter.Null{}
+	}
+
+	return pe_file.ImpHash()
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&_ImpHashFunction{})
+}
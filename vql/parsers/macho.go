@@ -0,0 +1,401 @@
+package parsers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"debug/macho"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/Velocidex/pkcs7"
+	pe "www.velocidex.com/golang/go-pe"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/constants"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/parsers/authenticode"
+	"www.velocidex.com/golang/velociraptor/vql/readers"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	LC_UUID           = 0x1b
+	LC_CODE_SIGNATURE = 0x1d
+
+	CSMAGIC_EMBEDDED_SIGNATURE    = 0xfade0cc0
+	CSMAGIC_CODEDIRECTORY         = 0xfade0c02
+	CSMAGIC_EMBEDDED_ENTITLEMENTS = 0xfade7171
+	CSMAGIC_BLOBWRAPPER           = 0xfade0b01
+
+	CSSLOT_CODEDIRECTORY      = 0
+	CSSLOT_ALTERNATE_CODEDIRS = 0x1000
+	CSSLOT_SIGNATURESLOT      = 0x10000
+
+	CS_ADHOC = 0x2
+
+	// Code signatures are small, this protects against corrupt
+	// load commands.
+	maxCodeSignatureSize = 10 * 1024 * 1024
+)
+
+type _MachoFunctionArgs struct {
+	Filename *accessors.OSPath `vfilter:"required,field=file,doc=The Mach-O file to open."`
+	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use."`
+}
+
+type _MachoFunction struct{}
+
+func (self _MachoFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "parse_macho",
+		Doc:      "Parse a Mach-O file including universal (fat) binaries.",
+		ArgType:  type_map.AddType(scope, &_MachoFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+func (self _MachoFunction) Call(
+	ctx context.Context, scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	defer utils.RecoverVQL(scope)
+
+	arg := &_MachoFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("parse_macho: %v", err)
+		return &vfilter.Null{}
+	}
+
+	err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+	if err != nil {
+		scope.Log("parse_macho: %s", err)
+		return &vfilter.Null{}
+	}
+
+	lru_size := vql_subsystem.GetIntFromRow(scope, scope, constants.BINARY_CACHE_SIZE)
+	paged_reader, err := readers.NewPagedReader(
+		scope, arg.Accessor, arg.Filename, int(lru_size))
+	if err != nil {
+		return &vfilter.Null{}
+	}
+	defer paged_reader.Close()
+
+	// Universal binaries contain a Mach-O file for each
+	// architecture.
+	fat_file, err := macho.NewFatFile(paged_reader)
+	if err == nil {
+		architectures := []*ordereddict.Dict{}
+		for _, arch := range fat_file.Arches {
+			reader := io.NewSectionReader(paged_reader,
+				int64(arch.Offset), int64(arch.Size))
+			architectures = append(architectures,
+				getMachoInfo(scope, arch.File, reader).
+					Set("Offset", arch.Offset).
+					Set("Size", arch.Size))
+		}
+
+		return ordereddict.NewDict().
+			Set("Universal", true).
+			Set("Architectures", architectures)
+	}
+
+	macho_file, err := macho.NewFile(paged_reader)
+	if err != nil {
+		// Suppress logging for invalid Mach-O files.
+		return &vfilter.Null{}
+	}
+
+	return getMachoInfo(scope, macho_file, paged_reader)
+}
+
+// Return a lazy object describing a single architecture.
+func getMachoInfo(scope vfilter.Scope,
+	macho_file *macho.File, reader io.ReaderAt) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Cpu", macho_file.Cpu.String()).
+		Set("SubCpu", macho_file.SubCpu).
+		Set("Type", macho_file.Type.String()).
+		Set("Flags", macho_file.Flags).
+		Set("UUID", func() vfilter.Any {
+			return getMachoUUID(macho_file)
+		}).
+		Set("Segments", func() vfilter.Any {
+			return getMachoSegments(macho_file)
+		}).
+		Set("Sections", func() vfilter.Any {
+			return getMachoSections(macho_file)
+		}).
+		Set("Libraries", func() vfilter.Any {
+			libraries, _ := macho_file.ImportedLibraries()
+			return libraries
+		}).
+		Set("RPaths", func() vfilter.Any {
+			return getMachoRPaths(macho_file)
+		}).
+		Set("Imports", func() vfilter.Any {
+			imports, _ := macho_file.ImportedSymbols()
+			return imports
+		}).
+		Set("Exports", func() vfilter.Any {
+			return getMachoExports(macho_file)
+		}).
+		Set("CodeSignature", func() vfilter.Any {
+			defer utils.RecoverVQL(scope)
+
+			return getMachoCodeSignature(macho_file, reader)
+		})
+}
+
+func getMachoUUID(macho_file *macho.File) vfilter.Any {
+	for _, load := range macho_file.Loads {
+		raw := load.Raw()
+		if len(raw) >= 24 && macho_file.ByteOrder.Uint32(raw) == LC_UUID {
+			uuid := raw[8:24]
+			return fmt.Sprintf("%X-%X-%X-%X-%X",
+				uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+		}
+	}
+	return vfilter.Null{}
+}
+
+func getMachoSegments(macho_file *macho.File) []*ordereddict.Dict {
+	result := []*ordereddict.Dict{}
+	for _, load := range macho_file.Loads {
+		segment, ok := load.(*macho.Segment)
+		if !ok {
+			continue
+		}
+
+		result = append(result, ordereddict.NewDict().
+			Set("Name", segment.Name).
+			Set("Offset", segment.Offset).
+			Set("FileSize", segment.Filesz).
+			Set("Addr", segment.Addr).
+			Set("MemSize", segment.Memsz).
+			Set("MaxProt", segment.Maxprot).
+			Set("Prot", segment.Prot))
+	}
+	return result
+}
+
+func getMachoSections(macho_file *macho.File) []*ordereddict.Dict {
+	result := []*ordereddict.Dict{}
+	for _, section := range macho_file.Sections {
+		result = append(result, ordereddict.NewDict().
+			Set("Name", section.Name).
+			Set("Segment", section.Seg).
+			Set("Addr", section.Addr).
+			Set("Offset", section.Offset).
+			Set("Size", section.Size))
+	}
+	return result
+}
+
+func getMachoRPaths(macho_file *macho.File) []string {
+	result := []string{}
+	for _, load := range macho_file.Loads {
+		rpath, ok := load.(*macho.Rpath)
+		if ok {
+			result = append(result, rpath.Path)
+		}
+	}
+	return result
+}
+
+func getMachoExports(macho_file *macho.File) []string {
+	result := []string{}
+	if macho_file.Symtab == nil {
+		return result
+	}
+
+	for _, symbol := range macho_file.Symtab.Syms {
+		// External symbols defined in a section.
+		if symbol.Type&0x01 != 0 && symbol.Type&0x0e == 0x0e &&
+			symbol.Sect != 0 {
+			result = append(result, symbol.Name)
+		}
+	}
+	return result
+}
+
+// Parse the embedded code signature. The signature is a super blob
+// containing the code directories (which hash each page of the
+// binary), the requirements, the entitlements and a detached CMS
+// signature over the first code directory.
+func getMachoCodeSignature(
+	macho_file *macho.File, reader io.ReaderAt) vfilter.Any {
+	var data []byte
+
+	for _, load := range macho_file.Loads {
+		raw := load.Raw()
+		if len(raw) < 16 || macho_file.ByteOrder.Uint32(raw) != LC_CODE_SIGNATURE {
+			continue
+		}
+
+		offset := macho_file.ByteOrder.Uint32(raw[8:])
+		size := macho_file.ByteOrder.Uint32(raw[12:])
+		if size > maxCodeSignatureSize {
+			return vfilter.Null{}
+		}
+
+		data = make([]byte, size)
+		n, _ := reader.ReadAt(data, int64(offset))
+		data = data[:n]
+	}
+
+	if len(data) < 12 ||
+		binary.BigEndian.Uint32(data) != CSMAGIC_EMBEDDED_SIGNATURE {
+		return vfilter.Null{}
+	}
+
+	result := ordereddict.NewDict().
+		Set("Identifier", "").
+		Set("TeamID", "").
+		Set("AdHoc", false).
+		Set("CodeDirectories", []*ordereddict.Dict{}).
+		Set("Entitlements", "").
+		Set("Signer", vfilter.Null{}).
+		Set("Verification", vfilter.Null{})
+
+	code_directories := []*ordereddict.Dict{}
+	var main_code_directory []byte
+	var cms []byte
+
+	count := int(binary.BigEndian.Uint32(data[8:]))
+	for i := 0; i < count && 12+i*8+8 <= len(data); i++ {
+		index := data[12+i*8:]
+		slot := binary.BigEndian.Uint32(index)
+		blob := getBlob(data, binary.BigEndian.Uint32(index[4:]))
+		if blob == nil {
+			continue
+		}
+		magic := binary.BigEndian.Uint32(blob)
+
+		switch {
+		case magic == CSMAGIC_CODEDIRECTORY &&
+			(slot == CSSLOT_CODEDIRECTORY || slot >= CSSLOT_ALTERNATE_CODEDIRS &&
+				slot < CSSLOT_ALTERNATE_CODEDIRS+5):
+			code_directory := parseCodeDirectory(blob)
+			if code_directory == nil {
+				continue
+			}
+			code_directories = append(code_directories, code_directory)
+
+			if slot == CSSLOT_CODEDIRECTORY {
+				main_code_directory = blob
+				result.Update("Identifier", utils.GetString(code_directory, "Identifier")).
+					Update("TeamID", utils.GetString(code_directory, "TeamID"))
+
+				flags := binary.BigEndian.Uint32(blob[12:])
+				result.Update("AdHoc", flags&CS_ADHOC != 0)
+			}
+
+		case magic == CSMAGIC_EMBEDDED_ENTITLEMENTS:
+			result.Update("Entitlements", string(blob[8:]))
+
+		case magic == CSMAGIC_BLOBWRAPPER && slot == CSSLOT_SIGNATURESLOT:
+			cms = blob[8:]
+		}
+	}
+	result.Update("CodeDirectories", code_directories)
+
+	// Ad hoc signed binaries have an empty CMS blob.
+	if len(cms) > 0 && main_code_directory != nil {
+		p7, err := pkcs7.Parse(cms)
+		if err == nil {
+			// The signature is detached - it signs the code
+			// directory.
+			p7.Content = main_code_directory
+			result.Update("Signer", pe.PKCS7ToOrderedDict(p7)).
+				Update("Verification", authenticode.VerifyPKCS7(p7, nil))
+		}
+	}
+
+	return result
+}
+
+// Blobs start with a magic and their length including the header.
+func getBlob(data []byte, offset uint32) []byte {
+	if uint64(offset)+8 > uint64(len(data)) {
+		return nil
+	}
+
+	length := binary.BigEndian.Uint32(data[offset+4:])
+	if length < 8 || uint64(offset)+uint64(length) > uint64(len(data)) {
+		return nil
+	}
+	return data[offset : offset+length]
+}
+
+func parseCodeDirectory(blob []byte) *ordereddict.Dict {
+	if len(blob) < 44 {
+		return nil
+	}
+
+	version := binary.BigEndian.Uint32(blob[8:])
+	flags := binary.BigEndian.Uint32(blob[12:])
+	ident_offset := binary.BigEndian.Uint32(blob[20:])
+	hash_type := blob[37]
+
+	var team_id string
+	if version >= 0x20200 && len(blob) >= 52 {
+		team_id = getCString(blob, binary.BigEndian.Uint32(blob[48:]))
+	}
+
+	// The CDHash identifies the signed code. It is the hash of the
+	// code directory truncated to 20 bytes.
+	var hash_name string
+	var cd_hash []byte
+	switch hash_type {
+	case 1:
+		hash_name = "SHA1"
+		sum := sha1.Sum(blob)
+		cd_hash = sum[:]
+	case 2, 3:
+		hash_name = "SHA256"
+		sum := sha256.Sum256(blob)
+		cd_hash = sum[:]
+	case 4:
+		hash_name = "SHA384"
+		sum := sha512.Sum384(blob)
+		cd_hash = sum[:]
+	}
+	if len(cd_hash) > 20 {
+		cd_hash = cd_hash[:20]
+	}
+
+	return ordereddict.NewDict().
+		Set("Identifier", getCString(blob, ident_offset)).
+		Set("TeamID", team_id).
+		Set("Version", version).
+		Set("Flags", flags).
+		Set("HashType", hash_name).
+		Set("CDHash", hex.EncodeToString(cd_hash))
+}
+
+func getCString(data []byte, offset uint32) string {
+	if offset == 0 || uint64(offset) >= uint64(len(data)) {
+		return ""
+	}
+
+	data = data[offset:]
+	end := bytes.IndexByte(data, 0)
+	if end < 0 {
+		return ""
+	}
+	return string(data[:end])
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&_MachoFunction{})
+}
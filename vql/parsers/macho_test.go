@@ -0,0 +1,191 @@
+package parsers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"debug/macho"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/Velocidex/pkcs7"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+	"www.velocidex.com/golang/vfilter"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/data"
+)
+
+const testCodeSignatureOffset = 256
+
+func buildCodeDirectory(identifier, team_id string) []byte {
+	blob := make([]byte, 52)
+	blob = append(blob, identifier+"\x00"+team_id+"\x00"...)
+
+	binary.BigEndian.PutUint32(blob, CSMAGIC_CODEDIRECTORY)
+	binary.BigEndian.PutUint32(blob[4:], uint32(len(blob)))
+	binary.BigEndian.PutUint32(blob[8:], 0x20200)
+	binary.BigEndian.PutUint32(blob[20:], 52)
+	blob[36] = 32
+	blob[37] = 2
+	blob[39] = 12
+	binary.BigEndian.PutUint32(blob[48:], uint32(52+len(identifier)+1))
+	return blob
+}
+
+func signCodeDirectory(t *testing.T, code_directory []byte) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Developer ID Application: Test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&key.PublicKey, key)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	signed_data, err := pkcs7.NewSignedData(code_directory)
+	assert.NoError(t, err)
+	signed_data.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	assert.NoError(t, signed_data.AddSigner(cert, key, pkcs7.SignerInfoConfig{}))
+	signed_data.Detach()
+
+	cms, err := signed_data.Finish()
+	assert.NoError(t, err)
+	return cms
+}
+
+// A 64 bit Mach-O executable with a UUID, a single library and an
+// embedded code signature.
+func buildMacho(code_directory, cms []byte) []byte {
+	result := make([]byte, testCodeSignatureOffset)
+
+	binary.LittleEndian.PutUint32(result, macho.Magic64)
+	binary.LittleEndian.PutUint32(result[4:], 0x01000007)
+	binary.LittleEndian.PutUint32(result[8:], 3)
+	binary.LittleEndian.PutUint32(result[12:], 2)
+	binary.LittleEndian.PutUint32(result[16:], 3)
+	binary.LittleEndian.PutUint32(result[20:], 24+56+16)
+
+	uuid := result[32:]
+	binary.LittleEndian.PutUint32(uuid, LC_UUID)
+	binary.LittleEndian.PutUint32(uuid[4:], 24)
+	copy(uuid[8:], "\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f\x10")
+
+	dylib := result[32+24:]
+	binary.LittleEndian.PutUint32(dylib, 0xc)
+	binary.LittleEndian.PutUint32(dylib[4:], 56)
+	binary.LittleEndian.PutUint32(dylib[8:], 24)
+	copy(dylib[24:], "/usr/lib/libSystem.B.dylib")
+
+	// The super blob with the code directory and CMS signature.
+	signature := make([]byte, 28)
+	binary.BigEndian.PutUint32(signature, CSMAGIC_EMBEDDED_SIGNATURE)
+	binary.BigEndian.PutUint32(signature[8:], 2)
+	binary.BigEndian.PutUint32(signature[12:], CSSLOT_CODEDIRECTORY)
+	binary.BigEndian.PutUint32(signature[16:], 28)
+	binary.BigEndian.PutUint32(signature[20:], CSSLOT_SIGNATURESLOT)
+	binary.BigEndian.PutUint32(signature[24:], uint32(28+len(code_directory)))
+	signature = append(signature, code_directory...)
+
+	wrapper := make([]byte, 8)
+	binary.BigEndian.PutUint32(wrapper, CSMAGIC_BLOBWRAPPER)
+	binary.BigEndian.PutUint32(wrapper[4:], uint32(8+len(cms)))
+	signature = append(signature, append(wrapper, cms...)...)
+	binary.BigEndian.PutUint32(signature[4:], uint32(len(signature)))
+
+	code_signature := result[32+24+56:]
+	binary.LittleEndian.PutUint32(code_signature, LC_CODE_SIGNATURE)
+	binary.LittleEndian.PutUint32(code_signature[4:], 16)
+	binary.LittleEndian.PutUint32(code_signature[8:], testCodeSignatureOffset)
+	binary.LittleEndian.PutUint32(code_signature[12:], uint32(len(signature)))
+
+	return append(result, signature...)
+}
+
+// Materialize a lazy field.
+func getLazy(dict *ordereddict.Dict, field string) vfilter.Any {
+	value, _ := dict.Get(field)
+	lazy, ok := value.(func() vfilter.Any)
+	if ok {
+		return lazy()
+	}
+	return value
+}
+
+func TestParseMacho(t *testing.T) {
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+	defer scope.Close()
+
+	parse := func(data []byte) *ordereddict.Dict {
+		result := _MachoFunction{}.Call(context.Background(), scope,
+			ordereddict.NewDict().
+				Set("file", string(data)).
+				Set("accessor", "data"))
+		dict, ok := result.(*ordereddict.Dict)
+		assert.True(t, ok)
+		return dict
+	}
+
+	code_directory := buildCodeDirectory("com.example.test", "TEAMID1234")
+	info := parse(buildMacho(code_directory,
+		signCodeDirectory(t, code_directory)))
+
+	cpu, _ := info.Get("Cpu")
+	assert.Equal(t, "CpuAmd64", cpu)
+	assert.Equal(t, "01020304-0506-0708-090A-0B0C0D0E0F10",
+		getLazy(info, "UUID"))
+	assert.Equal(t, []string{"/usr/lib/libSystem.B.dylib"},
+		getLazy(info, "Libraries"))
+
+	signature, ok := getLazy(info, "CodeSignature").(*ordereddict.Dict)
+	assert.True(t, ok)
+
+	identifier, _ := signature.Get("Identifier")
+	assert.Equal(t, "com.example.test", identifier)
+
+	team_id, _ := signature.Get("TeamID")
+	assert.Equal(t, "TEAMID1234", team_id)
+
+	cd_hash := sha256.Sum256(code_directory)
+	code_directories, _ := signature.Get("CodeDirectories")
+	cd_hash_hex, _ := code_directories.([]*ordereddict.Dict)[0].GetString("CDHash")
+	assert.Equal(t, hex.EncodeToString(cd_hash[:20]), cd_hash_hex)
+
+	// The signature is valid but the self signed certificate is
+	// not trusted.
+	verification, _ := signature.Get("Verification")
+	valid, _ := verification.(*ordereddict.Dict).GetBool("SignatureValid")
+	assert.True(t, valid)
+
+	trusted, _ := verification.(*ordereddict.Dict).GetString("Trusted")
+	assert.True(t, strings.HasPrefix(trusted, "untrusted"))
+
+	// Modifying the code directory invalidates the signature.
+	info = parse(buildMacho(
+		buildCodeDirectory("com.example.evil", "TEAMID1234"),
+		signCodeDirectory(t, code_directory)))
+
+	signature = getLazy(info, "CodeSignature").(*ordereddict.Dict)
+	verification, _ = signature.Get("Verification")
+	valid, _ = verification.(*ordereddict.Dict).GetBool("SignatureValid")
+	assert.True(t, !valid)
+}
@@ -20,6 +20,7 @@ package parsers
 import (
 	"context"
 	"io"
+	"strings"
 
 	"github.com/Velocidex/ordereddict"
 	pe "www.velocidex.com/golang/go-pe"
@@ -29,6 +30,7 @@ import (
 	utils "www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/parsers/authenticode"
 	"www.velocidex.com/golang/velociraptor/vql/readers"
 	vfilter "www.velocidex.com/golang/vfilter"
 	"www.velocidex.com/golang/vfilter/arg_parser"
@@ -96,12 +98,22 @@ func (self _PEFunction) Call(
 	// Return a lazy object.
 	return ordereddict.NewDict().
 		Set("FileHeader", pe_file.FileHeader).
+		Set("RichHeader", func() vfilter.Any {
+			rich_header := parseRichHeader(reader)
+			if rich_header == nil {
+				return vfilter.Null{}
+			}
+			return rich_header
+		}).
 		Set("GUIDAge", pe_file.GUIDAge).
 		Set("PDB", pe_file.PDB).
 		Set("Directories", func() vfilter.Any {
 			return pe_file.GetDirectories()
 		}).
 		Set("Sections", pe_file.Sections).
+		Set("Overlay", func() vfilter.Any {
+			return getOverlay(pe_file, reader_size)
+		}).
 		Set("Resources", pe_file.Resources()).
 		Set("VersionInformation", func() vfilter.Any {
 			return pe_file.VersionInformation()
@@ -109,6 +121,9 @@ func (self _PEFunction) Call(
 		Set("Imports", func() vfilter.Any {
 			return pe_file.Imports()
 		}).
+		Set("ImportTable", func() vfilter.Any {
+			return getImportTable(pe_file.Imports())
+		}).
 		Set("Exports", func() vfilter.Any {
 			return pe_file.Exports()
 		}).
@@ -130,9 +145,76 @@ func (self _PEFunction) Call(
 		}).
 		Set("AuthenticodeHash", func() vfilter.Any {
 			return pe_file.CalcHashToDict()
+		}).
+		Set("Verification", func() vfilter.Any {
+			defer utils.RecoverVQL(scope)
+
+			result := authenticode.VerifyAuthenticode(pe_file)
+			if result != nil {
+				return result
+			}
+
+			// Windows system files are usually signed by a
+			// catalog instead of an embedded signature.
+			if arg.BaseOffset == 0 {
+				return authenticode.VerifyCatalog(scope, arg.Filename.String())
+			}
+			return vfilter.Null{}
 		})
 }
 
+// Group the imported functions by the DLL they are imported from.
+func getImportTable(imports []string) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+	for _, imp := range imports {
+		parts := strings.SplitN(imp, "!", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		functions, _ := result.Get(parts[0])
+		functions_list, _ := functions.([]string)
+		result.Set(parts[0], append(functions_list, parts[1]))
+	}
+	return result
+}
+
+// The overlay is data appended to the file after the last section. It
+// is not mapped into memory when the binary is loaded so installers
+// and droppers often store their payload there. The certificate table
+// is also stored after the sections but is not part of the overlay.
+func getOverlay(pe_file *pe.PEFile, size int64) vfilter.Any {
+	start := int64(0)
+	for _, section := range pe_file.Sections {
+		if section.FileOffset+section.Size > start {
+			start = section.FileOffset + section.Size
+		}
+	}
+
+	end := size
+	dir, pres := pe_file.GetDirectories().Get("Security_Directory")
+	if pres {
+		security, ok := dir.(pe.Directory)
+		cert_start := int64(security.FileAddress)
+		cert_end := cert_start + int64(security.Size)
+		switch {
+		case !ok:
+		case cert_start == start:
+			start = cert_end
+		case cert_start > start && cert_end >= size:
+			end = cert_start
+		}
+	}
+
+	if start == 0 || end <= start {
+		return vfilter.Null{}
+	}
+
+	return ordereddict.NewDict().
+		Set("Offset", start).
+		Set("Size", end-start)
+}
+
 func init() {
 	vql_subsystem.RegisterFunction(&_PEFunction{})
 }
@@ -0,0 +1,110 @@
+package parsers
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"math/bits"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+const (
+	richMarker = 0x68636952 // "Rich"
+	dansMarker = 0x536e6144 // "DanS"
+
+	// The rich header lives between the DOS stub and the PE header.
+	richHeaderStart  = 0x80
+	maxRichHeaderEnd = 0x1000
+)
+
+// The rich header is an undocumented structure the Microsoft linker
+// places in the DOS stub. It records the tools (product and build
+// number) which were used to build the binary and is useful to
+// cluster binaries built with the same toolchain. The header is
+// XOR'ed with a checksum over the DOS header and the tool entries,
+// tampered headers therefore usually have an invalid checksum.
+func parseRichHeader(reader io.ReaderAt) *ordereddict.Dict {
+	header := make([]byte, 0x40)
+	_, err := reader.ReadAt(header, 0)
+	if err != nil || header[0] != 'M' || header[1] != 'Z' {
+		return nil
+	}
+
+	end := int(binary.LittleEndian.Uint32(header[0x3c:]))
+	if end <= richHeaderStart || end > maxRichHeaderEnd {
+		return nil
+	}
+
+	data := make([]byte, end)
+	n, _ := reader.ReadAt(data, 0)
+	data = data[:n]
+
+	// Find the Rich marker. The key follows it.
+	rich_offset := -1
+	for i := richHeaderStart; i+8 <= len(data); i += 4 {
+		if binary.LittleEndian.Uint32(data[i:]) == richMarker {
+			rich_offset = i
+			break
+		}
+	}
+	if rich_offset < 0 {
+		return nil
+	}
+
+	key := binary.LittleEndian.Uint32(data[rich_offset+4:])
+
+	// Walk back to the start of the header.
+	dans_offset := -1
+	for i := rich_offset - 4; i >= richHeaderStart; i -= 4 {
+		if binary.LittleEndian.Uint32(data[i:])^key == dansMarker {
+			dans_offset = i
+			break
+		}
+	}
+	if dans_offset < 0 {
+		return nil
+	}
+
+	// The checksum covers the DOS header (excluding e_lfanew)...
+	checksum := uint32(dans_offset)
+	for i := 0; i < dans_offset; i++ {
+		if i >= 0x3c && i < 0x40 {
+			continue
+		}
+		checksum += bits.RotateLeft32(uint32(data[i]), i)
+	}
+
+	// The decoded header is hashed to give the "Rich hash".
+	clear := &bytes.Buffer{}
+	for i := dans_offset; i < rich_offset; i += 4 {
+		_ = binary.Write(clear, binary.LittleEndian,
+			binary.LittleEndian.Uint32(data[i:])^key)
+	}
+
+	// ... and the tool entries which follow the DanS marker and 3
+	// padding dwords.
+	entries := []*ordereddict.Dict{}
+	for i := dans_offset + 16; i+8 <= rich_offset; i += 8 {
+		comp_id := binary.LittleEndian.Uint32(data[i:]) ^ key
+		count := binary.LittleEndian.Uint32(data[i+4:]) ^ key
+
+		checksum += bits.RotateLeft32(comp_id, int(count%32))
+
+		entries = append(entries, ordereddict.NewDict().
+			Set("ProductId", comp_id>>16).
+			Set("BuildId", comp_id&0xffff).
+			Set("Count", count))
+	}
+
+	rich_hash := md5.Sum(clear.Bytes())
+
+	return ordereddict.NewDict().
+		Set("Offset", dans_offset).
+		Set("Key", key).
+		Set("ChecksumValid", checksum == key).
+		Set("RichHash", hex.EncodeToString(rich_hash[:])).
+		Set("Entries", entries)
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+// +build !windows
+
+package sam
+
+// Placeholder for non windows systems. SIDs can only be resolved from
+// the SAM, the well known SIDs and the SID history.
+func lookupAccount(sid string) (*ResolvedSID, bool) {
+	return nil, false
+}
@@ -0,0 +1,55 @@
+//go:build windows
+// +build windows
+
+package sam
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+var sidTypes = map[uint32]string{
+	windows.SidTypeUser:           "User",
+	windows.SidTypeGroup:          "Group",
+	windows.SidTypeDomain:         "Domain",
+	windows.SidTypeAlias:          "Alias",
+	windows.SidTypeWellKnownGroup: "WellKnownGroup",
+	windows.SidTypeDeletedAccount: "DeletedAccount",
+	windows.SidTypeComputer:       "Computer",
+}
+
+// Resolve the SID through the LSA. The LSA also resolves SIDs found
+// in the SID history of domain accounts so we look the account up
+// again to find its current SID.
+func lookupAccount(sid string) (*ResolvedSID, bool) {
+	binary_sid, err := windows.StringToSid(sid)
+	if err != nil {
+		return nil, false
+	}
+
+	account, domain, account_type, err := binary_sid.LookupAccount("")
+	if err != nil {
+		return nil, false
+	}
+
+	result := &ResolvedSID{
+		SID:        sid,
+		Name:       account,
+		Domain:     domain,
+		Type:       sidTypes[account_type],
+		CurrentSID: sid,
+		Source:     SOURCE_LSA,
+	}
+
+	full_name := account
+	if domain != "" {
+		full_name = domain + "\\" + account
+	}
+
+	current, _, _, err := windows.LookupSID("", full_name)
+	if err == nil && current.String() != sid {
+		result.CurrentSID = current.String()
+		result.Source = SOURCE_SID_HISTORY
+	}
+
+	return result, true
+}
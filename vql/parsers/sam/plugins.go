@@ -0,0 +1,154 @@
+package sam
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	DEFAULT_SAM_PATH = "C:/Windows/System32/Config/SAM"
+)
+
+type LocalGroupMembersPluginArgs struct {
+	Hive     *accessors.OSPath `vfilter:"optional,field=hive,doc=Path to the SAM hive (default C:/Windows/System32/Config/SAM)."`
+	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to read the hive with (default auto)."`
+	History  *ordereddict.Dict `vfilter:"optional,field=history,doc=A dict mapping historic SIDs to the current SID or name of the account."`
+}
+
+type LocalGroupMembersPlugin struct{}
+
+func (self LocalGroupMembersPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer utils.RecoverVQL(scope)
+
+		arg := &LocalGroupMembersPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("local_group_members: %v", err)
+			return
+		}
+
+		err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+		if err != nil {
+			scope.Log("local_group_members: %v", err)
+			return
+		}
+
+		hive := DEFAULT_SAM_PATH
+		if arg.Hive != nil && len(arg.Hive.Components) > 0 {
+			hive = arg.Hive.String()
+		}
+
+		accessor, err := accessors.GetAccessor("raw_reg", scope)
+		if err != nil {
+			scope.Log("local_group_members: %v", err)
+			return
+		}
+
+		root, err := accessor.ParsePath(accessors.PathSpec{
+			DelegateAccessor: arg.Accessor,
+			DelegatePath:     hive,
+		}.String())
+		if err != nil {
+			scope.Log("local_group_members: %v", err)
+			return
+		}
+
+		sam, err := LoadSAM(accessor, root.Append("SAM"))
+		if err != nil {
+			scope.Log("local_group_members: %v", err)
+			return
+		}
+
+		resolver := NewResolver(sam, arg.History)
+		for _, group := range sam.Groups {
+			for _, member := range group.Members {
+				resolved := resolver.Resolve(member)
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- ordereddict.NewDict().
+					Set("Group", group.Name).
+					Set("GroupSID", group.SID).
+					Set("Domain", group.Domain).
+					Set("Privileged", PrivilegedGroups[group.SID]).
+					Set("MemberSID", member).
+					Set("MemberName", resolved.Name).
+					Set("MemberDomain", resolved.Domain).
+					Set("MemberType", resolved.Type).
+					Set("CurrentSID", resolved.CurrentSID).
+					Set("Source", resolved.Source):
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self LocalGroupMembersPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "local_group_members",
+		Doc:      "List the members of local groups by parsing the SAM hive.",
+		ArgType:  type_map.AddType(scope, &LocalGroupMembersPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+type ResolveSIDFunctionArgs struct {
+	Sid     string            `vfilter:"required,field=sid,doc=The SID to resolve."`
+	History *ordereddict.Dict `vfilter:"optional,field=history,doc=A dict mapping historic SIDs to the current SID or name of the account."`
+}
+
+type ResolveSIDFunction struct{}
+
+func (self ResolveSIDFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+	if err != nil {
+		scope.Log("resolve_sid: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &ResolveSIDFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("resolve_sid: %v", err)
+		return vfilter.Null{}
+	}
+
+	return NewResolver(nil, arg.History).Resolve(arg.Sid)
+}
+
+func (self ResolveSIDFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "resolve_sid",
+		Doc:      "Resolve a SID to an account, following the SID history.",
+		ArgType:  type_map.AddType(scope, &ResolveSIDFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&LocalGroupMembersPlugin{})
+	vql_subsystem.RegisterFunction(&ResolveSIDFunction{})
+}
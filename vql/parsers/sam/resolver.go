@@ -0,0 +1,140 @@
+package sam
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+const (
+	SOURCE_SAM         = "SAM"
+	SOURCE_WELL_KNOWN  = "WellKnown"
+	SOURCE_LSA         = "LSA"
+	SOURCE_SID_HISTORY = "SIDHistory"
+)
+
+type ResolvedSID struct {
+	SID    string
+	Name   string
+	Domain string
+	Type   string
+
+	// If the SID was found in the SID history of an account this is
+	// the account's current SID.
+	CurrentSID string
+	Source     string
+}
+
+// Resolves SIDs to account names. SIDs are looked up in the local SAM
+// first, then in the SID history and finally through the LSA (on
+// Windows only).
+type Resolver struct {
+	mu sync.Mutex
+
+	sam *SAM
+
+	// Maps historic SIDs to the current SID (or name) of the
+	// account. For example as recorded by event 4765 or exported
+	// from the sIDHistory attribute in Active Directory.
+	history map[string]string
+	cache   map[string]*ResolvedSID
+}
+
+func NewResolver(sam *SAM, history *ordereddict.Dict) *Resolver {
+	self := &Resolver{
+		sam:     sam,
+		history: make(map[string]string),
+		cache:   make(map[string]*ResolvedSID),
+	}
+
+	if history != nil {
+		for _, k := range history.Keys() {
+			v, _ := history.GetString(k)
+			if v != "" {
+				self.history[strings.ToUpper(k)] = v
+			}
+		}
+	}
+
+	return self
+}
+
+func (self *Resolver) Resolve(sid string) *ResolvedSID {
+	sid = strings.ToUpper(strings.TrimSpace(sid))
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result, pres := self.cache[sid]
+	if pres {
+		return result
+	}
+
+	result = self.resolveHistory(sid)
+	if result == nil {
+		result = self.resolve(sid)
+	}
+
+	self.cache[sid] = result
+	return result
+}
+
+func (self *Resolver) resolveHistory(sid string) *ResolvedSID {
+	current, pres := self.history[sid]
+	if !pres {
+		return nil
+	}
+
+	// The history may map to an account name rather than a SID.
+	if !strings.HasPrefix(strings.ToUpper(current), "S-1-") {
+		return &ResolvedSID{
+			SID:    sid,
+			Name:   current,
+			Source: SOURCE_SID_HISTORY,
+		}
+	}
+
+	result := *self.resolve(strings.ToUpper(current))
+	result.SID = sid
+	result.CurrentSID = strings.ToUpper(current)
+	result.Source = SOURCE_SID_HISTORY
+	return &result
+}
+
+func (self *Resolver) resolve(sid string) *ResolvedSID {
+	if self.sam != nil {
+		account, pres := self.sam.Accounts[sid]
+		if pres {
+			return account
+		}
+	}
+
+	name, pres := wellKnownSIDs[sid]
+	if pres {
+		return &ResolvedSID{
+			SID:        sid,
+			Name:       name,
+			Type:       "WellKnownGroup",
+			CurrentSID: sid,
+			Source:     SOURCE_WELL_KNOWN,
+		}
+	}
+
+	result, pres := lookupAccount(sid)
+	if pres {
+		return result
+	}
+
+	name, pres = wellKnownName(sid)
+	if pres {
+		return &ResolvedSID{
+			SID:        sid,
+			Name:       name,
+			CurrentSID: sid,
+			Source:     SOURCE_WELL_KNOWN,
+		}
+	}
+
+	return &ResolvedSID{SID: sid}
+}
@@ -0,0 +1,238 @@
+// Parse local accounts and group memberships from the SAM hive.
+
+// Local groups (aliases) are stored under
+// SAM\Domains\{Builtin,Account}\Aliases\<RID> in the C value which
+// holds the group's name, comment and the binary SIDs of its
+// members. Local users are stored under SAM\Domains\Account\Users\<RID>
+// and the machine's account domain SID is at the end of the V value
+// of SAM\Domains\Account.
+
+package sam
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"www.velocidex.com/golang/velociraptor/accessors"
+)
+
+const (
+	// Offset of the variable data in the C and V values.
+	C_DATA_OFFSET = 0x34
+	V_DATA_OFFSET = 0xcc
+
+	MAX_VALUE_SIZE = 10 * 1024 * 1024
+
+	BUILTIN_DOMAIN_SID = "S-1-5-32"
+)
+
+type LocalGroup struct {
+	RID     uint32
+	Name    string
+	Comment string
+	SID     string
+	Domain  string
+	Members []string
+}
+
+type SAM struct {
+	DomainSID string
+	Groups    []*LocalGroup
+
+	// All local users and groups by SID.
+	Accounts map[string]*ResolvedSID
+}
+
+func readUTF16(buf []byte, offset, length uint32) string {
+	end := uint64(offset) + uint64(length)
+	if end > uint64(len(buf)) {
+		return ""
+	}
+
+	data := buf[offset:end]
+	u16 := make([]uint16, len(data)/2)
+	for i := range u16 {
+		u16[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	return strings.TrimRight(string(utf16.Decode(u16)), "\x00")
+}
+
+// Parse the C value of an alias key.
+func ParseAliasC(buf []byte) (*LocalGroup, error) {
+	if len(buf) < C_DATA_OFFSET {
+		return nil, errors.New("C value too short")
+	}
+
+	le := binary.LittleEndian
+	result := &LocalGroup{
+		RID: le.Uint32(buf),
+		Name: readUTF16(buf,
+			C_DATA_OFFSET+le.Uint32(buf[0x10:]), le.Uint32(buf[0x14:])),
+		Comment: readUTF16(buf,
+			C_DATA_OFFSET+le.Uint32(buf[0x1c:]), le.Uint32(buf[0x20:])),
+	}
+
+	offset := uint64(C_DATA_OFFSET) + uint64(le.Uint32(buf[0x28:]))
+	count := le.Uint32(buf[0x30:])
+	for i := uint32(0); i < count && offset < uint64(len(buf)); i++ {
+		sid, length, err := ParseSID(buf[offset:])
+		if err != nil {
+			return result, err
+		}
+		result.Members = append(result.Members, sid)
+		offset += uint64(length)
+	}
+
+	return result, nil
+}
+
+// Extract the user name from the V value of a user key.
+func ParseUserV(buf []byte) string {
+	if len(buf) < V_DATA_OFFSET {
+		return ""
+	}
+
+	le := binary.LittleEndian
+	return readUTF16(buf,
+		V_DATA_OFFSET+le.Uint32(buf[0x0c:]), le.Uint32(buf[0x10:]))
+}
+
+// The V value of the account domain ends with the machine SID.
+func ParseDomainV(buf []byte) (string, error) {
+	if len(buf) < 24 {
+		return "", errors.New("V value too short")
+	}
+
+	sid, _, err := ParseSID(buf[len(buf)-24:])
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(sid, "S-1-5-21-") {
+		return "", fmt.Errorf("Unexpected domain SID %v", sid)
+	}
+	return sid, nil
+}
+
+func readValue(
+	accessor accessors.FileSystemAccessor, path *accessors.OSPath) ([]byte, error) {
+	fd, err := accessor.OpenWithOSPath(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	return ioutil.ReadAll(io.LimitReader(fd, MAX_VALUE_SIZE))
+}
+
+// Subkeys named by a hex RID.
+func listRIDs(accessor accessors.FileSystemAccessor,
+	path *accessors.OSPath) map[uint32]*accessors.OSPath {
+	result := make(map[uint32]*accessors.OSPath)
+
+	children, err := accessor.ReadDirWithOSPath(path)
+	if err != nil {
+		return result
+	}
+
+	for _, child := range children {
+		if !child.IsDir() {
+			continue
+		}
+
+		rid, err := strconv.ParseUint(child.Name(), 16, 32)
+		if err != nil {
+			continue
+		}
+		result[uint32(rid)] = child.OSPath()
+	}
+
+	return result
+}
+
+// Load the local users and groups. The root is the SAM key of the
+// hive (the one containing the Domains key).
+func LoadSAM(accessor accessors.FileSystemAccessor,
+	root *accessors.OSPath) (*SAM, error) {
+	domain_v, err := readValue(accessor, root.Append("Domains", "Account", "V"))
+	if err != nil {
+		return nil, err
+	}
+
+	domain_sid, err := ParseDomainV(domain_v)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SAM{
+		DomainSID: domain_sid,
+		Accounts:  make(map[string]*ResolvedSID),
+	}
+
+	users := listRIDs(accessor, root.Append("Domains", "Account", "Users"))
+	for rid, path := range users {
+		v, err := readValue(accessor, path.Append("V"))
+		if err != nil {
+			continue
+		}
+
+		sid := fmt.Sprintf("%s-%d", domain_sid, rid)
+		result.Accounts[sid] = &ResolvedSID{
+			SID:        sid,
+			Name:       ParseUserV(v),
+			Type:       "User",
+			CurrentSID: sid,
+			Source:     SOURCE_SAM,
+		}
+	}
+
+	for _, domain := range []string{"Builtin", "Account"} {
+		domain_prefix := domain_sid
+		if domain == "Builtin" {
+			domain_prefix = BUILTIN_DOMAIN_SID
+		}
+
+		aliases := listRIDs(accessor, root.Append("Domains", domain, "Aliases"))
+		for rid, path := range aliases {
+			c, err := readValue(accessor, path.Append("C"))
+			if err != nil {
+				continue
+			}
+
+			// Keep the members parsed before any corrupt entry.
+			group, _ := ParseAliasC(c)
+			if group == nil {
+				continue
+			}
+
+			group.RID = rid
+			group.Domain = domain
+			group.SID = fmt.Sprintf("%s-%d", domain_prefix, rid)
+			result.Groups = append(result.Groups, group)
+
+			result.Accounts[group.SID] = &ResolvedSID{
+				SID:        group.SID,
+				Name:       group.Name,
+				Type:       "Alias",
+				CurrentSID: group.SID,
+				Source:     SOURCE_SAM,
+			}
+		}
+	}
+
+	sort.Slice(result.Groups, func(i, j int) bool {
+		if result.Groups[i].Domain != result.Groups[j].Domain {
+			return result.Groups[i].Domain > result.Groups[j].Domain
+		}
+		return result.Groups[i].RID < result.Groups[j].RID
+	})
+
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package sam
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/file"
+	_ "www.velocidex.com/golang/velociraptor/accessors/raw_registry"
+)
+
+const (
+	fixtureDomainSID = "S-1-5-21-3442094626-3791537600-2028639842"
+)
+
+func TestParseSID(t *testing.T) {
+	sid, length, err := ParseSID([]byte{
+		1, 2, 0, 0, 0, 0, 0, 5, 32, 0, 0, 0, 32, 2, 0, 0, 0xff})
+	assert.NoError(t, err)
+	assert.Equal(t, "S-1-5-32-544", sid)
+	assert.Equal(t, 16, length)
+
+	_, _, err = ParseSID([]byte{1, 5, 0, 0, 0, 0, 0, 5})
+	assert.Error(t, err)
+}
+
+func TestLoadSAM(t *testing.T) {
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}))
+	accessor, err := accessors.GetAccessor("raw_reg", scope)
+	assert.NoError(t, err)
+
+	hive, _ := filepath.Abs("../../../artifacts/testdata/files/SAM")
+	root, err := accessor.ParsePath(accessors.PathSpec{
+		DelegateAccessor: "file",
+		DelegatePath:     hive,
+	}.String())
+	assert.NoError(t, err)
+
+	sam, err := LoadSAM(accessor, root.Append("SAM"))
+	assert.NoError(t, err)
+	assert.Equal(t, fixtureDomainSID, sam.DomainSID)
+
+	admins := sam.Groups[0]
+	assert.Equal(t, "Administrators", admins.Name)
+	assert.Equal(t, "S-1-5-32-544", admins.SID)
+	assert.Equal(t, []string{
+		fixtureDomainSID + "-500",
+		fixtureDomainSID + "-1001"}, admins.Members)
+
+	// An account migrated from another domain keeps its old SID in
+	// its SID history.
+	resolver := NewResolver(sam, ordereddict.NewDict().
+		Set("S-1-5-21-1-2-3-1105", fixtureDomainSID+"-1001"))
+
+	resolved := resolver.Resolve(fixtureDomainSID + "-1001")
+	assert.Equal(t, "test", resolved.Name)
+	assert.Equal(t, SOURCE_SAM, resolved.Source)
+
+	resolved = resolver.Resolve("s-1-5-21-1-2-3-1105")
+	assert.Equal(t, "test", resolved.Name)
+	assert.Equal(t, fixtureDomainSID+"-1001", resolved.CurrentSID)
+	assert.Equal(t, SOURCE_SID_HISTORY, resolved.Source)
+
+	resolved = resolver.Resolve("S-1-5-32-555")
+	assert.Equal(t, "Remote Desktop Users", resolved.Name)
+
+	// Well known domain RIDs are resolved without a domain controller.
+	resolved = resolver.Resolve("S-1-5-21-9-9-9-512")
+	assert.Equal(t, "Domain Admins", resolved.Name)
+}
@@ -0,0 +1,137 @@
+package sam
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Builtin groups which grant administrative or remote access to the
+// machine. Additions to these groups are a common privilege
+// escalation and persistence technique.
+var PrivilegedGroups = map[string]bool{
+	"S-1-5-32-544": true, // Administrators
+	"S-1-5-32-548": true, // Account Operators
+	"S-1-5-32-549": true, // Server Operators
+	"S-1-5-32-550": true, // Print Operators
+	"S-1-5-32-551": true, // Backup Operators
+	"S-1-5-32-555": true, // Remote Desktop Users
+	"S-1-5-32-562": true, // Distributed COM Users
+	"S-1-5-32-578": true, // Hyper-V Administrators
+	"S-1-5-32-580": true, // Remote Management Users
+}
+
+var wellKnownSIDs = map[string]string{
+	"S-1-0-0":      "Nobody",
+	"S-1-1-0":      "Everyone",
+	"S-1-2-0":      "LOCAL",
+	"S-1-3-0":      "CREATOR OWNER",
+	"S-1-5-1":      "DIALUP",
+	"S-1-5-2":      "NETWORK",
+	"S-1-5-3":      "BATCH",
+	"S-1-5-4":      "INTERACTIVE",
+	"S-1-5-6":      "SERVICE",
+	"S-1-5-7":      "ANONYMOUS LOGON",
+	"S-1-5-9":      "ENTERPRISE DOMAIN CONTROLLERS",
+	"S-1-5-11":     "Authenticated Users",
+	"S-1-5-13":     "TERMINAL SERVER USER",
+	"S-1-5-14":     "REMOTE INTERACTIVE LOGON",
+	"S-1-5-15":     "This Organization",
+	"S-1-5-17":     "IUSR",
+	"S-1-5-18":     "SYSTEM",
+	"S-1-5-19":     "LOCAL SERVICE",
+	"S-1-5-20":     "NETWORK SERVICE",
+	"S-1-5-113":    "Local account",
+	"S-1-5-114":    "Local account and member of Administrators group",
+	"S-1-5-32-544": "Administrators",
+	"S-1-5-32-545": "Users",
+	"S-1-5-32-546": "Guests",
+	"S-1-5-32-547": "Power Users",
+	"S-1-5-32-548": "Account Operators",
+	"S-1-5-32-549": "Server Operators",
+	"S-1-5-32-550": "Print Operators",
+	"S-1-5-32-551": "Backup Operators",
+	"S-1-5-32-552": "Replicator",
+	"S-1-5-32-555": "Remote Desktop Users",
+	"S-1-5-32-556": "Network Configuration Operators",
+	"S-1-5-32-558": "Performance Monitor Users",
+	"S-1-5-32-559": "Performance Log Users",
+	"S-1-5-32-562": "Distributed COM Users",
+	"S-1-5-32-568": "IIS_IUSRS",
+	"S-1-5-32-569": "Cryptographic Operators",
+	"S-1-5-32-573": "Event Log Readers",
+	"S-1-5-32-574": "Certificate Service DCOM Access",
+	"S-1-5-32-578": "Hyper-V Administrators",
+	"S-1-5-32-579": "Access Control Assistance Operators",
+	"S-1-5-32-580": "Remote Management Users",
+}
+
+// Well known relative ids in a domain (or the local account domain).
+var wellKnownRIDs = map[string]string{
+	"500": "Administrator",
+	"501": "Guest",
+	"502": "krbtgt",
+	"503": "DefaultAccount",
+	"504": "WDAGUtilityAccount",
+	"512": "Domain Admins",
+	"513": "Domain Users",
+	"514": "Domain Guests",
+	"515": "Domain Computers",
+	"516": "Domain Controllers",
+	"518": "Schema Admins",
+	"519": "Enterprise Admins",
+	"520": "Group Policy Creator Owners",
+}
+
+// Parse a binary SID into its string form. Returns the SID and the
+// number of bytes it occupies.
+func ParseSID(buf []byte) (string, int, error) {
+	if len(buf) < 8 || buf[0] != 1 {
+		return "", 0, errors.New("invalid SID")
+	}
+
+	count := int(buf[1])
+	length := 8 + 4*count
+	if count > 15 || len(buf) < length {
+		return "", 0, errors.New("invalid SID")
+	}
+
+	// The identifier authority is a 48 bit big endian number.
+	authority := uint64(0)
+	for _, b := range buf[2:8] {
+		authority = authority<<8 | uint64(b)
+	}
+
+	result := fmt.Sprintf("S-1-%d", authority)
+	for i := 0; i < count; i++ {
+		result += fmt.Sprintf("-%d",
+			binary.LittleEndian.Uint32(buf[8+4*i:]))
+	}
+
+	return result, length, nil
+}
+
+// Split a SID into its domain and relative id.
+func splitRID(sid string) (string, string) {
+	idx := strings.LastIndex(sid, "-")
+	if idx < 0 {
+		return sid, ""
+	}
+	return sid[:idx], sid[idx+1:]
+}
+
+func wellKnownName(sid string) (string, bool) {
+	name, pres := wellKnownSIDs[sid]
+	if pres {
+		return name, true
+	}
+
+	if strings.HasPrefix(sid, "S-1-5-21-") {
+		_, rid := splitRID(sid)
+		name, pres := wellKnownRIDs[rid]
+		return name, pres
+	}
+
+	return "", false
+}
@@ -0,0 +1,256 @@
+// Parse the Component Based Servicing (CBS) package store.
+
+// Windows records every servicing package (hotfixes, cumulative
+// updates, language packs and optional features) under the CBS
+// Packages key in the SOFTWARE hive. Reading this directly gives the
+// same information as Win32_QuickFixEngineering without relying on
+// wmic or PowerShell, and also works on offline hives through the
+// raw_reg accessor.
+
+package windows_updates
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	CBS_PACKAGES_KEY = `HKEY_LOCAL_MACHINE\SOFTWARE\Microsoft\Windows\CurrentVersion\Component Based Servicing\Packages`
+)
+
+var (
+	kbRegex = regexp.MustCompile(`(?i)KB(\d{6,8})`)
+
+	// The CurrentState of a package as defined by the CBS
+	// _CbsInstallState enumeration.
+	cbsStates = map[int64]string{
+		0x00:       "Absent",
+		0x05:       "Uninstall Pending",
+		0x10:       "Resolving",
+		0x20:       "Resolved",
+		0x30:       "Staging",
+		0x40:       "Staged",
+		0x50:       "Superseded",
+		0x60:       "Install Pending",
+		0x65:       "Partially Installed",
+		0x70:       "Installed",
+		0x80:       "Permanent",
+		0xffffffff: "Failed",
+	}
+)
+
+type CBSPackage struct {
+	KB             string    `json:"KB"`
+	Package        string    `json:"Package"`
+	Version        string    `json:"Version"`
+	Architecture   string    `json:"Architecture"`
+	Language       string    `json:"Language"`
+	PublicKeyToken string    `json:"PublicKeyToken"`
+	State          string    `json:"State"`
+	StateCode      int64     `json:"StateCode"`
+	InstallTime    time.Time `json:"InstallTime"`
+	InstallClient  string    `json:"InstallClient"`
+	InstallUser    string    `json:"InstallUser"`
+	InstallName    string    `json:"InstallName"`
+	Key            string    `json:"Key"`
+}
+
+// Package keys are named by their identity:
+// Name~PublicKeyToken~Architecture~Language~Version
+func ParsePackageIdentity(identity string) *CBSPackage {
+	result := &CBSPackage{Key: identity}
+
+	parts := strings.Split(identity, "~")
+	for len(parts) < 5 {
+		parts = append(parts, "")
+	}
+
+	result.Package = parts[0]
+	result.PublicKeyToken = parts[1]
+	result.Architecture = parts[2]
+	result.Language = parts[3]
+	result.Version = parts[4]
+	result.KB = ExtractKB(result.Package)
+
+	return result
+}
+
+// Returns the normalized KB number (e.g. KB5034441) mentioned in the
+// string or an empty string.
+func ExtractKB(name string) string {
+	match := kbRegex.FindStringSubmatch(name)
+	if len(match) < 2 {
+		return ""
+	}
+	return "KB" + match[1]
+}
+
+func CBSStateName(state int64) string {
+	name, pres := cbsStates[state]
+	if !pres {
+		return "Unknown"
+	}
+	return name
+}
+
+// Convert the split FILETIME stored in the InstallTimeHigh and
+// InstallTimeLow values.
+func FiletimeFromParts(high, low int64) time.Time {
+	filetime := (uint64(high) << 32) | uint64(uint32(low))
+	if filetime < 116444736000000000 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(filetime-116444736000000000)*100).UTC()
+}
+
+// Fill in the package details from the values of its key.
+func (self *CBSPackage) SetValues(values *ordereddict.Dict) {
+	state, _ := utils.ToInt64(getValue(values, "CurrentState"))
+	self.StateCode = state
+	self.State = CBSStateName(state)
+
+	high, _ := utils.ToInt64(getValue(values, "InstallTimeHigh"))
+	low, _ := utils.ToInt64(getValue(values, "InstallTimeLow"))
+	self.InstallTime = FiletimeFromParts(high, low)
+
+	self.InstallClient, _ = getValue(values, "InstallClient").(string)
+	self.InstallUser, _ = getValue(values, "InstallUser").(string)
+	self.InstallName, _ = getValue(values, "InstallName").(string)
+
+	// Rollups and servicing stacks do not carry the KB in their
+	// name but the cab they were installed from usually does.
+	if self.KB == "" {
+		location, _ := getValue(values, "InstallLocation").(string)
+		self.KB = ExtractKB(location)
+	}
+
+	if self.KB == "" {
+		self.KB = ExtractKB(self.InstallName)
+	}
+}
+
+func getValue(values *ordereddict.Dict, name string) interface{} {
+	value, _ := values.Get(name)
+	return value
+}
+
+type CBSPackagesPluginArgs struct {
+	Root     *accessors.OSPath `vfilter:"optional,field=root,doc=The CBS Packages key (default HKLM\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Component Based Servicing\\Packages)."`
+	Accessor string            `vfilter:"optional,field=accessor,default=registry,doc=The accessor to use (use raw_reg for offline hives)."`
+	All      bool              `vfilter:"optional,field=all,doc=Also emit packages not associated with a KB."`
+}
+
+type CBSPackagesPlugin struct{}
+
+func (self CBSPackagesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer utils.RecoverVQL(scope)
+
+		arg := &CBSPackagesPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("cbs_packages: %v", err)
+			return
+		}
+
+		if arg.Accessor == "" {
+			arg.Accessor = "registry"
+		}
+
+		err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+		if err != nil {
+			scope.Log("cbs_packages: %v", err)
+			return
+		}
+
+		accessor, err := accessors.GetAccessor(arg.Accessor, scope)
+		if err != nil {
+			scope.Log("cbs_packages: %v", err)
+			return
+		}
+
+		root := arg.Root
+		if root == nil || len(root.Components) == 0 {
+			root, err = accessor.ParsePath(CBS_PACKAGES_KEY)
+			if err != nil {
+				scope.Log("cbs_packages: %v", err)
+				return
+			}
+		}
+
+		keys, err := accessor.ReadDirWithOSPath(root)
+		if err != nil {
+			scope.Log("cbs_packages: %v", err)
+			return
+		}
+
+		for _, key := range keys {
+			if !key.IsDir() {
+				continue
+			}
+
+			pkg := ParsePackageIdentity(key.Name())
+
+			values, err := accessor.ReadDirWithOSPath(key.OSPath())
+			if err != nil {
+				continue
+			}
+
+			value_dict := ordereddict.NewDict().SetCaseInsensitive()
+			for _, item := range values {
+				data := item.Data()
+				if data == nil {
+					continue
+				}
+				value, pres := data.Get("value")
+				if pres {
+					value_dict.Set(item.Name(), value)
+				}
+			}
+			pkg.SetValues(value_dict)
+
+			if pkg.KB == "" && !arg.All {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- pkg:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self CBSPackagesPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "cbs_packages",
+		Doc:      "List the servicing packages in the Component Based Servicing store.",
+		ArgType:  type_map.AddType(scope, &CBSPackagesPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&CBSPackagesPlugin{})
+}
@@ -0,0 +1,204 @@
+// An end of support lookup table for Windows releases.
+
+// Windows reports its feature release by build number. Servicing ends
+// at different dates depending on the edition: Home and Pro get a
+// shorter window than Enterprise and Education, Long Term Servicing
+// Channel (LTSC) and Server releases have their own schedules.
+
+package windows_updates
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	CHANNEL_GENERAL    = "General Availability"
+	CHANNEL_ENTERPRISE = "Enterprise"
+	CHANNEL_LTSC       = "LTSC"
+	CHANNEL_SERVER     = "Server"
+)
+
+type lifecycleEntry struct {
+	Build   int64
+	Release string
+
+	// End of servicing for the general and enterprise editions. Where
+	// both are the same only General is set.
+	General    string
+	Enterprise string
+}
+
+var (
+	clientLifecycle = []lifecycleEntry{
+		{2600, "Windows XP", "2014-04-08", ""},
+		{6002, "Windows Vista", "2017-04-11", ""},
+		{7601, "Windows 7", "2020-01-14", ""},
+		{9200, "Windows 8", "2016-01-12", ""},
+		{9600, "Windows 8.1", "2023-01-10", ""},
+		{10240, "Windows 10 1507", "2017-05-09", ""},
+		{10586, "Windows 10 1511", "2017-10-10", "2018-04-10"},
+		{14393, "Windows 10 1607", "2018-04-10", "2019-04-09"},
+		{15063, "Windows 10 1703", "2018-10-09", "2019-10-08"},
+		{16299, "Windows 10 1709", "2019-04-09", "2020-10-13"},
+		{17134, "Windows 10 1803", "2019-11-12", "2021-05-11"},
+		{17763, "Windows 10 1809", "2020-11-10", "2021-05-11"},
+		{18362, "Windows 10 1903", "2020-12-08", ""},
+		{18363, "Windows 10 1909", "2021-05-11", "2022-05-10"},
+		{19041, "Windows 10 2004", "2021-12-14", ""},
+		{19042, "Windows 10 20H2", "2022-05-10", "2023-05-09"},
+		{19043, "Windows 10 21H1", "2022-12-13", ""},
+		{19044, "Windows 10 21H2", "2023-06-13", "2024-06-11"},
+		{19045, "Windows 10 22H2", "2025-10-14", ""},
+		{22000, "Windows 11 21H2", "2023-10-10", "2024-10-08"},
+		{22621, "Windows 11 22H2", "2024-10-08", "2025-10-14"},
+		{22631, "Windows 11 23H2", "2025-11-11", "2026-11-10"},
+		{26100, "Windows 11 24H2", "2026-10-13", "2027-10-12"},
+		{26200, "Windows 11 25H2", "2027-10-12", "2028-10-10"},
+	}
+
+	ltscLifecycle = []lifecycleEntry{
+		{10240, "Windows 10 Enterprise LTSB 2015", "2025-10-14", ""},
+		{14393, "Windows 10 Enterprise LTSB 2016", "2026-10-13", ""},
+		{17763, "Windows 10 Enterprise LTSC 2019", "2029-01-09", ""},
+		{19044, "Windows 10 Enterprise LTSC 2021", "2027-01-12", ""},
+		{26100, "Windows 11 Enterprise LTSC 2024", "2029-10-09", ""},
+	}
+
+	serverLifecycle = []lifecycleEntry{
+		{6002, "Windows Server 2008", "2020-01-14", ""},
+		{7601, "Windows Server 2008 R2", "2020-01-14", ""},
+		{9200, "Windows Server 2012", "2023-10-10", ""},
+		{9600, "Windows Server 2012 R2", "2023-10-10", ""},
+		{14393, "Windows Server 2016", "2027-01-12", ""},
+		{17763, "Windows Server 2019", "2029-01-09", ""},
+		{20348, "Windows Server 2022", "2031-10-14", ""},
+		{26100, "Windows Server 2025", "2034-10-10", ""},
+	}
+
+	// Builds embedded in version strings like "10.0.19045" or
+	// "Build 19045".
+	buildRegex = regexp.MustCompile(`(?i)(?:\d+\.\d+\.|build\s*)(\d{4,5})\b`)
+)
+
+type Lifecycle struct {
+	Product       string
+	Build         int64
+	Release       string
+	Channel       string
+	EndOfSupport  time.Time
+	Supported     bool
+	DaysRemaining int64
+}
+
+// Extract the build number from a version or release string.
+func ParseBuild(version string) int64 {
+	match := buildRegex.FindStringSubmatch(version)
+	if len(match) < 2 {
+		return 0
+	}
+	build, _ := strconv.ParseInt(match[1], 10, 64)
+	return build
+}
+
+// Look up the end of support of the product. Returns nil if the build
+// is not known.
+func LookupLifecycle(product string, build int64, now time.Time) *Lifecycle {
+	if build == 0 {
+		build = ParseBuild(product)
+	}
+
+	lower := strings.ToLower(product)
+
+	table := clientLifecycle
+	channel := CHANNEL_GENERAL
+	switch {
+	case strings.Contains(lower, "server"):
+		table = serverLifecycle
+		channel = CHANNEL_SERVER
+
+	case strings.Contains(lower, "ltsc") || strings.Contains(lower, "ltsb"):
+		table = ltscLifecycle
+		channel = CHANNEL_LTSC
+
+	case strings.Contains(lower, "enterprise") ||
+		strings.Contains(lower, "education"):
+		channel = CHANNEL_ENTERPRISE
+	}
+
+	for _, entry := range table {
+		if entry.Build != build {
+			continue
+		}
+
+		end_date := entry.General
+		if channel == CHANNEL_ENTERPRISE && entry.Enterprise != "" {
+			end_date = entry.Enterprise
+		}
+
+		end, err := time.Parse("2006-01-02", end_date)
+		if err != nil {
+			return nil
+		}
+
+		return &Lifecycle{
+			Product:       product,
+			Build:         build,
+			Release:       entry.Release,
+			Channel:       channel,
+			EndOfSupport:  end,
+			Supported:     now.Before(end),
+			DaysRemaining: int64(end.Sub(now).Hours() / 24),
+		}
+	}
+
+	return nil
+}
+
+type WindowsLifecycleFunctionArgs struct {
+	Product string `vfilter:"required,field=product,doc=The product name (e.g. ProductName from the registry or the client's OS release)."`
+	Build   int64  `vfilter:"optional,field=build,doc=The OS build number (default parsed from the product)."`
+}
+
+type WindowsLifecycleFunction struct{}
+
+func (self WindowsLifecycleFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	arg := &WindowsLifecycleFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("windows_lifecycle: %v", err)
+		return vfilter.Null{}
+	}
+
+	result := LookupLifecycle(arg.Product, arg.Build, utils.GetTime().Now())
+	if result == nil {
+		return vfilter.Null{}
+	}
+
+	return result
+}
+
+func (self WindowsLifecycleFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "windows_lifecycle",
+		Doc:     "Look up the end of support date of a Windows release.",
+		ArgType: type_map.AddType(scope, &WindowsLifecycleFunctionArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&WindowsLifecycleFunction{})
+}
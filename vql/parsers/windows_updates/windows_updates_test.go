@@ -0,0 +1,70 @@
+package windows_updates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestCBSPackage(t *testing.T) {
+	pkg := ParsePackageIdentity(
+		"Package_for_KB5034441~31bf3856ad364e35~amd64~~10.0.1.3")
+	assert.Equal(t, "KB5034441", pkg.KB)
+	assert.Equal(t, "Package_for_KB5034441", pkg.Package)
+	assert.Equal(t, "amd64", pkg.Architecture)
+	assert.Equal(t, "", pkg.Language)
+	assert.Equal(t, "10.0.1.3", pkg.Version)
+
+	// Rollups only mention the KB in the cab they came from.
+	pkg = ParsePackageIdentity(
+		"Package_for_RollupFix~31bf3856ad364e35~amd64~~19041.3930.1.7")
+	assert.Equal(t, "", pkg.KB)
+
+	pkg.SetValues(ordereddict.NewDict().
+		Set("CurrentState", uint64(0x70)).
+		Set("InstallTimeHigh", uint64(0x01da4b2c)).
+		Set("InstallTimeLow", uint64(0x8a3e5a00)).
+		Set("InstallClient", "UpdateAgentLCU").
+		Set("InstallLocation",
+			`\\?\C:\Windows\SoftwareDistribution\Download\x\Windows10.0-KB5034122-x64.cab`))
+
+	assert.Equal(t, "KB5034122", pkg.KB)
+	assert.Equal(t, "Installed", pkg.State)
+	assert.Equal(t, "UpdateAgentLCU", pkg.InstallClient)
+	assert.Equal(t, 2024, pkg.InstallTime.Year())
+
+	assert.Equal(t, "Failed", CBSStateName(0xffffffff))
+	assert.Equal(t, "Unknown", CBSStateName(3))
+	assert.True(t, FiletimeFromParts(0, 0).IsZero())
+}
+
+func TestLifecycle(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Windows 11 still reports itself as Windows 10 in ProductName.
+	result := LookupLifecycle("Windows 10 Pro", 22631, now)
+	assert.Equal(t, "Windows 11 23H2", result.Release)
+	assert.Equal(t, CHANNEL_GENERAL, result.Channel)
+	assert.True(t, result.Supported)
+
+	result = LookupLifecycle("Windows 10 Enterprise", 22621, now)
+	assert.Equal(t, "2025-10-14", result.EndOfSupport.Format("2006-01-02"))
+
+	result = LookupLifecycle("Windows 10 Enterprise LTSC 2019", 17763, now)
+	assert.Equal(t, CHANNEL_LTSC, result.Channel)
+	assert.True(t, result.Supported)
+
+	result = LookupLifecycle("Windows 10 Pro", 17763, now)
+	assert.True(t, !result.Supported)
+	assert.True(t, result.DaysRemaining < 0)
+
+	// The build is parsed from the client's OS release.
+	result = LookupLifecycle(
+		"Microsoft Windows Server 2016 Datacenter10.0.14393 Build 14393", 0, now)
+	assert.Equal(t, "Windows Server 2016", result.Release)
+	assert.Equal(t, int64(14393), result.Build)
+
+	assert.Nil(t, LookupLifecycle("ubuntu22.04", 0, now))
+}
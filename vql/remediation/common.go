@@ -0,0 +1,49 @@
+// Plugins which make changes to the endpoint in order to remove
+// threats. These require the REMEDIATION permission and record
+// everything they do in the query log so the collection serves as an
+// audit trail.
+package remediation
+
+import (
+	"errors"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+)
+
+func checkAccess(scope vfilter.Scope) error {
+	err := vql_subsystem.CheckAccess(scope, acls.REMEDIATION)
+	if err != nil {
+		return err
+	}
+
+	// Clients which may not run programs should not be changed
+	// either.
+	config_obj, ok := artifacts.GetConfig(scope)
+	if ok && config_obj.PreventExecve {
+		return errors.New("Not allowed to remediate by configuration.")
+	}
+
+	return nil
+}
+
+// Every action produces a single row describing what was done and
+// the state of the object before it was changed.
+func makeResult(name, action string,
+	details *ordereddict.Dict, err error) *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("Name", name).
+		Set("Action", action)
+	result.MergeFrom(details)
+	result.Set("Success", err == nil)
+
+	if err != nil {
+		result.Set("Error", err.Error())
+	} else {
+		result.Set("Error", vfilter.Null{})
+	}
+	return result
+}
@@ -0,0 +1,243 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/psutils"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+var (
+	// Killing any of these will crash or destabilize the system.
+	protected_processes = []string{
+		"system", "registry", "smss.exe", "csrss.exe", "wininit.exe",
+		"winlogon.exe", "services.exe", "lsass.exe", "lsaiso.exe",
+		"init", "systemd", "kthreadd", "launchd", "kernel_task",
+	}
+
+	// Allow tests to replace the kill function.
+	killProcess = psutils.Kill
+)
+
+type ProcessKillArgs struct {
+	Pid         int64  `vfilter:"optional,field=pid,doc=The pid to kill."`
+	Name        string `vfilter:"optional,field=name,doc=Kill processes with a name matching this regex."`
+	CommandLine string `vfilter:"optional,field=command_line,doc=Kill processes with a command line matching this regex."`
+	Exe         string `vfilter:"optional,field=exe,doc=Kill processes with an executable path matching this regex."`
+	Max         int64  `vfilter:"optional,field=max,doc=Refuse to kill anything if more than this many processes match (default 1)."`
+	Force       bool   `vfilter:"optional,field=force,doc=Allow killing critical system processes."`
+	DryRun      bool   `vfilter:"optional,field=dry_run,doc=Only report the processes that would be killed."`
+}
+
+type processMatcher struct {
+	pid     int64
+	regexes map[string]*regexp.Regexp
+	force   bool
+}
+
+func newProcessMatcher(arg *ProcessKillArgs) (*processMatcher, error) {
+	result := &processMatcher{
+		pid:     arg.Pid,
+		regexes: make(map[string]*regexp.Regexp),
+		force:   arg.Force,
+	}
+
+	for field, value := range map[string]string{
+		"Name":        arg.Name,
+		"CommandLine": arg.CommandLine,
+		"Exe":         arg.Exe,
+	} {
+		if value == "" {
+			continue
+		}
+
+		re, err := regexp.Compile("(?i)" + value)
+		if err != nil {
+			return nil, err
+		}
+		result.regexes[field] = re
+	}
+
+	if result.pid <= 0 && len(result.regexes) == 0 {
+		return nil, errors.New(
+			"At least one of pid, name, command_line or exe must be specified")
+	}
+
+	return result, nil
+}
+
+// All the specified criteria must match.
+func (self *processMatcher) matches(process *ordereddict.Dict) bool {
+	if self.pid > 0 && getInt(process, "Pid") != self.pid {
+		return false
+	}
+
+	for field, re := range self.regexes {
+		if !re.MatchString(getString(process, field)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Check that it is safe to kill the process.
+func (self *processMatcher) checkSafe(process *ordereddict.Dict) error {
+	pid := getInt(process, "Pid")
+	switch pid {
+	case 0, 1, 4:
+		return errors.New("Refusing to kill a system process")
+	case int64(os.Getpid()):
+		return errors.New("Refusing to kill ourselves")
+	case int64(os.Getppid()):
+		return errors.New("Refusing to kill our parent")
+	}
+
+	if self.force {
+		return nil
+	}
+
+	name := strings.ToLower(filepath.Base(getString(process, "Name")))
+	for _, protected := range protected_processes {
+		if name == protected {
+			return fmt.Errorf("Refusing to kill critical process %v", name)
+		}
+	}
+
+	return nil
+}
+
+func getString(item *ordereddict.Dict, field string) string {
+	value, _ := item.Get(field)
+	switch t := value.(type) {
+	case string:
+		return t
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+func getInt(item *ordereddict.Dict, field string) int64 {
+	value, _ := item.Get(field)
+	switch t := value.(type) {
+	case int64:
+		return t
+	case int32:
+		return int64(t)
+	case uint32:
+		return int64(t)
+	case uint64:
+		return int64(t)
+	case int:
+		return int64(t)
+	}
+	return -1
+}
+
+func runProcessKill(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	var result []vfilter.Row
+
+	err := checkAccess(scope)
+	if err != nil {
+		scope.Log("process_kill: %v", err)
+		return result
+	}
+
+	arg := &ProcessKillArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("process_kill: %v", err)
+		return result
+	}
+
+	matcher, err := newProcessMatcher(arg)
+	if err != nil {
+		scope.Log("process_kill: %v", err)
+		return result
+	}
+
+	if arg.Max <= 0 {
+		arg.Max = 1
+	}
+
+	pslist, pres := scope.GetPlugin("pslist")
+	if !pres {
+		scope.Log("process_kill: pslist() is not available")
+		return result
+	}
+
+	pslist_args := ordereddict.NewDict()
+	if arg.Pid > 0 {
+		pslist_args.Set("pid", arg.Pid)
+	}
+
+	var matches []*ordereddict.Dict
+	for row := range pslist.Call(ctx, scope, pslist_args) {
+		process := vfilter.RowToDict(ctx, scope, row)
+		if matcher.matches(process) {
+			matches = append(matches, process)
+		}
+	}
+
+	if int64(len(matches)) > arg.Max {
+		scope.Log("process_kill: %v processes match but max is %v - not killing anything",
+			len(matches), arg.Max)
+		return result
+	}
+
+	for _, process := range matches {
+		pid := getInt(process, "Pid")
+		row := ordereddict.NewDict().
+			Set("Pid", pid).
+			Set("Ppid", getInt(process, "Ppid")).
+			Set("CommandLine", getString(process, "CommandLine")).
+			Set("Exe", getString(process, "Exe")).
+			Set("Username", getString(process, "Username"))
+
+		err := matcher.checkSafe(process)
+		if err == nil && !arg.DryRun {
+			scope.Log("process_kill: Killing pid %v (%v): %v", pid,
+				getString(process, "Name"), getString(process, "CommandLine"))
+			err = killProcess(int32(pid))
+		}
+
+		if err != nil {
+			scope.Log("process_kill: %v (%v): %v", pid,
+				getString(process, "Name"), err)
+		}
+
+		action := "kill"
+		if arg.DryRun {
+			action = "dry_run"
+		}
+
+		result = append(result, makeResult(
+			getString(process, "Name"), action, row, err))
+	}
+
+	return result
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "process_kill",
+		Doc:        "Kill processes by pid or matching criteria.",
+		Function:   runProcessKill,
+		ArgType:    &ProcessKillArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.REMEDIATION).Build(),
+	})
+}
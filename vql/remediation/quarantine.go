@@ -0,0 +1,439 @@
+package remediation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services/writeback"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	// Quarantined files are scrambled so they can not be
+	// accidentally run or picked up by other tools.
+	QUARANTINE_KEY = 0xA5
+)
+
+var (
+	quarantine_id_regex = regexp.MustCompile("^[0-9a-f]{16}$")
+)
+
+// Stored next to each quarantined file.
+type QuarantineRecord struct {
+	Id             string    `json:"Id"`
+	OriginalPath   string    `json:"OriginalPath"`
+	Size           int64     `json:"Size"`
+	SHA256         string    `json:"SHA256"`
+	Mode           uint32    `json:"Mode"`
+	ModTime        time.Time `json:"ModTime"`
+	QuarantineTime time.Time `json:"QuarantineTime"`
+	Reason         string    `json:"Reason,omitempty"`
+}
+
+// By default the store is kept next to the writeback file which is
+// only accessible to the client.
+func getQuarantineStore(scope vfilter.Scope, store string) (string, error) {
+	if store == "" {
+		config_obj, ok := artifacts.GetConfig(scope)
+		if !ok {
+			return "", errors.New("No store specified and client not configured")
+		}
+
+		location, err := writeback.WritebackLocation(
+			&config_proto.Config{Client: config_obj})
+		if err != nil {
+			return "", err
+		}
+		store = filepath.Join(filepath.Dir(location), "quarantine")
+	}
+
+	err := os.MkdirAll(store, 0700)
+	return store, err
+}
+
+func newQuarantineId() (string, error) {
+	buf := make([]byte, 8)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+type scrambler struct {
+	io.Reader
+}
+
+func (self scrambler) Read(buf []byte) (int, error) {
+	n, err := self.Reader.Read(buf)
+	for i := 0; i < n; i++ {
+		buf[i] ^= QUARANTINE_KEY
+	}
+	return n, err
+}
+
+// Copy src to dst scrambling or unscrambling the data. Returns the
+// sha256 of the plain data.
+func copyScrambled(src io.Reader, dst string, scrambled_src bool) (string, error) {
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	hasher := sha256.New()
+	if scrambled_src {
+		// Hash the data after unscrambling.
+		_, err = io.Copy(io.MultiWriter(out, hasher), scrambler{src})
+	} else {
+		_, err = io.Copy(out, scrambler{io.TeeReader(src, hasher)})
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func recordPath(store, id string) string {
+	return filepath.Join(store, id+".json")
+}
+
+func dataPath(store, id string) string {
+	return filepath.Join(store, id+".bin")
+}
+
+func quarantineFile(store, path, reason string) (*QuarantineRecord, error) {
+	stat, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !stat.Mode().IsRegular() {
+		return nil, fmt.Errorf("%v is not a regular file", path)
+	}
+
+	id, err := newQuarantineId()
+	if err != nil {
+		return nil, err
+	}
+
+	record := &QuarantineRecord{
+		Id:             id,
+		OriginalPath:   path,
+		Size:           stat.Size(),
+		Mode:           uint32(stat.Mode().Perm()),
+		ModTime:        stat.ModTime().UTC(),
+		QuarantineTime: utils.GetTime().Now().UTC(),
+		Reason:         reason,
+	}
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	record.SHA256, err = copyScrambled(fd, dataPath(store, id), false)
+	fd.Close()
+	if err != nil {
+		os.Remove(dataPath(store, id))
+		return nil, err
+	}
+
+	serialized, err := json.MarshalIndent(record)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ioutil.WriteFile(recordPath(store, id), serialized, 0600)
+	if err != nil {
+		os.Remove(dataPath(store, id))
+		return nil, err
+	}
+
+	// Only remove the original once it is safely stored.
+	err = os.Remove(path)
+	if err != nil {
+		os.Remove(dataPath(store, id))
+		os.Remove(recordPath(store, id))
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func readRecord(store, id string) (*QuarantineRecord, error) {
+	if !quarantine_id_regex.MatchString(id) {
+		return nil, fmt.Errorf("Invalid quarantine id %v", id)
+	}
+
+	serialized, err := ioutil.ReadFile(recordPath(store, id))
+	if err != nil {
+		return nil, err
+	}
+
+	record := &QuarantineRecord{}
+	err = json.Unmarshal(serialized, record)
+	return record, err
+}
+
+func restoreFile(store, id, path string, overwrite bool) (*QuarantineRecord, error) {
+	record, err := readRecord(store, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		path = record.OriginalPath
+	}
+
+	_, err = os.Lstat(path)
+	if err == nil {
+		if !overwrite {
+			return nil, fmt.Errorf("%v already exists", path)
+		}
+		err = os.Remove(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fd, err := os.Open(dataPath(store, id))
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	hash, err := copyScrambled(fd, path, true)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	if hash != record.SHA256 {
+		os.Remove(path)
+		return nil, fmt.Errorf("Quarantined data is corrupted (hash %v)", hash)
+	}
+
+	_ = os.Chmod(path, os.FileMode(record.Mode))
+	_ = os.Chtimes(path, record.ModTime, record.ModTime)
+
+	fd.Close()
+	os.Remove(dataPath(store, id))
+	os.Remove(recordPath(store, id))
+
+	return record, nil
+}
+
+func listRecords(store string) ([]*QuarantineRecord, error) {
+	files, err := ioutil.ReadDir(store)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*QuarantineRecord
+	for _, file := range files {
+		id := strings.TrimSuffix(file.Name(), ".json")
+		if id == file.Name() {
+			continue
+		}
+
+		record, err := readRecord(store, id)
+		if err != nil {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].QuarantineTime.Before(result[j].QuarantineTime)
+	})
+
+	return result, nil
+}
+
+func recordToDict(record *QuarantineRecord) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Id", record.Id).
+		Set("OriginalPath", record.OriginalPath).
+		Set("Size", record.Size).
+		Set("SHA256", record.SHA256).
+		Set("ModTime", record.ModTime).
+		Set("QuarantineTime", record.QuarantineTime).
+		Set("Reason", record.Reason)
+}
+
+type FileQuarantineArgs struct {
+	Path   string `vfilter:"required,field=path,doc=The file to quarantine."`
+	Store  string `vfilter:"optional,field=store,doc=The quarantine directory (default a quarantine directory next to the client's writeback file)."`
+	Reason string `vfilter:"optional,field=reason,doc=Why the file was quarantined."`
+}
+
+func runFileQuarantine(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	err := checkAccess(scope)
+	if err != nil {
+		scope.Log("file_quarantine: %v", err)
+		return nil
+	}
+
+	arg := &FileQuarantineArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("file_quarantine: %v", err)
+		return nil
+	}
+
+	row := ordereddict.NewDict()
+	store, err := getQuarantineStore(scope, arg.Store)
+	if err == nil {
+		scope.Log("file_quarantine: Quarantining %v into %v", arg.Path, store)
+
+		var record *QuarantineRecord
+		record, err = quarantineFile(store, arg.Path, arg.Reason)
+		if err == nil {
+			row = recordToDict(record)
+			scope.Log("file_quarantine: Quarantined %v (sha256 %v) as %v",
+				arg.Path, record.SHA256, record.Id)
+		}
+	}
+
+	if err != nil {
+		scope.Log("file_quarantine: %v: %v", arg.Path, err)
+	}
+
+	return []vfilter.Row{makeResult(arg.Path, "quarantine", row, err)}
+}
+
+type FileRestoreArgs struct {
+	Id        string `vfilter:"required,field=id,doc=The quarantine id of the file."`
+	Store     string `vfilter:"optional,field=store,doc=The quarantine directory (default a quarantine directory next to the client's writeback file)."`
+	Path      string `vfilter:"optional,field=path,doc=Where to restore the file to (default its original path)."`
+	Overwrite bool   `vfilter:"optional,field=overwrite,doc=Replace an existing file at the destination."`
+}
+
+func runFileRestore(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	err := checkAccess(scope)
+	if err != nil {
+		scope.Log("file_restore: %v", err)
+		return nil
+	}
+
+	arg := &FileRestoreArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("file_restore: %v", err)
+		return nil
+	}
+
+	row := ordereddict.NewDict()
+	store, err := getQuarantineStore(scope, arg.Store)
+	if err == nil {
+		scope.Log("file_restore: Restoring %v from %v", arg.Id, store)
+
+		var record *QuarantineRecord
+		record, err = restoreFile(store, arg.Id, arg.Path, arg.Overwrite)
+		if err == nil {
+			row = recordToDict(record)
+			restored_path := arg.Path
+			if restored_path == "" {
+				restored_path = record.OriginalPath
+			}
+			scope.Log("file_restore: Restored %v to %v", arg.Id, restored_path)
+		}
+	}
+
+	if err != nil {
+		scope.Log("file_restore: %v: %v", arg.Id, err)
+	}
+
+	return []vfilter.Row{makeResult(arg.Id, "restore", row, err)}
+}
+
+type QuarantineListArgs struct {
+	Store string `vfilter:"optional,field=store,doc=The quarantine directory (default a quarantine directory next to the client's writeback file)."`
+}
+
+func runQuarantineList(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	var result []vfilter.Row
+
+	err := vql_subsystem.CheckAccess(scope, acls.FILESYSTEM_READ)
+	if err != nil {
+		scope.Log("quarantine_list: %v", err)
+		return result
+	}
+
+	arg := &QuarantineListArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("quarantine_list: %v", err)
+		return result
+	}
+
+	store, err := getQuarantineStore(scope, arg.Store)
+	if err != nil {
+		scope.Log("quarantine_list: %v", err)
+		return result
+	}
+
+	records, err := listRecords(store)
+	if err != nil {
+		scope.Log("quarantine_list: %v", err)
+		return result
+	}
+
+	for _, record := range records {
+		result = append(result, recordToDict(record))
+	}
+
+	return result
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "file_quarantine",
+		Doc:        "Move a file into the quarantine store so it can be restored later.",
+		Function:   runFileQuarantine,
+		ArgType:    &FileQuarantineArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.REMEDIATION).Build(),
+	})
+
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "file_restore",
+		Doc:        "Restore a file from the quarantine store.",
+		Function:   runFileRestore,
+		ArgType:    &FileRestoreArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.REMEDIATION).Build(),
+	})
+
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "quarantine_list",
+		Doc:        "List the files in the quarantine store.",
+		Function:   runQuarantineList,
+		ArgType:    &QuarantineListArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	})
+}
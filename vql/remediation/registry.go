@@ -0,0 +1,389 @@
+// +build windows
+
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"golang.org/x/sys/windows/registry"
+	registry_accessor "www.velocidex.com/golang/velociraptor/accessors/registry"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+	"www.velocidex.com/golang/vfilter/types"
+)
+
+var (
+	value_types = map[uint32]string{
+		registry.NONE:                       "NONE",
+		registry.SZ:                         "SZ",
+		registry.EXPAND_SZ:                  "EXPAND_SZ",
+		registry.BINARY:                     "BINARY",
+		registry.DWORD:                      "DWORD",
+		registry.DWORD_BIG_ENDIAN:           "DWORD_BIG_ENDIAN",
+		registry.LINK:                       "LINK",
+		registry.MULTI_SZ:                   "MULTI_SZ",
+		registry.RESOURCE_LIST:              "RESOURCE_LIST",
+		registry.FULL_RESOURCE_DESCRIPTOR:   "FULL_RESOURCE_DESCRIPTOR",
+		registry.RESOURCE_REQUIREMENTS_LIST: "RESOURCE_REQUIREMENTS_LIST",
+		registry.QWORD:                      "QWORD",
+	}
+)
+
+// Resolve the hive the path starts with.
+func (self registryPath) hive() (registry.Key, error) {
+	hive, ok := registry_accessor.GetHiveFromName(self.hive_name)
+	if !ok {
+		return 0, fmt.Errorf("Unknown root hive name %v", self.hive_name)
+	}
+	return hive, nil
+}
+
+// Read a value so it can be recorded before it is changed.
+func readValue(key registry.Key, name string) (string, interface{}, error) {
+	size, value_type, err := key.GetValue(name, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	type_name, pres := value_types[value_type]
+	if !pres {
+		type_name = fmt.Sprintf("%#x", value_type)
+	}
+
+	switch value_type {
+	case registry.SZ, registry.EXPAND_SZ:
+		data, _, err := key.GetStringValue(name)
+		return type_name, data, err
+
+	case registry.MULTI_SZ:
+		data, _, err := key.GetStringsValue(name)
+		return type_name, data, err
+
+	case registry.DWORD, registry.QWORD:
+		data, _, err := key.GetIntegerValue(name)
+		return type_name, data, err
+
+	default:
+		buf := make([]byte, size)
+		n, _, err := key.GetValue(name, buf)
+		if err != nil {
+			return type_name, nil, err
+		}
+		return type_name, buf[:n], nil
+	}
+}
+
+func writeValue(key registry.Key, name, value_type string, value vfilter.Any) error {
+	data, err := convertValue(value_type, value)
+	if err != nil {
+		return err
+	}
+
+	switch t := data.(type) {
+	case string:
+		if value_type == "EXPAND_SZ" {
+			return key.SetExpandStringValue(name, t)
+		}
+		return key.SetStringValue(name, t)
+
+	case []string:
+		return key.SetStringsValue(name, t)
+
+	case []byte:
+		return key.SetBinaryValue(name, t)
+
+	case uint32:
+		return key.SetDWordValue(name, t)
+
+	case uint64:
+		return key.SetQWordValue(name, t)
+
+	default:
+		return fmt.Errorf("Invalid registry value type %v", value_type)
+	}
+}
+
+// Capture all the values and subkeys of a key so the key may be
+// recreated if needed.
+func snapshotKey(key registry.Key, depth int) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+
+	values := ordereddict.NewDict()
+	names, _ := key.ReadValueNames(-1)
+	for _, name := range names {
+		value_type, data, err := readValue(key, name)
+		if err != nil {
+			continue
+		}
+		if name == "" {
+			name = "@"
+		}
+		values.Set(name, ordereddict.NewDict().
+			Set("Type", value_type).
+			Set("Data", data))
+	}
+	result.Set("Values", values)
+
+	subkeys := ordereddict.NewDict()
+	if depth > 0 {
+		names, _ = key.ReadSubKeyNames(-1)
+		for _, name := range names {
+			subkey, err := registry.OpenKey(key, name, registry.READ)
+			if err != nil {
+				continue
+			}
+			subkeys.Set(name, snapshotKey(subkey, depth-1))
+			subkey.Close()
+		}
+	}
+	result.Set("Subkeys", subkeys)
+
+	return result
+}
+
+// registry.DeleteKey refuses to remove keys with subkeys.
+func deleteKeyRecursive(parent registry.Key, name string) error {
+	key, err := registry.OpenKey(parent, name, registry.READ|registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+
+	subkeys, _ := key.ReadSubKeyNames(-1)
+	for _, subkey := range subkeys {
+		err = deleteKeyRecursive(key, subkey)
+		if err != nil {
+			key.Close()
+			return err
+		}
+	}
+	key.Close()
+
+	return registry.DeleteKey(parent, name)
+}
+
+type RegSetArgs struct {
+	Path   string         `vfilter:"required,field=path,doc=Registry value path (use @ for the default value)."`
+	Value  types.LazyExpr `vfilter:"required,field=value,doc=Value to set."`
+	Type   string         `vfilter:"optional,field=type,doc=Type to set (SZ, EXPAND_SZ, MULTI_SZ, BINARY, DWORD, QWORD). Defaults to the existing value's type or SZ."`
+	Create bool           `vfilter:"optional,field=create,doc=Create missing intermediate keys."`
+}
+
+func runRegSet(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	err := checkAccess(scope)
+	if err != nil {
+		scope.Log("reg_set: %v", err)
+		return nil
+	}
+
+	arg := &RegSetArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("reg_set: %v", err)
+		return nil
+	}
+
+	details := ordereddict.NewDict()
+	err = regSet(ctx, scope, arg, details)
+	if err != nil {
+		scope.Log("reg_set: %v: %v", arg.Path, err)
+	}
+
+	return []vfilter.Row{makeResult(arg.Path, "set", details, err)}
+}
+
+func regSet(ctx context.Context, scope vfilter.Scope,
+	arg *RegSetArgs, details *ordereddict.Dict) error {
+	path, err := parseRegistryPath(arg.Path)
+	if err != nil {
+		return err
+	}
+
+	hive, err := path.hive()
+	if err != nil {
+		return err
+	}
+
+	value_name := path.valueName()
+
+	var key registry.Key
+	if arg.Create {
+		key, _, err = registry.CreateKey(hive, path.subkey,
+			registry.QUERY_VALUE|registry.SET_VALUE)
+	} else {
+		key, err = registry.OpenKey(hive, path.subkey,
+			registry.QUERY_VALUE|registry.SET_VALUE)
+	}
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	// Record the previous value for rollback.
+	previous_type, previous_data, err := readValue(key, value_name)
+	existed := err == nil
+	details.Set("Existed", existed)
+	if existed {
+		details.Set("PreviousType", previous_type).
+			Set("PreviousData", previous_data)
+		scope.Log("reg_set: %v was %v %v", arg.Path, previous_type,
+			utils.ToString(previous_data))
+	} else {
+		details.Set("PreviousType", vfilter.Null{}).
+			Set("PreviousData", vfilter.Null{})
+	}
+
+	value_type := strings.ToUpper(arg.Type)
+	if value_type == "" {
+		value_type = "SZ"
+		if existed {
+			value_type = previous_type
+		}
+	}
+
+	value := arg.Value.Reduce(ctx)
+	scope.Log("reg_set: Setting %v to %v %v", arg.Path, value_type,
+		utils.ToString(value))
+
+	err = writeValue(key, value_name, value_type, value)
+	if err != nil {
+		return err
+	}
+
+	new_type, new_data, err := readValue(key, value_name)
+	if err != nil {
+		return err
+	}
+	details.Set("Type", new_type).Set("Data", new_data)
+
+	return nil
+}
+
+type RegRmArgs struct {
+	Path string `vfilter:"required,field=path,doc=Registry value or key path."`
+	Key  bool   `vfilter:"optional,field=key,doc=The path is a key - remove it with all its values and subkeys."`
+}
+
+func runRegRm(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	err := checkAccess(scope)
+	if err != nil {
+		scope.Log("reg_rm: %v", err)
+		return nil
+	}
+
+	arg := &RegRmArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("reg_rm: %v", err)
+		return nil
+	}
+
+	details := ordereddict.NewDict()
+	action := "delete_value"
+	if arg.Key {
+		action = "delete_key"
+		err = regRmKey(scope, arg.Path, details)
+	} else {
+		err = regRmValue(scope, arg.Path, details)
+	}
+	if err != nil {
+		scope.Log("reg_rm: %v: %v", arg.Path, err)
+	}
+
+	return []vfilter.Row{makeResult(arg.Path, action, details, err)}
+}
+
+func regRmValue(scope vfilter.Scope, reg_path string, details *ordereddict.Dict) error {
+	path, err := parseRegistryPath(reg_path)
+	if err != nil {
+		return err
+	}
+
+	hive, err := path.hive()
+	if err != nil {
+		return err
+	}
+
+	value_name := path.valueName()
+
+	key, err := registry.OpenKey(hive, path.subkey,
+		registry.QUERY_VALUE|registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	previous_type, previous_data, err := readValue(key, value_name)
+	if err != nil {
+		return err
+	}
+	details.Set("PreviousType", previous_type).
+		Set("PreviousData", previous_data)
+
+	scope.Log("reg_rm: Deleting value %v in key %v (was %v %v)", path.name,
+		path.parent(), previous_type, utils.ToString(previous_data))
+
+	return key.DeleteValue(value_name)
+}
+
+func regRmKey(scope vfilter.Scope, reg_path string, details *ordereddict.Dict) error {
+	path, err := parseRegistryPath(reg_path)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to remove a whole hive or top level key.
+	if path.subkey == "" {
+		return errors.New("Refusing to remove a top level key")
+	}
+
+	hive, err := path.hive()
+	if err != nil {
+		return err
+	}
+
+	parent, err := registry.OpenKey(hive, path.subkey,
+		registry.READ|registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer parent.Close()
+
+	key, err := registry.OpenKey(parent, path.name, registry.READ)
+	if err != nil {
+		return err
+	}
+	details.Set("Previous", snapshotKey(key, 5))
+	key.Close()
+
+	scope.Log("reg_rm: Deleting key %v in %v", path.name, path.parent())
+
+	return deleteKeyRecursive(parent, path.name)
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "reg_set",
+		Doc:        "Set a registry value, recording the previous value.",
+		Function:   runRegSet,
+		ArgType:    &RegSetArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.REMEDIATION).Build(),
+	})
+
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "reg_rm",
+		Doc:        "Remove a registry value or key, recording what was removed.",
+		Function:   runRegRm,
+		ArgType:    &RegRmArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.REMEDIATION).Build(),
+	})
+}
@@ -0,0 +1,91 @@
+package remediation
+
+import (
+	"fmt"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/utils"
+	vfilter "www.velocidex.com/golang/vfilter"
+)
+
+type registryPath struct {
+	hive_name string
+	subkey    string
+	// The last component - either a value or a subkey name.
+	name string
+}
+
+func (self registryPath) parent() string {
+	return self.hive_name + "\\" + self.subkey
+}
+
+// The default value of a key is referred to as @.
+func (self registryPath) valueName() string {
+	if self.name == "@" {
+		return ""
+	}
+	return self.name
+}
+
+func parseRegistryPath(path string) (*registryPath, error) {
+	components := utils.SplitComponents(path)
+	if len(components) < 2 {
+		return nil, fmt.Errorf("Invalid registry path %v", path)
+	}
+
+	last_idx := len(components) - 1
+	return &registryPath{
+		hive_name: components[0],
+		subkey:    strings.Join(components[1:last_idx], "\\"),
+		name:      components[last_idx],
+	}, nil
+}
+
+// Convert the VQL value to the Go type used to write the registry
+// value type: string for SZ and EXPAND_SZ, []string for MULTI_SZ,
+// []byte for BINARY, uint32 for DWORD and uint64 for QWORD.
+func convertValue(value_type string, value vfilter.Any) (interface{}, error) {
+	switch value_type {
+	case "SZ", "EXPAND_SZ":
+		return utils.ToString(value), nil
+
+	case "MULTI_SZ":
+		switch t := value.(type) {
+		case []string:
+			return t, nil
+		case []vfilter.Any:
+			data := []string{}
+			for _, item := range t {
+				data = append(data, utils.ToString(item))
+			}
+			return data, nil
+		default:
+			return []string{utils.ToString(value)}, nil
+		}
+
+	case "BINARY":
+		switch t := value.(type) {
+		case []byte:
+			return t, nil
+		default:
+			return []byte(utils.ToString(value)), nil
+		}
+
+	case "DWORD":
+		value_int, ok := utils.ToInt64(value)
+		if !ok {
+			return nil, fmt.Errorf("Value %v is not an integer", value)
+		}
+		return uint32(value_int), nil
+
+	case "QWORD":
+		value_int, ok := utils.ToInt64(value)
+		if !ok {
+			return nil, fmt.Errorf("Value %v is not an integer", value)
+		}
+		return uint64(value_int), nil
+
+	default:
+		return nil, fmt.Errorf("Invalid registry value type %v", value_type)
+	}
+}
@@ -0,0 +1,201 @@
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+	vfilter "www.velocidex.com/golang/vfilter"
+)
+
+func TestProcessMatcher(t *testing.T) {
+	_, err := newProcessMatcher(&ProcessKillArgs{})
+	assert.Error(t, err)
+
+	matcher, err := newProcessMatcher(&ProcessKillArgs{
+		Name:        "^evil",
+		CommandLine: "--beacon",
+	})
+	assert.NoError(t, err)
+
+	process := ordereddict.NewDict().
+		Set("Pid", int64(1234)).
+		Set("Name", "Evil.exe").
+		Set("CommandLine", "evil.exe --beacon 10.1.1.1")
+	assert.True(t, matcher.matches(process))
+	assert.NoError(t, matcher.checkSafe(process))
+
+	// All criteria must match
+	process.Set("CommandLine", "evil.exe")
+	assert.True(t, !matcher.matches(process))
+
+	// Critical processes are refused unless forced.
+	lsass := ordereddict.NewDict().
+		Set("Pid", int64(600)).
+		Set("Name", "lsass.exe")
+	assert.Error(t, matcher.checkSafe(lsass))
+
+	matcher.force = true
+	assert.NoError(t, matcher.checkSafe(lsass))
+
+	// Even with force we never kill ourselves.
+	self := ordereddict.NewDict().
+		Set("Pid", int64(os.Getpid())).
+		Set("Name", "velociraptor")
+	assert.Error(t, matcher.checkSafe(self))
+}
+
+func TestQuarantine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quarantine_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := filepath.Join(dir, "store")
+	assert.NoError(t, os.MkdirAll(store, 0700))
+
+	target := filepath.Join(dir, "malware.exe")
+	content := []byte("MZ this program is very bad")
+	assert.NoError(t, ioutil.WriteFile(target, content, 0600))
+
+	record, err := quarantineFile(store, target, "testing")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), record.Size)
+	assert.Equal(t, "testing", record.Reason)
+
+	// The original is gone and the stored copy is scrambled.
+	_, err = os.Stat(target)
+	assert.True(t, os.IsNotExist(err))
+
+	stored, err := ioutil.ReadFile(dataPath(store, record.Id))
+	assert.NoError(t, err)
+	assert.True(t, string(content) != string(stored))
+
+	records, err := listRecords(store)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, record.SHA256, records[0].SHA256)
+
+	// Invalid ids are rejected.
+	_, err = restoreFile(store, "../../etc/passwd", "", false)
+	assert.Error(t, err)
+
+	// Do not overwrite an existing file unless asked.
+	assert.NoError(t, ioutil.WriteFile(target, []byte("new"), 0600))
+	_, err = restoreFile(store, record.Id, "", false)
+	assert.Error(t, err)
+
+	_, err = restoreFile(store, record.Id, "", true)
+	assert.NoError(t, err)
+
+	restored, err := ioutil.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, content, restored)
+
+	// The store is now empty.
+	records, err = listRecords(store)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(records))
+}
+
+func TestParseRegistryPath(t *testing.T) {
+	_, err := parseRegistryPath(`HKEY_LOCAL_MACHINE`)
+	assert.Error(t, err)
+
+	path, err := parseRegistryPath(
+		`HKEY_LOCAL_MACHINE\Software\Microsoft\Windows\CurrentVersion\Run\Evil`)
+	assert.NoError(t, err)
+	assert.Equal(t, "HKEY_LOCAL_MACHINE", path.hive_name)
+	assert.Equal(t, `Software\Microsoft\Windows\CurrentVersion\Run`, path.subkey)
+	assert.Equal(t, "Evil", path.name)
+	assert.Equal(t, "Evil", path.valueName())
+	assert.Equal(t, `HKEY_LOCAL_MACHINE\Software\Microsoft\Windows\CurrentVersion\Run`,
+		path.parent())
+
+	// Forward slashes and quoted components containing backslashes.
+	path, err = parseRegistryPath(`/HKEY_USERS/S-1-5-18/Software/"a\b"/@`)
+	assert.NoError(t, err)
+	assert.Equal(t, "HKEY_USERS", path.hive_name)
+	assert.Equal(t, `S-1-5-18\Software\a\b`, path.subkey)
+	assert.Equal(t, "@", path.name)
+	assert.Equal(t, "", path.valueName())
+
+	// A top level key has no subkey.
+	path, err = parseRegistryPath(`HKEY_CURRENT_USER\Software`)
+	assert.NoError(t, err)
+	assert.Equal(t, "", path.subkey)
+	assert.Equal(t, "Software", path.name)
+}
+
+func TestConvertValue(t *testing.T) {
+	for _, test_case := range []struct {
+		value_type string
+		value      vfilter.Any
+		expected   interface{}
+	}{
+		{"SZ", "hello", "hello"},
+		{"SZ", int64(5), "5"},
+		{"EXPAND_SZ", `%SystemRoot%\evil.exe`, `%SystemRoot%\evil.exe`},
+		{"MULTI_SZ", "one", []string{"one"}},
+		{"MULTI_SZ", []string{"one", "two"}, []string{"one", "two"}},
+		{"MULTI_SZ", []vfilter.Any{"one", int64(2)}, []string{"one", "2"}},
+		{"BINARY", []byte{0, 1, 2}, []byte{0, 1, 2}},
+		{"BINARY", "MZ", []byte("MZ")},
+		{"DWORD", int64(1), uint32(1)},
+		{"DWORD", "16", uint32(16)},
+		{"QWORD", int64(1) << 40, uint64(1) << 40},
+	} {
+		result, err := convertValue(test_case.value_type, test_case.value)
+		assert.NoError(t, err)
+		assert.Equal(t, test_case.expected, result,
+			"%v %v", test_case.value_type, test_case.value)
+	}
+
+	_, err := convertValue("DWORD", "not a number")
+	assert.Error(t, err)
+
+	_, err = convertValue("QWORD", []string{})
+	assert.Error(t, err)
+
+	_, err = convertValue("LINK", "target")
+	assert.Error(t, err)
+}
+
+// Every plugin making changes must refuse to run without the
+// REMEDIATION permission. Some plugins are only registered on
+// Windows.
+func TestRequiresRemediationPermission(t *testing.T) {
+	for _, name := range []string{
+		"process_kill", "file_quarantine", "file_restore",
+		"reg_set", "reg_rm", "service_stop", "service_delete",
+		"driver_unload", "scheduled_task_delete",
+	} {
+		plugin, pres := vql_subsystem.GetPlugin(name)
+		if !pres {
+			continue
+		}
+
+		log_buffer := &bytes.Buffer{}
+		scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+			Set(vql_subsystem.ACL_MANAGER_VAR,
+				acl_managers.NewRoleACLManager(nil, "investigator")))
+		scope.SetLogger(log.New(log_buffer, "", 0))
+
+		rows := []vfilter.Row{}
+		for row := range plugin.Call(context.Background(), scope,
+			ordereddict.NewDict()) {
+			rows = append(rows, row)
+		}
+		scope.Close()
+
+		assert.Equal(t, 0, len(rows), name)
+		assert.Contains(t, log_buffer.String(), "REMEDIATION", name)
+	}
+}
@@ -0,0 +1,339 @@
+// +build windows
+
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+var (
+	service_states = map[svc.State]string{
+		svc.Stopped:         "Stopped",
+		svc.StartPending:    "StartPending",
+		svc.StopPending:     "StopPending",
+		svc.Running:         "Running",
+		svc.ContinuePending: "ContinuePending",
+		svc.PausePending:    "PausePending",
+		svc.Paused:          "Paused",
+	}
+
+	start_types = map[uint32]string{
+		windows.SERVICE_BOOT_START:   "Boot",
+		windows.SERVICE_SYSTEM_START: "System",
+		windows.SERVICE_AUTO_START:   "Automatic",
+		windows.SERVICE_DEMAND_START: "Manual",
+		windows.SERVICE_DISABLED:     "Disabled",
+	}
+)
+
+func stateName(state svc.State) string {
+	name, pres := service_states[state]
+	if pres {
+		return name
+	}
+	return fmt.Sprintf("%d", state)
+}
+
+func startTypeName(start_type uint32) string {
+	name, pres := start_types[start_type]
+	if pres {
+		return name
+	}
+	return fmt.Sprintf("%d", start_type)
+}
+
+// Record the service's details before we change it.
+func describeService(scope vfilter.Scope, plugin string,
+	service *mgr.Service, result *ordereddict.Dict) (mgr.Config, error) {
+	config, err := service.Config()
+	if err != nil {
+		return config, err
+	}
+
+	status, err := service.Query()
+	if err != nil {
+		return config, err
+	}
+
+	result.Set("DisplayName", config.DisplayName).
+		Set("BinaryPath", config.BinaryPathName).
+		Set("StartType", startTypeName(config.StartType)).
+		Set("PreviousState", stateName(status.State))
+
+	scope.Log("%v: Service %v (%v) binary %v start type %v state %v",
+		plugin, service.Name, config.DisplayName, config.BinaryPathName,
+		startTypeName(config.StartType), stateName(status.State))
+
+	return config, nil
+}
+
+// Stop the service and wait for it to actually stop.
+func stopService(ctx context.Context, scope vfilter.Scope, plugin string,
+	service *mgr.Service, wait time.Duration) (svc.State, error) {
+	status, err := service.Query()
+	if err != nil {
+		return 0, err
+	}
+
+	if status.State == svc.Stopped {
+		return status.State, nil
+	}
+
+	scope.Log("%v: Stopping %v", plugin, service.Name)
+	status, err = service.Control(svc.Stop)
+	if err != nil {
+		return status.State, err
+	}
+
+	deadline := time.Now().Add(wait)
+	for status.State != svc.Stopped {
+		if time.Now().After(deadline) {
+			return status.State, fmt.Errorf("Timed out waiting for %v to stop",
+				service.Name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.State, ctx.Err()
+		case <-time.After(300 * time.Millisecond):
+		}
+
+		status, err = service.Query()
+		if err != nil {
+			return status.State, err
+		}
+	}
+
+	return status.State, nil
+}
+
+func disableService(scope vfilter.Scope, plugin string,
+	service *mgr.Service, config mgr.Config) error {
+	scope.Log("%v: Disabling %v", plugin, service.Name)
+	config.StartType = windows.SERVICE_DISABLED
+	return service.UpdateConfig(config)
+}
+
+func openService(name string) (*mgr.Mgr, *mgr.Service, error) {
+	manager, err := mgr.Connect()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	service, err := manager.OpenService(name)
+	if err != nil {
+		manager.Disconnect()
+		return nil, nil, err
+	}
+
+	return manager, service, nil
+}
+
+type ServiceStopArgs struct {
+	Name    string `vfilter:"required,field=name,doc=The name of the service."`
+	Disable bool   `vfilter:"optional,field=disable,doc=Also disable the service so it does not start again."`
+	Wait    int64  `vfilter:"optional,field=wait,doc=How many seconds to wait for the service to stop (default 30)."`
+}
+
+type ServiceDeleteArgs struct {
+	Name string `vfilter:"required,field=name,doc=The name of the service."`
+	Stop bool   `vfilter:"optional,field=stop,doc=Stop the service first, otherwise a running service is only deleted once it stops."`
+	Wait int64  `vfilter:"optional,field=wait,doc=How many seconds to wait for the service to stop (default 30)."`
+}
+
+type DriverUnloadArgs struct {
+	Name   string `vfilter:"required,field=name,doc=The name of the driver's service."`
+	Delete bool   `vfilter:"optional,field=delete,doc=Also delete the driver's service so it is not loaded again."`
+	Wait   int64  `vfilter:"optional,field=wait,doc=How many seconds to wait for the driver to unload (default 30)."`
+}
+
+// What to do with the service.
+type serviceActions struct {
+	drivers bool
+	stop    bool
+	disable bool
+	delete  bool
+	wait    int64
+}
+
+func (self serviceActions) String() string {
+	var result []string
+	if self.stop {
+		result = append(result, "stop")
+	}
+	if self.disable {
+		result = append(result, "disable")
+	}
+	if self.delete {
+		result = append(result, "delete")
+	}
+	return strings.Join(result, ",")
+}
+
+func runServiceRemediation(
+	ctx context.Context, scope vfilter.Scope,
+	plugin, name string, actions serviceActions) []vfilter.Row {
+	if actions.wait <= 0 {
+		actions.wait = 30
+	}
+
+	row := ordereddict.NewDict()
+	err := remediateService(ctx, scope, plugin, name, actions, row)
+	if err != nil {
+		scope.Log("%v: %v: %v", plugin, name, err)
+	}
+
+	return []vfilter.Row{makeResult(name, actions.String(), row, err)}
+}
+
+func remediateService(
+	ctx context.Context, scope vfilter.Scope,
+	plugin, name string, actions serviceActions,
+	row *ordereddict.Dict) error {
+	manager, service, err := openService(name)
+	if err != nil {
+		return err
+	}
+	defer manager.Disconnect()
+	defer service.Close()
+
+	config, err := describeService(scope, plugin, service, row)
+	if err != nil {
+		return err
+	}
+
+	is_driver := config.ServiceType&windows.SERVICE_DRIVER != 0
+	if actions.drivers && !is_driver {
+		return errors.New("Service is not a driver, use service_stop()")
+	}
+
+	if !actions.drivers && is_driver {
+		return errors.New("Service is a driver, use driver_unload()")
+	}
+
+	if actions.stop {
+		state, err := stopService(ctx, scope, plugin, service,
+			time.Duration(actions.wait)*time.Second)
+		row.Set("State", stateName(state))
+		if err != nil {
+			return err
+		}
+	}
+
+	if actions.disable {
+		err = disableService(scope, plugin, service, config)
+		if err != nil {
+			return err
+		}
+	}
+
+	if actions.delete {
+		scope.Log("%v: Deleting %v", plugin, name)
+		err = service.Delete()
+		if err != nil {
+			return err
+		}
+	}
+
+	scope.Log("%v: Completed %v for %v", plugin, actions, name)
+	return nil
+}
+
+func runServiceStop(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	err := checkAccess(scope)
+	if err != nil {
+		scope.Log("service_stop: %v", err)
+		return nil
+	}
+
+	arg := &ServiceStopArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("service_stop: %v", err)
+		return nil
+	}
+
+	return runServiceRemediation(ctx, scope, "service_stop", arg.Name,
+		serviceActions{stop: true, disable: arg.Disable, wait: arg.Wait})
+}
+
+func runServiceDelete(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	err := checkAccess(scope)
+	if err != nil {
+		scope.Log("service_delete: %v", err)
+		return nil
+	}
+
+	arg := &ServiceDeleteArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("service_delete: %v", err)
+		return nil
+	}
+
+	return runServiceRemediation(ctx, scope, "service_delete", arg.Name,
+		serviceActions{stop: arg.Stop, delete: true, wait: arg.Wait})
+}
+
+// Drivers are unloaded by stopping their service.
+func runDriverUnload(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	err := checkAccess(scope)
+	if err != nil {
+		scope.Log("driver_unload: %v", err)
+		return nil
+	}
+
+	arg := &DriverUnloadArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("driver_unload: %v", err)
+		return nil
+	}
+
+	return runServiceRemediation(ctx, scope, "driver_unload", arg.Name,
+		serviceActions{drivers: true, stop: true, delete: arg.Delete,
+			wait: arg.Wait})
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "service_stop",
+		Doc:        "Stop a service and optionally disable it.",
+		Function:   runServiceStop,
+		ArgType:    &ServiceStopArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.REMEDIATION).Build(),
+	})
+
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "service_delete",
+		Doc:        "Delete a service, optionally stopping it first.",
+		Function:   runServiceDelete,
+		ArgType:    &ServiceDeleteArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.REMEDIATION).Build(),
+	})
+
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "driver_unload",
+		Doc:        "Unload a driver by stopping its service, optionally deleting it.",
+		Function:   runDriverUnload,
+		ArgType:    &DriverUnloadArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.REMEDIATION).Build(),
+	})
+}
@@ -0,0 +1,140 @@
+// +build windows
+
+package remediation
+
+import (
+	"context"
+	"runtime"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	ole "github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+// S_FALSE is returned by CoInitializeEx if it was already called on this thread.
+const S_FALSE = 0x00000001
+
+type ScheduledTaskDeleteArgs struct {
+	Path string `vfilter:"required,field=path,doc=The full path of the task (e.g. \\Microsoft\\Windows\\Task)."`
+}
+
+// Split the task path into its folder and name.
+func splitTaskPath(path string) (string, string) {
+	path = strings.ReplaceAll(path, "/", "\\")
+	if !strings.HasPrefix(path, "\\") {
+		path = "\\" + path
+	}
+
+	idx := strings.LastIndex(path, "\\")
+	folder := path[:idx]
+	if folder == "" {
+		folder = "\\"
+	}
+	return folder, path[idx+1:]
+}
+
+func deleteScheduledTask(scope vfilter.Scope,
+	path string, row *ordereddict.Dict) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED)
+	if err != nil {
+		oleCode := err.(*ole.OleError).Code()
+		if oleCode != ole.S_OK && oleCode != S_FALSE {
+			return err
+		}
+	}
+	defer ole.CoUninitialize()
+
+	unknown, err := oleutil.CreateObject("Schedule.Service")
+	if err != nil {
+		return err
+	}
+	defer unknown.Release()
+
+	service, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return err
+	}
+	defer service.Release()
+
+	_, err = oleutil.CallMethod(service, "Connect")
+	if err != nil {
+		return err
+	}
+
+	folder_path, name := splitTaskPath(path)
+	folder_raw, err := oleutil.CallMethod(service, "GetFolder", folder_path)
+	if err != nil {
+		return err
+	}
+	folder := folder_raw.ToIDispatch()
+	defer folder.Release()
+
+	// Record the task definition before we remove it.
+	task_raw, err := oleutil.CallMethod(folder, "GetTask", name)
+	if err != nil {
+		return err
+	}
+	task := task_raw.ToIDispatch()
+	defer task.Release()
+
+	for _, property := range []string{"Enabled", "LastRunTime", "Xml"} {
+		value, err := oleutil.GetProperty(task, property)
+		if err != nil {
+			continue
+		}
+		row.Set(property, value.Value())
+		_ = value.Clear()
+	}
+
+	xml, _ := row.GetString("Xml")
+	scope.Log("scheduled_task_delete: Deleting task %v with definition %v",
+		path, xml)
+
+	_, err = oleutil.CallMethod(folder, "DeleteTask", name, 0)
+	return err
+}
+
+func runScheduledTaskDelete(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	err := checkAccess(scope)
+	if err != nil {
+		scope.Log("scheduled_task_delete: %v", err)
+		return nil
+	}
+
+	arg := &ScheduledTaskDeleteArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("scheduled_task_delete: %v", err)
+		return nil
+	}
+
+	row := ordereddict.NewDict()
+	err = deleteScheduledTask(scope, arg.Path, row)
+	if err != nil {
+		scope.Log("scheduled_task_delete: %v: %v", arg.Path, err)
+	} else {
+		scope.Log("scheduled_task_delete: Completed delete for %v", arg.Path)
+	}
+
+	return []vfilter.Row{makeResult(arg.Path, "delete", row, err)}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "scheduled_task_delete",
+		Doc:        "Delete a scheduled task.",
+		Function:   runScheduledTaskDelete,
+		ArgType:    &ScheduledTaskDeleteArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.REMEDIATION).Build(),
+	})
+}
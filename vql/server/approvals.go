@@ -0,0 +1,148 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/approvals"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type ApprovalRequestsPluginArgs struct {
+	State string `vfilter:"optional,field=state,doc=Only show requests in this state (e.g. pending)."`
+}
+
+type ApprovalRequestsPlugin struct{}
+
+func (self ApprovalRequestsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("approval_requests: %v", err)
+			return
+		}
+
+		arg := &ApprovalRequestsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("approval_requests: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("approval_requests: Command can only run on the server")
+			return
+		}
+
+		service := approvals.GetApprovalService(config_obj)
+		if service == nil {
+			scope.Log("approval_requests: Approvals are not enabled")
+			return
+		}
+
+		for _, request := range service.List() {
+			if arg.State != "" && arg.State != request.State {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- approvalRequestRow(request):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self ApprovalRequestsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "approval_requests",
+		Doc:      "List the collections and hunts held for approval, most recent first.",
+		ArgType:  type_map.AddType(scope, &ApprovalRequestsPluginArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type ApprovalDecideFunctionArgs struct {
+	Id      string `vfilter:"required,field=id,doc=The id of the pending request."`
+	Deny    bool   `vfilter:"optional,field=deny,doc=Deny the request instead of approving it."`
+	Comment string `vfilter:"optional,field=comment,doc=A comment recorded with the decision."`
+}
+
+type ApprovalDecideFunction struct{}
+
+func (self ApprovalDecideFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.APPROVE_COLLECTION)
+	if err != nil {
+		scope.Log("approval_decide: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &ApprovalDecideFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("approval_decide: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("approval_decide: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	service := approvals.GetApprovalService(config_obj)
+	if service == nil {
+		scope.Log("approval_decide: Approvals are not enabled")
+		return vfilter.Null{}
+	}
+
+	request, err := service.Decide(ctx, vql_subsystem.GetPrincipal(scope),
+		arg.Id, !arg.Deny, arg.Comment)
+	if err != nil {
+		scope.Log("approval_decide: %v", err)
+		return vfilter.Null{}
+	}
+
+	return approvalRequestRow(request)
+}
+
+func (self ApprovalDecideFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "approval_decide",
+		Doc:      "Approve or deny a collection or hunt requested by another user.",
+		ArgType:  type_map.AddType(scope, &ApprovalDecideFunctionArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.APPROVE_COLLECTION).Build(),
+	}
+}
+
+func approvalRequestRow(request *approvals.Request) *ordereddict.Dict {
+	return approvals.RequestToRow(request).
+		Set("Created", unixTime(request.Created)).
+		Set("Decided", unixTime(request.Decided))
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&ApprovalRequestsPlugin{})
+	vql_subsystem.RegisterFunction(&ApprovalDecideFunction{})
+}
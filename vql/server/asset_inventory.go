@@ -0,0 +1,302 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/asset_inventory"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type AssetInventoryPluginArgs struct {
+	ClientId string `vfilter:"required,field=client_id,doc=The client to show the inventory of."`
+	Category string `vfilter:"required,field=category,doc=The inventory category (Software, Patches, Users, ListeningServices or Hardware)."`
+	Version  string `vfilter:"optional,field=version,doc=The version of the inventory (default latest)."`
+}
+
+type AssetInventoryPlugin struct{}
+
+func (self AssetInventoryPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("asset_inventory: %v", err)
+			return
+		}
+
+		arg := &AssetInventoryPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("asset_inventory: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("asset_inventory: Command can only run on the server")
+			return
+		}
+
+		service, err := asset_inventory.GetAssetInventoryService(config_obj)
+		if err != nil {
+			scope.Log("asset_inventory: %v", err)
+			return
+		}
+
+		rows, err := service.Rows(ctx, arg.ClientId, arg.Category, arg.Version)
+		if err != nil {
+			scope.Log("asset_inventory: %v", err)
+			return
+		}
+
+		for _, row := range rows {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self AssetInventoryPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "asset_inventory",
+		Doc:      "Show a category of a client's asset inventory.",
+		ArgType:  type_map.AddType(scope, &AssetInventoryPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type AssetInventoryDiffPluginArgs struct {
+	ClientId string `vfilter:"required,field=client_id,doc=The client to compare the inventory of."`
+	Category string `vfilter:"required,field=category,doc=The inventory category (Software, Patches, Users, ListeningServices or Hardware)."`
+	From     string `vfilter:"optional,field=from,doc=The older version (default the version before to)."`
+	To       string `vfilter:"optional,field=to,doc=The newer version (default latest)."`
+}
+
+type AssetInventoryDiffPlugin struct{}
+
+func (self AssetInventoryDiffPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("asset_inventory_diff: %v", err)
+			return
+		}
+
+		arg := &AssetInventoryDiffPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("asset_inventory_diff: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("asset_inventory_diff: Command can only run on the server")
+			return
+		}
+
+		service, err := asset_inventory.GetAssetInventoryService(config_obj)
+		if err != nil {
+			scope.Log("asset_inventory_diff: %v", err)
+			return
+		}
+
+		rows, err := service.Diff(ctx, arg.ClientId, arg.Category,
+			arg.From, arg.To)
+		if err != nil {
+			scope.Log("asset_inventory_diff: %v", err)
+			return
+		}
+
+		for _, row := range rows {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self AssetInventoryDiffPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "asset_inventory_diff",
+		Doc:      "Show what changed between two versions of a client's asset inventory.",
+		ArgType:  type_map.AddType(scope, &AssetInventoryDiffPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type AssetInventorySearchPluginArgs struct {
+	Category string `vfilter:"optional,field=category,doc=Only search this category (default all)."`
+	Name     string `vfilter:"optional,field=name,doc=A regex matched against the item name."`
+	Op       string `vfilter:"optional,field=op,doc=Compare the item version with version (one of =, !=, <, <=, >, >=)."`
+	Version  string `vfilter:"optional,field=version,doc=The version to compare with."`
+	Limit    int    `vfilter:"optional,field=limit,doc=Maximum number of results (default 10000)."`
+}
+
+type AssetInventorySearchPlugin struct{}
+
+func (self AssetInventorySearchPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("asset_inventory_search: %v", err)
+			return
+		}
+
+		arg := &AssetInventorySearchPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("asset_inventory_search: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("asset_inventory_search: Command can only run on the server")
+			return
+		}
+
+		service, err := asset_inventory.GetAssetInventoryService(config_obj)
+		if err != nil {
+			scope.Log("asset_inventory_search: %v", err)
+			return
+		}
+
+		results, err := service.Search(ctx, &asset_inventory.SearchOptions{
+			Category: arg.Category,
+			Name:     arg.Name,
+			Op:       arg.Op,
+			Version:  arg.Version,
+			Limit:    arg.Limit,
+		})
+		if err != nil {
+			scope.Log("asset_inventory_search: %v", err)
+			return
+		}
+
+		for _, result := range results {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("ClientId", result.ClientId).
+				Set("Hostname", result.Hostname).
+				Set("Category", result.Category).
+				Set("Name", result.Name).
+				Set("Version", result.Version):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self AssetInventorySearchPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "asset_inventory_search",
+		Doc:      "Search the latest asset inventory of all clients.",
+		ArgType:  type_map.AddType(scope, &AssetInventorySearchPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type AssetInventoryRefreshFunctionArgs struct {
+	ClientId string `vfilter:"required,field=client_id,doc=The client to collect the inventory from."`
+}
+
+type AssetInventoryRefreshFunction struct{}
+
+func (self AssetInventoryRefreshFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("asset_inventory_refresh: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &AssetInventoryRefreshFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("asset_inventory_refresh: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("asset_inventory_refresh: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	service, err := asset_inventory.GetAssetInventoryService(config_obj)
+	if err != nil {
+		scope.Log("asset_inventory_refresh: %v", err)
+		return vfilter.Null{}
+	}
+
+	flow_id, err := service.Refresh(ctx,
+		vql_subsystem.GetPrincipal(scope), arg.ClientId)
+	if err != nil {
+		scope.Log("asset_inventory_refresh: %v", err)
+		return vfilter.Null{}
+	}
+
+	return flow_id
+}
+
+func (self AssetInventoryRefreshFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "asset_inventory_refresh",
+		Doc:      "Schedule a new asset inventory collection on a client.",
+		ArgType:  type_map.AddType(scope, &AssetInventoryRefreshFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.COLLECT_CLIENT).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&AssetInventoryPlugin{})
+	vql_subsystem.RegisterPlugin(&AssetInventoryDiffPlugin{})
+	vql_subsystem.RegisterPlugin(&AssetInventorySearchPlugin{})
+	vql_subsystem.RegisterFunction(&AssetInventoryRefreshFunction{})
+}
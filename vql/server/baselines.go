@@ -0,0 +1,244 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/baselines"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type BaselineSetFunctionArgs struct {
+	ClientId string   `vfilter:"required,field=client_id,doc=The client to baseline."`
+	Artifact string   `vfilter:"required,field=artifact,doc=The artifact source to baseline (e.g. Windows.Sys.Drivers/RunningDrivers)."`
+	FlowId   string   `vfilter:"optional,field=flow_id,doc=The flow to take the baseline from (default the latest collection of the artifact)."`
+	Key      []string `vfilter:"optional,field=key,doc=The columns which identify a row (default depends on the artifact)."`
+}
+
+type BaselineSetFunction struct{}
+
+func (self BaselineSetFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("baseline_set: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &BaselineSetFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("baseline_set: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("baseline_set: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	service, err := baselines.GetBaselineService(config_obj)
+	if err != nil {
+		scope.Log("baseline_set: %v", err)
+		return vfilter.Null{}
+	}
+
+	flow_id := arg.FlowId
+	if flow_id == "" {
+		flow_id, err = service.LatestFlow(ctx, arg.ClientId, arg.Artifact)
+		if err != nil {
+			scope.Log("baseline_set: %v", err)
+			return vfilter.Null{}
+		}
+	}
+
+	baseline, err := service.Set(ctx, vql_subsystem.GetPrincipal(scope),
+		arg.ClientId, arg.Artifact, flow_id, arg.Key)
+	if err != nil {
+		scope.Log("baseline_set: %v", err)
+		return vfilter.Null{}
+	}
+
+	return baselineRow(baseline)
+}
+
+func (self BaselineSetFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "baseline_set",
+		Doc:      "Record a collection as the client's baseline for an artifact.",
+		ArgType:  type_map.AddType(scope, &BaselineSetFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.COLLECT_CLIENT).Build(),
+	}
+}
+
+type BaselinesPluginArgs struct {
+	ClientId string `vfilter:"required,field=client_id,doc=The client to list baselines for."`
+}
+
+type BaselinesPlugin struct{}
+
+func (self BaselinesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("baselines: %v", err)
+			return
+		}
+
+		arg := &BaselinesPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("baselines: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("baselines: Command can only run on the server")
+			return
+		}
+
+		service, err := baselines.GetBaselineService(config_obj)
+		if err != nil {
+			scope.Log("baselines: %v", err)
+			return
+		}
+
+		items, err := service.List(arg.ClientId)
+		if err != nil {
+			scope.Log("baselines: %v", err)
+			return
+		}
+
+		for _, baseline := range items {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- baselineRow(baseline):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self BaselinesPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "baselines",
+		Doc:      "List the baselines recorded for a client.",
+		ArgType:  type_map.AddType(scope, &BaselinesPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type BaselineDriftPluginArgs struct {
+	ClientId string `vfilter:"required,field=client_id,doc=The client to compare."`
+	Artifact string `vfilter:"required,field=artifact,doc=The baselined artifact source."`
+	FlowId   string `vfilter:"optional,field=flow_id,doc=The flow to compare against the baseline (default the latest collection of the artifact)."`
+}
+
+type BaselineDriftPlugin struct{}
+
+func (self BaselineDriftPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("baseline_drift: %v", err)
+			return
+		}
+
+		arg := &BaselineDriftPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("baseline_drift: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("baseline_drift: Command can only run on the server")
+			return
+		}
+
+		service, err := baselines.GetBaselineService(config_obj)
+		if err != nil {
+			scope.Log("baseline_drift: %v", err)
+			return
+		}
+
+		flow_id, drift, err := service.Drift(ctx,
+			arg.ClientId, arg.Artifact, arg.FlowId)
+		if err != nil {
+			scope.Log("baseline_drift: %v", err)
+			return
+		}
+
+		for _, item := range drift {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Status", item.Status).
+				Set("Key", item.Key).
+				Set("Changed", item.Changed).
+				Set("Row", item.Row).
+				Set("Baseline", item.Baseline).
+				Set("FlowId", flow_id):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self BaselineDriftPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "baseline_drift",
+		Doc:      "Show the rows added, removed or changed since the client's baseline.",
+		ArgType:  type_map.AddType(scope, &BaselineDriftPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func baselineRow(baseline *baselines.Baseline) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("ClientId", baseline.ClientId).
+		Set("Artifact", baseline.Artifact).
+		Set("FlowId", baseline.FlowId).
+		Set("Key", baseline.Key).
+		Set("Creator", baseline.Creator).
+		Set("Created", unixTime(baseline.Created)).
+		Set("TotalRows", baseline.TotalRows)
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&BaselineSetFunction{})
+	vql_subsystem.RegisterPlugin(&BaselinesPlugin{})
+	vql_subsystem.RegisterPlugin(&BaselineDriftPlugin{})
+}
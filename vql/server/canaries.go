@@ -0,0 +1,344 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/canaries"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type CanaryCreateFunctionArgs struct {
+	Name        string `vfilter:"optional,field=name,doc=A name for the canary."`
+	Type        string `vfilter:"required,field=type,doc=The type of canary (file, registry or credential)."`
+	Path        string `vfilter:"required,field=path,doc=The file path or registry value path to plant the canary at."`
+	Value       string `vfilter:"optional,field=value,doc=The content of the canary (credential canaries receive generated fake credentials by default)."`
+	Description string `vfilter:"optional,field=description,doc=A description of the canary."`
+}
+
+type CanaryCreateFunction struct{}
+
+func (self CanaryCreateFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("canary_create: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &CanaryCreateFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("canary_create: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("canary_create: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	service, err := canaries.GetCanaryService(config_obj)
+	if err != nil {
+		scope.Log("canary_create: %v", err)
+		return vfilter.Null{}
+	}
+
+	canary, err := service.Create(vql_subsystem.GetPrincipal(scope),
+		&canaries.Canary{
+			Name:        arg.Name,
+			Type:        arg.Type,
+			Path:        arg.Path,
+			Value:       arg.Value,
+			Description: arg.Description,
+		})
+	if err != nil {
+		scope.Log("canary_create: %v", err)
+		return vfilter.Null{}
+	}
+
+	return canaryRow(canary)
+}
+
+func (self CanaryCreateFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "canary_create",
+		Doc:      "Create a new canary token.",
+		ArgType:  type_map.AddType(scope, &CanaryCreateFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.COLLECT_CLIENT).Build(),
+	}
+}
+
+type CanaryDeployFunctionArgs struct {
+	Id       string   `vfilter:"required,field=id,doc=The id of the canary."`
+	ClientId []string `vfilter:"required,field=client_id,doc=The clients to deploy the canary to."`
+}
+
+type CanaryDeployFunction struct{}
+
+func (self CanaryDeployFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	// Deploying writes files and runs programs on the clients.
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT,
+		acls.FILESYSTEM_WRITE, acls.EXECVE)
+	if err != nil {
+		scope.Log("canary_deploy: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &CanaryDeployFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("canary_deploy: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("canary_deploy: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	service, err := canaries.GetCanaryService(config_obj)
+	if err != nil {
+		scope.Log("canary_deploy: %v", err)
+		return vfilter.Null{}
+	}
+
+	flow_ids, err := service.Deploy(ctx, vql_subsystem.GetPrincipal(scope),
+		arg.Id, arg.ClientId)
+	if err != nil {
+		scope.Log("canary_deploy: %v", err)
+		return vfilter.Null{}
+	}
+
+	return flow_ids
+}
+
+func (self CanaryDeployFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "canary_deploy",
+		Doc:     "Deploy a canary token to clients. Returns the flow ids of the deployment collections.",
+		ArgType: type_map.AddType(scope, &CanaryDeployFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.COLLECT_CLIENT,
+			acls.FILESYSTEM_WRITE, acls.EXECVE).Build(),
+	}
+}
+
+type CanaryDeleteFunctionArgs struct {
+	Id     string `vfilter:"required,field=id,doc=The id of the canary."`
+	Remove bool   `vfilter:"optional,field=remove,doc=Also remove the canary from the clients it was deployed to."`
+}
+
+type CanaryDeleteFunction struct{}
+
+func (self CanaryDeleteFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("canary_delete: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &CanaryDeleteFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("canary_delete: %v", err)
+		return vfilter.Null{}
+	}
+
+	if arg.Remove {
+		err := vql_subsystem.CheckAccess(scope,
+			acls.FILESYSTEM_WRITE, acls.EXECVE)
+		if err != nil {
+			scope.Log("canary_delete: %v", err)
+			return vfilter.Null{}
+		}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("canary_delete: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	service, err := canaries.GetCanaryService(config_obj)
+	if err != nil {
+		scope.Log("canary_delete: %v", err)
+		return vfilter.Null{}
+	}
+
+	err = service.Delete(ctx, vql_subsystem.GetPrincipal(scope),
+		arg.Id, arg.Remove)
+	if err != nil {
+		scope.Log("canary_delete: %v", err)
+		return vfilter.Null{}
+	}
+
+	return arg.Id
+}
+
+func (self CanaryDeleteFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "canary_delete",
+		Doc:      "Delete a canary token and its hit history.",
+		ArgType:  type_map.AddType(scope, &CanaryDeleteFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.COLLECT_CLIENT).Build(),
+	}
+}
+
+type CanariesPlugin struct{}
+
+func (self CanariesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("canaries: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("canaries: Command can only run on the server")
+			return
+		}
+
+		service, err := canaries.GetCanaryService(config_obj)
+		if err != nil {
+			scope.Log("canaries: %v", err)
+			return
+		}
+
+		for _, canary := range service.List() {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- canaryRow(canary):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self CanariesPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "canaries",
+		Doc:      "List the canary tokens and their hit counts.",
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type CanaryHitsPluginArgs struct {
+	Id string `vfilter:"required,field=id,doc=The id of the canary."`
+}
+
+type CanaryHitsPlugin struct{}
+
+func (self CanaryHitsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("canary_hits: %v", err)
+			return
+		}
+
+		arg := &CanaryHitsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("canary_hits: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("canary_hits: Command can only run on the server")
+			return
+		}
+
+		service, err := canaries.GetCanaryService(config_obj)
+		if err != nil {
+			scope.Log("canary_hits: %v", err)
+			return
+		}
+
+		hits, err := service.Hits(ctx, arg.Id)
+		if err != nil {
+			scope.Log("canary_hits: %v", err)
+			return
+		}
+
+		for _, hit := range hits {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- hit:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self CanaryHitsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "canary_hits",
+		Doc:      "Show the recorded accesses to a canary token.",
+		ArgType:  type_map.AddType(scope, &CanaryHitsPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func canaryRow(canary *canaries.Canary) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Id", canary.Id).
+		Set("Name", canary.Name).
+		Set("Type", canary.Type).
+		Set("Path", canary.Path).
+		Set("Description", canary.Description).
+		Set("Clients", canary.Clients).
+		Set("Creator", canary.Creator).
+		Set("Created", unixTime(canary.Created)).
+		Set("Hits", canary.Hits).
+		Set("LastHit", unixTime(canary.LastHit))
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&CanaryCreateFunction{})
+	vql_subsystem.RegisterFunction(&CanaryDeployFunction{})
+	vql_subsystem.RegisterFunction(&CanaryDeleteFunction{})
+	vql_subsystem.RegisterPlugin(&CanariesPlugin{})
+	vql_subsystem.RegisterPlugin(&CanaryHitsPlugin{})
+}
@@ -0,0 +1,121 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services/availability"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type ClientAvailabilityPluginArgs struct {
+	Report  string `vfilter:"optional,field=report,doc=The report to produce: clients, not_seen or flapping (default clients)."`
+	Days    int64  `vfilter:"optional,field=days,doc=The report covers this many days (default 7, up to 30)."`
+	GroupBy string `vfilter:"optional,field=group_by,doc=Group clients by 'label' (default) or the name of a client metadata field (e.g. OU)."`
+	MinGaps int64  `vfilter:"optional,field=min_gaps,doc=Clients going offline at least this many times are flapping (default 3)."`
+}
+
+type ClientAvailabilityPlugin struct{}
+
+func (self ClientAvailabilityPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("client_availability: %v", err)
+			return
+		}
+
+		arg := &ClientAvailabilityPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("client_availability: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		service, err := availability.GetAvailabilityService(config_obj)
+		if err != nil {
+			scope.Log("client_availability: %v", err)
+			return
+		}
+
+		options := availability.ReportOptions{
+			Days:    arg.Days,
+			GroupBy: arg.GroupBy,
+			MinGaps: arg.MinGaps,
+		}
+
+		var rows interface{}
+		switch arg.Report {
+		case "", "clients":
+			rows, err = service.ClientReport(ctx, options)
+		case "not_seen":
+			rows, err = service.NotSeenReport(ctx, options)
+		case "flapping":
+			rows, err = service.FlappingReport(ctx, options)
+		default:
+			scope.Log("client_availability: Unknown report type %v", arg.Report)
+			return
+		}
+
+		if err != nil {
+			scope.Log("client_availability: %v", err)
+			return
+		}
+
+		// Convert the report to rows.
+		serialized, err := json.Marshal(rows)
+		if err != nil {
+			scope.Log("client_availability: %v", err)
+			return
+		}
+
+		items, err := utils.ParseJsonToDicts(serialized)
+		if err != nil {
+			scope.Log("client_availability: %v", err)
+			return
+		}
+
+		for _, item := range items {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- item:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self ClientAvailabilityPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "client_availability",
+		Doc:      "Report on client check in history and fleet availability.",
+		ArgType:  type_map.AddType(scope, &ClientAvailabilityPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&ClientAvailabilityPlugin{})
+}
@@ -0,0 +1,156 @@
+package clients
+
+import (
+	"context"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/interrogation"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/functions"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type ClientClockFunctionArgs struct {
+	ClientId string `vfilter:"required,field=client_id,doc=The client to check."`
+}
+
+type ClientClockFunction struct{}
+
+func (self ClientClockFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+	if err != nil {
+		scope.Log("client_clock: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &ClientClockFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("client_clock: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("client_clock: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	clock, err := interrogation.GetClientClock(config_obj, arg.ClientId)
+	if err != nil {
+		scope.Log("client_clock: %v: %v", arg.ClientId, err)
+		return vfilter.Null{}
+	}
+
+	return ordereddict.NewDict().
+		Set("ClientId", clock.ClientId).
+		Set("Timezone", clock.Timezone).
+		Set("UTCOffset", clock.UTCOffset).
+		Set("Skew", clock.Skew).
+		Set("ClientTime", clock.ClientTime).
+		Set("ServerTime", clock.ServerTime).
+		Set("FlowId", clock.FlowId)
+}
+
+func (self ClientClockFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "client_clock",
+		Doc:      "Show the time zone and clock skew reported by a client when it was last interrogated.",
+		ArgType:  type_map.AddType(scope, &ClientClockFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type NormalizeTimeFunctionArgs struct {
+	Time     vfilter.Any `vfilter:"required,field=time,doc=The timestamp to normalize."`
+	ClientId string      `vfilter:"optional,field=client_id,doc=Correct using the time zone and clock skew of this client."`
+	Skew     float64     `vfilter:"optional,field=skew,doc=Seconds the clock was ahead when the timestamp was taken (overrides the client's skew)."`
+	Timezone string      `vfilter:"optional,field=timezone,doc=The time zone of local timestamps (overrides the client's time zone)."`
+	Local    bool        `vfilter:"optional,field=local,doc=The timestamp is in local time without zone information."`
+}
+
+type NormalizeTimeFunction struct{}
+
+func (self NormalizeTimeFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	arg := &NormalizeTimeFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("normalize_time: %v", err)
+		return vfilter.Null{}
+	}
+
+	ts, err := functions.TimeFromAny(ctx, scope, arg.Time)
+	if err != nil {
+		scope.Log("normalize_time: %v", err)
+		return vfilter.Null{}
+	}
+
+	clock := &interrogation.ClientClock{}
+	if arg.ClientId != "" {
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("normalize_time: %v", err)
+			return vfilter.Null{}
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("normalize_time: Command can only run on the server")
+			return vfilter.Null{}
+		}
+
+		client_clock, err := interrogation.GetClientClock(
+			config_obj, arg.ClientId)
+		if err != nil {
+			scope.Log("normalize_time: %v: %v", arg.ClientId, err)
+		} else {
+			clock = client_clock
+		}
+	}
+
+	_, pres := args.Get("skew")
+	if pres {
+		clock.Skew = arg.Skew
+	}
+
+	if arg.Timezone != "" {
+		_, err := time.LoadLocation(arg.Timezone)
+		if err != nil {
+			scope.Log("normalize_time: %v", err)
+			return vfilter.Null{}
+		}
+		clock.Timezone = arg.Timezone
+	}
+
+	if arg.Local {
+		ts = clock.FromLocal(ts)
+	}
+
+	return clock.Normalize(ts)
+}
+
+func (self NormalizeTimeFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "normalize_time",
+		Doc:      "Correct a timestamp collected from a client for the client's clock skew and time zone.",
+		ArgType:  type_map.AddType(scope, &NormalizeTimeFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&ClientClockFunction{})
+	vql_subsystem.RegisterFunction(&NormalizeTimeFunction{})
+}
@@ -0,0 +1,135 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/server/connections"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type FrontendConnectionsPluginArgs struct {
+	ClientId string `vfilter:"optional,field=client_id,doc=Only show this client."`
+	All      bool   `vfilter:"optional,field=all,doc=Also show clients which disconnected recently."`
+}
+
+type FrontendConnectionsPlugin struct{}
+
+func (self FrontendConnectionsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("frontend_connections: %v", err)
+			return
+		}
+
+		arg := &FrontendConnectionsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("frontend_connections: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		node := services.GetNodeName(config_obj.Frontend)
+		for _, state := range connections.Tracker.List(arg.All) {
+			if arg.ClientId != "" && arg.ClientId != state.ClientId {
+				continue
+			}
+
+			// The frontend is shared between orgs - only the root
+			// org can see all the clients.
+			if !utils.IsRootOrg(config_obj.OrgId) &&
+				!utils.CompareOrgIds(config_obj.OrgId, state.OrgId) {
+				continue
+			}
+
+			row := vfilter.RowToDict(ctx, scope, state).Set("Node", node)
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self FrontendConnectionsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "frontend_connections",
+		Doc:      "List the clients currently connected to this frontend.",
+		ArgType:  type_map.AddType(scope, &FrontendConnectionsPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type FrontendStatusPlugin struct{}
+
+func (self FrontendStatusPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+		if err != nil {
+			scope.Log("frontend_status: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		row := vfilter.RowToDict(ctx, scope, connections.Tracker.Status()).
+			Set("Node", services.GetNodeName(config_obj.Frontend))
+
+		select {
+		case <-ctx.Done():
+		case output_chan <- row:
+		}
+	}()
+
+	return output_chan
+}
+
+func (self FrontendStatusPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "frontend_status",
+		Doc:      "Report the concurrency and bandwidth of client connections to this frontend.",
+		Metadata: vql.VQLMetadata().Permissions(acls.SERVER_ADMIN).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&FrontendConnectionsPlugin{})
+	vql_subsystem.RegisterPlugin(&FrontendStatusPlugin{})
+}
@@ -14,6 +14,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/legal_hold"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
@@ -62,6 +63,14 @@ func (self DeleteClientPlugin) Call(ctx context.Context,
 			return
 		}
 
+		if arg.ReallyDoIt {
+			err = legal_hold.CheckClient(config_obj, arg.ClientId)
+			if err != nil {
+				scope.Log("client_delete: %v", err)
+				return
+			}
+		}
+
 		db, err := datastore.GetDB(config_obj)
 		if err != nil {
 			return
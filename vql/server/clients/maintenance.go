@@ -0,0 +1,86 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type MaintenanceWindowFunctionArgs struct {
+	ClientId string `vfilter:"required,field=client_id,doc=The client to check."`
+}
+
+type MaintenanceWindowFunction struct{}
+
+func (self MaintenanceWindowFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+	if err != nil {
+		scope.Log("maintenance_window: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &MaintenanceWindowFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("maintenance_window: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("maintenance_window: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	windows, err := hunt_manager.GetClientMaintenanceWindows(
+		ctx, config_obj, arg.ClientId)
+	if err != nil {
+		scope.Log("maintenance_window: %v", err)
+		return vfilter.Null{}
+	}
+
+	specs := []string{}
+	for _, window := range windows {
+		specs = append(specs, window.String())
+	}
+
+	// Clients without windows may run hunts at any time.
+	now := utils.GetTime().Now()
+	result := ordereddict.NewDict().
+		Set("ClientId", arg.ClientId).
+		Set("Windows", specs).
+		Set("InWindow", len(windows) == 0 ||
+			hunt_manager.InMaintenanceWindow(windows, now)).
+		Set("NextWindow", vfilter.Null{})
+
+	if len(windows) > 0 {
+		start, _ := hunt_manager.NextMaintenanceWindow(windows, now)
+		result.Set("NextWindow", start.UTC())
+	}
+
+	return result
+}
+
+func (self MaintenanceWindowFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "maintenance_window",
+		Doc:      "Show the maintenance windows of a client and whether hunts may currently run on it.",
+		ArgType:  type_map.AddType(scope, &MaintenanceWindowFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&MaintenanceWindowFunction{})
+}
@@ -0,0 +1,194 @@
+package cloudlogs
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	azureUpnClaim   = "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn"
+	azureAppIdClaim = "appid"
+)
+
+type AzureActivityLogsArgs struct {
+	SASURL    string              `vfilter:"optional,field=sas_url,doc=A SAS URL for the storage container the activity log is exported to (needs List and Read permissions)."`
+	Prefix    string              `vfilter:"optional,field=prefix,doc=Only read blobs under this prefix (e.g. resourceId=/SUBSCRIPTIONS/<id>/y=2023/)."`
+	Filenames []*accessors.OSPath `vfilter:"optional,field=filename,doc=Exported log files to parse instead of reading from the container."`
+	Accessor  string              `vfilter:"optional,field=accessor,doc=The accessor to open the files with."`
+	StartTime vfilter.Any         `vfilter:"optional,field=start_time,doc=Only emit events after this time."`
+	EndTime   vfilter.Any         `vfilter:"optional,field=end_time,doc=Only emit events before this time."`
+}
+
+// The subscription is the second component of the resource id:
+// /SUBSCRIPTIONS/<id>/RESOURCEGROUPS/...
+func azureSubscription(resource_id string) string {
+	parts := strings.Split(strings.Trim(resource_id, "/"), "/")
+	if len(parts) >= 2 && strings.EqualFold(parts[0], "subscriptions") {
+		return parts[1]
+	}
+	return ""
+}
+
+func normalizeAzureActivity(record *ordereddict.Dict) *event {
+	operation := utils.GetPathString(record, "operationName")
+	if operation == "" {
+		return nil
+	}
+
+	resource_id := utils.GetPathString(record, "resourceId")
+	result := &event{
+		Time:      utils.GetPathTime(record, "time"),
+		Provider:  "azure",
+		Service:   strings.SplitN(operation, "/", 2)[0],
+		Operation: operation,
+		SourceIP:  utils.GetPathString(record, "callerIpAddress"),
+		Region:    utils.GetPathString(record, "location"),
+		Account:   azureSubscription(resource_id),
+		Status:    utils.GetPathString(record, "resultType"),
+		EventId:   utils.GetPathString(record, "correlationId"),
+	}
+
+	if resource_id != "" {
+		result.Resources = []string{resource_id}
+	}
+
+	upn := utils.GetPathString(record, "identity", "claims", azureUpnClaim)
+	app_id := utils.GetPathString(record, "identity", "claims", azureAppIdClaim)
+	switch {
+	case upn != "":
+		result.Principal = upn
+		result.PrincipalType = "User"
+	case app_id != "":
+		result.Principal = app_id
+		result.PrincipalType = "Application"
+	}
+
+	if result.Status == "Failure" {
+		result.Error = utils.GetPathString(record, "resultSignature")
+	}
+
+	return result
+}
+
+func azureLister(sas_url, prefix string) (objectLister, error) {
+	client, err := container.NewClientWithNoCredential(sas_url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, cb func(obj *logObject)) error {
+		options := &container.ListBlobsFlatOptions{}
+		if prefix != "" {
+			options.Prefix = &prefix
+		}
+
+		pager := client.NewListBlobsFlatPager(options)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return err
+			}
+
+			for _, item := range page.Segment.BlobItems {
+				if item.Name == nil {
+					continue
+				}
+
+				name := *item.Name
+				obj := &logObject{
+					Name: name,
+					Open: func(ctx context.Context) (io.ReadCloser, error) {
+						resp, err := client.NewBlobClient(name).DownloadStream(ctx, nil)
+						if err != nil {
+							return nil, err
+						}
+						return resp.Body, nil
+					},
+				}
+
+				if item.Properties != nil && item.Properties.LastModified != nil {
+					obj.Mtime = *item.Properties.LastModified
+				}
+
+				cb(obj)
+			}
+		}
+		return nil
+	}, nil
+}
+
+type AzureActivityLogsPlugin struct{}
+
+func (self AzureActivityLogsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "azure_activity_logs")
+
+		err := vql_subsystem.CheckAccess(scope, acls.FILESYSTEM_READ)
+		if err != nil {
+			scope.Log("azure_activity_logs: %v", err)
+			return
+		}
+
+		arg := &AzureActivityLogsArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("azure_activity_logs: %v", err)
+			return
+		}
+
+		common, err := getCommonArgs(ctx, scope, arg.Filenames, arg.Accessor,
+			arg.StartTime, arg.EndTime)
+		if err != nil {
+			scope.Log("azure_activity_logs: %v", err)
+			return
+		}
+
+		var lister objectLister
+		switch {
+		case len(arg.Filenames) > 0:
+			lister, err = fileLister(scope, common)
+		case arg.SASURL != "":
+			lister, err = azureLister(arg.SASURL, arg.Prefix)
+		default:
+			err = errNoSource
+		}
+		if err != nil {
+			scope.Log("azure_activity_logs: %v", err)
+			return
+		}
+
+		emitObjects(ctx, scope, "azure_activity_logs", common, lister,
+			normalizeAzureActivity, output_chan)
+	}()
+
+	return output_chan
+}
+
+func (self AzureActivityLogsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "azure_activity_logs",
+		Doc:      "Read Azure Activity Logs exported to a storage account or from files and normalize the events.",
+		ArgType:  type_map.AddType(scope, &AzureActivityLogsArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&AzureActivityLogsPlugin{})
+}
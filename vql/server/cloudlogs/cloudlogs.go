@@ -0,0 +1,302 @@
+/*
+  Plugins to ingest cloud control plane audit logs.
+
+  Each provider delivers its audit logs as JSON objects to a storage
+  bucket:
+
+  * AWS CloudTrail writes gzipped files with a Records array to S3.
+  * Azure Activity Logs exported to a storage account are written as
+    one record per line (the Event Hub export uses the same record
+    format wrapped in a records array).
+  * GCP log sinks write one LogEntry per line to GCS.
+
+  The plugins read either directly from the bucket or from files
+  opened with an accessor (e.g. previously downloaded exports) and
+  emit normalized rows so events from different providers can be
+  analyzed together.
+*/
+
+package cloudlogs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/functions"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	errNoSource = errors.New("either a bucket or filename must be given")
+)
+
+// A log object stored in a bucket.
+type logObject struct {
+	Name  string
+	Mtime time.Time
+	Open  func(ctx context.Context) (io.ReadCloser, error)
+}
+
+// Listers call the callback for each log object. Buckets may hold
+// many objects so they are streamed rather than listed up front.
+type objectLister func(ctx context.Context, cb func(obj *logObject)) error
+
+// The arguments all plugins share.
+type commonArgs struct {
+	Filenames []*accessors.OSPath
+	Accessor  string
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+func getCommonArgs(ctx context.Context, scope vfilter.Scope,
+	filenames []*accessors.OSPath, accessor string,
+	start_time, end_time vfilter.Any) (*commonArgs, error) {
+	result := &commonArgs{
+		Filenames: filenames,
+		Accessor:  accessor,
+	}
+
+	var err error
+	if !utils.IsNil(start_time) {
+		result.StartTime, err = functions.TimeFromAny(ctx, scope, start_time)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !utils.IsNil(end_time) {
+		result.EndTime, err = functions.TimeFromAny(ctx, scope, end_time)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Objects are only ever written after the events they contain so
+// older objects can be skipped without downloading them.
+func (self *commonArgs) skipObject(obj *logObject) bool {
+	return !self.StartTime.IsZero() && !obj.Mtime.IsZero() &&
+		obj.Mtime.Before(self.StartTime)
+}
+
+func (self *commonArgs) inRange(ts time.Time) bool {
+	if !self.StartTime.IsZero() && ts.Before(self.StartTime) {
+		return false
+	}
+	if !self.EndTime.IsZero() && ts.After(self.EndTime) {
+		return false
+	}
+	return true
+}
+
+// List the files given by the filename arg.
+func fileLister(scope vfilter.Scope, arg *commonArgs) (objectLister, error) {
+	err := vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	accessor, err := accessors.GetAccessor(arg.Accessor, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, cb func(obj *logObject)) error {
+		for _, filename := range arg.Filenames {
+			filename := filename
+			cb(&logObject{
+				Name: filename.String(),
+				Open: func(ctx context.Context) (io.ReadCloser, error) {
+					return accessor.OpenWithOSPath(filename)
+				},
+			})
+		}
+		return nil
+	}, nil
+}
+
+// Log objects may be gzip compressed (CloudTrail always is, the
+// others depend on how they were exported).
+func maybeDecompress(reader io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(reader)
+	magic, _ := buffered.Peek(2)
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		return gzip.NewReader(buffered)
+	}
+	return buffered, nil
+}
+
+// Parse all the records in a log object. An object may be a single
+// JSON document, a JSON array or one document per line. Documents
+// with a Records or records array (CloudTrail and Event Hub exports)
+// are expanded into their records.
+func parseRecords(ctx context.Context,
+	reader io.Reader, cb func(record *ordereddict.Dict)) error {
+	reader, err := maybeDecompress(reader)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var item json.RawMessage
+		err := decoder.Decode(&item)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = expandRecords(item, cb)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func expandRecords(item json.RawMessage, cb func(record *ordereddict.Dict)) error {
+	item = bytes.TrimSpace(item)
+	if len(item) == 0 {
+		return nil
+	}
+
+	switch item[0] {
+	case '[':
+		var items []json.RawMessage
+		err := json.Unmarshal(item, &items)
+		if err != nil {
+			return err
+		}
+		for _, i := range items {
+			err = expandRecords(i, cb)
+			if err != nil {
+				return err
+			}
+		}
+
+	case '{':
+		var container struct {
+			Records      []json.RawMessage `json:"Records"`
+			LowerRecords []json.RawMessage `json:"records"`
+		}
+		err := json.Unmarshal(item, &container)
+		if err == nil && (container.Records != nil || container.LowerRecords != nil) {
+			for _, i := range append(container.Records, container.LowerRecords...) {
+				err = expandRecords(i, cb)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		record := ordereddict.NewDict()
+		err = record.UnmarshalJSON(item)
+		if err != nil {
+			return err
+		}
+		cb(record)
+	}
+
+	return nil
+}
+
+// The normalized event emitted by all plugins.
+type event struct {
+	Time          time.Time
+	Provider      string
+	Service       string
+	Operation     string
+	Principal     string
+	PrincipalType string
+	SourceIP      string
+	UserAgent     string
+	Region        string
+	Account       string
+	Resources     []string
+	Status        string
+	Error         string
+	EventId       string
+}
+
+func (self *event) toRow(object string, raw *ordereddict.Dict) *ordereddict.Dict {
+	resources := self.Resources
+	if resources == nil {
+		resources = []string{}
+	}
+
+	return ordereddict.NewDict().
+		Set("Time", self.Time).
+		Set("Provider", self.Provider).
+		Set("Service", self.Service).
+		Set("Operation", self.Operation).
+		Set("Principal", self.Principal).
+		Set("PrincipalType", self.PrincipalType).
+		Set("SourceIP", self.SourceIP).
+		Set("UserAgent", self.UserAgent).
+		Set("Region", self.Region).
+		Set("Account", self.Account).
+		Set("Resources", resources).
+		Set("Status", self.Status).
+		Set("Error", self.Error).
+		Set("EventId", self.EventId).
+		Set("Object", object).
+		Set("Event", raw)
+}
+
+type normalizer func(record *ordereddict.Dict) *event
+
+// Parse all the objects and send the normalized rows.
+func emitObjects(ctx context.Context, scope vfilter.Scope,
+	name string, arg *commonArgs, lister objectLister,
+	normalize normalizer, output_chan chan vfilter.Row) {
+
+	err := lister(ctx, func(obj *logObject) {
+		if ctx.Err() != nil || arg.skipObject(obj) {
+			return
+		}
+
+		reader, err := obj.Open(ctx)
+		if err != nil {
+			scope.Log("%v: %v: %v", name, obj.Name, err)
+			return
+		}
+		defer reader.Close()
+
+		err = parseRecords(ctx, reader, func(record *ordereddict.Dict) {
+			e := normalize(record)
+			if e == nil || !arg.inRange(e.Time) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+			case output_chan <- e.toRow(obj.Name, record):
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			scope.Log("%v: %v: %v", name, obj.Name, err)
+		}
+	})
+	if err != nil && ctx.Err() == nil {
+		scope.Log("%v: %v", name, err)
+	}
+}
@@ -0,0 +1,136 @@
+package cloudlogs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+)
+
+const cloudTrailFixture = `{"Records":[{
+ "eventVersion":"1.08",
+ "userIdentity":{"type":"IAMUser","principalId":"AIDA1","arn":"arn:aws:iam::123456789012:user/alice","accountId":"123456789012"},
+ "eventTime":"2023-01-02T03:04:05Z",
+ "eventSource":"iam.amazonaws.com",
+ "eventName":"CreateAccessKey",
+ "awsRegion":"us-east-1",
+ "sourceIPAddress":"198.51.100.1",
+ "userAgent":"aws-cli/2.0",
+ "errorCode":"AccessDenied",
+ "errorMessage":"User is not authorized",
+ "eventID":"e1",
+ "resources":[{"ARN":"arn:aws:iam::123456789012:user/bob","type":"AWS::IAM::User"}],
+ "recipientAccountId":"123456789012"
+},{
+ "userIdentity":{"type":"AWSService","invokedBy":"ec2.amazonaws.com"},
+ "eventTime":"2023-01-03T00:00:00Z",
+ "eventSource":"sts.amazonaws.com",
+ "eventName":"AssumeRole",
+ "eventID":"e2"
+}]}`
+
+// Activity logs exported to storage are written one record per line.
+const azureFixture = `{"time":"2023-01-02T03:04:05.1234567Z","resourceId":"/SUBSCRIPTIONS/SUB-1/RESOURCEGROUPS/RG/PROVIDERS/MICROSOFT.COMPUTE/VIRTUALMACHINES/VM1","operationName":"MICROSOFT.COMPUTE/VIRTUALMACHINES/DELETE","category":"Administrative","resultType":"Failure","resultSignature":"Failed.Conflict","callerIpAddress":"203.0.113.5","correlationId":"c1","identity":{"claims":{"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn":"alice@example.com","appid":"app-1"}},"location":"westeurope"}
+{"records":[{"time":"2023-01-02T04:00:00Z","resourceId":"/SUBSCRIPTIONS/SUB-1","operationName":"Microsoft.Authorization/roleAssignments/write","resultType":"Success","identity":{"claims":{"appid":"app-2"}}}]}
+`
+
+const gcpFixture = `{"insertId":"i1","logName":"projects/p1/logs/cloudaudit.googleapis.com%2Factivity","protoPayload":{"@type":"type.googleapis.com/google.cloud.audit.AuditLog","serviceName":"compute.googleapis.com","methodName":"v1.compute.instances.delete","resourceName":"projects/p1/zones/us-central1-a/instances/vm1","authenticationInfo":{"principalEmail":"sa@p1.iam.gserviceaccount.com"},"requestMetadata":{"callerIp":"192.0.2.7","callerSuppliedUserAgent":"gcloud"},"status":{"code":7,"message":"PERMISSION_DENIED"}},"resource":{"type":"gce_instance","labels":{"project_id":"p1","zone":"us-central1-a"}},"timestamp":"2023-01-02T03:04:05.5Z"}
+{"insertId":"i2","textPayload":"not an audit log","timestamp":"2023-01-02T03:04:05Z"}
+`
+
+func parseFixture(t *testing.T, data []byte, normalize normalizer) []*event {
+	var result []*event
+	err := parseRecords(context.Background(), bytes.NewReader(data),
+		func(record *ordereddict.Dict) {
+			e := normalize(record)
+			if e != nil {
+				result = append(result, e)
+			}
+		})
+	assert.NoError(t, err)
+	return result
+}
+
+func TestCloudTrail(t *testing.T) {
+	// CloudTrail objects are always gzipped.
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	_, err := writer.Write([]byte(cloudTrailFixture))
+	assert.NoError(t, err)
+	writer.Close()
+
+	events := parseFixture(t, buf.Bytes(), normalizeCloudTrail)
+	assert.Equal(t, 2, len(events))
+
+	e := events[0]
+	assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), e.Time)
+	assert.Equal(t, "iam.amazonaws.com", e.Service)
+	assert.Equal(t, "CreateAccessKey", e.Operation)
+	assert.Equal(t, "arn:aws:iam::123456789012:user/alice", e.Principal)
+	assert.Equal(t, "IAMUser", e.PrincipalType)
+	assert.Equal(t, "198.51.100.1", e.SourceIP)
+	assert.Equal(t, "123456789012", e.Account)
+	assert.Equal(t, []string{"arn:aws:iam::123456789012:user/bob"}, e.Resources)
+	assert.Equal(t, "Failure", e.Status)
+	assert.Equal(t, "AccessDenied: User is not authorized", e.Error)
+
+	assert.Equal(t, "ec2.amazonaws.com", events[1].Principal)
+	assert.Equal(t, "Success", events[1].Status)
+}
+
+func TestAzureActivity(t *testing.T) {
+	events := parseFixture(t, []byte(azureFixture), normalizeAzureActivity)
+	assert.Equal(t, 2, len(events))
+
+	e := events[0]
+	assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 123456700, time.UTC), e.Time)
+	assert.Equal(t, "MICROSOFT.COMPUTE", e.Service)
+	assert.Equal(t, "alice@example.com", e.Principal)
+	assert.Equal(t, "User", e.PrincipalType)
+	assert.Equal(t, "SUB-1", e.Account)
+	assert.Equal(t, "westeurope", e.Region)
+	assert.Equal(t, "Failure", e.Status)
+	assert.Equal(t, "Failed.Conflict", e.Error)
+
+	// The second record was wrapped in a records array.
+	assert.Equal(t, "app-2", events[1].Principal)
+	assert.Equal(t, "Application", events[1].PrincipalType)
+	assert.Equal(t, "Success", events[1].Status)
+}
+
+func TestGCPAudit(t *testing.T) {
+	events := parseFixture(t, []byte(gcpFixture), normalizeGCPAudit)
+
+	// Entries without an audit payload are skipped.
+	assert.Equal(t, 1, len(events))
+
+	e := events[0]
+	assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 500000000, time.UTC), e.Time)
+	assert.Equal(t, "compute.googleapis.com", e.Service)
+	assert.Equal(t, "v1.compute.instances.delete", e.Operation)
+	assert.Equal(t, "ServiceAccount", e.PrincipalType)
+	assert.Equal(t, "gcloud", e.UserAgent)
+	assert.Equal(t, "p1", e.Account)
+	assert.Equal(t, "us-central1-a", e.Region)
+	assert.Equal(t, "Failure", e.Status)
+	assert.Equal(t, "PERMISSION_DENIED", e.Error)
+}
+
+func TestTimeRange(t *testing.T) {
+	arg := &commonArgs{
+		StartTime: time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC),
+	}
+
+	events := parseFixture(t, []byte(strings.TrimSpace(cloudTrailFixture)),
+		normalizeCloudTrail)
+	assert.False(t, arg.inRange(events[0].Time))
+	assert.True(t, arg.inRange(events[1].Time))
+
+	assert.True(t, arg.skipObject(&logObject{Mtime: events[0].Time}))
+	assert.False(t, arg.skipObject(&logObject{Mtime: events[1].Time}))
+}
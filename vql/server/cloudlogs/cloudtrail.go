@@ -0,0 +1,186 @@
+package cloudlogs
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	s3_accessor "www.velocidex.com/golang/velociraptor/accessors/s3"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type CloudTrailArgs struct {
+	Bucket    string              `vfilter:"optional,field=bucket,doc=The S3 bucket CloudTrail delivers to. Credentials are taken from the S3_CREDENTIALS scope variable."`
+	Prefix    string              `vfilter:"optional,field=prefix,doc=Only read objects under this prefix (e.g. AWSLogs/<account>/CloudTrail/<region>/2023/01/)."`
+	Filenames []*accessors.OSPath `vfilter:"optional,field=filename,doc=CloudTrail files to parse instead of reading from the bucket."`
+	Accessor  string              `vfilter:"optional,field=accessor,doc=The accessor to open the files with."`
+	StartTime vfilter.Any         `vfilter:"optional,field=start_time,doc=Only emit events after this time."`
+	EndTime   vfilter.Any         `vfilter:"optional,field=end_time,doc=Only emit events before this time."`
+}
+
+func normalizeCloudTrail(record *ordereddict.Dict) *event {
+	operation := utils.GetPathString(record, "eventName")
+	if operation == "" {
+		return nil
+	}
+
+	result := &event{
+		Time:          utils.GetPathTime(record, "eventTime"),
+		Provider:      "aws",
+		Service:       utils.GetPathString(record, "eventSource"),
+		Operation:     operation,
+		PrincipalType: utils.GetPathString(record, "userIdentity", "type"),
+		SourceIP:      utils.GetPathString(record, "sourceIPAddress"),
+		UserAgent:     utils.GetPathString(record, "userAgent"),
+		Region:        utils.GetPathString(record, "awsRegion"),
+		Account:       utils.GetPathString(record, "recipientAccountId"),
+		Status:        "Success",
+		EventId:       utils.GetPathString(record, "eventID"),
+	}
+
+	for _, field := range []string{"arn", "principalId", "invokedBy"} {
+		result.Principal = utils.GetPathString(record, "userIdentity", field)
+		if result.Principal != "" {
+			break
+		}
+	}
+
+	resources, _ := utils.GetPath(record, "resources")
+	items, _ := resources.([]interface{})
+	for _, item := range items {
+		dict, ok := item.(*ordereddict.Dict)
+		if ok {
+			arn := utils.GetPathString(dict, "ARN")
+			if arn != "" {
+				result.Resources = append(result.Resources, arn)
+			}
+		}
+	}
+
+	error_code := utils.GetPathString(record, "errorCode")
+	if error_code != "" {
+		result.Status = "Failure"
+		result.Error = error_code
+		message := utils.GetPathString(record, "errorMessage")
+		if message != "" {
+			result.Error += ": " + message
+		}
+	}
+
+	return result
+}
+
+func s3Lister(scope vfilter.Scope, bucket, prefix string) (objectLister, error) {
+	session, err := s3_accessor.GetS3Session(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := s3.New(session)
+	return func(ctx context.Context, cb func(obj *logObject)) error {
+		return svc.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+			Bucket: aws.String(bucket),
+			Prefix: aws.String(prefix),
+		}, func(page *s3.ListObjectsV2Output, last bool) bool {
+			for _, item := range page.Contents {
+				key := aws.StringValue(item.Key)
+
+				// Digest files only contain hashes of the log files.
+				if !strings.HasSuffix(key, ".json.gz") ||
+					strings.Contains(key, "/CloudTrail-Digest/") {
+					continue
+				}
+
+				cb(&logObject{
+					Name:  key,
+					Mtime: aws.TimeValue(item.LastModified),
+					Open: func(ctx context.Context) (io.ReadCloser, error) {
+						out, err := svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+							Bucket: aws.String(bucket),
+							Key:    aws.String(key),
+						})
+						if err != nil {
+							return nil, err
+						}
+						return out.Body, nil
+					},
+				})
+			}
+			return ctx.Err() == nil
+		})
+	}, nil
+}
+
+type CloudTrailPlugin struct{}
+
+func (self CloudTrailPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "cloudtrail_logs")
+
+		err := vql_subsystem.CheckAccess(scope, acls.FILESYSTEM_READ)
+		if err != nil {
+			scope.Log("cloudtrail_logs: %v", err)
+			return
+		}
+
+		arg := &CloudTrailArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("cloudtrail_logs: %v", err)
+			return
+		}
+
+		common, err := getCommonArgs(ctx, scope, arg.Filenames, arg.Accessor,
+			arg.StartTime, arg.EndTime)
+		if err != nil {
+			scope.Log("cloudtrail_logs: %v", err)
+			return
+		}
+
+		var lister objectLister
+		switch {
+		case len(arg.Filenames) > 0:
+			lister, err = fileLister(scope, common)
+		case arg.Bucket != "":
+			lister, err = s3Lister(scope, arg.Bucket, arg.Prefix)
+		default:
+			err = errNoSource
+		}
+		if err != nil {
+			scope.Log("cloudtrail_logs: %v", err)
+			return
+		}
+
+		emitObjects(ctx, scope, "cloudtrail_logs", common, lister,
+			normalizeCloudTrail, output_chan)
+	}()
+
+	return output_chan
+}
+
+func (self CloudTrailPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "cloudtrail_logs",
+		Doc:      "Read AWS CloudTrail logs from S3 or from files and normalize the events.",
+		ArgType:  type_map.AddType(scope, &CloudTrailArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&CloudTrailPlugin{})
+}
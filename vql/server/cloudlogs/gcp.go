@@ -0,0 +1,185 @@
+package cloudlogs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/Velocidex/ordereddict"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type GCPAuditLogsArgs struct {
+	Bucket      string              `vfilter:"optional,field=bucket,doc=The GCS bucket the log sink writes to."`
+	Prefix      string              `vfilter:"optional,field=prefix,doc=Only read objects under this prefix (e.g. cloudaudit.googleapis.com/activity/2023/)."`
+	Credentials string              `vfilter:"optional,field=credentials,doc=The service account credentials JSON to use (default application default credentials)."`
+	Filenames   []*accessors.OSPath `vfilter:"optional,field=filename,doc=Exported log files to parse instead of reading from the bucket."`
+	Accessor    string              `vfilter:"optional,field=accessor,doc=The accessor to open the files with."`
+	StartTime   vfilter.Any         `vfilter:"optional,field=start_time,doc=Only emit events after this time."`
+	EndTime     vfilter.Any         `vfilter:"optional,field=end_time,doc=Only emit events before this time."`
+}
+
+func normalizeGCPAudit(record *ordereddict.Dict) *event {
+	// Only audit log entries carry an AuditLog protoPayload.
+	operation := utils.GetPathString(record, "protoPayload", "methodName")
+	if operation == "" {
+		return nil
+	}
+
+	result := &event{
+		Time:      utils.GetPathTime(record, "timestamp"),
+		Provider:  "gcp",
+		Service:   utils.GetPathString(record, "protoPayload", "serviceName"),
+		Operation: operation,
+		Principal: utils.GetPathString(record, "protoPayload",
+			"authenticationInfo", "principalEmail"),
+		SourceIP: utils.GetPathString(record, "protoPayload",
+			"requestMetadata", "callerIp"),
+		UserAgent: utils.GetPathString(record, "protoPayload",
+			"requestMetadata", "callerSuppliedUserAgent"),
+		Account: utils.GetPathString(record, "resource", "labels", "project_id"),
+		Status:  "Success",
+		EventId: utils.GetPathString(record, "insertId"),
+	}
+
+	for _, field := range []string{"location", "zone", "region"} {
+		result.Region = utils.GetPathString(record, "resource", "labels", field)
+		if result.Region != "" {
+			break
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(result.Principal, ".gserviceaccount.com"):
+		result.PrincipalType = "ServiceAccount"
+	case result.Principal != "":
+		result.PrincipalType = "User"
+	}
+
+	resource_name := utils.GetPathString(record, "protoPayload", "resourceName")
+	if resource_name != "" {
+		result.Resources = []string{resource_name}
+	}
+
+	// A non zero google.rpc.Status code is a failure.
+	code, pres := utils.GetPath(record, "protoPayload", "status", "code")
+	if pres && fmt.Sprintf("%v", code) != "0" {
+		result.Status = "Failure"
+		result.Error = utils.GetPathString(record, "protoPayload", "status", "message")
+	}
+
+	return result
+}
+
+func gcsLister(ctx context.Context,
+	bucket, prefix, credentials string) (objectLister, error) {
+	var options []option.ClientOption
+	if credentials != "" {
+		options = append(options, option.WithCredentialsJSON([]byte(credentials)))
+	}
+
+	client, err := storage.NewClient(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	bucket_handle := client.Bucket(bucket)
+
+	return func(ctx context.Context, cb func(obj *logObject)) error {
+		defer client.Close()
+
+		it := bucket_handle.Objects(ctx, &storage.Query{Prefix: prefix})
+		for {
+			attrs, err := it.Next()
+			if err == iterator.Done {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			name := attrs.Name
+			cb(&logObject{
+				Name:  name,
+				Mtime: attrs.Updated,
+				Open: func(ctx context.Context) (io.ReadCloser, error) {
+					return bucket_handle.Object(name).NewReader(ctx)
+				},
+			})
+		}
+	}, nil
+}
+
+type GCPAuditLogsPlugin struct{}
+
+func (self GCPAuditLogsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "gcp_audit_logs")
+
+		err := vql_subsystem.CheckAccess(scope, acls.FILESYSTEM_READ)
+		if err != nil {
+			scope.Log("gcp_audit_logs: %v", err)
+			return
+		}
+
+		arg := &GCPAuditLogsArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("gcp_audit_logs: %v", err)
+			return
+		}
+
+		common, err := getCommonArgs(ctx, scope, arg.Filenames, arg.Accessor,
+			arg.StartTime, arg.EndTime)
+		if err != nil {
+			scope.Log("gcp_audit_logs: %v", err)
+			return
+		}
+
+		var lister objectLister
+		switch {
+		case len(arg.Filenames) > 0:
+			lister, err = fileLister(scope, common)
+		case arg.Bucket != "":
+			lister, err = gcsLister(ctx, arg.Bucket, arg.Prefix, arg.Credentials)
+		default:
+			err = errNoSource
+		}
+		if err != nil {
+			scope.Log("gcp_audit_logs: %v", err)
+			return
+		}
+
+		emitObjects(ctx, scope, "gcp_audit_logs", common, lister,
+			normalizeGCPAudit, output_chan)
+	}()
+
+	return output_chan
+}
+
+func (self GCPAuditLogsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "gcp_audit_logs",
+		Doc:      "Read GCP audit logs written by a log sink to GCS or from files and normalize the events.",
+		ArgType:  type_map.AddType(scope, &GCPAuditLogsArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&GCPAuditLogsPlugin{})
+}
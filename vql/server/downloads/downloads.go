@@ -16,6 +16,7 @@ import (
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/custody"
 	"www.velocidex.com/golang/velociraptor/file_store"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
@@ -26,6 +27,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/reporting"
 	"www.velocidex.com/golang/velociraptor/result_sets"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/export_jobs"
 	"www.velocidex.com/golang/velociraptor/uploads"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql"
@@ -38,16 +40,28 @@ var (
 	Clock utils.Clock = utils.RealClock{}
 )
 
+// Tracks the progress of an export job.
+type progressTracker interface {
+	AddTotal(count int64)
+	Increment()
+}
+
 type CreateFlowDownloadArgs struct {
-	ClientId     string `vfilter:"required,field=client_id,doc=Client ID to export."`
-	FlowId       string `vfilter:"required,field=flow_id,doc=The flow id to export."`
-	Wait         bool   `vfilter:"optional,field=wait,doc=If set we wait for the download to complete before returning."`
-	Type         string `vfilter:"optional,field=type,doc=Type of download to create (deprecated Ignored)."`
-	Template     string `vfilter:"optional,field=template,doc=Report template to use (deprecated Ignored)."`
-	Password     string `vfilter:"optional,field=password,doc=An optional password to encrypt the collection zip."`
-	Format       string `vfilter:"optional,field=format,doc=Format to export (csv,json,csv_only) defaults to both."`
-	ExpandSparse bool   `vfilter:"optional,field=expand_sparse,doc=If set we expand sparse files in the archive."`
-	Name         string `vfilter:"optional,field=name,doc=If specified we call the file this name otherwise we generate name based on flow id."`
+	ClientId     string   `vfilter:"required,field=client_id,doc=Client ID to export."`
+	FlowId       string   `vfilter:"required,field=flow_id,doc=The flow id to export."`
+	Wait         bool     `vfilter:"optional,field=wait,doc=If set we wait for the download to complete before returning."`
+	Type         string   `vfilter:"optional,field=type,doc=Type of download to create (deprecated Ignored)."`
+	Template     string   `vfilter:"optional,field=template,doc=Report template to use (deprecated Ignored)."`
+	Password     string   `vfilter:"optional,field=password,doc=An optional password to encrypt the collection zip."`
+	Encryption   string   `vfilter:"optional,field=encryption,doc=How to encrypt the export: zip (default), 7z or age."`
+	Recipients   []string `vfilter:"optional,field=recipients,doc=age public keys (age1...) to encrypt the export to instead of a password."`
+	Format       string   `vfilter:"optional,field=format,doc=Format to export (csv,json,csv_only) defaults to both."`
+	ExpandSparse bool     `vfilter:"optional,field=expand_sparse,doc=If set we expand sparse files in the archive."`
+	Columns      []string `vfilter:"optional,field=columns,doc=If set only export these columns of the results."`
+	DropColumns  []string `vfilter:"optional,field=drop_columns,doc=Columns to remove from the exported results."`
+	Where        string   `vfilter:"optional,field=where,doc=A VQL lambda (e.g. x=>x.Size > 10) selecting the result rows to export."`
+	Redact       []string `vfilter:"optional,field=redact,doc=Regular expressions to redact from all exported result values."`
+	Name         string   `vfilter:"optional,field=name,doc=If specified we call the file this name otherwise we generate name based on flow id."`
 }
 
 type CreateFlowDownload struct{}
@@ -81,17 +95,39 @@ func (self *CreateFlowDownload) Call(ctx context.Context,
 		return vfilter.Null{}
 	}
 
+	encryption, err := reporting.NewEncryptionOptions(
+		arg.Encryption, arg.Password, arg.Recipients)
+	if err != nil {
+		scope.Log("create_flow_download: %v", err)
+		return vfilter.Null{}
+	}
+
+	transform := &ExportTransform{
+		Columns:     arg.Columns,
+		DropColumns: arg.DropColumns,
+		Where:       arg.Where,
+		Redact:      arg.Redact,
+	}
+	filter, err := newExportFilter(transform)
+	if err != nil {
+		scope.Log("create_flow_download: %v", err)
+		return vfilter.Null{}
+	}
+
 	principal := vql_subsystem.GetPrincipal(scope)
 	services.LogAudit(ctx,
 		config_obj, principal, "create_flow_download",
 		ordereddict.NewDict().
 			Set("format", format).
 			Set("client_id", arg.ClientId).
-			Set("flow_id", arg.FlowId))
+			Set("flow_id", arg.FlowId).
+			Set("encryption", encryption.Method).
+			Set("recipients", arg.Recipients).
+			Set("transform", transform))
 
 	result, err := createDownloadFile(
 		ctx, scope, config_obj, format,
-		arg.FlowId, arg.ClientId, arg.Password,
+		arg.FlowId, arg.ClientId, encryption, filter,
 		arg.ExpandSparse, arg.Name, arg.Wait)
 	if err != nil {
 		scope.Log("create_flow_download: %s", err)
@@ -111,13 +147,19 @@ func (self CreateFlowDownload) Info(scope vfilter.Scope, type_map *vfilter.TypeM
 }
 
 type CreateHuntDownloadArgs struct {
-	HuntId       string `vfilter:"required,field=hunt_id,doc=Hunt ID to export."`
-	OnlyCombined bool   `vfilter:"optional,field=only_combined,doc=If set we only export combined results."`
-	Wait         bool   `vfilter:"optional,field=wait,doc=If set we wait for the download to complete before returning."`
-	Format       string `vfilter:"optional,field=format,doc=Format to export (csv,json) defaults to both."`
-	Filename     string `vfilter:"optional,field=base,doc=Base filename to write to."`
-	Password     string `vfilter:"optional,field=password,doc=An optional password to encrypt the collection zip."`
-	ExpandSparse bool   `vfilter:"optional,field=expand_sparse,doc=If set we expand sparse files in the archive."`
+	HuntId       string   `vfilter:"required,field=hunt_id,doc=Hunt ID to export."`
+	OnlyCombined bool     `vfilter:"optional,field=only_combined,doc=If set we only export combined results."`
+	Wait         bool     `vfilter:"optional,field=wait,doc=If set we wait for the download to complete before returning."`
+	Format       string   `vfilter:"optional,field=format,doc=Format to export (csv,json) defaults to both."`
+	Filename     string   `vfilter:"optional,field=base,doc=Base filename to write to."`
+	Password     string   `vfilter:"optional,field=password,doc=An optional password to encrypt the collection zip."`
+	Encryption   string   `vfilter:"optional,field=encryption,doc=How to encrypt the export: zip (default), 7z or age."`
+	Recipients   []string `vfilter:"optional,field=recipients,doc=age public keys (age1...) to encrypt the export to instead of a password."`
+	ExpandSparse bool     `vfilter:"optional,field=expand_sparse,doc=If set we expand sparse files in the archive."`
+	Columns      []string `vfilter:"optional,field=columns,doc=If set only export these columns of the results."`
+	DropColumns  []string `vfilter:"optional,field=drop_columns,doc=Columns to remove from the exported results."`
+	Where        string   `vfilter:"optional,field=where,doc=A VQL lambda (e.g. x=>x.Size > 10) selecting the result rows to export."`
+	Redact       []string `vfilter:"optional,field=redact,doc=Regular expressions to redact from all exported result values."`
 }
 
 type CreateHuntDownload struct{}
@@ -151,10 +193,28 @@ func (self *CreateHuntDownload) Call(ctx context.Context,
 		return vfilter.Null{}
 	}
 
+	encryption, err := reporting.NewEncryptionOptions(
+		arg.Encryption, arg.Password, arg.Recipients)
+	if err != nil {
+		scope.Log("create_hunt_download: %v", err)
+		return vfilter.Null{}
+	}
+
+	filter, err := newExportFilter(&ExportTransform{
+		Columns:     arg.Columns,
+		DropColumns: arg.DropColumns,
+		Where:       arg.Where,
+		Redact:      arg.Redact,
+	})
+	if err != nil {
+		scope.Log("create_hunt_download: %v", err)
+		return vfilter.Null{}
+	}
+
 	result, err := createHuntDownloadFile(
 		ctx, config_obj, scope, arg.HuntId,
 		format, arg.ExpandSparse,
-		arg.Wait, arg.OnlyCombined, arg.Filename, arg.Password)
+		arg.Wait, arg.OnlyCombined, arg.Filename, encryption, filter)
 	if err != nil {
 		scope.Log("create_hunt_download: %s", err)
 		return vfilter.Null{}
@@ -177,7 +237,9 @@ func createDownloadFile(
 	scope vfilter.Scope,
 	config_obj *config_proto.Config,
 	format reporting.ContainerFormat,
-	flow_id, client_id, password string,
+	flow_id, client_id string,
+	encryption *reporting.EncryptionOptions,
+	filter *exportFilter,
 	expand_sparse bool,
 	name string, wait bool) (api.FSPathSpec, error) {
 	if client_id == "" || flow_id == "" {
@@ -186,10 +248,12 @@ func createDownloadFile(
 
 	hostname := services.GetHostname(ctx, config_obj, client_id)
 	flow_path_manager := paths.NewFlowPathManager(client_id, flow_id)
-	download_file := flow_path_manager.GetDownloadsFile(hostname, password != "")
+	download_file := flow_path_manager.GetDownloadsFile(
+		hostname, encryption.IsEncrypted())
 	if name != "" {
 		download_file = flow_path_manager.GetDownloadsFileRawName(name)
 	}
+	download_file = download_file.SetType(encryption.PathType())
 
 	logger := logging.GetLogger(config_obj, &logging.GUIComponent)
 	logger.WithFields(logrus.Fields{
@@ -211,8 +275,8 @@ func createDownloadFile(
 
 	// Create a new ZipContainer to write on. The container will close
 	// the underlying writer.
-	zip_writer, err := reporting.NewContainerFromWriter(
-		config_obj, fd, password,
+	zip_writer, err := reporting.NewEncryptedContainerFromWriter(
+		config_obj, fd, encryption,
 		reporting.DEFAULT_COMPRESSION, reporting.NO_METADATA)
 	if err != nil {
 		return nil, err
@@ -222,8 +286,11 @@ func createDownloadFile(
 
 	// Report the progress as we write the container.
 	progress_reporter := reporting.NewProgressReporter(config_obj,
-		flow_path_manager.GetDownloadsStats(hostname, password != ""),
+		getStatsPath(flow_path_manager.GetDownloadsStats(
+			hostname, encryption.IsEncrypted()), download_file),
 		download_file, zip_writer)
+	progress_reporter.SetJob(export_jobs.NewJobId(),
+		vql_subsystem.GetPrincipal(scope))
 
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -248,10 +315,11 @@ func createDownloadFile(
 
 		err := downloadFlowToZip(ctx, scope, config_obj, format,
 			client_id, path_specs.NewUnsafeFilestorePath(),
-			flow_id, expand_sparse, zip_writer)
+			flow_id, expand_sparse, zip_writer, filter, progress_reporter)
 		if err != nil {
 			logger := logging.GetLogger(config_obj, &logging.GUIComponent)
 			logger.Error("downloadFlowToZip: %v", err)
+			progress_reporter.SetError(err)
 		}
 	}()
 
@@ -272,7 +340,9 @@ func downloadFlowToZip(
 	prefix api.FSPathSpec,
 	flow_id string,
 	expand_sparse bool,
-	zip_writer *reporting.Container) error {
+	zip_writer *reporting.Container,
+	filter *exportFilter,
+	progress progressTracker) error {
 
 	// Write the client info so it can be imported again
 	client_info_manager, err := services.GetClientInfoManager(config_obj)
@@ -312,6 +382,15 @@ func downloadFlowToZip(
 		}
 	}
 
+	// The log, each artifact's results and the uploads.
+	if progress != nil {
+		total := int64(2)
+		if flow_details != nil && flow_details.Context != nil {
+			total += int64(len(flow_details.Context.ArtifactsWithResults))
+		}
+		progress.AddTotal(total)
+	}
+
 	flow_requests, err := launcher.Storage().GetFlowRequests(
 		ctx, config_obj, client_id, flow_id, 0, 100)
 	if err == nil {
@@ -325,12 +404,16 @@ func downloadFlowToZip(
 
 	// Copy the collection logs
 	flow_path_manager := paths.NewFlowPathManager(client_id, flow_id)
-	err = copyResultSetIntoContainer(ctx, config_obj, zip_writer, format,
-		flow_path_manager.Log(), prefix.AddChild("log"))
+	err = copyResultSetIntoContainer(ctx, scope, config_obj, zip_writer,
+		format, nil, flow_path_manager.Log(), prefix.AddChild("log"))
 	if err != nil {
 		return err
 	}
 
+	if progress != nil {
+		progress.Increment()
+	}
+
 	// Copy artifact results
 	if flow_details != nil && flow_details.Context != nil {
 		for _, name := range flow_details.Context.ArtifactsWithResults {
@@ -340,11 +423,21 @@ func downloadFlowToZip(
 				continue
 			}
 
-			err = copyResultSetIntoContainer(ctx, config_obj, zip_writer, format,
+			artifact_filter, err := maskFilter(config_obj, scope, filter, name)
+			if err != nil {
+				return err
+			}
+
+			err = copyResultSetIntoContainer(ctx, scope, config_obj,
+				zip_writer, format, artifact_filter,
 				artifact_path_manager.Path(), prefix.AddChild("results", name))
 			if err != nil {
 				return err
 			}
+
+			if progress != nil {
+				progress.Increment()
+			}
 		}
 	}
 
@@ -354,6 +447,55 @@ func downloadFlowToZip(
 	if err != nil {
 		return err
 	}
+
+	if progress != nil {
+		progress.Increment()
+	}
+
+	return writeCustodyManifest(ctx, scope, config_obj, zip_writer,
+		prefix, client_id, flow_id)
+}
+
+// Write the signed chain of custody manifest for the collection.
+func writeCustodyManifest(
+	ctx context.Context,
+	scope vfilter.Scope,
+	config_obj *config_proto.Config,
+	zip_writer *reporting.Container,
+	prefix api.FSPathSpec,
+	client_id, flow_id string) error {
+	manifest, err := custody.NewManifest(ctx, config_obj, client_id, flow_id)
+	if err != nil {
+		// Imported collections may not have all the details.
+		scope.Log("downloadFlowToZip: Unable to build custody manifest: %v", err)
+		return nil
+	}
+
+	serialized, signature, err := manifest.Sign(config_obj)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range []struct {
+		name string
+		data []byte
+	}{
+		{"custody_manifest", serialized},
+		{"custody_signature", signature},
+	} {
+		out_fd, err := zip_writer.Create(paths.ZipPathFromFSPathSpec(
+			prefix.AddChild(item.name)), Clock.Now())
+		if err != nil {
+			return err
+		}
+
+		_, err = out_fd.Write(item.data)
+		out_fd.Close()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -625,9 +767,11 @@ func maybeExpandSparseFile(
 // CSV.
 func copyResultSetIntoContainer(
 	ctx context.Context,
+	scope vfilter.Scope,
 	config_obj *config_proto.Config,
 	container *reporting.Container,
 	format reporting.ContainerFormat,
+	filter *exportFilter,
 	src api.FSPathSpec,
 	dest api.FSPathSpec) (err error) {
 
@@ -648,7 +792,8 @@ func copyResultSetIntoContainer(
 		return
 	}
 
-	json.ConvertJSONL(buf_chan, json_writer, csv_writer, nil)
+	json.ConvertJSONL(filter.Apply(ctx, scope, buf_chan),
+		json_writer, csv_writer, nil)
 
 	return nil
 }
@@ -675,6 +820,17 @@ func getWriters(
 	return json_writer, csv_writer
 }
 
+// Stats paths do not carry the container's extension so other
+// container types get their own stats next to the zip stats.
+func getStatsPath(stats_path api.DSPathSpec,
+	download_file api.FSPathSpec) api.DSPathSpec {
+	if download_file.Type() == api.PATH_TYPE_FILESTORE_DOWNLOAD_ZIP {
+		return stats_path
+	}
+	return stats_path.Dir().AddUnsafeChild(
+		stats_path.Base() + api.GetExtensionForFilestore(download_file))
+}
+
 func maybeClose(fd io.WriteCloser) {
 	if fd != nil {
 		fd.Close()
@@ -689,7 +845,9 @@ func createHuntDownloadFile(
 	format reporting.ContainerFormat,
 	expand_sparse bool,
 	wait, only_combined bool,
-	base_filename, password string) (api.FSPathSpec, error) {
+	base_filename string,
+	encryption *reporting.EncryptionOptions,
+	filter *exportFilter) (api.FSPathSpec, error) {
 	if hunt_id == "" {
 		return nil, errors.New("Hunt Id should be specified.")
 	}
@@ -697,7 +855,8 @@ func createHuntDownloadFile(
 	logger := logging.GetLogger(config_obj, &logging.GUIComponent)
 	hunt_path_manager := paths.NewHuntPathManager(hunt_id)
 	download_file := hunt_path_manager.GetHuntDownloadsFile(
-		only_combined, base_filename, password != "")
+		only_combined, base_filename, encryption.IsEncrypted()).
+		SetType(encryption.PathType())
 
 	logger.WithFields(logrus.Fields{
 		"hunt_id":       hunt_id,
@@ -732,8 +891,8 @@ func createHuntDownloadFile(
 
 	// Do these first to ensure errors are returned if the zip file
 	// is not writable.
-	zip_writer, err := reporting.NewContainerFromWriter(
-		config_obj, fd, password, 5, nil /* metadata */)
+	zip_writer, err := reporting.NewEncryptedContainerFromWriter(
+		config_obj, fd, encryption, 5, nil /* metadata */)
 	if err != nil {
 		fd.Close()
 		return nil, err
@@ -743,9 +902,17 @@ func createHuntDownloadFile(
 
 	// Report the progress as we write the container.
 	progress_reporter := reporting.NewProgressReporter(config_obj,
-		hunt_path_manager.GetHuntDownloadsStats(only_combined,
-			base_filename, password != ""),
+		getStatsPath(hunt_path_manager.GetHuntDownloadsStats(only_combined,
+			base_filename, encryption.IsEncrypted()), download_file),
 		download_file, zip_writer)
+	progress_reporter.SetJob(export_jobs.NewJobId(),
+		vql_subsystem.GetPrincipal(scope))
+
+	// The combined results and each of the hunt's collections.
+	progress_reporter.AddTotal(1)
+	if !only_combined && hunt_details.Stats != nil {
+		progress_reporter.AddTotal(int64(hunt_details.Stats.TotalClientsScheduled))
+	}
 
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -772,16 +939,19 @@ func createHuntDownloadFile(
 			paths.ZipPathFromFSPathSpec(path_specs.NewUnsafeFilestorePath().AddChild("hunt_info")),
 			hunt_details)
 		if err != nil {
+			progress_reporter.SetError(err)
 			return
 		}
 
 		err = generateCombinedResults(
 			sub_ctx, config_obj, scope,
-			hunt_details, format, zip_writer)
+			hunt_details, format, filter, zip_writer)
 		if err != nil {
 			logger.Error("createHuntDownloadFile: %v", err)
+			progress_reporter.SetError(err)
 			return
 		}
+		progress_reporter.Increment()
 
 		// If the user only asked for combined results do not
 		// export specific flow.
@@ -808,7 +978,8 @@ func createHuntDownloadFile(
 			err := downloadFlowToZip(
 				sub_ctx, scope, config_obj, format, client_id,
 				path_specs.NewUnsafeFilestorePath(hostname),
-				flow_id, expand_sparse, zip_writer)
+				flow_id, expand_sparse, zip_writer, filter, nil)
+			progress_reporter.Increment()
 			if err != nil {
 				logging.GetLogger(config_obj, &logging.FrontendComponent).
 					WithFields(logrus.Fields{
@@ -834,6 +1005,7 @@ func generateCombinedResults(
 	scope vfilter.Scope,
 	hunt_details *api_proto.Hunt,
 	format reporting.ContainerFormat,
+	filter *exportFilter,
 	zip_writer *reporting.Container) error {
 
 	file_store_factory := file_store.GetFileStore(config_obj)
@@ -849,6 +1021,12 @@ func generateCombinedResults(
 
 	// Export aggregate CSV and JSON files for all clients.
 	for _, artifact_source := range hunt_details.ArtifactSources {
+		artifact_filter, err := maskFilter(
+			config_obj, scope, filter, artifact_source)
+		if err != nil {
+			return err
+		}
+
 		// Figure out where to write it
 		path_manager := path_specs.NewUnsafeFilestorePath(
 			"results", "All "+artifact_source)
@@ -890,7 +1068,8 @@ func generateCombinedResults(
 				fqdn = api_client.OsInfo.Fqdn
 			}
 
-			json.ConvertJSONL(buf_chan, json_writer, csv_writer,
+			json.ConvertJSONL(artifact_filter.Apply(ctx, scope, buf_chan),
+				json_writer, csv_writer,
 				ordereddict.NewDict().
 					Set("FlowId", flow_id).
 					Set("ClientId", client_id).
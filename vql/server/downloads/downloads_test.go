@@ -23,6 +23,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/reporting"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/export_jobs"
 	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
 	"www.velocidex.com/golang/velociraptor/third_party/zip"
 	"www.velocidex.com/golang/velociraptor/utils"
@@ -142,6 +143,153 @@ func (self *TestSuite) TestExportCollectionServerArtifact() {
 	// Make sure the collection is
 	goldie.Assert(self.T(), "TestExportCollectionServerArtifact",
 		json.MustMarshalIndent(file_details))
+
+	// The export is tracked as an export job.
+	jobs, err := export_jobs.List(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(jobs))
+	assert.Equal(self.T(), export_jobs.STATE_FINISHED, jobs[0].State)
+	assert.Equal(self.T(), uint64(100), jobs[0].Progress)
+	assert.Equal(self.T(), path_specs.AsGenericComponentList(path_spec),
+		jobs[0].Components)
+}
+
+// Collect TestArtifact on the server and wait for it to finish.
+func (self *TestSuite) collectTestArtifact() (string, vfilter.Scope) {
+	manager, _ := services.GetRepositoryManager(self.ConfigObj)
+	repository, err := manager.GetGlobalRepository(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	var acl_manager vql_subsystem.ACLManager
+
+	flow_id, err := launcher.ScheduleArtifactCollection(self.Ctx, self.ConfigObj,
+		acl_manager, repository, &flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{"TestArtifact"},
+			ClientId:  "server",
+		}, utils.SyncCompleter)
+	assert.NoError(self.T(), err)
+
+	vtesting.WaitUntil(time.Second*5, self.T(), func() bool {
+		flow, err := launcher.GetFlowDetails(self.Ctx, self.ConfigObj, "server", flow_id)
+		assert.NoError(self.T(), err)
+
+		return flow.Context.State == flows_proto.ArtifactCollectorContext_FINISHED
+	})
+
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     self.ConfigObj,
+		ACLManager: acl_managers.NullACLManager{},
+		Logger:     logging.NewPlainLogger(self.ConfigObj, &logging.FrontendComponent),
+		Env:        ordereddict.NewDict(),
+	})
+
+	return flow_id, scope
+}
+
+func (self *TestSuite) TestExportCollectionEncrypted() {
+	flow_id, scope := self.collectTestArtifact()
+	defer scope.Close()
+
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+
+	for _, tc := range []struct {
+		args      *ordereddict.Dict
+		path_type api.PathType
+		magic     string
+	}{
+		{ordereddict.NewDict().
+			Set("encryption", "7z").
+			Set("password", "hunter2"),
+			api.PATH_TYPE_FILESTORE_DOWNLOAD_7Z, "7z\xbc\xaf\x27\x1c"},
+		{ordereddict.NewDict().
+			Set("recipients", []string{
+				"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"}),
+			api.PATH_TYPE_FILESTORE_DOWNLOAD_AGE, "age-encryption.org/v1\n"},
+	} {
+		result := (&CreateFlowDownload{}).Call(self.Ctx, scope,
+			tc.args.Set("client_id", "server").
+				Set("flow_id", flow_id).
+				Set("wait", true))
+
+		path_spec, ok := result.(path_specs.FSPathSpec)
+		assert.True(self.T(), ok)
+		assert.Equal(self.T(), tc.path_type, path_spec.Type())
+
+		fd, err := file_store_factory.ReadFile(path_spec)
+		assert.NoError(self.T(), err)
+
+		data, err := ioutil.ReadAll(fd)
+		assert.NoError(self.T(), err)
+		fd.Close()
+
+		assert.True(self.T(), strings.HasPrefix(string(data), tc.magic))
+	}
+
+	// Recipients are only supported by age.
+	result := (&CreateFlowDownload{}).Call(self.Ctx, scope,
+		ordereddict.NewDict().
+			Set("client_id", "server").
+			Set("flow_id", flow_id).
+			Set("encryption", "zip").
+			Set("recipients", []string{"age1xyz"}))
+	assert.Equal(self.T(), vfilter.Null{}, result)
+}
+
+func (self *TestSuite) TestExportCollectionTransform() {
+	flow_id, scope := self.collectTestArtifact()
+	defer scope.Close()
+
+	result := (&CreateFlowDownload{}).Call(self.Ctx, scope,
+		ordereddict.NewDict().
+			Set("client_id", "server").
+			Set("flow_id", flow_id).
+			Set("wait", true).
+			Set("columns", []string{"Col", "OSPath", "Upload1"}).
+			Set("drop_columns", []string{"Upload1"}).
+			Set("where", "x=>x.Col = 'Hello'").
+			Set("redact", []string{"ell", "^/bin"}))
+
+	path_spec, ok := result.(path_specs.FSPathSpec)
+	assert.True(self.T(), ok)
+
+	file_details, err := openZipFile(self.ConfigObj, scope, path_spec)
+	assert.NoError(self.T(), err)
+
+	rows, _ := file_details.Get("results/TestArtifact.json")
+	assert.Equal(self.T(), `[{"Col":"H[REDACTED]o","OSPath":"[REDACTED]/ls"}]`,
+		json.MustMarshalString(rows))
+
+	// Logs are not transformed.
+	logs, _ := file_details.Get("log.json")
+	assert.True(self.T(), len(json.MustMarshalString(logs)) > 100)
+
+	// Filter all the rows out.
+	result = (&CreateFlowDownload{}).Call(self.Ctx, scope,
+		ordereddict.NewDict().
+			Set("client_id", "server").
+			Set("flow_id", flow_id).
+			Set("wait", true).
+			Set("where", "x=>x.Col = 'Goodbye'"))
+
+	path_spec, ok = result.(path_specs.FSPathSpec)
+	assert.True(self.T(), ok)
+
+	file_details, err = openZipFile(self.ConfigObj, scope, path_spec)
+	assert.NoError(self.T(), err)
+
+	rows, _ = file_details.Get("results/TestArtifact.json")
+	assert.Equal(self.T(), "null", json.MustMarshalString(rows))
+
+	// Invalid transforms are rejected.
+	result = (&CreateFlowDownload{}).Call(self.Ctx, scope,
+		ordereddict.NewDict().
+			Set("client_id", "server").
+			Set("flow_id", flow_id).
+			Set("redact", []string{"(invalid"}))
+	assert.Equal(self.T(), vfilter.Null{}, result)
 }
 
 // First import a collection from a zip file to create a
@@ -0,0 +1,196 @@
+package downloads
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/api/tables"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// Transformations applied to the results as they are exported so
+// only the relevant subset of the data leaves the server.
+type ExportTransform struct {
+	// If set only these columns are exported.
+	Columns []string
+
+	// These columns are removed from the export.
+	DropColumns []string
+
+	// A VQL lambda (e.g. x=>x.Size > 10) selecting the rows to
+	// export.
+	Where string
+
+	// Regular expressions redacted from all values.
+	Redact []string
+}
+
+type exportFilter struct {
+	columns  []string
+	drop     map[string]bool
+	where    *vfilter.Lambda
+	redactor *tables.Redactor
+
+	// Applies the masking policies for users without the VIEW_PII
+	// permission.
+	mask *tables.Redactor
+}
+
+// Returns nil if the transform does not change the results, so the
+// results can be copied without parsing them.
+func newExportFilter(transform *ExportTransform) (*exportFilter, error) {
+	if transform == nil ||
+		len(transform.Columns) == 0 && len(transform.DropColumns) == 0 &&
+			transform.Where == "" && len(transform.Redact) == 0 {
+		return nil, nil
+	}
+
+	result := &exportFilter{
+		columns: transform.Columns,
+		drop:    make(map[string]bool),
+	}
+
+	for _, c := range transform.DropColumns {
+		result.drop[c] = true
+	}
+
+	if transform.Where != "" {
+		lambda, err := vfilter.ParseLambda(transform.Where)
+		if err != nil {
+			return nil, err
+		}
+		result.where = lambda
+	}
+
+	if len(transform.Redact) > 0 {
+		rules := []*config_proto.RedactionRule{}
+		for _, pattern := range transform.Redact {
+			rules = append(rules, &config_proto.RedactionRule{
+				Pattern: pattern,
+			})
+		}
+
+		redactor, err := tables.NewRedactorFromRules(rules)
+		if err != nil {
+			return nil, err
+		}
+		result.redactor = redactor
+	}
+
+	return result, nil
+}
+
+// Add the masking policies for the artifact's results if the user
+// may not view PII. The filter is shared by all the artifacts in the
+// export so a copy is returned.
+func maskFilter(config_obj *config_proto.Config, scope vfilter.Scope,
+	filter *exportFilter, artifact string) (*exportFilter, error) {
+	mask, err := tables.GetRedactor(config_obj,
+		vql_subsystem.GetPrincipal(scope), artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	if mask == nil {
+		return filter, nil
+	}
+
+	result := &exportFilter{drop: make(map[string]bool)}
+	if filter != nil {
+		filter_copy := *filter
+		result = &filter_copy
+	}
+	result.mask = mask
+
+	return result, nil
+}
+
+// Filter a stream of JSONL rows.
+func (self *exportFilter) Apply(
+	ctx context.Context, scope vfilter.Scope,
+	in <-chan []byte) <-chan []byte {
+	if self == nil {
+		return in
+	}
+
+	output := make(chan []byte)
+	go func() {
+		defer close(output)
+
+		for serialized := range in {
+			row, err := utils.ParseJsonToObject(serialized)
+			if err != nil {
+				continue
+			}
+
+			row, ok := self.transformRow(ctx, scope, row)
+			if !ok {
+				continue
+			}
+
+			serialized, err = json.Marshal(row)
+			if err != nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				// Drain the input so the reader can exit.
+				for range in {
+				}
+				return
+			case output <- serialized:
+			}
+		}
+	}()
+
+	return output
+}
+
+func (self *exportFilter) transformRow(
+	ctx context.Context, scope vfilter.Scope,
+	row *ordereddict.Dict) (*ordereddict.Dict, bool) {
+
+	// The filter sees the original row so it may refer to columns
+	// which are not exported.
+	if self.where != nil &&
+		!scope.Bool(self.where.Reduce(ctx, scope, []vfilter.Any{row})) {
+		return nil, false
+	}
+
+	if len(self.columns) > 0 {
+		selected := ordereddict.NewDict()
+		for _, c := range self.columns {
+			value, pres := row.Get(c)
+			if pres {
+				selected.Set(c, value)
+			}
+		}
+		row = selected
+	}
+
+	if len(self.drop) > 0 {
+		remaining := ordereddict.NewDict()
+		for _, k := range row.Keys() {
+			if !self.drop[k] {
+				value, _ := row.Get(k)
+				remaining.Set(k, value)
+			}
+		}
+		row = remaining
+	}
+
+	if self.redactor != nil {
+		row = self.redactor.RedactRow(row)
+	}
+
+	if self.mask != nil {
+		row = self.mask.RedactRow(row)
+	}
+
+	return row, true
+}
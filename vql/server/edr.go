@@ -0,0 +1,79 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/edr"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type EDRConnectorsPlugin struct{}
+
+func (self EDRConnectorsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("edr_connectors: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("edr_connectors: Command can only run on the server")
+			return
+		}
+
+		for _, connector := range config_obj.GetDefaults().GetEdrConnectors() {
+			state, err := edr.LoadState(config_obj, connector.Name)
+			if err != nil {
+				scope.Log("edr_connectors: %v", err)
+				return
+			}
+
+			var cursor vfilter.Any = vfilter.Null{}
+			if !state.Cursor.IsZero() {
+				cursor = state.Cursor
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Name", connector.Name).
+				Set("Type", connector.Type).
+				Set("LastPoll", unixTime(state.LastPoll)).
+				Set("LastSuccess", unixTime(state.LastSuccess)).
+				Set("LastError", state.LastError).
+				Set("Cursor", cursor).
+				Set("Total", state.Total):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self EDRConnectorsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "edr_connectors",
+		Doc:      "Show the polling state of the EDR alert connectors.",
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&EDRConnectorsPlugin{})
+}
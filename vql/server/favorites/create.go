@@ -4,11 +4,13 @@ import (
 	"context"
 
 	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
 	"www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/vfilter"
 	"www.velocidex.com/golang/vfilter/arg_parser"
@@ -19,6 +21,8 @@ type AddFavoriteArgs struct {
 	Description string           `vfilter:"optional,field=description,doc=A description for the template."`
 	Specs       vfilter.LazyExpr `vfilter:"required,field=specs,doc=The collection request spec that will be saved. We use this to create the new collection."`
 	Type        string           `vfilter:"required,field=type,doc=The type of favorite."`
+	Resources   vfilter.Any      `vfilter:"optional,field=resources,doc=Resource limits to apply when the favorite is launched (e.g. timeout, max_rows, max_upload_bytes, cpu_limit)."`
+	Shared      bool             `vfilter:"optional,field=shared,doc=If set the favorite is shared with all users in the org."`
 }
 
 type AddFavorite struct{}
@@ -62,15 +66,43 @@ func (self *AddFavorite) Call(ctx context.Context,
 		return vfilter.Null{}
 	}
 
-	path_manager := paths.NewUserPathManager(principal)
 	fav := &api_proto.Favorite{
 		Name:        arg.Name,
 		Description: arg.Description,
 		Spec:        specs,
 		Type:        arg.Type,
+		Shared:      arg.Shared,
+		Creator:     principal,
 	}
-	err = db.SetSubject(config_obj,
-		path_manager.Favorites(arg.Name, arg.Type), fav)
+
+	if !utils.IsNil(arg.Resources) {
+		fav.Resources, err = validateResources(arg.Resources)
+		if err != nil {
+			scope.Log("favorites_save: %s", err)
+			return vfilter.Null{}
+		}
+	}
+
+	path := paths.NewUserPathManager(principal).Favorites(arg.Name, arg.Type)
+	if arg.Shared {
+		// Other users can launch shared favorites so sharing
+		// requires the permission to collect.
+		err = vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+		if err != nil {
+			scope.Log("favorites_save: %s", err)
+			return vfilter.Null{}
+		}
+
+		err = checkSharedFavoriteOwner(
+			config_obj, db, principal, arg.Name, arg.Type)
+		if err != nil {
+			scope.Log("favorites_save: %s", err)
+			return vfilter.Null{}
+		}
+		path = paths.SharedFavorite(arg.Name, arg.Type)
+	}
+
+	err = db.SetSubject(config_obj, path, fav)
 	if err != nil {
 		scope.Log("favorites_save: %s", err)
 		return vfilter.Null{}
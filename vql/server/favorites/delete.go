@@ -12,8 +12,9 @@ import (
 )
 
 type RmFavoriteArgs struct {
-	Name string `vfilter:"required,field=name,doc=A name for this collection template."`
-	Type string `vfilter:"required,field=type,doc=The type of favorite."`
+	Name   string `vfilter:"required,field=name,doc=A name for this collection template."`
+	Type   string `vfilter:"required,field=type,doc=The type of favorite."`
+	Shared bool   `vfilter:"optional,field=shared,doc=Delete the favorite shared with the org instead of the user's own."`
 }
 
 type RmFavorite struct{}
@@ -49,9 +50,18 @@ func (self *RmFavorite) Call(ctx context.Context,
 		return vfilter.Null{}
 	}
 
-	path_manager := paths.NewUserPathManager(principal)
-	err = db.DeleteSubject(config_obj,
-		path_manager.Favorites(arg.Name, arg.Type))
+	path := paths.NewUserPathManager(principal).Favorites(arg.Name, arg.Type)
+	if arg.Shared {
+		err = checkSharedFavoriteOwner(
+			config_obj, db, principal, arg.Name, arg.Type)
+		if err != nil {
+			scope.Log("favorites_delete: %s", err)
+			return vfilter.Null{}
+		}
+		path = paths.SharedFavorite(arg.Name, arg.Type)
+	}
+
+	err = db.DeleteSubject(config_obj, path)
 	if err != nil {
 		scope.Log("favorites_delete: %s", err)
 		return vfilter.Null{}
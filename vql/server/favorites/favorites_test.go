@@ -0,0 +1,104 @@
+package favorites_test
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vql/server/favorites"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type FavoritesTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *FavoritesTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	for user, role := range map[string]string{
+		"alice": "investigator",
+		"bob":   "investigator",
+		"admin": "administrator",
+	} {
+		err := services.GrantRoles(self.ConfigObj, user, []string{role})
+		assert.NoError(self.T(), err)
+	}
+}
+
+func (self *FavoritesTestSuite) scopeForUser(principal string) vfilter.Scope {
+	manager, _ := services.GetRepositoryManager(self.ConfigObj)
+	return manager.BuildScope(services.ScopeBuilder{
+		Config:     self.ConfigObj,
+		ACLManager: acl_managers.NewServerACLManager(self.ConfigObj, principal),
+		Logger: logging.NewPlainLogger(self.ConfigObj,
+			&logging.FrontendComponent),
+		Env: ordereddict.NewDict(),
+	})
+}
+
+func (self *FavoritesTestSuite) save(principal, name string, shared bool) {
+	scope := self.scopeForUser(principal)
+	defer scope.Close()
+
+	(&favorites.AddFavorite{}).Call(self.Ctx, scope, ordereddict.NewDict().
+		Set("name", name).
+		Set("description", "Saved by "+principal).
+		Set("specs", `[{"artifact": "Generic.Client.Info"}]`).
+		Set("type", "CLIENT").
+		Set("resources", ordereddict.NewDict().
+			Set("timeout", 600).
+			Set("max_rows", "1000").
+			Set("artifacts", []string{"Ignored"})).
+		Set("shared", shared))
+}
+
+func (self *FavoritesTestSuite) TestSharedFavorites() {
+	self.save("alice", "Triage", true)
+	self.save("alice", "Private", false)
+
+	// Bob sees the shared favorite but not alice's private one.
+	fav, err := favorites.GetFavorite(
+		self.Ctx, self.ConfigObj, "bob", "Triage", "CLIENT")
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), fav.Shared)
+	assert.Equal(self.T(), "alice", fav.Creator)
+	assert.Equal(self.T(), uint64(600), fav.Resources.Timeout)
+	assert.Equal(self.T(), uint64(1000), fav.Resources.MaxRows)
+	assert.Equal(self.T(), 0, len(fav.Resources.Artifacts))
+
+	_, err = favorites.GetFavorite(
+		self.Ctx, self.ConfigObj, "bob", "Private", "CLIENT")
+	assert.Error(self.T(), err)
+
+	// Bob may not overwrite alice's shared favorite.
+	self.save("bob", "Triage", true)
+	fav, err = favorites.GetFavorite(
+		self.Ctx, self.ConfigObj, "bob", "Triage", "CLIENT")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "alice", fav.Creator)
+
+	// Bob's own favorite takes precedence over the shared one.
+	self.save("bob", "Triage", false)
+	fav, err = favorites.GetFavorite(
+		self.Ctx, self.ConfigObj, "bob", "Triage", "CLIENT")
+	assert.NoError(self.T(), err)
+	assert.False(self.T(), fav.Shared)
+	assert.Equal(self.T(), "bob", fav.Creator)
+
+	// An administrator may take over the shared favorite.
+	self.save("admin", "Triage", true)
+	fav, err = favorites.GetFavorite(
+		self.Ctx, self.ConfigObj, "alice", "Triage", "CLIENT")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "admin", fav.Creator)
+}
+
+func TestFavorites(t *testing.T) {
+	suite.Run(t, &FavoritesTestSuite{})
+}
@@ -0,0 +1,92 @@
+package favorites
+
+import (
+	"context"
+	"fmt"
+
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// Find the named favorite. The user's own favorites take precedence
+// over the ones shared with the org.
+func GetFavorite(ctx context.Context, config_obj *config_proto.Config,
+	principal, name, fav_type string) (*api_proto.Favorite, error) {
+	users_manager := services.GetUserManager()
+	favorites, err := users_manager.GetFavorites(
+		ctx, config_obj, principal, fav_type)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fav := range favorites.Items {
+		if fav.Name == name {
+			return fav, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Favorite %v not found", name)
+}
+
+// Only the creator may change a shared favorite unless the user is
+// an administrator.
+func checkSharedFavoriteOwner(config_obj *config_proto.Config,
+	db datastore.DataStore, principal, name, fav_type string) error {
+	existing := &api_proto.Favorite{}
+	err := db.GetSubject(config_obj,
+		paths.SharedFavorite(name, fav_type), existing)
+	if err != nil || existing.Creator == "" ||
+		existing.Creator == principal {
+		return nil
+	}
+
+	ok, _ := services.CheckAccess(config_obj, principal, acls.SERVER_ADMIN)
+	if !ok {
+		return fmt.Errorf("Favorite %v is owned by %v",
+			name, existing.Creator)
+	}
+	return nil
+}
+
+// Only keep the settings which control how the collection runs. The
+// artifacts to collect come from the favorite's specs.
+func validateResources(resources interface{}) (
+	*flows_proto.ArtifactCollectorArgs, error) {
+	serialized, ok := resources.(string)
+	if !ok {
+		data, err := json.Marshal(resources)
+		if err != nil {
+			return nil, err
+		}
+		serialized = string(data)
+	}
+
+	if serialized == "" {
+		return nil, nil
+	}
+
+	request := &flows_proto.ArtifactCollectorArgs{}
+	err := json.Unmarshal([]byte(serialized), request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &flows_proto.ArtifactCollectorArgs{
+		Urgent:          request.Urgent,
+		Priority:        request.Priority,
+		CpuLimit:        request.CpuLimit,
+		IopsLimit:       request.IopsLimit,
+		OpsPerSecond:    request.OpsPerSecond,
+		ProgressTimeout: request.ProgressTimeout,
+		Timeout:         request.Timeout,
+		MaxRows:         request.MaxRows,
+		MaxUploadBytes:  request.MaxUploadBytes,
+		TraceFreqSec:    request.TraceFreqSec,
+	}, nil
+}
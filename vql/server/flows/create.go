@@ -23,12 +23,14 @@ import (
 	"github.com/Velocidex/ordereddict"
 	"www.velocidex.com/golang/velociraptor/acls"
 	"www.velocidex.com/golang/velociraptor/artifacts"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
 	"www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/vql"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vql/server/favorites"
 	"www.velocidex.com/golang/velociraptor/vql/tools/collector"
 	"www.velocidex.com/golang/vfilter"
 	"www.velocidex.com/golang/vfilter/arg_parser"
@@ -36,7 +38,8 @@ import (
 
 type ScheduleCollectionFunctionArg struct {
 	ClientId     string      `vfilter:"required,field=client_id,doc=The client id to schedule a collection on"`
-	Artifacts    []string    `vfilter:"required,field=artifacts,doc=A list of artifacts to collect"`
+	Artifacts    []string    `vfilter:"optional,field=artifacts,doc=A list of artifacts to collect"`
+	Favorite     string      `vfilter:"optional,field=favorite,doc=Launch a saved favorite with its artifacts, parameters and resource limits. Other args override the favorite's settings."`
 	Env          vfilter.Any `vfilter:"optional,field=env,doc=Parameters to apply to the artifact (an alternative to a full spec)"`
 	Spec         vfilter.Any `vfilter:"optional,field=spec,doc=Parameters to apply to the artifacts"`
 	Timeout      uint64      `vfilter:"optional,field=timeout,doc=Set query timeout (default 10 min)"`
@@ -46,6 +49,8 @@ type ScheduleCollectionFunctionArg struct {
 	MaxRows      uint64      `vfilter:"optional,field=max_rows,doc=Max number of rows to fetch"`
 	MaxBytes     uint64      `vfilter:"optional,field=max_bytes,doc=Max number of bytes to upload"`
 	Urgent       bool        `vfilter:"optional,field=urgent,doc=Set the collection as urgent - skips other queues collections on the client."`
+	Priority     int64       `vfilter:"optional,field=priority,doc=Collections with a higher priority run first on the client and may preempt lower priority ones (default 0)."`
+	DryRun       bool        `vfilter:"optional,field=dry_run,doc=Only estimate the number of files and bytes the collection would upload without uploading them."`
 	OrgId        string      `vfilter:"optional,field=org_id,doc=If set the collection will be started in the specified org."`
 }
 
@@ -62,11 +67,6 @@ func (self *ScheduleCollectionFunction) Call(ctx context.Context,
 		return vfilter.Null{}
 	}
 
-	if len(arg.Artifacts) == 0 {
-		scope.Log("collect_client: no artifacts to collect!")
-		return vfilter.Null{}
-	}
-
 	config_obj, ok := vql_subsystem.GetServerConfig(scope)
 	if !ok {
 		scope.Log("collect_client: Command can only run on the server")
@@ -120,6 +120,19 @@ func (self *ScheduleCollectionFunction) Call(ctx context.Context,
 		}
 	}
 
+	if arg.Favorite != "" {
+		err = applyFavorite(ctx, config_obj, scope, args, arg)
+		if err != nil {
+			scope.Log("collect_client: %v", err)
+			return vfilter.Null{}
+		}
+	}
+
+	if len(arg.Artifacts) == 0 {
+		scope.Log("collect_client: no artifacts to collect!")
+		return vfilter.Null{}
+	}
+
 	manager, err := services.GetRepositoryManager(config_obj)
 	if err != nil {
 		scope.Log("collect_client: Command can only run on the server")
@@ -142,6 +155,8 @@ func (self *ScheduleCollectionFunction) Call(ctx context.Context,
 		MaxRows:        arg.MaxRows,
 		MaxUploadBytes: arg.MaxBytes,
 		Urgent:         arg.Urgent,
+		Priority:       arg.Priority,
+		DryRun:         arg.DryRun,
 	}
 
 	if arg.Spec == nil {
@@ -191,6 +206,70 @@ func (self *ScheduleCollectionFunction) Call(ctx context.Context,
 	return json.ConvertProtoToOrderedDict(result)
 }
 
+// Fill in the artifacts, parameters and resource limits from the
+// saved favorite. Args given explicitly take precedence.
+func applyFavorite(ctx context.Context,
+	config_obj *config_proto.Config, scope vfilter.Scope,
+	args *ordereddict.Dict, arg *ScheduleCollectionFunctionArg) error {
+	fav_type := "CLIENT"
+	if arg.ClientId == "server" {
+		fav_type = "SERVER"
+	}
+
+	fav, err := favorites.GetFavorite(ctx, config_obj,
+		vql_subsystem.GetPrincipal(scope), arg.Favorite, fav_type)
+	if err != nil {
+		return err
+	}
+
+	spec := ordereddict.NewDict()
+	artifacts := []string{}
+	for _, item := range fav.Spec {
+		env := ordereddict.NewDict()
+		if item.Parameters != nil {
+			for _, param := range item.Parameters.Env {
+				env.Set(param.Key, param.Value)
+			}
+		}
+		spec.Set(item.Artifact, env)
+		artifacts = append(artifacts, item.Artifact)
+	}
+
+	if len(arg.Artifacts) == 0 {
+		arg.Artifacts = artifacts
+	}
+
+	if arg.Spec == nil && arg.Env == nil {
+		arg.Spec = spec
+	}
+
+	resources := fav.Resources
+	if resources == nil {
+		return nil
+	}
+
+	for _, item := range []struct {
+		field string
+		set   func()
+	}{
+		{"timeout", func() { arg.Timeout = resources.Timeout }},
+		{"ops_per_sec", func() { arg.OpsPerSecond = float64(resources.OpsPerSecond) }},
+		{"cpu_limit", func() { arg.CpuLimit = float64(resources.CpuLimit) }},
+		{"iops_limit", func() { arg.IopsLimit = float64(resources.IopsLimit) }},
+		{"max_rows", func() { arg.MaxRows = resources.MaxRows }},
+		{"max_bytes", func() { arg.MaxBytes = resources.MaxUploadBytes }},
+		{"urgent", func() { arg.Urgent = resources.Urgent }},
+		{"priority", func() { arg.Priority = resources.Priority }},
+	} {
+		_, pres := args.Get(item.field)
+		if !pres {
+			item.set()
+		}
+	}
+
+	return nil
+}
+
 func (self ScheduleCollectionFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
 	return &vfilter.FunctionInfo{
 		Name:    "collect_client",
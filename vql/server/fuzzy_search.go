@@ -0,0 +1,164 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/functions"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	defaultSSDeepThreshold = 50
+	defaultTLSHThreshold   = 100
+)
+
+type FuzzySearchPluginArgs struct {
+	Hashes    []string            `vfilter:"required,field=hash,doc=One or more ssdeep or tlsh hashes to search for."`
+	Query     vfilter.StoredQuery `vfilter:"required,field=query,doc=A query producing the stored hash set (e.g. source() or parse_csv())."`
+	Column    string              `vfilter:"optional,field=column,doc=The column holding the hash in each row (default Hash)."`
+	Threshold int64               `vfilter:"optional,field=threshold,doc=The minimum ssdeep score (default 50) or the maximum tlsh distance (default 100) to report."`
+}
+
+type fuzzyHash struct {
+	hash      string
+	hash_type string
+}
+
+// Detect the hash type from its format: ssdeep hashes are of the
+// form blocksize:hash1:hash2 while tlsh hashes are hex encoded.
+func getFuzzyHashType(hash string) string {
+	if strings.Count(hash, ":") >= 2 {
+		return "ssdeep"
+	}
+
+	switch len(hash) {
+	case 70, 72:
+		return "tlsh"
+	}
+	return ""
+}
+
+// Search a stored hash set for hashes similar to the ones given. This
+// allows hunting for variants of known samples using the fuzzy hashes
+// collected from endpoints, e.g.:
+//
+//	SELECT * FROM fuzzy_search(hash=Sample.SSDeep, query={
+//	   SELECT * FROM hunt_results(hunt_id=HuntId, artifact=...)
+//	}, column="SSDeep")
+type FuzzySearchPlugin struct{}
+
+func (self FuzzySearchPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		// The query is evaluated with the caller's permissions
+		// so we do not need to check any further.
+		arg := &FuzzySearchPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("fuzzy_search: %v", err)
+			return
+		}
+
+		if arg.Column == "" {
+			arg.Column = "Hash"
+		}
+
+		hashes := []fuzzyHash{}
+		for _, hash := range arg.Hashes {
+			hash_type := getFuzzyHashType(hash)
+			if hash_type == "" {
+				scope.Log("fuzzy_search: Unknown hash type for %v", hash)
+				return
+			}
+			hashes = append(hashes, fuzzyHash{hash: hash, hash_type: hash_type})
+		}
+
+		for row := range arg.Query.Eval(ctx, scope) {
+			value, pres := scope.Associative(row, arg.Column)
+			if !pres {
+				continue
+			}
+
+			candidate := strings.TrimSpace(fmt.Sprintf("%v", value))
+			candidate_type := getFuzzyHashType(candidate)
+
+			for _, hash := range hashes {
+				if hash.hash_type != candidate_type {
+					continue
+				}
+
+				column, score, ok := compareFuzzyHash(hash, candidate, arg.Threshold)
+				if !ok {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- vfilter.RowToDict(ctx, scope, row).
+					Set("_Search", hash.hash).
+					Set(column, score):
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+// Returns the column name and score if the candidate is similar
+// enough to the hash.
+func compareFuzzyHash(hash fuzzyHash,
+	candidate string, threshold int64) (string, int, bool) {
+	switch hash.hash_type {
+	case "ssdeep":
+		if threshold == 0 {
+			threshold = defaultSSDeepThreshold
+		}
+
+		score, err := functions.SSDeepCompare(hash.hash, candidate)
+		if err != nil || int64(score) < threshold {
+			return "", 0, false
+		}
+		return "Score", score, true
+
+	case "tlsh":
+		if threshold == 0 {
+			threshold = defaultTLSHThreshold
+		}
+
+		distance, err := functions.TLSHDiff(hash.hash, candidate)
+		if err != nil || int64(distance) > threshold {
+			return "", 0, false
+		}
+		return "Distance", distance, true
+	}
+
+	return "", 0, false
+}
+
+func (self FuzzySearchPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "fuzzy_search",
+		Doc:     "Search a stored hash set for files similar to the given ssdeep or tlsh hashes.",
+		ArgType: type_map.AddType(scope, &FuzzySearchPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&FuzzySearchPlugin{})
+}
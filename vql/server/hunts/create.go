@@ -50,11 +50,18 @@ type ScheduleHuntFunctionArg struct {
 	IopsLimit     float64          `vfilter:"optional,field=iops_limit,doc=Set query ops_per_sec value"`
 	MaxRows       uint64           `vfilter:"optional,field=max_rows,doc=Max number of rows to fetch"`
 	MaxBytes      uint64           `vfilter:"optional,field=max_bytes,doc=Max number of bytes to upload"`
+	Priority      int64            `vfilter:"optional,field=priority,doc=Collections with a higher priority run first on the client and may preempt lower priority ones (default 0)."`
+	DryRun        bool             `vfilter:"optional,field=dry_run,doc=Only estimate the number of files and bytes the hunt would upload without uploading them."`
+	ClientLimit   uint64           `vfilter:"optional,field=client_limit,doc=Only schedule the hunt on this many clients (e.g. to sample a dry run)"`
 	Pause         bool             `vfilter:"optional,field=pause,doc=If specified the new hunt will be in the paused state"`
 	IncludeLabels []string         `vfilter:"optional,field=include_labels,doc=If specified only include these labels"`
 	ExcludeLabels []string         `vfilter:"optional,field=exclude_labels,doc=If specified exclude these labels"`
 	OS            string           `vfilter:"optional,field=os,doc=If specified target this OS"`
 	OrgIds        []string         `vfilter:"optional,field=org_id,doc=If set the collection will be started in the specified orgs."`
+	VQLCondition  string           `vfilter:"optional,field=vql_condition,doc=If specified only target clients selected by this VQL query (evaluated with ClientId set)"`
+	QuotaRows     uint64           `vfilter:"optional,field=quota_rows,doc=Stop the hunt when its collections returned more than this many rows in total"`
+	QuotaBytes    uint64           `vfilter:"optional,field=quota_upload_bytes,doc=Stop the hunt when its collections uploaded more than this many bytes in total"`
+	QuotaTime     uint64           `vfilter:"optional,field=quota_execution_time,doc=Stop the hunt when its collections ran for more than this many seconds in total"`
 }
 
 type ScheduleHuntFunction struct{}
@@ -132,6 +139,8 @@ func (self *ScheduleHuntFunction) Call(ctx context.Context,
 		Timeout:        arg.Timeout,
 		MaxRows:        arg.MaxRows,
 		MaxUploadBytes: arg.MaxBytes,
+		Priority:       arg.Priority,
+		DryRun:         arg.DryRun,
 	}
 
 	principal := vql_subsystem.GetPrincipal(scope)
@@ -143,7 +152,10 @@ func (self *ScheduleHuntFunction) Call(ctx context.Context,
 	}
 
 	state := api_proto.Hunt_RUNNING
-	if arg.Pause {
+
+	// Hunts with a VQL condition are started once the condition is
+	// in place so no clients are scheduled without it.
+	if arg.Pause || arg.VQLCondition != "" {
 		state = api_proto.Hunt_PAUSED
 	}
 
@@ -153,6 +165,7 @@ func (self *ScheduleHuntFunction) Call(ctx context.Context,
 		StartRequest:    request,
 		Expires:         expires,
 		State:           state,
+		ClientLimit:     arg.ClientLimit,
 	}
 
 	if len(arg.IncludeLabels) > 0 {
@@ -254,6 +267,42 @@ func (self *ScheduleHuntFunction) Call(ctx context.Context,
 			continue
 		}
 
+		if arg.QuotaRows > 0 || arg.QuotaBytes > 0 || arg.QuotaTime > 0 {
+			err = hunt_dispatcher.SetHuntQuota(ctx, org_config_obj,
+				new_hunt.HuntId, &services.HuntQuota{
+					MaxRows:          arg.QuotaRows,
+					MaxUploadBytes:   arg.QuotaBytes,
+					MaxExecutionTime: arg.QuotaTime,
+				})
+			if err != nil {
+				scope.Log("hunt: %v", err)
+				continue
+			}
+		}
+
+		if arg.VQLCondition != "" {
+			err = hunt_dispatcher.SetHuntVQLCondition(ctx, org_config_obj,
+				new_hunt.HuntId, principal, arg.VQLCondition)
+			if err != nil {
+				scope.Log("hunt: %v", err)
+				continue
+			}
+
+			if !arg.Pause {
+				err = hunt_dispatcher.MutateHunt(ctx, org_config_obj,
+					&api_proto.HuntMutation{
+						HuntId: new_hunt.HuntId,
+						State:  api_proto.Hunt_RUNNING,
+						StartTime: uint64(
+							utils.GetTime().Now().UnixNano() / 1000),
+					})
+				if err != nil {
+					scope.Log("hunt: %v", err)
+					continue
+				}
+			}
+		}
+
 		orgs_we_scheduled = append(orgs_we_scheduled, org_id)
 
 		// The first hunt will create an Id then subsequent hunts will
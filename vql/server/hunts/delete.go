@@ -2,11 +2,13 @@ package hunts
 
 import (
 	"context"
+	"errors"
 
 	"github.com/Velocidex/ordereddict"
 	"www.velocidex.com/golang/velociraptor/acls"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/legal_hold"
 	"www.velocidex.com/golang/velociraptor/vql"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/vfilter"
@@ -69,6 +71,12 @@ func (self DeleteHuntPlugin) Call(ctx context.Context,
 			return
 		}
 
+		if arg.ReallyDoIt && legal_hold.IsHuntHeld(config_obj, arg.HuntId) {
+			scope.Log("hunt_delete: %v: hunt %v",
+				legal_hold.ErrOnHold, arg.HuntId)
+			return
+		}
+
 		services.LogAudit(ctx,
 			config_obj, principal, "hunt_delete",
 			ordereddict.NewDict().
@@ -82,6 +90,11 @@ func (self DeleteHuntPlugin) Call(ctx context.Context,
 				flow_details.Context.ClientId,
 				flow_details.Context.SessionId,
 				services.NoAuditLogging, arg.ReallyDoIt)
+			// Flows on held clients are kept.
+			if errors.Is(err, legal_hold.ErrOnHold) {
+				scope.Log("hunt_delete: %v", err)
+				continue
+			}
 			if err != nil {
 				scope.Log("hunt_delete: %v", err)
 				return
@@ -0,0 +1,102 @@
+package hunts
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type HuntStackPluginArgs struct {
+	HuntId   string   `vfilter:"required,field=hunt_id,doc=The hunt id to stack."`
+	Artifact string   `vfilter:"optional,field=artifact,doc=The artifact source to stack (default the first source in the hunt)."`
+	Columns  []string `vfilter:"optional,field=columns,doc=The columns to stack on (default all columns)."`
+}
+
+type HuntStackPlugin struct{}
+
+func (self HuntStackPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("hunt_stack: %s", err)
+			return
+		}
+
+		arg := &HuntStackPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("hunt_stack: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		hunt_dispatcher, err := services.GetHuntDispatcher(config_obj)
+		if err != nil {
+			scope.Log("hunt_stack: %v", err)
+			return
+		}
+
+		stack, err := hunt_dispatcher.StackHuntResults(
+			ctx, config_obj, arg.HuntId, arg.Artifact, arg.Columns)
+		if err != nil {
+			scope.Log("hunt_stack: %v", err)
+			return
+		}
+
+		if stack.Truncated {
+			scope.Log("hunt_stack: Too many distinct values, results are truncated")
+		}
+
+		for _, row := range stack.Rows {
+			// Flatten the stacked columns into the row.
+			result := ordereddict.NewDict()
+			for _, k := range row.Value.Keys() {
+				v, _ := row.Value.Get(k)
+				result.Set(k, v)
+			}
+
+			result.Set("Clients", row.Clients).
+				Set("Rows", row.Rows).
+				Set("Percent", row.Percent).
+				Set("SampleClients", row.SampleClients)
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- result:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self HuntStackPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "hunt_stack",
+		Doc:      "Count how many clients in a hunt returned each distinct value, least frequent first.",
+		ArgType:  type_map.AddType(scope, &HuntStackPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&HuntStackPlugin{})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Velocidex/ordereddict"
 	"www.velocidex.com/golang/velociraptor/acls"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql"
@@ -16,15 +17,55 @@ import (
 )
 
 type UpdateHuntFunctionArg struct {
-	HuntId      string    `vfilter:"required,field=hunt_id,doc=The hunt to update"`
-	Stop        bool      `vfilter:"optional,field=stop,doc=Stop the hunt"`
-	Start       bool      `vfilter:"optional,field=start,doc=Start the hunt"`
-	Description string    `vfilter:"optional,field=description,doc=Update hunt description"`
-	Expires     time.Time `vfilter:"optional,field=expires,doc=Update hunt expiry"`
+	HuntId           string    `vfilter:"required,field=hunt_id,doc=The hunt to update"`
+	Stop             bool      `vfilter:"optional,field=stop,doc=Stop the hunt"`
+	Start            bool      `vfilter:"optional,field=start,doc=Start the hunt (or resume a paused hunt)"`
+	Pause            bool      `vfilter:"optional,field=pause,doc=Pause the hunt - no new clients are scheduled until it is started again"`
+	Description      string    `vfilter:"optional,field=description,doc=Update hunt description"`
+	Expires          time.Time `vfilter:"optional,field=expires,doc=Update hunt expiry"`
+	ClientsPerMinute float64   `vfilter:"optional,field=clients_per_minute,doc=Limit the rate new clients are scheduled on the hunt (0 for no limit)"`
+	VQLCondition     string    `vfilter:"optional,field=vql_condition,doc=Only target clients selected by this VQL query (empty to remove the condition)"`
+	QuotaRows        uint64    `vfilter:"optional,field=quota_rows,doc=Update the total number of rows the hunt may collect (0 for no limit)"`
+	QuotaBytes       uint64    `vfilter:"optional,field=quota_upload_bytes,doc=Update the total number of bytes the hunt may upload (0 for no limit)"`
+	QuotaTime        uint64    `vfilter:"optional,field=quota_execution_time,doc=Update the total execution time in seconds of the hunt's collections (0 for no limit)"`
 }
 
 type UpdateHuntFunction struct{}
 
+// Only update the parts of the quota which were specified.
+func updateHuntQuota(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_dispatcher services.IHuntDispatcher,
+	arg *UpdateHuntFunctionArg, args *ordereddict.Dict) error {
+
+	_, rows_pres := args.Get("quota_rows")
+	_, bytes_pres := args.Get("quota_upload_bytes")
+	_, time_pres := args.Get("quota_execution_time")
+	if !rows_pres && !bytes_pres && !time_pres {
+		return nil
+	}
+
+	quota, err := hunt_dispatcher.GetHuntQuota(config_obj, arg.HuntId)
+	if err != nil {
+		return err
+	}
+
+	if rows_pres {
+		quota.MaxRows = arg.QuotaRows
+	}
+
+	if bytes_pres {
+		quota.MaxUploadBytes = arg.QuotaBytes
+	}
+
+	if time_pres {
+		quota.MaxExecutionTime = arg.QuotaTime
+	}
+
+	return hunt_dispatcher.SetHuntQuota(ctx, config_obj, arg.HuntId, quota)
+}
+
 func (self *UpdateHuntFunction) Call(ctx context.Context,
 	scope vfilter.Scope,
 	args *ordereddict.Dict) vfilter.Any {
@@ -54,17 +95,50 @@ func (self *UpdateHuntFunction) Call(ctx context.Context,
 		return vfilter.Null{}
 	}
 
-	if arg.Start || arg.Stop {
-		state := api_proto.Hunt_STOPPED
+	_, pres := args.Get("clients_per_minute")
+	if pres {
+		err = hunt_dispatcher.SetHuntSchedulingRate(
+			ctx, config_obj, arg.HuntId, arg.ClientsPerMinute)
+		if err != nil {
+			scope.Log("hunt_update: %v", err)
+			return vfilter.Null{}
+		}
+	}
+
+	err = updateHuntQuota(ctx, config_obj, hunt_dispatcher, arg, args)
+	if err != nil {
+		scope.Log("hunt_update: %v", err)
+		return vfilter.Null{}
+	}
+
+	_, pres = args.Get("vql_condition")
+	if pres {
+		err = hunt_dispatcher.SetHuntVQLCondition(ctx, config_obj,
+			arg.HuntId, vql_subsystem.GetPrincipal(scope), arg.VQLCondition)
+		if err != nil {
+			scope.Log("hunt_update: %v", err)
+			return vfilter.Null{}
+		}
+	}
+
+	if arg.Start || arg.Stop || arg.Pause {
+		mutation := &api_proto.HuntMutation{
+			HuntId: arg.HuntId,
+			State:  api_proto.Hunt_STOPPED,
+		}
+
 		if arg.Start {
-			state = api_proto.Hunt_RUNNING
+			mutation.State = api_proto.Hunt_RUNNING
+
+			// Update the start time so clients which checked in
+			// while the hunt was paused are scheduled.
+			mutation.StartTime = uint64(utils.GetTime().Now().UnixNano() / 1000)
+
+		} else if arg.Pause {
+			mutation.State = api_proto.Hunt_PAUSED
 		}
 
-		err = hunt_dispatcher.MutateHunt(
-			ctx, config_obj, &api_proto.HuntMutation{
-				HuntId: arg.HuntId,
-				State:  state,
-			})
+		err = hunt_dispatcher.MutateHunt(ctx, config_obj, mutation)
 		if err != nil {
 			scope.Log("hunt_update: %v", err)
 			return vfilter.Null{}
@@ -0,0 +1,246 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/taxii"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type IOCLookupFunctionArgs struct {
+	Value          string `vfilter:"required,field=value,doc=The value to look up (e.g. a hash, IP or domain)."`
+	Type           string `vfilter:"optional,field=type,doc=The type of IOC (md5, sha1, sha256, sha512, filename, ipv4, ipv6, domain, url, email). By default all types are checked."`
+	IncludeExpired bool   `vfilter:"optional,field=include_expired,doc=Also return expired IOCs."`
+}
+
+type IOCLookupFunction struct{}
+
+func (self IOCLookupFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+	if err != nil {
+		scope.Log("ioc_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &IOCLookupFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("ioc_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("ioc_lookup: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	matches, err := taxii.Lookup(config_obj, arg.Type, arg.Value,
+		arg.IncludeExpired)
+	if err != nil {
+		scope.Log("ioc_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	if len(matches) == 0 {
+		return vfilter.Null{}
+	}
+
+	result := make([]*ordereddict.Dict, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, iocToRow(match.Collection, match.IOC))
+	}
+	return result
+}
+
+func (self IOCLookupFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "ioc_lookup",
+		Doc:      "Look up a value in the IOC tables populated from threat intel feeds.",
+		ArgType:  type_map.AddType(scope, &IOCLookupFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type IOCsPluginArgs struct {
+	Collection     string `vfilter:"optional,field=collection,doc=Only show IOCs from this collection."`
+	IncludeExpired bool   `vfilter:"optional,field=include_expired,doc=Also show expired IOCs."`
+}
+
+type IOCsPlugin struct{}
+
+func (self IOCsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("iocs: %v", err)
+			return
+		}
+
+		arg := &IOCsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("iocs: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("iocs: Command can only run on the server")
+			return
+		}
+
+		tables, err := taxii.GetTables(config_obj)
+		if err != nil {
+			scope.Log("iocs: %v", err)
+			return
+		}
+
+		now := utils.GetTime().Now()
+		for _, table := range tables {
+			if arg.Collection != "" && arg.Collection != table.Name {
+				continue
+			}
+
+			for _, ioc := range table.Sorted() {
+				if !arg.IncludeExpired && ioc.Expired(now) {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- iocToRow(table.Name, ioc):
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self IOCsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "iocs",
+		Doc:      "List the IOCs in the IOC tables populated from threat intel feeds.",
+		ArgType:  type_map.AddType(scope, &IOCsPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type TaxiiCollectionsPlugin struct{}
+
+func (self TaxiiCollectionsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("taxii_collections: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("taxii_collections: Command can only run on the server")
+			return
+		}
+
+		tables, err := taxii.GetTables(config_obj)
+		if err != nil {
+			scope.Log("taxii_collections: %v", err)
+			return
+		}
+
+		now := utils.GetTime().Now()
+		for _, table := range tables {
+			active := 0
+			for _, ioc := range table.IOCs {
+				if !ioc.Expired(now) {
+					active++
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Name", table.Name).
+				Set("Url", table.Url).
+				Set("LastPoll", unixTime(table.LastPoll)).
+				Set("LastSuccess", unixTime(table.LastSuccess)).
+				Set("LastError", table.LastError).
+				Set("AddedAfter", table.AddedAfter).
+				Set("Total", len(table.IOCs)).
+				Set("Active", active):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self TaxiiCollectionsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "taxii_collections",
+		Doc:      "Show the polling state of the TAXII collections feeding the IOC tables.",
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func iocToRow(collection string, ioc *taxii.IOC) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Collection", collection).
+		Set("Type", ioc.Type).
+		Set("Value", ioc.Value).
+		Set("Name", ioc.Name).
+		Set("Description", ioc.Description).
+		Set("IndicatorId", ioc.IndicatorId).
+		Set("IndicatorTypes", ioc.IndicatorTypes).
+		Set("Confidence", ioc.Confidence).
+		Set("ValidFrom", unixTime(ioc.ValidFrom)).
+		Set("ValidUntil", unixTime(ioc.ValidUntil)).
+		Set("FirstSeen", unixTime(ioc.FirstSeen)).
+		Set("LastSeen", unixTime(ioc.LastSeen)).
+		Set("Expired", ioc.Expired(utils.GetTime().Now()))
+}
+
+func unixTime(value int64) vfilter.Any {
+	if value == 0 {
+		return vfilter.Null{}
+	}
+	return time.Unix(value, 0).UTC()
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&IOCLookupFunction{})
+	vql_subsystem.RegisterPlugin(&IOCsPlugin{})
+	vql_subsystem.RegisterPlugin(&TaxiiCollectionsPlugin{})
+}
@@ -0,0 +1,175 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/legal_hold"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type LegalHoldFunctionArgs struct {
+	Type     string `vfilter:"required,field=type,doc=What to hold (client, flow or hunt)."`
+	ClientId string `vfilter:"optional,field=client_id,doc=The client to hold (or the client of the held flow)."`
+	FlowId   string `vfilter:"optional,field=flow_id,doc=The flow to hold."`
+	HuntId   string `vfilter:"optional,field=hunt_id,doc=The hunt to hold."`
+	Reason   string `vfilter:"optional,field=reason,doc=Why the data is held (e.g. a case reference)."`
+	Release  bool   `vfilter:"optional,field=release,doc=Release the hold instead of placing it."`
+}
+
+type LegalHoldFunction struct{}
+
+func (self LegalHoldFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+	if err != nil {
+		scope.Log("legal_hold: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &LegalHoldFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("legal_hold: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("legal_hold: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	hold := &legal_hold.Hold{
+		Type:     arg.Type,
+		ClientId: arg.ClientId,
+		FlowId:   arg.FlowId,
+		HuntId:   arg.HuntId,
+		Reason:   arg.Reason,
+	}
+	principal := vql_subsystem.GetPrincipal(scope)
+
+	if arg.Release {
+		err = legal_hold.Release(ctx, config_obj, principal, hold)
+		if err != nil {
+			scope.Log("legal_hold: %v", err)
+			return vfilter.Null{}
+		}
+		return legalHoldRow(hold)
+	}
+
+	hold, err = legal_hold.Place(ctx, config_obj, principal, hold)
+	if err != nil {
+		scope.Log("legal_hold: %v", err)
+		return vfilter.Null{}
+	}
+
+	return legalHoldRow(hold)
+}
+
+func (self LegalHoldFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "legal_hold",
+		Doc:      "Place or release a legal hold preventing deletion of a client, flow or hunt.",
+		ArgType:  type_map.AddType(scope, &LegalHoldFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.SERVER_ADMIN).Build(),
+	}
+}
+
+type LegalHoldsPluginArgs struct {
+	Report bool `vfilter:"optional,field=report,doc=Also calculate the volume of held data (slow)."`
+}
+
+type LegalHoldsPlugin struct{}
+
+func (self LegalHoldsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("legal_holds: %v", err)
+			return
+		}
+
+		arg := &LegalHoldsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("legal_holds: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("legal_holds: Command can only run on the server")
+			return
+		}
+
+		var holds []*legal_hold.Hold
+		if arg.Report {
+			holds, err = legal_hold.Report(ctx, config_obj)
+		} else {
+			holds, err = legal_hold.List(config_obj)
+		}
+		if err != nil {
+			scope.Log("legal_holds: %v", err)
+			return
+		}
+
+		for _, hold := range holds {
+			row := legalHoldRow(hold)
+			if arg.Report {
+				row.Set("Files", hold.Files).
+					Set("Bytes", hold.Bytes)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self LegalHoldsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "legal_holds",
+		Doc:      "List the legal holds and optionally the volume of data they hold.",
+		ArgType:  type_map.AddType(scope, &LegalHoldsPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func legalHoldRow(hold *legal_hold.Hold) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Type", hold.Type).
+		Set("ClientId", hold.ClientId).
+		Set("FlowId", hold.FlowId).
+		Set("HuntId", hold.HuntId).
+		Set("Reason", hold.Reason).
+		Set("Principal", hold.Principal).
+		Set("Created", unixTime(hold.Created))
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&LegalHoldFunction{})
+	vql_subsystem.RegisterPlugin(&LegalHoldsPlugin{})
+}
@@ -0,0 +1,182 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/libraries"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type VQLLibraryFunctionArgs struct {
+	Name        string `vfilter:"required,field=name,doc=The name of the library (e.g. Custom.Parsers)."`
+	VQL         string `vfilter:"optional,field=vql,doc=The LET statements making up the library."`
+	Description string `vfilter:"optional,field=description,doc=A description of the library."`
+	Delete      bool   `vfilter:"optional,field=delete,doc=Delete the library and all its versions."`
+}
+
+type VQLLibraryFunction struct{}
+
+func (self VQLLibraryFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	// Server artifacts may import libraries so changing them is as
+	// powerful as changing server artifacts.
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ARTIFACT_WRITER)
+	if err != nil {
+		scope.Log("vql_library: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &VQLLibraryFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("vql_library: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("vql_library: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	principal := vql_subsystem.GetPrincipal(scope)
+
+	if arg.Delete {
+		err = libraries.Delete(ctx, config_obj, principal, arg.Name)
+		if err != nil {
+			scope.Log("vql_library: %v", err)
+			return vfilter.Null{}
+		}
+		return arg.Name
+	}
+
+	library, err := libraries.Set(ctx, config_obj, principal,
+		arg.Name, arg.Description, arg.VQL)
+	if err != nil {
+		scope.Log("vql_library: %v", err)
+		return vfilter.Null{}
+	}
+
+	return vqlLibraryRow(library, library.Latest())
+}
+
+func (self VQLLibraryFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "vql_library",
+		Doc:      "Store a new version of a VQL library or delete a library.",
+		ArgType:  type_map.AddType(scope, &VQLLibraryFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.SERVER_ARTIFACT_WRITER).Build(),
+	}
+}
+
+type VQLLibrariesPluginArgs struct {
+	Name     string `vfilter:"optional,field=name,doc=Only show this library."`
+	Versions bool   `vfilter:"optional,field=versions,doc=Show all versions instead of the latest."`
+}
+
+type VQLLibrariesPlugin struct{}
+
+func (self VQLLibrariesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("vql_libraries: %v", err)
+			return
+		}
+
+		arg := &VQLLibrariesPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("vql_libraries: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("vql_libraries: Command can only run on the server")
+			return
+		}
+
+		var items []*libraries.Library
+		if arg.Name != "" {
+			library, err := libraries.Get(config_obj, arg.Name)
+			if err != nil {
+				scope.Log("vql_libraries: %v", err)
+				return
+			}
+			items = append(items, library)
+
+		} else {
+			items, err = libraries.List(config_obj)
+			if err != nil {
+				scope.Log("vql_libraries: %v", err)
+				return
+			}
+		}
+
+		for _, library := range items {
+			versions := []*libraries.Version{library.Latest()}
+			if arg.Versions {
+				versions = library.Versions
+			}
+
+			for _, version := range versions {
+				if version == nil {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- vqlLibraryRow(library, version):
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self VQLLibrariesPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "vql_libraries",
+		Doc:      "List the VQL libraries artifacts may import.",
+		ArgType:  type_map.AddType(scope, &VQLLibrariesPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func vqlLibraryRow(
+	library *libraries.Library, version *libraries.Version) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Name", library.Name).
+		Set("Description", library.Description).
+		Set("Version", version.Version).
+		Set("LatestVersion", library.Latest().Version).
+		Set("VQL", version.VQL).
+		Set("Principal", version.Principal).
+		Set("Created", unixTime(version.Created))
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&VQLLibraryFunction{})
+	vql_subsystem.RegisterPlugin(&VQLLibrariesPlugin{})
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"sort"
 
 	"github.com/Velocidex/ordereddict"
 	"www.velocidex.com/golang/velociraptor/acls"
@@ -41,20 +42,8 @@ func (self LogsPlugin) Call(
 			return
 		}
 
-		var target *string
-
-		switch arg.Component {
-		case "client":
-			target = &logging.ClientComponent
-		case "frontend":
-			target = &logging.FrontendComponent
-		case "gui":
-			target = &logging.GUIComponent
-		case "api":
-			target = &logging.APICmponent
-		case "tool":
-			target = &logging.ToolComponent
-		default:
+		target := logging.GetComponent(arg.Component)
+		if target == nil {
 			target = &logging.GenericComponent
 		}
 
@@ -94,6 +83,136 @@ func (self LogsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfi
 	}
 }
 
+type RecentLogsPluginArgs struct {
+	Component string `vfilter:"optional,field=component,doc=Only show logs from this component (e.g. frontend)"`
+	Level     string `vfilter:"optional,field=level,doc=Only show logs at or above this level (debug, info, warn, error)"`
+	Limit     int64  `vfilter:"optional,field=limit,doc=Only show this many of the most recent logs"`
+}
+
+type RecentLogsPlugin struct{}
+
+func (self RecentLogsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("recent_logs: %s", err)
+			return
+		}
+
+		arg := &RecentLogsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("recent_logs: %v", err)
+			return
+		}
+
+		filter, err := logging.NewLogFilter(arg.Component, arg.Level)
+		if err != nil {
+			scope.Log("recent_logs: %v", err)
+			return
+		}
+
+		entries := []logging.LogEntry{}
+		for _, entry := range logging.GetRecentLogs() {
+			if filter.Match(entry) {
+				entries = append(entries, entry)
+			}
+		}
+
+		if arg.Limit > 0 && int64(len(entries)) > arg.Limit {
+			entries = entries[int64(len(entries))-arg.Limit:]
+		}
+
+		for _, entry := range entries {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Time", entry.Time).
+				Set("Level", entry.Level).
+				Set("Component", entry.Component).
+				Set("Message", entry.Message):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self RecentLogsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "recent_logs",
+		Doc:      "Show the most recent logs kept in memory by the server.",
+		ArgType:  type_map.AddType(scope, &RecentLogsPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type LogLevelFunctionArgs struct {
+	Component string `vfilter:"optional,field=component,doc=The component to change (e.g. frontend)"`
+	Level     string `vfilter:"optional,field=level,doc=The new level (debug, info, warn, error)"`
+}
+
+type LogLevelFunction struct{}
+
+func (self LogLevelFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+	if err != nil {
+		scope.Log("log_level: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &LogLevelFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("log_level: %v", err)
+		return vfilter.Null{}
+	}
+
+	if arg.Component != "" || arg.Level != "" {
+		err = logging.SetLevel(arg.Component, arg.Level)
+		if err != nil {
+			scope.Log("log_level: %v", err)
+			return vfilter.Null{}
+		}
+	}
+
+	levels := logging.GetLevels()
+	keys := make([]string, 0, len(levels))
+	for k := range levels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := ordereddict.NewDict()
+	for _, k := range keys {
+		result.Set(k, levels[k])
+	}
+	return result
+}
+
+func (self LogLevelFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "log_level",
+		Doc:      "Change the log level of a server component until the next restart and return the current levels.",
+		ArgType:  type_map.AddType(scope, &LogLevelFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.SERVER_ADMIN).Build(),
+	}
+}
+
 func init() {
 	vql_subsystem.RegisterPlugin(&LogsPlugin{})
+	vql_subsystem.RegisterPlugin(&RecentLogsPlugin{})
+	vql_subsystem.RegisterFunction(&LogLevelFunction{})
 }
@@ -0,0 +1,246 @@
+/*
+  Plugins to collect Microsoft 365 and Entra ID audit logs.
+
+  Logs are pulled with an app registration using the client
+  credentials flow. The app needs the AuditLog.Read.All and
+  Directory.Read.All Graph permissions for the Entra ID logs and the
+  ActivityFeed.Read Office 365 Management API permission for the
+  unified audit log.
+
+  Both plugins emit a Cursor column. Passing the last cursor seen to
+  the next invocation only returns records that were not seen
+  before. When a cursor_name is given the cursor is kept in the
+  server metadata and only advanced when a run completes, so
+  collections can be scheduled incrementally.
+*/
+
+package m365
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/functions"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	M365_CREDENTIALS = "M365_CREDENTIALS"
+
+	// Cursors are formatted with a fixed precision so they sort
+	// lexically.
+	CURSOR_FORMAT = "2006-01-02T15:04:05.0000000Z"
+
+	MAX_RETRIES = 5
+)
+
+var (
+	// Override in tests
+	login_url   = "https://login.microsoftonline.com"
+	graph_url   = "https://graph.microsoft.com"
+	manage_url  = "https://manage.office.com"
+	retry_delay = time.Second
+)
+
+type credentials struct {
+	TenantId     string
+	ClientId     string
+	ClientSecret string
+}
+
+// Credentials are taken from the plugin args, falling back to the
+// M365_CREDENTIALS scope variable so they do not need to be repeated
+// in notebooks.
+func getCredentials(scope vfilter.Scope,
+	tenant_id, client_id, client_secret string) (*credentials, error) {
+	result := &credentials{
+		TenantId:     tenant_id,
+		ClientId:     client_id,
+		ClientSecret: client_secret,
+	}
+
+	setting, pres := scope.Resolve(M365_CREDENTIALS)
+	if pres {
+		if result.TenantId == "" {
+			result.TenantId = vql_subsystem.GetStringFromRow(
+				scope, setting, "tenant_id")
+		}
+		if result.ClientId == "" {
+			result.ClientId = vql_subsystem.GetStringFromRow(
+				scope, setting, "client_id")
+		}
+		if result.ClientSecret == "" {
+			result.ClientSecret = vql_subsystem.GetStringFromRow(
+				scope, setting, "client_secret")
+		}
+	}
+
+	if result.TenantId == "" || result.ClientId == "" ||
+		result.ClientSecret == "" {
+		return nil, errors.New(
+			"tenant_id, client_id and client_secret must be specified")
+	}
+
+	return result, nil
+}
+
+type apiClient struct {
+	client *http.Client
+}
+
+// Get a client that adds a bearer token for the resource. Tokens are
+// refreshed automatically.
+func newAPIClient(ctx context.Context,
+	creds *credentials, resource string) *apiClient {
+	config := &clientcredentials.Config{
+		ClientID:     creds.ClientId,
+		ClientSecret: creds.ClientSecret,
+		TokenURL: fmt.Sprintf("%s/%s/oauth2/v2.0/token",
+			login_url, creds.TenantId),
+		Scopes: []string{resource + "/.default"},
+	}
+
+	base := &http.Client{
+		Timeout: 5 * time.Minute,
+		Transport: &http.Transport{
+			Proxy: networking.GetProxy(),
+		},
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, base)
+	return &apiClient{client: config.Client(ctx)}
+}
+
+type apiError struct {
+	Status int
+	Body   string
+}
+
+func (self *apiError) Error() string {
+	return fmt.Sprintf("%v: %v", http.StatusText(self.Status), self.Body)
+}
+
+// Make a request, backing off when throttled.
+func (self *apiClient) do(ctx context.Context,
+	method, url string) ([]byte, http.Header, error) {
+	for i := 0; ; i++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resp, err := self.client.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return data, resp.Header, nil
+		}
+
+		if (resp.StatusCode != http.StatusTooManyRequests &&
+			resp.StatusCode < 500) || i >= MAX_RETRIES {
+			return nil, nil, &apiError{Status: resp.StatusCode, Body: string(data)}
+		}
+
+		delay := retry_delay * time.Duration(1<<i)
+		seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if err == nil {
+			delay = time.Duration(seconds) * time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func formatCursor(ts time.Time) string {
+	return ts.UTC().Format(CURSOR_FORMAT)
+}
+
+func getTimeRange(ctx context.Context, scope vfilter.Scope,
+	start_time, end_time vfilter.Any) (start, end time.Time, err error) {
+	if !utils.IsNil(start_time) {
+		start, err = functions.TimeFromAny(ctx, scope, start_time)
+		if err != nil {
+			return start, end, err
+		}
+	}
+
+	if !utils.IsNil(end_time) {
+		end, err = functions.TimeFromAny(ctx, scope, end_time)
+	}
+	return start, end, err
+}
+
+// Named cursors are stored in the server metadata.
+func cursorKey(name string) string {
+	return "m365_cursor_" + name
+}
+
+func loadCursor(ctx context.Context, scope vfilter.Scope, name string) (string, error) {
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		return "", errors.New("Command can only run on the server")
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, err := client_info_manager.GetMetadata(ctx, "server")
+	if err != nil {
+		return "", err
+	}
+
+	cursor, _ := metadata.GetString(cursorKey(name))
+	return cursor, nil
+}
+
+func saveCursor(ctx context.Context, scope vfilter.Scope, name, cursor string) error {
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		return errors.New("Command can only run on the server")
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return client_info_manager.SetMetadata(ctx, "server",
+		ordereddict.NewDict().Set(cursorKey(name), cursor),
+		vql_subsystem.GetPrincipal(scope))
+}
+
+// Resolve the starting cursor: An explicit cursor wins over a named
+// one.
+func startCursor(ctx context.Context, scope vfilter.Scope,
+	cursor, cursor_name string) (string, error) {
+	if cursor != "" || cursor_name == "" {
+		return cursor, nil
+	}
+	return loadCursor(ctx, scope, cursor_name)
+}
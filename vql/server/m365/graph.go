@@ -0,0 +1,241 @@
+package m365
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type graphLog struct {
+	path       string
+	time_field string
+	normalize  func(record *ordereddict.Dict) *ordereddict.Dict
+}
+
+var graphLogs = map[string]graphLog{
+	"signins": {
+		path:       "/v1.0/auditLogs/signIns",
+		time_field: "createdDateTime",
+		normalize:  normalizeSignIn,
+	},
+	"directory": {
+		path:       "/v1.0/auditLogs/directoryAudits",
+		time_field: "activityDateTime",
+		normalize:  normalizeDirectoryAudit,
+	},
+	"provisioning": {
+		path:       "/v1.0/auditLogs/provisioning",
+		time_field: "activityDateTime",
+		normalize:  normalizeDirectoryAudit,
+	},
+}
+
+func normalizeSignIn(record *ordereddict.Dict) *ordereddict.Dict {
+	result := "Success"
+	error_code := utils.GetPathString(record, "status", "errorCode")
+	if error_code != "" && error_code != "0" {
+		result = utils.GetPathString(record, "status", "failureReason")
+		if result == "" {
+			result = "Failure"
+		}
+	}
+
+	return ordereddict.NewDict().
+		Set("Service", utils.GetPathString(record, "appDisplayName")).
+		Set("Operation", "SignIn").
+		Set("User", utils.GetPathString(record, "userPrincipalName")).
+		Set("ClientIP", utils.GetPathString(record, "ipAddress")).
+		Set("Result", result).
+		Set("Id", utils.GetPathString(record, "id"))
+}
+
+func normalizeDirectoryAudit(record *ordereddict.Dict) *ordereddict.Dict {
+	user := utils.GetPathString(record, "initiatedBy", "user", "userPrincipalName")
+	if user == "" {
+		user = utils.GetPathString(record, "initiatedBy", "app", "displayName")
+	}
+
+	return ordereddict.NewDict().
+		Set("Service", utils.GetPathString(record, "loggedByService")).
+		Set("Operation", utils.GetPathString(record, "activityDisplayName")).
+		Set("User", user).
+		Set("ClientIP", utils.GetPathString(record, "initiatedBy", "user", "ipAddress")).
+		Set("Result", utils.GetPathString(record, "result")).
+		Set("Id", utils.GetPathString(record, "id"))
+}
+
+// Build the first page url. The cursor is exclusive so records
+// already seen are not returned again.
+func graphQueryURL(log graphLog, start, end time.Time,
+	cursor, filter string) string {
+	var clauses []string
+	if cursor != "" {
+		clauses = append(clauses, fmt.Sprintf("%s gt %s", log.time_field, cursor))
+	} else if !start.IsZero() {
+		clauses = append(clauses, fmt.Sprintf("%s ge %s",
+			log.time_field, start.UTC().Format(time.RFC3339)))
+	}
+
+	if !end.IsZero() {
+		clauses = append(clauses, fmt.Sprintf("%s le %s",
+			log.time_field, end.UTC().Format(time.RFC3339)))
+	}
+
+	if filter != "" {
+		clauses = append(clauses, "("+filter+")")
+	}
+
+	query := url.Values{}
+	if len(clauses) > 0 {
+		query.Set("$filter", strings.Join(clauses, " and "))
+	}
+
+	return graph_url + log.path + "?" + query.Encode()
+}
+
+type graphPage struct {
+	Value    []json.RawMessage `json:"value"`
+	NextLink string            `json:"@odata.nextLink"`
+}
+
+type GraphAuditLogsArgs struct {
+	Log          string      `vfilter:"required,field=log,doc=The log to collect: signins, directory or provisioning."`
+	StartTime    vfilter.Any `vfilter:"optional,field=start_time,doc=Only return records after this time."`
+	EndTime      vfilter.Any `vfilter:"optional,field=end_time,doc=Only return records before this time."`
+	Cursor       string      `vfilter:"optional,field=cursor,doc=Resume after this cursor (from a previous run). Overrides start_time."`
+	CursorName   string      `vfilter:"optional,field=cursor_name,doc=Resume from the cursor saved under this name and save the new cursor when done."`
+	Filter       string      `vfilter:"optional,field=filter,doc=An additional OData filter (e.g. userPrincipalName eq 'bob@example.com')."`
+	TenantId     string      `vfilter:"optional,field=tenant_id,doc=The tenant id (default from M365_CREDENTIALS)."`
+	ClientId     string      `vfilter:"optional,field=client_id,doc=The app registration's client id (default from M365_CREDENTIALS)."`
+	ClientSecret string      `vfilter:"optional,field=client_secret,doc=The app registration's client secret (default from M365_CREDENTIALS)."`
+}
+
+type GraphAuditLogsPlugin struct{}
+
+func (self GraphAuditLogsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "graph_audit_logs")
+
+		err := vql_subsystem.CheckAccess(scope, acls.COLLECT_SERVER)
+		if err != nil {
+			scope.Log("graph_audit_logs: %v", err)
+			return
+		}
+
+		arg := &GraphAuditLogsArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("graph_audit_logs: %v", err)
+			return
+		}
+
+		log, pres := graphLogs[strings.ToLower(arg.Log)]
+		if !pres {
+			scope.Log("graph_audit_logs: Unknown log %v", arg.Log)
+			return
+		}
+
+		start, end, err := getTimeRange(ctx, scope, arg.StartTime, arg.EndTime)
+		if err != nil {
+			scope.Log("graph_audit_logs: %v", err)
+			return
+		}
+
+		creds, err := getCredentials(scope,
+			arg.TenantId, arg.ClientId, arg.ClientSecret)
+		if err != nil {
+			scope.Log("graph_audit_logs: %v", err)
+			return
+		}
+
+		cursor, err := startCursor(ctx, scope, arg.Cursor, arg.CursorName)
+		if err != nil {
+			scope.Log("graph_audit_logs: %v", err)
+			return
+		}
+
+		// Records are returned newest first so the cursor can only be
+		// saved once all pages are read.
+		last_cursor := cursor
+		client := newAPIClient(ctx, creds, graph_url)
+		next := graphQueryURL(log, start, end, cursor, arg.Filter)
+		for next != "" {
+			data, _, err := client.do(ctx, "GET", next)
+			if err != nil {
+				scope.Log("graph_audit_logs: %v", err)
+				return
+			}
+
+			page := &graphPage{}
+			err = json.Unmarshal(data, page)
+			if err != nil {
+				scope.Log("graph_audit_logs: %v", err)
+				return
+			}
+
+			for _, item := range page.Value {
+				record := ordereddict.NewDict()
+				err = record.UnmarshalJSON(item)
+				if err != nil {
+					continue
+				}
+
+				ts := utils.GetPathTime(record, log.time_field)
+				row_cursor := formatCursor(ts)
+				if row_cursor > last_cursor {
+					last_cursor = row_cursor
+				}
+
+				row := ordereddict.NewDict().Set("Time", ts)
+				row.MergeFrom(log.normalize(record))
+				row.Set("Cursor", row_cursor).
+					Set("Record", record)
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- row:
+				}
+			}
+			next = page.NextLink
+		}
+
+		if arg.CursorName != "" && last_cursor != cursor {
+			err = saveCursor(ctx, scope, arg.CursorName, last_cursor)
+			if err != nil {
+				scope.Log("graph_audit_logs: %v", err)
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self GraphAuditLogsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "graph_audit_logs",
+		Doc:      "Collect Entra ID sign-in, directory and provisioning logs from the Microsoft Graph API.",
+		ArgType:  type_map.AddType(scope, &GraphAuditLogsArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.COLLECT_SERVER).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&GraphAuditLogsPlugin{})
+}
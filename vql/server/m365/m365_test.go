@@ -0,0 +1,200 @@
+package m365
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type fakeM365 struct {
+	mu            sync.Mutex
+	filters       []string
+	subscriptions []string
+	subscribed    map[string]bool
+	server        *httptest.Server
+}
+
+func (self *fakeM365) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if r.URL.Path == "/tenant/oauth2/v2.0/token" {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1.0/auditLogs/signIns":
+		self.filters = append(self.filters, r.URL.Query().Get("$filter"))
+		if r.URL.Query().Get("page") == "" {
+			fmt.Fprintf(w, `{"value":[{"id":"s1","createdDateTime":"2023-01-02T03:04:05Z","userPrincipalName":"alice@example.com","appDisplayName":"Office 365 Exchange Online","ipAddress":"198.51.100.1","status":{"errorCode":0}}],
+ "@odata.nextLink":"%s/v1.0/auditLogs/signIns?page=2"}`, self.server.URL)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"id":"s0","createdDateTime":"2023-01-01T00:00:00Z","userPrincipalName":"bob@example.com","ipAddress":"203.0.113.1","status":{"errorCode":50126,"failureReason":"Invalid username or password."}}]}`)
+
+	case "/api/v1.0/tenant/activity/feed/subscriptions/start":
+		self.subscriptions = append(self.subscriptions,
+			r.URL.Query().Get("contentType"))
+		self.subscribed[r.URL.Query().Get("contentType")] = true
+		fmt.Fprint(w, `{}`)
+
+	case "/api/v1.0/tenant/activity/feed/subscriptions/content":
+		if !self.subscribed[r.URL.Query().Get("contentType")] {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"code":"AF20022","message":"No subscription found for the specified content type"}}`)
+			return
+		}
+
+		if r.URL.Query().Get("contentType") != "Audit.Exchange" {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+
+		// Blobs are not listed in order.
+		fmt.Fprintf(w, `[
+ {"contentType":"Audit.Exchange","contentId":"c2","contentUri":"%s/content/c2","contentCreated":"2023-01-02T02:00:00.000Z"},
+ {"contentType":"Audit.Exchange","contentId":"c1","contentUri":"%s/content/c1","contentCreated":"2023-01-02T01:00:00.000Z"}]`,
+			self.server.URL, self.server.URL)
+
+	case "/content/c1":
+		fmt.Fprint(w, `[{"Id":"r1","CreationTime":"2023-01-02T00:30:00","Operation":"New-InboxRule","Workload":"Exchange","UserId":"alice@example.com","ClientIP":"198.51.100.1:1234","ResultStatus":"True"}]`)
+
+	case "/content/c2":
+		fmt.Fprint(w, `[{"Id":"r2","CreationTime":"2023-01-02T01:30:00","Operation":"MailItemsAccessed","Workload":"Exchange","UserId":"alice@example.com","ClientIPAddress":"198.51.100.1","ResultStatus":"Succeeded"}]`)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeM365(t *testing.T) *fakeM365 {
+	result := &fakeM365{subscribed: make(map[string]bool)}
+	result.server = httptest.NewServer(result)
+	t.Cleanup(result.server.Close)
+
+	login_url = result.server.URL
+	graph_url = result.server.URL
+	manage_url = result.server.URL
+	retry_delay = time.Millisecond
+
+	return result
+}
+
+func makeScope() vfilter.Scope {
+	return vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}).
+		Set(M365_CREDENTIALS, ordereddict.NewDict().
+			Set("tenant_id", "tenant").
+			Set("client_id", "client").
+			Set("client_secret", "secret")))
+}
+
+func collect(plugin vfilter.PluginGeneratorInterface,
+	args *ordereddict.Dict) []*ordereddict.Dict {
+	ctx := context.Background()
+	scope := makeScope()
+	defer scope.Close()
+
+	var result []*ordereddict.Dict
+	for row := range plugin.Call(ctx, scope, args) {
+		result = append(result, row.(*ordereddict.Dict))
+	}
+	return result
+}
+
+func TestGraphSignIns(t *testing.T) {
+	fake := newFakeM365(t)
+
+	rows := collect(GraphAuditLogsPlugin{}, ordereddict.NewDict().
+		Set("log", "signins").
+		Set("cursor", "2022-12-31T00:00:00.0000000Z"))
+	assert.Equal(t, 2, len(rows))
+
+	// Both pages are read.
+	user, _ := rows[0].GetString("User")
+	assert.Equal(t, "alice@example.com", user)
+
+	result, _ := rows[0].GetString("Result")
+	assert.Equal(t, "Success", result)
+
+	result, _ = rows[1].GetString("Result")
+	assert.Equal(t, "Invalid username or password.", result)
+
+	cursor, _ := rows[0].GetString("Cursor")
+	assert.Equal(t, "2023-01-02T03:04:05.0000000Z", cursor)
+
+	assert.Equal(t, "createdDateTime gt 2022-12-31T00:00:00.0000000Z",
+		fake.filters[0])
+}
+
+func TestManagementActivity(t *testing.T) {
+	fake := newFakeM365(t)
+
+	now := time.Now().UTC()
+	args := ordereddict.NewDict().
+		Set("content_type", []string{"Audit.Exchange", "Audit.General"}).
+		Set("start_time", now.Add(-time.Hour))
+
+	// The missing subscriptions are started. There is no content
+	// yet.
+	rows := collect(O365AuditLogsPlugin{}, args)
+	assert.Equal(t, 0, len(rows))
+	assert.Equal(t, []string{"Audit.Exchange", "Audit.General"},
+		fake.subscriptions)
+
+	rows = collect(O365AuditLogsPlugin{}, args)
+
+	// Content is read in the order it was created.
+	assert.Equal(t, 2, len(rows))
+
+	id, _ := rows[0].GetString("Id")
+	assert.Equal(t, "r1", id)
+
+	operation, _ := rows[1].GetString("Operation")
+	assert.Equal(t, "MailItemsAccessed", operation)
+
+	client_ip, _ := rows[1].GetString("ClientIP")
+	assert.Equal(t, "198.51.100.1", client_ip)
+
+	ts, _ := rows[0].Get("Time")
+	assert.Equal(t, time.Date(2023, 1, 2, 0, 30, 0, 0, time.UTC), ts)
+
+	cursor, _ := rows[1].GetString("Cursor")
+	assert.Equal(t, "2023-01-02T02:00:00.0000000Z", cursor)
+
+	// Content at or before the cursor is skipped.
+	rows = collect(O365AuditLogsPlugin{}, ordereddict.NewDict().
+		Set("content_type", []string{"Audit.Exchange"}).
+		Set("cursor", formatCursor(now.Add(-time.Hour).Truncate(time.Second))))
+	assert.Equal(t, 0, len(rows))
+}
+
+func TestCredentials(t *testing.T) {
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	_, err := getCredentials(scope, "tenant", "", "")
+	assert.Error(t, err)
+
+	creds, err := getCredentials(makeScope(), "other", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "other", creds.TenantId)
+	assert.Equal(t, "secret", creds.ClientSecret)
+}
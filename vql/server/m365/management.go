@@ -0,0 +1,322 @@
+package m365
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	// The API only keeps content for 7 days and lists at most 24
+	// hours at a time.
+	MAX_CONTENT_AGE = 7 * 24 * time.Hour
+	CONTENT_WINDOW  = 24 * time.Hour
+
+	MANAGEMENT_TIME_FORMAT = "2006-01-02T15:04:05"
+)
+
+var (
+	// DLP.All needs an additional permission so it must be asked
+	// for explicitly.
+	defaultContentTypes = []string{
+		"Audit.AzureActiveDirectory",
+		"Audit.Exchange",
+		"Audit.SharePoint",
+		"Audit.General",
+	}
+)
+
+type contentBlob struct {
+	ContentType    string `json:"contentType"`
+	ContentId      string `json:"contentId"`
+	ContentUri     string `json:"contentUri"`
+	ContentCreated string `json:"contentCreated"`
+
+	created time.Time
+}
+
+type managementAPI struct {
+	client    *apiClient
+	tenant_id string
+}
+
+func (self *managementAPI) url(path string, query url.Values) string {
+	query.Set("PublisherIdentifier", self.tenant_id)
+	return fmt.Sprintf("%s/api/v1.0/%s/activity/feed/%s?%s",
+		manage_url, self.tenant_id, path, query.Encode())
+}
+
+// Content is only recorded for content types the tenant has a
+// subscription for.
+func (self *managementAPI) startSubscription(
+	ctx context.Context, content_type string) error {
+	_, _, err := self.client.do(ctx, "POST", self.url("subscriptions/start",
+		url.Values{"contentType": {content_type}}))
+	return err
+}
+
+func isNoSubscription(err error) bool {
+	api_err := &apiError{}
+	return errors.As(err, &api_err) && strings.Contains(api_err.Body, "AF20022")
+}
+
+func (self *managementAPI) listContent(ctx context.Context,
+	content_type string, start, end time.Time) ([]*contentBlob, error) {
+	var result []*contentBlob
+
+	next := self.url("subscriptions/content", url.Values{
+		"contentType": {content_type},
+		"startTime":   {start.UTC().Format(MANAGEMENT_TIME_FORMAT)},
+		"endTime":     {end.UTC().Format(MANAGEMENT_TIME_FORMAT)},
+	})
+
+	for next != "" {
+		data, headers, err := self.client.do(ctx, "GET", next)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []*contentBlob
+		err = json.Unmarshal(data, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blob := range page {
+			blob.created, _ = time.Parse(time.RFC3339Nano, blob.ContentCreated)
+			if blob.created.IsZero() {
+				blob.created, _ = time.Parse(MANAGEMENT_TIME_FORMAT,
+					blob.ContentCreated)
+			}
+			result = append(result, blob)
+		}
+
+		next = headers.Get("NextPageUri")
+	}
+
+	return result, nil
+}
+
+func (self *managementAPI) getContent(ctx context.Context,
+	blob *contentBlob) ([]*ordereddict.Dict, error) {
+	data, _, err := self.client.do(ctx, "GET", blob.ContentUri)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []json.RawMessage
+	err = json.Unmarshal(data, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*ordereddict.Dict, 0, len(items))
+	for _, item := range items {
+		record := ordereddict.NewDict()
+		err = record.UnmarshalJSON(item)
+		if err == nil {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func normalizeManagementRecord(record *ordereddict.Dict) *ordereddict.Dict {
+	client_ip := utils.GetPathString(record, "ClientIP")
+	if client_ip == "" {
+		client_ip = utils.GetPathString(record, "ClientIPAddress")
+	}
+
+	return ordereddict.NewDict().
+		Set("Time", utils.GetPathTime(record, "CreationTime")).
+		Set("Service", utils.GetPathString(record, "Workload")).
+		Set("Operation", utils.GetPathString(record, "Operation")).
+		Set("User", utils.GetPathString(record, "UserId")).
+		Set("ClientIP", client_ip).
+		Set("Result", utils.GetPathString(record, "ResultStatus")).
+		Set("Id", utils.GetPathString(record, "Id"))
+}
+
+type O365AuditLogsArgs struct {
+	ContentTypes []string    `vfilter:"optional,field=content_type,doc=The content types to collect (default Audit.AzureActiveDirectory, Audit.Exchange, Audit.SharePoint and Audit.General)."`
+	StartTime    vfilter.Any `vfilter:"optional,field=start_time,doc=Only return content created after this time (default 24 hours ago, at most 7 days ago)."`
+	EndTime      vfilter.Any `vfilter:"optional,field=end_time,doc=Only return content created before this time (default now)."`
+	Cursor       string      `vfilter:"optional,field=cursor,doc=Resume after this cursor (from a previous run). Overrides start_time."`
+	CursorName   string      `vfilter:"optional,field=cursor_name,doc=Resume from the cursor saved under this name and save the new cursor when done."`
+	TenantId     string      `vfilter:"optional,field=tenant_id,doc=The tenant id (default from M365_CREDENTIALS)."`
+	ClientId     string      `vfilter:"optional,field=client_id,doc=The app registration's client id (default from M365_CREDENTIALS)."`
+	ClientSecret string      `vfilter:"optional,field=client_secret,doc=The app registration's client secret (default from M365_CREDENTIALS)."`
+}
+
+type O365AuditLogsPlugin struct{}
+
+func (self O365AuditLogsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "o365_audit_logs")
+
+		err := vql_subsystem.CheckAccess(scope, acls.COLLECT_SERVER)
+		if err != nil {
+			scope.Log("o365_audit_logs: %v", err)
+			return
+		}
+
+		arg := &O365AuditLogsArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("o365_audit_logs: %v", err)
+			return
+		}
+
+		if len(arg.ContentTypes) == 0 {
+			arg.ContentTypes = defaultContentTypes
+		}
+
+		start, end, err := getTimeRange(ctx, scope, arg.StartTime, arg.EndTime)
+		if err != nil {
+			scope.Log("o365_audit_logs: %v", err)
+			return
+		}
+
+		creds, err := getCredentials(scope,
+			arg.TenantId, arg.ClientId, arg.ClientSecret)
+		if err != nil {
+			scope.Log("o365_audit_logs: %v", err)
+			return
+		}
+
+		cursor, err := startCursor(ctx, scope, arg.Cursor, arg.CursorName)
+		if err != nil {
+			scope.Log("o365_audit_logs: %v", err)
+			return
+		}
+
+		// The cursor is the creation time of the last content blob
+		// read.
+		now := time.Now().UTC()
+		if cursor != "" {
+			start, err = time.Parse(time.RFC3339Nano, cursor)
+			if err != nil {
+				scope.Log("o365_audit_logs: Invalid cursor %v", cursor)
+				return
+			}
+		}
+
+		if end.IsZero() || end.After(now) {
+			end = now
+		}
+
+		if start.IsZero() {
+			start = end.Add(-CONTENT_WINDOW)
+		}
+
+		oldest := now.Add(-MAX_CONTENT_AGE).Add(time.Minute)
+		if start.Before(oldest) {
+			scope.Log("o365_audit_logs: Content is only kept for 7 days, starting at %v",
+				oldest)
+			start = oldest
+		}
+
+		api := &managementAPI{
+			client:    newAPIClient(ctx, creds, manage_url),
+			tenant_id: creds.TenantId,
+		}
+
+		last_cursor := cursor
+		for window_start := start; window_start.Before(end); window_start = window_start.Add(CONTENT_WINDOW) {
+			window_end := window_start.Add(CONTENT_WINDOW)
+			if window_end.After(end) {
+				window_end = end
+			}
+
+			// Read the content of all types in order of creation
+			// so the cursor only ever moves forward.
+			var blobs []*contentBlob
+			for _, content_type := range arg.ContentTypes {
+				content, err := api.listContent(ctx, content_type,
+					window_start, window_end)
+				if isNoSubscription(err) {
+					scope.Log("o365_audit_logs: Starting subscription for %v. Only content created from now on will be available.",
+						content_type)
+					err = api.startSubscription(ctx, content_type)
+				}
+				if err != nil {
+					scope.Log("o365_audit_logs: %v: %v", content_type, err)
+					return
+				}
+				blobs = append(blobs, content...)
+			}
+
+			sort.Slice(blobs, func(i, j int) bool {
+				return blobs[i].created.Before(blobs[j].created)
+			})
+
+			for _, blob := range blobs {
+				blob_cursor := formatCursor(blob.created)
+				if cursor != "" && blob_cursor <= cursor {
+					continue
+				}
+
+				records, err := api.getContent(ctx, blob)
+				if err != nil {
+					scope.Log("o365_audit_logs: %v: %v", blob.ContentId, err)
+					return
+				}
+
+				for _, record := range records {
+					row := normalizeManagementRecord(record).
+						Set("ContentType", blob.ContentType).
+						Set("Cursor", blob_cursor).
+						Set("Record", record)
+
+					select {
+					case <-ctx.Done():
+						return
+					case output_chan <- row:
+					}
+				}
+
+				last_cursor = blob_cursor
+			}
+		}
+
+		if arg.CursorName != "" && last_cursor != cursor {
+			err = saveCursor(ctx, scope, arg.CursorName, last_cursor)
+			if err != nil {
+				scope.Log("o365_audit_logs: %v", err)
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self O365AuditLogsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "o365_audit_logs",
+		Doc:      "Collect the Microsoft 365 unified audit log from the Office 365 Management Activity API.",
+		ArgType:  type_map.AddType(scope, &O365AuditLogsArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.COLLECT_SERVER).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&O365AuditLogsPlugin{})
+}
@@ -0,0 +1,156 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/masking"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type MaskingPolicyFunctionArgs struct {
+	Name        string `vfilter:"required,field=name,doc=The name of the policy."`
+	Artifact    string `vfilter:"optional,field=artifact,doc=A regex selecting the artifacts the policy applies to (default all results)."`
+	Column      string `vfilter:"optional,field=column,doc=Mask columns matching this regex (default all columns)."`
+	Pattern     string `vfilter:"optional,field=pattern,doc=Only mask the parts of values matching this regex (default the entire value)."`
+	Replacement string `vfilter:"optional,field=replacement,doc=Replace masked data with this (default [REDACTED])."`
+	Description string `vfilter:"optional,field=description,doc=A description of the policy."`
+	Delete      bool   `vfilter:"optional,field=delete,doc=Delete the policy."`
+}
+
+type MaskingPolicyFunction struct{}
+
+func (self MaskingPolicyFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+	if err != nil {
+		scope.Log("masking_policy: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &MaskingPolicyFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("masking_policy: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("masking_policy: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	principal := vql_subsystem.GetPrincipal(scope)
+
+	if arg.Delete {
+		err = masking.Delete(ctx, config_obj, principal, arg.Name)
+		if err != nil {
+			scope.Log("masking_policy: %v", err)
+			return vfilter.Null{}
+		}
+		return arg.Name
+	}
+
+	policy := &masking.Policy{
+		Name:        arg.Name,
+		Description: arg.Description,
+		Artifact:    arg.Artifact,
+		Column:      arg.Column,
+		Pattern:     arg.Pattern,
+		Replacement: arg.Replacement,
+	}
+
+	err = masking.Set(ctx, config_obj, principal, policy)
+	if err != nil {
+		scope.Log("masking_policy: %v", err)
+		return vfilter.Null{}
+	}
+
+	return maskingPolicyRow(policy)
+}
+
+func (self MaskingPolicyFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "masking_policy",
+		Doc:      "Create, replace or delete a policy masking sensitive data in results.",
+		ArgType:  type_map.AddType(scope, &MaskingPolicyFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.SERVER_ADMIN).Build(),
+	}
+}
+
+type MaskingPoliciesPlugin struct{}
+
+func (self MaskingPoliciesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("masking_policies: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("masking_policies: Command can only run on the server")
+			return
+		}
+
+		policies, err := masking.List(config_obj)
+		if err != nil {
+			scope.Log("masking_policies: %v", err)
+			return
+		}
+
+		for _, policy := range policies {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- maskingPolicyRow(policy):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self MaskingPoliciesPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "masking_policies",
+		Doc:      "List the policies masking sensitive data in results.",
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func maskingPolicyRow(policy *masking.Policy) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Name", policy.Name).
+		Set("Description", policy.Description).
+		Set("Artifact", policy.Artifact).
+		Set("Column", policy.Column).
+		Set("Pattern", policy.Pattern).
+		Set("Replacement", policy.Replacement).
+		Set("Principal", policy.Principal).
+		Set("Modified", unixTime(policy.Modified))
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&MaskingPolicyFunction{})
+	vql_subsystem.RegisterPlugin(&MaskingPoliciesPlugin{})
+}
@@ -0,0 +1,244 @@
+package notebooks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type QueryHistoryArgs struct {
+	User      string    `vfilter:"optional,field=user,doc=The user whose history to show (default the current user). Requires SERVER_ADMIN for other users."`
+	Search    string    `vfilter:"optional,field=search,doc=Only show queries matching this regex."`
+	StartTime time.Time `vfilter:"optional,field=start_time,doc=Only show queries run after this time."`
+	EndTime   time.Time `vfilter:"optional,field=end_time,doc=Only show queries run before this time."`
+	Id        string    `vfilter:"optional,field=id,doc=Only show the query with this id."`
+	Limit     int64     `vfilter:"optional,field=limit,doc=Show at most this many queries."`
+}
+
+type QueryHistoryPlugin struct{}
+
+func (self *QueryHistoryPlugin) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		arg := &QueryHistoryArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("query_history: %v", err)
+			return
+		}
+
+		principal := vql_subsystem.GetPrincipal(scope)
+		if arg.User == "" {
+			arg.User = principal
+		}
+
+		// Reviewing other users' activity is an administrative
+		// task.
+		permission := acls.READ_RESULTS
+		if arg.User != principal {
+			permission = acls.SERVER_ADMIN
+		}
+
+		err = vql_subsystem.CheckAccess(scope, permission)
+		if err != nil {
+			scope.Log("query_history: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("query_history: Command can only run on the server")
+			return
+		}
+
+		options := services.QueryHistoryOptions{
+			Search: arg.Search,
+			Id:     arg.Id,
+			Limit:  int(arg.Limit),
+		}
+
+		if !arg.StartTime.IsZero() {
+			options.Start = arg.StartTime.Unix()
+		}
+
+		if !arg.EndTime.IsZero() {
+			options.End = arg.EndTime.Unix()
+		}
+
+		history, err := getQueryHistory(ctx, config_obj, arg.User, options)
+		if err != nil {
+			scope.Log("query_history: %v", err)
+			return
+		}
+
+		for _, entry := range history {
+			timestamp, _ := entry.GetInt64("Timestamp")
+			entry.Set("Timestamp", time.Unix(timestamp, 0).UTC())
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- entry:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self QueryHistoryPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "query_history",
+		Doc:      "Search the VQL queries run in notebooks, most recent first.",
+		ArgType:  type_map.AddType(scope, &QueryHistoryArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func getQueryHistory(ctx context.Context,
+	config_obj *config_proto.Config, username string,
+	options services.QueryHistoryOptions) ([]*ordereddict.Dict, error) {
+	notebook_manager, err := services.GetNotebookManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return notebook_manager.QueryHistory(ctx, username, options)
+}
+
+type QueryHistoryRerunArgs struct {
+	Id         string `vfilter:"required,field=id,doc=The id of the query in the history."`
+	User       string `vfilter:"optional,field=user,doc=The user who ran the query (default the current user)."`
+	NotebookId string `vfilter:"optional,field=notebook_id,doc=The notebook to add the cell to (default the notebook the query was run in)."`
+}
+
+type QueryHistoryRerunFunction struct{}
+
+func (self *QueryHistoryRerunFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.NOTEBOOK_EDITOR)
+	if err != nil {
+		scope.Log("query_history_rerun: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &QueryHistoryRerunArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("query_history_rerun: %v", err)
+		return vfilter.Null{}
+	}
+
+	principal := vql_subsystem.GetPrincipal(scope)
+	if arg.User == "" {
+		arg.User = principal
+	}
+
+	if arg.User != principal {
+		err = vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+		if err != nil {
+			scope.Log("query_history_rerun: %v", err)
+			return vfilter.Null{}
+		}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("query_history_rerun: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	cell, err := rerunQuery(ctx, config_obj, principal, arg)
+	if err != nil {
+		scope.Log("query_history_rerun: %v", err)
+		return vfilter.Null{}
+	}
+
+	return cell
+}
+
+// Add the query as a new cell at the end of the notebook. The new
+// cell runs as the principal.
+func rerunQuery(ctx context.Context, config_obj *config_proto.Config,
+	principal string, arg *QueryHistoryRerunArgs) (*ordereddict.Dict, error) {
+	history, err := getQueryHistory(ctx, config_obj, arg.User,
+		services.QueryHistoryOptions{Id: arg.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return nil, errors.New("Query not found")
+	}
+	entry := history[0]
+
+	notebook_id := arg.NotebookId
+	if notebook_id == "" {
+		notebook_id, _ = entry.GetString("NotebookId")
+	}
+
+	notebook_manager, err := services.GetNotebookManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	notebook, err := notebook_manager.GetNotebook(ctx, notebook_id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if !notebook_manager.CheckNotebookAccess(notebook, principal) {
+		return nil, errors.New("Notebook is not shared with user")
+	}
+
+	query, _ := entry.GetString("Query")
+	notebook, err = notebook_manager.NewNotebookCell(ctx,
+		&api_proto.NotebookCellRequest{
+			NotebookId: notebook_id,
+			Input:      query,
+			Type:       "vql",
+		}, principal)
+
+	// The cell is still added if the query fails.
+	if notebook == nil {
+		return nil, err
+	}
+
+	return ordereddict.NewDict().
+		Set("NotebookId", notebook_id).
+		Set("CellId", notebook.LatestCellId).
+		Set("Query", query), nil
+}
+
+func (self QueryHistoryRerunFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "query_history_rerun",
+		Doc:      "Run a query from the query history again in a new notebook cell.",
+		ArgType:  type_map.AddType(scope, &QueryHistoryRerunArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.NOTEBOOK_EDITOR).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&QueryHistoryPlugin{})
+	vql_subsystem.RegisterFunction(&QueryHistoryRerunFunction{})
+}
@@ -0,0 +1,106 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+	"os"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/reporting/parquet"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type ParquetWriteFunctionArgs struct {
+	Filename     *accessors.OSPath   `vfilter:"required,field=filename,doc=The Parquet file to write"`
+	Accessor     string              `vfilter:"optional,field=accessor,doc=The accessor to use (only file is supported)"`
+	Query        vfilter.StoredQuery `vfilter:"required,field=query,doc=The query to write into the file."`
+	RowGroupSize int64               `vfilter:"optional,field=row_group_size,doc=Number of rows in each row group (default 10000). The schema is inferred from the first row group."`
+}
+
+type ParquetWriteFunction struct{}
+
+func (self ParquetWriteFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	arg := &ParquetWriteFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("parquet_write: %v", err)
+		return vfilter.Null{}
+	}
+
+	switch arg.Accessor {
+	case "", "auto", "file":
+	default:
+		scope.Log("parquet_write: Unsupported accessor for writing %v",
+			arg.Accessor)
+		return vfilter.Null{}
+	}
+
+	err = vql_subsystem.CheckAccess(scope, acls.FILESYSTEM_WRITE)
+	if err != nil {
+		scope.Log("parquet_write: %v", err)
+		return vfilter.Null{}
+	}
+
+	underlying_file, err := accessors.GetUnderlyingAPIFilename(
+		arg.Accessor, scope, arg.Filename)
+	if err != nil {
+		scope.Log("parquet_write: %v", err)
+		return vfilter.Null{}
+	}
+
+	file, err := os.OpenFile(underlying_file,
+		os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		scope.Log("parquet_write: Unable to open file %v: %v",
+			arg.Filename, err)
+		return vfilter.Null{}
+	}
+	defer file.Close()
+
+	writer := parquet.NewWriter(file, json.DefaultEncOpts())
+	writer.SetRowGroupSize(int(arg.RowGroupSize))
+
+	count := 0
+	for row := range arg.Query.Eval(ctx, scope) {
+		err := writer.Write(vfilter.RowToDict(ctx, scope, row))
+		if err != nil {
+			scope.Log("parquet_write: %v", err)
+			return vfilter.Null{}
+		}
+		count++
+	}
+
+	err = writer.Close()
+	if err != nil {
+		scope.Log("parquet_write: %v", err)
+		return vfilter.Null{}
+	}
+
+	return ordereddict.NewDict().
+		Set("Filename", underlying_file).
+		Set("Rows", count)
+}
+
+func (self ParquetWriteFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "parquet_write",
+		Doc:      "Write the results of a query into a Parquet file.",
+		ArgType:  type_map.AddType(scope, &ParquetWriteFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_WRITE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&ParquetWriteFunction{})
+}
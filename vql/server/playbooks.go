@@ -0,0 +1,140 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/playbooks"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type PlaybookRunsPlugin struct{}
+
+func (self PlaybookRunsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("playbook_runs: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("playbook_runs: Command can only run on the server")
+			return
+		}
+
+		service, err := playbooks.GetPlaybookService(config_obj)
+		if err != nil {
+			scope.Log("playbook_runs: %v", err)
+			return
+		}
+
+		for _, run := range service.List() {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- playbookRunRow(run):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self PlaybookRunsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "playbook_runs",
+		Doc:      "List the responses started by playbooks, most recent first.",
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type PlaybookApproveFunctionArgs struct {
+	Id   string `vfilter:"required,field=id,doc=The id of the pending run."`
+	Deny bool   `vfilter:"optional,field=deny,doc=Deny the run instead of approving it."`
+}
+
+type PlaybookApproveFunction struct{}
+
+func (self PlaybookApproveFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("playbook_approve: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &PlaybookApproveFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("playbook_approve: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("playbook_approve: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	service, err := playbooks.GetPlaybookService(config_obj)
+	if err != nil {
+		scope.Log("playbook_approve: %v", err)
+		return vfilter.Null{}
+	}
+
+	run, err := service.Decide(ctx, vql_subsystem.GetPrincipal(scope),
+		arg.Id, !arg.Deny)
+	if err != nil {
+		scope.Log("playbook_approve: %v", err)
+		return vfilter.Null{}
+	}
+
+	return playbookRunRow(run)
+}
+
+func (self PlaybookApproveFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "playbook_approve",
+		Doc:      "Approve or deny a playbook response awaiting approval.",
+		ArgType:  type_map.AddType(scope, &PlaybookApproveFunctionArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.COLLECT_CLIENT).Build(),
+	}
+}
+
+func playbookRunRow(run *playbooks.Run) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("RunId", run.Id).
+		Set("Playbook", run.Playbook).
+		Set("ClientId", run.ClientId).
+		Set("State", run.State).
+		Set("Created", unixTime(run.Created)).
+		Set("Approver", run.Approver).
+		Set("Decided", unixTime(run.Decided)).
+		Set("FlowIds", run.FlowIds).
+		Set("Labels", run.Labels).
+		Set("Error", run.Error).
+		Set("Event", run.Event)
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&PlaybookRunsPlugin{})
+	vql_subsystem.RegisterFunction(&PlaybookApproveFunction{})
+}
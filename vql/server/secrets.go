@@ -0,0 +1,206 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/services/secrets"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type SecretFunctionArgs struct {
+	Name string `vfilter:"required,field=name,doc=The name of the secret."`
+}
+
+type SecretFunction struct{}
+
+func (self SecretFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_SERVER)
+	if err != nil {
+		scope.Log("secret: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &SecretFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("secret: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("secret: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	// Only collections which redact their output may see secrets
+	// (e.g. not notebooks).
+	redactor_any, pres := scope.Resolve(constants.SCOPE_SECRETS)
+	redactor, ok := redactor_any.(*secrets.Redactor)
+	if !pres || !ok {
+		scope.Log("secret: Secrets are only available to server artifacts")
+		return vfilter.Null{}
+	}
+
+	value, err := secrets.GetValue(config_obj,
+		vql_subsystem.GetPrincipal(scope), arg.Name)
+	if err != nil {
+		scope.Log("secret: %v", err)
+		return vfilter.Null{}
+	}
+
+	redactor.Add(value)
+	return value
+}
+
+func (self SecretFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "secret",
+		Doc:      "Get the value of a secret in a server artifact.",
+		ArgType:  type_map.AddType(scope, &SecretFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.COLLECT_SERVER).Build(),
+	}
+}
+
+type SetSecretFunctionArgs struct {
+	Name        string   `vfilter:"required,field=name,doc=The name of the secret."`
+	Value       string   `vfilter:"optional,field=value,doc=The secret value. If not set the existing value is kept."`
+	Description string   `vfilter:"optional,field=description,doc=A description of the secret."`
+	Users       []string `vfilter:"optional,field=users,doc=If set only these users may use the secret."`
+	Delete      bool     `vfilter:"optional,field=delete,doc=Delete the secret."`
+}
+
+type SetSecretFunction struct{}
+
+func (self SetSecretFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+	if err != nil {
+		scope.Log("set_secret: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &SetSecretFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("set_secret: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("set_secret: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	principal := vql_subsystem.GetPrincipal(scope)
+
+	if arg.Delete {
+		err = secrets.Delete(ctx, config_obj, principal, arg.Name)
+		if err != nil {
+			scope.Log("set_secret: %v", err)
+			return vfilter.Null{}
+		}
+		return arg.Name
+	}
+
+	secret, err := secrets.Set(ctx, config_obj, principal,
+		arg.Name, arg.Description, arg.Value, arg.Users)
+	if err != nil {
+		scope.Log("set_secret: %v", err)
+		return vfilter.Null{}
+	}
+
+	return secretRow(secret)
+}
+
+func (self SetSecretFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "set_secret",
+		Doc:      "Store or delete a secret used by server artifacts.",
+		ArgType:  type_map.AddType(scope, &SetSecretFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.SERVER_ADMIN).Build(),
+	}
+}
+
+type SecretsPlugin struct{}
+
+func (self SecretsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.COLLECT_SERVER)
+		if err != nil {
+			scope.Log("secrets: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("secrets: Command can only run on the server")
+			return
+		}
+
+		items, err := secrets.List(config_obj)
+		if err != nil {
+			scope.Log("secrets: %v", err)
+			return
+		}
+
+		for _, secret := range items {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- secretRow(secret):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self SecretsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "secrets",
+		Doc:      "List the secrets available to server artifacts (without their values).",
+		Metadata: vql.VQLMetadata().Permissions(acls.COLLECT_SERVER).Build(),
+	}
+}
+
+// Never includes the value.
+func secretRow(secret *secrets.Secret) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Name", secret.Name).
+		Set("Description", secret.Description).
+		Set("Users", secret.Users).
+		Set("Principal", secret.Principal).
+		Set("Created", unixTime(secret.Created)).
+		Set("Modified", unixTime(secret.Modified))
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&SecretFunction{})
+	vql_subsystem.RegisterFunction(&SetSecretFunction{})
+	vql_subsystem.RegisterPlugin(&SecretsPlugin{})
+}
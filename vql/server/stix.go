@@ -0,0 +1,76 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/reporting/stix"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type StixBundleFunctionArgs struct {
+	Query     vfilter.StoredQuery `vfilter:"required,field=query,doc=The query producing the detections."`
+	Source    string              `vfilter:"optional,field=source,doc=Name of the source of the detections (e.g. the artifact name), used to name indicators."`
+	NameField string              `vfilter:"optional,field=name_field,doc=Name indicators using this field of each row (e.g. the rule name)."`
+	ClientId  string              `vfilter:"optional,field=client_id,doc=The client the detections came from, for rows without a ClientId column."`
+}
+
+type StixBundleFunction struct{}
+
+func (self StixBundleFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+	if err != nil {
+		scope.Log("stix_bundle: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &StixBundleFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("stix_bundle: %v", err)
+		return vfilter.Null{}
+	}
+
+	builder := stix.NewBuilder(arg.Source, json.DefaultEncOpts())
+	builder.SetNameField(arg.NameField)
+	builder.SetClientId(arg.ClientId)
+
+	for row := range arg.Query.Eval(ctx, scope) {
+		err := builder.AddRow(vfilter.RowToDict(ctx, scope, row))
+		if err != nil {
+			scope.Log("stix_bundle: %v", err)
+			return vfilter.Null{}
+		}
+	}
+
+	if builder.Skipped() > 0 {
+		scope.Log("stix_bundle: Skipped %v rows without observables",
+			builder.Skipped())
+	}
+
+	return builder.Bundle()
+}
+
+func (self StixBundleFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "stix_bundle",
+		Doc:      "Export detections and their observables as a STIX 2.1 bundle.",
+		ArgType:  type_map.AddType(scope, &StixBundleFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&StixBundleFunction{})
+}
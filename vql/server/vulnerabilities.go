@@ -0,0 +1,129 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/vulnerabilities"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type VulnerabilitiesPluginArgs struct {
+	ClientId string `vfilter:"optional,field=client_id,doc=Show the vulnerabilities of this client (default the fleet report)."`
+}
+
+type VulnerabilitiesPlugin struct{}
+
+func (self VulnerabilitiesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("vulnerabilities: %v", err)
+			return
+		}
+
+		arg := &VulnerabilitiesPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("vulnerabilities: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("vulnerabilities: Command can only run on the server")
+			return
+		}
+
+		service, err := vulnerabilities.GetVulnerabilityService(config_obj)
+		if err != nil {
+			scope.Log("vulnerabilities: %v", err)
+			return
+		}
+
+		rows, err := service.Report(ctx, arg.ClientId)
+		if err != nil {
+			scope.Log("vulnerabilities: %v", err)
+			return
+		}
+
+		for _, row := range rows {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self VulnerabilitiesPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "vulnerabilities",
+		Doc:      "Show the vulnerabilities found in the software inventory.",
+		ArgType:  type_map.AddType(scope, &VulnerabilitiesPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.READ_RESULTS).Build(),
+	}
+}
+
+type VulnerabilitySyncFunction struct{}
+
+func (self VulnerabilitySyncFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+	if err != nil {
+		scope.Log("vulnerability_sync: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("vulnerability_sync: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	service, err := vulnerabilities.GetVulnerabilityService(config_obj)
+	if err != nil {
+		scope.Log("vulnerability_sync: %v", err)
+		return vfilter.Null{}
+	}
+
+	err = service.Sync(ctx)
+	if err != nil {
+		scope.Log("vulnerability_sync: %v", err)
+	}
+
+	return service.Status()
+}
+
+func (self VulnerabilitySyncFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "vulnerability_sync",
+		Doc:      "Sync the vulnerability databases and match all clients again.",
+		Metadata: vql.VQLMetadata().Permissions(acls.SERVER_ADMIN).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&VulnerabilitiesPlugin{})
+	vql_subsystem.RegisterFunction(&VulnerabilitySyncFunction{})
+}
@@ -0,0 +1,149 @@
+// +build server_vql
+
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type SendWebhookFunctionArgs struct {
+	Destination string      `vfilter:"required,field=destination,doc=The name of the webhook destination in the server config."`
+	Data        vfilter.Any `vfilter:"required,field=data,doc=The data to send (usually a dict)."`
+	Template    string      `vfilter:"optional,field=template,doc=A Go text/template to render the payload with, overriding the destination's template."`
+}
+
+type SendWebhookFunction struct{}
+
+func (self SendWebhookFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_SERVER)
+	if err != nil {
+		scope.Log("send_webhook: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &SendWebhookFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("send_webhook: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("send_webhook: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	service, err := services.GetWebhookService(config_obj)
+	if err != nil {
+		scope.Log("send_webhook: %v", err)
+		return vfilter.Null{}
+	}
+
+	// Expand lazy values before rendering.
+	data := vfilter.RowToDict(ctx, scope, arg.Data)
+
+	id, err := service.Send(arg.Destination, arg.Template, data)
+	if err != nil {
+		scope.Log("send_webhook: %v", err)
+		return vfilter.Null{}
+	}
+
+	return id
+}
+
+func (self SendWebhookFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "send_webhook",
+		Doc:      "Queue data for delivery to a webhook destination.",
+		ArgType:  type_map.AddType(scope, &SendWebhookFunctionArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.COLLECT_SERVER).Build(),
+	}
+}
+
+type WebhookStatusPlugin struct{}
+
+func (self WebhookStatusPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+		if err != nil {
+			scope.Log("webhook_status: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("webhook_status: Command can only run on the server")
+			return
+		}
+
+		service, err := services.GetWebhookService(config_obj)
+		if err != nil {
+			scope.Log("webhook_status: %v", err)
+			return
+		}
+
+		queues, err := service.Status()
+		if err != nil {
+			scope.Log("webhook_status: %v", err)
+			return
+		}
+
+		for _, queue := range queues {
+			var oldest, next_attempt vfilter.Any = vfilter.Null{}, vfilter.Null{}
+			if len(queue.Pending) > 0 {
+				oldest = unixTime(queue.Pending[0].Created)
+				next_attempt = unixTime(queue.Pending[0].NextAttempt)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Destination", queue.Name).
+				Set("Pending", len(queue.Pending)).
+				Set("Delivered", queue.Delivered).
+				Set("Failed", queue.Failed).
+				Set("Dropped", queue.Dropped).
+				Set("OldestPending", oldest).
+				Set("NextAttempt", next_attempt).
+				Set("LastDelivery", unixTime(queue.LastDelivery)).
+				Set("LastError", queue.LastError):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self WebhookStatusPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "webhook_status",
+		Doc:      "Show the delivery status of the webhook destinations.",
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.SERVER_ADMIN).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&SendWebhookFunction{})
+	vql_subsystem.RegisterPlugin(&WebhookStatusPlugin{})
+}
@@ -0,0 +1,221 @@
+package carve
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/readers"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type CarveSignatureArgs struct {
+	Name       string `vfilter:"required,field=name,doc=The name of the file type."`
+	Category   string `vfilter:"optional,field=category,doc=A category for the file type."`
+	Extension  string `vfilter:"optional,field=extension,doc=The extension for uploaded files (default the name)."`
+	Header     string `vfilter:"required,field=header,doc=The header as a hex string."`
+	Footer     string `vfilter:"optional,field=footer,doc=The footer as a hex string."`
+	FooterTail int64  `vfilter:"optional,field=footer_tail,doc=Number of bytes after the footer which belong to the file."`
+	MaxSize    int64  `vfilter:"optional,field=max_size,doc=The maximum size of the file (default 10mb)."`
+}
+
+type CarvePluginArgs struct {
+	Filename   *accessors.OSPath `vfilter:"required,field=filename,doc=The device, image or file to carve from."`
+	Accessor   string            `vfilter:"optional,field=accessor,doc=The accessor to use."`
+	Types      []string          `vfilter:"optional,field=types,doc=Only use built in signatures with these names or categories (images, documents, archives, executables)."`
+	Signatures []vfilter.Any     `vfilter:"optional,field=signatures,doc=Additional signatures as dicts with name, header, footer, footer_tail, max_size, extension and category."`
+	Start      int64             `vfilter:"optional,field=start,doc=The offset to start carving from."`
+	End        int64             `vfilter:"optional,field=end,doc=The offset to stop carving at (default end of the file)."`
+	Blocksize  int64             `vfilter:"optional,field=blocksize,doc=Blocksize for scanning (1mb)."`
+	Nested     bool              `vfilter:"optional,field=nested,doc=Also carve files found inside other carved files."`
+	NoUpload   bool              `vfilter:"optional,field=no_upload,doc=Only report the carved files without uploading them."`
+	Number     int64             `vfilter:"optional,field=number,doc=Stop after carving this many files."`
+}
+
+type CarvePlugin struct{}
+
+func (self CarvePlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer utils.RecoverVQL(scope)
+
+		arg := &CarvePluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("carve: %v", err)
+			return
+		}
+
+		err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+		if err != nil {
+			scope.Log("carve: %v", err)
+			return
+		}
+
+		signatures, err := getSignatures(ctx, scope, arg)
+		if err != nil {
+			scope.Log("carve: %v", err)
+			return
+		}
+
+		carver, err := NewCarver(signatures, arg.Blocksize, arg.Nested)
+		if err != nil {
+			scope.Log("carve: %v", err)
+			return
+		}
+
+		lru_size := vql_subsystem.GetIntFromRow(
+			scope, scope, constants.NTFS_CACHE_SIZE)
+		reader, err := readers.NewPagedReader(
+			scope, arg.Accessor, arg.Filename, int(lru_size))
+		if err != nil {
+			scope.Log("carve: %v", err)
+			return
+		}
+		defer reader.Close()
+
+		end := arg.End
+		if end == 0 {
+			end = reader.MaxSize()
+		}
+
+		count := int64(0)
+		err = carver.Scan(ctx, reader, arg.Start, end,
+			func(offset int64) {
+				// Charge an op because we may not emit anything here
+				scope.ChargeOp()
+			},
+			func(hit *Hit) error {
+				row := ordereddict.NewDict().
+					Set("OSPath", arg.Filename).
+					Set("Type", hit.Signature.Name).
+					Set("Category", hit.Signature.Category).
+					Set("Offset", hit.Offset).
+					Set("Size", hit.Size)
+
+				if !arg.NoUpload {
+					row.Set("Upload", uploadHit(ctx, scope, arg, reader, hit))
+				}
+
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case output_chan <- row:
+				}
+
+				count++
+				if arg.Number > 0 && count >= arg.Number {
+					return io.EOF
+				}
+				return nil
+			})
+		if err != nil && err != io.EOF && err != context.Canceled {
+			scope.Log("carve: %v", err)
+		}
+	}()
+
+	return output_chan
+}
+
+func getSignatures(ctx context.Context, scope vfilter.Scope,
+	arg *CarvePluginArgs) ([]*Signature, error) {
+	result := []*Signature{}
+
+	// Custom signatures replace the built in ones unless types
+	// are explicitly requested.
+	if len(arg.Signatures) == 0 || len(arg.Types) > 0 {
+		result = append(result, GetBuiltinSignatures(arg.Types)...)
+	}
+
+	for _, item := range arg.Signatures {
+		sig_arg := &CarveSignatureArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope,
+			vfilter.RowToDict(ctx, scope, item), sig_arg)
+		if err != nil {
+			return nil, fmt.Errorf("signature: %w", err)
+		}
+
+		header, err := hex.DecodeString(sig_arg.Header)
+		if err != nil {
+			return nil, fmt.Errorf("signature %v: header: %w", sig_arg.Name, err)
+		}
+
+		footer, err := hex.DecodeString(sig_arg.Footer)
+		if err != nil {
+			return nil, fmt.Errorf("signature %v: footer: %w", sig_arg.Name, err)
+		}
+
+		extension := sig_arg.Extension
+		if extension == "" {
+			extension = sig_arg.Name
+		}
+
+		result = append(result, &Signature{
+			Name:       sig_arg.Name,
+			Category:   sig_arg.Category,
+			Extension:  extension,
+			Header:     header,
+			Footer:     footer,
+			FooterTail: sig_arg.FooterTail,
+			MaxSize:    sig_arg.MaxSize,
+		})
+	}
+
+	return result, nil
+}
+
+// Carved files are stored under the source file named by their
+// offset so they can be located in the original data.
+func uploadHit(ctx context.Context, scope vfilter.Scope,
+	arg *CarvePluginArgs, reader io.ReaderAt, hit *Hit) vfilter.Any {
+	uploader, ok := artifacts.GetUploader(scope)
+	if !ok {
+		scope.Log("carve: Uploader not configured.")
+		return vfilter.Null{}
+	}
+
+	name := arg.Filename.Append("carved",
+		fmt.Sprintf("%d.%s", hit.Offset, hit.Signature.Extension))
+
+	upload_response, err := uploader.Upload(
+		ctx, scope, name,
+		arg.Accessor,
+		name,
+		hit.Size, // Expected size.
+		time.Time{}, time.Time{}, time.Time{}, time.Time{},
+		io.NewSectionReader(reader, hit.Offset, hit.Size))
+	if err != nil {
+		scope.Log("carve: Unable to upload %v: %v", name, err)
+		return vfilter.Null{}
+	}
+	return upload_response
+}
+
+func (self CarvePlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "carve",
+		Doc:      "Carve files from raw devices, images or unallocated space using file signatures.",
+		ArgType:  type_map.AddType(scope, &CarvePluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&CarvePlugin{})
+}
@@ -0,0 +1,228 @@
+package carve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+)
+
+const (
+	DEFAULT_BLOCKSIZE = MB
+)
+
+type Hit struct {
+	Signature *Signature
+	Offset    int64
+	Size      int64
+}
+
+type headerMatch struct {
+	signature *Signature
+	pos       int
+}
+
+// A Carver scans raw data for the headers of its signatures and
+// determines the extent of each carved object.
+type Carver struct {
+	signatures []*Signature
+	blocksize  int64
+
+	// The longest header we need to detect across block boundaries.
+	max_header int
+
+	// If set we also report headers found within objects we
+	// already carved (e.g. thumbnails embedded in images).
+	nested bool
+}
+
+func NewCarver(signatures []*Signature,
+	blocksize int64, nested bool) (*Carver, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("no signatures to carve")
+	}
+
+	if blocksize <= 0 {
+		blocksize = DEFAULT_BLOCKSIZE
+	}
+
+	result := &Carver{
+		signatures: signatures,
+		blocksize:  blocksize,
+		nested:     nested,
+	}
+
+	for _, sig := range signatures {
+		if len(sig.Header) == 0 {
+			return nil, errors.New("signature " + sig.Name + " has no header")
+		}
+
+		if len(sig.Header) > result.max_header {
+			result.max_header = len(sig.Header)
+		}
+	}
+
+	return result, nil
+}
+
+// Scan the reader between start and end (0 means scan to the end of
+// the data) and call output with each carved object in order of
+// offset. The progress callback is called after each block.
+func (self *Carver) Scan(ctx context.Context, reader io.ReaderAt,
+	start, end int64, progress func(offset int64),
+	output func(hit *Hit) error) error {
+
+	// Read a little past the end of each block so headers which
+	// span block boundaries are detected.
+	buf := make([]byte, self.blocksize+int64(self.max_header)-1)
+
+	// The end of the last carved object.
+	carved_until := int64(-1)
+
+	for offset := start; end <= 0 || offset < end; offset += self.blocksize {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		n, err := reader.ReadAt(buf, offset)
+		if n == 0 {
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+			return nil
+		}
+
+		// Only report headers which start within this block.
+		limit := int64(n)
+		if limit > self.blocksize {
+			limit = self.blocksize
+		}
+		if end > 0 && offset+limit > end {
+			limit = end - offset
+		}
+
+		for _, match := range self.findHeaders(buf[:n], int(limit)) {
+			hit_offset := offset + int64(match.pos)
+			if !self.nested && hit_offset < carved_until {
+				continue
+			}
+
+			size := self.objectSize(reader, match.signature, hit_offset, end)
+			if size <= 0 {
+				continue
+			}
+
+			err := output(&Hit{
+				Signature: match.signature,
+				Offset:    hit_offset,
+				Size:      size,
+			})
+			if err != nil {
+				return err
+			}
+
+			if hit_offset+size > carved_until {
+				carved_until = hit_offset + size
+			}
+		}
+
+		if progress != nil {
+			progress(offset)
+		}
+
+		if int64(n) <= self.blocksize {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Find all headers starting before limit, sorted by position. When
+// several signatures match at the same position, the order of the
+// signatures decides.
+func (self *Carver) findHeaders(buf []byte, limit int) []headerMatch {
+	var result []headerMatch
+
+	for _, sig := range self.signatures {
+		pos := 0
+		for pos < limit {
+			idx := bytes.Index(buf[pos:], sig.Header)
+			if idx < 0 || pos+idx >= limit {
+				break
+			}
+
+			result = append(result, headerMatch{signature: sig, pos: pos + idx})
+			pos += idx + 1
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].pos < result[j].pos
+	})
+
+	return result
+}
+
+func (self *Carver) objectSize(reader io.ReaderAt,
+	sig *Signature, offset, end int64) int64 {
+	max_size := sig.maxSize()
+	if end > 0 && offset+max_size > end {
+		max_size = end - offset
+	}
+
+	var size int64
+	switch {
+	case sig.sizer != nil:
+		size = sig.sizer(reader, offset, max_size)
+
+	case len(sig.Footer) > 0:
+		footer := findFooter(reader, sig.Footer,
+			offset+int64(len(sig.Header)), offset+max_size)
+		if footer < 0 {
+			return 0
+		}
+		size = footer + int64(len(sig.Footer)) + sig.FooterTail - offset
+
+	default:
+		size = max_size
+	}
+
+	if size > max_size {
+		size = max_size
+	}
+
+	return size
+}
+
+// Returns the offset of the first footer between start and end or
+// -1 if it is not found.
+func findFooter(reader io.ReaderAt, footer []byte, start, end int64) int64 {
+	buf := make([]byte, 64*1024+len(footer)-1)
+
+	for offset := start; offset < end; offset += 64 * 1024 {
+		to_read := buf
+		if offset+int64(len(to_read)) > end {
+			to_read = to_read[:end-offset]
+		}
+
+		n, _ := reader.ReadAt(to_read, offset)
+		if n == 0 {
+			return -1
+		}
+
+		idx := bytes.Index(to_read[:n], footer)
+		if idx >= 0 {
+			return offset + int64(idx)
+		}
+
+		if n < len(to_read) {
+			return -1
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,196 @@
+package carve
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/uploads"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+
+	_ "www.velocidex.com/golang/velociraptor/accessors/file"
+)
+
+type testObject struct {
+	name   string
+	offset int64
+	data   []byte
+}
+
+func filler(size int) []byte {
+	return bytes.Repeat([]byte("A"), size)
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// A minimal PE32 file with a single section.
+func buildPE() []byte {
+	result := filler(1536)
+	copy(result, "MZ")
+	binary.LittleEndian.PutUint32(result[0x3c:], 64)
+
+	copy(result[64:], "PE\x00\x00")
+	binary.LittleEndian.PutUint16(result[64+6:], 1)
+	binary.LittleEndian.PutUint16(result[64+20:], 224)
+
+	optional_header := result[64+24:]
+	binary.LittleEndian.PutUint16(optional_header, 0x10b)
+	binary.LittleEndian.PutUint32(optional_header[60:], 512)
+	binary.LittleEndian.PutUint32(optional_header[96+4*8:], 0)
+
+	section := result[64+24+224:]
+	binary.LittleEndian.PutUint32(section[16:], 1024)
+	binary.LittleEndian.PutUint32(section[20:], 512)
+
+	return result
+}
+
+// A JPEG with an optional thumbnail stored in its APP1 segment. The
+// entropy coded data contains stuffed 0xff bytes and a restart marker.
+func buildJPEG(thumbnail []byte) []byte {
+	app1 := concat([]byte("Exif\x00\x00"), thumbnail)
+	return concat(
+		[]byte{0xff, 0xd8},
+		[]byte{0xff, 0xe1}, binary.BigEndian.AppendUint16(nil, uint16(len(app1)+2)), app1,
+		[]byte{0xff, 0xda, 0x00, 0x08}, filler(6),
+		filler(20), []byte{0xff, 0x00, 0xff, 0xd0}, filler(20),
+		[]byte{0xff, 0xd9})
+}
+
+func buildImage() ([]byte, []testObject) {
+	jpeg := buildJPEG(buildJPEG(nil))
+
+	png := concat([]byte("\x89PNG\r\n\x1a\n"), filler(200),
+		[]byte("IEND\xae\x42\x60\x82"))
+
+	zip := concat([]byte("PK\x03\x04"), filler(300),
+		[]byte("PK\x05\x06"), make([]byte, 18))
+
+	custom := concat([]byte{0xca, 0xfe, 0xba, 0xbe}, filler(20),
+		[]byte{0xde, 0xad, 0xbe, 0xef})
+
+	objects := []testObject{
+		{"jpeg", 100, jpeg},
+		// Spans the first block boundary
+		{"png", 4090, png},
+		{"zip", 9000, zip},
+		{"pe", 16384, buildPE()},
+		{"custom", 20000, custom},
+	}
+
+	image := filler(24000)
+	for _, o := range objects {
+		copy(image[o.offset:], o.data)
+	}
+
+	// Not a valid PE header.
+	copy(image[12000:], "MZ")
+
+	return image, objects
+}
+
+var customSignature = &Signature{
+	Name:      "custom",
+	Extension: "bin",
+	Header:    []byte{0xca, 0xfe, 0xba, 0xbe},
+	Footer:    []byte{0xde, 0xad, 0xbe, 0xef},
+}
+
+func TestCarver(t *testing.T) {
+	image, objects := buildImage()
+
+	carver, err := NewCarver(append(BuiltinSignatures, customSignature),
+		4096, false)
+	assert.NoError(t, err)
+
+	var hits []*Hit
+	err = carver.Scan(context.Background(), bytes.NewReader(image), 0,
+		int64(len(image)), nil, func(hit *Hit) error {
+			hits = append(hits, hit)
+			return nil
+		})
+	assert.NoError(t, err)
+
+	assert.Equal(t, len(objects), len(hits))
+	for i, o := range objects {
+		assert.Equal(t, o.name, hits[i].Signature.Name)
+		assert.Equal(t, o.offset, hits[i].Offset)
+		assert.Equal(t, int64(len(o.data)), hits[i].Size)
+	}
+
+	// Nested carving also reports the thumbnail.
+	carver, err = NewCarver(GetBuiltinSignatures([]string{"images"}),
+		4096, true)
+	assert.NoError(t, err)
+
+	hits = nil
+	err = carver.Scan(context.Background(), bytes.NewReader(image), 0,
+		int64(len(image)), nil, func(hit *Hit) error {
+			hits = append(hits, hit)
+			return nil
+		})
+	assert.NoError(t, err)
+
+	offsets := []int64{}
+	for _, hit := range hits {
+		offsets = append(offsets, hit.Offset)
+	}
+	assert.Equal(t, []int64{100, 112, 4090}, offsets)
+}
+
+func TestCarvePlugin(t *testing.T) {
+	dir, err := ioutil.TempDir("", "carve")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	image, objects := buildImage()
+	image_path := filepath.Join(dir, "image.dd")
+	assert.NoError(t, ioutil.WriteFile(image_path, image, 0644))
+
+	scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+		Set(vql_subsystem.ACL_MANAGER_VAR, acl_managers.NullACLManager{}).
+		Set(constants.SCOPE_UPLOADER, &uploads.FileBasedUploader{
+			UploadDir: filepath.Join(dir, "uploads"),
+		}))
+	defer scope.Close()
+
+	ctx := context.Background()
+	rows := []*ordereddict.Dict{}
+	for row := range (CarvePlugin{}).Call(ctx, scope, ordereddict.NewDict().
+		Set("filename", accessors.MustNewGenericOSPath(image_path)).
+		Set("accessor", "file").
+		Set("types", []string{"archives", "executables"}).
+		Set("signatures", []*ordereddict.Dict{ordereddict.NewDict().
+			Set("name", "custom").
+			Set("header", hex.EncodeToString(customSignature.Header)).
+			Set("footer", hex.EncodeToString(customSignature.Footer))})) {
+		rows = append(rows, row.(*ordereddict.Dict))
+	}
+
+	assert.Equal(t, 3, len(rows))
+	for i, o := range objects[2:] {
+		offset, _ := rows[i].Get("Offset")
+		assert.Equal(t, o.offset, offset)
+
+		upload_any, _ := rows[i].Get("Upload")
+		upload, ok := upload_any.(*uploads.UploadResponse)
+		assert.True(t, ok)
+
+		sha_sum := sha256.Sum256(o.data)
+		assert.Equal(t, hex.EncodeToString(sha_sum[:]), upload.Sha256)
+		assert.Equal(t, uint64(len(o.data)), upload.Size)
+	}
+}
@@ -0,0 +1,498 @@
+package carve
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+)
+
+const (
+	MB = 1024 * 1024
+
+	// Used when a signature does not specify a maximum size.
+	DEFAULT_MAX_SIZE = 10 * MB
+)
+
+// A Signature describes how to carve a file type from raw data.
+//
+// Carving starts at the Header. The end of the carved object is
+// determined by (in order of preference):
+//
+//  1. A sizer function which parses the file's own headers (only
+//     available to built in signatures).
+//  2. The first Footer found after the header, plus FooterTail
+//     trailing bytes. Objects without a footer within MaxSize are
+//     discarded.
+//  3. MaxSize bytes from the header.
+type Signature struct {
+	Name       string
+	Category   string
+	Extension  string
+	Header     []byte
+	Footer     []byte
+	FooterTail int64
+	MaxSize    int64
+
+	// Returns the size of the object at offset or 0 if the data
+	// does not look valid. Sizers should not read past max_size.
+	sizer func(reader io.ReaderAt, offset, max_size int64) int64
+}
+
+func (self *Signature) maxSize() int64 {
+	if self.MaxSize <= 0 {
+		return DEFAULT_MAX_SIZE
+	}
+	return self.MaxSize
+}
+
+var (
+	BuiltinSignatures = []*Signature{{
+		Name:      "jpeg",
+		Category:  "images",
+		Extension: "jpg",
+		Header:    []byte{0xff, 0xd8, 0xff},
+		MaxSize:   20 * MB,
+		sizer:     jpegSize,
+	}, {
+		Name:      "png",
+		Category:  "images",
+		Extension: "png",
+		Header:    []byte("\x89PNG\r\n\x1a\n"),
+		Footer:    []byte("IEND\xae\x42\x60\x82"),
+		MaxSize:   20 * MB,
+	}, {
+		Name:      "gif",
+		Category:  "images",
+		Extension: "gif",
+		Header:    []byte("GIF89a"),
+		Footer:    []byte{0x00, 0x3b},
+		MaxSize:   10 * MB,
+	}, {
+		Name:      "gif87",
+		Category:  "images",
+		Extension: "gif",
+		Header:    []byte("GIF87a"),
+		Footer:    []byte{0x00, 0x3b},
+		MaxSize:   10 * MB,
+	}, {
+		Name:      "bmp",
+		Category:  "images",
+		Extension: "bmp",
+		Header:    []byte("BM"),
+		MaxSize:   20 * MB,
+		sizer:     bmpSize,
+	}, {
+		Name:      "pdf",
+		Category:  "documents",
+		Extension: "pdf",
+		Header:    []byte("%PDF-"),
+		Footer:    []byte("%%EOF"),
+		MaxSize:   50 * MB,
+	}, {
+		Name:      "ole",
+		Category:  "documents",
+		Extension: "ole",
+		Header:    []byte{0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1},
+		MaxSize:   50 * MB,
+		sizer:     oleSize,
+	}, {
+		Name:      "sqlite",
+		Category:  "documents",
+		Extension: "sqlite",
+		Header:    []byte("SQLite format 3\x00"),
+		MaxSize:   100 * MB,
+		sizer:     sqliteSize,
+	}, {
+		// Also matches Office Open XML documents (docx, xlsx)
+		Name:       "zip",
+		Category:   "archives",
+		Extension:  "zip",
+		Header:     []byte("PK\x03\x04"),
+		Footer:     []byte("PK\x05\x06"),
+		FooterTail: 18,
+		MaxSize:    100 * MB,
+	}, {
+		Name:      "rar",
+		Category:  "archives",
+		Extension: "rar",
+		Header:    []byte("Rar!\x1a\x07"),
+		MaxSize:   100 * MB,
+	}, {
+		Name:      "7z",
+		Category:  "archives",
+		Extension: "7z",
+		Header:    []byte{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c},
+		MaxSize:   100 * MB,
+		sizer:     sevenZipSize,
+	}, {
+		Name:      "gzip",
+		Category:  "archives",
+		Extension: "gz",
+		Header:    []byte{0x1f, 0x8b, 0x08},
+		MaxSize:   50 * MB,
+	}, {
+		Name:      "pe",
+		Category:  "executables",
+		Extension: "exe",
+		Header:    []byte("MZ"),
+		MaxSize:   100 * MB,
+		sizer:     peSize,
+	}, {
+		Name:      "elf",
+		Category:  "executables",
+		Extension: "elf",
+		Header:    []byte("\x7fELF"),
+		MaxSize:   100 * MB,
+		sizer:     elfSize,
+	}}
+)
+
+// Select the built in signatures by name or category. An empty list
+// selects all of them.
+func GetBuiltinSignatures(types []string) []*Signature {
+	if len(types) == 0 {
+		return BuiltinSignatures
+	}
+
+	result := []*Signature{}
+	for _, sig := range BuiltinSignatures {
+		for _, t := range types {
+			if strings.EqualFold(t, sig.Name) ||
+				strings.EqualFold(t, sig.Category) {
+				result = append(result, sig)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func readAt(reader io.ReaderAt, offset int64, length int) []byte {
+	buf := make([]byte, length)
+	n, _ := reader.ReadAt(buf, offset)
+	if n < length {
+		return nil
+	}
+	return buf
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// JPEG files may contain embedded thumbnails so we can not simply
+// search for the first end of image marker. Instead we walk the
+// segments, skipping over the entropy coded data following each
+// start of scan marker.
+func jpegSize(reader io.ReaderAt, offset, max_size int64) int64 {
+	data := &pagedBytes{reader: reader, offset: offset, size: max_size}
+
+	pos := int64(2)
+	for {
+		// Markers may be preceded by any number of fill bytes.
+		b, ok := data.at(pos)
+		if !ok || b != 0xff {
+			return 0
+		}
+		for b == 0xff {
+			pos++
+			b, ok = data.at(pos)
+			if !ok {
+				return 0
+			}
+		}
+		marker := b
+		pos++
+
+		switch {
+		// End of image
+		case marker == 0xd9:
+			return pos
+
+		// Markers without a payload
+		case marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7):
+			continue
+		}
+
+		hi, ok1 := data.at(pos)
+		lo, ok2 := data.at(pos + 1)
+		length := int64(hi)<<8 | int64(lo)
+		if !ok1 || !ok2 || length < 2 {
+			return 0
+		}
+		pos += length
+
+		if marker != 0xda {
+			continue
+		}
+
+		// Skip the entropy coded data: 0xff is only followed by
+		// 0x00 (stuffing) or a restart marker within it.
+		for {
+			b, ok := data.at(pos)
+			if !ok {
+				return 0
+			}
+
+			if b == 0xff {
+				next, ok := data.at(pos + 1)
+				if !ok {
+					return 0
+				}
+				if next != 0x00 && (next < 0xd0 || next > 0xd7) {
+					break
+				}
+				pos++
+			}
+			pos++
+		}
+	}
+}
+
+// Provide byte level access to a reader through a small cache.
+type pagedBytes struct {
+	reader io.ReaderAt
+	offset int64
+	size   int64
+
+	buf       []byte
+	buf_start int64
+}
+
+func (self *pagedBytes) at(pos int64) (byte, bool) {
+	if pos < 0 || pos >= self.size {
+		return 0, false
+	}
+
+	if self.buf == nil || pos < self.buf_start ||
+		pos >= self.buf_start+int64(len(self.buf)) {
+		buf := make([]byte, 64*1024)
+		n, _ := self.reader.ReadAt(buf, self.offset+pos)
+		if n == 0 {
+			return 0, false
+		}
+		self.buf = buf[:n]
+		self.buf_start = pos
+	}
+
+	return self.buf[pos-self.buf_start], true
+}
+
+// The BMP header records the total file size.
+func bmpSize(reader io.ReaderAt, offset, max_size int64) int64 {
+	header := readAt(reader, offset, 30)
+	if header == nil {
+		return 0
+	}
+
+	size := int64(binary.LittleEndian.Uint32(header[2:]))
+	reserved := binary.LittleEndian.Uint32(header[6:])
+	pixel_offset := int64(binary.LittleEndian.Uint32(header[10:]))
+	info_size := binary.LittleEndian.Uint32(header[14:])
+	planes := binary.LittleEndian.Uint16(header[26:])
+
+	// "BM" is very common so validate as much as we can.
+	if reserved != 0 || planes != 1 || pixel_offset >= size ||
+		(info_size != 12 && info_size != 40 && info_size != 52 &&
+			info_size != 56 && info_size != 108 && info_size != 124) {
+		return 0
+	}
+
+	return size
+}
+
+// The size of an OLE compound file is bounded by the number of
+// sectors its FAT can describe.
+func oleSize(reader io.ReaderAt, offset, max_size int64) int64 {
+	header := readAt(reader, offset, 512)
+	if header == nil {
+		return 0
+	}
+
+	sector_shift := binary.LittleEndian.Uint16(header[30:])
+	if sector_shift != 9 && sector_shift != 12 {
+		return 0
+	}
+
+	sector_size := int64(1) << sector_shift
+	fat_sectors := int64(binary.LittleEndian.Uint32(header[44:]))
+	if fat_sectors == 0 {
+		return 0
+	}
+
+	return (fat_sectors*sector_size/4 + 1) * sector_size
+}
+
+func sqliteSize(reader io.ReaderAt, offset, max_size int64) int64 {
+	header := readAt(reader, offset, 100)
+	if header == nil {
+		return 0
+	}
+
+	page_size := int64(binary.BigEndian.Uint16(header[16:]))
+	if page_size == 1 {
+		page_size = 65536
+	}
+
+	// Page size must be a power of 2 between 512 and 65536
+	if page_size < 512 || page_size&(page_size-1) != 0 {
+		return 0
+	}
+
+	return page_size * int64(binary.BigEndian.Uint32(header[28:]))
+}
+
+// The 7z start header points to the next header which is stored at
+// the end of the archive.
+func sevenZipSize(reader io.ReaderAt, offset, max_size int64) int64 {
+	header := readAt(reader, offset, 32)
+	if header == nil {
+		return 0
+	}
+
+	next_header_offset := int64(binary.LittleEndian.Uint64(header[12:]))
+	next_header_size := int64(binary.LittleEndian.Uint64(header[20:]))
+	if next_header_offset < 0 || next_header_size <= 0 {
+		return 0
+	}
+
+	return 32 + next_header_offset + next_header_size
+}
+
+// A PE file extends to the end of its last section or its
+// authenticode signature, whichever comes last.
+func peSize(reader io.ReaderAt, offset, max_size int64) int64 {
+	dos_header := readAt(reader, offset, 64)
+	if dos_header == nil {
+		return 0
+	}
+
+	pe_offset := int64(binary.LittleEndian.Uint32(dos_header[0x3c:]))
+	if pe_offset < 64 || pe_offset > 4096 {
+		return 0
+	}
+
+	pe_header := readAt(reader, offset+pe_offset, 24)
+	if pe_header == nil || string(pe_header[:4]) != "PE\x00\x00" {
+		return 0
+	}
+
+	// The Windows loader supports at most 96 sections.
+	number_of_sections := int64(binary.LittleEndian.Uint16(pe_header[6:]))
+	optional_header_size := int64(binary.LittleEndian.Uint16(pe_header[20:]))
+	if number_of_sections == 0 || number_of_sections > 96 {
+		return 0
+	}
+
+	optional_header := readAt(reader, offset+pe_offset+24,
+		int(optional_header_size))
+	if optional_header == nil || optional_header_size < 64 {
+		return 0
+	}
+
+	size := int64(binary.LittleEndian.Uint32(optional_header[60:]))
+
+	// The security directory is the 5th data directory and is
+	// addressed by file offset.
+	data_directories := int64(96)
+	if binary.LittleEndian.Uint16(optional_header) == 0x20b {
+		data_directories = 112
+	}
+	security := data_directories + 4*8
+	if security+8 <= optional_header_size {
+		cert_offset := int64(binary.LittleEndian.Uint32(optional_header[security:]))
+		cert_size := int64(binary.LittleEndian.Uint32(optional_header[security+4:]))
+		if cert_offset > 0 {
+			size = maxInt64(size, cert_offset+cert_size)
+		}
+	}
+
+	sections := readAt(reader, offset+pe_offset+24+optional_header_size,
+		int(number_of_sections*40))
+	if sections == nil {
+		return 0
+	}
+
+	for i := int64(0); i < number_of_sections; i++ {
+		section := sections[i*40 : (i+1)*40]
+		raw_size := int64(binary.LittleEndian.Uint32(section[16:]))
+		raw_offset := int64(binary.LittleEndian.Uint32(section[20:]))
+		size = maxInt64(size, raw_offset+raw_size)
+	}
+
+	return size
+}
+
+// An ELF file extends to the end of its section header table or its
+// last segment, whichever comes last.
+func elfSize(reader io.ReaderAt, offset, max_size int64) int64 {
+	header := readAt(reader, offset, 64)
+	if header == nil {
+		return 0
+	}
+
+	// Only version 1 is defined.
+	if header[6] != 1 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch header[5] {
+	case 1:
+		order = binary.LittleEndian
+	case 2:
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	var ph_offset, sh_offset, ph_entsize, ph_num, sh_entsize, sh_num int64
+	switch header[4] {
+	case 1:
+		ph_offset = int64(order.Uint32(header[28:]))
+		sh_offset = int64(order.Uint32(header[32:]))
+		ph_entsize = int64(order.Uint16(header[42:]))
+		ph_num = int64(order.Uint16(header[44:]))
+		sh_entsize = int64(order.Uint16(header[46:]))
+		sh_num = int64(order.Uint16(header[48:]))
+	case 2:
+		ph_offset = int64(order.Uint64(header[32:]))
+		sh_offset = int64(order.Uint64(header[40:]))
+		ph_entsize = int64(order.Uint16(header[54:]))
+		ph_num = int64(order.Uint16(header[56:]))
+		sh_entsize = int64(order.Uint16(header[58:]))
+		sh_num = int64(order.Uint16(header[60:]))
+	default:
+		return 0
+	}
+
+	if ph_offset < 0 || sh_offset < 0 {
+		return 0
+	}
+
+	size := maxInt64(ph_offset+ph_entsize*ph_num, sh_offset+sh_entsize*sh_num)
+
+	if ph_num > 0 && ph_entsize >= 32 && ph_entsize*ph_num < MB {
+		program_headers := readAt(reader, offset+ph_offset,
+			int(ph_entsize*ph_num))
+		if program_headers == nil {
+			return size
+		}
+
+		for i := int64(0); i < ph_num; i++ {
+			ph := program_headers[i*ph_entsize:]
+			if header[4] == 1 {
+				size = maxInt64(size,
+					int64(order.Uint32(ph[4:]))+int64(order.Uint32(ph[16:])))
+			} else if ph_entsize >= 56 {
+				size = maxInt64(size,
+					int64(order.Uint64(ph[8:]))+int64(order.Uint64(ph[32:])))
+			}
+		}
+	}
+
+	return size
+}
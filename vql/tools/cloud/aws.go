@@ -0,0 +1,203 @@
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+var (
+	// Override in tests
+	ec2_endpoint = func(region string) string {
+		return fmt.Sprintf("https://ec2.%s.amazonaws.com/", region)
+	}
+)
+
+type awsIdentityDocument struct {
+	AccountId        string `json:"accountId"`
+	AvailabilityZone string `json:"availabilityZone"`
+	ImageId          string `json:"imageId"`
+	InstanceId       string `json:"instanceId"`
+	InstanceType     string `json:"instanceType"`
+	PrivateIp        string `json:"privateIp"`
+	Region           string `json:"region"`
+}
+
+func getAWSMetadata(ctx context.Context,
+	client *metadataClient) (*InstanceMetadata, error) {
+
+	// IMDSv2 requires a session token. If the PUT fails the
+	// instance may still allow IMDSv1.
+	headers := make(map[string]string)
+	token, _, err := client.do(ctx, "PUT", aws_metadata_url+"/latest/api/token",
+		map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+	if err == nil {
+		headers["X-aws-ec2-metadata-token"] = string(token)
+	}
+
+	get := func(path string) string {
+		data, _, err := client.do(ctx, "GET",
+			aws_metadata_url+"/latest/meta-data/"+path, headers)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+
+	data, _, err := client.do(ctx, "GET",
+		aws_metadata_url+"/latest/dynamic/instance-identity/document", headers)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &awsIdentityDocument{}
+	err = json.Unmarshal(data, doc)
+	if err != nil || doc.InstanceId == "" {
+		return nil, errNotFound
+	}
+
+	result := newMetadata("aws")
+	result.InstanceId = doc.InstanceId
+	result.InstanceType = doc.InstanceType
+	result.Region = doc.Region
+	result.Zone = doc.AvailabilityZone
+	result.Account = doc.AccountId
+	result.ImageId = doc.ImageId
+	result.PrivateIp = doc.PrivateIp
+	result.Hostname = get("local-hostname")
+	result.PublicIp = get("public-ipv4")
+
+	mac := get("mac")
+	if mac != "" {
+		result.VpcId = get("network/interfaces/macs/" + mac + "/vpc-id")
+		result.SubnetId = get("network/interfaces/macs/" + mac + "/subnet-id")
+	}
+
+	for _, group := range strings.Split(get("security-groups"), "\n") {
+		if group != "" {
+			result.SecurityGroups = append(result.SecurityGroups, group)
+		}
+	}
+
+	// Tags are only available if the instance allows tags in
+	// metadata.
+	for _, key := range strings.Split(get("tags/instance"), "\n") {
+		if key != "" {
+			result.Tags.Set(key, get("tags/instance/"+url.PathEscape(key)))
+		}
+	}
+
+	name, pres := result.Tags.GetString("Name")
+	if pres {
+		result.Name = name
+	}
+
+	return result, nil
+}
+
+type ec2DescribeInstancesResponse struct {
+	Reservations []struct {
+		Instances []struct {
+			InstanceId string `xml:"instanceId"`
+			VpcId      string `xml:"vpcId"`
+			SubnetId   string `xml:"subnetId"`
+			Groups     []struct {
+				GroupId   string `xml:"groupId"`
+				GroupName string `xml:"groupName"`
+			} `xml:"groupSet>item"`
+			Tags []struct {
+				Key   string `xml:"key"`
+				Value string `xml:"value"`
+			} `xml:"tagSet>item"`
+		} `xml:"instancesSet>item"`
+	} `xml:"reservationSet>item"`
+}
+
+// Query the EC2 API for the instance's tags and security groups
+// using the credentials of the instance's role. The instance
+// profile must allow ec2:DescribeInstances.
+func queryAWSInstance(ctx context.Context, metadata *InstanceMetadata) error {
+	sess, err := session.NewSession()
+	if err != nil {
+		return err
+	}
+
+	body := url.Values{
+		"Action":       {"DescribeInstances"},
+		"Version":      {"2016-11-15"},
+		"InstanceId.1": {metadata.InstanceId},
+	}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "POST",
+		ec2_endpoint(metadata.Region), strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	signer := v4.NewSigner(sess.Config.Credentials)
+	_, err = signer.Sign(req, bytes.NewReader([]byte(body)),
+		"ec2", metadata.Region, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("DescribeInstances: %v", resp.Status)
+	}
+
+	response := &ec2DescribeInstancesResponse{}
+	err = xml.Unmarshal(data, response)
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range response.Reservations {
+		for _, instance := range reservation.Instances {
+			if instance.InstanceId != metadata.InstanceId {
+				continue
+			}
+
+			metadata.VpcId = instance.VpcId
+			metadata.SubnetId = instance.SubnetId
+
+			metadata.SecurityGroups = []string{}
+			metadata.SecurityGroupIds = []string{}
+			for _, g := range instance.Groups {
+				metadata.SecurityGroups = append(metadata.SecurityGroups, g.GroupName)
+				metadata.SecurityGroupIds = append(metadata.SecurityGroupIds, g.GroupId)
+			}
+
+			for _, t := range instance.Tags {
+				metadata.Tags.Set(t.Key, t.Value)
+				if t.Key == "Name" {
+					metadata.Name = t.Value
+				}
+			}
+		}
+	}
+
+	return nil
+}
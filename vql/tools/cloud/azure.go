@@ -0,0 +1,96 @@
+package cloud
+
+import (
+	"context"
+
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+type azureInstance struct {
+	Compute struct {
+		VmId              string `json:"vmId"`
+		Name              string `json:"name"`
+		VmSize            string `json:"vmSize"`
+		Location          string `json:"location"`
+		Zone              string `json:"zone"`
+		SubscriptionId    string `json:"subscriptionId"`
+		ResourceGroupName string `json:"resourceGroupName"`
+		OsProfile         struct {
+			ComputerName string `json:"computerName"`
+		} `json:"osProfile"`
+		StorageProfile struct {
+			ImageReference struct {
+				Id        string `json:"id"`
+				Publisher string `json:"publisher"`
+				Offer     string `json:"offer"`
+				Sku       string `json:"sku"`
+				Version   string `json:"version"`
+			} `json:"imageReference"`
+		} `json:"storageProfile"`
+		TagsList []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"tagsList"`
+	} `json:"compute"`
+	Network struct {
+		Interface []struct {
+			Ipv4 struct {
+				IpAddress []struct {
+					PrivateIpAddress string `json:"privateIpAddress"`
+					PublicIpAddress  string `json:"publicIpAddress"`
+				} `json:"ipAddress"`
+			} `json:"ipv4"`
+		} `json:"interface"`
+	} `json:"network"`
+}
+
+func getAzureMetadata(ctx context.Context,
+	client *metadataClient) (*InstanceMetadata, error) {
+	data, _, err := client.do(ctx, "GET",
+		azure_metadata_url+"/metadata/instance?api-version=2021-02-01",
+		map[string]string{"Metadata": "true"})
+	if err != nil {
+		return nil, err
+	}
+
+	instance := &azureInstance{}
+	err = json.Unmarshal(data, instance)
+	if err != nil || instance.Compute.VmId == "" {
+		return nil, errNotFound
+	}
+
+	compute := &instance.Compute
+	result := newMetadata("azure")
+	result.InstanceId = compute.VmId
+	result.Name = compute.Name
+	result.InstanceType = compute.VmSize
+	result.Region = compute.Location
+	result.Zone = compute.Zone
+	result.Account = compute.SubscriptionId
+	result.Hostname = compute.OsProfile.ComputerName
+
+	image := &compute.StorageProfile.ImageReference
+	result.ImageId = image.Id
+	if result.ImageId == "" && image.Offer != "" {
+		result.ImageId = image.Publisher + ":" + image.Offer + ":" +
+			image.Sku + ":" + image.Version
+	}
+
+	result.Tags.Set("ResourceGroup", compute.ResourceGroupName)
+	for _, tag := range compute.TagsList {
+		result.Tags.Set(tag.Name, tag.Value)
+	}
+
+	for _, iface := range instance.Network.Interface {
+		for _, addr := range iface.Ipv4.IpAddress {
+			if result.PrivateIp == "" {
+				result.PrivateIp = addr.PrivateIpAddress
+			}
+			if result.PublicIp == "" {
+				result.PublicIp = addr.PublicIpAddress
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package cloud
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+type gcpMetadata struct {
+	Instance struct {
+		// The id is a large number
+		Id          json.RawMessage `json:"id"`
+		Name        string          `json:"name"`
+		Hostname    string          `json:"hostname"`
+		MachineType string          `json:"machineType"`
+		Zone        string          `json:"zone"`
+		Image       string          `json:"image"`
+		Tags        []string        `json:"tags"`
+
+		NetworkInterfaces []struct {
+			Ip            string `json:"ip"`
+			Network       string `json:"network"`
+			AccessConfigs []struct {
+				ExternalIp string `json:"externalIp"`
+			} `json:"accessConfigs"`
+		} `json:"networkInterfaces"`
+	} `json:"instance"`
+	Project struct {
+		ProjectId string `json:"projectId"`
+	} `json:"project"`
+}
+
+func getGCPMetadata(ctx context.Context,
+	client *metadataClient) (*InstanceMetadata, error) {
+	data, headers, err := client.do(ctx, "GET",
+		gcp_metadata_url+"/computeMetadata/v1/?recursive=true",
+		map[string]string{"Metadata-Flavor": "Google"})
+	if err != nil {
+		return nil, err
+	}
+
+	if headers.Get("Metadata-Flavor") != "Google" {
+		return nil, errNotFound
+	}
+
+	metadata := &gcpMetadata{}
+	err = json.Unmarshal(data, metadata)
+	if err != nil || len(metadata.Instance.Id) == 0 {
+		return nil, errNotFound
+	}
+
+	instance := &metadata.Instance
+	result := newMetadata("gcp")
+	result.InstanceId = strings.Trim(string(instance.Id), `"`)
+	result.Name = instance.Name
+	result.Hostname = instance.Hostname
+	result.Account = metadata.Project.ProjectId
+	result.ImageId = instance.Image
+
+	// These are given as projects/<number>/zones/<zone>
+	result.InstanceType = path.Base(instance.MachineType)
+	result.Zone = path.Base(instance.Zone)
+
+	// The region is the zone without the last part
+	// (us-central1-a -> us-central1)
+	idx := strings.LastIndex(result.Zone, "-")
+	if idx > 0 {
+		result.Region = result.Zone[:idx]
+	}
+
+	// GCP network tags are used like security groups to select
+	// firewall rules.
+	result.SecurityGroups = append(result.SecurityGroups, instance.Tags...)
+
+	for _, iface := range instance.NetworkInterfaces {
+		if result.PrivateIp == "" {
+			result.PrivateIp = iface.Ip
+			result.VpcId = path.Base(iface.Network)
+		}
+		for _, ac := range iface.AccessConfigs {
+			if result.PublicIp == "" {
+				result.PublicIp = ac.ExternalIp
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,226 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	// Non cloud hosts should not wait long for the metadata
+	// service.
+	DEFAULT_TIMEOUT = 2
+)
+
+var (
+	// All providers serve instance metadata from the link local
+	// address. These are variables so tests can redirect them.
+	aws_metadata_url   = "http://169.254.169.254"
+	azure_metadata_url = "http://169.254.169.254"
+	gcp_metadata_url   = "http://169.254.169.254"
+
+	errNotFound = errors.New("not found")
+)
+
+// A normalized view of the instance regardless of provider.
+type InstanceMetadata struct {
+	Provider       string
+	InstanceId     string
+	Name           string
+	InstanceType   string
+	Region         string
+	Zone           string
+	Account        string
+	ImageId        string
+	Hostname       string
+	PrivateIp      string
+	PublicIp       string
+	VpcId          string
+	SubnetId       string
+	Tags           *ordereddict.Dict
+	SecurityGroups []string
+
+	// Only available from the cloud API.
+	SecurityGroupIds []string
+}
+
+func newMetadata(provider string) *InstanceMetadata {
+	return &InstanceMetadata{
+		Provider:         provider,
+		Tags:             ordereddict.NewDict(),
+		SecurityGroups:   []string{},
+		SecurityGroupIds: []string{},
+	}
+}
+
+type metadataClient struct {
+	client *http.Client
+}
+
+// The metadata service must never be reached through a proxy.
+func newMetadataClient(timeout time.Duration) *metadataClient {
+	return &metadataClient{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy: nil,
+				DialContext: (&net.Dialer{
+					Timeout: timeout,
+				}).DialContext,
+			},
+		},
+	}
+}
+
+func (self *metadataClient) do(ctx context.Context,
+	method, url string, headers map[string]string) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := self.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, errNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("%v: %v", url, resp.Status)
+	}
+
+	return data, resp.Header, nil
+}
+
+type provider func(ctx context.Context,
+	client *metadataClient) (*InstanceMetadata, error)
+
+var providers = map[string]provider{
+	"aws":   getAWSMetadata,
+	"azure": getAzureMetadata,
+	"gcp":   getGCPMetadata,
+}
+
+// Probe all the providers at once and return the first one that
+// answers. Only one can succeed on any given host.
+func GetMetadata(ctx context.Context,
+	provider_name string, timeout time.Duration) (*InstanceMetadata, error) {
+	client := newMetadataClient(timeout)
+
+	sub_ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if provider_name != "" {
+		p, pres := providers[provider_name]
+		if !pres {
+			return nil, fmt.Errorf("unknown provider %v", provider_name)
+		}
+		return p(sub_ctx, client)
+	}
+
+	results := make(chan *InstanceMetadata, len(providers))
+	for _, p := range providers {
+		go func(p provider) {
+			result, err := p(sub_ctx, client)
+			if err != nil {
+				result = nil
+			}
+			results <- result
+		}(p)
+	}
+
+	for i := 0; i < len(providers); i++ {
+		result := <-results
+		if result != nil {
+			return result, nil
+		}
+	}
+
+	return nil, errNotFound
+}
+
+type CloudMetadataFunctionArgs struct {
+	Provider string `vfilter:"optional,field=provider,doc=Only check this provider (aws, azure or gcp). By default all are probed."`
+	Timeout  int64  `vfilter:"optional,field=timeout,doc=How long to wait for the metadata service in seconds (default 2)."`
+	QueryAPI bool   `vfilter:"optional,field=query_api,doc=Also query the cloud API using the instance's credentials for tags and security groups (AWS only)."`
+}
+
+type CloudMetadataFunction struct{}
+
+func (self CloudMetadataFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+	if err != nil {
+		scope.Log("cloud_metadata: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &CloudMetadataFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("cloud_metadata: %v", err)
+		return vfilter.Null{}
+	}
+
+	if arg.Timeout == 0 {
+		arg.Timeout = DEFAULT_TIMEOUT
+	}
+	timeout := time.Duration(arg.Timeout) * time.Second
+
+	result, err := GetMetadata(ctx, arg.Provider, timeout)
+	if err != nil {
+		// Not running in a cloud is not an error.
+		if !errors.Is(err, errNotFound) {
+			scope.Log("cloud_metadata: %v", err)
+		}
+		return vfilter.Null{}
+	}
+
+	if arg.QueryAPI && result.Provider == "aws" {
+		err = queryAWSInstance(ctx, result)
+		if err != nil {
+			scope.Log("cloud_metadata: Unable to query the EC2 API: %v", err)
+		}
+	}
+
+	return result
+}
+
+func (self CloudMetadataFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "cloud_metadata",
+		Doc:      "Get the cloud instance metadata (AWS, Azure or GCP) of this host.",
+		ArgType:  type_map.AddType(scope, &CloudMetadataFunctionArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&CloudMetadataFunction{})
+}
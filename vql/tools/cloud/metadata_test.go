@@ -0,0 +1,168 @@
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func startServer(t *testing.T, responses map[string]string,
+	check func(r *http.Request) bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			data, pres := responses[r.Method+" "+r.URL.Path]
+			if !pres || (check != nil && !check(r)) {
+				http.NotFound(w, r)
+				return
+			}
+			if strings.HasPrefix(r.URL.Path, "/computeMetadata/") {
+				w.Header().Set("Metadata-Flavor", "Google")
+			}
+			_, _ = w.Write([]byte(data))
+		}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func setURLs(url string) func() {
+	old_aws, old_azure, old_gcp := aws_metadata_url, azure_metadata_url, gcp_metadata_url
+	aws_metadata_url, azure_metadata_url, gcp_metadata_url = url, url, url
+	return func() {
+		aws_metadata_url, azure_metadata_url, gcp_metadata_url = old_aws, old_azure, old_gcp
+	}
+}
+
+func TestAWS(t *testing.T) {
+	// Require the IMDSv2 token on all requests.
+	server := startServer(t, map[string]string{
+		"PUT /latest/api/token": "TOKEN",
+		"GET /latest/dynamic/instance-identity/document": `{
+  "accountId": "123456789012", "availabilityZone": "us-east-1a",
+  "imageId": "ami-1", "instanceId": "i-1234", "instanceType": "t3.micro",
+  "privateIp": "10.0.0.5", "region": "us-east-1"}`,
+		"GET /latest/meta-data/security-groups":                                  "web\nssh",
+		"GET /latest/meta-data/mac":                                              "0a:00:00:00:00:01",
+		"GET /latest/meta-data/network/interfaces/macs/0a:00:00:00:00:01/vpc-id": "vpc-1",
+		"GET /latest/meta-data/tags/instance":                                    "Name\nteam",
+		"GET /latest/meta-data/tags/instance/Name":                               "web-1",
+		"GET /latest/meta-data/tags/instance/team":                               "blue",
+	}, func(r *http.Request) bool {
+		return r.Method == "PUT" ||
+			r.Header.Get("X-aws-ec2-metadata-token") == "TOKEN"
+	})
+	defer setURLs(server.URL)()
+
+	result, err := GetMetadata(context.Background(), "", 2*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "aws", result.Provider)
+	assert.Equal(t, "i-1234", result.InstanceId)
+	assert.Equal(t, "web-1", result.Name)
+	assert.Equal(t, "us-east-1", result.Region)
+	assert.Equal(t, "vpc-1", result.VpcId)
+	assert.Equal(t, []string{"web", "ssh"}, result.SecurityGroups)
+
+	team, _ := result.Tags.GetString("team")
+	assert.Equal(t, "blue", team)
+
+	// Now query the EC2 API
+	api := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_ = r.ParseForm()
+			if r.Form.Get("Action") != "DescribeInstances" ||
+				r.Form.Get("InstanceId.1") != "i-1234" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`<DescribeInstancesResponse>
+<reservationSet><item><instancesSet><item>
+  <instanceId>i-1234</instanceId>
+  <vpcId>vpc-1</vpcId><subnetId>subnet-1</subnetId>
+  <groupSet><item><groupId>sg-1</groupId><groupName>web</groupName></item></groupSet>
+  <tagSet><item><key>env</key><value>prod</value></item></tagSet>
+</item></instancesSet></item></reservationSet>
+</DescribeInstancesResponse>`))
+		}))
+	defer api.Close()
+
+	old_endpoint := ec2_endpoint
+	ec2_endpoint = func(region string) string { return api.URL }
+	defer func() { ec2_endpoint = old_endpoint }()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+
+	err = queryAWSInstance(context.Background(), result)
+	assert.NoError(t, err)
+	assert.Equal(t, "subnet-1", result.SubnetId)
+	assert.Equal(t, []string{"web"}, result.SecurityGroups)
+	assert.Equal(t, []string{"sg-1"}, result.SecurityGroupIds)
+
+	env, _ := result.Tags.GetString("env")
+	assert.Equal(t, "prod", env)
+}
+
+func TestAzure(t *testing.T) {
+	server := startServer(t, map[string]string{
+		"GET /metadata/instance": `{
+ "compute": {"vmId": "vm-1", "name": "web", "vmSize": "Standard_B1s",
+   "location": "westeurope", "zone": "1", "subscriptionId": "sub-1",
+   "resourceGroupName": "rg", "osProfile": {"computerName": "web-host"},
+   "tagsList": [{"name": "team", "value": "red"}]},
+ "network": {"interface": [{"ipv4": {"ipAddress": [
+    {"privateIpAddress": "10.1.0.4", "publicIpAddress": "1.2.3.4"}]}}]}}`,
+	}, func(r *http.Request) bool {
+		return r.Header.Get("Metadata") == "true"
+	})
+	defer setURLs(server.URL)()
+
+	result, err := GetMetadata(context.Background(), "", 2*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "azure", result.Provider)
+	assert.Equal(t, "vm-1", result.InstanceId)
+	assert.Equal(t, "westeurope", result.Region)
+	assert.Equal(t, "1.2.3.4", result.PublicIp)
+
+	team, _ := result.Tags.GetString("team")
+	assert.Equal(t, "red", team)
+}
+
+func TestGCP(t *testing.T) {
+	server := startServer(t, map[string]string{
+		"GET /computeMetadata/v1/": `{
+ "instance": {"id": 8123456789012345678, "name": "web", "hostname": "web.c.proj.internal",
+   "machineType": "projects/1/machineTypes/e2-medium",
+   "zone": "projects/1/zones/us-central1-a", "tags": ["http-server"],
+   "networkInterfaces": [{"ip": "10.128.0.2", "network": "projects/1/networks/default",
+      "accessConfigs": [{"externalIp": "5.6.7.8"}]}]},
+ "project": {"projectId": "proj"}}`,
+	}, func(r *http.Request) bool {
+		return r.Header.Get("Metadata-Flavor") == "Google"
+	})
+	defer setURLs(server.URL)()
+
+	result, err := GetMetadata(context.Background(), "gcp", 2*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "8123456789012345678", result.InstanceId)
+	assert.Equal(t, "e2-medium", result.InstanceType)
+	assert.Equal(t, "us-central1", result.Region)
+	assert.Equal(t, "us-central1-a", result.Zone)
+	assert.Equal(t, "default", result.VpcId)
+	assert.Equal(t, []string{"http-server"}, result.SecurityGroups)
+}
+
+func TestNotCloud(t *testing.T) {
+	server := startServer(t, map[string]string{}, nil)
+	defer setURLs(server.URL)()
+
+	_, err := GetMetadata(context.Background(), "", 2*time.Second)
+	assert.Equal(t, errNotFound, err)
+}
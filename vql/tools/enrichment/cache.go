@@ -0,0 +1,109 @@
+package enrichment
+
+import (
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// By default cached lookups are valid for a week.
+	DEFAULT_MAX_AGE = 7 * 24 * 60 * 60
+)
+
+type cacheEntry struct {
+	Timestamp int64  `json:"timestamp"`
+	Found     bool   `json:"found"`
+	Response  string `json:"response"`
+}
+
+// Lookup results are cached in the datastore so repeated lookups of
+// the same hash (e.g. when enriching hunt results from many
+// endpoints) do not consume the API quota.
+type Cache struct {
+	config_obj   *config_proto.Config
+	path_manager *paths.EnrichmentPathManager
+	max_age      time.Duration
+}
+
+func NewCache(config_obj *config_proto.Config,
+	service string, max_age int64) *Cache {
+	if max_age == 0 {
+		max_age = DEFAULT_MAX_AGE
+	}
+
+	return &Cache{
+		config_obj:   config_obj,
+		path_manager: paths.NewEnrichmentPathManager(service),
+		max_age:      time.Duration(max_age) * time.Second,
+	}
+}
+
+func (self *Cache) getRawDB() (datastore.RawDataStore, bool) {
+	// On the client there is no datastore so we do not cache.
+	if self.config_obj == nil || self.config_obj.Datastore == nil {
+		return nil, false
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return nil, false
+	}
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	return raw_db, ok
+}
+
+func (self *Cache) Get(key string) (*cacheEntry, bool) {
+	// A negative max age disables the cache.
+	if self.max_age < 0 {
+		return nil, false
+	}
+
+	raw_db, ok := self.getRawDB()
+	if !ok {
+		return nil, false
+	}
+
+	data, err := raw_db.GetBuffer(self.config_obj, self.path_manager.Cache(key))
+	if err != nil || len(data) == 0 {
+		return nil, false
+	}
+
+	entry := &cacheEntry{}
+	err = json.Unmarshal(data, entry)
+	if err != nil {
+		return nil, false
+	}
+
+	// Expired entries are refreshed.
+	age := utils.GetTime().Now().Sub(time.Unix(entry.Timestamp, 0))
+	if age > self.max_age {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+func (self *Cache) Set(key string, found bool, response []byte) error {
+	raw_db, ok := self.getRawDB()
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(&cacheEntry{
+		Timestamp: utils.GetTime().Now().Unix(),
+		Found:     found,
+		Response:  string(response),
+	})
+	if err != nil {
+		return err
+	}
+
+	return raw_db.SetBuffer(self.config_obj, self.path_manager.Cache(key),
+		data, utils.BackgroundWriter)
+}
@@ -0,0 +1,210 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	vt_response = `{"data": {"id": "abc", "attributes": {
+  "sha256": "abc",
+  "last_analysis_stats": {"malicious": 10, "undetected": 50},
+  "names": ["evil.exe"]
+}}}`
+
+	mb_response = `{"query_status": "ok", "data": [{
+  "sha256_hash": "abc",
+  "file_name": "evil.exe",
+  "signature": "AgentTesla",
+  "tags": ["exe"]
+}]}`
+)
+
+type EnrichmentTestSuite struct {
+	test_utils.TestSuite
+
+	mu       sync.Mutex
+	requests []string
+	server   *httptest.Server
+}
+
+func (self *EnrichmentTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	self.requests = nil
+	self.server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			self.mu.Lock()
+			defer self.mu.Unlock()
+
+			self.requests = append(self.requests, r.Method+" "+r.URL.Path)
+
+			switch r.URL.Path {
+			case "/vt/files/abc":
+				if r.Header.Get("x-apikey") == "" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				fmt.Fprint(w, vt_response)
+
+			case "/vt/files/throttled":
+				w.WriteHeader(http.StatusTooManyRequests)
+
+			case "/mb/":
+				_ = r.ParseForm()
+				if r.Form.Get("hash") != "abc" {
+					fmt.Fprint(w, `{"query_status": "hash_not_found"}`)
+					return
+				}
+				fmt.Fprint(w, mb_response)
+
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+	virustotal_url = self.server.URL + "/vt"
+	malwarebazaar_url = self.server.URL + "/mb/"
+}
+
+func (self *EnrichmentTestSuite) TearDownTest() {
+	self.server.Close()
+	self.TestSuite.TearDownTest()
+}
+
+func (self *EnrichmentTestSuite) getRequests() []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return append([]string{}, self.requests...)
+}
+
+func (self *EnrichmentTestSuite) makeScope() (vfilter.Scope, *strings.Builder) {
+	log_buffer := &strings.Builder{}
+	builder := services.ScopeBuilder{
+		Config:     self.ConfigObj,
+		ACLManager: acl_managers.NullACLManager{},
+		Logger:     log.New(log_buffer, "vql: ", 0),
+		Env:        ordereddict.NewDict(),
+	}
+
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	return manager.BuildScope(builder), log_buffer
+}
+
+func (self *EnrichmentTestSuite) TestVTLookup() {
+	ctx := context.Background()
+	scope, _ := self.makeScope()
+	defer scope.Close()
+
+	args := ordereddict.NewDict().
+		Set("hash", "abc").
+		Set("key", "secret").
+		Set("rate", -1)
+
+	result, ok := VTLookupFunction{}.Call(ctx, scope, args).(*ordereddict.Dict)
+	assert.True(self.T(), ok)
+	assert.Equal(self.T(), true, getField(result, "Found"))
+	assert.Equal(self.T(), false, getField(result, "Cached"))
+	assert.Equal(self.T(), int64(10), getField(result, "Malicious"))
+
+	// The second lookup comes from the cache.
+	result, ok = VTLookupFunction{}.Call(ctx, scope, args).(*ordereddict.Dict)
+	assert.True(self.T(), ok)
+	assert.Equal(self.T(), true, getField(result, "Cached"))
+	assert.Equal(self.T(), int64(10), getField(result, "Malicious"))
+	assert.Equal(self.T(), []string{"GET /vt/files/abc"}, self.getRequests())
+
+	// Unknown hashes are cached too.
+	args.Set("hash", "unknown")
+	for i := 0; i < 2; i++ {
+		result, ok = VTLookupFunction{}.Call(ctx, scope, args).(*ordereddict.Dict)
+		assert.True(self.T(), ok)
+		assert.Equal(self.T(), false, getField(result, "Found"))
+	}
+	assert.Equal(self.T(), 2, len(self.getRequests()))
+
+	// Disabling the cache forces a new request.
+	args.Set("max_age", -1)
+	VTLookupFunction{}.Call(ctx, scope, args)
+	assert.Equal(self.T(), 3, len(self.getRequests()))
+}
+
+func (self *EnrichmentTestSuite) TestVTQuota() {
+	ctx := context.Background()
+	scope, log_buffer := self.makeScope()
+	defer scope.Close()
+
+	args := ordereddict.NewDict().
+		Set("hash", "throttled").
+		Set("key", "quota_key").
+		Set("rate", -1).
+		Set("daily_quota", 5)
+
+	// The server tells us we are throttled, so the quota is exhausted
+	// for the rest of the day and no further requests are made.
+	for i := 0; i < 3; i++ {
+		result := VTLookupFunction{}.Call(ctx, scope, args)
+		assert.Equal(self.T(), vfilter.Null{}, result)
+	}
+	assert.Equal(self.T(), 1, len(self.getRequests()))
+	assert.Contains(self.T(), log_buffer.String(), "Daily quota exceeded")
+
+	// The daily quota is enforced locally.
+	args.Set("hash", "abc").Set("key", "other_key").Set("daily_quota", 1)
+	args.Set("max_age", -1)
+	VTLookupFunction{}.Call(ctx, scope, args)
+	result := VTLookupFunction{}.Call(ctx, scope, args)
+	assert.Equal(self.T(), vfilter.Null{}, result)
+	assert.Equal(self.T(), 2, len(self.getRequests()))
+}
+
+func (self *EnrichmentTestSuite) TestMBLookup() {
+	ctx := context.Background()
+	scope, _ := self.makeScope()
+	defer scope.Close()
+
+	args := ordereddict.NewDict().
+		Set("hash", "abc").
+		Set("key", "secret")
+
+	result, ok := MBLookupFunction{}.Call(ctx, scope, args).(*ordereddict.Dict)
+	assert.True(self.T(), ok)
+	assert.Equal(self.T(), true, getField(result, "Found"))
+	assert.Equal(self.T(), "AgentTesla", getField(result, "Signature"))
+
+	result, ok = MBLookupFunction{}.Call(ctx, scope, args).(*ordereddict.Dict)
+	assert.True(self.T(), ok)
+	assert.Equal(self.T(), true, getField(result, "Cached"))
+
+	args.Set("hash", "unknown")
+	result, ok = MBLookupFunction{}.Call(ctx, scope, args).(*ordereddict.Dict)
+	assert.True(self.T(), ok)
+	assert.Equal(self.T(), false, getField(result, "Found"))
+
+	assert.Equal(self.T(), []string{"POST /mb/", "POST /mb/"}, self.getRequests())
+}
+
+func getField(dict *ordereddict.Dict, field string) interface{} {
+	value, _ := dict.Get(field)
+	return value
+}
+
+func TestEnrichment(t *testing.T) {
+	suite.Run(t, &EnrichmentTestSuite{})
+}
@@ -0,0 +1,182 @@
+package enrichment
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	MALWAREBAZAAR_METADATA_KEY = "MalwareBazaarKey"
+
+	MALWAREBAZAAR_DEFAULT_RATE = 60
+)
+
+var (
+	malwarebazaar_url = "https://mb-api.abuse.ch/api/v1/"
+)
+
+type MBLookupFunctionArgs struct {
+	Hash       string  `vfilter:"required,field=hash,doc=The MD5, SHA1 or SHA256 hash to look up."`
+	Key        string  `vfilter:"optional,field=key,doc=The MalwareBazaar API key (default the MalwareBazaarKey server metadata)."`
+	MaxAge     int64   `vfilter:"optional,field=max_age,doc=Use cached results younger than this many seconds (default 1 week, -1 to disable the cache)."`
+	Rate       float64 `vfilter:"optional,field=rate,doc=Maximum number of requests per minute (default 60)."`
+	DailyQuota int64   `vfilter:"optional,field=daily_quota,doc=Maximum number of requests per day (default unlimited)."`
+}
+
+type MBLookupFunction struct{}
+
+func (self MBLookupFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	arg := &MBLookupFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("mb_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, err := checkAccess(scope)
+	if err != nil {
+		scope.Log("mb_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	cache := NewCache(config_obj, "malwarebazaar", arg.MaxAge)
+	entry, pres := cache.Get(arg.Hash)
+	if pres {
+		return parseMBResponse(arg.Hash, entry, true)
+	}
+
+	key, err := getAPIKey(ctx, config_obj, arg.Key, MALWAREBAZAAR_METADATA_KEY)
+	if err != nil {
+		scope.Log("mb_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	if arg.Rate == 0 {
+		arg.Rate = MALWAREBAZAAR_DEFAULT_RATE
+	}
+
+	quota := GetQuota("malwarebazaar", key, arg.Rate, arg.DailyQuota)
+	err = quota.Wait(ctx)
+	if err != nil {
+		scope.Log("mb_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	form := url.Values{}
+	form.Set("query", "get_info")
+	form.Set("hash", arg.Hash)
+
+	req, err := http.NewRequest("POST", malwarebazaar_url,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		scope.Log("mb_lookup: %v", err)
+		return vfilter.Null{}
+	}
+	req.Header.Set("Auth-Key", key)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	status, body, err := doRequest(ctx, scope, config_obj, req)
+	if err != nil {
+		scope.Log("mb_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	if status != http.StatusOK {
+		scope.Log("mb_lookup: MalwareBazaar returned status %v: %v",
+			status, string(body))
+		return vfilter.Null{}
+	}
+
+	response, err := utils.ParseJsonToObject(body)
+	if err != nil {
+		scope.Log("mb_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	// Errors are reported in the query status.
+	var found bool
+	query_status := utils.GetString(response, "query_status")
+	switch query_status {
+	case "ok":
+		found = true
+	case "hash_not_found", "no_results":
+		found = false
+	default:
+		scope.Log("mb_lookup: MalwareBazaar query failed: %v", query_status)
+		return vfilter.Null{}
+	}
+
+	err = cache.Set(arg.Hash, found, body)
+	if err != nil {
+		scope.Log("mb_lookup: %v", err)
+	}
+
+	return parseMBResponse(arg.Hash, &cacheEntry{
+		Timestamp: utils.GetTime().Now().Unix(),
+		Found:     found,
+		Response:  string(body),
+	}, false)
+}
+
+func parseMBResponse(hash string, entry *cacheEntry, cached bool) *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("Hash", hash).
+		Set("Found", entry.Found).
+		Set("Cached", cached).
+		Set("LookupTime", epochToTime(entry.Timestamp))
+
+	if !entry.Found || len(entry.Response) == 0 {
+		return result
+	}
+
+	response, err := utils.ParseJsonToObject([]byte(entry.Response))
+	if err != nil {
+		return result
+	}
+
+	samples, ok := utils.GetAny(response, "data").([]interface{})
+	if !ok || len(samples) == 0 {
+		return result
+	}
+
+	sample, ok := samples[0].(*ordereddict.Dict)
+	if !ok {
+		return result
+	}
+
+	return result.
+		Set("SHA256", utils.GetString(sample, "sha256_hash")).
+		Set("FileName", utils.GetString(sample, "file_name")).
+		Set("FileType", utils.GetString(sample, "file_type")).
+		Set("Signature", utils.GetString(sample, "signature")).
+		Set("Tags", utils.GetAny(sample, "tags")).
+		Set("FirstSeen", utils.GetString(sample, "first_seen")).
+		Set("LastSeen", utils.GetString(sample, "last_seen")).
+		Set("Data", sample)
+}
+
+func (self MBLookupFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "mb_lookup",
+		Doc:      "Look up a file hash on MalwareBazaar.",
+		ArgType:  type_map.AddType(scope, &MBLookupFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.COLLECT_SERVER).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&MBLookupFunction{})
+}
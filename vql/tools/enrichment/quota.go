@@ -0,0 +1,98 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	quotaExceededError = errors.New("Daily quota exceeded")
+
+	quota_mu sync.Mutex
+	quotas   = make(map[string]*Quota)
+)
+
+// Quotas are tracked per service and API key in the process so all
+// queries using the same key (e.g. a notebook enriching the results
+// of a large hunt) share the same budget.
+type Quota struct {
+	mu sync.Mutex
+
+	limiter     *rate.Limiter
+	daily_quota int64
+
+	// The current UTC day and the number of requests made in it.
+	day   string
+	count int64
+}
+
+// Wait until a request may be made or return an error if the daily
+// quota is used up.
+func (self *Quota) Wait(ctx context.Context) error {
+	self.mu.Lock()
+	today := utils.GetTime().Now().UTC().Format("2006-01-02")
+	if today != self.day {
+		self.day = today
+		self.count = 0
+	}
+
+	if self.daily_quota > 0 && self.count >= self.daily_quota {
+		self.mu.Unlock()
+		return quotaExceededError
+	}
+	self.count++
+	limiter := self.limiter
+	self.mu.Unlock()
+
+	return limiter.Wait(ctx)
+}
+
+// Mark the quota as used up for the day, e.g. when the service tells
+// us we made too many requests.
+func (self *Quota) Exhaust() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.daily_quota > 0 {
+		self.count = self.daily_quota
+	}
+}
+
+func (self *Quota) update(per_minute float64, daily_quota int64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	limit := rate.Inf
+	if per_minute > 0 {
+		limit = rate.Limit(per_minute / 60)
+	}
+
+	if self.limiter.Limit() != limit {
+		self.limiter.SetLimit(limit)
+	}
+	self.daily_quota = daily_quota
+}
+
+// Get the quota for the service and key. The limits are updated from
+// the most recent caller.
+func GetQuota(service, key string,
+	per_minute float64, daily_quota int64) *Quota {
+	quota_mu.Lock()
+	defer quota_mu.Unlock()
+
+	name := service + ":" + key
+	quota, pres := quotas[name]
+	if !pres {
+		quota = &Quota{
+			limiter: rate.NewLimiter(rate.Inf, 1),
+		}
+		quotas[name] = quota
+	}
+
+	quota.update(per_minute, daily_quota)
+	return quota
+}
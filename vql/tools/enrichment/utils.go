@@ -0,0 +1,87 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"www.velocidex.com/golang/velociraptor/acls"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	// API responses should be small.
+	MAX_RESPONSE_SIZE = 10 * 1024 * 1024
+)
+
+// Enrichment functions make requests to external services so they
+// need the same permission as http_client().
+func checkAccess(scope vfilter.Scope) (*config_proto.Config, error) {
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_SERVER)
+	if err != nil {
+		return nil, err
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		return nil, errors.New("Command can only run on the server")
+	}
+	return config_obj, nil
+}
+
+// Use the API key given by the caller, or fall back to the key stored
+// in the server metadata (this is what the Server.Enrichment
+// artifacts use).
+func getAPIKey(ctx context.Context,
+	config_obj *config_proto.Config, key, metadata_key string) (string, error) {
+	if key != "" {
+		return key, nil
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, err := client_info_manager.GetMetadata(ctx, "server")
+	if err != nil {
+		return "", err
+	}
+
+	key, _ = metadata.GetString(metadata_key)
+	if key == "" {
+		return "", errors.New("No API key specified and " + metadata_key +
+			" is not set in the server metadata")
+	}
+
+	return key, nil
+}
+
+// Make the request and return the status code and the response body.
+func doRequest(ctx context.Context, scope vfilter.Scope,
+	config_obj *config_proto.Config, req *http.Request) (int, []byte, error) {
+	client, err := networking.GetDefaultHTTPClient(
+		ctx, config_obj.Client, scope, "", nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, MAX_RESPONSE_SIZE))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return resp.StatusCode, body, nil
+}
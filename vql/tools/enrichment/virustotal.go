@@ -0,0 +1,336 @@
+package enrichment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	VIRUSTOTAL_METADATA_KEY = "VirustotalKey"
+
+	// The limits of the public API.
+	VIRUSTOTAL_DEFAULT_RATE        = 4
+	VIRUSTOTAL_DEFAULT_DAILY_QUOTA = 500
+
+	// Larger files need a special upload URL.
+	VIRUSTOTAL_MAX_UPLOAD_SIZE = 32 * 1024 * 1024
+)
+
+var (
+	virustotal_url = "https://www.virustotal.com/api/v3"
+)
+
+type VTLookupFunctionArgs struct {
+	Hash       string  `vfilter:"required,field=hash,doc=The MD5, SHA1 or SHA256 hash to look up."`
+	Key        string  `vfilter:"optional,field=key,doc=The VirusTotal API key (default the VirustotalKey server metadata)."`
+	MaxAge     int64   `vfilter:"optional,field=max_age,doc=Use cached results younger than this many seconds (default 1 week, -1 to disable the cache)."`
+	Rate       float64 `vfilter:"optional,field=rate,doc=Maximum number of requests per minute (default 4)."`
+	DailyQuota int64   `vfilter:"optional,field=daily_quota,doc=Maximum number of requests per day (default 500, -1 for unlimited)."`
+}
+
+type VTLookupFunction struct{}
+
+func (self VTLookupFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	arg := &VTLookupFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("vt_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, err := checkAccess(scope)
+	if err != nil {
+		scope.Log("vt_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	cache := NewCache(config_obj, "virustotal", arg.MaxAge)
+	entry, pres := cache.Get(arg.Hash)
+	if pres {
+		return parseVTResponse(arg.Hash, entry, true)
+	}
+
+	key, err := getAPIKey(ctx, config_obj, arg.Key, VIRUSTOTAL_METADATA_KEY)
+	if err != nil {
+		scope.Log("vt_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	quota := getVTQuota(key, arg.Rate, arg.DailyQuota)
+	err = quota.Wait(ctx)
+	if err != nil {
+		scope.Log("vt_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	req, err := http.NewRequest("GET",
+		virustotal_url+"/files/"+url.PathEscape(arg.Hash), nil)
+	if err != nil {
+		scope.Log("vt_lookup: %v", err)
+		return vfilter.Null{}
+	}
+	req.Header.Set("x-apikey", key)
+	req.Header.Set("Accept", "application/json")
+
+	status, body, err := doRequest(ctx, scope, config_obj, req)
+	if err != nil {
+		scope.Log("vt_lookup: %v", err)
+		return vfilter.Null{}
+	}
+
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// Unknown hashes are also cached.
+
+	case http.StatusTooManyRequests:
+		quota.Exhaust()
+		scope.Log("vt_lookup: VirusTotal quota exceeded")
+		return vfilter.Null{}
+
+	default:
+		scope.Log("vt_lookup: VirusTotal returned status %v: %v",
+			status, string(body))
+		return vfilter.Null{}
+	}
+
+	found := status == http.StatusOK
+	err = cache.Set(arg.Hash, found, body)
+	if err != nil {
+		scope.Log("vt_lookup: %v", err)
+	}
+
+	return parseVTResponse(arg.Hash, &cacheEntry{
+		Timestamp: utils.GetTime().Now().Unix(),
+		Found:     found,
+		Response:  string(body),
+	}, false)
+}
+
+func getVTQuota(key string, per_minute float64, daily_quota int64) *Quota {
+	if per_minute == 0 {
+		per_minute = VIRUSTOTAL_DEFAULT_RATE
+	}
+
+	if daily_quota == 0 {
+		daily_quota = VIRUSTOTAL_DEFAULT_DAILY_QUOTA
+	}
+
+	return GetQuota("virustotal", key, per_minute, daily_quota)
+}
+
+func epochToTime(epoch int64) vfilter.Any {
+	if epoch == 0 {
+		return vfilter.Null{}
+	}
+	return time.Unix(epoch, 0).UTC()
+}
+
+func parseVTResponse(hash string, entry *cacheEntry, cached bool) *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("Hash", hash).
+		Set("Found", entry.Found).
+		Set("Cached", cached).
+		Set("LookupTime", epochToTime(entry.Timestamp))
+
+	if !entry.Found || len(entry.Response) == 0 {
+		return result
+	}
+
+	response, err := utils.ParseJsonToObject([]byte(entry.Response))
+	if err != nil {
+		return result
+	}
+
+	attributes, ok := utils.GetAny(response, "data.attributes").(*ordereddict.Dict)
+	if !ok {
+		return result
+	}
+
+	stats := "last_analysis_stats."
+	return result.
+		Set("SHA256", utils.GetString(attributes, "sha256")).
+		Set("Malicious", utils.GetInt64(attributes, stats+"malicious")).
+		Set("Suspicious", utils.GetInt64(attributes, stats+"suspicious")).
+		Set("Undetected", utils.GetInt64(attributes, stats+"undetected")).
+		Set("Harmless", utils.GetInt64(attributes, stats+"harmless")).
+		Set("Reputation", utils.GetInt64(attributes, "reputation")).
+		Set("Label", utils.GetString(attributes,
+			"popular_threat_classification.suggested_threat_label")).
+		Set("TypeDescription", utils.GetString(attributes, "type_description")).
+		Set("Names", utils.GetAny(attributes, "names")).
+		Set("Tags", utils.GetAny(attributes, "tags")).
+		Set("FirstSubmitted", epochToTime(
+			utils.GetInt64(attributes, "first_submission_date"))).
+		Set("LastAnalysis", epochToTime(
+			utils.GetInt64(attributes, "last_analysis_date"))).
+		Set("Data", attributes)
+}
+
+func (self VTLookupFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "vt_lookup",
+		Doc:      "Look up a file hash on VirusTotal.",
+		ArgType:  type_map.AddType(scope, &VTLookupFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.COLLECT_SERVER).Build(),
+	}
+}
+
+type VTSubmitFunctionArgs struct {
+	Path       *accessors.OSPath `vfilter:"required,field=path,doc=The file to submit."`
+	Accessor   string            `vfilter:"optional,field=accessor,doc=The accessor to use."`
+	Key        string            `vfilter:"optional,field=key,doc=The VirusTotal API key (default the VirustotalKey server metadata)."`
+	Rate       float64           `vfilter:"optional,field=rate,doc=Maximum number of requests per minute (default 4)."`
+	DailyQuota int64             `vfilter:"optional,field=daily_quota,doc=Maximum number of requests per day (default 500, -1 for unlimited)."`
+}
+
+type VTSubmitFunction struct{}
+
+func (self VTSubmitFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	arg := &VTSubmitFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, err := checkAccess(scope)
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+
+	err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+
+	key, err := getAPIKey(ctx, config_obj, arg.Key, VIRUSTOTAL_METADATA_KEY)
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+
+	accessor, err := accessors.GetAccessor(arg.Accessor, scope)
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+
+	fd, err := accessor.OpenWithOSPath(arg.Path)
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+	defer fd.Close()
+
+	// Build the multipart form in memory - the file size is limited
+	// anyway.
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", arg.Path.Basename())
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+
+	n, err := io.Copy(part, io.LimitReader(fd, VIRUSTOTAL_MAX_UPLOAD_SIZE+1))
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+
+	if n > VIRUSTOTAL_MAX_UPLOAD_SIZE {
+		scope.Log("vt_submit: %v is larger than the maximum upload size (%v bytes)",
+			arg.Path.String(), VIRUSTOTAL_MAX_UPLOAD_SIZE)
+		return vfilter.Null{}
+	}
+	writer.Close()
+
+	quota := getVTQuota(key, arg.Rate, arg.DailyQuota)
+	err = quota.Wait(ctx)
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+
+	req, err := http.NewRequest("POST", virustotal_url+"/files", body)
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+	req.Header.Set("x-apikey", key)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	status, response, err := doRequest(ctx, scope, config_obj, req)
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+
+	if status == http.StatusTooManyRequests {
+		quota.Exhaust()
+		scope.Log("vt_submit: VirusTotal quota exceeded")
+		return vfilter.Null{}
+	}
+
+	if status != http.StatusOK {
+		scope.Log("vt_submit: VirusTotal returned status %v: %v",
+			status, string(response))
+		return vfilter.Null{}
+	}
+
+	parsed, err := utils.ParseJsonToObject(response)
+	if err != nil {
+		scope.Log("vt_submit: %v", err)
+		return vfilter.Null{}
+	}
+
+	// The file will be analyzed in the background. Use vt_lookup()
+	// later to get the results.
+	analysis_id := utils.GetString(parsed, "data.id")
+	return ordereddict.NewDict().
+		Set("Path", arg.Path).
+		Set("Size", n).
+		Set("AnalysisId", analysis_id).
+		Set("Link", fmt.Sprintf(
+			"https://www.virustotal.com/gui/file-analysis/%v", analysis_id))
+}
+
+func (self VTSubmitFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "vt_submit",
+		Doc:     "Submit a file to VirusTotal for analysis.",
+		ArgType: type_map.AddType(scope, &VTSubmitFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(
+			acls.COLLECT_SERVER, acls.FILESYSTEM_READ).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&VTLookupFunction{})
+	vql_subsystem.RegisterFunction(&VTSubmitFunction{})
+}
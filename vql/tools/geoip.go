@@ -2,10 +2,11 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"net"
 
 	"github.com/Velocidex/ordereddict"
-	"github.com/oschwald/maxminddb-golang"
+	"www.velocidex.com/golang/velociraptor/services/geoip"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/vfilter"
 	"www.velocidex.com/golang/vfilter/arg_parser"
@@ -13,11 +14,77 @@ import (
 
 const (
 	geoIPHandle = "$GeoIPDB"
+
+	// Default managed databases.
+	DEFAULT_GEOIP_EDITION = "GeoLite2-City"
+	DEFAULT_ASN_EDITION   = "GeoLite2-ASN"
 )
 
+// Open the database at the path given, or the managed database
+// edition on the server.
+func getGeoIPDatabase(scope vfilter.Scope,
+	path, edition string) (geoip.Database, error) {
+
+	// Cache key based on the database name.
+	key := geoIPHandle + path
+	if path == "" {
+		key = geoIPHandle + "edition:" + edition
+	}
+
+	cached := vql_subsystem.CacheGet(scope, key)
+	switch t := cached.(type) {
+
+	case error:
+		// Only report failures once.
+		return nil, nil
+
+	case geoip.Database:
+		return t, nil
+
+	case nil:
+
+	default:
+		// Unexpected value in cache.
+		return nil, nil
+	}
+
+	var db geoip.Database
+	var err error
+
+	if path != "" {
+		db, err = geoip.OpenFile(path)
+		if err == nil {
+			// Attach the database to the root destructor since it
+			// does not need to change very often.
+			vql_subsystem.GetRootScope(scope).
+				AddDestructor(func() { db.Close() })
+			vql_subsystem.CacheSet(scope, key, db)
+			return db, nil
+		}
+
+	} else {
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			err = errors.New("db must be specified when not running on the server")
+		} else {
+			// Managed databases are refreshed by the update service
+			// so they are not cached in the scope.
+			db, err = geoip.GetManagedDatabase(config_obj, edition)
+			if err == nil {
+				return db, nil
+			}
+		}
+	}
+
+	// Cache failures for next lookup.
+	vql_subsystem.CacheSet(scope, key, err)
+	return nil, err
+}
+
 type GeoIPFunctionArgs struct {
 	IP       string `vfilter:"required,field=ip,doc=IP Address to lookup."`
-	Database string `vfilter:"required,field=db,doc=Path to the MaxMind GeoIP Database."`
+	Database string `vfilter:"optional,field=db,doc=Path to the MaxMind GeoIP or IP2Location BIN Database."`
+	Edition  string `vfilter:"optional,field=edition,doc=The managed database to use if db is not specified (default GeoLite2-City)."`
 }
 
 type GeoIPFunction struct{}
@@ -34,35 +101,17 @@ func (self GeoIPFunction) Call(
 		return vfilter.Null{}
 	}
 
-	var db *maxminddb.Reader
-
-	// Cache key based on the database name.
-	key := geoIPHandle + arg.Database
-	cached := vql_subsystem.CacheGet(scope, key)
-	switch t := cached.(type) {
+	if arg.Edition == "" {
+		arg.Edition = DEFAULT_GEOIP_EDITION
+	}
 
-	case error:
+	db, err := getGeoIPDatabase(scope, arg.Database, arg.Edition)
+	if err != nil {
+		scope.Log("geoip: %v", err)
 		return vfilter.Null{}
+	}
 
-	case nil:
-		db, err = maxminddb.Open(arg.Database)
-		if err != nil {
-			scope.Log("geoip: %v", err)
-			// Cache failures for next lookup.
-			vql_subsystem.CacheSet(scope, key, err)
-			return vfilter.Null{}
-		}
-		// Attach the database to the root destructor since it
-		// does not need to change very often.
-		vql_subsystem.GetRootScope(scope).
-			AddDestructor(func() { db.Close() })
-		vql_subsystem.CacheSet(scope, key, db)
-
-	case *maxminddb.Reader:
-		db = t
-
-	default:
-		// Unexpected value in cache.
+	if db == nil {
 		return vfilter.Null{}
 	}
 
@@ -72,8 +121,7 @@ func (self GeoIPFunction) Call(
 		return vfilter.Null{}
 	}
 
-	var record interface{}
-	err = db.Lookup(ip, &record)
+	record, err := db.Lookup(ip)
 	if err != nil {
 		scope.Log("geoip: %v", err)
 		return vfilter.Null{}
@@ -84,12 +132,83 @@ func (self GeoIPFunction) Call(
 func (self GeoIPFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
 	return &vfilter.FunctionInfo{
 		Name:    "geoip",
-		Doc:     "Lookup an IP Address using the MaxMind GeoIP database.",
+		Doc:     "Lookup an IP Address using the MaxMind GeoIP or IP2Location database.",
 		ArgType: type_map.AddType(scope, &GeoIPFunctionArgs{}),
-		Version: 1,
+		Version: 2,
+	}
+}
+
+type ASNFunctionArgs struct {
+	IP       string `vfilter:"required,field=ip,doc=IP Address to lookup."`
+	Database string `vfilter:"optional,field=db,doc=Path to the MaxMind ASN or ISP Database."`
+	Edition  string `vfilter:"optional,field=edition,doc=The managed database to use if db is not specified (default GeoLite2-ASN)."`
+}
+
+type ASNFunction struct{}
+
+func (self ASNFunction) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	arg := &ASNFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("asn: %v", err)
+		return vfilter.Null{}
+	}
+
+	if arg.Edition == "" {
+		arg.Edition = DEFAULT_ASN_EDITION
+	}
+
+	db, err := getGeoIPDatabase(scope, arg.Database, arg.Edition)
+	if err != nil {
+		scope.Log("asn: %v", err)
+		return vfilter.Null{}
+	}
+
+	if db == nil {
+		return vfilter.Null{}
+	}
+
+	ip := net.ParseIP(arg.IP)
+	if ip == nil {
+		scope.Log("asn: invalid IP %v", arg.IP)
+		return vfilter.Null{}
+	}
+
+	record, err := db.Lookup(ip)
+	if err != nil {
+		scope.Log("asn: %v", err)
+		return vfilter.Null{}
+	}
+
+	// MaxMind ASN and ISP databases use the same field names.
+	fields, ok := record.(map[string]interface{})
+	if !ok {
+		return vfilter.Null{}
+	}
+
+	asn, pres := fields["autonomous_system_number"]
+	if !pres {
+		return vfilter.Null{}
+	}
+
+	return ordereddict.NewDict().
+		Set("ASN", asn).
+		Set("Organization", fields["autonomous_system_organization"])
+}
+
+func (self ASNFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "asn",
+		Doc:     "Lookup the autonomous system of an IP Address using the MaxMind ASN database.",
+		ArgType: type_map.AddType(scope, &ASNFunctionArgs{}),
 	}
 }
 
 func init() {
 	vql_subsystem.RegisterFunction(&GeoIPFunction{})
+	vql_subsystem.RegisterFunction(&ASNFunction{})
 }
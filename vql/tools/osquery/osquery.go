@@ -0,0 +1,301 @@
+package osquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	WINDOWS_PIPE_PREFIX = `\\.\pipe\`
+)
+
+type OSQueryPluginArgs struct {
+	Query  string `vfilter:"required,field=query,doc=The osquery SQL query to run."`
+	Socket string `vfilter:"optional,field=socket,doc=Path to the osquery extension socket (default /var/osquery/osquery.em or \\\\.\\pipe\\osquery.em on Windows)."`
+	Binary string `vfilter:"optional,field=binary,doc=Path to an osqueryi or osqueryd binary to run the query with instead of connecting to a running osqueryd."`
+}
+
+type OSQueryPlugin struct{}
+
+func (self OSQueryPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		// osquery tables can read anything on the system and some
+		// can run commands, so treat this like execve().
+		err := vql_subsystem.CheckAccess(scope, acls.EXECVE)
+		if err != nil {
+			scope.Log("osquery: %v", err)
+			return
+		}
+
+		arg := &OSQueryPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("osquery: %v", err)
+			return
+		}
+
+		if arg.Binary != "" {
+			// Check the config if we are allowed to execve at all.
+			config_obj, ok := artifacts.GetConfig(scope)
+			if ok && config_obj.PreventExecve {
+				scope.Log("osquery: Not allowed to execve by configuration.")
+				return
+			}
+
+			err = runBinary(ctx, scope, arg.Binary, arg.Query, output_chan)
+		} else {
+			err = runSocket(ctx, scope, arg.Socket, arg.Query, output_chan)
+		}
+
+		if err != nil {
+			scope.Log("osquery: %v", err)
+		}
+	}()
+
+	return output_chan
+}
+
+func defaultSocket() string {
+	if runtime.GOOS == "windows" {
+		return WINDOWS_PIPE_PREFIX + "osquery.em"
+	}
+	return "/var/osquery/osquery.em"
+}
+
+// On Windows osqueryd listens on a named pipe which can be opened
+// like a file, otherwise it is a unix domain socket.
+func dialSocket(ctx context.Context, socket string) (io.ReadWriteCloser, error) {
+	if strings.HasPrefix(socket, WINDOWS_PIPE_PREFIX) {
+		return os.OpenFile(socket, os.O_RDWR, 0)
+	}
+
+	dialer := &net.Dialer{}
+	return dialer.DialContext(ctx, "unix", socket)
+}
+
+func runSocket(ctx context.Context, scope vfilter.Scope,
+	socket, query string, output_chan chan vfilter.Row) error {
+	if socket == "" {
+		socket = defaultSocket()
+	}
+
+	conn, err := dialSocket(ctx, socket)
+	if err != nil {
+		return err
+	}
+
+	client := NewExtensionClient(conn)
+
+	// Make sure a stuck osqueryd does not block the query forever.
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-sub_ctx.Done()
+		client.Close()
+	}()
+
+	// The column types allow us to convert the values which are
+	// always sent as strings. Older versions may not support this
+	// so we just fall back to strings.
+	columns, err := client.GetQueryColumns(query)
+	if err != nil {
+		scope.Log("osquery: Unable to get columns: %v", err)
+		columns = nil
+	}
+
+	rows, err := client.Query(query)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		select {
+		case <-ctx.Done():
+			return nil
+		case output_chan <- convertRow(columns, row):
+		}
+	}
+
+	return nil
+}
+
+// Build the row in the order of the columns, converting the values
+// to their declared type.
+func convertRow(columns []map[string]string, row map[string]string) *ordereddict.Dict {
+	result := ordereddict.NewDict()
+
+	for _, column := range columns {
+		for name, column_type := range column {
+			value, pres := row[name]
+			if !pres {
+				continue
+			}
+			result.Set(name, convertValue(column_type, value))
+		}
+	}
+
+	// Any remaining columns are added in a stable order.
+	var extra []string
+	for name := range row {
+		_, pres := result.Get(name)
+		if !pres {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+
+	for _, name := range extra {
+		result.Set(name, row[name])
+	}
+
+	return result
+}
+
+func convertValue(column_type, value string) vfilter.Any {
+	switch column_type {
+	case "INTEGER", "BIGINT":
+		parsed, err := strconv.ParseInt(value, 0, 64)
+		if err == nil {
+			return parsed
+		}
+
+	case "UNSIGNED_BIGINT":
+		parsed, err := strconv.ParseUint(value, 0, 64)
+		if err == nil {
+			return parsed
+		}
+
+	case "DOUBLE":
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			return parsed
+		}
+	}
+
+	return value
+}
+
+// Run the query using the osquery shell and parse its JSON output.
+func runBinary(ctx context.Context, scope vfilter.Scope,
+	binary, query string, output_chan chan vfilter.Row) error {
+
+	// Kill subprocess when the scope is destroyed.
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err := scope.AddDestructor(cancel)
+	if err != nil {
+		return nil
+	}
+
+	// Report the command we ran for auditing purposes.
+	scope.Log("osquery: Running %v", binary)
+
+	// osqueryd behaves like osqueryi when run with -S
+	argv := []string{"--json", query}
+	if strings.Contains(strings.ToLower(binary), "osqueryd") {
+		argv = append([]string{"-S"}, argv...)
+	}
+
+	command := exec.CommandContext(sub_ctx, binary, argv...)
+	stderr := &bytes.Buffer{}
+	command.Stderr = stderr
+
+	stdout, err := command.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	err = command.Start()
+	if err != nil {
+		return err
+	}
+
+	err = parseJSONRows(sub_ctx, stdout, output_chan)
+
+	// Drain the output so the process can exit.
+	_, _ = io.Copy(ioutil.Discard, stdout)
+
+	wait_err := command.Wait()
+	if stderr.Len() > 0 {
+		scope.Log("osquery: %v", strings.TrimSpace(stderr.String()))
+	}
+
+	if err != nil {
+		return err
+	}
+	return wait_err
+}
+
+// The shell emits a JSON array of objects.
+func parseJSONRows(ctx context.Context,
+	reader io.Reader, output_chan chan vfilter.Row) error {
+	decoder := json.NewDecoder(reader)
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return errors.New("Unexpected output from osquery")
+	}
+
+	for decoder.More() {
+		row := ordereddict.NewDict()
+		err := decoder.Decode(row)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case output_chan <- row:
+		}
+	}
+
+	return nil
+}
+
+func (self OSQueryPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "osquery",
+		Doc:      "Run an osquery query through a running osqueryd or the osquery shell.",
+		ArgType:  type_map.AddType(scope, &OSQueryPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.EXECVE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&OSQueryPlugin{})
+}
@@ -0,0 +1,173 @@
+package osquery
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// A fake osqueryd extension manager which serves canned responses.
+type fakeServer struct {
+	*ExtensionClient
+
+	columns []map[string]string
+	rows    []map[string]string
+	queries []string
+}
+
+func (self *fakeServer) writeI64(value int64) {
+	_ = binary.Write(self.writer, binary.BigEndian, value)
+}
+
+func (self *fakeServer) writeRows(rows []map[string]string) {
+	self.writeByte(typeMap)
+	self.writeI32(int32(len(rows)))
+	for _, row := range rows {
+		self.writeByte(typeString)
+		self.writeByte(typeString)
+		self.writeI32(int32(len(row)))
+		for k, v := range row {
+			self.writeString(k)
+			self.writeString(v)
+		}
+	}
+}
+
+func (self *fakeServer) serve(t *testing.T) {
+	for {
+		header, err := self.readI32()
+		if err != nil {
+			return
+		}
+		assert.Equal(t, int32(thriftCall), header&thriftTypeMask)
+
+		method, _ := self.readString()
+		seq_id, _ := self.readI32()
+
+		var sql string
+		err = self.readStruct(func(field_type byte, id int16) error {
+			sql, err = self.readString()
+			return err
+		})
+		assert.NoError(t, err)
+		self.queries = append(self.queries, method+": "+sql)
+
+		reply := uint32(thriftVersion1 | thriftReply)
+		self.writeI32(int32(reply))
+		self.writeString(method)
+		self.writeI32(seq_id)
+
+		// Result struct with the ExtensionResponse in field 0
+		self.writeFieldHeader(typeStruct, 0)
+
+		// ExtensionStatus
+		self.writeFieldHeader(typeStruct, 1)
+		self.writeFieldHeader(typeI32, 1)
+		if strings.Contains(sql, "error") {
+			self.writeI32(1)
+			self.writeFieldHeader(typeString, 2)
+			self.writeString("no such table: error")
+		} else {
+			self.writeI32(0)
+			self.writeFieldHeader(typeString, 2)
+			self.writeString("OK")
+		}
+
+		// An unknown field should be skipped.
+		self.writeFieldHeader(typeI64, 3)
+		self.writeI64(0)
+		self.writeByte(typeStop)
+
+		self.writeFieldHeader(typeList, 2)
+		if method == "getQueryColumns" {
+			self.writeRows(self.columns)
+		} else {
+			self.writeRows(self.rows)
+		}
+		self.writeByte(typeStop)
+		self.writeByte(typeStop)
+
+		err = self.writer.Flush()
+		if err != nil {
+			return
+		}
+	}
+}
+
+func TestExtensionClient(t *testing.T) {
+	client_conn, server_conn := net.Pipe()
+	defer client_conn.Close()
+
+	server := &fakeServer{
+		ExtensionClient: NewExtensionClient(server_conn),
+		columns: []map[string]string{
+			{"pid": "BIGINT"},
+			{"name": "TEXT"},
+			{"resident_size": "BIGINT"},
+		},
+		rows: []map[string]string{
+			{"pid": "1", "name": "init", "resident_size": "100"},
+			{"pid": "42", "name": "bash", "resident_size": ""},
+		},
+	}
+	go server.serve(t)
+
+	client := NewExtensionClient(client_conn)
+	columns, err := client.GetQueryColumns("SELECT * FROM processes")
+	assert.NoError(t, err)
+
+	rows, err := client.Query("SELECT * FROM processes")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+
+	// Values are converted to the column types and ordered by the
+	// columns.
+	row := convertRow(columns, rows[0])
+	assert.Equal(t, `{"pid":1,"name":"init","resident_size":100}`,
+		json.MustMarshalString(row))
+
+	// Values which can not be converted are left as strings.
+	row = convertRow(columns, rows[1])
+	assert.Equal(t, `{"pid":42,"name":"bash","resident_size":""}`,
+		json.MustMarshalString(row))
+
+	// Errors are reported in the status.
+	_, err = client.Query("SELECT * FROM error")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no such table")
+
+	assert.Equal(t, []string{
+		"getQueryColumns: SELECT * FROM processes",
+		"query: SELECT * FROM processes",
+		"query: SELECT * FROM error",
+	}, server.queries)
+}
+
+func TestParseJSONRows(t *testing.T) {
+	output_chan := make(chan vfilter.Row)
+	var rows []vfilter.Row
+
+	go func() {
+		defer close(output_chan)
+		err := parseJSONRows(context.Background(), strings.NewReader(`[
+  {"name":"osqueryd","version":"5.9.1"},
+  {"name":"osqueryi","version":"5.9.1"}
+]`), output_chan)
+		assert.NoError(t, err)
+	}()
+
+	for row := range output_chan {
+		rows = append(rows, row)
+	}
+
+	assert.Equal(t, 2, len(rows))
+	name, _ := rows[1].(*ordereddict.Dict).GetString("name")
+	assert.Equal(t, "osqueryi", name)
+}
@@ -0,0 +1,404 @@
+package osquery
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A minimal client for the osquery extension manager API. Extensions
+// talk to osqueryd over a socket using the Thrift binary protocol
+// with a buffered transport. We only need the query() and
+// getQueryColumns() calls so we do not bring in the full Thrift
+// library.
+
+const (
+	thriftVersion1 = 0x80010000
+	thriftTypeMask = 0x000000ff
+
+	thriftCall      = 1
+	thriftReply     = 2
+	thriftException = 3
+
+	typeStop   = 0
+	typeBool   = 2
+	typeByte   = 3
+	typeDouble = 4
+	typeI16    = 6
+	typeI32    = 8
+	typeI64    = 10
+	typeString = 11
+	typeStruct = 12
+	typeMap    = 13
+	typeSet    = 14
+	typeList   = 15
+
+	// Protect against corrupted messages.
+	maxStringLength = 100 * 1024 * 1024
+	maxContainerLen = 10 * 1024 * 1024
+)
+
+type ExtensionClient struct {
+	conn   io.ReadWriteCloser
+	reader *bufio.Reader
+	writer *bufio.Writer
+	seq_id int32
+}
+
+func NewExtensionClient(conn io.ReadWriteCloser) *ExtensionClient {
+	return &ExtensionClient{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		writer: bufio.NewWriter(conn),
+	}
+}
+
+func (self *ExtensionClient) Close() error {
+	return self.conn.Close()
+}
+
+// Runs the query and returns the rows. Each row maps column names to
+// their (string) values.
+func (self *ExtensionClient) Query(sql string) ([]map[string]string, error) {
+	return self.call("query", sql)
+}
+
+// Returns the columns of the query in order. Each row maps the
+// column name to its type.
+func (self *ExtensionClient) GetQueryColumns(sql string) ([]map[string]string, error) {
+	return self.call("getQueryColumns", sql)
+}
+
+func (self *ExtensionClient) call(method, sql string) ([]map[string]string, error) {
+	self.seq_id++
+
+	// Both methods take a single string argument.
+	header := uint32(thriftVersion1 | thriftCall)
+	self.writeI32(int32(header))
+	self.writeString(method)
+	self.writeI32(self.seq_id)
+	self.writeFieldHeader(typeString, 1)
+	self.writeString(sql)
+	self.writeByte(typeStop)
+
+	err := self.writer.Flush()
+	if err != nil {
+		return nil, err
+	}
+
+	return self.readReply(method)
+}
+
+func (self *ExtensionClient) writeByte(value byte) {
+	_ = self.writer.WriteByte(value)
+}
+
+func (self *ExtensionClient) writeI32(value int32) {
+	_ = binary.Write(self.writer, binary.BigEndian, value)
+}
+
+func (self *ExtensionClient) writeString(value string) {
+	self.writeI32(int32(len(value)))
+	_, _ = self.writer.WriteString(value)
+}
+
+func (self *ExtensionClient) writeFieldHeader(field_type byte, id int16) {
+	self.writeByte(field_type)
+	_ = binary.Write(self.writer, binary.BigEndian, id)
+}
+
+func (self *ExtensionClient) readReply(method string) ([]map[string]string, error) {
+	header, err := self.readI32()
+	if err != nil {
+		return nil, err
+	}
+
+	if uint32(header)&0xffff0000 != thriftVersion1 {
+		return nil, errors.New("osquery: unsupported thrift protocol version")
+	}
+
+	name, err := self.readString()
+	if err != nil {
+		return nil, err
+	}
+
+	seq_id, err := self.readI32()
+	if err != nil {
+		return nil, err
+	}
+
+	if name != method || seq_id != self.seq_id {
+		return nil, fmt.Errorf("osquery: unexpected reply %v (%v)", name, seq_id)
+	}
+
+	switch header & thriftTypeMask {
+	case thriftReply:
+		return self.readResult()
+
+	case thriftException:
+		// A TApplicationException has the message in field 1.
+		var message string
+		err = self.readStruct(func(field_type byte, id int16) error {
+			if id == 1 && field_type == typeString {
+				message, err = self.readString()
+				return err
+			}
+			return self.skip(field_type)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("osquery: %v", message)
+
+	default:
+		return nil, errors.New("osquery: unexpected message type")
+	}
+}
+
+// The result struct has the ExtensionResponse in field 0.
+func (self *ExtensionClient) readResult() ([]map[string]string, error) {
+	var rows []map[string]string
+	var status_code int32
+	var status_message string
+	var received bool
+
+	err := self.readStruct(func(field_type byte, id int16) error {
+		if id != 0 || field_type != typeStruct {
+			return self.skip(field_type)
+		}
+		received = true
+
+		// ExtensionResponse: 1: ExtensionStatus, 2: list<map<string,string>>
+		return self.readStruct(func(field_type byte, id int16) error {
+			switch {
+			case id == 1 && field_type == typeStruct:
+				return self.readStruct(func(field_type byte, id int16) error {
+					var err error
+					switch {
+					case id == 1 && field_type == typeI32:
+						status_code, err = self.readI32()
+					case id == 2 && field_type == typeString:
+						status_message, err = self.readString()
+					default:
+						err = self.skip(field_type)
+					}
+					return err
+				})
+
+			case id == 2 && field_type == typeList:
+				var err error
+				rows, err = self.readRows()
+				return err
+
+			default:
+				return self.skip(field_type)
+			}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !received {
+		return nil, errors.New("osquery: no response received")
+	}
+
+	if status_code != 0 {
+		return nil, fmt.Errorf("osquery: %v", status_message)
+	}
+
+	return rows, nil
+}
+
+func (self *ExtensionClient) readRows() ([]map[string]string, error) {
+	elem_type, size, err := self.readListHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	if elem_type != typeMap {
+		return nil, errors.New("osquery: unexpected response type")
+	}
+
+	rows := make([]map[string]string, 0, size)
+	for i := 0; i < size; i++ {
+		key_type, err := self.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		value_type, err := self.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		count, err := self.readSize()
+		if err != nil {
+			return nil, err
+		}
+
+		if key_type != typeString || value_type != typeString {
+			return nil, errors.New("osquery: unexpected response type")
+		}
+
+		row := make(map[string]string, count)
+		for j := 0; j < count; j++ {
+			key, err := self.readString()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := self.readString()
+			if err != nil {
+				return nil, err
+			}
+			row[key] = value
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Calls cb for each field in the struct. The callback must consume
+// the field value.
+func (self *ExtensionClient) readStruct(cb func(field_type byte, id int16) error) error {
+	for {
+		field_type, err := self.readByte()
+		if err != nil {
+			return err
+		}
+
+		if field_type == typeStop {
+			return nil
+		}
+
+		var id int16
+		err = binary.Read(self.reader, binary.BigEndian, &id)
+		if err != nil {
+			return err
+		}
+
+		err = cb(field_type, id)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Skip over a value we are not interested in.
+func (self *ExtensionClient) skip(field_type byte) error {
+	var err error
+
+	switch field_type {
+	case typeBool, typeByte:
+		_, err = self.reader.Discard(1)
+	case typeI16:
+		_, err = self.reader.Discard(2)
+	case typeI32:
+		_, err = self.reader.Discard(4)
+	case typeDouble, typeI64:
+		_, err = self.reader.Discard(8)
+	case typeString:
+		_, err = self.readString()
+	case typeStruct:
+		err = self.readStruct(func(field_type byte, id int16) error {
+			return self.skip(field_type)
+		})
+
+	case typeMap:
+		key_type, err := self.readByte()
+		if err != nil {
+			return err
+		}
+
+		value_type, err := self.readByte()
+		if err != nil {
+			return err
+		}
+
+		size, err := self.readSize()
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < size; i++ {
+			err = self.skip(key_type)
+			if err != nil {
+				return err
+			}
+
+			err = self.skip(value_type)
+			if err != nil {
+				return err
+			}
+		}
+
+	case typeSet, typeList:
+		elem_type, size, err := self.readListHeader()
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < size; i++ {
+			err = self.skip(elem_type)
+			if err != nil {
+				return err
+			}
+		}
+
+	default:
+		err = fmt.Errorf("osquery: unknown thrift type %v", field_type)
+	}
+
+	return err
+}
+
+func (self *ExtensionClient) readByte() (byte, error) {
+	return self.reader.ReadByte()
+}
+
+func (self *ExtensionClient) readI32() (int32, error) {
+	var value int32
+	err := binary.Read(self.reader, binary.BigEndian, &value)
+	return value, err
+}
+
+func (self *ExtensionClient) readSize() (int, error) {
+	size, err := self.readI32()
+	if err != nil {
+		return 0, err
+	}
+
+	if size < 0 || size > maxContainerLen {
+		return 0, errors.New("osquery: invalid container size")
+	}
+	return int(size), nil
+}
+
+func (self *ExtensionClient) readListHeader() (byte, int, error) {
+	elem_type, err := self.readByte()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	size, err := self.readSize()
+	return elem_type, size, err
+}
+
+func (self *ExtensionClient) readString() (string, error) {
+	length, err := self.readI32()
+	if err != nil {
+		return "", err
+	}
+
+	if length < 0 || length > maxStringLength {
+		return "", errors.New("osquery: invalid string length")
+	}
+
+	buf := make([]byte, length)
+	_, err = io.ReadFull(self.reader, buf)
+	return string(buf), err
+}
@@ -0,0 +1,213 @@
+package powershell
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	// Each output line may hold a large serialized object.
+	MAX_LINE_SIZE = 10 * 1024 * 1024
+)
+
+var (
+	language_modes = []string{
+		"FullLanguage", "ConstrainedLanguage",
+		"RestrictedLanguage", "NoLanguage",
+	}
+)
+
+// The host script runs in the default runspace of the in process
+// PowerShell host. It creates a fresh runspace in the requested
+// language mode and runs the user's script inside it. The script is
+// embedded base64 encoded so it needs no quoting. Each output object
+// is serialized as a single line of JSON and the results are handed
+// back through the AppDomain's data slots under the given key. The
+// nested pipeline is published there too so it can be stopped when
+// the query is cancelled.
+const hostScript = `
+$ErrorActionPreference = "Stop"
+$key = '%s'
+$script = [System.Text.Encoding]::UTF8.GetString([System.Convert]::FromBase64String('%s'))
+$domain = [System.AppDomain]::CurrentDomain
+$output = New-Object System.Collections.Generic.List[string]
+$errors = New-Object System.Collections.Generic.List[string]
+$iss = [System.Management.Automation.Runspaces.InitialSessionState]::CreateDefault()
+$iss.LanguageMode = [System.Management.Automation.PSLanguageMode]::%s
+$rs = [System.Management.Automation.Runspaces.RunspaceFactory]::CreateRunspace($iss)
+$rs.Open()
+try {
+  $ps = [System.Management.Automation.PowerShell]::Create()
+  $ps.Runspace = $rs
+  $domain.SetData($key + '.ps', $ps)
+  [void]$ps.AddScript($script)
+  try {
+    foreach ($item in $ps.Invoke()) {
+      $output.Add((ConvertTo-Json -InputObject $item -Depth %d -Compress))
+    }
+  } catch {
+    $errors.Add($_.Exception.Message)
+  }
+  foreach ($err in $ps.Streams.Error) {
+    $errors.Add($err.ToString())
+  }
+} finally {
+  $domain.SetData($key + '.ps', $null)
+  $domain.SetData($key + '.output', ($output -join [char]10))
+  $domain.SetData($key + '.errors', ($errors -join [char]10))
+  $rs.Close()
+}
+`
+
+type PowershellPluginArgs struct {
+	Script       string `vfilter:"required,field=script,doc=The PowerShell script to run."`
+	LanguageMode string `vfilter:"optional,field=language_mode,doc=The language mode of the runspace: FullLanguage (default), ConstrainedLanguage, RestrictedLanguage or NoLanguage."`
+	Depth        int64  `vfilter:"optional,field=depth,doc=How deep to serialize output objects (default 2)."`
+}
+
+type PowershellPlugin struct{}
+
+func (self PowershellPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.EXECVE)
+		if err != nil {
+			scope.Log("powershell: %v", err)
+			return
+		}
+
+		// Check the config if we are allowed to execve at all.
+		config_obj, ok := artifacts.GetConfig(scope)
+		if ok && config_obj.PreventExecve {
+			scope.Log("powershell: Not allowed to execve by configuration.")
+			return
+		}
+
+		arg := &PowershellPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("powershell: %v", err)
+			return
+		}
+
+		if arg.LanguageMode == "" {
+			arg.LanguageMode = "FullLanguage"
+		}
+
+		if !utils.InString(language_modes, arg.LanguageMode) {
+			scope.Log("powershell: Invalid language_mode %v, should be one of %v",
+				arg.LanguageMode, strings.Join(language_modes, ", "))
+			return
+		}
+
+		if arg.Depth <= 0 {
+			arg.Depth = 2
+		}
+
+		// Record exactly what we are about to run in the flow log
+		// for auditing purposes.
+		hash := sha256.Sum256([]byte(arg.Script))
+		scope.Log("powershell: Running script in %v mode (sha256 %v):\n%v",
+			arg.LanguageMode, hex.EncodeToString(hash[:]), arg.Script)
+
+		err = runScript(ctx, scope, arg, output_chan)
+		if err != nil {
+			scope.Log("powershell: %v", err)
+		}
+	}()
+
+	return output_chan
+}
+
+func buildHostScript(language_mode string, depth int64,
+	key string, script string) string {
+	return fmt.Sprintf(hostScript, key,
+		base64.StdEncoding.EncodeToString([]byte(script)),
+		language_mode, depth)
+}
+
+// Log each line of the errors reported by the script.
+func logErrors(scope vfilter.Scope, errors string) {
+	for _, line := range strings.Split(errors, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			scope.Log("powershell: %v", line)
+		}
+	}
+}
+
+// Each line holds one output object. Objects become rows directly,
+// other values are wrapped in a row with a single Value column.
+func parseOutput(ctx context.Context,
+	reader io.Reader, output_chan chan vfilter.Row) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), MAX_LINE_SIZE)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var row vfilter.Row
+		if line[0] == '{' {
+			item, err := utils.ParseJsonToObject(line)
+			if err != nil {
+				return err
+			}
+			row = item
+		} else {
+			var value interface{}
+			err := json.Unmarshal(line, &value)
+			if err != nil {
+				// Not JSON - pass it through as is.
+				value = string(line)
+			}
+			row = ordereddict.NewDict().Set("Value", value)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case output_chan <- row:
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (self PowershellPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "powershell",
+		Doc:      "Run a PowerShell script in a dedicated in process runspace and return its output objects as rows.",
+		ArgType:  type_map.AddType(scope, &PowershellPluginArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.EXECVE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&PowershellPlugin{})
+}
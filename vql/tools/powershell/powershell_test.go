@@ -0,0 +1,51 @@
+package powershell
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+	"www.velocidex.com/golang/vfilter"
+)
+
+func TestBuildHostScript(t *testing.T) {
+	script := buildHostScript("ConstrainedLanguage", 3, "key.1", "Get-Date | 'it''s'")
+	assert.Contains(t, script, "$key = 'key.1'")
+	assert.Contains(t, script, "[System.Management.Automation.PSLanguageMode]::ConstrainedLanguage")
+	assert.Contains(t, script, "-Depth 3 -Compress")
+
+	// The user script is embedded encoded so it needs no quoting.
+	encoded := base64.StdEncoding.EncodeToString([]byte("Get-Date | 'it''s'"))
+	assert.Contains(t, script, "FromBase64String('"+encoded+"')")
+	assert.True(t, !strings.Contains(script, "Get-Date"))
+}
+
+func TestParseOutput(t *testing.T) {
+	output_chan := make(chan vfilter.Row)
+	var rows []string
+
+	go func() {
+		defer close(output_chan)
+		err := parseOutput(context.Background(), strings.NewReader(`
+{"Name":"svchost","Id":4,"Modules":{"Count":2}}
+"hello"
+42
+not json
+`), output_chan)
+		assert.NoError(t, err)
+	}()
+
+	for row := range output_chan {
+		rows = append(rows, json.MustMarshalString(row))
+	}
+
+	assert.Equal(t, []string{
+		`{"Name":"svchost","Id":4,"Modules":{"Count":2}}`,
+		`{"Value":"hello"}`,
+		`{"Value":42}`,
+		`{"Value":"not json"}`,
+	}, rows)
+}
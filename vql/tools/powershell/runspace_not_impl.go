@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package powershell
+
+import (
+	"context"
+	"errors"
+
+	"www.velocidex.com/golang/vfilter"
+)
+
+func runScript(ctx context.Context, scope vfilter.Scope,
+	arg *PowershellPluginArgs, output_chan chan vfilter.Row) error {
+	return errors.New("In process runspaces are only supported on Windows")
+}
@@ -0,0 +1,500 @@
+//go:build windows
+// +build windows
+
+// Host PowerShell in process.
+
+// The CLR is loaded into the process through the unmanaged hosting
+// API (mscoree.dll) and System.Management.Automation is driven by
+// reflection over the CLR's COM interfaces. This is how native hosts
+// such as the PowerShell console itself start the runtime, so no
+// powershell.exe process is spawned.
+
+package powershell
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+
+	ole "github.com/go-ole/go-ole"
+	"golang.org/x/sys/windows"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	// S_FALSE is returned by CoInitializeEx if it was already called
+	// on this thread.
+	S_FALSE = 0x00000001
+
+	CLR_VERSION  = "v4.0.30319"
+	SMA_ASSEMBLY = "System.Management.Automation, Version=3.0.0.0, " +
+		"Culture=neutral, PublicKeyToken=31bf3856ad364e35"
+
+	// Vtable indexes of the methods we call.
+	iunknown_Release         = 2
+	metahost_GetRuntime      = 3
+	runtimeinfo_GetInterface = 9
+	corhost_Start            = 10
+	corhost_GetDefaultDomain = 13
+	appdomain_GetType        = 10
+	appdomain_Load_2         = 44
+	assembly_GetType_2       = 17
+	type_InvokeMember_3      = 57
+
+	// System.Reflection.BindingFlags
+	BindingFlags_Instance     = 0x4
+	BindingFlags_Static       = 0x8
+	BindingFlags_Public       = 0x10
+	BindingFlags_InvokeMethod = 0x100
+	BindingFlags_SetProperty  = 0x2000
+
+	INVOKE_STATIC   = BindingFlags_Static | BindingFlags_Public | BindingFlags_InvokeMethod
+	INVOKE_INSTANCE = BindingFlags_Instance | BindingFlags_Public | BindingFlags_InvokeMethod
+	SET_STATIC      = BindingFlags_Static | BindingFlags_Public | BindingFlags_SetProperty
+)
+
+var (
+	modmscoree            = windows.NewLazySystemDLL("mscoree.dll")
+	procCLRCreateInstance = modmscoree.NewProc("CLRCreateInstance")
+
+	modoleaut32               = windows.NewLazySystemDLL("oleaut32.dll")
+	procSafeArrayCreateVector = modoleaut32.NewProc("SafeArrayCreateVector")
+	procSafeArrayPutElement   = modoleaut32.NewProc("SafeArrayPutElement")
+	procSafeArrayDestroy      = modoleaut32.NewProc("SafeArrayDestroy")
+
+	CLSID_CLRMetaHost    = ole.NewGUID("{9280188D-0E8E-4867-B30C-7FA83884E8DE}")
+	IID_ICLRMetaHost     = ole.NewGUID("{D332DB9E-B9B3-4125-8207-A14884F53216}")
+	IID_ICLRRuntimeInfo  = ole.NewGUID("{BD39D1D2-BA2F-486A-89B0-B4B0CB466891}")
+	CLSID_CorRuntimeHost = ole.NewGUID("{CB2F6723-AB3A-11D2-9C40-00C04FA30A3E}")
+	IID_ICorRuntimeHost  = ole.NewGUID("{CB2F6722-AB3A-11D2-9C40-00C04FA30A3E}")
+	IID_AppDomain        = ole.NewGUID("{05F696DC-2B29-3663-AD8B-C4389CF2A713}")
+
+	// The CLR can only be started once in a process, so the host is
+	// shared by all queries.
+	host_mu sync.Mutex
+	host    *clrHost
+
+	script_id uint64
+)
+
+// Call the method at index in the COM object's vtable.
+func comCall(obj unsafe.Pointer, method int, args ...uintptr) error {
+	vtbl := *(*unsafe.Pointer)(obj)
+	fn := *(*uintptr)(unsafe.Add(vtbl, method*int(unsafe.Sizeof(uintptr(0)))))
+
+	hr, _, _ := syscall.SyscallN(fn, append([]uintptr{uintptr(obj)}, args...)...)
+	if int32(hr) < 0 {
+		return ole.NewError(hr)
+	}
+	return nil
+}
+
+func release(obj unsafe.Pointer) {
+	if obj != nil {
+		_ = comCall(obj, iunknown_Release)
+	}
+}
+
+// A VARIANT is passed by value. On 64 bit platforms it is larger than
+// a register so the ABI passes it by reference, while on 32 bit
+// platforms it is pushed onto the stack.
+func variantArg(v *ole.VARIANT) []uintptr {
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		return []uintptr{uintptr(unsafe.Pointer(v))}
+	}
+
+	words := (*[4]uint32)(unsafe.Pointer(v))
+	return []uintptr{uintptr(words[0]), uintptr(words[1]),
+		uintptr(words[2]), uintptr(words[3])}
+}
+
+func newStringVariant(value string) ole.VARIANT {
+	return ole.NewVariant(ole.VT_BSTR,
+		int64(uintptr(unsafe.Pointer(ole.SysAllocString(value)))))
+}
+
+// Build a SAFEARRAY of VARIANTs holding the arguments. The array
+// holds copies so the caller still owns the arguments.
+func newArgsArray(args []*ole.VARIANT) (uintptr, error) {
+	psa, _, err := procSafeArrayCreateVector.Call(
+		uintptr(ole.VT_VARIANT), 0, uintptr(len(args)))
+	if psa == 0 {
+		return 0, err
+	}
+
+	for i, arg := range args {
+		idx := int32(i)
+		hr, _, _ := procSafeArrayPutElement.Call(psa,
+			uintptr(unsafe.Pointer(&idx)), uintptr(unsafe.Pointer(arg)))
+		if int32(hr) < 0 {
+			_, _, _ = procSafeArrayDestroy.Call(psa)
+			return 0, ole.NewError(hr)
+		}
+	}
+
+	return psa, nil
+}
+
+type clrHost struct {
+	domain   unsafe.Pointer // _AppDomain
+	assembly unsafe.Pointer // _Assembly of System.Management.Automation
+
+	// System.AppDomain
+	domain_type unsafe.Pointer
+
+	// Types in System.Management.Automation by name.
+	types map[string]unsafe.Pointer
+}
+
+func startCLR() (*clrHost, error) {
+	var metahost, runtime_info, cor_host, unknown unsafe.Pointer
+
+	hr, _, _ := procCLRCreateInstance.Call(
+		uintptr(unsafe.Pointer(CLSID_CLRMetaHost)),
+		uintptr(unsafe.Pointer(IID_ICLRMetaHost)),
+		uintptr(unsafe.Pointer(&metahost)))
+	if int32(hr) < 0 {
+		return nil, fmt.Errorf("CLRCreateInstance: %w", ole.NewError(hr))
+	}
+	defer release(metahost)
+
+	version, err := syscall.UTF16PtrFromString(CLR_VERSION)
+	if err != nil {
+		return nil, err
+	}
+
+	err = comCall(metahost, metahost_GetRuntime,
+		uintptr(unsafe.Pointer(version)),
+		uintptr(unsafe.Pointer(IID_ICLRRuntimeInfo)),
+		uintptr(unsafe.Pointer(&runtime_info)))
+	if err != nil {
+		return nil, fmt.Errorf("GetRuntime %v: %w", CLR_VERSION, err)
+	}
+	defer release(runtime_info)
+
+	err = comCall(runtime_info, runtimeinfo_GetInterface,
+		uintptr(unsafe.Pointer(CLSID_CorRuntimeHost)),
+		uintptr(unsafe.Pointer(IID_ICorRuntimeHost)),
+		uintptr(unsafe.Pointer(&cor_host)))
+	if err != nil {
+		return nil, fmt.Errorf("GetInterface: %w", err)
+	}
+
+	// Starting an already started runtime is a no-op.
+	err = comCall(cor_host, corhost_Start)
+	if err != nil {
+		return nil, fmt.Errorf("Start: %w", err)
+	}
+
+	err = comCall(cor_host, corhost_GetDefaultDomain,
+		uintptr(unsafe.Pointer(&unknown)))
+	if err != nil {
+		return nil, fmt.Errorf("GetDefaultDomain: %w", err)
+	}
+	defer release(unknown)
+
+	result := &clrHost{types: make(map[string]unsafe.Pointer)}
+	err = comCall(unknown, 0,
+		uintptr(unsafe.Pointer(IID_AppDomain)),
+		uintptr(unsafe.Pointer(&result.domain)))
+	if err != nil {
+		return nil, fmt.Errorf("QueryInterface _AppDomain: %w", err)
+	}
+
+	err = comCall(result.domain, appdomain_GetType,
+		uintptr(unsafe.Pointer(&result.domain_type)))
+	if err != nil {
+		return nil, fmt.Errorf("AppDomain.GetType: %w", err)
+	}
+
+	name := ole.SysAllocString(SMA_ASSEMBLY)
+	defer func() { _ = ole.SysFreeString(name) }()
+
+	err = comCall(result.domain, appdomain_Load_2,
+		uintptr(unsafe.Pointer(name)),
+		uintptr(unsafe.Pointer(&result.assembly)))
+	if err != nil {
+		return nil, fmt.Errorf("Loading System.Management.Automation: %w", err)
+	}
+
+	return result, nil
+}
+
+func getCLRHost() (*clrHost, error) {
+	host_mu.Lock()
+	defer host_mu.Unlock()
+
+	if host != nil {
+		return host, nil
+	}
+
+	result, err := startCLR()
+	if err != nil {
+		return nil, err
+	}
+	host = result
+	return host, nil
+}
+
+func (self *clrHost) getType(name string) (unsafe.Pointer, error) {
+	host_mu.Lock()
+	defer host_mu.Unlock()
+
+	result, pres := self.types[name]
+	if pres {
+		return result, nil
+	}
+
+	bstr := ole.SysAllocString(name)
+	defer func() { _ = ole.SysFreeString(bstr) }()
+
+	err := comCall(self.assembly, assembly_GetType_2,
+		uintptr(unsafe.Pointer(bstr)), uintptr(unsafe.Pointer(&result)))
+	if err != nil {
+		return nil, fmt.Errorf("GetType %v: %w", name, err)
+	}
+	if result == nil {
+		return nil, fmt.Errorf("GetType %v: type not found", name)
+	}
+
+	self.types[name] = result
+	return result, nil
+}
+
+// Invoke a member of the type through Type.InvokeMember(). The caller
+// must clear the returned VARIANT.
+func invokeMember(type_obj unsafe.Pointer, name string, flags uint32,
+	target *ole.VARIANT, args ...*ole.VARIANT) (*ole.VARIANT, error) {
+	bstr := ole.SysAllocString(name)
+	defer func() { _ = ole.SysFreeString(bstr) }()
+
+	psa, err := newArgsArray(args)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _, _, _ = procSafeArrayDestroy.Call(psa) }()
+
+	result := &ole.VARIANT{}
+	ole.VariantInit(result)
+
+	call_args := []uintptr{uintptr(unsafe.Pointer(bstr)), uintptr(flags), 0}
+	call_args = append(call_args, variantArg(target)...)
+	call_args = append(call_args, psa, uintptr(unsafe.Pointer(result)))
+
+	err = comCall(type_obj, type_InvokeMember_3, call_args...)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", name, err)
+	}
+	return result, nil
+}
+
+func (self *clrHost) invoke(type_name, name string, flags uint32,
+	target *ole.VARIANT, args ...*ole.VARIANT) (*ole.VARIANT, error) {
+	type_obj, err := self.getType(type_name)
+	if err != nil {
+		return nil, err
+	}
+
+	if target == nil {
+		target = &ole.VARIANT{}
+	}
+	return invokeMember(type_obj, name, flags, target, args...)
+}
+
+func (self *clrHost) domainVariant() *ole.VARIANT {
+	v := ole.NewVariant(ole.VT_UNKNOWN, int64(uintptr(self.domain)))
+	return &v
+}
+
+// Read (and clear) a data slot of the AppDomain.
+func (self *clrHost) getData(key string) (*ole.VARIANT, error) {
+	name := newStringVariant(key)
+	defer func() { _ = ole.VariantClear(&name) }()
+
+	result, err := invokeMember(self.domain_type, "GetData",
+		INVOKE_INSTANCE, self.domainVariant(), &name)
+	if err != nil {
+		return nil, err
+	}
+
+	null := &ole.VARIANT{}
+	cleared, err := invokeMember(self.domain_type, "SetData",
+		INVOKE_INSTANCE, self.domainVariant(), &name, null)
+	if err == nil {
+		_ = ole.VariantClear(cleared)
+	}
+
+	return result, nil
+}
+
+func (self *clrHost) getString(key string) string {
+	value, err := self.getData(key)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = ole.VariantClear(value) }()
+
+	if value.VT != ole.VT_BSTR {
+		return ""
+	}
+	result, _ := value.Value().(string)
+	return result
+}
+
+// Run the host script in a new default runspace on this thread.
+func (self *clrHost) run(script string) error {
+	runspace, err := self.invoke(
+		"System.Management.Automation.Runspaces.RunspaceFactory",
+		"CreateRunspace", INVOKE_STATIC, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ole.VariantClear(runspace) }()
+
+	for _, step := range []struct {
+		name  string
+		flags uint32
+		args  []*ole.VARIANT
+	}{
+		{"Open", INVOKE_INSTANCE, nil},
+		// The default runspace is per thread, which is why the
+		// caller must be locked to its OS thread.
+		{"DefaultRunspace", SET_STATIC, []*ole.VARIANT{runspace}},
+	} {
+		target := runspace
+		if step.flags&BindingFlags_Static != 0 {
+			target = nil
+		}
+
+		result, err := self.invoke(
+			"System.Management.Automation.Runspaces.Runspace",
+			step.name, step.flags, target, step.args...)
+		if err != nil {
+			return err
+		}
+		_ = ole.VariantClear(result)
+	}
+
+	defer func() {
+		for _, name := range []string{"Close", "Dispose"} {
+			result, err := self.invoke(
+				"System.Management.Automation.Runspaces.Runspace",
+				name, INVOKE_INSTANCE, runspace)
+			if err == nil {
+				_ = ole.VariantClear(result)
+			}
+		}
+	}()
+
+	source := newStringVariant(script)
+	defer func() { _ = ole.VariantClear(&source) }()
+
+	block, err := self.invoke("System.Management.Automation.ScriptBlock",
+		"Create", INVOKE_STATIC, nil, &source)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ole.VariantClear(block) }()
+
+	result, err := self.invoke("System.Management.Automation.ScriptBlock",
+		"InvokeReturnAsIs", INVOKE_INSTANCE, block)
+	if err != nil {
+		return err
+	}
+	_ = ole.VariantClear(result)
+
+	return nil
+}
+
+// Stop the nested pipeline of a running script.
+func (self *clrHost) stop(key string) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	err := coInitialize()
+	if err != nil {
+		return
+	}
+	defer ole.CoUninitialize()
+
+	pipeline, err := self.getData(key + ".ps")
+	if err != nil {
+		return
+	}
+	defer func() { _ = ole.VariantClear(pipeline) }()
+
+	if pipeline.VT != ole.VT_DISPATCH && pipeline.VT != ole.VT_UNKNOWN {
+		return
+	}
+
+	result, err := self.invoke("System.Management.Automation.PowerShell",
+		"Stop", INVOKE_INSTANCE, pipeline)
+	if err == nil {
+		_ = ole.VariantClear(result)
+	}
+}
+
+func coInitialize() error {
+	err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED)
+	if err != nil {
+		oleCode := err.(*ole.OleError).Code()
+		if oleCode != ole.S_OK && oleCode != S_FALSE {
+			return err
+		}
+	}
+	return nil
+}
+
+func runScript(ctx context.Context, scope vfilter.Scope,
+	arg *PowershellPluginArgs, output_chan chan vfilter.Row) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	err := coInitialize()
+	if err != nil {
+		return err
+	}
+	defer ole.CoUninitialize()
+
+	host, err := getCLRHost()
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("velociraptor.powershell.%d",
+		atomic.AddUint64(&script_id, 1))
+
+	// Stop the script when the scope is destroyed.
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err = scope.AddDestructor(cancel)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan bool)
+	defer close(done)
+
+	go func() {
+		select {
+		case <-done:
+		case <-sub_ctx.Done():
+			host.stop(key)
+		}
+	}()
+
+	err = host.run(buildHostScript(arg.LanguageMode, arg.Depth, key, arg.Script))
+	output := host.getString(key + ".output")
+	logErrors(scope, host.getString(key+".errors"))
+	if err != nil {
+		return err
+	}
+
+	return parseOutput(sub_ctx, strings.NewReader(output), output_chan)
+}
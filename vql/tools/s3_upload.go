@@ -4,18 +4,24 @@ package tools
 
 import (
 	"crypto/tls"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/Velocidex/ordereddict"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"golang.org/x/net/context"
 	"www.velocidex.com/golang/velociraptor/accessors"
 	"www.velocidex.com/golang/velociraptor/acls"
 	"www.velocidex.com/golang/velociraptor/artifacts"
 	"www.velocidex.com/golang/velociraptor/uploads"
+	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vql/networking"
@@ -37,6 +43,8 @@ type S3UploadArgs struct {
 	S3UploadRoot         string            `vfilter:"optional,field=s3uploadroot,doc=Prefix for the S3 object"`
 	NoVerifyCert         bool              `vfilter:"optional,field=noverifycert,doc=Skip TLS Verification (deprecated in favor of SkipVerify)"`
 	SkipVerify           bool              `vfilter:"optional,field=skip_verify,doc=Skip TLS Verification"`
+	ObjectLockMode       string            `vfilter:"optional,field=object_lock_mode,doc=Place the object under S3 Object Lock (GOVERNANCE or COMPLIANCE). Defaults to the server's WORM settings."`
+	ObjectLockRetention  int64             `vfilter:"optional,field=object_lock_retention,doc=How long to retain the locked object in seconds."`
 }
 
 type S3UploadFunction struct{}
@@ -80,6 +88,29 @@ func (self *S3UploadFunction) Call(ctx context.Context,
 		arg.Name = arg.File.String()
 	}
 
+	// On a WORM server, lock uploads for the same retention period
+	// as the file store.
+	if arg.ObjectLockMode == "" {
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if ok && config_obj.Datastore != nil &&
+			config_obj.Datastore.WormObjectLockMode != "" {
+			arg.ObjectLockMode = config_obj.Datastore.WormObjectLockMode
+			if arg.ObjectLockRetention == 0 {
+				arg.ObjectLockRetention = int64(
+					config_obj.Datastore.WormRetentionSec)
+			}
+		}
+	}
+
+	var lock *objectLock
+	if arg.ObjectLockMode != "" {
+		lock, err = newObjectLock(arg.ObjectLockMode, arg.ObjectLockRetention)
+		if err != nil {
+			scope.Log("upload_S3: %v", err)
+			return vfilter.Null{}
+		}
+	}
+
 	stat, err := accessor.LstatWithOSPath(arg.File)
 	if err != nil {
 		scope.Log("upload_S3: Unable to stat %s: %v",
@@ -101,6 +132,7 @@ func (self *S3UploadFunction) Call(ctx context.Context,
 			arg.KmsEncryptionKey,
 			arg.S3UploadRoot,
 			arg.NoVerifyCert || arg.SkipVerify,
+			lock,
 			uint64(stat.Size()))
 		if err != nil {
 			scope.Log("upload_S3: %v", err)
@@ -124,6 +156,7 @@ func upload_S3(ctx context.Context, scope vfilter.Scope,
 	kmsEncryptionKey string,
 	s3UploadRoot string,
 	NoVerifyCert bool,
+	lock *objectLock,
 	size uint64) (
 	*uploads.UploadResponse, error) {
 
@@ -195,6 +228,13 @@ func upload_S3(ctx context.Context, scope vfilter.Scope,
 		s3_params.SSEKMSKeyId = aws.String(kmsEncryptionKey)
 	}
 
+	// Object Lock requires an integrity checksum on the upload.
+	if lock != nil {
+		s3_params.ObjectLockMode = aws.String(lock.mode)
+		s3_params.ObjectLockRetainUntilDate = aws.Time(lock.retain_until)
+		s3_params.ChecksumAlgorithm = aws.String(s3.ChecksumAlgorithmSha256)
+	}
+
 	result, err = uploader.UploadWithContext(
 		ctx, s3_params)
 
@@ -213,6 +253,30 @@ func upload_S3(ctx context.Context, scope vfilter.Scope,
 	return response, nil
 }
 
+type objectLock struct {
+	mode         string
+	retain_until time.Time
+}
+
+func newObjectLock(mode string, retention int64) (*objectLock, error) {
+	mode = strings.ToUpper(mode)
+	switch mode {
+	case s3.ObjectLockModeGovernance, s3.ObjectLockModeCompliance:
+	default:
+		return nil, fmt.Errorf("Invalid object lock mode %v", mode)
+	}
+
+	if retention <= 0 {
+		return nil, errors.New("Object lock requires a retention period")
+	}
+
+	return &objectLock{
+		mode: mode,
+		retain_until: utils.GetTime().Now().Add(
+			time.Duration(retention) * time.Second),
+	}, nil
+}
+
 func (self S3UploadFunction) Info(
 	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
 	return &vfilter.FunctionInfo{
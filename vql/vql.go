@@ -98,6 +98,11 @@ func GetFunction(name string) (vfilter.FunctionInterface, bool) {
 	return res, pres
 }
 
+func GetPlugin(name string) (vfilter.PluginGeneratorInterface, bool) {
+	res, pres := exportedPlugins[name]
+	return res, pres
+}
+
 func EnforceVQLAllowList(
 	allowed_plugins []string, allowed_functions []string) error {
 
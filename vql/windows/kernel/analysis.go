@@ -0,0 +1,699 @@
+// Parse and analyze Windows kernel structures.
+//
+// Kernel notification callbacks and driver dispatch tables live in
+// kernel memory, which user space can not read. When a driver
+// providing access to physical memory (e.g. WinPmem) is loaded, we
+// translate kernel addresses through the page tables and read them
+// directly. The locations of the callback arrays are found by
+// scanning the code of the exported registration functions in the
+// kernel image on disk.
+
+package kernel
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// Size of RTL_PROCESS_MODULE_INFORMATION on 64 bit systems.
+	MODULE_INFORMATION_SIZE = 296
+
+	// The notify routine arrays hold this many EX_CALLBACK entries.
+	MAX_NOTIFY_ROUTINES = 64
+
+	IRP_MJ_MAXIMUM_FUNCTION = 0x1b
+
+	IO_TYPE_DRIVER     = 4
+	DRIVER_OBJECT_SIZE = 0x150
+
+	// Offsets into DRIVER_OBJECT
+	DRIVER_START_OFFSET    = 0x18
+	DRIVER_SIZE_OFFSET     = 0x20
+	DRIVER_NAME_OFFSET     = 0x38
+	MAJOR_FUNCTION_OFFSET  = 0x70
+	OBJECT_DIRECTORY_SLOTS = 37
+
+	PAGE_SIZE       = 0x1000
+	PTE_PRESENT     = 1
+	PTE_LARGE_PAGE  = 0x80
+	PTE_ADDRESS_MAX = 0x000ffffffffff000
+)
+
+var (
+	// The exported functions registering each type of callback.
+	NotifyRoutines = []struct {
+		Type   string
+		Export string
+	}{
+		{"Process", "PsSetCreateProcessNotifyRoutine"},
+		{"Thread", "PsSetCreateThreadNotifyRoutine"},
+		{"Image", "PsSetLoadImageNotifyRoutine"},
+	}
+
+	irpMajorFunctions = []string{
+		"IRP_MJ_CREATE", "IRP_MJ_CREATE_NAMED_PIPE", "IRP_MJ_CLOSE",
+		"IRP_MJ_READ", "IRP_MJ_WRITE", "IRP_MJ_QUERY_INFORMATION",
+		"IRP_MJ_SET_INFORMATION", "IRP_MJ_QUERY_EA", "IRP_MJ_SET_EA",
+		"IRP_MJ_FLUSH_BUFFERS", "IRP_MJ_QUERY_VOLUME_INFORMATION",
+		"IRP_MJ_SET_VOLUME_INFORMATION", "IRP_MJ_DIRECTORY_CONTROL",
+		"IRP_MJ_FILE_SYSTEM_CONTROL", "IRP_MJ_DEVICE_CONTROL",
+		"IRP_MJ_INTERNAL_DEVICE_CONTROL", "IRP_MJ_SHUTDOWN",
+		"IRP_MJ_LOCK_CONTROL", "IRP_MJ_CLEANUP",
+		"IRP_MJ_CREATE_MAILSLOT", "IRP_MJ_QUERY_SECURITY",
+		"IRP_MJ_SET_SECURITY", "IRP_MJ_POWER", "IRP_MJ_SYSTEM_CONTROL",
+		"IRP_MJ_DEVICE_CHANGE", "IRP_MJ_QUERY_QUOTA",
+		"IRP_MJ_SET_QUOTA", "IRP_MJ_PNP",
+	}
+
+	// Port and class drivers install their own dispatch routines
+	// into the driver objects of their miniport drivers, so dispatch
+	// routines inside these modules are expected.
+	DefaultDispatchModules = []string{
+		"ntoskrnl.exe", "classpnp.sys", "ndis.sys", "storport.sys",
+		"ataport.sys", "scsiport.sys", "wdf01000.sys", "portcls.sys",
+		"ks.sys", "videoprt.sys", "dxgkrnl.sys", "hidclass.sys",
+		"usbccgp.sys", "serenum.sys", "wmilib.sys", "ndiswan.sys",
+	}
+)
+
+type KernelModule struct {
+	Name           string
+	Path           string
+	KernelPath     string
+	ImageBase      uint64
+	ImageSize      uint32
+	Flags          uint32
+	LoadOrderIndex uint16
+	InitOrderIndex uint16
+	LoadCount      uint16
+}
+
+func (self *KernelModule) Contains(address uint64) bool {
+	return address >= self.ImageBase &&
+		address < self.ImageBase+uint64(self.ImageSize)
+}
+
+// Kernel module paths are given in the kernel namespace,
+// e.g. \SystemRoot\system32\ntoskrnl.exe or \??\C:\Temp\driver.sys
+func normalizeKernelPath(path string) string {
+	system_root := os.Getenv("SystemRoot")
+	if system_root == "" {
+		system_root = `C:\Windows`
+	}
+
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasPrefix(lower, `\systemroot\`):
+		return system_root + path[len(`\SystemRoot`):]
+
+	case strings.HasPrefix(lower, `\??\`):
+		return path[len(`\??\`):]
+
+	case strings.HasPrefix(lower, `\windows\`):
+		return system_root + path[len(`\Windows`):]
+
+	// Some drivers are loaded with a path relative to the system
+	// root (e.g. system32\drivers\foo.sys)
+	case strings.HasPrefix(lower, `system32\`):
+		return system_root + `\` + path
+	}
+
+	return path
+}
+
+// Parse the RTL_PROCESS_MODULES returned for SystemModuleInformation:
+// a count followed by the (8 byte aligned) module array.
+func ParseModuleInformation(buffer []byte) []*KernelModule {
+	result := []*KernelModule{}
+	if len(buffer) < 8 {
+		return result
+	}
+
+	le := binary.LittleEndian
+	count := int(le.Uint32(buffer))
+	for i := 0; i < count; i++ {
+		offset := 8 + i*MODULE_INFORMATION_SIZE
+		if offset+MODULE_INFORMATION_SIZE > len(buffer) {
+			break
+		}
+		item := buffer[offset : offset+MODULE_INFORMATION_SIZE]
+
+		path := string(item[40:])
+		end := strings.IndexByte(path, 0)
+		if end >= 0 {
+			path = path[:end]
+		}
+
+		name := path
+		offset_to_name := int(le.Uint16(item[38:]))
+		if offset_to_name < len(path) {
+			name = path[offset_to_name:]
+		}
+
+		result = append(result, &KernelModule{
+			Name:           name,
+			Path:           normalizeKernelPath(path),
+			KernelPath:     path,
+			ImageBase:      le.Uint64(item[16:]),
+			ImageSize:      le.Uint32(item[24:]),
+			Flags:          le.Uint32(item[28:]),
+			LoadOrderIndex: le.Uint16(item[32:]),
+			InitOrderIndex: le.Uint16(item[34:]),
+			LoadCount:      le.Uint16(item[36:]),
+		})
+	}
+
+	return result
+}
+
+type ModuleList struct {
+	modules []*KernelModule
+}
+
+func NewModuleList(modules []*KernelModule) *ModuleList {
+	sorted := append([]*KernelModule{}, modules...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ImageBase < sorted[j].ImageBase
+	})
+	return &ModuleList{modules: sorted}
+}
+
+// The kernel image is always the first module loaded.
+func (self *ModuleList) Kernel() *KernelModule {
+	var result *KernelModule
+	for _, module := range self.modules {
+		if module.LoadOrderIndex == 0 {
+			result = module
+		}
+	}
+	return result
+}
+
+// Find the module containing the address or nil if the address is
+// not backed by any loaded module.
+func (self *ModuleList) Find(address uint64) *KernelModule {
+	idx := sort.Search(len(self.modules), func(i int) bool {
+		return self.modules[i].ImageBase > address
+	})
+	if idx == 0 {
+		return nil
+	}
+
+	module := self.modules[idx-1]
+	if module.Contains(address) {
+		return module
+	}
+	return nil
+}
+
+// An image we can read by relative virtual address.
+type ImageReader interface {
+	ReadRVA(rva uint32, length int) []byte
+
+	// Is the RVA inside a writable, non executable section.
+	IsData(rva uint32) bool
+}
+
+type PEImage struct {
+	sections []*pe.Section
+	data     [][]byte
+	exports  pe.DataDirectory
+}
+
+func NewPEImage(buffer []byte) (*PEImage, error) {
+	pe_file, err := pe.NewFile(bytes.NewReader(buffer))
+	if err != nil {
+		return nil, err
+	}
+
+	header, ok := pe_file.OptionalHeader.(*pe.OptionalHeader64)
+	if !ok || len(header.DataDirectory) == 0 {
+		return nil, errors.New("Not a 64 bit image")
+	}
+
+	result := &PEImage{
+		sections: pe_file.Sections,
+		exports:  header.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_EXPORT],
+	}
+
+	for _, section := range pe_file.Sections {
+		data, _ := section.Data()
+		result.data = append(result.data, data)
+	}
+
+	return result, nil
+}
+
+func (self *PEImage) ReadRVA(rva uint32, length int) []byte {
+	for i, section := range self.sections {
+		data := self.data[i]
+		if rva < section.VirtualAddress ||
+			rva >= section.VirtualAddress+uint32(len(data)) {
+			continue
+		}
+
+		start := int(rva - section.VirtualAddress)
+		end := start + length
+		if end > len(data) {
+			end = len(data)
+		}
+		return data[start:end]
+	}
+	return nil
+}
+
+func (self *PEImage) IsData(rva uint32) bool {
+	for _, section := range self.sections {
+		if rva < section.VirtualAddress ||
+			rva >= section.VirtualAddress+section.VirtualSize {
+			continue
+		}
+
+		return section.Characteristics&pe.IMAGE_SCN_MEM_WRITE != 0 &&
+			section.Characteristics&pe.IMAGE_SCN_MEM_EXECUTE == 0
+	}
+	return false
+}
+
+func (self *PEImage) FindExport(name string) (uint32, error) {
+	return FindExport(self, self.exports.VirtualAddress, name)
+}
+
+func readUint32(image ImageReader, rva uint32) (uint32, bool) {
+	data := image.ReadRVA(rva, 4)
+	if len(data) < 4 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint32(data), true
+}
+
+func readCString(image ImageReader, rva uint32) string {
+	data := image.ReadRVA(rva, 256)
+	end := bytes.IndexByte(data, 0)
+	if end >= 0 {
+		data = data[:end]
+	}
+	return string(data)
+}
+
+// Look up an exported function by name in the IMAGE_EXPORT_DIRECTORY
+// at the given RVA.
+func FindExport(image ImageReader, directory uint32, name string) (uint32, error) {
+	dir := image.ReadRVA(directory, 40)
+	if len(dir) < 40 {
+		return 0, errors.New("Invalid export directory")
+	}
+
+	le := binary.LittleEndian
+	number_of_names := le.Uint32(dir[24:])
+	functions := le.Uint32(dir[28:])
+	names := le.Uint32(dir[32:])
+	ordinals := le.Uint32(dir[36:])
+
+	for i := uint32(0); i < number_of_names; i++ {
+		name_rva, ok := readUint32(image, names+4*i)
+		if !ok || readCString(image, name_rva) != name {
+			continue
+		}
+
+		ordinal := image.ReadRVA(ordinals+2*i, 2)
+		if len(ordinal) < 2 {
+			break
+		}
+
+		function, ok := readUint32(image, functions+4*uint32(le.Uint16(ordinal)))
+		if ok {
+			return function, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Export %v not found", name)
+}
+
+// The exported registration functions are thin wrappers which tail
+// call the internal implementation. Follow the first call or jump
+// into the implementation and find the first rip relative lea
+// referencing the data section - the callback array.
+func FindCallbackArray(image ImageReader, function uint32) (uint32, error) {
+	code := image.ReadRVA(function, 0x20)
+	for i := 0; i+5 <= len(code); i++ {
+		if code[i] == 0xe8 || code[i] == 0xe9 {
+			disp := int32(binary.LittleEndian.Uint32(code[i+1:]))
+			function = uint32(int64(function) + int64(i) + 5 + int64(disp))
+			break
+		}
+	}
+
+	code = image.ReadRVA(function, 0x300)
+	for i := 0; i+7 <= len(code); i++ {
+		// REX.W (optionally REX.R) lea reg, [rip+disp32]
+		if (code[i] != 0x48 && code[i] != 0x4c) || code[i+1] != 0x8d ||
+			code[i+2]&0xc7 != 0x05 {
+			continue
+		}
+
+		disp := int32(binary.LittleEndian.Uint32(code[i+3:]))
+		target := uint32(int64(function) + int64(i) + 7 + int64(disp))
+		if image.IsData(target) {
+			return target, nil
+		}
+	}
+
+	return 0, errors.New("Callback array not found")
+}
+
+// Find the RVAs of the notify routine arrays in the kernel image.
+func FindNotifyRoutineArrays(image *PEImage) map[string]uint32 {
+	result := make(map[string]uint32)
+	for _, routine := range NotifyRoutines {
+		function, err := image.FindExport(routine.Export)
+		if err != nil {
+			continue
+		}
+
+		array, err := FindCallbackArray(image, function)
+		if err == nil {
+			result[routine.Type] = array
+		}
+	}
+	return result
+}
+
+// Reads physical memory.
+type PhysicalReader interface {
+	ReadPhysical(address uint64, buf []byte) error
+}
+
+// Translate a kernel virtual address through the 4 level page tables
+// rooted at the directory table base.
+func TranslateAddress(reader PhysicalReader, dtb, address uint64) (uint64, error) {
+	table := dtb & PTE_ADDRESS_MAX
+	buf := make([]byte, 8)
+
+	for level, shift := range []uint{39, 30, 21, 12} {
+		index := (address >> shift) & 0x1ff
+		err := reader.ReadPhysical(table+index*8, buf)
+		if err != nil {
+			return 0, err
+		}
+
+		entry := binary.LittleEndian.Uint64(buf)
+		if entry&PTE_PRESENT == 0 {
+			return 0, fmt.Errorf("Address %#x is not mapped", address)
+		}
+
+		// 1GB and 2MB pages.
+		if (level == 1 || level == 2) && entry&PTE_LARGE_PAGE != 0 {
+			mask := uint64(1)<<shift - 1
+			return (entry & PTE_ADDRESS_MAX &^ mask) | (address & mask), nil
+		}
+
+		table = entry & PTE_ADDRESS_MAX
+	}
+
+	return table | (address & (PAGE_SIZE - 1)), nil
+}
+
+// Reads kernel virtual memory.
+type VirtualReader interface {
+	ReadVirtual(address uint64, buf []byte) error
+}
+
+type PagedReader struct {
+	Reader PhysicalReader
+	DTB    uint64
+}
+
+func (self *PagedReader) ReadVirtual(address uint64, buf []byte) error {
+	for len(buf) > 0 {
+		physical, err := TranslateAddress(self.Reader, self.DTB, address)
+		if err != nil {
+			return err
+		}
+
+		// Do not read past the end of the page.
+		length := int(PAGE_SIZE - address&(PAGE_SIZE-1))
+		if length > len(buf) {
+			length = len(buf)
+		}
+
+		err = self.Reader.ReadPhysical(physical, buf[:length])
+		if err != nil {
+			return err
+		}
+
+		buf = buf[length:]
+		address += uint64(length)
+	}
+	return nil
+}
+
+func readPointer(reader VirtualReader, address uint64) (uint64, error) {
+	buf := make([]byte, 8)
+	err := reader.ReadVirtual(address, buf)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf), nil
+}
+
+type KernelCallback struct {
+	Type      string
+	Index     int
+	Address   uint64
+	Module    string
+	Path      string
+	Offset    uint64
+	Anomalies []string
+}
+
+// Read the callbacks registered in a notify routine array. Each slot
+// holds an EX_CALLBACK - a pointer to an EX_CALLBACK_ROUTINE_BLOCK
+// with a reference count in its low bits. The routine itself follows
+// the block's rundown protection.
+func ReadCallbacks(reader VirtualReader, array uint64, callback_type string,
+	modules *ModuleList) ([]*KernelCallback, error) {
+	buf := make([]byte, 8*MAX_NOTIFY_ROUTINES)
+	err := reader.ReadVirtual(array, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*KernelCallback{}
+	for i := 0; i < MAX_NOTIFY_ROUTINES; i++ {
+		entry := binary.LittleEndian.Uint64(buf[8*i:])
+		if entry == 0 {
+			continue
+		}
+
+		function, err := readPointer(reader, (entry&^0xf)+8)
+		if err != nil {
+			return nil, err
+		}
+
+		callback := &KernelCallback{
+			Type:      callback_type,
+			Index:     i,
+			Address:   function,
+			Anomalies: []string{},
+		}
+
+		module := modules.Find(function)
+		if module == nil {
+			callback.Anomalies = append(callback.Anomalies,
+				"Callback outside any loaded module")
+		} else {
+			callback.Module = module.Name
+			callback.Path = module.Path
+			callback.Offset = function - module.ImageBase
+		}
+
+		result = append(result, callback)
+	}
+
+	return result, nil
+}
+
+type DriverObject struct {
+	Address       uint64
+	Name          string
+	DriverStart   uint64
+	DriverSize    uint32
+	MajorFunction []uint64
+}
+
+// Parse a DRIVER_OBJECT. Returns nil if this is not a driver object.
+func ParseDriverObject(buf []byte) *DriverObject {
+	if len(buf) < DRIVER_OBJECT_SIZE {
+		return nil
+	}
+
+	le := binary.LittleEndian
+	if le.Uint16(buf) != IO_TYPE_DRIVER ||
+		le.Uint16(buf[2:]) != DRIVER_OBJECT_SIZE {
+		return nil
+	}
+
+	result := &DriverObject{
+		DriverStart: le.Uint64(buf[DRIVER_START_OFFSET:]),
+		DriverSize:  le.Uint32(buf[DRIVER_SIZE_OFFSET:]),
+	}
+
+	for i := 0; i <= IRP_MJ_MAXIMUM_FUNCTION; i++ {
+		result.MajorFunction = append(result.MajorFunction,
+			le.Uint64(buf[MAJOR_FUNCTION_OFFSET+8*i:]))
+	}
+
+	return result
+}
+
+// Read a UNICODE_STRING from kernel memory.
+func readUnicodeString(reader VirtualReader, address uint64) (string, error) {
+	header := make([]byte, 16)
+	err := reader.ReadVirtual(address, header)
+	if err != nil {
+		return "", err
+	}
+
+	length := int(binary.LittleEndian.Uint16(header))
+	buffer := binary.LittleEndian.Uint64(header[8:])
+	if length == 0 || buffer == 0 {
+		return "", nil
+	}
+
+	data := make([]byte, length)
+	err = reader.ReadVirtual(buffer, data)
+	if err != nil {
+		return "", err
+	}
+
+	u16 := make([]uint16, length/2)
+	for i := range u16 {
+		u16[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(u16)), nil
+}
+
+func ReadDriverObject(reader VirtualReader, address uint64) (*DriverObject, error) {
+	buf := make([]byte, DRIVER_OBJECT_SIZE)
+	err := reader.ReadVirtual(address, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ParseDriverObject(buf)
+	if result == nil {
+		return nil, fmt.Errorf("%#x is not a driver object", address)
+	}
+
+	result.Address = address
+	result.Name, err = readUnicodeString(reader, address+DRIVER_NAME_OFFSET)
+	return result, err
+}
+
+// Collect the objects in an OBJECT_DIRECTORY: an array of hash
+// buckets, each a chain of OBJECT_DIRECTORY_ENTRY {ChainLink, Object}.
+func ReadObjectDirectory(reader VirtualReader, directory uint64) ([]uint64, error) {
+	buckets := make([]byte, 8*OBJECT_DIRECTORY_SLOTS)
+	err := reader.ReadVirtual(directory, buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []uint64{}
+	seen := make(map[uint64]bool)
+	for i := 0; i < OBJECT_DIRECTORY_SLOTS; i++ {
+		entry := binary.LittleEndian.Uint64(buckets[8*i:])
+
+		// Guard against loops in a corrupted chain.
+		for entry != 0 && !seen[entry] {
+			seen[entry] = true
+
+			object, err := readPointer(reader, entry+8)
+			if err != nil {
+				return result, err
+			}
+			if object != 0 {
+				result = append(result, object)
+			}
+
+			entry, err = readPointer(reader, entry)
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+type DispatchRoutine struct {
+	Driver        string
+	DriverModule  string
+	MajorFunction string
+	Address       uint64
+	Module        string
+	Path          string
+	Hooked        bool
+	Reason        string
+}
+
+// Check where each dispatch routine of the driver points. Routines
+// are expected inside the driver itself, the kernel (the default
+// handler) or one of the trusted port and class drivers. Anything
+// else indicates the dispatch table was hooked.
+func CheckDispatchRoutines(driver *DriverObject, modules *ModuleList,
+	trusted []string) []*DispatchRoutine {
+	trusted_modules := make(map[string]bool)
+	for _, name := range trusted {
+		trusted_modules[strings.ToLower(name)] = true
+	}
+
+	driver_module := modules.Find(driver.DriverStart)
+	kernel := modules.Kernel()
+
+	result := []*DispatchRoutine{}
+	for i, address := range driver.MajorFunction {
+		routine := &DispatchRoutine{
+			Driver:        driver.Name,
+			MajorFunction: irpMajorFunctions[i],
+			Address:       address,
+		}
+		if driver_module != nil {
+			routine.DriverModule = driver_module.Name
+		}
+
+		module := modules.Find(address)
+		if module != nil {
+			routine.Module = module.Name
+			routine.Path = module.Path
+		}
+
+		in_driver := address >= driver.DriverStart &&
+			address < driver.DriverStart+uint64(driver.DriverSize)
+
+		switch {
+		case address == 0 || in_driver:
+		case module == nil:
+			routine.Hooked = true
+			routine.Reason = "Dispatch routine outside any loaded module"
+		case module == driver_module || module == kernel ||
+			trusted_modules[strings.ToLower(module.Name)]:
+		default:
+			routine.Hooked = true
+			routine.Reason = "Dispatch routine in " + module.Name
+		}
+
+		result = append(result, routine)
+	}
+
+	return result
+}
@@ -0,0 +1,407 @@
+package kernel
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"unicode/utf16"
+
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+// Physical memory backed by sparse pages with a page table mapping
+// kernel addresses into it.
+type fakeMemory struct {
+	pages     map[uint64][]byte
+	next_page uint64
+	dtb       uint64
+}
+
+func newFakeMemory() *fakeMemory {
+	self := &fakeMemory{
+		pages:     make(map[uint64][]byte),
+		next_page: 0x10000,
+	}
+	self.dtb = self.allocPage()
+	return self
+}
+
+func (self *fakeMemory) allocPage() uint64 {
+	page := self.next_page
+	self.next_page += PAGE_SIZE
+	self.pages[page] = make([]byte, PAGE_SIZE)
+	return page
+}
+
+func (self *fakeMemory) ReadPhysical(address uint64, buf []byte) error {
+	page, pres := self.pages[address&^(PAGE_SIZE-1)]
+	offset := address & (PAGE_SIZE - 1)
+	if !pres || offset+uint64(len(buf)) > PAGE_SIZE {
+		return errors.New("Invalid physical read")
+	}
+	copy(buf, page[offset:])
+	return nil
+}
+
+// Map the virtual page containing address, creating the tables as
+// needed. Returns the physical page.
+func (self *fakeMemory) mapPage(address uint64) uint64 {
+	table := self.dtb
+	for _, shift := range []uint{39, 30, 21, 12} {
+		slot := self.pages[table][((address>>shift)&0x1ff)*8:]
+		entry := binary.LittleEndian.Uint64(slot)
+		if entry == 0 {
+			entry = self.allocPage() | PTE_PRESENT
+			binary.LittleEndian.PutUint64(slot, entry)
+		}
+		table = entry & PTE_ADDRESS_MAX
+	}
+	return table
+}
+
+func (self *fakeMemory) write(address uint64, data []byte) {
+	for len(data) > 0 {
+		page := self.mapPage(address)
+		n := copy(self.pages[page][address&(PAGE_SIZE-1):], data)
+		data = data[n:]
+		address += uint64(n)
+	}
+}
+
+func (self *fakeMemory) writePointer(address, value uint64) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, value)
+	self.write(address, buf)
+}
+
+func (self *fakeMemory) reader() *PagedReader {
+	return &PagedReader{Reader: self, DTB: self.dtb}
+}
+
+// An image laid out flat from RVA 0 with a data region.
+type fakeImage struct {
+	data       []byte
+	data_start uint32
+}
+
+func (self *fakeImage) ReadRVA(rva uint32, length int) []byte {
+	if int(rva) >= len(self.data) {
+		return nil
+	}
+	end := int(rva) + length
+	if end > len(self.data) {
+		end = len(self.data)
+	}
+	return self.data[rva:end]
+}
+
+func (self *fakeImage) IsData(rva uint32) bool {
+	return rva >= self.data_start && int(rva) < len(self.data)
+}
+
+func testModules() *ModuleList {
+	return NewModuleList([]*KernelModule{
+		{Name: "disk.sys", ImageBase: 0xfffff80002000000, ImageSize: 0x10000, LoadOrderIndex: 5},
+		{Name: "ntoskrnl.exe", ImageBase: 0xfffff80001000000, ImageSize: 0x100000},
+		{Name: "classpnp.sys", ImageBase: 0xfffff80003000000, ImageSize: 0x10000, LoadOrderIndex: 6},
+		{Name: "evil.sys", ImageBase: 0xfffff80004000000, ImageSize: 0x10000, LoadOrderIndex: 7},
+	})
+}
+
+func TestParseModuleInformation(t *testing.T) {
+	paths := []string{
+		`\SystemRoot\system32\ntoskrnl.exe`,
+		`\??\C:\Temp\driver.sys`,
+	}
+
+	buffer := make([]byte, 8+len(paths)*MODULE_INFORMATION_SIZE)
+	binary.LittleEndian.PutUint32(buffer, uint32(len(paths)))
+	for i, path := range paths {
+		item := buffer[8+i*MODULE_INFORMATION_SIZE:]
+		binary.LittleEndian.PutUint64(item[16:], 0xfffff80001000000+uint64(i)*0x1000000)
+		binary.LittleEndian.PutUint32(item[24:], 0x100000)
+		binary.LittleEndian.PutUint32(item[28:], 0x8804000)
+		binary.LittleEndian.PutUint16(item[32:], uint16(i))
+		binary.LittleEndian.PutUint16(item[34:], uint16(i+1))
+		binary.LittleEndian.PutUint16(item[36:], 1)
+		binary.LittleEndian.PutUint16(item[38:], uint16(len(path)-len("driver.sys")))
+		copy(item[40:], path)
+	}
+
+	// Trailing garbage past the declared count is ignored.
+	buffer = append(buffer, 1, 2, 3)
+
+	modules := ParseModuleInformation(buffer)
+	assert.Equal(t, 2, len(modules))
+
+	assert.Equal(t, `\SystemRoot\system32\ntoskrnl.exe`, modules[0].KernelPath)
+	assert.Equal(t, uint64(0xfffff80001000000), modules[0].ImageBase)
+	assert.Equal(t, uint32(0x100000), modules[0].ImageSize)
+	assert.Equal(t, uint16(1), modules[0].InitOrderIndex)
+
+	assert.Equal(t, "driver.sys", modules[1].Name)
+	assert.Equal(t, `C:\Temp\driver.sys`, modules[1].Path)
+	assert.Equal(t, uint16(1), modules[1].LoadOrderIndex)
+
+	// A truncated buffer yields only the complete entries.
+	assert.Equal(t, 1, len(ParseModuleInformation(
+		buffer[:8+MODULE_INFORMATION_SIZE+10])))
+	assert.Equal(t, 0, len(ParseModuleInformation(nil)))
+}
+
+func TestModuleListFind(t *testing.T) {
+	modules := testModules()
+
+	assert.Equal(t, "ntoskrnl.exe", modules.Kernel().Name)
+	assert.Equal(t, "disk.sys", modules.Find(0xfffff80002000000).Name)
+	assert.Equal(t, "disk.sys", modules.Find(0xfffff8000200ffff).Name)
+
+	// Gaps between modules and addresses below the first module.
+	assert.Nil(t, modules.Find(0xfffff80002010000))
+	assert.Nil(t, modules.Find(0x1000))
+}
+
+func TestFindExport(t *testing.T) {
+	image := &fakeImage{data: make([]byte, 0x1000), data_start: 0x800}
+	le := binary.LittleEndian
+
+	// IMAGE_EXPORT_DIRECTORY at 0x100 with two names.
+	dir := image.data[0x100:]
+	le.PutUint32(dir[24:], 2)
+	le.PutUint32(dir[28:], 0x200) // AddressOfFunctions
+	le.PutUint32(dir[32:], 0x300) // AddressOfNames
+	le.PutUint32(dir[36:], 0x400) // AddressOfNameOrdinals
+
+	le.PutUint32(image.data[0x200:], 0x5000)
+	le.PutUint32(image.data[0x204:], 0x6000)
+
+	le.PutUint32(image.data[0x300:], 0x500)
+	le.PutUint32(image.data[0x304:], 0x520)
+	copy(image.data[0x500:], "PsSetCreateProcessNotifyRoutine\x00")
+	copy(image.data[0x520:], "PsSetLoadImageNotifyRoutine\x00")
+
+	// Names are sorted independently of the function table.
+	le.PutUint16(image.data[0x400:], 1)
+	le.PutUint16(image.data[0x402:], 0)
+
+	rva, err := FindExport(image, 0x100, "PsSetCreateProcessNotifyRoutine")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x6000), rva)
+
+	rva, err = FindExport(image, 0x100, "PsSetLoadImageNotifyRoutine")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x5000), rva)
+
+	_, err = FindExport(image, 0x100, "PsSetCreateThreadNotifyRoutine")
+	assert.Error(t, err)
+
+	_, err = FindExport(image, 0xff0, "PsSetLoadImageNotifyRoutine")
+	assert.Error(t, err)
+}
+
+func putDisplacement(buf []byte, disp int32) {
+	binary.LittleEndian.PutUint32(buf, uint32(disp))
+}
+
+func TestFindCallbackArray(t *testing.T) {
+	image := &fakeImage{data: make([]byte, 0x2000), data_start: 0x1800}
+
+	// The exported function: sub rsp, 28h; xor r8d, r8d; call impl
+	copy(image.data[0x100:], []byte{0x48, 0x83, 0xec, 0x28, 0x45, 0x33, 0xc0, 0xe8})
+	putDisplacement(image.data[0x108:], 0x400-0x10c)
+
+	// The implementation first references a code address which
+	// must be skipped, then loads the array with lea r13, [rip+X]
+	copy(image.data[0x400:], []byte{0x48, 0x8d, 0x0d})
+	putDisplacement(image.data[0x403:], 0x200-0x407)
+	copy(image.data[0x410:], []byte{0x4c, 0x8d, 0x2d})
+	putDisplacement(image.data[0x413:], 0x1900-0x417)
+
+	array, err := FindCallbackArray(image, 0x100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x1900), array)
+
+	// Without any reference to the data section we fail.
+	_, err = FindCallbackArray(image, 0x500)
+	assert.Error(t, err)
+}
+
+func TestTranslateAddress(t *testing.T) {
+	memory := newFakeMemory()
+
+	// Data straddling a page boundary.
+	address := uint64(0xfffff80001000ffc)
+	memory.write(address, []byte("hello world"))
+
+	physical, err := TranslateAddress(memory, memory.dtb, address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0xffc), physical&(PAGE_SIZE-1))
+
+	buf := make([]byte, 11)
+	assert.NoError(t, memory.reader().ReadVirtual(address, buf))
+	assert.Equal(t, "hello world", string(buf))
+
+	// Unmapped addresses are errors.
+	_, err = TranslateAddress(memory, memory.dtb, 0xfffff80005000000)
+	assert.Error(t, err)
+
+	// A 2MB large page in the page directory.
+	large := uint64(0xfffff80006000000)
+	memory.mapPage(large)
+	table := memory.dtb
+	for _, shift := range []uint{39, 30} {
+		entry := binary.LittleEndian.Uint64(
+			memory.pages[table][((large>>shift)&0x1ff)*8:])
+		table = entry & PTE_ADDRESS_MAX
+	}
+	binary.LittleEndian.PutUint64(memory.pages[table][((large>>21)&0x1ff)*8:],
+		0x40000000|PTE_PRESENT|PTE_LARGE_PAGE)
+
+	physical, err = TranslateAddress(memory, memory.dtb, large+0x12345)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x40012345), physical)
+}
+
+func TestReadCallbacks(t *testing.T) {
+	memory := newFakeMemory()
+	array := uint64(0xfffff80001080000)
+
+	// Slot 0 is a legitimate callback in disk.sys, slot 3 points
+	// to unbacked memory. The low bits hold the reference count.
+	memory.writePointer(array, 0xfffff80007000000|0x7)
+	memory.writePointer(0xfffff80007000008, 0xfffff80002001230)
+
+	memory.writePointer(array+3*8, 0xfffff80007000100|0x3)
+	memory.writePointer(0xfffff80007000108, 0xffffa00000001000)
+
+	callbacks, err := ReadCallbacks(memory.reader(), array, "Process", testModules())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(callbacks))
+
+	assert.Equal(t, 0, callbacks[0].Index)
+	assert.Equal(t, "disk.sys", callbacks[0].Module)
+	assert.Equal(t, uint64(0x1230), callbacks[0].Offset)
+	assert.Equal(t, 0, len(callbacks[0].Anomalies))
+
+	assert.Equal(t, 3, callbacks[1].Index)
+	assert.Equal(t, "", callbacks[1].Module)
+	assert.Equal(t, []string{"Callback outside any loaded module"},
+		callbacks[1].Anomalies)
+}
+
+func buildDriverObject(memory *fakeMemory, address uint64, name string,
+	start uint64, size uint32, dispatch map[int]uint64) {
+	le := binary.LittleEndian
+	buf := make([]byte, DRIVER_OBJECT_SIZE)
+	le.PutUint16(buf, IO_TYPE_DRIVER)
+	le.PutUint16(buf[2:], DRIVER_OBJECT_SIZE)
+	le.PutUint64(buf[DRIVER_START_OFFSET:], start)
+	le.PutUint32(buf[DRIVER_SIZE_OFFSET:], size)
+
+	for i := 0; i <= IRP_MJ_MAXIMUM_FUNCTION; i++ {
+		le.PutUint64(buf[MAJOR_FUNCTION_OFFSET+8*i:], 0xfffff80001001000)
+	}
+	for i, function := range dispatch {
+		le.PutUint64(buf[MAJOR_FUNCTION_OFFSET+8*i:], function)
+	}
+
+	// The name buffer follows the object.
+	encoded := []byte{}
+	for _, c := range utf16.Encode([]rune(name)) {
+		encoded = append(encoded, byte(c), byte(c>>8))
+	}
+	le.PutUint16(buf[DRIVER_NAME_OFFSET:], uint16(len(encoded)))
+	le.PutUint16(buf[DRIVER_NAME_OFFSET+2:], uint16(len(encoded)))
+	le.PutUint64(buf[DRIVER_NAME_OFFSET+8:], address+DRIVER_OBJECT_SIZE)
+
+	memory.write(address, buf)
+	memory.write(address+DRIVER_OBJECT_SIZE, encoded)
+}
+
+func TestParseDriverObject(t *testing.T) {
+	memory := newFakeMemory()
+	buildDriverObject(memory, 0xffffa00000010000, `\Driver\Disk`,
+		0xfffff80002000000, 0x10000, map[int]uint64{
+			14: 0xfffff80002001000,
+		})
+
+	driver, err := ReadDriverObject(memory.reader(), 0xffffa00000010000)
+	assert.NoError(t, err)
+	assert.Equal(t, `\Driver\Disk`, driver.Name)
+	assert.Equal(t, uint64(0xfffff80002000000), driver.DriverStart)
+	assert.Equal(t, IRP_MJ_MAXIMUM_FUNCTION+1, len(driver.MajorFunction))
+	assert.Equal(t, uint64(0xfffff80002001000), driver.MajorFunction[14])
+
+	// Other objects are rejected by their type.
+	memory.write(0xffffa00000020000, make([]byte, DRIVER_OBJECT_SIZE))
+	_, err = ReadDriverObject(memory.reader(), 0xffffa00000020000)
+	assert.Error(t, err)
+
+	assert.Nil(t, ParseDriverObject(make([]byte, 10)))
+}
+
+func TestReadObjectDirectory(t *testing.T) {
+	memory := newFakeMemory()
+	directory := uint64(0xffffa00000030000)
+	memory.write(directory, make([]byte, 8*OBJECT_DIRECTORY_SLOTS))
+
+	// Bucket 2 chains two entries, bucket 36 one entry which
+	// links back to itself.
+	memory.writePointer(directory+2*8, 0xffffa00000031000)
+	memory.writePointer(0xffffa00000031000, 0xffffa00000031100)
+	memory.writePointer(0xffffa00000031008, 0xffffa00000040000)
+	memory.writePointer(0xffffa00000031100, 0)
+	memory.writePointer(0xffffa00000031108, 0xffffa00000041000)
+
+	memory.writePointer(directory+36*8, 0xffffa00000031200)
+	memory.writePointer(0xffffa00000031200, 0xffffa00000031200)
+	memory.writePointer(0xffffa00000031208, 0xffffa00000042000)
+
+	objects, err := ReadObjectDirectory(memory.reader(), directory)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{
+		0xffffa00000040000, 0xffffa00000041000, 0xffffa00000042000,
+	}, objects)
+}
+
+func TestCheckDispatchRoutines(t *testing.T) {
+	modules := testModules()
+	driver := &DriverObject{
+		Name:          `\Driver\Disk`,
+		DriverStart:   0xfffff80002000000,
+		DriverSize:    0x10000,
+		MajorFunction: make([]uint64, IRP_MJ_MAXIMUM_FUNCTION+1),
+	}
+	for i := range driver.MajorFunction {
+		// IopInvalidDeviceRequest in the kernel.
+		driver.MajorFunction[i] = 0xfffff80001001000
+	}
+	driver.MajorFunction[0] = 0xfffff80002001000  // The driver itself
+	driver.MajorFunction[3] = 0xfffff80003001000  // classpnp.sys
+	driver.MajorFunction[14] = 0xfffff80004001000 // evil.sys
+	driver.MajorFunction[27] = 0xffffa00000001000 // Unbacked memory
+
+	routines := CheckDispatchRoutines(driver, modules, DefaultDispatchModules)
+	assert.Equal(t, IRP_MJ_MAXIMUM_FUNCTION+1, len(routines))
+
+	hooked := map[string]string{}
+	for _, routine := range routines {
+		assert.Equal(t, "disk.sys", routine.DriverModule)
+		if routine.Hooked {
+			hooked[routine.MajorFunction] = routine.Reason
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"IRP_MJ_DEVICE_CONTROL": "Dispatch routine in evil.sys",
+		"IRP_MJ_PNP":            "Dispatch routine outside any loaded module",
+	}, hooked)
+
+	assert.Equal(t, "IRP_MJ_READ", routines[3].MajorFunction)
+	assert.Equal(t, "classpnp.sys", routines[3].Module)
+
+	// Without classpnp.sys in the trusted list its routine is
+	// reported too.
+	routines = CheckDispatchRoutines(driver, modules, []string{"ndis.sys"})
+	assert.True(t, routines[3].Hooked)
+}
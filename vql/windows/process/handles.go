@@ -37,24 +37,28 @@ type ProcessHandleInfo struct {
 }
 
 type TokenHandleInfo struct {
-	IsElevated       bool     `json:"IsElevated"`
-	User             string   `json:"User,omitempty"`
-	Username         string   `json:"Username,omitempty"`
-	ProfileDir       string   `json:"ProfileDir,omitempty"`
-	Owner            string   `json:"Owner,omitempty"`
-	PrimaryGroup     string   `json:"PrimaryGroup,omitempty"`
-	PrimaryGroupName string   `json:"PrimaryGroupName,omitempty"`
-	Groups           []string `json:"Groups,omitempty"`
+	IsElevated         bool     `json:"IsElevated"`
+	TokenType          string   `json:"TokenType,omitempty"`
+	ImpersonationLevel string   `json:"ImpersonationLevel,omitempty"`
+	IntegrityLevel     string   `json:"IntegrityLevel,omitempty"`
+	User               string   `json:"User,omitempty"`
+	Username           string   `json:"Username,omitempty"`
+	ProfileDir         string   `json:"ProfileDir,omitempty"`
+	Owner              string   `json:"Owner,omitempty"`
+	PrimaryGroup       string   `json:"PrimaryGroup,omitempty"`
+	PrimaryGroupName   string   `json:"PrimaryGroupName,omitempty"`
+	Groups             []string `json:"Groups,omitempty"`
 }
 
 type HandleInfo struct {
-	Pid         uint32             `json:"Pid"`
-	Type        string             `json:"Type"`
-	Name        string             `json:"Name,omitempty"`
-	Handle      uint32             `json:"Handle"`
-	ProcessInfo *ProcessHandleInfo `json:"ProcessInfo,omitempty"`
-	ThreadInfo  *ThreadHandleInfo  `json:"ThreadInfo,omitempty"`
-	TokenInfo   *TokenHandleInfo   `json:"TokenInfo,omitempty"`
+	Pid           uint32             `json:"Pid"`
+	Type          string             `json:"Type"`
+	Name          string             `json:"Name,omitempty"`
+	Handle        uint32             `json:"Handle"`
+	GrantedAccess uint32             `json:"GrantedAccess"`
+	ProcessInfo   *ProcessHandleInfo `json:"ProcessInfo,omitempty"`
+	ThreadInfo    *ThreadHandleInfo  `json:"ThreadInfo,omitempty"`
+	TokenInfo     *TokenHandleInfo   `json:"TokenInfo,omitempty"`
 }
 
 type HandlesPluginArgs struct {
@@ -246,8 +250,9 @@ func SendHandleInfo(arg *HandlesPluginArgs, scope vfilter.Scope,
 
 	to_send := false
 	result := &HandleInfo{
-		Pid:    uint32(handle_info.UniqueProcessId),
-		Handle: uint32(handle_info.HandleValue),
+		Pid:           uint32(handle_info.UniqueProcessId),
+		Handle:        uint32(handle_info.HandleValue),
+		GrantedAccess: handle_info.GrantedAccess,
 	}
 
 	// Sometimes the NtQueryObject blocks without a
@@ -290,8 +295,10 @@ func SendHandleInfo(arg *HandlesPluginArgs, scope vfilter.Scope,
 func GetTokenInfo(scope vfilter.Scope, handle syscall.Handle) *TokenHandleInfo {
 	token := gowin.Token(handle)
 	result := &TokenHandleInfo{
-		IsElevated: token.IsElevated(),
+		IsElevated:     token.IsElevated(),
+		IntegrityLevel: getTokenIntegrityLevel(token),
 	}
+	result.TokenType, result.ImpersonationLevel = getTokenType(token)
 
 	// Find the token user
 	tokenUser, err := token.GetTokenUser()
@@ -0,0 +1,148 @@
+// +build windows,amd64,cgo
+
+package process
+
+import (
+	"context"
+	"syscall"
+	"unsafe"
+
+	"github.com/Velocidex/ordereddict"
+	"golang.org/x/sys/windows"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type InjectedThreadsArgs struct {
+	Pid int64 `vfilter:"optional,field=pid,doc=If specified only check threads in this process."`
+}
+
+type InjectedThreadsPlugin struct{}
+
+func (self InjectedThreadsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "injected_threads")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("injected_threads: %s", err)
+			return
+		}
+
+		arg := &InjectedThreadsArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("injected_threads: %s", err.Error())
+			return
+		}
+
+		err = TryToGrantSeDebugPrivilege()
+		if err != nil {
+			scope.Log("injected_threads: Cannot get SeDebugPrivilege, %s", err.Error())
+		}
+
+		getInjectedThreads(ctx, scope, arg, output_chan)
+	}()
+
+	return output_chan
+}
+
+func getProcessNames() map[uint32]string {
+	result := make(map[uint32]string)
+
+	handle, err := windows.CreateToolhelp32Snapshot(
+		windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return result
+	}
+	defer windows.Close(handle)
+
+	entry := windows.ProcessEntry32{}
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	err = windows.Process32First(handle, &entry)
+	for err == nil {
+		result[entry.ProcessID] = syscall.UTF16ToString(entry.ExeFile[:])
+		err = windows.Process32Next(handle, &entry)
+	}
+
+	return result
+}
+
+// A thread is suspicious when its start address is not inside a
+// mapped image (e.g. shellcode in private memory started with
+// CreateRemoteThread).
+func getInjectedThreads(
+	ctx context.Context,
+	scope vfilter.Scope,
+	arg *InjectedThreadsArgs,
+	output_chan chan vfilter.Row) {
+
+	names := getProcessNames()
+
+	handle, err := windows.CreateToolhelp32Snapshot(
+		windows.TH32CS_SNAPTHREAD, 0)
+	if err != nil {
+		scope.Log("CreateToolhelp32Snapshot: %v ", err)
+		return
+	}
+	defer windows.Close(handle)
+
+	entry := windows.ThreadEntry32{}
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	err = windows.Thread32First(handle, &entry)
+	for err == nil {
+		pid := entry.OwnerProcessID
+
+		// Skip the idle and system processes.
+		if pid > 4 && (arg.Pid == 0 || arg.Pid == int64(pid)) {
+			start, err := getThreadStart(entry)
+			if err == nil && start != nil &&
+				start.StartAddress != 0 &&
+				start.MemoryInfo.Type != MEM_IMAGE {
+				mbi := start.MemoryInfo
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- ordereddict.NewDict().
+					Set("Pid", pid).
+					Set("Name", names[pid]).
+					Set("Tid", entry.ThreadID).
+					Set("StartAddress", start.StartAddress).
+					Set("AllocationBase", mbi.AllocationBase).
+					Set("BaseAddress", mbi.BaseAddress).
+					Set("RegionSize", mbi.RegionSize).
+					Set("State", getState(mbi.State)).
+					Set("Type", getType(mbi.Type)).
+					Set("Protection", getProtection(mbi.Protect)).
+					Set("AllocationProtection", getProtection(mbi.AllocationProtect)):
+				}
+			}
+		}
+
+		err = windows.Thread32Next(handle, &entry)
+	}
+}
+
+func (self InjectedThreadsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "injected_threads",
+		Doc:      "Find threads which start outside the mapped modules of their process.",
+		ArgType:  type_map.AddType(scope, &InjectedThreadsArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&InjectedThreadsPlugin{})
+}
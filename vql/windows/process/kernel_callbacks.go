@@ -0,0 +1,372 @@
+// +build windows,amd64,cgo
+
+package process
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"unsafe"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/hillu/go-ntdll"
+	gowin "golang.org/x/sys/windows"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/windows"
+	"www.velocidex.com/golang/velociraptor/vql/windows/kernel"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	DEFAULT_PMEM_DEVICE = `\\.\pmem`
+
+	// The WinPmem driver interface.
+	PMEM_CTRL_IOCTL    = 0x22c404
+	PMEM_INFO_IOCTL    = 0x22c40c
+	PMEM_MODE_PHYSICAL = 1
+
+	SystemExtendedHandleInformation = 0x40
+)
+
+// SYSTEM_HANDLE_TABLE_ENTRY_INFO_EX
+type systemHandleTableEntryInfoEx struct {
+	Object                uint64
+	UniqueProcessId       uint64
+	HandleValue           uint64
+	GrantedAccess         uint32
+	CreatorBackTraceIndex uint16
+	ObjectTypeIndex       uint16
+	HandleAttributes      uint32
+	Reserved              uint32
+}
+
+// Read physical memory through the WinPmem driver.
+type pmemReader struct {
+	mu     sync.Mutex
+	handle gowin.Handle
+}
+
+func (self *pmemReader) ReadPhysical(address uint64, buf []byte) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	_, err := gowin.Seek(self.handle, int64(address), 0)
+	if err != nil {
+		return err
+	}
+
+	var n uint32
+	err = gowin.ReadFile(self.handle, buf, &n, nil)
+	if err != nil {
+		return err
+	}
+	if int(n) != len(buf) {
+		return fmt.Errorf("Short read at %#x", address)
+	}
+	return nil
+}
+
+func (self *pmemReader) Close() {
+	gowin.CloseHandle(self.handle)
+}
+
+// Open the WinPmem device and return a reader for kernel memory.
+func openKernelReader(device string) (*pmemReader, *kernel.PagedReader, error) {
+	path, err := gowin.UTF16PtrFromString(device)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	handle, err := gowin.CreateFile(path,
+		gowin.GENERIC_READ|gowin.GENERIC_WRITE,
+		gowin.FILE_SHARE_READ|gowin.FILE_SHARE_WRITE, nil,
+		gowin.OPEN_EXISTING, gowin.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"Unable to open %v (is the WinPmem driver loaded?): %w", device, err)
+	}
+	pmem := &pmemReader{handle: handle}
+
+	var returned uint32
+	mode := uint32(PMEM_MODE_PHYSICAL)
+	err = gowin.DeviceIoControl(handle, PMEM_CTRL_IOCTL,
+		(*byte)(unsafe.Pointer(&mode)), 4, nil, 0, &returned, nil)
+	if err != nil {
+		pmem.Close()
+		return nil, nil, fmt.Errorf("Setting acquisition mode: %w", err)
+	}
+
+	// The info struct starts with the kernel's CR3.
+	info := make([]byte, 0x10000)
+	err = gowin.DeviceIoControl(handle, PMEM_INFO_IOCTL, nil, 0,
+		&info[0], uint32(len(info)), &returned, nil)
+	if err != nil || returned < 8 {
+		pmem.Close()
+		return nil, nil, fmt.Errorf("Querying memory info: %v", err)
+	}
+
+	return pmem, &kernel.PagedReader{
+		Reader: pmem,
+		DTB:    binary.LittleEndian.Uint64(info),
+	}, nil
+}
+
+func getKernelModules() (*kernel.ModuleList, error) {
+	buffer, err := SaneNtQuerySystemInformation(windows.SystemModuleInformation)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := kernel.NewModuleList(kernel.ParseModuleInformation(buffer))
+	if modules.Kernel() == nil {
+		return nil, errors.New("Kernel image not found")
+	}
+	return modules, nil
+}
+
+// Find the kernel address of an object we hold a handle to.
+func getHandleObject(handle uint64) (uint64, error) {
+	buffer, err := SaneNtQuerySystemInformation(SystemExtendedHandleInformation)
+	if err != nil {
+		return 0, err
+	}
+
+	// SYSTEM_HANDLE_INFORMATION_EX is a count and a reserved field
+	// followed by the entries.
+	pid := uint64(os.Getpid())
+	size := int(unsafe.Sizeof(systemHandleTableEntryInfoEx{}))
+	for i := 16; i+size <= len(buffer); i += size {
+		entry := (*systemHandleTableEntryInfoEx)(unsafe.Pointer(&buffer[i]))
+		if entry.UniqueProcessId == pid && entry.HandleValue == handle {
+			return entry.Object, nil
+		}
+	}
+
+	return 0, errors.New("Handle not found")
+}
+
+// List the driver objects in an object manager directory
+// (e.g. \Driver).
+func getDriverObjects(reader kernel.VirtualReader, path string) ([]*kernel.DriverObject, error) {
+	obj_attr := ntdll.NewObjectAttributes(path, 0, 0, nil)
+	dir_handle := ntdll.Handle(0)
+
+	status := ntdll.NtOpenDirectoryObject(&dir_handle,
+		ntdll.DIRECTORY_QUERY|ntdll.DIRECTORY_TRAVERSE, obj_attr)
+	if status != ntdll.STATUS_SUCCESS {
+		return nil, fmt.Errorf("%v for %v", status, path)
+	}
+	defer ntdll.NtClose(dir_handle)
+
+	directory, err := getHandleObject(uint64(dir_handle))
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := kernel.ReadObjectDirectory(reader, directory)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*kernel.DriverObject{}
+	for _, object := range objects {
+		driver, err := kernel.ReadDriverObject(reader, object)
+		if err == nil {
+			result = append(result, driver)
+		}
+	}
+	return result, nil
+}
+
+type KernelCallbacksPluginArgs struct {
+	Device string `vfilter:"optional,field=device,doc=The WinPmem device used to read kernel memory (default \\\\.\\pmem)."`
+}
+
+type KernelCallbacksPlugin struct{}
+
+func (self KernelCallbacksPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "kernel_callbacks")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("kernel_callbacks: %s", err)
+			return
+		}
+
+		arg := &KernelCallbacksPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("kernel_callbacks: %v", err)
+			return
+		}
+
+		if arg.Device == "" {
+			arg.Device = DEFAULT_PMEM_DEVICE
+		}
+
+		modules, err := getKernelModules()
+		if err != nil {
+			scope.Log("kernel_callbacks: %v", err)
+			return
+		}
+		kernel_image := modules.Kernel()
+
+		// The running kernel is mapped from this file, so the
+		// array offsets are the same.
+		data, err := ioutil.ReadFile(kernel_image.Path)
+		if err != nil {
+			scope.Log("kernel_callbacks: %v", err)
+			return
+		}
+
+		image, err := kernel.NewPEImage(data)
+		if err != nil {
+			scope.Log("kernel_callbacks: %v: %v", kernel_image.Path, err)
+			return
+		}
+
+		pmem, reader, err := openKernelReader(arg.Device)
+		if err != nil {
+			scope.Log("kernel_callbacks: %v", err)
+			return
+		}
+		defer pmem.Close()
+
+		arrays := kernel.FindNotifyRoutineArrays(image)
+		for _, routine := range kernel.NotifyRoutines {
+			rva, pres := arrays[routine.Type]
+			if !pres {
+				scope.Log("kernel_callbacks: Unable to locate the %v notify routines in %v",
+					routine.Type, kernel_image.Path)
+				continue
+			}
+
+			callbacks, err := kernel.ReadCallbacks(reader,
+				kernel_image.ImageBase+uint64(rva), routine.Type, modules)
+			if err != nil {
+				scope.Log("kernel_callbacks: %v: %v", routine.Type, err)
+				continue
+			}
+
+			for _, callback := range callbacks {
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- callback:
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self KernelCallbacksPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "kernel_callbacks",
+		Doc:      "Enumerate the process, thread and image load notification callbacks registered in the kernel.",
+		ArgType:  type_map.AddType(scope, &KernelCallbacksPluginArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+type DriverDispatchPluginArgs struct {
+	Device         string   `vfilter:"optional,field=device,doc=The WinPmem device used to read kernel memory (default \\\\.\\pmem)."`
+	TrustedModules []string `vfilter:"optional,field=trusted_modules,doc=Port and class drivers which may own dispatch routines of other drivers (default a built in list)."`
+}
+
+type DriverDispatchPlugin struct{}
+
+func (self DriverDispatchPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "driver_dispatch")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("driver_dispatch: %s", err)
+			return
+		}
+
+		arg := &DriverDispatchPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("driver_dispatch: %v", err)
+			return
+		}
+
+		if arg.Device == "" {
+			arg.Device = DEFAULT_PMEM_DEVICE
+		}
+
+		if len(arg.TrustedModules) == 0 {
+			arg.TrustedModules = kernel.DefaultDispatchModules
+		}
+
+		modules, err := getKernelModules()
+		if err != nil {
+			scope.Log("driver_dispatch: %v", err)
+			return
+		}
+
+		pmem, reader, err := openKernelReader(arg.Device)
+		if err != nil {
+			scope.Log("driver_dispatch: %v", err)
+			return
+		}
+		defer pmem.Close()
+
+		for _, directory := range []string{`\Driver`, `\FileSystem`} {
+			drivers, err := getDriverObjects(reader, directory)
+			if err != nil {
+				scope.Log("driver_dispatch: %v", err)
+				continue
+			}
+
+			for _, driver := range drivers {
+				for _, routine := range kernel.CheckDispatchRoutines(
+					driver, modules, arg.TrustedModules) {
+					select {
+					case <-ctx.Done():
+						return
+					case output_chan <- routine:
+					}
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self DriverDispatchPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "driver_dispatch",
+		Doc:      "List the IRP dispatch routines of all drivers and flag hooked entries.",
+		ArgType:  type_map.AddType(scope, &DriverDispatchPluginArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&KernelCallbacksPlugin{})
+	vql_subsystem.RegisterPlugin(&DriverDispatchPlugin{})
+}
@@ -0,0 +1,71 @@
+// +build windows,amd64,cgo
+
+package process
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/windows"
+	"www.velocidex.com/golang/velociraptor/vql/windows/kernel"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type KernelModulesPlugin struct{}
+
+func (self KernelModulesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "kernel_modules")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("kernel_modules: %s", err)
+			return
+		}
+
+		buffer, err := SaneNtQuerySystemInformation(windows.SystemModuleInformation)
+		if err != nil {
+			scope.Log("kernel_modules: %v", err)
+			return
+		}
+
+		for _, module := range kernel.ParseModuleInformation(buffer) {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Name", module.Name).
+				Set("Path", module.Path).
+				Set("KernelPath", module.KernelPath).
+				Set("ImageBase", module.ImageBase).
+				Set("ImageSize", module.ImageSize).
+				Set("LoadOrderIndex", module.LoadOrderIndex).
+				Set("InitOrderIndex", module.InitOrderIndex).
+				Set("LoadCount", module.LoadCount).
+				Set("Flags", module.Flags):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self KernelModulesPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "kernel_modules",
+		Doc:      "Enumerate the drivers and other modules loaded into the kernel.",
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&KernelModulesPlugin{})
+}
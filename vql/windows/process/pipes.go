@@ -0,0 +1,292 @@
+// +build windows,amd64,cgo
+
+package process
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	gowin "golang.org/x/sys/windows"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	// The name of pipe handles in the object manager
+	NAMED_PIPE_DEVICE = `\Device\NamedPipe\`
+)
+
+type PipeProcessInfo struct {
+	Pid      uint32 `json:"Pid"`
+	Binary   string `json:"Binary,omitempty"`
+	Username string `json:"Username,omitempty"`
+}
+
+type NamedPipe struct {
+	Name         string
+	Instances    uint32
+	MaxInstances int32
+
+	// Pids of the processes holding the pipe open.
+	pids map[uint32]bool
+}
+
+// Enumerate the pipes in the pipe namespace. Listing the pipe
+// namespace reports the current and maximum number of instances in
+// the file size fields.
+func ListNamedPipes() (map[string]*NamedPipe, error) {
+	pattern, err := gowin.UTF16PtrFromString(`\\.\pipe\*`)
+	if err != nil {
+		return nil, err
+	}
+
+	data := gowin.Win32finddata{}
+	handle, err := gowin.FindFirstFile(pattern, &data)
+	if err != nil {
+		return nil, err
+	}
+	defer gowin.FindClose(handle)
+
+	result := make(map[string]*NamedPipe)
+	for {
+		name := gowin.UTF16ToString(data.FileName[:])
+		result[name] = &NamedPipe{
+			Name:         name,
+			Instances:    data.FileSizeLow,
+			MaxInstances: int32(data.FileSizeHigh),
+			pids:         make(map[uint32]bool),
+		}
+
+		err = gowin.FindNextFile(handle, &data)
+		if err != nil {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// Find the processes holding each pipe open from the system handle
+// table.
+func getPipeProcesses(ctx context.Context, scope vfilter.Scope,
+	pipes map[string]*NamedPipe) {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		GetHandles(ctx, scope, &HandlesPluginArgs{
+			Types: []string{"File"},
+		}, output_chan)
+	}()
+
+	for row := range output_chan {
+		handle, ok := row.(*HandleInfo)
+		if !ok || !strings.HasPrefix(handle.Name, NAMED_PIPE_DEVICE) {
+			continue
+		}
+
+		pipe, pres := pipes[strings.TrimPrefix(handle.Name, NAMED_PIPE_DEVICE)]
+		if pres {
+			pipe.pids[handle.Pid] = true
+		}
+	}
+}
+
+func getPipeProcessInfo(scope vfilter.Scope, pid uint32) *PipeProcessInfo {
+	result := &PipeProcessInfo{Pid: pid}
+
+	handle, err := gowin.OpenProcess(
+		gowin.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return result
+	}
+	defer gowin.CloseHandle(handle)
+
+	info := GetProcessName(scope, syscall.Handle(handle))
+	if info != nil {
+		result.Binary = info.Binary
+	}
+
+	var token gowin.Token
+	err = gowin.OpenProcessToken(handle, gowin.TOKEN_QUERY, &token)
+	if err == nil {
+		tokenUser, err := token.GetTokenUser()
+		if err == nil {
+			result.Username = getUsernameFromSid(scope, tokenUser.User.Sid)
+		}
+		token.Close()
+	}
+
+	return result
+}
+
+func getPipeProcessInfos(scope vfilter.Scope,
+	pids map[uint32]bool) []*PipeProcessInfo {
+	result := make([]*PipeProcessInfo, 0, len(pids))
+	for pid := range pids {
+		result = append(result, getPipeProcessInfo(scope, pid))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Pid < result[j].Pid
+	})
+	return result
+}
+
+type WatchNamedPipesArgs struct {
+	Period int64 `vfilter:"optional,field=period,doc=How often to check for changes (default 10 seconds)."`
+}
+
+type WatchNamedPipesPlugin struct{}
+
+func (self WatchNamedPipesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer vql_subsystem.CheckForPanic(scope, "watch_named_pipes")
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("watch_named_pipes: %s", err)
+			return
+		}
+
+		arg := &WatchNamedPipesArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("watch_named_pipes: %s", err.Error())
+			return
+		}
+
+		if arg.Period == 0 {
+			arg.Period = 10
+		}
+
+		err = TryToGrantSeDebugPrivilege()
+		if err != nil {
+			scope.Log("watch_named_pipes while trying to grant SeDebugPrivilege: %v", err)
+		}
+
+		// The first listing is the baseline - we only report
+		// changes from it.
+		var last map[string]*NamedPipe
+
+		for {
+			pipes, err := getPipesSnapshot(ctx, scope)
+			if err != nil {
+				scope.Log("watch_named_pipes: %v", err)
+			} else {
+				if last != nil {
+					for _, row := range diffPipes(scope, last, pipes) {
+						select {
+						case <-ctx.Done():
+							return
+						case output_chan <- row:
+						}
+					}
+				}
+				last = pipes
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Duration(arg.Period) * time.Second):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func getPipesSnapshot(ctx context.Context,
+	scope vfilter.Scope) (map[string]*NamedPipe, error) {
+	pipes, err := ListNamedPipes()
+	if err != nil {
+		return nil, err
+	}
+
+	getPipeProcesses(ctx, scope, pipes)
+	return pipes, nil
+}
+
+func pipeRow(action string, pipe *NamedPipe) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Action", action).
+		Set("Name", pipe.Name).
+		Set("Path", `\\.\pipe\`+pipe.Name).
+		Set("Instances", pipe.Instances).
+		Set("MaxInstances", pipe.MaxInstances)
+}
+
+// Compare two snapshots of the pipe namespace. New pipes are reported
+// as Created with the processes holding them, new processes holding
+// an existing pipe are reported as Connected and pipes which are gone
+// are reported as Removed.
+func diffPipes(scope vfilter.Scope,
+	last, current map[string]*NamedPipe) []vfilter.Row {
+	var result []vfilter.Row
+
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pipe := current[name]
+		old_pipe, pres := last[name]
+		if !pres {
+			result = append(result, pipeRow("Created", pipe).
+				Set("Process", nil).
+				Set("Owners", getPipeProcessInfos(scope, pipe.pids)))
+			continue
+		}
+
+		for pid := range pipe.pids {
+			if old_pipe.pids[pid] {
+				continue
+			}
+
+			result = append(result, pipeRow("Connected", pipe).
+				Set("Process", getPipeProcessInfo(scope, pid)).
+				Set("Owners", getPipeProcessInfos(scope, old_pipe.pids)))
+		}
+	}
+
+	for name, pipe := range last {
+		_, pres := current[name]
+		if !pres {
+			result = append(result, pipeRow("Removed", pipe).
+				Set("Process", nil).
+				Set("Owners", getPipeProcessInfos(scope, pipe.pids)))
+		}
+	}
+
+	return result
+}
+
+func (self WatchNamedPipesPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "watch_named_pipes",
+		Doc:      "Watch for named pipes being created, connected to and removed.",
+		ArgType:  type_map.AddType(scope, &WatchNamedPipesArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&WatchNamedPipesPlugin{})
+}
@@ -23,6 +23,8 @@ const (
 	ThreadBasicInformation          = 0x00
 	ThreadQueryInformation          = 0x40
 	ThreadQuerySetWin32StartAddress = 0x09
+
+	MEM_IMAGE = 0x1000000
 )
 
 type ThreadArgs struct {
@@ -109,9 +111,17 @@ func getProcessThreads(
 	return
 }
 
-func checkThread(
-	scope vfilter.Scope, pid int64,
-	entry windows.ThreadEntry32) (vfilter.Row, error) {
+// Describes where a thread started executing.
+type threadStart struct {
+	ThreadInfo   vwindows.THREAD_BASIC_INFORMATION
+	StartAddress uint64
+	MemoryInfo   *vwindows.MEMORY_BASIC_INFORMATION
+
+	// The mapped file if the start address is in an image.
+	Filename string
+}
+
+func getThreadStart(entry windows.ThreadEntry32) (*threadStart, error) {
 	if entry.ThreadID == 0 {
 		return nil, errors.New("ThreadID is 0")
 	}
@@ -122,26 +132,26 @@ func checkThread(
 	}
 	defer windows.CloseHandle(thread)
 
-	thread_info := vwindows.THREAD_BASIC_INFORMATION{}
+	result := &threadStart{
+		MemoryInfo: &vwindows.MEMORY_BASIC_INFORMATION{},
+	}
 	var length uint32
 
 	status, _ := vwindows.NtQueryInformationThread(
 		syscall.Handle(thread),
 		vwindows.ThreadBasicInformation,
-		(*byte)(unsafe.Pointer(&thread_info)),
-		uint32(unsafe.Sizeof(thread_info)),
+		(*byte)(unsafe.Pointer(&result.ThreadInfo)),
+		uint32(unsafe.Sizeof(result.ThreadInfo)),
 		&length)
 	if status != vwindows.STATUS_SUCCESS || length == 0 {
 		return nil, fmt.Errorf("NtQueryInformationProcess failed, %X", status)
 	}
 
-	var thread_start_address uint64
-
 	status, _ = vwindows.NtQueryInformationThread(
 		syscall.Handle(thread),
 		ThreadQuerySetWin32StartAddress,
-		(*byte)(unsafe.Pointer(&thread_start_address)),
-		uint32(unsafe.Sizeof(thread_start_address)),
+		(*byte)(unsafe.Pointer(&result.StartAddress)),
+		uint32(unsafe.Sizeof(result.StartAddress)),
 		&length)
 	if status != vwindows.STATUS_SUCCESS || length == 0 {
 		return nil, fmt.Errorf("NtQueryInformationProcess failed, %X", status)
@@ -153,36 +163,45 @@ func checkThread(
 	}
 	defer windows.Close(proc_handle)
 
-	memory_basic_info := &vwindows.MEMORY_BASIC_INFORMATION{}
 	mem_length, err := vwindows.VirtualQueryEx(
 		syscall.Handle(proc_handle),
-		thread_start_address,
-		memory_basic_info,
-		uintptr(unsafe.Sizeof(*memory_basic_info)))
+		result.StartAddress,
+		result.MemoryInfo,
+		uintptr(unsafe.Sizeof(*result.MemoryInfo)))
 	if err != nil || mem_length == 0 {
 		return nil, err
 	}
 
 	// If address space is MEM_IMAGE, get the filename.
-	filename := ""
-	if memory_basic_info.Type == 0x1000000 {
+	if result.MemoryInfo.Type == MEM_IMAGE {
 		wide_filename := make([]uint16, syscall.MAX_PATH)
 		len, err := vwindows.GetMappedFileNameW(
 			syscall.Handle(proc_handle),
-			thread_start_address,
+			result.StartAddress,
 			&wide_filename[0], syscall.MAX_PATH)
 		if err == nil {
-			filename = syscall.UTF16ToString(wide_filename[:len])
+			result.Filename = syscall.UTF16ToString(wide_filename[:len])
 		}
 	}
 
+	return result, nil
+}
+
+func checkThread(
+	scope vfilter.Scope, pid int64,
+	entry windows.ThreadEntry32) (vfilter.Row, error) {
+	start, err := getThreadStart(entry)
+	if err != nil || start == nil {
+		return nil, err
+	}
+
 	ret := ordereddict.NewDict().
 		Set("pid", pid).
 		Set("tid", entry.ThreadID).
-		Set("thread_info", thread_info).
-		Set("thread_start_address", thread_start_address).
-		Set("memory_basic_info", memory_basic_info).
-		Set("filename", filename)
+		Set("thread_info", start.ThreadInfo).
+		Set("thread_start_address", start.StartAddress).
+		Set("memory_basic_info", start.MemoryInfo).
+		Set("filename", start.Filename)
 
 	return ret, nil
 }
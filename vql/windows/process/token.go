@@ -28,7 +28,8 @@ var (
 )
 
 type TokenArgs struct {
-	Pid int64 `vfilter:"required,field=pid,doc=The PID to get the token for."`
+	Pid int64 `vfilter:"optional,field=pid,doc=The PID to get the token for."`
+	Tid int64 `vfilter:"optional,field=tid,doc=If specified get the impersonation token of this thread instead."`
 }
 
 type TokenFunction struct{}
@@ -51,34 +52,41 @@ func (self TokenFunction) Call(
 		return vfilter.Null{}
 	}
 
-	TryToGrantSeDebugPrivilege()
-
-	handle, err := windows.OpenProcess(
-		syscall.PROCESS_QUERY_INFORMATION, false, uint32(arg.Pid))
-
-	if err != nil {
-		scope.Log("token: OpenProcess for %v: %s",
-			GetProcessContext(ctx, scope, uint64(arg.Pid)), err.Error())
+	if arg.Pid == 0 && arg.Tid == 0 {
+		scope.Log("token: One of pid or tid must be specified")
 		return vfilter.Null{}
 	}
-	defer windows.CloseHandle(handle)
+
+	TryToGrantSeDebugPrivilege()
 
 	var token windows.Token
+	if arg.Tid != 0 {
+		token, err = openThreadToken(uint32(arg.Tid))
+		if err == windows.ERROR_NO_TOKEN {
+			// The thread is not impersonating.
+			return vfilter.Null{}
+		}
 
-	// Find process token via win32
-	err = windows.OpenProcessToken(handle, syscall.TOKEN_QUERY, &token)
-	if err != nil {
-		scope.Log("token: OpenProcessToken for %v: %s",
-			GetProcessContext(ctx, scope, uint64(arg.Pid)), err.Error())
-		return vfilter.Null{}
+		if err != nil {
+			scope.Log("token: OpenThreadToken for thread %v: %s",
+				arg.Tid, err.Error())
+			return vfilter.Null{}
+		}
+
+	} else {
+		token, err = openProcessToken(uint32(arg.Pid))
+		if err != nil {
+			scope.Log("token: OpenProcessToken for %v: %s",
+				GetProcessContext(ctx, scope, uint64(arg.Pid)), err.Error())
+			return vfilter.Null{}
+		}
 	}
 	defer token.Close()
 
 	// Find the token user
 	tokenUser, err := token.GetTokenUser()
 	if err != nil {
-		scope.Log("token: GetTokenUser for %v: %s",
-			GetProcessContext(ctx, scope, uint64(arg.Pid)), err.Error())
+		scope.Log("token: GetTokenUser: %s", err.Error())
 		return vfilter.Null{}
 	}
 
@@ -135,6 +143,9 @@ func (self TokenFunction) Call(
 			return result
 		}).
 		Set("SID", tokenUser.User.Sid.String()).
+		Set("TokenType", vfilter.Null{}).
+		Set("ImpersonationLevel", vfilter.Null{}).
+		Set("IntegrityLevel", vfilter.Null{}).
 		Set("Privileges", vfilter.Null{}).
 		Set("PrimaryGroup", vfilter.Null{})
 
@@ -154,6 +165,17 @@ func (self TokenFunction) Call(
 		result.Update("PrimaryGroup", str)
 	}
 
+	integrity := getTokenIntegrityLevel(token)
+	if integrity != "" {
+		result.Update("IntegrityLevel", integrity)
+	}
+
+	token_type, level := getTokenType(token)
+	result.Update("TokenType", token_type)
+	if level != "" {
+		result.Update("ImpersonationLevel", level)
+	}
+
 	// Get privileges if possible
 	privs, err := getTokenPrivileges(token)
 	if err == nil {
@@ -163,6 +185,104 @@ func (self TokenFunction) Call(
 	return result
 }
 
+func openProcessToken(pid uint32) (windows.Token, error) {
+	handle, err := windows.OpenProcess(
+		syscall.PROCESS_QUERY_INFORMATION, false, pid)
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(handle)
+
+	var token windows.Token
+	err = windows.OpenProcessToken(handle, syscall.TOKEN_QUERY, &token)
+	return token, err
+}
+
+// Threads only have a token while they are impersonating.
+func openThreadToken(tid uint32) (windows.Token, error) {
+	handle, err := windows.OpenThread(
+		windows.THREAD_QUERY_LIMITED_INFORMATION, false, tid)
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(handle)
+
+	var token windows.Token
+	err = windows.OpenThreadToken(handle, syscall.TOKEN_QUERY, true, &token)
+	return token, err
+}
+
+var impersonationLevels = []string{
+	"Anonymous", "Identification", "Impersonation", "Delegation",
+}
+
+// Returns the token type and the impersonation level for
+// impersonation tokens.
+func getTokenType(t windows.Token) (string, string) {
+	var value, n uint32
+	err := windows.GetTokenInformation(t, windows.TokenType,
+		(*byte)(unsafe.Pointer(&value)), uint32(unsafe.Sizeof(value)), &n)
+	if err != nil {
+		return "", ""
+	}
+
+	// TokenPrimary = 1, TokenImpersonation = 2
+	if value == 1 {
+		return "Primary", ""
+	}
+
+	err = windows.GetTokenInformation(t, windows.TokenImpersonationLevel,
+		(*byte)(unsafe.Pointer(&value)), uint32(unsafe.Sizeof(value)), &n)
+	if err != nil || int(value) >= len(impersonationLevels) {
+		return "Impersonation", ""
+	}
+
+	return "Impersonation", impersonationLevels[value]
+}
+
+var integrityLevels = map[uint32]string{
+	0x0000: "Untrusted",
+	0x1000: "Low",
+	0x2000: "Medium",
+	0x2100: "MediumPlus",
+	0x3000: "High",
+	0x4000: "System",
+	0x5000: "Protected",
+}
+
+// The integrity level is the last sub authority of the token's
+// mandatory label SID.
+func getTokenIntegrityLevel(t windows.Token) string {
+	n := uint32(64)
+	for i := 0; i < 2; i++ {
+		b := make([]byte, n)
+		err := windows.GetTokenInformation(t, windows.TokenIntegrityLevel,
+			&b[0], uint32(len(b)), &n)
+		if err == windows.ERROR_INSUFFICIENT_BUFFER && n > uint32(len(b)) {
+			continue
+		}
+
+		if err != nil {
+			return ""
+		}
+
+		sid := (*windows.Tokenmandatorylabel)(unsafe.Pointer(&b[0])).Label.Sid
+		count := sid.SubAuthorityCount()
+		if count == 0 {
+			return ""
+		}
+
+		rid := sid.SubAuthority(uint32(count) - 1)
+		name, pres := integrityLevels[rid]
+		if !pres {
+			name = fmt.Sprintf("0x%x", rid)
+		}
+		return name
+	}
+
+	return ""
+}
+
 func getTokenPrivileges(t windows.Token) (*ordereddict.Dict, error) {
 	n := uint32(1024)
 	for {
@@ -173,6 +173,7 @@ const (
 	MAX_PATH          = 260
 
 	// NtQuerySystemInformation
+	SystemModuleInformation = 0xb
 	SystemHandleInformation = 0x10
 	SystemObjectInformation = 0x11
 
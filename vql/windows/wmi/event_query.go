@@ -0,0 +1,79 @@
+package wmi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+var (
+	event_classes = map[string]string{
+		"creation":     "__InstanceCreationEvent",
+		"deletion":     "__InstanceDeletionEvent",
+		"modification": "__InstanceModificationEvent",
+		"operation":    "__InstanceOperationEvent",
+	}
+
+	class_name_regex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Build a WQL query for a temporary subscription to instance events
+// of class, e.g. SELECT * FROM __InstanceCreationEvent WITHIN 1
+// WHERE TargetInstance ISA 'Win32_Process'
+func buildEventQuery(
+	class, event_type string, within float64, where string) (string, error) {
+	if !class_name_regex.MatchString(class) {
+		return "", fmt.Errorf("Invalid class name %v", class)
+	}
+
+	if event_type == "" {
+		event_type = "creation"
+	}
+
+	event_class, pres := event_classes[strings.ToLower(event_type)]
+	if !pres {
+		return "", fmt.Errorf("Invalid event type %v", event_type)
+	}
+
+	if within <= 0 {
+		within = 1
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s WITHIN %v WHERE TargetInstance ISA '%s'",
+		event_class, within, class)
+	if where != "" {
+		query += " AND (" + where + ")"
+	}
+
+	return query, nil
+}
+
+// Windows FILETIME is the number of 100ns intervals since 1601.
+func fileTimeToTime(filetime int64) time.Time {
+	return time.Unix(0, (filetime-116444736000000000)*100).UTC()
+}
+
+// Convert an instance event into a row.
+func eventToRow(event *ordereddict.Dict) *ordereddict.Dict {
+	event_type, _ := event.GetString("__Type")
+	row := ordereddict.NewDict().Set("EventType", event_type)
+
+	time_created, _ := event.GetString("TIME_CREATED")
+	filetime, err := strconv.ParseInt(time_created, 10, 64)
+	if err == nil {
+		row.Set("Time", fileTimeToTime(filetime))
+	}
+
+	for _, field := range []string{"TargetInstance", "PreviousInstance"} {
+		value, pres := event.Get(field)
+		if pres {
+			row.Set(field, value)
+		}
+	}
+
+	return row
+}
@@ -0,0 +1,36 @@
+package wmi
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestBuildEventQuery(t *testing.T) {
+	query, err := buildEventQuery("Win32_Process", "", 0, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM __InstanceCreationEvent WITHIN 1 WHERE TargetInstance ISA 'Win32_Process'", query)
+
+	query, err = buildEventQuery("Win32_Service", "Modification", 0.5,
+		"TargetInstance.State = 'Stopped'")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM __InstanceModificationEvent WITHIN 0.5 WHERE TargetInstance ISA 'Win32_Service' AND (TargetInstance.State = 'Stopped')", query)
+
+	_, err = buildEventQuery("Win32_Process' OR 1=1", "", 0, "")
+	assert.Error(t, err)
+
+	_, err = buildEventQuery("Win32_Process", "renamed", 0, "")
+	assert.Error(t, err)
+}
+
+func TestEventToRow(t *testing.T) {
+	row := eventToRow(ordereddict.NewDict().
+		Set("__Type", "__InstanceCreationEvent").
+		Set("TargetInstance", ordereddict.NewDict().Set("Name", "notepad.exe")).
+		Set("TIME_CREATED", "131834423287753198"))
+
+	assert.Equal(t, `{"EventType":"__InstanceCreationEvent","Time":"2018-10-08T03:18:48.7753198Z","TargetInstance":{"Name":"notepad.exe"}}`,
+		json.MustMarshalString(row))
+}
@@ -126,60 +126,86 @@ func (self WmiEventPlugin) Call(
 			return
 		}
 
-		runtime.LockOSThread()
-		defer runtime.UnlockOSThread()
-
 		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
 		if err != nil {
 			scope.Log("wmi_events: %s", err.Error())
 			return
 		}
 
-		if arg.Namespace == "" {
-			arg.Namespace = "ROOT/CIMV2"
-		}
+		watchEvents(ctx, scope, arg.Query, arg.Namespace, arg.Wait,
+			func(event *WMIObject) vfilter.Row { return event }, output_chan)
+	}()
 
-		sub_ctx, cancel := context.WithTimeout(
-			ctx, time.Duration(arg.Wait)*time.Second)
-		defer cancel()
+	return output_chan
+}
 
-		event_context := eventQueryContext{
-			// Queue up to 100 messages
-			output: make(chan vfilter.Row, 100),
-			scope:  scope,
-		}
-		defer close(event_context.output)
+// Runs the event query and sends the events to output_chan until the
+// context is done or wait seconds have passed. A wait of 0 means to
+// wait until the query is cancelled.
+func watchEvents(
+	ctx context.Context, scope vfilter.Scope,
+	query, namespace string, wait int64,
+	transform func(event *WMIObject) vfilter.Row,
+	output_chan chan vfilter.Row) {
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if namespace == "" {
+		namespace = "ROOT/CIMV2"
+	}
+
+	sub_ctx, cancel := context.WithCancel(ctx)
+	if wait > 0 {
+		sub_ctx, cancel = context.WithTimeout(
+			ctx, time.Duration(wait)*time.Second)
+	}
+	defer cancel()
+
+	event_context := eventQueryContext{
+		// Queue up to 100 messages
+		output: make(chan vfilter.Row, 100),
+		scope:  scope,
+	}
+	defer close(event_context.output)
+
+	ptr := pointer.Save(&event_context)
+	defer pointer.Unref(ptr)
+
+	c_query := C.CString(query)
+	defer C.free(unsafe.Pointer(c_query))
 
-		ptr := pointer.Save(&event_context)
-		defer pointer.Unref(ptr)
+	c_nsp := C.CString(namespace)
+	defer C.free(unsafe.Pointer(c_nsp))
 
-		c_query := C.CString(arg.Query)
-		defer C.free(unsafe.Pointer(c_query))
+	c_ctx := C.watchEvents(ptr, c_query, c_nsp)
+	if c_ctx == nil {
+		return
+	}
 
-		c_nsp := C.CString(arg.Namespace)
-		defer C.free(unsafe.Pointer(c_nsp))
+	// Destroy the C context when we are done here.
+	defer C.destroyEvent(c_ctx)
 
-		c_ctx := C.watchEvents(ptr, c_query, c_nsp)
-		if c_ctx == nil {
+	for {
+		select {
+		case <-sub_ctx.Done():
 			return
-		}
 
-		for item := range event_context.output {
+			// Read the next item from the event queue and send
+			// it to the VQL subsystem.
+		case item := <-event_context.output:
+			event, ok := item.(*WMIObject)
+			if !ok {
+				continue
+			}
+
 			select {
 			case <-sub_ctx.Done():
-				// Destroy the C context - we are done here.
-				C.destroyEvent(c_ctx)
 				return
-
-				// Read the next item from the event
-				// queue and send it to the VQL
-				// subsystem.
-			case output_chan <- item:
+			case output_chan <- transform(event):
 			}
 		}
-	}()
-
-	return output_chan
+	}
 }
 
 func (self WmiEventPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
@@ -0,0 +1,260 @@
+package wmi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+var (
+	default_subscription_namespaces = []string{
+		"ROOT/subscription", "ROOT/default"}
+
+	// The fields that describe what each standard consumer does.
+	consumer_action_fields = []string{
+		"CommandLineTemplate", "ExecutablePath", "ScriptText",
+		"ScriptFileName", "FileName", "SourceName", "SMTPServer",
+	}
+)
+
+// Permanent event subscriptions are made of a filter (the trigger)
+// and a consumer (the payload) tied together by a binding.
+type wmiSubscriptions struct {
+	namespace string
+	bindings  []*ordereddict.Dict
+	filters   []*ordereddict.Dict
+	consumers []*ordereddict.Dict
+}
+
+// Bindings refer to their filter and consumer by path, which may or
+// may not include the namespace.
+func normalizeReference(reference string) string {
+	colon := strings.Index(reference, ":")
+	quote := strings.Index(reference, "\"")
+	if colon >= 0 && (quote < 0 || colon < quote) {
+		reference = reference[colon+1:]
+	}
+	return strings.ToLower(reference)
+}
+
+func getString(item *ordereddict.Dict, field string) string {
+	value, _ := item.GetString(field)
+	return value
+}
+
+func consumerAction(consumer *ordereddict.Dict) string {
+	for _, field := range consumer_action_fields {
+		value := getString(consumer, field)
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Produce a row for each binding, as well as any filters or
+// consumers that are not bound since these are often left behind by
+// incomplete cleanups.
+func (self *wmiSubscriptions) Rows() []*ordereddict.Dict {
+	var result []*ordereddict.Dict
+
+	filters := make(map[string]*ordereddict.Dict)
+	for _, filter := range self.filters {
+		filters[normalizeReference(getString(filter, "__RELPATH"))] = filter
+	}
+
+	consumers := make(map[string]*ordereddict.Dict)
+	for _, consumer := range self.consumers {
+		consumers[normalizeReference(getString(consumer, "__RELPATH"))] = consumer
+	}
+
+	used := make(map[*ordereddict.Dict]bool)
+	for _, binding := range self.bindings {
+		filter := filters[normalizeReference(getString(binding, "Filter"))]
+		consumer := consumers[normalizeReference(getString(binding, "Consumer"))]
+		used[filter] = true
+		used[consumer] = true
+
+		result = append(result, self.makeRow(binding, filter, consumer))
+	}
+
+	for _, filter := range self.filters {
+		if !used[filter] {
+			result = append(result, self.makeRow(nil, filter, nil))
+		}
+	}
+
+	for _, consumer := range self.consumers {
+		if !used[consumer] {
+			result = append(result, self.makeRow(nil, nil, consumer))
+		}
+	}
+
+	return result
+}
+
+func (self *wmiSubscriptions) makeRow(
+	binding, filter, consumer *ordereddict.Dict) *ordereddict.Dict {
+	row := ordereddict.NewDict().Set("Namespace", self.namespace)
+
+	if filter != nil {
+		row.Set("FilterName", getString(filter, "Name")).
+			Set("Query", getString(filter, "Query"))
+	} else {
+		row.Set("FilterName", vfilter.Null{}).
+			Set("Query", vfilter.Null{})
+	}
+
+	if consumer != nil {
+		row.Set("ConsumerName", getString(consumer, "Name")).
+			Set("ConsumerType", getString(consumer, "__CLASS")).
+			Set("Action", consumerAction(consumer))
+	} else {
+		row.Set("ConsumerName", vfilter.Null{}).
+			Set("ConsumerType", vfilter.Null{}).
+			Set("Action", vfilter.Null{})
+	}
+
+	row.Set("Bound", binding != nil)
+
+	// The paths may be passed to wmi_delete() to remove the
+	// subscription.
+	for _, item := range []struct {
+		name string
+		obj  *ordereddict.Dict
+	}{{"Binding", binding}, {"Filter", filter}, {"Consumer", consumer}} {
+		if item.obj == nil {
+			row.Set(item.name+"Path", vfilter.Null{})
+		} else {
+			row.Set(item.name+"Path", getString(item.obj, "__RELPATH"))
+		}
+	}
+
+	for _, item := range []struct {
+		name string
+		obj  *ordereddict.Dict
+	}{{"Binding", binding}, {"Filter", filter}, {"Consumer", consumer}} {
+		if item.obj == nil {
+			row.Set(item.name, vfilter.Null{})
+		} else {
+			row.Set(item.name, item.obj)
+		}
+	}
+
+	return row
+}
+
+type WMIPersistenceArgs struct {
+	Namespaces []string `vfilter:"optional,field=namespaces,doc=The namespaces to search (default ROOT/subscription and ROOT/default)."`
+}
+
+func runWMIPersistence(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict) []vfilter.Row {
+	var result []vfilter.Row
+
+	err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+	if err != nil {
+		scope.Log("wmi_persistence: %v", err)
+		return result
+	}
+
+	arg := &WMIPersistenceArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("wmi_persistence: %v", err)
+		return result
+	}
+
+	if len(arg.Namespaces) == 0 {
+		arg.Namespaces = default_subscription_namespaces
+	}
+
+	for _, namespace := range arg.Namespaces {
+		subscriptions := &wmiSubscriptions{namespace: namespace}
+
+		for _, item := range []struct {
+			class  string
+			target *[]*ordereddict.Dict
+		}{
+			{"__FilterToConsumerBinding", &subscriptions.bindings},
+			{"__EventFilter", &subscriptions.filters},
+			{"__EventConsumer", &subscriptions.consumers},
+		} {
+			rows, err := QueryWithPath("SELECT * FROM "+item.class, namespace)
+			if err != nil {
+				scope.Log("wmi_persistence: %v: %v", namespace, err)
+				continue
+			}
+			*item.target = rows
+		}
+
+		for _, row := range subscriptions.Rows() {
+			result = append(result, row)
+		}
+	}
+
+	return result
+}
+
+type WMIDeleteFunctionArgs struct {
+	Path      string `vfilter:"required,field=path,doc=The path of the WMI object to delete."`
+	Namespace string `vfilter:"optional,field=namespace,doc=The WMI namespace to use (ROOT/CIMV2)"`
+}
+
+type WMIDeleteFunction struct{}
+
+func (self *WMIDeleteFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.EXECVE)
+	if err != nil {
+		scope.Log("wmi_delete: %v", err)
+		return false
+	}
+
+	arg := &WMIDeleteFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("wmi_delete: %v", err)
+		return false
+	}
+
+	// Leave a record of what was removed.
+	scope.Log("wmi_delete: Deleting %v in %v", arg.Path, arg.Namespace)
+
+	err = Delete(arg.Path, arg.Namespace)
+	if err != nil {
+		scope.Log("wmi_delete: %v", err)
+		return false
+	}
+
+	return true
+}
+
+func (self WMIDeleteFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:     "wmi_delete",
+		Doc:      "Delete a WMI object, for example to remove a persistent event subscription.",
+		ArgType:  type_map.AddType(scope, &WMIDeleteFunctionArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.EXECVE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
+		PluginName: "wmi_persistence",
+		Doc:        "Enumerate permanent WMI event subscriptions.",
+		Function:   runWMIPersistence,
+		ArgType:    &WMIPersistenceArgs{},
+		Metadata:   vql.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	})
+	vql_subsystem.RegisterFunction(&WMIDeleteFunction{})
+}
@@ -0,0 +1,66 @@
+package wmi
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+func TestWMISubscriptions(t *testing.T) {
+	subscriptions := &wmiSubscriptions{
+		namespace: "ROOT/subscription",
+		bindings: []*ordereddict.Dict{
+			ordereddict.NewDict().
+				Set("Filter", `\\.\ROOT\subscription:__EventFilter.Name="Updater"`).
+				Set("Consumer", `CommandLineEventConsumer.Name="Updater"`).
+				Set("__RELPATH", `__FilterToConsumerBinding.Consumer="CommandLineEventConsumer.Name=\"Updater\"",Filter="__EventFilter.Name=\"Updater\""`),
+		},
+		filters: []*ordereddict.Dict{
+			ordereddict.NewDict().
+				Set("Name", "Updater").
+				Set("Query", "SELECT * FROM __InstanceModificationEvent WITHIN 60").
+				Set("__RELPATH", `__EventFilter.Name="Updater"`),
+			ordereddict.NewDict().
+				Set("Name", "Leftover").
+				Set("Query", "SELECT * FROM __TimerEvent").
+				Set("__RELPATH", `__EventFilter.Name="Leftover"`),
+		},
+		consumers: []*ordereddict.Dict{
+			ordereddict.NewDict().
+				Set("Name", "Updater").
+				Set("CommandLineTemplate", "powershell.exe -enc AAAA").
+				Set("__CLASS", "CommandLineEventConsumer").
+				Set("__RELPATH", `CommandLineEventConsumer.Name="Updater"`),
+		},
+	}
+
+	rows := subscriptions.Rows()
+	assert.Equal(t, 2, len(rows))
+
+	row := rows[0]
+	bound, _ := row.Get("Bound")
+	assert.Equal(t, true, bound)
+	assert.Equal(t, "Updater", getString(row, "FilterName"))
+	assert.Equal(t, "CommandLineEventConsumer", getString(row, "ConsumerType"))
+	assert.Equal(t, "powershell.exe -enc AAAA", getString(row, "Action"))
+	assert.Equal(t, `CommandLineEventConsumer.Name="Updater"`,
+		getString(row, "ConsumerPath"))
+
+	// Unbound filters are reported too.
+	row = rows[1]
+	bound, _ = row.Get("Bound")
+	assert.Equal(t, false, bound)
+	assert.Equal(t, "Leftover", getString(row, "FilterName"))
+
+	consumer, _ := row.Get("Consumer")
+	assert.Equal(t, "null", json.MustMarshalString(consumer))
+}
+
+func TestNormalizeReference(t *testing.T) {
+	assert.Equal(t, `__eventfilter.name="a:b"`,
+		normalizeReference(`\\HOST\root\subscription:__EventFilter.Name="a:b"`))
+	assert.Equal(t, `__eventfilter.name="a:b"`,
+		normalizeReference(`__EventFilter.Name="a:b"`))
+}
@@ -0,0 +1,81 @@
+// +build windows,cgo
+
+package wmi
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/vql"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type WmiWatchPluginArgs struct {
+	Class     string  `vfilter:"required,field=class,doc=The class to watch (e.g. Win32_Process)."`
+	EventType string  `vfilter:"optional,field=event_type,doc=One of creation (default), deletion, modification or operation."`
+	Within    float64 `vfilter:"optional,field=within,doc=The polling interval in seconds for classes without an event provider (default 1)."`
+	Where     string  `vfilter:"optional,field=where,doc=An additional WQL condition (e.g. TargetInstance.Name = 'cmd.exe')."`
+	Namespace string  `vfilter:"optional,field=namespace,doc=The WMI namespace to use (ROOT/CIMV2)"`
+	Wait      int64   `vfilter:"optional,field=wait,doc=Wait this many seconds for events and then quit (default until the query is cancelled)."`
+}
+
+type WmiWatchPlugin struct{}
+
+func (self WmiWatchPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("wmi_watch: %s", err)
+			return
+		}
+
+		arg := &WmiWatchPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("wmi_watch: %s", err)
+			return
+		}
+
+		query, err := buildEventQuery(
+			arg.Class, arg.EventType, arg.Within, arg.Where)
+		if err != nil {
+			scope.Log("wmi_watch: %s", err)
+			return
+		}
+
+		watchEvents(ctx, scope, query, arg.Namespace, arg.Wait,
+			func(event *WMIObject) vfilter.Row {
+				parsed, err := event.Parse()
+				if err != nil {
+					scope.Log("wmi_watch: %s", err)
+					return ordereddict.NewDict().Set("Raw", event.Raw)
+				}
+				return eventToRow(parsed)
+			}, output_chan)
+	}()
+
+	return output_chan
+}
+
+func (self WmiWatchPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "wmi_watch",
+		Doc:      "Subscribe to WMI instance events of a class (e.g. process creation).",
+		ArgType:  type_map.AddType(scope, &WmiWatchPluginArgs{}),
+		Metadata: vql.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&WmiWatchPlugin{})
+}
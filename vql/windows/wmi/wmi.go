@@ -52,24 +52,23 @@ var (
 const S_FALSE = 0x00000001
 
 func Query(query string, namespace string) ([]*ordereddict.Dict, error) {
-	lock.Lock()
-	defer lock.Unlock()
-	runtime.LockOSThread()
-	defer runtime.UnlockOSThread()
+	return queryWithOptions(query, namespace, false)
+}
 
+// Like Query but also includes the object path (e.g. __PATH,
+// __RELPATH and __CLASS) in each row.
+func QueryWithPath(
+	query string, namespace string) ([]*ordereddict.Dict, error) {
+	return queryWithOptions(query, namespace, true)
+}
+
+// Connects to the WMI service in the namespace. The caller must hold
+// the lock, have locked the OS thread and initialized COM.
+func connectServer(namespace string) (*ole.IDispatch, error) {
 	if namespace == "" {
 		namespace = "ROOT/CIMV2"
 	}
 
-	err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED)
-	if err != nil {
-		oleCode := err.(*ole.OleError).Code()
-		if oleCode != ole.S_OK && oleCode != S_FALSE {
-			return nil, err
-		}
-	}
-	defer ole.CoUninitialize()
-
 	unknown, err := oleutil.CreateObject("WbemScripting.SWbemLocator")
 	if err != nil {
 		return nil, err
@@ -89,7 +88,60 @@ func Query(query string, namespace string) ([]*ordereddict.Dict, error) {
 		return nil, err
 	}
 
-	service := serviceRaw.ToIDispatch()
+	return serviceRaw.ToIDispatch(), nil
+}
+
+func coInitialize() error {
+	err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED)
+	if err != nil {
+		oleCode := err.(*ole.OleError).Code()
+		if oleCode != ole.S_OK && oleCode != S_FALSE {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deletes the WMI object (class or instance) at path.
+func Delete(path string, namespace string) error {
+	lock.Lock()
+	defer lock.Unlock()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	err := coInitialize()
+	if err != nil {
+		return err
+	}
+	defer ole.CoUninitialize()
+
+	service, err := connectServer(namespace)
+	if err != nil {
+		return err
+	}
+	defer service.Release()
+
+	_, err = oleutil.CallMethod(service, "Delete", path)
+	return err
+}
+
+func queryWithOptions(query string, namespace string,
+	with_path bool) ([]*ordereddict.Dict, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	err := coInitialize()
+	if err != nil {
+		return nil, err
+	}
+	defer ole.CoUninitialize()
+
+	service, err := connectServer(namespace)
+	if err != nil {
+		return nil, err
+	}
 	defer service.Release()
 
 	resultRaw, err := oleutil.CallMethod(service, "ExecQuery", query)
@@ -107,8 +159,11 @@ func Query(query string, namespace string) ([]*ordereddict.Dict, error) {
 			item := v.ToIDispatch()
 			defer item.Release()
 
-			if len(properties) == 0 {
-				item_properties, err := getProperties(item)
+			// Queries on a base class may return instances of
+			// different derived classes, so when we need the full
+			// object we get the properties of each row.
+			if len(properties) == 0 || with_path {
+				item_properties, err := getProperties(item, "Properties_")
 				if err != nil {
 					return err
 				}
@@ -153,15 +208,54 @@ func Query(query string, namespace string) ([]*ordereddict.Dict, error) {
 				}
 			}
 
+			if with_path {
+				err := getObjectPath(item, row)
+				if err != nil {
+					return err
+				}
+			}
+
 			result = append(result, row)
 			return nil
 		})
 	return result, err
 }
 
-func getProperties(item *ole.IDispatch) ([]string, error) {
+// Adds the object's path to the row using the usual names of the
+// system properties.
+func getObjectPath(item *ole.IDispatch, row *ordereddict.Dict) error {
+	path_raw, err := item.GetProperty("Path_")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = path_raw.Clear()
+	}()
+
+	path := path_raw.ToIDispatch()
+	defer path.Release()
+
+	for _, field := range []struct{ name, property string }{
+		{"__PATH", "Path"},
+		{"__RELPATH", "RelPath"},
+		{"__CLASS", "Class"},
+		{"__NAMESPACE", "Namespace"},
+		{"__SERVER", "Server"},
+	} {
+		value, err := path.GetProperty(field.property)
+		if err != nil {
+			continue
+		}
+		row.Set(field.name, value.Value())
+		_ = value.Clear()
+	}
+
+	return nil
+}
+
+func getProperties(item *ole.IDispatch, collection string) ([]string, error) {
 	result := []string{}
-	properties_raw, err := item.GetProperty("Properties_")
+	properties_raw, err := item.GetProperty(collection)
 	if err != nil {
 		return nil, err
 	}
@@ -2,11 +2,14 @@ package plugins
 
 import (
 	_ "www.velocidex.com/golang/velociraptor/accessors"
+	_ "www.velocidex.com/golang/velociraptor/accessors/apfs"
 	_ "www.velocidex.com/golang/velociraptor/accessors/collector"
 	_ "www.velocidex.com/golang/velociraptor/accessors/data"
+	_ "www.velocidex.com/golang/velociraptor/accessors/ext4"
 	_ "www.velocidex.com/golang/velociraptor/accessors/fat"
 	_ "www.velocidex.com/golang/velociraptor/accessors/file"
 	_ "www.velocidex.com/golang/velociraptor/accessors/file_store"
+	_ "www.velocidex.com/golang/velociraptor/accessors/images"
 	_ "www.velocidex.com/golang/velociraptor/accessors/ntfs"
 	_ "www.velocidex.com/golang/velociraptor/accessors/offset"
 	_ "www.velocidex.com/golang/velociraptor/accessors/pipe"
@@ -19,5 +22,7 @@ import (
 	_ "www.velocidex.com/golang/velociraptor/accessors/sparse"
 	_ "www.velocidex.com/golang/velociraptor/accessors/ssh"
 	_ "www.velocidex.com/golang/velociraptor/accessors/vfs"
+	_ "www.velocidex.com/golang/velociraptor/accessors/volumes"
+	_ "www.velocidex.com/golang/velociraptor/accessors/xfs"
 	_ "www.velocidex.com/golang/velociraptor/accessors/zip"
 )
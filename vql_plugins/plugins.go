@@ -28,6 +28,7 @@ import (
 	_ "www.velocidex.com/golang/velociraptor/vql/filesystem"
 	_ "www.velocidex.com/golang/velociraptor/vql/functions"
 	_ "www.velocidex.com/golang/velociraptor/vql/golang"
+	_ "www.velocidex.com/golang/velociraptor/vql/lsa"
 	_ "www.velocidex.com/golang/velociraptor/vql/networking"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/authenticode"
@@ -35,12 +36,20 @@ import (
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/csv"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/ese"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/event_logs"
+	_ "www.velocidex.com/golang/velociraptor/vql/parsers/sam"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/syslog"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/usn"
+	_ "www.velocidex.com/golang/velociraptor/vql/parsers/windows_updates"
 	_ "www.velocidex.com/golang/velociraptor/vql/protocols"
+	_ "www.velocidex.com/golang/velociraptor/vql/remediation"
 	_ "www.velocidex.com/golang/velociraptor/vql/sigma"
 	_ "www.velocidex.com/golang/velociraptor/vql/tools"
+	_ "www.velocidex.com/golang/velociraptor/vql/tools/carve"
+	_ "www.velocidex.com/golang/velociraptor/vql/tools/cloud"
 	_ "www.velocidex.com/golang/velociraptor/vql/tools/collector"
+	_ "www.velocidex.com/golang/velociraptor/vql/tools/enrichment"
 	_ "www.velocidex.com/golang/velociraptor/vql/tools/logscale"
+	_ "www.velocidex.com/golang/velociraptor/vql/tools/osquery"
+	_ "www.velocidex.com/golang/velociraptor/vql/tools/powershell"
 	_ "www.velocidex.com/golang/velociraptor/vql/tools/process"
 )
@@ -18,5 +18,7 @@
 package plugins
 
 import (
+	_ "www.velocidex.com/golang/velociraptor/accessors/container"
 	_ "www.velocidex.com/golang/velociraptor/vql/linux"
+	_ "www.velocidex.com/golang/velociraptor/vql/linux/containers"
 )
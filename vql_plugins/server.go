@@ -26,11 +26,13 @@ package plugins
 import (
 	_ "www.velocidex.com/golang/velociraptor/vql/server"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/clients"
+	_ "www.velocidex.com/golang/velociraptor/vql/server/cloudlogs"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/crypto"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/downloads"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/favorites"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/flows"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/hunts"
+	_ "www.velocidex.com/golang/velociraptor/vql/server/m365"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/monitoring"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/notebooks"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/orgs"